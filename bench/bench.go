@@ -0,0 +1,189 @@
+package bench
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/math"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/version"
+)
+
+// Config specifies a benchmark run
+type Config struct {
+	Scenario    string
+	NumNodes    int
+	NumTxs      int
+	TxsPerBlock int
+}
+
+// DefaultConfig returns the default configuration of the given scenario
+func DefaultConfig(scenario string) Config {
+	return Config{
+		Scenario:    scenario,
+		NumNodes:    4,
+		NumTxs:      10000,
+		TxsPerBlock: 1000,
+	}
+}
+
+// Validate checks whether the configuration is valid
+func (cfg Config) Validate() error {
+	if cfg.NumNodes < 1 {
+		return fmt.Errorf("The number of nodes needs to be at least 1")
+	}
+	if cfg.NumTxs < 1 {
+		return fmt.Errorf("The number of transactions needs to be at least 1")
+	}
+	if cfg.TxsPerBlock < 1 || cfg.TxsPerBlock > core.MaxNumRegularTxsPerBlock {
+		return fmt.Errorf("The number of transactions per block needs to be between 1 and %v", core.MaxNumRegularTxsPerBlock)
+	}
+	return nil
+}
+
+// Run runs the benchmark on an in-process network. The transactions of each block are signed
+// before they are submitted, and the signing is not included in the measurements. The
+// finality latency of a block spans from the submission of its transactions until every node
+// has applied and finalized it.
+func Run(cfg Config) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	scenario, err := NewScenario(cfg.Scenario, cfg.TxsPerBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	net, err := NewNetwork(cfg.NumNodes, scenario.Accounts())
+	if err != nil {
+		return nil, err
+	}
+	defer net.Stop()
+
+	view, err := net.ScreenedView()
+	if err != nil {
+		return nil, err
+	}
+	setupTxs, err := scenario.Setup(view)
+	if err != nil {
+		return nil, err
+	}
+	if len(setupTxs) > 0 {
+		if _, err := submitAndProduceBlock(net, setupTxs); err != nil {
+			return nil, fmt.Errorf("Failed to set up the scenario: %v", err)
+		}
+	}
+
+	stateBytesStart := net.Proposer().StateSize()
+	numTxs := 0
+	numBlocks := 0
+	duration := time.Duration(0)
+	latencies := []time.Duration{}
+	for numTxs < cfg.NumTxs {
+		view, err := net.ScreenedView()
+		if err != nil {
+			return nil, err
+		}
+		batch, err := scenario.NextBatch(view, math.MinInt(cfg.TxsPerBlock, cfg.NumTxs-numTxs))
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		if _, err := submitAndProduceBlock(net, batch); err != nil {
+			return nil, err
+		}
+		latency := time.Since(start)
+
+		duration += latency
+		latencies = append(latencies, latency)
+		numTxs += len(batch)
+		numBlocks++
+	}
+	stateBytesEnd := net.Proposer().StateSize()
+
+	report := &Report{
+		Scenario:        cfg.Scenario,
+		Version:         version.Version,
+		GitHash:         version.GitHash,
+		GoVersion:       runtime.Version(),
+		NumCPU:          runtime.NumCPU(),
+		NumNodes:        cfg.NumNodes,
+		TxsPerBlock:     cfg.TxsPerBlock,
+		NumBlocks:       numBlocks,
+		NumTxs:          numTxs,
+		DurationSeconds: duration.Seconds(),
+		TPS:             float64(numTxs) / duration.Seconds(),
+		FinalityLatency: newLatencyStats(latencies),
+		StateBytesStart: stateBytesStart,
+		StateBytesEnd:   stateBytesEnd,
+		StateRoot:       net.Tip().StateHash,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if stateBytesEnd > stateBytesStart {
+		report.StateGrowthPerMillionTxs = (stateBytesEnd - stateBytesStart) * 1000000 / uint64(numTxs)
+	}
+
+	return report, nil
+}
+
+// submitAndProduceBlock submits the transactions, and produces a block which needs to include
+// all of them
+func submitAndProduceBlock(net *Network, rawTxs []common.Bytes) (*core.Block, error) {
+	if err := net.Submit(rawTxs); err != nil {
+		return nil, err
+	}
+	block, err := net.ProduceBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	numIncluded := 0
+	for _, rawTx := range block.Txs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			return nil, err
+		}
+		switch tx.(type) {
+		case *types.CoinbaseTx, *types.SlashTx:
+		default:
+			numIncluded++
+		}
+	}
+	if numIncluded != len(rawTxs) {
+		return nil, fmt.Errorf("Block %v only includes %v out of %v submitted transactions", block.Height, numIncluded, len(rawTxs))
+	}
+	return block, nil
+}
+
+// newLatencyStats summarizes the latencies in milliseconds
+func newLatencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p int) float64 {
+		idx := (len(sorted)*p + 99) / 100
+		if idx > 0 {
+			idx--
+		}
+		return toMilliseconds(sorted[idx])
+	}
+	return LatencyStats{
+		P50: percentile(50),
+		P90: percentile(90),
+		P99: percentile(99),
+		Max: toMilliseconds(sorted[len(sorted)-1]),
+	}
+}
+
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
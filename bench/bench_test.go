@@ -0,0 +1,148 @@
+package bench
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runSmallBenchmark(t *testing.T, scenario string) *Report {
+	cfg := Config{
+		Scenario:    scenario,
+		NumNodes:    3,
+		NumTxs:      50,
+		TxsPerBlock: 20,
+	}
+	report, err := Run(cfg)
+	require.Nil(t, err)
+	return report
+}
+
+func TestRunScenarios(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, scenario := range ScenarioNames() {
+		report := runSmallBenchmark(t, scenario)
+		assert.Equal(scenario, report.Scenario)
+		assert.Equal(50, report.NumTxs)
+		assert.Equal(3, report.NumBlocks)
+		assert.True(report.TPS > 0, scenario)
+		assert.True(report.FinalityLatency.P50 > 0, scenario)
+		assert.True(report.FinalityLatency.P50 <= report.FinalityLatency.Max, scenario)
+		assert.True(report.StateBytesEnd > report.StateBytesStart, scenario)
+		assert.True(report.StateGrowthPerMillionTxs > 0, scenario)
+	}
+}
+
+func TestRunIsReproducible(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, scenario := range ScenarioNames() {
+		report1 := runSmallBenchmark(t, scenario)
+		report2 := runSmallBenchmark(t, scenario)
+		assert.Equal(report1.StateRoot, report2.StateRoot, scenario)
+		assert.Equal(report1.StateBytesEnd, report2.StateBytesEnd, scenario)
+	}
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Run(Config{Scenario: "unknown", NumNodes: 1, NumTxs: 1, TxsPerBlock: 1})
+	assert.NotNil(err)
+
+	_, err = Run(Config{Scenario: ScenarioSends, NumNodes: 0, NumTxs: 1, TxsPerBlock: 1})
+	assert.NotNil(err)
+
+	_, err = Run(Config{Scenario: ScenarioSends, NumNodes: 1, NumTxs: 1, TxsPerBlock: 100000})
+	assert.NotNil(err)
+}
+
+func TestCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	baseline := &Report{
+		Scenario:                 ScenarioSends,
+		NumNodes:                 4,
+		TxsPerBlock:              1000,
+		NumTxs:                   10000,
+		TPS:                      1000,
+		FinalityLatency:          LatencyStats{P50: 100, P90: 150, P99: 200, Max: 250},
+		StateGrowthPerMillionTxs: 1000000,
+	}
+
+	current := *baseline
+	current.TPS = 950
+	current.FinalityLatency.P99 = 210
+	regressions, err := Compare(baseline, &current, 0.1)
+	assert.Nil(err)
+	assert.Equal(0, len(regressions))
+
+	current.TPS = 800
+	current.FinalityLatency.P50 = 150
+	current.StateGrowthPerMillionTxs = 900000
+	regressions, err = Compare(baseline, &current, 0.1)
+	assert.Nil(err)
+	require.Equal(t, 2, len(regressions))
+	assert.Equal("tps", regressions[0].Metric)
+	assert.InDelta(0.2, regressions[0].Change, 1e-9)
+	assert.Equal("finality_latency_ms.p50", regressions[1].Metric)
+	assert.InDelta(0.5, regressions[1].Change, 1e-9)
+
+	current.NumTxs = 20000
+	_, err = Compare(baseline, &current, 0.1)
+	assert.NotNil(err)
+
+	current = *baseline
+	current.Scenario = ScenarioContracts
+	_, err = Compare(baseline, &current, 0.1)
+	assert.NotNil(err)
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	report := &Report{Scenario: ScenarioStakeChurn, NumTxs: 10, TPS: 12.5}
+	var buf bytes.Buffer
+	require.Nil(t, report.Write(&buf))
+	assert.Contains(buf.String(), `"scenario": "stake_churn"`)
+	assert.Contains(buf.String(), `"tps": 12.5`)
+}
+
+func TestContractsScenario(t *testing.T) {
+	assert := assert.New(t)
+
+	scenario, err := NewScenario(ScenarioContracts, 5)
+	require.Nil(t, err)
+	net, err := NewNetwork(2, scenario.Accounts())
+	require.Nil(t, err)
+	defer net.Stop()
+
+	view, err := net.ScreenedView()
+	require.Nil(t, err)
+	setupTxs, err := scenario.Setup(view)
+	require.Nil(t, err)
+	_, err = submitAndProduceBlock(net, setupTxs)
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		view, err = net.ScreenedView()
+		require.Nil(t, err)
+		batch, err := scenario.NextBatch(view, 5)
+		require.Nil(t, err)
+		_, err = submitAndProduceBlock(net, batch)
+		require.Nil(t, err)
+	}
+
+	// Every call increments the counter, and stores it in a new slot
+	view, err = net.ScreenedView()
+	require.Nil(t, err)
+	contract := scenario.(*contractsScenario).contract
+	assert.Equal(common.BigToHash(big.NewInt(15)), view.GetState(contract, common.Hash{}))
+	assert.Equal(common.BigToHash(big.NewInt(7)), view.GetState(contract, common.BigToHash(big.NewInt(7))))
+}
@@ -0,0 +1,265 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	dp "github.com/pandotoken/pando/dispatcher"
+	"github.com/pandotoken/pando/ledger"
+	exec "github.com/pandotoken/pando/ledger/execution"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	mp "github.com/pandotoken/pando/mempool"
+	p2psim "github.com/pandotoken/pando/p2p/simulation"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/pandotoken/pando/store/kvstore"
+)
+
+const (
+	// ChainID is the chain ID of the benchmark network
+	ChainID = "pando_bench"
+
+	// genesisTimestamp is the timestamp of the genesis block. The block timestamps are derived
+	// from it so that every run produces the same blocks, and hence the same state root.
+	genesisTimestamp = int64(1600000000)
+
+	// blockInterval is the number of seconds between the timestamps of two consecutive blocks
+	blockInterval = int64(6)
+)
+
+// Node is an in-process node of the benchmark network. Each node has its own chain and state
+// storage, and executes every block with its own ledger.
+type Node struct {
+	ID      string
+	stateDB *backend.MemDatabase
+	chain   *blockchain.Chain
+	ledger  *ledger.Ledger
+	mempool *mp.Mempool
+	signer  *consensusEngine
+}
+
+// consensusEngine is the consensus engine of a node. The network drives the blocks directly,
+// so the engine only provides the signer and the ledger of the node.
+type consensusEngine struct {
+	*exec.TestConsensusEngine
+	ledger core.Ledger
+}
+
+func (ce *consensusEngine) GetLedger() core.Ledger { return ce.ledger }
+
+// Network is an in-process network of nodes. The first node proposes all the blocks, which are
+// then applied by every node, including the proposer itself.
+type Network struct {
+	Nodes []*Node
+
+	tip    *core.Block
+	cancel context.CancelFunc
+}
+
+// NewNetwork creates a network with the given number of nodes, which all start from the same
+// genesis state holding the given accounts.
+func NewNetwork(numNodes int, accounts []*types.Account) (*Network, error) {
+	if numNodes < 1 {
+		return nil, fmt.Errorf("The network needs at least one node")
+	}
+
+	signers := []*consensusEngine{}
+	valSet := core.NewValidatorSet()
+	for i := 0; i < numNodes; i++ {
+		signer := &consensusEngine{TestConsensusEngine: exec.NewTestConsensusEngine(fmt.Sprintf("bench_node_%v", i))}
+		signers = append(signers, signer)
+		valSet.AddValidator(core.NewValidator(signer.Signer().PublicKey().Address().String(), core.MinValidatorStakeDeposit))
+	}
+	proposer := valSet.Validators()[0]
+	for _, val := range valSet.Validators() {
+		if val.Address == signers[0].Signer().PublicKey().Address() {
+			proposer = val
+		}
+	}
+	valMgr := exec.NewTestValidatorManager(proposer, valSet)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	net := &Network{cancel: cancel}
+	simnet := p2psim.NewSimnetWithHandler(nil)
+	for i := 0; i < numNodes; i++ {
+		stateDB := backend.NewMemDatabase()
+		genesis := newGenesisBlock(stateDB, accounts, valSet)
+		if net.tip == nil {
+			net.tip = genesis
+		}
+
+		chain := blockchain.NewChain(ChainID, kvstore.NewKVStore(backend.NewMemDatabase()), genesis)
+
+		id := fmt.Sprintf("bench_node_%v", i)
+		messenger := simnet.AddEndpoint(id)
+		dispatcher := dp.NewDispatcher(messenger, nil)
+		mempool := mp.CreateMempool(dispatcher, nil)
+		messenger.RegisterMessageHandler(mp.CreateMempoolMessageHandler(mempool))
+
+		ldgr := ledger.NewLedger(ChainID, stateDB, chain, signers[i], valMgr, mempool)
+		mempool.SetLedger(ldgr)
+		signers[i].ledger = ldgr
+		messenger.Start(ctx)
+		mempool.Start(ctx)
+
+		if res := ldgr.ResetState(genesis); res.IsError() {
+			cancel()
+			return nil, fmt.Errorf("Failed to reset the ledger state of %v: %v", id, res.Message)
+		}
+
+		net.Nodes = append(net.Nodes, &Node{
+			ID:      id,
+			stateDB: stateDB,
+			chain:   chain,
+			ledger:  ldgr,
+			mempool: mempool,
+			signer:  signers[i],
+		})
+	}
+
+	return net, nil
+}
+
+// newGenesisBlock writes the genesis state into the database, and returns the genesis block.
+// The validators stake for themselves in the genesis state.
+func newGenesisBlock(db *backend.MemDatabase, accounts []*types.Account, valSet *core.ValidatorSet) *core.Block {
+	view := state.NewStoreView(0, common.Hash{}, db)
+	for _, acc := range accounts {
+		view.SetAccount(acc.Address, acc)
+	}
+
+	vcp := &core.ValidatorCandidatePool{}
+	for _, val := range valSet.Validators() {
+		if err := vcp.DepositStake(val.Address, val.Address, val.Stake); err != nil {
+			panic(fmt.Sprintf("Failed to deposit the stake of validator %v: %v", val.Address, err))
+		}
+	}
+	view.UpdateValidatorCandidatePool(vcp)
+	stateHash := view.Save()
+
+	genesis := core.NewBlock()
+	genesis.ChainID = ChainID
+	genesis.Height = 0
+	genesis.StateHash = stateHash
+	genesis.Timestamp = big.NewInt(genesisTimestamp)
+	return genesis
+}
+
+// Proposer returns the node that proposes the blocks
+func (net *Network) Proposer() *Node {
+	return net.Nodes[0]
+}
+
+// Tip returns the latest block of the network
+func (net *Network) Tip() *core.Block {
+	return net.tip
+}
+
+// ScreenedView returns the state the transactions submitted to the proposer are screened against
+func (net *Network) ScreenedView() (*state.StoreView, error) {
+	return net.Proposer().ledger.GetScreenedSnapshot()
+}
+
+// Submit inserts the transactions into the mempool of the proposer
+func (net *Network) Submit(rawTxs []common.Bytes) error {
+	proposer := net.Proposer()
+	for _, rawTx := range rawTxs {
+		if err := proposer.mempool.InsertTransaction(rawTx); err != nil {
+			return fmt.Errorf("Failed to submit transaction: %v", err)
+		}
+	}
+	return nil
+}
+
+// ProduceBlock has the proposer assemble a block from its mempool, and then has all the nodes
+// apply and finalize the block. It returns the new block.
+func (net *Network) ProduceBlock() (*core.Block, error) {
+	proposer := net.Proposer()
+	parent := net.tip
+
+	block := core.NewBlock()
+	block.ChainID = ChainID
+	block.Epoch = parent.Epoch + 1
+	block.Height = parent.Height + 1
+	block.Parent = parent.Hash()
+	block.Proposer = proposer.signer.Signer().PublicKey().Address()
+	block.Timestamp = big.NewInt(genesisTimestamp + int64(block.Height)*blockInterval)
+
+	stateHash, txs, res := proposer.ledger.ProposeBlockTxs(block)
+	if res.IsError() {
+		return nil, fmt.Errorf("Failed to propose block %v: %v", block.Height, res.Message)
+	}
+	block.AddTxs(txs)
+	block.StateHash = stateHash
+	sig, err := proposer.signer.Signer().Sign(block.SignBytes())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to sign block %v: %v", block.Height, err)
+	}
+	block.SetSignature(sig)
+	block.Hash() // caches the hash before the block is shared with the nodes
+
+	// The nodes apply the block concurrently, just like in a real network
+	errs := make([]error, len(net.Nodes))
+	var wg sync.WaitGroup
+	for i, node := range net.Nodes {
+		wg.Add(1)
+		go func(i int, node *Node) {
+			defer wg.Done()
+			errs[i] = node.applyBlock(parent, block)
+		}(i, node)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	net.tip = block
+	return block, nil
+}
+
+// Stop stops all the nodes of the network
+func (net *Network) Stop() {
+	net.cancel()
+}
+
+// applyBlock adds the block to the chain of the node, executes it on top of the parent state,
+// and finalizes the resulting state
+func (node *Node) applyBlock(parent *core.Block, block *core.Block) error {
+	if _, err := node.chain.AddBlock(block); err != nil {
+		return fmt.Errorf("%v failed to add block %v: %v", node.ID, block.Height, err)
+	}
+	if res := node.ledger.ResetState(parent); res.IsError() {
+		return fmt.Errorf("%v failed to reset state to block %v: %v", node.ID, parent.Height, res.Message)
+	}
+	if res := node.ledger.ApplyBlockTxs(block); res.IsError() {
+		return fmt.Errorf("%v failed to apply block %v: %v", node.ID, block.Height, res.Message)
+	}
+	if res := node.ledger.FinalizeState(block.Height, block.StateHash); res.IsError() {
+		return fmt.Errorf("%v failed to finalize block %v: %v", node.ID, block.Height, res.Message)
+	}
+
+	// Wait for the mempool to be re-screened against the new state, so that the transactions
+	// submitted for the next block are not screened twice
+	node.ledger.WaitForMempoolUpdate()
+	return nil
+}
+
+// StateSize returns the total size of the keys and values in the state database of the node
+func (node *Node) StateSize() uint64 {
+	size := uint64(0)
+	for _, key := range node.stateDB.Keys() {
+		value, err := node.stateDB.Get(key)
+		if err != nil {
+			continue
+		}
+		size += uint64(len(key) + len(value))
+	}
+	return size
+}
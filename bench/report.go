@@ -0,0 +1,129 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// LatencyStats summarizes the latencies of a run, in milliseconds
+type LatencyStats struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// Report is the result of a benchmark run. Reports of the same scenario and configuration are
+// comparable across releases.
+type Report struct {
+	Scenario  string `json:"scenario"`
+	Version   string `json:"version"`
+	GitHash   string `json:"git_hash"`
+	GoVersion string `json:"go_version"`
+	NumCPU    int    `json:"num_cpu"`
+
+	NumNodes    int `json:"num_nodes"`
+	TxsPerBlock int `json:"txs_per_block"`
+	NumBlocks   int `json:"num_blocks"`
+	NumTxs      int `json:"num_txs"`
+
+	DurationSeconds float64      `json:"duration_seconds"`
+	TPS             float64      `json:"tps"`
+	FinalityLatency LatencyStats `json:"finality_latency_ms"`
+
+	StateBytesStart          uint64      `json:"state_bytes_start"`
+	StateBytesEnd            uint64      `json:"state_bytes_end"`
+	StateGrowthPerMillionTxs uint64      `json:"state_growth_per_million_txs"`
+	StateRoot                common.Hash `json:"state_root"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Write writes the report as JSON
+func (r *Report) Write(w io.Writer) error {
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(raw, '\n'))
+	return err
+}
+
+// LoadReport loads a report written by Report.Write from the given file
+func LoadReport(path string) (*Report, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	if err := json.Unmarshal(raw, report); err != nil {
+		return nil, fmt.Errorf("Failed to parse report %v: %v", path, err)
+	}
+	return report, nil
+}
+
+// Regression describes a metric which got worse than the baseline by more than the tolerance
+type Regression struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"` // relative change, positive when the metric got worse
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%v: %.2f -> %.2f (%+.1f%%)", r.Metric, r.Baseline, r.Current, r.Change*100)
+}
+
+// metric extracts a metric from a report
+type metric struct {
+	name           string
+	value          func(r *Report) float64
+	higherIsBetter bool
+}
+
+var comparedMetrics = []metric{
+	{"tps", func(r *Report) float64 { return r.TPS }, true},
+	{"finality_latency_ms.p50", func(r *Report) float64 { return r.FinalityLatency.P50 }, false},
+	{"finality_latency_ms.p99", func(r *Report) float64 { return r.FinalityLatency.P99 }, false},
+	{"state_growth_per_million_txs", func(r *Report) float64 { return float64(r.StateGrowthPerMillionTxs) }, false},
+}
+
+// Compare compares the current report against the baseline, and returns the metrics which got
+// worse by more than the given relative tolerance, e.g. 0.1 for 10%. Only the reports of the
+// same scenario and configuration can be compared.
+func Compare(baseline, current *Report, tolerance float64) ([]Regression, error) {
+	if baseline.Scenario != current.Scenario {
+		return nil, fmt.Errorf("Cannot compare scenario %v against %v", current.Scenario, baseline.Scenario)
+	}
+	if baseline.NumNodes != current.NumNodes || baseline.TxsPerBlock != current.TxsPerBlock || baseline.NumTxs != current.NumTxs {
+		return nil, fmt.Errorf("Cannot compare runs with different configurations: nodes %v/%v, txs per block %v/%v, txs %v/%v",
+			current.NumNodes, baseline.NumNodes, current.TxsPerBlock, baseline.TxsPerBlock, current.NumTxs, baseline.NumTxs)
+	}
+
+	regressions := []Regression{}
+	for _, m := range comparedMetrics {
+		base := m.value(baseline)
+		curr := m.value(current)
+		if base == 0 {
+			continue
+		}
+		change := (curr - base) / base
+		if m.higherIsBetter {
+			change = -change
+		}
+		if change > tolerance {
+			regressions = append(regressions, Regression{
+				Metric:   m.name,
+				Baseline: base,
+				Current:  curr,
+				Change:   change,
+			})
+		}
+	}
+	return regressions, nil
+}
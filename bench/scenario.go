@@ -0,0 +1,315 @@
+package bench
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+const (
+	// ScenarioSends sends PTX from the accounts to fresh recipients
+	ScenarioSends = "sends"
+
+	// ScenarioContracts calls a contract that writes a new storage slot on every call
+	ScenarioContracts = "contracts"
+
+	// ScenarioStakeChurn has the accounts alternately deposit and withdraw validator stakes
+	ScenarioStakeChurn = "stake_churn"
+)
+
+const (
+	contractDeployGasLimit = uint64(1000000)
+	contractCallGasLimit   = uint64(100000)
+
+	// contractCode deploys a contract which increments the counter stored in slot 0 on every
+	// call, and then stores the new counter value in the slot keyed by the counter itself:
+	//
+	//		counter := sload(0) + 1; sstore(0, counter); sstore(counter, counter)
+	contractCode = "600d80600b6000396000f3" + "60005460010180600055805500"
+)
+
+// Scenario generates the transactions of a benchmark run. The transactions are signed by
+// accounts derived from fixed secrets, so every run of a scenario produces the same chain.
+type Scenario interface {
+	// Name returns the name of the scenario
+	Name() string
+
+	// Accounts returns the accounts to be funded in the genesis state
+	Accounts() []*types.Account
+
+	// Setup returns the transactions to be included in a block before the measured ones
+	Setup(view *state.StoreView) ([]common.Bytes, error)
+
+	// NextBatch returns the next numTxs transactions to be submitted on top of the given state
+	NextBatch(view *state.StoreView, numTxs int) ([]common.Bytes, error)
+}
+
+var scenarios = map[string]func(numAccounts int) Scenario{
+	ScenarioSends:      newSendsScenario,
+	ScenarioContracts:  newContractsScenario,
+	ScenarioStakeChurn: newStakeChurnScenario,
+}
+
+// ScenarioNames returns the names of the supported scenarios
+func ScenarioNames() []string {
+	names := []string{}
+	for name := range scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewScenario creates the scenario with the given name, which signs the transactions with the
+// given number of accounts
+func NewScenario(name string, numAccounts int) (Scenario, error) {
+	create, ok := scenarios[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown scenario %v, supported scenarios: %v", name, ScenarioNames())
+	}
+	if numAccounts < 1 {
+		return nil, fmt.Errorf("The scenario needs at least one account")
+	}
+	return create(numAccounts), nil
+}
+
+// accountPool hands out the accounts signing the transactions in a round-robin fashion, and
+// keeps track of their sequences
+type accountPool struct {
+	accounts  []types.PrivAccount
+	sequences []uint64
+	next      int
+}
+
+func newAccountPool(numAccounts int) *accountPool {
+	// Large enough balances for the stake deposits
+	balance := new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(1000))
+
+	pool := &accountPool{}
+	for i := 0; i < numAccounts; i++ {
+		acc := types.PrivAccountFromSecret(fmt.Sprintf("bench_account_%v", i))
+		acc.Account = *types.NewAccount(acc.Address)
+		acc.Account.Balance = types.Coins{PandoWei: new(big.Int).Set(balance), PTXWei: new(big.Int).Set(balance)}
+		pool.accounts = append(pool.accounts, acc)
+		pool.sequences = append(pool.sequences, 0)
+	}
+	return pool
+}
+
+func (pool *accountPool) genesisAccounts() []*types.Account {
+	accounts := []*types.Account{}
+	for i := range pool.accounts {
+		accounts = append(accounts, &pool.accounts[i].Account)
+	}
+	return accounts
+}
+
+// nextSender returns the index of the account to sign the next transaction, and the sequence
+// of that transaction
+func (pool *accountPool) nextSender() (int, uint64) {
+	idx := pool.next
+	pool.next = (pool.next + 1) % len(pool.accounts)
+	return idx, pool.nextSequence(idx)
+}
+
+// nextSequence returns the sequence of the next transaction signed by the given account
+func (pool *accountPool) nextSequence(idx int) uint64 {
+	pool.sequences[idx]++
+	return pool.sequences[idx]
+}
+
+// signableTx is a transaction signed by a single account
+type signableTx interface {
+	types.Tx
+	SetSignature(addr common.Address, sig *crypto.Signature) bool
+}
+
+func (pool *accountPool) sign(idx int, tx signableTx) (common.Bytes, error) {
+	acc := pool.accounts[idx]
+	sig := acc.Sign(tx.SignBytes(ChainID))
+	if !tx.SetSignature(acc.Address, sig) {
+		return nil, fmt.Errorf("Failed to set the signature of %v", acc.Address)
+	}
+	return types.TxToBytes(tx)
+}
+
+// freshAddress derives a new address which does not hold an account yet
+func freshAddress(label string, idx int) common.Address {
+	return common.BytesToAddress(crypto.Keccak256([]byte(fmt.Sprintf("bench_%v_%v", label, idx))))
+}
+
+// sendsScenario transfers PTX to fresh recipients, so that every transaction creates an account
+type sendsScenario struct {
+	pool          *accountPool
+	numRecipients int
+}
+
+func newSendsScenario(numAccounts int) Scenario {
+	return &sendsScenario{pool: newAccountPool(numAccounts)}
+}
+
+func (s *sendsScenario) Name() string                                        { return ScenarioSends }
+func (s *sendsScenario) Accounts() []*types.Account                          { return s.pool.genesisAccounts() }
+func (s *sendsScenario) Setup(view *state.StoreView) ([]common.Bytes, error) { return nil, nil }
+
+func (s *sendsScenario) NextBatch(view *state.StoreView, numTxs int) ([]common.Bytes, error) {
+	rawTxs := []common.Bytes{}
+	for i := 0; i < numTxs; i++ {
+		idx, sequence := s.pool.nextSender()
+		recipient := freshAddress("recipient", s.numRecipients)
+		s.numRecipients++
+
+		amount := big.NewInt(1)
+		tx := &types.SendTx{
+			Outputs: []types.TxOutput{{
+				Address: recipient,
+				Coins:   types.Coins{PandoWei: big.NewInt(0), PTXWei: amount},
+			}},
+		}
+		fee := tx.MinimumFeeAt(view.GetBaseFee())
+		tx.Fee = types.Coins{PandoWei: big.NewInt(0), PTXWei: fee}
+		tx.Inputs = []types.TxInput{{
+			Address:  s.pool.accounts[idx].Address,
+			Coins:    types.Coins{PandoWei: big.NewInt(0), PTXWei: new(big.Int).Add(amount, fee)},
+			Sequence: sequence,
+		}}
+
+		rawTx, err := s.pool.sign(idx, tx)
+		if err != nil {
+			return nil, err
+		}
+		rawTxs = append(rawTxs, rawTx)
+	}
+	return rawTxs, nil
+}
+
+// contractsScenario deploys a contract, and then calls it so that every call grows the
+// contract storage by one slot
+type contractsScenario struct {
+	pool     *accountPool
+	contract common.Address
+}
+
+func newContractsScenario(numAccounts int) Scenario {
+	return &contractsScenario{pool: newAccountPool(numAccounts)}
+}
+
+func (s *contractsScenario) Name() string               { return ScenarioContracts }
+func (s *contractsScenario) Accounts() []*types.Account { return s.pool.genesisAccounts() }
+
+func (s *contractsScenario) Setup(view *state.StoreView) ([]common.Bytes, error) {
+	idx := 0 // the deployer also takes part in the calls
+	sequence := s.pool.nextSequence(idx)
+	from := s.pool.accounts[idx].Address
+	s.contract = crypto.CreateAddress(from, sequence-1)
+
+	tx := &types.SmartContractTx{
+		From: types.TxInput{
+			Address:  from,
+			Coins:    types.NewCoins(0, 0),
+			Sequence: sequence,
+		},
+		GasLimit: contractDeployGasLimit,
+		GasPrice: types.BaseGasPrice(view.GetBaseFee()),
+		Data:     common.FromHex(contractCode),
+	}
+	rawTx, err := s.pool.sign(idx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return []common.Bytes{rawTx}, nil
+}
+
+func (s *contractsScenario) NextBatch(view *state.StoreView, numTxs int) ([]common.Bytes, error) {
+	if len(view.GetCode(s.contract)) == 0 {
+		return nil, fmt.Errorf("The benchmark contract is not deployed at %v", s.contract)
+	}
+
+	gasPrice := types.BaseGasPrice(view.GetBaseFee())
+	rawTxs := []common.Bytes{}
+	for i := 0; i < numTxs; i++ {
+		idx, sequence := s.pool.nextSender()
+		tx := &types.SmartContractTx{
+			From: types.TxInput{
+				Address:  s.pool.accounts[idx].Address,
+				Coins:    types.NewCoins(0, 0),
+				Sequence: sequence,
+			},
+			To:       types.TxOutput{Address: s.contract},
+			GasLimit: contractCallGasLimit,
+			GasPrice: gasPrice,
+		}
+		rawTx, err := s.pool.sign(idx, tx)
+		if err != nil {
+			return nil, err
+		}
+		rawTxs = append(rawTxs, rawTx)
+	}
+	return rawTxs, nil
+}
+
+// stakeChurnScenario has every account deposit a validator stake to a fresh holder, and
+// withdraw it with its next transaction
+type stakeChurnScenario struct {
+	pool       *accountPool
+	holders    []*common.Address // the holder each account currently stakes to, if any
+	numHolders int
+}
+
+func newStakeChurnScenario(numAccounts int) Scenario {
+	return &stakeChurnScenario{
+		pool:    newAccountPool(numAccounts),
+		holders: make([]*common.Address, numAccounts),
+	}
+}
+
+func (s *stakeChurnScenario) Name() string                                        { return ScenarioStakeChurn }
+func (s *stakeChurnScenario) Accounts() []*types.Account                          { return s.pool.genesisAccounts() }
+func (s *stakeChurnScenario) Setup(view *state.StoreView) ([]common.Bytes, error) { return nil, nil }
+
+func (s *stakeChurnScenario) NextBatch(view *state.StoreView, numTxs int) ([]common.Bytes, error) {
+	fee := types.Coins{PandoWei: big.NewInt(0), PTXWei: view.GetBaseFee()}
+	rawTxs := []common.Bytes{}
+	for i := 0; i < numTxs; i++ {
+		idx, sequence := s.pool.nextSender()
+		source := s.pool.accounts[idx].Address
+
+		var tx signableTx
+		if holder := s.holders[idx]; holder != nil {
+			tx = &types.WithdrawStakeTx{
+				Fee:     fee,
+				Source:  types.TxInput{Address: source, Sequence: sequence},
+				Holder:  types.TxOutput{Address: *holder},
+				Purpose: core.StakeForValidator,
+			}
+			s.holders[idx] = nil
+		} else {
+			holder := freshAddress("holder", s.numHolders)
+			s.numHolders++
+			tx = &types.DepositStakeTx{
+				Fee: fee,
+				Source: types.TxInput{
+					Address:  source,
+					Coins:    types.Coins{PandoWei: big.NewInt(0), PTXWei: core.MinValidatorStakeDeposit},
+					Sequence: sequence,
+				},
+				Holder:  types.TxOutput{Address: holder},
+				Purpose: core.StakeForValidator,
+			}
+			s.holders[idx] = &holder
+		}
+
+		rawTx, err := s.pool.sign(idx, tx)
+		if err != nil {
+			return nil, err
+		}
+		rawTxs = append(rawTxs, rawTx)
+	}
+	return rawTxs, nil
+}
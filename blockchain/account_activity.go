@@ -0,0 +1,199 @@
+package blockchain
+
+import (
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// ---------------- Account Activity ---------------
+
+// MaxTrackedCounterparties is the maximum number of counterparties tracked for an address.
+const MaxTrackedCounterparties = 64
+
+// accountActivityKey constructs the DB key for the activity summary of the address.
+func accountActivityKey(addr common.Address) common.Bytes {
+	return append(common.Bytes("aa/"), addr[:]...)
+}
+
+// AccountActivity summarizes the finalized transactions sent or received by an address.
+type AccountActivity struct {
+	Address        common.Address
+	FirstHeight    uint64 // height of the block of the first transaction
+	FirstTimestamp uint64 // timestamp of the block of the first transaction
+	LastHeight     uint64 // height of the block of the last transaction
+	LastTimestamp  uint64 // timestamp of the block of the last transaction
+	LastIndex      uint64 // index of the last transaction in its block
+	TxCount        uint64
+	SentCount      uint64
+	ReceivedCount  uint64
+	Counterparties []Counterparty // the most frequent counterparties, in no particular order
+}
+
+// Counterparty is an address the account exchanged transactions with. The counts are estimates once
+// the account has had more than MaxTrackedCounterparties counterparties: a new counterparty replaces
+// the least frequent one and inherits its count, hence the frequent counterparties are never
+// evicted, but the count of a counterparty may be overestimated by up to Error.
+type Counterparty struct {
+	Address    common.Address
+	TxCount    uint64
+	Error      uint64 // upper bound of the overestimate of the count
+	LastHeight uint64
+}
+
+func (a *AccountActivity) after(height uint64, index uint64) bool {
+	return a.TxCount == 0 || height > a.LastHeight || (height == a.LastHeight && index > a.LastIndex)
+}
+
+// addCounterparty counts a transaction with the counterparty, evicting the least frequent
+// counterparty if needed
+func (a *AccountActivity) addCounterparty(addr common.Address, height uint64) {
+	for i := range a.Counterparties {
+		if a.Counterparties[i].Address == addr {
+			a.Counterparties[i].TxCount++
+			a.Counterparties[i].LastHeight = height
+			return
+		}
+	}
+	if len(a.Counterparties) < MaxTrackedCounterparties {
+		a.Counterparties = append(a.Counterparties, Counterparty{Address: addr, TxCount: 1, LastHeight: height})
+		return
+	}
+	min := 0
+	for i := range a.Counterparties {
+		cp := &a.Counterparties[i]
+		if cp.TxCount < a.Counterparties[min].TxCount ||
+			(cp.TxCount == a.Counterparties[min].TxCount && cp.LastHeight < a.Counterparties[min].LastHeight) {
+			min = i
+		}
+	}
+	evicted := a.Counterparties[min].TxCount
+	a.Counterparties[min] = Counterparty{Address: addr, TxCount: evicted + 1, Error: evicted, LastHeight: height}
+}
+
+// TopCounterparties returns up to limit counterparties, the most frequent first
+func (a *AccountActivity) TopCounterparties(limit int) []Counterparty {
+	top := append([]Counterparty{}, a.Counterparties...)
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].TxCount != top[j].TxCount {
+			return top[i].TxCount > top[j].TxCount
+		}
+		return top[i].LastHeight > top[j].LastHeight
+	})
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+// AddAccountActivity adds the transactions of the finalized block to the activity summaries of the
+// addresses sending or receiving them. Blocks need to be added in the order of their heights, and
+// adding a block twice is a no-op.
+func (ch *Chain) AddAccountActivity(block *core.ExtendedBlock) {
+	var timestamp uint64
+	if block.Timestamp != nil {
+		timestamp = block.Timestamp.Uint64()
+	}
+
+	activities := make(map[common.Address]*AccountActivity)
+	getActivity := func(addr common.Address) *AccountActivity {
+		activity, ok := activities[addr]
+		if !ok {
+			activity, ok = ch.GetAccountActivity(addr)
+			if !ok {
+				activity = &AccountActivity{Address: addr}
+			}
+			activities[addr] = activity
+		}
+		return activity
+	}
+
+	for idx, raw := range block.Txs {
+		tx, err := types.TxFromBytes(raw)
+		if err != nil {
+			logger.Warnf("Failed to parse transaction %v of block %v: %v", idx, block.Hash().Hex(), err)
+			continue
+		}
+		senders, receivers := types.GetTxAddresses(tx)
+		// The contract created by the transaction
+		if receipt, ok := ch.FindTxReceiptByHash(crypto.Keccak256Hash(raw)); ok && receipt.ContractAddress != (common.Address{}) {
+			receivers = append(receivers, receipt.ContractAddress)
+		}
+
+		sent := make(map[common.Address]bool)
+		received := make(map[common.Address]bool)
+		for _, addr := range senders {
+			sent[addr] = true
+		}
+		for _, addr := range receivers {
+			received[addr] = true
+		}
+
+		update := func(addr common.Address, counterparties []common.Address) {
+			activity := getActivity(addr)
+			if !activity.after(block.Height, uint64(idx)) {
+				return
+			}
+			if activity.TxCount == 0 {
+				activity.FirstHeight = block.Height
+				activity.FirstTimestamp = timestamp
+			}
+			activity.LastHeight = block.Height
+			activity.LastTimestamp = timestamp
+			activity.LastIndex = uint64(idx)
+			activity.TxCount++
+			if sent[addr] {
+				activity.SentCount++
+			}
+			if received[addr] {
+				activity.ReceivedCount++
+			}
+			seen := map[common.Address]bool{addr: true}
+			for _, cp := range counterparties {
+				if seen[cp] {
+					continue
+				}
+				seen[cp] = true
+				activity.addCounterparty(cp, block.Height)
+			}
+		}
+
+		// The counterparties of a sender are the receivers, and the other way around
+		for addr := range sent {
+			update(addr, receivers)
+		}
+		for addr := range received {
+			if !sent[addr] {
+				update(addr, senders)
+			}
+		}
+	}
+
+	for addr, activity := range activities {
+		if activity.TxCount == 0 {
+			continue
+		}
+		err := ch.store.Put(accountActivityKey(addr), *activity)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+}
+
+// GetAccountActivity returns the activity summary of the address, false if it has no finalized
+// transaction since the index was enabled.
+func (ch *Chain) GetAccountActivity(addr common.Address) (*AccountActivity, bool) {
+	activity := &AccountActivity{}
+	err := ch.store.Get(accountActivityKey(addr), activity)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return activity, true
+}
@@ -0,0 +1,106 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountActivity(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	carol := common.HexToAddress("0x3")
+	newSendTx := func(from, to common.Address, seq int) common.Bytes {
+		tx := &types.SendTx{
+			Fee:     types.NewCoins(0, 1),
+			Inputs:  []types.TxInput{types.NewTxInput(from, types.NewCoins(0, 10), seq)},
+			Outputs: []types.TxOutput{{Address: to, Coins: types.NewCoins(0, 9)}},
+		}
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		return raw
+	}
+
+	chain := CreateTestChain()
+
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Timestamp = big.NewInt(1000)
+	block1.Txs = []common.Bytes{newSendTx(alice, bob, 1), common.Bytes("invalid"), newSendTx(bob, carol, 1)}
+	block1.UpdateHash()
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	block2 := core.CreateTestBlock("b2", "b1")
+	block2.Height = 11
+	block2.Timestamp = big.NewInt(1010)
+	block2.Txs = []common.Bytes{newSendTx(alice, bob, 2), newSendTx(alice, carol, 3)}
+	block2.UpdateHash()
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+
+	chain.AddAccountActivity(eb1)
+	chain.AddAccountActivity(eb2)
+	// Adding a block again does not count its transactions twice
+	chain.AddAccountActivity(eb1)
+
+	activity, ok := chain.GetAccountActivity(alice)
+	require.True(ok)
+	assert.Equal(uint64(10), activity.FirstHeight)
+	assert.Equal(uint64(1000), activity.FirstTimestamp)
+	assert.Equal(uint64(11), activity.LastHeight)
+	assert.Equal(uint64(1010), activity.LastTimestamp)
+	assert.Equal(uint64(3), activity.TxCount)
+	assert.Equal(uint64(3), activity.SentCount)
+	assert.Equal(uint64(0), activity.ReceivedCount)
+	top := activity.TopCounterparties(1)
+	require.Equal(1, len(top))
+	assert.Equal(bob, top[0].Address)
+	assert.Equal(uint64(2), top[0].TxCount)
+
+	activity, ok = chain.GetAccountActivity(bob)
+	require.True(ok)
+	assert.Equal(uint64(3), activity.TxCount)
+	assert.Equal(uint64(1), activity.SentCount)
+	assert.Equal(uint64(2), activity.ReceivedCount)
+	top = activity.TopCounterparties(10)
+	require.Equal(2, len(top))
+	assert.Equal(alice, top[0].Address)
+	assert.Equal(carol, top[1].Address)
+
+	_, ok = chain.GetAccountActivity(common.HexToAddress("0x4"))
+	assert.False(ok)
+}
+
+func TestAccountActivityCounterpartyEviction(t *testing.T) {
+	assert := assert.New(t)
+
+	activity := &AccountActivity{}
+	frequent := common.HexToAddress("0xff")
+	for i := 0; i < 3; i++ {
+		activity.addCounterparty(frequent, 1)
+	}
+	for i := 1; i <= MaxTrackedCounterparties; i++ {
+		activity.addCounterparty(common.BigToAddress(big.NewInt(int64(i))), uint64(i))
+	}
+	assert.Equal(MaxTrackedCounterparties, len(activity.Counterparties))
+
+	// The frequent counterparty is kept, the new one replaces the oldest of the least frequent ones
+	top := activity.TopCounterparties(2)
+	assert.Equal(frequent, top[0].Address)
+	assert.Equal(uint64(3), top[0].TxCount)
+	assert.Equal(common.BigToAddress(big.NewInt(int64(MaxTrackedCounterparties))), top[1].Address)
+	assert.Equal(uint64(2), top[1].TxCount)
+	assert.Equal(uint64(1), top[1].Error)
+	for _, cp := range activity.Counterparties {
+		assert.NotEqual(common.BigToAddress(big.NewInt(1)), cp.Address)
+	}
+}
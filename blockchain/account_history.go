@@ -0,0 +1,150 @@
+package blockchain
+
+import (
+	"encoding/binary"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// ---------------- Account History ---------------
+
+// accountTxCountKey constructs the DB key for the number of transactions in the history of the address.
+func accountTxCountKey(addr common.Address) common.Bytes {
+	return append(common.Bytes("ah/n/"), addr[:]...)
+}
+
+// accountTxKey constructs the DB key for the transaction at the given position in the history of
+// the address. The positions follow the (block height, tx index) order of the transactions.
+func accountTxKey(addr common.Address, position uint64) common.Bytes {
+	key := append(common.Bytes("ah/t/"), addr[:]...)
+	pos := make([]byte, 8)
+	binary.BigEndian.PutUint64(pos, position)
+	return append(key, pos...)
+}
+
+// AccountTxEntry locates a transaction sent or received by an account.
+type AccountTxEntry struct {
+	TxHash      common.Hash
+	BlockHash   common.Hash
+	BlockHeight uint64
+	Index       uint64
+	Sent        bool // the account sent the coins or signed the transaction
+	Received    bool // the account received the coins or was acted upon by the transaction
+}
+
+func (e *AccountTxEntry) after(height uint64, index uint64) bool {
+	return e.BlockHeight > height || (e.BlockHeight == height && e.Index > index)
+}
+
+// AddTxsToAccountHistory appends the transactions of the finalized block to the history of the
+// accounts sending or receiving them. Blocks need to be added in the order of their heights, and
+// adding a block twice is a no-op.
+func (ch *Chain) AddTxsToAccountHistory(block *core.ExtendedBlock) {
+	for idx, raw := range block.Txs {
+		tx, err := types.TxFromBytes(raw)
+		if err != nil {
+			logger.Warnf("Failed to parse transaction %v of block %v: %v", idx, block.Hash().Hex(), err)
+			continue
+		}
+		txHash := crypto.Keccak256Hash(raw)
+
+		entries := make(map[common.Address]*AccountTxEntry)
+		getEntry := func(addr common.Address) *AccountTxEntry {
+			entry, ok := entries[addr]
+			if !ok {
+				entry = &AccountTxEntry{
+					TxHash:      txHash,
+					BlockHash:   block.Hash(),
+					BlockHeight: block.Height,
+					Index:       uint64(idx),
+				}
+				entries[addr] = entry
+			}
+			return entry
+		}
+
+		senders, receivers := types.GetTxAddresses(tx)
+		for _, addr := range senders {
+			getEntry(addr).Sent = true
+		}
+		for _, addr := range receivers {
+			getEntry(addr).Received = true
+		}
+		// The contract created by the transaction
+		if receipt, ok := ch.FindTxReceiptByHash(txHash); ok && receipt.ContractAddress != (common.Address{}) {
+			getEntry(receipt.ContractAddress).Received = true
+		}
+
+		for addr, entry := range entries {
+			ch.addAccountTx(addr, entry)
+		}
+	}
+}
+
+func (ch *Chain) addAccountTx(addr common.Address, entry *AccountTxEntry) {
+	count := ch.GetAccountTxCount(addr)
+	if count > 0 {
+		last, ok := ch.getAccountTx(addr, count-1)
+		if ok && !entry.after(last.BlockHeight, last.Index) {
+			return
+		}
+	}
+
+	// Write the entry before the count, so that the count never points past the last entry
+	err := ch.store.Put(accountTxKey(addr, count), *entry)
+	if err != nil {
+		logger.Panic(err)
+	}
+	err = ch.store.Put(accountTxCountKey(addr), count+1)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// GetAccountTxCount returns the number of transactions in the history of the account.
+func (ch *Chain) GetAccountTxCount(addr common.Address) uint64 {
+	var count uint64
+	err := ch.store.Get(accountTxCountKey(addr), &count)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return 0
+	}
+	return count
+}
+
+func (ch *Chain) getAccountTx(addr common.Address, position uint64) (*AccountTxEntry, bool) {
+	entry := &AccountTxEntry{}
+	err := ch.store.Get(accountTxKey(addr, position), entry)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return entry, true
+}
+
+// GetAccountTxs returns up to limit transactions in the history of the account, skipping the
+// first offset ones. The oldest transactions come first, unless newestFirst is set.
+func (ch *Chain) GetAccountTxs(addr common.Address, offset uint64, limit uint64, newestFirst bool) []*AccountTxEntry {
+	entries := []*AccountTxEntry{}
+	count := ch.GetAccountTxCount(addr)
+	for i := offset; i < count && uint64(len(entries)) < limit; i++ {
+		position := i
+		if newestFirst {
+			position = count - 1 - i
+		}
+		entry, ok := ch.getAccountTx(addr, position)
+		if !ok {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
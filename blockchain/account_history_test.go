@@ -0,0 +1,84 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHistory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	carol := common.HexToAddress("0x3")
+	newSendTx := func(from, to common.Address, seq int) common.Bytes {
+		tx := &types.SendTx{
+			Fee:     types.NewCoins(0, 1),
+			Inputs:  []types.TxInput{types.NewTxInput(from, types.NewCoins(0, 10), seq)},
+			Outputs: []types.TxOutput{{Address: to, Coins: types.NewCoins(0, 9)}},
+		}
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		return raw
+	}
+
+	chain := CreateTestChain()
+
+	tx1 := newSendTx(alice, bob, 1)
+	tx2 := newSendTx(bob, carol, 1)
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{tx1, common.Bytes("invalid"), tx2}
+	block1.UpdateHash()
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	tx3 := newSendTx(alice, alice, 2)
+	block2 := core.CreateTestBlock("b2", "b1")
+	block2.Height = 11
+	block2.Txs = []common.Bytes{tx3}
+	block2.UpdateHash()
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+
+	chain.AddTxsToAccountHistory(eb1)
+	chain.AddTxsToAccountHistory(eb2)
+	// Adding a block again does not duplicate its transactions
+	chain.AddTxsToAccountHistory(eb1)
+
+	assert.Equal(uint64(2), chain.GetAccountTxCount(alice))
+	assert.Equal(uint64(2), chain.GetAccountTxCount(bob))
+	assert.Equal(uint64(1), chain.GetAccountTxCount(carol))
+	assert.Equal(uint64(0), chain.GetAccountTxCount(common.HexToAddress("0x4")))
+
+	entries := chain.GetAccountTxs(bob, 0, 10, false)
+	require.Equal(2, len(entries))
+	assert.Equal(crypto.Keccak256Hash(tx1), entries[0].TxHash)
+	assert.Equal(block1.Hash(), entries[0].BlockHash)
+	assert.Equal(uint64(10), entries[0].BlockHeight)
+	assert.Equal(uint64(0), entries[0].Index)
+	assert.False(entries[0].Sent)
+	assert.True(entries[0].Received)
+	assert.Equal(crypto.Keccak256Hash(tx2), entries[1].TxHash)
+	assert.Equal(uint64(2), entries[1].Index)
+	assert.True(entries[1].Sent)
+	assert.False(entries[1].Received)
+
+	// Newest first, and paginated
+	entries = chain.GetAccountTxs(alice, 0, 1, true)
+	require.Equal(1, len(entries))
+	assert.Equal(crypto.Keccak256Hash(tx3), entries[0].TxHash)
+	assert.True(entries[0].Sent)
+	assert.True(entries[0].Received)
+	entries = chain.GetAccountTxs(alice, 1, 1, true)
+	require.Equal(1, len(entries))
+	assert.Equal(crypto.Keccak256Hash(tx1), entries[0].TxHash)
+	assert.Equal(0, len(chain.GetAccountTxs(alice, 2, 1, true)))
+}
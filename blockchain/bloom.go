@@ -0,0 +1,49 @@
+package blockchain
+
+import (
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// blockBloomKey constructs the DB key for the bloom filter of the given block hash.
+func blockBloomKey(hash common.Hash) common.Bytes {
+	return append(common.Bytes("bb/"), hash[:]...)
+}
+
+// AddBlockBloom indexes the bloom filter of the addresses and topics of the logs emitted by the
+// transactions of the block. It needs to be called after the block is executed, once the receipts
+// of its transactions are recorded.
+func (ch *Chain) AddBlockBloom(block *core.ExtendedBlock) core.Bloom {
+	logs := []*types.Log{}
+	for _, rawTx := range block.Txs {
+		receipt, found := ch.FindTxReceiptByHash(crypto.Keccak256Hash(rawTx))
+		if !found {
+			continue
+		}
+		logs = append(logs, receipt.Logs...)
+	}
+	bloom := types.LogsBloom(logs)
+
+	err := ch.store.Put(blockBloomKey(block.Hash()), bloom)
+	if err != nil {
+		logger.Panic(err)
+	}
+	return bloom
+}
+
+// FindBlockBloom looks up the bloom filter of the block. The blocks indexed before the bloom
+// filters were introduced, or imported from a snapshot, have no bloom filter.
+func (ch *Chain) FindBlockBloom(hash common.Hash) (core.Bloom, bool) {
+	bloom := core.Bloom{}
+	err := ch.store.Get(blockBloomKey(hash), &bloom)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return core.Bloom{}, false
+	}
+	return bloom, true
+}
@@ -0,0 +1,65 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockBloom(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	contract := common.HexToAddress("0x3535353535353535353535353535353535353535")
+	transfer := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	approval := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+	sctx := &types.SmartContractTx{
+		From:     types.NewTxInput(common.HexToAddress("0x1"), types.NewCoins(0, 0), 1),
+		To:       types.TxOutput{Address: contract},
+		GasLimit: 21000,
+		GasPrice: big.NewInt(4000000000000),
+	}
+	raw, err := types.TxToBytes(sctx)
+	require.Nil(err)
+
+	chain := CreateTestChain()
+	chain.AddTxReceipt(sctx, []*types.Log{{Address: contract, Topics: []common.Hash{transfer}}},
+		common.Bytes{}, common.Address{}, 21000, nil)
+
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{common.Bytes("tx1"), raw}
+	block1.UpdateHash()
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	_, found := chain.FindBlockBloom(block1.Hash())
+	assert.False(found)
+
+	chain.AddBlockBloom(eb1)
+	bloom, found := chain.FindBlockBloom(block1.Hash())
+	require.True(found)
+	assert.True(bloom.TestBytes(contract.Bytes()))
+	assert.True(bloom.TestBytes(transfer.Bytes()))
+	assert.False(bloom.TestBytes(approval.Bytes()))
+	assert.Equal(types.LogsBloom([]*types.Log{{Address: contract, Topics: []common.Hash{transfer}}}), bloom)
+
+	// The blocks without logs have an empty bloom filter
+	block2 := core.CreateTestBlock("b2", "b1")
+	block2.Height = 11
+	block2.Txs = []common.Bytes{common.Bytes("tx2")}
+	block2.UpdateHash()
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+	chain.AddBlockBloom(eb2)
+	bloom, found = chain.FindBlockBloom(block2.Hash())
+	require.True(found)
+	assert.Equal(core.Bloom{}, bloom)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/store/freezer"
 )
 
 const maxDistance = 2000
@@ -25,6 +26,9 @@ type Chain struct {
 	root    common.Hash
 
 	mu *sync.RWMutex
+
+	freezer  *freezer.Freezer
+	freezeMu sync.Mutex
 }
 
 // NewChain creates a new Chain instance.
@@ -71,9 +75,8 @@ func (ch *Chain) addBlock(block *core.Block, isSnapshotRoot bool) (*core.Extende
 		return nil, errors.Errorf("ChainID mismatch: block.ChainID(%s) != %s", block.ChainID, ch.ChainID)
 	}
 
-	val := &core.ExtendedBlock{}
 	hash := block.Hash()
-	err := ch.store.Get(hash[:], val)
+	val, err := ch.findBlock(hash)
 	if err == nil {
 		// Block has already been added.
 		return val, fmt.Errorf("Block has already been added: %X", hash[:])
@@ -320,6 +323,10 @@ func (ch *Chain) FindBlock(hash common.Hash) (*core.ExtendedBlock, error) {
 func (ch *Chain) findBlock(hash common.Hash) (*core.ExtendedBlock, error) {
 	var block core.ExtendedBlock
 	err := ch.store.Get(hash[:], &block)
+	if err == store.ErrKeyNotFound {
+		// The old finalized blocks are moved into the freezer
+		return ch.findFrozenBlock(hash)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -346,8 +353,7 @@ func (ch *Chain) IsDescendant(ascendantHash common.Hash, descendantHash common.H
 func (ch *Chain) PrintBranch(hash common.Hash) string {
 	ret := []string{}
 	for {
-		var currBlock core.ExtendedBlock
-		err := ch.store.Get(hash[:], &currBlock)
+		currBlock, err := ch.findBlock(hash)
 		if err != nil {
 			break
 		}
@@ -0,0 +1,294 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// ---------------- Fee Statistics ---------------
+
+// FeeStatsGranularity is the period covered by a fee statistics bucket.
+type FeeStatsGranularity byte
+
+const (
+	// FeeStatsBlock buckets are keyed by the block height
+	FeeStatsBlock FeeStatsGranularity = iota
+	// FeeStatsHour buckets are keyed by the unix time of the start of the hour
+	FeeStatsHour
+	// FeeStatsDay buckets are keyed by the unix time of the start of the (UTC) day
+	FeeStatsDay
+)
+
+const (
+	feeStatsHourSeconds = 3600
+	feeStatsDaySeconds  = 24 * feeStatsHourSeconds
+)
+
+// Interval returns the distance between the starts of two consecutive buckets, in blocks for
+// FeeStatsBlock, and in seconds otherwise.
+func (g FeeStatsGranularity) Interval() uint64 {
+	switch g {
+	case FeeStatsHour:
+		return feeStatsHourSeconds
+	case FeeStatsDay:
+		return feeStatsDaySeconds
+	}
+	return 1
+}
+
+// BucketStart returns the start of the bucket containing the given height or unix time.
+func (g FeeStatsGranularity) BucketStart(pos uint64) uint64 {
+	return pos - pos%g.Interval()
+}
+
+func (g FeeStatsGranularity) prefix() string {
+	switch g {
+	case FeeStatsHour:
+		return "fs/h/"
+	case FeeStatsDay:
+		return "fs/d/"
+	}
+	return "fs/b/"
+}
+
+// feeStatsKey constructs the DB key for the fee statistics bucket starting at the given position.
+func feeStatsKey(granularity FeeStatsGranularity, start uint64) common.Bytes {
+	pos := make([]byte, 8)
+	binary.BigEndian.PutUint64(pos, start)
+	return append(common.Bytes(granularity.prefix()), pos...)
+}
+
+// feeStatsLastHeightKey constructs the DB key for the height of the last block added to the fee statistics.
+func feeStatsLastHeightKey() common.Bytes {
+	return common.Bytes("fs/last")
+}
+
+// TxTypeFeeStats aggregates the fees of the transactions of a type.
+type TxTypeFeeStats struct {
+	Type     types.TxType
+	NumTxs   uint64
+	TotalFee types.Coins
+}
+
+// FeeStats aggregates the fees of the transactions of the finalized blocks in a bucket. Since all
+// the transaction fees are burned, Burned equals TotalFee, it is tracked separately so that the
+// consumers do not need to rely on the fee policy of the chain.
+type FeeStats struct {
+	Start     uint64 // the height or the unix time of the start of the bucket
+	NumBlocks uint64
+	NumTxs    uint64
+	TotalFee  types.Coins
+	Burned    types.Coins
+	ByType    []*TxTypeFeeStats // sorted by the transaction type
+}
+
+// NewFeeStats creates an empty bucket starting at the given position.
+func NewFeeStats(start uint64) *FeeStats {
+	return &FeeStats{
+		Start:    start,
+		TotalFee: types.NewCoins(0, 0),
+		Burned:   types.NewCoins(0, 0),
+		ByType:   []*TxTypeFeeStats{},
+	}
+}
+
+// Merge adds the statistics of another bucket to the bucket.
+func (fs *FeeStats) Merge(other *FeeStats) {
+	fs.NumBlocks += other.NumBlocks
+	fs.NumTxs += other.NumTxs
+	fs.TotalFee = fs.TotalFee.NoNil().Plus(other.TotalFee.NoNil())
+	fs.Burned = fs.Burned.NoNil().Plus(other.Burned.NoNil())
+	for _, ts := range other.ByType {
+		fs.addTxs(ts.Type, ts.NumTxs, ts.TotalFee)
+	}
+}
+
+func (fs *FeeStats) addTxs(txType types.TxType, numTxs uint64, fee types.Coins) {
+	idx := sort.Search(len(fs.ByType), func(i int) bool { return fs.ByType[i].Type >= txType })
+	if idx == len(fs.ByType) || fs.ByType[idx].Type != txType {
+		fs.ByType = append(fs.ByType, nil)
+		copy(fs.ByType[idx+1:], fs.ByType[idx:])
+		fs.ByType[idx] = &TxTypeFeeStats{Type: txType, TotalFee: types.NewCoins(0, 0)}
+	}
+	ts := fs.ByType[idx]
+	ts.NumTxs += numTxs
+	ts.TotalFee = ts.TotalFee.NoNil().Plus(fee.NoNil())
+}
+
+// AddFeeStats adds the fees of the transactions of the finalized block to the per-block statistics,
+// and to the hourly and daily rollups of the block timestamp. Blocks need to be added in the order
+// of their heights, and adding a block twice is a no-op.
+func (ch *Chain) AddFeeStats(block *core.ExtendedBlock) {
+	if last, ok := ch.getFeeStatsLastHeight(); ok && block.Height <= last {
+		return
+	}
+
+	stats := NewFeeStats(block.Height)
+	stats.NumBlocks = 1
+	for idx, raw := range block.Txs {
+		tx, err := types.TxFromBytes(raw)
+		if err != nil {
+			logger.Warnf("Failed to parse transaction %v of block %v: %v", idx, block.Hash().Hex(), err)
+			continue
+		}
+		txType, err := types.GetTxType(tx)
+		if err != nil {
+			continue
+		}
+		fee := ch.GetTxFee(tx, crypto.Keccak256Hash(raw))
+
+		stats.NumTxs++
+		stats.TotalFee = stats.TotalFee.Plus(fee)
+		stats.addTxs(txType, 1, fee)
+	}
+	stats.Burned = stats.TotalFee
+
+	ch.putFeeStats(FeeStatsBlock, stats)
+	var timestamp uint64
+	if block.Timestamp != nil {
+		timestamp = block.Timestamp.Uint64()
+	}
+	for _, granularity := range []FeeStatsGranularity{FeeStatsHour, FeeStatsDay} {
+		start := granularity.BucketStart(timestamp)
+		rollup, ok := ch.GetFeeStats(granularity, start)
+		if !ok {
+			rollup = NewFeeStats(start)
+		}
+		rollup.Merge(stats)
+		ch.putFeeStats(granularity, rollup)
+	}
+
+	// Write the height last, so that an interrupted update is retried
+	err := ch.store.Put(feeStatsLastHeightKey(), block.Height)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// GetTxFee returns the fee charged for the transaction. A smart contract transaction is charged
+// for the gas it used rather than its gas limit.
+func (ch *Chain) GetTxFee(tx types.Tx, txHash common.Hash) types.Coins {
+	switch tx := tx.(type) {
+	case *types.SmartContractTx:
+		receipt, ok := ch.FindTxReceiptByHash(txHash)
+		if !ok || tx.GasPrice == nil {
+			return types.NewCoins(0, 0)
+		}
+		return types.Coins{
+			PandoWei: big.NewInt(0),
+			PTXWei:   new(big.Int).Mul(tx.GasPrice, new(big.Int).SetUint64(receipt.GasUsed)),
+		}
+	case *types.SendTx:
+		return tx.Fee.NoNil()
+	case *types.RametronStakeTx:
+		return tx.Fee.NoNil()
+	case *types.BatchSendTx:
+		return tx.Fee.NoNil()
+	case *types.ReserveFundTx:
+		return tx.Fee.NoNil()
+	case *types.ReleaseFundTx:
+		return tx.Fee.NoNil()
+	case *types.ServicePaymentTx:
+		return tx.Fee.NoNil()
+	case *types.SplitRuleTx:
+		return tx.Fee.NoNil()
+	case *types.DepositStakeTx:
+		return tx.Fee.NoNil()
+	case *types.DepositStakeTxV2:
+		return tx.Fee.NoNil()
+	case *types.WithdrawStakeTx:
+		return tx.Fee.NoNil()
+	case *types.SessionKeyTx:
+		return tx.Fee.NoNil()
+	case *types.SlashAppealTx:
+		return tx.Fee.NoNil()
+	case *types.SlashAppealVoteTx:
+		return tx.Fee.NoNil()
+	case *types.ClaimEscrowTx:
+		return tx.Fee.NoNil()
+	case *types.MeteredSettlementTx:
+		return tx.Fee.NoNil()
+	case *types.DelegateTx:
+		return tx.Fee.NoNil()
+	case *types.UndelegateTx:
+		return tx.Fee.NoNil()
+	case *types.SetCommissionTx:
+		return tx.Fee.NoNil()
+	case *types.RametronAttestationTx:
+		return tx.Fee.NoNil()
+	case *types.KeyRotationTx:
+		return tx.Fee.NoNil()
+	case *types.HTLCCreateTx:
+		return tx.Fee.NoNil()
+	case *types.HTLCClaimTx:
+		return tx.Fee.NoNil()
+	case *types.HTLCRefundTx:
+		return tx.Fee.NoNil()
+	case *types.ProposalTx:
+		return tx.Fee.NoNil()
+	case *types.VoteTx:
+		return tx.Fee.NoNil()
+	case *types.BridgeLockTx:
+		return tx.Fee.NoNil()
+	case *types.BridgeBurnTx:
+		return tx.Fee.NoNil()
+	case *types.BridgeClaimTx:
+		return tx.Fee.NoNil()
+	case *types.SubchainRegisterTx:
+		return tx.Fee.NoNil()
+	case *types.SubchainStakeTx:
+		return tx.Fee.NoNil()
+	case *types.SubchainCheckpointTx:
+		return tx.Fee.NoNil()
+	case *types.SubchainChallengeTx:
+		return tx.Fee.NoNil()
+	}
+	// The coinbase and slash transactions are free
+	return types.NewCoins(0, 0)
+}
+
+func (ch *Chain) getFeeStatsLastHeight() (uint64, bool) {
+	var height uint64
+	err := ch.store.Get(feeStatsLastHeightKey(), &height)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return 0, false
+	}
+	return height, true
+}
+
+func (ch *Chain) putFeeStats(granularity FeeStatsGranularity, stats *FeeStats) {
+	err := ch.store.Put(feeStatsKey(granularity, stats.Start), *stats)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// GetFeeStats returns the fee statistics bucket of the granularity starting at the given position.
+// The start of a block bucket is the block height, and the start of an hourly or daily bucket is
+// the unix time of the start of the period.
+func (ch *Chain) GetFeeStats(granularity FeeStatsGranularity, start uint64) (*FeeStats, bool) {
+	stats := &FeeStats{}
+	err := ch.store.Get(feeStatsKey(granularity, start), stats)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	stats.TotalFee = stats.TotalFee.NoNil()
+	stats.Burned = stats.Burned.NoNil()
+	if stats.ByType == nil {
+		stats.ByType = []*TxTypeFeeStats{}
+	}
+	return stats, true
+}
@@ -0,0 +1,115 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeStats(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	toBytes := func(tx types.Tx) common.Bytes {
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		return raw
+	}
+	newSendTx := func(seq int, fee int64) common.Bytes {
+		return toBytes(&types.SendTx{
+			Fee:     types.NewCoins(0, fee),
+			Inputs:  []types.TxInput{types.NewTxInput(alice, types.NewCoins(0, 10+fee), seq)},
+			Outputs: []types.TxOutput{{Address: bob, Coins: types.NewCoins(0, 10)}},
+		})
+	}
+
+	chain := CreateTestChain()
+
+	// A smart contract transaction is charged for the gas it used
+	scTx := &types.SmartContractTx{
+		From:     types.NewTxInput(alice, types.NewCoins(0, 0), 3),
+		To:       types.TxOutput{Address: bob},
+		GasLimit: 100000,
+		GasPrice: big.NewInt(4),
+	}
+	chain.AddTxReceipt(scTx, nil, nil, common.Address{}, 21000, nil)
+
+	hour := uint64(3600)
+	day := 24 * hour
+	base := 10 * day
+
+	block1 := core.CreateTestBlock("fs1", "")
+	block1.Height = 10
+	block1.Timestamp = new(big.Int).SetUint64(base + 5)
+	block1.Txs = []common.Bytes{newSendTx(1, 100), common.Bytes("invalid"), newSendTx(2, 300)}
+	block1.UpdateHash()
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	block2 := core.CreateTestBlock("fs2", "fs1")
+	block2.Height = 11
+	block2.Timestamp = new(big.Int).SetUint64(base + 100)
+	block2.Txs = []common.Bytes{toBytes(scTx)}
+	block2.UpdateHash()
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+
+	block3 := core.CreateTestBlock("fs3", "fs2")
+	block3.Height = 12
+	block3.Timestamp = new(big.Int).SetUint64(base + hour + 1)
+	block3.Txs = []common.Bytes{}
+	block3.UpdateHash()
+	eb3, err := chain.AddBlock(block3)
+	require.Nil(err)
+
+	chain.AddFeeStats(eb1)
+	chain.AddFeeStats(eb2)
+	chain.AddFeeStats(eb3)
+	// Adding a block again does not count its fees twice
+	chain.AddFeeStats(eb2)
+
+	stats, ok := chain.GetFeeStats(FeeStatsBlock, 10)
+	require.True(ok)
+	assert.Equal(uint64(1), stats.NumBlocks)
+	assert.Equal(uint64(2), stats.NumTxs)
+	assert.Equal(int64(400), stats.TotalFee.PTXWei.Int64())
+	assert.Equal(int64(400), stats.Burned.PTXWei.Int64())
+	require.Equal(1, len(stats.ByType))
+	assert.Equal(types.TxSend, stats.ByType[0].Type)
+	assert.Equal(uint64(2), stats.ByType[0].NumTxs)
+
+	_, ok = chain.GetFeeStats(FeeStatsBlock, 13)
+	assert.False(ok)
+
+	stats, ok = chain.GetFeeStats(FeeStatsHour, base)
+	require.True(ok)
+	assert.Equal(base, stats.Start)
+	assert.Equal(uint64(2), stats.NumBlocks)
+	assert.Equal(uint64(3), stats.NumTxs)
+	assert.Equal(int64(400+4*21000), stats.TotalFee.PTXWei.Int64())
+	require.Equal(2, len(stats.ByType))
+	assert.Equal(types.TxSend, stats.ByType[0].Type)
+	assert.Equal(int64(400), stats.ByType[0].TotalFee.PTXWei.Int64())
+	assert.Equal(types.TxSmartContract, stats.ByType[1].Type)
+	assert.Equal(uint64(1), stats.ByType[1].NumTxs)
+	assert.Equal(int64(4*21000), stats.ByType[1].TotalFee.PTXWei.Int64())
+
+	stats, ok = chain.GetFeeStats(FeeStatsHour, base+hour)
+	require.True(ok)
+	assert.Equal(uint64(1), stats.NumBlocks)
+	assert.Equal(uint64(0), stats.NumTxs)
+	assert.Equal(int64(0), stats.TotalFee.PTXWei.Int64())
+
+	stats, ok = chain.GetFeeStats(FeeStatsDay, FeeStatsDay.BucketStart(base+hour+1))
+	require.True(ok)
+	assert.Equal(uint64(3), stats.NumBlocks)
+	assert.Equal(uint64(3), stats.NumTxs)
+	assert.Equal(int64(400+4*21000), stats.Burned.PTXWei.Int64())
+}
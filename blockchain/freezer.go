@@ -0,0 +1,215 @@
+package blockchain
+
+import (
+	"errors"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/store/freezer"
+)
+
+const (
+	freezerBlockTable   = "blocks"
+	freezerVoteTable    = "votes"
+	freezerReceiptTable = "receipts"
+)
+
+// FreezerTables are the tables of the freezer of the finalized blocks, which are numbered by height
+var FreezerTables = []string{freezerBlockTable, freezerVoteTable, freezerReceiptTable}
+
+// frozenBlockKey constructs the DB key of the height of a frozen block, which locates the block by
+// hash in the freezer.
+func frozenBlockKey(hash common.Hash) common.Bytes {
+	return append(common.Bytes("fz/"), hash[:]...)
+}
+
+// SetFreezer sets the freezer which the old finalized blocks are moved into.
+func (ch *Chain) SetFreezer(f *freezer.Freezer) {
+	ch.freezer = f
+}
+
+// FreezeBlocks moves the finalized blocks up to the given height, along with their votes and the
+// receipts of their transactions, from the key-value store into the freezer. At most maxBlocks
+// blocks are moved at once, so that a node enabling the freezer catches up gradually. It returns
+// the number of blocks moved.
+func (ch *Chain) FreezeBlocks(height uint64, maxBlocks int) (int, error) {
+	if ch.freezer == nil {
+		return 0, errors.New("The freezer is not enabled")
+	}
+	ch.freezeMu.Lock()
+	defer ch.freezeMu.Unlock()
+
+	next, ok := ch.freezer.Head()
+	if !ok {
+		root := ch.Root()
+		if root == nil {
+			return 0, errors.New("Failed to find the root block")
+		}
+		// The root block stays in the key-value store, where the chain is loaded from
+		next = root.Height + 1
+	}
+
+	// Append the blocks to the freezer, and make sure they are on the disk, before removing them
+	// from the key-value store
+	blocks := []*core.ExtendedBlock{}
+	receipts := [][]common.Hash{}
+	for ; next <= height && len(blocks) < maxBlocks; next++ {
+		block := ch.findFinalizedBlock(next)
+		if block == nil {
+			break
+		}
+		txHashes, err := ch.appendToFreezer(block)
+		if err != nil {
+			return 0, err
+		}
+		blocks = append(blocks, block)
+		receipts = append(receipts, txHashes)
+	}
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+	if err := ch.freezer.Sync(); err != nil {
+		return 0, err
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	for i, block := range blocks {
+		hash := block.Hash()
+		if err := ch.store.Put(frozenBlockKey(hash), block.Height); err != nil {
+			return i, err
+		}
+		ch.store.Delete(hash[:])
+		ch.store.Delete(voteIndexKey(hash))
+		for _, txHash := range receipts[i] {
+			ch.store.Delete(txReceiptKey(txHash))
+		}
+	}
+	logger.Infof("Moved the finalized blocks from height %v to %v into the freezer", blocks[0].Height, blocks[len(blocks)-1].Height)
+	return len(blocks), nil
+}
+
+// findFinalizedBlock returns the finalized block at the height, nil if there is none
+func (ch *Chain) findFinalizedBlock(height uint64) *core.ExtendedBlock {
+	for _, block := range ch.FindBlocksByHeight(height) {
+		if block.Status.IsFinalized() {
+			return block
+		}
+	}
+	return nil
+}
+
+// appendToFreezer appends the block, its votes and the receipts of its transactions to the
+// freezer, and returns the hashes of the transactions with a receipt
+func (ch *Chain) appendToFreezer(block *core.ExtendedBlock) ([]common.Hash, error) {
+	rawBlock, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+
+	voteSet := core.NewVoteSet()
+	ch.store.Get(voteIndexKey(block.Hash()), voteSet)
+	rawVotes, err := rlp.EncodeToBytes(voteSet)
+	if err != nil {
+		return nil, err
+	}
+
+	txHashes := []common.Hash{}
+	entries := []*TxReceiptEntry{}
+	for _, tx := range block.Txs {
+		txHash := crypto.Keccak256Hash(tx)
+		entry := &TxReceiptEntry{}
+		if err := ch.store.Get(txReceiptKey(txHash), entry); err != nil {
+			continue
+		}
+		txHashes = append(txHashes, txHash)
+		entries = append(entries, entry)
+	}
+	rawReceipts, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ch.freezer.Append(block.Height, map[string][]byte{
+		freezerBlockTable:   rawBlock,
+		freezerVoteTable:    rawVotes,
+		freezerReceiptTable: rawReceipts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txHashes, nil
+}
+
+// findFrozenHeight returns the height of the frozen block
+func (ch *Chain) findFrozenHeight(hash common.Hash) (uint64, error) {
+	if ch.freezer == nil {
+		return 0, store.ErrKeyNotFound
+	}
+	var height uint64
+	if err := ch.store.Get(frozenBlockKey(hash), &height); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// findFrozenBlock retrieves a block by hash from the freezer
+func (ch *Chain) findFrozenBlock(hash common.Hash) (*core.ExtendedBlock, error) {
+	height, err := ch.findFrozenHeight(hash)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ch.freezer.Retrieve(freezerBlockTable, height)
+	if err != nil {
+		return nil, err
+	}
+	block := &core.ExtendedBlock{}
+	if err := rlp.DecodeBytes(raw, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// findFrozenVotes retrieves the votes of a block from the freezer
+func (ch *Chain) findFrozenVotes(hash common.Hash, voteSet *core.VoteSet) error {
+	height, err := ch.findFrozenHeight(hash)
+	if err != nil {
+		return err
+	}
+	raw, err := ch.freezer.Retrieve(freezerVoteTable, height)
+	if err != nil {
+		return err
+	}
+	return rlp.DecodeBytes(raw, voteSet)
+}
+
+// findFrozenTxReceipt retrieves the receipt of a transaction of a frozen block from the freezer
+func (ch *Chain) findFrozenTxReceipt(txHash common.Hash) (*TxReceiptEntry, error) {
+	if ch.freezer == nil {
+		return nil, store.ErrKeyNotFound
+	}
+	txIndexEntry := &TxIndexEntry{}
+	if err := ch.store.Get(txIndexKey(txHash), txIndexEntry); err != nil {
+		return nil, err
+	}
+	if _, err := ch.findFrozenHeight(txIndexEntry.BlockHash); err != nil {
+		return nil, err
+	}
+	raw, err := ch.freezer.Retrieve(freezerReceiptTable, txIndexEntry.BlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*TxReceiptEntry{}
+	if err := rlp.DecodeBytes(raw, &entries); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.TxHash == txHash {
+			return entry, nil
+		}
+	}
+	return nil, store.ErrKeyNotFound
+}
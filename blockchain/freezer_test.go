@@ -0,0 +1,106 @@
+package blockchain
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store/freezer"
+)
+
+func TestFreezeBlocks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "freezer")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	chain := CreateTestChain()
+	_, err = chain.FreezeBlocks(10, 100)
+	assert.NotNil(err, "The freezer is not enabled")
+
+	f, err := freezer.Open(dir, FreezerTables)
+	require.Nil(err)
+	defer f.Close()
+	chain.SetFreezer(f)
+
+	sctx := &types.SmartContractTx{
+		From:     types.NewTxInput(common.HexToAddress("0x1"), types.NewCoins(0, 0), 1),
+		To:       types.TxOutput{Address: common.HexToAddress("0x2")},
+		GasLimit: 100000,
+		GasPrice: big.NewInt(4),
+	}
+	raw, err := types.TxToBytes(sctx)
+	require.Nil(err)
+	txHash := crypto.Keccak256Hash(raw)
+
+	f1 := core.CreateTestBlock("f1", "a0")
+	f2 := core.CreateTestBlock("f2", "f1")
+	f2.Txs = []common.Bytes{raw}
+	f2.UpdateHash()
+	f3 := core.CreateTestBlock("f3", "f2")
+	for _, block := range []*core.Block{f1, f2, f3} {
+		_, err := chain.AddBlock(block)
+		require.Nil(err)
+	}
+	chain.AddVoteToIndex(core.Vote{Block: f2.Hash(), Epoch: f2.Epoch, ID: common.HexToAddress("a1")})
+	chain.AddTxReceipt(sctx, nil, nil, common.Address{}, 30000, nil)
+
+	// Only the finalized blocks are frozen
+	n, err := chain.FreezeBlocks(f3.Height, 100)
+	require.Nil(err)
+	assert.Equal(0, n)
+
+	require.Nil(chain.FinalizePreviousBlocks(f3.Hash()))
+	n, err = chain.FreezeBlocks(f2.Height, 100)
+	require.Nil(err)
+	assert.Equal(2, n)
+	head, _ := f.Head()
+	assert.Equal(f3.Height, head)
+
+	// The frozen data is removed from the key-value store, but remains readable
+	for _, block := range []*core.Block{f1, f2} {
+		hash := block.Hash()
+		assert.NotNil(chain.store.Get(hash[:], &core.ExtendedBlock{}))
+		frozen, err := chain.FindBlock(hash)
+		require.Nil(err)
+		assert.Equal(hash, frozen.Hash())
+		assert.True(frozen.Status.IsFinalized())
+	}
+	assert.NotNil(chain.store.Get(voteIndexKey(f2.Hash()), core.NewVoteSet()))
+	voteSet := chain.FindVotesByHash(f2.Hash())
+	assert.Equal(1, voteSet.Size())
+
+	assert.NotNil(chain.store.Get(txReceiptKey(txHash), &TxReceiptEntry{}))
+	receipt, found := chain.FindTxReceiptByHash(txHash)
+	require.True(found)
+	assert.Equal(uint64(30000), receipt.GasUsed)
+	tx, block, found := chain.FindTxByHash(txHash)
+	require.True(found)
+	assert.Equal(raw, []byte(tx))
+	assert.Equal(f2.Hash(), block.Hash())
+
+	// The root block stays in the key-value store, and the frozen blocks are not added again
+	root := chain.Root()
+	require.NotNil(root)
+	assert.Nil(chain.store.Get(root.Hash().Bytes(), &core.ExtendedBlock{}))
+	_, err = chain.AddBlock(f1)
+	assert.NotNil(err)
+	assert.Equal(2, len(chain.FindBlocksByHeight(f1.Height))+len(chain.FindBlocksByHeight(f2.Height)))
+
+	// Freezing continues from the last frozen block
+	n, err = chain.FreezeBlocks(f3.Height, 100)
+	require.Nil(err)
+	assert.Equal(1, n)
+	n, err = chain.FreezeBlocks(f3.Height, 100)
+	require.Nil(err)
+	assert.Equal(0, n)
+}
@@ -0,0 +1,430 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// ---------------- NFT Index ---------------
+
+// The standards of the non-fungible tokens
+const (
+	NFTStandardERC721  = "ERC-721"
+	NFTStandardERC1155 = "ERC-1155"
+)
+
+var (
+	// NFTTransferSingleEventTopic is the topic of the TransferSingle event of the ERC-1155 contracts. The
+	// ERC-721 contracts emit the Transfer event of the ERC-20 tokens (TokenTransferEventTopic), with the
+	// token ID indexed.
+	NFTTransferSingleEventTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+
+	// NFTTransferBatchEventTopic is the topic of the TransferBatch event of the ERC-1155 contracts
+	NFTTransferBatchEventTopic = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+
+	// NFTURIEventTopic is the topic of the URI event of the ERC-1155 contracts
+	NFTURIEventTopic = crypto.Keccak256Hash([]byte("URI(string,uint256)"))
+)
+
+// nftIndexHeightKey constructs the DB key for the height of the last block added to the NFT index.
+func nftIndexHeightKey() common.Bytes {
+	return common.Bytes("nf/h")
+}
+
+// nftID returns the identifier of the token in the DB keys, i.e. the contract address followed by the
+// token ID as a 32 bytes big endian integer.
+func nftID(contract common.Address, tokenID *big.Int) common.Bytes {
+	return append(common.Bytes(contract[:]), common.LeftPadBytes(tokenID.Bytes(), 32)...)
+}
+
+// nftOwnedListKey constructs the DB key for the tokens currently owned by the address.
+func nftOwnedListKey(owner common.Address) common.Bytes {
+	return append(common.Bytes("nf/l/"), owner[:]...)
+}
+
+// nftHoldingKey constructs the DB key for the amount of the token owned by the address.
+func nftHoldingKey(owner common.Address, contract common.Address, tokenID *big.Int) common.Bytes {
+	key := append(common.Bytes("nf/o/"), owner[:]...)
+	return append(key, nftID(contract, tokenID)...)
+}
+
+// nftURIKey constructs the DB key for the URI of the token set by a URI event.
+func nftURIKey(contract common.Address, tokenID *big.Int) common.Bytes {
+	return append(common.Bytes("nf/u/"), nftID(contract, tokenID)...)
+}
+
+// nftTransferCountKey constructs the DB key for the number of transfers of the token.
+func nftTransferCountKey(contract common.Address, tokenID *big.Int) common.Bytes {
+	return append(common.Bytes("nf/n/"), nftID(contract, tokenID)...)
+}
+
+// nftTransferKey constructs the DB key for the transfer at the given position in the history of the
+// token. The positions follow the (block height, tx index, log index) order of the transfers.
+func nftTransferKey(contract common.Address, tokenID *big.Int, position uint64) common.Bytes {
+	key := append(common.Bytes("nf/t/"), nftID(contract, tokenID)...)
+	pos := make([]byte, 8)
+	binary.BigEndian.PutUint64(pos, position)
+	return append(key, pos...)
+}
+
+// NFTRef references a token of a contract.
+type NFTRef struct {
+	Contract common.Address
+	TokenID  *big.Int
+}
+
+// NFTTransfer is a transfer of a non-fungible token decoded from a Transfer, TransferSingle or
+// TransferBatch event.
+type NFTTransfer struct {
+	Contract    common.Address
+	TokenID     *big.Int
+	Standard    string
+	Operator    common.Address // the sender of the transfer, for the ERC-1155 tokens
+	From        common.Address // the zero address for the minted tokens
+	To          common.Address // the zero address for the burned tokens
+	Amount      *big.Int       // always 1 for the ERC-721 tokens
+	TxHash      common.Hash
+	BlockHeight uint64
+	Index       uint64 // index of the transaction in its block
+	LogIndex    uint64 // index of the event in the logs of the transaction
+}
+
+// NFTHolding is the amount of a token owned by an address.
+type NFTHolding struct {
+	Contract   common.Address
+	TokenID    *big.Int
+	Standard   string
+	Amount     *big.Int
+	LastHeight uint64 // height of the block of the last transfer
+}
+
+// decodeNFTTransfers decodes the transfers of the ERC-721 Transfer event, or of the ERC-1155
+// TransferSingle and TransferBatch events. The ERC-20 Transfer events, which do not index the value,
+// are skipped.
+func decodeNFTTransfers(log *types.Log) []*NFTTransfer {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	switch log.Topics[0] {
+	case TokenTransferEventTopic:
+		if len(log.Topics) != 4 || len(log.Data) != 0 {
+			return nil
+		}
+		return []*NFTTransfer{{
+			Contract: log.Address,
+			TokenID:  new(big.Int).SetBytes(log.Topics[3][:]),
+			Standard: NFTStandardERC721,
+			From:     common.BytesToAddress(log.Topics[1][12:]),
+			To:       common.BytesToAddress(log.Topics[2][12:]),
+			Amount:   big.NewInt(1),
+		}}
+	case NFTTransferSingleEventTopic:
+		if len(log.Topics) != 4 || len(log.Data) != 64 {
+			return nil
+		}
+		return []*NFTTransfer{{
+			Contract: log.Address,
+			TokenID:  new(big.Int).SetBytes(log.Data[:32]),
+			Standard: NFTStandardERC1155,
+			Operator: common.BytesToAddress(log.Topics[1][12:]),
+			From:     common.BytesToAddress(log.Topics[2][12:]),
+			To:       common.BytesToAddress(log.Topics[3][12:]),
+			Amount:   new(big.Int).SetBytes(log.Data[32:]),
+		}}
+	case NFTTransferBatchEventTopic:
+		if len(log.Topics) != 4 || len(log.Data) < 64 {
+			return nil
+		}
+		ids, ok := decodeABIUintArray(log.Data, 0)
+		if !ok {
+			return nil
+		}
+		amounts, ok := decodeABIUintArray(log.Data, 1)
+		if !ok || len(amounts) != len(ids) {
+			return nil
+		}
+		transfers := make([]*NFTTransfer, len(ids))
+		for i := range ids {
+			transfers[i] = &NFTTransfer{
+				Contract: log.Address,
+				TokenID:  ids[i],
+				Standard: NFTStandardERC1155,
+				Operator: common.BytesToAddress(log.Topics[1][12:]),
+				From:     common.BytesToAddress(log.Topics[2][12:]),
+				To:       common.BytesToAddress(log.Topics[3][12:]),
+				Amount:   amounts[i],
+			}
+		}
+		return transfers
+	}
+	return nil
+}
+
+// decodeABIDynamic returns the ABI encoded length and content of the dynamic argument at the given
+// position of the data.
+func decodeABIDynamic(data []byte, arg int) (uint64, []byte, bool) {
+	head := arg * 32
+	if len(data) < head+32 {
+		return 0, nil, false
+	}
+	offset := new(big.Int).SetBytes(data[head : head+32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)) || uint64(len(data))-offset.Uint64() < 32 {
+		return 0, nil, false
+	}
+	start := offset.Uint64()
+	length := new(big.Int).SetBytes(data[start : start+32])
+	if !length.IsUint64() {
+		return 0, nil, false
+	}
+	return length.Uint64(), data[start+32:], true
+}
+
+// decodeABIUintArray decodes the uint256[] argument at the given position of the ABI encoded data.
+func decodeABIUintArray(data []byte, arg int) ([]*big.Int, bool) {
+	length, content, ok := decodeABIDynamic(data, arg)
+	if !ok || length > uint64(len(content))/32 {
+		return nil, false
+	}
+	values := make([]*big.Int, length)
+	for i := range values {
+		values[i] = new(big.Int).SetBytes(content[i*32 : (i+1)*32])
+	}
+	return values, true
+}
+
+// AddNFTTransfers indexes the transfers of the non-fungible tokens emitted by the smart contract
+// transactions of the finalized block: it appends them to the history of the tokens, and updates the
+// tokens owned by the sender and the recipient. It also records the URIs set by the ERC-1155 URI
+// events. Blocks need to be added in the order of their heights, and adding a block twice is a no-op.
+func (ch *Chain) AddNFTTransfers(block *core.ExtendedBlock) {
+	if height, ok := ch.GetNFTIndexHeight(); ok && block.Height <= height {
+		return
+	}
+
+	for idx, raw := range block.Txs {
+		txHash := crypto.Keccak256Hash(raw)
+		receipt, ok := ch.FindTxReceiptByHash(txHash)
+		if !ok || receipt.EvmErr != "" {
+			continue
+		}
+		for logIdx, log := range receipt.Logs {
+			if len(log.Topics) == 2 && log.Topics[0] == NFTURIEventTopic {
+				if uri, ok := types.UnpackABIString(log.Data); ok {
+					tokenID := new(big.Int).SetBytes(log.Topics[1][:])
+					if err := ch.store.Put(nftURIKey(log.Address, tokenID), uri); err != nil {
+						logger.Panic(err)
+					}
+				}
+				continue
+			}
+
+			for _, transfer := range decodeNFTTransfers(log) {
+				transfer.TxHash = txHash
+				transfer.BlockHeight = block.Height
+				transfer.Index = uint64(idx)
+				transfer.LogIndex = uint64(logIdx)
+
+				if transfer.From != (common.Address{}) {
+					ch.updateNFTHolding(transfer.From, transfer, new(big.Int).Neg(transfer.Amount))
+				}
+				if transfer.To != (common.Address{}) {
+					ch.updateNFTHolding(transfer.To, transfer, transfer.Amount)
+				}
+				ch.addNFTTransfer(transfer)
+			}
+		}
+	}
+
+	if err := ch.store.Put(nftIndexHeightKey(), block.Height); err != nil {
+		logger.Panic(err)
+	}
+}
+
+// updateNFTHolding adds delta to the amount of the transferred token owned by the address. The token
+// is removed from the tokens owned by the address once its amount drops to zero.
+func (ch *Chain) updateNFTHolding(owner common.Address, transfer *NFTTransfer, delta *big.Int) {
+	holding, found := ch.GetNFTHolding(owner, transfer.Contract, transfer.TokenID)
+	if !found {
+		holding = &NFTHolding{
+			Contract: transfer.Contract,
+			TokenID:  transfer.TokenID,
+			Standard: transfer.Standard,
+			Amount:   new(big.Int),
+		}
+	}
+	holding.Amount.Add(holding.Amount, delta)
+	holding.LastHeight = transfer.BlockHeight
+
+	key := nftHoldingKey(owner, transfer.Contract, transfer.TokenID)
+	if holding.Amount.Sign() <= 0 { // the tokens received before the index was enabled are not tracked
+		if !found {
+			return
+		}
+		if err := ch.store.Delete(key); err != nil {
+			logger.Panic(err)
+		}
+		owned := ch.GetNFTsOwned(owner)
+		for i, ref := range owned {
+			if ref.Contract == transfer.Contract && ref.TokenID.Cmp(transfer.TokenID) == 0 {
+				owned = append(owned[:i], owned[i+1:]...)
+				break
+			}
+		}
+		if err := ch.store.Put(nftOwnedListKey(owner), owned); err != nil {
+			logger.Panic(err)
+		}
+		return
+	}
+
+	if err := ch.store.Put(key, *holding); err != nil {
+		logger.Panic(err)
+	}
+	if !found {
+		owned := append(ch.GetNFTsOwned(owner), NFTRef{Contract: transfer.Contract, TokenID: transfer.TokenID})
+		if err := ch.store.Put(nftOwnedListKey(owner), owned); err != nil {
+			logger.Panic(err)
+		}
+	}
+}
+
+func (ch *Chain) addNFTTransfer(transfer *NFTTransfer) {
+	count := ch.GetNFTTransferCount(transfer.Contract, transfer.TokenID)
+
+	// Write the transfer before the count, so that the count never points past the last transfer
+	err := ch.store.Put(nftTransferKey(transfer.Contract, transfer.TokenID, count), *transfer)
+	if err != nil {
+		logger.Panic(err)
+	}
+	err = ch.store.Put(nftTransferCountKey(transfer.Contract, transfer.TokenID), count+1)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// GetNFTIndexHeight returns the height of the last block added to the NFT index, false if no block has
+// been added yet.
+func (ch *Chain) GetNFTIndexHeight() (uint64, bool) {
+	var height uint64
+	err := ch.store.Get(nftIndexHeightKey(), &height)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return 0, false
+	}
+	return height, true
+}
+
+// GetNFTsOwned returns the tokens currently owned by the address, in the order they were received.
+func (ch *Chain) GetNFTsOwned(owner common.Address) []NFTRef {
+	owned := []NFTRef{}
+	err := ch.store.Get(nftOwnedListKey(owner), &owned)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return []NFTRef{}
+	}
+	return owned
+}
+
+// GetNFTHolding returns the amount of the token owned by the address, false if the address does not
+// own the token.
+func (ch *Chain) GetNFTHolding(owner common.Address, contract common.Address, tokenID *big.Int) (*NFTHolding, bool) {
+	holding := &NFTHolding{}
+	err := ch.store.Get(nftHoldingKey(owner, contract, tokenID), holding)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return holding, true
+}
+
+// GetNFTHoldings returns up to limit tokens currently owned by the address, skipping the first offset
+// ones. If contract is not the zero address, only the tokens of this contract are returned.
+func (ch *Chain) GetNFTHoldings(owner common.Address, contract common.Address, offset uint64, limit uint64) []*NFTHolding {
+	holdings := []*NFTHolding{}
+	skipped := uint64(0)
+	for _, ref := range ch.GetNFTsOwned(owner) {
+		if uint64(len(holdings)) >= limit {
+			break
+		}
+		if contract != (common.Address{}) && ref.Contract != contract {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		if holding, ok := ch.GetNFTHolding(owner, ref.Contract, ref.TokenID); ok {
+			holdings = append(holdings, holding)
+		}
+	}
+	return holdings
+}
+
+// GetNFTURI returns the URI of the token set by the last ERC-1155 URI event, false if the contract did
+// not emit one.
+func (ch *Chain) GetNFTURI(contract common.Address, tokenID *big.Int) (string, bool) {
+	var uri string
+	err := ch.store.Get(nftURIKey(contract, tokenID), &uri)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return "", false
+	}
+	return uri, true
+}
+
+// GetNFTTransferCount returns the number of transfers in the history of the token.
+func (ch *Chain) GetNFTTransferCount(contract common.Address, tokenID *big.Int) uint64 {
+	var count uint64
+	err := ch.store.Get(nftTransferCountKey(contract, tokenID), &count)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return 0
+	}
+	return count
+}
+
+func (ch *Chain) getNFTTransfer(contract common.Address, tokenID *big.Int, position uint64) (*NFTTransfer, bool) {
+	transfer := &NFTTransfer{}
+	err := ch.store.Get(nftTransferKey(contract, tokenID, position), transfer)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return transfer, true
+}
+
+// GetNFTTransfers returns up to limit transfers in the history of the token, skipping the first offset
+// ones. The oldest transfers come first, unless newestFirst is set.
+func (ch *Chain) GetNFTTransfers(contract common.Address, tokenID *big.Int, offset uint64, limit uint64, newestFirst bool) []*NFTTransfer {
+	transfers := []*NFTTransfer{}
+	count := ch.GetNFTTransferCount(contract, tokenID)
+	for i := offset; i < count && uint64(len(transfers)) < limit; i++ {
+		position := i
+		if newestFirst {
+			position = count - 1 - i
+		}
+		transfer, ok := ch.getNFTTransfer(contract, tokenID, position)
+		if !ok {
+			continue
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
@@ -0,0 +1,140 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNFTIndex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	collection := common.HexToAddress("0x100") // ERC-721
+	items := common.HexToAddress("0x200")      // ERC-1155
+	word := func(v int64) []byte { return common.LeftPadBytes(big.NewInt(v).Bytes(), 32) }
+	topic := func(addr common.Address) common.Hash { return common.BytesToHash(addr[:]) }
+
+	transfer721 := func(from, to common.Address, tokenID int64) *types.Log {
+		return &types.Log{
+			Address: collection,
+			Topics:  []common.Hash{TokenTransferEventTopic, topic(from), topic(to), common.BytesToHash(word(tokenID))},
+		}
+	}
+	transferSingle := func(from, to common.Address, tokenID, amount int64) *types.Log {
+		return &types.Log{
+			Address: items,
+			Topics:  []common.Hash{NFTTransferSingleEventTopic, topic(from), topic(from), topic(to)},
+			Data:    append(word(tokenID), word(amount)...),
+		}
+	}
+	var batchData []byte
+	for _, w := range []int64{64, 160, 2, 7, 8, 2, 5, 1} { // ids [7, 8], amounts [5, 1]
+		batchData = append(batchData, word(w)...)
+	}
+	transferBatch := &types.Log{
+		Address: items,
+		Topics:  []common.Hash{NFTTransferBatchEventTopic, topic(alice), topic(common.Address{}), topic(alice)},
+		Data:    batchData,
+	}
+	uriData := append(word(32), word(11)...)
+	uriData = append(uriData, common.RightPadBytes([]byte("ipfs://{id}"), 32)...)
+	uri := &types.Log{
+		Address: items,
+		Topics:  []common.Hash{NFTURIEventTopic, common.BytesToHash(word(7))},
+		Data:    uriData,
+	}
+	erc20 := &types.Log{
+		Address: collection,
+		Topics:  []common.Hash{TokenTransferEventTopic, topic(alice), topic(bob)},
+		Data:    word(100),
+	}
+
+	chain := CreateTestChain()
+	seq := 0
+	newTx := func(logs ...*types.Log) common.Bytes {
+		seq++
+		tx := &types.SmartContractTx{
+			From:     types.NewTxInput(alice, types.NewCoins(0, 0), seq),
+			GasLimit: 100000,
+			GasPrice: big.NewInt(1),
+		}
+		chain.AddTxReceipt(tx, logs, nil, common.Address{}, 50000, nil)
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		return raw
+	}
+
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{
+		newTx(transfer721(common.Address{}, alice, 1), transfer721(common.Address{}, alice, 2), erc20),
+		newTx(transferBatch, uri),
+	}
+	block1.UpdateHash()
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	block2 := core.CreateTestBlock("b2", "b1")
+	block2.Height = 11
+	block2.Txs = []common.Bytes{
+		newTx(transfer721(alice, bob, 1), transferSingle(alice, bob, 7, 2), transferSingle(alice, bob, 8, 1)),
+	}
+	block2.UpdateHash()
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+
+	chain.AddNFTTransfers(eb1)
+	chain.AddNFTTransfers(eb2)
+	// Adding a block again does not count its transfers twice
+	chain.AddNFTTransfers(eb1)
+
+	height, ok := chain.GetNFTIndexHeight()
+	require.True(ok)
+	assert.Equal(uint64(11), height)
+
+	holdings := chain.GetNFTHoldings(alice, common.Address{}, 0, 10)
+	require.Equal(2, len(holdings))
+	assert.Equal(collection, holdings[0].Contract)
+	assert.Equal(big.NewInt(2), holdings[0].TokenID)
+	assert.Equal(NFTStandardERC721, holdings[0].Standard)
+	assert.Equal(items, holdings[1].Contract)
+	assert.Equal(big.NewInt(7), holdings[1].TokenID)
+	assert.Equal(big.NewInt(3), holdings[1].Amount)
+	assert.Equal(1, len(chain.GetNFTHoldings(alice, items, 0, 10)))
+	assert.Equal(0, len(chain.GetNFTHoldings(alice, common.Address{}, 2, 10)))
+
+	holdings = chain.GetNFTHoldings(bob, common.Address{}, 1, 10)
+	require.Equal(2, len(holdings))
+	assert.Equal(big.NewInt(7), holdings[0].TokenID)
+	assert.Equal(big.NewInt(2), holdings[0].Amount)
+	assert.Equal(big.NewInt(8), holdings[1].TokenID)
+	assert.Equal(NFTStandardERC1155, holdings[1].Standard)
+
+	assert.Equal(uint64(2), chain.GetNFTTransferCount(collection, big.NewInt(1)))
+	transfers := chain.GetNFTTransfers(collection, big.NewInt(1), 0, 10, true)
+	require.Equal(2, len(transfers))
+	assert.Equal(alice, transfers[0].From)
+	assert.Equal(bob, transfers[0].To)
+	assert.Equal(uint64(11), transfers[0].BlockHeight)
+	assert.Equal(common.Address{}, transfers[1].From)
+	assert.Equal(uint64(0), transfers[1].LogIndex)
+
+	transfers = chain.GetNFTTransfers(items, big.NewInt(7), 0, 10, false)
+	require.Equal(2, len(transfers))
+	assert.Equal(alice, transfers[0].Operator)
+	assert.Equal(big.NewInt(5), transfers[0].Amount)
+
+	uriValue, ok := chain.GetNFTURI(items, big.NewInt(7))
+	require.True(ok)
+	assert.Equal("ipfs://{id}", uriValue)
+	_, ok = chain.GetNFTURI(items, big.NewInt(8))
+	assert.False(ok)
+}
@@ -0,0 +1,90 @@
+package blockchain
+
+import (
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// ---------------- Param Change Announcements ---------------
+
+// paramChangeAnnouncementsKey constructs the DB key for the parameter changes announced by the block.
+func paramChangeAnnouncementsKey(hash common.Hash) common.Bytes {
+	return append(common.Bytes("pca/"), hash[:]...)
+}
+
+// paramChangeHistoryKey constructs the DB key for the parameter changes announced by the finalized blocks.
+func paramChangeHistoryKey() common.Bytes {
+	return common.Bytes("pch")
+}
+
+// ParamChangeAnnouncements records the governance parameter changes announced by a block.
+type ParamChangeAnnouncements struct {
+	Changes []*types.ParamChange
+}
+
+// AddParamChangeAnnouncements records the parameter changes announced by the block with the given hash.
+// A block executed again overwrites the previous record.
+func (ch *Chain) AddParamChangeAnnouncements(blockHash common.Hash, changes []*types.ParamChange) {
+	if len(changes) == 0 {
+		return
+	}
+	err := ch.store.Put(paramChangeAnnouncementsKey(blockHash), ParamChangeAnnouncements{Changes: changes})
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// FindParamChangeAnnouncements looks up the parameter changes announced by the block with the given hash.
+func (ch *Chain) FindParamChangeAnnouncements(blockHash common.Hash) []*types.ParamChange {
+	announcements := &ParamChangeAnnouncements{}
+	err := ch.store.Get(paramChangeAnnouncementsKey(blockHash), announcements)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil
+	}
+	return announcements.Changes
+}
+
+// ParamChangeHistory keeps the parameter changes announced by the finalized blocks, in the order they
+// were announced. It outlives the schedule in the state, which drops the changes once activated.
+type ParamChangeHistory struct {
+	Changes    []*types.ParamChange
+	LastHeight uint64 // height of the last finalized block added
+}
+
+// AddParamChangeHistory adds the parameter changes announced by the finalized block to the history.
+// Blocks need to be added in the order of their heights, and adding a block twice is a no-op.
+func (ch *Chain) AddParamChangeHistory(block *core.ExtendedBlock) {
+	changes := ch.FindParamChangeAnnouncements(block.Hash())
+	if len(changes) == 0 {
+		return
+	}
+	history := ch.GetParamChangeHistory()
+	if history.LastHeight >= block.Height {
+		return
+	}
+	history.Changes = append(history.Changes, changes...)
+	history.LastHeight = block.Height
+
+	err := ch.store.Put(paramChangeHistoryKey(), *history)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// GetParamChangeHistory returns the parameter changes announced by the finalized blocks.
+func (ch *Chain) GetParamChangeHistory() *ParamChangeHistory {
+	history := &ParamChangeHistory{}
+	err := ch.store.Get(paramChangeHistoryKey(), history)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return &ParamChangeHistory{}
+	}
+	return history
+}
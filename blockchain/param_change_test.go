@@ -0,0 +1,60 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamChangeHistory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chain := CreateTestChain()
+
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 100
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	block2 := core.CreateTestBlock("b2", "b1")
+	block2.Height = 200
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+
+	// A block without announcements is not recorded
+	assert.Nil(chain.FindParamChangeAnnouncements(eb1.Hash()))
+	chain.AddParamChangeAnnouncements(eb1.Hash(), nil)
+	assert.Nil(chain.FindParamChangeAnnouncements(eb1.Hash()))
+
+	change1 := &types.ParamChange{Name: "min_fee", Value: big.NewInt(2000), AnnouncedHeight: 99, ActivationHeight: 1099}
+	change2 := &types.ParamChange{Name: "max_gas", Value: big.NewInt(5000), AnnouncedHeight: 199, ActivationHeight: 1199}
+	change3 := &types.ParamChange{Name: "min_fee", Value: big.NewInt(3000), AnnouncedHeight: 199, ActivationHeight: 1199}
+	chain.AddParamChangeAnnouncements(eb1.Hash(), []*types.ParamChange{change1})
+	chain.AddParamChangeAnnouncements(eb2.Hash(), []*types.ParamChange{change2, change3})
+
+	announced := chain.FindParamChangeAnnouncements(eb2.Hash())
+	require.Equal(2, len(announced))
+	assert.Equal("max_gas", announced[0].Name)
+	assert.Equal(int64(3000), announced[1].Value.Int64())
+
+	assert.Equal(0, len(chain.GetParamChangeHistory().Changes))
+
+	chain.AddParamChangeHistory(eb1)
+	chain.AddParamChangeHistory(eb2)
+	// Adding a block again does not record its announcements twice
+	chain.AddParamChangeHistory(eb1)
+	chain.AddParamChangeHistory(eb2)
+
+	history := chain.GetParamChangeHistory()
+	require.Equal(3, len(history.Changes))
+	assert.Equal(uint64(200), history.LastHeight)
+	assert.Equal("min_fee", history.Changes[0].Name)
+	assert.Equal(uint64(1099), history.Changes[0].ActivationHeight)
+	assert.Equal("max_gas", history.Changes[1].Name)
+	assert.Equal(int64(3000), history.Changes[2].Value.Int64())
+}
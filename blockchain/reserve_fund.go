@@ -0,0 +1,140 @@
+package blockchain
+
+import (
+	"encoding/binary"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// ---------------- Reserve Fund Payments ---------------
+
+// reserveFundPaymentKey constructs the DB key for the settlement of the service payment transaction.
+func reserveFundPaymentKey(hash common.Hash) common.Bytes {
+	return append(common.Bytes("rfp/"), hash[:]...)
+}
+
+// reserveFundUsageKey constructs the DB key for the cumulative usage of the reserve fund.
+func reserveFundUsageKey(addr common.Address, reserveSequence uint64) common.Bytes {
+	key := append(common.Bytes("rfu/"), addr[:]...)
+	seq := make([]byte, 8)
+	binary.BigEndian.PutUint64(seq, reserveSequence)
+	return append(key, seq...)
+}
+
+// ReserveFundPayment records how a service payment transaction was settled against the reserve
+// fund of the payer.
+type ReserveFundPayment struct {
+	TxHash          common.Hash
+	Source          common.Address // the payer owning the reserve fund
+	Target          common.Address
+	ReserveSequence uint64
+	PaymentSequence uint64
+	ResourceID      string
+	Amount          types.Coins
+	Settled         bool        // false if the fund could not cover the payment, or did not match it
+	UsedFund        types.Coins // cumulative spend of the fund after the payment
+	RemainingFund   types.Coins // remaining balance of the fund after the payment
+	Height          uint64      // height of the block which executed the payment
+}
+
+// AddReserveFundPayment records the settlement of a service payment or a metered settlement
+// transaction. A payment executed again, e.g. on another fork, overwrites the previous record.
+func (ch *Chain) AddReserveFundPayment(tx types.Tx, payment *ReserveFundPayment) {
+	raw, err := types.TxToBytes(tx)
+	if err != nil {
+		// Should never happen
+		logger.Panic(err)
+	}
+	payment.TxHash = crypto.Keccak256Hash(raw)
+
+	err = ch.store.Put(reserveFundPaymentKey(payment.TxHash), *payment)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// FindReserveFundPaymentByHash looks up the settlement of a service payment transaction by hash.
+func (ch *Chain) FindReserveFundPaymentByHash(hash common.Hash) (*ReserveFundPayment, bool) {
+	payment := &ReserveFundPayment{}
+	err := ch.store.Get(reserveFundPaymentKey(hash), payment)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return payment, true
+}
+
+// ReserveFundUsage aggregates the settled payments of the finalized blocks against a reserve fund.
+// It outlives the fund, which is removed from the account when released.
+type ReserveFundUsage struct {
+	Address         common.Address
+	ReserveSequence uint64
+	NumPayments     uint64
+	TotalPaid       types.Coins
+	LastTxHash      common.Hash
+	LastHeight      uint64 // height of the block of the last payment
+	LastIndex       uint64 // index of the last payment in its block
+}
+
+// AddReserveFundUsage adds the settled service payments of the finalized block to the usage of the
+// reserve funds they were paid from. Blocks need to be added in the order of their heights, and
+// adding a block twice is a no-op.
+func (ch *Chain) AddReserveFundUsage(block *core.ExtendedBlock) {
+	for idx, raw := range block.Txs {
+		tx, err := types.TxFromBytes(raw)
+		if err != nil {
+			logger.Warnf("Failed to parse transaction %v of block %v: %v", idx, block.Hash().Hex(), err)
+			continue
+		}
+		switch tx.(type) {
+		case *types.ServicePaymentTx, *types.MeteredSettlementTx:
+		default:
+			continue
+		}
+		txHash := crypto.Keccak256Hash(raw)
+		payment, ok := ch.FindReserveFundPaymentByHash(txHash)
+		if !ok || !payment.Settled {
+			continue
+		}
+
+		usage, ok := ch.GetReserveFundUsage(payment.Source, payment.ReserveSequence)
+		if !ok {
+			usage = &ReserveFundUsage{
+				Address:         payment.Source,
+				ReserveSequence: payment.ReserveSequence,
+				TotalPaid:       types.NewCoins(0, 0),
+			}
+		} else if usage.LastHeight > block.Height || (usage.LastHeight == block.Height && usage.LastIndex >= uint64(idx)) {
+			continue
+		}
+		usage.NumPayments++
+		usage.TotalPaid = usage.TotalPaid.Plus(payment.Amount)
+		usage.LastTxHash = txHash
+		usage.LastHeight = block.Height
+		usage.LastIndex = uint64(idx)
+
+		err = ch.store.Put(reserveFundUsageKey(usage.Address, usage.ReserveSequence), *usage)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+}
+
+// GetReserveFundUsage returns the usage of the reserve fund of the address with the given sequence.
+func (ch *Chain) GetReserveFundUsage(addr common.Address, reserveSequence uint64) (*ReserveFundUsage, bool) {
+	usage := &ReserveFundUsage{}
+	err := ch.store.Get(reserveFundUsageKey(addr, reserveSequence), usage)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return usage, true
+}
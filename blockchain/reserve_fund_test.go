@@ -0,0 +1,87 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveFundUsage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	chain := CreateTestChain()
+
+	// Records the payment as the service payment executor does, and returns the raw transaction
+	pay := func(paymentSeq uint64, amount int64, settled bool) common.Bytes {
+		tx := &types.ServicePaymentTx{
+			Fee:             types.NewCoins(0, 1),
+			Source:          types.NewTxInput(alice, types.NewCoins(0, amount), 0),
+			Target:          types.NewTxInput(bob, types.NewCoins(0, 0), int(paymentSeq)),
+			PaymentSequence: paymentSeq,
+			ReserveSequence: 7,
+			ResourceID:      "rid",
+		}
+		chain.AddReserveFundPayment(tx, &ReserveFundPayment{
+			Source:          alice,
+			Target:          bob,
+			ReserveSequence: 7,
+			PaymentSequence: paymentSeq,
+			ResourceID:      "rid",
+			Amount:          types.NewCoins(0, amount),
+			Settled:         settled,
+		})
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		return raw
+	}
+
+	tx1 := pay(1, 10, true)
+	tx2 := pay(2, 1000, false) // overspending
+	tx3 := pay(3, 20, true)
+
+	payment, ok := chain.FindReserveFundPaymentByHash(crypto.Keccak256Hash(tx2))
+	require.True(ok)
+	assert.False(payment.Settled)
+	assert.Equal(uint64(2), payment.PaymentSequence)
+
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{tx1, tx2}
+	block1.UpdateHash()
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	block2 := core.CreateTestBlock("b2", "b1")
+	block2.Height = 11
+	block2.Txs = []common.Bytes{tx3}
+	block2.UpdateHash()
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+
+	_, ok = chain.GetReserveFundUsage(alice, 7)
+	assert.False(ok)
+
+	chain.AddReserveFundUsage(eb1)
+	chain.AddReserveFundUsage(eb2)
+	// Adding a block again does not count its payments twice
+	chain.AddReserveFundUsage(eb1)
+	chain.AddReserveFundUsage(eb2)
+
+	usage, ok := chain.GetReserveFundUsage(alice, 7)
+	require.True(ok)
+	assert.Equal(uint64(2), usage.NumPayments)
+	assert.Equal(types.NewCoins(0, 30), usage.TotalPaid)
+	assert.Equal(crypto.Keccak256Hash(tx3), usage.LastTxHash)
+	assert.Equal(uint64(11), usage.LastHeight)
+
+	_, ok = chain.GetReserveFundUsage(alice, 8)
+	assert.False(ok)
+}
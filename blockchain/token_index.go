@@ -0,0 +1,279 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+// ---------------- Token Index ---------------
+
+// TokenTransferEventTopic is the topic of the Transfer(address,address,uint256) event emitted by the
+// ERC-20 compatible (PNC-20) token contracts
+var TokenTransferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// tokenIndexHeightKey constructs the DB key for the height of the last block added to the token index.
+func tokenIndexHeightKey() common.Bytes {
+	return common.Bytes("tk/h")
+}
+
+// tokenListKey constructs the DB key for the tokens held or transferred by the address.
+func tokenListKey(addr common.Address) common.Bytes {
+	return append(common.Bytes("tk/l/"), addr[:]...)
+}
+
+// tokenBalanceKey constructs the DB key for the balance of the token held by the address.
+func tokenBalanceKey(addr common.Address, token common.Address) common.Bytes {
+	key := append(common.Bytes("tk/b/"), addr[:]...)
+	return append(key, token[:]...)
+}
+
+// tokenTransferCountKey constructs the DB key for the number of token transfers of the address.
+func tokenTransferCountKey(addr common.Address) common.Bytes {
+	return append(common.Bytes("tk/n/"), addr[:]...)
+}
+
+// tokenTransferKey constructs the DB key for the token transfer at the given position in the history
+// of the address. The positions follow the (block height, tx index, log index) order of the transfers.
+func tokenTransferKey(addr common.Address, position uint64) common.Bytes {
+	key := append(common.Bytes("tk/t/"), addr[:]...)
+	pos := make([]byte, 8)
+	binary.BigEndian.PutUint64(pos, position)
+	return append(key, pos...)
+}
+
+// TokenTransfer is a transfer of tokens decoded from a Transfer event.
+type TokenTransfer struct {
+	Token       common.Address // the token contract
+	From        common.Address // the zero address for the minted tokens
+	To          common.Address // the zero address for the burned tokens
+	Value       *big.Int
+	TxHash      common.Hash
+	BlockHeight uint64
+	Index       uint64 // index of the transaction in its block
+	LogIndex    uint64 // index of the event in the logs of the transaction
+}
+
+// TokenBalance is the balance of a token held by an address. It is computed from the transfers indexed
+// since the index was enabled, hence it is exact only if the token was deployed afterwards.
+type TokenBalance struct {
+	Token      common.Address
+	Balance    *big.Int
+	LastHeight uint64 // height of the block of the last transfer
+}
+
+// decodeTokenTransfer decodes the log of an ERC-20 Transfer event. The ERC-721 Transfer events, which
+// share the topic but index the token ID, are skipped.
+func decodeTokenTransfer(log *types.Log) (*TokenTransfer, bool) {
+	if len(log.Topics) != 3 || log.Topics[0] != TokenTransferEventTopic || len(log.Data) != 32 {
+		return nil, false
+	}
+	return &TokenTransfer{
+		Token: log.Address,
+		From:  common.BytesToAddress(log.Topics[1][12:]),
+		To:    common.BytesToAddress(log.Topics[2][12:]),
+		Value: new(big.Int).SetBytes(log.Data),
+	}, true
+}
+
+// AddTokenTransfers indexes the token transfers emitted by the smart contract transactions of the
+// finalized block: it appends them to the transfer history of the sender and the recipient, and
+// updates their balances. Blocks need to be added in the order of their heights, and adding a block
+// twice is a no-op.
+func (ch *Chain) AddTokenTransfers(block *core.ExtendedBlock) {
+	if height, ok := ch.GetTokenIndexHeight(); ok && block.Height <= height {
+		return
+	}
+
+	balances := make(map[common.Address]map[common.Address]*TokenBalance)
+	tokens := make(map[common.Address][]common.Address) // in the order the tokens are first seen
+	getBalance := func(addr common.Address, token common.Address) *TokenBalance {
+		if balances[addr] == nil {
+			balances[addr] = make(map[common.Address]*TokenBalance)
+		}
+		balance, ok := balances[addr][token]
+		if !ok {
+			balance, ok = ch.GetTokenBalance(addr, token)
+			if !ok {
+				balance = &TokenBalance{Token: token, Balance: new(big.Int)}
+			}
+			balances[addr][token] = balance
+			tokens[addr] = append(tokens[addr], token)
+		}
+		return balance
+	}
+
+	for idx, raw := range block.Txs {
+		txHash := crypto.Keccak256Hash(raw)
+		receipt, ok := ch.FindTxReceiptByHash(txHash)
+		if !ok || receipt.EvmErr != "" {
+			continue
+		}
+		for logIdx, log := range receipt.Logs {
+			transfer, ok := decodeTokenTransfer(log)
+			if !ok {
+				continue
+			}
+			transfer.TxHash = txHash
+			transfer.BlockHeight = block.Height
+			transfer.Index = uint64(idx)
+			transfer.LogIndex = uint64(logIdx)
+
+			if transfer.From != (common.Address{}) {
+				balance := getBalance(transfer.From, transfer.Token)
+				balance.Balance.Sub(balance.Balance, transfer.Value)
+				if balance.Balance.Sign() < 0 {
+					balance.Balance.SetInt64(0) // received before the index was enabled
+				}
+				balance.LastHeight = block.Height
+				ch.addTokenTransfer(transfer.From, transfer)
+			}
+			if transfer.To != (common.Address{}) {
+				balance := getBalance(transfer.To, transfer.Token)
+				balance.Balance.Add(balance.Balance, transfer.Value)
+				balance.LastHeight = block.Height
+				if transfer.To != transfer.From {
+					ch.addTokenTransfer(transfer.To, transfer)
+				}
+			}
+		}
+	}
+
+	for addr, addrTokens := range tokens {
+		list := ch.GetTokens(addr)
+		known := make(map[common.Address]bool, len(list))
+		for _, token := range list {
+			known[token] = true
+		}
+		for _, token := range addrTokens {
+			if err := ch.store.Put(tokenBalanceKey(addr, token), *balances[addr][token]); err != nil {
+				logger.Panic(err)
+			}
+			if !known[token] {
+				list = append(list, token)
+			}
+		}
+		if err := ch.store.Put(tokenListKey(addr), list); err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	if err := ch.store.Put(tokenIndexHeightKey(), block.Height); err != nil {
+		logger.Panic(err)
+	}
+}
+
+func (ch *Chain) addTokenTransfer(addr common.Address, transfer *TokenTransfer) {
+	count := ch.GetTokenTransferCount(addr)
+
+	// Write the transfer before the count, so that the count never points past the last transfer
+	err := ch.store.Put(tokenTransferKey(addr, count), *transfer)
+	if err != nil {
+		logger.Panic(err)
+	}
+	err = ch.store.Put(tokenTransferCountKey(addr), count+1)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// GetTokenIndexHeight returns the height of the last block added to the token index, false if no
+// block has been added yet.
+func (ch *Chain) GetTokenIndexHeight() (uint64, bool) {
+	var height uint64
+	err := ch.store.Get(tokenIndexHeightKey(), &height)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return 0, false
+	}
+	return height, true
+}
+
+// GetTokens returns the tokens held or transferred by the address, in the order they were first seen.
+func (ch *Chain) GetTokens(addr common.Address) []common.Address {
+	tokens := []common.Address{}
+	err := ch.store.Get(tokenListKey(addr), &tokens)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return []common.Address{}
+	}
+	return tokens
+}
+
+// GetTokenBalance returns the balance of the token held by the address, false if the address has no
+// indexed transfer of the token.
+func (ch *Chain) GetTokenBalance(addr common.Address, token common.Address) (*TokenBalance, bool) {
+	balance := &TokenBalance{}
+	err := ch.store.Get(tokenBalanceKey(addr, token), balance)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return balance, true
+}
+
+// GetTokenBalances returns the balances of the tokens held or transferred by the address.
+func (ch *Chain) GetTokenBalances(addr common.Address) []*TokenBalance {
+	balances := []*TokenBalance{}
+	for _, token := range ch.GetTokens(addr) {
+		if balance, ok := ch.GetTokenBalance(addr, token); ok {
+			balances = append(balances, balance)
+		}
+	}
+	return balances
+}
+
+// GetTokenTransferCount returns the number of token transfers in the history of the address.
+func (ch *Chain) GetTokenTransferCount(addr common.Address) uint64 {
+	var count uint64
+	err := ch.store.Get(tokenTransferCountKey(addr), &count)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return 0
+	}
+	return count
+}
+
+func (ch *Chain) getTokenTransfer(addr common.Address, position uint64) (*TokenTransfer, bool) {
+	transfer := &TokenTransfer{}
+	err := ch.store.Get(tokenTransferKey(addr, position), transfer)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return transfer, true
+}
+
+// GetTokenTransfers returns up to limit token transfers in the history of the address, skipping the
+// first offset ones. The oldest transfers come first, unless newestFirst is set.
+func (ch *Chain) GetTokenTransfers(addr common.Address, offset uint64, limit uint64, newestFirst bool) []*TokenTransfer {
+	transfers := []*TokenTransfer{}
+	count := ch.GetTokenTransferCount(addr)
+	for i := offset; i < count && uint64(len(transfers)) < limit; i++ {
+		position := i
+		if newestFirst {
+			position = count - 1 - i
+		}
+		transfer, ok := ch.getTokenTransfer(addr, position)
+		if !ok {
+			continue
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
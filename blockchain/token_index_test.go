@@ -0,0 +1,106 @@
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenIndex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	token := common.HexToAddress("0x100")
+	nft := common.HexToAddress("0x200")
+
+	transferLog := func(token, from, to common.Address, value int64) *types.Log {
+		return &types.Log{
+			Address: token,
+			Topics:  []common.Hash{TokenTransferEventTopic, common.BytesToHash(from[:]), common.BytesToHash(to[:])},
+			Data:    common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+		}
+	}
+
+	chain := CreateTestChain()
+	seq := 0
+	newTx := func(logs []*types.Log, evmErr error) common.Bytes {
+		seq++
+		tx := &types.SmartContractTx{
+			From:     types.NewTxInput(alice, types.NewCoins(0, 0), seq),
+			To:       types.TxOutput{Address: token},
+			GasLimit: 100000,
+			GasPrice: big.NewInt(1),
+		}
+		chain.AddTxReceipt(tx, logs, nil, common.Address{}, 50000, evmErr)
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		return raw
+	}
+
+	mint := transferLog(token, common.Address{}, alice, 1000)
+	nftTransfer := transferLog(nft, alice, bob, 1)
+	nftTransfer.Topics = append(nftTransfer.Topics, common.BytesToHash([]byte{7})) // ERC-721, the token ID is indexed
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{
+		newTx([]*types.Log{mint}, nil),
+		newTx([]*types.Log{transferLog(token, alice, bob, 500)}, errors.New("reverted")),
+		newTx([]*types.Log{nftTransfer}, nil),
+	}
+	block1.UpdateHash()
+	eb1, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	block2 := core.CreateTestBlock("b2", "b1")
+	block2.Height = 11
+	block2.Txs = []common.Bytes{
+		newTx([]*types.Log{transferLog(token, alice, bob, 300), transferLog(token, bob, common.Address{}, 100)}, nil),
+	}
+	block2.UpdateHash()
+	eb2, err := chain.AddBlock(block2)
+	require.Nil(err)
+
+	chain.AddTokenTransfers(eb1)
+	chain.AddTokenTransfers(eb2)
+	// Adding a block again does not count its transfers twice
+	chain.AddTokenTransfers(eb1)
+
+	height, ok := chain.GetTokenIndexHeight()
+	require.True(ok)
+	assert.Equal(uint64(11), height)
+
+	balances := chain.GetTokenBalances(alice)
+	require.Equal(1, len(balances))
+	assert.Equal(token, balances[0].Token)
+	assert.Equal(big.NewInt(700), balances[0].Balance)
+	balance, ok := chain.GetTokenBalance(bob, token)
+	require.True(ok)
+	assert.Equal(big.NewInt(200), balance.Balance)
+	assert.Equal(uint64(11), balance.LastHeight)
+	_, ok = chain.GetTokenBalance(bob, nft)
+	assert.False(ok)
+	assert.Equal(0, len(chain.GetTokenBalances(common.Address{})))
+
+	assert.Equal(uint64(2), chain.GetTokenTransferCount(alice))
+	transfers := chain.GetTokenTransfers(alice, 0, 10, true)
+	require.Equal(2, len(transfers))
+	assert.Equal(alice, transfers[0].From)
+	assert.Equal(bob, transfers[0].To)
+	assert.Equal(big.NewInt(300), transfers[0].Value)
+	assert.Equal(uint64(11), transfers[0].BlockHeight)
+	assert.Equal(common.Address{}, transfers[1].From)
+	assert.Equal(uint64(10), transfers[1].BlockHeight)
+
+	transfers = chain.GetTokenTransfers(bob, 1, 10, false)
+	require.Equal(1, len(transfers))
+	assert.Equal(common.Address{}, transfers[0].To)
+	assert.Equal(uint64(1), transfers[0].LogIndex)
+}
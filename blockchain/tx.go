@@ -43,7 +43,29 @@ func (ch *Chain) AddTxsToIndex(block *core.ExtendedBlock, force bool) {
 		if err != nil {
 			logger.Panic(err)
 		}
+
+		// Transactions signed by Ethereum wallets can also be looked up by their Ethereum hash
+		if ethTxHash, ok := getEthTxHash(block.ChainID, tx); ok {
+			err := ch.store.Put(txIndexKey(ethTxHash), txIndexEntry)
+			if err != nil {
+				logger.Panic(err)
+			}
+		}
+	}
+}
+
+// getEthTxHash returns the Ethereum hash of the raw transaction, if it is a smart contract
+// transaction signed as an Ethereum transaction
+func getEthTxHash(chainID string, raw common.Bytes) (common.Hash, bool) {
+	tx, err := types.TxFromBytes(raw)
+	if err != nil {
+		return common.Hash{}, false
+	}
+	sctx, ok := tx.(*types.SmartContractTx)
+	if !ok {
+		return common.Hash{}, false
 	}
+	return types.EthTxHash(chainID, sctx)
 }
 
 // FindTxByHash looks up transaction by hash and additionally returns the containing block.
@@ -81,6 +103,7 @@ type TxReceiptEntry struct {
 	ContractAddress common.Address
 	GasUsed         uint64
 	EvmErr          string
+	RevertReason    string `rlp:"optional"` // the Error(string) reason of a reverted execution, decoded from EvmRet
 }
 
 // AddTxReceipt adds transaction receipt.
@@ -93,8 +116,10 @@ func (ch *Chain) AddTxReceipt(tx types.Tx, logs []*types.Log, evmRet common.Byte
 	}
 	txHash := crypto.Keccak256Hash(raw)
 	errStr := ""
+	revertReason := ""
 	if evmErr != nil {
 		errStr = evmErr.Error()
+		revertReason = types.UnpackRevertReason(evmRet)
 	}
 	txReceiptEntry := TxReceiptEntry{
 		TxHash:          txHash,
@@ -103,6 +128,7 @@ func (ch *Chain) AddTxReceipt(tx types.Tx, logs []*types.Log, evmRet common.Byte
 		ContractAddress: contractAddr,
 		GasUsed:         gasUsed,
 		EvmErr:          errStr,
+		RevertReason:    revertReason,
 	}
 	key := txReceiptKey(txHash)
 
@@ -119,6 +145,9 @@ func (ch *Chain) FindTxReceiptByHash(hash common.Hash) (*TxReceiptEntry, bool) {
 	key := txReceiptKey(hash)
 
 	err := ch.store.Get(key, txReceiptEntry)
+	if err == store.ErrKeyNotFound {
+		txReceiptEntry, err = ch.findFrozenTxReceipt(hash)
+	}
 
 	if err != nil {
 		if err != store.ErrKeyNotFound {
@@ -126,5 +155,9 @@ func (ch *Chain) FindTxReceiptByHash(hash common.Hash) (*TxReceiptEntry, bool) {
 		}
 		return nil, false
 	}
+	if len(txReceiptEntry.EvmErr) != 0 && len(txReceiptEntry.RevertReason) == 0 {
+		// Decode the reason of the receipts recorded before it was stored
+		txReceiptEntry.RevertReason = types.UnpackRevertReason(txReceiptEntry.EvmRet)
+	}
 	return txReceiptEntry, true
 }
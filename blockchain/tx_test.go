@@ -1,6 +1,8 @@
 package blockchain
 
 import (
+	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,6 +10,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
 )
 
 func TestTxIndex(t *testing.T) {
@@ -40,6 +43,44 @@ func TestTxIndex(t *testing.T) {
 	assert.Nil(block)
 }
 
+func TestTxIndexEthTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// A smart contract transaction signed by an Ethereum wallet
+	privAcc := types.PrivAccountFromSecret("eth_signer")
+	sctx := &types.SmartContractTx{
+		From:     types.NewTxInput(privAcc.Address, types.NewCoins(0, 0), 1),
+		To:       types.TxOutput{Address: common.HexToAddress("0x3535353535353535353535353535353535353535")},
+		GasLimit: 21000,
+		GasPrice: big.NewInt(4000000000000),
+	}
+	sig, err := privAcc.PrivKey.Sign(types.EthSignBytes("testchain", sctx))
+	require.Nil(err)
+	sctx.From.Signature = sig
+	ethRaw, err := types.EthTxFromSmartContractTx("testchain", sctx)
+	require.Nil(err)
+	raw, err := types.TxToBytes(sctx)
+	require.Nil(err)
+
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{common.Bytes("tx1"), raw}
+	block1.UpdateHash()
+
+	chain := CreateTestChain()
+	_, err = chain.AddBlock(block1)
+	require.Nil(err)
+
+	// The transaction can be looked up by both its Pando hash and Ethereum hash
+	for _, hash := range []common.Hash{crypto.Keccak256Hash(raw), crypto.Keccak256Hash(ethRaw)} {
+		tx, block, found := chain.FindTxByHash(hash)
+		assert.True(found)
+		assert.Equal(raw, []byte(tx))
+		assert.Equal(block1.Hash(), block.Hash())
+	}
+}
+
 func TestTxIndexDuplicateTx(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -176,3 +217,51 @@ func TestTxIndexDuplicateTx2(t *testing.T) {
 	assert.NotNil(block)
 	assert.Equal(block.Hash(), block2.Hash())
 }
+
+func TestTxReceiptRevertReason(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chain := CreateTestChain()
+	sctx := &types.SmartContractTx{
+		From:     types.NewTxInput(common.HexToAddress("0x1"), types.NewCoins(0, 0), 1),
+		To:       types.TxOutput{Address: common.HexToAddress("0x2")},
+		GasLimit: 100000,
+		GasPrice: big.NewInt(4),
+	}
+	raw, err := types.TxToBytes(sctx)
+	require.Nil(err)
+	txHash := crypto.Keccak256Hash(raw)
+
+	// revert("insufficient balance")
+	evmRet := common.Hex2Bytes("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000014" +
+		"696e73756666696369656e742062616c616e6365000000000000000000000000")
+	chain.AddTxReceipt(sctx, nil, evmRet, common.Address{}, 30000, errors.New("execution reverted"))
+	receipt, found := chain.FindTxReceiptByHash(txHash)
+	require.True(found)
+	assert.Equal("execution reverted", receipt.EvmErr)
+	assert.Equal("insufficient balance", receipt.RevertReason)
+	assert.Equal(evmRet, []byte(receipt.EvmRet))
+
+	// The receipts recorded without the revert reason are still decoded
+	legacy := struct {
+		TxHash          common.Hash
+		Logs            []*types.Log
+		EvmRet          common.Bytes
+		ContractAddress common.Address
+		GasUsed         uint64
+		EvmErr          string
+	}{TxHash: txHash, EvmRet: evmRet, GasUsed: 30000, EvmErr: "execution reverted"}
+	require.Nil(chain.store.Put(txReceiptKey(txHash), legacy))
+	receipt, found = chain.FindTxReceiptByHash(txHash)
+	require.True(found)
+	assert.Equal("insufficient balance", receipt.RevertReason)
+
+	// No reason for the successful executions
+	chain.AddTxReceipt(sctx, nil, evmRet, common.Address{}, 30000, nil)
+	receipt, found = chain.FindTxReceiptByHash(txHash)
+	require.True(found)
+	assert.Equal("", receipt.RevertReason)
+}
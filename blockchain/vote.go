@@ -28,7 +28,9 @@ func (ch *Chain) AddVoteToIndex(vote core.Vote) {
 // FindVotesByHash looks up votes by hash.
 func (ch *Chain) FindVotesByHash(hash common.Hash) *core.VoteSet {
 	voteSet := core.NewVoteSet()
-	ch.store.Get(voteIndexKey(hash), voteSet)
+	if ch.store.Get(voteIndexKey(hash), voteSet) != nil {
+		ch.findFrozenVotes(hash, voteSet)
+	}
 	return voteSet
 }
 
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/pandotoken/pando/bench"
+)
+
+var (
+	benchScenarioFlag    string
+	benchNumNodesFlag    int
+	benchNumTxsFlag      int
+	benchTxsPerBlockFlag int
+	benchOutputFlag      string
+	benchBaselineFlag    string
+	benchCurrentFlag     string
+	benchToleranceFlag   float64
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark the transaction throughput and the state growth on an in-process network.",
+}
+
+// benchRunCmd runs a benchmark scenario and writes the report.
+// Example:
+//		pando bench run --scenario=sends --txs=100000 --output=sends.json
+var benchRunCmd = &cobra.Command{
+	Use:     "run",
+	Short:   "Run a benchmark scenario and write the report",
+	Example: `pando bench run --scenario=sends --txs=100000 --output=sends.json`,
+	Run:     runBench,
+}
+
+// benchCompareCmd compares a report against a baseline report, and exits with a non-zero
+// code on regressions.
+// Example:
+//		pando bench compare --baseline=sends-v2.2.0.json --current=sends.json --tolerance=0.1
+var benchCompareCmd = &cobra.Command{
+	Use:     "compare",
+	Short:   "Compare a benchmark report against a baseline report",
+	Example: `pando bench compare --baseline=sends-v2.2.0.json --current=sends.json --tolerance=0.1`,
+	Run:     runBenchCompare,
+}
+
+func init() {
+	RootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchRunCmd)
+	benchCmd.AddCommand(benchCompareCmd)
+
+	defaults := bench.DefaultConfig(bench.ScenarioSends)
+	benchRunCmd.Flags().StringVar(&benchScenarioFlag, "scenario", defaults.Scenario, fmt.Sprintf("scenario to run: %v", strings.Join(bench.ScenarioNames(), ", ")))
+	benchRunCmd.Flags().IntVar(&benchNumNodesFlag, "nodes", defaults.NumNodes, "number of nodes in the network")
+	benchRunCmd.Flags().IntVar(&benchNumTxsFlag, "txs", defaults.NumTxs, "number of transactions to run")
+	benchRunCmd.Flags().IntVar(&benchTxsPerBlockFlag, "txs_per_block", defaults.TxsPerBlock, "number of transactions in each block")
+	benchRunCmd.Flags().StringVar(&benchOutputFlag, "output", "", "output file of the report (default to stdout)")
+
+	benchCompareCmd.Flags().StringVar(&benchBaselineFlag, "baseline", "", "baseline report")
+	benchCompareCmd.Flags().StringVar(&benchCurrentFlag, "current", "", "report to compare against the baseline")
+	benchCompareCmd.Flags().Float64Var(&benchToleranceFlag, "tolerance", 0.1, "tolerated relative regression of each metric")
+	benchCompareCmd.MarkFlagRequired("baseline")
+	benchCompareCmd.MarkFlagRequired("current")
+}
+
+func runBench(cmd *cobra.Command, args []string) {
+	// Keep the node logs out of the measurements
+	log.SetLevel(log.WarnLevel)
+
+	cfg := bench.Config{
+		Scenario:    benchScenarioFlag,
+		NumNodes:    benchNumNodesFlag,
+		NumTxs:      benchNumTxsFlag,
+		TxsPerBlock: benchTxsPerBlockFlag,
+	}
+	report, err := bench.Run(cfg)
+	if err != nil {
+		fmt.Printf("Benchmark failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if benchOutputFlag != "" {
+		f, err := os.OpenFile(benchOutputFlag, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			fmt.Printf("Failed to create %v: %v\n", benchOutputFlag, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := report.Write(out); err != nil {
+		fmt.Printf("Failed to write the report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runBenchCompare(cmd *cobra.Command, args []string) {
+	baseline, err := bench.LoadReport(benchBaselineFlag)
+	if err != nil {
+		fmt.Printf("Failed to load the baseline report: %v\n", err)
+		os.Exit(1)
+	}
+	current, err := bench.LoadReport(benchCurrentFlag)
+	if err != nil {
+		fmt.Printf("Failed to load the current report: %v\n", err)
+		os.Exit(1)
+	}
+
+	regressions, err := bench.Compare(baseline, current, benchToleranceFlag)
+	if err != nil {
+		fmt.Printf("Failed to compare the reports: %v\n", err)
+		os.Exit(1)
+	}
+	if len(regressions) == 0 {
+		fmt.Printf("No regression against %v (%v) beyond %.0f%%\n", baseline.Version, baseline.GitHash, benchToleranceFlag*100)
+		return
+	}
+
+	fmt.Printf("Regressions against %v (%v):\n", baseline.Version, baseline.GitHash)
+	for _, regression := range regressions {
+		fmt.Printf("  %v\n", regression)
+	}
+	os.Exit(1)
+}
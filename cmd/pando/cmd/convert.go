@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/store/database/backend"
+)
+
+var convertFrom string
+
+// convertCmd represents the convert command, which converts the database of the node into the
+// backend selected by the config, e.g. an existing LevelDB data directory into RocksDB or Pebble.
+// The node needs to be stopped. Example:
+//
+//	pando convert --config=../privatenet/node --from=leveldb
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert the database to the backend selected by storage.backend.",
+	Run:   runConvert,
+}
+
+func init() {
+	RootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().StringVar(&convertFrom, "from", backend.BackendLevelDB, "the backend the database is currently stored in")
+}
+
+func runConvert(cmd *cobra.Command, args []string) {
+	to := viper.GetString(common.CfgStorageBackend)
+	if to == convertFrom {
+		log.Fatalf("The database is already stored in %v, set storage.backend to the target backend", to)
+	}
+
+	dbPath := viper.GetString(common.CfgDataPath)
+	if dbPath == "" {
+		dbPath = cfgPath
+	}
+	mainDBPath := path.Join(dbPath, "db", "main")
+	refDBPath := path.Join(dbPath, "db", "ref")
+	cache := viper.GetInt(common.CfgStorageLevelDBCacheSize)
+	handles := viper.GetInt(common.CfgStorageLevelDBHandles)
+
+	// The converted database is built next to the current one, which is kept as a backup under
+	// db/<backend> once the conversion succeeds
+	convertedPath := path.Join(dbPath, "db", to+".converting")
+	if err := os.RemoveAll(convertedPath); err != nil {
+		log.Fatalf("Failed to clean up the previous conversion: %v", err)
+	}
+
+	src, err := backend.OpenDatabase(convertFrom, mainDBPath, refDBPath, cache, handles)
+	if err != nil {
+		log.Fatalf("Failed to open the %v db. main: %v, ref: %v, err: %v", convertFrom, mainDBPath, refDBPath, err)
+	}
+	dst, err := backend.OpenDatabase(to, path.Join(convertedPath, "main"), path.Join(convertedPath, "ref"), cache, handles)
+	if err != nil {
+		src.Close()
+		log.Fatalf("Failed to create the %v db: %v", to, err)
+	}
+
+	log.Infof("Converting the db from %v to %v", convertFrom, to)
+	numEntries, err := backend.Convert(src, dst)
+	src.Close()
+	dst.Close()
+	if err != nil {
+		log.Fatalf("Failed to convert the db: %v", err)
+	}
+
+	backupPath := path.Join(dbPath, "db", convertFrom)
+	if err := os.MkdirAll(backupPath, 0700); err != nil {
+		log.Fatalf("Failed to create the backup folder: %v", err)
+	}
+	for _, name := range []string{"main", "ref"} {
+		if err := os.Rename(path.Join(dbPath, "db", name), path.Join(backupPath, name)); err != nil {
+			log.Fatalf("Failed to move the %v db into %v: %v", convertFrom, backupPath, err)
+		}
+		if err := os.Rename(path.Join(convertedPath, name), path.Join(dbPath, "db", name)); err != nil {
+			log.Fatalf("Failed to move the %v db into place: %v", to, err)
+		}
+	}
+	os.RemoveAll(convertedPath)
+
+	log.Infof("Converted %v entries from %v to %v, the %v db is kept in %v", numEntries, convertFrom, to, convertFrom, backupPath)
+}
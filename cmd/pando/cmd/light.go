@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"path"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/lightclient"
+	"github.com/pandotoken/pando/store/database/backend"
+)
+
+var lightProvider string
+var lightSyncInterval time.Duration
+
+// lightCmd represents the light command. The light node only syncs the block headers and the
+// validator set changes from the RPC endpoint of a full node, and verifies them. Example:
+//
+//	pando light --config=../privatenet/node --provider=http://localhost:16888/rpc
+var lightCmd = &cobra.Command{
+	Use:   "light",
+	Short: "Start Pando light node.",
+	Long:  `Start Pando light node, which syncs and verifies only the block headers and the validator set changes from a full node.`,
+	Run:   runLight,
+}
+
+func init() {
+	RootCmd.AddCommand(lightCmd)
+
+	lightCmd.Flags().StringVar(&lightProvider, "provider", "http://localhost:16888/rpc", "RPC endpoint of the full node to sync from")
+	lightCmd.Flags().DurationVar(&lightSyncInterval, "interval", 5*time.Second, "Interval between the syncs")
+}
+
+func runLight(cmd *cobra.Command, args []string) {
+	configureForks()
+
+	dbPath := viper.GetString(common.CfgDataPath)
+	if dbPath == "" {
+		dbPath = cfgPath
+	}
+	mainDBPath := path.Join(dbPath, "db", "light", "main")
+	refDBPath := path.Join(dbPath, "db", "light", "ref")
+	db, err := backend.OpenDatabase(viper.GetString(common.CfgStorageBackend), mainDBPath, refDBPath,
+		viper.GetInt(common.CfgStorageLevelDBCacheSize),
+		viper.GetInt(common.CfgStorageLevelDBHandles))
+	if err != nil {
+		log.Fatalf("Failed to connect to the db. main: %v, ref: %v, err: %v",
+			mainDBPath, refDBPath, err)
+	}
+	defer db.Close()
+
+	lc, err := lightclient.NewLightClient(db, lightclient.NewRPCProvider(lightProvider))
+	if err != nil {
+		log.Fatalf("Failed to load the light client: %v", err)
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	defer signal.Stop(c)
+
+	ticker := time.NewTicker(lightSyncInterval)
+	defer ticker.Stop()
+	for {
+		if err := lc.Sync(); err != nil {
+			log.Warnf("Failed to sync from %v: %v", lightProvider, err)
+		} else if header := lc.LatestHeader(); header != nil {
+			log.Infof("Latest finalized header, height: %v, hash: %v", header.Height, header.Hash().Hex())
+		}
+
+		select {
+		case <-c:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/pandotoken/pando/store/migration"
+)
+
+var migrateDryRun bool
+
+// migrateCmd represents the migrate command. The node migrates its database at startup, the
+// command allows to check the pending migrations beforehand. Example:
+//
+//	pando migrate --config=../privatenet/node --dry_run
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate the database to the latest schema version.",
+	Run:   runMigrate,
+}
+
+func init() {
+	RootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry_run", false, "report the pending migrations without modifying the database")
+}
+
+func runMigrate(cmd *cobra.Command, args []string) {
+	dbPath := viper.GetString(common.CfgDataPath)
+	if dbPath == "" {
+		dbPath = cfgPath
+	}
+	mainDBPath := path.Join(dbPath, "db", "main")
+	refDBPath := path.Join(dbPath, "db", "ref")
+	db, err := backend.OpenDatabase(viper.GetString(common.CfgStorageBackend), mainDBPath, refDBPath,
+		viper.GetInt(common.CfgStorageLevelDBCacheSize),
+		viper.GetInt(common.CfgStorageLevelDBHandles))
+	if err != nil {
+		log.Fatalf("Failed to connect to the db. main: %v, ref: %v, err: %v",
+			mainDBPath, refDBPath, err)
+	}
+	defer db.Close()
+
+	if err := migrateDB(db, dbPath, migrateDryRun); err != nil {
+		log.Fatalf("Failed to migrate the db: %v", err)
+	}
+}
+
+// migrateDB brings the database to the latest schema version. A new database is stamped with the
+// latest version, since it is created in the latest layout.
+func migrateDB(db backend.NodeDatabase, dbPath string, dryRun bool) error {
+	if _, err := db.Get([]byte(migration.SchemaVersionKey)); err != nil {
+		if _, err := db.Get([]byte("/snapshot_blockheader")); err != nil {
+			if dryRun {
+				return nil
+			}
+			return migration.Initialize(db)
+		}
+	}
+
+	runner := migration.NewRunner(db)
+	runner.SetDryRun(dryRun)
+	if viper.GetBool(common.CfgStorageMigrationBackup) {
+		runner.SetBackupHook(func(from uint64, to uint64) error {
+			backupPath := path.Join(dbPath, "db", "backup", fmt.Sprintf("v%v-%v", from, time.Now().Unix()))
+			log.Infof("Backing up the db before migrating from schema version %v to %v: %v", from, to, backupPath)
+			return db.Backup(path.Join(backupPath, "main"), path.Join(backupPath, "ref"))
+		})
+	}
+	return runner.Run()
+}
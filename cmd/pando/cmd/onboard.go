@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	qrcode "github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
+)
+
+// StakePayloadFileName is the file under the config path the unsigned staking transaction is saved to
+const StakePayloadFileName = "rametron_stake.json"
+
+var (
+	onboardOperatorFlag string
+	onboardTierFlag     string
+	onboardStakeFlag    string
+	onboardFeeFlag      string
+	onboardRPCFlag      string
+	onboardSeedsFlag    []string
+	onboardTimeoutFlag  time.Duration
+	onboardQRFlag       bool
+)
+
+// onboardCmd represents the onboard command
+var onboardCmd = &cobra.Command{
+	Use:   "onboard",
+	Short: "Set up a new node in one guided flow.",
+}
+
+// onboardRametronCmd sets up a Rametron node: it generates the node key, produces the staking transaction
+// for the operator's wallet to sign, waits for the stake to be confirmed on-chain, and configures the seeds.
+// Example:
+//
+//	pando onboard rametron --config=../privatenet/node --operator=2E833968E5bB786Ae419c4d13189fB081Cc43bab --tier=pro --rpc=http://rpc.pando.network/rpc
+var onboardRametronCmd = &cobra.Command{
+	Use:   "rametron",
+	Short: "Onboard a Rametron node",
+	Long: `Onboard a Rametron node. The node key is generated under the config path if it does not exist yet.
+The command then prints the staking transaction from the operator's wallet to the node, both as a pandocli
+command and as a QR code for a mobile wallet, and saves it to ` + StakePayloadFileName + `. Once the operator
+signs and broadcasts the transaction, the command waits until the node is registered for the tier on-chain,
+and writes the peers of the RPC node to the seeds of the config. The node can then be started with "pando start".`,
+	Example: `pando onboard rametron --config=../privatenet/node --operator=2E833968E5bB786Ae419c4d13189fB081Cc43bab --tier=pro --rpc=http://rpc.pando.network/rpc`,
+	Run:     runOnboardRametron,
+}
+
+func init() {
+	RootCmd.AddCommand(onboardCmd)
+	onboardCmd.AddCommand(onboardRametronCmd)
+
+	onboardRametronCmd.Flags().StringVar(&onboardOperatorFlag, "operator", "", "Address of the operator's wallet which stakes for the node")
+	onboardRametronCmd.Flags().StringVar(&onboardTierFlag, "tier", "mobile", "Tier of the node (mobile|pro|enterprise)")
+	onboardRametronCmd.Flags().StringVar(&onboardStakeFlag, "stake", "", "Pando amount to stake (default to the minimum stake of the tier)")
+	onboardRametronCmd.Flags().StringVar(&onboardFeeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee of the staking transaction")
+	onboardRametronCmd.Flags().StringVar(&onboardRPCFlag, "rpc", fmt.Sprintf("http://localhost:%d/rpc", core.DefaultRPCPort), "RPC endpoint of a synced node of the network to join")
+	onboardRametronCmd.Flags().StringSliceVar(&onboardSeedsFlag, "seeds", []string{}, "Seeds to configure in addition to the peers of the RPC node")
+	onboardRametronCmd.Flags().DurationVar(&onboardTimeoutFlag, "timeout", 30*time.Minute, "How long to wait for the stake to be confirmed")
+	onboardRametronCmd.Flags().BoolVar(&onboardQRFlag, "qr", true, "Print the staking transaction as a QR code")
+
+	onboardRametronCmd.MarkFlagRequired("operator")
+}
+
+func runOnboardRametron(cmd *cobra.Command, args []string) {
+	if !common.IsHexAddress(onboardOperatorFlag) {
+		log.Fatalf("Invalid operator address: %v", onboardOperatorFlag)
+	}
+	operator := common.HexToAddress(onboardOperatorFlag)
+
+	tier, err := types.ParseRametronTier(onboardTierFlag)
+	if err != nil || tier == types.RametronTierNone {
+		log.Fatalf("Invalid tier: %v", onboardTierFlag)
+	}
+	spec, _ := types.GetRametronTierSpec(tier)
+	stake := spec.MinStake
+	if onboardStakeFlag != "" {
+		var ok bool
+		if stake, ok = types.ParseCoinAmount(onboardStakeFlag); !ok {
+			log.Fatalf("Failed to parse the stake: %v", onboardStakeFlag)
+		}
+		if stake.Cmp(spec.MinStake) < 0 {
+			log.Fatalf("The %v tier requires a stake of at least %v PandoWei", tier, spec.MinStake)
+		}
+	}
+	fee, ok := types.ParseCoinAmount(onboardFeeFlag)
+	if !ok {
+		log.Fatalf("Failed to parse the fee: %v", onboardFeeFlag)
+	}
+
+	// Step 1: node key
+	fmt.Println("[1/4] Setting up the node key")
+	if err := ensureConfig(); err != nil {
+		log.Fatalf("Failed to set up the config under %v: %v", cfgPath, err)
+	}
+	privKey, err := loadOrCreateKey()
+	if err != nil {
+		log.Fatalf("Failed to load or create key: %v", err)
+	}
+	nodeAddress := privKey.PublicKey().Address()
+	fmt.Printf("Node address: %v\n\n", nodeAddress.Hex())
+
+	client := rpcc.NewRPCClient(onboardRPCFlag)
+	status := &rpc.GetStatusResult{}
+	if err := callOnboardRPC(client, "pando.GetStatus", rpc.GetStatusArgs{}, status); err != nil {
+		log.Fatalf("Failed to get the status of %v: %v", onboardRPCFlag, err)
+	}
+
+	// Step 2: staking transaction
+	fmt.Println("[2/4] Staking from the operator's wallet")
+	if isRametronStaked(client, nodeAddress, tier, spec.MinStake) {
+		fmt.Printf("The node is already registered for the %v tier, skipping\n\n", tier)
+	} else {
+		operatorAccount := &rpc.GetAccountResult{}
+		if err := callOnboardRPC(client, "pando.GetAccount", rpc.GetAccountArgs{Address: operator.Hex()}, operatorAccount); err != nil {
+			log.Fatalf("Failed to get the operator account %v: %v", operator.Hex(), err)
+		}
+		stakeTx := newOnboardStakeTx(operator, operatorAccount.Sequence+1, nodeAddress, tier, stake, fee)
+		if err := printStakePayload(status.ChainID, stakeTx, tier); err != nil {
+			log.Fatalf("Failed to produce the staking transaction: %v", err)
+		}
+
+		// Step 3: stake confirmation
+		fmt.Printf("[3/4] Waiting up to %v for the stake to be confirmed on-chain\n", onboardTimeoutFlag)
+		if !waitForRametronStake(client, nodeAddress, tier, spec.MinStake) {
+			log.Fatalf("The stake of %v is not confirmed after %v. Re-run the command once the transaction is broadcasted.",
+				nodeAddress.Hex(), onboardTimeoutFlag)
+		}
+		fmt.Printf("The node is registered for the %v tier\n\n", tier)
+	}
+
+	// Step 4: peers
+	fmt.Println("[4/4] Configuring the peers")
+	seeds := append([]string{}, onboardSeedsFlag...)
+	peerURLs := &rpc.GetPeerURLsResult{}
+	if err := callOnboardRPC(client, "pando.GetPeerURLs", rpc.GetPeerURLsArgs{}, peerURLs); err != nil {
+		log.Warnf("Failed to get the peers of %v: %v", onboardRPCFlag, err)
+	} else {
+		seeds = append(seeds, peerURLs.PeerURLs...)
+	}
+	seeds = dedupSeeds(seeds)
+	if len(seeds) == 0 {
+		log.Fatalf("No seeds found, please specify them with --seeds")
+	}
+	if err := writeSeeds(seeds); err != nil {
+		log.Fatalf("Failed to write the seeds to the config: %v", err)
+	}
+	fmt.Printf("Configured %v seeds\n\n", len(seeds))
+
+	fmt.Printf("The Rametron node is ready, start it with: pando start --config=%v\n", cfgPath)
+}
+
+// ensureConfig creates the config folder with the initial config, unless it exists
+func ensureConfig() error {
+	if _, err := os.Stat(path.Join(cfgPath, "config.yaml")); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(cfgPath, 0700); err != nil {
+		return err
+	}
+	return common.WriteInitialConfig(path.Join(cfgPath, "config.yaml"))
+}
+
+// newOnboardStakeTx creates the unsigned transaction staking for the node from the operator's wallet
+func newOnboardStakeTx(operator common.Address, sequence uint64, node common.Address, tier types.RametronTier,
+	stake *big.Int, fee *big.Int) *types.RametronStakeTx {
+	return &types.RametronStakeTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Inputs: []types.TxInput{{
+			Address: operator,
+			Coins: types.Coins{
+				PandoWei: stake,
+				PTXWei:   fee,
+			},
+			Sequence: sequence,
+		}},
+		Outputs: []types.TxOutput{{
+			Address: node,
+			Coins: types.Coins{
+				PandoWei: stake,
+				PTXWei:   new(big.Int).SetUint64(0),
+			},
+		}},
+		Tier: tier,
+	}
+}
+
+// StakePayload is the unsigned staking transaction for the operator's wallet to sign
+type StakePayload struct {
+	ChainID   string `json:"chain_id"`
+	Tx        string `json:"tx"`         // hex of the unsigned raw transaction
+	SignBytes string `json:"sign_bytes"` // hex of the bytes to sign
+}
+
+// printStakePayload prints the staking transaction as a pandocli command and as a QR code, and saves it
+// under the config path
+func printStakePayload(chainID string, tx *types.RametronStakeTx, tier types.RametronTier) error {
+	raw, err := types.TxToBytes(tx)
+	if err != nil {
+		return err
+	}
+	payload := StakePayload{
+		ChainID:   chainID,
+		Tx:        hex.EncodeToString(raw),
+		SignBytes: hex.EncodeToString(tx.SignBytes(chainID)),
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	payloadPath := path.Join(cfgPath, StakePayloadFileName)
+	if err := common.WriteFileAtomic(payloadPath, encoded, 0600); err != nil {
+		return err
+	}
+
+	input, output := tx.Inputs[0], tx.Outputs[0]
+	fmt.Println("Sign and broadcast the staking transaction with the operator's wallet, e.g. with pandocli:")
+	fmt.Println("")
+	fmt.Printf("  pandocli tx rametronStake --chain=%v --from=%v --to=%v --pando=%vwei --fee=%vwei --tier=%v --seq=%v\n",
+		chainID, input.Address.Hex(), output.Address.Hex(), output.Coins.PandoWei, tx.Fee.PTXWei, strings.ToLower(tier.String()), input.Sequence)
+	fmt.Println("")
+	if onboardQRFlag {
+		qr, err := qrcode.New(string(encoded), qrcode.Low)
+		if err != nil {
+			return err
+		}
+		fmt.Println("or scan the unsigned transaction with a mobile wallet:")
+		fmt.Println(qr.ToSmallString(false))
+	}
+	fmt.Printf("The unsigned transaction is saved to %v\n\n", payloadPath)
+	return nil
+}
+
+// waitForRametronStake polls the RPC node until the node is registered for the tier with enough stake
+func waitForRametronStake(client *rpcc.RPCClient, node common.Address, tier types.RametronTier, minStake *big.Int) bool {
+	deadline := time.Now().Add(onboardTimeoutFlag)
+	for time.Now().Before(deadline) {
+		if isRametronStaked(client, node, tier, minStake) {
+			return true
+		}
+		fmt.Print(".")
+		time.Sleep(time.Duration(viper.GetInt(common.CfgConsensusMinProposalWait)) * time.Second)
+	}
+	fmt.Println("")
+	return false
+}
+
+// isRametronStaked checks whether the node is registered for the tier with enough stake as of the
+// last finalized block
+func isRametronStaked(client *rpcc.RPCClient, node common.Address, tier types.RametronTier, minStake *big.Int) bool {
+	result := &rpc.GetRametronNodeResult{}
+	if err := callOnboardRPC(client, "pando.GetRametronNode", rpc.GetRametronNodeArgs{Address: node.Hex()}, result); err != nil {
+		return false // not registered yet
+	}
+	if result.Node == nil || result.Node.Tier != tier || result.Stake == nil {
+		return false
+	}
+	return (*big.Int)(result.Stake).Cmp(minStake) >= 0
+}
+
+func callOnboardRPC(client *rpcc.RPCClient, method string, args interface{}, result interface{}) error {
+	res, err := client.Call(method, args)
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	return res.GetObject(result)
+}
+
+func dedupSeeds(seeds []string) []string {
+	deduped := []string{}
+	seen := make(map[string]bool)
+	for _, seed := range seeds {
+		seed = strings.TrimSpace(seed)
+		if seed == "" || seen[seed] {
+			continue
+		}
+		seen[seed] = true
+		deduped = append(deduped, seed)
+	}
+	return deduped
+}
+
+// writeSeeds sets the seeds in the config file. A separate viper instance is used so that only the
+// settings of the file are written back, not the defaults.
+func writeSeeds(seeds []string) error {
+	cfg := viper.New()
+	cfg.SetConfigFile(path.Join(cfgPath, "config.yaml"))
+	if err := cfg.ReadInConfig(); err != nil {
+		return err
+	}
+	cfg.Set(common.CfgP2PSeeds, strings.Join(seeds, ","))
+	return cfg.WriteConfig()
+}
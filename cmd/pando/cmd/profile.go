@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/profiling"
+)
+
+var (
+	profileDurationFlag time.Duration
+	profileAddressFlag  string
+	profileTokenFlag    string
+	profileOutputFlag   string
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Collect performance profiles from a running node.",
+}
+
+// profileCollectCmd collects the profiles of a running node into an archive.
+// Example:
+//		pando profile collect --config=../privatenet/node --duration=30s
+var profileCollectCmd = &cobra.Command{
+	Use:     "collect",
+	Short:   "Collect CPU, trace, heap, block, mutex and goroutine profiles into an archive",
+	Example: `pando profile collect --config=../privatenet/node --duration=30s`,
+	Run:     runProfileCollect,
+}
+
+func init() {
+	RootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileCollectCmd)
+
+	profileCollectCmd.Flags().DurationVar(&profileDurationFlag, "duration", 30*time.Second, "duration of the CPU profile and the execution trace")
+	profileCollectCmd.Flags().StringVar(&profileAddressFlag, "address", "", "address of the profiling endpoints (default to prof.address in config)")
+	profileCollectCmd.Flags().StringVar(&profileTokenFlag, "token", "", "auth token of the profiling endpoints (default to the token saved under the config path)")
+	profileCollectCmd.Flags().StringVar(&profileOutputFlag, "output", "", "output file (default to pando-profile-<timestamp>.tar.gz)")
+}
+
+func runProfileCollect(cmd *cobra.Command, args []string) {
+	address := profileAddressFlag
+	if address == "" {
+		address = viper.GetString(common.CfgProfAddress)
+	}
+
+	token := profileTokenFlag
+	if token == "" {
+		token = viper.GetString(common.CfgProfAuthToken)
+	}
+	if token == "" {
+		raw, err := ioutil.ReadFile(path.Join(cfgPath, profiling.TokenFileName))
+		if err != nil {
+			fmt.Printf("Failed to load the profiling auth token: %v\n", err)
+			os.Exit(1)
+		}
+		token = strings.TrimSpace(string(raw))
+	}
+
+	output := profileOutputFlag
+	if output == "" {
+		output = fmt.Sprintf("pando-profile-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	}
+	f, err := os.OpenFile(output, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		fmt.Printf("Failed to create %v: %v\n", output, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	fmt.Printf("Collecting profiles from %v for %v...\n", address, profileDurationFlag)
+	if err := profiling.Collect(address, token, profileDurationFlag, f); err != nil {
+		fmt.Printf("Failed to collect profiles: %v\n", err)
+		os.Remove(output)
+		os.Exit(1)
+	}
+	fmt.Printf("Profiles saved to %v\n", output)
+}
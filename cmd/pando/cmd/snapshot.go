@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/snapshot"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/pandotoken/pando/store/freezer"
+	"github.com/pandotoken/pando/store/kvstore"
+)
+
+var (
+	snapshotExportHeight uint64
+	snapshotExportOut    string
+	snapshotImportBundle string
+	snapshotImportSigner string
+)
+
+// snapshotCmd represents the snapshot command, which exports and imports the snapshots of the
+// state as signed bundles. The node needs to be stopped. Example:
+//
+//	pando snapshot export --config=../privatenet/node --height=1000 --out=./bundle
+//	pando snapshot import --config=../privatenet/node2 --bundle=./bundle --signer=0x2E83...
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export or import a signed snapshot bundle.",
+}
+
+var snapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the snapshot at a finalized height as a bundle signed by the node key.",
+	Run:   runSnapshotExport,
+}
+
+var snapshotImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Verify a snapshot bundle and install it as the snapshot of the node.",
+	Run:   runSnapshotImport,
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotExportCmd)
+	snapshotCmd.AddCommand(snapshotImportCmd)
+	RootCmd.AddCommand(snapshotCmd)
+
+	snapshotExportCmd.Flags().Uint64Var(&snapshotExportHeight, "height", 0, "the height of the finalized block to export the snapshot at")
+	snapshotExportCmd.Flags().StringVar(&snapshotExportOut, "out", "", "the directory to write the bundle into (default is <config>/snapshot_bundle-<height>)")
+	snapshotExportCmd.MarkFlagRequired("height")
+
+	snapshotImportCmd.Flags().StringVar(&snapshotImportBundle, "bundle", "", "the directory of the bundle")
+	snapshotImportCmd.Flags().StringVar(&snapshotImportSigner, "signer", "", "the address expected to have signed the bundle")
+	snapshotImportCmd.MarkFlagRequired("bundle")
+}
+
+func runSnapshotExport(cmd *cobra.Command, args []string) {
+	if snapshotExportHeight == 0 {
+		log.Fatalf("Please specify the height of the snapshot with --height")
+	}
+
+	dbPath := viper.GetString(common.CfgDataPath)
+	if dbPath == "" {
+		dbPath = cfgPath
+	}
+	mainDBPath := path.Join(dbPath, "db", "main")
+	refDBPath := path.Join(dbPath, "db", "ref")
+	db, err := backend.OpenDatabase(viper.GetString(common.CfgStorageBackend), mainDBPath, refDBPath,
+		viper.GetInt(common.CfgStorageLevelDBCacheSize),
+		viper.GetInt(common.CfgStorageLevelDBHandles))
+	if err != nil {
+		log.Fatalf("Failed to connect to the db. main: %v, ref: %v, err: %v", mainDBPath, refDBPath, err)
+	}
+	defer db.Close()
+
+	raw, err := db.Get([]byte("/snapshot_blockheader"))
+	if err != nil {
+		log.Fatalf("The db has not been initialized from a snapshot yet: %v", err)
+	}
+	rootHeader := &core.BlockHeader{}
+	if err := rlp.DecodeBytes(raw, rootHeader); err != nil {
+		log.Fatalf("Failed to decode the snapshot block header: %v", err)
+	}
+	chain := blockchain.NewChain(rootHeader.ChainID, kvstore.NewKVStore(db), &core.Block{BlockHeader: rootHeader})
+	if viper.GetBool(common.CfgStorageFreezerEnabled) {
+		freezerPath := viper.GetString(common.CfgStorageFreezerPath)
+		if freezerPath == "" {
+			freezerPath = path.Join(dbPath, "db", "freezer")
+		}
+		blockFreezer, err := freezer.Open(freezerPath, blockchain.FreezerTables)
+		if err != nil {
+			log.Fatalf("Failed to open the freezer: %v, err: %v", freezerPath, err)
+		}
+		defer blockFreezer.Close()
+		chain.SetFreezer(blockFreezer)
+	}
+
+	var block *core.ExtendedBlock
+	for _, b := range chain.FindBlocksByHeight(snapshotExportHeight) {
+		if b.Status.IsDirectlyFinalized() {
+			block = b
+			break
+		}
+	}
+	if block == nil {
+		log.Fatalf("Can't find a directly finalized block at height %v", snapshotExportHeight)
+	}
+
+	var nodeSigner crypto.Signer
+	remoteSigner, err := connectRemoteSigner()
+	if err != nil {
+		log.Fatalf("Failed to connect to signer: %v", err)
+	}
+	if remoteSigner != nil {
+		nodeSigner = remoteSigner
+	} else {
+		privKey, err := loadOrCreateKey()
+		if err != nil {
+			log.Fatalf("Failed to load or create key: %v", err)
+		}
+		nodeSigner = privKey
+	}
+
+	tmpDir, err := ioutil.TempDir(dbPath, "snapshot_export_")
+	if err != nil {
+		log.Fatalf("Failed to create the temporary folder: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	log.Infof("Exporting the snapshot at height %v, block %v", block.Height, block.Hash().Hex())
+	filename, err := snapshot.ExportSnapshot(db, nil, chain, tmpDir, snapshotExportHeight)
+	if err != nil {
+		log.Fatalf("Failed to export the snapshot: %v", err)
+	}
+
+	out := snapshotExportOut
+	if out == "" {
+		out = path.Join(cfgPath, fmt.Sprintf("snapshot_bundle-%v", block.Height))
+	}
+	manifest, err := snapshot.WriteBundle(path.Join(tmpDir, filename), block.BlockHeader, out, nodeSigner)
+	if err != nil {
+		log.Fatalf("Failed to write the snapshot bundle: %v", err)
+	}
+	log.Infof("Exported the snapshot bundle to %v, signed by %v", out, manifest.Signer.Hex())
+}
+
+func runSnapshotImport(cmd *cobra.Command, args []string) {
+	manifest, err := snapshot.LoadBundleManifest(snapshotImportBundle)
+	if err != nil {
+		log.Fatalf("Failed to verify the snapshot bundle manifest: %v", err)
+	}
+	if snapshotImportSigner != "" {
+		if !common.IsHexAddress(snapshotImportSigner) {
+			log.Fatalf("Invalid signer address: %v", snapshotImportSigner)
+		}
+		if expected := common.HexToAddress(snapshotImportSigner); manifest.Signer != expected {
+			log.Fatalf("The snapshot bundle is signed by %v, expected %v", manifest.Signer.Hex(), expected.Hex())
+		}
+	} else {
+		log.Warnf("No --signer given, please check that the bundle signer %v is trusted", manifest.Signer.Hex())
+	}
+	log.Infof("Importing %v", manifest)
+
+	if len(snapshotPath) == 0 {
+		snapshotPath = path.Join(cfgPath, "snapshot")
+	}
+	importPath := snapshotPath + ".import"
+	defer os.Remove(importPath)
+	if err := snapshot.AssembleBundle(snapshotImportBundle, manifest, importPath); err != nil {
+		log.Fatalf("Failed to assemble the snapshot bundle: %v", err)
+	}
+
+	// The chunks match the signed checksums, check the snapshot content matches the manifest too
+	header, err := snapshot.ValidateSnapshot(importPath, chainImportDirPath, chainCorrectionPath)
+	if err != nil {
+		log.Fatalf("Snapshot validation failed, err: %v", err)
+	}
+	if header.ChainID != manifest.ChainID || header.Height != manifest.Height || header.Hash() != manifest.BlockHash {
+		log.Fatalf("The snapshot block %v at height %v does not match the manifest", header.Hash().Hex(), header.Height)
+	}
+
+	if _, err := os.Stat(snapshotPath); err == nil {
+		backupPath := snapshotPath + ".bak"
+		if err := os.Rename(snapshotPath, backupPath); err != nil {
+			log.Fatalf("Failed to back up the current snapshot: %v", err)
+		}
+		log.Infof("The current snapshot is kept in %v", backupPath)
+	}
+	if err := os.Rename(importPath, snapshotPath); err != nil {
+		log.Fatalf("Failed to move the snapshot into place: %v", err)
+	}
+	log.Infof("Imported the snapshot at height %v into %v", header.Height, snapshotPath)
+}
@@ -3,8 +3,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"net/http"
-	_ "net/http/pprof"
 	"os"
 	"os/signal"
 	"path"
@@ -15,17 +13,24 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/pandotoken/pando/blockchain"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/util"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/netsync"
 	"github.com/pandotoken/pando/node"
 	msg "github.com/pandotoken/pando/p2p/messenger"
 	msgl "github.com/pandotoken/pando/p2pl/messenger"
+	"github.com/pandotoken/pando/profiling"
 	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/signer"
 	"github.com/pandotoken/pando/snapshot"
+	"github.com/pandotoken/pando/store/database"
 	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/pandotoken/pando/store/freezer"
 	ks "github.com/pandotoken/pando/wallet/softwallet/keystore"
 )
 
@@ -38,6 +43,13 @@ var startCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(startCmd)
+
+	startCmd.Flags().Bool("skip_genesis_check", false, "start the node even if the genesis block does not match the expected hash")
+	viper.BindPFlag(common.CfgGenesisSkipHashCheck, startCmd.Flags().Lookup("skip_genesis_check"))
+	startCmd.Flags().String("restart_manifest", "", "restart the halted chain from the block of the restart manifest signed by the validators")
+	viper.BindPFlag(common.CfgConsensusRestartManifest, startCmd.Flags().Lookup("restart_manifest"))
+	startCmd.Flags().Bool("archive", false, "run an archive node, which retains the states of all the blocks for the historical queries")
+	viper.BindPFlag(common.CfgStorageArchive, startCmd.Flags().Lookup("archive"))
 }
 
 func runStart(cmd *cobra.Command, args []string) {
@@ -45,10 +57,32 @@ func runStart(cmd *cobra.Command, args []string) {
 	var network *msgl.Messenger
 	var err error
 
-	privKey, err := loadOrCreateKey()
+	forkOverrides := configureForks()
+
+	// The old p2p network uses the key for the handshake. When a separate signer holds the
+	// node key, a dedicated p2p key is used instead so that the node key stays out of the process.
+	var nodeSigner crypto.Signer
+	var p2pKey *crypto.PrivateKey
+	remoteSigner, err := connectRemoteSigner()
 	if err != nil {
-		log.Fatalf("Failed to load or create key: %v", err)
+		log.Fatalf("Failed to connect to signer: %v", err)
 	}
+	if remoteSigner != nil {
+		nodeSigner = remoteSigner
+		p2pKey, err = loadOrCreateP2PKey()
+		if err != nil {
+			log.Fatalf("Failed to load or create p2p key: %v", err)
+		}
+	} else {
+		privKey, err := loadOrCreateKey()
+		if err != nil {
+			log.Fatalf("Failed to load or create key: %v", err)
+		}
+		nodeSigner = privKey
+		p2pKey = privKey
+	}
+
+	crypto.SetSignatureCacheSize(viper.GetInt(common.CfgConsensusSignatureCacheSize))
 
 	// Open database
 	dbPath := viper.GetString(common.CfgDataPath)
@@ -58,7 +92,7 @@ func runStart(cmd *cobra.Command, args []string) {
 
 	mainDBPath := path.Join(dbPath, "db", "main")
 	refDBPath := path.Join(dbPath, "db", "ref")
-	db, err := backend.NewLDBDatabase(mainDBPath, refDBPath,
+	db, err := backend.OpenDatabase(viper.GetString(common.CfgStorageBackend), mainDBPath, refDBPath,
 		viper.GetInt(common.CfgStorageLevelDBCacheSize),
 		viper.GetInt(common.CfgStorageLevelDBHandles))
 
@@ -67,11 +101,71 @@ func runStart(cmd *cobra.Command, args []string) {
 			mainDBPath, refDBPath, err)
 	}
 
+	if err := migrateDB(db, dbPath, false); err != nil {
+		log.Fatalf("Failed to migrate the db: %v", err)
+	}
+
+	var nodeDB database.Database = db
+	if viper.GetBool(common.CfgStorageArchive) {
+		ancientDBPath := viper.GetString(common.CfgStorageAncientPath)
+		if ancientDBPath == "" {
+			ancientDBPath = path.Join(dbPath, "db", "ancient")
+		}
+		archiveDB, err := backend.NewArchiveDatabase(db, ancientDBPath,
+			viper.GetInt(common.CfgStorageLevelDBCacheSize),
+			viper.GetInt(common.CfgStorageLevelDBHandles))
+		if err != nil {
+			log.Fatalf("Failed to open the ancient db: %v, err: %v", ancientDBPath, err)
+		}
+		log.Infof("Running as an archive node, the historical states are retained in %v", ancientDBPath)
+		nodeDB = archiveDB
+	}
+
+	var blockFreezer *freezer.Freezer
+	if viper.GetBool(common.CfgStorageFreezerEnabled) {
+		freezerPath := viper.GetString(common.CfgStorageFreezerPath)
+		if freezerPath == "" {
+			freezerPath = path.Join(dbPath, "db", "freezer")
+		}
+		blockFreezer, err = freezer.Open(freezerPath, blockchain.FreezerTables)
+		if err != nil {
+			log.Fatalf("Failed to open the freezer: %v, err: %v", freezerPath, err)
+		}
+		log.Infof("Moving the old finalized blocks into the freezer at %v", freezerPath)
+	}
+
 	// load snapshot
 	if len(snapshotPath) == 0 {
 		snapshotPath = path.Join(cfgPath, "snapshot")
 	}
 
+	// trap Ctrl+C and call cancel on the context
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// A fresh node can download the latest snapshot from the peers instead of syncing from the genesis
+	var snapshotMgr *netsync.SnapshotManager
+	if _, err := db.Get([]byte("/snapshot_blockheader")); err != nil && viper.GetBool(common.CfgSyncSnapshotSync) {
+		localHeader := snapshot.LoadSnapshotCheckpointHeader(snapshotPath)
+		if localHeader == nil {
+			log.Fatalf("Failed to load snapshot: %v", snapshotPath)
+		}
+		viper.Set(common.CfgGenesisChainID, localHeader.ChainID)
+
+		networkOld, network = createNetworks(ctx, nodeSigner, p2pKey)
+		snapshotMgr = netsync.NewSnapshotManager(networkOld, network, viper.GetString(common.CfgSyncSnapshotServeDir))
+		startNetworks(ctx, networkOld, network)
+
+		if header := syncSnapshot(ctx, snapshotMgr, snapshotPath, localHeader); header != nil {
+			raw, err := rlp.EncodeToBytes(header)
+			if err == nil {
+				err = db.Put([]byte("/snapshot_blockheader"), raw)
+			}
+			if err != nil {
+				log.Errorf("Failed to save snapshot validation result: %v", err)
+			}
+		}
+	}
+
 	var root *core.Block
 	var snapshotBlockHeader *core.BlockHeader
 	dbSnapshotHeader := &core.BlockHeader{}
@@ -106,41 +200,48 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Verify the genesis block on every start, since snapshot validation is skipped once it has been loaded
+	genesisBlockHeader, err := snapshot.LoadSnapshotGenesisHeader(snapshotPath)
+	if err != nil {
+		log.Fatalf("Failed to load genesis block from snapshot, err: %v", err)
+	}
+	if err = snapshot.VerifyGenesisBlockHash(genesisBlockHeader); err != nil {
+		if !viper.GetBool(common.CfgGenesisSkipHashCheck) {
+			log.Fatalf("Genesis block verification failed, err: %v. Use --skip_genesis_check to start anyway.", err)
+		}
+		log.Warnf("Genesis block verification failed, err: %v", err)
+	}
+	genesisSigPath := viper.GetString(common.CfgGenesisSignatureFile)
+	if len(genesisSigPath) == 0 {
+		genesisSigPath = path.Join(cfgPath, "genesis.sig")
+	}
+	if err = snapshot.VerifyGenesisSignatures(genesisBlockHeader, genesisSigPath); err != nil {
+		log.Fatalf("Genesis block signature verification failed, err: %v", err)
+	}
+
 	root = &core.Block{BlockHeader: snapshotBlockHeader}
 
 	viper.Set(common.CfgGenesisChainID, root.ChainID)
-
-	// Parse seeds and filter out empty item.
-	f := func(c rune) bool {
-		return c == ','
+	if len(forkOverrides) > 0 && root.ChainID == core.MainnetChainID {
+		log.Fatalf("The fork heights cannot be overridden on the mainnet, please remove %v from the config", common.CfgForkHeights)
 	}
 
-	// trap Ctrl+C and call cancel on the context
-	ctx, cancel := context.WithCancel(context.Background())
-
-	p2pOpt := common.P2POptEnum(viper.GetInt(common.CfgP2POpt))
-	if p2pOpt != common.P2POptOld {
-		port := viper.GetInt(common.CfgP2PLPort)
-		peerSeeds := strings.FieldsFunc(viper.GetString(common.CfgLibP2PSeeds), f)
-		seedPeerOnly := viper.GetBool(common.CfgP2PSeedPeerOnly)
-		network = newMessenger(privKey, peerSeeds, port, seedPeerOnly, ctx)
-	}
-	if p2pOpt != common.P2POptLibp2p {
-		portOld := viper.GetInt(common.CfgP2PPort)
-		peerSeedsOld := strings.FieldsFunc(viper.GetString(common.CfgP2PSeeds), f)
-		networkOld = newMessengerOld(privKey, peerSeedsOld, portOld, ctx)
+	if snapshotMgr == nil {
+		networkOld, network = createNetworks(ctx, nodeSigner, p2pKey)
 	}
 
 	params := &node.Params{
 		ChainID:             root.ChainID,
-		PrivateKey:          privKey,
+		Signer:              nodeSigner,
 		Root:                root,
 		NetworkOld:          networkOld,
 		Network:             network,
-		DB:                  db,
+		DB:                  nodeDB,
 		SnapshotPath:        snapshotPath,
 		ChainImportDirPath:  chainImportDirPath,
 		ChainCorrectionPath: chainCorrectionPath,
+		SnapshotManager:     snapshotMgr,
+		Freezer:             blockFreezer,
 	}
 
 	n := node.NewNode(params)
@@ -161,9 +262,7 @@ func runStart(cmd *cobra.Command, args []string) {
 	n.Start(ctx)
 
 	if viper.GetBool(common.CfgProfEnabled) {
-		go func() {
-			log.Println(http.ListenAndServe("localhost:6060", nil))
-		}()
+		startProfilingServer(ctx)
 	}
 
 	if viper.GetBool(common.CfgForceGCEnabled) {
@@ -181,6 +280,125 @@ func runStart(cmd *cobra.Command, args []string) {
 	printExitBanner()
 }
 
+// createNetworks creates the p2p networks selected by the config
+func createNetworks(ctx context.Context, nodeSigner crypto.Signer, p2pKey *crypto.PrivateKey) (*msg.Messenger, *msgl.Messenger) {
+	var networkOld *msg.Messenger
+	var network *msgl.Messenger
+
+	// Parse seeds and filter out empty item.
+	f := func(c rune) bool {
+		return c == ','
+	}
+
+	p2pOpt := common.P2POptEnum(viper.GetInt(common.CfgP2POpt))
+	if viper.GetBool(common.CfgP2PSentryMode) && p2pOpt != common.P2POptOld {
+		// The libp2p network discovers and accepts peers on its own, which would bypass the sentries
+		log.Fatalf("The sentry mode is only supported on the p2p network, please set %v to %v", common.CfgP2POpt, common.P2POptOld)
+	}
+	if p2pOpt != common.P2POptOld {
+		port := viper.GetInt(common.CfgP2PLPort)
+		peerSeeds := strings.FieldsFunc(viper.GetString(common.CfgLibP2PSeeds), f)
+		seedPeerOnly := viper.GetBool(common.CfgP2PSeedPeerOnly)
+		network = newMessenger(nodeSigner.PublicKey(), peerSeeds, port, seedPeerOnly, ctx)
+	}
+	if p2pOpt != common.P2POptLibp2p {
+		portOld := viper.GetInt(common.CfgP2PPort)
+		peerSeedsOld := strings.FieldsFunc(viper.GetString(common.CfgP2PSeeds), f)
+		networkOld = newMessengerOld(p2pKey, peerSeedsOld, portOld, ctx)
+	}
+	return networkOld, network
+}
+
+// startNetworks starts the networks ahead of the node, the node does not restart them
+func startNetworks(ctx context.Context, networkOld *msg.Messenger, network *msgl.Messenger) {
+	if networkOld != nil {
+		if err := networkOld.Start(ctx); err != nil {
+			log.Fatalf("Failed to start the p2p network: %v", err)
+		}
+	}
+	if network != nil {
+		if err := network.Start(ctx); err != nil {
+			log.Fatalf("Failed to start the libp2p network: %v", err)
+		}
+	}
+}
+
+// syncSnapshot downloads the latest snapshot served by the peers to the snapshot path, and returns
+// the header of the validated snapshot. The local snapshot is kept if no snapshot could be downloaded
+// in time, in which case the node syncs from the local snapshot instead.
+func syncSnapshot(ctx context.Context, snapshotMgr *netsync.SnapshotManager, snapshotPath string, localHeader *core.BlockHeader) *core.BlockHeader {
+	localGenesis, err := snapshot.LoadSnapshotGenesisHeader(snapshotPath)
+	if err != nil {
+		log.Fatalf("Failed to load genesis block from snapshot, err: %v", err)
+	}
+
+	// The downloaded snapshot is only accepted if its proofs are valid against the validator sets
+	// since the genesis, and it is a snapshot of the same chain
+	var validatedHeader *core.BlockHeader
+	verify := func(filePath string) error {
+		genesis, err := snapshot.LoadSnapshotGenesisHeader(filePath)
+		if err != nil {
+			return err
+		}
+		if genesis.Hash() != localGenesis.Hash() {
+			return fmt.Errorf("Genesis block mismatch, expected: %v, got: %v", localGenesis.Hash().Hex(), genesis.Hash().Hex())
+		}
+		header, err := snapshot.ValidateSnapshot(filePath, "", "")
+		if err != nil {
+			return err
+		}
+		if header.ChainID != localHeader.ChainID {
+			return fmt.Errorf("Chain ID mismatch, expected: %v, got: %v", localHeader.ChainID, header.ChainID)
+		}
+		validatedHeader = header
+		return nil
+	}
+
+	timeout := time.Duration(viper.GetInt(common.CfgSyncSnapshotTimeoutSecs)) * time.Second
+	c, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	manifest, err := snapshotMgr.Sync(c, localHeader.Height, viper.GetInt(common.CfgSyncSnapshotMinPeers), snapshotPath, verify)
+	if err != nil {
+		log.Warnf("Snapshot sync failed, syncing from the local snapshot at height %v instead, err: %v", localHeader.Height, err)
+		return nil
+	}
+	log.Infof("Snapshot sync completed, snapshot at height %v saved to %v", manifest.Height, snapshotPath)
+	return validatedHeader
+}
+
+// configureForks applies the fork heights overridden by the config, and returns the overrides
+func configureForks() map[string]uint64 {
+	overrides, err := features.ParseOverrides(viper.GetStringMapString(common.CfgForkHeights))
+	if err == nil {
+		err = features.Configure(overrides)
+	}
+	if err != nil {
+		log.Fatalf("Failed to configure the fork heights: %v", err)
+	}
+	for name, height := range overrides {
+		log.Warnf("The activation height of fork %v is overridden to %v", name, height)
+	}
+	return overrides
+}
+
+// connectRemoteSigner connects to the pandosigner holding the node key, over TLS if the signer is
+// shared with a standby node, or over the local socket. It returns nil if no signer is configured.
+func connectRemoteSigner() (*signer.RemoteSigner, error) {
+	if address := viper.GetString(common.CfgSignerAddress); address != "" {
+		tlsConfig, err := signer.LoadTLSConfig(viper.GetString(common.CfgSignerTLSCert),
+			viper.GetString(common.CfgSignerTLSKey), viper.GetString(common.CfgSignerTLSCA))
+		if err != nil {
+			return nil, err
+		}
+		return signer.NewRemoteSignerTLS(address, tlsConfig)
+	}
+	if socket := viper.GetString(common.CfgSignerSocket); socket != "" {
+		return signer.NewRemoteSigner(socket)
+	}
+	return nil, nil
+}
+
 func loadOrCreateKey() (*crypto.PrivateKey, error) {
 	keyPath := viper.GetString(common.CfgKeyPath)
 	if keyPath == "" {
@@ -276,19 +494,45 @@ func loadOrCreateKey() (*crypto.PrivateKey, error) {
 	return nodePrivKey, nil
 }
 
-func newMessenger(privKey *crypto.PrivateKey, seedPeerNetAddresses []string, port int, seedPeerOnly bool, ctx context.Context) *msgl.Messenger {
+func newMessenger(pubKey *crypto.PublicKey, seedPeerNetAddresses []string, port int, seedPeerOnly bool, ctx context.Context) *msgl.Messenger {
 	log.WithFields(log.Fields{
-		"pubKey":  fmt.Sprintf("%v", privKey.PublicKey().ToBytes()),
-		"address": fmt.Sprintf("%v", privKey.PublicKey().Address()),
+		"pubKey":  fmt.Sprintf("%v", pubKey.ToBytes()),
+		"address": fmt.Sprintf("%v", pubKey.Address()),
 	}).Info("Using key:")
 	msgrConfig := msgl.GetDefaultMessengerConfig()
-	messenger, err := msgl.CreateMessenger(privKey.PublicKey(), seedPeerNetAddresses, port, seedPeerOnly, msgrConfig, true, ctx)
+	messenger, err := msgl.CreateMessenger(pubKey, seedPeerNetAddresses, port, seedPeerOnly, msgrConfig, true, ctx)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Fatal("Failed to create Messenger instance.")
 	}
 	return messenger
 }
 
+// loadOrCreateP2PKey loads the key used by the old p2p network when the node key is held
+// by a separate signer. The key only identifies the node in the p2p network.
+func loadOrCreateP2PKey() (*crypto.PrivateKey, error) {
+	keyPath := viper.GetString(common.CfgKeyPath)
+	if keyPath == "" {
+		keyPath = cfgPath
+	}
+	p2pKeyPath := path.Join(keyPath, "key", "p2p_node_key")
+
+	if _, err := os.Stat(p2pKeyPath); err == nil {
+		return crypto.PrivateKeyFromFile(p2pKeyPath)
+	}
+
+	if err := os.MkdirAll(path.Dir(p2pKeyPath), 0700); err != nil {
+		return nil, err
+	}
+	privKey, _, err := crypto.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	if err := privKey.SaveToFile(p2pKeyPath); err != nil {
+		return nil, err
+	}
+	return privKey, nil
+}
+
 func newMessengerOld(privKey *crypto.PrivateKey, seedPeerNetAddresses []string, port int, ctx context.Context) *msg.Messenger {
 	log.WithFields(log.Fields{
 		"pubKey":  fmt.Sprintf("%v", privKey.PublicKey().ToBytes()),
@@ -331,6 +575,26 @@ func printExitBanner() {
 	fmt.Println("")
 }
 
+// startProfilingServer serves the profiling endpoints behind an auth token. The token
+// is generated and saved under the config folder if not configured.
+func startProfilingServer(ctx context.Context) {
+	tokenFilePath := path.Join(cfgPath, profiling.TokenFileName)
+	token, err := profiling.LoadOrCreateAuthToken(tokenFilePath)
+	if err != nil {
+		log.Fatalf("Failed to load profiling auth token: %v", err)
+	}
+
+	profiling.SetSampling(profiling.SamplingConfig{
+		BlockProfileRate:     viper.GetInt(common.CfgProfBlockProfileRate),
+		MutexProfileFraction: viper.GetInt(common.CfgProfMutexProfileFraction),
+	})
+
+	server := profiling.NewServer(viper.GetString(common.CfgProfAddress), token)
+	if err := server.Start(ctx); err != nil {
+		log.Errorf("Failed to start profiling server: %v", err)
+	}
+}
+
 // memoryCleanupRoutine peridically forces memory garbage collection.
 func memoryCleanupRoutine() {
 	var m runtime.MemStats
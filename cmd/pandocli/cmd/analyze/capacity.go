@@ -0,0 +1,195 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// maxBlocksPerQuery is the max number of blocks GetBlocksByRange returns per call.
+const maxBlocksPerQuery = 100
+
+var (
+	numBlocksFlag   uint64
+	endHeightFlag   uint64
+	txLimitsFlag    []string
+	gasLimitsFlag   []string
+	bytesLimitsFlag []string
+)
+
+// capacityCmd replays the recent finalized blocks and reports the block utilization.
+// Example:
+//		pandocli analyze capacity --blocks=5000
+//		pandocli analyze capacity --blocks=5000 --end=1200000 --tx_limits=4096,8192,16384 --gas_limits=20000000,40000000
+var capacityCmd = &cobra.Command{
+	Use:   "capacity",
+	Short: "Report block utilization and project the effect of candidate block limits",
+	Long: `Replay the recent finalized blocks and report the utilization percentiles by tx type, gas and bytes.
+Candidate per-block limits on the number of txs, gas and bytes can be specified to project the share
+of blocks that would be saturated, and the amount of usage that would be deferred to later blocks.`,
+	Example: `pandocli analyze capacity --blocks=5000 --tx_limits=4096,8192,16384`,
+	Run:     doCapacityCmd,
+}
+
+// blockWithRawTxs mirrors rpc.GetBlockResultInner, keeping the txs undecoded so that
+// they can be decoded according to their types.
+type blockWithRawTxs struct {
+	Height common.JSONUint64 `json:"height"`
+	Txs    []rawTx           `json:"transactions"`
+}
+
+type rawTx struct {
+	Raw     json.RawMessage `json:"raw"`
+	Type    byte            `json:"type"`
+	Receipt *struct {
+		GasUsed uint64
+	} `json:"receipt"`
+}
+
+func doCapacityCmd(cmd *cobra.Command, args []string) {
+	txLimits := parseLimits("tx_limits", txLimitsFlag)
+	gasLimits := parseLimits("gas_limits", gasLimitsFlag)
+	bytesLimits := parseLimits("bytes_limits", bytesLimitsFlag)
+	if numBlocksFlag == 0 {
+		utils.Error("Number of blocks must be positive\n")
+	}
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	end := endHeightFlag
+	if end == 0 {
+		res, err := client.Call("pando.GetStatus", rpc.GetStatusArgs{})
+		if err != nil {
+			utils.Error("Failed to get status: %v\n", err)
+		}
+		if res.Error != nil {
+			utils.Error("Failed to get status: %v\n", res.Error)
+		}
+		status := &rpc.GetStatusResult{}
+		if err := res.GetObject(status); err != nil {
+			utils.Error("Failed to parse server response: %v\n", err)
+		}
+		end = uint64(status.LatestFinalizedBlockHeight)
+	}
+	start := uint64(1)
+	if end >= numBlocksFlag {
+		start = end - numBlocksFlag + 1
+	}
+
+	samples := []*BlockSample{}
+	for from := start; from <= end; from += maxBlocksPerQuery {
+		to := from + maxBlocksPerQuery - 1
+		if to > end {
+			to = end
+		}
+		res, err := client.Call("pando.GetBlocksByRange", rpc.GetBlocksByRangeArgs{
+			Start: common.JSONUint64(from),
+			End:   common.JSONUint64(to),
+		})
+		if err != nil {
+			utils.Error("Failed to get blocks %v-%v: %v\n", from, to, err)
+		}
+		if res.Error != nil {
+			utils.Error("Failed to get blocks %v-%v: %v\n", from, to, res.Error)
+		}
+		blocks := []*blockWithRawTxs{}
+		if err := res.GetObject(&blocks); err != nil {
+			utils.Error("Failed to parse server response: %v\n", err)
+		}
+		for _, block := range blocks {
+			samples = append(samples, sampleBlock(block))
+		}
+	}
+
+	report := Plan(samples, txLimits, gasLimits, bytesLimits)
+	json, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		utils.Error("Failed to encode report: %v\n", err)
+	}
+	fmt.Println(string(json))
+}
+
+// sampleBlock collects the usage of the regular txs in the block. The coinbase and
+// slash txs are added by the proposer and do not count against the block limits.
+func sampleBlock(block *blockWithRawTxs) *BlockSample {
+	sample := &BlockSample{
+		Height: uint64(block.Height),
+		ByType: make(map[string]*TypeUsage),
+	}
+	for _, tx := range block.Txs {
+		if tx.Type == rpc.TxTypeCoinbase || tx.Type == rpc.TxTypeSlash {
+			continue
+		}
+
+		gas := uint64(0)
+		if tx.Receipt != nil { // only smart contract txs consume gas
+			gas = tx.Receipt.GasUsed
+		}
+		size := txSize(tx)
+
+		name := txTypeName(tx.Type)
+		usage, ok := sample.ByType[name]
+		if !ok {
+			usage = &TypeUsage{}
+			sample.ByType[name] = usage
+		}
+		usage.NumTxs++
+		usage.Gas += gas
+		usage.Bytes += size
+
+		sample.NumTxs++
+		sample.Gas += gas
+		sample.Bytes += size
+	}
+	return sample
+}
+
+// txSize returns the size of the RLP encoded tx, which is how the tx is stored in the block.
+func txSize(tx rawTx) uint64 {
+	decoded, err := types.NewTx(types.TxType(tx.Type))
+	if err != nil {
+		return uint64(len(tx.Raw))
+	}
+	if err := json.Unmarshal(tx.Raw, decoded); err != nil {
+		return uint64(len(tx.Raw))
+	}
+	raw, err := types.TxToBytes(decoded)
+	if err != nil {
+		return uint64(len(tx.Raw))
+	}
+	return uint64(len(raw))
+}
+
+func txTypeName(t byte) string {
+	return types.TxTypeName(types.TxType(t))
+}
+
+func parseLimits(name string, values []string) []uint64 {
+	limits := []uint64{}
+	for _, v := range values {
+		limit, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || limit == 0 {
+			utils.Error("Invalid %v: %v\n", name, v)
+		}
+		limits = append(limits, limit)
+	}
+	return limits
+}
+
+func init() {
+	capacityCmd.Flags().Uint64Var(&numBlocksFlag, "blocks", 1000, "Number of recent finalized blocks to replay")
+	capacityCmd.Flags().Uint64Var(&endHeightFlag, "end", 0, "Height of the last block to replay (default to the latest finalized block)")
+	capacityCmd.Flags().StringSliceVar(&txLimitsFlag, "tx_limits", []string{strconv.Itoa(core.MaxNumRegularTxsPerBlock)}, "Candidate limits on the number of txs per block")
+	capacityCmd.Flags().StringSliceVar(&gasLimitsFlag, "gas_limits", []string{}, "Candidate limits on the gas per block")
+	capacityCmd.Flags().StringSliceVar(&bytesLimitsFlag, "bytes_limits", []string{}, "Candidate limits on the bytes per block")
+}
@@ -0,0 +1,16 @@
+package analyze
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AnalyzeCmd represents the analyze command
+var AnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze historical chain data",
+}
+
+func init() {
+	AnalyzeCmd.AddCommand(capacityCmd)
+	AnalyzeCmd.AddCommand(incomeCmd)
+}
@@ -0,0 +1,215 @@
+package analyze
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// reportedPercentiles are the percentiles included in the capacity report.
+var reportedPercentiles = []float64{50, 90, 95, 99, 100}
+
+// BlockSample records the resource usage of a single block.
+type BlockSample struct {
+	Height uint64
+	NumTxs uint64
+	Gas    uint64
+	Bytes  uint64
+	ByType map[string]*TypeUsage
+}
+
+// TypeUsage records the resource usage of the transactions of one type within a block.
+type TypeUsage struct {
+	NumTxs uint64 `json:"num_txs"`
+	Gas    uint64 `json:"gas"`
+	Bytes  uint64 `json:"bytes"`
+}
+
+// Percentiles maps a percentile (e.g. "p95") to the observed value.
+type Percentiles map[string]uint64
+
+// UsageStats summarizes a resource over the sampled blocks.
+type UsageStats struct {
+	Total       uint64      `json:"total"`
+	Mean        float64     `json:"mean"`
+	Percentiles Percentiles `json:"percentiles"`
+}
+
+// TypeStats summarizes the resource usage of one transaction type per block.
+type TypeStats struct {
+	Txs   UsageStats `json:"txs"`
+	Gas   UsageStats `json:"gas"`
+	Bytes UsageStats `json:"bytes"`
+}
+
+// LimitProjection projects the effect of a candidate per-block limit on the sampled blocks.
+type LimitProjection struct {
+	Limit uint64 `json:"limit"`
+	// SaturatedBlocks is the number of sampled blocks that reach or exceed the limit.
+	SaturatedBlocks uint64 `json:"saturated_blocks"`
+	// SaturatedRatio is the fraction of sampled blocks that reach or exceed the limit.
+	SaturatedRatio float64 `json:"saturated_ratio"`
+	// Deferred is the amount that would not fit into the blocks under the limit, and
+	// needs to be carried over to later blocks.
+	Deferred uint64 `json:"deferred"`
+	// Utilization maps a percentile to the block utilization (usage / limit) under the limit.
+	Utilization map[string]float64 `json:"utilization"`
+}
+
+// CapacityReport is the output of the capacity planner.
+type CapacityReport struct {
+	StartHeight uint64                `json:"start_height"`
+	EndHeight   uint64                `json:"end_height"`
+	NumBlocks   uint64                `json:"num_blocks"`
+	Txs         UsageStats            `json:"txs"`
+	Gas         UsageStats            `json:"gas"`
+	Bytes       UsageStats            `json:"bytes"`
+	ByType      map[string]*TypeStats `json:"by_type"`
+
+	TxLimits    []*LimitProjection `json:"tx_limit_projections,omitempty"`
+	GasLimits   []*LimitProjection `json:"gas_limit_projections,omitempty"`
+	BytesLimits []*LimitProjection `json:"bytes_limit_projections,omitempty"`
+}
+
+// Plan computes the utilization statistics of the sampled blocks and projects the
+// effect of the candidate tx count, gas and byte limits.
+func Plan(samples []*BlockSample, txLimits, gasLimits, bytesLimits []uint64) *CapacityReport {
+	report := &CapacityReport{
+		NumBlocks: uint64(len(samples)),
+		ByType:    make(map[string]*TypeStats),
+	}
+	if len(samples) == 0 {
+		return report
+	}
+
+	report.StartHeight = samples[0].Height
+	report.EndHeight = samples[0].Height
+	txs := make([]uint64, len(samples))
+	gas := make([]uint64, len(samples))
+	bytes := make([]uint64, len(samples))
+	typeNames := make(map[string]bool)
+	for i, s := range samples {
+		if s.Height < report.StartHeight {
+			report.StartHeight = s.Height
+		}
+		if s.Height > report.EndHeight {
+			report.EndHeight = s.Height
+		}
+		txs[i] = s.NumTxs
+		gas[i] = s.Gas
+		bytes[i] = s.Bytes
+		for name := range s.ByType {
+			typeNames[name] = true
+		}
+	}
+	report.Txs = summarize(txs)
+	report.Gas = summarize(gas)
+	report.Bytes = summarize(bytes)
+
+	for name := range typeNames {
+		typeTxs := make([]uint64, len(samples))
+		typeGas := make([]uint64, len(samples))
+		typeBytes := make([]uint64, len(samples))
+		for i, s := range samples {
+			if usage, ok := s.ByType[name]; ok {
+				typeTxs[i] = usage.NumTxs
+				typeGas[i] = usage.Gas
+				typeBytes[i] = usage.Bytes
+			}
+		}
+		report.ByType[name] = &TypeStats{
+			Txs:   summarize(typeTxs),
+			Gas:   summarize(typeGas),
+			Bytes: summarize(typeBytes),
+		}
+	}
+
+	for _, limit := range txLimits {
+		report.TxLimits = append(report.TxLimits, project(txs, limit))
+	}
+	for _, limit := range gasLimits {
+		report.GasLimits = append(report.GasLimits, project(gas, limit))
+	}
+	for _, limit := range bytesLimits {
+		report.BytesLimits = append(report.BytesLimits, project(bytes, limit))
+	}
+
+	return report
+}
+
+func summarize(values []uint64) UsageStats {
+	stats := UsageStats{Percentiles: make(Percentiles)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := make([]uint64, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for _, v := range sorted {
+		stats.Total += v
+	}
+	stats.Mean = float64(stats.Total) / float64(len(sorted))
+	for _, p := range reportedPercentiles {
+		stats.Percentiles[percentileName(p)] = percentile(sorted, p)
+	}
+	return stats
+}
+
+// project replays the usage against the limit. Usage above the limit is carried over
+// to the following blocks, which approximates how the mempool would have been drained.
+func project(values []uint64, limit uint64) *LimitProjection {
+	projection := &LimitProjection{
+		Limit:       limit,
+		Utilization: make(map[string]float64),
+	}
+	if len(values) == 0 || limit == 0 {
+		return projection
+	}
+
+	included := make([]uint64, len(values))
+	backlog := uint64(0)
+	for i, v := range values {
+		demand := v + backlog
+		if demand >= limit {
+			projection.SaturatedBlocks++
+			included[i] = limit
+			backlog = demand - limit
+		} else {
+			included[i] = demand
+			backlog = 0
+		}
+	}
+	projection.Deferred = backlog
+	projection.SaturatedRatio = float64(projection.SaturatedBlocks) / float64(len(values))
+
+	sort.Slice(included, func(i, j int) bool { return included[i] < included[j] })
+	for _, p := range reportedPercentiles {
+		utilization := float64(percentile(included, p)) / float64(limit)
+		projection.Utilization[percentileName(p)] = math.Round(utilization*10000) / 10000
+	}
+	return projection
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []uint64, p float64) uint64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func percentileName(p float64) string {
+	if p == 100 {
+		return "max"
+	}
+	return "p" + strconv.FormatUint(uint64(p), 10)
+}
@@ -82,6 +82,7 @@ func doSmartContractCmd(cmd *cobra.Command, args []string) {
 
 	rpcCallArgs := rpc.CallSmartContractArgs{
 		SctxBytes: hex.EncodeToString(sctxBytes),
+		Block:     rpc.BlockSpecifier(blockFlag),
 	}
 
 	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
@@ -110,6 +111,7 @@ func init() {
 	smartContractCmd.Flags().StringVar(&dataFlag, "data", "", "The data for the smart contract")
 	smartContractCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
 	smartContractCmd.Flags().BoolVar(&verboseFlag, "verbose", false, "")
+	smartContractCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending to execute against")
 
 	smartContractCmd.MarkFlagRequired("from")
 	smartContractCmd.MarkFlagRequired("gas_price")
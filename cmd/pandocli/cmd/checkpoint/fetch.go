@@ -0,0 +1,134 @@
+package checkpoint
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// fetchCmd fetches a recent checkpoint from several independent RPC endpoints and cross-checks it.
+// Example:
+//		pandocli checkpoint fetch --sources=https://rpc-a.example.com/rpc,https://rpc-b.example.com/rpc,http://localhost:16888/rpc
+var fetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Fetch a recent checkpoint from independent sources",
+	Long: `Fetch the finalized block at a recent checkpoint height from several independent RPC endpoints,
+and cross-check them. By default the height is the latest checkpoint height finalized by all the
+sources. The checkpoint is printed only if all the reachable sources agree on the block, and at least
+--min_agree of them answered.`,
+	Example: `pandocli checkpoint fetch --sources=https://rpc-a.example.com/rpc,https://rpc-b.example.com/rpc,http://localhost:16888/rpc`,
+	Run:     doFetchCmd,
+}
+
+type checkpointSource struct {
+	endpoint string
+	client   *rpcc.RPCClient
+	status   *rpc.GetStatusResult
+}
+
+func doFetchCmd(cmd *cobra.Command, args []string) {
+	if len(sourcesFlag) < minAgreeFlag {
+		utils.Error("At least %v sources are required, %v given\n", minAgreeFlag, len(sourcesFlag))
+	}
+
+	sources := []*checkpointSource{}
+	chainID := ""
+	for _, endpoint := range sourcesFlag {
+		source := &checkpointSource{
+			endpoint: endpoint,
+			client:   rpcc.NewRPCClient(endpoint),
+		}
+		status, err := getStatus(source.client)
+		if err != nil {
+			fmt.Printf("Skipping %v: %v\n", endpoint, err)
+			continue
+		}
+		if chainID == "" {
+			chainID = status.ChainID
+		} else if chainID != status.ChainID {
+			utils.Error("Source %v is on chain %v, expected %v\n", endpoint, status.ChainID, chainID)
+		}
+		source.status = status
+		sources = append(sources, source)
+	}
+	if len(sources) < minAgreeFlag {
+		utils.Error("Only %v sources are reachable, %v required\n", len(sources), minAgreeFlag)
+	}
+
+	height := heightFlag
+	if height == 0 {
+		minFinalized := uint64(sources[0].status.LatestFinalizedBlockHeight)
+		for _, source := range sources[1:] {
+			if h := uint64(source.status.LatestFinalizedBlockHeight); h < minFinalized {
+				minFinalized = h
+			}
+		}
+		height = common.LastCheckPointHeight(minFinalized)
+	}
+	if height == 0 {
+		utils.Error("No finalized checkpoint height\n")
+	}
+
+	var checkpoint *core.WeakSubjectivityCheckpoint
+	for _, source := range sources {
+		block, err := getFinalizedBlock(source.client, height)
+		if err != nil {
+			utils.Error("Failed to get the block at height %v from %v: %v\n", height, source.endpoint, err)
+		}
+		fmt.Printf("%v: %v\n", source.endpoint, block.Hash.Hex())
+		if checkpoint == nil {
+			checkpoint = &core.WeakSubjectivityCheckpoint{Height: height, Hash: block.Hash}
+		} else if checkpoint.Hash != block.Hash {
+			utils.Error("The sources disagree on the block at height %v, do not use any of them as a checkpoint\n", height)
+		}
+	}
+
+	fmt.Printf("\n%v sources agree on the checkpoint of chain %v:\n%v\n", len(sources), chainID, checkpoint)
+	fmt.Printf("\nAdd it to the config of the node:\n%v:\n  - \"%v\"\n", common.CfgSyncWeakSubjectivityCheckpoints, checkpoint)
+}
+
+func getStatus(client *rpcc.RPCClient) (*rpc.GetStatusResult, error) {
+	res, err := client.Call("pando.GetStatus", rpc.GetStatusArgs{})
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	status := &rpc.GetStatusResult{}
+	if err := res.GetObject(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func getFinalizedBlock(client *rpcc.RPCClient, height uint64) (*rpc.GetBlockResultInner, error) {
+	res, err := client.Call("pando.GetBlockByHeight", rpc.GetBlockByHeightArgs{Height: common.JSONUint64(height)})
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	block := &rpc.GetBlockResultInner{}
+	if err := res.GetObject(block); err != nil {
+		return nil, err
+	}
+	if uint64(block.Height) != height || !block.Status.IsFinalized() {
+		return nil, fmt.Errorf("no finalized block")
+	}
+	return block, nil
+}
+
+func init() {
+	fetchCmd.Flags().StringSliceVar(&sourcesFlag, "sources", []string{}, "Comma separated RPC endpoints of the independent sources")
+	fetchCmd.Flags().IntVar(&minAgreeFlag, "min_agree", 2, "Minimum number of reachable sources which need to agree on the checkpoint")
+	fetchCmd.Flags().Uint64Var(&heightFlag, "height", 0, "Height of the checkpoint (default to the latest checkpoint height finalized by all the sources)")
+	fetchCmd.MarkFlagRequired("sources")
+}
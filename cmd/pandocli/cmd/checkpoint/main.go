@@ -0,0 +1,25 @@
+package checkpoint
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	sourcesFlag  []string
+	minAgreeFlag int
+	heightFlag   uint64
+)
+
+// CheckpointCmd represents the checkpoint command
+var CheckpointCmd = &cobra.Command{
+	Use:   "checkpoint",
+	Short: "Manage the weak subjectivity checkpoints",
+	Long: `Manage the weak subjectivity checkpoints. A node rejects the branches which conflict with its
+checkpoints (sync.weakSubjectivityCheckpoints), and refuses to start once its last finalized block is
+older than the trust period (sync.weakSubjectivityPeriodSecs) unless a more recent checkpoint is
+configured. The checkpoints should be obtained from several independent sources.`,
+}
+
+func init() {
+	CheckpointCmd.AddCommand(fetchCmd)
+}
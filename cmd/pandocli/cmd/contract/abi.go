@@ -0,0 +1,604 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/common/math"
+	"github.com/pandotoken/pando/crypto"
+)
+
+const abiWordSize = 32
+
+// revertSelector is the selector of Error(string), which encodes the revert reasons
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+type abiKind int
+
+const (
+	abiUint abiKind = iota
+	abiInt
+	abiAddress
+	abiBool
+	abiFixedBytes
+	abiBytes
+	abiString
+	abiSlice // T[]
+	abiArray // T[k]
+	abiTuple
+)
+
+// abiType is a type of the Solidity contract ABI
+type abiType struct {
+	kind       abiKind
+	size       int        // bits of the integers, length of the fixed bytes and the arrays
+	elem       *abiType   // element type of the slices and arrays
+	components []*abiType // component types of the tuples
+}
+
+// abiArgument is an input or output of a function in the ABI JSON
+type abiArgument struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Components []abiArgument `json:"components"`
+}
+
+// abiEntry is an entry of the ABI JSON
+type abiEntry struct {
+	Type            string        `json:"type"`
+	Name            string        `json:"name"`
+	Inputs          []abiArgument `json:"inputs"`
+	Outputs         []abiArgument `json:"outputs"`
+	StateMutability string        `json:"stateMutability"`
+	Constant        bool          `json:"constant"`
+	Payable         bool          `json:"payable"`
+}
+
+// abiMethod is a function or the constructor of a contract
+type abiMethod struct {
+	Name     string
+	Inputs   []*abiType
+	Outputs  []*abiType
+	Payable  bool
+	ReadOnly bool
+}
+
+// contractABI is the ABI of a contract
+type contractABI struct {
+	Constructor *abiMethod
+	Methods     []*abiMethod
+}
+
+// parseABI parses the ABI JSON. Events, errors and the fallback functions are skipped.
+func parseABI(raw []byte) (*contractABI, error) {
+	entries := []abiEntry{}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("Failed to parse ABI: %v", err)
+	}
+
+	cabi := &contractABI{}
+	for _, entry := range entries {
+		if entry.Type != "" && entry.Type != "function" && entry.Type != "constructor" {
+			continue
+		}
+		method := &abiMethod{
+			Name:     entry.Name,
+			Payable:  entry.Payable || entry.StateMutability == "payable",
+			ReadOnly: entry.Constant || entry.StateMutability == "view" || entry.StateMutability == "pure",
+		}
+		var err error
+		if method.Inputs, err = parseABIArguments(entry.Inputs); err != nil {
+			return nil, fmt.Errorf("Failed to parse the inputs of %v: %v", entry.Name, err)
+		}
+		if method.Outputs, err = parseABIArguments(entry.Outputs); err != nil {
+			return nil, fmt.Errorf("Failed to parse the outputs of %v: %v", entry.Name, err)
+		}
+		if entry.Type == "constructor" {
+			cabi.Constructor = method
+		} else {
+			cabi.Methods = append(cabi.Methods, method)
+		}
+	}
+	return cabi, nil
+}
+
+func parseABIArguments(args []abiArgument) ([]*abiType, error) {
+	types := make([]*abiType, len(args))
+	for i, arg := range args {
+		t, err := parseABIType(arg.Type, arg.Components)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+// parseABIType parses a type of the ABI JSON, the components are the fields of the tuples
+func parseABIType(typ string, components []abiArgument) (*abiType, error) {
+	if strings.HasSuffix(typ, "]") {
+		idx := strings.LastIndex(typ, "[")
+		if idx < 0 {
+			return nil, fmt.Errorf("Invalid type: %v", typ)
+		}
+		elem, err := parseABIType(typ[:idx], components)
+		if err != nil {
+			return nil, err
+		}
+		dim := typ[idx+1 : len(typ)-1]
+		if dim == "" {
+			return &abiType{kind: abiSlice, elem: elem}, nil
+		}
+		size, err := strconv.Atoi(dim)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("Invalid array length: %v", typ)
+		}
+		return &abiType{kind: abiArray, size: size, elem: elem}, nil
+	}
+
+	switch {
+	case typ == "tuple":
+		fields, err := parseABIArguments(components)
+		if err != nil {
+			return nil, err
+		}
+		return &abiType{kind: abiTuple, components: fields}, nil
+	case typ == "address":
+		return &abiType{kind: abiAddress}, nil
+	case typ == "bool":
+		return &abiType{kind: abiBool}, nil
+	case typ == "string":
+		return &abiType{kind: abiString}, nil
+	case typ == "bytes":
+		return &abiType{kind: abiBytes}, nil
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(typ[len("bytes"):])
+		if err != nil || size < 1 || size > abiWordSize {
+			return nil, fmt.Errorf("Invalid type: %v", typ)
+		}
+		return &abiType{kind: abiFixedBytes, size: size}, nil
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		kind, bits := abiUint, strings.TrimPrefix(typ, "uint")
+		if strings.HasPrefix(typ, "int") {
+			kind, bits = abiInt, strings.TrimPrefix(typ, "int")
+		}
+		size := 256
+		if bits != "" {
+			var err error
+			size, err = strconv.Atoi(bits)
+			if err != nil || size < 8 || size > 256 || size%8 != 0 {
+				return nil, fmt.Errorf("Invalid type: %v", typ)
+			}
+		}
+		return &abiType{kind: kind, size: size}, nil
+	}
+	return nil, fmt.Errorf("Unsupported type: %v", typ)
+}
+
+// String returns the canonical name of the type used in the function signatures
+func (t *abiType) String() string {
+	switch t.kind {
+	case abiUint:
+		return fmt.Sprintf("uint%d", t.size)
+	case abiInt:
+		return fmt.Sprintf("int%d", t.size)
+	case abiAddress:
+		return "address"
+	case abiBool:
+		return "bool"
+	case abiFixedBytes:
+		return fmt.Sprintf("bytes%d", t.size)
+	case abiBytes:
+		return "bytes"
+	case abiString:
+		return "string"
+	case abiSlice:
+		return t.elem.String() + "[]"
+	case abiArray:
+		return fmt.Sprintf("%v[%d]", t.elem, t.size)
+	default:
+		return "(" + joinABITypes(t.components) + ")"
+	}
+}
+
+func joinABITypes(types []*abiType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// isDynamic indicates whether the type is encoded in the tail
+func (t *abiType) isDynamic() bool {
+	switch t.kind {
+	case abiBytes, abiString, abiSlice:
+		return true
+	case abiArray:
+		return t.elem.isDynamic()
+	case abiTuple:
+		for _, component := range t.components {
+			if component.isDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize returns the size of the type in the head of the enclosing tuple
+func (t *abiType) headSize() int {
+	if t.isDynamic() {
+		return abiWordSize
+	}
+	switch t.kind {
+	case abiArray:
+		return t.size * t.elem.headSize()
+	case abiTuple:
+		size := 0
+		for _, component := range t.components {
+			size += component.headSize()
+		}
+		return size
+	}
+	return abiWordSize
+}
+
+// Signature returns the signature of the method, e.g. transfer(address,uint256)
+func (m *abiMethod) Signature() string {
+	return m.Name + "(" + joinABITypes(m.Inputs) + ")"
+}
+
+// Selector returns the first 4 bytes of the hash of the signature, which identifies the method in the call data
+func (m *abiMethod) Selector() []byte {
+	return crypto.Keccak256([]byte(m.Signature()))[:4]
+}
+
+// Pack encodes the arguments of the method. The selector is prepended unless it is the constructor.
+func (m *abiMethod) Pack(args []interface{}, constructor bool) ([]byte, error) {
+	encoded, err := encodeABITuple(m.Inputs, args)
+	if err != nil {
+		return nil, err
+	}
+	if constructor {
+		return encoded, nil
+	}
+	return append(m.Selector(), encoded...), nil
+}
+
+// Unpack decodes the return data of the method
+func (m *abiMethod) Unpack(data []byte) ([]interface{}, error) {
+	return decodeABITuple(m.Outputs, data)
+}
+
+// Method looks up the method by its name, or by its signature if it is overloaded. The number of
+// arguments is used to tell the overloaded methods apart when only the name is given.
+func (cabi *contractABI) Method(name string, numArgs int) (*abiMethod, error) {
+	candidates := []*abiMethod{}
+	for _, method := range cabi.Methods {
+		if method.Signature() == name {
+			return method, nil
+		}
+		if method.Name == name {
+			candidates = append(candidates, method)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	matches := []*abiMethod{}
+	signatures := []string{}
+	for _, method := range candidates {
+		if len(method.Inputs) == numArgs {
+			matches = append(matches, method)
+		}
+		signatures = append(signatures, method.Signature())
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("Method %v not found in the ABI", name)
+	}
+	return nil, fmt.Errorf("Method %v is overloaded, please specify one of: %v", name, strings.Join(signatures, ", "))
+}
+
+func encodeABITuple(types []*abiType, values []interface{}) ([]byte, error) {
+	if len(types) != len(values) {
+		return nil, fmt.Errorf("Expected %v arguments, got %v", len(types), len(values))
+	}
+
+	headSize := 0
+	for _, t := range types {
+		headSize += t.headSize()
+	}
+	var head, tail bytes.Buffer
+	for i, t := range types {
+		encoded, err := encodeABIValue(t, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %v argument %v: %v", t, values[i], err)
+		}
+		if t.isDynamic() {
+			head.Write(encodeABIUint(headSize + tail.Len()))
+			tail.Write(encoded)
+		} else {
+			head.Write(encoded)
+		}
+	}
+	return append(head.Bytes(), tail.Bytes()...), nil
+}
+
+func encodeABIValue(t *abiType, value interface{}) ([]byte, error) {
+	switch t.kind {
+	case abiUint, abiInt:
+		n, err := toABIBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		min, max := new(big.Int), new(big.Int).Lsh(big.NewInt(1), uint(t.size))
+		if t.kind == abiInt {
+			max.Rsh(max, 1)
+			min.Neg(max)
+		}
+		if n.Cmp(min) < 0 || n.Cmp(max) >= 0 {
+			return nil, fmt.Errorf("Out of range")
+		}
+		return math.PaddedBigBytes(math.U256(new(big.Int).Set(n)), abiWordSize), nil
+	case abiAddress:
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("Invalid address")
+		}
+		return common.LeftPadBytes(common.HexToAddress(s).Bytes(), abiWordSize), nil
+	case abiBool:
+		b, err := toABIBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return encodeABIUint(1), nil
+		}
+		return encodeABIUint(0), nil
+	case abiFixedBytes:
+		data, err := toABIBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > t.size {
+			return nil, fmt.Errorf("Expected at most %v bytes, got %v", t.size, len(data))
+		}
+		return common.RightPadBytes(data, abiWordSize), nil
+	case abiBytes, abiString:
+		var data []byte
+		if t.kind == abiString {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("Expected a string")
+			}
+			data = []byte(s)
+		} else {
+			var err error
+			if data, err = toABIBytes(value); err != nil {
+				return nil, err
+			}
+		}
+		paddedSize := (len(data) + abiWordSize - 1) / abiWordSize * abiWordSize
+		return append(encodeABIUint(len(data)), common.RightPadBytes(data, paddedSize)...), nil
+	case abiSlice, abiArray:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Expected an array")
+		}
+		if t.kind == abiArray && len(list) != t.size {
+			return nil, fmt.Errorf("Expected %v elements, got %v", t.size, len(list))
+		}
+		types := make([]*abiType, len(list))
+		for i := range types {
+			types[i] = t.elem
+		}
+		encoded, err := encodeABITuple(types, list)
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == abiSlice {
+			encoded = append(encodeABIUint(len(list)), encoded...)
+		}
+		return encoded, nil
+	default:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Expected an array of the tuple fields")
+		}
+		return encodeABITuple(t.components, list)
+	}
+}
+
+func encodeABIUint(n int) []byte {
+	return math.PaddedBigBytes(big.NewInt(int64(n)), abiWordSize)
+}
+
+func toABIBigInt(value interface{}) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	default:
+		return nil, fmt.Errorf("Expected an integer")
+	}
+	negative := strings.HasPrefix(s, "-")
+	n, ok := math.ParseBig256(strings.TrimPrefix(s, "-"))
+	if !ok {
+		return nil, fmt.Errorf("Expected an integer")
+	}
+	if negative {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+func toABIBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+	return false, fmt.Errorf("Expected a boolean")
+}
+
+func toABIBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Expected a hex string")
+	}
+	if !hexutil.Has0xPrefix(s) {
+		s = "0x" + s
+	}
+	return hexutil.Decode(s)
+}
+
+func decodeABITuple(types []*abiType, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	pos := 0
+	for i, t := range types {
+		var value interface{}
+		var err error
+		if t.isDynamic() {
+			var offset int
+			if offset, err = readABIUint(data, pos); err != nil {
+				return nil, err
+			}
+			value, err = decodeABIValue(t, data[offset:])
+		} else {
+			if pos+t.headSize() > len(data) {
+				return nil, fmt.Errorf("Unexpected end of data")
+			}
+			value, err = decodeABIValue(t, data[pos:])
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		pos += t.headSize()
+	}
+	return values, nil
+}
+
+// decodeABIValue decodes the value at the beginning of data. The integers are decoded as *big.Int, the
+// addresses as common.Address, and the bytes as hex strings, so that the values are readable as JSON.
+func decodeABIValue(t *abiType, data []byte) (interface{}, error) {
+	switch t.kind {
+	case abiUint, abiInt, abiAddress, abiBool, abiFixedBytes:
+		if len(data) < abiWordSize {
+			return nil, fmt.Errorf("Unexpected end of data")
+		}
+		word := data[:abiWordSize]
+		switch t.kind {
+		case abiUint:
+			return new(big.Int).SetBytes(word), nil
+		case abiInt:
+			return math.S256(new(big.Int).SetBytes(word)), nil
+		case abiAddress:
+			return common.BytesToAddress(word), nil
+		case abiBool:
+			return word[abiWordSize-1] == 1, nil
+		default:
+			return hexutil.Encode(word[:t.size]), nil
+		}
+	case abiBytes, abiString:
+		length, err := readABIUint(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if abiWordSize+length > len(data) {
+			return nil, fmt.Errorf("Unexpected end of data")
+		}
+		content := data[abiWordSize : abiWordSize+length]
+		if t.kind == abiString {
+			return string(content), nil
+		}
+		return hexutil.Encode(content), nil
+	case abiSlice, abiArray:
+		length := t.size
+		if t.kind == abiSlice {
+			var err error
+			if length, err = readABIUint(data, 0); err != nil {
+				return nil, err
+			}
+			data = data[abiWordSize:]
+		}
+		// Each element takes at least a word, which bounds the length by the data
+		if length > len(data)/abiWordSize {
+			return nil, fmt.Errorf("Unexpected end of data")
+		}
+		types := make([]*abiType, length)
+		for i := range types {
+			types[i] = t.elem
+		}
+		return decodeABITuple(types, data)
+	default:
+		return decodeABITuple(t.components, data)
+	}
+}
+
+// readABIUint reads the length or offset at the given position, which needs to be within the data
+func readABIUint(data []byte, pos int) (int, error) {
+	if pos+abiWordSize > len(data) {
+		return 0, fmt.Errorf("Unexpected end of data")
+	}
+	n := new(big.Int).SetBytes(data[pos : pos+abiWordSize])
+	if !n.IsInt64() || n.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("Invalid length or offset: %v", n)
+	}
+	return int(n.Int64()), nil
+}
+
+// decodeRevertReason decodes the reason of the revert from the return data, if any
+func decodeRevertReason(ret []byte) (string, bool) {
+	if len(ret) < len(revertSelector) || !bytes.Equal(ret[:len(revertSelector)], revertSelector) {
+		return "", false
+	}
+	values, err := decodeABITuple([]*abiType{{kind: abiString}}, ret[len(revertSelector):])
+	if err != nil {
+		return "", false
+	}
+	return values[0].(string), true
+}
+
+// parseABIArgs parses the arguments given on the command line. The arguments are either a JSON
+// array, e.g. ["0x2E83...", 100, [1, 2]], or a comma separated list of the elementary values.
+func parseABIArgs(s string) ([]interface{}, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return []interface{}{}, nil
+	}
+	if strings.HasPrefix(s, "[") {
+		decoder := json.NewDecoder(strings.NewReader(s))
+		decoder.UseNumber()
+		args := []interface{}{}
+		if err := decoder.Decode(&args); err != nil {
+			return nil, fmt.Errorf("Failed to parse the arguments: %v", err)
+		}
+		return args, nil
+	}
+	args := []interface{}{}
+	for _, arg := range strings.Split(s, ",") {
+		args = append(args, strings.TrimSpace(arg))
+	}
+	return args, nil
+}
@@ -0,0 +1,235 @@
+package contract
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type": "constructor", "inputs": [{"name": "name", "type": "string"}, {"name": "supply", "type": "uint256"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "balanceOf", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+	{"type": "function", "name": "f", "inputs": [{"type": "uint256"}, {"type": "uint32[]"}, {"type": "bytes10"}, {"type": "bytes"}], "outputs": [], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "info", "inputs": [], "outputs": [{"type": "tuple", "components": [{"name": "owner", "type": "string"}, {"name": "delta", "type": "int8"}]}, {"type": "bool[2]"}], "stateMutability": "view"},
+	{"type": "function", "name": "mint", "inputs": [{"type": "uint256"}], "outputs": [], "stateMutability": "payable"},
+	{"type": "function", "name": "mint", "inputs": [{"type": "address"}, {"type": "uint256"}], "outputs": [], "stateMutability": "payable"},
+	{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}]}
+]`
+
+func unhex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	require.Nil(t, err)
+	return b
+}
+
+func TestABIPack(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cabi, err := parseABI([]byte(testABI))
+	require.Nil(err)
+	assert.Equal(6, len(cabi.Methods))
+
+	transfer, err := cabi.Method("transfer", 2)
+	require.Nil(err)
+	assert.Equal("transfer(address,uint256)", transfer.Signature())
+	assert.Equal("a9059cbb", hex.EncodeToString(transfer.Selector()))
+	assert.False(transfer.ReadOnly)
+
+	args, err := parseABIArgs("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab, 1000")
+	require.Nil(err)
+	data, err := transfer.Pack(args, false)
+	require.Nil(err)
+	assert.Equal(unhex(t, `a9059cbb
+		0000000000000000000000002e833968e5bb786ae419c4d13189fb081cc43bab
+		00000000000000000000000000000000000000000000000000000000000003e8`), data)
+
+	// The example of the Solidity ABI specification
+	f, err := cabi.Method("f", 4)
+	require.Nil(err)
+	args, err = parseABIArgs(`["0x123", [1110, 1929], "0x31323334353637383930", "0x48656c6c6f2c20776f726c6421"]`)
+	require.Nil(err)
+	data, err = f.Pack(args, false)
+	require.Nil(err)
+	assert.Equal(unhex(t, `8be65246
+		0000000000000000000000000000000000000000000000000000000000000123
+		0000000000000000000000000000000000000000000000000000000000000080
+		3132333435363738393000000000000000000000000000000000000000000000
+		00000000000000000000000000000000000000000000000000000000000000e0
+		0000000000000000000000000000000000000000000000000000000000000002
+		0000000000000000000000000000000000000000000000000000000000000456
+		0000000000000000000000000000000000000000000000000000000000000789
+		000000000000000000000000000000000000000000000000000000000000000d
+		48656c6c6f2c20776f726c642100000000000000000000000000000000000000`), data)
+
+	// The constructor arguments are not prefixed with a selector
+	args, err = parseABIArgs(`["Token", 5]`)
+	require.Nil(err)
+	data, err = cabi.Constructor.Pack(args, true)
+	require.Nil(err)
+	assert.Equal(unhex(t, `
+		0000000000000000000000000000000000000000000000000000000000000040
+		0000000000000000000000000000000000000000000000000000000000000005
+		0000000000000000000000000000000000000000000000000000000000000005
+		546f6b656e000000000000000000000000000000000000000000000000000000`), data)
+
+	// Invalid arguments
+	_, err = transfer.Pack([]interface{}{"0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"}, false)
+	assert.NotNil(err)
+	_, err = transfer.Pack([]interface{}{"not an address", "1"}, false)
+	assert.NotNil(err)
+	_, err = transfer.Pack([]interface{}{"0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", "-1"}, false)
+	assert.NotNil(err)
+}
+
+func TestABIMethodLookup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cabi, err := parseABI([]byte(testABI))
+	require.Nil(err)
+
+	mint, err := cabi.Method("mint", 2)
+	require.Nil(err)
+	assert.Equal("mint(address,uint256)", mint.Signature())
+	assert.True(mint.Payable)
+
+	mint, err = cabi.Method("mint(uint256)", 2)
+	require.Nil(err)
+	assert.Equal("mint(uint256)", mint.Signature())
+
+	_, err = cabi.Method("mint", 3)
+	assert.NotNil(err)
+	_, err = cabi.Method("burn", 1)
+	assert.NotNil(err)
+}
+
+func TestABIUnpack(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cabi, err := parseABI([]byte(testABI))
+	require.Nil(err)
+
+	balanceOf, err := cabi.Method("balanceOf", 1)
+	require.Nil(err)
+	assert.True(balanceOf.ReadOnly)
+	values, err := balanceOf.Unpack(unhex(t, "00000000000000000000000000000000000000000000000000000000000003e8"))
+	require.Nil(err)
+	assert.Equal([]interface{}{big.NewInt(1000)}, values)
+
+	info, err := cabi.Method("info", 0)
+	require.Nil(err)
+	assert.Equal("info()", info.Signature())
+	ret := unhex(t, `
+		0000000000000000000000000000000000000000000000000000000000000060
+		0000000000000000000000000000000000000000000000000000000000000001
+		0000000000000000000000000000000000000000000000000000000000000000
+		0000000000000000000000000000000000000000000000000000000000000040
+		ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff
+		0000000000000000000000000000000000000000000000000000000000000005
+		616c696365000000000000000000000000000000000000000000000000000000`)
+	values, err = info.Unpack(ret)
+	require.Nil(err)
+	assert.Equal([]interface{}{
+		[]interface{}{"alice", big.NewInt(-1)},
+		[]interface{}{true, false},
+	}, values)
+
+	// Truncated data and offsets out of range are rejected
+	_, err = info.Unpack(ret[:100])
+	assert.NotNil(err)
+	corrupted := append([]byte{}, ret...)
+	corrupted[31] = 0xff
+	_, err = info.Unpack(corrupted)
+	assert.NotNil(err)
+
+	transfer, err := cabi.Method("transfer", 2)
+	require.Nil(err)
+	values, err = transfer.Unpack(unhex(t, "0000000000000000000000000000000000000000000000000000000000000001"))
+	require.Nil(err)
+	assert.Equal([]interface{}{true}, values)
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	assert := assert.New(t)
+
+	ret := unhex(t, `08c379a0
+		0000000000000000000000000000000000000000000000000000000000000020
+		0000000000000000000000000000000000000000000000000000000000000012
+		696e73756666696369656e742066756e64730000000000000000000000000000`)
+	reason, ok := decodeRevertReason(ret)
+	assert.True(ok)
+	assert.Equal("insufficient funds", reason)
+
+	_, ok = decodeRevertReason(ret[:40])
+	assert.False(ok)
+	_, ok = decodeRevertReason([]byte{})
+	assert.False(ok)
+}
+
+func TestLoadArtifact(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "contract_artifact")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) string {
+		filePath := path.Join(dir, name)
+		require.Nil(ioutil.WriteFile(filePath, []byte(content), 0600))
+		return filePath
+	}
+
+	// Hardhat
+	hardhat := write("Token.json", `{"contractName": "Token", "abi": `+testABI+`, "bytecode": "0x6080"}`)
+	art, err := loadArtifact(hardhat, "")
+	require.Nil(err)
+	assert.Equal("Token", art.Name)
+	assert.Equal(common.Bytes{0x60, 0x80}, art.Bytecode)
+	assert.NotNil(art.ABI.Constructor)
+
+	// Foundry, named after the file
+	foundry := write("Counter.json", `{"abi": [], "bytecode": {"object": "0x6001"}}`)
+	art, err = loadArtifact(foundry, "")
+	require.Nil(err)
+	assert.Equal("Counter", art.Name)
+	assert.Equal(common.Bytes{0x60, 0x01}, art.Bytecode)
+
+	// solc --combined-json with the ABI as a string
+	combined := write("combined.json", `{"contracts": {
+		"contracts/Token.sol:Token": {"abi": "[]", "bin": "6002"},
+		"contracts/Token.sol:Lib": {"abi": [], "bin": "6003"}
+	}}`)
+	_, err = loadArtifact(combined, "")
+	assert.NotNil(err)
+	art, err = loadArtifact(combined, "Token")
+	require.Nil(err)
+	assert.Equal("Token", art.Name)
+	assert.Equal(common.Bytes{0x60, 0x02}, art.Bytecode)
+	_, err = loadArtifact(combined, "Missing")
+	assert.NotNil(err)
+
+	// solc --standard-json
+	standard := write("standard.json", `{"contracts": {"contracts/Token.sol": {
+		"Token": {"abi": [], "evm": {"bytecode": {"object": "6004"}}}
+	}}}`)
+	art, err = loadArtifact(standard, "contracts/Token.sol:Token")
+	require.Nil(err)
+	assert.Equal(common.Bytes{0x60, 0x04}, art.Bytecode)
+
+	// Unlinked libraries
+	unlinked := write("Unlinked.json", `{"abi": [], "bytecode": "0x73__$abcdef$__"}`)
+	_, err = loadArtifact(unlinked, "")
+	assert.NotNil(err)
+}
@@ -0,0 +1,144 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+)
+
+// artifact is a compiled contract
+type artifact struct {
+	Name     string
+	ABI      *contractABI
+	Bytecode common.Bytes // the creation code, empty for the abstract contracts and interfaces
+}
+
+// artifactJSON covers the artifacts of Hardhat, Truffle and Foundry, and the contracts in the
+// solc outputs. The bytecode is a hex string, or an object with the hex string in "object".
+type artifactJSON struct {
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+	Bytecode     json.RawMessage `json:"bytecode"`
+	Bin          string          `json:"bin"` // solc --combined-json
+	EVM          *struct {
+		Bytecode json.RawMessage `json:"bytecode"`
+	} `json:"evm"` // solc --standard-json
+}
+
+// loadArtifact loads the contract from the artifact file. The name selects the contract if the
+// file is a solc output with multiple contracts, e.g. "MyContract" or "contracts/My.sol:MyContract".
+func loadArtifact(filePath, name string) (*artifact, error) {
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var top struct {
+		artifactJSON
+		Contracts map[string]json.RawMessage `json:"contracts"`
+	}
+	if err := json.Unmarshal(raw, &top); err != nil {
+		return nil, fmt.Errorf("Failed to parse artifact %v: %v", filePath, err)
+	}
+
+	if len(top.ABI) != 0 {
+		contractName := top.ContractName
+		if contractName == "" {
+			contractName = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		}
+		return parseArtifact(contractName, &top.artifactJSON)
+	}
+
+	// solc outputs, keyed by "file:Name" in the combined JSON, and by file then name in the standard JSON
+	contracts := make(map[string]*artifactJSON)
+	for key, value := range top.Contracts {
+		contract := &artifactJSON{}
+		if err := json.Unmarshal(value, contract); err == nil && len(contract.ABI) != 0 {
+			contracts[key] = contract
+			continue
+		}
+		fileContracts := make(map[string]*artifactJSON)
+		if err := json.Unmarshal(value, &fileContracts); err != nil {
+			return nil, fmt.Errorf("Failed to parse contract %v: %v", key, err)
+		}
+		for contractName, contract := range fileContracts {
+			contracts[key+":"+contractName] = contract
+		}
+	}
+
+	matches := []string{}
+	for key := range contracts {
+		if name == "" || key == name || strings.HasSuffix(key, ":"+name) {
+			matches = append(matches, key)
+		}
+	}
+	sort.Strings(matches)
+	if len(matches) != 1 {
+		all := []string{}
+		for key := range contracts {
+			all = append(all, key)
+		}
+		sort.Strings(all)
+		if len(matches) == 0 && name != "" {
+			return nil, fmt.Errorf("Contract %v not found in %v, available: %v", name, filePath, strings.Join(all, ", "))
+		}
+		return nil, fmt.Errorf("Please select the contract with --contract, available: %v", strings.Join(all, ", "))
+	}
+	key := matches[0]
+	return parseArtifact(key[strings.LastIndex(key, ":")+1:], contracts[key])
+}
+
+func parseArtifact(name string, aj *artifactJSON) (*artifact, error) {
+	abiJSON := []byte(aj.ABI)
+	// solc --combined-json of the older versions encodes the ABI as a string
+	var abiString string
+	if err := json.Unmarshal(aj.ABI, &abiString); err == nil {
+		abiJSON = []byte(abiString)
+	}
+	cabi, err := parseABI(abiJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	code := aj.Bin
+	bytecode := aj.Bytecode
+	if aj.EVM != nil {
+		bytecode = aj.EVM.Bytecode
+	}
+	if len(bytecode) != 0 {
+		var object struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(bytecode, &code); err != nil {
+			if err := json.Unmarshal(bytecode, &object); err != nil {
+				return nil, fmt.Errorf("Failed to parse the bytecode of %v: %v", name, err)
+			}
+			code = object.Object
+		}
+	}
+
+	if strings.Contains(code, "__") {
+		return nil, fmt.Errorf("The bytecode of %v references libraries which are not linked", name)
+	}
+	if code != "" && !hexutil.Has0xPrefix(code) {
+		code = "0x" + code
+	}
+	var bytes []byte
+	if code != "" && code != "0x" {
+		if bytes, err = hexutil.Decode(code); err != nil {
+			return nil, fmt.Errorf("Failed to decode the bytecode of %v: %v", name, err)
+		}
+	}
+
+	return &artifact{
+		Name:     name,
+		ABI:      cabi,
+		Bytecode: bytes,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// callCmd represents the call command, which executes a method of a deployed contract against the
+// state of the remote node without submitting a transaction, and decodes the returned values.
+// Example:
+//
+//	pandocli contract call --artifact=build/MyToken.json --to=0x7ad6cea2bc3162e30a3c98d84f821b3233c22647 --method=balanceOf --args=2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var callCmd = &cobra.Command{
+	Use:     "call",
+	Short:   "Call a method of a smart contract without submitting a transaction",
+	Example: `pandocli contract call --artifact=build/MyToken.json --to=0x7ad6cea2bc3162e30a3c98d84f821b3233c22647 --method=balanceOf --args=2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doCallCmd,
+}
+
+// sendCmd represents the send command, which submits a transaction calling a method of a deployed
+// contract, then waits for the receipt.
+// Example:
+//
+//	pandocli contract send --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --artifact=build/MyToken.json --to=0x7ad6cea2bc3162e30a3c98d84f821b3233c22647 --method=transfer --args='["0d2fD67d573c8ecB4161510fc00754d64B401F86", 100]'
+var sendCmd = &cobra.Command{
+	Use:     "send",
+	Short:   "Submit a transaction calling a method of a smart contract",
+	Example: `pandocli contract send --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --artifact=build/MyToken.json --to=0x7ad6cea2bc3162e30a3c98d84f821b3233c22647 --method=transfer --args='["0d2fD67d573c8ecB4161510fc00754d64B401F86", 100]'`,
+	Run:     doSendCmd,
+}
+
+func parseContractAddress() common.Address {
+	if !common.IsHexAddress(toFlag) {
+		utils.Error("Invalid contract address: %v\n", toFlag)
+	}
+	return common.HexToAddress(toFlag)
+}
+
+func doCallCmd(cmd *cobra.Command, args []string) {
+	_, method, methodArgs := loadMethod(methodFlag)
+	data, err := method.Pack(methodArgs, false)
+	if err != nil {
+		utils.Error("Failed to encode the arguments: %v\n", err)
+	}
+	to := parseContractAddress()
+	from := common.Address{}
+	if fromFlag != "" {
+		if !common.IsHexAddress(fromFlag) {
+			utils.Error("Invalid caller address: %v\n", fromFlag)
+		}
+		from = common.HexToAddress(fromFlag)
+	}
+
+	sctx := &types.SmartContractTx{
+		From: types.TxInput{
+			Address: from,
+			Coins: types.Coins{
+				PandoWei: new(big.Int).SetUint64(0),
+				PTXWei:   parseValue(method),
+			},
+		},
+		To: types.TxOutput{
+			Address: to,
+		},
+		GasLimit: types.MaximumTxGasLimit,
+		GasPrice: new(big.Int).SetUint64(types.MinimumGasPrice),
+		Data:     data,
+	}
+	sctxBytes, err := types.TxToBytes(sctx)
+	if err != nil {
+		utils.Error("Failed to encode smart contract transaction: %v\n", err)
+	}
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	res, err := client.Call("pando.CallSmartContract", rpc.CallSmartContractArgs{
+		SctxBytes: hex.EncodeToString(sctxBytes),
+		Block:     rpc.BlockSpecifier(blockFlag),
+	})
+	if err != nil {
+		utils.Error("Failed to call smart contract: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to execute smart contract: %v\n", res.Error)
+	}
+	result := &rpc.CallSmartContractResult{}
+	if err := res.GetObject(result); err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	vmRet, err := hex.DecodeString(result.VmReturn)
+	if err != nil {
+		utils.Error("Failed to decode the returned data: %v\n", err)
+	}
+	if result.VmError != "" {
+		utils.Error("The call fails: %v\n", formatVMError(result.VmError, vmRet))
+	}
+
+	values, err := method.Unpack(vmRet)
+	if err != nil {
+		utils.Error("Failed to decode the returned values %x: %v\n", vmRet, err)
+	}
+	printJSON(fmt.Sprintf("%v returned:", method.Signature()), values)
+}
+
+func doSendCmd(cmd *cobra.Command, args []string) {
+	_, method, methodArgs := loadMethod(methodFlag)
+	if method.ReadOnly {
+		fmt.Printf("Warning: %v does not modify the state, consider using pandocli contract call\n", method.Signature())
+	}
+	data, err := method.Pack(methodArgs, false)
+	if err != nil {
+		utils.Error("Failed to encode the arguments: %v\n", err)
+	}
+	to := parseContractAddress()
+	value := parseValue(method)
+
+	result := sendSmartContractTx(cmd, func(from common.Address) *types.SmartContractTx {
+		return newSmartContractTx(from, to, value, data)
+	})
+	if result.Receipt != nil && result.Receipt.EvmErr != "" {
+		printJSON("Transaction failed:", result)
+		utils.Error("%v\n", formatVMError(result.Receipt.EvmErr, result.Receipt.EvmRet))
+	}
+	printJSON("Successfully executed "+method.Signature()+":", result)
+}
+
+func init() {
+	callCmd.Flags().StringVar(&artifactFlag, "artifact", "", "Path of the compiled contract artifact")
+	callCmd.Flags().StringVar(&contractFlag, "contract", "", "Name of the contract if the artifact has multiple contracts")
+	callCmd.Flags().StringVar(&toFlag, "to", "", "The smart contract address")
+	callCmd.Flags().StringVar(&methodFlag, "method", "", "Name or signature of the method, e.g. balanceOf or balanceOf(address)")
+	callCmd.Flags().StringVar(&argsFlag, "args", "", "Arguments, as a JSON array or comma separated")
+	callCmd.Flags().StringVar(&fromFlag, "from", "", "The caller address")
+	callCmd.Flags().StringVar(&valueFlag, "value", "0", "PTX value to be transferred")
+	callCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending to execute against")
+
+	callCmd.MarkFlagRequired("artifact")
+	callCmd.MarkFlagRequired("to")
+	callCmd.MarkFlagRequired("method")
+
+	addTxFlags(sendCmd)
+	sendCmd.Flags().StringVar(&toFlag, "to", "", "The smart contract address")
+	sendCmd.Flags().StringVar(&methodFlag, "method", "", "Name or signature of the method, e.g. transfer or transfer(address,uint256)")
+
+	sendCmd.MarkFlagRequired("to")
+	sendCmd.MarkFlagRequired("method")
+}
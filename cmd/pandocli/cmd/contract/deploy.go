@@ -0,0 +1,47 @@
+package contract
+
+import (
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+// deployCmd represents the deploy command, which deploys the contract of an artifact file with the
+// encoded constructor arguments, then waits for the receipt to report the contract address.
+// Example:
+//
+//	pandocli contract deploy --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --artifact=build/MyToken.json --args='["MyToken", "MTK", 1000000]'
+var deployCmd = &cobra.Command{
+	Use:     "deploy",
+	Short:   "Deploy a smart contract from an artifact file",
+	Long:    `Deploy a smart contract from an artifact file. The constructor arguments are encoded with the ABI of the artifact, the gas limit is estimated by the remote node unless --gas_limit is set, and the command waits for the receipt to report the address of the contract.`,
+	Example: `pandocli contract deploy --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --artifact=build/MyToken.json --args='["MyToken", "MTK", 1000000]'`,
+	Run:     doDeployCmd,
+}
+
+func doDeployCmd(cmd *cobra.Command, args []string) {
+	art, constructor, ctorArgs := loadMethod("")
+	if len(art.Bytecode) == 0 {
+		utils.Error("Artifact %v has no bytecode, the contract may be abstract or an interface\n", art.Name)
+	}
+	encodedArgs, err := constructor.Pack(ctorArgs, true)
+	if err != nil {
+		utils.Error("Failed to encode the constructor arguments: %v\n", err)
+	}
+	value := parseValue(constructor)
+	data := append(append(common.Bytes{}, art.Bytecode...), encodedArgs...)
+
+	result := sendSmartContractTx(cmd, func(from common.Address) *types.SmartContractTx {
+		return newSmartContractTx(from, common.Address{}, value, data)
+	})
+	if result.Receipt != nil && result.Receipt.EvmErr != "" {
+		printJSON("Deployment failed:", result)
+		utils.Error("%v\n", formatVMError(result.Receipt.EvmErr, result.Receipt.EvmRet))
+	}
+	printJSON("Successfully deployed contract "+art.Name+":", result)
+}
+
+func init() {
+	addTxFlags(deployCmd)
+}
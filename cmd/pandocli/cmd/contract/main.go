@@ -0,0 +1,43 @@
+package contract
+
+import (
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// Common flags used in Contract sub commands.
+var (
+	chainIDFlag     string
+	artifactFlag    string
+	contractFlag    string
+	argsFlag        string
+	methodFlag      string
+	fromFlag        string
+	toFlag          string
+	pathFlag        string
+	seqFlag         uint64
+	valueFlag       string
+	gasPriceFlag    string
+	gasLimitFlag    uint64
+	walletFlag      string
+	blockFlag       string
+	retryFlag       bool
+	receiptWaitFlag uint64
+)
+
+// ContractCmd represents the contract command
+var ContractCmd = &cobra.Command{
+	Use:   "contract",
+	Short: "Deploy and interact with smart contracts compiled to artifact files",
+	Long: `Deploy and interact with smart contracts compiled to artifact files. The artifacts of Hardhat, Truffle and Foundry,
+and the outputs of solc --combined-json and --standard-json are supported.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		chainIDFlag = utils.ResolveChainID(chainIDFlag)
+	},
+}
+
+func init() {
+	ContractCmd.AddCommand(deployCmd)
+	ContractCmd.AddCommand(callCmd)
+	ContractCmd.AddCommand(sendCmd)
+}
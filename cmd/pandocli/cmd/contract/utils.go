@@ -0,0 +1,248 @@
+package contract
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/tx"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+const receiptPollInterval = 2 * time.Second
+
+// contractReceipt is the receipt of a smart contract transaction
+type contractReceipt struct {
+	ContractAddress common.Address `json:"contract_address"`
+	GasUsed         uint64         `json:"gas_used"`
+	EvmRet          common.Bytes   `json:"evm_return"`
+	EvmErr          string         `json:"evm_error"`
+}
+
+// contractTxResult is printed once a smart contract transaction is broadcasted
+type contractTxResult struct {
+	TxHash      string           `json:"hash"`
+	BlockHeight uint64           `json:"block_height,omitempty"`
+	GasLimit    uint64           `json:"gas_limit"`
+	Receipt     *contractReceipt `json:"receipt,omitempty"`
+}
+
+// loadMethod loads the artifact and resolves the method called with the arguments
+func loadMethod(methodName string) (*artifact, *abiMethod, []interface{}) {
+	art, err := loadArtifact(artifactFlag, contractFlag)
+	if err != nil {
+		utils.Error("Failed to load artifact: %v\n", err)
+	}
+	args, err := parseABIArgs(argsFlag)
+	if err != nil {
+		utils.Error("Failed to parse arguments: %v\n", err)
+	}
+
+	if methodName == "" {
+		method := art.ABI.Constructor
+		if method == nil {
+			// Contracts without an explicit constructor take no arguments
+			method = &abiMethod{}
+		}
+		return art, method, args
+	}
+	method, err := art.ABI.Method(methodName, len(args))
+	if err != nil {
+		utils.Error("%v\n", err)
+	}
+	return art, method, args
+}
+
+func newSmartContractTx(from, to common.Address, value *big.Int, data common.Bytes) *types.SmartContractTx {
+	gasPrice, ok := types.ParseCoinAmount(gasPriceFlag)
+	if !ok {
+		utils.Error("Failed to parse gas price\n")
+	}
+	return &types.SmartContractTx{
+		From: types.TxInput{
+			Address: from,
+			Coins: types.Coins{
+				PandoWei: new(big.Int).SetUint64(0),
+				PTXWei:   value,
+			},
+			Sequence: seqFlag,
+		},
+		To: types.TxOutput{
+			Address: to,
+		},
+		GasLimit: gasLimitFlag,
+		GasPrice: gasPrice,
+		Data:     data,
+	}
+}
+
+func parseValue(method *abiMethod) *big.Int {
+	value, ok := types.ParseCoinAmount(valueFlag)
+	if !ok {
+		utils.Error("Failed to parse value\n")
+	}
+	if value.Sign() > 0 && !method.Payable {
+		utils.Error("The method is not payable, the value must be 0\n")
+	}
+	return value
+}
+
+// estimateGas asks the remote node for the gas limit of the transaction, the VM error is
+// reported with the revert reason if the transaction fails regardless of the gas limit
+func estimateGas(client *rpcc.RPCClient, sctx *types.SmartContractTx) uint64 {
+	sctxBytes, err := types.TxToBytes(sctx)
+	if err != nil {
+		utils.Error("Failed to encode smart contract transaction: %v\n", err)
+	}
+	res, err := client.Call("pando.EstimateGas", rpc.EstimateGasArgs{SctxBytes: hex.EncodeToString(sctxBytes)})
+	if err != nil {
+		utils.Error("Failed to estimate gas: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to estimate gas: %v\n", res.Error)
+	}
+	result := &rpc.EstimateGasResult{}
+	if err := res.GetObject(result); err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	if result.VmError != "" {
+		vmRet, _ := hex.DecodeString(result.VmReturn)
+		utils.Error("The transaction fails: %v\n", formatVMError(result.VmError, vmRet))
+	}
+	return uint64(result.GasLimit)
+}
+
+// sendSmartContractTx signs and broadcasts the transaction, estimating the gas limit and
+// retrieving the sequence if not given by the flags, then waits for the receipt
+func sendSmartContractTx(cmd *cobra.Command, build func(from common.Address) *types.SmartContractTx) *contractTxResult {
+	wallet, fromAddress, err := tx.WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		utils.Error("Failed to unlock wallet\n")
+	}
+	defer wallet.Lock(fromAddress)
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	sctx := build(fromAddress)
+	if sctx.From.Sequence == 0 {
+		if sctx.From.Sequence, err = tx.GetNextSequence(fromAddress); err != nil {
+			utils.Error("Failed to get the sequence of %v: %v\n", fromAddress.Hex(), err)
+		}
+	}
+	if sctx.GasLimit == 0 {
+		sctx.GasLimit = estimateGas(client, sctx)
+	}
+
+	policy := rpc.DefaultRetryPolicy
+	if !retryFlag {
+		policy = rpc.NoRetryPolicy
+	}
+	broadcasted, err := rpc.NewTxSender(client, policy).Send(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := tx.GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			sctx.From.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, sctx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		sctx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(sctx)
+	}, false)
+	if err != nil {
+		utils.Error("Failed to broadcast transaction: %v\n", err)
+	}
+
+	result := &contractTxResult{
+		TxHash:   broadcasted.TxHash,
+		GasLimit: sctx.GasLimit,
+	}
+	if receiptWaitFlag > 0 {
+		result.BlockHeight, result.Receipt = waitForReceipt(client, broadcasted.TxHash, time.Duration(receiptWaitFlag)*time.Second)
+	}
+	return result
+}
+
+// waitForReceipt polls the remote node until the transaction is finalized
+func waitForReceipt(client *rpcc.RPCClient, txHash string, timeout time.Duration) (uint64, *contractReceipt) {
+	deadline := time.Now().Add(timeout)
+	for {
+		res, err := client.Call("pando.GetTransaction", rpc.GetTransactionArgs{Hash: txHash})
+		if err == nil && res.Error == nil {
+			result := &struct {
+				BlockHeight common.JSONUint64 `json:"block_height"`
+				Status      rpc.TxStatus      `json:"status"`
+				Receipt     *struct {
+					ContractAddress common.Address
+					GasUsed         uint64
+					EvmRet          common.Bytes
+					EvmErr          string
+				} `json:"receipt"`
+			}{}
+			if err := res.GetObject(result); err != nil {
+				utils.Error("Failed to parse server response: %v\n", err)
+			}
+			if result.Status == rpc.TxStatusAbandoned {
+				utils.Error("Transaction %v was abandoned\n", txHash)
+			}
+			if result.Status == rpc.TxStatusFinalized && result.Receipt != nil {
+				return uint64(result.BlockHeight), &contractReceipt{
+					ContractAddress: result.Receipt.ContractAddress,
+					GasUsed:         result.Receipt.GasUsed,
+					EvmRet:          result.Receipt.EvmRet,
+					EvmErr:          result.Receipt.EvmErr,
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			utils.Error("Timed out waiting for the receipt of transaction %v\n", txHash)
+		}
+		time.Sleep(receiptPollInterval)
+	}
+}
+
+// formatVMError appends the revert reason to the VM error if the return data carries one
+func formatVMError(vmErr string, ret []byte) string {
+	if reason, ok := decodeRevertReason(ret); ok {
+		return fmt.Sprintf("%v: %v", vmErr, reason)
+	}
+	return vmErr
+}
+
+func printJSON(prefix string, v interface{}) {
+	formatted, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		utils.Error("Failed to format result: %v\n", err)
+	}
+	fmt.Printf("%s\n%s\n", prefix, formatted)
+}
+
+// addTxFlags adds the flags shared by the commands sending transactions
+func addTxFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	cmd.Flags().StringVar(&artifactFlag, "artifact", "", "Path of the compiled contract artifact")
+	cmd.Flags().StringVar(&contractFlag, "contract", "", "Name of the contract if the artifact has multiple contracts")
+	cmd.Flags().StringVar(&argsFlag, "args", "", "Arguments, as a JSON array or comma separated")
+	cmd.Flags().StringVar(&fromFlag, "from", "", "The sender address")
+	cmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	cmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	cmd.Flags().StringVar(&valueFlag, "value", "0", "PTX value to be transferred")
+	cmd.Flags().StringVar(&gasPriceFlag, "gas_price", fmt.Sprintf("%dwei", types.MinimumGasPrice), "The gas price")
+	cmd.Flags().Uint64Var(&gasLimitFlag, "gas_limit", 0, "The gas limit (default to the estimate of the remote node)")
+	cmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction (default to the next sequence of the sender)")
+	cmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+	cmd.Flags().Uint64Var(&receiptWaitFlag, "wait", 120, "Seconds to wait for the receipt, 0 to skip")
+
+	cmd.MarkFlagRequired("artifact")
+	cmd.MarkFlagRequired("from")
+}
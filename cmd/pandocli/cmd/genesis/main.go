@@ -0,0 +1,26 @@
+package genesis
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	chainIDFlag     string
+	genesisHashFlag string
+	signaturesFlag  string
+	fromFlag        string
+	walletFlag      string
+	pathFlag        string
+)
+
+// GenesisCmd represents the genesis command
+var GenesisCmd = &cobra.Command{
+	Use:   "genesis",
+	Short: "Sign the genesis block of a network",
+	Long: `Sign the genesis block of a network with a release key. The nodes configured with the release keys in
+genesis.signers refuse to start unless the genesis block is signed by genesis.signatureThreshold of them.`,
+}
+
+func init() {
+	GenesisCmd.AddCommand(signCmd)
+}
@@ -0,0 +1,75 @@
+package genesis
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/tx"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+
+	"github.com/spf13/cobra"
+)
+
+// signCmd adds the signature of a release key to the signature file of a genesis block.
+// Example:
+//		pandocli genesis sign --chain=pandonet --genesis_hash=0x294191fdd2a46c213dbb34dd61a873a293604080f6d5372a6e005a1673426b7a --signatures=genesis.sig --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var signCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Sign a genesis block",
+	Long: `Sign a genesis block with a release key. The signature is added to the signature file, which is
+created if it does not exist yet, replacing the previous signature of the key if any. The signature
+file is distributed with the genesis snapshot, and placed at <config>/genesis.sig on the nodes.`,
+	Example: `pandocli genesis sign --chain=pandonet --genesis_hash=0x294191fdd2a46c213dbb34dd61a873a293604080f6d5372a6e005a1673426b7a --signatures=genesis.sig --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doSignCmd,
+}
+
+func doSignCmd(cmd *cobra.Command, args []string) {
+	genesisHash := common.HexToHash(genesisHashFlag)
+	if genesisHash.IsEmpty() {
+		utils.Error("Invalid genesis block hash: %v\n", genesisHashFlag)
+	}
+
+	sigs, err := core.ReadGenesisSignatures(signaturesFlag)
+	if os.IsNotExist(err) {
+		sigs, err = &core.GenesisSignatures{ChainID: chainIDFlag, GenesisHash: genesisHash}, nil
+	}
+	if err != nil {
+		utils.Error("Failed to read %v: %v\n", signaturesFlag, err)
+	}
+	if sigs.ChainID != chainIDFlag || sigs.GenesisHash != genesisHash {
+		utils.Error("%v signs the genesis block %v of chain %v\n", signaturesFlag, sigs.GenesisHash.Hex(), sigs.ChainID)
+	}
+
+	wallet, address, err := tx.WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		utils.Error("Failed to unlock wallet\n")
+	}
+	defer wallet.Lock(address)
+
+	sig, err := wallet.Sign(address, sigs.SignBytes())
+	if err != nil {
+		utils.Error("Failed to sign genesis block: %v\n", err)
+	}
+	if err := sigs.AddSignature(address, sig); err != nil {
+		utils.Error("Failed to sign genesis block: %v\n", err)
+	}
+
+	if err := core.WriteGenesisSignatures(signaturesFlag, sigs); err != nil {
+		utils.Error("Failed to write %v: %v\n", signaturesFlag, err)
+	}
+	fmt.Printf("Genesis block %v of chain %v signed by %v, %v signatures\n",
+		genesisHash.Hex(), chainIDFlag, address.Hex(), len(sigs.Signatures))
+}
+
+func init() {
+	signCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	signCmd.Flags().StringVar(&genesisHashFlag, "genesis_hash", "", "Hash of the genesis block")
+	signCmd.Flags().StringVar(&signaturesFlag, "signatures", "genesis.sig", "Signature file")
+	signCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the release key")
+	signCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	signCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	signCmd.MarkFlagRequired("chain")
+	signCmd.MarkFlagRequired("genesis_hash")
+}
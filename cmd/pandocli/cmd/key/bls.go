@@ -0,0 +1,62 @@
+package key
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/softwallet"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/cobra"
+)
+
+// blsCmd prints the BLS key info of a validator key, to register the key with a stake deposit
+var blsCmd = &cobra.Command{
+	Use:     "bls",
+	Short:   "Show the BLS key a validator signs its votes with",
+	Long:    `Show the BLS key a validator signs its votes with. The key is derived from the private key, the same way the validator node derives it. Pass the printed key info as the --holder of a validator stake deposit to register the key, so that the votes of the validator can be aggregated.`,
+	Example: "pandocli key bls 1d8E1191E0a97C1aDa4940B79188D3B1f6f5C695",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			utils.Error("Usage: pandocli key bls <address>\n")
+		}
+		address := common.HexToAddress(args[0])
+
+		cfgPath := cmd.Flag("config").Value.String()
+		w, err := wallet.OpenWallet(cfgPath, wtypes.WalletTypeSoft, true)
+		if err != nil {
+			utils.Error("Failed to open wallet: %v\n", err)
+		}
+		sw := w.(*softwallet.SoftWallet)
+
+		prompt := fmt.Sprintf("Please enter password: ")
+		password, err := utils.GetPassword(prompt)
+		if err != nil {
+			utils.Error("Failed to get password: %v\n", err)
+		}
+		err = sw.Unlock(address, password, nil)
+		if err != nil {
+			utils.Error("Failed to unlock address %v: %v\n", address.Hex(), err)
+		}
+		defer sw.Lock(address)
+
+		blsKey, err := sw.GetValidatorBlsKey(address)
+		if err != nil {
+			utils.Error("Failed to derive the BLS key: %v\n", err)
+		}
+		pubkey := blsKey.PublicKey().ToBytes()
+		pop := blsKey.PopProve().ToBytes()
+		sig, err := sw.Sign(address, pop)
+		if err != nil {
+			utils.Error("Failed to sign the BLS key: %v\n", err)
+		}
+
+		fmt.Printf("BLS pubkey: %v\n", hex.EncodeToString(pubkey))
+		fmt.Printf("BLS pop: %v\n", hex.EncodeToString(pop))
+		fmt.Printf("Signature: %v\n", hex.EncodeToString(sig.ToBytes()))
+		keyInfo := append(append(append(address.Bytes(), pubkey...), pop...), sig.ToBytes()...)
+		fmt.Printf("Key info: 0x%v\n", hex.EncodeToString(keyInfo))
+	},
+}
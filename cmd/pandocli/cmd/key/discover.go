@@ -0,0 +1,97 @@
+package key
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/tx"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/pandotoken/pando/wallet/coldwallet"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+var (
+	discoverWalletFlag string
+	discoverPathFlag   string
+	discoverGapFlag    int
+)
+
+// discoverCmd scans the accounts of a hardware wallet for the ones used on chain
+var discoverCmd = &cobra.Command{
+	Use:     "discover",
+	Short:   "Discover the used accounts of a hardware wallet",
+	Long:    `Discover the used accounts of a hardware wallet. The accounts on the sequential derivation paths starting from --path are checked for activity on chain through the remote RPC endpoint, until --gap consecutive accounts are unused.`,
+	Example: `pandocli key discover --wallet=nano --path="m/44'/60'/0'/0/0" --gap=20`,
+	Run:     doDiscoverCmd,
+}
+
+func doDiscoverCmd(cmd *cobra.Command, args []string) {
+	var walletType wtypes.WalletType
+	switch discoverWalletFlag {
+	case "nano":
+		walletType = wtypes.WalletTypeColdNano
+	case "trezor":
+		walletType = wtypes.WalletTypeColdTrezor
+	default:
+		utils.Error("Account discovery is only available for the hardware wallets (nano|trezor)\n")
+	}
+
+	base := wtypes.DefaultBaseDerivationPath
+	if len(discoverPathFlag) != 0 {
+		path, err := tx.ParseDerivationPath(discoverPathFlag, walletType)
+		if err != nil {
+			utils.Error("Failed to parse the derivation path: %v\n", err)
+		}
+		base = path
+	}
+	unlockPath, err := tx.ParseDerivationPath("", walletType)
+	if err != nil {
+		utils.Error("Failed to parse the derivation path: %v\n", err)
+	}
+
+	w, _, err := tx.ColdWalletUnlock(walletType, unlockPath)
+	if err != nil {
+		utils.Error("Failed to unlock the wallet: %v\n", err)
+	}
+	cw, ok := w.(*coldwallet.ColdWallet)
+	if !ok {
+		utils.Error("Account discovery is only available for the hardware wallets\n")
+	}
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	isUsed := func(address common.Address) (bool, error) {
+		res, err := client.Call("pando.GetAccount", rpc.GetAccountArgs{Address: address.Hex()})
+		if err != nil {
+			return false, err
+		}
+		if res.Error != nil {
+			if strings.Contains(res.Error.Message, "not found") {
+				return false, nil
+			}
+			return false, res.Error
+		}
+		return true, nil
+	}
+
+	accounts, err := cw.DiscoverAccounts(base, discoverGapFlag, isUsed)
+	for _, account := range accounts {
+		fmt.Printf("%s  %s\n", account.Address.Hex(), account.Path)
+	}
+	if err != nil {
+		utils.Error("Failed to discover the accounts: %v\n", err)
+	}
+	if len(accounts) == 0 {
+		fmt.Printf("No used account found\n")
+	}
+}
+
+func init() {
+	discoverCmd.Flags().StringVar(&discoverWalletFlag, "wallet", "nano", "Wallet type (nano|trezor)")
+	discoverCmd.Flags().StringVar(&discoverPathFlag, "path", "", "Derivation path of the first account to scan, m/44'/60'/0'/0/0 by default")
+	discoverCmd.Flags().IntVar(&discoverGapFlag, "gap", coldwallet.DefaultDiscoveryGapLimit, "Number of consecutive unused accounts to stop the discovery after")
+}
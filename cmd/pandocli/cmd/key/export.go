@@ -0,0 +1,80 @@
+package key
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/softwallet"
+	"github.com/pandotoken/pando/wallet/softwallet/keystore"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportEthKeystoreFlag string
+	exportKDFFlag         string
+)
+
+// exportCmd exports a key into an Ethereum keystore file
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export a key into an Ethereum keystore file",
+	Long:    `Export a key into an Ethereum keystore V3 file, which MetaMask, geth and the other Ethereum wallets can import. If --eth-keystore is a folder, e.g. the keystore folder of geth, the file is named the way geth names its key files.`,
+	Example: "pandocli key export 1d8E1191E0a97C1aDa4940B79188D3B1f6f5C695 --eth-keystore=~/.ethereum/keystore",
+	Run:     doExportCmd,
+}
+
+func doExportCmd(cmd *cobra.Command, args []string) {
+	if len(args) < 1 || len(exportEthKeystoreFlag) == 0 {
+		utils.Error("Usage: pandocli key export <address> --eth-keystore=<keystore file or folder>\n")
+	}
+	address := common.HexToAddress(args[0])
+
+	filePath := exportEthKeystoreFlag
+	if info, err := os.Stat(filePath); err == nil {
+		if !info.IsDir() {
+			utils.Error("File %v already exists\n", filePath)
+		}
+		filePath = path.Join(filePath, keystore.EthereumKeyFileName(address))
+	}
+
+	cfgPath := cmd.Flag("config").Value.String()
+	w, err := wallet.OpenWallet(cfgPath, wtypes.WalletTypeSoft, true)
+	if err != nil {
+		utils.Error("Failed to open wallet: %v\n", err)
+	}
+	sw := w.(*softwallet.SoftWallet)
+
+	password, err := utils.GetPassword("Please enter the password of the key: ")
+	if err != nil {
+		utils.Error("Failed to get password: %v\n", err)
+	}
+	keyjsonPassword, err := utils.GetPassword("Please enter a password for the keystore file: ")
+	if err != nil {
+		utils.Error("Failed to get password: %v\n", err)
+	}
+
+	keyjson, err := sw.ExportEthereumKey(address, password, keyjsonPassword, exportKDFFlag)
+	if err != nil {
+		utils.Error("Failed to export the key: %v\n", err)
+	}
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		utils.Error("Failed to create the keystore file: %v\n", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(keyjson); err != nil {
+		utils.Error("Failed to write the keystore file: %v\n", err)
+	}
+
+	fmt.Printf("Successfully exported key %v to %v\n", address.Hex(), filePath)
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportEthKeystoreFlag, "eth-keystore", "", "Ethereum keystore V3 file or folder to export to")
+	exportCmd.Flags().StringVar(&exportKDFFlag, "kdf", keystore.KDFScrypt, "key derivation function of the keystore file (scrypt|pbkdf2)")
+}
@@ -0,0 +1,60 @@
+package key
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/softwallet"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/cobra"
+)
+
+var importEthKeystoreFlag string
+
+// importCmd imports a key from an Ethereum keystore file
+var importCmd = &cobra.Command{
+	Use:     "import",
+	Short:   "Import a key from an Ethereum keystore file",
+	Long:    `Import a key from an Ethereum keystore V3 file, e.g. exported by MetaMask or copied from the keystore folder of geth. The key controls the same address on Pando, and is stored encrypted under a new password.`,
+	Example: "pandocli key import --eth-keystore=UTC--2016-03-22T12-57-55.920751759Z--7ef5a6135f1fd6a02593eedc869c6d41d934aef8",
+	Run:     doImportCmd,
+}
+
+func doImportCmd(cmd *cobra.Command, args []string) {
+	if len(importEthKeystoreFlag) == 0 {
+		utils.Error("Usage: pandocli key import --eth-keystore=<keystore file>\n")
+	}
+	keyjson, err := ioutil.ReadFile(importEthKeystoreFlag)
+	if err != nil {
+		utils.Error("Failed to read the keystore file: %v\n", err)
+	}
+
+	cfgPath := cmd.Flag("config").Value.String()
+	w, err := wallet.OpenWallet(cfgPath, wtypes.WalletTypeSoft, true)
+	if err != nil {
+		utils.Error("Failed to open wallet: %v\n", err)
+	}
+	sw := w.(*softwallet.SoftWallet)
+
+	keyjsonPassword, err := utils.GetPassword("Please enter the password of the keystore file: ")
+	if err != nil {
+		utils.Error("Failed to get password: %v\n", err)
+	}
+	password, err := utils.GetPassword("Please enter a password for the imported key: ")
+	if err != nil {
+		utils.Error("Failed to get password: %v\n", err)
+	}
+
+	address, err := sw.ImportEthereumKey(keyjson, keyjsonPassword, password)
+	if err != nil {
+		utils.Error("Failed to import the key: %v\n", err)
+	}
+
+	fmt.Printf("Successfully imported key: %v\n", address.Hex())
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importEthKeystoreFlag, "eth-keystore", "", "Ethereum keystore V3 file to import")
+}
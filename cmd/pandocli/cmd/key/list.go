@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/softwallet"
 	wtypes "github.com/pandotoken/pando/wallet/types"
 )
 
@@ -13,7 +14,7 @@ import (
 var listCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List all keys",
-	Long:    `List all keys.`,
+	Long:    `List all keys, and the watch-only accounts.`,
 	Example: "pandocli key list",
 	Run: func(cmd *cobra.Command, args []string) {
 		cfgPath := cmd.Flag("config").Value.String()
@@ -30,5 +31,17 @@ var listCmd = &cobra.Command{
 		for _, keyAddress := range keyAddresses {
 			fmt.Printf("%s\n", keyAddress.Hex())
 		}
+
+		accounts, err := wallet.(*softwallet.SoftWallet).ListWatchOnly()
+		if err != nil {
+			utils.Error("Failed to list watch-only accounts: %v\n", err)
+		}
+		for _, account := range accounts {
+			if len(account.Label) != 0 {
+				fmt.Printf("%s (watch-only, %s)\n", account.Address.Hex(), account.Label)
+			} else {
+				fmt.Printf("%s (watch-only)\n", account.Address.Hex())
+			}
+		}
 	},
 }
@@ -16,4 +16,11 @@ func init() {
 	KeyCmd.AddCommand(listCmd)
 	KeyCmd.AddCommand(deleteCmd)
 	KeyCmd.AddCommand(passwordCmd)
+	KeyCmd.AddCommand(blsCmd)
+	KeyCmd.AddCommand(discoverCmd)
+	KeyCmd.AddCommand(importCmd)
+	KeyCmd.AddCommand(exportCmd)
+	KeyCmd.AddCommand(recoverCmd)
+	KeyCmd.AddCommand(watchCmd)
+	KeyCmd.AddCommand(unwatchCmd)
 }
@@ -6,16 +6,24 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/softwallet/keystore"
 	wtypes "github.com/pandotoken/pando/wallet/types"
 )
 
+var newMnemonicFlag bool
+
 // newCmd generates a new key
 var newCmd = &cobra.Command{
 	Use:     "new",
 	Short:   "Generates a new private key",
-	Long:    `Generates a new private key.`,
+	Long:    `Generates a new private key. With --mnemonic, generates a BIP-39 seed phrase instead, and derives the keys of the --accounts consecutive accounts starting from --path. The keys can be recovered from the seed phrase with "pandocli key recover".`,
 	Example: "pandocli key new",
 	Run: func(cmd *cobra.Command, args []string) {
+		if newMnemonicFlag {
+			doNewMnemonicCmd(cmd)
+			return
+		}
+
 		cfgPath := cmd.Flag("config").Value.String()
 		wallet, err := wallet.OpenWallet(cfgPath, wtypes.WalletTypeSoft, true)
 		if err != nil {
@@ -36,3 +44,22 @@ var newCmd = &cobra.Command{
 		fmt.Printf("Successfully created key: %v\n", address.Hex())
 	},
 }
+
+func doNewMnemonicCmd(cmd *cobra.Command) {
+	mnemonic, err := keystore.NewMnemonic()
+	if err != nil {
+		utils.Error("Failed to generate seed phrase: %v\n", err)
+	}
+
+	addresses := importMnemonicKeys(cmd, mnemonic)
+	for _, address := range addresses {
+		fmt.Printf("Successfully created key: %v\n", address.Hex())
+	}
+	fmt.Printf("\nSeed phrase:\n\n%v\n\n", mnemonic)
+	fmt.Println("Write down the seed phrase and keep it in a safe place. Anyone with the seed phrase can recover the keys.")
+}
+
+func init() {
+	newCmd.Flags().BoolVar(&newMnemonicFlag, "mnemonic", false, "generate a BIP-39 seed phrase and derive the keys from it")
+	addMnemonicFlags(newCmd)
+}
@@ -3,15 +3,96 @@ package key
 import (
 	"fmt"
 
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/tx"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/softwallet"
+	"github.com/pandotoken/pando/wallet/softwallet/keystore"
+	wtypes "github.com/pandotoken/pando/wallet/types"
 	"github.com/spf13/cobra"
 )
 
+var (
+	mnemonicPathFlag       string
+	mnemonicAccountsFlag   int
+	mnemonicPassphraseFlag bool
+)
+
 // recoverCmd recovers the key from the given seed phrase
 var recoverCmd = &cobra.Command{
-	Use:   "recover",
-	Short: "Recover a key from seed phrase",
-	Long:  `Recover a key from seed phrase.`,
+	Use:     "recover",
+	Short:   "Recover keys from seed phrase",
+	Long:    `Recover keys from a BIP-39 seed phrase, e.g. generated by "pandocli key new --mnemonic", MetaMask or a hardware wallet. The keys of the --accounts consecutive accounts starting from --path are derived and stored.`,
+	Example: `pandocli key recover --path="m/44'/60'/0'/0/0" --accounts=3`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("key recover not supported yet")
+		mnemonic, err := utils.GetPassword("Please enter the seed phrase: ")
+		if err != nil {
+			utils.Error("Failed to get seed phrase: %v\n", err)
+		}
+		if err := keystore.ValidateMnemonic(mnemonic); err != nil {
+			utils.Error("%v\n", err)
+		}
+
+		addresses := importMnemonicKeys(cmd, mnemonic)
+		for _, address := range addresses {
+			fmt.Printf("Successfully recovered key: %v\n", address.Hex())
+		}
 	},
 }
+
+// importMnemonicKeys stores the keys of the accounts derived from the mnemonic along the path
+// given by the flags, incrementing the last component of the path for each account
+func importMnemonicKeys(cmd *cobra.Command, mnemonic string) []common.Address {
+	base, err := tx.ParseDerivationPath(mnemonicPathFlag, wtypes.WalletTypeSoft)
+	if err != nil {
+		utils.Error("Failed to parse the derivation path: %v\n", err)
+	}
+	if len(base) == 0 {
+		utils.Error("Empty derivation path\n")
+	}
+	if mnemonicAccountsFlag < 1 {
+		utils.Error("The number of accounts needs to be positive\n")
+	}
+
+	cfgPath := cmd.Flag("config").Value.String()
+	w, err := wallet.OpenWallet(cfgPath, wtypes.WalletTypeSoft, true)
+	if err != nil {
+		utils.Error("Failed to open wallet: %v\n", err)
+	}
+	sw := w.(*softwallet.SoftWallet)
+
+	passphrase := ""
+	if mnemonicPassphraseFlag {
+		passphrase, err = utils.GetPassword("Please enter the passphrase of the seed phrase: ")
+		if err != nil {
+			utils.Error("Failed to get passphrase: %v\n", err)
+		}
+	}
+	password, err := utils.GetPassword("Please enter password: ")
+	if err != nil {
+		utils.Error("Failed to get password: %v\n", err)
+	}
+
+	addresses := []common.Address{}
+	for i := 0; i < mnemonicAccountsFlag; i++ {
+		path := append(wtypes.DerivationPath{}, base...)
+		path[len(path)-1] += uint32(i)
+		address, err := sw.ImportMnemonicKey(mnemonic, passphrase, path, password)
+		if err != nil {
+			utils.Error("Failed to import the key of %v: %v\n", path, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+func addMnemonicFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&mnemonicPathFlag, "path", wtypes.DefaultBaseDerivationPath.String(), "BIP-44 derivation path of the first account")
+	cmd.Flags().IntVar(&mnemonicAccountsFlag, "accounts", 1, "number of consecutive accounts to derive")
+	cmd.Flags().BoolVar(&mnemonicPassphraseFlag, "passphrase", false, "prompt for the BIP-39 passphrase of the seed phrase")
+}
+
+func init() {
+	addMnemonicFlags(recoverCmd)
+}
@@ -0,0 +1,69 @@
+package key
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/softwallet"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/cobra"
+)
+
+var watchLabelFlag string
+
+// watchCmd adds a watch-only account
+var watchCmd = &cobra.Command{
+	Use:     "watch",
+	Short:   "Add a watch-only account",
+	Long:    `Add a watch-only account, i.e. an address whose private key is kept elsewhere, e.g. on an air-gapped machine. Its transactions are built with "pandocli tx build", signed offline with "pandocli tx sign --offline", and broadcasted with "pandocli tx broadcast".`,
+	Example: `pandocli key watch 2E833968E5bB786Ae419c4d13189fB081Cc43bab --label="cold storage"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || !common.IsHexAddress(args[0]) {
+			utils.Error("Usage: pandocli key watch <address>\n")
+		}
+		address := common.HexToAddress(args[0])
+
+		sw := openSoftWallet(cmd)
+		if err := sw.AddWatchOnly(address, watchLabelFlag); err != nil {
+			utils.Error("Failed to add the watch-only account: %v\n", err)
+		}
+
+		fmt.Printf("Successfully added watch-only account: %v\n", address.Hex())
+	},
+}
+
+// unwatchCmd removes a watch-only account
+var unwatchCmd = &cobra.Command{
+	Use:     "unwatch",
+	Short:   "Remove a watch-only account",
+	Long:    `Remove a watch-only account.`,
+	Example: "pandocli key unwatch 2E833968E5bB786Ae419c4d13189fB081Cc43bab",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || !common.IsHexAddress(args[0]) {
+			utils.Error("Usage: pandocli key unwatch <address>\n")
+		}
+		address := common.HexToAddress(args[0])
+
+		sw := openSoftWallet(cmd)
+		if err := sw.RemoveWatchOnly(address); err != nil {
+			utils.Error("Failed to remove the watch-only account: %v\n", err)
+		}
+
+		fmt.Printf("Successfully removed watch-only account: %v\n", address.Hex())
+	},
+}
+
+func openSoftWallet(cmd *cobra.Command) *softwallet.SoftWallet {
+	cfgPath := cmd.Flag("config").Value.String()
+	w, err := wallet.OpenWallet(cfgPath, wtypes.WalletTypeSoft, true)
+	if err != nil {
+		utils.Error("Failed to open wallet: %v\n", err)
+	}
+	return w.(*softwallet.SoftWallet)
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&watchLabelFlag, "label", "", "Label of the account")
+}
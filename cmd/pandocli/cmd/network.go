@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/core"
+	"github.com/spf13/cobra"
+)
+
+// networkCmd lists the known networks that can be selected with --network.
+// Example:
+//		pandocli network
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "List the known networks",
+	Run:   runNetwork,
+}
+
+func init() {
+	RootCmd.AddCommand(networkCmd)
+}
+
+func runNetwork(cmd *cobra.Command, args []string) {
+	json, err := json.MarshalIndent(core.KnownNetworks(), "", "    ")
+	if err != nil {
+		utils.Error("Failed to encode networks: %v\n", err)
+	}
+	fmt.Println(string(json))
+}
@@ -27,7 +27,7 @@ func doAccountCmd(cmd *cobra.Command, args []string) {
 	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
 
 	res, err := client.Call("pando.GetAccount", rpc.GetAccountArgs{
-		Address: addressFlag, Preview: previewFlag})
+		Address: addressFlag, Preview: previewFlag, Block: rpc.BlockSpecifier(blockFlag)})
 	if err != nil {
 		utils.Error("Failed to get account details: %v\n", err)
 	}
@@ -44,5 +44,6 @@ func doAccountCmd(cmd *cobra.Command, args []string) {
 func init() {
 	accountCmd.Flags().StringVar(&addressFlag, "address", "", "Address of the account")
 	accountCmd.Flags().BoolVar(&previewFlag, "preview", false, "Preview account balance from the screened view")
+	accountCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
 	accountCmd.MarkFlagRequired("address")
 }
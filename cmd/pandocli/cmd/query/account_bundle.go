@@ -0,0 +1,48 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// accountBundleCmd represents the account bundle command.
+// Example:
+//		pandocli query account_bundle --address=0xdf1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E
+var accountBundleCmd = &cobra.Command{
+	Use:     "account_bundle",
+	Short:   "Get the balance, stakes, reserve funds and split rules of an address",
+	Long:    `Get the balance, sequence, stakes, reserve funds and split rules of an address, all from the state of the same block.`,
+	Example: `pandocli query account_bundle --address=0xdf1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E`,
+	Run:     doAccountBundleCmd,
+}
+
+func doAccountBundleCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.GetAccountBundle", rpc.GetAccountBundleArgs{
+		Address: addressFlag, Block: rpc.BlockSpecifier(blockFlag)})
+	if err != nil {
+		utils.Error("Failed to get account bundle: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get account bundle: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	accountBundleCmd.Flags().StringVar(&addressFlag, "address", "", "Address of the account")
+	accountBundleCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+	accountBundleCmd.MarkFlagRequired("address")
+}
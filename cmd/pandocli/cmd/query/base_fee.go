@@ -0,0 +1,46 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// baseFeeCmd represents the base_fee command.
+// Example:
+//		pandocli query base_fee
+var baseFeeCmd = &cobra.Command{
+	Use:     "base_fee",
+	Short:   "Get the base fee and the suggested fee of the next block",
+	Example: `pandocli query base_fee`,
+	Run:     doBaseFeeCmd,
+}
+
+func doBaseFeeCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.GetBaseFee", rpc.GetBaseFeeArgs{
+		Block: rpc.BlockSpecifier(blockFlag),
+	})
+	if err != nil {
+		utils.Error("Failed to get base fee: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get base fee: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	baseFeeCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending (default to pending)")
+}
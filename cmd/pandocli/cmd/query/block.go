@@ -19,6 +19,7 @@ import (
 // Example:
 //		pandocli query block --height=300
 //		pandocli query block --hash=0xc88485a473527c55c5ddb067b018324b7e390b188e76702bc1db74dfc2dc6d13
+//		pandocli query block --block=finalized
 //
 var blockCmd = &cobra.Command{
 	Use:     "block",
@@ -34,6 +35,10 @@ var blockCmd = &cobra.Command{
 			res, err = client.Call("pando.GetBlock", rpc.GetBlockArgs{
 				Hash: common.HexToHash(hashFlag),
 			})
+		} else if len(blockFlag) != 0 {
+			res, err = client.Call("pando.GetBlock", rpc.GetBlockArgs{
+				Block: rpc.BlockSpecifier(blockFlag),
+			})
 		} else if endFlag != 0 {
 			res, err = client.Call("pando.GetBlocksByRange", rpc.GetBlocksByRangeArgs{
 				Start: common.JSONUint64(startFlag),
@@ -64,4 +69,5 @@ func init() {
 	blockCmd.Flags().Uint64Var(&heightFlag, "height", uint64(0), "height of the block")
 	blockCmd.Flags().Uint64Var(&startFlag, "start", uint64(0), "starting height of the blocks")
 	blockCmd.Flags().Uint64Var(&endFlag, "end", uint64(0), "ending height of the blocks")
+	blockCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
 }
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+var (
+	bridgeNonceFlag uint64
+	bridgeLimitFlag uint64
+)
+
+// bridgeCmd represents the bridge command.
+// Example:
+//
+//	pandocli query bridge --start=1 --limit=20
+//	pandocli query bridge --nonce=12
+var bridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Get the outbound bridge messages",
+	Long: `Get the outbound bridge messages, starting from the --start nonce. With --nonce, get the proof of the
+message against the state root of a finalized block instead, for relaying it to the foreign chain.`,
+	Example: `pandocli query bridge --start=1 --limit=20`,
+	Run:     doBridgeCmd,
+}
+
+// bridgeValidatorsCmd represents the bridge validators command.
+// Example:
+//
+//	pandocli query bridge_validators
+var bridgeValidatorsCmd = &cobra.Command{
+	Use:     "bridge_validators",
+	Short:   "Get the bridge validators and the attestation threshold",
+	Example: `pandocli query bridge_validators`,
+	Run:     doBridgeValidatorsCmd,
+}
+
+func doBridgeCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	var res *rpcc.RPCResponse
+	var err error
+	if bridgeNonceFlag != 0 {
+		res, err = client.Call("pando.GetBridgeMessageProof", rpc.GetBridgeMessageProofArgs{
+			Nonce:  common.JSONUint64(bridgeNonceFlag),
+			Height: common.JSONUint64(heightFlag),
+		})
+	} else {
+		res, err = client.Call("pando.GetBridgeMessages", rpc.GetBridgeMessagesArgs{
+			Start: common.JSONUint64(startFlag),
+			Limit: common.JSONUint64(bridgeLimitFlag),
+			Block: rpc.BlockSpecifier(blockFlag),
+		})
+	}
+	if err != nil {
+		utils.Error("Failed to get bridge messages: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get bridge messages: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func doBridgeValidatorsCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.GetBridgeValidators", rpc.GetBridgeValidatorsArgs{Block: rpc.BlockSpecifier(blockFlag)})
+	if err != nil {
+		utils.Error("Failed to get bridge validators: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get bridge validators: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	bridgeCmd.Flags().Uint64Var(&startFlag, "start", 1, "Nonce of the first message")
+	bridgeCmd.Flags().Uint64Var(&bridgeLimitFlag, "limit", 20, "Maximum number of messages")
+	bridgeCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+	bridgeCmd.Flags().Uint64Var(&bridgeNonceFlag, "nonce", 0, "Nonce of the message to prove")
+	bridgeCmd.Flags().Uint64Var(&heightFlag, "height", 0, "Height of the block to prove the message at (default to the latest provable block)")
+
+	bridgeValidatorsCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+}
@@ -0,0 +1,115 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+var (
+	storageStartFlag string
+	storageLimitFlag uint64
+	storageProveFlag bool
+	storageDumpFlag  bool
+)
+
+// contractStorageCmd represents the contract_storage command.
+// Example:
+//		pandocli query contract_storage --address=0x5d3d5ebd5f2d7e5b1f9c4d2b9e5a1b0c6f1e2d3a
+//		pandocli query contract_storage --address=0x5d3d5ebd5f2d7e5b1f9c4d2b9e5a1b0c6f1e2d3a --dump > storage.json
+var contractStorageCmd = &cobra.Command{
+	Use:   "contract_storage",
+	Short: "Get the storage slots of a smart contract",
+	Long: `Get the storage slots of a smart contract, one page at a time. With --dump, all the pages are fetched
+at the same block and the full storage of the contract is printed as a JSON object.`,
+	Example: `pandocli query contract_storage --address=0x5d3d5ebd5f2d7e5b1f9c4d2b9e5a1b0c6f1e2d3a --limit=10 --prove`,
+	Run:     doContractStorageCmd,
+}
+
+// contractStorageDump is the full storage of a contract at a block
+type contractStorageDump struct {
+	Address     string            `json:"address"`
+	BlockHeight string            `json:"block_height"`
+	StateRoot   string            `json:"state_root"`
+	StorageRoot string            `json:"storage_root"`
+	Storage     map[string]string `json:"storage"` // map: slot |-> value
+}
+
+func doContractStorageCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	if !storageDumpFlag {
+		res, err := client.Call("pando.GetContractStorage", rpc.GetContractStorageArgs{
+			Address: addressFlag, Block: rpc.BlockSpecifier(blockFlag), Start: storageStartFlag,
+			Limit: storageLimitFlag, Prove: storageProveFlag})
+		if err != nil {
+			utils.Error("Failed to get contract storage: %v\n", err)
+		}
+		if res.Error != nil {
+			utils.Error("Failed to get contract storage: %v\n", res.Error)
+		}
+		json, err := json.MarshalIndent(res.Result, "", "    ")
+		if err != nil {
+			utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+		}
+		fmt.Println(string(json))
+		return
+	}
+
+	dump := &contractStorageDump{Storage: make(map[string]string)}
+	block := rpc.BlockSpecifier(blockFlag)
+	start := storageStartFlag
+	for {
+		res, err := client.Call("pando.GetContractStorage", rpc.GetContractStorageArgs{
+			Address: addressFlag, Block: block, Start: start, Limit: storageLimitFlag})
+		if err != nil {
+			utils.Error("Failed to get contract storage: %v\n", err)
+		}
+		if res.Error != nil {
+			utils.Error("Failed to get contract storage: %v\n", res.Error)
+		}
+		page := &rpc.GetContractStorageResult{}
+		if err := res.GetObject(page); err != nil {
+			utils.Error("Failed to parse server response: %v\n", err)
+		}
+
+		// The following pages are fetched at the block of the first page
+		if dump.Address == "" {
+			dump.Address = page.Address
+			dump.BlockHeight = strconv.FormatUint(uint64(page.BlockHeight), 10)
+			dump.StateRoot = page.StateRoot.Hex()
+			dump.StorageRoot = page.StorageRoot.Hex()
+			block = rpc.BlockSpecifier(dump.BlockHeight)
+		}
+		for _, slot := range page.Slots {
+			dump.Storage[slot.Slot.Hex()] = slot.Value.Hex()
+		}
+		if page.Next == nil {
+			break
+		}
+		start = page.Next.Hex()
+	}
+
+	json, err := json.MarshalIndent(dump, "", "    ")
+	if err != nil {
+		utils.Error("Failed to encode the contract storage: %v\n", err)
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	contractStorageCmd.Flags().StringVar(&addressFlag, "address", "", "Address of the contract")
+	contractStorageCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+	contractStorageCmd.Flags().StringVar(&storageStartFlag, "start", "", "Storage slot to start from")
+	contractStorageCmd.Flags().Uint64Var(&storageLimitFlag, "limit", 100, "Maximum number of slots per page")
+	contractStorageCmd.Flags().BoolVar(&storageProveFlag, "prove", false, "Include the Merkle proofs of the account and the slots")
+	contractStorageCmd.Flags().BoolVar(&storageDumpFlag, "dump", false, "Export the full storage of the contract as JSON")
+	contractStorageCmd.MarkFlagRequired("address")
+}
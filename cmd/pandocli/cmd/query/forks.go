@@ -0,0 +1,43 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// forksCmd represents the forks command.
+// Example:
+//		pandocli query forks
+var forksCmd = &cobra.Command{
+	Use:     "forks",
+	Short:   "Get the fork schedule of the node",
+	Long:    `Get the activation heights of the forks in effect on the node, and whether they are enabled.`,
+	Example: `pandocli query forks`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+		res, err := client.Call("pando.GetForkSchedule", rpc.GetForkScheduleArgs{Block: rpc.BlockSpecifier(blockFlag)})
+		if err != nil {
+			utils.Error("Failed to get fork schedule: %v\n", err)
+		}
+		if res.Error != nil {
+			utils.Error("Failed to retrieve fork schedule: %v\n", res.Error)
+		}
+		json, err := json.MarshalIndent(res.Result, "", "    ")
+		if err != nil {
+			utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+		}
+		fmt.Println(string(json))
+	},
+}
+
+func init() {
+	forksCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// governanceCmd represents the governance command.
+// Example:
+//		pandocli query governance
+var governanceCmd = &cobra.Command{
+	Use:     "governance",
+	Short:   "Get the pending governance proposals",
+	Example: `pandocli query governance`,
+	Run:     doGovernanceCmd,
+}
+
+func doGovernanceCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.GetGovernanceProposals", rpc.GetGovernanceProposalsArgs{Block: rpc.BlockSpecifier(blockFlag)})
+	if err != nil {
+		utils.Error("Failed to get governance proposals: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get governance proposals: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	governanceCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+}
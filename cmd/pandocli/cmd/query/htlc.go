@@ -0,0 +1,48 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+var htlcIDFlag string
+
+// htlcCmd represents the htlc command.
+// Example:
+//		pandocli query htlc --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d
+var htlcCmd = &cobra.Command{
+	Use:     "htlc",
+	Short:   "Get the status of an HTLC",
+	Example: `pandocli query htlc --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d`,
+	Run:     doHTLCCmd,
+}
+
+func doHTLCCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.GetHTLC", rpc.GetHTLCArgs{ID: htlcIDFlag, Block: rpc.BlockSpecifier(blockFlag)})
+	if err != nil {
+		utils.Error("Failed to get HTLC details: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get HTLC details: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	htlcCmd.Flags().StringVar(&htlcIDFlag, "id", "", "ID of the HTLC")
+	htlcCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+	htlcCmd.MarkFlagRequired("id")
+}
@@ -12,6 +12,7 @@ var (
 	hashFlag       string
 	startFlag      uint64
 	endFlag        uint64
+	blockFlag      string
 )
 
 // QueryCmd represents the query command
@@ -23,12 +24,24 @@ var QueryCmd = &cobra.Command{
 func init() {
 	QueryCmd.AddCommand(statusCmd)
 	QueryCmd.AddCommand(accountCmd)
+	QueryCmd.AddCommand(accountBundleCmd)
+	QueryCmd.AddCommand(stakeCmd)
 	QueryCmd.AddCommand(guardianCmd)
 	QueryCmd.AddCommand(blockCmd)
 	QueryCmd.AddCommand(txCmd)
 	QueryCmd.AddCommand(splitRuleCmd)
+	QueryCmd.AddCommand(splitRulesCmd)
+	QueryCmd.AddCommand(htlcCmd)
+	QueryCmd.AddCommand(bridgeCmd)
+	QueryCmd.AddCommand(bridgeValidatorsCmd)
+	QueryCmd.AddCommand(subchainCmd)
+	QueryCmd.AddCommand(governanceCmd)
+	QueryCmd.AddCommand(baseFeeCmd)
 	QueryCmd.AddCommand(vcpCmd)
 	QueryCmd.AddCommand(gcpCmd)
 	QueryCmd.AddCommand(peersCmd)
+	QueryCmd.AddCommand(networkVersionsCmd)
+	QueryCmd.AddCommand(forksCmd)
+	QueryCmd.AddCommand(contractStorageCmd)
 	QueryCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// networkVersionsCmd represents the network_versions command.
+// Example:
+//		pandocli query network_versions
+var networkVersionsCmd = &cobra.Command{
+	Use:     "network_versions",
+	Short:   "Get the versions and forks of the peers",
+	Long:    `Get the binary versions and the forks advertised by the peers, and the upcoming forks the binary of the node does not support.`,
+	Example: `pandocli query network_versions`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+		res, err := client.Call("pando.GetNetworkVersions", rpc.GetNetworkVersionsArgs{})
+		if err != nil {
+			utils.Error("Failed to get network versions: %v\n", err)
+		}
+		if res.Error != nil {
+			utils.Error("Failed to retrieve network versions: %v\n", res.Error)
+		}
+		json, err := json.MarshalIndent(res.Result, "", "    ")
+		if err != nil {
+			utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+		}
+		fmt.Println(string(json))
+	},
+}
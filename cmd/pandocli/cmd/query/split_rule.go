@@ -26,7 +26,7 @@ func doSplitRuleCmd(cmd *cobra.Command, args []string) {
 	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
 
 	resourceID := resourceIDFlag
-	res, err := client.Call("pando.GetSplitRule", rpc.GetSplitRuleArgs{ResourceID: resourceID})
+	res, err := client.Call("pando.GetSplitRule", rpc.GetSplitRuleArgs{ResourceID: resourceID, Block: rpc.BlockSpecifier(blockFlag)})
 	if err != nil {
 		utils.Error("Failed to get split rule details: %v\n", err)
 	}
@@ -42,5 +42,6 @@ func doSplitRuleCmd(cmd *cobra.Command, args []string) {
 
 func init() {
 	splitRuleCmd.Flags().StringVar(&resourceIDFlag, "resource_id", "", "Resource ID of the contract")
+	splitRuleCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
 	splitRuleCmd.MarkFlagRequired("resource_id")
 }
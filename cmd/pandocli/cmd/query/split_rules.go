@@ -0,0 +1,50 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// splitRulesCmd represents the split_rules command.
+// Example:
+//		pandocli query split_rules --initiator=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var splitRulesCmd = &cobra.Command{
+	Use:     "split_rules",
+	Short:   "Get the active split rules and their remaining durations",
+	Example: `pandocli query split_rules --initiator=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doSplitRulesCmd,
+}
+
+func doSplitRulesCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.GetSplitRules", rpc.GetSplitRulesArgs{
+		ResourceID: resourceIDFlag,
+		Initiator:  addressFlag,
+		Block:      rpc.BlockSpecifier(blockFlag),
+	})
+	if err != nil {
+		utils.Error("Failed to get split rules: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get split rules: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	splitRulesCmd.Flags().StringVar(&resourceIDFlag, "resource_id", "", "Resource ID of the split rule")
+	splitRulesCmd.Flags().StringVar(&addressFlag, "initiator", "", "Address of the initiator of the split rules")
+	splitRulesCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// stakeCmd represents the stake command, which shows the pending stake withdrawals of an address.
+// Example:
+//		pandocli query stake --address=0xdf1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E
+var stakeCmd = &cobra.Command{
+	Use:     "stake",
+	Short:   "Get pending stake withdrawals",
+	Long:    `Get pending stake withdrawals, including the release height and the estimated remaining time.`,
+	Example: `pandocli query stake --address=0xdf1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E`,
+	Run:     doStakeCmd,
+}
+
+func doStakeCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.GetStakeWithdrawalStatus", rpc.GetStakeWithdrawalStatusArgs{
+		Address: addressFlag, Block: rpc.BlockSpecifier(blockFlag)})
+	if err != nil {
+		utils.Error("Failed to get stake withdrawal status: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get stake withdrawal status: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	stakeCmd.Flags().StringVar(&addressFlag, "address", "", "Source address of the stakes")
+	stakeCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+	stakeCmd.MarkFlagRequired("address")
+}
@@ -0,0 +1,65 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+var (
+	subchainIDFlag      string
+	subchainChainIDFlag string
+)
+
+// subchainCmd represents the subchain command.
+// Example:
+//
+//	pandocli query subchain --chain_id=gamechain
+//	pandocli query subchain
+var subchainCmd = &cobra.Command{
+	Use:   "subchain",
+	Short: "Get the registered subchains",
+	Long: `Get a subchain by --id or --chain_id, with its validator stakes and its pending and finalized checkpoints.
+Without either, list all the registered subchains.`,
+	Example: `pandocli query subchain --chain_id=gamechain`,
+	Run:     doSubchainCmd,
+}
+
+func doSubchainCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	var res *rpcc.RPCResponse
+	var err error
+	if subchainIDFlag != "" || subchainChainIDFlag != "" {
+		res, err = client.Call("pando.GetSubchain", rpc.GetSubchainArgs{
+			ID:      subchainIDFlag,
+			ChainID: subchainChainIDFlag,
+			Block:   rpc.BlockSpecifier(blockFlag),
+		})
+	} else {
+		res, err = client.Call("pando.GetSubchains", rpc.GetSubchainsArgs{Block: rpc.BlockSpecifier(blockFlag)})
+	}
+	if err != nil {
+		utils.Error("Failed to get subchains: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get subchains: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	subchainCmd.Flags().StringVar(&subchainIDFlag, "id", "", "ID of the subchain")
+	subchainCmd.Flags().StringVar(&subchainChainIDFlag, "chain_id", "", "Chain ID of the subchain")
+	subchainCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
+}
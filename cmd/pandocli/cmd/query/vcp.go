@@ -27,7 +27,10 @@ func doVcpCmd(cmd *cobra.Command, args []string) {
 	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
 
 	height := heightFlag
-	res, err := client.Call("pando.GetVcpByHeight", rpc.GetVcpByHeightArgs{Height: common.JSONUint64(height)})
+	res, err := client.Call("pando.GetVcpByHeight", rpc.GetVcpByHeightArgs{
+		Height: common.JSONUint64(height),
+		Block:  rpc.BlockSpecifier(blockFlag),
+	})
 	if err != nil {
 		utils.Error("Failed to get validator candidate pool: %v\n", err)
 	}
@@ -43,5 +46,5 @@ func doVcpCmd(cmd *cobra.Command, args []string) {
 
 func init() {
 	vcpCmd.Flags().Uint64Var(&heightFlag, "height", uint64(0), "height of the block")
-	vcpCmd.MarkFlagRequired("height")
+	vcpCmd.Flags().StringVar(&blockFlag, "block", "", "Block height, hash, latest, finalized or pending")
 }
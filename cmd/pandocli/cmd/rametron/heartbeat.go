@@ -0,0 +1,98 @@
+package rametron
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/tx"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// heartbeatCmd signs the heartbeat of a Rametron node for the current epoch, and sends it to the guardians.
+// Example:
+//		pandocli rametron heartbeat --node=2E833968E5bB786Ae419c4d13189fB081Cc43bab --guardians=http://guardian1:16888/rpc,http://guardian2:16888/rpc
+var heartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Send the heartbeat of a Rametron node to the guardians",
+	Long: fmt.Sprintf(`Sign the heartbeat of a Rametron node for the current reward epoch, and send it to the guardians.
+The node is active in an epoch once %v guardians attested its heartbeat on chain, so the heartbeat
+needs to be sent to several guardians in every epoch, e.g. from a cron job.`, types.MinRametronAttesters),
+	Example: `pandocli rametron heartbeat --node=2E833968E5bB786Ae419c4d13189fB081Cc43bab --guardians=http://guardian1:16888/rpc,http://guardian2:16888/rpc`,
+	Run:     doHeartbeatCmd,
+}
+
+func doHeartbeatCmd(cmd *cobra.Command, args []string) {
+	guardians := guardiansFlag
+	if len(guardians) == 0 {
+		guardians = []string{viper.GetString(utils.CfgRemoteRPCEndpoint)}
+	}
+
+	wallet, nodeAddress, err := tx.WalletUnlockWithPath(cmd, nodeFlag, pathFlag)
+	if err != nil || wallet == nil {
+		utils.Error("Failed to unlock wallet\n")
+	}
+	defer wallet.Lock(nodeAddress)
+
+	// The guardians may be a block apart at the end of an epoch, so the heartbeat is signed for
+	// the current epoch of each guardian
+	signatures := map[string]*crypto.Signature{}
+	attested := 0
+	for _, guardian := range guardians {
+		client := rpcc.NewRPCClient(guardian)
+		res, err := client.Call("pando.GetStatus", rpc.GetStatusArgs{})
+		if err == nil && res.Error != nil {
+			err = res.Error
+		}
+		if err != nil {
+			fmt.Printf("Failed to get the status of %v: %v\n", guardian, err)
+			continue
+		}
+		status := &rpc.GetStatusResult{}
+		if err := res.GetObject(status); err != nil {
+			fmt.Printf("Failed to parse the status of %v: %v\n", guardian, err)
+			continue
+		}
+
+		heartbeat := &types.RametronHeartbeat{
+			Node:  nodeAddress,
+			Epoch: types.RametronEpoch(uint64(status.CurrentHeight) + 1),
+		}
+		signBytes := heartbeat.SignBytes(status.ChainID)
+		key := string(signBytes)
+		if heartbeat.Signature = signatures[key]; heartbeat.Signature == nil {
+			heartbeat.Signature, err = wallet.Sign(nodeAddress, signBytes)
+			if err != nil {
+				utils.Error("Failed to sign the heartbeat: %v\n", err)
+			}
+			signatures[key] = heartbeat.Signature
+		}
+
+		res, err = client.Call("pando.SubmitRametronHeartbeat", rpc.SubmitRametronHeartbeatArgs{Heartbeat: heartbeat})
+		if err == nil && res.Error != nil {
+			err = res.Error
+		}
+		if err != nil {
+			fmt.Printf("Failed to send the heartbeat to %v: %v\n", guardian, err)
+			continue
+		}
+		fmt.Printf("Sent the heartbeat of epoch %v to %v\n", heartbeat.Epoch, guardian)
+		attested++
+	}
+
+	if attested < types.MinRametronAttesters {
+		utils.Error("The heartbeat was sent to %v guardians, but %v guardians need to attest it\n", attested, types.MinRametronAttesters)
+	}
+}
+
+func init() {
+	heartbeatCmd.Flags().StringVar(&nodeFlag, "node", "", "Address of the Rametron node")
+	heartbeatCmd.Flags().StringSliceVar(&guardiansFlag, "guardians", []string{}, "RPC endpoints of the guardians (default to the remote RPC endpoint)")
+	heartbeatCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	heartbeatCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+}
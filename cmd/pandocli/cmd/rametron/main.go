@@ -0,0 +1,22 @@
+package rametron
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeFlag      string
+	guardiansFlag []string
+	walletFlag    string
+	pathFlag      string
+)
+
+// RametronCmd represents the rametron command
+var RametronCmd = &cobra.Command{
+	Use:   "rametron",
+	Short: "Operate a Rametron node",
+}
+
+func init() {
+	RametronCmd.AddCommand(heartbeatCmd)
+}
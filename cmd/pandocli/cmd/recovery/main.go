@@ -0,0 +1,33 @@
+package recovery
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	heightFlag   uint64
+	reasonFlag   string
+	exportFlag   string
+	manifestFlag string
+	outputFlag   string
+	snapshotFlag string
+	fromFlag     string
+	walletFlag   string
+	pathFlag     string
+)
+
+// RecoveryCmd represents the recovery command
+var RecoveryCmd = &cobra.Command{
+	Use:   "recovery",
+	Short: "Coordinate the restart of a halted chain",
+	Long: `Coordinate the restart of a halted chain. A restart manifest designates the finalized block to
+restart from, and is signed by the validators. Once more than 2/3 of the stake signed it, the nodes
+are restarted with "pando start --restart_manifest", which verifies the manifest and disposes the
+blocks built on top of the restart block.`,
+}
+
+func init() {
+	RecoveryCmd.AddCommand(proposeCmd)
+	RecoveryCmd.AddCommand(signCmd)
+	RecoveryCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,64 @@
+package recovery
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// proposeCmd creates an unsigned restart manifest at a finalized block of the node.
+// Example:
+//		pandocli recovery propose --height=1000001 --reason="halted at 1000205" --export=/home/pando/.pando --output=restart_manifest.json
+var proposeCmd = &cobra.Command{
+	Use:   "propose",
+	Short: "Create a restart manifest at a finalized block",
+	Long: `Create an unsigned restart manifest at a finalized block of the node, by default its last finalized
+block. With --export, the node also exports the snapshot at the block to the backup/snapshot dir of
+the given config dir, for the nodes which do not have the block, and the manifest references the
+hash of the snapshot.`,
+	Example: `pandocli recovery propose --height=1000001 --reason="halted at 1000205" --export=/home/pando/.pando --output=restart_manifest.json`,
+	Run:     doProposeCmd,
+}
+
+func doProposeCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	res, err := client.Call("pando.ProposeRestartManifest", rpc.ProposeRestartManifestArgs{
+		Height:         common.JSONUint64(heightFlag),
+		Reason:         reasonFlag,
+		ExportSnapshot: exportFlag != "",
+		Config:         exportFlag,
+	})
+	if err != nil {
+		utils.Error("Failed to propose restart manifest: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to propose restart manifest: %v\n", res.Error)
+	}
+	result := &rpc.ProposeRestartManifestResult{}
+	if err := res.GetObject(result); err != nil || result.Manifest == nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+
+	if err := core.WriteRestartManifest(outputFlag, result.Manifest); err != nil {
+		utils.Error("Failed to write %v: %v\n", outputFlag, err)
+	}
+	fmt.Printf("Restart manifest written to %v\n%v\n", outputFlag, result.Manifest)
+	if result.SnapshotFile != "" {
+		fmt.Printf("Snapshot exported to %v\n", result.SnapshotFile)
+	}
+}
+
+func init() {
+	proposeCmd.Flags().Uint64Var(&heightFlag, "height", 0, "Height of the finalized block to restart from (default to the last finalized block)")
+	proposeCmd.Flags().StringVar(&reasonFlag, "reason", "", "Reason of the restart")
+	proposeCmd.Flags().StringVar(&exportFlag, "export", "", "Config dir of the node to export the snapshot at the block to (no snapshot if empty)")
+	proposeCmd.Flags().StringVar(&outputFlag, "output", "restart_manifest.json", "Output file of the manifest")
+	proposeCmd.MarkFlagRequired("reason")
+}
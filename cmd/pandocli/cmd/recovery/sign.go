@@ -0,0 +1,58 @@
+package recovery
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/tx"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/core"
+
+	"github.com/spf13/cobra"
+)
+
+// signCmd adds the signature of a validator to a restart manifest.
+// Example:
+//		pandocli recovery sign --manifest=restart_manifest.json --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var signCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Sign a restart manifest",
+	Long: `Sign a restart manifest with the key of a validator. The signature is added to the manifest file,
+replacing the previous signature of the validator if any. Check the block and the state hash of the
+manifest against your own node before signing.`,
+	Example: `pandocli recovery sign --manifest=restart_manifest.json --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doSignCmd,
+}
+
+func doSignCmd(cmd *cobra.Command, args []string) {
+	m, err := core.ReadRestartManifest(manifestFlag)
+	if err != nil {
+		utils.Error("Failed to read %v: %v\n", manifestFlag, err)
+	}
+
+	wallet, address, err := tx.WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		utils.Error("Failed to unlock wallet\n")
+	}
+	defer wallet.Lock(address)
+
+	sig, err := wallet.Sign(address, m.SignBytes())
+	if err != nil {
+		utils.Error("Failed to sign restart manifest: %v\n", err)
+	}
+	if err := m.AddSignature(address, sig); err != nil {
+		utils.Error("Failed to sign restart manifest: %v\n", err)
+	}
+
+	if err := core.WriteRestartManifest(manifestFlag, m); err != nil {
+		utils.Error("Failed to write %v: %v\n", manifestFlag, err)
+	}
+	fmt.Printf("Restart manifest signed by %v\n%v\n", address.Hex(), m)
+}
+
+func init() {
+	signCmd.Flags().StringVar(&manifestFlag, "manifest", "", "Restart manifest file")
+	signCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the validator")
+	signCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	signCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	signCmd.MarkFlagRequired("manifest")
+}
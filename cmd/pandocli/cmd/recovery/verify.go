@@ -0,0 +1,64 @@
+package recovery
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// verifyCmd checks a restart manifest against the chain of the node.
+// Example:
+//		pandocli recovery verify --manifest=restart_manifest.json --snapshot=pando_snapshot-1000001-0x...-2026-10-17
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a restart manifest",
+	Long: `Verify a restart manifest against the chain of the node, and report the stake of the validators
+which signed it and the validators which have not signed yet. With --snapshot, the snapshot file is
+checked against the hash referenced by the manifest.`,
+	Example: `pandocli recovery verify --manifest=restart_manifest.json`,
+	Run:     doVerifyCmd,
+}
+
+func doVerifyCmd(cmd *cobra.Command, args []string) {
+	m, err := core.ReadRestartManifest(manifestFlag)
+	if err != nil {
+		utils.Error("Failed to read %v: %v\n", manifestFlag, err)
+	}
+
+	if snapshotFlag != "" {
+		snapshotHash, err := core.HashSnapshotFile(snapshotFlag)
+		if err != nil {
+			utils.Error("Failed to hash %v: %v\n", snapshotFlag, err)
+		}
+		if snapshotHash != m.SnapshotHash {
+			utils.Error("The snapshot hash %v does not match the manifest, expected %v\n", snapshotHash.Hex(), m.SnapshotHash.Hex())
+		}
+	}
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	res, err := client.Call("pando.VerifyRestartManifest", rpc.VerifyRestartManifestArgs{Manifest: m})
+	if err != nil {
+		utils.Error("Failed to verify restart manifest: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to verify restart manifest: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&manifestFlag, "manifest", "", "Restart manifest file")
+	verifyCmd.Flags().StringVar(&snapshotFlag, "snapshot", "", "Snapshot file to check against the manifest")
+	verifyCmd.MarkFlagRequired("manifest")
+}
@@ -6,16 +6,23 @@ import (
 	"path"
 	"strings"
 
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/analyze"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/backup"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/call"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/checkpoint"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/contract"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/daemon"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/genesis"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/key"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/query"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/rametron"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/recovery"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/tx"
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 )
 
 var cfgPath string
@@ -40,13 +47,21 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	RootCmd.PersistentFlags().StringVar(&cfgPath, "config", getDefaultConfigPath(), fmt.Sprintf("config path (default is %s)", getDefaultConfigPath()))
+	RootCmd.PersistentFlags().String("network", "", "Network to connect to (mainnet|testnet|devnet), which sets the chain ID and the default RPC endpoint")
+	viper.BindPFlag(utils.CfgNetwork, RootCmd.PersistentFlags().Lookup("network"))
 
 	RootCmd.AddCommand(daemon.DaemonCmd)
 	RootCmd.AddCommand(key.KeyCmd)
 	RootCmd.AddCommand(tx.TxCmd)
 	RootCmd.AddCommand(query.QueryCmd)
 	RootCmd.AddCommand(call.CallCmd)
+	RootCmd.AddCommand(contract.ContractCmd)
 	RootCmd.AddCommand(backup.BackupCmd)
+	RootCmd.AddCommand(analyze.AnalyzeCmd)
+	RootCmd.AddCommand(recovery.RecoveryCmd)
+	RootCmd.AddCommand(rametron.RametronCmd)
+	RootCmd.AddCommand(checkpoint.CheckpointCmd)
+	RootCmd.AddCommand(genesis.GenesisCmd)
 	RootCmd.AddCommand(versionCmd)
 }
 
@@ -64,6 +79,8 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
+
+	utils.ApplyNetworkDefaults()
 }
 
 func getDefaultConfigPath() string {
@@ -0,0 +1,173 @@
+package tx
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/cobra"
+)
+
+// batchSendCmd represents the batch send command. The outputs file is a CSV file with
+// one output per line in the format of "address,pando,ptx[,memo]"
+// Example:
+//		pandocli tx batch_send --chain="pandonet" --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --outputs=payroll.csv --seq=1
+var batchSendCmd = &cobra.Command{
+	Use:     "batch_send",
+	Short:   "Send tokens to multiple addresses in a single transaction",
+	Example: `pandocli tx batch_send --chain="pandonet" --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --outputs=payroll.csv --seq=1`,
+	Run:     doBatchSendCmd,
+}
+
+func doBatchSendCmd(cmd *cobra.Command, args []string) {
+	walletType := getWalletType(cmd)
+	if walletType == wtypes.WalletTypeSoft && len(fromFlag) == 0 {
+		utils.Error("The from address cannot be empty") // we don't need to specify the "from address" for hardware wallets
+		return
+	}
+
+	outputs, err := readBatchOutputs(outputsFileFlag)
+	if err != nil {
+		utils.Error("Failed to read outputs: %v\n", err)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	batchSendTx := &types.BatchSendTx{
+		Outputs:         outputs,
+		ValidUntilBlock: validUntilFlag,
+	}
+	fee := batchSendTx.MinimumFee()
+	if cmd.Flags().Changed("fee") {
+		var ok bool
+		fee, ok = types.ParseCoinAmount(feeFlag)
+		if !ok {
+			utils.Error("Failed to parse fee")
+		}
+	}
+	batchSendTx.Fee = types.Coins{
+		PandoWei: new(big.Int).SetUint64(0),
+		PTXWei:   fee,
+	}
+
+	total := types.NewCoins(0, 0)
+	for _, out := range outputs {
+		total = total.Plus(out.Coins)
+	}
+	batchSendTx.Inputs = []types.TxInput{{
+		Address:  fromAddress,
+		Coins:    total.Plus(batchSendTx.Fee),
+		Sequence: uint64(seqFlag),
+	}}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			batchSendTx.Inputs[0].Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, batchSendTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		batchSendTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(batchSendTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction with %v outputs:\n%s\n", len(outputs), formatted)
+}
+
+// readBatchOutputs parses the outputs CSV file
+func readBatchOutputs(path string) ([]types.BatchOutput, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	outputs := []types.BatchOutput{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 || len(record) > 4 {
+			return nil, fmt.Errorf("line %v: expected address,pando,ptx[,memo], got %v fields", line, len(record))
+		}
+		address := strings.TrimSpace(record[0])
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("line %v: invalid address %v", line, address)
+		}
+		pando, ok := types.ParseCoinAmount(strings.TrimSpace(record[1]))
+		if !ok {
+			return nil, fmt.Errorf("line %v: failed to parse pando amount", line)
+		}
+		ptx, ok := types.ParseCoinAmount(strings.TrimSpace(record[2]))
+		if !ok {
+			return nil, fmt.Errorf("line %v: failed to parse ptx amount", line)
+		}
+		out := types.BatchOutput{
+			Address: common.HexToAddress(address),
+			Coins: types.Coins{
+				PandoWei: pando,
+				PTXWei:   ptx,
+			},
+		}
+		if len(record) == 4 {
+			out.Memo = record[3]
+		}
+		if len(out.Memo) > types.MaximumBatchSendTxMemoLength {
+			return nil, fmt.Errorf("line %v: memo longer than %v bytes", line, types.MaximumBatchSendTxMemoLength)
+		}
+		outputs = append(outputs, out)
+	}
+
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("no outputs in %v", path)
+	}
+	if len(outputs) > types.MaximumBatchSendTxOutputs {
+		return nil, fmt.Errorf("too many outputs, at most %v are allowed", types.MaximumBatchSendTxOutputs)
+	}
+	return outputs, nil
+}
+
+func init() {
+	batchSendCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	batchSendCmd.Flags().StringVar(&fromFlag, "from", "", "Address to send from")
+	batchSendCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	batchSendCmd.Flags().StringVar(&outputsFileFlag, "outputs", "", "CSV file of the outputs, one \"address,pando,ptx[,memo]\" per line")
+	batchSendCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	batchSendCmd.Flags().StringVar(&feeFlag, "fee", "", "Fee (default to the minimum fee for the number of outputs)")
+	batchSendCmd.Flags().Uint64Var(&validUntilFlag, "valid_until", 0, "The tx is rejected after this block height (0 for no expiry)")
+	batchSendCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	batchSendCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	batchSendCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	batchSendCmd.MarkFlagRequired("outputs")
+	batchSendCmd.MarkFlagRequired("seq")
+}
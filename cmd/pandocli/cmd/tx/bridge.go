@@ -0,0 +1,306 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bridgeChainFlag     string
+	bridgeTokenFlag     string
+	bridgeAmountFlag    string
+	bridgeProofFileFlag string
+)
+
+// bridgeLockCmd represents the bridge lock command
+// Example:
+//
+//	pandocli tx bridge_lock --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --dest_chain=ethereum --to=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --ptx=10 --seq=8
+var bridgeLockCmd = &cobra.Command{
+	Use:   "bridge_lock",
+	Short: "Lock coins in the bridge to transfer them to a foreign chain",
+	Long: `Lock coins in the custody of the bridge, and record an outbound bridge message for the relayers, which
+mint the wrapped coins for the recipient on the foreign chain. The message and its proof can be queried
+with "pandocli query bridge".`,
+	Example: `pandocli tx bridge_lock --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --dest_chain=ethereum --to=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --ptx=10 --seq=8`,
+	Run:     doBridgeLockCmd,
+}
+
+// bridgeBurnCmd represents the bridge burn command
+// Example:
+//
+//	pandocli tx bridge_burn --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --source_chain=ethereum --token=0xdAC17F958D2ee523a2206206994597C13D831ec7 --amount=1000000 --to=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --seq=9
+var bridgeBurnCmd = &cobra.Command{
+	Use:   "bridge_burn",
+	Short: "Burn a wrapped asset to release the original asset on its foreign chain",
+	Long: `Burn a wrapped foreign asset, and record an outbound bridge message for the relayers, which release the
+original asset to the recipient on its foreign chain. Leave --token empty for the native coin of the foreign chain.`,
+	Example: `pandocli tx bridge_burn --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --source_chain=ethereum --token=0xdAC17F958D2ee523a2206206994597C13D831ec7 --amount=1000000 --to=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --seq=9`,
+	Run:     doBridgeBurnCmd,
+}
+
+// bridgeClaimCmd represents the bridge claim command
+// Example:
+//
+//	pandocli tx bridge_claim --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --proof=./event_proof.json --seq=3
+var bridgeClaimCmd = &cobra.Command{
+	Use:   "bridge_claim",
+	Short: "Claim a foreign bridge event attested by the bridge validators",
+	Long: `Submit a foreign bridge event along with the attestations of the bridge validators. Depending on the
+event, the coins are either released from the custody of the bridge, or the wrapped asset is minted for the
+recipient. The proof file holds the JSON encoded event and attestations, as collected by the relayer. The
+fee is paid by the --from account.`,
+	Example: `pandocli tx bridge_claim --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --proof=./event_proof.json --seq=3`,
+	Run:     doBridgeClaimCmd,
+}
+
+func doBridgeLockCmd(cmd *cobra.Command, args []string) {
+	if err := types.CheckBridgeChainID(bridgeChainFlag); err != nil {
+		utils.Error("Invalid input: %v\n", err)
+	}
+	if !common.IsHexAddress(toFlag) {
+		utils.Error("Invalid input: recipient must be an address\n")
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	pando, ok := types.ParseCoinAmount(pandoAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse pando amount")
+	}
+	ptx, ok := types.ParseCoinAmount(ptxAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse ptx amount")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	bridgeLockTx := &types.BridgeLockTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Sender: types.TxInput{
+			Address: fromAddress,
+			Coins: types.Coins{
+				PandoWei: pando,
+				PTXWei:   ptx,
+			},
+			Sequence: uint64(seqFlag),
+		},
+		DestChain: bridgeChainFlag,
+		Recipient: common.HexToAddress(toFlag),
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			bridgeLockTx.Sender.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, bridgeLockTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		bridgeLockTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(bridgeLockTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func doBridgeBurnCmd(cmd *cobra.Command, args []string) {
+	if err := types.CheckBridgeChainID(bridgeChainFlag); err != nil {
+		utils.Error("Invalid input: %v\n", err)
+	}
+	if !common.IsHexAddress(toFlag) {
+		utils.Error("Invalid input: recipient must be an address\n")
+	}
+	var token common.Address
+	if bridgeTokenFlag != "" {
+		if !common.IsHexAddress(bridgeTokenFlag) {
+			utils.Error("Invalid input: token must be an address\n")
+		}
+		token = common.HexToAddress(bridgeTokenFlag)
+	}
+	amount, ok := new(big.Int).SetString(bridgeAmountFlag, 10)
+	if !ok || amount.Sign() <= 0 {
+		utils.Error("Invalid input: amount must be a positive integer\n")
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	bridgeBurnTx := &types.BridgeBurnTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Sender: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		SourceChain: bridgeChainFlag,
+		Token:       token,
+		Amount:      amount,
+		Recipient:   common.HexToAddress(toFlag),
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			bridgeBurnTx.Sender.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, bridgeBurnTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		bridgeBurnTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(bridgeBurnTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func doBridgeClaimCmd(cmd *cobra.Command, args []string) {
+	raw, err := ioutil.ReadFile(bridgeProofFileFlag)
+	if err != nil {
+		utils.Error("Failed to read the proof: %v\n", err)
+	}
+	var proof types.BridgeForeignProof
+	if err := json.Unmarshal(raw, &proof); err != nil {
+		utils.Error("Failed to parse the proof: %v\n", err)
+	}
+	if err := proof.Event.ValidateBasic(); err != nil {
+		utils.Error("Invalid input: %v\n", err)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	bridgeClaimTx := &types.BridgeClaimTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Relayer: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		Proof: proof,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			bridgeClaimTx.Relayer.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, bridgeClaimTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		bridgeClaimTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(bridgeClaimTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	bridgeLockCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	bridgeLockCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the sender")
+	bridgeLockCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	bridgeLockCmd.Flags().StringVar(&bridgeChainFlag, "dest_chain", "", "ID of the foreign chain")
+	bridgeLockCmd.Flags().StringVar(&toFlag, "to", "", "Address of the recipient on the foreign chain")
+	bridgeLockCmd.Flags().StringVar(&pandoAmountFlag, "pando", "0", "Pando amount to lock")
+	bridgeLockCmd.Flags().StringVar(&ptxAmountFlag, "ptx", "0", "PTX amount to lock")
+	bridgeLockCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	bridgeLockCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	bridgeLockCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	bridgeLockCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	bridgeLockCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	bridgeLockCmd.MarkFlagRequired("from")
+	bridgeLockCmd.MarkFlagRequired("dest_chain")
+	bridgeLockCmd.MarkFlagRequired("to")
+	bridgeLockCmd.MarkFlagRequired("seq")
+
+	bridgeBurnCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	bridgeBurnCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the holder of the wrapped asset")
+	bridgeBurnCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	bridgeBurnCmd.Flags().StringVar(&bridgeChainFlag, "source_chain", "", "ID of the foreign chain of the wrapped asset")
+	bridgeBurnCmd.Flags().StringVar(&bridgeTokenFlag, "token", "", "Address of the token on the foreign chain (empty for its native coin)")
+	bridgeBurnCmd.Flags().StringVar(&bridgeAmountFlag, "amount", "", "Amount of the wrapped asset to burn, in the smallest unit")
+	bridgeBurnCmd.Flags().StringVar(&toFlag, "to", "", "Address of the recipient on the foreign chain")
+	bridgeBurnCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	bridgeBurnCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	bridgeBurnCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	bridgeBurnCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	bridgeBurnCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	bridgeBurnCmd.MarkFlagRequired("from")
+	bridgeBurnCmd.MarkFlagRequired("source_chain")
+	bridgeBurnCmd.MarkFlagRequired("amount")
+	bridgeBurnCmd.MarkFlagRequired("to")
+	bridgeBurnCmd.MarkFlagRequired("seq")
+
+	bridgeClaimCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	bridgeClaimCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the account submitting the claim")
+	bridgeClaimCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	bridgeClaimCmd.Flags().StringVar(&bridgeProofFileFlag, "proof", "", "JSON file of the foreign event and the attestations of the bridge validators")
+	bridgeClaimCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	bridgeClaimCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	bridgeClaimCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	bridgeClaimCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	bridgeClaimCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	bridgeClaimCmd.MarkFlagRequired("from")
+	bridgeClaimCmd.MarkFlagRequired("proof")
+	bridgeClaimCmd.MarkFlagRequired("seq")
+}
@@ -0,0 +1,159 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var validatorFlag string
+
+// delegateCmd represents the delegate command
+// Example:
+//
+//	pandocli tx delegate --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --validator=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --stake=1000 --seq=7
+var delegateCmd = &cobra.Command{
+	Use:     "delegate",
+	Short:   "Delegate stake to a validator",
+	Example: `pandocli tx delegate --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --validator=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --stake=1000 --seq=7`,
+	Run:     doDelegateCmd,
+}
+
+// undelegateCmd represents the undelegate command
+// Example:
+//
+//	pandocli tx undelegate --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --validator=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --seq=8
+var undelegateCmd = &cobra.Command{
+	Use:     "undelegate",
+	Short:   "Withdraw the stake delegated to a validator",
+	Example: `pandocli tx undelegate --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --validator=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --seq=8`,
+	Run:     doUndelegateCmd,
+}
+
+func doDelegateCmd(cmd *cobra.Command, args []string) {
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+	stake, ok := types.ParseCoinAmount(stakeInPandoFlag)
+	if !ok {
+		utils.Error("Failed to parse stake")
+	}
+	if stake.Cmp(core.Zero) <= 0 {
+		utils.Error("Invalid input: stake must be positive\n")
+	}
+
+	delegateTx := &types.DelegateTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Delegator: types.TxInput{
+			Address: fromAddress,
+			Coins: types.Coins{
+				PandoWei: stake,
+				PTXWei:   new(big.Int).SetUint64(0),
+			},
+			Sequence: uint64(seqFlag),
+		},
+		Validator: common.HexToAddress(validatorFlag),
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			delegateTx.Delegator.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, delegateTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		delegateTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(delegateTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func doUndelegateCmd(cmd *cobra.Command, args []string) {
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	undelegateTx := &types.UndelegateTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Delegator: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		Validator: common.HexToAddress(validatorFlag),
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			undelegateTx.Delegator.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, undelegateTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		undelegateTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(undelegateTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{delegateCmd, undelegateCmd} {
+		c.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+		c.Flags().StringVar(&fromFlag, "from", "", "Address of the delegator")
+		c.Flags().StringVar(&validatorFlag, "validator", "", "Address of the validator")
+		c.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+		c.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+		c.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+		c.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+		c.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+		c.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+		c.MarkFlagRequired("from")
+		c.MarkFlagRequired("validator")
+		c.MarkFlagRequired("seq")
+	}
+	delegateCmd.Flags().StringVar(&stakeInPandoFlag, "stake", "1000", "Pando amount to delegate")
+}
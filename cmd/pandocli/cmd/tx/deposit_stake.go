@@ -32,7 +32,7 @@ var depositStakeCmd = &cobra.Command{
 }
 
 func doDepositStakeCmd(cmd *cobra.Command, args []string) {
-	wallet, sourceAddress, err := walletUnlockWithPath(cmd, sourceFlag, pathFlag)
+	wallet, sourceAddress, err := WalletUnlockWithPath(cmd, sourceFlag, pathFlag)
 	if err != nil {
 		return
 	}
@@ -68,18 +68,19 @@ func doDepositStakeCmd(cmd *cobra.Command, args []string) {
 		Purpose: purposeFlag,
 	}
 
-	// Parse holder flag.
+	// Parse holder flag. A validator may pass the key info printed by "pandocli key bls" in place of
+	// its address, to register the BLS key its votes are aggregated with.
 	var holderAddress common.Address
-	if purposeFlag == core.StakeForValidator {
-		if len(holderFlag) != 40 && len(holderFlag) != 42 {
-			utils.Error("holder must be a valid address")
-		}
+	if strings.HasPrefix(holderFlag, "0x") {
+		holderFlag = holderFlag[2:]
+	}
+	if purposeFlag == core.StakeForValidator && len(holderFlag) == 40 {
 		holderAddress = common.HexToAddress(holderFlag)
 	} else {
-		if strings.HasPrefix(holderFlag, "0x") {
-			holderFlag = holderFlag[2:]
-		}
 		if len(holderFlag) != 458 {
+			if purposeFlag == core.StakeForValidator {
+				utils.Error("holder must be a valid address or validator key info")
+			}
 			utils.Error("Holder must be a valid guardian address")
 		}
 		guardianKeyBytes, err := hex.DecodeString(holderFlag)
@@ -134,7 +135,7 @@ func doDepositStakeCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	depositStakeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	depositStakeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	depositStakeCmd.Flags().StringVar(&sourceFlag, "source", "", "Source of the stake")
 	depositStakeCmd.Flags().StringVar(&holderFlag, "holder", "", "Holder of the stake")
 	depositStakeCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
@@ -144,7 +145,6 @@ func init() {
 	depositStakeCmd.Flags().Uint8Var(&purposeFlag, "purpose", 0, "Purpose of staking")
 	depositStakeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
 
-	depositStakeCmd.MarkFlagRequired("chain")
 	depositStakeCmd.MarkFlagRequired("source")
 	depositStakeCmd.MarkFlagRequired("holder")
 	depositStakeCmd.MarkFlagRequired("seq")
@@ -0,0 +1,178 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clausesFileFlag string
+	clauseIndexFlag uint64
+	preimageFlag    string
+)
+
+// escrowAddressCmd represents the escrow address command. The clauses file is a JSON array
+// of the escrow clauses, e.g.
+//
+//	[{"recipient": "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", "hash_lock": "0x...", "expiry": 20000},
+//	 {"recipient": "0x0d2fD67d573c8ecB4161510fc00754d64B401F86", "time_lock": 20001}]
+//
+// Example:
+//
+//	pandocli tx escrow_address --clauses=htlc.json
+var escrowAddressCmd = &cobra.Command{
+	Use:     "escrow_address",
+	Short:   "Derive the address of an escrow from its clauses",
+	Long:    `Derive the address of an escrow from its clauses. The coins sent to the address can only be claimed with a claim_escrow transaction satisfying one of the clauses.`,
+	Example: `pandocli tx escrow_address --clauses=htlc.json`,
+	Run:     doEscrowAddressCmd,
+}
+
+// claimEscrowCmd represents the claim escrow command
+// Example:
+//
+//	pandocli tx claim_escrow --chain="pandonet" --clauses=htlc.json --clause=0 --preimage=0x736563726574 --ptx=10 --seq=1
+var claimEscrowCmd = &cobra.Command{
+	Use:     "claim_escrow",
+	Short:   "Claim the coins of an escrow by satisfying one of its clauses",
+	Long:    `Claim the coins of an escrow by satisfying one of its clauses. The coins are paid to the recipient of the clause, and the fee is charged to the escrow. If the clause requires signatures, the transaction is signed by the --from signer.`,
+	Example: `pandocli tx claim_escrow --chain="pandonet" --clauses=htlc.json --clause=0 --preimage=0x736563726574 --ptx=10 --seq=1`,
+	Run:     doClaimEscrowCmd,
+}
+
+func doEscrowAddressCmd(cmd *cobra.Command, args []string) {
+	clauses, err := readEscrowClauses(clausesFileFlag)
+	if err != nil {
+		utils.Error("Failed to read clauses: %v\n", err)
+	}
+	fmt.Printf("Escrow address: %v\n", types.EscrowAddress(clauses).Hex())
+}
+
+func doClaimEscrowCmd(cmd *cobra.Command, args []string) {
+	clauses, err := readEscrowClauses(clausesFileFlag)
+	if err != nil {
+		utils.Error("Failed to read clauses: %v\n", err)
+	}
+	if clauseIndexFlag >= uint64(len(clauses)) {
+		utils.Error("Invalid clause index %v, the escrow has %v clauses\n", clauseIndexFlag, len(clauses))
+	}
+
+	pando, ok := types.ParseCoinAmount(pandoAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse pando amount")
+	}
+	ptx, ok := types.ParseCoinAmount(ptxAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse ptx amount")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	escrowAddress := types.EscrowAddress(clauses)
+	claimEscrowTx := &types.ClaimEscrowTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Escrow: types.TxInput{
+			Address: escrowAddress,
+			Coins: types.Coins{
+				PandoWei: pando,
+				PTXWei:   ptx,
+			},
+			Sequence: uint64(seqFlag),
+		},
+		Clauses:     clauses,
+		ClauseIndex: clauseIndexFlag,
+		Preimage:    common.FromHex(preimageFlag),
+	}
+
+	// Only sign if the clause requires signatures
+	sign := func() error { return nil }
+	if clauses[clauseIndexFlag].Threshold > 0 {
+		wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+		if err != nil || wallet == nil {
+			return
+		}
+		defer wallet.Lock(fromAddress)
+
+		sign = func() error {
+			claimEscrowTx.Signatures = nil
+			sig, err := wallet.Sign(fromAddress, claimEscrowTx.SignBytes(chainIDFlag))
+			if err != nil {
+				return fmt.Errorf("Failed to sign transaction: %v", err)
+			}
+			if !claimEscrowTx.SetSignature(fromAddress, sig) {
+				return fmt.Errorf("%v is not a signer of clause %v", fromAddress.Hex(), clauseIndexFlag)
+			}
+			return nil
+		}
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(escrowAddress)
+			if err != nil {
+				return nil, err
+			}
+			claimEscrowTx.Escrow.Sequence = seq
+		}
+		if err := sign(); err != nil {
+			return nil, err
+		}
+		return types.TxToBytes(claimEscrowTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+// readEscrowClauses parses and validates the clauses JSON file
+func readEscrowClauses(path string) ([]types.EscrowClause, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	clauses := []types.EscrowClause{}
+	if err := json.Unmarshal(raw, &clauses); err != nil {
+		return nil, err
+	}
+	if err := types.ValidateEscrowClauses(clauses); err != nil {
+		return nil, err
+	}
+	return clauses, nil
+}
+
+func init() {
+	escrowAddressCmd.Flags().StringVar(&clausesFileFlag, "clauses", "", "Path to the JSON file of the escrow clauses")
+
+	escrowAddressCmd.MarkFlagRequired("clauses")
+
+	claimEscrowCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	claimEscrowCmd.Flags().StringVar(&clausesFileFlag, "clauses", "", "Path to the JSON file of the escrow clauses")
+	claimEscrowCmd.Flags().Uint64Var(&clauseIndexFlag, "clause", 0, "Index of the claimed clause")
+	claimEscrowCmd.Flags().StringVar(&preimageFlag, "preimage", "", "Hex encoded preimage of the hash lock of the clause")
+	claimEscrowCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the signer, if the clause requires signatures")
+	claimEscrowCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	claimEscrowCmd.Flags().StringVar(&pandoAmountFlag, "pando", "0", "Pando amount to claim")
+	claimEscrowCmd.Flags().StringVar(&ptxAmountFlag, "ptx", "0", "PTX amount to claim")
+	claimEscrowCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee, charged to the escrow")
+	claimEscrowCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the escrow")
+	claimEscrowCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	claimEscrowCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	claimEscrowCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	claimEscrowCmd.MarkFlagRequired("clauses")
+	claimEscrowCmd.MarkFlagRequired("seq")
+}
@@ -0,0 +1,196 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	paramNameFlag  string
+	depositFlag    string
+	proposalIDFlag string
+	approveFlag    bool
+)
+
+// proposeCmd represents the propose command
+// Example:
+//
+//	pandocli tx propose --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --name=BlockGasLimit --value=200000000 --seq=8
+var proposeCmd = &cobra.Command{
+	Use:   "propose",
+	Short: "Propose a change of a governance parameter",
+	Long: `Propose a change of a governance parameter, and lock a deposit until the proposal is tallied. The stakers
+vote on the proposal until the first checkpoint after the voting period. The deposit is returned if the quorum
+is reached, and burned otherwise. A passed proposal takes effect after the parameter change activation delay.
+The ID of the proposal is printed once the transaction is broadcasted.`,
+	Example: `pandocli tx propose --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --name=BlockGasLimit --value=200000000 --seq=8`,
+	Run:     doProposeCmd,
+}
+
+// voteCmd represents the vote command
+// Example:
+//
+//	pandocli tx vote --chain="pandonet" --from=0d2fD67d573c8ecB4161510fc00754d64B401F86 --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --approve=true --seq=5
+var voteCmd = &cobra.Command{
+	Use:     "vote",
+	Short:   "Vote on a pending governance proposal, only stakers can vote",
+	Long:    `Vote on a pending governance proposal. The vote counts with the stakes the voter deposited or delegated to the validator candidates at the tally, and can be changed until then.`,
+	Example: `pandocli tx vote --chain="pandonet" --from=0d2fD67d573c8ecB4161510fc00754d64B401F86 --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --approve=true --seq=5`,
+	Run:     doVoteCmd,
+}
+
+func doProposeCmd(cmd *cobra.Command, args []string) {
+	value, ok := new(big.Int).SetString(valueFlag, 10)
+	if !ok {
+		utils.Error("Invalid input: value must be a decimal integer\n")
+	}
+	if err := types.CheckGovernableParam(paramNameFlag, value); err != nil {
+		utils.Error("Invalid input: %v\n", err)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	deposit, ok := types.ParseCoinAmount(depositFlag)
+	if !ok {
+		utils.Error("Failed to parse deposit")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	proposalTx := &types.ProposalTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Proposer: types.TxInput{
+			Address: fromAddress,
+			Coins: types.Coins{
+				PandoWei: new(big.Int).SetUint64(0),
+				PTXWei:   deposit,
+			},
+			Sequence: uint64(seqFlag),
+		},
+		Name:  paramNameFlag,
+		Value: value,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			proposalTx.Proposer.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, proposalTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		proposalTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(proposalTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+	fmt.Printf("Proposal ID: %v\n", proposalTx.ProposalID().Hex())
+}
+
+func doVoteCmd(cmd *cobra.Command, args []string) {
+	proposalID := common.FromHex(proposalIDFlag)
+	if len(proposalID) != common.HashLength {
+		utils.Error("Invalid input: proposal ID must be a %v-byte hash\n", common.HashLength)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	voteTx := &types.VoteTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Voter: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		ProposalID: common.BytesToHash(proposalID),
+		Approve:    approveFlag,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			voteTx.Voter.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, voteTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		voteTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(voteTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	proposeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	proposeCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the proposer")
+	proposeCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	proposeCmd.Flags().StringVar(&paramNameFlag, "name", "", "Name of the governance parameter")
+	proposeCmd.Flags().StringVar(&valueFlag, "value", "", "Proposed value of the parameter, as a decimal integer")
+	proposeCmd.Flags().StringVar(&depositFlag, "deposit", fmt.Sprintf("%d", types.MinimumGovernanceDepositPTX), "PTX amount locked as the deposit of the proposal")
+	proposeCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	proposeCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	proposeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	proposeCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	proposeCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	proposeCmd.MarkFlagRequired("from")
+	proposeCmd.MarkFlagRequired("name")
+	proposeCmd.MarkFlagRequired("value")
+	proposeCmd.MarkFlagRequired("seq")
+
+	voteCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	voteCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the voting staker")
+	voteCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	voteCmd.Flags().StringVar(&proposalIDFlag, "id", "", "ID of the proposal")
+	voteCmd.Flags().BoolVar(&approveFlag, "approve", true, "Whether to approve the proposal")
+	voteCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	voteCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	voteCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	voteCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	voteCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	voteCmd.MarkFlagRequired("from")
+	voteCmd.MarkFlagRequired("id")
+	voteCmd.MarkFlagRequired("seq")
+}
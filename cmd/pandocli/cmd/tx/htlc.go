@@ -0,0 +1,276 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hashLockFlag string
+	timeLockFlag uint64
+	htlcIDFlag   string
+)
+
+// htlcCreateCmd represents the htlc create command
+// Example:
+//
+//	pandocli tx htlc_create --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --ptx=10 --hash_lock=0x9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 --time_lock=20000 --seq=8
+var htlcCreateCmd = &cobra.Command{
+	Use:   "htlc_create",
+	Short: "Lock coins in an HTLC for an atomic swap",
+	Long: `Lock coins in a hash time locked contract (HTLC). Until the time lock height, the coins can be claimed
+for the recipient by revealing the 32-byte preimage of the hash lock, which is the SHA-256 hash of the
+preimage. After the time lock height, the coins can only be refunded to the sender. The ID of the HTLC is
+printed once the transaction is broadcasted.`,
+	Example: `pandocli tx htlc_create --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --ptx=10 --hash_lock=0x9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 --time_lock=20000 --seq=8`,
+	Run:     doHTLCCreateCmd,
+}
+
+// htlcClaimCmd represents the htlc claim command
+// Example:
+//
+//	pandocli tx htlc_claim --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --preimage=0x7365637265747365637265747365637265747365637265747365637265747365 --seq=3
+var htlcClaimCmd = &cobra.Command{
+	Use:     "htlc_claim",
+	Short:   "Claim the coins of an HTLC by revealing the preimage of its hash lock",
+	Long:    `Claim the coins of an HTLC by revealing the preimage of its hash lock. The coins are paid to the recipient of the HTLC, while the fee is paid by the --from account.`,
+	Example: `pandocli tx htlc_claim --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --preimage=0x7365637265747365637265747365637265747365637265747365637265747365 --seq=3`,
+	Run:     doHTLCClaimCmd,
+}
+
+// htlcRefundCmd represents the htlc refund command
+// Example:
+//
+//	pandocli tx htlc_refund --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --seq=9
+var htlcRefundCmd = &cobra.Command{
+	Use:     "htlc_refund",
+	Short:   "Refund the coins of an expired HTLC to its sender",
+	Long:    `Refund the coins of an HTLC to its sender once its time lock has expired. The fee is paid by the --from account.`,
+	Example: `pandocli tx htlc_refund --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --id=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --seq=9`,
+	Run:     doHTLCRefundCmd,
+}
+
+func doHTLCCreateCmd(cmd *cobra.Command, args []string) {
+	if !common.IsHexAddress(toFlag) {
+		utils.Error("Invalid input: recipient must be an address\n")
+	}
+	hashLock := common.FromHex(hashLockFlag)
+	if len(hashLock) != common.HashLength {
+		utils.Error("Invalid input: hash lock must be a %v-byte hash\n", common.HashLength)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	pando, ok := types.ParseCoinAmount(pandoAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse pando amount")
+	}
+	ptx, ok := types.ParseCoinAmount(ptxAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse ptx amount")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	htlcCreateTx := &types.HTLCCreateTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Sender: types.TxInput{
+			Address: fromAddress,
+			Coins: types.Coins{
+				PandoWei: pando,
+				PTXWei:   ptx,
+			},
+			Sequence: uint64(seqFlag),
+		},
+		Recipient: common.HexToAddress(toFlag),
+		HashLock:  common.BytesToHash(hashLock),
+		TimeLock:  timeLockFlag,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			htlcCreateTx.Sender.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, htlcCreateTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		htlcCreateTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(htlcCreateTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+	fmt.Printf("HTLC ID: %v\n", htlcCreateTx.HTLCID().Hex())
+}
+
+func doHTLCClaimCmd(cmd *cobra.Command, args []string) {
+	preimage := common.FromHex(preimageFlag)
+	if len(preimage) != types.HTLCPreimageLength {
+		utils.Error("Invalid input: preimage must be %v bytes\n", types.HTLCPreimageLength)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	htlcClaimTx := &types.HTLCClaimTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Claimer: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		HTLCID:   common.HexToHash(htlcIDFlag),
+		Preimage: preimage,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			htlcClaimTx.Claimer.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, htlcClaimTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		htlcClaimTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(htlcClaimTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func doHTLCRefundCmd(cmd *cobra.Command, args []string) {
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	htlcRefundTx := &types.HTLCRefundTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Refunder: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		HTLCID: common.HexToHash(htlcIDFlag),
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			htlcRefundTx.Refunder.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, htlcRefundTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		htlcRefundTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(htlcRefundTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	htlcCreateCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	htlcCreateCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the sender")
+	htlcCreateCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	htlcCreateCmd.Flags().StringVar(&toFlag, "to", "", "Address of the recipient")
+	htlcCreateCmd.Flags().StringVar(&pandoAmountFlag, "pando", "0", "Pando amount to lock")
+	htlcCreateCmd.Flags().StringVar(&ptxAmountFlag, "ptx", "0", "PTX amount to lock")
+	htlcCreateCmd.Flags().StringVar(&hashLockFlag, "hash_lock", "", "SHA-256 hash of the preimage")
+	htlcCreateCmd.Flags().Uint64Var(&timeLockFlag, "time_lock", 0, "Last block height to claim the coins")
+	htlcCreateCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	htlcCreateCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	htlcCreateCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	htlcCreateCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	htlcCreateCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	htlcCreateCmd.MarkFlagRequired("from")
+	htlcCreateCmd.MarkFlagRequired("to")
+	htlcCreateCmd.MarkFlagRequired("hash_lock")
+	htlcCreateCmd.MarkFlagRequired("time_lock")
+	htlcCreateCmd.MarkFlagRequired("seq")
+
+	htlcClaimCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	htlcClaimCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the account submitting the claim")
+	htlcClaimCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	htlcClaimCmd.Flags().StringVar(&htlcIDFlag, "id", "", "ID of the HTLC")
+	htlcClaimCmd.Flags().StringVar(&preimageFlag, "preimage", "", "Hex encoded preimage of the hash lock")
+	htlcClaimCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	htlcClaimCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	htlcClaimCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	htlcClaimCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	htlcClaimCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	htlcClaimCmd.MarkFlagRequired("from")
+	htlcClaimCmd.MarkFlagRequired("id")
+	htlcClaimCmd.MarkFlagRequired("preimage")
+	htlcClaimCmd.MarkFlagRequired("seq")
+
+	htlcRefundCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	htlcRefundCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the account submitting the refund")
+	htlcRefundCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	htlcRefundCmd.Flags().StringVar(&htlcIDFlag, "id", "", "ID of the HTLC")
+	htlcRefundCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	htlcRefundCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	htlcRefundCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	htlcRefundCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	htlcRefundCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	htlcRefundCmd.MarkFlagRequired("from")
+	htlcRefundCmd.MarkFlagRequired("id")
+	htlcRefundCmd.MarkFlagRequired("seq")
+}
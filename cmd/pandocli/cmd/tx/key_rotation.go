@@ -0,0 +1,106 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	accountFlag string
+	newKeyFlag  string
+)
+
+// keyRotationCmd represents the key rotation command
+// Example:
+//
+//	pandocli tx key_rotation --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --new_key=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --seq=8
+var keyRotationCmd = &cobra.Command{
+	Use:   "key_rotation",
+	Short: "Authorize a new key to control an account",
+	Long: `Authorize a new key to control an account, without moving its funds. The transaction is signed by
+a key currently accepted for the account, by default the key of the account address. The previous key
+is still accepted during the grace period, and rejected afterwards. Rotating back to the previous key
+during the grace period cancels the rotation. Once the key of an account has been rotated, sign with
+--from set to the current key and --account set to the account.`,
+	Example: `pandocli tx key_rotation --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --new_key=0x70f587259738cB626A1720Af7038B8DcDb6a42a0 --seq=8`,
+	Run:     doKeyRotationCmd,
+}
+
+func doKeyRotationCmd(cmd *cobra.Command, args []string) {
+	if !common.IsHexAddress(newKeyFlag) {
+		utils.Error("Invalid input: new key must be an address\n")
+	}
+	newKey := common.HexToAddress(newKeyFlag)
+
+	wallet, signerAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(signerAddress)
+
+	accountAddress := signerAddress
+	if accountFlag != "" {
+		accountAddress = common.HexToAddress(accountFlag)
+	}
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	keyRotationTx := &types.KeyRotationTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Account: types.TxInput{
+			Address:  accountAddress,
+			Sequence: uint64(seqFlag),
+		},
+		NewKey: newKey,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(accountAddress)
+			if err != nil {
+				return nil, err
+			}
+			keyRotationTx.Account.Sequence = seq
+		}
+		sig, err := wallet.Sign(signerAddress, keyRotationTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		keyRotationTx.SetSignature(accountAddress, sig)
+		return types.TxToBytes(keyRotationTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	keyRotationCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	keyRotationCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the key signing the transaction")
+	keyRotationCmd.Flags().StringVar(&accountFlag, "account", "", "Address of the account whose key is rotated (default to --from)")
+	keyRotationCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	keyRotationCmd.Flags().StringVar(&newKeyFlag, "new_key", "", "Address of the new key controlling the account")
+	keyRotationCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	keyRotationCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	keyRotationCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	keyRotationCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	keyRotationCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	keyRotationCmd.MarkFlagRequired("from")
+	keyRotationCmd.MarkFlagRequired("new_key")
+	keyRotationCmd.MarkFlagRequired("seq")
+}
@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +22,7 @@ var (
 	reserveFundInPTXFlag       string
 	reserveCollateralInPTXFlag string
 	reserveSeqFlag             uint64
+	reserveCurrencyFlag        string
 	addressesFlag              []string
 	percentagesFlag            []string
 	valueFlag                  string
@@ -33,6 +35,10 @@ var (
 	sourceFlag                 string
 	holderFlag                 string
 	asyncFlag                  bool
+	retryFlag                  bool
+	outputsFileFlag            string
+	validUntilFlag             uint64
+	qrFramesFlag               string
 )
 
 // TxCmd represents the Tx command
@@ -40,16 +46,57 @@ var TxCmd = &cobra.Command{
 	Use:   "tx",
 	Short: "Manage transactions",
 	Long:  `Manage transactions.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		chainIDFlag = utils.ResolveChainID(chainIDFlag)
+	},
 }
 
 func init() {
 	TxCmd.AddCommand(sendCmd)
+	TxCmd.AddCommand(batchSendCmd)
 	TxCmd.AddCommand(reserveFundCmd)
 	//TxCmd.AddCommand(releaseFundCmd) // No need for releaseFundCmd since auto-release is already implemented
 	TxCmd.AddCommand(splitRuleCmd)
 	TxCmd.AddCommand(smartContractCmd)
 	TxCmd.AddCommand(depositStakeCmd)
 	TxCmd.AddCommand(withdrawStakeCmd)
+	TxCmd.AddCommand(delegateCmd)
+	TxCmd.AddCommand(undelegateCmd)
+	TxCmd.AddCommand(setCommissionCmd)
 	TxCmd.AddCommand(rametronStakeCmd)
+	TxCmd.AddCommand(sessionKeyCmd)
+	TxCmd.AddCommand(keyRotationCmd)
+	TxCmd.AddCommand(slashAppealCmd)
+	TxCmd.AddCommand(slashAppealVoteCmd)
+	TxCmd.AddCommand(escrowAddressCmd)
+	TxCmd.AddCommand(claimEscrowCmd)
+	TxCmd.AddCommand(htlcCreateCmd)
+	TxCmd.AddCommand(htlcClaimCmd)
+	TxCmd.AddCommand(htlcRefundCmd)
+	TxCmd.AddCommand(bridgeLockCmd)
+	TxCmd.AddCommand(bridgeBurnCmd)
+	TxCmd.AddCommand(bridgeClaimCmd)
+	TxCmd.AddCommand(subchainRegisterCmd)
+	TxCmd.AddCommand(subchainStakeCmd)
+	TxCmd.AddCommand(subchainCheckpointCmd)
+	TxCmd.AddCommand(subchainChallengeCmd)
+	TxCmd.AddCommand(proposeCmd)
+	TxCmd.AddCommand(voteCmd)
+	TxCmd.AddCommand(signMeteringRecordCmd)
+	TxCmd.AddCommand(meteredSettlementCmd)
+	TxCmd.AddCommand(buildCmd)
+	TxCmd.AddCommand(signCmd)
+	TxCmd.AddCommand(broadcastCmd)
+	addBuildCommands()
+
+	TxCmd.PersistentFlags().StringVar(&qrFramesFlag, "qr-frames", "", "Directory to write the QR codes of the qr wallet to as PNG files, instead of showing them on the terminal")
+	buildCmd.PersistentFlags().StringVar(&unsignedFlag, "unsigned", "", "File to write the unsigned transaction to")
+	buildCmd.MarkPersistentFlagRequired("unsigned")
+	signCmd.Flags().StringVar(&outFlag, "out", "", "File to write the signed transaction to")
+	signCmd.Flags().BoolVar(&offlineFlag, "offline", false, "Do not connect to the RPC endpoint")
+	signCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	signCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	broadcastCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	broadcastCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures")
 }
 
@@ -0,0 +1,186 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recordFileFlag  string
+	recordsFileFlag string
+	clientFlag      string
+)
+
+// signMeteringRecordCmd represents the sign metering record command. The record file is the JSON
+// of a metering record, e.g.
+//
+//	{"client": "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", "edge_node": "0x0d2fD67d573c8ecB4161510fc00754d64B401F86",
+//	 "reserve_sequence": "1", "resource_id": "vid01", "sequence": "1", "bytes": "2500000000",
+//	 "price_per_gb": {"PandoWei": "0", "PTXWei": "1000000000000000000"}}
+//
+// Example:
+//
+//	pandocli tx sign_metering_record --chain="pandonet" --from=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --record=record.json
+var signMeteringRecordCmd = &cobra.Command{
+	Use:     "sign_metering_record",
+	Short:   "Sign a metering record as its client or edge node",
+	Long:    `Sign a metering record as its client or edge node. The record is printed with the signature added, and can be settled with a metered_settlement transaction once signed by both.`,
+	Example: `pandocli tx sign_metering_record --chain="pandonet" --from=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --record=record.json`,
+	Run:     doSignMeteringRecordCmd,
+}
+
+// meteredSettlementCmd represents the metered settlement command. The records file is a JSON array
+// of metering records signed by both the client and the edge node, in ascending order of their sequences.
+// Example:
+//
+//	pandocli tx metered_settlement --chain="pandonet" --from=0x0d2fD67d573c8ecB4161510fc00754d64B401F86 --client=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --reserve_seq=1 --resource_id=vid01 --records=records.json --seq=3
+var meteredSettlementCmd = &cobra.Command{
+	Use:     "metered_settlement",
+	Short:   "Settle the metered bandwidth delivered to a client",
+	Long:    `Settle the metered bandwidth delivered to a client. The delivered bytes of the records are paid at their price per gigabyte from the reserve fund of the client, and the fee is charged to the edge node.`,
+	Example: `pandocli tx metered_settlement --chain="pandonet" --from=0x0d2fD67d573c8ecB4161510fc00754d64B401F86 --client=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --reserve_seq=1 --resource_id=vid01 --records=records.json --seq=3`,
+	Run:     doMeteredSettlementCmd,
+}
+
+func doSignMeteringRecordCmd(cmd *cobra.Command, args []string) {
+	raw, err := ioutil.ReadFile(recordFileFlag)
+	if err != nil {
+		utils.Error("Failed to read the record: %v\n", err)
+	}
+	record := types.MeteringRecord{}
+	if err := json.Unmarshal(raw, &record); err != nil {
+		utils.Error("Failed to parse the record: %v\n", err)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	sig, err := wallet.Sign(fromAddress, record.SignBytes(chainIDFlag))
+	if err != nil {
+		utils.Error("Failed to sign the record: %v\n", err)
+	}
+	switch fromAddress {
+	case record.Client:
+		record.ClientSignature = sig
+	case record.EdgeNode:
+		record.EdgeNodeSignature = sig
+	default:
+		utils.Error("%v is neither the client nor the edge node of the record\n", fromAddress.Hex())
+	}
+
+	formatted, err := json.MarshalIndent(record, "", "    ")
+	if err != nil {
+		utils.Error("Failed to encode the record: %v\n", err)
+	}
+	fmt.Println(string(formatted))
+}
+
+func doMeteredSettlementCmd(cmd *cobra.Command, args []string) {
+	records, err := readMeteringRecords(recordsFileFlag)
+	if err != nil {
+		utils.Error("Failed to read the records: %v\n", err)
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	wallet, edgeNodeAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(edgeNodeAddress)
+
+	settlementTx := &types.MeteredSettlementTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		EdgeNode: types.TxInput{
+			Address:  edgeNodeAddress,
+			Sequence: uint64(seqFlag),
+		},
+		Client:          common.HexToAddress(clientFlag),
+		ReserveSequence: reserveSeqFlag,
+		ResourceID:      resourceIDFlag,
+		Records:         records,
+	}
+	if err := settlementTx.ValidateRecords(chainIDFlag); err != nil {
+		utils.Error("Invalid records: %v\n", err)
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(edgeNodeAddress)
+			if err != nil {
+				return nil, err
+			}
+			settlementTx.EdgeNode.Sequence = seq
+		}
+		sig, err := wallet.Sign(edgeNodeAddress, settlementTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		settlementTx.SetSignature(edgeNodeAddress, sig)
+		return types.TxToBytes(settlementTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+// readMeteringRecords parses the records JSON file
+func readMeteringRecords(path string) ([]types.MeteringRecord, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	records := []types.MeteringRecord{}
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func init() {
+	signMeteringRecordCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	signMeteringRecordCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the client or the edge node of the record")
+	signMeteringRecordCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	signMeteringRecordCmd.Flags().StringVar(&recordFileFlag, "record", "", "Path to the JSON file of the metering record")
+	signMeteringRecordCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+
+	signMeteringRecordCmd.MarkFlagRequired("from")
+	signMeteringRecordCmd.MarkFlagRequired("record")
+
+	meteredSettlementCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	meteredSettlementCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the edge node")
+	meteredSettlementCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	meteredSettlementCmd.Flags().StringVar(&clientFlag, "client", "", "Address of the client")
+	meteredSettlementCmd.Flags().Uint64Var(&reserveSeqFlag, "reserve_seq", 1000, "Reserve sequence of the reserve fund of the client")
+	meteredSettlementCmd.Flags().StringVar(&resourceIDFlag, "resource_id", "", "Corresponding resourceID")
+	meteredSettlementCmd.Flags().StringVar(&recordsFileFlag, "records", "", "Path to the JSON file of the metering records")
+	meteredSettlementCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee, charged to the edge node")
+	meteredSettlementCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the edge node")
+	meteredSettlementCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	meteredSettlementCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	meteredSettlementCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	meteredSettlementCmd.MarkFlagRequired("from")
+	meteredSettlementCmd.MarkFlagRequired("client")
+	meteredSettlementCmd.MarkFlagRequired("reserve_seq")
+	meteredSettlementCmd.MarkFlagRequired("resource_id")
+	meteredSettlementCmd.MarkFlagRequired("records")
+	meteredSettlementCmd.MarkFlagRequired("seq")
+}
@@ -0,0 +1,259 @@
+package tx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unsignedFlag string
+	offlineFlag  bool
+	outFlag      string
+)
+
+// OfflineTx is the file a transaction is carried in between the online machine which builds and
+// broadcasts it, and the air-gapped machine which signs it. The transaction is recovered from the
+// sign bytes, so that the signer reviews exactly what it signs.
+type OfflineTx struct {
+	ChainID   string            `json:"chain_id"`
+	Signer    common.Address    `json:"signer"`
+	SignBytes hexutil.Bytes     `json:"sign_bytes"`
+	Signature *crypto.Signature `json:"signature,omitempty"`
+}
+
+// signableTx is a transaction signed by a single account
+type signableTx interface {
+	types.Tx
+	SetSignature(addr common.Address, sig *crypto.Signature) bool
+}
+
+// buildCmd builds unsigned transactions with the transaction commands it mirrors
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build an unsigned transaction",
+	Long: `Build an unsigned transaction, e.g. of a watch-only account. Any transaction command can be built with the same flags, and the sign bytes of the transaction are written to the --unsigned file instead of being signed.
+The file is signed with "pandocli tx sign --offline" on the machine which has the key, and broadcasted with "pandocli tx broadcast".`,
+	Example: `pandocli tx build send --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=9F1233798E905E173560071255140b4A8aBd3Ec6 --pando=10 --ptx=9 --seq=1 --unsigned=send.json`,
+}
+
+// signCmd signs a transaction built with "tx build"
+var signCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Sign a transaction built with tx build",
+	Long: `Sign a transaction built with "pandocli tx build", after showing it for review. The signed transaction is written to the --out file, to be broadcasted with "pandocli tx broadcast".
+With --offline, no connection is made to the RPC endpoint, e.g. on an air-gapped machine. Otherwise the chain ID of the transaction is checked against the selected network.`,
+	Example: `pandocli tx sign send.json --out=send_signed.json --offline`,
+	// The chain ID is the one the transaction was built for
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Run:              doSignCmd,
+}
+
+// broadcastCmd broadcasts a transaction signed with "tx sign"
+var broadcastCmd = &cobra.Command{
+	Use:              "broadcast",
+	Short:            "Broadcast a transaction signed with tx sign",
+	Long:             `Broadcast a transaction signed with "pandocli tx sign".`,
+	Example:          `pandocli tx broadcast send_signed.json`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Run:              doBroadcastCmd,
+}
+
+func doSignCmd(cmd *cobra.Command, args []string) {
+	if len(args) < 1 || len(outFlag) == 0 {
+		utils.Error("Usage: pandocli tx sign <unsigned transaction file> --out=<signed transaction file>\n")
+	}
+	offlineTx, tx, err := loadOfflineTx(args[0])
+	if err != nil {
+		utils.Error("Failed to load the transaction: %v\n", err)
+	}
+	if offlineTx.Signature != nil {
+		utils.Error("The transaction is signed already\n")
+	}
+	if !offlineFlag {
+		utils.ResolveChainID(offlineTx.ChainID)
+	}
+
+	formatted, err := json.MarshalIndent(tx, "", "    ")
+	if err != nil {
+		utils.Error("Failed to format the transaction: %v\n", err)
+	}
+	fmt.Printf("Chain ID: %v\nSigner: %v\nTransaction:\n%s\n", offlineTx.ChainID, offlineTx.Signer.Hex(), formatted)
+	fmt.Println("Are you sure to sign the transaction? Please enter 'no' to stop or 'yes' to proceed: ")
+	confirmation, err := utils.GetConfirmation()
+	if err != nil {
+		utils.Error("Failed to get confirmation: %v\n", err)
+	}
+	if strings.ToLower(confirmation) != "yes" {
+		return
+	}
+
+	wallet, address, err := WalletUnlockWithPath(cmd, offlineTx.Signer.Hex(), pathFlag)
+	if err != nil || wallet == nil {
+		utils.Error("Failed to unlock the wallet: %v\n", err)
+	}
+	defer wallet.Lock(address)
+	if address != offlineTx.Signer {
+		utils.Error("The wallet signs for %v, not %v\n", address.Hex(), offlineTx.Signer.Hex())
+	}
+
+	offlineTx.Signature, err = wallet.Sign(address, common.Bytes(offlineTx.SignBytes))
+	if err != nil {
+		utils.Error("Failed to sign transaction: %v\n", err)
+	}
+	if err := saveOfflineTx(outFlag, offlineTx); err != nil {
+		utils.Error("Failed to write the signed transaction: %v\n", err)
+	}
+	fmt.Printf("Successfully signed transaction: %v\n", outFlag)
+}
+
+func doBroadcastCmd(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		utils.Error("Usage: pandocli tx broadcast <signed transaction file>\n")
+	}
+	offlineTx, tx, err := loadOfflineTx(args[0])
+	if err != nil {
+		utils.Error("Failed to load the transaction: %v\n", err)
+	}
+	if offlineTx.Signature == nil {
+		utils.Error("The transaction is not signed yet\n")
+	}
+	if !offlineTx.Signature.Verify(common.Bytes(offlineTx.SignBytes), offlineTx.Signer) {
+		utils.Error("Invalid signature, the transaction is not signed by %v\n", offlineTx.Signer.Hex())
+	}
+	utils.ResolveChainID(offlineTx.ChainID)
+
+	signable, ok := tx.(signableTx)
+	if !ok || !signable.SetSignature(offlineTx.Signer, offlineTx.Signature) {
+		utils.Error("Failed to set the signature of %v\n", offlineTx.Signer.Hex())
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			return nil, errors.New("the transaction was signed offline, and cannot be re-signed with a new sequence")
+		}
+		return types.TxToBytes(signable)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+// loadOfflineTx reads the transaction file, and recovers the transaction from its sign bytes
+func loadOfflineTx(filePath string) (*OfflineTx, types.Tx, error) {
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	offlineTx := &OfflineTx{}
+	if err := json.Unmarshal(raw, offlineTx); err != nil {
+		return nil, nil, err
+	}
+	chainID, tx, err := types.TxFromSignBytes(common.Bytes(offlineTx.SignBytes))
+	if err != nil {
+		return nil, nil, err
+	}
+	if chainID != offlineTx.ChainID {
+		return nil, nil, fmt.Errorf("the transaction is for chain %v, not %v", chainID, offlineTx.ChainID)
+	}
+	return offlineTx, tx, nil
+}
+
+func saveOfflineTx(filePath string, offlineTx *OfflineTx) error {
+	raw, err := json.MarshalIndent(offlineTx, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, raw, 0600)
+}
+
+// unsignedWallet stands in for the wallet when a transaction is built with "tx build". Instead of
+// signing the transaction, it writes the sign bytes to the --unsigned file, and exits.
+type unsignedWallet struct {
+	address common.Address
+}
+
+var _ wtypes.Wallet = (*unsignedWallet)(nil)
+
+var errUnsignedWallet = errors.New("not supported when building an unsigned transaction")
+
+func (w *unsignedWallet) ID() string                      { return "unsigned" }
+func (w *unsignedWallet) Status() (string, error)         { return "", nil }
+func (w *unsignedWallet) List() ([]common.Address, error) { return []common.Address{w.address}, nil }
+func (w *unsignedWallet) NewKey(password string) (common.Address, error) {
+	return common.Address{}, errUnsignedWallet
+}
+func (w *unsignedWallet) Unlock(address common.Address, password string, derivationPath wtypes.DerivationPath) error {
+	return nil
+}
+func (w *unsignedWallet) Lock(address common.Address) error      { return nil }
+func (w *unsignedWallet) IsUnlocked(address common.Address) bool { return address == w.address }
+func (w *unsignedWallet) Delete(address common.Address, password string) error {
+	return errUnsignedWallet
+}
+func (w *unsignedWallet) UpdatePassword(address common.Address, oldPassword, newPassword string) error {
+	return errUnsignedWallet
+}
+func (w *unsignedWallet) Derive(path wtypes.DerivationPath, pin bool) (common.Address, error) {
+	return common.Address{}, errUnsignedWallet
+}
+func (w *unsignedWallet) GetPublicKey(address common.Address) (*crypto.PublicKey, error) {
+	return nil, errUnsignedWallet
+}
+
+// Sign writes the sign bytes to the --unsigned file, and exits
+func (w *unsignedWallet) Sign(address common.Address, signBytes common.Bytes) (*crypto.Signature, error) {
+	chainID, _, err := types.TxFromSignBytes(signBytes)
+	if err != nil {
+		return nil, err
+	}
+	offlineTx := &OfflineTx{
+		ChainID:   chainID,
+		Signer:    address,
+		SignBytes: hexutil.Bytes(signBytes),
+	}
+	if err := saveOfflineTx(unsignedFlag, offlineTx); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Successfully built unsigned transaction: %v\n", unsignedFlag)
+	os.Exit(0)
+	return nil, nil
+}
+
+// addBuildCommands mirrors the transaction commands under "tx build". A mirror shares the flag set
+// of the command, which is created here so that it is shared with the flags registered later.
+func addBuildCommands() {
+	for _, c := range TxCmd.Commands() {
+		if c == buildCmd || c == signCmd || c == broadcastCmd {
+			continue
+		}
+		c.Flags()
+		mirror := *c
+		mirror.Example = buildExample(c.Example)
+		buildCmd.AddCommand(&mirror)
+	}
+}
+
+// buildExample turns the example of a transaction command into the example of its mirror
+func buildExample(example string) string {
+	lines := strings.Split(example, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "pandocli tx ") {
+			lines[i] = strings.Replace(line, "pandocli tx ", "pandocli tx build ", 1) + " --unsigned=tx.json"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -18,11 +18,14 @@ import (
 	rpcc "github.com/ybbus/jsonrpc"
 )
 
+var tierFlag string
+
 // rametronStakeCmd represents the rametronStake command
 // Example:
 //		pandocli tx rametronStake --chain="pandonet" --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --to=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --pando=10 --ptx=9 --seq=1
 //		pandocli tx rametronStake --chain="pandonet" --path "m/44'/60'/0'/0/0" --to=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --pando=10 --ptx=9 --seq=1 --wallet=trezor
 //		pandocli tx rametronStake --chain="pandonet" --path "m/44'/60'/0'/0" --to=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --pando=10 --ptx=9 --seq=1 --wallet=nano
+//		pandocli tx rametronStake --chain="pandonet" --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --to=2E833968E5bB786Ae419c4d13189fB081Cc43bab --pando=10000 --tier=pro --seq=1
 var rametronStakeCmd = &cobra.Command{
 	Use:     "rametronStake",
 	Short:   "RametronStake tokens",
@@ -46,7 +49,7 @@ func doRametronStakeCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	wallet, fromAddress, err := walletUnlockWithPath(cmd, fromFlag, pathFlag)
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
 	if err != nil || wallet == nil {
 		return
 	}
@@ -64,6 +67,10 @@ func doRametronStakeCmd(cmd *cobra.Command, args []string) {
 	if !ok {
 		utils.Error("Failed to parse fee")
 	}
+	tier, err := types.ParseRametronTier(tierFlag)
+	if err != nil {
+		utils.Error("%v\n", err)
+	}
 	inputs := []types.TxInput{{
 		Address: fromAddress,
 		Coins: types.Coins{
@@ -86,6 +93,7 @@ func doRametronStakeCmd(cmd *cobra.Command, args []string) {
 		},
 		Inputs:  inputs,
 		Outputs: outputs,
+		Tier:    tier,
 	}
 
 	sig, err := wallet.Sign(fromAddress, rametronStakeTx.SignBytes(chainIDFlag))
@@ -128,7 +136,7 @@ func doRametronStakeCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	rametronStakeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	rametronStakeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	rametronStakeCmd.Flags().StringVar(&fromFlag, "from", "", "Source of the stake")
 	rametronStakeCmd.Flags().StringVar(&toFlag, "to", "", "Holder of the stake")
 	rametronStakeCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
@@ -136,10 +144,10 @@ func init() {
 	rametronStakeCmd.Flags().StringVar(&pandoAmountFlag, "pando", "0", "Pando amount")
 	rametronStakeCmd.Flags().StringVar(&ptxAmountFlag, "ptx", "0", "Pando amount")
 	rametronStakeCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
-	rametronStakeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor)")
+	rametronStakeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
 	rametronStakeCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	rametronStakeCmd.Flags().StringVar(&tierFlag, "tier", "none", "Register the holder as a rametron node of the tier (none|mobile|pro|enterprise)")
 
-	rametronStakeCmd.MarkFlagRequired("chain")
 	//rametronStakeCmd.MarkFlagRequired("from")
 	rametronStakeCmd.MarkFlagRequired("to")
 	rametronStakeCmd.MarkFlagRequired("seq")
@@ -74,14 +74,13 @@ func doReleaseFundCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	releaseFundCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	releaseFundCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	releaseFundCmd.Flags().StringVar(&fromFlag, "from", "", "Reserve owner's address")
 	releaseFundCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
 	releaseFundCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
 	releaseFundCmd.Flags().Uint64Var(&reserveSeqFlag, "reserve_seq", 1000, "Reserve sequence")
 	releaseFundCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
 
-	releaseFundCmd.MarkFlagRequired("chain")
 	releaseFundCmd.MarkFlagRequired("from")
 	releaseFundCmd.MarkFlagRequired("seq")
 	releaseFundCmd.MarkFlagRequired("reserve_seq")
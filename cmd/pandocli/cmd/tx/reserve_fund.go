@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 	"github.com/pandotoken/pando/ledger/types"
@@ -17,6 +18,7 @@ import (
 // reserveFundCmd represents the reserve fund command
 // Example:
 //		pandocli tx reserve --chain="pandonet" --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --fund=900 --collateral=1203 --seq=6 --duration=1002 --resource_ids=die_another_day,hello
+//		pandocli tx reserve --chain="pandonet" --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --fund=900 --collateral=1203 --currency=pando --seq=6 --duration=1002 --resource_ids=die_another_day,hello
 var reserveFundCmd = &cobra.Command{
 	Use:     "reserve",
 	Short:   "Reserve fund for an off-chain micropayment",
@@ -43,22 +45,26 @@ func doReserveFundCmd(cmd *cobra.Command, args []string) {
 	if !ok {
 		utils.Error("Failed to parse collateral")
 	}
+	var fundCoins, collateral types.Coins
+	switch strings.ToLower(reserveCurrencyFlag) {
+	case "ptx":
+		fundCoins = types.Coins{PandoWei: new(big.Int).SetUint64(0), PTXWei: fund}
+		collateral = types.Coins{PandoWei: new(big.Int).SetUint64(0), PTXWei: col}
+	case "pando":
+		fundCoins = types.Coins{PandoWei: fund, PTXWei: new(big.Int).SetUint64(0)}
+		collateral = types.Coins{PandoWei: col, PTXWei: new(big.Int).SetUint64(0)}
+	default:
+		utils.Error("Invalid currency: %v, should be either ptx or pando\n", reserveCurrencyFlag)
+	}
 	input := types.TxInput{
-		Address: fromAddress,
-		Coins: types.Coins{
-			PandoWei: new(big.Int).SetUint64(0),
-			PTXWei:   fund,
-		},
+		Address:  fromAddress,
+		Coins:    fundCoins,
 		Sequence: uint64(seqFlag),
 	}
 	resourceIDs := []string{}
 	for _, id := range resourceIDsFlag {
 		resourceIDs = append(resourceIDs, id)
 	}
-	collateral := types.Coins{
-		PandoWei: new(big.Int).SetUint64(0),
-		PTXWei:   col,
-	}
 	if !collateral.IsPositive() {
 		utils.Error("Invalid input: collateral must be positive\n")
 	}
@@ -99,17 +105,17 @@ func doReserveFundCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	reserveFundCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	reserveFundCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	reserveFundCmd.Flags().StringVar(&fromFlag, "from", "", "Address to send from")
 	reserveFundCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
-	reserveFundCmd.Flags().StringVar(&reserveFundInPTXFlag, "fund", "0", "PTX amount to reserve")
-	reserveFundCmd.Flags().StringVar(&reserveCollateralInPTXFlag, "collateral", "0", "PTX amount as collateral")
+	reserveFundCmd.Flags().StringVar(&reserveFundInPTXFlag, "fund", "0", "Amount to reserve")
+	reserveFundCmd.Flags().StringVar(&reserveCollateralInPTXFlag, "collateral", "0", "Amount as collateral")
+	reserveFundCmd.Flags().StringVar(&reserveCurrencyFlag, "currency", "ptx", "Currency of the fund and the collateral (ptx|pando)")
 	reserveFundCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
 	reserveFundCmd.Flags().Uint64Var(&durationFlag, "duration", 1000, "Reserve duration")
 	reserveFundCmd.Flags().StringSliceVar(&resourceIDsFlag, "resource_ids", []string{}, "Reserouce IDs")
 	reserveFundCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
 
-	reserveFundCmd.MarkFlagRequired("chain")
 	reserveFundCmd.MarkFlagRequired("from")
 	reserveFundCmd.MarkFlagRequired("seq")
 	reserveFundCmd.MarkFlagRequired("duration")
@@ -5,22 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/ledger/types"
-	"github.com/pandotoken/pando/rpc"
 	wtypes "github.com/pandotoken/pando/wallet/types"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-
-	"github.com/ybbus/jsonrpc"
-	rpcc "github.com/ybbus/jsonrpc"
 )
 
 // sendCmd represents the send command
 // Example:
 //		pandocli tx send --chain="pandonet" --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --to=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --pando=10 --ptx=9 --seq=1
+//		pandocli tx send --network=mainnet --from=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --to=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --pando=10 --ptx=9 --seq=1
 //		pandocli tx send --chain="pandonet" --path "m/44'/60'/0'/0/0" --to=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --pando=10 --ptx=9 --seq=1 --wallet=trezor
 //		pandocli tx send --chain="pandonet" --path "m/44'/60'/0'/0" --to=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --pando=10 --ptx=9 --seq=1 --wallet=nano
 var sendCmd = &cobra.Command{
@@ -46,7 +43,7 @@ func doSendCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	wallet, fromAddress, err := walletUnlockWithPath(cmd, fromFlag, pathFlag)
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
 	if err != nil || wallet == nil {
 		return
 	}
@@ -64,6 +61,15 @@ func doSendCmd(cmd *cobra.Command, args []string) {
 	if !ok {
 		utils.Error("Failed to parse fee")
 	}
+	var data common.Bytes
+	if strings.HasPrefix(dataFlag, "0x") {
+		data, err = hex.DecodeString(dataFlag[2:])
+		if err != nil {
+			utils.Error("Failed to parse data: %v\n", err)
+		}
+	} else {
+		data = common.Bytes(dataFlag)
+	}
 	inputs := []types.TxInput{{
 		Address: fromAddress,
 		Coins: types.Coins{
@@ -84,42 +90,32 @@ func doSendCmd(cmd *cobra.Command, args []string) {
 			PandoWei: new(big.Int).SetUint64(0),
 			PTXWei:   fee,
 		},
-		Inputs:  inputs,
-		Outputs: outputs,
-	}
-
-	sig, err := wallet.Sign(fromAddress, sendTx.SignBytes(chainIDFlag))
-	if err != nil {
-		utils.Error("Failed to sign transaction: %v\n", err)
-	}
-	sendTx.SetSignature(fromAddress, sig)
-
-	raw, err := types.TxToBytes(sendTx)
-	if err != nil {
-		utils.Error("Failed to encode transaction: %v\n", err)
+		Inputs:          inputs,
+		Outputs:         outputs,
+		Data:            data,
+		ValidUntilBlock: validUntilFlag,
 	}
-	signedTx := hex.EncodeToString(raw)
-
-	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
-
-	var res *jsonrpc.RPCResponse
-	if asyncFlag {
-		res, err = client.Call("pando.BroadcastRawTransactionAsync", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
-	} else {
-		res, err = client.Call("pando.BroadcastRawTransaction", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+	if !cmd.Flags().Changed("fee") && fee.Cmp(sendTx.MinimumFee()) < 0 {
+		fee = sendTx.MinimumFee()
+		sendTx.Fee.PTXWei = fee
+		inputs[0].Coins.PTXWei = new(big.Int).Add(ptx, fee)
 	}
 
-	if err != nil {
-		utils.Error("Failed to broadcast transaction: %v\n", err)
-	}
-	if res.Error != nil {
-		utils.Error("Server returned error: %v\n", res.Error)
-	}
-	result := &rpc.BroadcastRawTransactionResult{}
-	err = res.GetObject(result)
-	if err != nil {
-		utils.Error("Failed to parse server response: %v\n", err)
-	}
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			inputs[0].Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, sendTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		sendTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(sendTx)
+	})
 	formatted, err := json.MarshalIndent(result, "", "    ")
 	if err != nil {
 		utils.Error("Failed to parse server response: %v\n", err)
@@ -128,7 +124,7 @@ func doSendCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	sendCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	sendCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	sendCmd.Flags().StringVar(&fromFlag, "from", "", "Address to send from")
 	sendCmd.Flags().StringVar(&toFlag, "to", "", "Address to send to")
 	sendCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
@@ -136,10 +132,12 @@ func init() {
 	sendCmd.Flags().StringVar(&pandoAmountFlag, "pando", "0", "Pando amount")
 	sendCmd.Flags().StringVar(&ptxAmountFlag, "ptx", "0", "Pando amount")
 	sendCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
-	sendCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor)")
+	sendCmd.Flags().StringVar(&dataFlag, "data", "", "Data to attach, e.g. the deposit identifier required by an exchange (hex if prefixed with 0x)")
+	sendCmd.Flags().Uint64Var(&validUntilFlag, "valid_until", 0, "The tx is rejected after this block height (0 for no expiry)")
+	sendCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
 	sendCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	sendCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
 
-	sendCmd.MarkFlagRequired("chain")
 	//sendCmd.MarkFlagRequired("from")
 	sendCmd.MarkFlagRequired("to")
 	sendCmd.MarkFlagRequired("seq")
@@ -0,0 +1,145 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+var (
+	sessionKeyFlag   string
+	txTypesFlag      []string
+	expiryFlag       uint64
+	revokeFlag       bool
+	destinationsFlag []string
+)
+
+// sessionKeyCmd represents the session key command
+// Example:
+//
+//	pandocli tx session_key --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --session_key=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --tx_types=send --destinations=0d2fD67d573c8ecB4161510fc00754d64B401F86 --ptx=100 --expiry=1200000 --seq=8
+//	pandocli tx session_key --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --session_key=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --revoke --seq=9
+var sessionKeyCmd = &cobra.Command{
+	Use:     "session_key",
+	Short:   "Register or revoke a session key which may sign transactions on behalf of the account",
+	Example: `pandocli tx session_key --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --session_key=df1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E --tx_types=send --ptx=100 --expiry=1200000 --seq=8`,
+	Run:     doSessionKeyCmd,
+}
+
+func doSessionKeyCmd(cmd *cobra.Command, args []string) {
+	if !revokeFlag && expiryFlag == 0 {
+		utils.Error("The expiry height must be specified\n")
+	}
+
+	txTypes := []types.TxType{}
+	for _, name := range txTypesFlag {
+		switch name {
+		case "send":
+			txTypes = append(txTypes, types.TxSend)
+		case "smart_contract":
+			txTypes = append(txTypes, types.TxSmartContract)
+		default:
+			utils.Error("Invalid tx type: %v, session keys can only sign send and smart_contract transactions\n", name)
+		}
+	}
+
+	destinations := []common.Address{}
+	for _, addr := range destinationsFlag {
+		destinations = append(destinations, common.HexToAddress(addr))
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	pando, ok := types.ParseCoinAmount(pandoAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse pando amount")
+	}
+	ptx, ok := types.ParseCoinAmount(ptxAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse ptx amount")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	sessionKeyTx := &types.SessionKeyTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Account: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		SessionKey: common.HexToAddress(sessionKeyFlag),
+		TxTypes:    txTypes,
+		SpendingLimit: types.Coins{
+			PandoWei: pando,
+			PTXWei:   ptx,
+		},
+		Destinations: destinations,
+		ExpiryHeight: expiryFlag,
+	}
+	if revokeFlag {
+		sessionKeyTx.TxTypes = []types.TxType{}
+		sessionKeyTx.Destinations = []common.Address{}
+		sessionKeyTx.SpendingLimit = types.NewCoins(0, 0)
+		sessionKeyTx.ExpiryHeight = 0
+	}
+
+	sig, err := wallet.Sign(fromAddress, sessionKeyTx.SignBytes(chainIDFlag))
+	if err != nil {
+		utils.Error("Failed to sign transaction: %v\n", err)
+	}
+	sessionKeyTx.SetSignature(fromAddress, sig)
+
+	raw, err := types.TxToBytes(sessionKeyTx)
+	if err != nil {
+		utils.Error("Failed to encode transaction: %v\n", err)
+	}
+	signedTx := hex.EncodeToString(raw)
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("pando.BroadcastRawTransaction", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+	if err != nil {
+		utils.Error("Failed to broadcast transaction: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Server returned error: %v\n", res.Error)
+	}
+	fmt.Printf("Successfully broadcasted transaction.\n")
+}
+
+func init() {
+	sessionKeyCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	sessionKeyCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the account granting the session key")
+	sessionKeyCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	sessionKeyCmd.Flags().StringVar(&sessionKeyFlag, "session_key", "", "Address of the session key")
+	sessionKeyCmd.Flags().StringSliceVar(&txTypesFlag, "tx_types", []string{"send"}, "Tx types the session key may sign (send|smart_contract)")
+	sessionKeyCmd.Flags().StringSliceVar(&destinationsFlag, "destinations", []string{}, "Addresses the session key may send to (default to any address)")
+	sessionKeyCmd.Flags().StringVar(&pandoAmountFlag, "pando", "0", "Cumulative Pando amount the session key may spend")
+	sessionKeyCmd.Flags().StringVar(&ptxAmountFlag, "ptx", "0", "Cumulative PTX amount the session key may spend, fees included")
+	sessionKeyCmd.Flags().Uint64Var(&expiryFlag, "expiry", 0, "Height after which the session key cannot sign")
+	sessionKeyCmd.Flags().BoolVar(&revokeFlag, "revoke", false, "Revoke the session key")
+	sessionKeyCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	sessionKeyCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	sessionKeyCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+
+	sessionKeyCmd.MarkFlagRequired("session_key")
+	sessionKeyCmd.MarkFlagRequired("seq")
+}
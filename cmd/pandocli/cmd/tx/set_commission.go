@@ -0,0 +1,92 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var commissionRateFlag uint64
+
+// setCommissionCmd represents the set commission command
+// Example:
+//
+//	pandocli tx set_commission --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --rate=500 --seq=8
+var setCommissionCmd = &cobra.Command{
+	Use:     "set_commission",
+	Short:   "Set the commission rate the validator keeps from the rewards of its delegators",
+	Long:    `Set the commission rate the validator keeps from the rewards of its delegators, in basis points. The rate can be changed once per reward epoch, by a limited amount.`,
+	Example: `pandocli tx set_commission --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --rate=500 --seq=8`,
+	Run:     doSetCommissionCmd,
+}
+
+func doSetCommissionCmd(cmd *cobra.Command, args []string) {
+	if commissionRateFlag > types.MaxCommissionRate {
+		utils.Error("Invalid input: rate must be at most %v basis points\n", types.MaxCommissionRate)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	setCommissionTx := &types.SetCommissionTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Validator: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		CommissionRate: commissionRateFlag,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			setCommissionTx.Validator.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, setCommissionTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		setCommissionTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(setCommissionTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	setCommissionCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	setCommissionCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the validator")
+	setCommissionCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	setCommissionCmd.Flags().Uint64Var(&commissionRateFlag, "rate", 0, "Commission rate in basis points, e.g. 500 for 5%")
+	setCommissionCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	setCommissionCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	setCommissionCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	setCommissionCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	setCommissionCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	setCommissionCmd.MarkFlagRequired("from")
+	setCommissionCmd.MarkFlagRequired("rate")
+	setCommissionCmd.MarkFlagRequired("seq")
+}
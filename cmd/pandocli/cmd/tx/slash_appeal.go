@@ -0,0 +1,176 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bondFlag      string
+	appellantFlag string
+)
+
+// slashAppealCmd represents the slash appeal command
+// Example:
+//
+//	pandocli tx slash_appeal --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --reserve_seq=3 --bond=10 --seq=8
+var slashAppealCmd = &cobra.Command{
+	Use:     "slash_appeal",
+	Short:   "Appeal the slash of a reserve fund by locking a bond",
+	Long:    `Appeal the slash of a reserve fund by locking a bond. If the validators approve the appeal in time, the slashed amount is reimbursed from the insurance pool and the bond is returned, otherwise the bond is forfeited to the insurance pool.`,
+	Example: `pandocli tx slash_appeal --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --reserve_seq=3 --bond=10 --seq=8`,
+	Run:     doSlashAppealCmd,
+}
+
+// slashAppealVoteCmd represents the slash appeal vote command
+// Example:
+//
+//	pandocli tx slash_appeal_vote --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --appellant=0d2fD67d573c8ecB4161510fc00754d64B401F86 --reserve_seq=3 --seq=9
+var slashAppealVoteCmd = &cobra.Command{
+	Use:     "slash_appeal_vote",
+	Short:   "Approve a pending slash appeal, only validators can vote",
+	Example: `pandocli tx slash_appeal_vote --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --appellant=0d2fD67d573c8ecB4161510fc00754d64B401F86 --reserve_seq=3 --seq=9`,
+	Run:     doSlashAppealVoteCmd,
+}
+
+func doSlashAppealCmd(cmd *cobra.Command, args []string) {
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	bond, ok := types.ParseCoinAmount(bondFlag)
+	if !ok {
+		utils.Error("Failed to parse bond")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	slashAppealTx := &types.SlashAppealTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Appellant: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		ReserveSequence: reserveSeqFlag,
+		Bond: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   bond,
+		},
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			slashAppealTx.Appellant.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, slashAppealTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		slashAppealTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(slashAppealTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func doSlashAppealVoteCmd(cmd *cobra.Command, args []string) {
+	if !common.IsHexAddress(appellantFlag) {
+		utils.Error("Invalid appellant address: %v\n", appellantFlag)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	slashAppealVoteTx := &types.SlashAppealVoteTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Voter: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		Appellant:       common.HexToAddress(appellantFlag),
+		ReserveSequence: reserveSeqFlag,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			slashAppealVoteTx.Voter.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, slashAppealVoteTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		slashAppealVoteTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(slashAppealVoteTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	slashAppealCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	slashAppealCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the slashed account")
+	slashAppealCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	slashAppealCmd.Flags().Uint64Var(&reserveSeqFlag, "reserve_seq", 0, "Reserve sequence of the slashed reserve fund")
+	slashAppealCmd.Flags().StringVar(&bondFlag, "bond", fmt.Sprintf("%dwei", types.MinimumSlashAppealBondPTXWei), "PTX amount locked as the bond of the appeal")
+	slashAppealCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	slashAppealCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	slashAppealCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	slashAppealCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	slashAppealCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	slashAppealCmd.MarkFlagRequired("reserve_seq")
+	slashAppealCmd.MarkFlagRequired("seq")
+
+	slashAppealVoteCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	slashAppealVoteCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the voting validator")
+	slashAppealVoteCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	slashAppealVoteCmd.Flags().StringVar(&appellantFlag, "appellant", "", "Address of the slashed account")
+	slashAppealVoteCmd.Flags().Uint64Var(&reserveSeqFlag, "reserve_seq", 0, "Reserve sequence of the slashed reserve fund")
+	slashAppealVoteCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	slashAppealVoteCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	slashAppealVoteCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	slashAppealVoteCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	slashAppealVoteCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	slashAppealVoteCmd.MarkFlagRequired("appellant")
+	slashAppealVoteCmd.MarkFlagRequired("reserve_seq")
+	slashAppealVoteCmd.MarkFlagRequired("seq")
+}
@@ -80,6 +80,14 @@ func doSmartContractCmd(cmd *cobra.Command, args []string) {
 		GasPrice: gasPrice,
 		Data:     data,
 	}
+	if smartContractTx.GasLimit == 0 {
+		gasLimit, err := EstimateGasLimit(smartContractTx)
+		if err != nil {
+			utils.Error("Failed to estimate gas: %v\n", err)
+		}
+		fmt.Printf("Estimated gas limit: %v\n", gasLimit)
+		smartContractTx.GasLimit = gasLimit
+	}
 
 	sig, err := wallet.Sign(fromAddress, smartContractTx.SignBytes(chainIDFlag))
 	if err != nil {
@@ -115,19 +123,17 @@ func doSmartContractCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	smartContractCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	smartContractCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	smartContractCmd.Flags().StringVar(&fromFlag, "from", "", "The caller address")
 	smartContractCmd.Flags().StringVar(&toFlag, "to", "", "The smart contract address")
 	smartContractCmd.Flags().StringVar(&valueFlag, "value", "0", "Value to be transferred")
 	smartContractCmd.Flags().StringVar(&gasPriceFlag, "gas_price", fmt.Sprintf("%dwei", types.MinimumGasPrice), "The gas price")
-	smartContractCmd.Flags().Uint64Var(&gasLimitFlag, "gas_limit", 0, "The gas limit")
+	smartContractCmd.Flags().Uint64Var(&gasLimitFlag, "gas_limit", 0, "The gas limit (default to the estimate of the remote node)")
 	smartContractCmd.Flags().StringVar(&dataFlag, "data", "", "The data for the smart contract")
 	smartContractCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
 	smartContractCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
 
-	smartContractCmd.MarkFlagRequired("chain")
 	smartContractCmd.MarkFlagRequired("from")
 	smartContractCmd.MarkFlagRequired("gas_price")
-	smartContractCmd.MarkFlagRequired("gas_limit")
 	smartContractCmd.MarkFlagRequired("seq")
 }
@@ -106,7 +106,7 @@ func doSplitRuleCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	splitRuleCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	splitRuleCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	splitRuleCmd.Flags().StringVar(&fromFlag, "from", "", "Initiator's address")
 	splitRuleCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
 	splitRuleCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
@@ -116,7 +116,6 @@ func init() {
 	splitRuleCmd.Flags().Uint64Var(&durationFlag, "duration", 1000, "Reserve duration")
 	splitRuleCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
 
-	splitRuleCmd.MarkFlagRequired("chain")
 	splitRuleCmd.MarkFlagRequired("from")
 	splitRuleCmd.MarkFlagRequired("seq")
 	splitRuleCmd.MarkFlagRequired("addresses")
@@ -0,0 +1,374 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	subchainChainIDFlag            string
+	subchainIDFlag                 string
+	subchainCheckpointIntervalFlag uint64
+	subchainWithdrawFlag           bool
+	subchainHeightFlag             uint64
+	subchainBlockHashFlag          string
+	subchainStateRootFlag          string
+	subchainSignaturesFlag         []string
+)
+
+// subchainRegisterCmd represents the subchain register command
+// Example:
+//
+//	pandocli tx subchain_register --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --subchain_chain_id=gamechain --deposit=1000 --checkpoint_interval=1000 --seq=8
+var subchainRegisterCmd = &cobra.Command{
+	Use:   "subchain_register",
+	Short: "Register a subchain",
+	Long: `Register an app-specific subchain secured by the main chain. The PTX deposit is locked for the lifetime
+of the subchain. The subchain is anchored by a checkpoint every --checkpoint_interval subchain blocks.`,
+	Example: `pandocli tx subchain_register --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --subchain_chain_id=gamechain --deposit=1000 --checkpoint_interval=1000 --seq=8`,
+	Run:     doSubchainRegisterCmd,
+}
+
+// subchainStakeCmd represents the subchain stake command
+// Example:
+//
+//	pandocli tx subchain_stake --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --subchain=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --stake=1000 --seq=9
+var subchainStakeCmd = &cobra.Command{
+	Use:   "subchain_stake",
+	Short: "Deposit or withdraw the stake of a subchain validator",
+	Long: `Deposit Pando as the stake of a subchain validator. With --withdraw, withdraw the whole stake instead. The
+withdrawn stake is returned after the fraud proof window, during which it can still be slashed.`,
+	Example: `pandocli tx subchain_stake --chain="pandonet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --subchain=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --stake=1000 --seq=9`,
+	Run:     doSubchainStakeCmd,
+}
+
+// subchainCheckpointCmd represents the subchain checkpoint command
+// Example:
+//
+//	pandocli tx subchain_checkpoint --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --subchain=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --height=1000 --block_hash=0x9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 --state_root=0x2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae --signatures=0x...,0x... --seq=3
+var subchainCheckpointCmd = &cobra.Command{
+	Use:   "subchain_checkpoint",
+	Short: "Anchor a subchain block on the main chain",
+	Long: `Anchor a subchain block on the main chain, along with the signatures of the subchain validators holding
+more than 2/3 of the stake. The checkpoint is final once its fraud proof window is over. The fee is paid by
+the --from account.`,
+	Example: `pandocli tx subchain_checkpoint --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --subchain=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --height=1000 --block_hash=0x9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 --state_root=0x2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae --signatures=0x...,0x... --seq=3`,
+	Run:     doSubchainCheckpointCmd,
+}
+
+// subchainChallengeCmd represents the subchain challenge command
+// Example:
+//
+//	pandocli tx subchain_challenge --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --subchain=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --height=1000 --block_hash=0x486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7 --state_root=0x2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae --signatures=0x...,0x... --seq=4
+var subchainChallengeCmd = &cobra.Command{
+	Use:   "subchain_challenge",
+	Short: "Challenge a pending subchain checkpoint with a conflicting one",
+	Long: `Challenge a pending subchain checkpoint with a conflicting block at the same height, signed by the subchain
+validators who also signed the pending checkpoint. The pending checkpoints from that height are reverted, and
+the equivocating validators are slashed, part of their stake rewarding the challenger.`,
+	Example: `pandocli tx subchain_challenge --chain="pandonet" --from=70f587259738cB626A1720Af7038B8DcDb6a42a0 --subchain=0x4d3e5c6f2a1b0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d --height=1000 --block_hash=0x486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7 --state_root=0x2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae --signatures=0x...,0x... --seq=4`,
+	Run:     doSubchainChallengeCmd,
+}
+
+func doSubchainRegisterCmd(cmd *cobra.Command, args []string) {
+	if err := types.CheckSubchainChainID(subchainChainIDFlag); err != nil {
+		utils.Error("Invalid input: %v\n", err)
+	}
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	deposit, ok := types.ParseCoinAmount(depositFlag)
+	if !ok {
+		utils.Error("Failed to parse deposit")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	subchainRegisterTx := &types.SubchainRegisterTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Owner: types.TxInput{
+			Address: fromAddress,
+			Coins: types.Coins{
+				PandoWei: new(big.Int).SetUint64(0),
+				PTXWei:   deposit,
+			},
+			Sequence: uint64(seqFlag),
+		},
+		ChainID:            subchainChainIDFlag,
+		CheckpointInterval: subchainCheckpointIntervalFlag,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			subchainRegisterTx.Owner.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, subchainRegisterTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		subchainRegisterTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(subchainRegisterTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+	fmt.Printf("Subchain ID: %v\n", types.SubchainID(subchainChainIDFlag).Hex())
+}
+
+func doSubchainStakeCmd(cmd *cobra.Command, args []string) {
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	stake := new(big.Int).SetUint64(0)
+	if !subchainWithdrawFlag {
+		var ok bool
+		stake, ok = types.ParseCoinAmount(stakeInPandoFlag)
+		if !ok {
+			utils.Error("Failed to parse stake")
+		}
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	subchainStakeTx := &types.SubchainStakeTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Staker: types.TxInput{
+			Address: fromAddress,
+			Coins: types.Coins{
+				PandoWei: stake,
+				PTXWei:   new(big.Int).SetUint64(0),
+			},
+			Sequence: uint64(seqFlag),
+		},
+		SubchainID: common.HexToHash(subchainIDFlag),
+		Withdraw:   subchainWithdrawFlag,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			subchainStakeTx.Staker.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, subchainStakeTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		subchainStakeTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(subchainStakeTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func doSubchainCheckpointCmd(cmd *cobra.Command, args []string) {
+	sigs := parseSubchainSignatures()
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	subchainCheckpointTx := &types.SubchainCheckpointTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Submitter: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		SubchainID: common.HexToHash(subchainIDFlag),
+		Height:     subchainHeightFlag,
+		BlockHash:  common.HexToHash(subchainBlockHashFlag),
+		StateRoot:  common.HexToHash(subchainStateRootFlag),
+		Signatures: sigs,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			subchainCheckpointTx.Submitter.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, subchainCheckpointTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		subchainCheckpointTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(subchainCheckpointTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func doSubchainChallengeCmd(cmd *cobra.Command, args []string) {
+	sigs := parseSubchainSignatures()
+
+	wallet, fromAddress, err := WalletUnlockWithPath(cmd, fromFlag, pathFlag)
+	if err != nil || wallet == nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	subchainChallengeTx := &types.SubchainChallengeTx{
+		Fee: types.Coins{
+			PandoWei: new(big.Int).SetUint64(0),
+			PTXWei:   fee,
+		},
+		Challenger: types.TxInput{
+			Address:  fromAddress,
+			Sequence: uint64(seqFlag),
+		},
+		SubchainID: common.HexToHash(subchainIDFlag),
+		Height:     subchainHeightFlag,
+		BlockHash:  common.HexToHash(subchainBlockHashFlag),
+		StateRoot:  common.HexToHash(subchainStateRootFlag),
+		Signatures: sigs,
+	}
+
+	result := broadcastTx(func(resign bool) (common.Bytes, error) {
+		if resign {
+			seq, err := GetNextSequence(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			subchainChallengeTx.Challenger.Sequence = seq
+		}
+		sig, err := wallet.Sign(fromAddress, subchainChallengeTx.SignBytes(chainIDFlag))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign transaction: %v", err)
+		}
+		subchainChallengeTx.SetSignature(fromAddress, sig)
+		return types.TxToBytes(subchainChallengeTx)
+	})
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+// parseSubchainSignatures parses the hex encoded signatures of the subchain validators
+func parseSubchainSignatures() []*crypto.Signature {
+	if len(subchainSignaturesFlag) == 0 || len(subchainSignaturesFlag) > types.MaximumSubchainStakers {
+		utils.Error("Invalid input: between 1 and %v signatures are required\n", types.MaximumSubchainStakers)
+	}
+	sigs := []*crypto.Signature{}
+	for _, encoded := range subchainSignaturesFlag {
+		sig, err := crypto.SignatureFromBytes(common.FromHex(encoded))
+		if err != nil {
+			utils.Error("Invalid input: failed to parse signature %v: %v\n", encoded, err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+func init() {
+	subchainRegisterCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	subchainRegisterCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the owner")
+	subchainRegisterCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	subchainRegisterCmd.Flags().StringVar(&subchainChainIDFlag, "subchain_chain_id", "", "Chain ID of the subchain")
+	subchainRegisterCmd.Flags().StringVar(&depositFlag, "deposit", fmt.Sprintf("%d", types.MinimumSubchainDepositPTX), "PTX amount of the registration deposit")
+	subchainRegisterCmd.Flags().Uint64Var(&subchainCheckpointIntervalFlag, "checkpoint_interval", 1000, "Number of subchain blocks between two checkpoints")
+	subchainRegisterCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	subchainRegisterCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	subchainRegisterCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	subchainRegisterCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	subchainRegisterCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	subchainRegisterCmd.MarkFlagRequired("from")
+	subchainRegisterCmd.MarkFlagRequired("subchain_chain_id")
+	subchainRegisterCmd.MarkFlagRequired("seq")
+
+	subchainStakeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+	subchainStakeCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the staker")
+	subchainStakeCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+	subchainStakeCmd.Flags().StringVar(&subchainIDFlag, "subchain", "", "ID of the subchain")
+	subchainStakeCmd.Flags().StringVar(&stakeInPandoFlag, "stake", "0", "Pando amount to stake")
+	subchainStakeCmd.Flags().BoolVar(&subchainWithdrawFlag, "withdraw", false, "Withdraw the whole stake instead")
+	subchainStakeCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+	subchainStakeCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	subchainStakeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+	subchainStakeCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	subchainStakeCmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+	subchainStakeCmd.MarkFlagRequired("from")
+	subchainStakeCmd.MarkFlagRequired("subchain")
+	subchainStakeCmd.MarkFlagRequired("seq")
+
+	for _, cmd := range []*cobra.Command{subchainCheckpointCmd, subchainChallengeCmd} {
+		cmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
+		cmd.Flags().StringVar(&fromFlag, "from", "", "Address of the account submitting the transaction")
+		cmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
+		cmd.Flags().StringVar(&subchainIDFlag, "subchain", "", "ID of the subchain")
+		cmd.Flags().Uint64Var(&subchainHeightFlag, "height", 0, "Height of the subchain block")
+		cmd.Flags().StringVar(&subchainBlockHashFlag, "block_hash", "", "Hash of the subchain block")
+		cmd.Flags().StringVar(&subchainStateRootFlag, "state_root", "", "State root of the subchain block")
+		cmd.Flags().StringSliceVar(&subchainSignaturesFlag, "signatures", []string{}, "Hex encoded signatures of the subchain validators")
+		cmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeePTXWei), "Fee")
+		cmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+		cmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|qr)")
+		cmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+		cmd.Flags().BoolVar(&retryFlag, "retry", true, "Retry the transient broadcast failures, and re-sign with a fresh sequence if needed")
+
+		cmd.MarkFlagRequired("from")
+		cmd.MarkFlagRequired("subchain")
+		cmd.MarkFlagRequired("height")
+		cmd.MarkFlagRequired("block_hash")
+		cmd.MarkFlagRequired("signatures")
+		cmd.MarkFlagRequired("seq")
+	}
+}
@@ -1,8 +1,12 @@
 package tx
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"strconv"
 	"strings"
 
@@ -11,18 +15,33 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
 	"github.com/pandotoken/pando/common"
+	ltypes "github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
 	"github.com/pandotoken/pando/wallet"
+	"github.com/pandotoken/pando/wallet/coldwallet"
 	"github.com/pandotoken/pando/wallet/types"
 	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
 )
 
 const HARDENED_FLAG = 1 << 31
 
 func walletUnlock(cmd *cobra.Command, addressStr string) (wtypes.Wallet, common.Address, error) {
-	return walletUnlockWithPath(cmd, addressStr, "")
+	return WalletUnlockWithPath(cmd, addressStr, "")
 }
 
-func walletUnlockWithPath(cmd *cobra.Command, addressStr string, path string) (wtypes.Wallet, common.Address, error) {
+// WalletUnlockWithPath unlocks the wallet selected by the --wallet flag of the command, the
+// derivation path only applies to the cold wallets. When the transaction is built with "tx build",
+// the wallet writes the unsigned transaction instead of signing it.
+func WalletUnlockWithPath(cmd *cobra.Command, addressStr string, path string) (wtypes.Wallet, common.Address, error) {
+	if len(unsignedFlag) != 0 {
+		if len(addressStr) == 0 {
+			return nil, common.Address{}, fmt.Errorf("The address of the signer is required to build an unsigned transaction")
+		}
+		return &unsignedWallet{address: common.HexToAddress(addressStr)}, common.HexToAddress(addressStr), nil
+	}
+
 	var wallet wtypes.Wallet
 	var address common.Address
 	var err error
@@ -31,7 +50,7 @@ func walletUnlockWithPath(cmd *cobra.Command, addressStr string, path string) (w
 		cfgPath := cmd.Flag("config").Value.String()
 		wallet, address, err = SoftWalletUnlock(cfgPath, addressStr)
 	} else {
-		derivationPath, err := parseDerivationPath(path, walletType)
+		derivationPath, err := ParseDerivationPath(path, walletType)
 		if err != nil {
 			return nil, common.Address{}, err
 		}
@@ -41,19 +60,30 @@ func walletUnlockWithPath(cmd *cobra.Command, addressStr string, path string) (w
 }
 
 func ColdWalletUnlock(walletType wtypes.WalletType, derivationPath types.DerivationPath) (wtypes.Wallet, common.Address, error) {
-	wallet, err := wallet.OpenWallet("", walletType, true)
-	if err != nil {
-		fmt.Printf("Failed to open wallet: %v\n", err)
-		return nil, common.Address{}, err
+	var w wtypes.Wallet
+	var err error
+	if walletType == wtypes.WalletTypeColdQR {
+		// The terminal shares the input of the prompts, e.g. the confirmation of the transaction
+		terminal := struct {
+			io.Reader
+			io.Writer
+		}{utils.Stdin(), os.Stdout}
+		w = coldwallet.NewQRWallet(terminal, qrFramesFlag)
+	} else {
+		w, err = wallet.OpenWallet("", walletType, true)
+		if err != nil {
+			fmt.Printf("Failed to open wallet: %v\n", err)
+			return nil, common.Address{}, err
+		}
 	}
 
-	err = wallet.Unlock(common.Address{}, "", derivationPath)
+	err = w.Unlock(common.Address{}, "", derivationPath)
 	if err != nil {
 		fmt.Printf("Failed to unlock wallet: %v\n", err)
 		return nil, common.Address{}, err
 	}
 
-	addresses, err := wallet.List()
+	addresses, err := w.List()
 	if err != nil {
 		fmt.Printf("Failed to list wallet addresses: %v\n", err)
 		return nil, common.Address{}, err
@@ -68,7 +98,7 @@ func ColdWalletUnlock(walletType wtypes.WalletType, derivationPath types.Derivat
 
 	log.Infof("Wallet address: %v", address)
 
-	return wallet, address, nil
+	return w, address, nil
 }
 
 func SoftWalletUnlock(cfgPath, addressStr string) (wtypes.Wallet, common.Address, error) {
@@ -101,18 +131,22 @@ func getWalletType(cmd *cobra.Command) (walletType wtypes.WalletType) {
 		walletType = wtypes.WalletTypeColdNano
 	} else if walletTypeStr == "trezor" {
 		walletType = wtypes.WalletTypeColdTrezor
+	} else if walletTypeStr == "qr" {
+		walletType = wtypes.WalletTypeColdQR
 	} else {
 		walletType = wtypes.WalletTypeSoft
 	}
 	return walletType
 }
 
-func parseDerivationPath(nstr string, walletType wtypes.WalletType) (types.DerivationPath, error) {
+// ParseDerivationPath parses a path like m/44'/60'/0'/0/0, the empty path is the default path of
+// the cold wallet type
+func ParseDerivationPath(nstr string, walletType wtypes.WalletType) (types.DerivationPath, error) {
 	if len(nstr) == 0 {
 		if walletType == wtypes.WalletTypeColdNano {
 			// nstr = "m/44'/60'/0'/0"
 			return types.DefaultRootDerivationPath, nil
-		} else if walletType == wtypes.WalletTypeColdTrezor {
+		} else if walletType == wtypes.WalletTypeColdTrezor || walletType == wtypes.WalletTypeColdQR {
 			// nstr = "m/44'/60'/0'/0/0"
 			return types.DefaultBaseDerivationPath, nil
 		} else {
@@ -160,3 +194,70 @@ func strToHarden(x string) (int, error) {
 func H(x int) int {
 	return x | HARDENED_FLAG
 }
+
+// broadcastTx broadcasts the transaction returned by build to the remote RPC endpoint,
+// retrying the transient failures unless --retry=false. On failure, the retry hints of
+// the error are printed as JSON on the second line for scripts to pick up.
+func broadcastTx(build rpc.TxBuilder) *rpc.BroadcastRawTransactionResult {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	policy := rpc.DefaultRetryPolicy
+	if !retryFlag {
+		policy = rpc.NoRetryPolicy
+	}
+	result, err := rpc.NewTxSender(client, policy).Send(build, asyncFlag)
+	if err != nil {
+		broadcastErr, ok := err.(*rpc.BroadcastError)
+		if !ok {
+			utils.Error("Failed to build transaction: %v\n", err)
+		}
+		hints, _ := json.Marshal(broadcastErr.ErrorData)
+		utils.Error("Failed to broadcast transaction: %v\n%s\n", broadcastErr, hints)
+	}
+	return result
+}
+
+// GetNextSequence returns the sequence number of the next transaction of the account,
+// accounting for its transactions pending in the mempool of the remote node
+func GetNextSequence(address common.Address) (uint64, error) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	res, err := client.Call("pando.GetAccount", rpc.GetAccountArgs{Address: address.Hex(), Preview: true})
+	if err != nil {
+		return 0, err
+	}
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	result := &rpc.GetAccountResult{Account: &ltypes.Account{}}
+	if err := res.GetObject(result); err != nil {
+		return 0, err
+	}
+	return result.Sequence + 1, nil
+}
+
+// EstimateGasLimit asks the remote node for the lowest gas limit the smart contract transaction
+// succeeds with. The VM error is returned with the revert reason if the transaction fails regardless.
+func EstimateGasLimit(sctx *ltypes.SmartContractTx) (uint64, error) {
+	sctxBytes, err := ltypes.TxToBytes(sctx)
+	if err != nil {
+		return 0, err
+	}
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	res, err := client.Call("pando.EstimateGas", rpc.EstimateGasArgs{SctxBytes: hex.EncodeToString(sctxBytes)})
+	if err != nil {
+		return 0, err
+	}
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	result := &rpc.EstimateGasResult{}
+	if err := res.GetObject(result); err != nil {
+		return 0, err
+	}
+	if result.VmError != "" {
+		if result.RevertReason != "" {
+			return 0, fmt.Errorf("the transaction fails: %v: %v", result.VmError, result.RevertReason)
+		}
+		return 0, fmt.Errorf("the transaction fails: %v", result.VmError)
+	}
+	return uint64(result.GasLimit), nil
+}
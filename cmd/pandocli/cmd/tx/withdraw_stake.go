@@ -26,7 +26,7 @@ var withdrawStakeCmd = &cobra.Command{
 }
 
 func doWithdrawStakeCmd(cmd *cobra.Command, args []string) {
-	wallet, sourceAddress, err := walletUnlockWithPath(cmd, sourceFlag, pathFlag)
+	wallet, sourceAddress, err := WalletUnlockWithPath(cmd, sourceFlag, pathFlag)
 	if err != nil {
 		return
 	}
@@ -80,7 +80,7 @@ func doWithdrawStakeCmd(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	withdrawStakeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	withdrawStakeCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID (default to the chain ID of --network)")
 	withdrawStakeCmd.Flags().StringVar(&sourceFlag, "source", "", "Source of the stake")
 	withdrawStakeCmd.Flags().StringVar(&holderFlag, "holder", "", "Holder of the stake")
 	withdrawStakeCmd.Flags().StringVar(&pathFlag, "path", "", "Wallet derivation path")
@@ -89,7 +89,6 @@ func init() {
 	withdrawStakeCmd.Flags().Uint8Var(&purposeFlag, "purpose", 0, "Purpose of staking")
 	withdrawStakeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
 
-	withdrawStakeCmd.MarkFlagRequired("chain")
 	withdrawStakeCmd.MarkFlagRequired("source")
 	withdrawStakeCmd.MarkFlagRequired("holder")
 	withdrawStakeCmd.MarkFlagRequired("seq")
@@ -4,10 +4,14 @@ import "github.com/spf13/viper"
 
 const (
 	CfgRemoteRPCEndpoint = "remoteRPCEndpoint"
+	CfgRemoteRPCIPCPath  = "remoteRPCIPCPath"
 	CfgDebug             = "debug"
+	CfgNetwork           = "network"
 )
 
 func init() {
 	viper.SetDefault(CfgRemoteRPCEndpoint, "http://localhost:16888/rpc")
+	viper.SetDefault(CfgRemoteRPCIPCPath, defaultIPCPath())
 	viper.SetDefault(CfgDebug, false)
+	viper.SetDefault(CfgNetwork, "")
 }
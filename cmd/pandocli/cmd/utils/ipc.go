@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+// IPCEndpointScheme is the scheme of the RPC endpoints served over a unix domain socket, e.g.
+// unix:///home/pando/.pando/pando.ipc
+const IPCEndpointScheme = "unix"
+
+// ipcRPCPath is the path the RPC is served at, over the socket as over TCP
+const ipcRPCPath = "/rpc"
+
+// LocalIPCEndpoint returns the RPC endpoint of the local node served over its unix domain socket,
+// false if the socket does not exist
+func LocalIPCEndpoint() (string, bool) {
+	ipcPath := viper.GetString(CfgRemoteRPCIPCPath)
+	if ipcPath == "" {
+		return "", false
+	}
+	info, err := os.Stat(ipcPath)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return "", false
+	}
+	return IPCEndpointScheme + "://" + ipcPath, true
+}
+
+func defaultIPCPath() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	return path.Join(home, ".pando", "pando.ipc")
+}
+
+// ipcTransport sends the requests to the unix:// endpoints over the unix domain socket in the
+// path of the endpoint
+type ipcTransport struct {
+	transports sync.Map // socket path -> *http.Transport
+}
+
+func (t *ipcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ipcPath := req.URL.Path
+	transport, ok := t.transports.Load(ipcPath)
+	if !ok {
+		transport, _ = t.transports.LoadOrStore(ipcPath, &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", ipcPath)
+			},
+		})
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = "localhost"
+	req.URL.Path = ipcRPCPath
+	req.Host = "localhost"
+	return transport.(*http.Transport).RoundTrip(req)
+}
+
+func init() {
+	// The RPC clients use the default HTTP client, which can then reach the unix:// endpoints
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.RegisterProtocol(IPCEndpointScheme, &ipcTransport{})
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+
+	"github.com/pandotoken/pando/core"
+)
+
+// nodeStatus holds the fields of the pando.GetStatus result needed here. The rpc package
+// is not imported since it depends on the ledger packages, whose tests import this package.
+type nodeStatus struct {
+	ChainID string `json:"chain_id"`
+}
+
+// SelectedNetwork returns the network selected with --network, if any.
+func SelectedNetwork() (core.Network, bool) {
+	name := viper.GetString(CfgNetwork)
+	if name == "" {
+		return core.Network{}, false
+	}
+	network, ok := core.LookupNetwork(name)
+	if !ok {
+		Error("Unknown network: %v\n", name)
+	}
+	return network, true
+}
+
+// ApplyNetworkDefaults points the RPC endpoint to the selected network, unless the
+// endpoint is configured explicitly. Without a selected network, the unix domain socket
+// of the local node is preferred if it exists. Should be called after the config is loaded.
+func ApplyNetworkDefaults() {
+	port := core.DefaultRPCPort
+	if network, ok := SelectedNetwork(); ok {
+		port = int(network.RPCPort)
+	} else if endpoint, ok := LocalIPCEndpoint(); ok {
+		viper.SetDefault(CfgRemoteRPCEndpoint, endpoint)
+		return
+	}
+	viper.SetDefault(CfgRemoteRPCEndpoint, fmt.Sprintf("http://localhost:%d/rpc", port))
+}
+
+// ResolveChainID returns the chain ID to sign transactions for. It defaults to the chain
+// ID of the selected network, and fails if the chain ID does not match the network or
+// the chain served by the RPC endpoint, since the signature would be invalid.
+func ResolveChainID(chainID string) string {
+	network, ok := SelectedNetwork()
+	if !ok {
+		if chainID == "" {
+			Error("Either --chain or --network needs to be specified\n")
+		}
+		return chainID
+	}
+
+	if chainID == "" {
+		chainID = network.ChainID
+	} else if chainID != network.ChainID {
+		Error("Chain ID %v does not match the %v network, which has chain ID %v\n", chainID, network.Name, network.ChainID)
+	}
+
+	client := rpcc.NewRPCClient(viper.GetString(CfgRemoteRPCEndpoint))
+	res, err := client.Call("pando.GetStatus", struct{}{})
+	if err != nil {
+		Error("Failed to get status from %v: %v\n", viper.GetString(CfgRemoteRPCEndpoint), err)
+	}
+	if res.Error != nil {
+		Error("Failed to get status from %v: %v\n", viper.GetString(CfgRemoteRPCEndpoint), res.Error)
+	}
+	status := &nodeStatus{}
+	if err := res.GetObject(status); err != nil {
+		Error("Failed to parse server response: %v\n", err)
+	}
+	if status.ChainID != chainID {
+		Error("The node at %v serves chain %v, not the %v network (chain ID %v)\n",
+			viper.GetString(CfgRemoteRPCEndpoint), status.ChainID, network.Name, chainID)
+	}
+
+	return chainID
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -30,11 +31,21 @@ func inputIsTty() bool {
 	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
 }
 
-func stdinLine() (string, error) {
+// Stdin returns the reader of the standard input shared by the prompts, so that the input buffered
+// by a prompt is not lost to the next reader
+func Stdin() io.Reader {
+	return stdinReader()
+}
+
+func stdinReader() *bufio.Reader {
 	if buf == nil {
 		buf = bufio.NewReader(os.Stdin)
 	}
-	line, err := buf.ReadString('\n')
+	return buf
+}
+
+func stdinLine() (string, error) {
+	line, err := stdinReader().ReadString('\n')
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+
+	homedir "github.com/mitchellh/go-homedir"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/pandotoken/pando/cmd/pandocli/cmd/utils"
+	"github.com/pandotoken/pando/common/util"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/signer"
+	ks "github.com/pandotoken/pando/wallet/softwallet/keystore"
+)
+
+var cfgPath string
+var keyPath string
+var socketPath string
+var password string
+var allowedUIDs []string
+var listenAddress string
+var tlsCert string
+var tlsKey string
+var tlsCA string
+var statePath string
+
+// RootCmd represents the base command when called without any subcommands
+var RootCmd = &cobra.Command{
+	Use:   "pandosigner",
+	Short: "Pando signer",
+	Long: `Pando signer holds the node key in a separate, minimally privileged process, and signs votes
+and blocks for the Pando node over a local socket. The node process never loads the key.
+
+The signer can also listen on a TCP address with mutual TLS, to be shared by an active and a
+standby validator node. It keeps the last vote and proposal it signed in its state file, and
+refuses to sign the conflicting ones, so that the two nodes cannot double sign.`,
+	Example: `pandosigner --config=../privatenet/node --allowed_uids=1001
+pandosigner --config=../privatenet/node --listen=10.0.0.5:15900 --tls_cert=signer.crt --tls_key=signer.key --tls_ca=ca.crt`,
+	Run: runSigner,
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := RootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	cobra.OnInitialize(initConfig)
+
+	RootCmd.Flags().StringVar(&cfgPath, "config", "", fmt.Sprintf("config path (default is %s)", getDefaultConfigPath()))
+	RootCmd.Flags().StringVar(&keyPath, "key", "", "key path (default to config path)")
+	RootCmd.Flags().StringVar(&socketPath, "socket", "", "path of the signer socket (default is <config path>/signer.sock)")
+	RootCmd.Flags().StringVar(&password, "password", "", "password of the node key")
+	RootCmd.Flags().StringSliceVar(&allowedUIDs, "allowed_uids", []string{}, "IDs of the users allowed to connect (default to the current user)")
+	RootCmd.Flags().StringVar(&listenAddress, "listen", "", "TCP address to listen on with mutual TLS instead of the socket")
+	RootCmd.Flags().StringVar(&tlsCert, "tls_cert", "", "certificate of the signer, for --listen")
+	RootCmd.Flags().StringVar(&tlsKey, "tls_key", "", "key of the signer certificate, for --listen")
+	RootCmd.Flags().StringVar(&tlsCA, "tls_ca", "", "CA certificate the node certificates are verified against, for --listen")
+	RootCmd.Flags().StringVar(&statePath, "state", "", "path of the double signing protection state (default is <config path>/signer_state.json)")
+}
+
+func initConfig() {
+	if cfgPath == "" {
+		cfgPath = getDefaultConfigPath()
+	}
+	if keyPath == "" {
+		keyPath = cfgPath
+	}
+	if socketPath == "" {
+		socketPath = path.Join(cfgPath, signer.DefaultSocketName)
+	}
+	if statePath == "" {
+		statePath = path.Join(cfgPath, signer.DefaultStateName)
+	}
+
+	util.InitLog()
+}
+
+func runSigner(cmd *cobra.Command, args []string) {
+	uids := []uint32{}
+	for _, s := range allowedUIDs {
+		uid, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+		if err != nil {
+			log.Fatalf("Invalid user ID: %v", s)
+		}
+		uids = append(uids, uint32(uid))
+	}
+
+	privKey, err := loadKey()
+	if err != nil {
+		log.Fatalf("Failed to load key: %v", err)
+	}
+	log.WithFields(log.Fields{
+		"address": privKey.PublicKey().Address().Hex(),
+	}).Info("Loaded key")
+
+	guard, err := signer.LoadGuard(statePath)
+	if err != nil {
+		log.Fatalf("Failed to load the signer state: %v", err)
+	}
+	state := guard.State()
+	log.WithFields(log.Fields{
+		"voteHeight":    state.VoteHeight,
+		"proposalEpoch": state.ProposalEpoch,
+	}).Info("Loaded signer state")
+
+	var server *signer.Server
+	address := socketPath
+	if listenAddress != "" {
+		tlsConfig, err := signer.LoadTLSConfig(tlsCert, tlsKey, tlsCA)
+		if err != nil {
+			log.Fatalf("Failed to load the TLS config: %v", err)
+		}
+		server = signer.NewTLSServer(privKey, listenAddress, tlsConfig)
+		address = listenAddress
+	} else {
+		server = signer.NewServer(privKey, socketPath, uids)
+	}
+	server.SetGuard(guard)
+	if err := server.Listen(); err != nil {
+		log.Fatalf("Failed to listen on %v: %v", address, err)
+	}
+
+	// Everything the signer needs is set up, drop the privileges.
+	if err := signer.Harden(); err != nil {
+		log.Fatalf("Failed to harden the signer process: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		signal.Stop(c)
+		cancel()
+	}()
+
+	server.Start(ctx)
+	server.Wait()
+	log.Infof("Graceful exit.")
+}
+
+// loadKey loads the node key from the encrypted keystore. The key is created by the
+// Pando node on its first launch.
+func loadKey() (*crypto.PrivateKey, error) {
+	keysDir := path.Join(keyPath, "key")
+	keystore, err := ks.NewKeystoreEncrypted(keysDir, ks.StandardScryptN, ks.StandardScryptP)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create key store: %v", err)
+	}
+	addresses, err := keystore.ListKeyAddresses()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get key address: %v", err)
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("No key found under %v", path.Join(keysDir, "encrypted"))
+	}
+	if len(addresses) > 1 {
+		return nil, fmt.Errorf("Multiple encrypted keys detected under %v. Please keep only one key.", path.Join(keysDir, "encrypted"))
+	}
+
+	if len(password) == 0 {
+		password, err = utils.GetPassword("Please enter the password of the node key: ")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get password: %v", err)
+		}
+	}
+
+	key, err := keystore.GetKey(addresses[0], password)
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey, nil
+}
+
+// getDefaultConfigPath returns the default config path.
+func getDefaultConfigPath() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return path.Join(home, ".pando")
+}
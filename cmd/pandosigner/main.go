@@ -0,0 +1,7 @@
+package main
+
+import "github.com/pandotoken/pando/cmd/pandosigner/cmd"
+
+func main() {
+	cmd.Execute()
+}
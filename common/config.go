@@ -16,11 +16,34 @@ const (
 
 	// CfgForceValidateSnapshot defines wether validation of snapshot can be skipped
 	CfgForceValidateSnapshot = "snapshot.force_validate"
+	// CfgSnapshotGenerateInterval sets the number of finalized blocks between the snapshots generated in
+	// the background, 0 to disable.
+	CfgSnapshotGenerateInterval = "snapshot.generateInterval"
+	// CfgSnapshotGenerateDir sets the directory of the generated snapshots (default to <config>/backup/snapshot).
+	CfgSnapshotGenerateDir = "snapshot.generateDir"
+	// CfgSnapshotGenerateRetain sets the number of generated snapshots to keep, 0 to keep all.
+	CfgSnapshotGenerateRetain = "snapshot.generateRetain"
+	// CfgSnapshotGenerateWriteLimit sets the maximal write rate (in KB/s) of the snapshot generation, 0 for
+	// unlimited. The generation needs to finish before the state of the snapshot block is pruned, see
+	// CfgStorageStatePruningRetainedBlocks.
+	CfgSnapshotGenerateWriteLimit = "snapshot.generateWriteLimit"
 
 	// CfgGenesisHash defines the hash of the genesis block
 	CfgGenesisHash = "genesis.hash"
 	// CfgGenesisChainID defines the chainID.
 	CfgGenesisChainID = "genesis.chainID"
+	// CfgGenesisSkipHashCheck allows the node to start with a genesis block that does not match the expected hash.
+	CfgGenesisSkipHashCheck = "genesis.skipHashCheck"
+	// CfgGenesisSigners sets the addresses of the release keys trusted to sign the genesis block. The node
+	// refuses to start unless the genesis block is signed by enough of them, see core.GenesisSignatures.
+	CfgGenesisSigners = "genesis.signers"
+	// CfgGenesisSignatureThreshold sets the number of the trusted release keys required to sign the genesis block.
+	CfgGenesisSignatureThreshold = "genesis.signatureThreshold"
+	// CfgGenesisSignatureFile sets the signature file of the genesis block (default to <config>/genesis.sig).
+	CfgGenesisSignatureFile = "genesis.signatureFile"
+
+	// CfgForkHeights overrides the activation heights of the forks by name, for the private and the test networks.
+	CfgForkHeights = "fork.heights"
 
 	// CfgConsensusMaxEpochLength defines the maxium length of an epoch.
 	CfgConsensusMaxEpochLength = "consensus.maxEpochLength"
@@ -30,6 +53,17 @@ const (
 	CfgConsensusMessageQueueSize = "consensus.messageQueueSize"
 	// CfgConsensusPassThroughGuardianVote defines the how guardian vote is handled.
 	CfgConsensusPassThroughGuardianVote = "consensus.passThroughGuardianVote"
+	// CfgConsensusRestartManifest sets the restart manifest signed by the validators to resume a
+	// halted chain from, see core.RestartManifest.
+	CfgConsensusRestartManifest = "consensus.restartManifest"
+	// CfgConsensusParallelExecutionWorkers sets the number of workers executing the independent transactions
+	// of a block in parallel. The transactions are executed serially if it is 0 or 1.
+	CfgConsensusParallelExecutionWorkers = "consensus.parallelExecutionWorkers"
+	// CfgConsensusSignatureVerificationWorkers sets the number of workers recovering the signers of the
+	// transactions ahead of their execution. The signatures are only verified during the execution if it is 0.
+	CfgConsensusSignatureVerificationWorkers = "consensus.signatureVerificationWorkers"
+	// CfgConsensusSignatureCacheSize sets the number of recovered signers kept in memory, 0 disables the cache.
+	CfgConsensusSignatureCacheSize = "consensus.signatureCacheSize"
 
 	// CfgStorageStatePruningEnabled indicates whether state pruning is enabled
 	CfgStorageStatePruningEnabled = "storage.statePruningEnabled"
@@ -39,10 +73,50 @@ const (
 	CfgStorageStatePruningRetainedBlocks = "storage.statePruningRetainedBlocks"
 	// CfgStorageStatePruningSkipCheckpoints indicates if the checkpoint state trie should be retained
 	CfgStorageStatePruningSkipCheckpoints = "storage.statePruningSkipCheckpoints"
+	// CfgStorageArchive indicates whether to run an archive node, which retains the states of all the
+	// blocks for the historical queries. The states pruned from the hot database are frozen into the
+	// ancient database instead of being deleted.
+	CfgStorageArchive = "storage.archive"
+	// CfgStorageAncientPath sets the folder of the ancient database of an archive node (default to db/ancient
+	// under the data path)
+	CfgStorageAncientPath = "storage.ancientPath"
+	// CfgStorageFreezerEnabled indicates whether to move the old finalized blocks, along with their votes and
+	// receipts, out of the key-value store into the append-only freezer files
+	CfgStorageFreezerEnabled = "storage.freezerEnabled"
+	// CfgStorageFreezerThreshold indicates the number of blocks prior to the latest finalized block kept in the
+	// key-value store
+	CfgStorageFreezerThreshold = "storage.freezerThreshold"
+	// CfgStorageFreezerPath sets the folder of the freezer (default to db/freezer under the data path)
+	CfgStorageFreezerPath = "storage.freezerPath"
+	// CfgStorageBackend selects the backend of the node database: leveldb, rocksdb or pebble. The rocksdb
+	// and pebble backends need to be compiled in with the build tags of the same name. The cache size and
+	// the handle count of the LevelDB settings apply to all the backends.
+	CfgStorageBackend = "storage.backend"
 	// CfgStorageLevelDBCacheSize indicates Level DB cache size
 	CfgStorageLevelDBCacheSize = "storage.levelDBCacheSize"
 	// CfgStorageLevelDBHandles indicates Level DB handle count
 	CfgStorageLevelDBHandles = "storage.levelDBHandles"
+	// CfgStorageAccountHistoryIndex indicates whether to index the transactions sent or received by
+	// each account, for the pando.GetTransactionHistory RPC
+	CfgStorageAccountHistoryIndex = "storage.accountHistoryIndex"
+	// CfgStorageFeeStatsIndex indicates whether to aggregate the transaction fees of the finalized
+	// blocks into the per-block, hourly and daily statistics, for the pando.GetFeeStats RPC
+	CfgStorageFeeStatsIndex = "storage.feeStatsIndex"
+	// CfgStorageReserveFundIndex indicates whether to aggregate the settled service payments of the
+	// finalized blocks into the usage of each reserve fund, for the pando.GetReserveFund RPC
+	CfgStorageReserveFundIndex = "storage.reserveFundIndex"
+	// CfgStorageAccountActivityIndex indicates whether to summarize the activity of each address, i.e. its
+	// first and last transactions and its top counterparties, for the pando.GetAccountActivity RPC
+	CfgStorageAccountActivityIndex = "storage.accountActivityIndex"
+	// CfgStorageTokenIndex indicates whether to index the transfers of the ERC-20 compatible tokens, and the
+	// token balances of each address, for the pando.GetTokenBalances and pando.GetTokenTransfers RPCs
+	CfgStorageTokenIndex = "storage.tokenIndex"
+	// CfgStorageNFTIndex indicates whether to index the transfers of the ERC-721 and ERC-1155 tokens, and
+	// their current owners, for the pando.GetNFTsByOwner and pando.GetNFTHistory RPCs
+	CfgStorageNFTIndex = "storage.nftIndex"
+	// CfgStorageMigrationBackup determines whether to back up the database before migrating it to
+	// a newer schema version at startup.
+	CfgStorageMigrationBackup = "storage.migrationBackup"
 
 	// CfgSyncMessageQueueSize defines the capacity of Sync Manager message queue.
 	CfgSyncMessageQueueSize = "sync.messageQueueSize"
@@ -50,6 +124,24 @@ const (
 	CfgSyncDownloadByHash = "sync.downloadByHash"
 	// CfgSyncDownloadByHeader indicates whether should download blocks using header.
 	CfgSyncDownloadByHeader = "sync.downloadByHeader"
+	// CfgSyncSnapshotSync indicates whether a fresh node should download the latest snapshot from
+	// the peers instead of syncing from the genesis.
+	CfgSyncSnapshotSync = "sync.snapshotSync"
+	// CfgSyncSnapshotMinPeers sets the minimal number of peers which need to serve the same snapshot
+	// before it is downloaded.
+	CfgSyncSnapshotMinPeers = "sync.snapshotMinPeers"
+	// CfgSyncSnapshotTimeoutSecs sets how long (in seconds) a fresh node waits for the snapshot before
+	// falling back to syncing from the genesis.
+	CfgSyncSnapshotTimeoutSecs = "sync.snapshotTimeoutSecs"
+	// CfgSyncSnapshotServeDir sets the folder of the snapshots served to the peers, the latest snapshot
+	// in the folder is served. Snapshots are not served if empty.
+	CfgSyncSnapshotServeDir = "sync.snapshotServeDir"
+	// CfgSyncWeakSubjectivityCheckpoints sets the weak subjectivity checkpoints in the "height:hash" format.
+	// The blocks conflicting with a checkpoint are rejected.
+	CfgSyncWeakSubjectivityCheckpoints = "sync.weakSubjectivityCheckpoints"
+	// CfgSyncWeakSubjectivityPeriodSecs sets the trust period (in seconds). Once checkpoints are configured,
+	// the blocks older than the trust period are only synced up to the latest checkpoint.
+	CfgSyncWeakSubjectivityPeriodSecs = "sync.weakSubjectivityPeriodSecs"
 
 	// CfgP2POpt sets which P2P network to use: p2p, libp2p, or both.
 	CfgP2POpt = "p2p.opt"
@@ -93,20 +185,85 @@ const (
 	CfgP2PNatMapping = "p2p.natMapping"
 	// CfgP2PMaxConnections specifies the number of max connections a node can accept
 	CfgP2PMaxConnections = "p2p.maxConnections"
+	// CfgP2PSentryMode sets whether the node is a validator only reachable through its sentry nodes
+	CfgP2PSentryMode = "p2p.sentryMode"
+	// CfgP2PSentries sets the addresses of the sentry nodes of the validator, which replace the seeds
+	CfgP2PSentries = "p2p.sentries"
+	// CfgP2PSentryCheckIntervalSecs sets the interval of the sentry health checks
+	CfgP2PSentryCheckIntervalSecs = "p2p.sentryCheckIntervalSecs"
+	// CfgP2PPexEnabled sets whether the node periodically exchanges healthy peer addresses with its peers
+	CfgP2PPexEnabled = "p2p.pexEnabled"
+	// CfgP2PPexIntervalSecs sets the interval of the peer address exchanges
+	CfgP2PPexIntervalSecs = "p2p.pexIntervalSecs"
 
 	// CfgSyncInboundResponseWhitelist filters inbound messages based on peer ID.
 	CfgSyncInboundResponseWhitelist = "sync.inboundResponseWhitelist"
 
+	// CfgMempoolMaxSize sets the maximum number of transactions in the mempool. When the mempool is full,
+	// the lowest priced transactions are evicted to make room for the higher priced ones.
+	CfgMempoolMaxSize = "mempool.maxSize"
+	// CfgMempoolInclusionAudit enables auditing the transactions included by the block proposers
+	// against the local mempool.
+	CfgMempoolInclusionAudit = "mempool.inclusionAudit"
+	// CfgMempoolInclusionAuditGracePeriod sets how long (in seconds) a transaction needs to be pending
+	// before the block timestamp to be expected in the block.
+	CfgMempoolInclusionAuditGracePeriod = "mempool.inclusionAuditGracePeriod"
+
+	// CfgSchedulerEnabled sets whether to run the scheduler of the periodic operator tasks.
+	CfgSchedulerEnabled = "scheduler.enabled"
+	// CfgSchedulerHistorySize sets the number of task runs retained for the RPC.
+	CfgSchedulerHistorySize = "scheduler.historySize"
+	// CfgSchedulerSnapshotSchedule sets the cron-like schedule of the snapshot export, empty to disable.
+	CfgSchedulerSnapshotSchedule = "scheduler.snapshot.schedule"
+	// CfgSchedulerSnapshotDir sets the directory of the exported snapshots (default to <config>/backup/snapshot).
+	CfgSchedulerSnapshotDir = "scheduler.snapshot.dir"
+	// CfgSchedulerSnapshotRetain sets the number of exported snapshots to keep, 0 to keep all.
+	CfgSchedulerSnapshotRetain = "scheduler.snapshot.retain"
+	// CfgSchedulerCompactionSchedule sets the cron-like schedule of the database compaction, empty to disable.
+	CfgSchedulerCompactionSchedule = "scheduler.compaction.schedule"
+	// CfgSchedulerStakeCompoundSchedule sets the cron-like schedule of the stake compounding, empty to disable.
+	CfgSchedulerStakeCompoundSchedule = "scheduler.stakeCompound.schedule"
+	// CfgSchedulerStakeCompoundSource sets the address of the staker account.
+	CfgSchedulerStakeCompoundSource = "scheduler.stakeCompound.source"
+	// CfgSchedulerStakeCompoundHolder sets the address of the validator or guardian to deposit the stake to.
+	CfgSchedulerStakeCompoundHolder = "scheduler.stakeCompound.holder"
+	// CfgSchedulerStakeCompoundPurpose sets the stake purpose, 0 for validator and 1 for guardian.
+	CfgSchedulerStakeCompoundPurpose = "scheduler.stakeCompound.purpose"
+	// CfgSchedulerStakeCompoundReserve sets the amount of PTX to keep in the staker account, e.g. "10" or "10000wei".
+	CfgSchedulerStakeCompoundReserve = "scheduler.stakeCompound.reserve"
+	// CfgSchedulerStakeCompoundKeysDir sets the keystore directory holding the encrypted key of the staker account
+	// (default to the key directory of the node).
+	CfgSchedulerStakeCompoundKeysDir = "scheduler.stakeCompound.keysDir"
+	// CfgSchedulerStakeCompoundPasswordFile sets the file containing the password of the staker key.
+	CfgSchedulerStakeCompoundPasswordFile = "scheduler.stakeCompound.passwordFile"
+	// CfgSchedulerRametronAttestationSchedule sets the cron-like schedule of the attestation of the heartbeats the
+	// guardian received from the Rametron nodes, empty to disable. The node then accepts the heartbeats over the RPC.
+	CfgSchedulerRametronAttestationSchedule = "scheduler.rametronAttestation.schedule"
+
 	// CfgRPCEnabled sets whether to run RPC service.
 	CfgRPCEnabled = "rpc.enabled"
 	// CfgRPCAddress sets the binding address of RPC service.
 	CfgRPCAddress = "rpc.address"
 	// CfgRPCPort sets the port of RPC service.
 	CfgRPCPort = "rpc.port"
+	// CfgRPCTCPEnabled sets whether to serve the RPC over TCP, on the address and port above.
+	CfgRPCTCPEnabled = "rpc.tcp.enabled"
+	// CfgRPCIPCEnabled sets whether to serve the RPC over a unix domain socket for the local tools.
+	CfgRPCIPCEnabled = "rpc.ipc.enabled"
+	// CfgRPCIPCPath sets the path of the unix domain socket (default to pando.ipc in the config path).
+	CfgRPCIPCPath = "rpc.ipc.path"
+	// CfgRPCIPCMode sets the file permissions of the unix domain socket, in octal.
+	CfgRPCIPCMode = "rpc.ipc.mode"
 	// CfgRPCMaxConnections limits concurrent connections accepted by RPC server.
 	CfgRPCMaxConnections = "rpc.maxConnections"
 	// CfgRPCTimeoutSecs set a timeout for RPC.
 	CfgRPCTimeoutSecs = "rpc.timeoutSecs"
+	// CfgRPCMaxSubscriptionsPerConnection limits the subscriptions of a websocket connection.
+	CfgRPCMaxSubscriptionsPerConnection = "rpc.maxSubscriptionsPerConnection"
+	// CfgRPCCallCacheSize sets the number of read-only contract call results cached by the RPC, 0 to disable the cache.
+	CfgRPCCallCacheSize = "rpc.callCache.size"
+	// CfgRPCCallCacheTTLSecs sets how long the result of a read-only contract call is cached.
+	CfgRPCCallCacheTTLSecs = "rpc.callCache.ttlSecs"
 
 	// CfgLogLevels sets the log level.
 	CfgLogLevels = "log.levels"
@@ -122,6 +279,28 @@ const (
 
 	// CfgProfEnabled to enable profiling
 	CfgProfEnabled = "prof.enabled"
+	// CfgProfAddress sets the binding address of the profiling endpoints.
+	CfgProfAddress = "prof.address"
+	// CfgProfAuthToken sets the token required to access the profiling endpoints. A random
+	// token is generated and saved under the config folder if not specified.
+	CfgProfAuthToken = "prof.authToken"
+	// CfgProfBlockProfileRate sets the initial sampling rate of the block profile, 0 to disable.
+	CfgProfBlockProfileRate = "prof.blockProfileRate"
+	// CfgProfMutexProfileFraction sets the initial sampling fraction of the mutex profile, 0 to disable.
+	CfgProfMutexProfileFraction = "prof.mutexProfileFraction"
+
+	// CfgSignerSocket sets the socket of the pandosigner process holding the node key. The
+	// node loads the key from its keystore if not specified.
+	CfgSignerSocket = "signer.socket"
+	// CfgSignerAddress sets the TCP address of a pandosigner shared with a standby node, which is
+	// connected with mutual TLS. Takes precedence over CfgSignerSocket.
+	CfgSignerAddress = "signer.address"
+	// CfgSignerTLSCert sets the certificate of the node presented to the signer.
+	CfgSignerTLSCert = "signer.tlsCert"
+	// CfgSignerTLSKey sets the key of the certificate of the node.
+	CfgSignerTLSKey = "signer.tlsKey"
+	// CfgSignerTLSCA sets the CA certificate the signer certificate is verified against.
+	CfgSignerTLSCA = "signer.tlsCA"
 
 	// CfgForceGCEnabled to enable force GC
 	CfgForceGCEnabled = "gc.enabled"
@@ -141,22 +320,70 @@ p2p:
 
 func init() {
 	viper.SetDefault(CfgForceValidateSnapshot, false)
+	viper.SetDefault(CfgSnapshotGenerateInterval, 0)
+	viper.SetDefault(CfgSnapshotGenerateDir, "")
+	viper.SetDefault(CfgSnapshotGenerateRetain, 3)
+	viper.SetDefault(CfgSnapshotGenerateWriteLimit, 20480)
+	viper.SetDefault(CfgGenesisSkipHashCheck, false)
+	viper.SetDefault(CfgGenesisSigners, []string{})
+	viper.SetDefault(CfgGenesisSignatureThreshold, 1)
+	viper.SetDefault(CfgGenesisSignatureFile, "")
 
 	viper.SetDefault(CfgConsensusMaxEpochLength, 10)
 	viper.SetDefault(CfgConsensusMinProposalWait, 6)
 	viper.SetDefault(CfgConsensusMessageQueueSize, 512)
 	viper.SetDefault(CfgConsensusPassThroughGuardianVote, false)
+	viper.SetDefault(CfgConsensusRestartManifest, "")
+	viper.SetDefault(CfgConsensusParallelExecutionWorkers, 0)
+	viper.SetDefault(CfgConsensusSignatureVerificationWorkers, 4)
+	viper.SetDefault(CfgConsensusSignatureCacheSize, 65536)
 
 	viper.SetDefault(CfgSyncMessageQueueSize, 512)
 	viper.SetDefault(CfgSyncDownloadByHash, false)
 	viper.SetDefault(CfgSyncDownloadByHeader, true)
+	viper.SetDefault(CfgSyncSnapshotSync, false)
+	viper.SetDefault(CfgSyncSnapshotMinPeers, 2)
+	viper.SetDefault(CfgSyncSnapshotTimeoutSecs, 3600)
+	viper.SetDefault(CfgSyncSnapshotServeDir, "")
+	viper.SetDefault(CfgSyncWeakSubjectivityCheckpoints, []string{})
+	viper.SetDefault(CfgSyncWeakSubjectivityPeriodSecs, 14*24*3600)
 
 	viper.SetDefault(CfgStorageStatePruningEnabled, true)
 	viper.SetDefault(CfgStorageStatePruningInterval, 16)
 	viper.SetDefault(CfgStorageStatePruningRetainedBlocks, 2048)
 	viper.SetDefault(CfgStorageStatePruningSkipCheckpoints, true)
+	viper.SetDefault(CfgStorageArchive, false)
+	viper.SetDefault(CfgStorageAncientPath, "")
+	viper.SetDefault(CfgStorageFreezerEnabled, false)
+	viper.SetDefault(CfgStorageFreezerThreshold, 90000)
+	viper.SetDefault(CfgStorageFreezerPath, "")
+	viper.SetDefault(CfgStorageBackend, "leveldb")
 	viper.SetDefault(CfgStorageLevelDBCacheSize, 256)
 	viper.SetDefault(CfgStorageLevelDBHandles, 16)
+	viper.SetDefault(CfgStorageAccountHistoryIndex, false)
+	viper.SetDefault(CfgStorageFeeStatsIndex, false)
+	viper.SetDefault(CfgStorageReserveFundIndex, false)
+	viper.SetDefault(CfgStorageAccountActivityIndex, false)
+	viper.SetDefault(CfgStorageTokenIndex, false)
+	viper.SetDefault(CfgStorageNFTIndex, false)
+	viper.SetDefault(CfgStorageMigrationBackup, true)
+
+	viper.SetDefault(CfgMempoolMaxSize, 25600)
+	viper.SetDefault(CfgMempoolInclusionAudit, false)
+	viper.SetDefault(CfgMempoolInclusionAuditGracePeriod, 12)
+
+	viper.SetDefault(CfgSchedulerEnabled, false)
+	viper.SetDefault(CfgSchedulerHistorySize, 100)
+	viper.SetDefault(CfgSchedulerSnapshotSchedule, "")
+	viper.SetDefault(CfgSchedulerSnapshotDir, "")
+	viper.SetDefault(CfgSchedulerSnapshotRetain, 3)
+	viper.SetDefault(CfgSchedulerCompactionSchedule, "")
+	viper.SetDefault(CfgSchedulerStakeCompoundSchedule, "")
+	viper.SetDefault(CfgSchedulerStakeCompoundPurpose, 1)
+	viper.SetDefault(CfgSchedulerStakeCompoundReserve, "0")
+	viper.SetDefault(CfgSchedulerStakeCompoundKeysDir, "")
+	viper.SetDefault(CfgSchedulerStakeCompoundPasswordFile, "")
+	viper.SetDefault(CfgSchedulerRametronAttestationSchedule, "")
 
 	viper.SetDefault(CfgRPCEnabled, false)
 	viper.SetDefault(CfgP2PMessageQueueSize, 512)
@@ -177,11 +404,23 @@ func init() {
 	viper.SetDefault(CfgP2PConnectionFIFO, false)
 	viper.SetDefault(CfgP2PNatMapping, false)
 	viper.SetDefault(CfgP2PMaxConnections, 2048)
+	viper.SetDefault(CfgP2PSentryMode, false)
+	viper.SetDefault(CfgP2PSentries, "")
+	viper.SetDefault(CfgP2PSentryCheckIntervalSecs, 30)
+	viper.SetDefault(CfgP2PPexEnabled, true)
+	viper.SetDefault(CfgP2PPexIntervalSecs, 60)
 
 	viper.SetDefault(CfgRPCAddress, "0.0.0.0")
 	viper.SetDefault(CfgRPCPort, "16888")
+	viper.SetDefault(CfgRPCTCPEnabled, true)
+	viper.SetDefault(CfgRPCIPCEnabled, true)
+	viper.SetDefault(CfgRPCIPCPath, "")
+	viper.SetDefault(CfgRPCIPCMode, "0600")
 	viper.SetDefault(CfgRPCMaxConnections, 200)
 	viper.SetDefault(CfgRPCTimeoutSecs, 60)
+	viper.SetDefault(CfgRPCMaxSubscriptionsPerConnection, 100)
+	viper.SetDefault(CfgRPCCallCacheSize, 4096)
+	viper.SetDefault(CfgRPCCallCacheTTLSecs, 10)
 
 	viper.SetDefault(CfgLogLevels, "*:debug")
 	viper.SetDefault(CfgLogPrintSelfID, false)
@@ -191,6 +430,16 @@ func init() {
 	viper.SetDefault(CfgMetricsServer, "")
 
 	viper.SetDefault(CfgProfEnabled, false)
+	viper.SetDefault(CfgProfAddress, "127.0.0.1:6060")
+	viper.SetDefault(CfgProfAuthToken, "")
+	viper.SetDefault(CfgProfBlockProfileRate, 0)
+	viper.SetDefault(CfgProfMutexProfileFraction, 0)
+	viper.SetDefault(CfgSignerSocket, "")
+	viper.SetDefault(CfgSignerAddress, "")
+	viper.SetDefault(CfgSignerTLSCert, "")
+	viper.SetDefault(CfgSignerTLSKey, "")
+	viper.SetDefault(CfgSignerTLSCA, "")
+
 	viper.SetDefault(CfgForceGCEnabled, true)
 }
 
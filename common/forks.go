@@ -0,0 +1,51 @@
+package common
+
+// Fork is a protocol change activated at a block height. The nodes advertise the forks their binary
+// supports to their peers, so that the operators can be warned of the upgrades they have missed.
+type Fork struct {
+	Name   string
+	Height uint64
+}
+
+// SupportedForks returns the forks supported by this binary, in the order they were introduced, with
+// their default heights. A fork needs to be added here along with its height in heights.go, and its
+// name in the features package, which gates the behavior changes on the heights in effect.
+func SupportedForks() []Fork {
+	return []Fork{
+		{"ValidatorReward", HeightEnableValidatorReward},
+		{"Pando2", HeightEnablePando2},
+		{"LowerGNStakeThresholdTo1000", HeightLowerGNStakeThresholdTo1000},
+		{"SmartContract", HeightEnableSmartContract},
+		{"SampleStakingReward", HeightSampleStakingReward},
+		{"MultiCurrencyReservedFund", HeightEnableMultiCurrencyReservedFund},
+		{"SessionKeys", HeightEnableSessionKeys},
+		{"TypedSigning", HeightEnableTypedSigning},
+		{"MultiSig", HeightEnableMultiSig},
+		{"BatchSendTx", HeightEnableBatchSendTx},
+		{"SendTxData", HeightEnableSendTxData},
+		{"EthTxSigning", HeightEnableEthTxSigning},
+		{"SlashAppeals", HeightEnableSlashAppeals},
+		{"BlockHash", HeightEnableBlockHash},
+		{"Escrow", HeightEnableEscrow},
+		{"CanonicalSigning", HeightEnableCanonicalSigning},
+		{"AggregatedVotes", HeightEnableAggregatedVotes},
+		{"ThresholdVotes", HeightEnableThresholdVotes},
+		{"MeteredSettlement", HeightEnableMeteredSettlement},
+		{"StakeUnbondingQueue", HeightEnableStakeUnbondingQueue},
+		{"DoubleSignSlash", HeightEnableDoubleSignSlash},
+		{"Delegation", HeightEnableDelegation},
+		{"ValidatorCommission", HeightEnableValidatorCommission},
+		{"RametronTiers", HeightEnableRametronTiers},
+		{"RametronAttestation", HeightEnableRametronAttestation},
+		{"RandomnessBeacon", HeightEnableRandomnessBeacon},
+		{"PaymentDisputeWindow", HeightEnablePaymentDisputeWindow},
+		{"KeyRotation", HeightEnableKeyRotation},
+		{"TxValidUntil", HeightEnableTxValidUntil},
+		{"DynamicBaseFee", HeightEnableDynamicBaseFee},
+		{"HTLC", HeightEnableHTLC},
+		{"BlockBudget", HeightEnableBlockBudget},
+		{"Governance", HeightEnableGovernance},
+		{"Bridge", HeightEnableBridge},
+		{"Subchain", HeightEnableSubchain},
+	}
+}
@@ -1,5 +1,12 @@
 package common
 
+import "math"
+
+// HeightUnscheduled is the height of the forks this binary implements, but which are not scheduled
+// on the mainnet yet. They stay disabled until a release sets their activation height, and can be
+// activated earlier on the private and test networks with the fork.heights config.
+const HeightUnscheduled uint64 = math.MaxUint64
+
 // HeightEnableValidatorReward specifies the minimal block height to enable the validtor PTX reward
 const HeightEnableValidatorReward uint64 = 1 // approximate time: 2pm January 14th, 2020 PST
 
@@ -15,6 +22,110 @@ const HeightEnableSmartContract uint64 = 1 // approximate time: 12pm Dec 10th, 2
 // HeightSampleStakingReward specifies the block heigth to enable sampling of staking reward
 const HeightSampleStakingReward uint64 = 1 // approximate time: 7pm Mar 10th, 2021 PST
 
+// HeightEnableMultiCurrencyReservedFund specifies the minimal block height to allow reserving fund and making service payments in PandoWei
+const HeightEnableMultiCurrencyReservedFund uint64 = HeightUnscheduled
+
+// HeightEnableSessionKeys specifies the minimal block height to allow registering session keys and signing transactions with them
+const HeightEnableSessionKeys uint64 = HeightUnscheduled
+
+// HeightEnableTypedSigning specifies the minimal block height to accept transactions signed over their typed (EIP-712 style) sign bytes
+const HeightEnableTypedSigning uint64 = HeightUnscheduled
+
+// HeightEnableMultiSig specifies the minimal block height to allow multi-signature inputs in SendTx and RametronStakeTx
+const HeightEnableMultiSig uint64 = HeightUnscheduled
+
+// HeightEnableBatchSendTx specifies the minimal block height to allow BatchSendTx transactions
+const HeightEnableBatchSendTx uint64 = HeightUnscheduled
+
+// HeightEnableSendTxData specifies the minimal block height to allow data attached to SendTx transactions
+const HeightEnableSendTxData uint64 = HeightUnscheduled
+
+// HeightEnableEthTxSigning specifies the minimal block height to accept smart contract transactions signed as Ethereum (EIP-155) transactions
+const HeightEnableEthTxSigning uint64 = HeightUnscheduled
+
+// HeightEnableSlashAppeals specifies the minimal block height to allow SlashAppealTx and SlashAppealVoteTx transactions
+const HeightEnableSlashAppeals uint64 = HeightUnscheduled
+
+// HeightEnableBlockHash specifies the minimal block height to record the recent block hashes, and to support the BLOCKHASH opcode
+const HeightEnableBlockHash uint64 = HeightUnscheduled
+
+// HeightEnableEscrow specifies the minimal block height to allow ClaimEscrowTx transactions
+const HeightEnableEscrow uint64 = HeightUnscheduled
+
+// HeightEnableCanonicalSigning specifies the minimal block height to accept transactions signed over their canonical JSON sign bytes
+const HeightEnableCanonicalSigning uint64 = HeightUnscheduled
+
+// HeightEnableAggregatedVotes specifies the minimal block height to aggregate the validator votes of the commit certificates into BLS signatures
+const HeightEnableAggregatedVotes uint64 = HeightUnscheduled
+
+// HeightEnableThresholdVotes specifies the minimal block height to accept the validator votes only signed with the BLS key of the validator, e.g. by a threshold key
+const HeightEnableThresholdVotes uint64 = HeightUnscheduled
+
+// HeightEnableMeteredSettlement specifies the minimal block height to allow MeteredSettlementTx transactions
+const HeightEnableMeteredSettlement uint64 = HeightUnscheduled
+
+// HeightEnableStakeUnbondingQueue specifies the minimal block height to lock the withdrawn stakes for the governed unbonding period, and to release them from the unbonding queue
+const HeightEnableStakeUnbondingQueue uint64 = HeightUnscheduled
+
+// HeightEnableDoubleSignSlash specifies the minimal block height to slash the validators double signing with SlashTx transactions
+const HeightEnableDoubleSignSlash uint64 = HeightUnscheduled
+
+// HeightEnableDelegation specifies the minimal block height to allow DelegateTx and UndelegateTx transactions
+const HeightEnableDelegation uint64 = HeightUnscheduled
+
+// HeightEnableValidatorCommission specifies the minimal block height to allow SetCommissionTx transactions and
+// to deduct the validator commissions from the rewards of the delegated stake
+const HeightEnableValidatorCommission uint64 = HeightUnscheduled
+
+// HeightEnableRametronTiers specifies the minimal block height to register Rametron nodes for a tier with
+// RametronStakeTx transactions, and to grant the Rametron rewards at the checkpoints
+const HeightEnableRametronTiers uint64 = HeightUnscheduled
+
+// HeightEnableRametronAttestation specifies the minimal block height to allow RametronAttestationTx transactions.
+// From then on, a Rametron node is only active in the epochs in which the guardians attested its heartbeats.
+const HeightEnableRametronAttestation uint64 = HeightUnscheduled
+
+// HeightEnableRandomnessBeacon specifies the minimal block height to commit the randomness beacon derived from
+// the guardian votes in the block header, and to expose it to the smart contracts
+const HeightEnableRandomnessBeacon uint64 = HeightUnscheduled
+
+// HeightEnablePaymentDisputeWindow specifies the minimal block height from which a ServicePaymentTx settles the
+// cumulative amount paid to its target, and a settlement can be overridden with a higher payment sequence during
+// the dispute window
+const HeightEnablePaymentDisputeWindow uint64 = HeightUnscheduled
+
+// HeightEnableKeyRotation specifies the minimal block height to allow KeyRotationTx transactions, which
+// authorize a new key to control an account
+const HeightEnableKeyRotation uint64 = HeightUnscheduled
+
+// HeightEnableTxValidUntil specifies the minimal block height to accept the transactions which
+// expire after a given block height
+const HeightEnableTxValidUntil uint64 = HeightUnscheduled
+
+// HeightEnableDynamicBaseFee specifies the minimal block height to replace the fixed minimum fee with a
+// base fee adjusted to the utilization of the blocks
+const HeightEnableDynamicBaseFee uint64 = HeightUnscheduled
+
+// HeightEnableHTLC specifies the minimal block height to allow the hash time locked contract transactions,
+// which lock coins for atomic swaps with other chains
+const HeightEnableHTLC uint64 = HeightUnscheduled
+
+// HeightEnableBlockBudget specifies the minimal block height to limit the total gas and size of the regular
+// transactions in a block, as set by the governance parameters
+const HeightEnableBlockBudget uint64 = HeightUnscheduled
+
+// HeightEnableGovernance specifies the minimal block height to allow the governance proposals and votes,
+// and to tally the proposals
+const HeightEnableGovernance uint64 = HeightUnscheduled
+
+// HeightEnableBridge specifies the minimal block height to allow the bridge transactions, which lock and burn
+// assets for the other chains, and mint the assets attested by the bridge validators
+const HeightEnableBridge uint64 = HeightUnscheduled
+
+// HeightEnableSubchain specifies the minimal block height to allow the subchain transactions, which register
+// and stake to the subchains, and anchor and challenge their checkpoints
+const HeightEnableSubchain uint64 = HeightUnscheduled
+
 // CheckpointInterval defines the interval between checkpoints.
 const CheckpointInterval = int64(100)
 
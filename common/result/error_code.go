@@ -13,6 +13,7 @@ const (
 	CodeEmptyPubKeyWithSequence1 ErrorCode = 100004
 	CodeUnauthorizedTx           ErrorCode = 100005
 	CodeInvalidFee               ErrorCode = 100006
+	CodeTxExpired                ErrorCode = 100007
 
 	// ReserveFund Errors
 	CodeReserveFundCheckFailed   ErrorCode = 101001
@@ -40,4 +41,42 @@ const (
 	CodeInvalidStake            ErrorCode = 106002
 	CodeInsufficientStake       ErrorCode = 106003
 	CodeNotEnoughBalanceToStake ErrorCode = 106004
+
+	// SessionKey Errors
+	CodeInvalidSessionKey      ErrorCode = 107001
+	CodeSessionKeyNotPermitted ErrorCode = 107002
+
+	// MultiSig Errors
+	CodeInvalidMultiSig      ErrorCode = 108001
+	CodeMultiSigNotSupported ErrorCode = 108002
+
+	// SlashAppeal Errors
+	CodeInvalidSlashAppeal            ErrorCode = 109001
+	CodeInsufficientInsurancePoolFund ErrorCode = 109002
+
+	// Escrow Errors
+	CodeInvalidEscrowClaim ErrorCode = 110001
+
+	// MeteredSettlement Errors
+	CodeInvalidMeteringRecord    ErrorCode = 111001
+	CodeInsufficientReservedFund ErrorCode = 111002
+
+	// KeyRotation Errors
+	CodeInvalidKeyRotation ErrorCode = 112001
+
+	// HTLC Errors
+	CodeInvalidHTLC ErrorCode = 113001
+
+	// Governance Errors
+	CodeInvalidGovernanceProposal ErrorCode = 114001
+
+	// Bridge Errors
+	CodeInvalidBridgeTx         ErrorCode = 115001
+	CodeInvalidForeignProof     ErrorCode = 115002
+	CodeForeignEventAlreadyUsed ErrorCode = 115003
+
+	// Subchain Errors
+	CodeInvalidSubchainTx         ErrorCode = 116001
+	CodeInvalidSubchainCheckpoint ErrorCode = 116002
+	CodeInvalidSubchainChallenge  ErrorCode = 116003
 )
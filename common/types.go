@@ -361,6 +361,10 @@ const (
 	MessageIDInvResponse
 	MessageIDDataRequest
 	MessageIDDataResponse
+	MessageIDSnapshotManifestRequest
+	MessageIDSnapshotManifest
+	MessageIDSnapshotChunkRequest
+	MessageIDSnapshotChunk
 )
 
 // ChannelIDEnum defines the channelID for different type of data for synchronization among blockchain nodes
@@ -403,6 +407,9 @@ const (
 
 	// ChannelIDNATMapping indicates the channel for NAT Mapping messages between peers
 	ChannelIDNATMapping
+
+	// ChannelIDSnapshot indicates the channel for the snapshot manifests and chunks exchanged by snapshot sync
+	ChannelIDSnapshot
 )
 
 // P2POptEnum defines the p2p network
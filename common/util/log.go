@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -11,6 +12,12 @@ import (
 
 var logLevels map[string]string
 
+// moduleLoggers holds the loggers created for each module, so that their level can be changed at runtime
+var (
+	moduleLoggers     = make(map[string][]*log.Logger)
+	moduleLoggersLock = &sync.Mutex{}
+)
+
 const (
 	panicLevel = "panic"
 	fatalLevel = "fatal"
@@ -39,6 +46,56 @@ func InitLog() {
 	}
 }
 
+// SetModuleLogLevel changes the log level of the module at runtime. The "*" module sets the default
+// level, which applies to the modules without a level of their own, and to the modules logging
+// through the standard logger.
+func SetModuleLogLevel(module string, level string) error {
+	logrusLevel, err := log.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if logrusLevel > log.DebugLevel {
+		logrusLevel = log.DebugLevel
+	}
+
+	moduleLoggersLock.Lock()
+	defer moduleLoggersLock.Unlock()
+
+	if logLevels == nil {
+		logLevels = map[string]string{"*": defaultLevel}
+	}
+	logLevels[module] = logrusLevel.String()
+	if module == "*" {
+		log.SetLevel(logrusLevel)
+	}
+	for m, loggers := range moduleLoggers {
+		if m != module && (module != "*" || hasModuleLogLevel(m)) {
+			continue
+		}
+		for _, logger := range loggers {
+			logger.SetLevel(logrusLevel)
+		}
+	}
+	return nil
+}
+
+func hasModuleLogLevel(module string) bool {
+	_, ok := logLevels[module]
+	return ok
+}
+
+// GetModuleLogLevels returns the log levels of the modules, "*" being the default level
+func GetModuleLogLevels() map[string]string {
+	moduleLoggersLock.Lock()
+	defer moduleLoggersLock.Unlock()
+
+	levels := make(map[string]string)
+	for module, level := range logLevels {
+		levels[module] = level
+	}
+	return levels
+}
+
 func parseLogLevelConfig(config string) map[string]string {
 	levels := make(map[string]string)
 
@@ -87,5 +144,9 @@ func GetLoggerForModule(module string) *log.Entry {
 		logger.SetLevel(log.DebugLevel)
 	}
 
+	moduleLoggersLock.Lock()
+	moduleLoggers[module] = append(moduleLoggers[module], logger)
+	moduleLoggersLock.Unlock()
+
 	return logger.WithFields(log.Fields{"prefix": module})
 }
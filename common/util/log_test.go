@@ -35,3 +35,25 @@ func TestGetLoggerForModule(t *testing.T) {
 	assert.Equal(log.InfoLevel, GetLoggerForModule("consensus").Logger.Level)
 	assert.Equal(log.ErrorLevel, GetLoggerForModule("sync").Logger.Level)
 }
+
+func TestSetModuleLogLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	logLevels = parseLogLevelConfig("*:error,p2p:debug")
+	p2pLogger := GetLoggerForModule("p2p").Logger
+	syncLogger := GetLoggerForModule("sync").Logger
+
+	assert.Nil(SetModuleLogLevel("p2p", "info"))
+	assert.Equal(log.InfoLevel, p2pLogger.Level)
+	assert.Equal(log.ErrorLevel, syncLogger.Level)
+
+	// The default level does not override the levels set for the modules
+	assert.Nil(SetModuleLogLevel("*", "warn"))
+	assert.Equal(log.InfoLevel, p2pLogger.Level)
+	assert.Equal(log.WarnLevel, syncLogger.Level)
+
+	assert.NotNil(SetModuleLogLevel("p2p", "verbose"))
+	levels := GetModuleLogLevels()
+	assert.Equal("info", levels["p2p"])
+	assert.Equal("warning", levels["*"])
+}
@@ -0,0 +1,277 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TraceKind is the kind of the objects traced through the pipeline
+type TraceKind string
+
+const (
+	TracePeer  TraceKind = "peer"
+	TraceTx    TraceKind = "tx"
+	TraceBlock TraceKind = "block"
+)
+
+const (
+	// maxTraceTargets caps the number of objects traced at the same time
+	maxTraceTargets = 32
+	// maxTraceEventsPerTarget is the number of the most recent events retained for each target
+	maxTraceEventsPerTarget = 1000
+	// DefaultTraceDuration is how long a target is traced if no duration is specified
+	DefaultTraceDuration = time.Hour
+	// MaxTraceDuration is the longest a target can be traced
+	MaxTraceDuration = 24 * time.Hour
+)
+
+// TraceTarget is a peer, transaction or block whose pipeline stages are traced
+type TraceTarget struct {
+	Kind      TraceKind `json:"kind"`
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	NumEvents int       `json:"num_events"` // total number of events, including the ones no longer retained
+}
+
+// TraceEvent is a pipeline stage which touched a trace target
+type TraceEvent struct {
+	Time   time.Time `json:"time"`
+	Kind   TraceKind `json:"kind"`
+	ID     string    `json:"id"`
+	Module string    `json:"module"`
+	Stage  string    `json:"stage"`
+	Detail string    `json:"detail"`
+}
+
+type traceTargetKey struct {
+	kind TraceKind
+	id   string
+}
+
+type traceTarget struct {
+	TraceTarget
+	events []TraceEvent
+}
+
+//
+// tracer logs the pipeline stages touching the trace targets, regardless of the log levels of the
+// modules, and retains the recent events of each target
+//
+type tracer struct {
+	mutex      *sync.Mutex
+	targets    map[traceTargetKey]*traceTarget
+	numTargets int32 // read without the lock, to keep the stages cheap when nothing is traced
+	logger     *log.Entry
+}
+
+var globalTracer = newTracer()
+
+func newTracer() *tracer {
+	logger := log.New()
+	formatter := new(TextFormatter)
+	formatter.TimestampFormat = "2006-01-02 15:04:05"
+	formatter.FullTimestamp = true
+	formatter.ForceFormatting = true
+	logger.Formatter = formatter
+	logger.SetLevel(log.InfoLevel)
+
+	return &tracer{
+		mutex:   &sync.Mutex{},
+		targets: make(map[traceTargetKey]*traceTarget),
+		logger:  logger.WithFields(log.Fields{"prefix": "trace"}),
+	}
+}
+
+// normalizeTraceID makes the IDs with and without the 0x prefix, or in different cases, match
+func normalizeTraceID(id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	return strings.TrimPrefix(id, "0x")
+}
+
+func validTraceKind(kind TraceKind) bool {
+	return kind == TracePeer || kind == TraceTx || kind == TraceBlock
+}
+
+func (tr *tracer) add(kind TraceKind, id string, duration time.Duration) (TraceTarget, error) {
+	if !validTraceKind(kind) {
+		return TraceTarget{}, fmt.Errorf("Invalid trace kind: %v, expected %v, %v or %v", kind, TracePeer, TraceTx, TraceBlock)
+	}
+	key := traceTargetKey{kind: kind, id: normalizeTraceID(id)}
+	if key.id == "" {
+		return TraceTarget{}, fmt.Errorf("The %v ID to trace is empty", kind)
+	}
+	if duration <= 0 {
+		duration = DefaultTraceDuration
+	}
+	if duration > MaxTraceDuration {
+		return TraceTarget{}, fmt.Errorf("The trace duration cannot exceed %v", MaxTraceDuration)
+	}
+
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	tr.removeExpiredUnsafe()
+	now := time.Now()
+	target, ok := tr.targets[key]
+	if !ok {
+		if len(tr.targets) >= maxTraceTargets {
+			return TraceTarget{}, fmt.Errorf("Cannot trace more than %v targets at the same time", maxTraceTargets)
+		}
+		target = &traceTarget{
+			TraceTarget: TraceTarget{Kind: kind, ID: key.id, CreatedAt: now},
+		}
+		tr.targets[key] = target
+		atomic.StoreInt32(&tr.numTargets, int32(len(tr.targets)))
+	}
+	target.ExpiresAt = now.Add(duration)
+
+	tr.logger.Infof("Start tracing %v %v until %v", kind, key.id, target.ExpiresAt.Format(time.RFC3339))
+	return target.TraceTarget, nil
+}
+
+func (tr *tracer) remove(kind TraceKind, id string) bool {
+	key := traceTargetKey{kind: kind, id: normalizeTraceID(id)}
+
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	if _, ok := tr.targets[key]; !ok {
+		return false
+	}
+	delete(tr.targets, key)
+	atomic.StoreInt32(&tr.numTargets, int32(len(tr.targets)))
+	tr.logger.Infof("Stop tracing %v %v", kind, key.id)
+	return true
+}
+
+// removeExpiredUnsafe removes the expired targets. The caller needs to hold the lock.
+func (tr *tracer) removeExpiredUnsafe() {
+	now := time.Now()
+	for key, target := range tr.targets {
+		if now.After(target.ExpiresAt) {
+			delete(tr.targets, key)
+			tr.logger.Infof("Stop tracing %v %v, the trace expired", key.kind, key.id)
+		}
+	}
+	atomic.StoreInt32(&tr.numTargets, int32(len(tr.targets)))
+}
+
+func (tr *tracer) list() []TraceTarget {
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	tr.removeExpiredUnsafe()
+	targets := []TraceTarget{}
+	for _, target := range tr.targets {
+		targets = append(targets, target.TraceTarget)
+	}
+	return targets
+}
+
+func (tr *tracer) events(kind TraceKind, id string) ([]TraceEvent, bool) {
+	key := traceTargetKey{kind: kind, id: normalizeTraceID(id)}
+
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	tr.removeExpiredUnsafe()
+	target, ok := tr.targets[key]
+	if !ok {
+		return nil, false
+	}
+	events := make([]TraceEvent, len(target.events))
+	copy(events, target.events)
+	return events, true
+}
+
+func (tr *tracer) enabled(kind TraceKind) bool {
+	if atomic.LoadInt32(&tr.numTargets) == 0 {
+		return false
+	}
+
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	for key := range tr.targets {
+		if key.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (tr *tracer) trace(kind TraceKind, id string, module string, stage string, detail string) {
+	if atomic.LoadInt32(&tr.numTargets) == 0 {
+		return
+	}
+	key := traceTargetKey{kind: kind, id: normalizeTraceID(id)}
+
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	target, ok := tr.targets[key]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if now.After(target.ExpiresAt) {
+		tr.removeExpiredUnsafe()
+		return
+	}
+
+	target.NumEvents++
+	target.events = append(target.events, TraceEvent{
+		Time:   now,
+		Kind:   kind,
+		ID:     key.id,
+		Module: module,
+		Stage:  stage,
+		Detail: detail,
+	})
+	if len(target.events) > maxTraceEventsPerTarget {
+		target.events = target.events[len(target.events)-maxTraceEventsPerTarget:]
+	}
+
+	tr.logger.WithFields(log.Fields{"kind": kind, "id": key.id, "module": module}).Infof("%v %v", stage, detail)
+}
+
+// AddTraceTarget starts tracing the peer, transaction or block for the given duration. Tracing a
+// target again extends its trace.
+func AddTraceTarget(kind TraceKind, id string, duration time.Duration) (TraceTarget, error) {
+	return globalTracer.add(kind, id, duration)
+}
+
+// RemoveTraceTarget stops tracing the target, it returns false if the target is not traced
+func RemoveTraceTarget(kind TraceKind, id string) bool {
+	return globalTracer.remove(kind, id)
+}
+
+// GetTraceTargets returns the targets currently traced
+func GetTraceTargets() []TraceTarget {
+	return globalTracer.list()
+}
+
+// GetTraceEvents returns the retained events of the target, the oldest first
+func GetTraceEvents(kind TraceKind, id string) ([]TraceEvent, bool) {
+	return globalTracer.events(kind, id)
+}
+
+// IsTracing indicates whether any target of the kind is traced. The callers use it to skip
+// computing the IDs, e.g. hashing the transactions, when nothing is traced.
+func IsTracing(kind TraceKind) bool {
+	return globalTracer.enabled(kind)
+}
+
+// Trace records that the pipeline stage of the module touched the object, if it is a trace target
+func Trace(kind TraceKind, id string, module string, stage string, format string, args ...interface{}) {
+	if atomic.LoadInt32(&globalTracer.numTargets) == 0 {
+		return
+	}
+	globalTracer.trace(kind, id, module, stage, fmt.Sprintf(format, args...))
+}
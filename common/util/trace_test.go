@@ -0,0 +1,97 @@
+// +build unit
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceTargets(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	globalTracer = newTracer()
+	assert.False(IsTracing(TraceTx))
+
+	_, err := AddTraceTarget(TraceKind("account"), "0x1", time.Minute)
+	assert.NotNil(err)
+	_, err = AddTraceTarget(TraceTx, "0x", time.Minute)
+	assert.NotNil(err)
+	_, err = AddTraceTarget(TraceTx, "0xab", MaxTraceDuration+time.Second)
+	assert.NotNil(err)
+
+	target, err := AddTraceTarget(TraceTx, "0xABCD", 0)
+	require.Nil(err)
+	assert.Equal("abcd", target.ID)
+	assert.True(IsTracing(TraceTx))
+	assert.False(IsTracing(TraceBlock))
+
+	// The IDs match regardless of the 0x prefix and the case
+	Trace(TraceTx, "abcd", "mempool", "inserted", "size: %v", 1)
+	Trace(TraceTx, "0xAbCd", "consensus", "finalized", "height: %v", 10)
+	Trace(TraceTx, "0xef", "mempool", "inserted", "size: %v", 2)
+	Trace(TraceBlock, "0xabcd", "consensus", "valid", "")
+
+	events, ok := GetTraceEvents(TraceTx, "0xabcd")
+	require.True(ok)
+	require.Equal(2, len(events))
+	assert.Equal("mempool", events[0].Module)
+	assert.Equal("inserted", events[0].Stage)
+	assert.Equal("size: 1", events[0].Detail)
+	assert.Equal("finalized", events[1].Stage)
+
+	_, ok = GetTraceEvents(TraceTx, "0xef")
+	assert.False(ok)
+
+	targets := GetTraceTargets()
+	require.Equal(1, len(targets))
+	assert.Equal(2, targets[0].NumEvents)
+
+	assert.True(RemoveTraceTarget(TraceTx, "0xabcd"))
+	assert.False(RemoveTraceTarget(TraceTx, "0xabcd"))
+	assert.False(IsTracing(TraceTx))
+	assert.Equal(0, len(GetTraceTargets()))
+}
+
+func TestTraceExpiration(t *testing.T) {
+	assert := assert.New(t)
+
+	globalTracer = newTracer()
+	_, err := AddTraceTarget(TracePeer, "peer1", time.Millisecond)
+	assert.Nil(err)
+	assert.True(IsTracing(TracePeer))
+
+	time.Sleep(5 * time.Millisecond)
+	Trace(TracePeer, "peer1", "p2p", "connected", "")
+	_, ok := GetTraceEvents(TracePeer, "peer1")
+	assert.False(ok)
+	assert.False(IsTracing(TracePeer))
+}
+
+func TestTraceEventsRetention(t *testing.T) {
+	assert := assert.New(t)
+
+	globalTracer = newTracer()
+	_, err := AddTraceTarget(TraceBlock, "0x1", time.Minute)
+	assert.Nil(err)
+	for i := 0; i < maxTraceEventsPerTarget+10; i++ {
+		Trace(TraceBlock, "0x1", "consensus", "voted", "%v", i)
+	}
+
+	events, ok := GetTraceEvents(TraceBlock, "0x1")
+	assert.True(ok)
+	assert.Equal(maxTraceEventsPerTarget, len(events))
+	assert.Equal("10", events[0].Detail)
+	assert.Equal(maxTraceEventsPerTarget+10, GetTraceTargets()[0].NumEvents)
+
+	for i := 0; i < maxTraceTargets-1; i++ {
+		_, err = AddTraceTarget(TraceTx, string(rune('a'+i%26))+string(rune('a'+i/26)), time.Minute)
+		assert.Nil(err)
+	}
+	_, err = AddTraceTarget(TraceTx, "0xff", time.Minute)
+	assert.NotNil(err)
+}
@@ -11,8 +11,6 @@ import (
 
 	"github.com/pandotoken/pando/crypto/bls"
 
-	log "github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 	"github.com/pandotoken/pando/blockchain"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
@@ -20,8 +18,11 @@ import (
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
 	"github.com/pandotoken/pando/dispatcher"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/rlp"
 	"github.com/pandotoken/pando/store"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 var logger = log.WithFields(log.Fields{"prefix": "consensus"})
@@ -32,13 +33,18 @@ var _ core.ConsensusEngine = (*ConsensusEngine)(nil)
 type ConsensusEngine struct {
 	logger *log.Entry
 
-	privateKey *crypto.PrivateKey
+	signer crypto.Signer
+	blsKey *bls.SecretKey // Signs the votes for the aggregation, nil if the private key of the signer is not local
+	// blsSigner signs the votes in place of the account key if set, e.g. a threshold signer
+	// collecting the partial signatures of the key shares held by multiple machines
+	blsSigner BlsSigner
 
 	chain            *blockchain.Chain
 	dispatcher       *dispatcher.Dispatcher
 	validatorManager core.ValidatorManager
 	ledger           core.Ledger
 	guardian         *GuardianEngine
+	evidence         *EvidencePool
 
 	incoming        chan interface{}
 	finalizedBlocks chan *core.Block
@@ -59,12 +65,12 @@ type ConsensusEngine struct {
 }
 
 // NewConsensusEngine creates a instance of ConsensusEngine.
-func NewConsensusEngine(privateKey *crypto.PrivateKey, db store.Store, chain *blockchain.Chain, dispatcher *dispatcher.Dispatcher, validatorManager core.ValidatorManager) *ConsensusEngine {
+func NewConsensusEngine(signer crypto.Signer, db store.Store, chain *blockchain.Chain, dispatcher *dispatcher.Dispatcher, validatorManager core.ValidatorManager) *ConsensusEngine {
 	e := &ConsensusEngine{
 		chain:      chain,
 		dispatcher: dispatcher,
 
-		privateKey: privateKey,
+		signer: signer,
 
 		incoming:        make(chan interface{}, viper.GetInt(common.CfgConsensusMessageQueueSize)),
 		finalizedBlocks: make(chan *core.Block, viper.GetInt(common.CfgConsensusMessageQueueSize)),
@@ -75,22 +81,53 @@ func NewConsensusEngine(privateKey *crypto.PrivateKey, db store.Store, chain *bl
 		state: NewState(db, chain),
 
 		validatorManager: validatorManager,
+		evidence:         NewEvidencePool(db, chain.ChainID),
 	}
 
 	logger = util.GetLoggerForModule("consensus")
 	e.logger = logger
 
-	blsKey, err := bls.GenKey(strings.NewReader(common.Bytes2Hex(privateKey.PublicKey().ToBytes())))
+	blsKey, err := bls.GenKey(strings.NewReader(common.Bytes2Hex(signer.PublicKey().ToBytes())))
 	if err != nil {
 		e.logger.Panic(err)
 	}
 	e.guardian = NewGuardianEngine(e, blsKey)
 
+	// The validator votes can only be aggregated if the validator BLS key can be derived, i.e. the
+	// node is not signing with a separate signer process.
+	if privKey, ok := signer.(*crypto.PrivateKey); ok {
+		e.blsKey, err = bls.DeriveValidatorKey(privKey)
+		if err != nil {
+			e.logger.Panic(err)
+		}
+	}
+
 	e.logger.WithFields(log.Fields{"state": e.state}).Info("Starting state")
 
 	return e
 }
 
+// BlsSigner signs messages with the BLS key the validator registered, e.g. a bls.ThresholdSigner.
+type BlsSigner interface {
+	Sign(message []byte) (*bls.Signature, error)
+	PublicKey() *bls.PublicKey
+}
+
+// GuardedSigner is implemented by the signers protecting the validator against the double signing,
+// e.g. a signer.RemoteSigner shared by an active and a standby node. They need to know the votes and
+// the proposals they sign, and refuse to sign the conflicting ones. A vote comes with the header of
+// the voted block, so the signer does not have to trust the height of the vote.
+type GuardedSigner interface {
+	SignVote(vote core.Vote, header *core.BlockHeader) (*crypto.Signature, error)
+	SignProposal(header *core.BlockHeader) (*crypto.Signature, error)
+}
+
+// SetBlsSigner sets the signer of the votes. The votes are then only signed with the BLS key, so the
+// public key of the signer needs to be registered as the BLS key of the validator.
+func (e *ConsensusEngine) SetBlsSigner(signer BlsSigner) {
+	e.blsSigner = signer
+}
+
 func (e *ConsensusEngine) SetLedger(ledger core.Ledger) {
 	e.ledger = ledger
 }
@@ -102,12 +139,12 @@ func (e *ConsensusEngine) GetLedger() core.Ledger {
 
 // ID returns the identifier of current node.
 func (e *ConsensusEngine) ID() string {
-	return e.privateKey.PublicKey().Address().Hex()
+	return e.signer.PublicKey().Address().Hex()
 }
 
-// PrivateKey returns the private key
-func (e *ConsensusEngine) PrivateKey() *crypto.PrivateKey {
-	return e.privateKey
+// Signer returns the signer of the node's key
+func (e *ConsensusEngine) Signer() crypto.Signer {
+	return e.signer
 }
 
 // Chain return a pointer to the underlying chain store.
@@ -125,6 +162,11 @@ func (e *ConsensusEngine) GetValidatorManager() core.ValidatorManager {
 	return e.validatorManager
 }
 
+// PendingEvidence returns the evidence of double signing to be included in the proposed blocks.
+func (e *ConsensusEngine) PendingEvidence() []*core.DoubleSignEvidence {
+	return e.evidence.PendingEvidence()
+}
+
 // Start starts sub components and kick off the main loop.
 func (e *ConsensusEngine) Start(ctx context.Context) {
 	c, cancel := context.WithCancel(ctx)
@@ -141,6 +183,7 @@ func (e *ConsensusEngine) Start(ctx context.Context) {
 
 	// Set ledger state pointer to initial state.
 	lastCC := e.autoRewind(e.state.GetHighestCCBlock())
+	lastCC = e.restartFromManifest(lastCC)
 	//e.ledger.ResetState(lastCC.Height, lastCC.StateHash)
 	e.ledger.ResetState(lastCC.Block)
 
@@ -406,6 +449,9 @@ func (e *ConsensusEngine) validateBlock(block *core.Block, parent *core.Extended
 	}
 
 	// Validate HCC.
+	if block.HCC.Aggregated != nil && !features.IsEnabled(features.AggregatedVotes, block.Height) {
+		return result.Error("Aggregated HCC is not enabled yet")
+	}
 	if !e.chain.IsDescendant(block.HCC.BlockHash, block.Hash()) {
 		e.logger.WithFields(log.Fields{
 			"block.HCC": block.HCC.BlockHash.Hex(),
@@ -476,7 +522,7 @@ func (e *ConsensusEngine) validateBlock(block *core.Block, parent *core.Extended
 
 	// Validate Guardian Votes.
 	// We allow checkpoint blocs to have nil guardian votes.
-	if block.GuardianVotes != nil && block.Height >= common.HeightEnablePando2 && common.IsCheckPointHeight(block.Height) {
+	if block.GuardianVotes != nil && features.IsEnabled(features.Pando2, block.Height) && common.IsCheckPointHeight(block.Height) {
 		// Voted block must exist.
 		lastCheckpoint, err := e.chain.FindBlock(block.GuardianVotes.Block)
 		if err != nil {
@@ -540,6 +586,19 @@ func (e *ConsensusEngine) validateBlock(block *core.Block, parent *core.Extended
 		}
 	}
 
+	// Validate the randomness beacon.
+	if features.IsEnabled(features.RandomnessBeacon, block.Height) {
+		if expected := core.NextRandomness(parent.Randomness, block.GuardianVotes); block.Randomness != expected {
+			e.logger.WithFields(log.Fields{
+				"block.Hash":       block.Hash().Hex(),
+				"block.Height":     block.Height,
+				"block.Randomness": block.Randomness.Hex(),
+				"expected":         expected.Hex(),
+			}).Warn("Invalid randomness beacon")
+			return result.Error("Randomness beacon is incorrect")
+		}
+	}
+
 	return result.OK
 }
 
@@ -632,16 +691,22 @@ func (e *ConsensusEngine) handleNormalBlock(eb *core.ExtendedBlock) {
 		}).Fatal("Failed to find parent block")
 	}
 
+	util.Trace(util.TraceBlock, block.Hash().Hex(), "consensus", "processing", "height: %v, epoch: %v, proposer: %v, num txs: %v",
+		block.Height, block.Epoch, block.Proposer.Hex(), len(block.Txs))
+
 	start1 := time.Now()
-	if e.validateBlock(block, parent).IsError() {
+	if res := e.validateBlock(block, parent); res.IsError() {
 		e.logger.WithFields(log.Fields{
 			"block.Hash": block.Hash().Hex(),
 		}).Warn("Block is invalid")
+		util.Trace(util.TraceBlock, block.Hash().Hex(), "consensus", "invalid", "error: %v", res.Message)
 		e.chain.MarkBlockInvalid(block.Hash())
 		return
 	}
 	validateBlockTime := time.Since(start1)
 
+	e.evidence.AddProposal(block.BlockHeader)
+
 	for _, vote := range block.HCC.Votes.Votes() {
 		e.handleVote(vote)
 	}
@@ -650,7 +715,12 @@ func (e *ConsensusEngine) handleNormalBlock(eb *core.ExtendedBlock) {
 			"localHCC":            localHCC.Hex(),
 			"block.HCC.BlockHash": block.HCC.BlockHash.Hex(),
 		}).Debug("Updating HCC before process block")
-		e.checkCC(block.HCC.BlockHash)
+		if block.HCC.Aggregated != nil {
+			// The aggregated votes are not available individually, the HCC has been validated with the block
+			e.checkCertifiedCC(block.HCC.BlockHash)
+		} else {
+			e.checkCC(block.HCC.BlockHash)
+		}
 	}
 
 	//result := e.ledger.ResetState(parent.Height, parent.StateHash)
@@ -673,6 +743,7 @@ func (e *ConsensusEngine) handleNormalBlock(eb *core.ExtendedBlock) {
 			"block":           block.Hash().Hex(),
 			"block.StateHash": block.StateHash.Hex(),
 		}).Error("Failed to apply block Txs")
+		util.Trace(util.TraceBlock, block.Hash().Hex(), "consensus", "apply_failed", "error: %v", result.String())
 		e.chain.MarkBlockInvalid(block.Hash())
 		return
 	}
@@ -690,6 +761,7 @@ func (e *ConsensusEngine) handleNormalBlock(eb *core.ExtendedBlock) {
 	}
 
 	e.chain.MarkBlockValid(block.Hash())
+	util.Trace(util.TraceBlock, block.Hash().Hex(), "consensus", "valid", "applied the txs in %v", applyBlockTime)
 
 	// Skip voting for block older than current best known epoch.
 	// Allow block with one epoch behind since votes are processed first and might advance epoch
@@ -718,7 +790,7 @@ func (e *ConsensusEngine) handleNormalBlock(eb *core.ExtendedBlock) {
 }
 
 func (e *ConsensusEngine) shouldVote(block common.Hash) bool {
-	return e.shouldVoteByID(e.privateKey.PublicKey().Address(), block)
+	return e.shouldVoteByID(e.signer.PublicKey().Address(), block)
 }
 
 func (e *ConsensusEngine) shouldVoteByID(id common.Address, block common.Hash) bool {
@@ -756,21 +828,32 @@ func (e *ConsensusEngine) vote() {
 		shouldRepeatVote = true
 	}
 
+	var err error
 	if shouldRepeatVote {
-		block, err := e.chain.FindBlock(lastVote.Block)
-		if err != nil {
+		block, findErr := e.chain.FindBlock(lastVote.Block)
+		if findErr != nil {
 			// Should not happen
-			log.Panic(err)
+			log.Panic(findErr)
 		}
 		// Recreating vote so that it has updated epoch and signature.
-		vote = e.createVote(block.Block)
+		vote, err = e.createVote(block.Block)
 	} else {
-		vote = e.createVote(tip.Block)
-		e.state.SetLastVote(vote)
+		vote, err = e.createVote(tip.Block)
+		if err == nil {
+			e.state.SetLastVote(vote)
+		}
+	}
+	if err != nil {
+		// The signer refuses the votes conflicting with the ones it signed, e.g. for another node
+		// with the same key
+		e.logger.WithFields(log.Fields{"err": err, "tip": tip.Hash().Hex()}).Warn("Failed to sign vote")
+		return
 	}
 	e.logger.WithFields(log.Fields{
 		"vote": vote,
 	}).Debug("Sending vote")
+	util.Trace(util.TraceBlock, vote.Block.Hex(), "consensus", "voted", "height: %v, epoch: %v, repeated: %v",
+		vote.Height, vote.Epoch, shouldRepeatVote)
 	e.broadcastVote(vote)
 
 	go func() {
@@ -791,19 +874,51 @@ func (e *ConsensusEngine) broadcastVote(vote core.Vote) {
 	e.dispatcher.SendData([]string{}, voteMsg)
 }
 
-func (e *ConsensusEngine) createVote(block *core.Block) core.Vote {
+func (e *ConsensusEngine) createVote(block *core.Block) (core.Vote, error) {
 	vote := core.Vote{
 		Block:  block.Hash(),
 		Height: block.Height,
-		ID:     e.privateKey.PublicKey().Address(),
+		ID:     e.signer.PublicKey().Address(),
 		Epoch:  e.GetEpoch(),
 	}
-	vote.Sign(e.privateKey)
-	return vote
+	if e.blsSigner != nil && features.IsEnabled(features.ThresholdVotes, block.Height) {
+		sig, err := e.blsSigner.Sign(core.BlsVoteSignBytes(vote.Block))
+		if err == nil {
+			vote.BlsSignature = sig
+			return vote, nil
+		}
+		// Fall back to the account key, so that the validator keeps voting while the signers are unavailable
+		e.logger.WithFields(log.Fields{"err": err}).Warn("Failed to sign the vote with the BLS signer")
+	}
+	if guarded, ok := e.signer.(GuardedSigner); ok {
+		sig, err := guarded.SignVote(vote, block.BlockHeader)
+		if err != nil {
+			return vote, err
+		}
+		vote.SetSignature(sig)
+	} else {
+		vote.Sign(e.signer)
+	}
+	if e.blsKey != nil && features.IsEnabled(features.AggregatedVotes, block.Height) {
+		vote.SignBls(e.blsKey)
+	}
+	return vote, nil
 }
 
 func (e *ConsensusEngine) validateVote(vote core.Vote) bool {
-	if res := vote.Validate(); res.IsError() {
+	var res result.Result
+	if vote.IsBlsOnly() {
+		// The BLS key of the voter is registered in the validator set of the block
+		block, err := e.chain.FindBlock(vote.Block)
+		if err != nil || block == nil {
+			res = result.Error("Unknown block of a vote only signed with the BLS key")
+		} else {
+			res = vote.ValidateWithValidators(e.validatorManager.GetValidatorSet(vote.Block))
+		}
+	} else {
+		res = vote.Validate()
+	}
+	if res.IsError() {
 		e.logger.WithFields(log.Fields{
 			"err": res.String(),
 		}).Warn("Ignoring invalid vote")
@@ -818,6 +933,10 @@ func (e *ConsensusEngine) handleVote(vote core.Vote) (endEpoch bool) {
 		return
 	}
 
+	if block, err := e.chain.FindBlock(vote.Block); err == nil {
+		e.evidence.AddVote(vote, block.BlockHeader)
+	}
+
 	// Save vote.
 	err := e.state.AddVote(&vote)
 	if err != nil {
@@ -867,42 +986,61 @@ func (e *ConsensusEngine) handleVote(vote core.Vote) (endEpoch bool) {
 }
 
 func (e *ConsensusEngine) checkCC(hash common.Hash) {
-	if hash.IsEmpty() {
+	block, ok := e.getCCCandidate(hash)
+	if !ok {
 		return
 	}
+
+	votes := e.chain.FindVotesByHash(hash).UniqueVoter()
+	validators := e.validatorManager.GetValidatorSet(hash)
+	if validators.HasMajority(votes) {
+		e.processCCBlock(block)
+	}
+}
+
+// checkCertifiedCC processes the block of a valid commit certificate carried by a child block.
+func (e *ConsensusEngine) checkCertifiedCC(hash common.Hash) {
+	block, ok := e.getCCCandidate(hash)
+	if !ok {
+		return
+	}
+	e.processCCBlock(block)
+}
+
+// getCCCandidate returns the block if it could become the highest CC block. Trusted blocks are
+// processed right away.
+func (e *ConsensusEngine) getCCCandidate(hash common.Hash) (*core.ExtendedBlock, bool) {
+	if hash.IsEmpty() {
+		return nil, false
+	}
 	block, err := e.Chain().FindBlock(hash)
 	if err != nil {
 		e.logger.WithFields(log.Fields{"block": hash.Hex()}).Debug("checkCC: Block hash in vote is not found")
-		return
+		return nil, false
 	}
 	// Skip invalid block.
 	if block.Status.IsInvalid() {
-		return
+		return nil, false
 	}
 	// Skip if block is still pending.
 	if block.Status.IsPending() {
-		return
+		return nil, false
 	}
 	// Skip if block already has CC.
 	if block.Status.IsCommitted() || block.Status.IsDirectlyFinalized() || block.Status.IsIndirectlyFinalized() {
-		return
+		return nil, false
 	}
 	// Process hardcoded blocks.
 	if block.Status.IsTrusted() {
 		e.processCCBlock(block)
-		return
+		return nil, false
 	}
 	// Ignore outdated votes.
 	highestCCBlockHeight := e.state.GetHighestCCBlock().Height
 	if block.Height < highestCCBlockHeight {
-		return
-	}
-
-	votes := e.chain.FindVotesByHash(hash).UniqueVoter()
-	validators := e.validatorManager.GetValidatorSet(hash)
-	if validators.HasMajority(votes) {
-		e.processCCBlock(block)
+		return nil, false
 	}
+	return block, true
 }
 
 func (e *ConsensusEngine) GetTipToVote() *core.ExtendedBlock {
@@ -1023,6 +1161,8 @@ func (e *ConsensusEngine) finalizeBlock(block *core.ExtendedBlock) error {
 	e.state.SetLastFinalizedBlock(block)
 	e.ledger.FinalizeState(block.Height, block.StateHash)
 
+	e.evidence.Prune(block.Height)
+
 	e.checkSyncStatus()
 
 	// Mark block and its ancestors as finalized.
@@ -1034,6 +1174,42 @@ func (e *ConsensusEngine) finalizeBlock(block *core.ExtendedBlock) error {
 	// duplicate TX in fork.
 	e.chain.AddTxsToIndex(block, true)
 
+	// Index the bloom filter of the logs, so that the log queries can skip the blocks without matches
+	e.chain.AddBlockBloom(block)
+
+	// Keep the governance parameter changes announced by the block once they leave the state
+	e.chain.AddParamChangeHistory(block)
+
+	if viper.GetBool(common.CfgStorageAccountHistoryIndex) {
+		e.chain.AddTxsToAccountHistory(block)
+	}
+	if viper.GetBool(common.CfgStorageFeeStatsIndex) {
+		e.chain.AddFeeStats(block)
+	}
+	if viper.GetBool(common.CfgStorageReserveFundIndex) {
+		e.chain.AddReserveFundUsage(block)
+	}
+	if viper.GetBool(common.CfgStorageAccountActivityIndex) {
+		e.chain.AddAccountActivity(block)
+	}
+	if viper.GetBool(common.CfgStorageTokenIndex) {
+		e.chain.AddTokenTransfers(block)
+	}
+	if viper.GetBool(common.CfgStorageNFTIndex) {
+		e.chain.AddNFTTransfers(block)
+	}
+	if viper.GetBool(common.CfgStorageFreezerEnabled) {
+		go e.freezeBlocks(block.Height)
+	}
+
+	util.Trace(util.TraceBlock, block.Hash().Hex(), "consensus", "finalized", "height: %v", block.Height)
+	if util.IsTracing(util.TraceTx) {
+		for _, rawTx := range block.Txs {
+			util.Trace(util.TraceTx, crypto.Keccak256Hash(rawTx).Hex(), "consensus", "finalized", "block: %v, height: %v",
+				block.Hash().Hex(), block.Height)
+		}
+	}
+
 	// Guardians to vote for checkpoint blocks.
 	if common.IsCheckPointHeight(block.Height) {
 		e.guardian.StartNewBlock(block.Hash())
@@ -1104,17 +1280,25 @@ func (e *ConsensusEngine) createProposal() (core.Proposal, error) {
 	block.Epoch = e.GetEpoch()
 	block.Parent = tip.Hash()
 	block.Height = tip.Height + 1
-	block.Proposer = e.privateKey.PublicKey().Address()
+	block.Proposer = e.signer.PublicKey().Address()
 	block.Timestamp = big.NewInt(time.Now().Unix())
 	block.HCC.BlockHash = e.state.GetHighestCCBlock().Hash()
 	hccValidators := e.validatorManager.GetValidatorSet(block.HCC.BlockHash)
 	block.HCC.Votes = e.chain.FindVotesByHash(block.HCC.BlockHash).UniqueVoter().FilterByValidators(hccValidators)
+	if features.IsEnabled(features.AggregatedVotes, block.Height) {
+		block.HCC.Aggregate(hccValidators)
+	}
 
 	// Add guardian votes.
-	if block.Height >= common.HeightEnablePando2 && common.IsCheckPointHeight(block.Height) {
+	if features.IsEnabled(features.Pando2, block.Height) && common.IsCheckPointHeight(block.Height) {
 		block.GuardianVotes = e.guardian.GetBestVote()
 	}
 
+	// Advance the randomness beacon.
+	if features.IsEnabled(features.RandomnessBeacon, block.Height) {
+		block.Randomness = core.NextRandomness(tip.Randomness, block.GuardianVotes)
+	}
+
 	// Add Txs.
 	newRoot, txs, result := e.ledger.ProposeBlockTxs(block)
 	if result.IsError() {
@@ -1125,9 +1309,15 @@ func (e *ConsensusEngine) createProposal() (core.Proposal, error) {
 	block.StateHash = newRoot
 
 	// Sign block.
-	sig, err := e.privateKey.Sign(block.SignBytes())
+	var sig *crypto.Signature
+	var err error
+	if guarded, ok := e.signer.(GuardedSigner); ok {
+		sig, err = guarded.SignProposal(block.BlockHeader)
+	} else {
+		sig, err = e.signer.Sign(block.SignBytes())
+	}
 	if err != nil {
-		e.logger.WithFields(log.Fields{"error": err}).Panic("Failed to sign vote")
+		return core.Proposal{}, fmt.Errorf("Failed to sign block: %v", err)
 	}
 	block.SetSignature(sig)
 
@@ -1170,7 +1360,8 @@ func (e *ConsensusEngine) propose() {
 			e.logger.WithFields(log.Fields{"error": err}).Error("Failed to create proposal")
 			return
 		}
-		e.state.LastProposal = proposal
+		// Persisted so that the node does not propose another block in the epoch after a restart
+		e.state.SetLastProposal(proposal)
 
 		_, err = e.chain.AddBlock(proposal.Block)
 		if err != nil {
@@ -1179,6 +1370,8 @@ func (e *ConsensusEngine) propose() {
 
 		e.logger.WithFields(log.Fields{"proposal": proposal}).Info("Making proposal")
 	}
+	util.Trace(util.TraceBlock, proposal.Block.Hash().Hex(), "consensus", "proposed", "height: %v, epoch: %v, num txs: %v",
+		proposal.Block.Height, proposal.Block.Epoch, len(proposal.Block.Txs))
 
 	payload, err := rlp.EncodeToBytes(proposal)
 	if err != nil {
@@ -1197,7 +1390,8 @@ func (e *ConsensusEngine) propose() {
 }
 
 func (e *ConsensusEngine) pruneState(currentBlockHeight uint64) {
-	if !viper.GetBool(common.CfgStorageStatePruningEnabled) {
+	// An archive node prunes the hot database too, since the pruned states are frozen into the ancient database
+	if !viper.GetBool(common.CfgStorageStatePruningEnabled) && !viper.GetBool(common.CfgStorageArchive) {
 		return
 	}
 
@@ -1215,6 +1409,20 @@ func (e *ConsensusEngine) pruneState(currentBlockHeight uint64) {
 	e.ledger.PruneState(endHeight)
 }
 
+// maxFreezeBlocks is the maximum number of blocks moved into the freezer at once, so that a node
+// enabling the freezer catches up over a number of blocks
+const maxFreezeBlocks = 1000
+
+func (e *ConsensusEngine) freezeBlocks(finalizedHeight uint64) {
+	threshold := uint64(viper.GetInt(common.CfgStorageFreezerThreshold))
+	if finalizedHeight <= threshold {
+		return
+	}
+	if _, err := e.chain.FreezeBlocks(finalizedHeight-threshold, maxFreezeBlocks); err != nil {
+		e.logger.WithFields(log.Fields{"error": err, "height": finalizedHeight - threshold}).Warn("Failed to freeze blocks")
+	}
+}
+
 func (e *ConsensusEngine) State() *State {
 	return e.state
 }
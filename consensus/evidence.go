@@ -0,0 +1,178 @@
+package consensus
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store"
+)
+
+const (
+	DBPendingEvidenceKey = "cs/evd"
+
+	// evidenceWindow is the number of heights below the last finalized block for which the votes
+	// and the proposals are kept to detect the double signing
+	evidenceWindow = 1000
+)
+
+type voteKey struct {
+	voter  common.Address
+	height uint64
+}
+
+type proposalKey struct {
+	proposer common.Address
+	epoch    uint64
+}
+
+type seenVote struct {
+	vote   core.Vote
+	header *core.BlockHeader
+}
+
+// EvidencePool detects the validators voting for two different blocks at the same height, or
+// proposing two different blocks in the same epoch. The evidence is persisted until it expires,
+// and picked up by the ledger to slash the validators when the node proposes a block.
+type EvidencePool struct {
+	mu *sync.Mutex
+
+	db      store.Store
+	chainID string
+
+	votes     map[voteKey]seenVote
+	proposals map[proposalKey]*core.BlockHeader
+	pending   []*core.DoubleSignEvidence
+}
+
+// NewEvidencePool creates an evidence pool, loading the pending evidence from the db.
+func NewEvidencePool(db store.Store, chainID string) *EvidencePool {
+	p := &EvidencePool{
+		mu:        &sync.Mutex{},
+		db:        db,
+		chainID:   chainID,
+		votes:     make(map[voteKey]seenVote),
+		proposals: make(map[proposalKey]*core.BlockHeader),
+	}
+	pending := []*core.DoubleSignEvidence{}
+	if err := db.Get([]byte(DBPendingEvidenceKey), &pending); err == nil {
+		p.pending = pending
+	}
+	return p
+}
+
+// AddVote records a vote on the block of the given header, and returns the evidence if the voter
+// has voted for another block at the same height.
+func (p *EvidencePool) AddVote(vote core.Vote, header *core.BlockHeader) *core.DoubleSignEvidence {
+	// The votes only signed with the BLS key cannot be verified without the validator set
+	if vote.IsBlsOnly() || header == nil || vote.Block != header.Hash() {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := voteKey{voter: vote.ID, height: header.Height}
+	seen, ok := p.votes[key]
+	if !ok {
+		p.votes[key] = seenVote{vote: vote, header: header}
+		return nil
+	}
+	if seen.vote.Block == vote.Block {
+		return nil
+	}
+	return p.addEvidence(core.NewDoubleVoteEvidence(seen.vote, seen.header, vote, header))
+}
+
+// AddProposal records a proposed block, and returns the evidence if the proposer has proposed
+// another block in the same epoch.
+func (p *EvidencePool) AddProposal(header *core.BlockHeader) *core.DoubleSignEvidence {
+	if header.Validate(p.chainID).IsError() {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := proposalKey{proposer: header.Proposer, epoch: header.Epoch}
+	seen, ok := p.proposals[key]
+	if !ok {
+		p.proposals[key] = header
+		return nil
+	}
+	if seen.Hash() == header.Hash() {
+		return nil
+	}
+	return p.addEvidence(core.NewDoubleProposalEvidence(seen, header))
+}
+
+func (p *EvidencePool) addEvidence(evidence *core.DoubleSignEvidence) *core.DoubleSignEvidence {
+	if evidence.Validate(p.chainID).IsError() {
+		return nil
+	}
+	for _, e := range p.pending {
+		if e.Offender() == evidence.Offender() && e.Height() == evidence.Height() {
+			return nil
+		}
+	}
+	p.pending = append(p.pending, evidence)
+	p.commit()
+
+	logger.WithFields(log.Fields{
+		"offender": evidence.Offender().Hex(),
+		"height":   evidence.Height(),
+		"evidence": evidence,
+	}).Warn("Detected double signing")
+	return evidence
+}
+
+// PendingEvidence returns the evidence not expired yet.
+func (p *EvidencePool) PendingEvidence() []*core.DoubleSignEvidence {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ret := make([]*core.DoubleSignEvidence, len(p.pending))
+	copy(ret, p.pending)
+	return ret
+}
+
+// Prune drops the votes and the proposals too old to matter, and the expired evidence, once the
+// block at the given height is finalized.
+func (p *EvidencePool) Prune(finalizedHeight uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if finalizedHeight > evidenceWindow {
+		minHeight := finalizedHeight - evidenceWindow
+		for key := range p.votes {
+			if key.height < minHeight {
+				delete(p.votes, key)
+			}
+		}
+		for key, header := range p.proposals {
+			if header.Height < minHeight {
+				delete(p.proposals, key)
+			}
+		}
+	}
+
+	pending := []*core.DoubleSignEvidence{}
+	for _, evidence := range p.pending {
+		if evidence.Height()+types.DoubleSignEvidenceMaxAge >= finalizedHeight {
+			pending = append(pending, evidence)
+		}
+	}
+	if len(pending) != len(p.pending) {
+		p.pending = pending
+		p.commit()
+	}
+}
+
+func (p *EvidencePool) commit() {
+	if err := p.db.Put([]byte(DBPendingEvidenceKey), p.pending); err != nil {
+		logger.WithFields(log.Fields{"err": err}).Error("Failed to persist the pending evidence")
+	}
+}
@@ -0,0 +1,104 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/pandotoken/pando/store/kvstore"
+)
+
+func newEvidenceTestHeader(priv *crypto.PrivateKey, height uint64, epoch uint64, parent string) *core.BlockHeader {
+	header := &core.BlockHeader{
+		ChainID:   "testchain",
+		Height:    height,
+		Epoch:     epoch,
+		Parent:    common.HexToHash(parent),
+		HCC:       core.CommitCertificate{BlockHash: common.HexToHash(parent)},
+		Timestamp: big.NewInt(100),
+		Proposer:  priv.PublicKey().Address(),
+	}
+	sig, _ := priv.Sign(header.SignBytes())
+	header.SetSignature(sig)
+	return header
+}
+
+func newEvidenceTestVote(priv *crypto.PrivateKey, header *core.BlockHeader) core.Vote {
+	vote := core.Vote{ID: priv.PublicKey().Address(), Block: header.Hash(), Height: header.Height, Epoch: header.Epoch}
+	vote.Sign(priv)
+	return vote
+}
+
+func TestEvidencePoolDoubleVote(t *testing.T) {
+	assert := assert.New(t)
+
+	db := kvstore.NewKVStore(backend.NewMemDatabase())
+	pool := NewEvidencePool(db, "testchain")
+
+	proposer, _, _ := crypto.GenerateKeyPair()
+	voter, _, _ := crypto.GenerateKeyPair()
+	headerA := newEvidenceTestHeader(proposer, 10, 12, "a1")
+	headerB := newEvidenceTestHeader(proposer, 10, 13, "a2")
+	headerC := newEvidenceTestHeader(proposer, 11, 14, "a2")
+
+	assert.Nil(pool.AddVote(newEvidenceTestVote(voter, headerA), headerA))
+	// Voting again for the same block is fine
+	assert.Nil(pool.AddVote(newEvidenceTestVote(voter, headerA), headerA))
+	// So is voting for a block of another height
+	assert.Nil(pool.AddVote(newEvidenceTestVote(voter, headerC), headerC))
+	assert.Equal(0, len(pool.PendingEvidence()))
+
+	evidence := pool.AddVote(newEvidenceTestVote(voter, headerB), headerB)
+	assert.NotNil(evidence)
+	assert.Equal(voter.PublicKey().Address(), evidence.Offender())
+	assert.Equal(1, len(pool.PendingEvidence()))
+
+	// The same offence is only reported once
+	assert.Nil(pool.AddVote(newEvidenceTestVote(voter, headerB), headerB))
+	assert.Equal(1, len(pool.PendingEvidence()))
+
+	// The pending evidence survives a restart
+	reloaded := NewEvidencePool(db, "testchain")
+	assert.Equal(1, len(reloaded.PendingEvidence()))
+	assert.Equal(evidence.Hash(), reloaded.PendingEvidence()[0].Hash())
+
+	// The evidence expires
+	reloaded.Prune(10 + types.DoubleSignEvidenceMaxAge)
+	assert.Equal(1, len(reloaded.PendingEvidence()))
+	reloaded.Prune(11 + types.DoubleSignEvidenceMaxAge)
+	assert.Equal(0, len(reloaded.PendingEvidence()))
+	assert.Equal(0, len(NewEvidencePool(db, "testchain").PendingEvidence()))
+}
+
+func TestEvidencePoolDoubleProposal(t *testing.T) {
+	assert := assert.New(t)
+
+	db := kvstore.NewKVStore(backend.NewMemDatabase())
+	pool := NewEvidencePool(db, "testchain")
+
+	proposer, _, _ := crypto.GenerateKeyPair()
+	headerA := newEvidenceTestHeader(proposer, 10, 12, "a1")
+	headerB := newEvidenceTestHeader(proposer, 10, 12, "a2")
+	headerC := newEvidenceTestHeader(proposer, 11, 13, "a2")
+
+	assert.Nil(pool.AddProposal(headerA))
+	assert.Nil(pool.AddProposal(headerA))
+	assert.Nil(pool.AddProposal(headerC))
+
+	evidence := pool.AddProposal(headerB)
+	assert.NotNil(evidence)
+	assert.False(evidence.IsDoubleVote())
+	assert.Equal(proposer.PublicKey().Address(), evidence.Offender())
+
+	// Unsigned proposals are ignored
+	unsigned := newEvidenceTestHeader(proposer, 12, 14, "a3")
+	unsigned.Signature = nil
+	assert.Nil(pool.AddProposal(unsigned))
+	assert.Equal(1, len(pool.PendingEvidence()))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pandotoken/pando/common/util"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto/bls"
+	"github.com/pandotoken/pando/features"
 )
 
 const (
@@ -30,7 +31,9 @@ type GuardianEngine struct {
 	nextVote    *core.AggregatedVotes
 	gcp         *core.GuardianCandidatePool
 	gcpHash     common.Hash
-	signerIndex int // Signer's index in current gcp
+	signerIndex int         // Signer's index in current gcp
+	beacon      bool        // Whether the votes sign the randomness beacon
+	seed        common.Hash // Seed of the randomness beacon
 
 	incoming chan *core.AggregatedVotes
 	mu       *sync.Mutex
@@ -75,8 +78,20 @@ func (g *GuardianEngine) StartNewBlock(block common.Hash) {
 		"signerIndex": g.signerIndex,
 	}).Debug("Starting new block")
 
+	// Sign the randomness beacon along with the votes, with the randomness of the voted block as the seed
+	g.beacon = false
+	g.seed = common.Hash{}
+	if eb, err := g.engine.chain.FindBlock(block); err == nil && features.IsEnabled(features.RandomnessBeacon, eb.Height) {
+		g.beacon = true
+		g.seed = eb.Randomness
+	}
+
 	if g.isGuardian() {
-		g.nextVote = core.NewAggregateVotes(block, gcp)
+		if g.beacon {
+			g.nextVote = core.NewAggregateVotesWithBeacon(block, gcp, g.seed)
+		} else {
+			g.nextVote = core.NewAggregateVotes(block, gcp)
+		}
 		g.nextVote.Sign(g.privKey, g.signerIndex)
 		g.currVote = g.nextVote.Copy()
 	} else {
@@ -251,6 +266,16 @@ func (g *GuardianEngine) validateVote(vote *core.AggregatedVotes) (res bool) {
 		}).Debug("Ignoring guardian vote: gcp hash does not match with local value")
 		return
 	}
+	if vote.HasBeacon() != g.beacon || vote.Seed != g.seed {
+		g.logger.WithFields(log.Fields{
+			"local.block": g.block.Hex(),
+			"local.round": g.round,
+			"vote.block":  vote.Block.Hex(),
+			"vote.seed":   vote.Seed.Hex(),
+			"local.seed":  g.seed.Hex(),
+		}).Debug("Ignoring guardian vote: beacon does not match with local value")
+		return
+	}
 	if !g.checkMultipliesForRound(vote, g.round) {
 		g.logger.WithFields(log.Fields{
 			"local.block":    g.block.Hex(),
@@ -0,0 +1,118 @@
+package consensus
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+)
+
+// VerifyRestartManifest checks that the manifest is signed by a quorum of the validators of the
+// blocks after its restart block, and returns the restart block
+func (e *ConsensusEngine) VerifyRestartManifest(m *core.RestartManifest) (*core.ExtendedBlock, error) {
+	block, validatorSet, err := e.GetRestartValidatorSet(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Verify(validatorSet); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// GetRestartValidatorSet checks that the restart block of the manifest is a finalized block of the
+// local chain with its state available. It returns the restart block and the validator set of the
+// blocks after it, which needs to sign the manifest.
+func (e *ConsensusEngine) GetRestartValidatorSet(m *core.RestartManifest) (*core.ExtendedBlock, *core.ValidatorSet, error) {
+	if m.ChainID != e.chain.ChainID {
+		return nil, nil, fmt.Errorf("The manifest is for chain %v, not %v", m.ChainID, e.chain.ChainID)
+	}
+
+	block, err := e.chain.FindBlock(m.BlockHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("The restart block %v is not found, the snapshot exported at the block needs to be imported first", m.BlockHash.Hex())
+	}
+	if block.Height != m.Height || block.StateHash != m.StateHash {
+		return nil, nil, fmt.Errorf("The restart block %v does not match the manifest, height: %v, state hash: %v",
+			m.BlockHash.Hex(), block.Height, block.StateHash.Hex())
+	}
+	if !block.Status.IsFinalized() && !block.Status.IsTrusted() {
+		return nil, nil, fmt.Errorf("The restart block %v is not finalized, status: %v", m.BlockHash.Hex(), block.Status)
+	}
+
+	vcp, err := e.ledger.GetFinalizedValidatorCandidatePool(m.BlockHash, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("The state of the restart block is not available: %v", err)
+	}
+	return block, SelectTopStakeHoldersAsValidators(vcp), nil
+}
+
+// restartFromManifest resumes the consensus from the restart block of the manifest in the config,
+// if any. The blocks built on top of the restart block are disposed. The manifest is applied once,
+// so the node can be restarted with the same config without rewinding again.
+func (e *ConsensusEngine) restartFromManifest(lastCC *core.ExtendedBlock) *core.ExtendedBlock {
+	manifestPath := viper.GetString(common.CfgConsensusRestartManifest)
+	if manifestPath == "" {
+		return lastCC
+	}
+
+	m, err := core.ReadRestartManifest(manifestPath)
+	if err != nil {
+		e.logger.WithFields(log.Fields{"error": err, "path": manifestPath}).Fatal("Failed to read the restart manifest")
+	}
+	if e.state.GetAppliedRestartManifest() == m.Hash() {
+		e.logger.WithFields(log.Fields{"manifest": m}).Info("Restart manifest already applied")
+		return lastCC
+	}
+
+	restartBlock, err := e.VerifyRestartManifest(m)
+	if err != nil {
+		e.logger.WithFields(log.Fields{"error": err, "manifest": m}).Fatal("Invalid restart manifest")
+	}
+
+	e.logger.WithFields(log.Fields{
+		"height": restartBlock.Height,
+		"block":  restartBlock.Hash().Hex(),
+		"reason": m.Reason,
+	}).Warn("Restarting the consensus from the block of the restart manifest")
+
+	disposed := e.disposeDescendants(restartBlock)
+
+	e.state.SetLastFinalizedBlock(restartBlock)
+	e.state.SetHighestCCBlock(restartBlock)
+	e.state.SetLastVote(core.Vote{})
+	e.state.SetLastProposal(core.Proposal{})
+	e.state.SetAppliedRestartManifest(m.Hash())
+
+	e.logger.WithFields(log.Fields{"disposed": disposed}).Info("Restart manifest applied")
+
+	return restartBlock
+}
+
+// disposeDescendants disposes all the blocks built on top of the block, and returns their number
+func (e *ConsensusEngine) disposeDescendants(block *core.ExtendedBlock) int {
+	disposed := 0
+	queue := append([]common.Hash{}, block.Children...)
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+
+		child, err := e.chain.FindBlock(hash)
+		if err != nil {
+			continue
+		}
+		queue = append(queue, child.Children...)
+
+		if child.Status == core.BlockStatusDisposed {
+			continue
+		}
+		child.Status = core.BlockStatusDisposed
+		e.chain.SaveBlock(child)
+		e.chain.RemoveVotesByHash(child.Hash())
+		disposed++
+	}
+	return disposed
+}
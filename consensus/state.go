@@ -21,9 +21,10 @@ type StateStub struct {
 }
 
 const (
-	DBStateStubKey      = "cs/ss"
-	DBVoteByBlockPrefix = "cs/vbb/"
-	DBEpochVotesKey     = "cs/ev"
+	DBStateStubKey       = "cs/ss"
+	DBVoteByBlockPrefix  = "cs/vbb/"
+	DBEpochVotesKey      = "cs/ev"
+	DBRestartManifestKey = "cs/rm"
 )
 
 type State struct {
@@ -223,3 +224,18 @@ func (s *State) AddEpochVote(vote *core.Vote) error {
 	key := []byte(DBEpochVotesKey)
 	return s.db.Put(key, voteset)
 }
+
+// GetAppliedRestartManifest returns the hash of the last restart manifest applied, empty if none
+func (s *State) GetAppliedRestartManifest() common.Hash {
+	key := []byte(DBRestartManifestKey)
+	var hash common.Hash
+	s.db.Get(key, &hash)
+	return hash
+}
+
+// SetAppliedRestartManifest records the restart manifest applied, so that it is not applied again
+// when the node restarts with the same manifest
+func (s *State) SetAppliedRestartManifest(hash common.Hash) error {
+	key := []byte(DBRestartManifestKey)
+	return s.db.Put(key, hash)
+}
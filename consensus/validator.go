@@ -151,6 +151,7 @@ func SelectTopStakeHoldersAsValidators(vcp *core.ValidatorCandidatePool) *core.V
 			continue
 		}
 		validator := core.NewValidator(valAddr, valStake)
+		validator.BlsPubkey = stakeHolder.BlsPubkey
 		valSet.AddValidator(validator)
 	}
 
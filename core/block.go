@@ -11,7 +11,9 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/store/database"
 	"github.com/pandotoken/pando/store/trie"
 )
 
@@ -109,14 +111,43 @@ func (b *Block) Validate(chainID string) result.Result {
 }
 
 func CalculateRootHash(items []common.Bytes) common.Hash {
-	keybuf := new(bytes.Buffer)
+	return newItemTrie(items).Hash()
+}
+
+// ProveTx constructs the Merkle proof of the transaction at the given index of the transactions,
+// whose root hash is the TxHash of their block.
+func ProveTx(txs []common.Bytes, index int, proofDb database.Putter) error {
+	if index < 0 || index >= len(txs) {
+		return fmt.Errorf("transaction index %v out of range", index)
+	}
+	return newItemTrie(txs).Prove(itemKey(index), 0, proofDb)
+}
+
+// VerifyTxProof checks the Merkle proof of the transaction at the given index against the TxHash of
+// a block, and returns the proven transaction.
+func VerifyTxProof(txHash common.Hash, index int, proofDb trie.DatabaseReader) (common.Bytes, error) {
+	tx, _, err := trie.VerifyProof(txHash, itemKey(index), proofDb)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx) == 0 {
+		return nil, fmt.Errorf("no transaction at index %v", index)
+	}
+	return tx, nil
+}
+
+func newItemTrie(items []common.Bytes) *trie.Trie {
 	trie := new(trie.Trie)
 	for i := 0; i < len(items); i++ {
-		keybuf.Reset()
-		rlp.Encode(keybuf, uint(i))
-		trie.Update(keybuf.Bytes(), items[i])
+		trie.Update(itemKey(i), items[i])
 	}
-	return trie.Hash()
+	return trie
+}
+
+func itemKey(index int) []byte {
+	keybuf := new(bytes.Buffer)
+	rlp.Encode(keybuf, uint(index))
+	return keybuf.Bytes()
 }
 
 // BlockHeader contains the essential information of a block.
@@ -127,6 +158,7 @@ type BlockHeader struct {
 	Parent        common.Hash
 	HCC           CommitCertificate
 	GuardianVotes *AggregatedVotes `rlp:"nil"` // Added in Pando2.0 fork.
+	Randomness    common.Hash      // Added in RandomnessBeacon fork.
 	TxHash        common.Hash
 	ReceiptHash   common.Hash `json:"-"`
 	Bloom         Bloom       `json:"-"`
@@ -145,7 +177,7 @@ func (h *BlockHeader) EncodeRLP(w io.Writer) error {
 	if h == nil {
 		return rlp.Encode(w, &BlockHeader{})
 	}
-	if h.Height < common.HeightEnablePando2 {
+	if !features.IsEnabled(features.Pando2, h.Height) {
 		return rlp.Encode(w, []interface{}{
 			h.ChainID,
 			h.Epoch,
@@ -163,7 +195,7 @@ func (h *BlockHeader) EncodeRLP(w io.Writer) error {
 	}
 
 	// Pando2.0 fork
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		h.ChainID,
 		h.Epoch,
 		h.Height,
@@ -177,7 +209,13 @@ func (h *BlockHeader) EncodeRLP(w io.Writer) error {
 		h.Proposer,
 		h.Signature,
 		h.GuardianVotes,
-	})
+	}
+
+	// RandomnessBeacon fork
+	if features.IsEnabled(features.RandomnessBeacon, h.Height) {
+		fields = append(fields, h.Randomness)
+	}
+	return rlp.Encode(w, fields)
 
 }
 
@@ -251,7 +289,7 @@ func (h *BlockHeader) DecodeRLP(stream *rlp.Stream) error {
 	}
 
 	// Pando2.0 fork
-	if h.Height >= common.HeightEnablePando2 {
+	if features.IsEnabled(features.Pando2, h.Height) {
 		raw, err := stream.Raw()
 		if err != nil {
 			return err
@@ -269,6 +307,14 @@ func (h *BlockHeader) DecodeRLP(stream *rlp.Stream) error {
 		}
 	}
 
+	// RandomnessBeacon fork
+	if features.IsEnabled(features.RandomnessBeacon, h.Height) {
+		err = stream.Decode(&h.Randomness)
+		if err != nil {
+			return err
+		}
+	}
+
 	return stream.ListEnd()
 }
 
@@ -2,13 +2,17 @@ package core
 
 import (
 	"encoding/hex"
+	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/store/database/backend"
 )
 
 func TestBlockEncoding(t *testing.T) {
@@ -168,3 +172,71 @@ func TestBlockBasicValidation(t *testing.T) {
 	require.True(res.IsError())
 	require.Equal("Signature verification failed", res.Message)
 }
+
+func TestTxProof(t *testing.T) {
+	require := require.New(t)
+
+	txs := []common.Bytes{}
+	for i := 0; i < 20; i++ {
+		txs = append(txs, common.Bytes(fmt.Sprintf("tx%v", i)))
+	}
+	txHash := CalculateRootHash(txs)
+
+	for _, index := range []int{0, 7, 19} {
+		proof := backend.NewMemDatabase()
+		require.Nil(ProveTx(txs, index, proof))
+		tx, err := VerifyTxProof(txHash, index, proof)
+		require.Nil(err)
+		require.Equal(txs[index], tx)
+
+		_, err = VerifyTxProof(txHash, index, backend.NewMemDatabase())
+		require.NotNil(err)
+	}
+
+	require.NotNil(ProveTx(txs, len(txs), backend.NewMemDatabase()))
+
+	// The proof does not verify against another root
+	proof := backend.NewMemDatabase()
+	require.Nil(ProveTx(txs, 3, proof))
+	_, err := VerifyTxProof(CalculateRootHash(txs[:10]), 3, proof)
+	require.NotNil(err)
+}
+
+func TestBlockHeaderRandomnessEncoding(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(features.Configure(map[string]uint64{features.RandomnessBeacon: 10}))
+	defer features.Configure(nil)
+
+	h := &BlockHeader{
+		ChainID:    "testchain",
+		Height:     10,
+		Timestamp:  big.NewInt(0),
+		Randomness: common.BytesToHash([]byte{12}),
+	}
+	raw, err := rlp.EncodeToBytes(h)
+	require.Nil(err)
+
+	decoded := &BlockHeader{}
+	require.Nil(rlp.DecodeBytes(raw, decoded))
+	require.Equal(h.Randomness, decoded.Randomness)
+	require.Equal(h.Hash(), decoded.Hash())
+
+	// The randomness is committed in the hash of the header
+	decoded.Randomness = common.BytesToHash([]byte{34})
+	require.NotEqual(h.Hash(), decoded.UpdateHash())
+
+	// The headers before the fork keep their encoding
+	h.Height = 9
+	raw, err = rlp.EncodeToBytes(h)
+	require.Nil(err)
+	decoded = &BlockHeader{}
+	require.Nil(rlp.DecodeBytes(raw, decoded))
+	require.Equal(common.Hash{}, decoded.Randomness)
+	h.Randomness = common.Hash{}
+	require.Equal(h.UpdateHash(), decoded.Hash())
+
+	// The headers after the fork must carry the randomness
+	require.Nil(features.Configure(map[string]uint64{features.RandomnessBeacon: 9}))
+	require.NotNil(rlp.DecodeBytes(raw, &BlockHeader{}))
+}
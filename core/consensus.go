@@ -8,7 +8,7 @@ import (
 // ConsensusEngine is the interface of a consensus engine.
 type ConsensusEngine interface {
 	ID() string
-	PrivateKey() *crypto.PrivateKey
+	Signer() crypto.Signer
 	GetTip(includePendingBlockingLeaf bool) *ExtendedBlock
 	GetEpoch() uint64
 	GetLedger() Ledger
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// DoubleSignEvidence proves that a validator signed two conflicting messages. It is either a
+// double vote, i.e. the votes of the validator on two different blocks of the same height along
+// with the headers of the blocks, or a double proposal, i.e. two different blocks proposed by
+// the validator in the same epoch.
+//
+// The height of a vote is not signed, so the headers of a double vote are needed to prove that
+// the voted blocks are at the same height.
+type DoubleSignEvidence struct {
+	VoteA   *Vote        `rlp:"nil"` // nil for a double proposal
+	VoteB   *Vote        `rlp:"nil"` // nil for a double proposal
+	HeaderA *BlockHeader `rlp:"nil"`
+	HeaderB *BlockHeader `rlp:"nil"`
+}
+
+// NewDoubleVoteEvidence creates the evidence of the votes on two blocks of the same height.
+func NewDoubleVoteEvidence(voteA Vote, headerA *BlockHeader, voteB Vote, headerB *BlockHeader) *DoubleSignEvidence {
+	return &DoubleSignEvidence{VoteA: &voteA, VoteB: &voteB, HeaderA: headerA, HeaderB: headerB}
+}
+
+// NewDoubleProposalEvidence creates the evidence of two blocks proposed in the same epoch.
+func NewDoubleProposalEvidence(headerA *BlockHeader, headerB *BlockHeader) *DoubleSignEvidence {
+	return &DoubleSignEvidence{HeaderA: headerA, HeaderB: headerB}
+}
+
+// IsDoubleVote returns whether the evidence is of a double vote, rather than a double proposal.
+func (e *DoubleSignEvidence) IsDoubleVote() bool {
+	return e.VoteA != nil || e.VoteB != nil
+}
+
+// Offender returns the address of the validator who double signed.
+func (e *DoubleSignEvidence) Offender() common.Address {
+	if e.IsDoubleVote() {
+		if e.VoteA == nil {
+			return common.Address{}
+		}
+		return e.VoteA.ID
+	}
+	if e.HeaderA == nil {
+		return common.Address{}
+	}
+	return e.HeaderA.Proposer
+}
+
+// Height returns the height of the conflicting blocks.
+func (e *DoubleSignEvidence) Height() uint64 {
+	if e.HeaderA == nil {
+		return 0
+	}
+	return e.HeaderA.Height
+}
+
+// Hash returns the hash identifying the evidence.
+func (e *DoubleSignEvidence) Hash() common.Hash {
+	raw, _ := rlp.EncodeToBytes(e)
+	return crypto.Keccak256Hash(raw)
+}
+
+// Validate checks that the evidence proves the double signing of its offender on the chain.
+func (e *DoubleSignEvidence) Validate(chainID string) result.Result {
+	if e.HeaderA == nil || e.HeaderB == nil {
+		return result.Error("Both headers must be specified")
+	}
+	if e.HeaderA.ChainID != chainID || e.HeaderB.ChainID != chainID {
+		return result.Error("ChainID mismatch")
+	}
+
+	if e.IsDoubleVote() {
+		if e.VoteA == nil || e.VoteB == nil {
+			return result.Error("Both votes must be specified")
+		}
+		if e.VoteA.ID != e.VoteB.ID {
+			return result.Error("Votes are cast by different validators")
+		}
+		if res := e.VoteA.Validate(); res.IsError() {
+			return res
+		}
+		if res := e.VoteB.Validate(); res.IsError() {
+			return res
+		}
+		if e.VoteA.Block != e.HeaderA.Hash() || e.VoteB.Block != e.HeaderB.Hash() {
+			return result.Error("Votes are not on the given headers")
+		}
+		if e.VoteA.Block == e.VoteB.Block {
+			return result.Error("Votes are on the same block")
+		}
+		if e.HeaderA.Height != e.HeaderB.Height {
+			return result.Error("Voted blocks are at different heights")
+		}
+		return result.OK
+	}
+
+	if e.HeaderA.Proposer != e.HeaderB.Proposer {
+		return result.Error("Blocks are proposed by different validators")
+	}
+	if e.HeaderA.Epoch != e.HeaderB.Epoch {
+		return result.Error("Blocks are proposed in different epochs")
+	}
+	if res := e.HeaderA.Validate(chainID); res.IsError() {
+		return res
+	}
+	if res := e.HeaderB.Validate(chainID); res.IsError() {
+		return res
+	}
+	if bytes.Equal(e.HeaderA.SignBytes(), e.HeaderB.SignBytes()) {
+		return result.Error("Proposals are the same block")
+	}
+	return result.OK
+}
+
+func (e *DoubleSignEvidence) String() string {
+	if e.IsDoubleVote() {
+		return fmt.Sprintf("DoubleSignEvidence{offender: %v, height: %v, vote_a: %v, vote_b: %v}",
+			e.Offender().Hex(), e.Height(), e.VoteA, e.VoteB)
+	}
+	return fmt.Sprintf("DoubleSignEvidence{offender: %v, height: %v, proposal_a: %v, proposal_b: %v}",
+		e.Offender().Hex(), e.Height(), e.HeaderA.Hash().Hex(), e.HeaderB.Hash().Hex())
+}
+
+// EvidenceSource is implemented by the consensus engines collecting the evidence of the validators
+// double signing, which the proposers include in their blocks as slash transactions.
+type EvidenceSource interface {
+	PendingEvidence() []*DoubleSignEvidence
+}
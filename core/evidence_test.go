@@ -0,0 +1,119 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+func newSignedHeader(priv *crypto.PrivateKey, height uint64, epoch uint64, parent string) *BlockHeader {
+	header := &BlockHeader{
+		ChainID:   "test",
+		Height:    height,
+		Epoch:     epoch,
+		Parent:    common.HexToHash(parent),
+		HCC:       CommitCertificate{BlockHash: common.HexToHash(parent)},
+		Timestamp: big.NewInt(100),
+		Proposer:  priv.PublicKey().Address(),
+	}
+	sig, _ := priv.Sign(header.SignBytes())
+	header.SetSignature(sig)
+	return header
+}
+
+func newSignedVote(priv *crypto.PrivateKey, header *BlockHeader) Vote {
+	vote := Vote{ID: priv.PublicKey().Address(), Block: header.Hash(), Height: header.Height, Epoch: header.Epoch}
+	vote.Sign(priv)
+	return vote
+}
+
+func TestDoubleVoteEvidence(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	proposer, _, _ := crypto.GenerateKeyPair()
+	voter, _, _ := crypto.GenerateKeyPair()
+	headerA := newSignedHeader(proposer, 10, 12, "a1")
+	headerB := newSignedHeader(proposer, 10, 13, "a2")
+
+	evidence := NewDoubleVoteEvidence(newSignedVote(voter, headerA), headerA, newSignedVote(voter, headerB), headerB)
+	assert.True(evidence.IsDoubleVote())
+	assert.Equal(voter.PublicKey().Address(), evidence.Offender())
+	assert.Equal(uint64(10), evidence.Height())
+	assert.True(evidence.Validate("test").IsOK())
+	assert.True(evidence.Validate("other").IsError())
+
+	// The evidence survives the encoding
+	raw, err := rlp.EncodeToBytes(evidence)
+	require.Nil(err)
+	decoded := &DoubleSignEvidence{}
+	require.Nil(rlp.DecodeBytes(raw, decoded))
+	assert.Equal(evidence.Hash(), decoded.Hash())
+	assert.True(decoded.Validate("test").IsOK())
+
+	// Votes on the same block
+	same := NewDoubleVoteEvidence(newSignedVote(voter, headerA), headerA, newSignedVote(voter, headerA), headerA)
+	assert.True(same.Validate("test").IsError())
+
+	// Votes on blocks of different heights
+	headerC := newSignedHeader(proposer, 11, 13, "a2")
+	different := NewDoubleVoteEvidence(newSignedVote(voter, headerA), headerA, newSignedVote(voter, headerC), headerC)
+	assert.True(different.Validate("test").IsError())
+
+	// Votes of different validators
+	other, _, _ := crypto.GenerateKeyPair()
+	mixed := NewDoubleVoteEvidence(newSignedVote(voter, headerA), headerA, newSignedVote(other, headerB), headerB)
+	assert.True(mixed.Validate("test").IsError())
+
+	// Vote not on the given header
+	mismatched := NewDoubleVoteEvidence(newSignedVote(voter, headerA), headerB, newSignedVote(voter, headerB), headerA)
+	assert.True(mismatched.Validate("test").IsError())
+
+	// Forged signature
+	forgedVote := newSignedVote(voter, headerB)
+	forgedVote.Signature = newSignedVote(voter, headerA).Signature
+	forged := NewDoubleVoteEvidence(newSignedVote(voter, headerA), headerA, forgedVote, headerB)
+	assert.True(forged.Validate("test").IsError())
+}
+
+func TestDoubleProposalEvidence(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	proposer, _, _ := crypto.GenerateKeyPair()
+	headerA := newSignedHeader(proposer, 10, 12, "a1")
+	headerB := newSignedHeader(proposer, 10, 12, "a2")
+
+	evidence := NewDoubleProposalEvidence(headerA, headerB)
+	assert.False(evidence.IsDoubleVote())
+	assert.Equal(proposer.PublicKey().Address(), evidence.Offender())
+	assert.True(evidence.Validate("test").IsOK())
+
+	raw, err := rlp.EncodeToBytes(evidence)
+	require.Nil(err)
+	decoded := &DoubleSignEvidence{}
+	require.Nil(rlp.DecodeBytes(raw, decoded))
+	assert.False(decoded.IsDoubleVote())
+	assert.True(decoded.Validate("test").IsOK())
+
+	// Same block proposed twice
+	assert.True(NewDoubleProposalEvidence(headerA, headerA).Validate("test").IsError())
+
+	// Blocks proposed in different epochs
+	headerC := newSignedHeader(proposer, 10, 13, "a2")
+	assert.True(NewDoubleProposalEvidence(headerA, headerC).Validate("test").IsError())
+
+	// Blocks proposed by different validators
+	other, _, _ := crypto.GenerateKeyPair()
+	headerD := newSignedHeader(other, 10, 12, "a2")
+	assert.True(NewDoubleProposalEvidence(headerA, headerD).Validate("test").IsError())
+
+	// Missing header
+	assert.True(NewDoubleProposalEvidence(headerA, nil).Validate("test").IsError())
+}
@@ -7,3 +7,16 @@ const (
 
 	GenesisBlockHeight = uint64(0)
 )
+
+// genesisBlockHashes holds the genesis block hashes of the released networks. The
+// genesis block of a chain listed here has to match the embedded hash, regardless
+// of the genesis hash in the local config.
+var genesisBlockHashes = map[string]string{
+	MainnetChainID: MainnetGenesisBlockHash,
+}
+
+// KnownGenesisBlockHash returns the genesis block hash embedded in the binary for the given chain.
+func KnownGenesisBlockHash(chainID string) (string, bool) {
+	hash, ok := genesisBlockHashes[chainID]
+	return hash, ok
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// GenesisSignatures is the detached signature file of the genesis block of a network, signed by
+// its release keys. The genesis block commits to the initial allocations through its state hash,
+// which the snapshot import checks against the state trie, so a valid signature over the block
+// hash covers the whole genesis file.
+type GenesisSignatures struct {
+	ChainID     string      `json:"chain_id"`
+	GenesisHash common.Hash `json:"genesis_hash"`
+
+	Signatures []*GenesisSignature `json:"signatures"`
+}
+
+// GenesisSignature is the signature of a release key over the genesis block
+type GenesisSignature struct {
+	Signer    common.Address    `json:"signer"`
+	Signature *crypto.Signature `json:"signature"`
+}
+
+// SignBytes returns the bytes the release keys sign
+func (g *GenesisSignatures) SignBytes() common.Bytes {
+	raw, err := rlp.EncodeToBytes([]interface{}{"genesis", g.ChainID, g.GenesisHash})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the genesis signatures: %v", err))
+	}
+	return raw
+}
+
+// AddSignature adds the signature of the signer, replacing its previous signature if any
+func (g *GenesisSignatures) AddSignature(signer common.Address, sig *crypto.Signature) error {
+	if sig == nil || !sig.Verify(g.SignBytes(), signer) {
+		return fmt.Errorf("Invalid signature of %v", signer.Hex())
+	}
+	for _, s := range g.Signatures {
+		if s.Signer == signer {
+			s.Signature = sig
+			return nil
+		}
+	}
+	g.Signatures = append(g.Signatures, &GenesisSignature{Signer: signer, Signature: sig})
+	return nil
+}
+
+// Verify checks that the signatures are for the genesis block, and that at least threshold of the
+// trusted signers signed it. The signatures of the other keys are ignored.
+func (g *GenesisSignatures) Verify(genesis *BlockHeader, signers []common.Address, threshold int) error {
+	if g.ChainID != genesis.ChainID || g.GenesisHash != genesis.Hash() {
+		return fmt.Errorf("The signatures are for the genesis block %v of chain %v, got block %v of chain %v",
+			g.GenesisHash.Hex(), g.ChainID, genesis.Hash().Hex(), genesis.ChainID)
+	}
+
+	signBytes := g.SignBytes()
+	signed := make(map[common.Address]bool)
+	for _, s := range g.Signatures {
+		if s.Signature == nil || !s.Signature.Verify(signBytes, s.Signer) {
+			return fmt.Errorf("Invalid signature of %v", s.Signer.Hex())
+		}
+		for _, signer := range signers {
+			if signer == s.Signer {
+				signed[signer] = true
+			}
+		}
+	}
+	if len(signed) < threshold {
+		return fmt.Errorf("The genesis block is signed by %v of the trusted signers, %v required", len(signed), threshold)
+	}
+	return nil
+}
+
+// ReadGenesisSignatures reads the signatures from the JSON file
+func ReadGenesisSignatures(filePath string) (*GenesisSignatures, error) {
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	g := &GenesisSignatures{}
+	if err := json.Unmarshal(raw, g); err != nil {
+		return nil, fmt.Errorf("Failed to parse the genesis signatures %v: %v", filePath, err)
+	}
+	return g, nil
+}
+
+// WriteGenesisSignatures writes the signatures to the JSON file
+func WriteGenesisSignatures(filePath string, g *GenesisSignatures) error {
+	raw, err := json.MarshalIndent(g, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, raw, 0644)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"math/big"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+)
+
+func TestGenesisSignatures(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	keys := []*crypto.PrivateKey{}
+	signers := []common.Address{}
+	for i := 0; i < 3; i++ {
+		priv, _, _ := crypto.GenerateKeyPair()
+		keys = append(keys, priv)
+		signers = append(signers, priv.PublicKey().Address())
+	}
+
+	genesis := &BlockHeader{
+		ChainID:   "test",
+		Height:    GenesisBlockHeight,
+		StateHash: common.HexToHash("0x1001"),
+		Timestamp: big.NewInt(0),
+	}
+	g := &GenesisSignatures{ChainID: genesis.ChainID, GenesisHash: genesis.Hash()}
+	sign := func(priv *crypto.PrivateKey) *crypto.Signature {
+		sig, err := priv.Sign(g.SignBytes())
+		require.Nil(err)
+		return sig
+	}
+
+	// Unsigned
+	assert.NotNil(g.Verify(genesis, signers, 1))
+
+	require.Nil(g.AddSignature(signers[0], sign(keys[0])))
+	assert.Nil(g.Verify(genesis, signers, 1))
+	assert.NotNil(g.Verify(genesis, signers, 2))
+
+	// A signature of another key is rejected
+	assert.NotNil(g.AddSignature(signers[1], sign(keys[2])))
+
+	// Only the trusted signers count
+	outsider, _, _ := crypto.GenerateKeyPair()
+	require.Nil(g.AddSignature(outsider.PublicKey().Address(), sign(outsider)))
+	assert.NotNil(g.Verify(genesis, signers, 2))
+	require.Nil(g.AddSignature(signers[1], sign(keys[1])))
+	assert.Nil(g.Verify(genesis, signers, 2))
+
+	// The signatures do not cover a tampered genesis block
+	tampered := *genesis
+	tampered.StateHash = common.HexToHash("0x1002")
+	tampered.UpdateHash()
+	assert.NotNil(g.Verify(&tampered, signers, 1))
+	g.Signatures[0].Signature = sign(outsider)
+	assert.NotNil(g.Verify(genesis, signers, 1))
+	g.Signatures[0].Signature = sign(keys[0])
+
+	// The signatures are preserved through the file
+	filePath := path.Join(t.TempDir(), "genesis.sig")
+	require.Nil(WriteGenesisSignatures(filePath, g))
+	g2, err := ReadGenesisSignatures(filePath)
+	require.Nil(err)
+	assert.Equal(g.SignBytes(), g2.SignBytes())
+	assert.Nil(g2.Verify(genesis, signers, 2))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/crypto"
 	"github.com/pandotoken/pando/crypto/bls"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/rlp"
 )
 
@@ -24,6 +25,10 @@ type AggregatedVotes struct {
 	Gcp        common.Hash    // Hash of guardian candidate pool.
 	Multiplies []uint32       // Multiplies of each signer.
 	Signature  *bls.Signature // Aggregated signiature.
+
+	// Added in the RandomnessBeacon fork.
+	Seed   common.Hash    `rlp:"optional"` // Randomness of the voted block, signed by the guardians for the beacon.
+	Beacon *bls.Signature `rlp:"optional"` // Aggregated signature over the beacon message of the seed.
 }
 
 func NewAggregateVotes(block common.Hash, gcp *GuardianCandidatePool) *AggregatedVotes {
@@ -35,8 +40,23 @@ func NewAggregateVotes(block common.Hash, gcp *GuardianCandidatePool) *Aggregate
 	}
 }
 
+// NewAggregateVotesWithBeacon creates the votes on a block, with which the guardians also sign the
+// randomness beacon message of the seed.
+func NewAggregateVotesWithBeacon(block common.Hash, gcp *GuardianCandidatePool, seed common.Hash) *AggregatedVotes {
+	votes := NewAggregateVotes(block, gcp)
+	votes.Seed = seed
+	votes.Beacon = bls.NewAggregateSignature()
+	return votes
+}
+
 func (a *AggregatedVotes) String() string {
-	return fmt.Sprintf("AggregatedVotes{Block: %s, Gcp: %s,  Multiplies: %v}", a.Block.Hex(), a.Gcp.Hex(), a.Multiplies)
+	return fmt.Sprintf("AggregatedVotes{Block: %s, Gcp: %s,  Multiplies: %v, Seed: %s, HasBeacon: %v}", a.Block.Hex(), a.Gcp.Hex(),
+		a.Multiplies, a.Seed.Hex(), a.HasBeacon())
+}
+
+// HasBeacon returns whether the guardians signed the randomness beacon with the votes
+func (a *AggregatedVotes) HasBeacon() bool {
+	return !a.Beacon.IsEmpty()
 }
 
 // signBytes returns the bytes to be signed.
@@ -58,13 +78,16 @@ func (a *AggregatedVotes) Sign(key *bls.SecretKey, signerIdx int) bool {
 
 	a.Multiplies[signerIdx] = 1
 	a.Signature.Aggregate(key.Sign(a.signBytes()))
+	if a.HasBeacon() {
+		a.Beacon.Aggregate(key.Sign(BeaconSignBytes(a.Seed)))
+	}
 	return true
 }
 
 // Merge creates a new aggregation that combines two vote sets. Returns nil, nil if input vote
 // is a subset of current vote.
 func (a *AggregatedVotes) Merge(b *AggregatedVotes) (*AggregatedVotes, error) {
-	if a.Block != b.Block || a.Gcp != b.Gcp {
+	if a.Block != b.Block || a.Gcp != b.Gcp || a.Seed != b.Seed || a.HasBeacon() != b.HasBeacon() {
 		return nil, errors.New("Cannot merge incompatible votes")
 	}
 	newMultiplies := make([]uint32, len(a.Multiplies))
@@ -84,12 +107,18 @@ func (a *AggregatedVotes) Merge(b *AggregatedVotes) (*AggregatedVotes, error) {
 	}
 	newSig := a.Signature.Copy()
 	newSig.Aggregate(b.Signature)
-	return &AggregatedVotes{
+	merged := &AggregatedVotes{
 		Block:      a.Block,
 		Gcp:        a.Gcp,
 		Multiplies: newMultiplies,
 		Signature:  newSig,
-	}, nil
+		Seed:       a.Seed,
+	}
+	if a.HasBeacon() {
+		merged.Beacon = a.Beacon.Copy()
+		merged.Beacon.Aggregate(b.Beacon)
+	}
+	return merged, nil
 }
 
 // Abs returns the number of voted guardians in the vote
@@ -130,6 +159,10 @@ func (a *AggregatedVotes) Validate(gcp *GuardianCandidatePool) result.Result {
 	if !a.Signature.Verify(a.signBytes(), aggPubkey) {
 		return result.Error("signature verification failed")
 	}
+	// The beacon is signed by the same guardians as the votes
+	if a.HasBeacon() && !a.Beacon.Verify(BeaconSignBytes(a.Seed), aggPubkey) {
+		return result.Error("beacon signature verification failed")
+	}
 	return result.OK
 }
 
@@ -138,6 +171,7 @@ func (a *AggregatedVotes) Copy() *AggregatedVotes {
 	clone := &AggregatedVotes{
 		Block: a.Block,
 		Gcp:   a.Gcp,
+		Seed:  a.Seed,
 	}
 	if a.Multiplies != nil {
 		clone.Multiplies = make([]uint32, len(a.Multiplies))
@@ -146,6 +180,9 @@ func (a *AggregatedVotes) Copy() *AggregatedVotes {
 	if a.Signature != nil {
 		clone.Signature = a.Signature.Copy()
 	}
+	if a.Beacon != nil {
+		clone.Beacon = a.Beacon.Copy()
+	}
 
 	return clone
 }
@@ -289,7 +326,7 @@ func (gcp *GuardianCandidatePool) Hash() common.Hash {
 
 func (gcp *GuardianCandidatePool) DepositStake(source common.Address, holder common.Address, amount *big.Int, pubkey *bls.PublicKey, blockHeight uint64) (err error) {
 	minGuardianStake := MinGuardianStakeDeposit
-	if blockHeight >= common.HeightLowerGNStakeThresholdTo1000 {
+	if features.IsEnabled(features.LowerGNStakeThresholdTo1000, blockHeight) {
 		minGuardianStake = MinGuardianStakeDeposit1000
 	}
 	if amount.Cmp(minGuardianStake) < 0 {
@@ -319,22 +356,38 @@ func (gcp *GuardianCandidatePool) DepositStake(source common.Address, holder com
 }
 
 func (gcp *GuardianCandidatePool) WithdrawStake(source common.Address, holder common.Address, currentHeight uint64) error {
-	matchedHolderFound := false
+	_, err := gcp.WithdrawStakeUntil(source, holder, currentHeight+ReturnLockingPeriod)
+	return err
+}
+
+// WithdrawStakeUntil withdraws the stake of the source from the holder, and locks it until the
+// return height. It returns the withdrawn stake.
+func (gcp *GuardianCandidatePool) WithdrawStakeUntil(source common.Address, holder common.Address, returnHeight uint64) (*Stake, error) {
 	for _, g := range gcp.SortedGuardians {
 		if g.Holder == holder {
-			matchedHolderFound = true
-			err := g.withdrawStake(source, currentHeight)
-			if err != nil {
-				return err
-			}
-			break
+			return g.withdrawStakeUntil(source, returnHeight)
 		}
 	}
+	return nil, fmt.Errorf("No matched stake holder address found: %v", holder)
+}
 
-	if !matchedHolderFound {
-		return fmt.Errorf("No matched stake holder address found: %v", holder)
+// ReturnStake removes the withdrawn stake of the source from the holder once its return height
+// is reached, and returns it.
+func (gcp *GuardianCandidatePool) ReturnStake(source common.Address, holder common.Address, currentHeight uint64) (*Stake, error) {
+	for _, g := range gcp.SortedGuardians {
+		if g.Holder != holder {
+			continue
+		}
+		stake, err := g.returnStake(source, currentHeight)
+		if err != nil {
+			return nil, err
+		}
+		if len(g.Stakes) == 0 {
+			gcp.Remove(g.Holder)
+		}
+		return stake, nil
 	}
-	return nil
+	return nil, fmt.Errorf("No matched stake holder address found: %v", holder)
 }
 
 func (gcp *GuardianCandidatePool) ReturnStakes(currentHeight uint64) []*Stake {
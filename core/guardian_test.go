@@ -190,3 +190,77 @@ func TestAggregateVoteEncoding(t *testing.T) {
 	err = rlp.DecodeBytes(raw, vote2)
 	require.Nil(err)
 }
+
+func TestAggregateVoteBeacon(t *testing.T) {
+	require := require.New(t)
+
+	pool, sks := createTestGuardianPool(10)
+
+	bh := common.BytesToHash([]byte{12})
+	seed := common.BytesToHash([]byte{34})
+
+	g1 := pool.SortedGuardians[0].Holder
+	vote1 := NewAggregateVotesWithBeacon(bh, pool, seed)
+	require.True(vote1.Sign(sks[g1], 0))
+	require.True(vote1.Validate(pool).IsOK())
+
+	g2 := pool.SortedGuardians[1].Holder
+	vote2 := NewAggregateVotesWithBeacon(bh, pool, seed)
+	require.True(vote2.Sign(sks[g2], 1))
+
+	vote12, err := vote1.Merge(vote2)
+	require.Nil(err)
+	require.True(vote12.Validate(pool).IsOK())
+
+	// The beacon is preserved through the encoding
+	raw, err := rlp.EncodeToBytes(vote12)
+	require.Nil(err)
+	decoded := &AggregatedVotes{}
+	require.Nil(rlp.DecodeBytes(raw, decoded))
+	require.Equal(seed, decoded.Seed)
+	require.True(decoded.Beacon.Equals(vote12.Beacon))
+	require.True(decoded.Validate(pool).IsOK())
+
+	// The beacon does not depend on the order the votes are merged in
+	vote21, err := vote2.Merge(vote1)
+	require.Nil(err)
+	require.Equal(NextRandomness(seed, vote12), NextRandomness(seed, vote21))
+
+	// Votes without the beacon, or with another seed, cannot be merged
+	vote3 := NewAggregateVotes(bh, pool)
+	require.True(vote3.Sign(sks[pool.SortedGuardians[2].Holder], 2))
+	_, err = vote12.Merge(vote3)
+	require.NotNil(err)
+	vote4 := NewAggregateVotesWithBeacon(bh, pool, common.BytesToHash([]byte{56}))
+	require.True(vote4.Sign(sks[pool.SortedGuardians[3].Holder], 3))
+	_, err = vote12.Merge(vote4)
+	require.NotNil(err)
+
+	// A tampered seed invalidates the beacon
+	tampered := vote12.Copy()
+	tampered.Seed = common.BytesToHash([]byte{56})
+	require.True(tampered.Validate(pool).IsError())
+}
+
+func TestNextRandomness(t *testing.T) {
+	require := require.New(t)
+
+	pool, sks := createTestGuardianPool(4)
+	bh := common.BytesToHash([]byte{12})
+	seed := common.BytesToHash([]byte{34})
+
+	vote := NewAggregateVotesWithBeacon(bh, pool, seed)
+	require.True(vote.Sign(sks[pool.SortedGuardians[0].Holder], 0))
+
+	// The randomness advances with a beacon of the parent randomness
+	next := NextRandomness(seed, vote)
+	require.NotEqual(seed, next)
+	require.Equal(next, NextRandomness(seed, vote.Copy()))
+
+	// The randomness stays the same otherwise
+	require.Equal(seed, NextRandomness(seed, nil))
+	require.Equal(next, NextRandomness(next, vote))
+	noBeacon := NewAggregateVotes(bh, pool)
+	require.True(noBeacon.Sign(sks[pool.SortedGuardians[0].Holder], 0))
+	require.Equal(seed, NextRandomness(seed, noBeacon))
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"strings"
+)
+
+const (
+	TestnetChainID = "testnet"
+	DevnetChainID  = "privatenet"
+
+	// DefaultRPCPort is the port the RPC service of a node listens on by default.
+	DefaultRPCPort = 16888
+)
+
+// Network describes a known Pando network.
+type Network struct {
+	Name        string `json:"name"`
+	ChainID     string `json:"chain_id"`
+	RPCPort     uint16 `json:"rpc_port"`
+	ExplorerURL string `json:"explorer_url"`
+}
+
+// knownNetworks is the registry of the networks clients can select by name. The
+// explorer URL is empty for networks without a public explorer.
+var knownNetworks = []Network{
+	{
+		Name:    "mainnet",
+		ChainID: MainnetChainID,
+		RPCPort: DefaultRPCPort,
+	},
+	{
+		Name:    "testnet",
+		ChainID: TestnetChainID,
+		RPCPort: DefaultRPCPort,
+	},
+	{
+		Name:    "devnet",
+		ChainID: DevnetChainID,
+		RPCPort: DefaultRPCPort,
+	},
+}
+
+// KnownNetworks returns the registry of known networks.
+func KnownNetworks() []Network {
+	networks := make([]Network, len(knownNetworks))
+	copy(networks, knownNetworks)
+	return networks
+}
+
+// LookupNetwork returns the known network with the given name or chain ID.
+func LookupNetwork(nameOrChainID string) (Network, bool) {
+	for _, network := range knownNetworks {
+		if strings.EqualFold(network.Name, nameOrChainID) || network.ChainID == nameOrChainID {
+			return network, true
+		}
+	}
+	return Network{}, false
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	network, ok := LookupNetwork("mainnet")
+	assert.True(ok)
+	assert.Equal(MainnetChainID, network.ChainID)
+
+	network, ok = LookupNetwork("Testnet")
+	assert.True(ok)
+	assert.Equal(TestnetChainID, network.ChainID)
+
+	network, ok = LookupNetwork(DevnetChainID)
+	assert.True(ok)
+	assert.Equal("devnet", network.Name)
+
+	_, ok = LookupNetwork("unknown")
+	assert.False(ok)
+
+	// The registry can not be modified by the callers
+	networks := KnownNetworks()
+	networks[0].ChainID = "modified"
+	network, _ = LookupNetwork("mainnet")
+	assert.Equal(MainnetChainID, network.ChainID)
+}
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// BeaconSignBytes returns the beacon message the guardians sign for the seed. The message does not
+// depend on the content of any block, and a BLS signature is unique for a key and a message, so
+// neither the guardians nor the proposers can grind the beacon.
+func BeaconSignBytes(seed common.Hash) common.Bytes {
+	b, _ := rlp.EncodeToBytes([]interface{}{"randomness_beacon", seed})
+	return b
+}
+
+// NextRandomness returns the randomness of a block, given the randomness of its parent and the
+// guardian votes it carries. The randomness advances at the checkpoints whose guardian votes sign
+// the beacon of the parent randomness, and otherwise stays the same for the whole epoch. A beacon
+// of an older seed was already revealed, and thus is not used.
+func NextRandomness(parentRandomness common.Hash, votes *AggregatedVotes) common.Hash {
+	if votes == nil || !votes.HasBeacon() || votes.Seed != parentRandomness {
+		return parentRandomness
+	}
+	return crypto.Keccak256Hash(parentRandomness[:], votes.Beacon.ToBytes())
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/sha3"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// RestartManifest designates the block a halted chain restarts from. It is signed by a quorum of
+// the validators which extend the block, i.e. by more than 2/3 of their stake. A node started
+// with the manifest disposes the blocks built on top of the restart block, and resumes the
+// consensus from it.
+type RestartManifest struct {
+	ChainID      string      `json:"chain_id"`
+	Height       uint64      `json:"height"`
+	BlockHash    common.Hash `json:"block_hash"`
+	StateHash    common.Hash `json:"state_hash"`
+	SnapshotHash common.Hash `json:"snapshot_hash"` // hash of the snapshot exported at the block, empty if none
+	Reason       string      `json:"reason"`
+
+	Signatures []*RestartManifestSignature `json:"signatures"`
+}
+
+// RestartManifestSignature is the signature of a validator over a restart manifest
+type RestartManifestSignature struct {
+	Validator common.Address    `json:"validator"`
+	Signature *crypto.Signature `json:"signature"`
+}
+
+// SignBytes returns the bytes the validators sign, which cover everything but the signatures
+func (m *RestartManifest) SignBytes() common.Bytes {
+	raw, err := rlp.EncodeToBytes([]interface{}{
+		"restart_manifest", m.ChainID, m.Height, m.BlockHash, m.StateHash, m.SnapshotHash, m.Reason,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the restart manifest: %v", err))
+	}
+	return raw
+}
+
+// Hash identifies the manifest regardless of the signatures collected
+func (m *RestartManifest) Hash() common.Hash {
+	return crypto.Keccak256Hash(m.SignBytes())
+}
+
+// AddSignature adds the signature of the validator, replacing its previous signature if any
+func (m *RestartManifest) AddSignature(validator common.Address, sig *crypto.Signature) error {
+	if sig == nil || !sig.Verify(m.SignBytes(), validator) {
+		return fmt.Errorf("Invalid signature of %v", validator.Hex())
+	}
+	for _, s := range m.Signatures {
+		if s.Validator == validator {
+			s.Signature = sig
+			return nil
+		}
+	}
+	m.Signatures = append(m.Signatures, &RestartManifestSignature{Validator: validator, Signature: sig})
+	return nil
+}
+
+// SignedStake returns the stake of the validators in the set which signed the manifest. It fails
+// on any invalid signature, or on a signature of a validator outside of the set.
+func (m *RestartManifest) SignedStake(validatorSet *ValidatorSet) (*big.Int, error) {
+	signBytes := m.SignBytes()
+	signers := []common.Address{}
+	for _, s := range m.Signatures {
+		if s.Signature == nil || !s.Signature.Verify(signBytes, s.Validator) {
+			return nil, fmt.Errorf("Invalid signature of %v", s.Validator.Hex())
+		}
+		if validatorSet.Index(s.Validator) < 0 {
+			return nil, fmt.Errorf("%v is not a validator of the blocks after the restart block", s.Validator.Hex())
+		}
+		for _, signer := range signers {
+			if signer == s.Validator {
+				return nil, fmt.Errorf("Duplicate signature of %v", s.Validator.Hex())
+			}
+		}
+		signers = append(signers, s.Validator)
+	}
+
+	signedStake := big.NewInt(0)
+	for _, signer := range signers {
+		validator, _ := validatorSet.GetValidator(signer)
+		signedStake.Add(signedStake, validator.Stake)
+	}
+	return signedStake, nil
+}
+
+// Verify checks that the manifest is signed by more than 2/3 of the stake of the validator set
+func (m *RestartManifest) Verify(validatorSet *ValidatorSet) error {
+	signedStake, err := m.SignedStake(validatorSet)
+	if err != nil {
+		return err
+	}
+	if !hasMajorityStake(signedStake, validatorSet.TotalStake()) {
+		return fmt.Errorf("The manifest is signed by %v of the total stake of %v, more than 2/3 is required",
+			signedStake, validatorSet.TotalStake())
+	}
+	return nil
+}
+
+func (m *RestartManifest) String() string {
+	return fmt.Sprintf("RestartManifest{chain_id: %v, height: %v, block_hash: %v, state_hash: %v, snapshot_hash: %v, reason: %q, signatures: %v}",
+		m.ChainID, m.Height, m.BlockHash.Hex(), m.StateHash.Hex(), m.SnapshotHash.Hex(), m.Reason, len(m.Signatures))
+}
+
+// ReadRestartManifest reads the manifest from the JSON file
+func ReadRestartManifest(filePath string) (*RestartManifest, error) {
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	m := &RestartManifest{}
+	if err := json.Unmarshal(raw, m); err != nil {
+		return nil, fmt.Errorf("Failed to parse the restart manifest %v: %v", filePath, err)
+	}
+	if m.BlockHash.IsEmpty() {
+		return nil, errors.New("The restart manifest does not specify a block")
+	}
+	return m, nil
+}
+
+// WriteRestartManifest writes the manifest to the JSON file
+func WriteRestartManifest(filePath string, m *RestartManifest) error {
+	raw, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, raw, 0644)
+}
+
+// HashSnapshotFile returns the hash of the snapshot file referenced by a restart manifest
+func HashSnapshotFile(filePath string) (common.Hash, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer file.Close()
+
+	hasher := sha3.NewKeccak256()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hasher.Sum(nil)), nil
+}
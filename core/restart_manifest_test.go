@@ -0,0 +1,76 @@
+package core
+
+import (
+	"math/big"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+)
+
+func TestRestartManifest(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	keys := []*crypto.PrivateKey{}
+	vs := NewValidatorSet()
+	for i := 0; i < 4; i++ {
+		priv, _, _ := crypto.GenerateKeyPair()
+		keys = append(keys, priv)
+		vs.AddValidator(NewValidator(priv.PublicKey().Address().Hex(), big.NewInt(100)))
+	}
+
+	m := &RestartManifest{
+		ChainID:   "test",
+		Height:    1001,
+		BlockHash: common.HexToHash("0x1001"),
+		StateHash: common.HexToHash("0x2002"),
+		Reason:    "halted",
+	}
+	sign := func(priv *crypto.PrivateKey) *crypto.Signature {
+		sig, err := priv.Sign(m.SignBytes())
+		require.Nil(err)
+		return sig
+	}
+
+	// 2 of 4 validators is not a quorum
+	require.Nil(m.AddSignature(keys[0].PublicKey().Address(), sign(keys[0])))
+	require.Nil(m.AddSignature(keys[1].PublicKey().Address(), sign(keys[1])))
+	assert.NotNil(m.Verify(vs))
+
+	// Signing again replaces the signature
+	require.Nil(m.AddSignature(keys[1].PublicKey().Address(), sign(keys[1])))
+	assert.Equal(2, len(m.Signatures))
+
+	// A signature of another validator is rejected
+	assert.NotNil(m.AddSignature(keys[2].PublicKey().Address(), sign(keys[3])))
+
+	require.Nil(m.AddSignature(keys[2].PublicKey().Address(), sign(keys[2])))
+	stake, err := m.SignedStake(vs)
+	require.Nil(err)
+	assert.Equal(big.NewInt(300), stake)
+	assert.Nil(m.Verify(vs))
+
+	// The signatures do not cover another block
+	tampered := *m
+	tampered.BlockHash = common.HexToHash("0x1002")
+	assert.NotNil(tampered.Verify(vs))
+
+	// Only the validators of the set count
+	outsider, _, _ := crypto.GenerateKeyPair()
+	m.Signatures = append(m.Signatures, &RestartManifestSignature{Validator: outsider.PublicKey().Address(), Signature: sign(outsider)})
+	assert.NotNil(m.Verify(vs))
+	m.Signatures = m.Signatures[:3]
+
+	// The manifest is preserved through the file
+	filePath := path.Join(t.TempDir(), "restart_manifest.json")
+	require.Nil(WriteRestartManifest(filePath, m))
+	m2, err := ReadRestartManifest(filePath)
+	require.Nil(err)
+	assert.Equal(m.Hash(), m2.Hash())
+	assert.Nil(m2.Verify(vs))
+}
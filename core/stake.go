@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto/bls"
 )
 
 const (
@@ -93,8 +94,9 @@ func (s *Stake) UnmarshalJSON(data []byte) error {
 
 // TODO: Should rename StakeHolder to StakeDelegate
 type StakeHolder struct {
-	Holder common.Address
-	Stakes []*Stake
+	Holder    common.Address
+	Stakes    []*Stake
+	BlsPubkey *bls.PublicKey `json:"-" rlp:"optional"` // Registered by the validators to aggregate their votes
 }
 
 func newStakeHolder(holder common.Address, stakes []*Stake) *StakeHolder {
@@ -137,18 +139,23 @@ func (sh *StakeHolder) depositStake(source common.Address, amount *big.Int) erro
 }
 
 func (sh *StakeHolder) withdrawStake(source common.Address, currentHeight uint64) error {
+	_, err := sh.withdrawStakeUntil(source, currentHeight+ReturnLockingPeriod)
+	return err
+}
+
+func (sh *StakeHolder) withdrawStakeUntil(source common.Address, returnHeight uint64) (*Stake, error) {
 	for _, stake := range sh.Stakes {
 		if stake.Source == source {
 			if stake.Withdrawn {
-				return fmt.Errorf("Already withdrawn, cannot withdraw again for source: %v", source)
+				return nil, fmt.Errorf("Already withdrawn, cannot withdraw again for source: %v", source)
 			}
 			stake.Withdrawn = true
-			stake.ReturnHeight = currentHeight + ReturnLockingPeriod
-			return nil
+			stake.ReturnHeight = returnHeight
+			return stake, nil
 		}
 	}
 
-	return fmt.Errorf("Cannot withdraw, no matched stake source address found: %v", source)
+	return nil, fmt.Errorf("Cannot withdraw, no matched stake source address found: %v", source)
 }
 
 func (sh *StakeHolder) returnStake(source common.Address, currentHeight uint64) (*Stake, error) {
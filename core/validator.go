@@ -11,6 +11,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto/bls"
 )
 
 var logger *log.Entry = log.WithFields(log.Fields{"prefix": "core"})
@@ -22,14 +23,15 @@ var (
 
 // Validator contains the public information of a validator.
 type Validator struct {
-	Address common.Address
-	Stake   *big.Int
+	Address   common.Address
+	Stake     *big.Int
+	BlsPubkey *bls.PublicKey `json:"-"` // the key the validator signs the aggregatable votes with, nil if not registered
 }
 
 // NewValidator creates a new validator instance.
 func NewValidator(addressStr string, stake *big.Int) Validator {
 	address := common.HexToAddress(addressStr)
-	return Validator{address, stake}
+	return Validator{Address: address, Stake: stake}
 }
 
 // ID returns the ID of the validator, which is the string representation of its address.
@@ -145,13 +147,18 @@ func (s *ValidatorSet) HasMajorityVotes(votes []Vote) bool {
 		}
 	}
 
+	return hasMajorityStake(votedStake, s.TotalStake())
+}
+
+// hasMajorityStake returns whether the stake is more than 2/3 of the total stake
+func hasMajorityStake(stake *big.Int, totalStake *big.Int) bool {
 	three := new(big.Int).SetUint64(3)
 	two := new(big.Int).SetUint64(2)
 	lhs := new(big.Int)
 	rhs := new(big.Int)
 
-	//return votedStake*3 > s.TotalStake()*2
-	return lhs.Mul(votedStake, three).Cmp(rhs.Mul(s.TotalStake(), two)) > 0
+	//return stake*3 > totalStake*2
+	return lhs.Mul(stake, three).Cmp(rhs.Mul(totalStake, two)) > 0
 }
 
 // HasMajority checks whether a vote set has reach majority.
@@ -164,17 +171,31 @@ func (s *ValidatorSet) Validators() []Validator {
 	return s.validators
 }
 
+// Index returns the position of the validator in the set, or -1 if not found.
+func (s *ValidatorSet) Index(id common.Address) int {
+	for i, v := range s.validators {
+		if v.ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
 //
 // ------- ValidatorCandidatePool ------- //
 //
 
 var (
 	MinValidatorStakeDeposit *big.Int
+	MinDelegationDeposit     *big.Int
 )
 
 func init() {
 	// Each stake deposit needs to be at least 2,000,000 Pando
 	MinValidatorStakeDeposit = new(big.Int).Mul(new(big.Int).SetUint64(1000000), new(big.Int).SetUint64(1000000000000000000))
+
+	// Each delegation needs to be at least 1,000 Pando
+	MinDelegationDeposit = new(big.Int).Mul(new(big.Int).SetUint64(1000), new(big.Int).SetUint64(1000000000000000000))
 }
 
 type ValidatorCandidatePool struct {
@@ -225,26 +246,83 @@ func (vcp *ValidatorCandidatePool) DepositStake(source common.Address, holder co
 	return nil
 }
 
+// Delegate deposits the stake of a delegator to an existing validator candidate. A delegation
+// cannot create a candidate, hence its minimum amount is lower than the one of a stake deposit.
+func (vcp *ValidatorCandidatePool) Delegate(source common.Address, holder common.Address, amount *big.Int) error {
+	if amount.Cmp(MinDelegationDeposit) < 0 {
+		return fmt.Errorf("Insufficient delegation: %v", amount)
+	}
+
+	candidate := vcp.FindStakeDelegate(holder)
+	if candidate == nil {
+		return fmt.Errorf("No matched stake holder address found: %v", holder)
+	}
+	if err := candidate.depositStake(source, amount); err != nil {
+		return err
+	}
+
+	vcp.sortCandidates()
+
+	return nil
+}
+
+// SetBlsPubkey registers the BLS key the stake holder signs the aggregatable votes with.
+func (vcp *ValidatorCandidatePool) SetBlsPubkey(holder common.Address, pubkey *bls.PublicKey) error {
+	candidate := vcp.FindStakeDelegate(holder)
+	if candidate == nil {
+		return fmt.Errorf("No matched stake holder address found: %v", holder)
+	}
+	candidate.BlsPubkey = pubkey
+	return nil
+}
+
 func (vcp *ValidatorCandidatePool) WithdrawStake(source common.Address, holder common.Address, currentHeight uint64) error {
-	matchedHolderFound := false
+	_, err := vcp.WithdrawStakeUntil(source, holder, currentHeight+ReturnLockingPeriod)
+	return err
+}
+
+// WithdrawStakeUntil withdraws the stake of the source from the holder, and locks it until the
+// return height. It returns the withdrawn stake.
+func (vcp *ValidatorCandidatePool) WithdrawStakeUntil(source common.Address, holder common.Address, returnHeight uint64) (*Stake, error) {
+	var withdrawn *Stake
 	for _, candidate := range vcp.SortedCandidates {
 		if candidate.Holder == holder {
-			matchedHolderFound = true
-			err := candidate.withdrawStake(source, currentHeight)
+			stake, err := candidate.withdrawStakeUntil(source, returnHeight)
 			if err != nil {
-				return err
+				return nil, err
 			}
+			withdrawn = stake
 			break
 		}
 	}
 
-	if !matchedHolderFound {
-		return fmt.Errorf("No matched stake holder address found: %v", holder)
+	if withdrawn == nil {
+		return nil, fmt.Errorf("No matched stake holder address found: %v", holder)
 	}
 
 	vcp.sortCandidates()
 
-	return nil
+	return withdrawn, nil
+}
+
+// ReturnStake removes the withdrawn stake of the source from the holder once its return height
+// is reached, and returns it.
+func (vcp *ValidatorCandidatePool) ReturnStake(source common.Address, holder common.Address, currentHeight uint64) (*Stake, error) {
+	for cidx, candidate := range vcp.SortedCandidates {
+		if candidate.Holder != holder {
+			continue
+		}
+		stake, err := candidate.returnStake(source, currentHeight)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidate.Stakes) == 0 {
+			vcp.SortedCandidates = append(vcp.SortedCandidates[:cidx], vcp.SortedCandidates[cidx+1:]...)
+		}
+		vcp.sortCandidates()
+		return stake, nil
+	}
+	return nil, fmt.Errorf("No matched stake holder address found: %v", holder)
 }
 
 func (vcp *ValidatorCandidatePool) ReturnStakes(currentHeight uint64) []*Stake {
@@ -279,6 +357,32 @@ func (vcp *ValidatorCandidatePool) ReturnStakes(currentHeight uint64) []*Stake {
 	return returnedStakes
 }
 
+// SlashStakes burns the given percentage of each stake deposited to the holder, including the
+// withdrawn stakes not returned yet. It returns the burnt amount of each stake.
+func (vcp *ValidatorCandidatePool) SlashStakes(holder common.Address, percentage int64) ([]*Stake, error) {
+	candidate := vcp.FindStakeDelegate(holder)
+	if candidate == nil {
+		return nil, fmt.Errorf("No matched stake holder address found: %v", holder)
+	}
+
+	slashed := []*Stake{}
+	for _, stake := range candidate.Stakes {
+		amount := new(big.Int).Mul(stake.Amount, big.NewInt(percentage))
+		amount.Div(amount, big.NewInt(100))
+		stake.Amount = new(big.Int).Sub(stake.Amount, amount)
+		slashed = append(slashed, &Stake{
+			Source:       stake.Source,
+			Amount:       amount,
+			Withdrawn:    stake.Withdrawn,
+			ReturnHeight: stake.ReturnHeight,
+		})
+	}
+
+	vcp.sortCandidates()
+
+	return slashed, nil
+}
+
 func (vcp *ValidatorCandidatePool) sortCandidates() {
 	sort.Slice(vcp.SortedCandidates[:], func(i, j int) bool { // descending order in (totalStake, holderAddress)
 		stakeCmp := vcp.SortedCandidates[i].TotalStake().Cmp(vcp.SortedCandidates[j].TotalStake())
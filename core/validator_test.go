@@ -358,6 +358,29 @@ func TestValidatorSetUniqueSortedOrder(t *testing.T) {
 	assert.Equal(vcpJson3, vcpJson4)
 }
 
+func TestValidatorCandidatePoolDelegate(t *testing.T) {
+	assert := assert.New(t)
+
+	validatorAddr := common.HexToAddress("0xf01")
+	delegatorAddr := common.HexToAddress("0x222")
+	validatorStake := new(big.Int).Mul(new(big.Int).SetUint64(10), MinValidatorStakeDeposit)
+
+	vcp := &ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(validatorAddr, validatorAddr, validatorStake))
+
+	// Below the minimum delegation
+	assert.NotNil(vcp.Delegate(delegatorAddr, validatorAddr, new(big.Int).SetInt64(1000)))
+
+	// Delegating to an address which is not a candidate
+	assert.NotNil(vcp.Delegate(delegatorAddr, common.HexToAddress("0xf02"), MinDelegationDeposit))
+
+	assert.Nil(vcp.Delegate(delegatorAddr, validatorAddr, MinDelegationDeposit))
+	candidate := vcp.FindStakeDelegate(validatorAddr)
+	assert.NotNil(candidate)
+	assert.Equal(2, len(candidate.Stakes))
+	assert.Equal(new(big.Int).Add(validatorStake, MinDelegationDeposit), candidate.TotalStake())
+}
+
 // ------------------------- Utilities -------------------------
 
 func checkAndPrintAllSortedCandidates(t *testing.T, assert *assert.Assertions, vcp *ValidatorCandidatePool) {
@@ -7,11 +7,13 @@ import (
 	"io"
 	"sort"
 
-	log "github.com/sirupsen/logrus"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/bls"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/rlp"
+	log "github.com/sirupsen/logrus"
 )
 
 // Proposal represents a proposal of a new block.
@@ -27,8 +29,9 @@ func (p Proposal) String() string {
 
 // CommitCertificate represents a commit made a majority of validators.
 type CommitCertificate struct {
-	Votes     *VoteSet `rlp:"nil"`
-	BlockHash common.Hash
+	Votes      *VoteSet `rlp:"nil"`
+	BlockHash  common.Hash
+	Aggregated *AggregatedCommit `rlp:"optional"` // Votes aggregated into a single BLS signature, in place of the individual votes
 }
 
 // Copy creates a copy of this commit certificate.
@@ -39,34 +42,217 @@ func (cc CommitCertificate) Copy() CommitCertificate {
 	if cc.Votes != nil {
 		ret.Votes = cc.Votes.Copy()
 	}
+	if cc.Aggregated != nil {
+		ret.Aggregated = cc.Aggregated.Copy()
+	}
 	return ret
 }
 
 func (cc CommitCertificate) String() string {
+	if cc.Aggregated != nil {
+		return fmt.Sprintf("CC{BlockHash: %v, Votes: %v, Aggregated: %v}", cc.BlockHash.Hex(), cc.Votes, cc.Aggregated)
+	}
 	return fmt.Sprintf("CC{BlockHash: %v, Votes: %v}", cc.BlockHash.Hex(), cc.Votes)
 }
 
 // IsValid checks if a CommitCertificate is valid.
 func (cc CommitCertificate) IsValid(validators *ValidatorSet) bool {
-	if cc.Votes == nil || cc.Votes.IsEmpty() {
+	voters := []Vote{}
+	aggregated := make(map[common.Address]bool)
+	if cc.Aggregated != nil {
+		if cc.Aggregated.Validate(cc.BlockHash, validators).IsError() {
+			return false
+		}
+		for _, validator := range cc.Aggregated.Voters(validators) {
+			aggregated[validator.ID()] = true
+			voters = append(voters, Vote{Block: cc.BlockHash, ID: validator.ID()})
+		}
+	} else if cc.Votes == nil || cc.Votes.IsEmpty() {
 		return false
 	}
-	filtered := cc.Votes.UniqueVoter()
-	if filtered.Size() != cc.Votes.Size() {
+
+	if cc.Votes != nil {
+		filtered := cc.Votes.UniqueVoter()
+		if filtered.Size() != cc.Votes.Size() {
+			return false
+		}
+		for _, vote := range filtered.Votes() {
+			if vote.Block != cc.BlockHash {
+				return false
+			}
+			if aggregated[vote.ID] {
+				return false
+			}
+			if vote.ValidateWithValidators(validators).IsError() {
+				return false
+			}
+		}
+		voters = append(voters, filtered.Votes()...)
+	}
+	if len(voters) > validators.Size() {
 		return false
 	}
-	if filtered.Size() > validators.Size() {
+	return validators.HasMajorityVotes(voters)
+}
+
+// Aggregate moves the votes carrying a valid BLS signature of a validator with a registered BLS
+// key into the aggregated signature. The other votes are kept as they are.
+func (cc *CommitCertificate) Aggregate(validators *ValidatorSet) {
+	if cc.Votes == nil {
+		return
+	}
+	aggregated := cc.Aggregated
+	if aggregated == nil {
+		aggregated = NewAggregatedCommit(validators.Size())
+	}
+	remaining := NewVoteSet()
+	for _, vote := range cc.Votes.Votes() {
+		idx := validators.Index(vote.ID)
+		if idx < 0 || vote.Block != cc.BlockHash || aggregated.IsSigner(idx) {
+			remaining.AddVote(vote)
+			continue
+		}
+		pubkey := validators.Validators()[idx].BlsPubkey
+		if pubkey.IsEmpty() || !vote.ValidateBlsSignature(pubkey) {
+			remaining.AddVote(vote)
+			continue
+		}
+		aggregated.Add(idx, vote.BlsSignature)
+	}
+	if aggregated.Abs() == 0 {
+		return
+	}
+	cc.Votes = remaining
+	cc.Aggregated = aggregated
+}
+
+// AggregatedCommit is the BLS aggregate of the votes of validators on the block of a commit
+// certificate. Since the validators sign the same message, it is verified with a single pairing
+// against the aggregate of their public keys.
+type AggregatedCommit struct {
+	Signers   common.Bytes   // Bitmap of the signers, indexed by their positions in the validator set
+	Signature *bls.Signature // Aggregated signature
+}
+
+// NewAggregatedCommit creates an empty aggregation for a validator set of the given size.
+func NewAggregatedCommit(numValidators int) *AggregatedCommit {
+	return &AggregatedCommit{
+		Signers:   make(common.Bytes, (numValidators+7)/8),
+		Signature: bls.NewAggregateSignature(),
+	}
+}
+
+func (a *AggregatedCommit) String() string {
+	return fmt.Sprintf("AggregatedCommit{Signers: %x, Abs: %v}", a.Signers, a.Abs())
+}
+
+// IsSigner returns whether the validator at the given position has signed.
+func (a *AggregatedCommit) IsSigner(idx int) bool {
+	if idx < 0 || idx/8 >= len(a.Signers) {
 		return false
 	}
-	for _, vote := range filtered.Votes() {
-		if vote.Block != cc.BlockHash {
-			return false
+	return a.Signers[idx/8]&(1<<uint(idx%8)) != 0
+}
+
+// Add adds the signature of the validator at the given position.
+func (a *AggregatedCommit) Add(idx int, sig *bls.Signature) {
+	a.Signers[idx/8] |= 1 << uint(idx%8)
+	a.Signature.Aggregate(sig)
+}
+
+// Abs returns the number of signers.
+func (a *AggregatedCommit) Abs() int {
+	ret := 0
+	for i := 0; i < len(a.Signers)*8; i++ {
+		if a.IsSigner(i) {
+			ret++
 		}
-		if vote.Validate().IsError() {
-			return false
+	}
+	return ret
+}
+
+// Voters returns the validators who have signed.
+func (a *AggregatedCommit) Voters(validators *ValidatorSet) []Validator {
+	ret := []Validator{}
+	for i, validator := range validators.Validators() {
+		if a.IsSigner(i) {
+			ret = append(ret, validator)
 		}
 	}
-	return validators.HasMajority(filtered)
+	return ret
+}
+
+// Validate verifies the aggregated signature of the signers on the block.
+func (a *AggregatedCommit) Validate(block common.Hash, validators *ValidatorSet) result.Result {
+	if len(a.Signers) != (validators.Size()+7)/8 {
+		return result.Error("signers size %d does not match the validator set size %d", len(a.Signers), validators.Size())
+	}
+	for i := validators.Size(); i < len(a.Signers)*8; i++ {
+		if a.IsSigner(i) {
+			return result.Error("signer %d is out of the validator set", i)
+		}
+	}
+	if a.Signature.IsEmpty() {
+		return result.Error("signature cannot be nil")
+	}
+	signers := a.Voters(validators)
+	if len(signers) == 0 {
+		return result.Error("no signer")
+	}
+	pubkeys := make([]*bls.PublicKey, 0, len(signers))
+	for _, validator := range signers {
+		if validator.BlsPubkey.IsEmpty() {
+			return result.Error("validator %v has no BLS key", validator.ID())
+		}
+		pubkeys = append(pubkeys, validator.BlsPubkey)
+	}
+	if !a.Signature.Verify(BlsVoteSignBytes(block), bls.AggregatePublicKeys(pubkeys)) {
+		return result.Error("signature verification failed")
+	}
+	return result.OK
+}
+
+// Copy clones the aggregated commit.
+func (a *AggregatedCommit) Copy() *AggregatedCommit {
+	clone := &AggregatedCommit{}
+	if a.Signers != nil {
+		clone.Signers = make(common.Bytes, len(a.Signers))
+		copy(clone.Signers, a.Signers)
+	}
+	if a.Signature != nil {
+		clone.Signature = a.Signature.Copy()
+	}
+	return clone
+}
+
+// BlsVoteSignBytes returns the bytes the validators sign with their BLS keys to vote for the block.
+// Unlike the vote sign bytes, they do not depend on the voter or the epoch, so that the signatures
+// of the votes on the same block can be aggregated.
+func BlsVoteSignBytes(block common.Hash) common.Bytes {
+	raw, _ := rlp.EncodeToBytes([]interface{}{"PandoValidatorVote", block})
+	return raw
+}
+
+// HeaderCommit is a block header together with the commit certificate of the validators on it.
+// It is the compact finality proof forwarded by the bridge relayers to the other chains.
+type HeaderCommit struct {
+	Header *BlockHeader
+	CC     CommitCertificate
+}
+
+// Validate checks that the commit certificate is on the header, and carries the votes of a
+// majority of the validators.
+func (hc *HeaderCommit) Validate(validators *ValidatorSet) result.Result {
+	if hc.Header == nil {
+		return result.Error("header cannot be nil")
+	}
+	if hc.CC.BlockHash != hc.Header.Hash() {
+		return result.Error("commit certificate is on block %v instead of %v", hc.CC.BlockHash.Hex(), hc.Header.Hash().Hex())
+	}
+	if !hc.CC.IsValid(validators) {
+		return result.Error("invalid commit certificate")
+	}
+	return result.OK
 }
 
 // Vote represents a vote on a block by a validaor.
@@ -76,6 +262,8 @@ type Vote struct {
 	Epoch     uint64         // Voter's current epoch. It doesn't need to equal the epoch in the block above.
 	ID        common.Address // Voter's address.
 	Signature *crypto.Signature
+
+	BlsSignature *bls.Signature `rlp:"optional"` // Signature over BlsVoteSignBytes, for the aggregation of the commit certificates
 }
 
 func (v Vote) String() string {
@@ -93,9 +281,9 @@ func (v Vote) SignBytes() common.Bytes {
 	return raw
 }
 
-// Sign signs the vote using given private key.
-func (v *Vote) Sign(priv *crypto.PrivateKey) {
-	sig, err := priv.Sign(v.SignBytes())
+// Sign signs the vote using given signer.
+func (v *Vote) Sign(signer crypto.Signer) {
+	sig, err := signer.Sign(v.SignBytes())
 	if err != nil {
 		// Should not happen.
 		logger.WithFields(log.Fields{"error": err}).Panic("Failed to sign vote")
@@ -108,6 +296,19 @@ func (v *Vote) SetSignature(sig *crypto.Signature) {
 	v.Signature = sig
 }
 
+// SignBls adds the BLS signature of the voter, which can be aggregated with the ones of the other voters.
+func (v *Vote) SignBls(key *bls.SecretKey) {
+	v.BlsSignature = key.Sign(BlsVoteSignBytes(v.Block))
+}
+
+// ValidateBlsSignature checks the BLS signature of the vote against the BLS key of the voter.
+func (v Vote) ValidateBlsSignature(pubkey *bls.PublicKey) bool {
+	if v.BlsSignature.IsEmpty() || pubkey.IsEmpty() {
+		return false
+	}
+	return v.BlsSignature.Verify(BlsVoteSignBytes(v.Block), pubkey)
+}
+
 // Validate checks the vote is legitimate.
 func (v Vote) Validate() result.Result {
 	if v.Block.IsEmpty() {
@@ -125,6 +326,37 @@ func (v Vote) Validate() result.Result {
 	return result.OK
 }
 
+// IsBlsOnly returns whether the vote is only signed with the BLS key of the voter, e.g. by a
+// threshold key split across multiple machines, which do not hold the account key of the validator.
+func (v Vote) IsBlsOnly() bool {
+	return (v.Signature == nil || v.Signature.IsEmpty()) && !v.BlsSignature.IsEmpty()
+}
+
+// ValidateWithValidators checks the vote is legitimate. Unlike Validate, it also accepts the votes
+// only signed with the BLS key the voter registered.
+func (v Vote) ValidateWithValidators(validators *ValidatorSet) result.Result {
+	if !v.IsBlsOnly() {
+		return v.Validate()
+	}
+	if v.Block.IsEmpty() {
+		return result.Error("Block is not specified")
+	}
+	if v.ID.IsEmpty() {
+		return result.Error("Voter is not specified")
+	}
+	if !features.IsEnabled(features.ThresholdVotes, v.Height) {
+		return result.Error("Vote is not signed")
+	}
+	validator, err := validators.GetValidator(v.ID)
+	if err != nil {
+		return result.Error("Voter is not a validator")
+	}
+	if !v.ValidateBlsSignature(validator.BlsPubkey) {
+		return result.Error("BLS signature verification failed")
+	}
+	return result.OK
+}
+
 // Hash calculates vote's hash.
 func (v Vote) Hash() common.Hash {
 	raw, _ := rlp.EncodeToBytes(v)
@@ -160,6 +392,10 @@ func (s *VoteSet) AddVote(vote Vote) {
 
 // Size returns the number of votes in the vote set.
 func (s *VoteSet) Size() int {
+	if s == nil {
+		// The votes of a commit certificate decode to nil if they have all been aggregated
+		return 0
+	}
 	return len(s.votes)
 }
 
@@ -170,6 +406,9 @@ func (s *VoteSet) IsEmpty() bool {
 
 // Votes return a slice of votes in the vote set.
 func (s *VoteSet) Votes() []Vote {
+	if s == nil {
+		return []Vote{}
+	}
 	ret := make([]Vote, 0, len(s.votes))
 	for _, v := range s.votes {
 		ret = append(ret, v)
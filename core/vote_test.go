@@ -6,8 +6,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/bls"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/rlp"
 )
 
@@ -259,3 +262,219 @@ func TestCommitCertificate(t *testing.T) {
 	cc = CommitCertificate{Votes: invalidVoteSet, BlockHash: blockHash}
 	assert.False(cc.IsValid(vs))
 }
+
+func TestAggregatedCommitCertificate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ten18 := new(big.Int).SetUint64(1e18) // 10^18
+	stakes := []int64{100000001, 100000000, 50000000, 50000000}
+
+	vs := NewValidatorSet()
+	privKeys := make(map[common.Address]*crypto.PrivateKey)
+	blsKeys := make(map[common.Address]*bls.SecretKey)
+	for i, stake := range stakes {
+		priv, _, _ := crypto.GenerateKeyPair()
+		va := NewValidator(priv.PublicKey().Address().Hex(), new(big.Int).Mul(big.NewInt(stake), ten18))
+		// The last validator has not registered a BLS key
+		if i < len(stakes)-1 {
+			blsKey, err := bls.DeriveValidatorKey(priv)
+			require.Nil(err)
+			va.BlsPubkey = blsKey.PublicKey()
+			blsKeys[va.Address] = blsKey
+		}
+		privKeys[va.Address] = priv
+		vs.AddValidator(va)
+	}
+
+	blockHash := common.HexToHash("a1")
+	newVote := func(addr common.Address, signBls bool) Vote {
+		vote := Vote{ID: addr, Block: blockHash, Height: 1}
+		vote.Sign(privKeys[addr])
+		if signBls && blsKeys[addr] != nil {
+			vote.SignBls(blsKeys[addr])
+		}
+		return vote
+	}
+
+	votes := NewVoteSet()
+	for _, va := range vs.Validators() {
+		votes.AddVote(newVote(va.Address, true))
+	}
+	cc := CommitCertificate{Votes: votes, BlockHash: blockHash}
+	cc.Aggregate(vs)
+	require.NotNil(cc.Aggregated)
+	assert.Equal(3, cc.Aggregated.Abs())
+	assert.Equal(1, cc.Votes.Size())
+	assert.True(cc.IsValid(vs))
+
+	// The certificate survives the encoding, and the votes decode to nil if they were all aggregated
+	for _, withIndividualVote := range []bool{true, false} {
+		encoded := cc.Copy()
+		if !withIndividualVote {
+			encoded.Votes = NewVoteSet()
+			encoded.Aggregated = NewAggregatedCommit(vs.Size())
+			for i, va := range vs.Validators() {
+				if blsKeys[va.Address] != nil {
+					encoded.Aggregated.Add(i, blsKeys[va.Address].Sign(BlsVoteSignBytes(blockHash)))
+				}
+			}
+		}
+		raw, err := rlp.EncodeToBytes(encoded)
+		require.Nil(err)
+		decoded := CommitCertificate{}
+		require.Nil(rlp.DecodeBytes(raw, &decoded))
+		require.NotNil(decoded.Aggregated)
+		assert.Equal(encoded.Votes.Size(), decoded.Votes.Size())
+		assert.True(decoded.IsValid(vs))
+	}
+
+	// The encoding of the certificates without aggregated votes is unchanged
+	legacy := CommitCertificate{Votes: votes, BlockHash: blockHash}
+	raw, err := rlp.EncodeToBytes(legacy)
+	require.Nil(err)
+	expected, err := rlp.EncodeToBytes([]interface{}{votes, blockHash})
+	require.Nil(err)
+	assert.Equal(expected, raw)
+
+	// Reject a voter both aggregated and voting individually
+	duplicated := cc.Copy()
+	for _, vote := range votes.Votes() {
+		if cc.Aggregated.IsSigner(vs.Index(vote.ID)) {
+			duplicated.Votes.AddVote(vote)
+			break
+		}
+	}
+	assert.False(duplicated.IsValid(vs))
+
+	// Reject a signer without a BLS key
+	forged := cc.Copy()
+	for i, va := range vs.Validators() {
+		if va.BlsPubkey == nil {
+			forged.Aggregated.Signers[i/8] |= 1 << uint(i%8)
+		}
+	}
+	assert.False(forged.IsValid(vs))
+
+	// Reject a signature over another block
+	tampered := cc.Copy()
+	tampered.BlockHash = common.HexToHash("a2")
+	assert.False(tampered.IsValid(vs))
+
+	// The votes without a valid BLS signature are not aggregated
+	votes = NewVoteSet()
+	for _, va := range vs.Validators() {
+		vote := newVote(va.Address, false)
+		if blsKeys[va.Address] != nil {
+			vote.BlsSignature = blsKeys[va.Address].Sign(BlsVoteSignBytes(common.HexToHash("a2")))
+		}
+		votes.AddVote(vote)
+	}
+	cc = CommitCertificate{Votes: votes, BlockHash: blockHash}
+	cc.Aggregate(vs)
+	assert.Nil(cc.Aggregated)
+	assert.Equal(4, cc.Votes.Size())
+	assert.True(cc.IsValid(vs))
+}
+
+func TestThresholdSignedVote(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.Nil(features.Configure(map[string]uint64{features.ThresholdVotes: 0}))
+	defer features.Configure(nil)
+
+	ten18 := new(big.Int).SetUint64(1e18) // 10^18
+	priv1, _, _ := crypto.GenerateKeyPair()
+	priv2, _, _ := crypto.GenerateKeyPair()
+	va1 := NewValidator(priv1.PublicKey().Address().Hex(), new(big.Int).Mul(big.NewInt(300000000), ten18))
+	va2 := NewValidator(priv2.PublicKey().Address().Hex(), new(big.Int).Mul(big.NewInt(100000000), ten18))
+
+	// The BLS key of the first validator is split across three machines, any two of which can sign
+	groupKey, err := bls.RandKey()
+	require.Nil(err)
+	shares, tpk, err := bls.SplitKey(groupKey, 2, 3)
+	require.Nil(err)
+	va1.BlsPubkey = tpk.GroupKey()
+	vs := NewValidatorSet()
+	vs.AddValidator(va1)
+	vs.AddValidator(va2)
+
+	blockHash := common.HexToHash("a1")
+	msg := BlsVoteSignBytes(blockHash)
+	sig, err := bls.RecoverSignature([]*bls.PartialSignature{shares[0].Sign(msg), shares[2].Sign(msg)}, tpk.Threshold)
+	require.Nil(err)
+	vote := Vote{ID: va1.Address, Block: blockHash, Height: 1, BlsSignature: sig}
+	assert.True(vote.IsBlsOnly())
+	assert.True(vote.Validate().IsError())
+	assert.True(vote.ValidateWithValidators(vs).IsOK())
+
+	// The vote survives the encoding without the account key signature
+	raw, err := rlp.EncodeToBytes(vote)
+	require.Nil(err)
+	decoded := Vote{}
+	require.Nil(rlp.DecodeBytes(raw, &decoded))
+	assert.True(decoded.IsBlsOnly())
+	assert.True(decoded.ValidateWithValidators(vs).IsOK())
+
+	// A single share cannot sign for the validator, and a validator without a BLS key cannot cast
+	// BLS only votes
+	forged := Vote{ID: va1.Address, Block: blockHash, Height: 1, BlsSignature: shares[1].Sign(msg).Signature}
+	assert.True(forged.ValidateWithValidators(vs).IsError())
+	forged = Vote{ID: va2.Address, Block: blockHash, Height: 1, BlsSignature: sig}
+	assert.True(forged.ValidateWithValidators(vs).IsError())
+
+	// The threshold signed vote is a majority on its own, and is aggregated into the certificate
+	votes := NewVoteSet()
+	votes.AddVote(vote)
+	cc := CommitCertificate{Votes: votes, BlockHash: blockHash}
+	assert.True(cc.IsValid(vs))
+	cc.Aggregate(vs)
+	require.NotNil(cc.Aggregated)
+	assert.Equal(1, cc.Aggregated.Abs())
+	assert.True(cc.IsValid(vs))
+}
+
+func TestHeaderCommit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ten18 := new(big.Int).SetUint64(1e18) // 10^18
+	vs := NewValidatorSet()
+	privKeys := []*crypto.PrivateKey{}
+	for i := 0; i < 4; i++ {
+		priv, _, _ := crypto.GenerateKeyPair()
+		va := NewValidator(priv.PublicKey().Address().Hex(), new(big.Int).Mul(big.NewInt(100000000), ten18))
+		blsKey, err := bls.DeriveValidatorKey(priv)
+		require.Nil(err)
+		va.BlsPubkey = blsKey.PublicKey()
+		vs.AddValidator(va)
+		privKeys = append(privKeys, priv)
+	}
+
+	header := &BlockHeader{ChainID: "test", Height: 10, Epoch: 12, Timestamp: big.NewInt(100)}
+	votes := NewVoteSet()
+	for _, priv := range privKeys[:3] {
+		vote := Vote{ID: priv.PublicKey().Address(), Block: header.Hash(), Height: header.Height}
+		vote.Sign(priv)
+		blsKey, _ := bls.DeriveValidatorKey(priv)
+		vote.SignBls(blsKey)
+		votes.AddVote(vote)
+	}
+	hc := &HeaderCommit{Header: header, CC: CommitCertificate{Votes: votes, BlockHash: header.Hash()}}
+	hc.CC.Aggregate(vs)
+	assert.True(hc.Validate(vs).IsOK())
+
+	// The proof survives the encoding
+	raw, err := rlp.EncodeToBytes(hc)
+	require.Nil(err)
+	decoded := &HeaderCommit{}
+	require.Nil(rlp.DecodeBytes(raw, decoded))
+	assert.Equal(header.Hash(), decoded.Header.Hash())
+	assert.True(decoded.Validate(vs).IsOK())
+
+	// Reject a certificate on another header
+	forged := &HeaderCommit{}
+	require.Nil(rlp.DecodeBytes(raw, forged))
+	forged.Header.Height = 11
+	assert.True(forged.Validate(vs).IsError())
+}
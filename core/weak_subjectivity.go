@@ -0,0 +1,105 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// WeakSubjectivityCheckpoint is a finalized block obtained out of band, e.g. from trusted peers or
+// block explorers. A node syncing the chain rejects the branches which conflict with its checkpoints,
+// which protects it against the long-range attacks with the keys of the past validators.
+type WeakSubjectivityCheckpoint struct {
+	Height uint64
+	Hash   common.Hash
+}
+
+// ParseWeakSubjectivityCheckpoint parses a checkpoint in the "height:hash" format
+func ParseWeakSubjectivityCheckpoint(s string) (*WeakSubjectivityCheckpoint, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid checkpoint %q, expected height:hash", s)
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid height of checkpoint %q: %v", s, err)
+	}
+	hashBytes := common.FromHex(parts[1])
+	if len(hashBytes) != common.HashLength {
+		return nil, fmt.Errorf("Invalid hash of checkpoint %q", s)
+	}
+	return &WeakSubjectivityCheckpoint{
+		Height: height,
+		Hash:   common.BytesToHash(hashBytes),
+	}, nil
+}
+
+func (c *WeakSubjectivityCheckpoint) String() string {
+	return fmt.Sprintf("%v:%v", c.Height, c.Hash.Hex())
+}
+
+// WeakSubjectivityCheckpoints is a set of checkpoints sorted by height
+type WeakSubjectivityCheckpoints struct {
+	checkpoints []*WeakSubjectivityCheckpoint
+}
+
+// NewWeakSubjectivityCheckpoints parses the checkpoints in the "height:hash" format. Two checkpoints
+// at the same height need to agree on the block.
+func NewWeakSubjectivityCheckpoints(list []string) (*WeakSubjectivityCheckpoints, error) {
+	byHeight := make(map[uint64]*WeakSubjectivityCheckpoint)
+	for _, s := range list {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		checkpoint, err := ParseWeakSubjectivityCheckpoint(s)
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := byHeight[checkpoint.Height]; ok && existing.Hash != checkpoint.Hash {
+			return nil, fmt.Errorf("Conflicting checkpoints %v and %v", existing, checkpoint)
+		}
+		byHeight[checkpoint.Height] = checkpoint
+	}
+
+	checkpoints := &WeakSubjectivityCheckpoints{}
+	for _, checkpoint := range byHeight {
+		checkpoints.checkpoints = append(checkpoints.checkpoints, checkpoint)
+	}
+	sort.Slice(checkpoints.checkpoints, func(i, j int) bool {
+		return checkpoints.checkpoints[i].Height < checkpoints.checkpoints[j].Height
+	})
+	return checkpoints, nil
+}
+
+// IsEmpty returns true if no checkpoint is configured
+func (wsc *WeakSubjectivityCheckpoints) IsEmpty() bool {
+	return len(wsc.checkpoints) == 0
+}
+
+// List returns the checkpoints sorted by height
+func (wsc *WeakSubjectivityCheckpoints) List() []*WeakSubjectivityCheckpoint {
+	return wsc.checkpoints
+}
+
+// Latest returns the checkpoint with the highest height, or nil if no checkpoint is configured
+func (wsc *WeakSubjectivityCheckpoints) Latest() *WeakSubjectivityCheckpoint {
+	if wsc.IsEmpty() {
+		return nil
+	}
+	return wsc.checkpoints[len(wsc.checkpoints)-1]
+}
+
+// Check returns an error if the block conflicts with the checkpoint at its height
+func (wsc *WeakSubjectivityCheckpoints) Check(height uint64, hash common.Hash) error {
+	idx := sort.Search(len(wsc.checkpoints), func(i int) bool {
+		return wsc.checkpoints[i].Height >= height
+	})
+	if idx < len(wsc.checkpoints) && wsc.checkpoints[idx].Height == height && wsc.checkpoints[idx].Hash != hash {
+		return fmt.Errorf("Block %v at height %v conflicts with the weak subjectivity checkpoint %v",
+			hash.Hex(), height, wsc.checkpoints[idx])
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeakSubjectivityCheckpoints(t *testing.T) {
+	assert := assert.New(t)
+
+	hash1 := common.BytesToHash([]byte("block1"))
+	hash2 := common.BytesToHash([]byte("block2"))
+
+	_, err := ParseWeakSubjectivityCheckpoint("1001")
+	assert.NotNil(err)
+	_, err = ParseWeakSubjectivityCheckpoint("abc:" + hash1.Hex())
+	assert.NotNil(err)
+	_, err = ParseWeakSubjectivityCheckpoint("1001:0x1234")
+	assert.NotNil(err)
+
+	checkpoint, err := ParseWeakSubjectivityCheckpoint(" 1001:" + hash1.Hex())
+	assert.Nil(err)
+	assert.Equal(uint64(1001), checkpoint.Height)
+	assert.Equal(hash1, checkpoint.Hash)
+
+	// Conflicting checkpoints at the same height
+	_, err = NewWeakSubjectivityCheckpoints([]string{"1001:" + hash1.Hex(), "1001:" + hash2.Hex()})
+	assert.NotNil(err)
+
+	checkpoints, err := NewWeakSubjectivityCheckpoints([]string{})
+	assert.Nil(err)
+	assert.True(checkpoints.IsEmpty())
+	assert.Nil(checkpoints.Latest())
+	assert.Nil(checkpoints.Check(1001, hash2))
+
+	checkpoints, err = NewWeakSubjectivityCheckpoints([]string{"2001:" + hash2.Hex(), "", "1001:" + hash1.Hex(), "1001:" + hash1.Hex()})
+	assert.Nil(err)
+	assert.Equal(2, len(checkpoints.List()))
+	assert.Equal(uint64(1001), checkpoints.List()[0].Height)
+	assert.Equal(uint64(2001), checkpoints.Latest().Height)
+
+	assert.Nil(checkpoints.Check(1001, hash1))
+	assert.NotNil(checkpoints.Check(1001, hash2))
+	assert.Nil(checkpoints.Check(2001, hash2))
+	assert.NotNil(checkpoints.Check(2001, hash1))
+	assert.Nil(checkpoints.Check(1500, hash2))
+	assert.Nil(checkpoints.Check(3001, hash1))
+}
@@ -1,11 +1,13 @@
 package bls
 
 import (
+	"errors"
 	"io"
 	"sync"
 
 	bh "github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
 	"github.com/pandotoken/pando/rlp"
 )
 
@@ -246,6 +248,34 @@ func GenKey(seed io.Reader) (*SecretKey, error) {
 	return &SecretKey{f: k}, nil
 }
 
+// DeriveKey deterministically derives a secret key from the secret material of another key, e.g.
+// the private key of an account, so that the BLS key does not need to be stored separately. The
+// domain separates the keys derived from the same secret for different purposes.
+func DeriveKey(secret []byte, domain string) (*SecretKey, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("Cannot derive a BLS key from an empty secret")
+	}
+	// Expand the secret to 64 bytes so that the reduction modulo the group order is unbiased
+	seed := append(crypto.Keccak256([]byte(domain), []byte{0}, secret), crypto.Keccak256([]byte(domain), []byte{1}, secret)...)
+	k := &bh.SecretKey{}
+	if err := k.SetLittleEndianMod(seed); err != nil {
+		return nil, err
+	}
+	if k.IsEqual(&bh.SecretKey{}) {
+		return nil, errors.New("Derived a zero BLS key")
+	}
+	return &SecretKey{f: k}, nil
+}
+
+// validatorKeyDomain separates the BLS keys the validators sign their votes with.
+const validatorKeyDomain = "PandoValidatorVoteKey"
+
+// DeriveValidatorKey derives the BLS key a validator signs its votes with from the private key of
+// the validator account.
+func DeriveValidatorKey(privKey *crypto.PrivateKey) (*SecretKey, error) {
+	return DeriveKey(privKey.ToBytes(), validatorKeyDomain)
+}
+
 // RandKey generates a random secret key.
 func RandKey() (*SecretKey, error) {
 	// genkeyLock.Lock()
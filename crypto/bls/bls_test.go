@@ -303,3 +303,36 @@ func TestVerifyAggregate(t *testing.T) {
 	}
 
 }
+
+func TestDeriveKey(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	k1, err := DeriveKey(secret, "validator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := DeriveKey(secret, "validator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !k1.Equals(k2) {
+		t.Error("Derived keys should be deterministic")
+	}
+
+	k3, err := DeriveKey(secret, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k1.Equals(k3) {
+		t.Error("Keys of different domains should differ")
+	}
+
+	msg := []byte("message")
+	if !k1.Sign(msg).Verify(msg, k1.PublicKey()) {
+		t.Error("Signature did not verify")
+	}
+
+	if _, err := DeriveKey(nil, "validator"); err == nil {
+		t.Error("Should not derive a key from an empty secret")
+	}
+}
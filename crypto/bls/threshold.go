@@ -0,0 +1,397 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	bh "github.com/herumi/bls-eth-go-binary/bls"
+)
+
+// ------------- Threshold keys --------------
+//
+// A threshold key is shared among n signers, so that any t of them can produce a signature which
+// verifies against the group public key, while fewer than t learn nothing about the key. The key
+// is the constant term of a secret polynomial of degree t-1, and the share of the signer with index
+// i (1 <= i <= n) is the evaluation of the polynomial at i. The signatures of the shares are
+// combined by Lagrange interpolation at 0.
+//
+// The shares are either dealt from an existing key with SplitKey, or generated without any party
+// ever knowing the group key with the distributed key generation (DKG) of DKGParticipant.
+
+// MaxThresholdSigners caps the number of signers of a threshold key
+const MaxThresholdSigners = 64
+
+// KeyShare is the share of a threshold key held by a signer.
+type KeyShare struct {
+	Index uint32
+	Key   *SecretKey
+}
+
+// PartialSignature is the signature of a message by a key share.
+type PartialSignature struct {
+	Index     uint32
+	Signature *Signature
+}
+
+// Sign signs a message with the key share.
+func (ks *KeyShare) Sign(message []byte) *PartialSignature {
+	return &PartialSignature{Index: ks.Index, Signature: ks.Key.Sign(message)}
+}
+
+// ThresholdPublicKey is the public part of a threshold key, i.e. the commitments to the coefficients
+// of the secret polynomial. The first commitment is the group public key.
+type ThresholdPublicKey struct {
+	Threshold   int
+	Commitments []*PublicKey
+}
+
+// GroupKey returns the public key the combined signatures verify against.
+func (tpk *ThresholdPublicKey) GroupKey() *PublicKey {
+	return tpk.Commitments[0]
+}
+
+// ShareKey returns the public key of the share of the signer with the given index.
+func (tpk *ThresholdPublicKey) ShareKey(index uint32) (*PublicKey, error) {
+	id, err := shareID(index)
+	if err != nil {
+		return nil, err
+	}
+	return evalCommitments(tpk.Commitments, id)
+}
+
+// VerifyPartial checks the partial signature of a message against the key share of its signer.
+func (tpk *ThresholdPublicKey) VerifyPartial(message []byte, partial *PartialSignature) bool {
+	if partial == nil || partial.Signature.IsEmpty() {
+		return false
+	}
+	pubkey, err := tpk.ShareKey(partial.Index)
+	if err != nil {
+		return false
+	}
+	return partial.Signature.Verify(message, pubkey)
+}
+
+// RecoverSignature combines the partial signatures of at least threshold distinct signers into the
+// signature of the group key. The partial signatures need to be verified beforehand, since a
+// single invalid one yields an invalid signature.
+func RecoverSignature(partials []*PartialSignature, threshold int) (*Signature, error) {
+	seen := make(map[uint32]bool)
+	sigs := []bh.Sign{}
+	ids := []bh.ID{}
+	for _, partial := range partials {
+		if partial == nil || partial.Signature.IsEmpty() || seen[partial.Index] {
+			continue
+		}
+		id, err := shareID(partial.Index)
+		if err != nil {
+			return nil, err
+		}
+		seen[partial.Index] = true
+		sigs = append(sigs, *partial.Signature.s)
+		ids = append(ids, *id)
+		if len(sigs) == threshold {
+			break
+		}
+	}
+	if len(sigs) < threshold {
+		return nil, fmt.Errorf("Not enough partial signatures: %v, %v are needed", len(sigs), threshold)
+	}
+
+	sig := &bh.Sign{}
+	if err := sig.Recover(sigs, ids); err != nil {
+		return nil, err
+	}
+	return &Signature{s: sig}, nil
+}
+
+// SplitKey deals the shares of an existing key to n signers, any threshold of which can sign. The
+// dealer knows the key, so the shares need to be dealt on a trusted machine, which erases the key
+// afterwards.
+func SplitKey(key *SecretKey, threshold, n int) ([]*KeyShare, *ThresholdPublicKey, error) {
+	if err := checkThresholdParams(threshold, n); err != nil {
+		return nil, nil, err
+	}
+	poly := key.f.GetMasterSecretKey(threshold)
+	shares := make([]*KeyShare, n)
+	for i := 0; i < n; i++ {
+		share, err := evalPolynomial(poly, uint32(i+1))
+		if err != nil {
+			return nil, nil, err
+		}
+		shares[i] = &KeyShare{Index: uint32(i + 1), Key: share}
+	}
+	return shares, &ThresholdPublicKey{Threshold: threshold, Commitments: commitPolynomial(poly)}, nil
+}
+
+// ------------- Distributed key generation --------------
+
+// DKGParticipant runs the joint Feldman distributed key generation. Each of the n participants
+// deals a random polynomial: it broadcasts the commitments to its coefficients, and privately
+// sends the evaluation of the polynomial at the index of each other participant. A participant
+// verifies the shares it receives against the commitments of their dealers, and its key share is
+// the sum of the shares. The group key is the sum of the constant terms of all the polynomials,
+// which no participant learns.
+type DKGParticipant struct {
+	index     uint32
+	threshold int
+	n         int
+
+	poly        []bh.SecretKey
+	shares      map[uint32]*SecretKey   // map: dealer index |-> share received from the dealer
+	commitments map[uint32][]*PublicKey // map: dealer index |-> commitments of the dealer
+}
+
+// NewDKGParticipant creates the participant with the given index (1 <= index <= n) of a distributed
+// key generation of a threshold-of-n key.
+func NewDKGParticipant(index uint32, threshold, n int) (*DKGParticipant, error) {
+	if err := checkThresholdParams(threshold, n); err != nil {
+		return nil, err
+	}
+	if index < 1 || int(index) > n {
+		return nil, fmt.Errorf("Invalid participant index %v, expected 1 to %v", index, n)
+	}
+	secret, err := RandKey()
+	if err != nil {
+		return nil, err
+	}
+	p := &DKGParticipant{
+		index:       index,
+		threshold:   threshold,
+		n:           n,
+		poly:        secret.f.GetMasterSecretKey(threshold),
+		shares:      make(map[uint32]*SecretKey),
+		commitments: make(map[uint32][]*PublicKey),
+	}
+	// The participant deals its own share to itself
+	own, err := p.ShareFor(index)
+	if err != nil {
+		return nil, err
+	}
+	p.shares[index] = own
+	p.commitments[index] = p.Commitments()
+	return p, nil
+}
+
+// Index returns the index of the participant.
+func (p *DKGParticipant) Index() uint32 {
+	return p.index
+}
+
+// Commitments returns the commitments to the polynomial of the participant, which are broadcast to
+// all the other participants.
+func (p *DKGParticipant) Commitments() []*PublicKey {
+	return commitPolynomial(p.poly)
+}
+
+// ShareFor returns the share the participant deals to the participant with the given index, which
+// needs to be sent over a private and authenticated channel.
+func (p *DKGParticipant) ShareFor(index uint32) (*SecretKey, error) {
+	if index < 1 || int(index) > p.n {
+		return nil, fmt.Errorf("Invalid participant index %v, expected 1 to %v", index, p.n)
+	}
+	return evalPolynomial(p.poly, index)
+}
+
+// AddDealing verifies the share dealt to the participant by another participant against the
+// commitments the dealer broadcast. An error means the dealer misbehaved, and needs to be
+// disqualified, or the key generation restarted.
+func (p *DKGParticipant) AddDealing(dealer uint32, commitments []*PublicKey, share *SecretKey) error {
+	if dealer < 1 || int(dealer) > p.n {
+		return fmt.Errorf("Invalid dealer index %v, expected 1 to %v", dealer, p.n)
+	}
+	if _, ok := p.shares[dealer]; ok {
+		return fmt.Errorf("Duplicated dealing of dealer %v", dealer)
+	}
+	if len(commitments) != p.threshold {
+		return fmt.Errorf("Dealer %v committed to %v coefficients, expected %v", dealer, len(commitments), p.threshold)
+	}
+	for _, commitment := range commitments {
+		if commitment.IsEmpty() {
+			return fmt.Errorf("Dealer %v committed to an empty coefficient", dealer)
+		}
+	}
+	if share == nil {
+		return fmt.Errorf("Dealer %v did not deal a share", dealer)
+	}
+
+	id, err := shareID(p.index)
+	if err != nil {
+		return err
+	}
+	expected, err := evalCommitments(commitments, id)
+	if err != nil {
+		return err
+	}
+	if !share.PublicKey().Equals(expected) {
+		return fmt.Errorf("The share of dealer %v does not match its commitments", dealer)
+	}
+
+	p.shares[dealer] = share
+	p.commitments[dealer] = commitments
+	return nil
+}
+
+// Finalize combines the verified dealings of all the participants into the key share of the
+// participant and the threshold public key, which are the same for all the participants.
+func (p *DKGParticipant) Finalize() (*KeyShare, *ThresholdPublicKey, error) {
+	if len(p.shares) != p.n {
+		return nil, nil, fmt.Errorf("Received the dealings of %v participants, expected %v", len(p.shares), p.n)
+	}
+
+	dealers := make([]uint32, 0, len(p.shares))
+	for dealer := range p.shares {
+		dealers = append(dealers, dealer)
+	}
+	sort.Slice(dealers, func(i, j int) bool { return dealers[i] < dealers[j] })
+
+	key := &bh.SecretKey{}
+	commitments := make([]*PublicKey, p.threshold)
+	for i := range commitments {
+		commitments[i] = NewAggregatePubkey()
+	}
+	for _, dealer := range dealers {
+		key.Add(p.shares[dealer].f)
+		for i, commitment := range p.commitments[dealer] {
+			commitments[i].Aggregate(commitment)
+		}
+	}
+
+	share := &KeyShare{Index: p.index, Key: &SecretKey{f: key}}
+	tpk := &ThresholdPublicKey{Threshold: p.threshold, Commitments: commitments}
+	pubkey, err := tpk.ShareKey(p.index)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !share.Key.PublicKey().Equals(pubkey) {
+		return nil, nil, errors.New("The key share does not match the combined commitments")
+	}
+	return share, tpk, nil
+}
+
+// ------------- Threshold signer --------------
+
+// PartialSigner produces the partial signatures of a key share, e.g. a local KeyShare, or a client
+// of the signing service of another machine.
+type PartialSigner interface {
+	SignPartial(message []byte) (*PartialSignature, error)
+}
+
+// SignPartial implements the PartialSigner interface.
+func (ks *KeyShare) SignPartial(message []byte) (*PartialSignature, error) {
+	return ks.Sign(message), nil
+}
+
+// ThresholdSigner produces the signatures of a threshold key by collecting the partial signatures
+// of its signers. The partial signatures are verified, so that a faulty or compromised signer
+// cannot prevent the others from signing.
+type ThresholdSigner struct {
+	pubkey  *ThresholdPublicKey
+	signers []PartialSigner
+}
+
+// NewThresholdSigner creates a ThresholdSigner collecting the partial signatures from the signers.
+func NewThresholdSigner(pubkey *ThresholdPublicKey, signers []PartialSigner) (*ThresholdSigner, error) {
+	if len(signers) < pubkey.Threshold {
+		return nil, fmt.Errorf("Not enough signers: %v, %v are needed", len(signers), pubkey.Threshold)
+	}
+	return &ThresholdSigner{pubkey: pubkey, signers: signers}, nil
+}
+
+// PublicKey returns the group public key the signatures verify against.
+func (ts *ThresholdSigner) PublicKey() *PublicKey {
+	return ts.pubkey.GroupKey()
+}
+
+// Sign collects the partial signatures until the threshold is reached, and combines them.
+func (ts *ThresholdSigner) Sign(message []byte) (*Signature, error) {
+	partials := []*PartialSignature{}
+	var lastErr error
+	for _, signer := range ts.signers {
+		partial, err := signer.SignPartial(message)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if !ts.pubkey.VerifyPartial(message, partial) {
+			lastErr = fmt.Errorf("Invalid partial signature of signer %v", partial.Index)
+			continue
+		}
+		partials = append(partials, partial)
+		if len(partials) == ts.pubkey.Threshold {
+			break
+		}
+	}
+	if len(partials) < ts.pubkey.Threshold {
+		return nil, fmt.Errorf("Collected %v partial signatures, %v are needed, last error: %v",
+			len(partials), ts.pubkey.Threshold, lastErr)
+	}
+
+	sig, err := RecoverSignature(partials, ts.pubkey.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	if !sig.Verify(message, ts.PublicKey()) {
+		return nil, errors.New("The combined signature does not verify against the group key")
+	}
+	return sig, nil
+}
+
+// ------------- Helpers --------------
+
+func checkThresholdParams(threshold, n int) error {
+	if n < 1 || n > MaxThresholdSigners {
+		return fmt.Errorf("Invalid number of signers %v, expected 1 to %v", n, MaxThresholdSigners)
+	}
+	if threshold < 1 || threshold > n {
+		return fmt.Errorf("Invalid threshold %v, expected 1 to %v", threshold, n)
+	}
+	return nil
+}
+
+// shareID converts the index of a signer to the point the polynomial is evaluated at. The index
+// 0 is excluded, since the polynomial evaluates to the key there.
+func shareID(index uint32) (*bh.ID, error) {
+	if index == 0 {
+		return nil, errors.New("The signer index needs to be positive")
+	}
+	id := &bh.ID{}
+	if err := id.SetDecString(strconv.FormatUint(uint64(index), 10)); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+func evalPolynomial(poly []bh.SecretKey, index uint32) (*SecretKey, error) {
+	id, err := shareID(index)
+	if err != nil {
+		return nil, err
+	}
+	share := &bh.SecretKey{}
+	if err := share.Set(poly, id); err != nil {
+		return nil, err
+	}
+	return &SecretKey{f: share}, nil
+}
+
+func commitPolynomial(poly []bh.SecretKey) []*PublicKey {
+	commitments := make([]*PublicKey, len(poly))
+	for i, mpk := range bh.GetMasterPublicKey(poly) {
+		pk := mpk
+		commitments[i] = &PublicKey{p: &pk}
+	}
+	return commitments
+}
+
+func evalCommitments(commitments []*PublicKey, id *bh.ID) (*PublicKey, error) {
+	mpk := make([]bh.PublicKey, len(commitments))
+	for i, commitment := range commitments {
+		mpk[i] = *commitment.p
+	}
+	pubkey := &bh.PublicKey{}
+	if err := pubkey.Set(mpk, id); err != nil {
+		return nil, err
+	}
+	return &PublicKey{p: pubkey}, nil
+}
@@ -0,0 +1,141 @@
+package bls
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitKey(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := RandKey()
+	require.Nil(err)
+	shares, tpk, err := SplitKey(key, 3, 5)
+	require.Nil(err)
+	require.Equal(5, len(shares))
+	assert.True(tpk.GroupKey().Equals(key.PublicKey()))
+
+	msg := []byte("block hash")
+	partials := []*PartialSignature{}
+	for _, share := range []*KeyShare{shares[4], shares[1], shares[2]} {
+		partial := share.Sign(msg)
+		assert.True(tpk.VerifyPartial(msg, partial))
+		partials = append(partials, partial)
+	}
+	sig, err := RecoverSignature(partials, tpk.Threshold)
+	require.Nil(err)
+	assert.True(sig.Equals(key.Sign(msg)))
+	assert.True(sig.Verify(msg, tpk.GroupKey()))
+
+	// Duplicated partial signatures do not count towards the threshold
+	_, err = RecoverSignature([]*PartialSignature{partials[0], partials[1], partials[1]}, tpk.Threshold)
+	assert.NotNil(err)
+
+	// A partial signature does not verify against the key share of another signer
+	partials[0].Index = 1
+	assert.False(tpk.VerifyPartial(msg, partials[0]))
+
+	_, _, err = SplitKey(key, 6, 5)
+	assert.NotNil(err)
+	_, _, err = SplitKey(key, 0, 5)
+	assert.NotNil(err)
+}
+
+func TestDKG(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	threshold, n := 2, 3
+	participants := make([]*DKGParticipant, n)
+	for i := range participants {
+		p, err := NewDKGParticipant(uint32(i+1), threshold, n)
+		require.Nil(err)
+		participants[i] = p
+	}
+	for _, dealer := range participants {
+		for _, p := range participants {
+			if p == dealer {
+				continue
+			}
+			share, err := dealer.ShareFor(p.Index())
+			require.Nil(err)
+			require.Nil(p.AddDealing(dealer.Index(), dealer.Commitments(), share))
+		}
+	}
+
+	shares := make([]*KeyShare, n)
+	var tpk *ThresholdPublicKey
+	for i, p := range participants {
+		share, pk, err := p.Finalize()
+		require.Nil(err)
+		shares[i] = share
+		if tpk == nil {
+			tpk = pk
+		}
+		assert.True(tpk.GroupKey().Equals(pk.GroupKey()))
+	}
+
+	// Any two signers can sign for the group key
+	msg := []byte("block hash")
+	for _, pair := range [][]int{{0, 1}, {0, 2}, {1, 2}} {
+		sig, err := RecoverSignature([]*PartialSignature{shares[pair[0]].Sign(msg), shares[pair[1]].Sign(msg)}, threshold)
+		require.Nil(err)
+		assert.True(sig.Verify(msg, tpk.GroupKey()))
+	}
+	_, err := RecoverSignature([]*PartialSignature{shares[0].Sign(msg)}, threshold)
+	assert.NotNil(err)
+
+	// A share which does not match the commitments of its dealer is rejected
+	p, err := NewDKGParticipant(1, threshold, n)
+	require.Nil(err)
+	bad, err := participants[1].ShareFor(3)
+	require.Nil(err)
+	assert.NotNil(p.AddDealing(2, participants[1].Commitments(), bad))
+	assert.NotNil(p.AddDealing(2, participants[1].Commitments()[:1], bad))
+	_, _, err = p.Finalize()
+	assert.NotNil(err)
+}
+
+type failingSigner struct{}
+
+func (fs failingSigner) SignPartial(message []byte) (*PartialSignature, error) {
+	return nil, errors.New("signer offline")
+}
+
+type corruptSigner struct {
+	share *KeyShare
+}
+
+func (cs corruptSigner) SignPartial(message []byte) (*PartialSignature, error) {
+	return cs.share.Sign(append([]byte("other "), message...)), nil
+}
+
+func TestThresholdSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := RandKey()
+	require.Nil(err)
+	shares, tpk, err := SplitKey(key, 2, 4)
+	require.Nil(err)
+
+	// The offline and corrupt signers are skipped
+	signer, err := NewThresholdSigner(tpk, []PartialSigner{failingSigner{}, corruptSigner{shares[1]}, shares[2], shares[3]})
+	require.Nil(err)
+	msg := []byte("block hash")
+	sig, err := signer.Sign(msg)
+	require.Nil(err)
+	assert.True(sig.Verify(msg, signer.PublicKey()))
+
+	signer, err = NewThresholdSigner(tpk, []PartialSigner{failingSigner{}, corruptSigner{shares[1]}, shares[2]})
+	require.Nil(err)
+	_, err = signer.Sign(msg)
+	assert.NotNil(err)
+
+	_, err = NewThresholdSigner(tpk, []PartialSigner{shares[0]})
+	assert.NotNil(err)
+}
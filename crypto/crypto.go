@@ -226,6 +226,10 @@ func (sig *Signature) IsEmpty() bool {
 // RecoverSignerAddress recovers the address of the signer for the given message
 func (sig *Signature) RecoverSignerAddress(msg common.Bytes) (common.Address, error) {
 	msgHash := keccak256(msg)
+	if address, ok := lookupSigner(msgHash, sig.ToBytes()); ok {
+		return address, nil
+	}
+
 	recoveredUncompressedPubKey, err := ecrecover(msgHash, sig.ToBytes())
 	if err != nil {
 		return common.Address{}, err
@@ -237,6 +241,7 @@ func (sig *Signature) RecoverSignerAddress(msg common.Bytes) (common.Address, er
 	}
 
 	address := pk.Address()
+	cacheSigner(msgHash, sig.ToBytes(), address)
 	return address, nil
 }
 
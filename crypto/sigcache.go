@@ -0,0 +1,76 @@
+package crypto
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/metrics"
+)
+
+// DefaultSignatureCacheSize is the default number of recovered signers kept by the signature cache
+const DefaultSignatureCacheSize = 65536
+
+var (
+	sigCacheHitCounter  = metrics.NewRegisteredCounter("crypto/sig_cache/hit", nil)
+	sigCacheMissCounter = metrics.NewRegisteredCounter("crypto/sig_cache/miss", nil)
+)
+
+// sigCache memoizes the signer addresses recovered from the signatures. The ECDSA public key
+// recovery dominates the cost of verifying a transaction, and the same signature is typically
+// recovered several times, e.g. when the transaction is screened by the mempool, when the
+// proposal including it is validated, and when the block is finalized. A recovered address is
+// keyed by the hash of the signed message and the signature, so a hit is exactly the result the
+// recovery would have returned.
+var sigCache = struct {
+	mu    sync.RWMutex
+	cache *lru.Cache // map: sigCacheKey(msgHash, sig) |-> common.Address
+}{}
+
+func init() {
+	SetSignatureCacheSize(DefaultSignatureCacheSize)
+}
+
+// SetSignatureCacheSize resizes the cache of the recovered signers, and drops the cached
+// signers. A size of 0 disables the cache.
+func SetSignatureCacheSize(size int) {
+	var cache *lru.Cache
+	if size > 0 {
+		cache, _ = lru.New(size)
+	}
+	sigCache.mu.Lock()
+	sigCache.cache = cache
+	sigCache.mu.Unlock()
+}
+
+func sigCacheKey(msgHash []byte, sig []byte) common.Hash {
+	return Keccak256Hash(msgHash, sig)
+}
+
+// lookupSigner returns the cached signer of the signature over the message hash
+func lookupSigner(msgHash []byte, sig []byte) (common.Address, bool) {
+	sigCache.mu.RLock()
+	cache := sigCache.cache
+	sigCache.mu.RUnlock()
+	if cache == nil {
+		return common.Address{}, false
+	}
+	value, ok := cache.Get(sigCacheKey(msgHash, sig))
+	if !ok {
+		sigCacheMissCounter.Inc(1)
+		return common.Address{}, false
+	}
+	sigCacheHitCounter.Inc(1)
+	return value.(common.Address), true
+}
+
+// cacheSigner caches the signer recovered from the signature over the message hash
+func cacheSigner(msgHash []byte, sig []byte, signer common.Address) {
+	sigCache.mu.RLock()
+	cache := sigCache.cache
+	sigCache.mu.RUnlock()
+	if cache == nil {
+		return
+	}
+	cache.Add(sigCacheKey(msgHash, sig), signer)
+}
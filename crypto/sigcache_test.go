@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureCache(t *testing.T) {
+	assert := assert.New(t)
+	defer SetSignatureCacheSize(DefaultSignatureCacheSize)
+
+	privKey, pubKey, err := TEST_GenerateKeyPairWithSeed("sig_cache_seed")
+	assert.Nil(err)
+	msg := common.Bytes("cached message")
+	sig, err := privKey.Sign(msg)
+	assert.Nil(err)
+	msgHash := keccak256(msg)
+
+	SetSignatureCacheSize(16)
+	_, ok := lookupSigner(msgHash, sig.ToBytes())
+	assert.False(ok)
+
+	// The recovered signer is cached, and only for the message it signs
+	assert.True(sig.Verify(msg, pubKey.Address()))
+	signer, ok := lookupSigner(msgHash, sig.ToBytes())
+	assert.True(ok)
+	assert.Equal(pubKey.Address(), signer)
+	_, ok = lookupSigner(keccak256(common.Bytes("other message")), sig.ToBytes())
+	assert.False(ok)
+
+	// A cached signer is returned as recovered
+	fakeAddr := common.HexToAddress("0x1")
+	cacheSigner(msgHash, sig.ToBytes(), fakeAddr)
+	assert.True(sig.Verify(msg, fakeAddr))
+
+	// Resizing drops the cached signers
+	SetSignatureCacheSize(0)
+	_, ok = lookupSigner(msgHash, sig.ToBytes())
+	assert.False(ok)
+	assert.True(sig.Verify(msg, pubKey.Address()))
+	_, ok = lookupSigner(msgHash, sig.ToBytes())
+	assert.False(ok)
+}
@@ -0,0 +1,15 @@
+package crypto
+
+import (
+	"github.com/pandotoken/pando/common"
+)
+
+// Signer signs messages on behalf of a key pair. The private key does not have to
+// reside in the current process, e.g. it could be held by a separate signer process.
+// PrivateKey implements Signer.
+type Signer interface {
+	PublicKey() *PublicKey
+	Sign(msg common.Bytes) (*Signature, error)
+}
+
+var _ Signer = (*PrivateKey)(nil)
@@ -0,0 +1,214 @@
+// Package features gates the protocol changes on their activation heights.
+//
+// Each protocol change is a named fork, activated at a block height (see common.SupportedForks and
+// the heights in common/heights.go). The behavior changes are gated with
+//
+//	if features.IsEnabled(features.RametronTiers, blockHeight) {
+//		...
+//	}
+//
+// instead of comparing the block height against the fork height constants, so that the schedule is
+// kept in one place. The heights can be overridden with the fork.heights config for the private
+// and the test networks, e.g.
+//
+//	fork:
+//	  heights:
+//	    RametronTiers: 5000
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// The names of the forks, as advertised to the peers
+const (
+	ValidatorReward             = "ValidatorReward"
+	Pando2                      = "Pando2"
+	LowerGNStakeThresholdTo1000 = "LowerGNStakeThresholdTo1000"
+	SmartContract               = "SmartContract"
+	SampleStakingReward         = "SampleStakingReward"
+	MultiCurrencyReservedFund   = "MultiCurrencyReservedFund"
+	SessionKeys                 = "SessionKeys"
+	TypedSigning                = "TypedSigning"
+	MultiSig                    = "MultiSig"
+	BatchSendTx                 = "BatchSendTx"
+	SendTxData                  = "SendTxData"
+	EthTxSigning                = "EthTxSigning"
+	SlashAppeals                = "SlashAppeals"
+	BlockHash                   = "BlockHash"
+	Escrow                      = "Escrow"
+	CanonicalSigning            = "CanonicalSigning"
+	AggregatedVotes             = "AggregatedVotes"
+	ThresholdVotes              = "ThresholdVotes"
+	MeteredSettlement           = "MeteredSettlement"
+	StakeUnbondingQueue         = "StakeUnbondingQueue"
+	DoubleSignSlash             = "DoubleSignSlash"
+	Delegation                  = "Delegation"
+	ValidatorCommission         = "ValidatorCommission"
+	RametronTiers               = "RametronTiers"
+	RametronAttestation         = "RametronAttestation"
+	RandomnessBeacon            = "RandomnessBeacon"
+	PaymentDisputeWindow        = "PaymentDisputeWindow"
+	KeyRotation                 = "KeyRotation"
+	TxValidUntil                = "TxValidUntil"
+	DynamicBaseFee              = "DynamicBaseFee"
+	HTLC                        = "HTLC"
+	BlockBudget                 = "BlockBudget"
+	Governance                  = "Governance"
+	Bridge                      = "Bridge"
+	Subchain                    = "Subchain"
+)
+
+// Schedule is the activation heights of the forks. It is immutable once created.
+type Schedule struct {
+	forks   []common.Fork // in the order the forks were introduced
+	heights map[string]uint64
+}
+
+// NewSchedule creates the schedule of the given forks, with the heights of some of them overridden.
+// It returns an error if an override does not name a fork.
+func NewSchedule(forks []common.Fork, overrides map[string]uint64) (*Schedule, error) {
+	s := &Schedule{
+		forks:   make([]common.Fork, 0, len(forks)),
+		heights: make(map[string]uint64, len(forks)),
+	}
+	for _, fork := range forks {
+		if _, ok := s.heights[fork.Name]; ok {
+			return nil, fmt.Errorf("Fork %v is declared more than once", fork.Name)
+		}
+		s.heights[fork.Name] = fork.Height
+	}
+
+	// The fork names are matched case-insensitively, since the config keys are lower cased
+	byLowerName := make(map[string]string, len(forks))
+	for _, fork := range forks {
+		byLowerName[strings.ToLower(fork.Name)] = fork.Name
+	}
+	overridden := make(map[string]bool, len(overrides))
+	for _, override := range sortedNames(overrides) {
+		name, ok := byLowerName[strings.ToLower(override)]
+		if !ok {
+			return nil, fmt.Errorf("Cannot override the height of unknown fork %v", override)
+		}
+		if overridden[name] {
+			return nil, fmt.Errorf("The height of fork %v is overridden more than once", name)
+		}
+		overridden[name] = true
+		s.heights[name] = overrides[override]
+	}
+
+	for _, fork := range forks {
+		s.forks = append(s.forks, common.Fork{Name: fork.Name, Height: s.heights[fork.Name]})
+	}
+	return s, nil
+}
+
+func sortedNames(overrides map[string]uint64) []string {
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsEnabled returns whether the fork is active at the given block height. It panics if the fork is
+// unknown, since that is a programming error.
+func (s *Schedule) IsEnabled(name string, height uint64) bool {
+	return height >= s.Height(name)
+}
+
+// Height returns the activation height of the fork. It panics if the fork is unknown.
+func (s *Schedule) Height(name string) uint64 {
+	height, ok := s.heights[name]
+	if !ok {
+		panic(fmt.Sprintf("Unknown fork: %v", name))
+	}
+	return height
+}
+
+// Forks returns the forks with their activation heights, in the order they were introduced
+func (s *Schedule) Forks() []common.Fork {
+	forks := make([]common.Fork, len(s.forks))
+	copy(forks, s.forks)
+	return forks
+}
+
+var (
+	scheduleMtx     sync.RWMutex
+	currentSchedule = mustNewSchedule(common.SupportedForks(), nil)
+)
+
+func mustNewSchedule(forks []common.Fork, overrides map[string]uint64) *Schedule {
+	s, err := NewSchedule(forks, overrides)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Configure overrides the activation heights of the forks supported by this binary. It is meant to
+// be called once at startup, before the node processes any block.
+func Configure(overrides map[string]uint64) error {
+	s, err := NewSchedule(common.SupportedForks(), overrides)
+	if err != nil {
+		return err
+	}
+	scheduleMtx.Lock()
+	defer scheduleMtx.Unlock()
+	currentSchedule = s
+	return nil
+}
+
+// ParseOverrides parses the fork heights of the config, e.g. the result of viper.GetStringMapString
+func ParseOverrides(config map[string]string) (map[string]uint64, error) {
+	overrides := make(map[string]uint64, len(config))
+	for name, value := range config {
+		height, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid height of fork %v: %v", name, value)
+		}
+		overrides[name] = height
+	}
+	return overrides, nil
+}
+
+// ActivateUnscheduled returns the overrides activating the forks not scheduled on the mainnet yet at
+// the given height, e.g. for a private network or the tests exercising the upcoming protocol changes
+func ActivateUnscheduled(height uint64) map[string]uint64 {
+	overrides := make(map[string]uint64)
+	for _, fork := range common.SupportedForks() {
+		if fork.Height == common.HeightUnscheduled {
+			overrides[fork.Name] = height
+		}
+	}
+	return overrides
+}
+
+// CurrentSchedule returns the schedule in effect
+func CurrentSchedule() *Schedule {
+	scheduleMtx.RLock()
+	defer scheduleMtx.RUnlock()
+	return currentSchedule
+}
+
+// IsEnabled returns whether the fork is active at the given block height
+func IsEnabled(name string, height uint64) bool {
+	return CurrentSchedule().IsEnabled(name, height)
+}
+
+// Height returns the activation height of the fork
+func Height(name string) uint64 {
+	return CurrentSchedule().Height(name)
+}
+
+// Forks returns the forks with their activation heights, in the order they were introduced
+func Forks() []common.Fork {
+	return CurrentSchedule().Forks()
+}
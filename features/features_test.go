@@ -0,0 +1,73 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/common"
+)
+
+func TestSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	forks := []common.Fork{{Name: "SmartContract", Height: 10}, {Name: "Escrow", Height: 5000}}
+	s, err := NewSchedule(forks, nil)
+	assert.Nil(err)
+	assert.False(s.IsEnabled("SmartContract", 9))
+	assert.True(s.IsEnabled("SmartContract", 10))
+	assert.Equal(uint64(5000), s.Height("Escrow"))
+	assert.Equal(forks, s.Forks())
+	assert.Panics(func() { s.IsEnabled("Rametron", 10) })
+
+	// The overrides are matched case-insensitively, and keep the order of the forks
+	s, err = NewSchedule(forks, map[string]uint64{"escrow": 20})
+	assert.Nil(err)
+	assert.True(s.IsEnabled("Escrow", 20))
+	assert.Equal([]common.Fork{{Name: "SmartContract", Height: 10}, {Name: "Escrow", Height: 20}}, s.Forks())
+
+	_, err = NewSchedule(forks, map[string]uint64{"Rametron": 20})
+	assert.NotNil(err)
+	_, err = NewSchedule(forks, map[string]uint64{"Escrow": 20, "escrow": 30})
+	assert.NotNil(err)
+	_, err = NewSchedule(append(forks, common.Fork{Name: "Escrow", Height: 1}), nil)
+	assert.NotNil(err)
+}
+
+func TestConfigure(t *testing.T) {
+	assert := assert.New(t)
+	defer Configure(nil)
+
+	// Every supported fork has a name constant
+	for _, fork := range common.SupportedForks() {
+		assert.Equal(fork.Height, Height(fork.Name))
+	}
+	assert.Equal(common.HeightEnableGovernance, Height(Governance))
+
+	overrides, err := ParseOverrides(map[string]string{"governance": "12345"})
+	assert.Nil(err)
+	assert.Nil(Configure(overrides))
+	assert.False(IsEnabled(Governance, 12344))
+	assert.True(IsEnabled(Governance, 12345))
+	assert.Equal(len(common.SupportedForks()), len(Forks()))
+
+	_, err = ParseOverrides(map[string]string{"governance": "soon"})
+	assert.NotNil(err)
+	assert.NotNil(Configure(map[string]uint64{"Rametron": 1}))
+	assert.True(IsEnabled(Governance, 12345)) // unchanged on error
+}
+
+func TestActivateUnscheduled(t *testing.T) {
+	assert := assert.New(t)
+	defer Configure(nil)
+
+	// The forks are disabled on the mainnet until they are scheduled
+	assert.Nil(Configure(nil))
+	assert.False(IsEnabled(Governance, 1e9))
+	assert.True(IsEnabled(SmartContract, 1))
+
+	assert.Nil(Configure(ActivateUnscheduled(10)))
+	assert.False(IsEnabled(Governance, 9))
+	assert.True(IsEnabled(Governance, 10))
+	assert.Equal(common.HeightEnableSmartContract, Height(SmartContract))
+}
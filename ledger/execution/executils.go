@@ -7,6 +7,8 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 )
@@ -161,15 +163,34 @@ func validateInputsBasic(ins []types.TxInput) result.Result {
 	return result.OK
 }
 
+// validateMultiSigEnabled rejects multi-signature inputs before the multi-signature support is enabled
+func validateMultiSigEnabled(view *state.StoreView, ins []types.TxInput) result.Result {
+	blockHeight := view.Height() + 1
+	if features.IsEnabled(features.MultiSig, blockHeight) {
+		return result.OK
+	}
+	for _, in := range ins {
+		if in.MultiSig != nil {
+			return result.Error("Multi-signature inputs are not enabled until height %v", features.Height(features.MultiSig)).
+				WithErrorCode(result.CodeMultiSigNotSupported)
+		}
+	}
+	return result.OK
+}
+
 // Validate inputs and compute total amount of coins
-func validateInputsAdvanced(accounts map[string]*types.Account, signBytes []byte, ins []types.TxInput) (total types.Coins, res result.Result) {
+func validateInputsAdvanced(accounts map[string]*types.Account, signBytes []byte, altSignBytes []common.Bytes, ins []types.TxInput) (total types.Coins, res result.Result) {
 	total = types.NewCoins(0, 0)
 	for _, in := range ins {
 		acc := accounts[string(in.Address[:])]
 		if acc == nil {
 			panic("validateInputsAdvanced() expects account in accounts")
 		}
-		res = validateInputAdvanced(acc, signBytes, in)
+		if in.MultiSig != nil {
+			res = validateInputAdvancedWithMultiSig(acc, signBytes, altSignBytes, in)
+		} else {
+			res = validateInputAdvanced(acc, signBytes, altSignBytes, in)
+		}
 		if res.IsError() {
 			return
 		}
@@ -179,7 +200,68 @@ func validateInputsAdvanced(accounts map[string]*types.Account, signBytes []byte
 	return total, result.OK
 }
 
-func validateInputAdvanced(acc *types.Account, signBytes []byte, in types.TxInput) result.Result {
+// Validate inputs which may be signed by session keys, and compute total amount of coins
+func validateInputsAdvancedWithSessionKeys(view *state.StoreView, accounts map[string]*types.Account, signBytes []byte, altSignBytes []common.Bytes, ins []types.TxInput,
+	txType types.TxType, destinations []common.Address) (total types.Coins, res result.Result) {
+	total = types.NewCoins(0, 0)
+	for _, in := range ins {
+		acc := accounts[string(in.Address[:])]
+		if acc == nil {
+			panic("validateInputsAdvancedWithSessionKeys() expects account in accounts")
+		}
+		if in.MultiSig != nil {
+			res = validateInputAdvancedWithMultiSig(acc, signBytes, altSignBytes, in)
+		} else {
+			res = validateInputAdvancedWithSessionKey(view, acc, signBytes, altSignBytes, in, txType, destinations, in.Coins)
+		}
+		if res.IsError() {
+			return
+		}
+		total = total.Plus(in.Coins)
+	}
+	return total, result.OK
+}
+
+func validateInputAdvanced(acc *types.Account, signBytes []byte, altSignBytes []common.Bytes, in types.TxInput) result.Result {
+	if in.MultiSig != nil {
+		return result.Error("Multi-signature inputs are only supported by send and rametron stake transactions").
+			WithErrorCode(result.CodeMultiSigNotSupported)
+	}
+
+	if res := validateInputSequenceAndBalance(acc, in); res.IsError() {
+		return res
+	}
+
+	// Check signatures
+	if !verifyAccountSignature(in.Signature, signBytes, altSignBytes, acc) {
+		return result.Error("Signature verification failed, SignBytes: %v",
+			hex.EncodeToString(signBytes)).WithErrorCode(result.CodeInvalidSignature)
+	}
+
+	return result.OK
+}
+
+// validateInputAdvancedWithMultiSig validates the input of a multi-signature account, which needs
+// to be signed by at least threshold of its signers
+func validateInputAdvancedWithMultiSig(acc *types.Account, signBytes []byte, altSignBytes []common.Bytes, in types.TxInput) result.Result {
+	if res := validateInputSequenceAndBalance(acc, in); res.IsError() {
+		return res
+	}
+
+	// Check signatures
+	if in.Signature != nil && !in.Signature.IsEmpty() {
+		return result.Error("Multi-signature input %v should not carry a single signature", in.Address.Hex()).
+			WithErrorCode(result.CodeInvalidMultiSig)
+	}
+	if err := in.MultiSig.Verify(acc.Address, append([]common.Bytes{signBytes}, altSignBytes...)...); err != nil {
+		return result.Error("Multi-signature verification failed: %v, SignBytes: %v",
+			err, hex.EncodeToString(signBytes)).WithErrorCode(result.CodeInvalidSignature)
+	}
+
+	return result.OK
+}
+
+func validateInputSequenceAndBalance(acc *types.Account, in types.TxInput) result.Result {
 	// Check sequence/coins
 	seq, balance := acc.Sequence, acc.Balance
 	if seq+1 != in.Sequence {
@@ -192,16 +274,128 @@ func validateInputAdvanced(acc *types.Account, signBytes []byte, in types.TxInpu
 		return result.Error("Insufficient fund: balance is %v, tried to send %v",
 			balance, in.Coins).WithErrorCode(result.CodeInsufficientFund)
 	}
+	return result.OK
+}
 
-	// Check signatures
-	if !in.Signature.Verify(signBytes, acc.Address) {
-		return result.Error("Signature verification failed, SignBytes: %v",
-			hex.EncodeToString(signBytes)).WithErrorCode(result.CodeInvalidSignature)
+// validateInputAdvancedWithSessionKey validates the input like validateInputAdvanced, except that the
+// input may also be signed by a session key of the account, if the session key is permitted to sign
+// a transaction of the given type, sending the given amount to the given destinations
+func validateInputAdvancedWithSessionKey(view *state.StoreView, acc *types.Account, signBytes []byte, altSignBytes []common.Bytes, in types.TxInput,
+	txType types.TxType, destinations []common.Address, amount types.Coins) result.Result {
+	res := validateInputAdvanced(acc, signBytes, altSignBytes, in)
+	if res.Code != result.CodeInvalidSignature {
+		return res
+	}
+
+	blockHeight := view.Height() + 1
+	if !features.IsEnabled(features.SessionKeys, blockHeight) {
+		return res
+	}
+	_, sessionKey := getSigningSessionKey(view, signBytes, altSignBytes, in)
+	if sessionKey == nil {
+		return res
+	}
+	if err := sessionKey.Authorize(txType, destinations, amount, blockHeight); err != nil {
+		return result.Error("Session key %v of %v cannot sign the transaction: %v",
+			sessionKey.Address.Hex(), in.Address.Hex(), err).WithErrorCode(result.CodeSessionKeyNotPermitted)
 	}
 
 	return result.OK
 }
 
+// chargeSessionKey adds the amount to the spending of the session key which signed the input, if any
+func chargeSessionKey(view *state.StoreView, signBytes []byte, altSignBytes []common.Bytes, in types.TxInput, amount types.Coins) {
+	sessionKeys, sessionKey := getSigningSessionKey(view, signBytes, altSignBytes, in)
+	if sessionKey == nil {
+		return
+	}
+	sessionKey.Spend(amount)
+	view.UpdateSessionKeys(in.Address, sessionKeys)
+}
+
+// getSigningSessionKey returns the session key of the account which signed the input, nil if the
+// input is not signed by a session key. The signature is only recovered when the account has
+// session keys.
+func getSigningSessionKey(view *state.StoreView, signBytes []byte, altSignBytes []common.Bytes, in types.TxInput) (*types.SessionKeySet, *types.SessionKey) {
+	sessionKeys := view.GetSessionKeys(in.Address)
+	if sessionKeys.Len() == 0 || in.Signature == nil || in.Signature.IsEmpty() {
+		return sessionKeys, nil
+	}
+	for _, msg := range append([]common.Bytes{signBytes}, altSignBytes...) {
+		if msg == nil {
+			continue
+		}
+		signer, err := in.Signature.RecoverSignerAddress(msg)
+		if err != nil || signer == in.Address {
+			continue
+		}
+		if sessionKey := sessionKeys.Get(signer); sessionKey != nil {
+			return sessionKeys, sessionKey
+		}
+	}
+	return sessionKeys, nil
+}
+
+// getAltSignBytes returns the alternative sign bytes the signatures of the transaction may be over,
+// instead of its RLP sign bytes, i.e. the typed sign bytes and the canonical JSON sign bytes once
+// they are enabled
+func getAltSignBytes(chainID string, view *state.StoreView, tx types.Tx) []common.Bytes {
+	blockHeight := view.Height() + 1
+	altSignBytes := []common.Bytes{}
+	if features.IsEnabled(features.TypedSigning, blockHeight) {
+		typedSignBytes, err := types.TypedSignBytes(chainID, tx)
+		if err != nil {
+			logger.Warnf("Failed to get the typed sign bytes: %v", err)
+		} else {
+			altSignBytes = append(altSignBytes, typedSignBytes)
+		}
+	}
+	if features.IsEnabled(features.CanonicalSigning, blockHeight) {
+		canonicalSignBytes, err := types.CanonicalSignBytes(chainID, tx)
+		if err != nil {
+			logger.Warnf("Failed to get the canonical sign bytes: %v", err)
+		} else {
+			altSignBytes = append(altSignBytes, canonicalSignBytes)
+		}
+	}
+	return altSignBytes
+}
+
+// getEthSignBytes returns the Ethereum sign bytes of the smart contract transaction, or nil if
+// signing as Ethereum transactions is not enabled yet
+func getEthSignBytes(chainID string, view *state.StoreView, tx *types.SmartContractTx) []byte {
+	blockHeight := view.Height() + 1
+	if !features.IsEnabled(features.EthTxSigning, blockHeight) {
+		return nil
+	}
+	return types.EthSignBytes(chainID, tx)
+}
+
+// verifySignature verifies the signature over the sign bytes, or over any of the alternative
+// sign bytes
+func verifySignature(sig *crypto.Signature, signBytes []byte, altSignBytes []common.Bytes, addr common.Address) bool {
+	if sig.Verify(signBytes, addr) {
+		return true
+	}
+	for _, msg := range altSignBytes {
+		if sig.Verify(msg, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyAccountSignature verifies that the signature is signed by a key accepted for the account,
+// i.e. the key of the account address, or the keys authorized by a key rotation
+func verifyAccountSignature(sig *crypto.Signature, signBytes []byte, altSignBytes []common.Bytes, acc *types.Account) bool {
+	for _, signer := range acc.SignerAddresses() {
+		if verifySignature(sig, signBytes, altSignBytes, signer) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateOutputsBasic(outs []types.TxOutput) result.Result {
 	for _, out := range outs {
 		// Check TxOutput basic
@@ -248,23 +442,39 @@ func adjustByOutputs(view *state.StoreView, accounts map[string]*types.Account,
 	}
 }
 
-func sanityCheckForGasPrice(gasPrice *big.Int) bool {
+// minimumTxFee returns the minimum fee of a regular transaction in the block following the view,
+// which is the base fee once the base fee is enabled
+func minimumTxFee(view *state.StoreView) *big.Int {
+	if !features.IsEnabled(features.DynamicBaseFee, view.Height()+1) {
+		return types.MinimumBaseFee()
+	}
+	return view.GetBaseFee()
+}
+
+// minimumGasPrice returns the minimum gas price of a smart contract transaction in the block
+// following the view
+func minimumGasPrice(view *state.StoreView) *big.Int {
+	if !features.IsEnabled(features.DynamicBaseFee, view.Height()+1) {
+		return new(big.Int).SetUint64(types.MinimumGasPrice)
+	}
+	return types.BaseGasPrice(view.GetBaseFee())
+}
+
+func sanityCheckForGasPrice(view *state.StoreView, gasPrice *big.Int) bool {
 	if gasPrice == nil {
 		return false
 	}
 
-	minimumGasPrice := new(big.Int).SetUint64(types.MinimumGasPrice)
-	if gasPrice.Cmp(minimumGasPrice) < 0 {
+	if gasPrice.Cmp(minimumGasPrice(view)) < 0 {
 		return false
 	}
 
 	return true
 }
 
-func sanityCheckForFee(fee types.Coins) bool {
+func sanityCheckForFee(view *state.StoreView, fee types.Coins) bool {
 	fee = fee.NoNil()
-	minimumFee := new(big.Int).SetUint64(types.MinimumTransactionFeePTXWei)
-	return fee.PandoWei.Cmp(types.Zero) == 0 && fee.PTXWei.Cmp(minimumFee) >= 0
+	return fee.PandoWei.Cmp(types.Zero) == 0 && fee.PTXWei.Cmp(minimumTxFee(view)) >= 0
 }
 
 func chargeFee(account *types.Account, fee types.Coins) bool {
@@ -275,3 +485,56 @@ func chargeFee(account *types.Account, fee types.Coins) bool {
 	account.Balance = account.Balance.Minus(fee)
 	return true
 }
+
+// validateFeePayer checks the input of a transaction which carries no coins, i.e. the account only
+// signs the transaction and pays the fee
+func validateFeePayer(chainID string, view *state.StoreView, tx types.Tx, submitter types.TxInput, fee types.Coins) result.Result {
+	res := submitter.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	account, success := getInput(view, submitter)
+	if success.IsError() {
+		return result.Error("Failed to get the account: %v", submitter.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(account, signBytes, altSignBytes, submitter)
+	if res.IsError() {
+		logger.Debugf("validateSourceAdvanced failed on %v: %v", submitter.Address.Hex(), res)
+		return res
+	}
+
+	if !sanityCheckForFee(view, fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if !submitter.Coins.NoNil().IsZero() {
+		return result.Error("The input of %v cannot carry coins", submitter.Address.Hex())
+	}
+
+	if !account.Balance.IsGTE(fee) {
+		return result.Error("Account %v balance is %v, but required minimal balance is %v",
+			submitter.Address.Hex(), account.Balance, fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+// chargeFeePayer charges the fee to the input of a transaction which carries no coins, and
+// increments its sequence
+func chargeFeePayer(view *state.StoreView, submitter types.TxInput, fee types.Coins) result.Result {
+	account, success := getInput(view, submitter)
+	if success.IsError() {
+		return result.Error("Failed to get the account")
+	}
+	if !chargeFee(account, fee) {
+		return result.Error("Failed to charge transaction fee")
+	}
+	account.Sequence++
+	view.SetAccount(submitter.Address, account)
+	return result.OK
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/ledger/vm"
 	"github.com/pandotoken/pando/store/database"
 )
 
@@ -33,17 +35,7 @@ type Executor struct {
 	consensus core.ConsensusEngine
 	valMgr    core.ValidatorManager
 
-	coinbaseTxExec *CoinbaseTxExecutor
-	// slashTxExec          *SlashTxExecutor
-	sendTxExec           *SendTxExecutor
-	rametronStakeTxExec  *RametronStakeTxExecutor
-	reserveFundTxExec    *ReserveFundTxExecutor
-	releaseFundTxExec    *ReleaseFundTxExecutor
-	servicePaymentTxExec *ServicePaymentTxExecutor
-	splitRuleTxExec      *SplitRuleTxExecutor
-	smartContractTxExec  *SmartContractTxExecutor
-	depositStakeTxExec   *DepositStakeExecutor
-	withdrawStakeTxExec  *WithdrawStakeExecutor
+	txExecutors map[types.TxType]TxExecutor
 
 	skipSanityCheck bool
 }
@@ -51,24 +43,14 @@ type Executor struct {
 // NewExecutor creates a new instance of Executor
 func NewExecutor(db database.Database, chain *blockchain.Chain, state *st.LedgerState, consensus core.ConsensusEngine, valMgr core.ValidatorManager) *Executor {
 	executor := &Executor{
-		db:             db,
-		chain:          chain,
-		state:          state,
-		consensus:      consensus,
-		valMgr:         valMgr,
-		coinbaseTxExec: NewCoinbaseTxExecutor(db, chain, state, consensus, valMgr),
-		// slashTxExec:          NewSlashTxExecutor(consensus, valMgr),
-		sendTxExec:           NewSendTxExecutor(),
-		rametronStakeTxExec:  NewRametronStakeTxExecutor(),
-		reserveFundTxExec:    NewReserveFundTxExecutor(state),
-		releaseFundTxExec:    NewReleaseFundTxExecutor(state),
-		servicePaymentTxExec: NewServicePaymentTxExecutor(state),
-		splitRuleTxExec:      NewSplitRuleTxExecutor(state),
-		smartContractTxExec:  NewSmartContractTxExecutor(chain, state),
-		depositStakeTxExec:   NewDepositStakeExecutor(),
-		withdrawStakeTxExec:  NewWithdrawStakeExecutor(state),
-		skipSanityCheck:      false,
+		db:              db,
+		chain:           chain,
+		state:           state,
+		consensus:       consensus,
+		valMgr:          valMgr,
+		skipSanityCheck: false,
 	}
+	executor.txExecutors = newTxExecutors(executor)
 
 	return executor
 }
@@ -79,6 +61,12 @@ func (exec *Executor) SetSkipSanityCheck(skip bool) {
 	exec.skipSanityCheck = skip
 }
 
+// SetTracer sets the tracer of the EVM execution of the smart contract transactions, nil disables
+// the tracing.
+func (exec *Executor) SetTracer(tracer vm.Tracer) {
+	exec.txExecutors[types.TxSmartContract].(*SmartContractTxExecutor).tracer = tracer
+}
+
 // ExecuteTx executes the given transaction
 func (exec *Executor) ExecuteTx(tx types.Tx) (common.Hash, result.Result) {
 	return exec.processTx(tx, core.DeliveredView)
@@ -136,6 +124,10 @@ func (exec *Executor) sanityCheck(chainID string, view *st.StoreView, tx types.T
 		return result.Error("tx type not supported yet")
 	}
 
+	if res := checkTxExpiry(view, tx); res.IsError() {
+		return res
+	}
+
 	var sanityCheckResult result.Result
 	txExecutor := exec.getTxExecutor(tx)
 	if txExecutor != nil {
@@ -171,48 +163,44 @@ func (exec *Executor) process(chainID string, view *st.StoreView, tx types.Tx) (
 func (exec *Executor) isTxTypeSupported(view *st.StoreView, tx types.Tx) bool {
 	blockHeight := view.Height() + 1
 
-	switch tx.(type) {
-	case *types.SmartContractTx:
-		if blockHeight < common.HeightEnableSmartContract {
-			return false
-		}
-	default:
+	txType, err := types.GetTxType(tx)
+	if err != nil {
+		return true // rejected as an unknown tx type
+	}
+	spec, ok := lookupTxExecutorSpec(txType)
+	if !ok {
 		return true
 	}
+	return spec.feature == "" || features.IsEnabled(spec.feature, blockHeight)
+}
 
-	return true
+// checkTxExpiry rejects the tx once the block height is beyond its ValidUntilBlock. The mempool
+// screens its txs again after each block, which also evicts the expired txs.
+func checkTxExpiry(view *st.StoreView, tx types.Tx) result.Result {
+	validUntil := types.GetValidUntilBlock(tx)
+	if validUntil == 0 {
+		return result.OK
+	}
+	blockHeight := view.Height() + 1
+	if !features.IsEnabled(features.TxValidUntil, blockHeight) {
+		return result.Error("ValidUntilBlock is not supported yet")
+	}
+	if types.IsTxExpired(tx, blockHeight) {
+		return result.Error("Transaction expired at block height %v, current block height: %v",
+			validUntil, blockHeight).WithErrorCode(result.CodeTxExpired)
+	}
+	return result.OK
 }
 
 func (exec *Executor) getTxExecutor(tx types.Tx) TxExecutor {
-	var txExecutor TxExecutor
-	switch tx.(type) {
-	case *types.CoinbaseTx:
-		txExecutor = exec.coinbaseTxExec
-	// case *types.SlashTx:
-	// 	txExecutor = exec.slashTxExec
-	case *types.SendTx:
-		txExecutor = exec.sendTxExec
-	case *types.RametronStakeTx:
-		txExecutor = exec.rametronStakeTxExec
-	case *types.ReserveFundTx:
-		txExecutor = exec.reserveFundTxExec
-	case *types.ReleaseFundTx:
-		txExecutor = exec.releaseFundTxExec
-	case *types.ServicePaymentTx:
-		txExecutor = exec.servicePaymentTxExec
-	case *types.SplitRuleTx:
-		txExecutor = exec.splitRuleTxExec
-	case *types.SmartContractTx:
-		txExecutor = exec.smartContractTxExec
-	case *types.DepositStakeTx:
-		txExecutor = exec.depositStakeTxExec
-	case *types.WithdrawStakeTx:
-		txExecutor = exec.withdrawStakeTxExec
-	case *types.DepositStakeTxV2:
-		txExecutor = exec.depositStakeTxExec
-	default:
-		txExecutor = nil
+	// Only the slashes for double signing are enabled
+	if slashTx, ok := tx.(*types.SlashTx); ok && slashTx.DoubleSign == nil {
+		return nil
 	}
-	return txExecutor
-}
 
+	txType, err := types.GetTxType(tx)
+	if err != nil {
+		return nil
+	}
+	return exec.txExecutors[txType]
+}
@@ -0,0 +1,154 @@
+package execution
+
+import (
+	"sync"
+
+	"github.com/pandotoken/pando/common/result"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+// maxSpeculativeBatchSize is the maximum number of consecutive transactions executed speculatively at once
+const maxSpeculativeBatchSize = 256
+
+// ExecuteTxs executes the transactions of a block on the delivered view in order, and returns the index of
+// the first failed transaction along with its result, or len(txs) and OK if all of them succeeded.
+//
+// With more than one worker, each run of consecutive SendTx and SmartContractTx transactions is executed
+// speculatively in parallel, each transaction on its own copy of the view, and the changes are merged in the
+// block order. A transaction which read any key written by a preceding transaction of the run, or which
+// failed speculatively, is executed again serially on top of the merged changes. Hence the resulting state
+// is the same as the one of the serial execution. So are the receipts, which the speculative executions only
+// record once merged, see StoreView.RunSideEffect.
+func (exec *Executor) ExecuteTxs(txs []types.Tx, workers int) (int, result.Result) {
+	if workers <= 1 || exec.txExecutors[types.TxSmartContract].(*SmartContractTxExecutor).tracer != nil {
+		return exec.executeTxsSerially(txs, 0, len(txs))
+	}
+
+	view := exec.state.Delivered()
+	numSpeculated, numReexecuted := 0, 0
+	for start := 0; start < len(txs); {
+		end := start
+		for end < len(txs) && end-start < maxSpeculativeBatchSize && isParallelizable(txs[end]) {
+			end++
+		}
+		if end-start <= 1 {
+			end = start + 1
+			if idx, res := exec.executeTxsSerially(txs, start, end); res.IsError() {
+				return idx, res
+			}
+			start = end
+			continue
+		}
+
+		reexecuted, idx, res := exec.executeTxsInParallel(view, txs, start, end, workers)
+		if res.IsError() {
+			return idx, res
+		}
+		numSpeculated += end - start
+		numReexecuted += reexecuted
+		start = end
+	}
+
+	logger.Debugf("ExecuteTxs: executed %v txs in parallel, re-executed %v conflicting txs serially", numSpeculated, numReexecuted)
+	return len(txs), result.OK
+}
+
+func (exec *Executor) executeTxsSerially(txs []types.Tx, start, end int) (int, result.Result) {
+	for idx := start; idx < end; idx++ {
+		if _, res := exec.ExecuteTx(txs[idx]); res.IsError() {
+			return idx, res
+		}
+	}
+	return end, result.OK
+}
+
+// executeTxsInParallel executes the transactions from start to end speculatively, merges the ones without
+// conflict, and re-executes the others. It returns the number of re-executed transactions, along with
+// the index and the result of the failed transaction if any.
+func (exec *Executor) executeTxsInParallel(view *st.StoreView, txs []types.Tx, start, end, workers int) (int, int, result.Result) {
+	chainID := exec.state.GetChainID()
+	specs := make([]*st.StoreView, end-start)
+	results := make([]result.Result, end-start)
+
+	jobs := make(chan int, len(specs))
+	for i := range specs {
+		spec, err := view.SpeculativeCopy()
+		if err != nil {
+			logger.Warnf("Failed to copy the view for the speculative execution: %v", err)
+			continue // executed serially below
+		}
+		specs[i] = spec
+		jobs <- i
+	}
+	close(jobs)
+
+	if workers > len(specs) {
+		workers = len(specs)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = exec.speculate(chainID, specs[i], txs[start+i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	written := st.KeySet{}
+	reexecuted := 0
+	for i, spec := range specs {
+		if spec != nil && results[i].IsOK() && !spec.Access().ConflictsWith(written) {
+			if err := view.Merge(spec); err != nil {
+				return reexecuted, start + i, result.Error("Failed to merge the speculative execution: %v", err)
+			}
+			written.Merge(spec.Access().Writes)
+			continue
+		}
+
+		access := st.NewAccessSet()
+		view.TrackAccess(access)
+		_, res := exec.ExecuteTx(txs[start+i])
+		view.TrackAccess(nil)
+		if res.IsError() {
+			return reexecuted, start + i, res
+		}
+		written.Merge(access.Writes)
+		reexecuted++
+	}
+	return reexecuted, end, result.OK
+}
+
+// speculate executes the transaction on the speculative copy of the view
+func (exec *Executor) speculate(chainID string, view *st.StoreView, tx types.Tx) (res result.Result) {
+	defer func() {
+		// The transaction might panic on a state it would never see in the serial execution, it is
+		// executed again serially in that case
+		if r := recover(); r != nil {
+			res = result.Error("Speculative execution panicked: %v", r)
+		}
+	}()
+
+	if res := exec.sanityCheck(chainID, view, tx); res.IsError() {
+		return res
+	}
+	txExecutor := exec.getTxExecutor(tx)
+	if txExecutor == nil {
+		return result.Error("Unknown tx type")
+	}
+	_, res = txExecutor.process(chainID, view, tx)
+	return res
+}
+
+// isParallelizable returns whether the transaction only accesses the state through the keys tracked by
+// the StoreView, so that its conflicts with the other transactions can be detected
+func isParallelizable(tx types.Tx) bool {
+	switch tx.(type) {
+	case *types.SendTx, *types.SmartContractTx:
+		return true
+	}
+	return false
+}
@@ -3,13 +3,19 @@ package execution
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/pandotoken/pando/blockchain"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/ledger/types"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetInputs(t *testing.T) {
@@ -134,12 +140,12 @@ func TestValidateInputsAdvanced(t *testing.T) {
 	signBytes := tx.SignBytes(et.chainID)
 
 	//test bad case, unsigned
-	totalCoins, res := validateInputsAdvanced(accMap, signBytes, tx.Inputs)
+	totalCoins, res := validateInputsAdvanced(accMap, signBytes, nil, tx.Inputs)
 	assert.True(res.IsError(), "validateInputsAdvanced: expected an error on an unsigned tx input")
 
 	//test good case sgined
 	et.signSendTx(tx, accIn1, accIn2, accIn3, et.accOut)
-	totalCoins, res = validateInputsAdvanced(accMap, signBytes, tx.Inputs)
+	totalCoins, res = validateInputsAdvanced(accMap, signBytes, nil, tx.Inputs)
 	assert.True(res.IsOK(), "validateInputsAdvanced: expected no error on good tx input. Error: %v", res.Message)
 
 	txTotalCoins := tx.Inputs[0].Coins.
@@ -161,25 +167,25 @@ func TestValidateInputAdvanced(t *testing.T) {
 	signBytes := tx.SignBytes(et.chainID)
 
 	//unsigned case
-	res := validateInputAdvanced(&et.accIn.Account, signBytes, tx.Inputs[0])
+	res := validateInputAdvanced(&et.accIn.Account, signBytes, nil, tx.Inputs[0])
 	assert.True(res.IsError(), "validateInputAdvanced: expected error on tx input without signature")
 
 	//good signed case
 	et.signSendTx(tx, et.accIn, et.accOut)
-	res = validateInputAdvanced(&et.accIn.Account, signBytes, tx.Inputs[0])
+	res = validateInputAdvanced(&et.accIn.Account, signBytes, nil, tx.Inputs[0])
 	assert.True(res.IsOK(), "validateInputAdvanced: expected no error on good tx input. Error: %v", res.Message)
 
 	//bad sequence case
 	et.accIn.Sequence = 1
 	et.signSendTx(tx, et.accIn, et.accOut)
-	res = validateInputAdvanced(&et.accIn.Account, signBytes, tx.Inputs[0])
+	res = validateInputAdvanced(&et.accIn.Account, signBytes, nil, tx.Inputs[0])
 	assert.Equal(result.CodeInvalidSequence, res.Code, "validateInputAdvanced: expected error on tx input with bad sequence")
 	et.accIn.Sequence = 0 //restore sequence
 
 	//bad balance case
 	et.accIn.Balance = types.NewCoins(2, 0)
 	et.signSendTx(tx, et.accIn, et.accOut)
-	res = validateInputAdvanced(&et.accIn.Account, signBytes, tx.Inputs[0])
+	res = validateInputAdvanced(&et.accIn.Account, signBytes, nil, tx.Inputs[0])
 	assert.Equal(result.CodeInsufficientFund, res.Code,
 		"validateInputAdvanced: expected error on tx input with insufficient funds %v", et.accIn.Sequence)
 }
@@ -281,6 +287,169 @@ func TestSendTx(t *testing.T) {
 		"ExecTx/good DeliverTx: unexpected change in output balance, got: %v, expected: %v", balOut, balOutExp)
 }
 
+func TestSendTxData(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	// Plain accounts, not smart contracts
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn)
+	et.acc2State(et.accOut)
+
+	coins := types.NewCoins(1000, 20)
+	sendTx := &types.SendTx{
+		Inputs: []types.TxInput{
+			types.TxInput{
+				Address:  et.accIn.Address,
+				Sequence: et.accIn.Sequence + 1,
+			},
+		},
+		Outputs: []types.TxOutput{
+			types.TxOutput{
+				Address: et.accOut.Address,
+				Coins:   coins,
+			},
+		},
+		Data: common.Bytes("exchange-deposit-id-42"),
+	}
+	sign := func(fee types.Coins) {
+		sendTx.Fee = fee
+		sendTx.Inputs[0].Coins = coins.Plus(fee)
+		sendTx.Inputs[0].Signature = et.accIn.Sign(sendTx.SignBytes(et.chainID))
+	}
+
+	// The minimum fee of a transaction without data is not enough
+	sign(types.NewCoins(0, getMinimumTxFee()))
+	_, res := et.executor.ScreenTx(sendTx)
+	assert.True(res.IsError())
+	assert.Equal(result.CodeInvalidFee, res.Code)
+
+	sign(types.Coins{PandoWei: big.NewInt(0), PTXWei: sendTx.MinimumFee()})
+	_, res = et.executor.ScreenTx(sendTx)
+	assert.True(res.IsOK(), res.String())
+
+	// Data too long
+	data := sendTx.Data
+	sendTx.Data = make(common.Bytes, types.MaximumSendTxDataLength+1)
+	sign(types.Coins{PandoWei: big.NewInt(0), PTXWei: sendTx.MinimumFee()})
+	_, res = et.executor.ScreenTx(sendTx)
+	assert.True(res.IsError())
+
+	sendTx.Data = data
+	sign(types.Coins{PandoWei: big.NewInt(0), PTXWei: sendTx.MinimumFee()})
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.True(res.IsOK(), res.String())
+	assert.Equal(et.accOut.Balance.Plus(coins), et.state().Delivered().GetAccount(et.accOut.Address).Balance)
+}
+
+func TestSendTxValidUntilBlock(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn)
+	et.acc2State(et.accOut)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	coins := types.NewCoins(1000, 20)
+	validUntil := et.state().Height() + 5
+	sendTx := &types.SendTx{
+		Fee: fee,
+		Inputs: []types.TxInput{
+			types.TxInput{
+				Address:  et.accIn.Address,
+				Coins:    coins.Plus(fee),
+				Sequence: et.accIn.Sequence + 1,
+			},
+		},
+		Outputs: []types.TxOutput{
+			types.TxOutput{
+				Address: et.accOut.Address,
+				Coins:   coins,
+			},
+		},
+		ValidUntilBlock: validUntil,
+	}
+	sendTx.Inputs[0].Signature = et.accIn.Sign(sendTx.SignBytes(et.chainID))
+
+	// The height is covered by the signature
+	sendTx.ValidUntilBlock = validUntil + 1
+	_, res := et.executor.ScreenTx(sendTx)
+	assert.True(res.IsError())
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+	sendTx.ValidUntilBlock = validUntil
+
+	_, res = et.executor.ScreenTx(sendTx)
+	assert.True(res.IsOK(), res.String())
+
+	// The tx can still be included in the block at ValidUntilBlock
+	et.fastforwardTo(validUntil - 1)
+	_, res = et.executor.ScreenTx(sendTx)
+	assert.True(res.IsOK(), res.String())
+
+	// and is rejected afterwards
+	et.fastforwardTo(validUntil)
+	_, res = et.executor.ScreenTx(sendTx)
+	assert.True(res.IsError())
+	assert.Equal(result.CodeTxExpired, res.Code)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.True(res.IsError())
+	assert.Equal(result.CodeTxExpired, res.Code)
+	assert.Equal(et.accOut.Balance, et.state().Delivered().GetAccount(et.accOut.Address).Balance)
+}
+
+func TestDynamicBaseFee(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(map[string]uint64{features.DynamicBaseFee: 0}))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn)
+	et.acc2State(et.accOut)
+
+	baseFee := new(big.Int).Mul(types.MinimumBaseFee(), big.NewInt(2))
+	et.state().Delivered().SetBaseFee(baseFee)
+	et.fastforwardBy(1)
+
+	coins := types.NewCoins(1000, 20)
+	makeSendTx := func(fee types.Coins) *types.SendTx {
+		sendTx := &types.SendTx{
+			Fee: fee,
+			Inputs: []types.TxInput{
+				types.TxInput{
+					Address:  et.accIn.Address,
+					Coins:    coins.Plus(fee),
+					Sequence: et.accIn.Sequence + 1,
+				},
+			},
+			Outputs: []types.TxOutput{
+				types.TxOutput{
+					Address: et.accOut.Address,
+					Coins:   coins,
+				},
+			},
+		}
+		sendTx.Inputs[0].Signature = et.accIn.Sign(sendTx.SignBytes(et.chainID))
+		return sendTx
+	}
+
+	// The fixed minimum fee is below the base fee
+	_, res := et.executor.ScreenTx(makeSendTx(types.NewCoins(0, getMinimumTxFee())))
+	assert.True(res.IsError())
+	assert.Equal(result.CodeInvalidFee, res.Code)
+
+	_, res = et.executor.ScreenTx(makeSendTx(types.Coins{PandoWei: big.NewInt(0), PTXWei: baseFee}))
+	assert.True(res.IsOK(), res.String())
+}
+
 func TestSendDuplicatedInputOutput(t *testing.T) {
 	assert := assert.New(t)
 	et := NewExecTest()
@@ -783,6 +952,8 @@ func TestReleaseFundTx(t *testing.T) {
 
 func TestServicePaymentTxNormalExecutionAndSlash(t *testing.T) {
 	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
 	et, resourceID, alice, bob, carol, _, bobInitBalance, carolInitBalance := setupForServicePayment(assert)
 	et.state().Commit()
 
@@ -818,7 +989,8 @@ func TestServicePaymentTxNormalExecutionAndSlash(t *testing.T) {
 	payAmount2 := int64(50 * txFee)
 	srcSeq, tgtSeq, paymentSeq, reserveSeq = 1, 2, 2, 1
 	_ = createServicePaymentTx(et.chainID, &alice, &bob, 30*txFee, srcSeq, tgtSeq, paymentSeq, reserveSeq, resourceID)
-	servicePaymentTx2 := createServicePaymentTx(et.chainID, &alice, &bob, payAmount2, srcSeq, tgtSeq, paymentSeq, reserveSeq, resourceID)
+	// The payments to a target settle the cumulative amount paid to it
+	servicePaymentTx2 := createServicePaymentTx(et.chainID, &alice, &bob, payAmount1+payAmount2, srcSeq, tgtSeq, paymentSeq, reserveSeq, resourceID)
 	res = et.executor.getTxExecutor(servicePaymentTx2).sanityCheck(et.chainID, et.state().Delivered(), servicePaymentTx2)
 	assert.True(res.IsOK(), res.Message)
 	_, res = et.executor.getTxExecutor(servicePaymentTx2).process(et.chainID, et.state().Delivered(), servicePaymentTx2)
@@ -908,6 +1080,52 @@ func TestServicePaymentTxExpiration(t *testing.T) {
 	log.Infof("Service payment check message: %v", res.Message)
 }
 
+func TestServicePaymentTxDisputeWindow(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et, resourceID, alice, bob, _, _, bobInitBalance, _ := setupForServicePayment(assert)
+	et.state().Commit()
+
+	txFee := getMinimumTxFee()
+	settle := func(tx *types.ServicePaymentTx) result.Result {
+		res := et.executor.getTxExecutor(tx).sanityCheck(et.chainID, et.state().Delivered(), tx)
+		if res.IsError() {
+			return res
+		}
+		_, res = et.executor.getTxExecutor(tx).process(et.chainID, et.state().Delivered(), tx)
+		et.state().Commit()
+		return res
+	}
+
+	// A stale voucher is settled first
+	res := settle(createServicePaymentTx(et.chainID, &alice, &bob, 50*txFee, 1, 1, 1, 1, resourceID))
+	assert.True(res.IsOK(), res.Message)
+
+	// Bob overrides the settlement with the latest voucher, and is only paid the difference
+	res = settle(createServicePaymentTx(et.chainID, &alice, &bob, 80*txFee, 1, 2, 3, 1, resourceID))
+	assert.True(res.IsOK(), res.Message)
+	aliceAcc := et.state().Delivered().GetAccount(alice.Address)
+	assert.Equal(types.Coins{PTXWei: big.NewInt(80 * txFee), PandoWei: big.NewInt(0)}, aliceAcc.ReservedFunds[0].UsedFund)
+	settlement := aliceAcc.ReservedFunds[0].GetSettlement(bob.Address)
+	assert.NotNil(settlement)
+	assert.Equal(uint64(3), settlement.PaymentSequence)
+	bobAcc := et.state().Delivered().GetAccount(bob.Address)
+	assert.Equal(bobInitBalance.Plus(types.Coins{PTXWei: big.NewInt(80*txFee - 2*txFee)}), bobAcc.Balance)
+
+	// The latest payment sequence wins, and the cumulative amount cannot decrease
+	res = settle(createServicePaymentTx(et.chainID, &alice, &bob, 90*txFee, 1, 3, 2, 1, resourceID))
+	assert.False(res.IsOK())
+	res = settle(createServicePaymentTx(et.chainID, &alice, &bob, 70*txFee, 1, 3, 4, 1, resourceID))
+	assert.False(res.IsOK())
+
+	// The settlement is final once the dispute window closes
+	et.fastforwardBy(types.ServicePaymentDisputeWindow + 1)
+	res = settle(createServicePaymentTx(et.chainID, &alice, &bob, 100*txFee, 1, 3, 5, 1, resourceID))
+	assert.False(res.IsOK())
+	assert.Equal(result.CodeCheckTransferReservedFundFailed, res.Code)
+}
+
 // func TestSlashTx(t *testing.T) {
 // 	assert := assert.New(t)
 // 	et, resourceID, alice, bob, _, _, _, _ := setupForServicePayment(assert)
@@ -1228,7 +1446,7 @@ func TestSplitPaymentInternalMethod(t *testing.T) {
 		EndBlockHeight:   uint64(99999),
 	}
 
-	exec := NewServicePaymentTxExecutor(et.state())
+	exec := NewServicePaymentTxExecutor(et.executor.chain, et.state())
 	fullAmount := types.NewCoins(0, 10000)
 
 	// carol is the target account
@@ -2313,3 +2531,1560 @@ func TestSplitRuleZeroDuration(t *testing.T) {
 	assert.Nil(retrievedSplitRule2ndTime) // Should be expired and got deleted
 }
 
+
+func TestSessionKeyTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut)
+
+	sessionKey := types.MakeAcc("session_key")
+	accOther := types.MakeAcc("other")
+	accOther.CodeHash = types.EmptyCodeHash
+
+	makeSessionKeyTx := func(seq uint64, expiry uint64, limit types.Coins) *types.SessionKeyTx {
+		tx := &types.SessionKeyTx{
+			Fee: types.NewCoins(0, getMinimumTxFee()),
+			Account: types.TxInput{
+				Address:  et.accIn.Address,
+				Sequence: seq,
+			},
+			SessionKey:    sessionKey.Address,
+			TxTypes:       []types.TxType{types.TxSend},
+			Destinations:  []common.Address{et.accOut.Address},
+			SpendingLimit: limit,
+			ExpiryHeight:  expiry,
+		}
+		tx.SetSignature(et.accIn.Address, et.accIn.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+
+	// A session key cannot register itself
+	skTx := makeSessionKeyTx(1, 100, types.NewCoins(8, 2*getMinimumTxFee()))
+	skTx.SetSignature(et.accIn.Address, sessionKey.Sign(skTx.SignBytes(et.chainID)))
+	_, res := et.executor.ExecuteTx(skTx)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+
+	skTx = makeSessionKeyTx(1, 100, types.NewCoins(8, 2*getMinimumTxFee()))
+	_, res = et.executor.ExecuteTx(skTx)
+	assert.True(res.IsOK(), res.Message)
+	assert.Equal(1, et.state().Delivered().GetSessionKeys(et.accIn.Address).Len())
+
+	// Send signed by the session key
+	sendTx := types.MakeSendTx(2, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, sessionKey)
+	res, balIn, balInExp, balOut, balOutExp := et.execSendTx(sendTx, false)
+	assert.True(res.IsOK(), res.Message)
+	assert.True(balIn.IsEqual(balInExp))
+	assert.True(balOut.IsEqual(balOutExp))
+	sk := et.state().Delivered().GetSessionKeys(et.accIn.Address).Get(sessionKey.Address)
+	assert.True(sk.Spent.IsEqual(sendTx.Inputs[0].Coins))
+
+	// Destination not permitted
+	sendTx = types.MakeSendTx(3, accOther, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, sessionKey)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.Equal(result.CodeSessionKeyNotPermitted, res.Code)
+
+	// Spending limit exceeded
+	sendTx = types.MakeSendTx(3, et.accOut, et.accIn)
+	sendTx.Inputs[0].Coins = types.NewCoins(5, getMinimumTxFee())
+	sendTx.Outputs[0].Coins = types.NewCoins(5, 0)
+	types.SignSendTx(et.chainID, sendTx, sessionKey)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.Equal(result.CodeSessionKeyNotPermitted, res.Code)
+
+	// Within the remaining limit
+	sendTx = types.MakeSendTx(3, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, sessionKey)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.True(res.IsOK(), res.Message)
+
+	// Revoke the session key
+	skTx = makeSessionKeyTx(4, 0, types.NewCoins(0, 0))
+	_, res = et.executor.ExecuteTx(skTx)
+	assert.True(res.IsOK(), res.Message)
+	assert.Equal(0, et.state().Delivered().GetSessionKeys(et.accIn.Address).Len())
+
+	sendTx = types.MakeSendTx(5, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, sessionKey)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+}
+
+func TestTypedSignedSendTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut)
+
+	tx := types.MakeSendTx(1, et.accOut, et.accIn)
+	typedSignBytes, err := types.TypedSignBytes(et.chainID, tx)
+	assert.Nil(err)
+
+	// Signed over the typed sign bytes of another chain
+	otherSignBytes, err := types.TypedSignBytes("other_chain_id", tx)
+	assert.Nil(err)
+	tx.SetSignature(et.accIn.Address, et.accIn.Sign(otherSignBytes))
+	res, _, _, _, _ := et.execSendTx(tx, true)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+
+	tx.SetSignature(et.accIn.Address, et.accIn.Sign(typedSignBytes))
+	res, balIn, balInExp, balOut, balOutExp := et.execSendTx(tx, false)
+	assert.True(res.IsOK(), res.Message)
+	assert.True(balIn.IsEqual(balInExp))
+	assert.True(balOut.IsEqual(balOutExp))
+}
+
+func TestCanonicalSignedSendTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut)
+
+	tx := types.MakeSendTx(1, et.accOut, et.accIn)
+	canonicalSignBytes, err := types.CanonicalSignBytes(et.chainID, tx)
+	assert.Nil(err)
+
+	// Signed over the canonical sign bytes of another chain
+	otherSignBytes, err := types.CanonicalSignBytes("other_chain_id", tx)
+	assert.Nil(err)
+	tx.SetSignature(et.accIn.Address, et.accIn.Sign(otherSignBytes))
+	res, _, _, _, _ := et.execSendTx(tx, true)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+
+	tx.SetSignature(et.accIn.Address, et.accIn.Sign(canonicalSignBytes))
+	res, balIn, balInExp, balOut, balOutExp := et.execSendTx(tx, false)
+	assert.True(res.IsOK(), res.Message)
+	assert.True(balIn.IsEqual(balInExp))
+	assert.True(balOut.IsEqual(balOutExp))
+}
+
+func TestMultiSigSendTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	require := require.New(t)
+
+	signer1 := types.MakeAcc("signer1")
+	signer2 := types.MakeAcc("signer2")
+	signer3 := types.MakeAcc("signer3")
+	signers := []common.Address{signer1.Address, signer2.Address, signer3.Address}
+
+	et := NewExecTest()
+	et.accIn.Address = types.MultiSigAddress(2, signers)
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut)
+
+	makeMultiSigSendTx := func(seq int, cosigners ...types.PrivAccount) *types.SendTx {
+		tx := types.MakeSendTx(seq, et.accOut, et.accIn)
+		signBytes := tx.SignBytes(et.chainID)
+		ms := types.NewMultiSigSignature(2, signers)
+		for _, cosigner := range cosigners {
+			require.Nil(ms.AddSignature(signBytes, cosigner.Sign(signBytes)))
+		}
+		tx.Inputs[0].MultiSig = ms
+		return tx
+	}
+
+	// Below the threshold
+	tx := makeMultiSigSendTx(1, signer2)
+	res, _, _, _, _ := et.execSendTx(tx, true)
+	assert.True(res.IsError())
+
+	// Signed by a non-signer
+	tx = makeMultiSigSendTx(1, signer2)
+	signBytes := tx.SignBytes(et.chainID)
+	tx.Inputs[0].MultiSig.Signatures = append(tx.Inputs[0].MultiSig.Signatures, et.accOut.Sign(signBytes))
+	res, _, _, _, _ = et.execSendTx(tx, true)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+
+	// A single signature is not accepted for a multi-signature account
+	tx = makeMultiSigSendTx(1, signer1, signer3)
+	tx.Inputs[0].Signature = signer1.Sign(signBytes)
+	res, _, _, _, _ = et.execSendTx(tx, true)
+	assert.Equal(result.CodeInvalidMultiSig, res.Code)
+
+	tx = makeMultiSigSendTx(1, signer1, signer3)
+	res, balIn, balInExp, balOut, balOutExp := et.execSendTx(tx, false)
+	assert.True(res.IsOK(), res.Message)
+	assert.True(balIn.IsEqual(balInExp))
+	assert.True(balOut.IsEqual(balOutExp))
+
+	// Multi-signature inputs are not supported by the other transaction types
+	skTx := &types.SessionKeyTx{
+		Fee: types.NewCoins(0, getMinimumTxFee()),
+		Account: types.TxInput{
+			Address:  et.accIn.Address,
+			Sequence: 2,
+		},
+		SessionKey:    signer1.Address,
+		TxTypes:       []types.TxType{types.TxSend},
+		Destinations:  []common.Address{},
+		SpendingLimit: types.NewCoins(0, getMinimumTxFee()),
+		ExpiryHeight:  100,
+	}
+	skSignBytes := skTx.SignBytes(et.chainID)
+	skTx.Account.MultiSig = types.NewMultiSigSignature(2, signers)
+	require.Nil(skTx.Account.MultiSig.AddSignature(skSignBytes, signer1.Sign(skSignBytes)))
+	require.Nil(skTx.Account.MultiSig.AddSignature(skSignBytes, signer2.Sign(skSignBytes)))
+	_, res = et.executor.ExecuteTx(skTx)
+	assert.Equal(result.CodeMultiSigNotSupported, res.Code)
+}
+
+func TestBatchSendTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	// Plain accounts, not smart contracts
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn)
+	et.acc2State(et.accOut)
+	accOut2 := types.MakeAcc("baz")
+
+	c1 := types.NewCoins(1000, 0)
+	c2 := types.NewCoins(2000, 300)
+	batchSendTx := &types.BatchSendTx{
+		Inputs: []types.TxInput{
+			types.TxInput{
+				Address:  et.accIn.Address,
+				Sequence: et.accIn.Sequence + 1,
+			},
+		},
+		Outputs: []types.BatchOutput{
+			types.BatchOutput{
+				Address: et.accOut.Address,
+				Coins:   c1,
+				Memo:    "invoice #1",
+			},
+			types.BatchOutput{
+				Address: accOut2.Address,
+				Coins:   c2,
+				Memo:    "invoice #2",
+			},
+		},
+	}
+	sign := func() {
+		batchSendTx.Inputs[0].Coins = c1.Plus(c2).Plus(batchSendTx.Fee)
+		batchSendTx.Inputs[0].Signature = et.accIn.Sign(batchSendTx.SignBytes(et.chainID))
+	}
+
+	// Fee below the per-output minimum
+	batchSendTx.Fee = types.NewCoins(0, getMinimumTxFee())
+	sign()
+	_, res := et.executor.ScreenTx(batchSendTx)
+	assert.True(res.IsError())
+	assert.Equal(result.CodeInvalidFee, res.Code)
+
+	// Memo too long
+	batchSendTx.Fee = types.Coins{PandoWei: big.NewInt(0), PTXWei: batchSendTx.MinimumFee()}
+	batchSendTx.Outputs[1].Memo = strings.Repeat("x", types.MaximumBatchSendTxMemoLength+1)
+	sign()
+	_, res = et.executor.ScreenTx(batchSendTx)
+	assert.True(res.IsError())
+
+	batchSendTx.Outputs[1].Memo = "invoice #2"
+	sign()
+	_, res = et.executor.ScreenTx(batchSendTx)
+	assert.True(res.IsOK(), res.String())
+
+	accInBal0 := et.accIn.Balance
+	accOutBal0 := et.accOut.Balance
+	_, res = et.executor.ExecuteTx(batchSendTx)
+	assert.True(res.IsOK(), res.String())
+
+	view := et.state().Delivered()
+	assert.Equal(accInBal0.Minus(c1).Minus(c2).Minus(batchSendTx.Fee), view.GetAccount(et.accIn.Address).Balance)
+	assert.Equal(accOutBal0.Plus(c1), view.GetAccount(et.accOut.Address).Balance)
+	assert.Equal(c2, view.GetAccount(accOut2.Address).Balance)
+
+	// Replay
+	_, res = et.executor.ScreenTx(batchSendTx)
+	assert.True(res.IsError())
+}
+
+func TestSlashAppealTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	ptx := func(amount int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+	}
+	appellant := types.MakeAccWithInitBalance("appellant", types.Coins{PandoWei: big.NewInt(0), PTXWei: ptx(100)})
+	appellant.CodeHash = types.EmptyCodeHash
+	val1, val2, val3 := types.MakeAcc("val_1"), types.MakeAcc("val_2"), types.MakeAcc("val_3")
+	val1.CodeHash, val2.CodeHash, val3.CodeHash = types.EmptyCodeHash, types.EmptyCodeHash, types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(appellant, val1, val2, val3, et.accOut)
+
+	// val1 holds half of the stake, hence two validators need to approve an appeal
+	vcp := &core.ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(val1.Address, val1.Address, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(2))))
+	assert.Nil(vcp.DepositStake(val2.Address, val2.Address, core.MinValidatorStakeDeposit))
+	assert.Nil(vcp.DepositStake(val3.Address, val3.Address, core.MinValidatorStakeDeposit))
+	et.state().Delivered().UpdateValidatorCandidatePool(vcp)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	bond := types.Coins{PandoWei: big.NewInt(0), PTXWei: ptx(10)}
+	makeAppealTx := func(seq uint64, bond types.Coins) *types.SlashAppealTx {
+		tx := &types.SlashAppealTx{
+			Fee:             fee,
+			Appellant:       types.TxInput{Address: appellant.Address, Sequence: seq},
+			ReserveSequence: 3,
+			Bond:            bond,
+		}
+		tx.SetSignature(appellant.Address, appellant.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeVoteTx := func(voter types.PrivAccount, seq uint64) *types.SlashAppealVoteTx {
+		tx := &types.SlashAppealVoteTx{
+			Fee:             fee,
+			Voter:           types.TxInput{Address: voter.Address, Sequence: seq},
+			Appellant:       appellant.Address,
+			ReserveSequence: 3,
+		}
+		tx.SetSignature(voter.Address, voter.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+
+	// No slash to appeal
+	_, res := et.executor.ExecuteTx(makeAppealTx(1, bond))
+	assert.Equal(result.CodeInvalidSlashAppeal, res.Code)
+
+	slashed := types.Coins{PandoWei: big.NewInt(0), PTXWei: ptx(50)}
+	view := et.state().Delivered()
+	view.SetSlashRecord(&types.SlashRecord{
+		SlashedAddress:  appellant.Address,
+		ReserveSequence: 3,
+		Amount:          slashed,
+		Beneficiary:     val1.Address,
+		Height:          view.Height(),
+	})
+
+	// Bond too small
+	_, res = et.executor.ExecuteTx(makeAppealTx(1, types.NewCoins(0, 1000)))
+	assert.Equal(result.CodeInvalidSlashAppeal, res.Code)
+
+	_, res = et.executor.ExecuteTx(makeAppealTx(1, bond))
+	assert.True(res.IsOK(), res.Message)
+	view = et.state().Delivered()
+	assert.Equal(appellant.Balance.Minus(fee).Minus(bond), view.GetAccount(appellant.Address).Balance)
+	assert.Equal(1, view.GetSlashAppeals().Len())
+
+	// Already appealed
+	_, res = et.executor.ExecuteTx(makeAppealTx(2, bond))
+	assert.Equal(result.CodeInvalidSlashAppeal, res.Code)
+
+	// Only the validators can vote, and only once
+	_, res = et.executor.ExecuteTx(makeVoteTx(et.accOut, 1))
+	assert.Equal(result.CodeInvalidSlashAppeal, res.Code)
+	_, res = et.executor.ExecuteTx(makeVoteTx(val1, 1))
+	assert.True(res.IsOK(), res.Message)
+	_, res = et.executor.ExecuteTx(makeVoteTx(val1, 2))
+	assert.Equal(result.CodeInvalidSlashAppeal, res.Code)
+	assert.Equal([]common.Address{val1.Address}, et.state().Delivered().GetSlashAppeals().Get(appellant.Address, 3).Approvals)
+
+	// The approval needs the insurance pool to reimburse the slashed amount
+	_, res = et.executor.ExecuteTx(makeVoteTx(val2, 1))
+	assert.Equal(result.CodeInsufficientInsurancePoolFund, res.Code)
+
+	pool := types.NewAccount(types.SlashInsurancePoolAddress)
+	pool.Balance = types.Coins{PandoWei: big.NewInt(0), PTXWei: ptx(80)}
+	et.state().Delivered().SetAccount(types.SlashInsurancePoolAddress, pool)
+
+	_, res = et.executor.ExecuteTx(makeVoteTx(val2, 1))
+	assert.True(res.IsOK(), res.Message)
+	view = et.state().Delivered()
+	assert.Equal(appellant.Balance.Minus(fee).Plus(slashed), view.GetAccount(appellant.Address).Balance)
+	assert.Equal(pool.Balance.Minus(slashed), view.GetAccount(types.SlashInsurancePoolAddress).Balance)
+	assert.Equal(0, view.GetSlashAppeals().Len())
+	assert.Nil(view.GetSlashRecord(appellant.Address, 3))
+
+	// The slash cannot be appealed again
+	_, res = et.executor.ExecuteTx(makeVoteTx(val3, 1))
+	assert.Equal(result.CodeInvalidSlashAppeal, res.Code)
+	_, res = et.executor.ExecuteTx(makeAppealTx(2, bond))
+	assert.Equal(result.CodeInvalidSlashAppeal, res.Code)
+}
+
+func TestClaimEscrowTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut)
+
+	// The escrow pays accOut against the preimage and its signature, or refunds accIn after a time lock
+	preimage := common.Bytes("secret")
+	timeLock := et.state().Height() + 100
+	clauses := []types.EscrowClause{
+		types.EscrowClause{
+			Recipient: et.accOut.Address,
+			Threshold: 1,
+			Signers:   []common.Address{et.accOut.Address},
+			HashLock:  types.HashLockOf(preimage),
+		},
+		types.EscrowClause{
+			Recipient: et.accIn.Address,
+			TimeLock:  timeLock,
+		},
+	}
+	escrowAddr := types.EscrowAddress(clauses)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	coins := types.NewCoins(1000, 5*getMinimumTxFee())
+	escrowAcc := types.NewAccount(escrowAddr)
+	escrowAcc.Balance = coins.Plus(fee).Plus(coins).Plus(fee)
+	et.state().Delivered().SetAccount(escrowAddr, escrowAcc)
+	et.state().Commit()
+
+	makeClaimTx := func(seq uint64, clauseIndex uint64, preimage common.Bytes, signer *types.PrivAccount) *types.ClaimEscrowTx {
+		tx := &types.ClaimEscrowTx{
+			Fee:         fee,
+			Escrow:      types.TxInput{Address: escrowAddr, Coins: coins, Sequence: seq},
+			Clauses:     clauses,
+			ClauseIndex: clauseIndex,
+			Preimage:    preimage,
+		}
+		if signer != nil {
+			tx.SetSignature(signer.Address, signer.Sign(tx.SignBytes(et.chainID)))
+		}
+		return tx
+	}
+
+	// The refund clause is time locked
+	_, res := et.executor.ScreenTx(makeClaimTx(1, 1, nil, nil))
+	assert.Equal(result.CodeInvalidEscrowClaim, res.Code)
+
+	// Wrong preimage, or missing signature
+	_, res = et.executor.ScreenTx(makeClaimTx(1, 0, common.Bytes("guess"), &et.accOut))
+	assert.Equal(result.CodeInvalidEscrowClaim, res.Code)
+	_, res = et.executor.ScreenTx(makeClaimTx(1, 0, preimage, nil))
+	assert.Equal(result.CodeInvalidEscrowClaim, res.Code)
+	_, res = et.executor.ScreenTx(makeClaimTx(1, 0, preimage, &et.accIn))
+	assert.Equal(result.CodeInvalidEscrowClaim, res.Code)
+
+	// The clauses cannot be altered, since they determine the escrow address
+	tampered := makeClaimTx(1, 0, preimage, nil)
+	tampered.Clauses = []types.EscrowClause{clauses[0], types.EscrowClause{Recipient: et.accOut.Address, TimeLock: 1}}
+	tampered.ClauseIndex = 1
+	_, res = et.executor.ScreenTx(tampered)
+	assert.Equal(result.CodeInvalidEscrowClaim, res.Code)
+
+	claimTx := makeClaimTx(1, 0, preimage, &et.accOut)
+	_, res = et.executor.ScreenTx(claimTx)
+	assert.True(res.IsOK(), res.String())
+	_, res = et.executor.ExecuteTx(claimTx)
+	assert.True(res.IsOK(), res.String())
+
+	view := et.state().Delivered()
+	assert.Equal(et.accOut.Balance.Plus(coins), view.GetAccount(et.accOut.Address).Balance)
+	assert.Equal(coins.Plus(fee), view.GetAccount(escrowAddr).Balance)
+
+	// Replay
+	_, res = et.executor.ScreenTx(claimTx)
+	assert.True(res.IsError())
+
+	// The refund clause can be claimed by anyone once the time lock has passed
+	et.fastforwardTo(timeLock)
+	refundTx := makeClaimTx(2, 1, nil, nil)
+	_, res = et.executor.ExecuteTx(refundTx)
+	assert.True(res.IsOK(), res.String())
+
+	view = et.state().Delivered()
+	assert.Equal(et.accIn.Balance.Plus(coins), view.GetAccount(et.accIn.Address).Balance)
+	assert.True(view.GetAccount(escrowAddr).Balance.IsZero())
+}
+
+func TestMeteredSettlementTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et, resourceID, alice, bob, _, _, bobInitBalance, _ := setupForServicePayment(assert)
+	et.state().Commit()
+
+	txFee := getMinimumTxFee()
+	pricePerGB := types.NewCoins(0, 100*txFee)
+	makeRecord := func(seq uint64, bytes uint64) types.MeteringRecord {
+		record := types.MeteringRecord{
+			Client:          alice.Address,
+			EdgeNode:        bob.Address,
+			ReserveSequence: 1,
+			ResourceID:      resourceID,
+			Sequence:        seq,
+			Bytes:           bytes,
+			PricePerGB:      pricePerGB,
+		}
+		record.ClientSignature = alice.Sign(record.SignBytes(et.chainID))
+		record.EdgeNodeSignature = bob.Sign(record.SignBytes(et.chainID))
+		return record
+	}
+	makeSettlementTx := func(seq uint64, records ...types.MeteringRecord) *types.MeteredSettlementTx {
+		tx := &types.MeteredSettlementTx{
+			Fee:             types.NewCoins(0, txFee),
+			EdgeNode:        types.TxInput{Address: bob.Address, Sequence: seq},
+			Client:          alice.Address,
+			ReserveSequence: 1,
+			ResourceID:      resourceID,
+			Records:         records,
+		}
+		tx.SetSignature(bob.Address, bob.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+
+	// A record needs to be signed by the client
+	forged := makeRecord(1, 2*types.BytesPerGB)
+	forged.ClientSignature = bob.Sign(forged.SignBytes(et.chainID))
+	_, res := et.executor.ScreenTx(makeSettlementTx(1, forged))
+	assert.Equal(result.CodeInvalidMeteringRecord, res.Code)
+
+	// The records need to be in order
+	_, res = et.executor.ScreenTx(makeSettlementTx(1, makeRecord(2, types.BytesPerGB), makeRecord(1, types.BytesPerGB)))
+	assert.Equal(result.CodeInvalidMeteringRecord, res.Code)
+
+	// The reserve fund of 1000 tx fees cannot cover 11 GB
+	_, res = et.executor.ScreenTx(makeSettlementTx(1, makeRecord(1, 11*types.BytesPerGB)))
+	assert.Equal(result.CodeInsufficientReservedFund, res.Code)
+
+	settlementTx := makeSettlementTx(1, makeRecord(1, 2*types.BytesPerGB), makeRecord(2, 3*types.BytesPerGB/2))
+	assert.Equal(types.NewCoins(0, 350*txFee), settlementTx.Amount())
+	_, res = et.executor.ScreenTx(settlementTx)
+	assert.True(res.IsOK(), res.String())
+	_, res = et.executor.ExecuteTx(settlementTx)
+	assert.True(res.IsOK(), res.String())
+
+	view := et.state().Delivered()
+	aliceAcc := view.GetAccount(alice.Address)
+	assert.Equal(types.NewCoins(0, 350*txFee), aliceAcc.ReservedFunds[0].UsedFund)
+	bobAcc := view.GetAccount(bob.Address)
+	assert.Equal(bobInitBalance.Plus(types.NewCoins(0, 349*txFee)), bobAcc.Balance)
+	assert.Equal(uint64(1), bobAcc.Sequence)
+
+	// The settled records cannot be settled again
+	_, res = et.executor.ScreenTx(makeSettlementTx(2, makeRecord(2, 3*types.BytesPerGB/2)))
+	assert.Equal(result.CodeCheckTransferReservedFundFailed, res.Code)
+	_, res = et.executor.ScreenTx(makeSettlementTx(2, makeRecord(3, types.BytesPerGB)))
+	assert.True(res.IsOK(), res.String())
+}
+
+func TestSetCommissionTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	validator := types.MakeAccWithInitBalance("validator", types.NewCoins(0, 50*getMinimumTxFee()))
+	validator.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(validator, et.accOut)
+
+	vcp := &core.ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(validator.Address, validator.Address, core.MinValidatorStakeDeposit))
+	et.state().Delivered().UpdateValidatorCandidatePool(vcp)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	makeTx := func(acc types.PrivAccount, seq uint64, rate uint64) *types.SetCommissionTx {
+		tx := &types.SetCommissionTx{
+			Fee:            fee,
+			Validator:      types.TxInput{Address: acc.Address, Sequence: seq},
+			CommissionRate: rate,
+		}
+		tx.SetSignature(acc.Address, acc.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+
+	// Only the validator candidates can set a commission
+	_, res := et.executor.ExecuteTx(makeTx(et.accOut, 1, 50))
+	assert.True(res.IsError())
+
+	// The rate changes by at most DefaultMaxCommissionRateChange per epoch
+	_, res = et.executor.ExecuteTx(makeTx(validator, 1, types.DefaultMaxCommissionRateChange+1))
+	assert.True(res.IsError())
+	_, res = et.executor.ExecuteTx(makeTx(validator, 1, types.DefaultMaxCommissionRateChange))
+	assert.True(res.IsOK(), res.Message)
+	params := et.state().Delivered().GetValidatorParams(validator.Address)
+	assert.NotNil(params)
+	assert.Equal(types.DefaultMaxCommissionRateChange, params.CommissionRate)
+	assert.Equal(validator.Balance.Minus(fee), et.state().Delivered().GetAccount(validator.Address).Balance)
+
+	// and at most once per epoch
+	_, res = et.executor.ExecuteTx(makeTx(validator, 2, types.DefaultMaxCommissionRateChange+10))
+	assert.True(res.IsError())
+
+	et.fastforwardBy(uint64(common.CheckpointInterval) + 1)
+	_, res = et.executor.ExecuteTx(makeTx(validator, 2, types.DefaultMaxCommissionRateChange+10))
+	assert.True(res.IsOK(), res.Message)
+	assert.Equal(types.DefaultMaxCommissionRateChange+10, et.state().Delivered().GetValidatorParams(validator.Address).CommissionRate)
+}
+
+func TestApplyValidatorCommissions(t *testing.T) {
+	assert := assert.New(t)
+	et := NewExecTest()
+	view := et.state().Delivered()
+
+	val1 := common.HexToAddress("0x111")
+	val2 := common.HexToAddress("0x222")
+	delegator := common.HexToAddress("0x333")
+
+	vcp := &core.ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(val1, val1, core.MinValidatorStakeDeposit))
+	assert.Nil(vcp.DepositStake(val2, val2, core.MinValidatorStakeDeposit))
+	assert.Nil(vcp.Delegate(delegator, val1, core.MinDelegationDeposit))
+	assert.Nil(vcp.Delegate(delegator, val2, core.MinDelegationDeposit))
+	view.UpdateValidatorCandidatePool(vcp)
+
+	valSet := core.NewValidatorSet()
+	valSet.AddValidator(core.NewValidator(val1.Hex(), core.MinValidatorStakeDeposit))
+	valSet.AddValidator(core.NewValidator(val2.Hex(), core.MinValidatorStakeDeposit))
+
+	// val1 keeps 10% of the rewards of the stake delegated to it, val2 does not charge a commission
+	view.SetValidatorParams(&types.ValidatorParams{Validator: val1, CommissionRate: 1000})
+
+	accountReward := map[string]types.Coins{
+		string(val1[:]):      types.NewCoins(0, 5000),
+		string(val2[:]):      types.NewCoins(0, 5000),
+		string(delegator[:]): types.NewCoins(0, 2000),
+	}
+	commissions := applyValidatorCommissions(view, valSet, nil, nil, &accountReward)
+
+	// Half of the reward of the delegator comes from the stake delegated to val1
+	assert.Equal(types.NewCoins(0, 5100), accountReward[string(val1[:])])
+	assert.Equal(types.NewCoins(0, 5000), accountReward[string(val2[:])])
+	assert.Equal(types.NewCoins(0, 1900), accountReward[string(delegator[:])])
+	assert.Equal(1, len(commissions))
+	assert.Equal(big.NewInt(100), commissions[val1])
+}
+
+func TestRametronStakeTxTier(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	proSpec, _ := types.GetRametronTierSpec(types.RametronTierPro)
+	holder := types.MakeAccWithInitBalance("holder", types.Coins{
+		PandoWei: new(big.Int).Mul(proSpec.MinStake, big.NewInt(2)),
+		PTXWei:   big.NewInt(50 * getMinimumTxFee()),
+	})
+	holder.CodeHash = types.EmptyCodeHash
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(holder, et.accIn, et.accOut)
+	node := et.accOut.Address
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	makeTx := func(acc types.PrivAccount, seq uint64, pandoWei *big.Int, tier types.RametronTier) *types.RametronStakeTx {
+		tx := &types.RametronStakeTx{
+			Fee:     fee,
+			Inputs:  []types.TxInput{{Address: acc.Address, Coins: types.Coins{PandoWei: pandoWei, PTXWei: fee.PTXWei}, Sequence: seq}},
+			Outputs: []types.TxOutput{{Address: node, Coins: types.Coins{PandoWei: pandoWei, PTXWei: big.NewInt(0)}}},
+			Tier:    tier,
+		}
+		et.signRametronStakeTx(tx, acc)
+		return tx
+	}
+
+	// The node needs to hold the min stake of the tier
+	halfStake := new(big.Int).Div(proSpec.MinStake, big.NewInt(2))
+	_, res := et.executor.ExecuteTx(makeTx(holder, 1, halfStake, types.RametronTierPro))
+	assert.True(res.IsError())
+	assert.Equal(result.CodeInsufficientStake, res.Code)
+	assert.Nil(et.state().Delivered().GetRametronNode(node))
+
+	_, res = et.executor.ExecuteTx(makeTx(holder, 1, proSpec.MinStake, types.RametronTierPro))
+	assert.True(res.IsOK(), res.Message)
+	registered := et.state().Delivered().GetRametronNode(node)
+	assert.NotNil(registered)
+	assert.Equal(holder.Address, registered.Holder)
+	assert.Equal(types.RametronTierPro, registered.Tier)
+
+	// Only the holder can change the tier
+	_, res = et.executor.ExecuteTx(makeTx(et.accIn, 1, big.NewInt(1), types.RametronTierMobile))
+	assert.True(res.IsError())
+	_, res = et.executor.ExecuteTx(makeTx(holder, 2, big.NewInt(1), types.RametronTierMobile))
+	assert.True(res.IsOK(), res.Message)
+	assert.Equal(types.RametronTierMobile, et.state().Delivered().GetRametronNode(node).Tier)
+
+	// Unknown tiers are rejected
+	_, res = et.executor.ExecuteTx(makeTx(holder, 3, big.NewInt(1), types.RametronTier(99)))
+	assert.True(res.IsError())
+}
+
+func TestGrantRametronReward(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+	view := et.state().Delivered()
+
+	mobileSpec, _ := types.GetRametronTierSpec(types.RametronTierMobile)
+	proSpec, _ := types.GetRametronTierSpec(types.RametronTierPro)
+	enterpriseSpec, _ := types.GetRametronTierSpec(types.RametronTierEnterprise)
+
+	addNode := func(addr common.Address, tier types.RametronTier, stake *big.Int, activeEpochs ...uint64) {
+		view.SetAccount(addr, &types.Account{Address: addr, Balance: types.Coins{PandoWei: stake, PTXWei: big.NewInt(0)}})
+		node := &types.RametronNode{Node: addr, Holder: addr, Tier: tier}
+		for _, epoch := range activeEpochs {
+			node.MarkActive(epoch)
+		}
+		view.SetRametronNode(node)
+	}
+	allEpochs := []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	mobile := common.HexToAddress("0x111")
+	enterprise := common.HexToAddress("0x222")
+	underStaked := common.HexToAddress("0x333")
+	mostlyDown := common.HexToAddress("0x444")
+	addNode(mobile, types.RametronTierMobile, mobileSpec.MinStake, allEpochs...)
+	addNode(enterprise, types.RametronTierEnterprise, enterpriseSpec.MinStake, allEpochs...)
+	addNode(underStaked, types.RametronTierPro, mobileSpec.MinStake, allEpochs...)
+	addNode(mostlyDown, types.RametronTierPro, proSpec.MinStake, 9)
+	// meets the min uptime, but was not attested in the epoch the rewards are for
+	notAttested := common.HexToAddress("0x555")
+	addNode(notAttested, types.RametronTierPro, proSpec.MinStake, 0, 1, 2, 3, 4, 5, 6, 7, 8)
+
+	// Nothing is granted between the checkpoints
+	checkpoint := uint64(10*common.CheckpointInterval) + 1
+	accountReward := map[string]types.Coins{}
+	grantRametronReward(view, &accountReward, checkpoint+1)
+	assert.Equal(0, len(accountReward))
+
+	accountReward = map[string]types.Coins{
+		string(mobile[:]): types.NewCoins(0, 1000),
+	}
+	grantRametronReward(view, &accountReward, checkpoint)
+	assert.Equal(2, len(accountReward))
+
+	totalReward := new(big.Int).Mul(rametronRewardPerBlock, big.NewInt(common.CheckpointInterval))
+	totalWeight := big.NewInt(int64(mobileSpec.RewardWeight + enterpriseSpec.RewardWeight))
+	mobileReward := new(big.Int).Mul(totalReward, big.NewInt(int64(mobileSpec.RewardWeight)))
+	mobileReward.Div(mobileReward, totalWeight)
+	enterpriseReward := new(big.Int).Mul(totalReward, big.NewInt(int64(enterpriseSpec.RewardWeight)))
+	enterpriseReward.Div(enterpriseReward, totalWeight)
+	assert.Equal(0, new(big.Int).Add(mobileReward, big.NewInt(1000)).Cmp(accountReward[string(mobile[:])].PTXWei))
+	assert.Equal(0, enterpriseReward.Cmp(accountReward[string(enterprise[:])].PTXWei))
+}
+
+func TestRametronAttestationTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+
+	guardian1 := types.MakeAccWithInitBalance("guardian1", types.NewCoins(0, 50*getMinimumTxFee()))
+	guardian2 := types.MakeAccWithInitBalance("guardian2", types.NewCoins(0, 50*getMinimumTxFee()))
+	node := types.MakeAcc("rametron")
+	et.acc2State(guardian1, guardian2, et.accIn)
+
+	view := et.state().Delivered()
+	gcp := view.GetGuardianCandidatePool()
+	for _, guardian := range []types.PrivAccount{guardian1, guardian2} {
+		gcp.Add(&core.Guardian{StakeHolder: &core.StakeHolder{
+			Holder: guardian.Address,
+			Stakes: []*core.Stake{{Source: guardian.Address, Amount: core.MinGuardianStakeDeposit}},
+		}})
+	}
+	view.UpdateGuardianCandidatePool(gcp)
+	view.SetRametronNode(&types.RametronNode{Node: node.Address, Holder: node.Address, Tier: types.RametronTierMobile})
+	et.state().Commit()
+
+	epoch := types.RametronEpoch(et.state().Delivered().Height() + 1)
+	heartbeat := func(acc types.PrivAccount, epoch uint64) *types.RametronHeartbeat {
+		h := &types.RametronHeartbeat{Node: acc.Address, Epoch: epoch}
+		h.Signature = acc.Sign(h.SignBytes(et.chainID))
+		return h
+	}
+	makeTx := func(guardian types.PrivAccount, seq uint64, epoch uint64, heartbeats ...*types.RametronHeartbeat) *types.RametronAttestationTx {
+		tx := &types.RametronAttestationTx{
+			Fee:        types.NewCoins(0, getMinimumTxFee()),
+			Guardian:   types.TxInput{Address: guardian.Address, Sequence: seq},
+			Epoch:      epoch,
+			Heartbeats: heartbeats,
+		}
+		tx.SetSignature(guardian.Address, guardian.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+
+	// Only the guardians can attest
+	_, res := et.executor.ExecuteTx(makeTx(et.accIn, 1, epoch, heartbeat(node, epoch)))
+	assert.True(res.IsError())
+
+	// The heartbeats need to be signed by the node, for the current epoch
+	forged := heartbeat(node, epoch)
+	forged.Signature = et.accIn.Sign(forged.SignBytes(et.chainID))
+	_, res = et.executor.ExecuteTx(makeTx(guardian1, 1, epoch, forged))
+	assert.True(res.IsError())
+	_, res = et.executor.ExecuteTx(makeTx(guardian1, 1, epoch+1, heartbeat(node, epoch+1)))
+	assert.True(res.IsError())
+
+	// Unregistered nodes cannot be attested
+	_, res = et.executor.ExecuteTx(makeTx(guardian1, 1, epoch, heartbeat(et.accOut, epoch)))
+	assert.True(res.IsError())
+
+	// The node is active once enough guardians attested it
+	_, res = et.executor.ExecuteTx(makeTx(guardian1, 1, epoch, heartbeat(node, epoch)))
+	assert.True(res.IsOK(), res.Message)
+	attested := et.state().Delivered().GetRametronNode(node.Address)
+	assert.Equal(1, len(attested.Attesters))
+	assert.False(attested.IsActive(epoch))
+
+	_, res = et.executor.ExecuteTx(makeTx(guardian1, 2, epoch, heartbeat(node, epoch)))
+	assert.True(res.IsOK(), res.Message)
+	assert.False(et.state().Delivered().GetRametronNode(node.Address).IsActive(epoch))
+
+	_, res = et.executor.ExecuteTx(makeTx(guardian2, 1, epoch, heartbeat(node, epoch)))
+	assert.True(res.IsOK(), res.Message)
+	attested = et.state().Delivered().GetRametronNode(node.Address)
+	assert.Equal(2, len(attested.Attesters))
+	assert.True(attested.IsActive(epoch))
+}
+
+func TestKeyRotationTx(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	et := NewExecTest()
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut)
+
+	newKey := types.MakeAcc("new_key")
+	otherKey := types.MakeAcc("other_key")
+
+	makeKeyRotationTx := func(seq uint64, key common.Address, signer types.PrivAccount) *types.KeyRotationTx {
+		tx := &types.KeyRotationTx{
+			Fee: types.NewCoins(0, getMinimumTxFee()),
+			Account: types.TxInput{
+				Address:  et.accIn.Address,
+				Sequence: seq,
+			},
+			NewKey: key,
+		}
+		tx.SetSignature(et.accIn.Address, signer.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+
+	// The new key cannot authorize itself
+	krTx := makeKeyRotationTx(1, newKey.Address, newKey)
+	_, res := et.executor.ExecuteTx(krTx)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+
+	krTx = makeKeyRotationTx(1, et.accIn.Address, et.accIn)
+	_, res = et.executor.ExecuteTx(krTx)
+	assert.Equal(result.CodeInvalidKeyRotation, res.Code)
+
+	krTx = makeKeyRotationTx(1, newKey.Address, et.accIn)
+	_, res = et.executor.ExecuteTx(krTx)
+	assert.True(res.IsOK(), res.Message)
+	account := et.state().Delivered().GetAccount(et.accIn.Address)
+	assert.Equal(newKey.Address, account.ControllingAddress())
+
+	// Both keys are accepted during the grace period
+	sendTx := types.MakeSendTx(2, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, newKey)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.True(res.IsOK(), res.Message)
+	sendTx = types.MakeSendTx(3, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, et.accIn)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.True(res.IsOK(), res.Message)
+
+	// Rotating back to the previous key cancels the rotation
+	krTx = makeKeyRotationTx(4, et.accIn.Address, et.accIn)
+	_, res = et.executor.ExecuteTx(krTx)
+	assert.True(res.IsOK(), res.Message)
+	account = et.state().Delivered().GetAccount(et.accIn.Address)
+	assert.Nil(account.AuthorizedKey)
+	sendTx = types.MakeSendTx(5, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, newKey)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+
+	krTx = makeKeyRotationTx(5, newKey.Address, et.accIn)
+	_, res = et.executor.ExecuteTx(krTx)
+	assert.True(res.IsOK(), res.Message)
+	account = et.state().Delivered().GetAccount(et.accIn.Address)
+
+	// The previous key is rejected after the grace period
+	et.fastforwardTo(account.AuthorizedKey.GraceEndHeight)
+	sendTx = types.MakeSendTx(6, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, et.accIn)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+	sendTx = types.MakeSendTx(6, et.accOut, et.accIn)
+	types.SignSendTx(et.chainID, sendTx, newKey)
+	_, res = et.executor.ExecuteTx(sendTx)
+	assert.True(res.IsOK(), res.Message)
+
+	// The new key can rotate again
+	krTx = makeKeyRotationTx(7, otherKey.Address, et.accIn)
+	_, res = et.executor.ExecuteTx(krTx)
+	assert.Equal(result.CodeInvalidSignature, res.Code)
+	krTx = makeKeyRotationTx(7, otherKey.Address, newKey)
+	_, res = et.executor.ExecuteTx(krTx)
+	assert.True(res.IsOK(), res.Message)
+	account = et.state().Delivered().GetAccount(et.accIn.Address)
+	assert.Equal(otherKey.Address, account.AuthorizedKey.Address)
+	assert.Equal(newKey.Address, account.AuthorizedKey.PreviousAddress)
+}
+
+func TestHTLCTxs(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	require := require.New(t)
+	et := NewExecTest()
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	relayer := types.MakeAcc("relayer")
+	relayer.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut, relayer)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	coins := types.NewCoins(1000, 2000)
+	preimage := common.Bytes(strings.Repeat("s", types.HTLCPreimageLength))
+	hashLock := types.HTLCHashLockOf(preimage)
+
+	makeCreateTx := func(seq uint64, timeLock uint64) *types.HTLCCreateTx {
+		tx := &types.HTLCCreateTx{
+			Fee:       fee,
+			Sender:    types.TxInput{Address: et.accIn.Address, Coins: coins, Sequence: seq},
+			Recipient: et.accOut.Address,
+			HashLock:  hashLock,
+			TimeLock:  timeLock,
+		}
+		tx.SetSignature(et.accIn.Address, et.accIn.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeClaimTx := func(seq uint64, id common.Hash, preimage common.Bytes) *types.HTLCClaimTx {
+		tx := &types.HTLCClaimTx{
+			Fee:      fee,
+			Claimer:  types.TxInput{Address: relayer.Address, Sequence: seq},
+			HTLCID:   id,
+			Preimage: preimage,
+		}
+		tx.SetSignature(relayer.Address, relayer.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeRefundTx := func(seq uint64, id common.Hash) *types.HTLCRefundTx {
+		tx := &types.HTLCRefundTx{
+			Fee:      fee,
+			Refunder: types.TxInput{Address: relayer.Address, Sequence: seq},
+			HTLCID:   id,
+		}
+		tx.SetSignature(relayer.Address, relayer.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	balanceOf := func(addr common.Address) types.Coins {
+		return et.state().Delivered().GetAccount(addr).Balance
+	}
+
+	// The time lock needs to be in the future, and not too far away
+	height := et.state().Height()
+	_, res := et.executor.ExecuteTx(makeCreateTx(1, height))
+	assert.Equal(result.CodeInvalidHTLC, res.Code)
+	_, res = et.executor.ExecuteTx(makeCreateTx(1, height+2+types.MaximumHTLCDuration))
+	assert.Equal(result.CodeInvalidHTLC, res.Code)
+
+	senderBalance := balanceOf(et.accIn.Address)
+	createTx := makeCreateTx(1, height+100)
+	_, res = et.executor.ExecuteTx(createTx)
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(senderBalance.Minus(coins).Minus(fee), balanceOf(et.accIn.Address))
+	htlc := et.state().Delivered().GetHTLC(createTx.HTLCID())
+	require.NotNil(htlc)
+	assert.Equal(et.accOut.Address, htlc.Recipient)
+	assert.Equal(coins, htlc.Coins)
+
+	// The HTLC can only be claimed with the preimage, and cannot be refunded before the time lock expires
+	_, res = et.executor.ExecuteTx(makeClaimTx(1, htlc.ID, common.Bytes(strings.Repeat("g", types.HTLCPreimageLength))))
+	assert.Equal(result.CodeInvalidHTLC, res.Code)
+	_, res = et.executor.ExecuteTx(makeRefundTx(1, htlc.ID))
+	assert.Equal(result.CodeInvalidHTLC, res.Code)
+
+	// Anyone can claim the coins for the recipient
+	recipientBalance := balanceOf(et.accOut.Address)
+	_, res = et.executor.ExecuteTx(makeClaimTx(1, htlc.ID, preimage))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(recipientBalance.Plus(coins), balanceOf(et.accOut.Address))
+	assert.Nil(et.state().Delivered().GetHTLC(htlc.ID))
+	_, res = et.executor.ExecuteTx(makeClaimTx(2, htlc.ID, preimage))
+	assert.Equal(result.CodeInvalidHTLC, res.Code)
+
+	// The expired HTLC can only be refunded to the sender
+	createTx = makeCreateTx(2, height+100)
+	_, res = et.executor.ExecuteTx(createTx)
+	require.True(res.IsOK(), res.Message)
+	htlc = et.state().Delivered().GetHTLC(createTx.HTLCID())
+	require.NotNil(htlc)
+	et.fastforwardTo(htlc.TimeLock)
+
+	_, res = et.executor.ExecuteTx(makeClaimTx(2, htlc.ID, preimage))
+	assert.Equal(result.CodeInvalidHTLC, res.Code)
+	senderBalance = balanceOf(et.accIn.Address)
+	_, res = et.executor.ExecuteTx(makeRefundTx(2, htlc.ID))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(senderBalance.Plus(coins), balanceOf(et.accIn.Address))
+	assert.Nil(et.state().Delivered().GetHTLC(htlc.ID))
+}
+
+func TestGovernanceTxs(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	require := require.New(t)
+	et := NewExecTest()
+
+	ptx := func(amount int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+	}
+	proposer := types.MakeAccWithInitBalance("proposer", types.Coins{PandoWei: big.NewInt(0), PTXWei: ptx(5000)})
+	proposer.CodeHash = types.EmptyCodeHash
+	staker, outsider := types.MakeAcc("staker"), types.MakeAcc("outsider")
+	staker.CodeHash, outsider.CodeHash = types.EmptyCodeHash, types.EmptyCodeHash
+	et.acc2State(proposer, staker, outsider)
+
+	vcp := &core.ValidatorCandidatePool{}
+	require.Nil(vcp.DepositStake(staker.Address, staker.Address, core.MinValidatorStakeDeposit))
+	et.state().Delivered().UpdateValidatorCandidatePool(vcp)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	deposit := types.Coins{PandoWei: big.NewInt(0), PTXWei: types.MinimumGovernanceDeposit()}
+	makeProposalTx := func(seq uint64, name string, value *big.Int, deposit types.Coins) *types.ProposalTx {
+		tx := &types.ProposalTx{
+			Fee:      fee,
+			Proposer: types.TxInput{Address: proposer.Address, Coins: deposit, Sequence: seq},
+			Name:     name,
+			Value:    value,
+		}
+		tx.SetSignature(proposer.Address, proposer.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeVoteTx := func(voter types.PrivAccount, seq uint64, id common.Hash, approve bool) *types.VoteTx {
+		tx := &types.VoteTx{
+			Fee:        fee,
+			Voter:      types.TxInput{Address: voter.Address, Sequence: seq},
+			ProposalID: id,
+			Approve:    approve,
+		}
+		tx.SetSignature(voter.Address, voter.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+
+	// Only the governable parameters can be proposed, within their bounds, with a sufficient deposit
+	_, res := et.executor.ExecuteTx(makeProposalTx(1, "ChainID", big.NewInt(1), deposit))
+	assert.Equal(result.CodeInvalidGovernanceProposal, res.Code)
+	_, res = et.executor.ExecuteTx(makeProposalTx(1, types.ParamBlockGasLimit, big.NewInt(1), deposit))
+	assert.Equal(result.CodeInvalidGovernanceProposal, res.Code)
+	_, res = et.executor.ExecuteTx(makeProposalTx(1, types.ParamBlockGasLimit, big.NewInt(200e6), types.Coins{PandoWei: big.NewInt(0), PTXWei: ptx(1)}))
+	assert.Equal(result.CodeInvalidGovernanceProposal, res.Code)
+
+	proposerBalance := et.state().Delivered().GetAccount(proposer.Address).Balance
+	proposalTx := makeProposalTx(1, types.ParamBlockGasLimit, big.NewInt(200e6), deposit)
+	_, res = et.executor.ExecuteTx(proposalTx)
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(proposerBalance.Minus(deposit).Minus(fee), et.state().Delivered().GetAccount(proposer.Address).Balance)
+
+	proposal := et.state().Delivered().GetGovernanceProposals().Get(proposalTx.ProposalID())
+	require.NotNil(proposal)
+	assert.Equal(types.ParamBlockGasLimit, proposal.Name)
+	assert.True(proposal.TallyHeight >= proposal.SubmitHeight+types.GovernanceVotingPeriod)
+
+	// Only the stakers can vote, on the pending proposals
+	_, res = et.executor.ExecuteTx(makeVoteTx(outsider, 1, proposalTx.ProposalID(), true))
+	assert.Equal(result.CodeInvalidGovernanceProposal, res.Code)
+	_, res = et.executor.ExecuteTx(makeVoteTx(staker, 1, common.Hash{}, true))
+	assert.Equal(result.CodeInvalidGovernanceProposal, res.Code)
+
+	// A staker can change its vote
+	_, res = et.executor.ExecuteTx(makeVoteTx(staker, 1, proposalTx.ProposalID(), false))
+	require.True(res.IsOK(), res.Message)
+	_, res = et.executor.ExecuteTx(makeVoteTx(staker, 2, proposalTx.ProposalID(), true))
+	require.True(res.IsOK(), res.Message)
+
+	proposal = et.state().Delivered().GetGovernanceProposals().Get(proposalTx.ProposalID())
+	require.Equal(1, len(proposal.Votes))
+	assert.True(proposal.Votes[0].Approve)
+	quorum, passed := proposal.Tally(et.state().Delivered().GetValidatorCandidatePool())
+	assert.True(quorum)
+	assert.True(passed)
+}
+
+func TestBridgeTxs(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	require := require.New(t)
+	et := NewExecTest()
+	et.accIn.CodeHash = types.EmptyCodeHash
+	et.accOut.CodeHash = types.EmptyCodeHash
+	relayer := types.MakeAcc("relayer")
+	relayer.CodeHash = types.EmptyCodeHash
+	et.acc2State(et.accIn, et.accOut, relayer)
+
+	val1, val2, val3 := types.MakeAcc("val1"), types.MakeAcc("val2"), types.MakeAcc("val3")
+	vcp := &core.ValidatorCandidatePool{}
+	require.Nil(vcp.DepositStake(val1.Address, val1.Address, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(5))))
+	require.Nil(vcp.DepositStake(val2.Address, val2.Address, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(3))))
+	require.Nil(vcp.DepositStake(val3.Address, val3.Address, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(2))))
+	et.state().Delivered().UpdateValidatorCandidatePool(vcp)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	coins := types.NewCoins(1000, 2000)
+	foreignRecipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	token := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	makeLockTx := func(seq uint64, destChain string) *types.BridgeLockTx {
+		tx := &types.BridgeLockTx{
+			Fee:       fee,
+			Sender:    types.TxInput{Address: et.accIn.Address, Coins: coins, Sequence: seq},
+			DestChain: destChain,
+			Recipient: foreignRecipient,
+		}
+		tx.SetSignature(et.accIn.Address, et.accIn.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeBurnTx := func(seq uint64, amount int64) *types.BridgeBurnTx {
+		tx := &types.BridgeBurnTx{
+			Fee:         fee,
+			Sender:      types.TxInput{Address: et.accOut.Address, Sequence: seq},
+			SourceChain: "ethereum",
+			Token:       token,
+			Amount:      big.NewInt(amount),
+			Recipient:   foreignRecipient,
+		}
+		tx.SetSignature(et.accOut.Address, et.accOut.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeClaimTx := func(seq uint64, event types.BridgeForeignEvent, attesters ...types.PrivAccount) *types.BridgeClaimTx {
+		proof := types.BridgeForeignProof{Event: event}
+		for _, attester := range attesters {
+			proof.Attestations = append(proof.Attestations, attester.Sign(event.SignBytes(et.chainID)))
+		}
+		tx := &types.BridgeClaimTx{
+			Fee:     fee,
+			Relayer: types.TxInput{Address: relayer.Address, Sequence: seq},
+			Proof:   proof,
+		}
+		tx.SetSignature(relayer.Address, relayer.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	balanceOf := func(addr common.Address) types.Coins {
+		return et.state().Delivered().GetAccount(addr).Balance
+	}
+
+	// Locking the coins moves them to the custody of the bridge, and records an outbound message
+	_, res := et.executor.ExecuteTx(makeLockTx(1, ""))
+	assert.Equal(result.CodeInvalidBridgeTx, res.Code)
+	senderBalance := balanceOf(et.accIn.Address)
+	_, res = et.executor.ExecuteTx(makeLockTx(1, "ethereum"))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(senderBalance.Minus(coins).Minus(fee), balanceOf(et.accIn.Address))
+	assert.Equal(coins, et.state().Delivered().GetBridgeCustody())
+	assert.Equal(uint64(1), et.state().Delivered().GetBridgeNonce())
+	msg := et.state().Delivered().GetBridgeMessage(1)
+	require.NotNil(msg)
+	assert.Equal(types.BridgeMessageLock, msg.Kind)
+	assert.Equal(foreignRecipient, msg.Recipient)
+	assert.Equal(coins, msg.Coins)
+
+	// The wrapped asset is minted once the lock on the foreign chain is attested by 2/3 of the stake
+	mint := types.BridgeForeignEvent{
+		SourceChain: "ethereum",
+		TxHash:      common.HexToHash("0x1"),
+		Recipient:   et.accOut.Address,
+		Token:       token,
+		Amount:      big.NewInt(500),
+	}
+	asset := types.WrappedAssetID("ethereum", token)
+	_, res = et.executor.ExecuteTx(makeClaimTx(1, mint, val1))
+	assert.Equal(result.CodeInvalidForeignProof, res.Code)
+	_, res = et.executor.ExecuteTx(makeClaimTx(1, mint, val1, val3))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(big.NewInt(500), et.state().Delivered().GetWrappedBalance(asset, et.accOut.Address))
+	assert.Equal(big.NewInt(500), et.state().Delivered().GetWrappedSupply(asset))
+	_, res = et.executor.ExecuteTx(makeClaimTx(2, mint, val1, val2))
+	assert.Equal(result.CodeForeignEventAlreadyUsed, res.Code)
+
+	// Burning the wrapped asset records an outbound message to release it on the foreign chain
+	_, res = et.executor.ExecuteTx(makeBurnTx(1, 501))
+	assert.Equal(result.CodeInsufficientFund, res.Code)
+	_, res = et.executor.ExecuteTx(makeBurnTx(1, 200))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(big.NewInt(300), et.state().Delivered().GetWrappedBalance(asset, et.accOut.Address))
+	assert.Equal(big.NewInt(300), et.state().Delivered().GetWrappedSupply(asset))
+	msg = et.state().Delivered().GetBridgeMessage(2)
+	require.NotNil(msg)
+	assert.Equal(types.BridgeMessageBurn, msg.Kind)
+	assert.Equal("ethereum", msg.DestChain)
+	assert.Equal(big.NewInt(200), msg.Amount)
+
+	// The burn of the wrapped coins on the foreign chain releases the coins from the custody
+	release := types.BridgeForeignEvent{
+		SourceChain: "ethereum",
+		TxHash:      common.HexToHash("0x2"),
+		Recipient:   et.accOut.Address,
+		Coins:       coins.Plus(coins),
+	}
+	_, res = et.executor.ExecuteTx(makeClaimTx(2, release, val1, val2))
+	assert.Equal(result.CodeInvalidBridgeTx, res.Code)
+	release.Coins = coins
+	recipientBalance := balanceOf(et.accOut.Address)
+	_, res = et.executor.ExecuteTx(makeClaimTx(2, release, val1, val2))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(recipientBalance.Plus(coins), balanceOf(et.accOut.Address))
+	assert.True(et.state().Delivered().GetBridgeCustody().IsZero())
+}
+
+func TestSubchainTxs(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(features.ActivateUnscheduled(0)))
+	defer features.Configure(nil)
+	require := require.New(t)
+	et := NewExecTest()
+
+	wealth := types.Coins{
+		PandoWei: new(big.Int).Mul(big.NewInt(1000000), core.MinValidatorStakeDeposit),
+		PTXWei:   new(big.Int).Mul(big.NewInt(10), types.MinimumSubchainDeposit()),
+	}
+	owner := types.MakeAccWithInitBalance("owner", wealth)
+	val1 := types.MakeAccWithInitBalance("val1", wealth)
+	val2 := types.MakeAccWithInitBalance("val2", wealth)
+	val3 := types.MakeAccWithInitBalance("val3", wealth)
+	relayer := types.MakeAcc("relayer")
+	for _, acc := range []*types.PrivAccount{&owner, &val1, &val2, &val3, &relayer} {
+		acc.CodeHash = types.EmptyCodeHash
+	}
+	et.acc2State(owner, val1, val2, val3, relayer)
+
+	fee := types.NewCoins(0, getMinimumTxFee())
+	deposit := types.Coins{PandoWei: big.NewInt(0), PTXWei: types.MinimumSubchainDeposit()}
+	subchainID := types.SubchainID("gamechain")
+
+	makeRegisterTx := func(chainID string, interval uint64) *types.SubchainRegisterTx {
+		tx := &types.SubchainRegisterTx{
+			Fee:                fee,
+			Owner:              types.TxInput{Address: owner.Address, Coins: deposit, Sequence: 1},
+			ChainID:            chainID,
+			CheckpointInterval: interval,
+		}
+		tx.SetSignature(owner.Address, owner.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeStakeTx := func(staker types.PrivAccount, seq uint64, amount *big.Int, withdraw bool) *types.SubchainStakeTx {
+		tx := &types.SubchainStakeTx{
+			Fee:        fee,
+			Staker:     types.TxInput{Address: staker.Address, Coins: types.Coins{PandoWei: amount, PTXWei: big.NewInt(0)}, Sequence: seq},
+			SubchainID: subchainID,
+			Withdraw:   withdraw,
+		}
+		tx.SetSignature(staker.Address, staker.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	signCheckpoint := func(checkpoint *types.SubchainCheckpoint, signers ...types.PrivAccount) []*crypto.Signature {
+		sigs := []*crypto.Signature{}
+		for _, signer := range signers {
+			sigs = append(sigs, signer.Sign(checkpoint.SignBytes(et.chainID)))
+		}
+		return sigs
+	}
+	makeCheckpointTx := func(seq, height uint64, blockHash common.Hash, signers ...types.PrivAccount) *types.SubchainCheckpointTx {
+		tx := &types.SubchainCheckpointTx{
+			Fee:        fee,
+			Submitter:  types.TxInput{Address: relayer.Address, Sequence: seq},
+			SubchainID: subchainID,
+			Height:     height,
+			BlockHash:  blockHash,
+		}
+		tx.Signatures = signCheckpoint(tx.Checkpoint(), signers...)
+		tx.SetSignature(relayer.Address, relayer.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	makeChallengeTx := func(seq, height uint64, blockHash common.Hash, signers ...types.PrivAccount) *types.SubchainChallengeTx {
+		tx := &types.SubchainChallengeTx{
+			Fee:        fee,
+			Challenger: types.TxInput{Address: relayer.Address, Sequence: seq},
+			SubchainID: subchainID,
+			Height:     height,
+			BlockHash:  blockHash,
+		}
+		tx.Signatures = signCheckpoint(tx.Conflict(), signers...)
+		tx.SetSignature(relayer.Address, relayer.Sign(tx.SignBytes(et.chainID)))
+		return tx
+	}
+	balanceOf := func(addr common.Address) types.Coins {
+		return et.state().Delivered().GetAccount(addr).Balance
+	}
+
+	// Registering a subchain locks the deposit of the owner
+	_, res := et.executor.ExecuteTx(makeRegisterTx(et.chainID, 100))
+	assert.Equal(result.CodeInvalidSubchainTx, res.Code)
+	_, res = et.executor.ExecuteTx(makeRegisterTx("gamechain", 0))
+	assert.Equal(result.CodeInvalidSubchainTx, res.Code)
+	ownerBalance := balanceOf(owner.Address)
+	_, res = et.executor.ExecuteTx(makeRegisterTx("gamechain", 100))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(ownerBalance.Minus(deposit).Minus(fee), balanceOf(owner.Address))
+	subchain := et.state().Delivered().GetSubchain(subchainID)
+	require.NotNil(subchain)
+	assert.Equal(owner.Address, subchain.Owner)
+	assert.Equal(deposit, subchain.Deposit)
+
+	// The validators stake Pando to sign the checkpoints
+	stake := types.MinimumSubchainStake()
+	_, res = et.executor.ExecuteTx(makeStakeTx(val1, 1, new(big.Int).Sub(stake, big.NewInt(1)), false))
+	assert.Equal(result.CodeInvalidSubchainTx, res.Code)
+	_, res = et.executor.ExecuteTx(makeStakeTx(val1, 1, new(big.Int).Mul(stake, big.NewInt(4)), false))
+	require.True(res.IsOK(), res.Message)
+	_, res = et.executor.ExecuteTx(makeStakeTx(val2, 1, new(big.Int).Mul(stake, big.NewInt(3)), false))
+	require.True(res.IsOK(), res.Message)
+	_, res = et.executor.ExecuteTx(makeStakeTx(val3, 1, new(big.Int).Mul(stake, big.NewInt(3)), false))
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(new(big.Int).Mul(stake, big.NewInt(10)), et.state().Delivered().GetSubchain(subchainID).ActiveStake())
+
+	// A checkpoint needs the signatures of more than 2/3 of the stake, and to extend the subchain
+	_, res = et.executor.ExecuteTx(makeCheckpointTx(1, 100, common.HexToHash("0x1"), val2, val3))
+	assert.Equal(result.CodeInvalidSubchainCheckpoint, res.Code)
+	_, res = et.executor.ExecuteTx(makeCheckpointTx(1, 200, common.HexToHash("0x1"), val1, val2))
+	assert.Equal(result.CodeInvalidSubchainCheckpoint, res.Code)
+	_, res = et.executor.ExecuteTx(makeCheckpointTx(1, 100, common.HexToHash("0x1"), val1, val2))
+	require.True(res.IsOK(), res.Message)
+	_, res = et.executor.ExecuteTx(makeCheckpointTx(2, 200, common.HexToHash("0x2"), val1, val2, val3))
+	require.True(res.IsOK(), res.Message)
+	subchain = et.state().Delivered().GetSubchain(subchainID)
+	assert.Equal(2, len(subchain.Pending))
+	assert.Equal(uint64(300), subchain.NextCheckpointHeight())
+	queue := et.state().Delivered().GetSubchainUpdateQueue()
+	assert.Equal(2, queue.Len())
+
+	// The stake of a withdrawing validator is still slashable within the fraud proof window
+	_, res = et.executor.ExecuteTx(makeStakeTx(val1, 2, big.NewInt(0), true))
+	require.True(res.IsOK(), res.Message)
+	assert.True(et.state().Delivered().GetSubchain(subchainID).GetStake(val1.Address).Withdrawn)
+	_, res = et.executor.ExecuteTx(makeStakeTx(val1, 3, big.NewInt(0), true))
+	assert.Equal(result.CodeInvalidSubchainTx, res.Code)
+
+	// A conflicting block at the same height co-signed by 30% of the stake does not prove the fraud
+	_, res = et.executor.ExecuteTx(makeChallengeTx(3, 100, common.HexToHash("0xbad"), val2, val3))
+	assert.Equal(result.CodeInvalidSubchainChallenge, res.Code)
+
+	// val1 and val2 equivocated with 70% of the stake: the pending checkpoints are reverted and they are slashed
+	relayerBalance := balanceOf(relayer.Address)
+	_, res = et.executor.ExecuteTx(makeChallengeTx(3, 100, common.HexToHash("0xbad"), val1, val2))
+	require.True(res.IsOK(), res.Message)
+	subchain = et.state().Delivered().GetSubchain(subchainID)
+	assert.Equal(0, len(subchain.Pending))
+	assert.Equal(uint64(100), subchain.NextCheckpointHeight())
+	assert.Nil(subchain.GetStake(val1.Address))
+	assert.Nil(subchain.GetStake(val2.Address))
+	assert.Equal(new(big.Int).Mul(stake, big.NewInt(3)), subchain.ActiveStake())
+	slashed := new(big.Int).Mul(stake, big.NewInt(7))
+	reward := new(big.Int).Div(new(big.Int).Mul(slashed, big.NewInt(types.SubchainChallengeRewardPercentage)), big.NewInt(100))
+	assert.Equal(relayerBalance.Plus(types.Coins{PandoWei: reward, PTXWei: big.NewInt(0)}).Minus(fee), balanceOf(relayer.Address))
+}
+
+func TestExecuteTxsInParallel(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Stores 42 at slot 0, and deploys an empty contract
+	deploymentCode := common.Hex2Bytes("602a60005560006000f3")
+
+	execute := func(workers int, makeTxs func(et *execTest, accs []types.PrivAccount) []types.Tx) (*execTest, []types.Tx, int, result.Result) {
+		et := NewExecTest()
+		accs := []types.PrivAccount{}
+		for i := 0; i < 6; i++ {
+			acc := types.MakeAccWithInitBalance(fmt.Sprintf("parallel_%v", i), types.NewCoins(0, int64(9000000*types.MinimumGasPrice)))
+			acc.CodeHash = types.EmptyCodeHash
+			et.acc2State(acc)
+			accs = append(accs, acc)
+		}
+		et.fastforwardTo(1e2)
+		txs := makeTxs(et, accs)
+		idx, res := et.executor.ExecuteTxs(txs, workers)
+		return et, txs, idx, res
+	}
+
+	send := func(et *execTest, from, to types.PrivAccount, seq int) *types.SendTx {
+		tx := &types.SendTx{
+			Fee: types.NewCoins(0, getMinimumTxFee()),
+			Inputs: []types.TxInput{
+				types.NewTxInput(from.Address, types.NewCoins(0, 1000+getMinimumTxFee()), seq),
+			},
+			Outputs: []types.TxOutput{{Address: to.Address, Coins: types.NewCoins(0, 1000)}},
+		}
+		et.signSendTx(tx, from)
+		return tx
+	}
+	deploy := func(et *execTest, deployer types.PrivAccount, seq uint64) *types.SmartContractTx {
+		tx := &types.SmartContractTx{
+			From:     types.TxInput{Address: deployer.Address, Coins: types.NewCoins(0, 0), Sequence: seq},
+			GasLimit: 200000,
+			GasPrice: new(big.Int).SetUint64(types.MinimumGasPrice),
+			Data:     deploymentCode,
+		}
+		tx.From.Signature = deployer.Sign(tx.SignBytes(et.chainID))
+		return tx
+	}
+
+	// The independent txs are merged, the ones depending on the preceding txs are re-executed
+	makeTxs := func(et *execTest, accs []types.PrivAccount) []types.Tx {
+		return []types.Tx{
+			send(et, accs[0], accs[1], 1),
+			send(et, accs[2], accs[3], 1),
+			deploy(et, accs[4], 1),
+			send(et, accs[1], accs[5], 1), // reads the output of the first tx
+			send(et, accs[0], accs[5], 2), // invalid without the first tx
+			deploy(et, accs[3], 1),        // reads the output of the second tx
+		}
+	}
+	serial, txs, idx, res := execute(1, makeTxs)
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(len(txs), idx)
+	parallel, _, idx, res := execute(4, makeTxs)
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(len(txs), idx)
+	assert.Equal(serial.state().Commit(), parallel.state().Commit())
+
+	// The contract storage written speculatively is in the database
+	for _, deployTx := range []types.Tx{txs[2], txs[5]} {
+		raw, err := types.TxToBytes(deployTx)
+		require.Nil(err)
+		receipt, found := parallel.executor.chain.FindTxReceiptByHash(crypto.Keccak256Hash(raw))
+		require.True(found)
+		assert.Equal(common.BigToHash(big.NewInt(42)), parallel.state().Delivered().GetState(receipt.ContractAddress, common.Hash{}))
+	}
+
+	// The first failed tx is reported the same way
+	makeInvalidTxs := func(et *execTest, accs []types.PrivAccount) []types.Tx {
+		return []types.Tx{
+			send(et, accs[0], accs[1], 1),
+			send(et, accs[2], accs[3], 1),
+			send(et, accs[4], accs[5], 2), // wrong sequence
+			send(et, accs[1], accs[5], 1),
+		}
+	}
+	_, _, serialIdx, serialRes := execute(1, makeInvalidTxs)
+	_, _, parallelIdx, parallelRes := execute(4, makeInvalidTxs)
+	assert.True(serialRes.IsError())
+	assert.True(parallelRes.IsError())
+	assert.Equal(2, serialIdx)
+	assert.Equal(serialIdx, parallelIdx)
+	assert.Equal(serialRes.Message, parallelRes.Message)
+}
+
+func TestExecuteTxsInParallelReceipts(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Increments the counter at slot 0, logs it, and returns it
+	counterCode := common.Hex2Bytes("6000546001018060005560005260206000a060206000f3")
+	counter := common.HexToAddress("0xc0c0")
+	other := common.HexToAddress("0xc1c1")
+
+	execute := func(workers int, makeTxs func(et *execTest, accs []types.PrivAccount) []types.Tx) (*execTest, []types.Tx, int, result.Result) {
+		et := NewExecTest()
+		accs := []types.PrivAccount{}
+		for i := 0; i < 6; i++ {
+			acc := types.MakeAccWithInitBalance(fmt.Sprintf("parallel_%v", i), types.NewCoins(0, int64(9000000*types.MinimumGasPrice)))
+			acc.CodeHash = types.EmptyCodeHash
+			et.acc2State(acc)
+			accs = append(accs, acc)
+		}
+		for _, contract := range []common.Address{counter, other} {
+			et.state().Delivered().CreateAccount(contract)
+			et.state().Delivered().SetCode(contract, counterCode)
+		}
+		et.fastforwardTo(1e2)
+		txs := makeTxs(et, accs)
+		idx, res := et.executor.ExecuteTxs(txs, workers)
+		return et, txs, idx, res
+	}
+
+	call := func(et *execTest, caller types.PrivAccount, contract common.Address) *types.SmartContractTx {
+		tx := &types.SmartContractTx{
+			From:     types.TxInput{Address: caller.Address, Coins: types.NewCoins(0, 0), Sequence: 1},
+			To:       types.TxOutput{Address: contract},
+			GasLimit: 200000,
+			GasPrice: new(big.Int).SetUint64(types.MinimumGasPrice),
+		}
+		tx.From.Signature = caller.Sign(tx.SignBytes(et.chainID))
+		return tx
+	}
+	receipt := func(et *execTest, tx types.Tx) (*blockchain.TxReceiptEntry, bool) {
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		return et.executor.chain.FindTxReceiptByHash(crypto.Keccak256Hash(raw))
+	}
+
+	// All the calls of the counter conflict with each other, and are re-executed
+	makeTxs := func(et *execTest, accs []types.PrivAccount) []types.Tx {
+		return []types.Tx{
+			call(et, accs[0], counter),
+			call(et, accs[1], counter),
+			call(et, accs[2], other),
+			call(et, accs[3], counter),
+			call(et, accs[4], counter),
+		}
+	}
+	serial, txs, idx, res := execute(1, makeTxs)
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(len(txs), idx)
+	parallel, _, idx, res := execute(4, makeTxs)
+	require.True(res.IsOK(), res.Message)
+	assert.Equal(len(txs), idx)
+	assert.Equal(serial.state().Commit(), parallel.state().Commit())
+
+	expected := []int64{1, 2, 1, 3, 4}
+	for i, tx := range txs {
+		serialReceipt, found := receipt(serial, tx)
+		require.True(found)
+		parallelReceipt, found := receipt(parallel, tx)
+		require.True(found)
+		assert.Equal(serialReceipt, parallelReceipt)
+		require.Equal(1, len(parallelReceipt.Logs))
+		assert.Equal(common.BigToHash(big.NewInt(expected[i])).Bytes(), parallelReceipt.Logs[0].Data)
+		assert.Equal(common.BigToHash(big.NewInt(expected[i])).Bytes(), []byte(parallelReceipt.EvmRet))
+	}
+
+	// The txs after the first failed one are not executed, and leave no receipt
+	makeInvalidTxs := func(et *execTest, accs []types.PrivAccount) []types.Tx {
+		invalid := call(et, accs[5], other)
+		invalid.From.Sequence = 2
+		invalid.From.Signature = accs[5].Sign(invalid.SignBytes(et.chainID))
+		return []types.Tx{
+			call(et, accs[0], counter),
+			invalid,
+			call(et, accs[1], other),
+		}
+	}
+	serial, txs, serialIdx, serialRes := execute(1, makeInvalidTxs)
+	parallel, _, parallelIdx, parallelRes := execute(4, makeInvalidTxs)
+	assert.True(serialRes.IsError())
+	assert.True(parallelRes.IsError())
+	assert.Equal(1, serialIdx)
+	assert.Equal(serialIdx, parallelIdx)
+	for _, et := range []*execTest{serial, parallel} {
+		_, found := receipt(et, txs[0])
+		assert.True(found)
+		_, found = receipt(et, txs[2])
+		assert.False(found)
+	}
+}
@@ -0,0 +1,143 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*BatchSendTxExecutor)(nil)
+
+// ------------------------------- Batch Send Transaction -----------------------------------
+
+// BatchSendTxExecutor implements the TxExecutor interface
+type BatchSendTxExecutor struct {
+}
+
+// NewBatchSendTxExecutor creates a new instance of BatchSendTxExecutor
+func NewBatchSendTxExecutor() *BatchSendTxExecutor {
+	return &BatchSendTxExecutor{}
+}
+
+func (exec *BatchSendTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.BatchSendTx)
+
+	// Validate inputs and outputs, basic
+	res := validateInputsBasic(tx.Inputs)
+	if res.IsError() {
+		return res
+	}
+	res = validateMultiSigEnabled(view, tx.Inputs)
+	if res.IsError() {
+		return res
+	}
+	for _, out := range tx.Outputs {
+		if res := out.ValidateBasic(); res.IsError() {
+			return res
+		}
+	}
+
+	if len(tx.Inputs) == 0 || len(tx.Outputs) == 0 {
+		return result.Error("Invalid batchSendTx, Inputs and/or Outputs are empty")
+	}
+	if len(tx.Outputs) > types.MaximumBatchSendTxOutputs {
+		return result.Error("Too many outputs. At most %v outputs are allowed per transaction",
+			types.MaximumBatchSendTxOutputs)
+	}
+	if len(tx.Inputs) > types.MaxAccountsAffectedPerTx {
+		return result.Error("Too many inputs. At most %v inputs are allowed per transaction",
+			types.MaxAccountsAffectedPerTx)
+	}
+
+	outputs := tx.TxOutputs()
+
+	// Get inputs
+	accounts, res := getInputs(view, tx.Inputs)
+	if res.IsError() {
+		return res
+	}
+
+	// Get or make outputs.
+	accounts, res = getOrMakeOutputs(view, accounts, outputs)
+	if res.IsError() {
+		return res
+	}
+
+	for _, outAcc := range accounts {
+		if outAcc.IsASmartContract() {
+			return result.Error(
+				fmt.Sprintf("Sending Pando/PTX to a smart contract (%v) through a BatchSendTx transaction is not allowed", outAcc.Address))
+		}
+	}
+
+	// Validate inputs and outputs, advanced
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	inTotal, res := validateInputsAdvanced(accounts, signBytes, altSignBytes, tx.Inputs)
+	if res.IsError() {
+		return res
+	}
+
+	minimumFee := tx.MinimumFeeAt(minimumTxFee(view))
+	fee := tx.Fee.NoNil()
+	if fee.PandoWei.Cmp(types.Zero) != 0 || fee.PTXWei.Cmp(minimumFee) < 0 {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei for %v outputs",
+			minimumFee, len(tx.Outputs)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	outTotal := sumOutputs(outputs)
+	outPlusFees := outTotal.Plus(tx.Fee)
+	if !inTotal.IsEqual(outPlusFees) {
+		return result.Error("Input total (%v) != output total + fees (%v)", inTotal, outPlusFees)
+	}
+
+	return result.OK
+}
+
+func (exec *BatchSendTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.BatchSendTx)
+	outputs := tx.TxOutputs()
+
+	accounts, res := getInputs(view, tx.Inputs)
+	if res.IsError() {
+		return common.Hash{}, res
+	}
+
+	accounts, res = getOrMakeOutputs(view, accounts, outputs)
+	if res.IsError() {
+		return common.Hash{}, res
+	}
+
+	adjustByInputs(view, accounts, tx.Inputs)
+	adjustByOutputs(view, accounts, outputs)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *BatchSendTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.BatchSendTx)
+	return &core.TxInfo{
+		Address:           tx.Inputs[0].Address,
+		Sequence:          tx.Inputs[0].Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *BatchSendTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.BatchSendTx)
+	fee := tx.Fee
+	numAccountsAffected := uint64(len(tx.Inputs) + len(tx.Outputs))
+	gasUint64 := types.GasSendTxPerAccount * numAccountsAffected
+	if gasUint64 < 2*types.GasSendTxPerAccount {
+		gasUint64 = 2 * types.GasSendTxPerAccount // to prevent spamming with invalid transactions, e.g. empty inputs/outputs
+	}
+	gas := new(big.Int).SetUint64(gasUint64)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
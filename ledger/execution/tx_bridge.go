@@ -0,0 +1,297 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*BridgeLockTxExecutor)(nil)
+var _ TxExecutor = (*BridgeBurnTxExecutor)(nil)
+var _ TxExecutor = (*BridgeClaimTxExecutor)(nil)
+
+// ------------------------------- BridgeLock Transaction -----------------------------------
+
+// BridgeLockTxExecutor implements the TxExecutor interface
+type BridgeLockTxExecutor struct {
+}
+
+// NewBridgeLockTxExecutor creates a new instance of BridgeLockTxExecutor
+func NewBridgeLockTxExecutor() *BridgeLockTxExecutor {
+	return &BridgeLockTxExecutor{}
+}
+
+func (exec *BridgeLockTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.BridgeLockTx)
+
+	res := tx.Sender.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	senderAccount, success := getInput(view, tx.Sender)
+	if success.IsError() {
+		return result.Error("Failed to get the sender account: %v", tx.Sender.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(senderAccount, signBytes, altSignBytes, tx.Sender)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Sender.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	coins := tx.Sender.Coins.NoNil()
+	if !coins.IsValid() || !coins.IsPositive() {
+		return result.Error("Invalid coins to lock in the bridge: %v", coins).WithErrorCode(result.CodeInvalidBridgeTx)
+	}
+	if err := types.CheckBridgeChainID(tx.DestChain); err != nil {
+		return result.Error("%v", err).WithErrorCode(result.CodeInvalidBridgeTx)
+	}
+	if tx.Recipient == (common.Address{}) {
+		return result.Error("The recipient on the foreign chain is not specified").WithErrorCode(result.CodeInvalidBridgeTx)
+	}
+
+	minimalBalance := coins.Plus(tx.Fee)
+	if !senderAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("BridgeLock: Sender did not have enough balance %v", tx.Sender.Address.Hex()))
+		return result.Error("BridgeLock: Sender balance is %v, but required minimal balance is %v",
+			senderAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *BridgeLockTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.BridgeLockTx)
+
+	senderAccount, success := getInput(view, tx.Sender)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the sender account")
+	}
+
+	if !chargeFee(senderAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	coins := tx.Sender.Coins.NoNil()
+	senderAccount.Balance = senderAccount.Balance.Minus(coins)
+	senderAccount.Sequence++
+	view.SetAccount(tx.Sender.Address, senderAccount)
+
+	view.SetBridgeCustody(view.GetBridgeCustody().Plus(coins))
+	view.AddBridgeMessage(&types.BridgeMessage{
+		Kind:      types.BridgeMessageLock,
+		Sender:    tx.Sender.Address,
+		DestChain: tx.DestChain,
+		Recipient: tx.Recipient,
+		Coins:     coins,
+		Height:    view.Height() + 1,
+	})
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *BridgeLockTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.BridgeLockTx)
+	return &core.TxInfo{
+		Address:           tx.Sender.Address,
+		Sequence:          tx.Sender.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *BridgeLockTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.BridgeLockTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasBridgeLockTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- BridgeBurn Transaction -----------------------------------
+
+// BridgeBurnTxExecutor implements the TxExecutor interface
+type BridgeBurnTxExecutor struct {
+}
+
+// NewBridgeBurnTxExecutor creates a new instance of BridgeBurnTxExecutor
+func NewBridgeBurnTxExecutor() *BridgeBurnTxExecutor {
+	return &BridgeBurnTxExecutor{}
+}
+
+func (exec *BridgeBurnTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.BridgeBurnTx)
+
+	res := validateFeePayer(chainID, view, tx, tx.Sender, tx.Fee)
+	if res.IsError() {
+		return res
+	}
+
+	if err := types.CheckBridgeChainID(tx.SourceChain); err != nil {
+		return result.Error("%v", err).WithErrorCode(result.CodeInvalidBridgeTx)
+	}
+	if tx.Recipient == (common.Address{}) {
+		return result.Error("The recipient on the foreign chain is not specified").WithErrorCode(result.CodeInvalidBridgeTx)
+	}
+	if tx.Amount == nil || tx.Amount.Sign() <= 0 {
+		return result.Error("Invalid amount to burn: %v", tx.Amount).WithErrorCode(result.CodeInvalidBridgeTx)
+	}
+
+	asset := types.WrappedAssetID(tx.SourceChain, tx.Token)
+	balance := view.GetWrappedBalance(asset, tx.Sender.Address)
+	if balance.Cmp(tx.Amount) < 0 {
+		return result.Error("BridgeBurn: Wrapped balance is %v, but %v is burned", balance, tx.Amount).
+			WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *BridgeBurnTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.BridgeBurnTx)
+
+	if res := chargeFeePayer(view, tx.Sender, tx.Fee); res.IsError() {
+		return common.Hash{}, res
+	}
+
+	asset := types.WrappedAssetID(tx.SourceChain, tx.Token)
+	balance := view.GetWrappedBalance(asset, tx.Sender.Address)
+	view.SetWrappedBalance(asset, tx.Sender.Address, balance.Sub(balance, tx.Amount))
+	supply := view.GetWrappedSupply(asset)
+	view.SetWrappedSupply(asset, supply.Sub(supply, tx.Amount))
+
+	view.AddBridgeMessage(&types.BridgeMessage{
+		Kind:      types.BridgeMessageBurn,
+		Sender:    tx.Sender.Address,
+		DestChain: tx.SourceChain,
+		Recipient: tx.Recipient,
+		Coins:     types.NewCoins(0, 0),
+		Token:     tx.Token,
+		Amount:    new(big.Int).Set(tx.Amount),
+		Height:    view.Height() + 1,
+	})
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *BridgeBurnTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.BridgeBurnTx)
+	return &core.TxInfo{
+		Address:           tx.Sender.Address,
+		Sequence:          tx.Sender.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *BridgeBurnTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.BridgeBurnTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasBridgeBurnTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- BridgeClaim Transaction -----------------------------------
+
+// BridgeClaimTxExecutor implements the TxExecutor interface
+type BridgeClaimTxExecutor struct {
+}
+
+// NewBridgeClaimTxExecutor creates a new instance of BridgeClaimTxExecutor
+func NewBridgeClaimTxExecutor() *BridgeClaimTxExecutor {
+	return &BridgeClaimTxExecutor{}
+}
+
+func (exec *BridgeClaimTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.BridgeClaimTx)
+
+	res := validateFeePayer(chainID, view, tx, tx.Relayer, tx.Fee)
+	if res.IsError() {
+		return res
+	}
+
+	event := &tx.Proof.Event
+	if view.IsForeignEventClaimed(event.ID()) {
+		return result.Error("The event %v of transaction %v on %v has already been claimed",
+			event.LogIndex, event.TxHash.Hex(), event.SourceChain).WithErrorCode(result.CodeForeignEventAlreadyUsed)
+	}
+
+	validators := types.BridgeValidators(view.GetValidatorCandidatePool())
+	err := types.VerifyForeignProof(chainID, &tx.Proof, validators, view.BridgeAttestationThreshold())
+	if err != nil {
+		return result.Error("Invalid foreign proof: %v", err).WithErrorCode(result.CodeInvalidForeignProof)
+	}
+
+	if event.IsRelease() {
+		coins := event.Coins.NoNil()
+		if !view.GetBridgeCustody().IsGTE(coins) {
+			return result.Error("The bridge holds %v, which does not cover the release of %v",
+				view.GetBridgeCustody(), coins).WithErrorCode(result.CodeInvalidBridgeTx)
+		}
+		recipientAccount := view.GetAccount(event.Recipient)
+		if recipientAccount != nil && recipientAccount.IsASmartContract() {
+			return result.Error(
+				fmt.Sprintf("Releasing Pando/PTX to a smart contract (%v) through a BridgeClaimTx transaction is not allowed", event.Recipient))
+		}
+	}
+
+	return result.OK
+}
+
+func (exec *BridgeClaimTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.BridgeClaimTx)
+
+	if res := chargeFeePayer(view, tx.Relayer, tx.Fee); res.IsError() {
+		return common.Hash{}, res
+	}
+
+	event := &tx.Proof.Event
+	view.MarkForeignEventClaimed(event.ID())
+	if event.IsRelease() {
+		coins := event.Coins.NoNil()
+		view.SetBridgeCustody(view.GetBridgeCustody().Minus(coins))
+		account := getOrMakeAccount(view, event.Recipient)
+		account.Balance = account.Balance.Plus(coins)
+		view.SetAccount(event.Recipient, account)
+	} else {
+		asset := types.WrappedAssetID(event.SourceChain, event.Token)
+		balance := view.GetWrappedBalance(asset, event.Recipient)
+		view.SetWrappedBalance(asset, event.Recipient, balance.Add(balance, event.Amount))
+		supply := view.GetWrappedSupply(asset)
+		view.SetWrappedSupply(asset, supply.Add(supply, event.Amount))
+	}
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *BridgeClaimTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.BridgeClaimTx)
+	return &core.TxInfo{
+		Address:           tx.Relayer.Address,
+		Sequence:          tx.Relayer.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *BridgeClaimTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.BridgeClaimTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(tx.Gas())
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
@@ -0,0 +1,136 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*ClaimEscrowTxExecutor)(nil)
+
+// ------------------------------- ClaimEscrow Transaction -----------------------------------
+
+// ClaimEscrowTxExecutor implements the TxExecutor interface
+type ClaimEscrowTxExecutor struct {
+}
+
+// NewClaimEscrowTxExecutor creates a new instance of ClaimEscrowTxExecutor
+func NewClaimEscrowTxExecutor() *ClaimEscrowTxExecutor {
+	return &ClaimEscrowTxExecutor{}
+}
+
+func (exec *ClaimEscrowTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.ClaimEscrowTx)
+
+	res := tx.Escrow.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+	if tx.Escrow.Signature != nil || tx.Escrow.MultiSig != nil {
+		return result.Error("The escrow input should not carry a signature, the signatures of the clause are attached to the transaction").
+			WithErrorCode(result.CodeInvalidEscrowClaim)
+	}
+	if len(tx.Preimage) > types.MaximumEscrowPreimageLength {
+		return result.Error("Preimage too long, at most %v bytes are allowed", types.MaximumEscrowPreimageLength).
+			WithErrorCode(result.CodeInvalidEscrowClaim)
+	}
+
+	if err := types.ValidateEscrowClauses(tx.Clauses); err != nil {
+		return result.Error("%v", err).WithErrorCode(result.CodeInvalidEscrowClaim)
+	}
+	if types.EscrowAddress(tx.Clauses) != tx.Escrow.Address {
+		return result.Error("The clauses do not match the escrow address %v", tx.Escrow.Address.Hex()).
+			WithErrorCode(result.CodeInvalidEscrowClaim)
+	}
+	clause, ok := tx.Clause()
+	if !ok {
+		return result.Error("Invalid clause index %v, the escrow has %v clauses", tx.ClauseIndex, len(tx.Clauses)).
+			WithErrorCode(result.CodeInvalidEscrowClaim)
+	}
+
+	blockHeight := view.Height() + 1
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	if err := clause.Verify(blockHeight, tx.Preimage, tx.Signatures, append([]common.Bytes{signBytes}, altSignBytes...)...); err != nil {
+		return result.Error("Clause %v is not satisfied: %v", tx.ClauseIndex, err).WithErrorCode(result.CodeInvalidEscrowClaim)
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	escrowAccount, success := getInput(view, tx.Escrow)
+	if success.IsError() {
+		return result.Error("Failed to get the escrow account: %v", tx.Escrow.Address)
+	}
+	if escrowAccount.Sequence+1 != tx.Escrow.Sequence {
+		return result.Error("ValidateInputAdvanced: Got %v, expected %v. (acc.seq=%v)",
+			tx.Escrow.Sequence, escrowAccount.Sequence+1, escrowAccount.Sequence).WithErrorCode(result.CodeInvalidSequence)
+	}
+	minimalBalance := tx.Escrow.Coins.Plus(tx.Fee)
+	if !escrowAccount.Balance.IsGTE(minimalBalance) {
+		return result.Error("ClaimEscrow: Escrow balance is %v, but required minimal balance is %v",
+			escrowAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	recipientAccount := view.GetAccount(clause.Recipient)
+	if recipientAccount != nil && recipientAccount.IsASmartContract() {
+		return result.Error(
+			fmt.Sprintf("Sending Pando/PTX to a smart contract (%v) through a ClaimEscrowTx transaction is not allowed", clause.Recipient))
+	}
+
+	return result.OK
+}
+
+func (exec *ClaimEscrowTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.ClaimEscrowTx)
+
+	clause, ok := tx.Clause()
+	if !ok {
+		return common.Hash{}, result.Error("Invalid clause index %v", tx.ClauseIndex)
+	}
+
+	escrowAccount, success := getInput(view, tx.Escrow)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the escrow account")
+	}
+
+	if !chargeFee(escrowAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	coins := tx.Escrow.Coins.NoNil()
+	escrowAccount.Balance = escrowAccount.Balance.Minus(coins)
+	escrowAccount.Sequence++
+	view.SetAccount(tx.Escrow.Address, escrowAccount)
+
+	recipientAccount := getOrMakeAccount(view, clause.Recipient)
+	recipientAccount.Balance = recipientAccount.Balance.Plus(coins)
+	view.SetAccount(clause.Recipient, recipientAccount)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *ClaimEscrowTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.ClaimEscrowTx)
+	return &core.TxInfo{
+		Address:           tx.Escrow.Address,
+		Sequence:          tx.Escrow.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *ClaimEscrowTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.ClaimEscrowTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasClaimEscrowTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
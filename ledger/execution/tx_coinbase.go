@@ -13,14 +13,16 @@ import (
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 	"github.com/pandotoken/pando/store/database"
 )
 
 var weiMultiplier = big.NewInt(1e18)
-var ptxRewardPerBlock = big.NewInt(1).Mul(big.NewInt(48), weiMultiplier) // 48 PTX per block, corresponds to about 5% *initial* annual inflation rate. The inflation rate naturally approaches 0 as the chain grows.
-var ptxRewardN = 400                                                     // Reward receiver sampling params
+var ptxRewardPerBlock = big.NewInt(1).Mul(big.NewInt(48), weiMultiplier)     // 48 PTX per block, corresponds to about 5% *initial* annual inflation rate. The inflation rate naturally approaches 0 as the chain grows.
+var rametronRewardPerBlock = big.NewInt(1).Mul(big.NewInt(4), weiMultiplier) // 4 PTX per block, shared by the eligible Rametron nodes
+var ptxRewardN = 400                                                         // Reward receiver sampling params
 
 var _ TxExecutor = (*CoinbaseTxExecutor)(nil)
 
@@ -94,7 +96,7 @@ func (exec *CoinbaseTxExecutor) sanityCheck(chainID string, view *st.StoreView,
 	var expectedRewards map[string]types.Coins
 	guardianVotes := exec.consensus.GetLedger().GetCurrentBlock().GuardianVotes
 
-	if tx.BlockHeight < common.HeightEnablePando2 || guardianVotes == nil {
+	if !features.IsEnabled(features.Pando2, tx.BlockHeight) || guardianVotes == nil {
 		expectedRewards = CalculateReward(exec.consensus.GetLedger(), view, validatorSet, nil, nil)
 	} else {
 		guradianVoteBlock, err := exec.chain.FindBlock(guardianVotes.Block)
@@ -140,6 +142,12 @@ func (exec *CoinbaseTxExecutor) process(chainID string, view *st.StoreView, tran
 		}
 	}
 
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if features.IsEnabled(features.Delegation, blockHeight) {
+		validatorSet := getValidatorSet(exec.consensus.GetLedger(), exec.valMgr)
+		attributeDelegationRewards(view, validatorSet, tx.Outputs)
+	}
+
 	view.SetCoinbaseTransactionProcessed(true)
 
 	txHash := types.TxID(chainID, tx)
@@ -148,19 +156,36 @@ func (exec *CoinbaseTxExecutor) process(chainID string, view *st.StoreView, tran
 
 // CalculateReward calculates the block reward for each account
 func CalculateReward(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet, guardianVotes *core.AggregatedVotes, guardianPool *core.GuardianCandidatePool) map[string]types.Coins {
+	accountReward, _ := CalculateRewardWithCommissions(ledger, view, validatorSet, guardianVotes, guardianPool)
+	return accountReward
+}
+
+// CalculateRewardWithCommissions calculates the block reward for each account like CalculateReward,
+// and also returns the commission each validator earned, which is included in the validator's reward
+func CalculateRewardWithCommissions(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet, guardianVotes *core.AggregatedVotes,
+	guardianPool *core.GuardianCandidatePool) (map[string]types.Coins, map[common.Address]*big.Int) {
 	accountReward := map[string]types.Coins{}
+	commissions := map[common.Address]*big.Int{}
 	blockHeight := view.Height() + 1 // view points to the parent block
-	if blockHeight < common.HeightEnableValidatorReward {
+	if !features.IsEnabled(features.ValidatorReward, blockHeight) {
 		grantValidatorsWithZeroReward(validatorSet, &accountReward)
-	} else if blockHeight < common.HeightEnablePando2 || guardianVotes == nil || guardianPool == nil {
+	} else if !features.IsEnabled(features.Pando2, blockHeight) || guardianVotes == nil || guardianPool == nil {
 		grantValidatorReward(ledger, view, validatorSet, &accountReward, blockHeight)
-	} else if blockHeight < common.HeightSampleStakingReward {
+	} else if !features.IsEnabled(features.SampleStakingReward, blockHeight) {
 		grantStakerReward(ledger, view, validatorSet, guardianVotes, guardianPool, &accountReward, blockHeight)
 	} else {
 		grantStakerRewardRandomized(ledger, view, validatorSet, guardianVotes, guardianPool, &accountReward, blockHeight)
 	}
 
-	return accountReward
+	if features.IsEnabled(features.ValidatorCommission, blockHeight) {
+		commissions = applyValidatorCommissions(view, validatorSet, guardianVotes, guardianPool, &accountReward)
+	}
+
+	if features.IsEnabled(features.RametronTiers, blockHeight) {
+		grantRametronReward(view, &accountReward, blockHeight)
+	}
+
+	return accountReward, commissions
 }
 
 func grantValidatorsWithZeroReward(validatorSet *core.ValidatorSet, accountReward *map[string]types.Coins) {
@@ -0,0 +1,320 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*DelegateTxExecutor)(nil)
+var _ TxExecutor = (*UndelegateTxExecutor)(nil)
+
+// ------------------------------- Delegate Transaction -----------------------------------
+
+// DelegateTxExecutor implements the TxExecutor interface
+type DelegateTxExecutor struct {
+}
+
+// NewDelegateTxExecutor creates a new instance of DelegateTxExecutor
+func NewDelegateTxExecutor() *DelegateTxExecutor {
+	return &DelegateTxExecutor{}
+}
+
+func (exec *DelegateTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.DelegateTx)
+
+	res := tx.Delegator.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	delegatorAccount, success := getInput(view, tx.Delegator)
+	if success.IsError() {
+		return result.Error("Failed to get the delegator account: %v", tx.Delegator.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(delegatorAccount, signBytes, altSignBytes, tx.Delegator)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Delegator.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	stake := tx.Delegator.Coins.NoNil()
+	if !stake.IsValid() || !stake.IsNonnegative() || stake.PTXWei.Sign() != 0 {
+		return result.Error("Invalid stake for delegation, only PandoWei can be delegated").
+			WithErrorCode(result.CodeInvalidStake)
+	}
+	if stake.PandoWei.Cmp(core.MinDelegationDeposit) < 0 {
+		return result.Error("Insufficient amount of stake, at least %v PandoWei is required for each delegation", core.MinDelegationDeposit).
+			WithErrorCode(result.CodeInsufficientStake)
+	}
+
+	if tx.Delegator.Address == tx.Validator {
+		return result.Error("A validator cannot delegate to itself, use a DepositStakeTx instead")
+	}
+
+	vcp := view.GetValidatorCandidatePool()
+	candidate := vcp.FindStakeDelegate(tx.Validator)
+	if candidate == nil {
+		return result.Error("%v is not a validator candidate", tx.Validator.Hex())
+	}
+	for _, s := range candidate.Stakes {
+		if s.Source == tx.Delegator.Address && s.Withdrawn {
+			return result.Error("Cannot delegate during the withdrawal locking period of the previous stake")
+		}
+	}
+
+	minimalBalance := stake.Plus(tx.Fee)
+	if !delegatorAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("Delegate: Delegator did not have enough balance %v", tx.Delegator.Address.Hex()))
+		return result.Error("Delegate: Delegator balance is %v, but required minimal balance is %v",
+			delegatorAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientStake)
+	}
+
+	return result.OK
+}
+
+func (exec *DelegateTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.DelegateTx)
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+
+	delegatorAccount, success := getInput(view, tx.Delegator)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the delegator account")
+	}
+
+	if !chargeFee(delegatorAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	stake := tx.Delegator.Coins.NoNil()
+	if !delegatorAccount.Balance.IsGTE(stake) {
+		return common.Hash{}, result.Error("Not enough balance to stake").WithErrorCode(result.CodeNotEnoughBalanceToStake)
+	}
+	delegatorAccount.Balance = delegatorAccount.Balance.Minus(stake)
+
+	delegatorAddress := tx.Delegator.Address
+	vcp := view.GetValidatorCandidatePool()
+	if err := vcp.Delegate(delegatorAddress, tx.Validator, stake.PandoWei); err != nil {
+		return common.Hash{}, result.Error("Failed to delegate stake, err: %v", err)
+	}
+	view.UpdateValidatorCandidatePool(vcp)
+
+	delegations := view.GetDelegations(delegatorAddress)
+	delegations.Add(tx.Validator, stake.PandoWei, blockHeight)
+	view.UpdateDelegations(delegatorAddress, delegations)
+
+	// The delegation changes the stake of the validator
+	hl := view.GetStakeTransactionHeightList()
+	if hl == nil {
+		hl = &types.HeightList{}
+	}
+	hl.Append(blockHeight)
+	view.UpdateStakeTransactionHeightList(hl)
+
+	delegatorAccount.Sequence++
+	view.SetAccount(delegatorAddress, delegatorAccount)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *DelegateTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.DelegateTx)
+	return &core.TxInfo{
+		Address:           tx.Delegator.Address,
+		Sequence:          tx.Delegator.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *DelegateTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.DelegateTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasDelegateTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- Undelegate Transaction -----------------------------------
+
+// UndelegateTxExecutor implements the TxExecutor interface
+type UndelegateTxExecutor struct {
+	state *st.LedgerState
+}
+
+// NewUndelegateTxExecutor creates a new instance of UndelegateTxExecutor
+func NewUndelegateTxExecutor(state *st.LedgerState) *UndelegateTxExecutor {
+	return &UndelegateTxExecutor{
+		state: state,
+	}
+}
+
+func (exec *UndelegateTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.UndelegateTx)
+
+	res := tx.Delegator.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	delegatorAccount, success := getInput(view, tx.Delegator)
+	if success.IsError() {
+		return result.Error("Failed to get the delegator account: %v", tx.Delegator.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(delegatorAccount, signBytes, altSignBytes, tx.Delegator)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Delegator.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if view.GetDelegations(tx.Delegator.Address).Get(tx.Validator) == nil {
+		return result.Error("No delegation from %v to %v", tx.Delegator.Address.Hex(), tx.Validator.Hex())
+	}
+
+	minimalBalance := tx.Fee
+	if !delegatorAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("Undelegate: Delegator did not have enough balance %v", tx.Delegator.Address.Hex()))
+		return result.Error("Undelegate: Delegator balance is %v, but required minimal balance is %v",
+			delegatorAccount.Balance, minimalBalance)
+	}
+
+	return result.OK
+}
+
+// NOTE: UndelegateTxExecutor.process() does NOT return the stake to the delegator. Like a
+// withdrawn stake, it is added to the unbonding queue and returned after the unbonding period.
+func (exec *UndelegateTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.UndelegateTx)
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+
+	delegatorAccount, success := getInput(view, tx.Delegator)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the delegator account")
+	}
+
+	if !chargeFee(delegatorAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	delegatorAddress := tx.Delegator.Address
+	res := withdrawStakeToUnbondingQueue(view, exec.state.Height(), delegatorAddress, tx.Validator, core.StakeForValidator)
+	if res.IsError() {
+		return common.Hash{}, res
+	}
+
+	delegations := view.GetDelegations(delegatorAddress)
+	delegations.Remove(tx.Validator)
+	view.UpdateDelegations(delegatorAddress, delegations)
+
+	hl := view.GetStakeTransactionHeightList()
+	if hl == nil {
+		hl = &types.HeightList{}
+	}
+	hl.Append(blockHeight)
+	view.UpdateStakeTransactionHeightList(hl)
+
+	delegatorAccount.Sequence++
+	view.SetAccount(delegatorAddress, delegatorAccount)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *UndelegateTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.UndelegateTx)
+	return &core.TxInfo{
+		Address:           tx.Delegator.Address,
+		Sequence:          tx.Delegator.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *UndelegateTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.UndelegateTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasUndelegateTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// attributeDelegationRewards attributes the block reward of each delegator to its delegations,
+// pro-rata to their stakes among the stakes the delegator is rewarded for, i.e. its stakes to the
+// current validators and to the guardians.
+func attributeDelegationRewards(view *st.StoreView, validatorSet *core.ValidatorSet, outputs []types.TxOutput) {
+	var vcp *core.ValidatorCandidatePool
+	var gcp *core.GuardianCandidatePool
+	for _, output := range outputs {
+		reward := output.Coins.NoNil().PTXWei
+		if reward.Sign() <= 0 {
+			continue
+		}
+		delegations := view.GetDelegations(output.Address)
+		if delegations.Len() == 0 {
+			continue
+		}
+
+		if vcp == nil {
+			vcp = view.GetValidatorCandidatePool()
+			gcp = view.GetGuardianCandidatePool()
+		}
+
+		stakes := map[common.Address]*big.Int{}
+		totalStake := big.NewInt(0)
+		for _, v := range validatorSet.Validators() {
+			holder := vcp.FindStakeDelegate(v.Address)
+			if holder == nil {
+				continue
+			}
+			for _, stake := range holder.Stakes {
+				if stake.Source == output.Address && !stake.Withdrawn {
+					stakes[v.Address] = stake.Amount
+					totalStake.Add(totalStake, stake.Amount)
+				}
+			}
+		}
+		if gcp != nil {
+			for _, g := range gcp.SortedGuardians {
+				for _, stake := range g.Stakes {
+					if stake.Source == output.Address && !stake.Withdrawn {
+						totalStake.Add(totalStake, stake.Amount)
+					}
+				}
+			}
+		}
+		if totalStake.Sign() == 0 {
+			continue
+		}
+
+		for _, d := range delegations.Delegations {
+			stake, ok := stakes[d.Validator]
+			if !ok {
+				continue
+			}
+			share := new(big.Int).Mul(reward, stake)
+			share.Div(share, totalStake)
+			d.Rewards = new(big.Int).Add(d.Rewards, share)
+		}
+		view.UpdateDelegations(output.Address, delegations)
+	}
+}
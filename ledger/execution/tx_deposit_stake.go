@@ -7,6 +7,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 )
@@ -27,7 +28,7 @@ func NewDepositStakeExecutor() *DepositStakeExecutor {
 func (exec *DepositStakeExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
 	// Feature block height check
 	blockHeight := view.Height() + 1 // the view points to the parent of the current block
-	if _, ok := transaction.(*types.DepositStakeTxV2); ok && blockHeight < common.HeightEnablePando2 {
+	if _, ok := transaction.(*types.DepositStakeTxV2); ok && !features.IsEnabled(features.Pando2, blockHeight) {
 		return result.Error("Feature guardian is not active yet")
 	}
 
@@ -44,15 +45,16 @@ func (exec *DepositStakeExecutor) sanityCheck(chainID string, view *st.StoreView
 	}
 
 	signBytes := tx.SignBytes(chainID)
-	res = validateInputAdvanced(sourceAccount, signBytes, tx.Source)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(sourceAccount, signBytes, altSignBytes, tx.Source)
 	if res.IsError() {
 		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Source.Address.Hex(), res))
 		return res
 	}
 
-	if !sanityCheckForFee(tx.Fee) {
+	if !sanityCheckForFee(view, tx.Fee) {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
-			types.MinimumTransactionFeePTXWei).WithErrorCode(result.CodeInvalidFee)
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
 	}
 
 	if !(tx.Purpose == core.StakeForValidator || tx.Purpose == core.StakeForGuardian) {
@@ -60,6 +62,10 @@ func (exec *DepositStakeExecutor) sanityCheck(chainID string, view *st.StoreView
 			WithErrorCode(result.CodeInvalidStakePurpose)
 	}
 
+	if tx.Purpose == core.StakeForValidator && !tx.BlsPubkey.IsEmpty() && !features.IsEnabled(features.AggregatedVotes, blockHeight) {
+		return result.Error("Feature validator BLS key is not active yet")
+	}
+
 	stake := tx.Source.Coins.NoNil()
 	if !stake.IsValid() || !stake.IsNonnegative() {
 		return result.Error("Invalid stake for stake deposit!").
@@ -72,14 +78,15 @@ func (exec *DepositStakeExecutor) sanityCheck(chainID string, view *st.StoreView
 //	}
 
 	// Minimum stake deposit requirement to avoid spamming
-	if tx.Purpose == core.StakeForValidator && stake.PTXWei.Cmp(core.MinValidatorStakeDeposit) < 0 {
-		return result.Error("Insufficient amount of stake, at least %v PandoWei is required for each validator deposit", core.MinValidatorStakeDeposit).
+	minValidatorStake := view.MinValidatorStakeDeposit()
+	if tx.Purpose == core.StakeForValidator && stake.PTXWei.Cmp(minValidatorStake) < 0 {
+		return result.Error("Insufficient amount of stake, at least %v PandoWei is required for each validator deposit", minValidatorStake).
 			WithErrorCode(result.CodeInsufficientStake)
 	}
 
 	if tx.Purpose == core.StakeForGuardian {
 		minGuardianStake := core.MinGuardianStakeDeposit
-		if blockHeight >= common.HeightLowerGNStakeThresholdTo1000 {
+		if features.IsEnabled(features.LowerGNStakeThresholdTo1000, blockHeight) {
 			minGuardianStake = core.MinGuardianStakeDeposit1000
 		}
 		if stake.PTXWei.Cmp(minGuardianStake) < 0 {
@@ -121,6 +128,13 @@ func (exec *DepositStakeExecutor) process(chainID string, view *st.StoreView, tr
 	holderAddress := tx.Holder.Address
 
 	if tx.Purpose == core.StakeForValidator {
+		// The validator optionally registers the BLS key it signs the aggregatable votes with
+		if !tx.BlsPubkey.IsEmpty() {
+			if res := validateBlsKeyInfo(tx); res.IsError() {
+				return common.Hash{}, res
+			}
+		}
+
 		sourceAccount.Balance = sourceAccount.Balance.Minus(stake)
 		stakeAmount := stake.PTXWei
 		vcp := view.GetValidatorCandidatePool()
@@ -128,6 +142,11 @@ func (exec *DepositStakeExecutor) process(chainID string, view *st.StoreView, tr
 		if err != nil {
 			return common.Hash{}, result.Error("Failed to deposit stake, err: %v", err)
 		}
+		if !tx.BlsPubkey.IsEmpty() {
+			if err := vcp.SetBlsPubkey(holderAddress, tx.BlsPubkey); err != nil {
+				return common.Hash{}, result.Error("Failed to register the BLS key, err: %v", err)
+			}
+		}
 		view.UpdateValidatorCandidatePool(vcp)
 	} else if tx.Purpose == core.StakeForGuardian {
 		sourceAccount.Balance = sourceAccount.Balance.Minus(stake)
@@ -135,22 +154,8 @@ func (exec *DepositStakeExecutor) process(chainID string, view *st.StoreView, tr
 		gcp := view.GetGuardianCandidatePool()
 
 		if !gcp.Contains(holderAddress) {
-			if tx.BlsPubkey.IsEmpty() {
-				return common.Hash{}, result.Error("Must provide BLS Pubkey")
-			}
-			if tx.BlsPop.IsEmpty() {
-				return common.Hash{}, result.Error("Must provide BLS POP")
-			}
-			if tx.HolderSig == nil || tx.HolderSig.IsEmpty() {
-				return common.Hash{}, result.Error("Must provide Holder Signature")
-			}
-
-			if !tx.HolderSig.Verify(tx.BlsPop.ToBytes(), tx.Holder.Address) {
-				return common.Hash{}, result.Error("BLS key info is not properly signed")
-			}
-
-			if !tx.BlsPop.PopVerify(tx.BlsPubkey) {
-				return common.Hash{}, result.Error("BLS pop is invalid")
+			if res := validateBlsKeyInfo(tx); res.IsError() {
+				return common.Hash{}, res
 			}
 		}
 
@@ -212,3 +217,26 @@ func (exec *DepositStakeExecutor) castTx(transaction types.Tx) *types.DepositSta
 	}
 	panic("Unreachable code")
 }
+
+// validateBlsKeyInfo checks that the BLS key of the transaction is signed by the holder, along with
+// the proof of possession of the key.
+func validateBlsKeyInfo(tx *types.DepositStakeTxV2) result.Result {
+	if tx.BlsPubkey.IsEmpty() {
+		return result.Error("Must provide BLS Pubkey")
+	}
+	if tx.BlsPop.IsEmpty() {
+		return result.Error("Must provide BLS POP")
+	}
+	if tx.HolderSig == nil || tx.HolderSig.IsEmpty() {
+		return result.Error("Must provide Holder Signature")
+	}
+
+	if !tx.HolderSig.Verify(tx.BlsPop.ToBytes(), tx.Holder.Address) {
+		return result.Error("BLS key info is not properly signed")
+	}
+
+	if !tx.BlsPop.PopVerify(tx.BlsPubkey) {
+		return result.Error("BLS pop is invalid")
+	}
+	return result.OK
+}
@@ -0,0 +1,244 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*ProposalTxExecutor)(nil)
+var _ TxExecutor = (*VoteTxExecutor)(nil)
+
+// ------------------------------- Proposal Transaction -----------------------------------
+
+// ProposalTxExecutor implements the TxExecutor interface
+type ProposalTxExecutor struct {
+}
+
+// NewProposalTxExecutor creates a new instance of ProposalTxExecutor
+func NewProposalTxExecutor() *ProposalTxExecutor {
+	return &ProposalTxExecutor{}
+}
+
+func (exec *ProposalTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.ProposalTx)
+
+	res := tx.Proposer.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	proposerAccount, success := getInput(view, tx.Proposer)
+	if success.IsError() {
+		return result.Error("Failed to get the proposer account: %v", tx.Proposer.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(proposerAccount, signBytes, altSignBytes, tx.Proposer)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Proposer.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if err := types.CheckGovernableParam(tx.Name, tx.Value); err != nil {
+		return result.Error("%v", err).WithErrorCode(result.CodeInvalidGovernanceProposal)
+	}
+
+	deposit := tx.Proposer.Coins.NoNil()
+	if deposit.PandoWei.Sign() != 0 || deposit.PTXWei.Cmp(types.MinimumGovernanceDeposit()) < 0 {
+		return result.Error("The deposit needs to be at least %v PTXWei, and cannot contain Pando",
+			types.MinimumGovernanceDeposit()).WithErrorCode(result.CodeInvalidGovernanceProposal)
+	}
+
+	if view.GetGovernanceProposals().Len() >= types.MaxPendingGovernanceProposals {
+		return result.Error("There are already %v pending proposals", types.MaxPendingGovernanceProposals).
+			WithErrorCode(result.CodeInvalidGovernanceProposal)
+	}
+
+	minimalBalance := deposit.Plus(tx.Fee)
+	if !proposerAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("Proposal: Proposer did not have enough balance %v", tx.Proposer.Address.Hex()))
+		return result.Error("Proposal: Proposer balance is %v, but required minimal balance is %v",
+			proposerAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *ProposalTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.ProposalTx)
+
+	proposerAccount, success := getInput(view, tx.Proposer)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the proposer account")
+	}
+
+	if !chargeFee(proposerAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	// Lock the deposit until the proposal is tallied
+	deposit := tx.Proposer.Coins.NoNil()
+	proposerAccount.Balance = proposerAccount.Balance.Minus(deposit)
+	proposerAccount.Sequence++
+	view.SetAccount(tx.Proposer.Address, proposerAccount)
+
+	submitHeight := view.Height() + 1
+	proposal := &types.GovernanceProposal{
+		ID:           tx.ProposalID(),
+		Proposer:     tx.Proposer.Address,
+		Name:         tx.Name,
+		Value:        new(big.Int).Set(tx.Value),
+		Deposit:      deposit,
+		SubmitHeight: submitHeight,
+		TallyHeight:  types.GovernanceTallyHeight(submitHeight),
+		Votes:        []types.GovernanceVote{},
+	}
+	proposals := view.GetGovernanceProposals()
+	proposals.Add(proposal)
+	view.UpdateGovernanceProposals(proposals)
+
+	logger.Infof("Governance proposal submitted: %v", proposal)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *ProposalTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.ProposalTx)
+	return &core.TxInfo{
+		Address:           tx.Proposer.Address,
+		Sequence:          tx.Proposer.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *ProposalTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.ProposalTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasProposalTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- Vote Transaction -----------------------------------
+
+// VoteTxExecutor implements the TxExecutor interface
+type VoteTxExecutor struct {
+}
+
+// NewVoteTxExecutor creates a new instance of VoteTxExecutor
+func NewVoteTxExecutor() *VoteTxExecutor {
+	return &VoteTxExecutor{}
+}
+
+func (exec *VoteTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.VoteTx)
+
+	res := tx.Voter.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	voterAccount, success := getInput(view, tx.Voter)
+	if success.IsError() {
+		return result.Error("Failed to get the voter account: %v", tx.Voter.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(voterAccount, signBytes, altSignBytes, tx.Voter)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Voter.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if !tx.Voter.Coins.NoNil().IsZero() {
+		return result.Error("The votes cannot carry coins").WithErrorCode(result.CodeInvalidGovernanceProposal)
+	}
+
+	if !voterAccount.Balance.IsGTE(tx.Fee) {
+		logger.Infof(fmt.Sprintf("Vote: Voter did not have enough balance %v", tx.Voter.Address.Hex()))
+		return result.Error("Vote: Voter balance is %v, but required minimal balance is %v",
+			voterAccount.Balance, tx.Fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	if types.GovernanceVotingStake(view.GetValidatorCandidatePool(), tx.Voter.Address).Sign() == 0 {
+		return result.Error("%v has no stake to vote with", tx.Voter.Address.Hex()).
+			WithErrorCode(result.CodeInvalidGovernanceProposal)
+	}
+
+	proposal := view.GetGovernanceProposals().Get(tx.ProposalID)
+	if proposal == nil {
+		return result.Error("No pending proposal found with ID %v", tx.ProposalID.Hex()).
+			WithErrorCode(result.CodeInvalidGovernanceProposal)
+	}
+
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if blockHeight >= proposal.TallyHeight {
+		return result.Error("The voting on the proposal ended at height %v", proposal.TallyHeight).
+			WithErrorCode(result.CodeInvalidGovernanceProposal)
+	}
+
+	return result.OK
+}
+
+func (exec *VoteTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.VoteTx)
+
+	voterAccount, success := getInput(view, tx.Voter)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the voter account")
+	}
+
+	if !chargeFee(voterAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	voterAccount.Sequence++
+	view.SetAccount(tx.Voter.Address, voterAccount)
+
+	proposals := view.GetGovernanceProposals()
+	proposal := proposals.Get(tx.ProposalID)
+	if proposal == nil {
+		return common.Hash{}, result.Error("No pending proposal found")
+	}
+	proposal.Vote(tx.Voter.Address, tx.Approve)
+	view.UpdateGovernanceProposals(proposals)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *VoteTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.VoteTx)
+	return &core.TxInfo{
+		Address:           tx.Voter.Address,
+		Sequence:          tx.Voter.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *VoteTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.VoteTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasVoteTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
@@ -0,0 +1,324 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*HTLCCreateTxExecutor)(nil)
+var _ TxExecutor = (*HTLCClaimTxExecutor)(nil)
+var _ TxExecutor = (*HTLCRefundTxExecutor)(nil)
+
+// ------------------------------- HTLCCreate Transaction -----------------------------------
+
+// HTLCCreateTxExecutor implements the TxExecutor interface
+type HTLCCreateTxExecutor struct {
+}
+
+// NewHTLCCreateTxExecutor creates a new instance of HTLCCreateTxExecutor
+func NewHTLCCreateTxExecutor() *HTLCCreateTxExecutor {
+	return &HTLCCreateTxExecutor{}
+}
+
+func (exec *HTLCCreateTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.HTLCCreateTx)
+
+	res := tx.Sender.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	senderAccount, success := getInput(view, tx.Sender)
+	if success.IsError() {
+		return result.Error("Failed to get the sender account: %v", tx.Sender.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(senderAccount, signBytes, altSignBytes, tx.Sender)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Sender.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	coins := tx.Sender.Coins.NoNil()
+	if !coins.IsValid() || !coins.IsPositive() {
+		return result.Error("Invalid coins to lock in the HTLC: %v", coins).WithErrorCode(result.CodeInvalidHTLC)
+	}
+	if tx.Recipient == (common.Address{}) {
+		return result.Error("The recipient of the HTLC is not specified").WithErrorCode(result.CodeInvalidHTLC)
+	}
+	if tx.HashLock == (common.Hash{}) {
+		return result.Error("The hash lock of the HTLC is not specified").WithErrorCode(result.CodeInvalidHTLC)
+	}
+
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if tx.TimeLock < blockHeight || tx.TimeLock > blockHeight+types.MaximumHTLCDuration {
+		return result.Error("Invalid time lock %v, needs to be between %v and %v",
+			tx.TimeLock, blockHeight, blockHeight+types.MaximumHTLCDuration).WithErrorCode(result.CodeInvalidHTLC)
+	}
+
+	minimalBalance := coins.Plus(tx.Fee)
+	if !senderAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("HTLCCreate: Sender did not have enough balance %v", tx.Sender.Address.Hex()))
+		return result.Error("HTLCCreate: Sender balance is %v, but required minimal balance is %v",
+			senderAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	recipientAccount := view.GetAccount(tx.Recipient)
+	if recipientAccount != nil && recipientAccount.IsASmartContract() {
+		return result.Error(
+			fmt.Sprintf("Sending Pando/PTX to a smart contract (%v) through an HTLCCreateTx transaction is not allowed", tx.Recipient))
+	}
+
+	return result.OK
+}
+
+func (exec *HTLCCreateTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.HTLCCreateTx)
+
+	senderAccount, success := getInput(view, tx.Sender)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the sender account")
+	}
+
+	if !chargeFee(senderAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	coins := tx.Sender.Coins.NoNil()
+	senderAccount.Balance = senderAccount.Balance.Minus(coins)
+	senderAccount.Sequence++
+	view.SetAccount(tx.Sender.Address, senderAccount)
+
+	view.SetHTLC(&types.HTLC{
+		ID:            tx.HTLCID(),
+		Sender:        tx.Sender.Address,
+		Recipient:     tx.Recipient,
+		Coins:         coins,
+		HashLock:      tx.HashLock,
+		TimeLock:      tx.TimeLock,
+		CreatedHeight: view.Height() + 1,
+	})
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *HTLCCreateTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.HTLCCreateTx)
+	return &core.TxInfo{
+		Address:           tx.Sender.Address,
+		Sequence:          tx.Sender.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *HTLCCreateTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.HTLCCreateTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasHTLCCreateTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- HTLCClaim Transaction -----------------------------------
+
+// HTLCClaimTxExecutor implements the TxExecutor interface
+type HTLCClaimTxExecutor struct {
+}
+
+// NewHTLCClaimTxExecutor creates a new instance of HTLCClaimTxExecutor
+func NewHTLCClaimTxExecutor() *HTLCClaimTxExecutor {
+	return &HTLCClaimTxExecutor{}
+}
+
+func (exec *HTLCClaimTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.HTLCClaimTx)
+
+	res := checkHTLCSubmitter(chainID, view, tx, tx.Claimer, tx.Fee)
+	if res.IsError() {
+		return res
+	}
+
+	htlc := view.GetHTLC(tx.HTLCID)
+	if htlc == nil {
+		return result.Error("HTLC %v does not exist, or has already been claimed or refunded", tx.HTLCID.Hex()).
+			WithErrorCode(result.CodeInvalidHTLC)
+	}
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if err := htlc.CheckClaim(blockHeight, tx.Preimage); err != nil {
+		return result.Error("Cannot claim HTLC %v: %v", tx.HTLCID.Hex(), err).WithErrorCode(result.CodeInvalidHTLC)
+	}
+
+	return result.OK
+}
+
+func (exec *HTLCClaimTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.HTLCClaimTx)
+
+	htlc := view.GetHTLC(tx.HTLCID)
+	if htlc == nil {
+		return common.Hash{}, result.Error("HTLC %v does not exist", tx.HTLCID.Hex())
+	}
+	if res := chargeHTLCSubmitter(view, tx.Claimer, tx.Fee); res.IsError() {
+		return common.Hash{}, res
+	}
+	releaseHTLC(view, htlc, htlc.Recipient)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *HTLCClaimTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.HTLCClaimTx)
+	return &core.TxInfo{
+		Address:           tx.Claimer.Address,
+		Sequence:          tx.Claimer.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *HTLCClaimTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.HTLCClaimTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasHTLCClaimTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- HTLCRefund Transaction -----------------------------------
+
+// HTLCRefundTxExecutor implements the TxExecutor interface
+type HTLCRefundTxExecutor struct {
+}
+
+// NewHTLCRefundTxExecutor creates a new instance of HTLCRefundTxExecutor
+func NewHTLCRefundTxExecutor() *HTLCRefundTxExecutor {
+	return &HTLCRefundTxExecutor{}
+}
+
+func (exec *HTLCRefundTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.HTLCRefundTx)
+
+	res := checkHTLCSubmitter(chainID, view, tx, tx.Refunder, tx.Fee)
+	if res.IsError() {
+		return res
+	}
+
+	htlc := view.GetHTLC(tx.HTLCID)
+	if htlc == nil {
+		return result.Error("HTLC %v does not exist, or has already been claimed or refunded", tx.HTLCID.Hex()).
+			WithErrorCode(result.CodeInvalidHTLC)
+	}
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if err := htlc.CheckRefund(blockHeight); err != nil {
+		return result.Error("Cannot refund HTLC %v: %v", tx.HTLCID.Hex(), err).WithErrorCode(result.CodeInvalidHTLC)
+	}
+
+	return result.OK
+}
+
+func (exec *HTLCRefundTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.HTLCRefundTx)
+
+	htlc := view.GetHTLC(tx.HTLCID)
+	if htlc == nil {
+		return common.Hash{}, result.Error("HTLC %v does not exist", tx.HTLCID.Hex())
+	}
+	if res := chargeHTLCSubmitter(view, tx.Refunder, tx.Fee); res.IsError() {
+		return common.Hash{}, res
+	}
+	releaseHTLC(view, htlc, htlc.Sender)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *HTLCRefundTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.HTLCRefundTx)
+	return &core.TxInfo{
+		Address:           tx.Refunder.Address,
+		Sequence:          tx.Refunder.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *HTLCRefundTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.HTLCRefundTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasHTLCRefundTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// checkHTLCSubmitter checks the account submitting an HTLC claim or refund, which signs the
+// transaction and pays the fee, but carries no coins
+func checkHTLCSubmitter(chainID string, view *st.StoreView, tx types.Tx, submitter types.TxInput, fee types.Coins) result.Result {
+	res := submitter.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	account, success := getInput(view, submitter)
+	if success.IsError() {
+		return result.Error("Failed to get the account: %v", submitter.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(account, signBytes, altSignBytes, submitter)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", submitter.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if !submitter.Coins.NoNil().IsZero() {
+		return result.Error("The HTLC claims and refunds cannot carry coins")
+	}
+
+	if !account.Balance.IsGTE(fee) {
+		return result.Error("HTLC: Account balance is %v, but required minimal balance is %v",
+			account.Balance, fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+// chargeHTLCSubmitter charges the fee to the account submitting an HTLC claim or refund
+func chargeHTLCSubmitter(view *st.StoreView, submitter types.TxInput, fee types.Coins) result.Result {
+	account, success := getInput(view, submitter)
+	if success.IsError() {
+		return result.Error("Failed to get the account")
+	}
+	if !chargeFee(account, fee) {
+		return result.Error("Failed to charge transaction fee")
+	}
+	account.Sequence++
+	view.SetAccount(submitter.Address, account)
+	return result.OK
+}
+
+// releaseHTLC pays the coins of the HTLC to the given address, and deletes the HTLC
+func releaseHTLC(view *st.StoreView, htlc *types.HTLC, to common.Address) {
+	account := getOrMakeAccount(view, to)
+	account.Balance = account.Balance.Plus(htlc.Coins)
+	view.SetAccount(to, account)
+	view.DeleteHTLC(htlc.ID)
+}
@@ -0,0 +1,110 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*KeyRotationTxExecutor)(nil)
+
+// ------------------------------- KeyRotation Transaction -----------------------------------
+
+// KeyRotationTxExecutor implements the TxExecutor interface
+type KeyRotationTxExecutor struct {
+}
+
+// NewKeyRotationTxExecutor creates a new instance of KeyRotationTxExecutor
+func NewKeyRotationTxExecutor() *KeyRotationTxExecutor {
+	return &KeyRotationTxExecutor{}
+}
+
+func (exec *KeyRotationTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.KeyRotationTx)
+
+	res := tx.Account.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	account, success := getInput(view, tx.Account)
+	if success.IsError() {
+		return result.Error("Failed to get the account: %v", tx.Account.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(account, signBytes, altSignBytes, tx.Account)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Account.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	coins := tx.Account.Coins.NoNil()
+	if !coins.IsZero() {
+		return result.Error("KeyRotationTx cannot carry coins")
+	}
+
+	minimalBalance := tx.Fee
+	if !account.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("KeyRotation: Account did not have enough balance %v", tx.Account.Address.Hex()))
+		return result.Error("KeyRotation: Account balance is %v, but required minimal balance is %v",
+			account.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	if err := account.CheckRotateKey(tx.NewKey); err != nil {
+		return result.Error("Cannot rotate the key of %v: %v", tx.Account.Address.Hex(), err).
+			WithErrorCode(result.CodeInvalidKeyRotation)
+	}
+
+	return result.OK
+}
+
+func (exec *KeyRotationTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.KeyRotationTx)
+
+	account, success := getInput(view, tx.Account)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the account")
+	}
+
+	if !chargeFee(account, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	account.RotateKey(tx.NewKey, blockHeight)
+
+	account.Sequence++
+	view.SetAccount(tx.Account.Address, account)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *KeyRotationTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.KeyRotationTx)
+	return &core.TxInfo{
+		Address:           tx.Account.Address,
+		Sequence:          tx.Account.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *KeyRotationTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.KeyRotationTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasKeyRotationTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
@@ -0,0 +1,187 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*MeteredSettlementTxExecutor)(nil)
+
+// ------------------------------- MeteredSettlement Transaction -----------------------------------
+
+// MeteredSettlementTxExecutor implements the TxExecutor interface
+type MeteredSettlementTxExecutor struct {
+	chain *blockchain.Chain
+}
+
+// NewMeteredSettlementTxExecutor creates a new instance of MeteredSettlementTxExecutor
+func NewMeteredSettlementTxExecutor(chain *blockchain.Chain) *MeteredSettlementTxExecutor {
+	return &MeteredSettlementTxExecutor{
+		chain: chain,
+	}
+}
+
+func (exec *MeteredSettlementTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.MeteredSettlementTx)
+
+	res := tx.EdgeNode.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+	if !tx.EdgeNode.Coins.NoNil().IsZero() {
+		return result.Error("The edge node input should not carry coins, the payment is made from the reserve fund").
+			WithErrorCode(result.CodeInvalidMeteringRecord)
+	}
+	if tx.EdgeNode.Address == tx.Client {
+		return result.Error("Client and edge node address for the metered settlement cannot be identical: %v", tx.Client)
+	}
+
+	if err := tx.ValidateRecords(chainID); err != nil {
+		return result.Error("%v", err).WithErrorCode(result.CodeInvalidMeteringRecord)
+	}
+
+	edgeNodeAccount, success := getInput(view, tx.EdgeNode)
+	if success.IsError() {
+		return result.Error("Failed to get the edge node account: %v", tx.EdgeNode.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(edgeNodeAccount, signBytes, altSignBytes, tx.EdgeNode)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.EdgeNode.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	clientAccount := view.GetAccount(tx.Client)
+	if clientAccount == nil {
+		return result.Error("Failed to get the client account: %v", tx.Client)
+	}
+
+	// The fee is charged after the payment, so an empty edge node account can pay it
+	amount := tx.Amount()
+	if !edgeNodeAccount.Balance.Plus(amount).IsGTE(tx.Fee) {
+		return result.Error("MeteredSettlement: Edge node balance is %v and the payment is %v, but the fee is %v",
+			edgeNodeAccount.Balance, amount, tx.Fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	currentBlockHeight := view.Height()
+	err := clientAccount.CheckTransferReservedFund(edgeNodeAccount, amount, tx.PaymentSequence(), currentBlockHeight, tx.ReserveSequence)
+	if err != nil {
+		return result.Error(err.Error()).WithErrorCode(result.CodeCheckTransferReservedFundFailed)
+	}
+
+	reservedFund := clientAccount.GetReservedFund(tx.ReserveSequence)
+	if !reservedFund.HasResourceID(tx.ResourceID) {
+		return result.Error("The reserve fund %v of %v is not reserved for resource %v",
+			tx.ReserveSequence, tx.Client.Hex(), tx.ResourceID).WithErrorCode(result.CodeCheckTransferReservedFundFailed)
+	}
+	if !reservedFund.RemainingFund().IsGTE(amount) {
+		return result.Error("The remaining reserve fund %v cannot cover the payment %v", reservedFund.RemainingFund(), amount).
+			WithErrorCode(result.CodeInsufficientReservedFund)
+	}
+
+	return result.OK
+}
+
+func (exec *MeteredSettlementTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.MeteredSettlementTx)
+
+	edgeNodeAccount, success := getInput(view, tx.EdgeNode)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the edge node account")
+	}
+	clientAccount := view.GetAccount(tx.Client)
+	if clientAccount == nil {
+		return common.Hash{}, result.Error("Failed to get the client account")
+	}
+
+	amount := tx.Amount()
+	payment := tx.ServicePayment()
+	currentBlockHeight := view.Height()
+	shouldSlash, _ := clientAccount.TransferReservedFund(map[*types.Account]types.Coins{edgeNodeAccount: amount},
+		currentBlockHeight, tx.ReserveSequence, payment)
+	if shouldSlash {
+		return common.Hash{}, result.Error("The reserve fund cannot cover the payment").
+			WithErrorCode(result.CodeInsufficientReservedFund)
+	}
+
+	if !chargeFee(edgeNodeAccount, tx.Fee) {
+		// should charge after transfer the fund, so an empty address has some fund to pay the tx fee
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+	edgeNodeAccount.Sequence++
+
+	view.SetAccount(tx.Client, clientAccount)
+	view.SetAccount(tx.EdgeNode.Address, edgeNodeAccount)
+	exec.recordPayment(tx, clientAccount, amount, currentBlockHeight)
+
+	blockHeight := currentBlockHeight + 1
+	if features.IsEnabled(features.RametronTiers, blockHeight) && !features.IsEnabled(features.RametronAttestation, blockHeight) {
+		// the settled bandwidth is the proof of the uptime of a rametron node, until the guardians
+		// attest the heartbeats of the nodes
+		if node := view.GetRametronNode(tx.EdgeNode.Address); node != nil {
+			node.MarkActive(types.RametronEpoch(blockHeight))
+			view.SetRametronNode(node)
+		}
+	}
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+// recordPayment records the settlement against the reserve fund of the client, along with the
+// service payments
+func (exec *MeteredSettlementTxExecutor) recordPayment(tx *types.MeteredSettlementTx, clientAccount *types.Account,
+	amount types.Coins, currentBlockHeight uint64) {
+	if exec.chain == nil {
+		return
+	}
+	payment := &blockchain.ReserveFundPayment{
+		Source:          tx.Client,
+		Target:          tx.EdgeNode.Address,
+		ReserveSequence: tx.ReserveSequence,
+		PaymentSequence: tx.PaymentSequence(),
+		ResourceID:      tx.ResourceID,
+		Amount:          amount,
+		Settled:         true,
+		UsedFund:        types.NewCoins(0, 0),
+		RemainingFund:   types.NewCoins(0, 0),
+		Height:          currentBlockHeight,
+	}
+	if reservedFund := clientAccount.GetReservedFund(tx.ReserveSequence); reservedFund != nil {
+		payment.UsedFund = reservedFund.UsedFund.NoNil()
+		payment.RemainingFund = reservedFund.RemainingFund()
+	}
+	exec.chain.AddReserveFundPayment(tx, payment)
+}
+
+func (exec *MeteredSettlementTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.MeteredSettlementTx)
+	return &core.TxInfo{
+		Address:           tx.EdgeNode.Address,
+		Sequence:          tx.EdgeNode.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *MeteredSettlementTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.MeteredSettlementTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasMeteredSettlementTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
@@ -0,0 +1,148 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*RametronAttestationTxExecutor)(nil)
+
+// ------------------------------- RametronAttestation Transaction -----------------------------------
+
+// RametronAttestationTxExecutor implements the TxExecutor interface
+type RametronAttestationTxExecutor struct {
+}
+
+// NewRametronAttestationTxExecutor creates a new instance of RametronAttestationTxExecutor
+func NewRametronAttestationTxExecutor() *RametronAttestationTxExecutor {
+	return &RametronAttestationTxExecutor{}
+}
+
+func (exec *RametronAttestationTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.RametronAttestationTx)
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+
+	res := tx.Guardian.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	guardianAccount, success := getInput(view, tx.Guardian)
+	if success.IsError() {
+		return result.Error("Failed to get the guardian account: %v", tx.Guardian.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(guardianAccount, signBytes, altSignBytes, tx.Guardian)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Guardian.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	coins := tx.Guardian.Coins.NoNil()
+	if !coins.IsZero() {
+		return result.Error("RametronAttestationTx cannot carry coins")
+	}
+
+	if !view.GetGuardianCandidatePool().WithStake().Contains(tx.Guardian.Address) {
+		return result.Error("%v is not a guardian", tx.Guardian.Address.Hex())
+	}
+
+	// The heartbeats can only be attested in their epoch, as the guardian received them
+	if epoch := types.RametronEpoch(blockHeight); tx.Epoch != epoch {
+		return result.Error("The heartbeats are for epoch %v, but the current epoch is %v", tx.Epoch, epoch)
+	}
+
+	if len(tx.Heartbeats) == 0 {
+		return result.Error("No heartbeat to attest")
+	}
+	if len(tx.Heartbeats) > types.MaxRametronHeartbeatsPerTx {
+		return result.Error("Too many heartbeats. At most %v heartbeats are allowed per transaction",
+			types.MaxRametronHeartbeatsPerTx)
+	}
+	nodes := make(map[common.Address]bool)
+	for _, heartbeat := range tx.Heartbeats {
+		if heartbeat == nil {
+			return result.Error("Empty heartbeat")
+		}
+		if nodes[heartbeat.Node] {
+			return result.Error("Duplicate heartbeat of %v", heartbeat.Node.Hex())
+		}
+		nodes[heartbeat.Node] = true
+		if heartbeat.Epoch != tx.Epoch {
+			return result.Error("The heartbeat of %v is for epoch %v, not %v", heartbeat.Node.Hex(), heartbeat.Epoch, tx.Epoch)
+		}
+		if !heartbeat.Verify(chainID) {
+			return result.Error("Invalid heartbeat signature of %v", heartbeat.Node.Hex())
+		}
+		if view.GetRametronNode(heartbeat.Node) == nil {
+			return result.Error("%v is not a registered rametron node", heartbeat.Node.Hex())
+		}
+	}
+
+	if !guardianAccount.Balance.IsGTE(tx.Fee) {
+		return result.Error("Insufficient fund: balance is %v, but the fee is %v",
+			guardianAccount.Balance, tx.Fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *RametronAttestationTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.RametronAttestationTx)
+
+	guardianAccount, success := getInput(view, tx.Guardian)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the guardian account")
+	}
+
+	if !chargeFee(guardianAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	for _, heartbeat := range tx.Heartbeats {
+		node := view.GetRametronNode(heartbeat.Node)
+		if node == nil {
+			continue
+		}
+		// A heartbeat attested again by the same guardian is ignored
+		if node.Attest(tx.Epoch, tx.Guardian.Address) {
+			view.SetRametronNode(node)
+		}
+	}
+
+	guardianAccount.Sequence++
+	view.SetAccount(tx.Guardian.Address, guardianAccount)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *RametronAttestationTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.RametronAttestationTx)
+	return &core.TxInfo{
+		Address:           tx.Guardian.Address,
+		Sequence:          tx.Guardian.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *RametronAttestationTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.RametronAttestationTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(tx.Gas())
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
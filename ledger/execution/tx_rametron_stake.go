@@ -7,6 +7,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 )
@@ -32,6 +33,10 @@ func (exec *RametronStakeTxExecutor) sanityCheck(chainID string, view *st.StoreV
 	if res.IsError() {
 		return res
 	}
+	res = validateMultiSigEnabled(view, tx.Inputs)
+	if res.IsError() {
+		return res
+	}
 	res = validateOutputsBasic(tx.Outputs)
 	if res.IsError() {
 		return res
@@ -60,7 +65,7 @@ func (exec *RametronStakeTxExecutor) sanityCheck(chainID string, view *st.StoreV
 	}
 
 	blockHeight := view.Height() + 1
-	if blockHeight >= common.HeightEnableSmartContract {
+	if features.IsEnabled(features.SmartContract, blockHeight) {
 		for _, outAcc := range accounts {
 			if outAcc.IsASmartContract() {
 				return result.Error(
@@ -71,14 +76,15 @@ func (exec *RametronStakeTxExecutor) sanityCheck(chainID string, view *st.StoreV
 
 	// Validate inputs and outputs, advanced
 	signBytes := tx.SignBytes(chainID)
-	inTotal, res := validateInputsAdvanced(accounts, signBytes, tx.Inputs)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	inTotal, res := validateInputsAdvanced(accounts, signBytes, altSignBytes, tx.Inputs)
 	if res.IsError() {
 		return res
 	}
 
-	if !sanityCheckForFee(tx.Fee) {
+	if !sanityCheckForFee(view, tx.Fee) {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
-			types.MinimumTransactionFeePTXWei).WithErrorCode(result.CodeInvalidFee)
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
 	}
 
 	outTotal := sumOutputs(tx.Outputs)
@@ -88,6 +94,46 @@ func (exec *RametronStakeTxExecutor) sanityCheck(chainID string, view *st.StoreV
 		return result.Error("Input total (%v) != output total + fees (%v)", inTotal, outPlusFees)
 	}
 
+	if tx.Tier != types.RametronTierNone {
+		return exec.checkTier(view, tx, blockHeight)
+	}
+
+	return result.OK
+}
+
+// checkTier checks the registration of the output as a Rametron node of the tier. The node needs
+// to hold the min stake of the tier after the transfer.
+func (exec *RametronStakeTxExecutor) checkTier(view *st.StoreView, tx *types.RametronStakeTx, blockHeight uint64) result.Result {
+	if !features.IsEnabled(features.RametronTiers, blockHeight) {
+		return result.Error("Rametron tiers are not enabled until height %v", features.Height(features.RametronTiers))
+	}
+	spec, ok := types.GetRametronTierSpec(tx.Tier)
+	if !ok {
+		return result.Error("Unknown rametron tier: %v", tx.Tier)
+	}
+	if len(tx.Outputs) != 1 {
+		return result.Error("A rametronStakeTx registering a tier needs exactly one output, the rametron node")
+	}
+
+	nodeAddress := tx.Outputs[0].Address
+	for _, input := range tx.Inputs {
+		if input.Address == nodeAddress {
+			return result.Error("The rametron node %v cannot stake for itself", nodeAddress)
+		}
+	}
+	if node := view.GetRametronNode(nodeAddress); node != nil && node.Holder != tx.Inputs[0].Address {
+		return result.Error("The tier of rametron node %v can only be changed by its holder %v", nodeAddress, node.Holder)
+	}
+
+	stake := tx.Outputs[0].Coins.NoNil().PandoWei
+	if nodeAccount := view.GetAccount(nodeAddress); nodeAccount != nil {
+		stake = new(big.Int).Add(stake, nodeAccount.Balance.NoNil().PandoWei)
+	}
+	if stake.Cmp(spec.MinStake) < 0 {
+		return result.Error("Insufficient stake for the %v tier, the rametron node needs at least %v PandoWei, but would hold %v",
+			spec.Name, spec.MinStake, stake).WithErrorCode(result.CodeInsufficientStake)
+	}
+
 	return result.OK
 }
 
@@ -107,6 +153,21 @@ func (exec *RametronStakeTxExecutor) process(chainID string, view *st.StoreView,
 	adjustByInputs(view, accounts, tx.Inputs)
 	adjustByOutputs(view, accounts, tx.Outputs)
 
+	if tx.Tier != types.RametronTierNone {
+		blockHeight := view.Height() + 1
+		nodeAddress := tx.Outputs[0].Address
+		node := view.GetRametronNode(nodeAddress)
+		if node == nil {
+			node = &types.RametronNode{
+				Node:            nodeAddress,
+				Holder:          tx.Inputs[0].Address,
+				RegisteredEpoch: types.RametronEpoch(blockHeight),
+			}
+		}
+		node.Tier = tx.Tier
+		view.SetRametronNode(node)
+	}
+
 	txHash := types.TxID(chainID, tx)
 	return txHash, result.OK
 }
@@ -133,3 +194,68 @@ func (exec *RametronStakeTxExecutor) calculateEffectiveGasPrice(transaction type
 	return effectiveGasPrice
 }
 
+// grantRametronReward shares the Rametron rewards of the reward epoch among the registered
+// Rametron nodes which hold the min stake and meet the min uptime of their tiers, in proportion
+// to the reward weights of the tiers
+func grantRametronReward(view *st.StoreView, accountReward *map[string]types.Coins, blockHeight uint64) {
+	if !common.IsCheckPointHeight(blockHeight) {
+		return
+	}
+
+	epoch := types.RametronEpoch(blockHeight)
+	eligible := []*types.RametronNode{}
+	weights := []uint64{}
+	totalWeight := uint64(0)
+	for _, node := range view.GetRametronNodes() {
+		weight := RametronRewardWeight(view, node, epoch, blockHeight)
+		if weight == 0 {
+			continue
+		}
+		eligible = append(eligible, node)
+		weights = append(weights, weight)
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return
+	}
+
+	totalReward := big.NewInt(1).Mul(rametronRewardPerBlock, big.NewInt(common.CheckpointInterval))
+	for i, node := range eligible {
+		tmp := big.NewInt(1).Mul(totalReward, new(big.Int).SetUint64(weights[i]))
+		rewardAmount := tmp.Div(tmp, new(big.Int).SetUint64(totalWeight))
+
+		reward := types.Coins{
+			PandoWei: big.NewInt(0),
+			PTXWei:   rewardAmount,
+		}.NoNil()
+		addr := string(node.Node[:])
+		if existing, ok := (*accountReward)[addr]; ok {
+			reward = existing.NoNil().Plus(reward)
+		}
+		(*accountReward)[addr] = reward
+
+		logger.Infof("Rametron reward for node %v, tier %v : %v", node.Node.Hex(), node.Tier, rewardAmount)
+	}
+}
+
+// RametronRewardWeight returns the reward weight of the node in the Rametron rewards granted at the
+// start of the given epoch, 0 if the node is not eligible. The node needs to hold the min stake and
+// meet the min uptime of its tier. Once the heartbeats are attested, it also needs to have been
+// attested in the epoch the rewards are for.
+func RametronRewardWeight(view *st.StoreView, node *types.RametronNode, epoch uint64, blockHeight uint64) uint64 {
+	spec, ok := types.GetRametronTierSpec(node.Tier)
+	if !ok {
+		return 0
+	}
+	account := view.GetAccount(node.Node)
+	if account == nil || account.Balance.NoNil().PandoWei.Cmp(spec.MinStake) < 0 {
+		return 0
+	}
+	if node.Uptime(epoch) < spec.MinUptime {
+		return 0
+	}
+	if features.IsEnabled(features.RametronAttestation, blockHeight) && (epoch == 0 || !node.IsActive(epoch-1)) {
+		return 0
+	}
+	return spec.RewardWeight
+}
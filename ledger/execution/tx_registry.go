@@ -0,0 +1,171 @@
+package execution
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+// TxExecutorFactory creates the executor of a transaction type for the given Executor
+type TxExecutorFactory func(exec *Executor) TxExecutor
+
+// txExecutorSpec declares how a transaction type is executed
+type txExecutorSpec struct {
+	feature string // the fork enabling the transaction type, empty if always enabled
+	factory TxExecutorFactory
+}
+
+var (
+	txExecutorSpecsMtx sync.RWMutex
+	txExecutorSpecs    = make(map[types.TxType]*txExecutorSpec)
+)
+
+// RegisterTxExecutor registers the executor of a transaction type, which must have been registered
+// with types.RegisterTxType. Transactions of the type are rejected until the feature is enabled, see
+// features.IsEnabled. It is meant to be called from init(), and panics if the type already has an executor.
+func RegisterTxExecutor(txType types.TxType, feature string, factory TxExecutorFactory) {
+	if _, ok := types.LookupTxType(txType); !ok {
+		panic(fmt.Sprintf("Tx type %v is not registered", txType))
+	}
+
+	txExecutorSpecsMtx.Lock()
+	defer txExecutorSpecsMtx.Unlock()
+
+	if _, ok := txExecutorSpecs[txType]; ok {
+		panic(fmt.Sprintf("Executor of tx type %v is already registered", types.TxTypeName(txType)))
+	}
+	txExecutorSpecs[txType] = &txExecutorSpec{
+		feature: feature,
+		factory: factory,
+	}
+}
+
+func lookupTxExecutorSpec(txType types.TxType) (*txExecutorSpec, bool) {
+	txExecutorSpecsMtx.RLock()
+	defer txExecutorSpecsMtx.RUnlock()
+
+	spec, ok := txExecutorSpecs[txType]
+	return spec, ok
+}
+
+// newTxExecutors creates the executors of all the registered transaction types
+func newTxExecutors(exec *Executor) map[types.TxType]TxExecutor {
+	txExecutorSpecsMtx.RLock()
+	defer txExecutorSpecsMtx.RUnlock()
+
+	txExecutors := make(map[types.TxType]TxExecutor, len(txExecutorSpecs))
+	for txType, spec := range txExecutorSpecs {
+		txExecutors[txType] = spec.factory(exec)
+	}
+	return txExecutors
+}
+
+func init() {
+	RegisterTxExecutor(types.TxCoinbase, "", func(exec *Executor) TxExecutor {
+		return NewCoinbaseTxExecutor(exec.db, exec.chain, exec.state, exec.consensus, exec.valMgr)
+	})
+	RegisterTxExecutor(types.TxSlash, features.DoubleSignSlash, func(exec *Executor) TxExecutor {
+		return NewSlashTxExecutor(exec.consensus, exec.valMgr)
+	})
+	RegisterTxExecutor(types.TxSend, "", func(exec *Executor) TxExecutor {
+		return NewSendTxExecutor()
+	})
+	RegisterTxExecutor(types.TxRametronStake, "", func(exec *Executor) TxExecutor {
+		return NewRametronStakeTxExecutor()
+	})
+	RegisterTxExecutor(types.TxReserveFund, "", func(exec *Executor) TxExecutor {
+		return NewReserveFundTxExecutor(exec.state)
+	})
+	RegisterTxExecutor(types.TxReleaseFund, "", func(exec *Executor) TxExecutor {
+		return NewReleaseFundTxExecutor(exec.state)
+	})
+	RegisterTxExecutor(types.TxServicePayment, "", func(exec *Executor) TxExecutor {
+		return NewServicePaymentTxExecutor(exec.chain, exec.state)
+	})
+	RegisterTxExecutor(types.TxSplitRule, "", func(exec *Executor) TxExecutor {
+		return NewSplitRuleTxExecutor(exec.state)
+	})
+	RegisterTxExecutor(types.TxSmartContract, features.SmartContract, func(exec *Executor) TxExecutor {
+		return NewSmartContractTxExecutor(exec.chain, exec.state)
+	})
+	RegisterTxExecutor(types.TxDepositStake, "", func(exec *Executor) TxExecutor {
+		return NewDepositStakeExecutor()
+	})
+	RegisterTxExecutor(types.TxWithdrawStake, "", func(exec *Executor) TxExecutor {
+		return NewWithdrawStakeExecutor(exec.state)
+	})
+	RegisterTxExecutor(types.TxDepositStakeV2, "", func(exec *Executor) TxExecutor {
+		return NewDepositStakeExecutor()
+	})
+	RegisterTxExecutor(types.TxSessionKey, features.SessionKeys, func(exec *Executor) TxExecutor {
+		return NewSessionKeyTxExecutor()
+	})
+	RegisterTxExecutor(types.TxBatchSend, features.BatchSendTx, func(exec *Executor) TxExecutor {
+		return NewBatchSendTxExecutor()
+	})
+	RegisterTxExecutor(types.TxSlashAppeal, features.SlashAppeals, func(exec *Executor) TxExecutor {
+		return NewSlashAppealTxExecutor()
+	})
+	RegisterTxExecutor(types.TxSlashAppealVote, features.SlashAppeals, func(exec *Executor) TxExecutor {
+		return NewSlashAppealVoteTxExecutor()
+	})
+	RegisterTxExecutor(types.TxClaimEscrow, features.Escrow, func(exec *Executor) TxExecutor {
+		return NewClaimEscrowTxExecutor()
+	})
+	RegisterTxExecutor(types.TxMeteredSettlement, features.MeteredSettlement, func(exec *Executor) TxExecutor {
+		return NewMeteredSettlementTxExecutor(exec.chain)
+	})
+	RegisterTxExecutor(types.TxDelegate, features.Delegation, func(exec *Executor) TxExecutor {
+		return NewDelegateTxExecutor()
+	})
+	RegisterTxExecutor(types.TxUndelegate, features.Delegation, func(exec *Executor) TxExecutor {
+		return NewUndelegateTxExecutor(exec.state)
+	})
+	RegisterTxExecutor(types.TxSetCommission, features.ValidatorCommission, func(exec *Executor) TxExecutor {
+		return NewSetCommissionTxExecutor()
+	})
+	RegisterTxExecutor(types.TxRametronAttestation, features.RametronAttestation, func(exec *Executor) TxExecutor {
+		return NewRametronAttestationTxExecutor()
+	})
+	RegisterTxExecutor(types.TxKeyRotation, features.KeyRotation, func(exec *Executor) TxExecutor {
+		return NewKeyRotationTxExecutor()
+	})
+	RegisterTxExecutor(types.TxHTLCCreate, features.HTLC, func(exec *Executor) TxExecutor {
+		return NewHTLCCreateTxExecutor()
+	})
+	RegisterTxExecutor(types.TxHTLCClaim, features.HTLC, func(exec *Executor) TxExecutor {
+		return NewHTLCClaimTxExecutor()
+	})
+	RegisterTxExecutor(types.TxHTLCRefund, features.HTLC, func(exec *Executor) TxExecutor {
+		return NewHTLCRefundTxExecutor()
+	})
+	RegisterTxExecutor(types.TxProposal, features.Governance, func(exec *Executor) TxExecutor {
+		return NewProposalTxExecutor()
+	})
+	RegisterTxExecutor(types.TxVote, features.Governance, func(exec *Executor) TxExecutor {
+		return NewVoteTxExecutor()
+	})
+	RegisterTxExecutor(types.TxBridgeLock, features.Bridge, func(exec *Executor) TxExecutor {
+		return NewBridgeLockTxExecutor()
+	})
+	RegisterTxExecutor(types.TxBridgeBurn, features.Bridge, func(exec *Executor) TxExecutor {
+		return NewBridgeBurnTxExecutor()
+	})
+	RegisterTxExecutor(types.TxBridgeClaim, features.Bridge, func(exec *Executor) TxExecutor {
+		return NewBridgeClaimTxExecutor()
+	})
+	RegisterTxExecutor(types.TxSubchainRegister, features.Subchain, func(exec *Executor) TxExecutor {
+		return NewSubchainRegisterTxExecutor()
+	})
+	RegisterTxExecutor(types.TxSubchainStake, features.Subchain, func(exec *Executor) TxExecutor {
+		return NewSubchainStakeTxExecutor()
+	})
+	RegisterTxExecutor(types.TxSubchainCheckpoint, features.Subchain, func(exec *Executor) TxExecutor {
+		return NewSubchainCheckpointTxExecutor()
+	})
+	RegisterTxExecutor(types.TxSubchainChallenge, features.Subchain, func(exec *Executor) TxExecutor {
+		return NewSubchainChallengeTxExecutor()
+	})
+}
@@ -44,15 +44,16 @@ func (exec *ReleaseFundTxExecutor) sanityCheck(chainID string, view *st.StoreVie
 
 	// Validate input, advanced
 	signBytes := tx.SignBytes(chainID)
-	res = validateInputAdvanced(sourceAccount, signBytes, tx.Source)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(sourceAccount, signBytes, altSignBytes, tx.Source)
 	if res.IsError() {
 		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Source.Address.Hex(), res))
 		return res
 	}
 
-	if !sanityCheckForFee(tx.Fee) {
+	if !sanityCheckForFee(view, tx.Fee) {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
-			types.MinimumTransactionFeePTXWei).WithErrorCode(result.CodeInvalidFee)
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
 	}
 
 	minimalBalance := tx.Fee
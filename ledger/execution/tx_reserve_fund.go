@@ -7,6 +7,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 )
@@ -44,7 +45,8 @@ func (exec *ReserveFundTxExecutor) sanityCheck(chainID string, view *st.StoreVie
 
 	// Validate input, advanced
 	signBytes := tx.SignBytes(chainID)
-	res = validateInputAdvanced(sourceAccount, signBytes, tx.Source)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(sourceAccount, signBytes, altSignBytes, tx.Source)
 	if res.IsError() {
 		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Source.Address.Hex(), res))
 		return res
@@ -57,14 +59,20 @@ func (exec *ReserveFundTxExecutor) sanityCheck(chainID string, view *st.StoreVie
 			WithErrorCode(result.CodeReservedFundNotSpecified)
 	}
 
-	if coins.PandoWei.Cmp(types.Zero) != 0 {
-		return result.Error("Cannot reserve Pando as service fund!").
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if !features.IsEnabled(features.MultiCurrencyReservedFund, blockHeight) {
+		if coins.PandoWei.Cmp(types.Zero) != 0 {
+			return result.Error("Cannot reserve Pando as service fund!").
+				WithErrorCode(result.CodeInvalidFundToReserve)
+		}
+	} else if coins.PandoWei.Cmp(types.Zero) != 0 && coins.PTXWei.Cmp(types.Zero) != 0 {
+		return result.Error("Service fund should be reserved in either PandoWei or PTXWei, but not both").
 			WithErrorCode(result.CodeInvalidFundToReserve)
 	}
 
-	if !sanityCheckForFee(tx.Fee) {
+	if !sanityCheckForFee(view, tx.Fee) {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
-			types.MinimumTransactionFeePTXWei).WithErrorCode(result.CodeInvalidFee)
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
 	}
 
 	fund := tx.Source.Coins
@@ -7,6 +7,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 )
@@ -32,6 +33,10 @@ func (exec *SendTxExecutor) sanityCheck(chainID string, view *st.StoreView, tran
 	if res.IsError() {
 		return res
 	}
+	res = validateMultiSigEnabled(view, tx.Inputs)
+	if res.IsError() {
+		return res
+	}
 	res = validateOutputsBasic(tx.Outputs)
 	if res.IsError() {
 		return res
@@ -41,6 +46,17 @@ func (exec *SendTxExecutor) sanityCheck(chainID string, view *st.StoreView, tran
 		return result.Error("Invalid sendTx, Inputs and/or Outputs are empty")
 	}
 
+	blockHeight := view.Height() + 1
+	if len(tx.Data) != 0 {
+		if !features.IsEnabled(features.SendTxData, blockHeight) {
+			return result.Error("Data in SendTx is not supported yet")
+		}
+		if len(tx.Data) > types.MaximumSendTxDataLength {
+			return result.Error("Data too long. At most %v bytes of data are allowed per transaction",
+				types.MaximumSendTxDataLength)
+		}
+	}
+
 	numAccountsAffected := uint64(len(tx.Inputs) + len(tx.Outputs))
 	if numAccountsAffected > types.MaxAccountsAffectedPerTx {
 		return result.Error("Trasaction modifying too many accounts. At most %v accounts are allowed per transaction",
@@ -59,8 +75,7 @@ func (exec *SendTxExecutor) sanityCheck(chainID string, view *st.StoreView, tran
 		return res
 	}
 
-	blockHeight := view.Height() + 1
-	if blockHeight >= common.HeightEnableSmartContract {
+	if features.IsEnabled(features.SmartContract, blockHeight) {
 		for _, outAcc := range accounts {
 			if outAcc.IsASmartContract() {
 				return result.Error(
@@ -71,14 +86,23 @@ func (exec *SendTxExecutor) sanityCheck(chainID string, view *st.StoreView, tran
 
 	// Validate inputs and outputs, advanced
 	signBytes := tx.SignBytes(chainID)
-	inTotal, res := validateInputsAdvanced(accounts, signBytes, tx.Inputs)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	destinations := make([]common.Address, len(tx.Outputs))
+	for i, out := range tx.Outputs {
+		destinations[i] = out.Address
+	}
+	inTotal, res := validateInputsAdvancedWithSessionKeys(view, accounts, signBytes, altSignBytes, tx.Inputs, types.TxSend, destinations)
 	if res.IsError() {
 		return res
 	}
 
-	if !sanityCheckForFee(tx.Fee) {
+	if !sanityCheckForFee(view, tx.Fee) {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
-			types.MinimumTransactionFeePTXWei).WithErrorCode(result.CodeInvalidFee)
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+	if minimumFee := tx.MinimumFeeAt(minimumTxFee(view)); tx.Fee.NoNil().PTXWei.Cmp(minimumFee) < 0 {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei for %v bytes of data",
+			minimumFee, len(tx.Data)).WithErrorCode(result.CodeInvalidFee)
 	}
 
 	outTotal := sumOutputs(tx.Outputs)
@@ -104,6 +128,12 @@ func (exec *SendTxExecutor) process(chainID string, view *st.StoreView, transact
 		return common.Hash{}, res
 	}
 
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	for _, in := range tx.Inputs {
+		chargeSessionKey(view, signBytes, altSignBytes, in, in.Coins)
+	}
+
 	adjustByInputs(view, accounts, tx.Inputs)
 	adjustByOutputs(view, accounts, tx.Outputs)
 
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/pandotoken/pando/blockchain"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 )
@@ -18,12 +20,14 @@ var _ TxExecutor = (*ServicePaymentTxExecutor)(nil)
 // ServicePaymentTxExecutor implements the TxExecutor interface
 type ServicePaymentTxExecutor struct {
 	state *st.LedgerState
+	chain *blockchain.Chain
 }
 
 // NewServicePaymentTxExecutor creates a new instance of ServicePaymentTxExecutor
-func NewServicePaymentTxExecutor(state *st.LedgerState) *ServicePaymentTxExecutor {
+func NewServicePaymentTxExecutor(chain *blockchain.Chain, state *st.LedgerState) *ServicePaymentTxExecutor {
 	return &ServicePaymentTxExecutor{
 		state: state,
+		chain: chain,
 	}
 }
 
@@ -58,28 +62,35 @@ func (exec *ServicePaymentTxExecutor) sanityCheck(chainID string, view *st.Store
 		return res
 	}
 
-	if tx.Source.Coins.PandoWei.Cmp(types.Zero) != 0 {
-		return result.Error("Cannot send PandoWei as service payment!")
+	paymentCoins := tx.Source.Coins.NoNil()
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if !features.IsEnabled(features.MultiCurrencyReservedFund, blockHeight) {
+		if paymentCoins.PandoWei.Cmp(types.Zero) != 0 {
+			return result.Error("Cannot send PandoWei as service payment!")
+		}
+	} else if paymentCoins.PandoWei.Cmp(types.Zero) != 0 && paymentCoins.PTXWei.Cmp(types.Zero) != 0 {
+		return result.Error("Service payment should be made in either PandoWei or PTXWei, but not both")
 	}
 
 	// Verify source
+	altSignBytes := getAltSignBytes(chainID, view, tx)
 	sourceSignBytes := tx.SourceSignBytes(chainID)
-	if !tx.Source.Signature.Verify(sourceSignBytes, sourceAccount.Address) {
+	if !verifyAccountSignature(tx.Source.Signature, sourceSignBytes, altSignBytes, sourceAccount) {
 		errMsg := fmt.Sprintf("sanityCheckForServicePaymentTx failed on source signature, addr: %v", sourceAddress.Hex())
 		logger.Infof(errMsg)
 		return result.Error(errMsg)
 	}
 
 	targetSignBytes := tx.TargetSignBytes(chainID)
-	if !tx.Target.Signature.Verify(targetSignBytes, targetAccount.Address) {
+	if !verifyAccountSignature(tx.Target.Signature, targetSignBytes, altSignBytes, targetAccount) {
 		errMsg := fmt.Sprintf("sanityCheckForServicePaymentTx failed on target signature, addr: %v", targetAddress.Hex())
 		logger.Infof(errMsg)
 		return result.Error(errMsg)
 	}
 
-	if !sanityCheckForFee(tx.Fee) {
+	if !sanityCheckForFee(view, tx.Fee) {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
-			types.MinimumTransactionFeePTXWei).WithErrorCode(result.CodeInvalidFee)
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
 	}
 
 	transferAmount := tx.Source.Coins
@@ -90,7 +101,13 @@ func (exec *ServicePaymentTxExecutor) sanityCheck(chainID string, view *st.Store
 	// Note: No need to check whether the source account has enough reserved fund to cover the
 	//       transaction. If the source account does not have sufficient reserved fund,
 	//       the source account will be slashed by the process() function
-	err := sourceAccount.CheckTransferReservedFund(targetAccount, transferAmount, paymentSequence, currentBlockHeight, reserveSequence)
+	var err error
+	if features.IsEnabled(features.PaymentDisputeWindow, currentBlockHeight) {
+		// The payment settles the cumulative amount paid to the target
+		err = sourceAccount.CheckSettleReservedFund(targetAccount, transferAmount, paymentSequence, currentBlockHeight, reserveSequence)
+	} else {
+		err = sourceAccount.CheckTransferReservedFund(targetAccount, transferAmount, paymentSequence, currentBlockHeight, reserveSequence)
+	}
 	if err != nil {
 		return result.Error(err.Error()).WithErrorCode(result.CodeCheckTransferReservedFundFailed)
 	}
@@ -116,8 +133,16 @@ func (exec *ServicePaymentTxExecutor) process(chainID string, view *st.StoreView
 
 	resourceID := tx.ResourceID
 	splitRule := view.GetSplitRule(resourceID)
+	currentBlockHeight := view.Height()
 
+	// The payment settles the cumulative amount paid to the target, of which only the part not
+	// settled yet is transferred
 	fullTransferAmount := tx.Source.Coins
+	if features.IsEnabled(features.PaymentDisputeWindow, currentBlockHeight) {
+		if reservedFund := sourceAccount.GetReservedFund(tx.ReserveSequence); reservedFund != nil {
+			fullTransferAmount = reservedFund.UnsettledAmount(targetAddress, tx.Source.Coins)
+		}
+	}
 	splitSuccess, addrCoinsMap := exec.splitPayment(view, splitRule, resourceID, targetAddress, fullTransferAmount)
 	if !splitSuccess {
 		return common.Hash{}, result.Error("Failed to split payment")
@@ -136,12 +161,18 @@ func (exec *ServicePaymentTxExecutor) process(chainID string, view *st.StoreView
 		accCoinsMap[account] = coins
 	}
 
-	currentBlockHeight := view.Height()
 	reserveSequence := tx.ReserveSequence
 	shouldSlash, _ := sourceAccount.TransferReservedFund(accCoinsMap, currentBlockHeight, reserveSequence, tx)
 	if shouldSlash {
 		//view.AddSlashIntent(slashIntent)
 	}
+	if !shouldSlash && features.IsEnabled(features.PaymentDisputeWindow, currentBlockHeight) {
+		// The latest payment overrides the settlement of the target
+		if reservedFund := sourceAccount.GetReservedFund(reserveSequence); reservedFund != nil && reservedFund.HasResourceID(resourceID) {
+			reservedFund.Settle(targetAddress, tx.PaymentSequence, tx.Source.Coins, currentBlockHeight)
+		}
+	}
+	exec.recordPayment(tx, sourceAccount, fullTransferAmount, currentBlockHeight, shouldSlash)
 	if !chargeFee(targetAccount, tx.Fee) {
 		// should charge after transfer the fund, so an empty address has some fund to pay the tx fee
 		return common.Hash{}, result.Error("failed to charge transaction fee")
@@ -157,6 +188,33 @@ func (exec *ServicePaymentTxExecutor) process(chainID string, view *st.StoreView
 	return txHash, result.OK
 }
 
+// recordPayment records how the payment was settled against the reserve fund of the source account,
+// so that the payer can follow the spend of the fund
+func (exec *ServicePaymentTxExecutor) recordPayment(tx *types.ServicePaymentTx, sourceAccount *types.Account,
+	amount types.Coins, currentBlockHeight uint64, shouldSlash bool) {
+	if exec.chain == nil {
+		return
+	}
+	payment := &blockchain.ReserveFundPayment{
+		Source:          tx.Source.Address,
+		Target:          tx.Target.Address,
+		ReserveSequence: tx.ReserveSequence,
+		PaymentSequence: tx.PaymentSequence,
+		ResourceID:      tx.ResourceID,
+		Amount:          amount.NoNil(),
+		UsedFund:        types.NewCoins(0, 0),
+		RemainingFund:   types.NewCoins(0, 0),
+		Height:          currentBlockHeight,
+	}
+	if reservedFund := sourceAccount.GetReservedFund(tx.ReserveSequence); reservedFund != nil {
+		// TransferReservedFund skips the payments for the resources the fund was not reserved for
+		payment.Settled = !shouldSlash && reservedFund.HasResourceID(tx.ResourceID)
+		payment.UsedFund = reservedFund.UsedFund.NoNil()
+		payment.RemainingFund = reservedFund.RemainingFund()
+	}
+	exec.chain.AddReserveFundPayment(tx, payment)
+}
+
 func (exec *ServicePaymentTxExecutor) splitPayment(view *st.StoreView, splitRule *types.SplitRule, resourceID string,
 	targetAddress common.Address, fullAmount types.Coins) (bool, map[common.Address]types.Coins) {
 	addressCoinsMap := map[common.Address]types.Coins{}
@@ -0,0 +1,162 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*SessionKeyTxExecutor)(nil)
+
+// ------------------------------- SessionKey Transaction -----------------------------------
+
+// SessionKeyTxExecutor implements the TxExecutor interface
+type SessionKeyTxExecutor struct {
+}
+
+// NewSessionKeyTxExecutor creates a new instance of SessionKeyTxExecutor
+func NewSessionKeyTxExecutor() *SessionKeyTxExecutor {
+	return &SessionKeyTxExecutor{}
+}
+
+func (exec *SessionKeyTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SessionKeyTx)
+
+	res := tx.Account.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	account, success := getInput(view, tx.Account)
+	if success.IsError() {
+		return result.Error("Failed to get the account: %v", tx.Account.Address)
+	}
+
+	// Only the account key can grant or revoke session keys
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(account, signBytes, altSignBytes, tx.Account)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Account.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	minimalBalance := tx.Fee
+	if !account.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("SessionKey: Account did not have enough balance %v", tx.Account.Address.Hex()))
+		return result.Error("SessionKey: Account balance is %v, but required minimal balance is %v",
+			account.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	if (tx.SessionKey == common.Address{}) || tx.SessionKey == tx.Account.Address {
+		return result.Error("Invalid session key address: %v", tx.SessionKey.Hex()).
+			WithErrorCode(result.CodeInvalidSessionKey)
+	}
+
+	sessionKeys := view.GetSessionKeys(tx.Account.Address)
+	if tx.IsRevocation() {
+		if sessionKeys.Get(tx.SessionKey) == nil {
+			return result.Error("Session key %v is not registered", tx.SessionKey.Hex()).
+				WithErrorCode(result.CodeInvalidSessionKey)
+		}
+		return result.OK
+	}
+
+	blockHeight := view.Height() + 1
+	if tx.ExpiryHeight < blockHeight || tx.ExpiryHeight > blockHeight+types.MaximumSessionKeyDuration {
+		return result.Error("Invalid expiry height %v, needs to be between %v and %v",
+			tx.ExpiryHeight, blockHeight, blockHeight+types.MaximumSessionKeyDuration).WithErrorCode(result.CodeInvalidSessionKey)
+	}
+
+	if len(tx.TxTypes) == 0 {
+		return result.Error("The session key needs to be permitted at least one tx type").
+			WithErrorCode(result.CodeInvalidSessionKey)
+	}
+	for _, txType := range tx.TxTypes {
+		if !types.IsSessionKeyTxType(txType) {
+			return result.Error("Session keys cannot be permitted to sign tx type %v", txType).
+				WithErrorCode(result.CodeInvalidSessionKey)
+		}
+	}
+
+	if len(tx.Destinations) > types.MaximumSessionKeyDestinations {
+		return result.Error("Too many destinations, at most %v destinations are allowed",
+			types.MaximumSessionKeyDestinations).WithErrorCode(result.CodeInvalidSessionKey)
+	}
+
+	if !tx.SpendingLimit.IsNonnegative() {
+		return result.Error("Invalid spending limit: %v", tx.SpendingLimit).
+			WithErrorCode(result.CodeInvalidSessionKey)
+	}
+
+	sessionKeys.RemoveExpired(blockHeight)
+	if sessionKeys.Get(tx.SessionKey) == nil && sessionKeys.Len() >= types.MaximumSessionKeysPerAccount {
+		return result.Error("Too many session keys, at most %v session keys are allowed per account",
+			types.MaximumSessionKeysPerAccount).WithErrorCode(result.CodeInvalidSessionKey)
+	}
+
+	return result.OK
+}
+
+func (exec *SessionKeyTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SessionKeyTx)
+
+	account, success := getInput(view, tx.Account)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the account")
+	}
+
+	if !chargeFee(account, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	blockHeight := view.Height() + 1
+	sessionKeys := view.GetSessionKeys(tx.Account.Address)
+	sessionKeys.RemoveExpired(blockHeight)
+	if tx.IsRevocation() {
+		sessionKeys.Remove(tx.SessionKey)
+	} else {
+		sessionKeys.Set(&types.SessionKey{
+			Address:       tx.SessionKey,
+			TxTypes:       tx.TxTypes,
+			Destinations:  tx.Destinations,
+			SpendingLimit: tx.SpendingLimit.NoNil(),
+			Spent:         types.NewCoins(0, 0),
+			ExpiryHeight:  tx.ExpiryHeight,
+		})
+	}
+	view.UpdateSessionKeys(tx.Account.Address, sessionKeys)
+
+	account.Sequence++
+	view.SetAccount(tx.Account.Address, account)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SessionKeyTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SessionKeyTx)
+	return &core.TxInfo{
+		Address:           tx.Account.Address,
+		Sequence:          tx.Account.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SessionKeyTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SessionKeyTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasSessionKeyTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
@@ -0,0 +1,234 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*SetCommissionTxExecutor)(nil)
+
+// ------------------------------- SetCommission Transaction -----------------------------------
+
+// SetCommissionTxExecutor implements the TxExecutor interface
+type SetCommissionTxExecutor struct {
+}
+
+// NewSetCommissionTxExecutor creates a new instance of SetCommissionTxExecutor
+func NewSetCommissionTxExecutor() *SetCommissionTxExecutor {
+	return &SetCommissionTxExecutor{}
+}
+
+func (exec *SetCommissionTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SetCommissionTx)
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+
+	res := tx.Validator.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	validatorAccount, success := getInput(view, tx.Validator)
+	if success.IsError() {
+		return result.Error("Failed to get the validator account: %v", tx.Validator.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(validatorAccount, signBytes, altSignBytes, tx.Validator)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Validator.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	coins := tx.Validator.Coins.NoNil()
+	if !coins.IsZero() {
+		return result.Error("SetCommissionTx cannot carry coins")
+	}
+
+	vcp := view.GetValidatorCandidatePool()
+	if vcp.FindStakeDelegate(tx.Validator.Address) == nil {
+		return result.Error("%v is not a validator candidate", tx.Validator.Address.Hex())
+	}
+
+	params := view.GetValidatorParams(tx.Validator.Address)
+	if params == nil {
+		params = &types.ValidatorParams{Validator: tx.Validator.Address}
+	}
+	if err := params.CanChangeCommissionRate(tx.CommissionRate, blockHeight, view.MaxCommissionRateChange()); err != nil {
+		return result.Error("%v", err)
+	}
+
+	if !validatorAccount.Balance.IsGTE(tx.Fee) {
+		return result.Error("Insufficient fund: balance is %v, but the fee is %v",
+			validatorAccount.Balance, tx.Fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *SetCommissionTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SetCommissionTx)
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+
+	validatorAccount, success := getInput(view, tx.Validator)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the validator account")
+	}
+
+	if !chargeFee(validatorAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	view.SetValidatorParams(&types.ValidatorParams{
+		Validator:        tx.Validator.Address,
+		CommissionRate:   tx.CommissionRate,
+		LastUpdateHeight: blockHeight,
+	})
+
+	validatorAccount.Sequence++
+	view.SetAccount(tx.Validator.Address, validatorAccount)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SetCommissionTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SetCommissionTx)
+	return &core.TxInfo{
+		Address:           tx.Validator.Address,
+		Sequence:          tx.Validator.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SetCommissionTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SetCommissionTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasSetCommissionTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// applyValidatorCommissions moves the commission of each validator out of the rewards of the
+// stakes delegated to it. A stake source may stake to several validators and guardians, so its
+// reward is first split pro-rata to its stakes, the same way the reward was calculated. It returns
+// the commission earned by each validator.
+func applyValidatorCommissions(view *st.StoreView, validatorSet *core.ValidatorSet, guardianVotes *core.AggregatedVotes,
+	guardianPool *core.GuardianCandidatePool, accountReward *map[string]types.Coins) map[common.Address]*big.Int {
+	commissions := map[common.Address]*big.Int{}
+	if len(*accountReward) == 0 {
+		return commissions
+	}
+
+	vcp := view.GetValidatorCandidatePool()
+	sourceStakes := map[common.Address]*big.Int{}
+	addStake := func(stake *core.Stake) {
+		if stake.Withdrawn {
+			return
+		}
+		if sum, exists := sourceStakes[stake.Source]; exists {
+			sourceStakes[stake.Source] = new(big.Int).Add(sum, stake.Amount)
+		} else {
+			sourceStakes[stake.Source] = stake.Amount
+		}
+	}
+	for _, v := range validatorSet.Validators() {
+		if holder := vcp.FindStakeDelegate(v.Address); holder != nil {
+			for _, stake := range holder.Stakes {
+				addStake(stake)
+			}
+		}
+	}
+	if guardianVotes != nil && guardianPool != nil {
+		for i, g := range guardianPool.WithStake().SortedGuardians {
+			if guardianVotes.Multiplies[i] == 0 {
+				continue
+			}
+			for _, stake := range g.Stakes {
+				addStake(stake)
+			}
+		}
+	}
+
+	// Calculate all the commissions from the original rewards, so that the result does not
+	// depend on the order of the validators
+	deductions := map[common.Address]*big.Int{}
+	validators := []common.Address{}
+	for _, v := range validatorSet.Validators() {
+		params := view.GetValidatorParams(v.Address)
+		if params == nil || params.CommissionRate == 0 {
+			continue
+		}
+		holder := vcp.FindStakeDelegate(v.Address)
+		if holder == nil {
+			continue
+		}
+		for _, stake := range holder.Stakes {
+			if stake.Withdrawn || stake.Source == v.Address {
+				continue // no commission on the stake of the validator itself
+			}
+			reward, ok := (*accountReward)[string(stake.Source[:])]
+			if !ok || reward.PTXWei.Sign() <= 0 {
+				continue
+			}
+			totalStake := sourceStakes[stake.Source]
+			if totalStake == nil || totalStake.Sign() == 0 {
+				continue
+			}
+
+			commission := new(big.Int).Mul(reward.PTXWei, stake.Amount)
+			commission.Div(commission, totalStake)
+			commission.Mul(commission, new(big.Int).SetUint64(params.CommissionRate))
+			commission.Div(commission, new(big.Int).SetUint64(types.MaxCommissionRate))
+			if commission.Sign() == 0 {
+				continue
+			}
+
+			if sum, exists := deductions[stake.Source]; exists {
+				deductions[stake.Source] = new(big.Int).Add(sum, commission)
+			} else {
+				deductions[stake.Source] = commission
+			}
+			if sum, exists := commissions[v.Address]; exists {
+				commissions[v.Address] = new(big.Int).Add(sum, commission)
+			} else {
+				commissions[v.Address] = commission
+				validators = append(validators, v.Address)
+			}
+		}
+	}
+
+	for source, deduction := range deductions {
+		key := string(source[:])
+		reward := (*accountReward)[key]
+		(*accountReward)[key] = types.Coins{
+			PandoWei: reward.PandoWei,
+			PTXWei:   new(big.Int).Sub(reward.PTXWei, deduction),
+		}.NoNil()
+	}
+	for _, validator := range validators {
+		key := string(validator[:])
+		reward, ok := (*accountReward)[key]
+		if !ok {
+			reward = types.Coins{}.NoNil()
+		}
+		(*accountReward)[key] = types.Coins{
+			PandoWei: reward.PandoWei,
+			PTXWei:   new(big.Int).Add(reward.PTXWei, commissions[validator]),
+		}.NoNil()
+
+		logger.Infof("Commission for validator %v : %v", validator.Hex(), commissions[validator])
+	}
+	return commissions
+}
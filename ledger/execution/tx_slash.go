@@ -56,11 +56,16 @@ func (exec *SlashTxExecutor) sanityCheck(chainID string, view *st.StoreView, tra
 		return result.Error("SignBytes: %X", signBytes)
 	}
 
+	if tx.DoubleSign != nil {
+		return exec.sanityCheckDoubleSign(chainID, view, tx)
+	}
+
 	slashedAddress := tx.SlashedAddress
 	slashedAccount := view.GetAccount(slashedAddress)
 	if slashedAccount == nil {
 		return result.Error("Account %v does not exist!", slashedAddress)
 	}
+	slashedAccount.ExpireKeyRotation(view.Height()) // the proof is only accepted from the current keys
 
 	reservedFundFound := false
 	for _, reservedFund := range slashedAccount.ReservedFunds {
@@ -92,6 +97,10 @@ func (exec *SlashTxExecutor) sanityCheck(chainID string, view *st.StoreView, tra
 func (exec *SlashTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
 	tx := transaction.(*types.SlashTx)
 
+	if tx.DoubleSign != nil {
+		return exec.processDoubleSign(chainID, view, tx)
+	}
+
 	slashedAddress := tx.SlashedAddress
 	slashedAccount := view.GetAccount(slashedAddress)
 
@@ -133,6 +142,85 @@ func (exec *SlashTxExecutor) process(chainID string, view *st.StoreView, transac
 	view.SetAccount(proposerAddress, proposerAccount)
 	view.SetAccount(slashedAddress, slashedAccount)
 
+	// Record the slash, so that the slashed account can appeal it
+	view.SetSlashRecord(&types.SlashRecord{
+		SlashedAddress:  slashedAddress,
+		ReserveSequence: tx.ReserveSequence,
+		Amount:          slashedAmount,
+		Beneficiary:     proposerAddress,
+		Height:          view.Height() + 1,
+	})
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SlashTxExecutor) sanityCheckDoubleSign(chainID string, view *st.StoreView, tx *types.SlashTx) result.Result {
+	evidence := tx.DoubleSign
+	if res := evidence.Validate(chainID); res.IsError() {
+		return result.Error("Invalid double sign evidence: %v", res.Message)
+	}
+
+	offender := evidence.Offender()
+	if tx.SlashedAddress != offender {
+		return result.Error("Slashed address %v is not the offender %v", tx.SlashedAddress, offender)
+	}
+
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if evidence.Height() >= blockHeight {
+		return result.Error("Double sign evidence is from the future: %v", evidence.Height())
+	}
+	if blockHeight-evidence.Height() > types.DoubleSignEvidenceMaxAge {
+		return result.Error("Double sign evidence is too old: %v", evidence.Height())
+	}
+
+	if view.GetDoubleSignSlashRecord(offender, evidence.Height()) != nil {
+		return result.Error("Validator %v has already been slashed for double signing at height %v",
+			offender, evidence.Height())
+	}
+
+	vcp := view.GetValidatorCandidatePool()
+	if vcp == nil || vcp.FindStakeDelegate(offender) == nil {
+		return result.Error("Validator %v has no stake to slash", offender)
+	}
+
+	return result.OK
+}
+
+// processDoubleSign burns a share of the stakes deposited to the validator who double signed. The
+// slashed stake is burnt rather than transferred to the proposer, so that the proposer gains
+// nothing by colluding with the validator.
+func (exec *SlashTxExecutor) processDoubleSign(chainID string, view *st.StoreView, tx *types.SlashTx) (common.Hash, result.Result) {
+	evidence := tx.DoubleSign
+	offender := evidence.Offender()
+
+	vcp := view.GetValidatorCandidatePool()
+	slashed, err := vcp.SlashStakes(offender, types.DoubleSignSlashPercentage)
+	if err != nil {
+		return common.Hash{}, result.Error("Failed to slash stakes, err: %v", err)
+	}
+	view.UpdateValidatorCandidatePool(vcp)
+
+	// Keep the withdrawn stakes waiting in the unbonding queue in sync with the pool
+	queue := view.GetStakeUnbondingQueue()
+	total := new(big.Int)
+	for _, stake := range slashed {
+		total.Add(total, stake.Amount)
+		if entry := queue.Get(stake.Source, offender, core.StakeForValidator); entry != nil {
+			entry.Amount = new(big.Int).Sub(entry.Amount, stake.Amount)
+		}
+	}
+	view.UpdateStakeUnbondingQueue(queue)
+
+	view.SetDoubleSignSlashRecord(&types.DoubleSignSlashRecord{
+		Offender:       offender,
+		EvidenceHeight: evidence.Height(),
+		Amount:         total,
+		Reporter:       tx.Proposer.Address,
+		Height:         view.Height() + 1,
+	})
+	logger.Infof("Slashed validator %v for double signing at height %v, burnt: %v", offender, evidence.Height(), total)
+
 	txHash := types.TxID(chainID, tx)
 	return txHash, result.OK
 }
@@ -166,7 +254,10 @@ func (exec *SlashTxExecutor) verifySlashProof(chainID string, slashedAccount *ty
 			}
 
 			sourceSignedBytes := servicePaymentTx.SourceSignBytes(chainID)
-			if !servicePaymentTx.Source.Signature.Verify(sourceSignedBytes, slashedAccount.Address) {
+			typedSignBytes, _ := types.TypedSignBytes(chainID, &servicePaymentTx)
+			canonicalSignBytes, _ := types.CanonicalSignBytes(chainID, &servicePaymentTx)
+			altSignBytes := []common.Bytes{typedSignBytes, canonicalSignBytes}
+			if !verifyAccountSignature(servicePaymentTx.Source.Signature, sourceSignedBytes, altSignBytes, slashedAccount) {
 				return false // servicePaymentTx not signed by the slashed account
 			}
 
@@ -0,0 +1,136 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*SlashAppealTxExecutor)(nil)
+
+// ------------------------------- SlashAppeal Transaction -----------------------------------
+
+// SlashAppealTxExecutor implements the TxExecutor interface
+type SlashAppealTxExecutor struct {
+}
+
+// NewSlashAppealTxExecutor creates a new instance of SlashAppealTxExecutor
+func NewSlashAppealTxExecutor() *SlashAppealTxExecutor {
+	return &SlashAppealTxExecutor{}
+}
+
+func (exec *SlashAppealTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SlashAppealTx)
+
+	res := tx.Appellant.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	appellantAccount, success := getInput(view, tx.Appellant)
+	if success.IsError() {
+		return result.Error("Failed to get the appellant account: %v", tx.Appellant.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(appellantAccount, signBytes, altSignBytes, tx.Appellant)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Appellant.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	record := view.GetSlashRecord(tx.Appellant.Address, tx.ReserveSequence)
+	if record == nil {
+		return result.Error("No slash found for %v with reserve sequence %v",
+			tx.Appellant.Address.Hex(), tx.ReserveSequence).WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	blockHeight := view.Height() + 1
+	if blockHeight > record.Height+types.SlashAppealWindow {
+		return result.Error("The slash at height %v can only be appealed until height %v",
+			record.Height, record.Height+types.SlashAppealWindow).WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	if view.GetSlashAppeals().Get(tx.Appellant.Address, tx.ReserveSequence) != nil {
+		return result.Error("The slash is already being appealed").WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	bond := tx.Bond.NoNil()
+	minimumBond := new(big.Int).SetUint64(types.MinimumSlashAppealBondPTXWei)
+	if bond.PandoWei.Sign() != 0 || bond.PTXWei.Cmp(minimumBond) < 0 {
+		return result.Error("The bond needs to be at least %v PTXWei, and cannot contain Pando",
+			types.MinimumSlashAppealBondPTXWei).WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	minimalBalance := tx.Fee.Plus(bond)
+	if !appellantAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("SlashAppeal: Appellant did not have enough balance %v", tx.Appellant.Address.Hex()))
+		return result.Error("SlashAppeal: Appellant balance is %v, but required minimal balance is %v",
+			appellantAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *SlashAppealTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SlashAppealTx)
+
+	appellantAccount, success := getInput(view, tx.Appellant)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the appellant account")
+	}
+
+	if !chargeFee(appellantAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	// Lock the bond until the appeal is settled
+	bond := tx.Bond.NoNil()
+	appellantAccount.Balance = appellantAccount.Balance.Minus(bond)
+	appellantAccount.Sequence++
+	view.SetAccount(tx.Appellant.Address, appellantAccount)
+
+	appeal := &types.SlashAppeal{
+		Appellant:       tx.Appellant.Address,
+		ReserveSequence: tx.ReserveSequence,
+		Bond:            bond,
+		FiledHeight:     view.Height() + 1,
+		Approvals:       []common.Address{},
+	}
+	appeals := view.GetSlashAppeals()
+	appeals.Add(appeal)
+	view.UpdateSlashAppeals(appeals)
+
+	logger.Infof("Slash appeal filed: %v", appeal)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SlashAppealTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SlashAppealTx)
+	return &core.TxInfo{
+		Address:           tx.Appellant.Address,
+		Sequence:          tx.Appellant.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SlashAppealTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SlashAppealTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasSlashAppealTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
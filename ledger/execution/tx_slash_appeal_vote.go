@@ -0,0 +1,198 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/consensus"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*SlashAppealVoteTxExecutor)(nil)
+
+// ------------------------------- SlashAppealVote Transaction -----------------------------------
+
+// SlashAppealVoteTxExecutor implements the TxExecutor interface
+type SlashAppealVoteTxExecutor struct {
+}
+
+// NewSlashAppealVoteTxExecutor creates a new instance of SlashAppealVoteTxExecutor
+func NewSlashAppealVoteTxExecutor() *SlashAppealVoteTxExecutor {
+	return &SlashAppealVoteTxExecutor{}
+}
+
+func (exec *SlashAppealVoteTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SlashAppealVoteTx)
+
+	res := tx.Voter.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	voterAccount, success := getInput(view, tx.Voter)
+	if success.IsError() {
+		return result.Error("Failed to get the voter account: %v", tx.Voter.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(voterAccount, signBytes, altSignBytes, tx.Voter)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Voter.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if !voterAccount.Balance.IsGTE(tx.Fee) {
+		logger.Infof(fmt.Sprintf("SlashAppealVote: Voter did not have enough balance %v", tx.Voter.Address.Hex()))
+		return result.Error("SlashAppealVote: Voter balance is %v, but required minimal balance is %v",
+			voterAccount.Balance, tx.Fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	validatorSet := getSlashAppealValidatorSet(view)
+	if _, err := validatorSet.GetValidator(tx.Voter.Address); err != nil {
+		return result.Error("%v is not a validator", tx.Voter.Address.Hex()).
+			WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	appeal := view.GetSlashAppeals().Get(tx.Appellant, tx.ReserveSequence)
+	if appeal == nil {
+		return result.Error("No pending appeal found for %v with reserve sequence %v",
+			tx.Appellant.Hex(), tx.ReserveSequence).WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	blockHeight := view.Height() + 1
+	if blockHeight > appeal.ExpiryHeight() {
+		return result.Error("The appeal could only be approved until height %v", appeal.ExpiryHeight()).
+			WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	if appeal.HasApproved(tx.Voter.Address) {
+		return result.Error("%v has already approved the appeal", tx.Voter.Address.Hex()).
+			WithErrorCode(result.CodeInvalidSlashAppeal)
+	}
+
+	// The slash is reversed as soon as the appeal is approved, hence the insurance pool
+	// needs to be able to reimburse the slashed amount
+	if isSlashAppealApproved(validatorSet, append(appeal.Approvals, tx.Voter.Address)) {
+		record := view.GetSlashRecord(tx.Appellant, tx.ReserveSequence)
+		if record == nil {
+			return result.Error("No slash found for %v with reserve sequence %v",
+				tx.Appellant.Hex(), tx.ReserveSequence).WithErrorCode(result.CodeInvalidSlashAppeal)
+		}
+		poolAccount := view.GetAccount(types.SlashInsurancePoolAddress)
+		if poolAccount == nil || !poolAccount.Balance.IsGTE(record.Amount) {
+			return result.Error("The insurance pool cannot reimburse the slashed amount %v", record.Amount).
+				WithErrorCode(result.CodeInsufficientInsurancePoolFund)
+		}
+	}
+
+	return result.OK
+}
+
+func (exec *SlashAppealVoteTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SlashAppealVoteTx)
+
+	voterAccount, success := getInput(view, tx.Voter)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the voter account")
+	}
+
+	if !chargeFee(voterAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	voterAccount.Sequence++
+	view.SetAccount(tx.Voter.Address, voterAccount)
+
+	appeals := view.GetSlashAppeals()
+	appeal := appeals.Get(tx.Appellant, tx.ReserveSequence)
+	if appeal == nil {
+		return common.Hash{}, result.Error("No pending appeal found")
+	}
+	appeal.Approvals = append(appeal.Approvals, tx.Voter.Address)
+
+	if isSlashAppealApproved(getSlashAppealValidatorSet(view), appeal.Approvals) {
+		res := reverseSlash(view, appeal)
+		if res.IsError() {
+			return common.Hash{}, res
+		}
+		appeals.Remove(tx.Appellant, tx.ReserveSequence)
+	}
+	view.UpdateSlashAppeals(appeals)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SlashAppealVoteTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SlashAppealVoteTx)
+	return &core.TxInfo{
+		Address:           tx.Voter.Address,
+		Sequence:          tx.Voter.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SlashAppealVoteTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SlashAppealVoteTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasSlashAppealVoteTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// getSlashAppealValidatorSet returns the validators reviewing the slash appeals, which are
+// selected from the validator candidate pool of the state being updated
+func getSlashAppealValidatorSet(view *st.StoreView) *core.ValidatorSet {
+	vcp := view.GetValidatorCandidatePool()
+	if vcp == nil {
+		return core.NewValidatorSet()
+	}
+	return consensus.SelectTopStakeHoldersAsValidators(vcp)
+}
+
+// isSlashAppealApproved indicates whether the validators holding more than 2/3 of the stake
+// have approved the appeal
+func isSlashAppealApproved(validatorSet *core.ValidatorSet, approvals []common.Address) bool {
+	votes := make([]core.Vote, len(approvals))
+	for i, approval := range approvals {
+		votes[i] = core.Vote{ID: approval}
+	}
+	return validatorSet.HasMajorityVotes(votes)
+}
+
+// reverseSlash reimburses the slashed amount from the insurance pool and returns the bond
+// to the appellant
+func reverseSlash(view *st.StoreView, appeal *types.SlashAppeal) result.Result {
+	record := view.GetSlashRecord(appeal.Appellant, appeal.ReserveSequence)
+	if record == nil {
+		return result.Error("No slash found for %v with reserve sequence %v",
+			appeal.Appellant.Hex(), appeal.ReserveSequence)
+	}
+
+	poolAccount, res := getAccount(view, types.SlashInsurancePoolAddress)
+	if res.IsError() || !poolAccount.Balance.IsGTE(record.Amount) {
+		return result.Error("The insurance pool cannot reimburse the slashed amount %v", record.Amount).
+			WithErrorCode(result.CodeInsufficientInsurancePoolFund)
+	}
+	poolAccount.Balance = poolAccount.Balance.Minus(record.Amount)
+	view.SetAccount(types.SlashInsurancePoolAddress, poolAccount)
+
+	appellantAccount := getOrMakeAccount(view, appeal.Appellant)
+	appellantAccount.Balance = appellantAccount.Balance.Plus(record.Amount).Plus(appeal.Bond)
+	view.SetAccount(appeal.Appellant, appellantAccount)
+
+	view.DeleteSlashRecord(appeal.Appellant, appeal.ReserveSequence)
+
+	logger.Infof("Slash appeal approved, reimbursed %v to %v", record.Amount, appeal.Appellant.Hex())
+	return result.OK
+}
@@ -19,8 +19,9 @@ var _ TxExecutor = (*SmartContractTxExecutor)(nil)
 
 // SmartContractTxExecutor implements the TxExecutor interface
 type SmartContractTxExecutor struct {
-	state *st.LedgerState
-	chain *blockchain.Chain
+	state  *st.LedgerState
+	chain  *blockchain.Chain
+	tracer vm.Tracer // traces the EVM execution if set
 }
 
 // NewSmartContractTxExecutor creates a new instance of SmartContractTxExecutor
@@ -46,22 +47,14 @@ func (exec *SmartContractTxExecutor) sanityCheck(chainID string, view *st.StoreV
 		return result.Error("Failed to get the account (the address has no Pando nor PTX)")
 	}
 
-	// Validate input, advanced
-	signBytes := tx.SignBytes(chainID)
-	res = validateInputAdvanced(fromAccount, signBytes, tx.From)
-	if res.IsError() {
-		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.From.Address.Hex(), res))
-		return res
-	}
-
 	coins := tx.From.Coins.NoNil()
 	if !coins.IsNonnegative() {
 		return result.Error("Invalid value to transfer").
 			WithErrorCode(result.CodeInvalidValueToTransfer)
 	}
 
-	if !sanityCheckForGasPrice(tx.GasPrice) {
-		return result.Error("Insufficient gas price. Gas price needs to be at least %v PTXWei", types.MinimumGasPrice).
+	if !sanityCheckForGasPrice(view, tx.GasPrice) {
+		return result.Error("Insufficient gas price. Gas price needs to be at least %v PTXWei", minimumGasPrice(view)).
 			WithErrorCode(result.CodeInvalidGasPrice)
 	}
 
@@ -79,6 +72,20 @@ func (exec *SmartContractTxExecutor) sanityCheck(chainID string, view *st.StoreV
 			WithErrorCode(result.CodeFeeLimitTooHigh)
 	}
 
+	// Validate input, advanced. A session key needs to be permitted to spend the value and the fee limit
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	if ethSignBytes := getEthSignBytes(chainID, view, tx); ethSignBytes != nil && verifyAccountSignature(tx.From.Signature, ethSignBytes, nil, fromAccount) {
+		signBytes = ethSignBytes // signed by an Ethereum wallet
+	}
+	maxSpending := coins.Plus(types.Coins{PandoWei: zero, PTXWei: feeLimit})
+	res = validateInputAdvancedWithSessionKey(view, fromAccount, signBytes, altSignBytes, tx.From,
+		types.TxSmartContract, []common.Address{tx.To.Address}, maxSpending)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.From.Address.Hex(), res))
+		return res
+	}
+
 	value := coins.PTXWei // NoNil() already guarantees value is NOT nil
 	minimalBalance := types.Coins{
 		PandoWei: zero,
@@ -101,7 +108,8 @@ func (exec *SmartContractTxExecutor) process(chainID string, view *st.StoreView,
 	// Note: for contract deployment, vm.Execute() might transfer coins from the fromAccount to the
 	//       deployed smart contract. Thus, we should call vm.Execute() before calling getInput().
 	//       Otherwise, the fromAccount returned by getInput() will have incorrect balance.
-	evmRet, contractAddr, gasUsed, evmErr := vm.Execute(exec.state.ParentBlock(), tx, view)
+	vmConfig := vm.Config{Debug: exec.tracer != nil, Tracer: exec.tracer}
+	evmRet, contractAddr, gasUsed, evmErr := vm.ExecuteWithConfig(exec.state.ParentBlock(), tx, view, vmConfig)
 
 	fromAddress := tx.From.Address
 	fromAccount, success := getInput(view, tx.From)
@@ -117,6 +125,7 @@ func (exec *SmartContractTxExecutor) process(chainID string, view *st.StoreView,
 	if !chargeFee(fromAccount, fee) {
 		return common.Hash{}, result.Error("failed to charge transaction fee")
 	}
+	chargeSessionKey(view, tx.SignBytes(chainID), getAltSignBytes(chainID, view, tx), tx.From, tx.From.Coins.Plus(fee))
 
 	createContract := (tx.To.Address == common.Address{})
 	if !createContract { // vm.create() increments the sequence of the from account
@@ -132,7 +141,9 @@ func (exec *SmartContractTxExecutor) process(chainID string, view *st.StoreView,
 		// Do not record events if transaction is reverted
 		logs = nil
 	}
-	exec.chain.AddTxReceipt(tx, logs, evmRet, contractAddr, gasUsed, evmErr)
+	view.RunSideEffect(func() {
+		exec.chain.AddTxReceipt(tx, logs, evmRet, contractAddr, gasUsed, evmErr)
+	})
 
 	return txHash, result.OK
 }
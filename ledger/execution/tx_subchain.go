@@ -0,0 +1,438 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/core"
+	st "github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var _ TxExecutor = (*SubchainRegisterTxExecutor)(nil)
+var _ TxExecutor = (*SubchainStakeTxExecutor)(nil)
+var _ TxExecutor = (*SubchainCheckpointTxExecutor)(nil)
+var _ TxExecutor = (*SubchainChallengeTxExecutor)(nil)
+
+// ------------------------------- SubchainRegister Transaction -----------------------------------
+
+// SubchainRegisterTxExecutor implements the TxExecutor interface
+type SubchainRegisterTxExecutor struct {
+}
+
+// NewSubchainRegisterTxExecutor creates a new instance of SubchainRegisterTxExecutor
+func NewSubchainRegisterTxExecutor() *SubchainRegisterTxExecutor {
+	return &SubchainRegisterTxExecutor{}
+}
+
+func (exec *SubchainRegisterTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SubchainRegisterTx)
+
+	res := tx.Owner.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	ownerAccount, success := getInput(view, tx.Owner)
+	if success.IsError() {
+		return result.Error("Failed to get the owner account: %v", tx.Owner.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(ownerAccount, signBytes, altSignBytes, tx.Owner)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Owner.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if err := types.CheckSubchainChainID(tx.ChainID); err != nil {
+		return result.Error("%v", err).WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+	if tx.ChainID == chainID {
+		return result.Error("A subchain cannot reuse the chain ID of the main chain").WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+	if view.GetSubchain(types.SubchainID(tx.ChainID)) != nil {
+		return result.Error("Subchain %v is already registered", tx.ChainID).WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+	if tx.CheckpointInterval == 0 || tx.CheckpointInterval > types.MaximumSubchainCheckpointInterval {
+		return result.Error("The checkpoint interval needs to be between 1 and %v blocks",
+			types.MaximumSubchainCheckpointInterval).WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+
+	deposit := tx.Owner.Coins.NoNil()
+	if deposit.PandoWei.Sign() != 0 || deposit.PTXWei.Cmp(types.MinimumSubchainDeposit()) < 0 {
+		return result.Error("The deposit needs to be at least %v PTXWei, and cannot contain Pando",
+			types.MinimumSubchainDeposit()).WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+
+	minimalBalance := deposit.Plus(tx.Fee)
+	if !ownerAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("SubchainRegister: Owner did not have enough balance %v", tx.Owner.Address.Hex()))
+		return result.Error("SubchainRegister: Owner balance is %v, but required minimal balance is %v",
+			ownerAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *SubchainRegisterTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SubchainRegisterTx)
+
+	ownerAccount, success := getInput(view, tx.Owner)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the owner account")
+	}
+
+	if !chargeFee(ownerAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	deposit := tx.Owner.Coins.NoNil()
+	ownerAccount.Balance = ownerAccount.Balance.Minus(deposit)
+	ownerAccount.Sequence++
+	view.SetAccount(tx.Owner.Address, ownerAccount)
+
+	view.SetSubchain(&types.Subchain{
+		ID:                 types.SubchainID(tx.ChainID),
+		ChainID:            tx.ChainID,
+		Owner:              tx.Owner.Address,
+		Deposit:            deposit,
+		CheckpointInterval: tx.CheckpointInterval,
+		RegisteredHeight:   view.Height() + 1,
+	})
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SubchainRegisterTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SubchainRegisterTx)
+	return &core.TxInfo{
+		Address:           tx.Owner.Address,
+		Sequence:          tx.Owner.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SubchainRegisterTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SubchainRegisterTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasSubchainRegisterTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- SubchainStake Transaction -----------------------------------
+
+// SubchainStakeTxExecutor implements the TxExecutor interface
+type SubchainStakeTxExecutor struct {
+}
+
+// NewSubchainStakeTxExecutor creates a new instance of SubchainStakeTxExecutor
+func NewSubchainStakeTxExecutor() *SubchainStakeTxExecutor {
+	return &SubchainStakeTxExecutor{}
+}
+
+func (exec *SubchainStakeTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SubchainStakeTx)
+
+	if tx.Withdraw {
+		res := validateFeePayer(chainID, view, tx, tx.Staker, tx.Fee)
+		if res.IsError() {
+			return res
+		}
+		subchain := view.GetSubchain(tx.SubchainID)
+		if subchain == nil {
+			return result.Error("Subchain %v is not registered", tx.SubchainID.Hex()).WithErrorCode(result.CodeInvalidSubchainTx)
+		}
+		if err := subchain.WithdrawStake(tx.Staker.Address, 0); err != nil {
+			return result.Error("%v", err).WithErrorCode(result.CodeInvalidSubchainTx)
+		}
+		return result.OK
+	}
+
+	res := tx.Staker.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	stakerAccount, success := getInput(view, tx.Staker)
+	if success.IsError() {
+		return result.Error("Failed to get the staker account: %v", tx.Staker.Address)
+	}
+
+	signBytes := tx.SignBytes(chainID)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(stakerAccount, signBytes, altSignBytes, tx.Staker)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Staker.Address.Hex(), res))
+		return res
+	}
+
+	if !sanityCheckForFee(view, tx.Fee) {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	subchain := view.GetSubchain(tx.SubchainID)
+	if subchain == nil {
+		return result.Error("Subchain %v is not registered", tx.SubchainID.Hex()).WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+
+	stake := tx.Staker.Coins.NoNil()
+	if stake.PTXWei.Sign() != 0 || stake.PandoWei.Sign() <= 0 {
+		return result.Error("The stake needs to be in Pando only").WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+	totalStake := new(big.Int).Set(stake.PandoWei)
+	if existing := subchain.GetStake(tx.Staker.Address); existing != nil {
+		totalStake.Add(totalStake, existing.Amount)
+	}
+	if totalStake.Cmp(types.MinimumSubchainStake()) < 0 {
+		return result.Error("The stake of a subchain validator needs to be at least %v PandoWei",
+			types.MinimumSubchainStake()).WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+	if err := subchain.DepositStake(tx.Staker.Address, stake.PandoWei); err != nil {
+		return result.Error("%v", err).WithErrorCode(result.CodeInvalidSubchainTx)
+	}
+
+	minimalBalance := stake.Plus(tx.Fee)
+	if !stakerAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("SubchainStake: Staker did not have enough balance %v", tx.Staker.Address.Hex()))
+		return result.Error("SubchainStake: Staker balance is %v, but required minimal balance is %v",
+			stakerAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	return result.OK
+}
+
+func (exec *SubchainStakeTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SubchainStakeTx)
+
+	subchain := view.GetSubchain(tx.SubchainID)
+	if subchain == nil {
+		return common.Hash{}, result.Error("Subchain %v is not registered", tx.SubchainID.Hex())
+	}
+
+	if tx.Withdraw {
+		if res := chargeFeePayer(view, tx.Staker, tx.Fee); res.IsError() {
+			return common.Hash{}, res
+		}
+		// The withdrawn stake remains slashable until the checkpoints it may have signed are final
+		returnHeight := view.Height() + 1 + types.SubchainFraudProofWindow
+		if err := subchain.WithdrawStake(tx.Staker.Address, returnHeight); err != nil {
+			return common.Hash{}, result.Error("Failed to withdraw the stake: %v", err)
+		}
+		view.SetSubchain(subchain)
+		view.ScheduleSubchainUpdate(subchain.ID, returnHeight)
+
+		txHash := types.TxID(chainID, tx)
+		return txHash, result.OK
+	}
+
+	stakerAccount, success := getInput(view, tx.Staker)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the staker account")
+	}
+
+	if !chargeFee(stakerAccount, tx.Fee) {
+		return common.Hash{}, result.Error("Failed to charge transaction fee")
+	}
+
+	stake := tx.Staker.Coins.NoNil()
+	if err := subchain.DepositStake(tx.Staker.Address, stake.PandoWei); err != nil {
+		return common.Hash{}, result.Error("Failed to deposit the stake: %v", err)
+	}
+	stakerAccount.Balance = stakerAccount.Balance.Minus(stake)
+	stakerAccount.Sequence++
+	view.SetAccount(tx.Staker.Address, stakerAccount)
+	view.SetSubchain(subchain)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SubchainStakeTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SubchainStakeTx)
+	return &core.TxInfo{
+		Address:           tx.Staker.Address,
+		Sequence:          tx.Staker.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SubchainStakeTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SubchainStakeTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(types.GasSubchainStakeTx)
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- SubchainCheckpoint Transaction -----------------------------------
+
+// SubchainCheckpointTxExecutor implements the TxExecutor interface
+type SubchainCheckpointTxExecutor struct {
+}
+
+// NewSubchainCheckpointTxExecutor creates a new instance of SubchainCheckpointTxExecutor
+func NewSubchainCheckpointTxExecutor() *SubchainCheckpointTxExecutor {
+	return &SubchainCheckpointTxExecutor{}
+}
+
+func (exec *SubchainCheckpointTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SubchainCheckpointTx)
+
+	res := validateFeePayer(chainID, view, tx, tx.Submitter, tx.Fee)
+	if res.IsError() {
+		return res
+	}
+
+	subchain := view.GetSubchain(tx.SubchainID)
+	if subchain == nil {
+		return result.Error("Subchain %v is not registered", tx.SubchainID.Hex()).WithErrorCode(result.CodeInvalidSubchainCheckpoint)
+	}
+	if len(subchain.Pending) >= types.MaximumPendingSubchainCheckpoints {
+		return result.Error("Subchain %v already has %v checkpoints within their fraud proof window",
+			subchain.ChainID, len(subchain.Pending)).WithErrorCode(result.CodeInvalidSubchainCheckpoint)
+	}
+	if _, err := subchain.VerifyCheckpoint(chainID, tx.Checkpoint(), tx.Signatures); err != nil {
+		return result.Error("Invalid checkpoint: %v", err).WithErrorCode(result.CodeInvalidSubchainCheckpoint)
+	}
+
+	return result.OK
+}
+
+func (exec *SubchainCheckpointTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SubchainCheckpointTx)
+
+	subchain := view.GetSubchain(tx.SubchainID)
+	if subchain == nil {
+		return common.Hash{}, result.Error("Subchain %v is not registered", tx.SubchainID.Hex())
+	}
+	checkpoint := tx.Checkpoint()
+	signers, err := subchain.VerifyCheckpoint(chainID, checkpoint, tx.Signatures)
+	if err != nil {
+		return common.Hash{}, result.Error("Invalid checkpoint: %v", err)
+	}
+
+	if res := chargeFeePayer(view, tx.Submitter, tx.Fee); res.IsError() {
+		return common.Hash{}, res
+	}
+
+	checkpoint.SubmitHeight = view.Height() + 1
+	checkpoint.FinalizeHeight = checkpoint.SubmitHeight + types.SubchainFraudProofWindow
+	checkpoint.Signers = signers
+	subchain.AddPending(checkpoint)
+	view.SetSubchain(subchain)
+	view.ScheduleSubchainUpdate(subchain.ID, checkpoint.FinalizeHeight)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SubchainCheckpointTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SubchainCheckpointTx)
+	return &core.TxInfo{
+		Address:           tx.Submitter.Address,
+		Sequence:          tx.Submitter.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SubchainCheckpointTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SubchainCheckpointTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(tx.Gas())
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
+
+// ------------------------------- SubchainChallenge Transaction -----------------------------------
+
+// SubchainChallengeTxExecutor implements the TxExecutor interface
+type SubchainChallengeTxExecutor struct {
+}
+
+// NewSubchainChallengeTxExecutor creates a new instance of SubchainChallengeTxExecutor
+func NewSubchainChallengeTxExecutor() *SubchainChallengeTxExecutor {
+	return &SubchainChallengeTxExecutor{}
+}
+
+func (exec *SubchainChallengeTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	tx := transaction.(*types.SubchainChallengeTx)
+
+	res := validateFeePayer(chainID, view, tx, tx.Challenger, tx.Fee)
+	if res.IsError() {
+		return res
+	}
+
+	subchain := view.GetSubchain(tx.SubchainID)
+	if subchain == nil {
+		return result.Error("Subchain %v is not registered", tx.SubchainID.Hex()).WithErrorCode(result.CodeInvalidSubchainChallenge)
+	}
+	if _, err := subchain.VerifyChallenge(chainID, tx.Conflict(), tx.Signatures); err != nil {
+		return result.Error("Invalid challenge: %v", err).WithErrorCode(result.CodeInvalidSubchainChallenge)
+	}
+
+	return result.OK
+}
+
+func (exec *SubchainChallengeTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.SubchainChallengeTx)
+
+	subchain := view.GetSubchain(tx.SubchainID)
+	if subchain == nil {
+		return common.Hash{}, result.Error("Subchain %v is not registered", tx.SubchainID.Hex())
+	}
+	equivocators, err := subchain.VerifyChallenge(chainID, tx.Conflict(), tx.Signatures)
+	if err != nil {
+		return common.Hash{}, result.Error("Invalid challenge: %v", err)
+	}
+
+	if res := chargeFeePayer(view, tx.Challenger, tx.Fee); res.IsError() {
+		return common.Hash{}, res
+	}
+
+	// The disproven checkpoint is reverted along with the checkpoints building on it, and the
+	// equivocators lose their whole stake. The challenger is rewarded with a share of the slashed
+	// stakes, the rest is burnt.
+	reverted := subchain.RevertFrom(tx.Height)
+	slashed := subchain.Slash(equivocators)
+	view.SetSubchain(subchain)
+
+	reward := new(big.Int).Div(new(big.Int).Mul(slashed, big.NewInt(types.SubchainChallengeRewardPercentage)), big.NewInt(100))
+	challengerAccount := getOrMakeAccount(view, tx.Challenger.Address)
+	challengerAccount.Balance = challengerAccount.Balance.Plus(types.Coins{PandoWei: reward, PTXWei: big.NewInt(0)})
+	view.SetAccount(tx.Challenger.Address, challengerAccount)
+
+	logger.Infof("Subchain %v challenged at height %v, reverted %v checkpoints, slashed %v PandoWei from %v equivocators",
+		subchain.ChainID, tx.Height, len(reverted), slashed, len(equivocators))
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *SubchainChallengeTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.SubchainChallengeTx)
+	return &core.TxInfo{
+		Address:           tx.Challenger.Address,
+		Sequence:          tx.Challenger.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *SubchainChallengeTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.SubchainChallengeTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(tx.Gas())
+	effectiveGasPrice := new(big.Int).Div(fee.PTXWei, gas)
+	return effectiveGasPrice
+}
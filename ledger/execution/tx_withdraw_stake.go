@@ -7,6 +7,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 )
@@ -41,15 +42,16 @@ func (exec *WithdrawStakeExecutor) sanityCheck(chainID string, view *st.StoreVie
 	}
 
 	signBytes := tx.SignBytes(chainID)
-	res = validateInputAdvanced(sourceAccount, signBytes, tx.Source)
+	altSignBytes := getAltSignBytes(chainID, view, tx)
+	res = validateInputAdvanced(sourceAccount, signBytes, altSignBytes, tx.Source)
 	if res.IsError() {
 		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Source.Address.Hex(), res))
 		return res
 	}
 
-	if !sanityCheckForFee(tx.Fee) {
+	if !sanityCheckForFee(view, tx.Fee) {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v PTXWei",
-			types.MinimumTransactionFeePTXWei).WithErrorCode(result.CodeInvalidFee)
+			minimumTxFee(view)).WithErrorCode(result.CodeInvalidFee)
 	}
 
 	if !(tx.Purpose == core.StakeForValidator || tx.Purpose == core.StakeForGuardian) {
@@ -85,7 +87,12 @@ func (exec *WithdrawStakeExecutor) process(chainID string, view *st.StoreView, t
 	sourceAddress := tx.Source.Address
 	holderAddress := tx.Holder.Address
 
-	if tx.Purpose == core.StakeForValidator {
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if features.IsEnabled(features.StakeUnbondingQueue, blockHeight) {
+		if res := withdrawStakeToUnbondingQueue(view, exec.state.Height(), sourceAddress, holderAddress, tx.Purpose); res.IsError() {
+			return common.Hash{}, res
+		}
+	} else if tx.Purpose == core.StakeForValidator {
 		vcp := view.GetValidatorCandidatePool()
 		currentHeight := exec.state.Height()
 		err := vcp.WithdrawStake(sourceAddress, holderAddress, currentHeight)
@@ -111,7 +118,6 @@ func (exec *WithdrawStakeExecutor) process(chainID string, view *st.StoreView, t
 		if hl == nil {
 			hl = &types.HeightList{}
 		}
-		blockHeight := view.Height() + 1 // the view points to the parent of the current block
 		hl.Append(blockHeight)
 		view.UpdateStakeTransactionHeightList(hl)
 	}
@@ -123,6 +129,45 @@ func (exec *WithdrawStakeExecutor) process(chainID string, view *st.StoreView, t
 	return txHash, result.OK
 }
 
+// withdrawStakeToUnbondingQueue withdraws the stake, locks it for the unbonding period set by the
+// governance, and adds it to the unbonding queue from which it is returned to the source
+func withdrawStakeToUnbondingQueue(view *st.StoreView, currentHeight uint64, sourceAddress common.Address,
+	holderAddress common.Address, purpose uint8) result.Result {
+	returnHeight := currentHeight + view.StakeUnbondingPeriod()
+
+	var stake *core.Stake
+	var err error
+	if purpose == core.StakeForValidator {
+		vcp := view.GetValidatorCandidatePool()
+		stake, err = vcp.WithdrawStakeUntil(sourceAddress, holderAddress, returnHeight)
+		if err != nil {
+			return result.Error("Failed to withdraw stake, err: %v", err)
+		}
+		view.UpdateValidatorCandidatePool(vcp)
+	} else if purpose == core.StakeForGuardian {
+		gcp := view.GetGuardianCandidatePool()
+		stake, err = gcp.WithdrawStakeUntil(sourceAddress, holderAddress, returnHeight)
+		if err != nil {
+			return result.Error("Failed to withdraw stake, err: %v", err)
+		}
+		view.UpdateGuardianCandidatePool(gcp)
+	} else {
+		return result.Error("Invalid staking purpose").WithErrorCode(result.CodeInvalidStakePurpose)
+	}
+
+	queue := view.GetStakeUnbondingQueue()
+	queue.Add(&types.StakeUnbonding{
+		Source:         sourceAddress,
+		Holder:         holderAddress,
+		Purpose:        purpose,
+		Amount:         new(big.Int).Set(stake.Amount),
+		WithdrawHeight: currentHeight,
+		ReleaseHeight:  returnHeight,
+	})
+	view.UpdateStakeUnbondingQueue(queue)
+	return result.OK
+}
+
 func (exec *WithdrawStakeExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
 	tx := transaction.(*types.WithdrawStakeTx)
 	return &core.TxInfo{
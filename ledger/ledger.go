@@ -1,13 +1,15 @@
 package ledger
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
 
-	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/store/kvstore"
 	"github.com/spf13/viper"
 
@@ -16,12 +18,15 @@ import (
 	"github.com/pandotoken/pando/blockchain"
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/common/util"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
 	exec "github.com/pandotoken/pando/ledger/execution"
+	"github.com/pandotoken/pando/ledger/sigverify"
 	"github.com/pandotoken/pando/ledger/state"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/ledger/vm"
 	mp "github.com/pandotoken/pando/mempool"
 	"github.com/pandotoken/pando/store/database"
 )
@@ -44,21 +49,30 @@ type Ledger struct {
 	mu       *sync.RWMutex // Lock for accessing ledger state.
 	state    *st.LedgerState
 	executor *exec.Executor
+
+	sigVerifier *sigverify.Verifier // Recovers the signers of the block transactions ahead of the execution.
+
+	mempoolUpdates sync.WaitGroup // Tracks the pending mempool updates triggered by the applied blocks.
 }
 
 // NewLedger creates an instance of Ledger
 func NewLedger(chainID string, db database.Database, chain *blockchain.Chain, consensus core.ConsensusEngine, valMgr core.ValidatorManager, mempool *mp.Mempool) *Ledger {
 	state := st.NewLedgerState(chainID, db)
 	executor := exec.NewExecutor(db, chain, state, consensus, valMgr)
+	sigVerifier := sigverify.NewVerifier(chainID, viper.GetInt(common.CfgConsensusSignatureVerificationWorkers))
 	ledger := &Ledger{
-		db:        db,
-		chain:     chain,
-		consensus: consensus,
-		valMgr:    valMgr,
-		mempool:   mempool,
-		mu:        &sync.RWMutex{},
-		state:     state,
-		executor:  executor,
+		db:          db,
+		chain:       chain,
+		consensus:   consensus,
+		valMgr:      valMgr,
+		mempool:     mempool,
+		mu:          &sync.RWMutex{},
+		state:       state,
+		executor:    executor,
+		sigVerifier: sigVerifier,
+	}
+	if mempool != nil {
+		mempool.SetSignatureVerifier(sigVerifier)
 	}
 	return ledger
 }
@@ -100,7 +114,6 @@ func (ledger *Ledger) GetFinalizedSnapshot() (*st.StoreView, error) {
 // GetFinalizedValidatorCandidatePool returns the validator candidate pool of the latest DIRECTLY finalized block
 func (ledger *Ledger) GetFinalizedValidatorCandidatePool(blockHash common.Hash, isNext bool) (*core.ValidatorCandidatePool, error) {
 	db := ledger.state.DB()
-	store := kvstore.NewKVStore(db)
 
 	var i int
 	if isNext {
@@ -109,7 +122,7 @@ func (ledger *Ledger) GetFinalizedValidatorCandidatePool(blockHash common.Hash,
 		i = 2
 	}
 	for ; ; i-- {
-		block, err := findBlock(store, blockHash)
+		block, err := ledger.chain.FindBlock(blockHash)
 		if err != nil {
 			logger.Errorf("Failed to find block for VCP: %v, err: %v", blockHash.Hex(), err)
 			return nil, err
@@ -143,16 +156,15 @@ func (ledger *Ledger) GetFinalizedValidatorCandidatePool(blockHash common.Hash,
 // GetGuardianCandidatePool returns the guardian candidate pool of the given block.
 func (ledger *Ledger) GetGuardianCandidatePool(blockHash common.Hash) (*core.GuardianCandidatePool, error) {
 	db := ledger.state.DB()
-	store := kvstore.NewKVStore(db)
 
 	// Find last checkpoint and retrieve GCP.
-	block, err := findBlock(store, blockHash)
+	block, err := ledger.chain.FindBlock(blockHash)
 	if err != nil {
 		return nil, err
 	}
 	blockHash = block.Hash()
 	for {
-		block, err := findBlock(store, blockHash)
+		block, err := ledger.chain.FindBlock(blockHash)
 		if err != nil {
 			return nil, err
 		}
@@ -166,15 +178,6 @@ func (ledger *Ledger) GetGuardianCandidatePool(blockHash common.Hash) (*core.Gua
 	}
 }
 
-func findBlock(store store.Store, blockHash common.Hash) (*core.ExtendedBlock, error) {
-	var block core.ExtendedBlock
-	err := store.Get(blockHash[:], &block)
-	if err != nil {
-		return nil, err
-	}
-	return &block, nil
-}
-
 // ScreenTxUnsafe screens the given transaction without locking.
 func (ledger *Ledger) ScreenTxUnsafe(rawTx common.Bytes) (res result.Result) {
 	var tx types.Tx
@@ -231,31 +234,51 @@ func (ledger *Ledger) ProposeBlockTxs(block *core.Block) (stateRootHash common.H
 	defer func() { ledger.currentBlock = nil }()
 
 	view := ledger.state.Checked()
+	ledger.recordParentBlockHash(block, view)
+	ledger.recordRandomness(block, view)
 
 	// Add special transactions
 	rawTxCandidates := []common.Bytes{}
 	ledger.addSpecialTransactions(block, view, &rawTxCandidates)
 
-	// Add regular transactions submitted by the clients
-	regularRawTxs := ledger.mempool.ReapUnsafe(core.MaxNumRegularTxsPerBlock)
+	// Add regular transactions submitted by the clients, packed by fee priority under the block budget
+	// The usage tracks the transactions which pass the check, the ones the block actually includes
+	var budget, usage *types.BlockBudget
+	if features.IsEnabled(features.BlockBudget, view.Height()+1) {
+		budget = view.NewBlockBudget()
+		usage = view.NewBlockBudget()
+	}
+	regularRawTxs := ledger.mempool.ReapWithBudgetUnsafe(core.MaxNumRegularTxsPerBlock, budget)
 	for _, regularRawTx := range regularRawTxs {
 		rawTxCandidates = append(rawTxCandidates, regularRawTx)
 	}
 
 	blockRawTxs = []common.Bytes{}
+	numRegularTxs := 0
 	for _, rawTxCandidate := range rawTxCandidates {
 		tx, err := types.TxFromBytes(rawTxCandidate)
 		if err != nil {
 			continue
 		}
+		isRegularTx := !ledger.shouldSkipCheckTx(tx)
+		if usage != nil && isRegularTx && !usage.Fits(types.TxGas(tx), uint64(len(rawTxCandidate))) {
+			continue
+		}
 		_, res := ledger.executor.CheckTx(tx)
 		if res.IsError() {
 			logger.Errorf("Transaction check failed: errMsg = %v, tx = %v", res.Message, tx)
 			continue
 		}
 		blockRawTxs = append(blockRawTxs, rawTxCandidate)
+		if isRegularTx {
+			numRegularTxs++
+			if usage != nil {
+				usage.Add(rawTxCandidate, tx)
+			}
+		}
 	}
 
+	ledger.updateBaseFee(block, view, numRegularTxs, usage)
 	ledger.handleDelayedStateUpdates(view)
 
 	stateRootHash = view.Hash()
@@ -271,6 +294,9 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 	// Otherwise, could cause deadlock since mempool.InsertTransaction() also first acquires the mempool, and then the ledger lock
 	logger.Debugf("ApplyBlockTxs: Apply block transactions, block.height = %v", block.Height)
 
+	// The signers are recovered in parallel, and before taking the lock since it does not read the state
+	ledger.sigVerifier.VerifyTxs(block.Txs)
+
 	ledger.mu.Lock()
 	defer ledger.mu.Unlock()
 
@@ -289,36 +315,66 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 		panic(fmt.Sprintf("Failed to find the parent block: %v, err: %v", block.Parent.Hex(), err))
 	}
 	parentBlock := extParentBlock.Block
+	ledger.recordParentBlockHash(block, view)
+	ledger.recordRandomness(block, view)
 	logger.Debugf("ApplyBlockTxs: Start applying block transactions, block.height = %v", block.Height)
 
+	var budget *types.BlockBudget
+	if features.IsEnabled(features.BlockBudget, block.Height) {
+		budget = view.NewBlockBudget()
+	}
+
+	// The txs up to the first invalid one are executed, the block is rejected with the error of
+	// the invalid tx if they all succeed
 	hasValidatorUpdate := false
-	txProcessTime := []time.Duration{}
+	numRegularTxs := 0
+	txs := []types.Tx{}
+	invalidTxRes := result.OK
 	for _, rawTx := range blockRawTxs {
-		start := time.Now()
 		tx, err := types.TxFromBytes(rawTx)
 		if err != nil {
-			//ledger.resetState(currHeight, currStateRoot)
-			ledger.resetState(parentBlock)
-			return result.Error("Failed to parse transaction: %v", hex.EncodeToString(rawTx))
+			invalidTxRes = result.Error("Failed to parse transaction: %v", hex.EncodeToString(rawTx))
+			break
 		}
-		if _, ok := tx.(*types.DepositStakeTx); ok {
-			hasValidatorUpdate = true
-		} else if _, ok := tx.(*types.WithdrawStakeTx); ok {
+		if budget != nil && !ledger.shouldSkipCheckTx(tx) {
+			if err := budget.Add(rawTx, tx); err != nil {
+				invalidTxRes = result.Error("Block %v exceeds the block budget: %v", block.Height, err)
+				break
+			}
+		}
+		if isStakeUpdateTx(tx) {
 			hasValidatorUpdate = true
 		}
-		_, res := ledger.executor.ExecuteTx(tx)
-		if res.IsError() {
-			//ledger.resetState(currHeight, currStateRoot)
-			ledger.resetState(parentBlock)
-			return res
+		if !ledger.shouldSkipCheckTx(tx) {
+			numRegularTxs++
 		}
-		txProcessTime = append(txProcessTime, time.Since(start))
+		txs = append(txs, tx)
 	}
 
+	start := time.Now()
+	workers := viper.GetInt(common.CfgConsensusParallelExecutionWorkers)
+	failedIdx, res := ledger.executor.ExecuteTxs(txs, workers)
+	if res.IsError() {
+		//ledger.resetState(currHeight, currStateRoot)
+		ledger.resetState(parentBlock)
+		traceBlockTx(blockRawTxs[failedIdx], block, "execution_failed", "error: %v", res.Message)
+		return res
+	}
+	for _, rawTx := range blockRawTxs[:len(txs)] {
+		traceBlockTx(rawTx, block, "executed", "result: %v", res.Code)
+	}
+	if invalidTxRes.IsError() {
+		ledger.resetState(parentBlock)
+		return invalidTxRes
+	}
+	txProcessTime := time.Since(start)
+
 	logger.Debugf("ApplyBlockTxs: Finish applying block transactions, block.height=%v, txProcessTime=%v", block.Height, txProcessTime)
 
-	start := time.Now()
-	ledger.handleDelayedStateUpdates(view)
+	ledger.updateBaseFee(block, view, numRegularTxs, budget)
+
+	start = time.Now()
+	announced := ledger.handleDelayedStateUpdates(view)
 	handleDelayedUpdateTime := time.Since(start)
 
 	newStateRoot := view.Hash()
@@ -334,12 +390,21 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 	ledger.state.Commit() // commit to persistent storage
 	commitTime := time.Since(start)
 
+	ledger.chain.AddParamChangeAnnouncements(block.Hash(), announced)
+
 	logger.Debugf("ApplyBlockTxs: Committed state change, block.height = %v", block.Height)
 
+	ledger.mempoolUpdates.Add(1)
 	go func() {
+		defer ledger.mempoolUpdates.Done()
+
 		ledger.mempool.Lock()
 		defer ledger.mempool.Unlock()
 
+		if viper.GetBool(common.CfgMempoolInclusionAudit) {
+			gracePeriod := time.Duration(viper.GetInt(common.CfgMempoolInclusionAuditGracePeriod)) * time.Second
+			ledger.mempool.AuditInclusionUnsafe(block, gracePeriod)
+		}
 		ledger.mempool.UpdateUnsafe(blockRawTxs) // clear txs from the mempool
 	}()
 
@@ -351,6 +416,22 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 	return result.OKWith(result.Info{"hasValidatorUpdate": hasValidatorUpdate})
 }
 
+// WaitForMempoolUpdate blocks until the mempool updates triggered by the applied blocks are done,
+// so that transactions inserted afterwards are not screened again against a stale state
+func (ledger *Ledger) WaitForMempoolUpdate() {
+	ledger.mempoolUpdates.Wait()
+}
+
+// traceBlockTx records the execution of the transaction in the block if the transaction is traced
+func traceBlockTx(rawTx common.Bytes, block *core.Block, stage string, format string, args ...interface{}) {
+	if !util.IsTracing(util.TraceTx) {
+		return
+	}
+	detail := fmt.Sprintf(format, args...)
+	util.Trace(util.TraceTx, crypto.Keccak256Hash(rawTx).Hex(), "ledger", stage, "block: %v, height: %v, %v",
+		block.Hash().Hex(), block.Height, detail)
+}
+
 // ApplyBlockTxsForChainCorrection applies all block's txs and re-calculate root hash
 func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common.Hash, result.Result) {
 	ledger.mempool.Lock()
@@ -373,8 +454,16 @@ func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common
 		panic(fmt.Sprintf("Failed to find the parent block: %v, err: %v", block.Parent.Hex(), err))
 	}
 	parentBlock := extParentBlock.Block
+	ledger.recordParentBlockHash(block, view)
+	ledger.recordRandomness(block, view)
+
+	var budget *types.BlockBudget
+	if features.IsEnabled(features.BlockBudget, block.Height) {
+		budget = view.NewBlockBudget()
+	}
 
 	hasValidatorUpdate := false
+	numRegularTxs := 0
 	for _, rawTx := range blockRawTxs {
 		tx, err := types.TxFromBytes(rawTx)
 		if err != nil {
@@ -382,9 +471,13 @@ func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common
 			ledger.resetState(parentBlock)
 			return common.Hash{}, result.Error("Failed to parse transaction: %v", hex.EncodeToString(rawTx))
 		}
-		if _, ok := tx.(*types.DepositStakeTx); ok {
-			hasValidatorUpdate = true
-		} else if _, ok := tx.(*types.WithdrawStakeTx); ok {
+		if budget != nil && !ledger.shouldSkipCheckTx(tx) {
+			if err := budget.Add(rawTx, tx); err != nil {
+				ledger.resetState(parentBlock)
+				return common.Hash{}, result.Error("Block %v exceeds the block budget: %v", block.Height, err)
+			}
+		}
+		if isStakeUpdateTx(tx) {
 			hasValidatorUpdate = true
 		}
 		_, res := ledger.executor.ExecuteTx(tx)
@@ -393,15 +486,60 @@ func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common
 			ledger.resetState(parentBlock)
 			return common.Hash{}, res
 		}
+		if !ledger.shouldSkipCheckTx(tx) {
+			numRegularTxs++
+		}
 	}
 
-	ledger.handleDelayedStateUpdates(view)
+	ledger.updateBaseFee(block, view, numRegularTxs, budget)
+	announced := ledger.handleDelayedStateUpdates(view)
 
 	ledger.state.Commit() // commit to persistent storage
+	ledger.chain.AddParamChangeAnnouncements(block.Hash(), announced)
 
 	return view.Hash(), result.OKWith(result.Info{"hasValidatorUpdate": hasValidatorUpdate})
 }
 
+// TraceBlockTxs re-executes the transactions of the block on top of the state of its parent, up to
+// the transaction at position last, and traces the EVM execution of the smart contract transactions
+// with the tracers returned by getTracer, which returns nil to skip a transaction. The execution
+// happens on a separate ledger state, the state of the ledger is left untouched.
+func (ledger *Ledger) TraceBlockTxs(block *core.Block, last int, getTracer func(idx int, tx *types.SmartContractTx) vm.Tracer) error {
+	extParentBlock, err := ledger.chain.FindBlock(block.Parent)
+	if extParentBlock == nil || err != nil {
+		return fmt.Errorf("Failed to find the parent block: %v, err: %v", block.Parent.Hex(), err)
+	}
+
+	state := st.NewLedgerState(ledger.state.GetChainID(), ledger.db)
+	res := state.ResetState(extParentBlock.Block)
+	if res.IsError() {
+		return fmt.Errorf("The state of block %v does not exist, it might have been pruned", block.Parent.Hex())
+	}
+	executor := exec.NewExecutor(ledger.db, ledger.chain, state, ledger.consensus, ledger.valMgr)
+	executor.SetSkipSanityCheck(true) // the block has been validated already
+	ledger.recordParentBlockHash(block, state.Delivered())
+	ledger.recordRandomness(block, state.Delivered())
+
+	for idx, rawTx := range block.Txs {
+		if idx > last {
+			break
+		}
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			return fmt.Errorf("Failed to parse transaction: %v", hex.EncodeToString(rawTx))
+		}
+		var tracer vm.Tracer
+		if sctx, ok := tx.(*types.SmartContractTx); ok {
+			tracer = getTracer(idx, sctx)
+		}
+		executor.SetTracer(tracer)
+		if _, res := executor.ExecuteTx(tx); res.IsError() {
+			return fmt.Errorf("Failed to re-execute transaction %v of block %v: %v", idx, block.Hash().Hex(), res.Message)
+		}
+	}
+	return nil
+}
+
 // PruneState attempts to prune the state up to the targetEndHeight
 func (ledger *Ledger) PruneState(targetEndHeight uint64) error {
 	var processedHeight uint64
@@ -433,6 +571,19 @@ func (ledger *Ledger) PruneState(targetEndHeight uint64) error {
 		return fmt.Errorf(errMsg)
 	}
 
+	// An archive node retains the states since the height it was first pruned in the archive mode. The
+	// states pruned before, or while not in the archive mode, are lost.
+	if viper.GetBool(common.CfgStorageArchive) {
+		var archiveStartHeight uint64
+		if kvStore.Get(state.ArchiveStartHeightKey(), &archiveStartHeight) != nil {
+			archiveStartHeight = ledger.EarliestStateHeight()
+			kvStore.Put(state.ArchiveStartHeightKey(), archiveStartHeight)
+			logger.Infof("Archive node retains the states since height %v", archiveStartHeight)
+		}
+	} else {
+		kvStore.Delete(state.ArchiveStartHeightKey())
+	}
+
 	// Need to save the progress before pruning -- in case the program exits during pruning (e.g. Ctrl+C),
 	// the states that are already pruned do not get pruned again
 	kvStore.Put(state.StatePruningProgressKey(), endHeight)
@@ -446,6 +597,26 @@ func (ledger *Ledger) PruneState(targetEndHeight uint64) error {
 	return nil
 }
 
+// EarliestStateHeight returns the height since which the states of the finalized blocks are retained
+func (ledger *Ledger) EarliestStateHeight() uint64 {
+	kvStore := kvstore.NewKVStore(ledger.State().DB())
+	var height uint64
+	if viper.GetBool(common.CfgStorageArchive) {
+		if err := kvStore.Get(state.ArchiveStartHeightKey(), &height); err == nil {
+			return height
+		}
+	}
+	if err := kvStore.Get(state.StatePruningProgressKey(), &height); err == nil {
+		return height + 1
+	}
+	return ledger.chain.Root().Height
+}
+
+// freezer is implemented by the databases of the archive nodes, see backend.ArchiveDatabase
+type freezer interface {
+	Freezer() database.Database
+}
+
 // pruneStateForRange prunes states from startHeight to endHeight (inclusive for both end)
 func (ledger *Ledger) pruneStateForRange(startHeight, endHeight uint64) error {
 	logger.Infof("Prune state from height %v to %v", startHeight, endHeight)
@@ -455,6 +626,16 @@ func (ledger *Ledger) pruneStateForRange(startHeight, endHeight uint64) error {
 	chain := ledger.chain
 	lastFinalizedBlock := consensus.GetLastFinalizedBlock()
 
+	// An archive node freezes the pruned state trie nodes into its ancient database instead of deleting them
+	pruneDB := db
+	if viper.GetBool(common.CfgStorageArchive) {
+		f, ok := db.(freezer)
+		if !ok {
+			return fmt.Errorf("The database does not support the archive mode")
+		}
+		pruneDB = f.Freezer()
+	}
+
 	sv := state.NewStoreView(lastFinalizedBlock.Height, lastFinalizedBlock.BlockHeader.StateHash, db)
 
 	stateHashMap := make(map[string]bool)
@@ -510,7 +691,7 @@ func (ledger *Ledger) pruneStateForRange(startHeight, endHeight uint64) error {
 					continue
 				}
 
-				sv := state.NewStoreView(height, block.StateHash, db)
+				sv := state.NewStoreView(height, block.StateHash, pruneDB)
 				err = sv.Prune()
 				if err != nil {
 					return fmt.Errorf("Failed to prune storeview at height %v, %v", height, err)
@@ -575,11 +756,225 @@ func (ledger *Ledger) shouldSkipCheckTx(tx types.Tx) bool {
 	}
 }
 
+// recordParentBlockHash records the hash of the parent block in the recent block hashes of the state,
+// before the transactions of the block are executed
+func (ledger *Ledger) recordParentBlockHash(block *core.Block, view *st.StoreView) {
+	if !features.IsEnabled(features.BlockHash, block.Height) || block.Height == 0 {
+		return
+	}
+	view.SetBlockHash(block.Height-1, block.Parent)
+}
+
+// recordRandomness records the randomness beacon of the block in the state when it advances, before
+// the transactions of the block are executed, so the smart contracts of the block can read it
+func (ledger *Ledger) recordRandomness(block *core.Block, view *st.StoreView) {
+	if !features.IsEnabled(features.RandomnessBeacon, block.Height) {
+		return
+	}
+	if randomness, _ := view.GetRandomness(); randomness != block.Randomness {
+		view.SetRandomness(block.Height, block.Randomness)
+	}
+}
+
+// updateBaseFee adjusts the base fee of the next block to the utilization of the block budget, as the
+// EIP-1559 base fee follows the gas used. Without the block budget, i.e. before the BlockBudget fork,
+// it follows the number of regular transactions in the block instead. The base fee is only recorded
+// in the state once it departs from the minimum fee, which keeps the state of the blocks below the
+// target unchanged.
+func (ledger *Ledger) updateBaseFee(block *core.Block, view *st.StoreView, numRegularTxs int, budget *types.BlockBudget) {
+	if !features.IsEnabled(features.DynamicBaseFee, block.Height) {
+		return
+	}
+	used, target := uint64(numRegularTxs), types.BaseFeeTargetNumTxsPerBlock
+	if budget != nil {
+		used, target = budget.Utilization()
+	}
+	baseFee := view.GetBaseFee()
+	nextBaseFee := types.NextBaseFee(baseFee, used, target)
+	if minFee := view.MinimumTxFee(); nextBaseFee.Cmp(minFee) < 0 {
+		nextBaseFee = minFee
+	}
+	if nextBaseFee.Cmp(baseFee) != 0 {
+		view.SetBaseFee(nextBaseFee)
+	}
+}
+
 // handleDelayedStateUpdates handles delayed state updates, e.g. stake return, where the stake
-// is returned only after X blocks of its corresponding StakeWithdraw transaction
-func (ledger *Ledger) handleDelayedStateUpdates(view *st.StoreView) {
+// is returned only after X blocks of its corresponding StakeWithdraw transaction. It returns the
+// governance parameter changes announced by the block.
+func (ledger *Ledger) handleDelayedStateUpdates(view *st.StoreView) (announced []*types.ParamChange) {
+	ledger.handleStakeUnbonding(view)
 	ledger.handleValidatorStakeReturn(view)
 	ledger.handleGuardianStakeReturn(view)
+	announced = ledger.handleGovernanceTally(view)
+	ledger.handleParamChangeActivation(view)
+	ledger.handleSlashAppealExpiry(view)
+	ledger.handleSubchainUpdates(view)
+	return announced
+}
+
+// handleGovernanceTally tallies the governance proposals whose voting ended, and schedules the
+// parameter changes of the passed proposals. The deposits are returned to the proposers when the
+// quorum is reached, and burned otherwise. It returns the scheduled parameter changes.
+func (ledger *Ledger) handleGovernanceTally(view *st.StoreView) (announced []*types.ParamChange) {
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if !features.IsEnabled(features.Governance, blockHeight) || !common.IsCheckPointHeight(blockHeight) {
+		return nil
+	}
+	proposals := view.GetGovernanceProposals()
+	if proposals.Len() == 0 {
+		return nil
+	}
+
+	tallied := proposals.PopTallied(blockHeight)
+	if len(tallied) == 0 {
+		return nil
+	}
+
+	vcp := view.GetValidatorCandidatePool()
+	for _, proposal := range tallied {
+		quorum, passed := proposal.Tally(vcp)
+		if quorum {
+			proposerAccount := view.GetAccount(proposal.Proposer)
+			if proposerAccount == nil {
+				proposerAccount = types.NewAccount(proposal.Proposer)
+				proposerAccount.LastUpdatedBlockHeight = view.Height()
+			}
+			proposerAccount.Balance = proposerAccount.Balance.Plus(proposal.Deposit)
+			view.SetAccount(proposal.Proposer, proposerAccount)
+		}
+		if passed {
+			announced = append(announced, view.ScheduleParamChange(proposal.Name, proposal.Value))
+		}
+		logger.Infof("Governance proposal tallied, quorum: %v, passed: %v, proposal: %v", quorum, passed, proposal)
+	}
+	view.UpdateGovernanceProposals(proposals)
+	return announced
+}
+
+func (ledger *Ledger) handleParamChangeActivation(view *st.StoreView) {
+	activated := view.ActivateParamChanges()
+	for _, change := range activated {
+		logger.Infof("Activated param change: %v", change)
+	}
+}
+
+// handleSlashAppealExpiry forfeits the bonds of the slash appeals which were not approved in
+// time to the insurance pool
+func (ledger *Ledger) handleSlashAppealExpiry(view *st.StoreView) {
+	appeals := view.GetSlashAppeals()
+	if appeals.Len() == 0 {
+		return
+	}
+
+	expired := appeals.PopExpired(view.Height())
+	if len(expired) == 0 {
+		return
+	}
+
+	poolAccount := view.GetAccount(types.SlashInsurancePoolAddress)
+	if poolAccount == nil {
+		poolAccount = types.NewAccount(types.SlashInsurancePoolAddress)
+		poolAccount.LastUpdatedBlockHeight = view.Height()
+	}
+	for _, appeal := range expired {
+		poolAccount.Balance = poolAccount.Balance.Plus(appeal.Bond)
+		view.DeleteSlashRecord(appeal.Appellant, appeal.ReserveSequence)
+		logger.Infof("Slash appeal expired, bond forfeited: %v", appeal)
+	}
+	view.SetAccount(types.SlashInsurancePoolAddress, poolAccount)
+	view.UpdateSlashAppeals(appeals)
+}
+
+// handleSubchainUpdates finalizes the subchain checkpoints whose fraud proof window is over, and
+// returns the withdrawn subchain stakes which are no longer slashable
+func (ledger *Ledger) handleSubchainUpdates(view *st.StoreView) {
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	if !features.IsEnabled(features.Subchain, blockHeight) {
+		return
+	}
+	queue := view.GetSubchainUpdateQueue()
+	if queue.Len() == 0 {
+		return
+	}
+
+	due := queue.PopDue(blockHeight)
+	if len(due) == 0 {
+		return
+	}
+
+	for _, id := range due {
+		subchain := view.GetSubchain(id)
+		if subchain == nil {
+			continue
+		}
+		for _, checkpoint := range subchain.PopFinalized(blockHeight) {
+			logger.Infof("Finalized subchain checkpoint: %v", checkpoint)
+		}
+		for _, stake := range subchain.PopReturned(blockHeight) {
+			stakerAccount := view.GetAccount(stake.Staker)
+			if stakerAccount == nil {
+				stakerAccount = types.NewAccount(stake.Staker)
+				stakerAccount.LastUpdatedBlockHeight = view.Height()
+			}
+			stakerAccount.Balance = stakerAccount.Balance.Plus(types.Coins{PandoWei: stake.Amount, PTXWei: types.Zero})
+			view.SetAccount(stake.Staker, stakerAccount)
+			logger.Infof("Returned subchain stake of %v to %v, subchain: %v", stake.Amount, stake.Staker.Hex(), subchain.ChainID)
+		}
+		view.SetSubchain(subchain)
+	}
+	view.UpdateSubchainUpdateQueue(queue)
+}
+
+// handleStakeUnbonding returns the stakes of the unbonding queue whose unbonding period is over.
+// The stakes withdrawn before the unbonding queue are still returned by the scans of the candidate
+// pools below.
+func (ledger *Ledger) handleStakeUnbonding(view *st.StoreView) {
+	queue := view.GetStakeUnbondingQueue()
+	if queue.Len() == 0 {
+		return
+	}
+
+	currentHeight := view.Height()
+	released := queue.PopReleased(currentHeight)
+	if len(released) == 0 {
+		return
+	}
+
+	vcp := view.GetValidatorCandidatePool()
+	gcp := view.GetGuardianCandidatePool()
+	for _, entry := range released {
+		var returnedStake *core.Stake
+		var err error
+		if entry.Purpose == core.StakeForValidator && vcp != nil {
+			returnedStake, err = vcp.ReturnStake(entry.Source, entry.Holder, currentHeight)
+		} else if entry.Purpose == core.StakeForGuardian && gcp != nil {
+			returnedStake, err = gcp.ReturnStake(entry.Source, entry.Holder, currentHeight)
+		} else {
+			err = fmt.Errorf("no candidate pool for purpose %v", entry.Purpose)
+		}
+		if err != nil {
+			log.Panicf("Cannot return unbonded stake %v: %v", entry, err)
+		}
+		sourceAccount := view.GetAccount(entry.Source)
+		if sourceAccount == nil {
+			log.Panicf("Failed to retrieve source account for stake return: %v", entry.Source)
+		}
+		returnedCoins := types.Coins{
+			PandoWei: returnedStake.Amount,
+			PTXWei:   types.Zero,
+		}
+		sourceAccount.Balance = sourceAccount.Balance.Plus(returnedCoins)
+		view.SetAccount(entry.Source, sourceAccount)
+		logger.Infof("Returned unbonded stake: %v", entry)
+	}
+	if vcp != nil {
+		view.UpdateValidatorCandidatePool(vcp)
+	}
+	if gcp != nil {
+		view.UpdateGuardianCandidatePool(gcp)
+	}
+	view.UpdateStakeUnbondingQueue(queue)
 }
 
 func (ledger *Ledger) handleValidatorStakeReturn(view *st.StoreView) {
@@ -656,6 +1051,9 @@ func (ledger *Ledger) addSpecialTransactions(block *core.Block, view *st.StoreVi
 
 	ledger.addCoinbaseTx(view, &proposer, validatorSet, rawTxs)
 	//ledger.addSlashTxs(view, &proposer, &validators, rawTxs)
+	if features.IsEnabled(features.DoubleSignSlash, block.Height) {
+		ledger.addDoubleSignSlashTxs(view, &proposer, rawTxs)
+	}
 }
 
 // addCoinbaseTx adds a Coinbase transaction
@@ -670,7 +1068,7 @@ func (ledger *Ledger) addCoinbaseTx(view *st.StoreView, proposer *core.Validator
 	ch := ledger.GetCurrentBlock().Height
 	guardianVotes := ledger.GetCurrentBlock().GuardianVotes
 
-	if guardianVotes != nil && ch >= common.HeightEnablePando2 && common.IsCheckPointHeight(ch) {
+	if guardianVotes != nil && features.IsEnabled(features.Pando2, ch) && common.IsCheckPointHeight(ch) {
 		guradianVoteBlock, err := ledger.chain.FindBlock(guardianVotes.Block)
 		if err != nil {
 			logger.Panic(err)
@@ -692,6 +1090,10 @@ func (ledger *Ledger) addCoinbaseTx(view *st.StoreView, proposer *core.Validator
 			Coins:   accountReward,
 		})
 	}
+	// Order the outputs by address, so that the same rewards always produce the same coinbase transaction
+	sort.Slice(coinbaseTxOutputs, func(i, j int) bool {
+		return bytes.Compare(coinbaseTxOutputs[i].Address[:], coinbaseTxOutputs[j].Address[:]) < 0
+	})
 
 	coinbaseTx := &types.CoinbaseTx{
 		Proposer:    proposerTxIn,
@@ -749,11 +1151,64 @@ func (ledger *Ledger) addSlashTxs(view *st.StoreView, proposer *core.Validator,
 	view.ClearSlashIntents()
 }
 
+// isStakeUpdateTx returns whether the transaction may change the stakes of the validators
+func isStakeUpdateTx(tx types.Tx) bool {
+	switch tx := tx.(type) {
+	case *types.DepositStakeTx, *types.WithdrawStakeTx, *types.DelegateTx, *types.UndelegateTx:
+		return true
+	case *types.SlashTx:
+		return tx.DoubleSign != nil
+	}
+	return false
+}
+
+// addDoubleSignSlashTxs adds the Slash transactions of the double signing evidence collected by the
+// consensus engine. The evidence already punished is skipped, the rest is checked again with the
+// other transactions of the proposal.
+func (ledger *Ledger) addDoubleSignSlashTxs(view *st.StoreView, proposer *core.Validator, rawTxs *[]common.Bytes) {
+	source, ok := ledger.consensus.(core.EvidenceSource)
+	if !ok {
+		return
+	}
+
+	proposerAddress := proposer.Address
+	proposerTxIn := types.TxInput{
+		Address: proposerAddress,
+	}
+
+	for _, evidence := range source.PendingEvidence() {
+		if view.GetDoubleSignSlashRecord(evidence.Offender(), evidence.Height()) != nil {
+			continue
+		}
+
+		slashTx := &types.SlashTx{
+			Proposer:       proposerTxIn,
+			SlashedAddress: evidence.Offender(),
+			DoubleSign:     evidence,
+		}
+
+		signature, err := ledger.signTransaction(slashTx)
+		if err != nil {
+			logger.Errorf("Failed to add double sign slash transaction: %v", err)
+			continue
+		}
+		slashTx.SetSignature(proposerAddress, signature)
+		slashTxBytes, err := types.TxToBytes(slashTx)
+		if err != nil {
+			logger.Errorf("Failed to add double sign slash transaction: %v", err)
+			continue
+		}
+
+		*rawTxs = append(*rawTxs, slashTxBytes)
+		logger.Debugf("Adding double sign slash transction: tx: %v, bytes: %v", slashTx, hex.EncodeToString(slashTxBytes))
+	}
+}
+
 // signTransaction signs the given transaction
 func (ledger *Ledger) signTransaction(tx types.Tx) (*crypto.Signature, error) {
 	chainID := ledger.state.GetChainID()
 	signBytes := tx.SignBytes(chainID)
-	signature, err := ledger.consensus.PrivateKey().Sign(signBytes)
+	signature, err := ledger.consensus.Signer().Sign(signBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/result"
 	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	st "github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 	"github.com/pandotoken/pando/store/database/backend"
@@ -98,7 +99,7 @@ func TestLedgerProposerBlockTxs(t *testing.T) {
 		// 	assert.Equal(0, idx) // The first tx needs to be a coinbase transaction
 		// 	coinbaseTx := tx.(*types.CoinbaseTx)
 		// 	signBytes := coinbaseTx.SignBytes(chainID)
-		// 	ledger.consensus.PrivateKey().PublicKey().VerifySignature(signBytes, coinbaseTx.Proposer.Signature)
+		// 	ledger.consensus.Signer().PublicKey().VerifySignature(signBytes, coinbaseTx.Proposer.Signature)
 		case *types.SendTx:
 			assert.True(idx >= 0)
 			currSendTx := tx.(*types.SendTx)
@@ -124,7 +125,7 @@ func TestLedgerProposerBlockTxs(t *testing.T) {
 		// 	assert.Equal(0, idx) // The first tx needs to be a coinbase transaction
 		// 	coinbaseTx := tx.(*types.CoinbaseTx)
 		// 	signBytes := coinbaseTx.SignBytes(chainID)
-		// 	ledger.consensus.PrivateKey().PublicKey().VerifySignature(signBytes, coinbaseTx.Proposer.Signature)
+		// 	ledger.consensus.Signer().PublicKey().VerifySignature(signBytes, coinbaseTx.Proposer.Signature)
 		case *types.RametronStakeTx:
 			assert.True(idx >= 0)
 			currRametronStakeTx := tx.(*types.RametronStakeTx)
@@ -198,6 +199,114 @@ func TestLedgerApplyBlockTxs(t *testing.T) {
 	}
 }
 
+func TestLedgerUpdateBaseFee(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(map[string]uint64{features.DynamicBaseFee: 0}))
+	defer features.Configure(nil)
+
+	_, ledger, _ := newTestLedger()
+	view := ledger.state.Delivered()
+	block := &core.Block{BlockHeader: &core.BlockHeader{Height: view.Height() + 1}}
+	minimum := types.MinimumBaseFee()
+
+	budget := func(gasUsed, sizeUsed uint64) *types.BlockBudget {
+		budget := view.NewBlockBudget()
+		budget.GasUsed, budget.SizeUsed = gasUsed, sizeUsed
+		return budget
+	}
+	gasLimit, sizeLimit := view.BlockGasLimit(), view.BlockTxsSizeLimit()
+
+	// The base fee stays at the minimum, and out of the state, while the blocks are below the target
+	ledger.updateBaseFee(block, view, 0, budget(gasLimit/2-1, sizeLimit/2-1))
+	assert.Equal(minimum, view.GetBaseFee())
+	assert.Equal(0, len(view.Get(st.BaseFeeKey())))
+
+	// Full blocks raise the base fee by 1/8, whether they are out of gas or out of space
+	raised := new(big.Int).Div(new(big.Int).Mul(minimum, big.NewInt(9)), big.NewInt(8))
+	ledger.updateBaseFee(block, view, 0, budget(0, sizeLimit))
+	assert.Equal(raised, view.GetBaseFee())
+	ledger.updateBaseFee(block, view, 0, budget(gasLimit, 0))
+	assert.Equal(new(big.Int).Div(new(big.Int).Mul(raised, big.NewInt(9)), big.NewInt(8)), view.GetBaseFee())
+
+	// and empty blocks lower it back to the minimum, whatever their number of transactions
+	for i := 0; i < 5; i++ {
+		ledger.updateBaseFee(block, view, core.MaxNumRegularTxsPerBlock, budget(0, 0))
+	}
+	assert.Equal(minimum, view.GetBaseFee())
+
+	// Without the block budget, the base fee follows the number of regular transactions
+	ledger.updateBaseFee(block, view, core.MaxNumRegularTxsPerBlock, nil)
+	assert.Equal(raised, view.GetBaseFee())
+}
+
+func TestLedgerMinimumTxFee(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(map[string]uint64{features.DynamicBaseFee: 0}))
+	defer features.Configure(nil)
+
+	_, ledger, _ := newTestLedger()
+	view := ledger.state.Delivered()
+	block := &core.Block{BlockHeader: &core.BlockHeader{Height: view.Height() + 1}}
+
+	// The governance parameter raises the floor of the base fee
+	floor := new(big.Int).Mul(types.MinimumBaseFee(), big.NewInt(2))
+	view.SetParam(types.ParamMinimumTxFee, floor)
+	assert.Equal(floor, view.GetBaseFee())
+
+	ledger.updateBaseFee(block, view, 0, nil)
+	assert.Equal(floor, view.GetBaseFee())
+	ledger.updateBaseFee(block, view, core.MaxNumRegularTxsPerBlock, nil)
+	assert.Equal(new(big.Int).Div(new(big.Int).Mul(floor, big.NewInt(9)), big.NewInt(8)), view.GetBaseFee())
+}
+
+func TestLedgerGovernanceTally(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(features.Configure(map[string]uint64{features.Governance: 0}))
+	defer features.Configure(nil)
+
+	_, ledger, _ := newTestLedger()
+	view := ledger.state.Delivered()
+	for !common.IsCheckPointHeight(view.Height() + 1) {
+		view.IncrementHeight()
+	}
+	tallyHeight := view.Height() + 1
+
+	staker := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	proposer := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	vcp := &core.ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(staker, staker, core.MinValidatorStakeDeposit))
+	view.UpdateValidatorCandidatePool(vcp)
+
+	deposit := types.Coins{PandoWei: big.NewInt(0), PTXWei: types.MinimumGovernanceDeposit()}
+	passing := &types.GovernanceProposal{
+		ID: common.BytesToHash([]byte{1}), Proposer: proposer, Name: types.ParamBlockGasLimit,
+		Value: big.NewInt(200e6), Deposit: deposit, TallyHeight: tallyHeight,
+	}
+	passing.Vote(staker, true)
+	noQuorum := &types.GovernanceProposal{
+		ID: common.BytesToHash([]byte{2}), Proposer: proposer, Name: types.ParamMaxCommissionRateChange,
+		Value: big.NewInt(500), Deposit: deposit, TallyHeight: tallyHeight,
+	}
+	pending := &types.GovernanceProposal{
+		ID: common.BytesToHash([]byte{3}), Proposer: proposer, Name: types.ParamMaxCommissionRateChange,
+		Value: big.NewInt(500), Deposit: deposit, TallyHeight: tallyHeight + uint64(common.CheckpointInterval),
+	}
+	view.UpdateGovernanceProposals(&types.GovernanceProposalSet{Proposals: []*types.GovernanceProposal{passing, noQuorum, pending}})
+
+	announced := ledger.handleGovernanceTally(view)
+
+	// The passed proposal is scheduled and announced, and only its deposit is returned
+	proposals := view.GetGovernanceProposals()
+	assert.Equal(1, proposals.Len())
+	assert.NotNil(proposals.Get(pending.ID))
+	changes := view.GetParamChangeSchedule().Changes
+	assert.Equal(1, len(changes))
+	assert.Equal(types.ParamBlockGasLimit, changes[0].Name)
+	assert.Equal(big.NewInt(200e6), changes[0].Value)
+	assert.Equal(changes, announced)
+	assert.Equal(deposit, view.GetAccount(proposer).Balance)
+}
+
 // Test case for validator stake deposit, withdrawal, and return
 func TestValidatorStakeUpdate(t *testing.T) {
 	assert := assert.New(t)
@@ -0,0 +1,228 @@
+package sigverify
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/metrics"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "sigverify"})
+
+// verifiedTxCacheSize is the number of the hashes of the pre-verified transactions kept in memory
+const verifiedTxCacheSize = 32768
+
+var (
+	preverifiedTxCounter = metrics.NewRegisteredCounter("sigverify/preverified", nil)
+	skippedTxCounter     = metrics.NewRegisteredCounter("sigverify/skipped", nil)
+)
+
+// Verifier recovers the signers of the transactions on a pool of workers, ahead of their
+// execution. The recovered signers land in the signature cache of the crypto package, so the
+// signature checks of the execution, which run serially under the ledger lock, only compare the
+// addresses. A transaction is pre-verified once, the hashes of the pre-verified transactions
+// are remembered, so the validation of a block skips the transactions already pre-verified
+// when they entered the mempool.
+//
+// The pre-verification does not reject any transaction. Whether the recovered signer is
+// accepted for the account depends on the state, e.g. on the key rotations, and is left to the
+// execution.
+type Verifier struct {
+	chainID  string
+	jobs     chan *batch
+	verified *lru.Cache // map: tx hash |-> struct{}
+
+	stopOnce sync.Once
+}
+
+// batch is a slice of the transactions pre-verified by a single worker
+type batch struct {
+	rawTxs []common.Bytes
+	wg     *sync.WaitGroup
+}
+
+// NewVerifier creates a Verifier with the given number of workers, nil if workers is 0. The
+// methods of a nil Verifier do nothing, the signatures are then only verified by the execution.
+func NewVerifier(chainID string, workers int) *Verifier {
+	if workers <= 0 {
+		return nil
+	}
+	verified, err := lru.New(verifiedTxCacheSize)
+	if err != nil {
+		logger.Warnf("Failed to create the verified tx cache: %v", err)
+		return nil
+	}
+	v := &Verifier{
+		chainID:  chainID,
+		jobs:     make(chan *batch, workers),
+		verified: verified,
+	}
+	for i := 0; i < workers; i++ {
+		go v.work()
+	}
+	return v
+}
+
+// Stop stops the workers. The Verifier must not be used afterwards.
+func (v *Verifier) Stop() {
+	if v == nil {
+		return
+	}
+	v.stopOnce.Do(func() {
+		close(v.jobs)
+	})
+}
+
+// Verify pre-verifies the transaction on the calling goroutine.
+func (v *Verifier) Verify(rawTx common.Bytes) {
+	if v == nil {
+		return
+	}
+	v.preverify(rawTx)
+}
+
+// VerifyTxs pre-verifies the transactions, e.g. the ones of a block, split into a batch per
+// worker, and returns when all of them have been pre-verified.
+func (v *Verifier) VerifyTxs(rawTxs []common.Bytes) {
+	if v == nil || len(rawTxs) == 0 {
+		return
+	}
+	workers := cap(v.jobs)
+	batchSize := (len(rawTxs) + workers - 1) / workers
+
+	wg := &sync.WaitGroup{}
+	for start := 0; start < len(rawTxs); start += batchSize {
+		end := start + batchSize
+		if end > len(rawTxs) {
+			end = len(rawTxs)
+		}
+		wg.Add(1)
+		v.jobs <- &batch{rawTxs: rawTxs[start:end], wg: wg}
+	}
+	wg.Wait()
+}
+
+// IsVerified returns whether the transaction has been pre-verified
+func (v *Verifier) IsVerified(rawTx common.Bytes) bool {
+	if v == nil {
+		return false
+	}
+	return v.verified.Contains(crypto.Keccak256Hash(rawTx))
+}
+
+func (v *Verifier) work() {
+	for b := range v.jobs {
+		for _, rawTx := range b.rawTxs {
+			v.preverify(rawTx)
+		}
+		b.wg.Done()
+	}
+}
+
+// preverify recovers the signers of the transaction, over the sign bytes first, and over the
+// alternative sign bytes of the transaction if the recovered signer is not the address of the
+// input, since the execution accepts the signatures over any of them.
+func (v *Verifier) preverify(rawTx common.Bytes) {
+	txHash := crypto.Keccak256Hash(rawTx)
+	if v.verified.Contains(txHash) {
+		skippedTxCounter.Inc(1)
+		return
+	}
+
+	tx, err := types.TxFromBytes(rawTx)
+	if err != nil {
+		return
+	}
+	inputs := signedInputs(tx)
+	if len(inputs) == 0 {
+		return
+	}
+
+	var msgs []common.Bytes
+	for _, in := range inputs {
+		if in.Signature == nil || in.Signature.IsEmpty() {
+			continue
+		}
+		if msgs == nil {
+			msgs = []common.Bytes{tx.SignBytes(v.chainID)}
+		}
+		if recoversTo(in.Signature, msgs[0], in.Address) {
+			continue
+		}
+		if len(msgs) == 1 {
+			msgs = append(msgs, altSignBytes(v.chainID, tx)...)
+		}
+		for _, msg := range msgs[1:] {
+			if recoversTo(in.Signature, msg, in.Address) {
+				break
+			}
+		}
+	}
+
+	v.verified.Add(txHash, struct{}{})
+	preverifiedTxCounter.Inc(1)
+}
+
+func recoversTo(sig *crypto.Signature, msg common.Bytes, addr common.Address) bool {
+	signer, err := sig.RecoverSignerAddress(msg)
+	return err == nil && signer == addr
+}
+
+// altSignBytes returns the alternative sign bytes of the transaction, regardless of whether the
+// signing schemes are enabled at the current height. Recovering over a scheme not enabled yet
+// only costs the recovery, the execution still rejects the signature.
+func altSignBytes(chainID string, tx types.Tx) []common.Bytes {
+	msgs := []common.Bytes{}
+	if typedSignBytes, err := types.TypedSignBytes(chainID, tx); err == nil {
+		msgs = append(msgs, typedSignBytes)
+	}
+	if canonicalSignBytes, err := types.CanonicalSignBytes(chainID, tx); err == nil {
+		msgs = append(msgs, canonicalSignBytes)
+	}
+	if sctx, ok := tx.(*types.SmartContractTx); ok {
+		if ethSignBytes := types.EthSignBytes(chainID, sctx); ethSignBytes != nil {
+			msgs = append(msgs, ethSignBytes)
+		}
+	}
+	return msgs
+}
+
+// signedInputs returns the inputs of the transaction which sign the sign bytes of the whole
+// transaction. The transactions with other signatures, e.g. the ServicePaymentTx whose source
+// and target sign different bytes, are left to the execution.
+func signedInputs(tx types.Tx) []types.TxInput {
+	switch tx := tx.(type) {
+	case *types.SendTx:
+		return tx.Inputs
+	case *types.BatchSendTx:
+		return tx.Inputs
+	case *types.RametronStakeTx:
+		return tx.Inputs
+	case *types.SmartContractTx:
+		return []types.TxInput{tx.From}
+	case *types.CoinbaseTx:
+		return []types.TxInput{tx.Proposer}
+	case *types.ReserveFundTx:
+		return []types.TxInput{tx.Source}
+	case *types.ReleaseFundTx:
+		return []types.TxInput{tx.Source}
+	case *types.SplitRuleTx:
+		return []types.TxInput{tx.Initiator}
+	case *types.DepositStakeTx:
+		return []types.TxInput{tx.Source}
+	case *types.DepositStakeTxV2:
+		return []types.TxInput{tx.Source}
+	case *types.WithdrawStakeTx:
+		return []types.TxInput{tx.Source}
+	case *types.DelegateTx:
+		return []types.TxInput{tx.Delegator}
+	case *types.UndelegateTx:
+		return []types.TxInput{tx.Delegator}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package sigverify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+func TestVerifyTxs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := "test_chain_id"
+	v := NewVerifier(chainID, 3)
+	require.NotNil(v)
+	defer v.Stop()
+
+	rawTxs := []common.Bytes{}
+	for i := 0; i < 10; i++ {
+		accIn1 := types.MakeAcc("in1")
+		accIn2 := types.MakeAcc("in2")
+		accOut := types.MakeAcc("out")
+		tx := types.MakeSendTx(i+1, accOut, accIn1, accIn2)
+		types.SignSendTx(chainID, tx, accIn1, accIn2)
+		raw, err := types.TxToBytes(tx)
+		require.Nil(err)
+		rawTxs = append(rawTxs, raw)
+	}
+	malformed := common.Bytes("not a transaction")
+
+	assert.False(v.IsVerified(rawTxs[0]))
+	v.VerifyTxs(append(rawTxs, malformed))
+	for _, raw := range rawTxs {
+		assert.True(v.IsVerified(raw))
+	}
+	assert.False(v.IsVerified(malformed))
+
+	// The transactions pre-verified by the mempool are skipped by the block validation
+	tx := types.MakeSendTx(11, types.MakeAcc("out"), types.MakeAcc("in1"))
+	types.SignSendTx(chainID, tx, types.MakeAcc("in1"))
+	raw, err := types.TxToBytes(tx)
+	require.Nil(err)
+	v.Verify(raw)
+	assert.True(v.IsVerified(raw))
+	v.VerifyTxs([]common.Bytes{raw})
+	assert.True(v.IsVerified(raw))
+}
+
+func TestNilVerifier(t *testing.T) {
+	assert := assert.New(t)
+
+	v := NewVerifier("test_chain_id", 0)
+	assert.Nil(v)
+
+	raw := common.Bytes("raw tx")
+	v.Verify(raw)
+	v.VerifyTxs([]common.Bytes{raw})
+	assert.False(v.IsVerified(raw))
+	v.Stop()
+}
@@ -0,0 +1,182 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/store/database"
+	"github.com/pandotoken/pando/store/treestore"
+)
+
+// StateChangeType is the category of a state mutation
+type StateChangeType string
+
+const (
+	StateChangeAccount     StateChangeType = "account"      // account balance, sequence, code hash, etc
+	StateChangeStorage     StateChangeType = "storage"      // smart contract storage word
+	StateChangeStake       StateChangeType = "stake"        // validator/guardian candidate pools, rametron nodes and stake tx heights
+	StateChangeCode        StateChangeType = "code"         // smart contract code
+	StateChangeSplitRule   StateChangeType = "split_rule"   // split rule of a resource
+	StateChangeSessionKeys StateChangeType = "session_keys" // session keys of an account
+	StateChangeParam       StateChangeType = "param"        // governance parameters and their scheduled changes
+	StateChangeSlash       StateChangeType = "slash"        // slash records and pending slash appeals
+	StateChangeOther       StateChangeType = "other"
+)
+
+// StateChange is a single mutation of the ledger state. OldValue is empty if the key is
+// created, and NewValue is empty if the key is deleted. Values are the raw encoded state
+// values, except for the storage changes whose values are the 32-byte storage words.
+type StateChange struct {
+	Type       StateChangeType `json:"type"`
+	Key        hexutil.Bytes   `json:"key"`
+	Address    *common.Address `json:"address,omitempty"`     // for the account, storage and session key changes
+	StorageKey *common.Hash    `json:"storage_key,omitempty"` // for the storage changes
+	OldValue   hexutil.Bytes   `json:"old_value"`
+	NewValue   hexutil.Bytes   `json:"new_value"`
+}
+
+// DiffStates returns the mutations turning the state with fromRoot into the state with
+// toRoot, ordered by key. The storage changes of an account directly follow the change of
+// the account itself. It returns an error if either state has been pruned.
+func DiffStates(db database.Database, fromRoot, toRoot common.Hash) ([]StateChange, error) {
+	from := treestore.NewTreeStore(fromRoot, db)
+	to := treestore.NewTreeStore(toRoot, db)
+	if from == nil || to == nil {
+		return nil, fmt.Errorf("the state from %v to %v does not exist, it might have been pruned", fromRoot.Hex(), toRoot.Hex())
+	}
+
+	changes := []StateChange{}
+	var storageErr error
+	err := from.Diff(to, func(k, oldValue, newValue common.Bytes) bool {
+		change := StateChange{
+			Type:     stateChangeType(k),
+			Key:      hexutil.Bytes(k),
+			OldValue: hexutil.Bytes(oldValue),
+			NewValue: hexutil.Bytes(newValue),
+		}
+		switch change.Type {
+		case StateChangeAccount, StateChangeSessionKeys:
+			addr := common.BytesToAddress(k[len(k)-common.AddressLength:])
+			change.Address = &addr
+		}
+		changes = append(changes, change)
+
+		if change.Type == StateChangeAccount {
+			var storageChanges []StateChange
+			storageChanges, storageErr = diffAccountStorage(db, *change.Address, oldValue, newValue)
+			if storageErr != nil {
+				return false
+			}
+			changes = append(changes, storageChanges...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if storageErr != nil {
+		return nil, storageErr
+	}
+	return changes, nil
+}
+
+// diffAccountStorage returns the storage mutations of the account between the two encoded
+// account values
+func diffAccountStorage(db database.Database, addr common.Address, oldValue, newValue common.Bytes) ([]StateChange, error) {
+	oldRoot, err := accountStorageRoot(oldValue)
+	if err != nil {
+		return nil, err
+	}
+	newRoot, err := accountStorageRoot(newValue)
+	if err != nil {
+		return nil, err
+	}
+	if oldRoot == newRoot {
+		return nil, nil
+	}
+
+	from := treestore.NewTreeStore(oldRoot, db)
+	to := treestore.NewTreeStore(newRoot, db)
+	if from == nil || to == nil {
+		return nil, fmt.Errorf("the storage of %v does not exist, it might have been pruned", addr.Hex())
+	}
+
+	changes := []StateChange{}
+	var decodeErr error
+	err = from.Diff(to, func(k, oldWord, newWord common.Bytes) bool {
+		change := StateChange{
+			Type: StateChangeStorage,
+			Key:  hexutil.Bytes(k),
+		}
+		address := addr
+		storageKey := common.BytesToHash(k)
+		change.Address, change.StorageKey = &address, &storageKey
+		if change.OldValue, decodeErr = decodeStorageWord(oldWord); decodeErr != nil {
+			return false
+		}
+		if change.NewValue, decodeErr = decodeStorageWord(newWord); decodeErr != nil {
+			return false
+		}
+		changes = append(changes, change)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return changes, nil
+}
+
+func accountStorageRoot(value common.Bytes) (common.Hash, error) {
+	if len(value) == 0 {
+		return common.Hash{}, nil
+	}
+	acc := &types.Account{}
+	if err := types.FromBytes(value, acc); err != nil {
+		return common.Hash{}, err
+	}
+	return acc.Root, nil
+}
+
+func decodeStorageWord(enc common.Bytes) (hexutil.Bytes, error) {
+	if len(enc) == 0 {
+		return nil, nil
+	}
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return nil, err
+	}
+	word := common.BytesToHash(content)
+	return hexutil.Bytes(word[:]), nil
+}
+
+func stateChangeType(k common.Bytes) StateChangeType {
+	switch {
+	case bytes.HasPrefix(k, AccountKeyPrefix()):
+		return StateChangeAccount
+	case bytes.HasPrefix(k, SessionKeysKeyPrefix()):
+		return StateChangeSessionKeys
+	case bytes.Equal(k, ValidatorCandidatePoolKey()), bytes.Equal(k, GuardianCandidatePoolKey()),
+		bytes.Equal(k, StakeTransactionHeightListKey()), bytes.Equal(k, StakeUnbondingQueueKey()),
+		bytes.HasPrefix(k, DelegationsKeyPrefix()), bytes.HasPrefix(k, ValidatorParamsKeyPrefix()),
+		bytes.HasPrefix(k, RametronNodeKeyPrefix()):
+		return StateChangeStake
+	case bytes.HasPrefix(k, CodeKey(nil)):
+		return StateChangeCode
+	case bytes.HasPrefix(k, SplitRuleKeyPrefix()):
+		return StateChangeSplitRule
+	case bytes.HasPrefix(k, ParamKey("")), bytes.Equal(k, ParamChangeScheduleKey()),
+		bytes.Equal(k, GovernanceProposalsKey()):
+		return StateChangeParam
+	case bytes.HasPrefix(k, SlashRecordKeyPrefix()), bytes.Equal(k, SlashAppealsKey()),
+		bytes.HasPrefix(k, DoubleSignSlashKeyPrefix()):
+		return StateChangeSlash
+	}
+	return StateChangeOther
+}
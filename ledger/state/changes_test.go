@@ -0,0 +1,79 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffStates(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(1, common.Hash{}, db)
+
+	addr1 := common.HexToAddress("0x1")
+	addr2 := common.HexToAddress("0x2")
+	contract := common.HexToAddress("0x3")
+	slot1 := common.BytesToHash([]byte{1})
+	slot2 := common.BytesToHash([]byte{2})
+
+	acc1 := types.NewAccount(addr1)
+	acc1.Balance = types.NewCoins(100, 100)
+	sv.SetAccount(addr1, acc1)
+	sv.SetAccount(addr2, types.NewAccount(addr2))
+	sv.SetAccount(contract, types.NewAccount(contract))
+	sv.SetState(contract, slot1, common.BytesToHash([]byte{0xaa}))
+	sv.Set(common.Bytes("ls/other"), common.Bytes("value"))
+	root1 := sv.Save()
+
+	acc1 = sv.GetAccount(addr1)
+	acc1.Balance = types.NewCoins(50, 100)
+	sv.SetAccount(addr1, acc1)
+	sv.Delete(AccountKey(addr2))
+	sv.SetState(contract, slot1, common.Hash{})
+	sv.SetState(contract, slot2, common.BytesToHash([]byte{0xbb}))
+	sv.UpdateValidatorCandidatePool(&core.ValidatorCandidatePool{})
+	root2 := sv.Save()
+
+	changes, err := DiffStates(db, root1, root2)
+	require.Nil(err)
+	require.Equal(6, len(changes))
+
+	// Account changes, with the storage changes following the contract account
+	assert.Equal(StateChangeAccount, changes[0].Type)
+	assert.Equal(addr1, *changes[0].Address)
+	assert.NotEmpty(changes[0].OldValue)
+	assert.NotEmpty(changes[0].NewValue)
+
+	assert.Equal(StateChangeAccount, changes[1].Type)
+	assert.Equal(addr2, *changes[1].Address)
+	assert.Empty(changes[1].NewValue)
+
+	assert.Equal(StateChangeAccount, changes[2].Type)
+	assert.Equal(contract, *changes[2].Address)
+
+	assert.Equal(StateChangeStorage, changes[3].Type)
+	assert.Equal(slot1, *changes[3].StorageKey)
+	assert.Equal(common.BytesToHash([]byte{0xaa}).Bytes(), []byte(changes[3].OldValue))
+	assert.Empty(changes[3].NewValue)
+
+	assert.Equal(StateChangeStorage, changes[4].Type)
+	assert.Equal(slot2, *changes[4].StorageKey)
+	assert.Empty(changes[4].OldValue)
+	assert.Equal(common.BytesToHash([]byte{0xbb}).Bytes(), []byte(changes[4].NewValue))
+
+	assert.Equal(StateChangeStake, changes[5].Type)
+	assert.Empty(changes[5].OldValue)
+
+	// No change between the same states
+	changes, err = DiffStates(db, root2, root2)
+	require.Nil(err)
+	assert.Empty(changes)
+}
@@ -1,6 +1,10 @@
 package state
 
-import "github.com/pandotoken/pando/common"
+import (
+	"encoding/binary"
+
+	"github.com/pandotoken/pando/common"
+)
 
 //
 // ------------------------- Ledger State Keys -------------------------
@@ -11,9 +15,14 @@ func ChainIDKey() common.Bytes {
 	return common.Bytes("chainid")
 }
 
+// AccountKeyPrefix returns the prefix for the account key
+func AccountKeyPrefix() common.Bytes {
+	return common.Bytes("ls/a/")
+}
+
 // AccountKey constructs the state key for the given address
 func AccountKey(addr common.Address) common.Bytes {
-	return append(common.Bytes("ls/a/"), addr[:]...)
+	return append(AccountKeyPrefix(), addr[:]...)
 }
 
 // SplitRuleKeyPrefix returns the prefix for the split rule key
@@ -48,7 +57,183 @@ func StakeTransactionHeightListKey() common.Bytes {
 	return common.Bytes("ls/sthl")
 }
 
+// StakeUnbondingQueueKey returns the state key for the withdrawn stakes waiting to be returned
+func StakeUnbondingQueueKey() common.Bytes {
+	return common.Bytes("ls/suq")
+}
+
 // StatePruningProgressKey returns the key for the state pruning progress
 func StatePruningProgressKey() common.Bytes {
 	return common.Bytes("ls/spp")
 }
+
+// ArchiveStartHeightKey returns the key for the height since which an archive node retains the states
+func ArchiveStartHeightKey() common.Bytes {
+	return common.Bytes("ls/ash")
+}
+
+// ParamKey constructs the state key for the given governance parameter
+func ParamKey(name string) common.Bytes {
+	return append(common.Bytes("ls/param/"), common.Bytes(name)...)
+}
+
+// GovernanceProposalsKey returns the state key for the pending governance proposals
+func GovernanceProposalsKey() common.Bytes {
+	return common.Bytes("ls/gov")
+}
+
+// ParamChangeScheduleKey returns the state key for the pending governance parameter changes
+func ParamChangeScheduleKey() common.Bytes {
+	return common.Bytes("ls/pcs")
+}
+
+// SessionKeysKeyPrefix returns the prefix for the session keys key
+func SessionKeysKeyPrefix() common.Bytes {
+	return common.Bytes("ls/sk/")
+}
+
+// SessionKeysKey constructs the state key for the session keys of the given account
+func SessionKeysKey(addr common.Address) common.Bytes {
+	return append(SessionKeysKeyPrefix(), addr[:]...)
+}
+
+// SlashRecordKeyPrefix returns the prefix for the slash record key
+func SlashRecordKeyPrefix() common.Bytes {
+	return common.Bytes("ls/sr/")
+}
+
+// SlashRecordKey constructs the state key for the slash of the given reserve fund
+func SlashRecordKey(addr common.Address, reserveSequence uint64) common.Bytes {
+	seqBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(seqBytes, reserveSequence)
+	key := append(SlashRecordKeyPrefix(), addr[:]...)
+	return append(key, seqBytes...)
+}
+
+// DoubleSignSlashKeyPrefix returns the prefix for the double sign slash record key
+func DoubleSignSlashKeyPrefix() common.Bytes {
+	return common.Bytes("ls/dss/")
+}
+
+// DoubleSignSlashKey constructs the state key for the slash of the validator double signing at the given height
+func DoubleSignSlashKey(addr common.Address, height uint64) common.Bytes {
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, height)
+	key := append(DoubleSignSlashKeyPrefix(), addr[:]...)
+	return append(key, heightBytes...)
+}
+
+// ValidatorParamsKeyPrefix returns the prefix for the validator parameters key
+func ValidatorParamsKeyPrefix() common.Bytes {
+	return common.Bytes("ls/vp/")
+}
+
+// ValidatorParamsKey constructs the state key for the parameters of the given validator
+func ValidatorParamsKey(addr common.Address) common.Bytes {
+	return append(ValidatorParamsKeyPrefix(), addr[:]...)
+}
+
+// RametronNodeKeyPrefix returns the prefix for the Rametron node key
+func RametronNodeKeyPrefix() common.Bytes {
+	return common.Bytes("ls/rn/")
+}
+
+// RametronNodeKey constructs the state key for the given Rametron node
+func RametronNodeKey(addr common.Address) common.Bytes {
+	return append(RametronNodeKeyPrefix(), addr[:]...)
+}
+
+// DelegationsKeyPrefix returns the prefix for the delegations key
+func DelegationsKeyPrefix() common.Bytes {
+	return common.Bytes("ls/dlg/")
+}
+
+// DelegationsKey constructs the state key for the delegations of the given delegator
+func DelegationsKey(addr common.Address) common.Bytes {
+	return append(DelegationsKeyPrefix(), addr[:]...)
+}
+
+// HTLCKeyPrefix returns the prefix for the HTLC key
+func HTLCKeyPrefix() common.Bytes {
+	return common.Bytes("ls/htlc/")
+}
+
+// HTLCKey constructs the state key for the HTLC with the given ID
+func HTLCKey(id common.Hash) common.Bytes {
+	return append(HTLCKeyPrefix(), id[:]...)
+}
+
+// BridgeNonceKey returns the state key for the nonce of the last outbound bridge message
+func BridgeNonceKey() common.Bytes {
+	return common.Bytes("ls/brn")
+}
+
+// BridgeMessageKeyPrefix returns the prefix for the outbound bridge message key
+func BridgeMessageKeyPrefix() common.Bytes {
+	return common.Bytes("ls/brm/")
+}
+
+// BridgeMessageKey constructs the state key for the outbound bridge message with the given nonce
+func BridgeMessageKey(nonce uint64) common.Bytes {
+	nonceBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(nonceBytes, nonce)
+	return append(BridgeMessageKeyPrefix(), nonceBytes...)
+}
+
+// BridgeClaimedEventKey constructs the state key marking the foreign bridge event with the given ID as claimed
+func BridgeClaimedEventKey(id common.Hash) common.Bytes {
+	return append(common.Bytes("ls/brc/"), id[:]...)
+}
+
+// BridgeCustodyKey returns the state key for the coins locked in the bridge
+func BridgeCustodyKey() common.Bytes {
+	return common.Bytes("ls/brl")
+}
+
+// WrappedBalanceKey constructs the state key for the balance of the wrapped asset held by the address
+func WrappedBalanceKey(asset common.Hash, addr common.Address) common.Bytes {
+	key := append(common.Bytes("ls/brw/"), asset[:]...)
+	return append(key, addr[:]...)
+}
+
+// WrappedSupplyKey constructs the state key for the supply of the wrapped asset
+func WrappedSupplyKey(asset common.Hash) common.Bytes {
+	return append(common.Bytes("ls/brs/"), asset[:]...)
+}
+
+// SubchainKeyPrefix returns the prefix for the subchain key
+func SubchainKeyPrefix() common.Bytes {
+	return common.Bytes("ls/sc/")
+}
+
+// SubchainKey constructs the state key for the subchain with the given ID
+func SubchainKey(id common.Hash) common.Bytes {
+	return append(SubchainKeyPrefix(), id[:]...)
+}
+
+// SubchainUpdateQueueKey returns the state key for the scheduled subchain updates
+func SubchainUpdateQueueKey() common.Bytes {
+	return common.Bytes("ls/scu")
+}
+
+// SlashAppealsKey returns the state key for the pending slash appeals
+func SlashAppealsKey() common.Bytes {
+	return common.Bytes("ls/sap")
+}
+
+// BlockHashKey constructs the state key for the given slot of the recent block hashes
+func BlockHashKey(slot uint64) common.Bytes {
+	slotBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(slotBytes, slot)
+	return append(common.Bytes("ls/bh/"), slotBytes...)
+}
+
+// RandomnessKey returns the state key for the latest randomness beacon
+func RandomnessKey() common.Bytes {
+	return common.Bytes("ls/rnd")
+}
+
+// BaseFeeKey returns the state key for the base fee of the next block
+func BaseFeeKey() common.Bytes {
+	return common.Bytes("ls/bf")
+}
@@ -0,0 +1,347 @@
+package state
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/store/database"
+)
+
+//
+// ------------------------- Access Tracking -------------------------
+//
+
+// KeySet is a set of the keys of the state trie
+type KeySet map[string]struct{}
+
+// Add adds the key to the set
+func (ks KeySet) Add(key common.Bytes) {
+	ks[string(key)] = struct{}{}
+}
+
+// Has returns whether the key is in the set
+func (ks KeySet) Has(key common.Bytes) bool {
+	_, ok := ks[string(key)]
+	return ok
+}
+
+// Merge adds the keys of the other set to the set
+func (ks KeySet) Merge(other KeySet) {
+	for key := range other {
+		ks[key] = struct{}{}
+	}
+}
+
+// Intersects returns whether the two sets have any key in common
+func (ks KeySet) Intersects(other KeySet) bool {
+	if len(ks) > len(other) {
+		ks, other = other, ks
+	}
+	for key := range ks {
+		if _, ok := other[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessSet records the keys of the state trie read and written through a StoreView. The storage
+// of a smart contract is covered by the key of its account, which holds the storage root.
+type AccessSet struct {
+	Reads  KeySet
+	Writes KeySet
+}
+
+// NewAccessSet creates an empty AccessSet
+func NewAccessSet() *AccessSet {
+	return &AccessSet{
+		Reads:  KeySet{},
+		Writes: KeySet{},
+	}
+}
+
+// ConflictsWith returns whether any of the read keys has been written by the given keys
+func (as *AccessSet) ConflictsWith(written KeySet) bool {
+	return as.Reads.Intersects(written)
+}
+
+// TrackAccess starts recording the keys accessed through the StoreView into the AccessSet, nil stops
+// the recording.
+func (sv *StoreView) TrackAccess(access *AccessSet) {
+	sv.access = access
+}
+
+// Access returns the AccessSet recording the keys accessed through the StoreView, nil if the accesses
+// are not tracked.
+func (sv *StoreView) Access() *AccessSet {
+	return sv.access
+}
+
+//
+// ------------------------- Speculative Execution -------------------------
+//
+
+// SpeculativeCopy returns a copy of the StoreView for executing a transaction speculatively, e.g. in
+// parallel with the other transactions of the block. The copy tracks the keys accessed by the
+// transaction, and buffers its writes to the database, i.e. the smart contract storage tries, so
+// that a discarded execution leaves no trace. See Merge.
+func (sv *StoreView) SpeculativeCopy() (*StoreView, error) {
+	copied, err := sv.Copy()
+	if err != nil {
+		return nil, err
+	}
+	copied.db = newJournalDB(sv.GetDB())
+	copied.access = NewAccessSet()
+	return copied, nil
+}
+
+// RunSideEffect runs a side effect of the transaction outside of the state, e.g. recording its receipt in
+// the chain. On a speculative copy, the effect is deferred until the copy is merged, so that a discarded
+// execution leaves no trace, and the effects of the merged transactions happen in the block order.
+func (sv *StoreView) RunSideEffect(effect func()) {
+	if _, ok := sv.db.(*journalDB); ok {
+		sv.effects = append(sv.effects, effect)
+		return
+	}
+	effect()
+}
+
+// Merge applies the changes of the speculative copy onto the StoreView, as if the transaction had been
+// executed on the StoreView itself, and runs its deferred side effects. It is only equivalent if none of
+// the keys read by the transaction has been changed on the StoreView since the copy was made.
+func (sv *StoreView) Merge(spec *StoreView) error {
+	journal, ok := spec.db.(*journalDB)
+	if !ok || spec.access == nil {
+		return errNotSpeculative
+	}
+	if err := journal.replay(sv.GetDB()); err != nil {
+		return err
+	}
+	for key := range spec.access.Writes {
+		value := spec.store.Get(common.Bytes(key))
+		if len(value) == 0 {
+			sv.Delete(common.Bytes(key))
+		} else {
+			sv.Set(common.Bytes(key), value)
+		}
+	}
+	for _, effect := range spec.effects {
+		sv.RunSideEffect(effect)
+	}
+	spec.effects = nil
+	return nil
+}
+
+var errNotSpeculative = errors.New("The StoreView is not a speculative copy")
+
+type journalOpType byte
+
+const (
+	journalPut journalOpType = iota
+	journalDelete
+	journalReference
+	journalDereference
+)
+
+type journalOp struct {
+	opType journalOpType
+	key    []byte
+	value  []byte
+}
+
+// journalDB buffers the writes to the underlying database, and serves the reads from the buffered
+// writes first. The writes are replayed onto a database in their original order, and the writes of a
+// batch in a batch, so that the replay has the same effect, including on the reference counts, as the
+// original writes.
+type journalDB struct {
+	db      database.Database
+	values  map[string][]byte // nil for the deleted keys
+	batches []*journalBatch
+
+	mu sync.RWMutex
+}
+
+var _ database.Database = (*journalDB)(nil)
+
+func newJournalDB(db database.Database) *journalDB {
+	return &journalDB{
+		db:     db,
+		values: make(map[string][]byte),
+	}
+}
+
+func (jdb *journalDB) Get(key []byte) ([]byte, error) {
+	jdb.mu.RLock()
+	value, ok := jdb.values[string(key)]
+	jdb.mu.RUnlock()
+	if ok {
+		if value == nil {
+			return nil, store.ErrKeyNotFound
+		}
+		return common.CopyBytes(value), nil
+	}
+	return jdb.db.Get(key)
+}
+
+func (jdb *journalDB) Has(key []byte) (bool, error) {
+	jdb.mu.RLock()
+	value, ok := jdb.values[string(key)]
+	jdb.mu.RUnlock()
+	if ok {
+		return value != nil, nil
+	}
+	return jdb.db.Has(key)
+}
+
+// CountReference returns the reference count of the key in the underlying database. The buffered
+// references and dereferences are not counted until they are replayed by Merge, which is fine as the
+// execution of the transactions never reads the reference counts.
+func (jdb *journalDB) CountReference(key []byte) (int, error) {
+	return jdb.db.CountReference(key)
+}
+
+func (jdb *journalDB) Put(key []byte, value []byte) error {
+	return jdb.writeDirect(journalOp{journalPut, common.CopyBytes(key), common.CopyBytes(value)})
+}
+
+func (jdb *journalDB) Delete(key []byte) error {
+	return jdb.writeDirect(journalOp{opType: journalDelete, key: common.CopyBytes(key)})
+}
+
+func (jdb *journalDB) Reference(key []byte) error {
+	if has, err := jdb.Has(key); err != nil || !has {
+		return store.ErrKeyNotFound
+	}
+	return jdb.writeDirect(journalOp{opType: journalReference, key: common.CopyBytes(key)})
+}
+
+func (jdb *journalDB) Dereference(key []byte) error {
+	if has, err := jdb.Has(key); err != nil || !has {
+		return store.ErrKeyNotFound
+	}
+	return jdb.writeDirect(journalOp{opType: journalDereference, key: common.CopyBytes(key)})
+}
+
+func (jdb *journalDB) Close() {
+	// Do nothing; don't close the underlying DB.
+}
+
+func (jdb *journalDB) NewBatch() database.Batch {
+	return &journalBatch{jdb: jdb}
+}
+
+func (jdb *journalDB) writeDirect(op journalOp) error {
+	return (&journalBatch{jdb: jdb, ops: []journalOp{op}, direct: true}).Write()
+}
+
+func (jdb *journalDB) apply(batch *journalBatch) {
+	jdb.mu.Lock()
+	defer jdb.mu.Unlock()
+
+	for _, op := range batch.ops {
+		switch op.opType {
+		case journalPut:
+			jdb.values[string(op.key)] = op.value
+		case journalDelete:
+			jdb.values[string(op.key)] = nil
+		}
+	}
+	jdb.batches = append(jdb.batches, batch)
+}
+
+// replay replays the buffered writes onto the database
+func (jdb *journalDB) replay(db database.Database) error {
+	jdb.mu.RLock()
+	defer jdb.mu.RUnlock()
+
+	for _, batch := range jdb.batches {
+		if batch.direct {
+			if err := batch.ops[0].apply(db); err != nil && err != store.ErrKeyNotFound {
+				return err
+			}
+			continue
+		}
+		dbBatch := db.NewBatch()
+		for _, op := range batch.ops {
+			if err := op.apply(dbBatch); err != nil {
+				return err
+			}
+		}
+		if err := dbBatch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type journalWriter interface {
+	database.Putter
+	database.Deleter
+	database.Referencer
+	database.Dereferencer
+}
+
+func (op journalOp) apply(w journalWriter) error {
+	switch op.opType {
+	case journalPut:
+		return w.Put(op.key, op.value)
+	case journalDelete:
+		return w.Delete(op.key)
+	case journalReference:
+		return w.Reference(op.key)
+	default:
+		return w.Dereference(op.key)
+	}
+}
+
+// journalBatch records the writes of a batch, which are added to the journal on Write
+type journalBatch struct {
+	jdb    *journalDB
+	ops    []journalOp
+	size   int
+	direct bool // a single write to the database, outside of a batch
+}
+
+func (b *journalBatch) Put(key []byte, value []byte) error {
+	b.ops = append(b.ops, journalOp{journalPut, common.CopyBytes(key), common.CopyBytes(value)})
+	b.size += len(value)
+	return nil
+}
+
+func (b *journalBatch) Delete(key []byte) error {
+	b.ops = append(b.ops, journalOp{opType: journalDelete, key: common.CopyBytes(key)})
+	b.size++
+	return nil
+}
+
+func (b *journalBatch) Reference(key []byte) error {
+	b.ops = append(b.ops, journalOp{opType: journalReference, key: common.CopyBytes(key)})
+	b.size++
+	return nil
+}
+
+func (b *journalBatch) Dereference(key []byte) error {
+	b.ops = append(b.ops, journalOp{opType: journalDereference, key: common.CopyBytes(key)})
+	b.size++
+	return nil
+}
+
+func (b *journalBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *journalBatch) Write() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	written := &journalBatch{jdb: b.jdb, ops: b.ops, size: b.size, direct: b.direct}
+	b.jdb.apply(written)
+	return nil
+}
+
+func (b *journalBatch) Reset() {
+	b.ops = nil
+	b.size = 0
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"math/big"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/pandotoken/pando/rlp"
 	"github.com/pandotoken/pando/store/database"
 	"github.com/pandotoken/pando/store/treestore"
+	"github.com/pandotoken/pando/store/trie"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -22,13 +24,18 @@ var logger *log.Entry = log.WithFields(log.Fields{"prefix": "ledger"})
 //
 
 type StoreView struct {
-	height uint64 // block height
-	store  *treestore.TreeStore
+	height   uint64 // block height
+	store    *treestore.TreeStore
+	readOnly bool // the changes are never persisted, see NewReadOnlyStoreView
 
 	coinbaseTransactinProcessed bool
 	slashIntents                []types.SlashIntent
 	refund                      uint64       // Gas refund during smart contract execution
 	logs                        []*types.Log // Temporary store of events during smart contract execution
+
+	db      database.Database // overrides the database of the tree store, see SpeculativeCopy
+	access  *AccessSet        // records the accessed keys if not nil, see TrackAccess
+	effects []func()          // side effects deferred until the speculative copy is merged, see RunSideEffect
 }
 
 // NewStoreView creates an instance of the StoreView
@@ -47,6 +54,23 @@ func NewStoreView(height uint64, root common.Hash, db database.Database) *StoreV
 	return sv
 }
 
+// NewReadOnlyStoreView creates a StoreView of a historical state, e.g. to execute calls against the
+// state of a past block. The view can be modified, but Save does not persist the changes, so the
+// tries of the past blocks are never altered. It returns nil if the state has been pruned.
+func NewReadOnlyStoreView(height uint64, root common.Hash, db database.Database) *StoreView {
+	sv := NewStoreView(height, root, db)
+	if sv == nil {
+		return nil
+	}
+	sv.readOnly = true
+	return sv
+}
+
+// IsReadOnly returns true if the changes to the StoreView are never persisted
+func (sv *StoreView) IsReadOnly() bool {
+	return sv.readOnly
+}
+
 // Copy returns a copy of the StoreView
 func (sv *StoreView) Copy() (*StoreView, error) {
 	copiedStore, err := sv.store.Copy()
@@ -56,6 +80,7 @@ func (sv *StoreView) Copy() (*StoreView, error) {
 	copiedStoreView := &StoreView{
 		height:       sv.height,
 		store:        copiedStore,
+		readOnly:     sv.readOnly,
 		slashIntents: []types.SlashIntent{},
 		refund:       0,
 	}
@@ -64,6 +89,9 @@ func (sv *StoreView) Copy() (*StoreView, error) {
 
 // GetDB returns the underlying database.
 func (sv *StoreView) GetDB() database.Database {
+	if sv.db != nil {
+		return sv.db
+	}
 	return sv.store.GetDB()
 }
 
@@ -82,8 +110,13 @@ func (sv *StoreView) IncrementHeight() {
 	sv.height++
 }
 
-// Save saves the StoreView to the persistent storage, and return the root hash
+// Save saves the StoreView to the persistent storage, and return the root hash. A read-only
+// StoreView is not saved, only its root hash is returned.
 func (sv *StoreView) Save() common.Hash {
+	if sv.readOnly {
+		return sv.store.Hash()
+	}
+
 	rootHash, err := sv.store.Commit()
 
 	logger.Debugf("Commit to data store, height: %v, rootHash: %v", sv.height+1, rootHash.Hex())
@@ -96,6 +129,9 @@ func (sv *StoreView) Save() common.Hash {
 
 // Get returns the value corresponding to the key
 func (sv *StoreView) Get(key common.Bytes) common.Bytes {
+	if sv.access != nil {
+		sv.access.Reads.Add(key)
+	}
 	value := sv.store.Get(key)
 	return value
 }
@@ -106,11 +142,17 @@ func (sv *StoreView) ProveVCP(vcpKey []byte, vp *core.VCPProof) error {
 
 // Delete removes the value corresponding to the key
 func (sv *StoreView) Delete(key common.Bytes) {
+	if sv.access != nil {
+		sv.access.Writes.Add(key)
+	}
 	sv.store.Delete(key)
 }
 
 // Set returns the value corresponding to the key
 func (sv *StoreView) Set(key common.Bytes, value common.Bytes) {
+	if sv.access != nil {
+		sv.access.Writes.Add(key)
+	}
 	sv.store.Set(key, value)
 }
 
@@ -241,6 +283,38 @@ func (sv *StoreView) GetSplitRule(resourceID string) *types.SplitRule {
 	return splitRule
 }
 
+// GetSplitRulesByInitiator returns the split rules initiated by the address.
+func (sv *StoreView) GetSplitRulesByInitiator(initiator common.Address) []*types.SplitRule {
+	splitRules := []*types.SplitRule{}
+	sv.store.Traverse(SplitRuleKeyPrefix(), func(key, value common.Bytes) bool {
+		splitRule := &types.SplitRule{}
+		err := types.FromBytes(value, splitRule)
+		if err != nil {
+			log.Panicf("Error reading splitRule %X error: %v", value, err.Error())
+		}
+		if splitRule.InitiatorAddress == initiator {
+			splitRules = append(splitRules, splitRule)
+		}
+		return true
+	})
+	return splitRules
+}
+
+// GetSplitRules returns all the split rules, including the expired ones which have not been deleted yet.
+func (sv *StoreView) GetSplitRules() []*types.SplitRule {
+	splitRules := []*types.SplitRule{}
+	sv.store.Traverse(SplitRuleKeyPrefix(), func(key, value common.Bytes) bool {
+		splitRule := &types.SplitRule{}
+		err := types.FromBytes(value, splitRule)
+		if err != nil {
+			log.Panicf("Error reading splitRule %X error: %v", value, err.Error())
+		}
+		splitRules = append(splitRules, splitRule)
+		return true
+	})
+	return splitRules
+}
+
 // SetSplitRule sets split rule.
 func (sv *StoreView) SetSplitRule(resourceID string, splitRule *types.SplitRule) {
 	splitRuleBytes, err := types.ToBytes(splitRule)
@@ -270,8 +344,7 @@ func (sv *StoreView) DeleteExpiredSplitRules(currentBlockHeight uint64) bool {
 			log.Panicf("Error reading splitRule %X error: %v", value, err.Error())
 		}
 
-		expired := (splitRule.EndBlockHeight < currentBlockHeight)
-		if expired {
+		if splitRule.IsExpired(currentBlockHeight) {
 			expiredKeys = append(expiredKeys, key)
 		}
 		return true
@@ -364,6 +437,720 @@ func (sv *StoreView) UpdateStakeTransactionHeightList(hl *types.HeightList) {
 	sv.Set(StakeTransactionHeightListKey(), hlBytes)
 }
 
+// GetSessionKeys gets the session keys of the given account
+func (sv *StoreView) GetSessionKeys(addr common.Address) *types.SessionKeySet {
+	data := sv.Get(SessionKeysKey(addr))
+	if data == nil || len(data) == 0 {
+		return &types.SessionKeySet{}
+	}
+
+	sessionKeys := &types.SessionKeySet{}
+	err := types.FromBytes(data, sessionKeys)
+	if err != nil {
+		log.Panicf("Error reading session keys %X, error: %v",
+			data, err.Error())
+	}
+	return sessionKeys
+}
+
+// UpdateSessionKeys updates the session keys of the given account
+func (sv *StoreView) UpdateSessionKeys(addr common.Address, sessionKeys *types.SessionKeySet) {
+	if sessionKeys.Len() == 0 {
+		sv.Delete(SessionKeysKey(addr))
+		return
+	}
+	sessionKeysBytes, err := types.ToBytes(sessionKeys)
+	if err != nil {
+		log.Panicf("Error writing session keys %v, error: %v",
+			sessionKeys, err.Error())
+	}
+	sv.Set(SessionKeysKey(addr), sessionKeysBytes)
+}
+
+// GetDelegations gets the delegations of the given delegator
+func (sv *StoreView) GetDelegations(addr common.Address) *types.DelegationSet {
+	data := sv.Get(DelegationsKey(addr))
+	if data == nil || len(data) == 0 {
+		return &types.DelegationSet{}
+	}
+
+	delegations := &types.DelegationSet{}
+	err := types.FromBytes(data, delegations)
+	if err != nil {
+		log.Panicf("Error reading delegations %X, error: %v",
+			data, err.Error())
+	}
+	return delegations
+}
+
+// UpdateDelegations updates the delegations of the given delegator
+func (sv *StoreView) UpdateDelegations(addr common.Address, delegations *types.DelegationSet) {
+	if delegations.Len() == 0 {
+		sv.Delete(DelegationsKey(addr))
+		return
+	}
+	delegationsBytes, err := types.ToBytes(delegations)
+	if err != nil {
+		log.Panicf("Error writing delegations %v, error: %v",
+			delegations, err.Error())
+	}
+	sv.Set(DelegationsKey(addr), delegationsBytes)
+}
+
+// GetValidatorParams gets the parameters of the given validator, nil if the validator has not
+// registered any
+func (sv *StoreView) GetValidatorParams(addr common.Address) *types.ValidatorParams {
+	data := sv.Get(ValidatorParamsKey(addr))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+
+	params := &types.ValidatorParams{}
+	err := types.FromBytes(data, params)
+	if err != nil {
+		log.Panicf("Error reading validator params %X, error: %v",
+			data, err.Error())
+	}
+	return params
+}
+
+// SetValidatorParams sets the parameters of the validator
+func (sv *StoreView) SetValidatorParams(params *types.ValidatorParams) {
+	paramsBytes, err := types.ToBytes(params)
+	if err != nil {
+		log.Panicf("Error writing validator params %v, error: %v",
+			params, err.Error())
+	}
+	sv.Set(ValidatorParamsKey(params.Validator), paramsBytes)
+}
+
+// GetRametronNode gets the Rametron node registered at the given address, nil if the node has not
+// registered for a tier
+func (sv *StoreView) GetRametronNode(addr common.Address) *types.RametronNode {
+	data := sv.Get(RametronNodeKey(addr))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+
+	node := &types.RametronNode{}
+	err := types.FromBytes(data, node)
+	if err != nil {
+		log.Panicf("Error reading rametron node %X, error: %v",
+			data, err.Error())
+	}
+	return node
+}
+
+// GetRametronNodes gets all the registered Rametron nodes, ordered by address
+func (sv *StoreView) GetRametronNodes() []*types.RametronNode {
+	nodes := []*types.RametronNode{}
+	sv.store.Traverse(RametronNodeKeyPrefix(), func(key, value common.Bytes) bool {
+		node := &types.RametronNode{}
+		err := types.FromBytes(value, node)
+		if err != nil {
+			log.Panicf("Error reading rametron node %X, error: %v", value, err.Error())
+		}
+		nodes = append(nodes, node)
+		return true
+	})
+	return nodes
+}
+
+// SetRametronNode sets the Rametron node
+func (sv *StoreView) SetRametronNode(node *types.RametronNode) {
+	nodeBytes, err := types.ToBytes(node)
+	if err != nil {
+		log.Panicf("Error writing rametron node %v, error: %v",
+			node, err.Error())
+	}
+	sv.Set(RametronNodeKey(node.Node), nodeBytes)
+}
+
+// GetHTLC gets the HTLC with the given ID, nil if the HTLC does not exist, or has been claimed or
+// refunded
+func (sv *StoreView) GetHTLC(id common.Hash) *types.HTLC {
+	data := sv.Get(HTLCKey(id))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+
+	htlc := &types.HTLC{}
+	err := types.FromBytes(data, htlc)
+	if err != nil {
+		log.Panicf("Error reading HTLC %X, error: %v",
+			data, err.Error())
+	}
+	return htlc
+}
+
+// SetHTLC sets the HTLC
+func (sv *StoreView) SetHTLC(htlc *types.HTLC) {
+	htlcBytes, err := types.ToBytes(htlc)
+	if err != nil {
+		log.Panicf("Error writing HTLC %v, error: %v",
+			htlc, err.Error())
+	}
+	sv.Set(HTLCKey(htlc.ID), htlcBytes)
+}
+
+// DeleteHTLC deletes the HTLC once it has been claimed or refunded
+func (sv *StoreView) DeleteHTLC(id common.Hash) {
+	sv.Delete(HTLCKey(id))
+}
+
+// GetBridgeNonce gets the nonce of the last outbound bridge message, 0 if there is none
+func (sv *StoreView) GetBridgeNonce() uint64 {
+	data := sv.Get(BridgeNonceKey())
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// AddBridgeMessage assigns the next nonce to the outbound bridge message, and records it
+func (sv *StoreView) AddBridgeMessage(msg *types.BridgeMessage) {
+	msg.Nonce = sv.GetBridgeNonce() + 1
+	msgBytes, err := types.ToBytes(msg)
+	if err != nil {
+		log.Panicf("Error writing bridge message %v, error: %v",
+			msg, err.Error())
+	}
+	sv.Set(BridgeMessageKey(msg.Nonce), msgBytes)
+
+	nonceBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(nonceBytes, msg.Nonce)
+	sv.Set(BridgeNonceKey(), nonceBytes)
+}
+
+// GetBridgeMessage gets the outbound bridge message with the given nonce, nil if it does not exist
+func (sv *StoreView) GetBridgeMessage(nonce uint64) *types.BridgeMessage {
+	data := sv.Get(BridgeMessageKey(nonce))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+
+	msg := &types.BridgeMessage{}
+	err := types.FromBytes(data, msg)
+	if err != nil {
+		log.Panicf("Error reading bridge message %X, error: %v",
+			data, err.Error())
+	}
+	return msg
+}
+
+// ProveBridgeMessage returns the Merkle proof of the outbound bridge message in the state trie, i.e. the
+// trie nodes from the state root to the message, or to the node proving its absence.
+func (sv *StoreView) ProveBridgeMessage(nonce uint64) ([][]byte, error) {
+	pc := &proofCollector{}
+	if err := sv.store.Prove(BridgeMessageKey(nonce), 0, pc); err != nil {
+		return nil, err
+	}
+	return pc.nodes, nil
+}
+
+// IsForeignEventClaimed returns whether the foreign bridge event with the given ID has been claimed
+func (sv *StoreView) IsForeignEventClaimed(id common.Hash) bool {
+	return len(sv.Get(BridgeClaimedEventKey(id))) > 0
+}
+
+// MarkForeignEventClaimed marks the foreign bridge event with the given ID as claimed
+func (sv *StoreView) MarkForeignEventClaimed(id common.Hash) {
+	sv.Set(BridgeClaimedEventKey(id), common.Bytes{1})
+}
+
+// GetBridgeCustody gets the coins locked in the bridge, which back the wrapped Pando coins of the
+// foreign chains
+func (sv *StoreView) GetBridgeCustody() types.Coins {
+	data := sv.Get(BridgeCustodyKey())
+	if data == nil || len(data) == 0 {
+		return types.NewCoins(0, 0)
+	}
+
+	coins := types.Coins{}
+	err := types.FromBytes(data, &coins)
+	if err != nil {
+		log.Panicf("Error reading bridge custody %X, error: %v",
+			data, err.Error())
+	}
+	return coins.NoNil()
+}
+
+// SetBridgeCustody sets the coins locked in the bridge
+func (sv *StoreView) SetBridgeCustody(coins types.Coins) {
+	coinsBytes, err := types.ToBytes(coins)
+	if err != nil {
+		log.Panicf("Error writing bridge custody %v, error: %v",
+			coins, err.Error())
+	}
+	sv.Set(BridgeCustodyKey(), coinsBytes)
+}
+
+// GetWrappedBalance gets the balance of the wrapped asset held by the address
+func (sv *StoreView) GetWrappedBalance(asset common.Hash, addr common.Address) *big.Int {
+	return new(big.Int).SetBytes(sv.Get(WrappedBalanceKey(asset, addr)))
+}
+
+// SetWrappedBalance sets the balance of the wrapped asset held by the address
+func (sv *StoreView) SetWrappedBalance(asset common.Hash, addr common.Address, balance *big.Int) {
+	if balance.Sign() == 0 {
+		sv.Delete(WrappedBalanceKey(asset, addr))
+		return
+	}
+	sv.Set(WrappedBalanceKey(asset, addr), balance.Bytes())
+}
+
+// GetWrappedSupply gets the supply of the wrapped asset, i.e. the amount minted and not burned yet
+func (sv *StoreView) GetWrappedSupply(asset common.Hash) *big.Int {
+	return new(big.Int).SetBytes(sv.Get(WrappedSupplyKey(asset)))
+}
+
+// SetWrappedSupply sets the supply of the wrapped asset
+func (sv *StoreView) SetWrappedSupply(asset common.Hash, supply *big.Int) {
+	if supply.Sign() == 0 {
+		sv.Delete(WrappedSupplyKey(asset))
+		return
+	}
+	sv.Set(WrappedSupplyKey(asset), supply.Bytes())
+}
+
+// GetSubchain gets the subchain with the given ID, nil if it is not registered
+func (sv *StoreView) GetSubchain(id common.Hash) *types.Subchain {
+	data := sv.Get(SubchainKey(id))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+
+	subchain := &types.Subchain{}
+	err := types.FromBytes(data, subchain)
+	if err != nil {
+		log.Panicf("Error reading subchain %X, error: %v",
+			data, err.Error())
+	}
+	return subchain
+}
+
+// GetSubchains gets all the registered subchains, ordered by ID
+func (sv *StoreView) GetSubchains() []*types.Subchain {
+	subchains := []*types.Subchain{}
+	sv.store.Traverse(SubchainKeyPrefix(), func(key, value common.Bytes) bool {
+		subchain := &types.Subchain{}
+		err := types.FromBytes(value, subchain)
+		if err != nil {
+			log.Panicf("Error reading subchain %X, error: %v", value, err.Error())
+		}
+		subchains = append(subchains, subchain)
+		return true
+	})
+	return subchains
+}
+
+// SetSubchain sets the subchain
+func (sv *StoreView) SetSubchain(subchain *types.Subchain) {
+	subchainBytes, err := types.ToBytes(subchain)
+	if err != nil {
+		log.Panicf("Error writing subchain %v, error: %v",
+			subchain, err.Error())
+	}
+	sv.Set(SubchainKey(subchain.ID), subchainBytes)
+}
+
+// GetSubchainUpdateQueue gets the scheduled subchain updates
+func (sv *StoreView) GetSubchainUpdateQueue() *types.SubchainUpdateQueue {
+	data := sv.Get(SubchainUpdateQueueKey())
+	if data == nil || len(data) == 0 {
+		return &types.SubchainUpdateQueue{}
+	}
+
+	queue := &types.SubchainUpdateQueue{}
+	err := types.FromBytes(data, queue)
+	if err != nil {
+		log.Panicf("Error reading subchain update queue %X, error: %v",
+			data, err.Error())
+	}
+	return queue
+}
+
+// UpdateSubchainUpdateQueue updates the scheduled subchain updates
+func (sv *StoreView) UpdateSubchainUpdateQueue(queue *types.SubchainUpdateQueue) {
+	if queue.Len() == 0 {
+		sv.Delete(SubchainUpdateQueueKey())
+		return
+	}
+	queueBytes, err := types.ToBytes(queue)
+	if err != nil {
+		log.Panicf("Error writing subchain update queue %v, error: %v",
+			queue, err.Error())
+	}
+	sv.Set(SubchainUpdateQueueKey(), queueBytes)
+}
+
+// ScheduleSubchainUpdate schedules the update of the subchain at the height, see SubchainUpdate
+func (sv *StoreView) ScheduleSubchainUpdate(id common.Hash, height uint64) {
+	queue := sv.GetSubchainUpdateQueue()
+	queue.Add(id, height)
+	sv.UpdateSubchainUpdateQueue(queue)
+}
+
+// BridgeAttestationThreshold returns the share of the stake of the bridge validators (in basis points)
+// which needs to attest a foreign event, as set by the governance parameter
+func (sv *StoreView) BridgeAttestationThreshold() uint64 {
+	threshold := sv.GetParam(types.ParamBridgeAttestationThreshold)
+	if threshold == nil || !threshold.IsUint64() {
+		return types.DefaultBridgeAttestationThreshold
+	}
+	return threshold.Uint64()
+}
+
+// MaxCommissionRateChange returns the largest change of the commission rate of a validator
+// allowed per reward epoch, as set by the governance parameter
+func (sv *StoreView) MaxCommissionRateChange() uint64 {
+	maxChange := sv.GetParam(types.ParamMaxCommissionRateChange)
+	if maxChange == nil || !maxChange.IsUint64() {
+		return types.DefaultMaxCommissionRateChange
+	}
+	return maxChange.Uint64()
+}
+
+// GetParam gets the value of the governance parameter, nil if the parameter has never been changed
+func (sv *StoreView) GetParam(name string) *big.Int {
+	data := sv.Get(ParamKey(name))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+	return new(big.Int).SetBytes(data)
+}
+
+// SetParam sets the value of the governance parameter
+func (sv *StoreView) SetParam(name string, value *big.Int) {
+	sv.Set(ParamKey(name), value.Bytes())
+}
+
+// GetParamChangeSchedule gets the pending governance parameter changes
+func (sv *StoreView) GetParamChangeSchedule() *types.ParamChangeSchedule {
+	data := sv.Get(ParamChangeScheduleKey())
+	if data == nil || len(data) == 0 {
+		return &types.ParamChangeSchedule{}
+	}
+
+	schedule := &types.ParamChangeSchedule{}
+	err := types.FromBytes(data, schedule)
+	if err != nil {
+		log.Panicf("Error reading param change schedule %X, error: %v",
+			data, err.Error())
+	}
+	return schedule
+}
+
+// UpdateParamChangeSchedule updates the pending governance parameter changes
+func (sv *StoreView) UpdateParamChangeSchedule(schedule *types.ParamChangeSchedule) {
+	if schedule.Len() == 0 {
+		sv.Delete(ParamChangeScheduleKey())
+		return
+	}
+	scheduleBytes, err := types.ToBytes(schedule)
+	if err != nil {
+		log.Panicf("Error writing param change schedule %v, error: %v",
+			schedule, err.Error())
+	}
+	sv.Set(ParamChangeScheduleKey(), scheduleBytes)
+}
+
+// ScheduleParamChange schedules a governance parameter change which passed at the current height.
+// The change takes effect ParamChangeActivationDelay blocks later. The announcement is recorded by
+// the chain, since the state only keeps the change until it is activated.
+func (sv *StoreView) ScheduleParamChange(name string, value *big.Int) *types.ParamChange {
+	change := &types.ParamChange{
+		Name:             name,
+		Value:            new(big.Int).Set(value),
+		AnnouncedHeight:  sv.Height(),
+		ActivationHeight: sv.Height() + types.ParamChangeActivationDelay,
+	}
+	schedule := sv.GetParamChangeSchedule()
+	schedule.Add(change)
+	sv.UpdateParamChangeSchedule(schedule)
+
+	logger.Infof("Scheduled param change: %v", change)
+	return change
+}
+
+// GetGovernanceProposals gets the pending governance proposals
+func (sv *StoreView) GetGovernanceProposals() *types.GovernanceProposalSet {
+	data := sv.Get(GovernanceProposalsKey())
+	if data == nil || len(data) == 0 {
+		return &types.GovernanceProposalSet{}
+	}
+
+	proposals := &types.GovernanceProposalSet{}
+	err := types.FromBytes(data, proposals)
+	if err != nil {
+		log.Panicf("Error reading governance proposals %X, error: %v",
+			data, err.Error())
+	}
+	return proposals
+}
+
+// UpdateGovernanceProposals updates the pending governance proposals
+func (sv *StoreView) UpdateGovernanceProposals(proposals *types.GovernanceProposalSet) {
+	if proposals.Len() == 0 {
+		sv.Delete(GovernanceProposalsKey())
+		return
+	}
+	proposalsBytes, err := types.ToBytes(proposals)
+	if err != nil {
+		log.Panicf("Error writing governance proposals %v, error: %v",
+			proposals, err.Error())
+	}
+	sv.Set(GovernanceProposalsKey(), proposalsBytes)
+}
+
+// ActivateParamChanges applies the scheduled governance parameter changes that take effect
+// at or before the current height, and returns them
+func (sv *StoreView) ActivateParamChanges() []*types.ParamChange {
+	schedule := sv.GetParamChangeSchedule()
+	if schedule.Len() == 0 {
+		return nil
+	}
+
+	activated := schedule.PopActivated(sv.Height())
+	if len(activated) == 0 {
+		return nil
+	}
+	for _, change := range activated {
+		sv.SetParam(change.Name, change.Value)
+	}
+	sv.UpdateParamChangeSchedule(schedule)
+	return activated
+}
+
+// GetSlashRecord gets the record of the slash of the given reserve fund, nil if not found
+func (sv *StoreView) GetSlashRecord(addr common.Address, reserveSequence uint64) *types.SlashRecord {
+	data := sv.Get(SlashRecordKey(addr, reserveSequence))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+	record := &types.SlashRecord{}
+	err := types.FromBytes(data, record)
+	if err != nil {
+		log.Panicf("Error reading slash record %X, error: %v",
+			data, err.Error())
+	}
+	return record
+}
+
+// SetSlashRecord records a slash
+func (sv *StoreView) SetSlashRecord(record *types.SlashRecord) {
+	recordBytes, err := types.ToBytes(record)
+	if err != nil {
+		log.Panicf("Error writing slash record %v, error: %v",
+			record, err.Error())
+	}
+	sv.Set(SlashRecordKey(record.SlashedAddress, record.ReserveSequence), recordBytes)
+}
+
+// DeleteSlashRecord deletes the record of the slash of the given reserve fund
+func (sv *StoreView) DeleteSlashRecord(addr common.Address, reserveSequence uint64) {
+	sv.Delete(SlashRecordKey(addr, reserveSequence))
+}
+
+// GetDoubleSignSlashRecord gets the slash of the validator for double signing at the given height,
+// nil if the validator has not been slashed for it
+func (sv *StoreView) GetDoubleSignSlashRecord(addr common.Address, height uint64) *types.DoubleSignSlashRecord {
+	data := sv.Get(DoubleSignSlashKey(addr, height))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+	record := &types.DoubleSignSlashRecord{}
+	err := types.FromBytes(data, record)
+	if err != nil {
+		log.Panicf("Error reading double sign slash record %X, error: %v",
+			data, err.Error())
+	}
+	return record
+}
+
+// SetDoubleSignSlashRecord records a slash for double signing
+func (sv *StoreView) SetDoubleSignSlashRecord(record *types.DoubleSignSlashRecord) {
+	recordBytes, err := types.ToBytes(record)
+	if err != nil {
+		log.Panicf("Error writing double sign slash record %v, error: %v",
+			record, err.Error())
+	}
+	sv.Set(DoubleSignSlashKey(record.Offender, record.EvidenceHeight), recordBytes)
+}
+
+// StakeUnbondingPeriod returns the number of blocks a withdrawn stake stays locked before it is
+// returned to its source, as set by the governance parameter
+func (sv *StoreView) StakeUnbondingPeriod() uint64 {
+	period := sv.GetParam(types.ParamStakeUnbondingPeriod)
+	if period == nil || !period.IsUint64() {
+		return core.ReturnLockingPeriod
+	}
+	return period.Uint64()
+}
+
+// BlockGasLimit returns the limit on the total gas of the regular transactions in a block, as set by
+// the governance parameter
+func (sv *StoreView) BlockGasLimit() uint64 {
+	limit := sv.GetParam(types.ParamBlockGasLimit)
+	if limit == nil || !limit.IsUint64() {
+		return types.DefaultBlockGasLimit
+	}
+	return limit.Uint64()
+}
+
+// BlockTxsSizeLimit returns the limit on the total size of the regular transactions in a block, as set
+// by the governance parameter
+func (sv *StoreView) BlockTxsSizeLimit() uint64 {
+	limit := sv.GetParam(types.ParamBlockTxsSizeLimit)
+	if limit == nil || !limit.IsUint64() {
+		return types.DefaultBlockTxsSizeLimit
+	}
+	return limit.Uint64()
+}
+
+// MinValidatorStakeDeposit returns the minimum stake of a validator deposit, as set by the governance
+// parameter
+func (sv *StoreView) MinValidatorStakeDeposit() *big.Int {
+	minStake := sv.GetParam(types.ParamMinValidatorStakeDeposit)
+	if minStake == nil || minStake.Cmp(core.MinValidatorStakeDeposit) < 0 {
+		return core.MinValidatorStakeDeposit
+	}
+	return minStake
+}
+
+// MinimumTxFee returns the floor of the base fee, as set by the governance parameter
+func (sv *StoreView) MinimumTxFee() *big.Int {
+	minFee := sv.GetParam(types.ParamMinimumTxFee)
+	if minFee == nil || minFee.Cmp(types.MinimumBaseFee()) < 0 {
+		return types.MinimumBaseFee()
+	}
+	return minFee
+}
+
+// NewBlockBudget creates the budget of the regular transactions of the next block
+func (sv *StoreView) NewBlockBudget() *types.BlockBudget {
+	return types.NewBlockBudget(sv.BlockGasLimit(), sv.BlockTxsSizeLimit())
+}
+
+// GetStakeUnbondingQueue gets the withdrawn stakes waiting to be returned
+func (sv *StoreView) GetStakeUnbondingQueue() *types.StakeUnbondingQueue {
+	data := sv.Get(StakeUnbondingQueueKey())
+	if data == nil || len(data) == 0 {
+		return &types.StakeUnbondingQueue{}
+	}
+
+	queue := &types.StakeUnbondingQueue{}
+	err := types.FromBytes(data, queue)
+	if err != nil {
+		log.Panicf("Error reading stake unbonding queue %X, error: %v",
+			data, err.Error())
+	}
+	return queue
+}
+
+// UpdateStakeUnbondingQueue updates the withdrawn stakes waiting to be returned
+func (sv *StoreView) UpdateStakeUnbondingQueue(queue *types.StakeUnbondingQueue) {
+	if queue.Len() == 0 {
+		sv.Delete(StakeUnbondingQueueKey())
+		return
+	}
+	queueBytes, err := types.ToBytes(queue)
+	if err != nil {
+		log.Panicf("Error writing stake unbonding queue %v, error: %v",
+			queue, err.Error())
+	}
+	sv.Set(StakeUnbondingQueueKey(), queueBytes)
+}
+
+// GetSlashAppeals gets the pending slash appeals
+func (sv *StoreView) GetSlashAppeals() *types.SlashAppealSet {
+	data := sv.Get(SlashAppealsKey())
+	if data == nil || len(data) == 0 {
+		return &types.SlashAppealSet{}
+	}
+
+	appeals := &types.SlashAppealSet{}
+	err := types.FromBytes(data, appeals)
+	if err != nil {
+		log.Panicf("Error reading slash appeals %X, error: %v",
+			data, err.Error())
+	}
+	return appeals
+}
+
+// UpdateSlashAppeals updates the pending slash appeals
+func (sv *StoreView) UpdateSlashAppeals(appeals *types.SlashAppealSet) {
+	if appeals.Len() == 0 {
+		sv.Delete(SlashAppealsKey())
+		return
+	}
+	appealsBytes, err := types.ToBytes(appeals)
+	if err != nil {
+		log.Panicf("Error writing slash appeals %v, error: %v",
+			appeals, err.Error())
+	}
+	sv.Set(SlashAppealsKey(), appealsBytes)
+}
+
+// NumRecentBlockHashes is the number of recent block hashes kept in the state, which bounds the
+// blocks accessible to the BLOCKHASH opcode
+const NumRecentBlockHashes = 256
+
+// SetBlockHash records the hash of the block at the given height. The hashes are kept in a ring
+// buffer, which overwrites the hash recorded NumRecentBlockHashes blocks earlier.
+func (sv *StoreView) SetBlockHash(height uint64, hash common.Hash) {
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, height)
+	sv.Set(BlockHashKey(height%NumRecentBlockHashes), append(heightBytes, hash[:]...))
+}
+
+// GetBlockHash gets the hash of the block at the given height, or an empty hash if the hash
+// was not recorded or has been overwritten
+func (sv *StoreView) GetBlockHash(height uint64) common.Hash {
+	data := sv.Get(BlockHashKey(height % NumRecentBlockHashes))
+	if len(data) != 8+common.HashLength || binary.BigEndian.Uint64(data[:8]) != height {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data[8:])
+}
+
+// SetRandomness records the randomness beacon which advanced at the block of the given height
+func (sv *StoreView) SetRandomness(height uint64, randomness common.Hash) {
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, height)
+	sv.Set(RandomnessKey(), append(heightBytes, randomness[:]...))
+}
+
+// GetRandomness gets the latest randomness beacon, along with the height of the block at which
+// it advanced
+func (sv *StoreView) GetRandomness() (common.Hash, uint64) {
+	data := sv.Get(RandomnessKey())
+	if len(data) != 8+common.HashLength {
+		return common.Hash{}, 0
+	}
+	return common.BytesToHash(data[8:]), binary.BigEndian.Uint64(data[:8])
+}
+
+// GetBaseFee gets the base fee of the next block, i.e. the minimum fee of a regular transaction. The
+// base fee never falls below the floor set by the governance parameter.
+func (sv *StoreView) GetBaseFee() *big.Int {
+	minFee := sv.MinimumTxFee()
+	data := sv.Get(BaseFeeKey())
+	if len(data) == 0 {
+		return minFee
+	}
+	baseFee := new(big.Int).SetBytes(data)
+	if baseFee.Cmp(minFee) < 0 {
+		return minFee
+	}
+	return baseFee
+}
+
+// SetBaseFee sets the base fee of the next block
+func (sv *StoreView) SetBaseFee(baseFee *big.Int) {
+	sv.Set(BaseFeeKey(), baseFee.Bytes())
+}
+
 func (sv *StoreView) GetStore() *treestore.TreeStore {
 	return sv.store
 }
@@ -531,7 +1318,7 @@ func (sv *StoreView) GetCommittedState(addr common.Address, key common.Hash) com
 }
 
 func (sv *StoreView) getAccountStorage(account *types.Account) *treestore.TreeStore {
-	return treestore.NewTreeStore(account.Root, sv.store.GetDB())
+	return treestore.NewTreeStore(account.Root, sv.GetDB())
 }
 
 func (sv *StoreView) GetState(addr common.Address, key common.Hash) common.Hash {
@@ -586,6 +1373,78 @@ func (sv *StoreView) SetState(addr common.Address, key, val common.Hash) {
 	logger.Debugf("StoreView.SetState, address: %v, account.root: %v, key: %v, val: %v", addr.Hex(), root.Hex(), key.Hex(), val.Hex())
 }
 
+// IterateStorage calls cb on the non-empty storage slots of the contract in ascending order, starting
+// from the slot start, until cb returns false. With prove, the Merkle proof of each slot in the storage
+// trie of the contract, i.e. the trie nodes from its root to the slot, is passed along.
+func (sv *StoreView) IterateStorage(addr common.Address, start common.Hash, prove bool,
+	cb func(slot, value common.Hash, proof [][]byte) bool) error {
+	account := sv.GetAccount(addr)
+	if account == nil {
+		return nil
+	}
+	tree := sv.getAccountStorage(account)
+	if tree == nil {
+		return fmt.Errorf("the storage of %v is not found, it might have been pruned", addr.Hex())
+	}
+
+	it := trie.NewIterator(tree.NodeIterator(start[:]))
+	for it.Next() {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return err
+		}
+		var proof [][]byte
+		if prove {
+			for _, node := range it.Prove() {
+				proof = append(proof, common.CopyBytes(node))
+			}
+		}
+		if !cb(common.BytesToHash(it.Key), common.BytesToHash(content), proof) {
+			return nil
+		}
+	}
+	return it.Err
+}
+
+// proofCollector collects the trie nodes of a Merkle proof, from the root to the leaf
+type proofCollector struct {
+	nodes [][]byte
+}
+
+func (pc *proofCollector) Put(key []byte, value []byte) error {
+	pc.nodes = append(pc.nodes, common.CopyBytes(value))
+	return nil
+}
+
+// ProveAccount returns the Merkle proof of the account in the state trie, i.e. the trie nodes from the
+// state root to the account, or to the node proving its absence.
+func (sv *StoreView) ProveAccount(addr common.Address) ([][]byte, error) {
+	pc := &proofCollector{}
+	if err := sv.store.Prove(AccountKey(addr), 0, pc); err != nil {
+		return nil, err
+	}
+	return pc.nodes, nil
+}
+
+// ProveStorage returns the Merkle proof of the storage slot of the account in its storage trie, i.e. the
+// trie nodes from the storage root to the slot, or to the node proving its absence. The proof is empty if
+// the account does not exist.
+func (sv *StoreView) ProveStorage(addr common.Address, slot common.Hash) ([][]byte, error) {
+	account := sv.GetAccount(addr)
+	if account == nil {
+		return [][]byte{}, nil
+	}
+	tree := sv.getAccountStorage(account)
+	if tree == nil {
+		return nil, fmt.Errorf("the storage of %v is not found, it might have been pruned", addr.Hex())
+	}
+	pc := &proofCollector{}
+	if err := tree.Prove(slot[:], 0, pc); err != nil {
+		return nil, err
+	}
+	return pc.nodes, nil
+}
+
 func (sv *StoreView) Suicide(addr common.Address) bool {
 	account := sv.GetAccount(addr)
 	if account == nil {
@@ -1,7 +1,10 @@
 package state
 
 import (
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
@@ -10,7 +13,9 @@ import (
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
 	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rlp"
 	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/pandotoken/pando/store/trie"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -160,6 +165,20 @@ func TestStoreViewSplitRuleAccess(t *testing.T) {
 	log.Infof("Retrieved SplitRule #3: %v\n\n", retrievedSc3)
 	assert.Equal(sc3.String(), retrievedSc3.String())
 
+	otherSc := &types.SplitRule{
+		InitiatorAddress: common.HexToAddress("0x100"),
+		ResourceID:       "rid4",
+		EndBlockHeight:   100,
+	}
+	sv.SetSplitRule(otherSc.ResourceID, otherSc)
+	initiated := sv.GetSplitRulesByInitiator(initiatorAddr)
+	assert.Equal(3, len(initiated))
+	for _, splitRule := range initiated {
+		assert.Equal(initiatorAddr, splitRule.InitiatorAddress)
+	}
+	assert.Equal(4, len(sv.GetSplitRules()))
+	sv.DeleteSplitRule(otherSc.ResourceID)
+
 	sv.DeleteSplitRule(rid1)
 	assert.Nil(sv.GetSplitRule(rid1))
 	assert.NotNil(sv.GetSplitRule(rid2))
@@ -177,6 +196,12 @@ func TestStoreViewSplitRuleAccess(t *testing.T) {
 	assert.NotNil(sv.GetSplitRule(rid1))
 	assert.Nil(sv.GetSplitRule(rid2))
 	assert.NotNil(sv.GetSplitRule(rid3))
+	assert.Equal(2, len(sv.GetSplitRules()))
+
+	assert.False(sc2.IsExpired(17))
+	assert.True(sc2.IsExpired(18))
+	assert.Equal(uint64(11), sc3.RemainingDuration(17))
+	assert.Equal(uint64(0), sc3.RemainingDuration(29))
 }
 
 func TestRevertAndPruneStoreView(t *testing.T) {
@@ -254,6 +279,50 @@ func TestRevertAndPruneStoreView(t *testing.T) {
 	assert.Equal(value2, sv.GetState(acc1Addr, key1))
 }
 
+func TestArchivePruneStoreView(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "archive_test")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	hot, err := backend.NewLDBDatabase(path.Join(dir, "main"), path.Join(dir, "ref"), 0, 0)
+	assert.Nil(err)
+	db, err := backend.NewArchiveDatabase(hot, path.Join(dir, "ancient"), 0, 0)
+	assert.Nil(err)
+	defer db.Close()
+
+	addr := common.HexToAddress("0x111")
+	key := common.BytesToHash([]byte{1})
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+	sv.SetAccount(addr, &types.Account{Address: addr, Sequence: 1, Balance: types.NewCoins(100, 0)})
+	sv.SetState(addr, key, common.BytesToHash([]byte{11}))
+	root1 := sv.Save()
+
+	sv = NewStoreView(uint64(2), root1, db)
+	sv.SetAccount(addr, &types.Account{Address: addr, Sequence: 2, Balance: types.NewCoins(200, 0)})
+	sv.SetState(addr, key, common.BytesToHash([]byte{22}))
+	root2 := sv.Save()
+
+	// Pruning the state of the first block through the freezer empties the hot database of it
+	assert.Nil(NewStoreView(uint64(1), root1, db.Freezer()).Prune())
+	has, err := hot.Has(root1[:])
+	assert.Nil(err)
+	assert.False(has)
+
+	// The historical balance, nonce and storage are still readable from the ancient database
+	view := NewReadOnlyStoreView(uint64(1), root1, db)
+	if assert.NotNil(view) {
+		assert.Equal(int64(100), view.GetAccount(addr).Balance.PandoWei.Int64())
+		assert.Equal(uint64(1), view.GetAccount(addr).Sequence)
+		assert.Equal(common.BytesToHash([]byte{11}), view.GetState(addr, key))
+	}
+	view = NewReadOnlyStoreView(uint64(2), root2, db)
+	if assert.NotNil(view) {
+		assert.Equal(uint64(2), view.GetAccount(addr).Sequence)
+		assert.Equal(common.BytesToHash([]byte{22}), view.GetState(addr, key))
+	}
+}
+
 func TestGetAndUpdateValidatorCandidatePool(t *testing.T) {
 	assert := assert.New(t)
 
@@ -443,3 +512,366 @@ func compareHeightList(hl1, hl2 *types.HeightList) bool {
 
 	return true
 }
+
+func TestParamChangeSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(100), common.Hash{}, db)
+	rootHash := sv.Hash()
+
+	// Nothing scheduled, the state should not be touched
+	assert.Nil(sv.ActivateParamChanges())
+	assert.Equal(rootHash, sv.Hash())
+	assert.Nil(sv.GetParam("min_fee"))
+
+	sv.ResetLogs()
+	change1 := sv.ScheduleParamChange("min_fee", big.NewInt(2000))
+	assert.Equal(uint64(100), change1.AnnouncedHeight)
+	assert.Equal(100+types.ParamChangeActivationDelay, change1.ActivationHeight)
+
+	// The announcement is recorded by the chain, it is not a log of the transaction being executed
+	assert.Equal(0, len(sv.PopLogs()))
+
+	sv.height = 150
+	change2 := sv.ScheduleParamChange("max_gas", big.NewInt(5000))
+	sv.height = 120
+	change3 := sv.ScheduleParamChange("min_fee", big.NewInt(3000))
+	assert.Equal(3, sv.GetParamChangeSchedule().Len())
+
+	// Not activated before the activation height
+	sv.height = change1.ActivationHeight - 1
+	assert.Nil(sv.ActivateParamChanges())
+	assert.Nil(sv.GetParam("min_fee"))
+
+	sv.height = change1.ActivationHeight
+	activated := sv.ActivateParamChanges()
+	assert.Equal(1, len(activated))
+	assert.Equal(int64(2000), sv.GetParam("min_fee").Int64())
+	assert.Equal(2, sv.GetParamChangeSchedule().Len())
+
+	// Changes are activated in the order of the activation heights
+	sv.height = change2.ActivationHeight
+	activated = sv.ActivateParamChanges()
+	assert.Equal(2, len(activated))
+	assert.Equal(change3.Name, activated[0].Name)
+	assert.Equal(change2.Name, activated[1].Name)
+	assert.Equal(int64(3000), sv.GetParam("min_fee").Int64())
+	assert.Equal(int64(5000), sv.GetParam("max_gas").Int64())
+	assert.Equal(0, sv.GetParamChangeSchedule().Len())
+	assert.Nil(sv.Get(ParamChangeScheduleKey()))
+}
+
+func TestStakeUnbonding(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(100), common.Hash{}, db)
+
+	// The unbonding period defaults to the return locking period until set by the governance
+	assert.Equal(core.ReturnLockingPeriod, sv.StakeUnbondingPeriod())
+	sv.SetParam(types.ParamStakeUnbondingPeriod, big.NewInt(1000))
+	assert.Equal(uint64(1000), sv.StakeUnbondingPeriod())
+
+	source := common.HexToAddress("0x111")
+	holder := common.HexToAddress("0x222")
+	queue := sv.GetStakeUnbondingQueue()
+	assert.Equal(0, queue.Len())
+	queue.Add(&types.StakeUnbonding{Source: source, Holder: holder, Amount: big.NewInt(500), WithdrawHeight: 100, ReleaseHeight: 1100})
+	sv.UpdateStakeUnbondingQueue(queue)
+
+	queue = sv.GetStakeUnbondingQueue()
+	assert.Equal(1, queue.Len())
+	assert.Equal(int64(500), queue.Get(source, holder, core.StakeForValidator).Amount.Int64())
+
+	queue.PopReleased(1100)
+	sv.UpdateStakeUnbondingQueue(queue)
+	assert.Nil(sv.Get(StakeUnbondingQueueKey()))
+}
+
+func TestSessionKeysAccess(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(100), common.Hash{}, db)
+	rootHash := sv.Hash()
+
+	addr := common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+	skAddr := common.HexToAddress("0xdf1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E")
+
+	sessionKeys := sv.GetSessionKeys(addr)
+	assert.NotNil(sessionKeys)
+	assert.Equal(0, sessionKeys.Len())
+
+	sessionKeys.Set(&types.SessionKey{
+		Address:       skAddr,
+		TxTypes:       []types.TxType{types.TxSend},
+		Destinations:  []common.Address{},
+		SpendingLimit: types.NewCoins(0, 1000),
+		Spent:         types.NewCoins(0, 10),
+		ExpiryHeight:  200,
+	})
+	sv.UpdateSessionKeys(addr, sessionKeys)
+
+	retrieved := sv.GetSessionKeys(addr).Get(skAddr)
+	assert.NotNil(retrieved)
+	assert.Equal(int64(1000), retrieved.SpendingLimit.PTXWei.Int64())
+	assert.Equal(int64(10), retrieved.Spent.PTXWei.Int64())
+	assert.Equal(uint64(200), retrieved.ExpiryHeight)
+	assert.Equal([]types.TxType{types.TxSend}, retrieved.TxTypes)
+
+	// Removing the last session key deletes the entry
+	sessionKeys.Remove(skAddr)
+	sv.UpdateSessionKeys(addr, sessionKeys)
+	assert.Equal(0, sv.GetSessionKeys(addr).Len())
+	assert.Equal(rootHash, sv.Hash())
+}
+
+func TestRecentBlockHashes(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+
+	blockHash := func(height uint64) common.Hash {
+		return crypto.Keccak256Hash(new(big.Int).SetUint64(height).Bytes())
+	}
+
+	assert.Equal(common.Hash{}, sv.GetBlockHash(1))
+
+	for height := uint64(1); height <= 300; height++ {
+		sv.SetBlockHash(height, blockHash(height))
+	}
+
+	// Only the last NumRecentBlockHashes hashes are kept
+	for height := uint64(1); height <= 300; height++ {
+		if height > 300-NumRecentBlockHashes {
+			assert.Equal(blockHash(height), sv.GetBlockHash(height), "height %v", height)
+		} else {
+			assert.Equal(common.Hash{}, sv.GetBlockHash(height), "height %v", height)
+		}
+	}
+	assert.Equal(common.Hash{}, sv.GetBlockHash(301))
+	assert.Equal(common.Hash{}, sv.GetBlockHash(300+NumRecentBlockHashes))
+
+	// The forks of the state keep their own block hashes
+	sv.Save()
+	fork, err := sv.Copy()
+	assert.Nil(err)
+	forkHash := crypto.Keccak256Hash([]byte("fork"))
+	fork.SetBlockHash(301, forkHash)
+	sv.SetBlockHash(301, blockHash(301))
+	assert.Equal(forkHash, fork.GetBlockHash(301))
+	assert.Equal(blockHash(301), sv.GetBlockHash(301))
+	assert.NotEqual(fork.Hash(), sv.Hash())
+}
+
+func TestRandomness(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+
+	randomness, height := sv.GetRandomness()
+	assert.Equal(common.Hash{}, randomness)
+	assert.Equal(uint64(0), height)
+
+	beacon := crypto.Keccak256Hash([]byte("beacon"))
+	sv.SetRandomness(101, beacon)
+	randomness, height = sv.GetRandomness()
+	assert.Equal(beacon, randomness)
+	assert.Equal(uint64(101), height)
+}
+
+func TestReadOnlyStoreView(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(1, common.Hash{}, db)
+	k1, v1 := common.Bytes("key1"), common.Bytes("value1")
+	k2, v2 := common.Bytes("key2"), common.Bytes("value2")
+	sv.Set(k1, v1)
+	root := sv.Save()
+
+	rsv := NewReadOnlyStoreView(1, root, db)
+	assert.NotNil(rsv)
+	assert.True(rsv.IsReadOnly())
+	assert.Equal(v1, rsv.Get(k1))
+
+	rsv.Set(k2, v2)
+	rsvRoot := rsv.Save()
+	assert.NotEqual(root, rsvRoot)
+	assert.Equal(v2, rsv.Get(k2))
+
+	copied, err := rsv.Copy()
+	assert.Nil(err)
+	assert.True(copied.IsReadOnly())
+
+	// The changes are not persisted
+	assert.Nil(NewStoreView(1, rsvRoot, db))
+	sv = NewStoreView(1, root, db)
+	assert.Equal(v1, sv.Get(k1))
+	assert.Nil(sv.Get(k2))
+
+	// Pruned or unknown state
+	assert.Nil(NewReadOnlyStoreView(1, common.BytesToHash([]byte("unknown")), db))
+}
+
+func TestStoreViewIterateStorage(t *testing.T) {
+	assert := assert.New(t)
+
+	sv := NewStoreView(1, common.Hash{}, backend.NewMemDatabase())
+	contract := common.HexToAddress("0x200")
+	sv.CreateAccount(contract)
+	for i := int64(1); i <= 5; i++ {
+		sv.SetState(contract, common.BigToHash(big.NewInt(i*10)), common.BigToHash(big.NewInt(i)))
+	}
+	sv.SetState(contract, common.BigToHash(big.NewInt(30)), common.Hash{}) // deleted
+	storageRoot := sv.GetAccount(contract).Root
+
+	slots := []common.Hash{}
+	values := []common.Hash{}
+	err := sv.IterateStorage(contract, common.Hash{}, true, func(slot, value common.Hash, proof [][]byte) bool {
+		slots = append(slots, slot)
+		values = append(values, value)
+		assert.True(len(proof) > 0)
+		assert.Equal(storageRoot, crypto.Keccak256Hash(proof[0]), "the proof starts from the storage root")
+		return true
+	})
+	assert.Nil(err)
+	assert.Equal([]common.Hash{common.BigToHash(big.NewInt(10)), common.BigToHash(big.NewInt(20)),
+		common.BigToHash(big.NewInt(40)), common.BigToHash(big.NewInt(50))}, slots)
+	assert.Equal([]common.Hash{common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(2)),
+		common.BigToHash(big.NewInt(4)), common.BigToHash(big.NewInt(5))}, values)
+
+	// Resume from a slot, and stop early
+	slots = []common.Hash{}
+	err = sv.IterateStorage(contract, common.BigToHash(big.NewInt(20)), false, func(slot, value common.Hash, proof [][]byte) bool {
+		assert.Nil(proof)
+		slots = append(slots, slot)
+		return len(slots) < 2
+	})
+	assert.Nil(err)
+	assert.Equal([]common.Hash{common.BigToHash(big.NewInt(20)), common.BigToHash(big.NewInt(40))}, slots)
+
+	// The account proof starts from the state root
+	sv.Save()
+	proof, err := sv.ProveAccount(contract)
+	assert.Nil(err)
+	assert.True(len(proof) > 0)
+	assert.Equal(sv.Hash(), crypto.Keccak256Hash(proof[0]))
+}
+
+// proofReader serves the nodes of a Merkle proof by their hashes
+type proofReader map[common.Hash][]byte
+
+func newProofReader(proof [][]byte) proofReader {
+	pr := proofReader{}
+	for _, node := range proof {
+		pr[crypto.Keccak256Hash(node)] = node
+	}
+	return pr
+}
+
+func (pr proofReader) Get(key []byte) ([]byte, error) {
+	return pr[common.BytesToHash(key)], nil
+}
+
+func (pr proofReader) Has(key []byte) (bool, error) {
+	_, ok := pr[common.BytesToHash(key)]
+	return ok, nil
+}
+
+func TestStoreViewProveStorage(t *testing.T) {
+	assert := assert.New(t)
+
+	sv := NewStoreView(1, common.Hash{}, backend.NewMemDatabase())
+	contract := common.HexToAddress("0x200")
+	sv.CreateAccount(contract)
+	slot := common.BigToHash(big.NewInt(7))
+	sv.SetState(contract, slot, common.BigToHash(big.NewInt(42)))
+	sv.SetState(contract, common.BigToHash(big.NewInt(8)), common.BigToHash(big.NewInt(43)))
+	sv.Save()
+
+	// The account proof verifies against the state root
+	proof, err := sv.ProveAccount(contract)
+	assert.Nil(err)
+	value, _, err := trie.VerifyProof(sv.Hash(), AccountKey(contract), newProofReader(proof))
+	assert.Nil(err)
+	assert.NotEmpty(value)
+
+	// The storage proof verifies against the storage root
+	storageRoot := sv.GetAccount(contract).Root
+	proof, err = sv.ProveStorage(contract, slot)
+	assert.Nil(err)
+	value, _, err = trie.VerifyProof(storageRoot, slot[:], newProofReader(proof))
+	assert.Nil(err)
+	_, content, _, err := rlp.Split(value)
+	assert.Nil(err)
+	assert.Equal(common.BigToHash(big.NewInt(42)), common.BytesToHash(content))
+
+	// The absence of a slot is proven too
+	absent := common.BigToHash(big.NewInt(9))
+	proof, err = sv.ProveStorage(contract, absent)
+	assert.Nil(err)
+	value, _, err = trie.VerifyProof(storageRoot, absent[:], newProofReader(proof))
+	assert.Nil(err)
+	assert.Nil(value)
+}
+
+func TestSpeculativeStoreView(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(1, common.Hash{}, db)
+	contract := common.HexToAddress("0x300")
+	sender := common.HexToAddress("0x301")
+	sv.CreateAccount(contract)
+	sv.CreateAccount(sender)
+	sv.Save()
+	numKeys := db.Len()
+
+	// The speculative changes are kept off the StoreView and the database
+	spec, err := sv.SpeculativeCopy()
+	assert.Nil(err)
+	spec.SetState(contract, common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(7)))
+	spec.SetNonce(sender, 5)
+	assert.Equal(common.BigToHash(big.NewInt(7)), spec.GetState(contract, common.BigToHash(big.NewInt(1))))
+	assert.Equal(common.Hash{}, sv.GetState(contract, common.BigToHash(big.NewInt(1))))
+	assert.Equal(uint64(0), sv.GetNonce(sender))
+	assert.Equal(numKeys, db.Len())
+
+	access := spec.Access()
+	assert.True(access.Reads.Has(AccountKey(contract)))
+	assert.True(access.Writes.Has(AccountKey(contract)))
+	assert.True(access.Writes.Has(AccountKey(sender)))
+	written := KeySet{}
+	written.Add(AccountKey(sender))
+	assert.True(access.ConflictsWith(written))
+	assert.False(access.ConflictsWith(KeySet{}))
+
+	// The side effects are deferred until merged, in their original order
+	effects := []int{}
+	spec.RunSideEffect(func() { effects = append(effects, 1) })
+	spec.RunSideEffect(func() { effects = append(effects, 2) })
+	assert.Equal(0, len(effects))
+
+	// The storage trie written speculatively is added to the database by merging
+	serial, err := sv.Copy()
+	assert.Nil(err)
+	assert.Nil(sv.Merge(spec))
+	assert.Equal([]int{1, 2}, effects)
+	serial.RunSideEffect(func() { effects = append(effects, 3) })
+	assert.Equal([]int{1, 2, 3}, effects)
+	sv.Save()
+	reloaded := NewStoreView(1, sv.Hash(), db)
+	assert.Equal(common.BigToHash(big.NewInt(7)), reloaded.GetState(contract, common.BigToHash(big.NewInt(1))))
+
+	// Merging has the same effect as executing on the StoreView
+	serial.SetState(contract, common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(7)))
+	serial.SetNonce(sender, 5)
+	assert.Equal(serial.Hash(), sv.Hash())
+
+	// Only the speculative copies can be merged
+	assert.NotNil(sv.Merge(serial))
+}
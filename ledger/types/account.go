@@ -21,6 +21,9 @@ type Account struct {
 	// Smart contract
 	Root     common.Hash `json:"root"`      // merkle root of the storage trie
 	CodeHash common.Hash `json:"code_hash"` // hash of the smart contract code
+
+	// Key rotation
+	AuthorizedKey *AuthorizedKey `rlp:"optional"` // nil if the key of the account address controls the account
 }
 
 type AccountJSON struct {
@@ -30,6 +33,7 @@ type AccountJSON struct {
 	LastUpdatedBlockHeight common.JSONUint64 `json:"last_updated_block_height"`
 	Root                   common.Hash       `json:"root"`
 	CodeHash               common.Hash       `json:"code"`
+	AuthorizedKey          *AuthorizedKey    `json:"authorized_key,omitempty"`
 }
 
 func NewAccountJSON(acc Account) AccountJSON {
@@ -40,6 +44,7 @@ func NewAccountJSON(acc Account) AccountJSON {
 		LastUpdatedBlockHeight: common.JSONUint64(acc.LastUpdatedBlockHeight),
 		Root:                   acc.Root,
 		CodeHash:               acc.CodeHash,
+		AuthorizedKey:          acc.AuthorizedKey,
 	}
 }
 
@@ -51,6 +56,7 @@ func (acc AccountJSON) Account() Account {
 		LastUpdatedBlockHeight: uint64(acc.LastUpdatedBlockHeight),
 		Root:                   acc.Root,
 		CodeHash:               acc.CodeHash,
+		AuthorizedKey:          acc.AuthorizedKey,
 	}
 }
 
@@ -185,6 +191,13 @@ func calcMinimumReleaseBlockHeight(reservedFund *ReservedFund) uint64 {
 	// releaseFundTx are NOT included in the same block. Otherwise the releaseFundTx may be
 	// executed before the slashTx, and the overspender can escape from the punishment
 	minimumReleaseBlockHeight := reservedFund.EndBlockHeight + ReservedFundFreezePeriodDuration
+
+	// The fund also needs to cover the overrides of the settlements until their dispute windows close
+	for _, settlement := range reservedFund.Settlements {
+		if settlement.DisputeWindowEnd()+1 > minimumReleaseBlockHeight {
+			minimumReleaseBlockHeight = settlement.DisputeWindowEnd() + 1
+		}
+	}
 	return minimumReleaseBlockHeight
 }
 
@@ -204,6 +217,16 @@ func (acc *Account) ReleaseFund(currentBlockHeight uint64, reserveSequence uint6
 	}
 }
 
+// GetReservedFund returns the reserved fund with the given reserve sequence, nil if not found
+func (acc *Account) GetReservedFund(reserveSequence uint64) *ReservedFund {
+	for idx := range acc.ReservedFunds {
+		if acc.ReservedFunds[idx].ReserveSequence == reserveSequence {
+			return &acc.ReservedFunds[idx]
+		}
+	}
+	return nil
+}
+
 // CheckTransferReservedFund verifies inputs for SplitReservedFund
 func (acc *Account) CheckTransferReservedFund(tgtAcc *Account, transferAmount Coins, paymentSequence uint64, currentBlockHeight uint64, reserveSequence uint64) error {
 	for _, reservedFund := range acc.ReservedFunds {
@@ -215,8 +238,13 @@ func (acc *Account) CheckTransferReservedFund(tgtAcc *Account, transferAmount Co
 			return errors.New("Already expired")
 		}
 
+		err := reservedFund.CheckPaymentCurrency(transferAmount)
+		if err != nil {
+			return err
+		}
+
 		targetAddress := tgtAcc.Address
-		err := reservedFund.VerifyPaymentSequence(targetAddress, paymentSequence)
+		err = reservedFund.VerifyPaymentSequence(targetAddress, paymentSequence)
 		if err != nil {
 			return err
 		}
@@ -226,6 +254,32 @@ func (acc *Account) CheckTransferReservedFund(tgtAcc *Account, transferAmount Co
 	return errors.Errorf("No matching ReservedFund with reserveSequence %d", reserveSequence)
 }
 
+// CheckSettleReservedFund verifies the inputs of a service payment settling the cumulative amount paid to the
+// target. A settlement can still be overridden after the fund expires, until its dispute window closes.
+func (acc *Account) CheckSettleReservedFund(tgtAcc *Account, cumulativeAmount Coins, paymentSequence uint64, currentBlockHeight uint64, reserveSequence uint64) error {
+	reservedFund := acc.GetReservedFund(reserveSequence)
+	if reservedFund == nil {
+		return errors.Errorf("No matching ReservedFund with reserveSequence %d", reserveSequence)
+	}
+
+	targetAddress := tgtAcc.Address
+	if reservedFund.EndBlockHeight < currentBlockHeight && !reservedFund.IsInDisputeWindow(targetAddress, currentBlockHeight) {
+		return errors.New("Already expired")
+	}
+
+	err := reservedFund.CheckPaymentCurrency(cumulativeAmount)
+	if err != nil {
+		return err
+	}
+
+	err = reservedFund.VerifyPaymentSequence(targetAddress, paymentSequence)
+	if err != nil {
+		return err
+	}
+
+	return reservedFund.CheckSettlement(targetAddress, paymentSequence, cumulativeAmount, currentBlockHeight)
+}
+
 // TransferReservedFund transfers the specified amount of reserved fund to the accounts participated in the payment split, and send remainder back to the source account (i.e. the acount itself)
 func (acc *Account) TransferReservedFund(splittedCoinsMap map[*Account]Coins, currentBlockHeight uint64,
 	reserveSequence uint64, servicePaymentTx *ServicePaymentTx) (shouldSlash bool, slashIntent SlashIntent) {
@@ -279,6 +333,7 @@ func (acc *Account) generateSlashIntent(reservedFund *ReservedFund, currentServi
 func (acc *Account) UpdateToHeight(height uint64) {
 	//	acc.UpdateAccountPTXReward(height) // Initial PTX inflation should be zero for all accounts
 	acc.ReleaseExpiredFunds(height)
+	acc.ExpireKeyRotation(height)
 }
 
 // func (acc *Account) UpdateAccountPTXReward(currentBlockHeight uint64) {
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/pandotoken/pando/core"
+)
+
+const (
+	// BaseFeeElasticityMultiplier is the ratio of the block budget to its utilization at which the base
+	// fee stays unchanged. The base fee rises after the fuller blocks and falls after the emptier ones.
+	BaseFeeElasticityMultiplier uint64 = 2
+
+	// BaseFeeTargetNumTxsPerBlock is the number of regular transactions per block at which the base fee
+	// stays unchanged before the block budget
+	BaseFeeTargetNumTxsPerBlock uint64 = uint64(core.MaxNumRegularTxsPerBlock) / BaseFeeElasticityMultiplier
+
+	// BaseFeeMaxChangeDenominator bounds the change of the base fee between two blocks, to 1/8 of
+	// the base fee for an empty or a full block
+	BaseFeeMaxChangeDenominator int64 = 8
+)
+
+// MinimumBaseFee returns the lowest base fee, i.e. the minimum fee of a regular transaction
+func MinimumBaseFee() *big.Int {
+	return new(big.Int).SetUint64(MinimumTransactionFeePTXWei)
+}
+
+// NextBaseFee returns the base fee following a block which used the given amount of its capacity, e.g.
+// gas, against the target. The base fee is the minimum fee of a regular transaction, it never falls
+// below the fixed minimum.
+func NextBaseFee(baseFee *big.Int, used uint64, target uint64) *big.Int {
+	minimum := MinimumBaseFee()
+	if baseFee == nil || baseFee.Cmp(minimum) < 0 {
+		baseFee = minimum
+	}
+	if target == 0 || used == target {
+		return new(big.Int).Set(baseFee)
+	}
+
+	// delta = baseFee * (used - target) / target / BaseFeeMaxChangeDenominator
+	delta := new(big.Int).Sub(new(big.Int).SetUint64(used), new(big.Int).SetUint64(target))
+	delta.Mul(delta, baseFee)
+	delta.Quo(delta, new(big.Int).SetUint64(target))
+	delta.Quo(delta, big.NewInt(BaseFeeMaxChangeDenominator))
+	if used > target && delta.Sign() == 0 {
+		delta.SetInt64(1)
+	}
+
+	next := new(big.Int).Add(baseFee, delta)
+	if next.Cmp(minimum) < 0 {
+		return minimum
+	}
+	return next
+}
+
+// MaxNextBaseFee returns the highest base fee possible for the next block, i.e. after a full block
+func MaxNextBaseFee(baseFee *big.Int) *big.Int {
+	return NextBaseFee(baseFee, BaseFeeElasticityMultiplier, 1)
+}
+
+// BaseGasPrice returns the minimum gas price of a smart contract transaction under the base fee,
+// which scales with the base fee from MinimumGasPrice
+func BaseGasPrice(baseFee *big.Int) *big.Int {
+	gasPrice := new(big.Int).Mul(new(big.Int).SetUint64(MinimumGasPrice), baseFee)
+	gasPrice.Quo(gasPrice, MinimumBaseFee())
+	if gasPrice.Cmp(new(big.Int).SetUint64(MinimumGasPrice)) < 0 {
+		return new(big.Int).SetUint64(MinimumGasPrice)
+	}
+	return gasPrice
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextBaseFee(t *testing.T) {
+	assert := assert.New(t)
+
+	minimum := MinimumBaseFee()
+	target := uint64(100)
+
+	// The base fee never falls below the minimum
+	assert.Equal(minimum, NextBaseFee(minimum, 0, target))
+	assert.Equal(minimum, NextBaseFee(nil, target, target))
+	assert.Equal(minimum, NextBaseFee(big.NewInt(1), target, target))
+
+	// A full block raises the base fee by 1/8
+	baseFee := big.NewInt(8e12)
+	assert.Equal(big.NewInt(9e12), NextBaseFee(baseFee, 2*target, target))
+	assert.Equal(big.NewInt(8e12+5e11), NextBaseFee(baseFee, target+target/2, target))
+
+	// An empty block lowers it by 1/8
+	assert.Equal(big.NewInt(7e12), NextBaseFee(baseFee, 0, target))
+
+	// and a block on target keeps it
+	assert.Equal(baseFee, NextBaseFee(baseFee, target, target))
+	assert.Equal(big.NewInt(8e12), baseFee)
+
+	// Any block above the target raises the base fee
+	assert.Equal(1, NextBaseFee(minimum, 1000001, 1000000).Cmp(minimum))
+
+	assert.Equal(NextBaseFee(baseFee, 2*BaseFeeTargetNumTxsPerBlock, BaseFeeTargetNumTxsPerBlock), MaxNextBaseFee(baseFee))
+}
+
+func TestBaseGasPrice(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(new(big.Int).SetUint64(MinimumGasPrice), BaseGasPrice(MinimumBaseFee()))
+	assert.Equal(new(big.Int).SetUint64(MinimumGasPrice), BaseGasPrice(big.NewInt(1)))
+	baseFee := new(big.Int).Mul(MinimumBaseFee(), big.NewInt(3))
+	assert.Equal(new(big.Int).SetUint64(3*MinimumGasPrice), BaseGasPrice(baseFee))
+}
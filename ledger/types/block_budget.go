@@ -0,0 +1,164 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ParamBlockGasLimit is the governance parameter for the total gas of the regular transactions in a
+// block. A smart contract transaction counts with its gas limit, since its gas usage is only known
+// after it executes.
+const ParamBlockGasLimit = "BlockGasLimit"
+
+// ParamBlockTxsSizeLimit is the governance parameter for the total size (in bytes) of the encoded
+// regular transactions in a block
+const ParamBlockTxsSizeLimit = "BlockTxsSizeLimit"
+
+const (
+	// DefaultBlockGasLimit is the default of the block gas limit. It leaves room for a block full of
+	// send transactions, or for 10 smart contract transactions with the maximum gas limit.
+	DefaultBlockGasLimit uint64 = 100e6
+
+	// DefaultBlockTxsSizeLimit is the default of the block transactions size limit, which leaves room
+	// for the block header and the special transactions under the max block message size
+	DefaultBlockTxsSizeLimit uint64 = 8 * 1024 * 1024
+)
+
+// TxGas returns the gas the transaction counts against the block gas limit. The coinbase and slash
+// transactions are not subject to the limit.
+func TxGas(tx Tx) uint64 {
+	switch tx := tx.(type) {
+	case *CoinbaseTx, *SlashTx:
+		return 0
+	case *SendTx:
+		return sendTxGas(len(tx.Inputs) + len(tx.Outputs))
+	case *BatchSendTx:
+		return sendTxGas(len(tx.Inputs) + len(tx.Outputs))
+	case *RametronStakeTx:
+		return sendTxGas(len(tx.Inputs) + len(tx.Outputs))
+	case *SmartContractTx:
+		return tx.GasLimit
+	case *RametronAttestationTx:
+		return tx.Gas()
+	case *BridgeClaimTx:
+		return tx.Gas()
+	case *SubchainCheckpointTx:
+		return tx.Gas()
+	case *SubchainChallengeTx:
+		return tx.Gas()
+	case *ReserveFundTx:
+		return GasReserveFundTx
+	case *ReleaseFundTx:
+		return GasReleaseFundTx
+	case *ServicePaymentTx:
+		return GasServicePaymentTx
+	case *SplitRuleTx:
+		return GasSplitRuleTx
+	case *DepositStakeTx, *DepositStakeTxV2:
+		return GasDepositStakeTx
+	case *WithdrawStakeTx:
+		return GasWidthdrawStakeTx
+	case *SessionKeyTx:
+		return GasSessionKeyTx
+	case *SlashAppealTx:
+		return GasSlashAppealTx
+	case *SlashAppealVoteTx:
+		return GasSlashAppealVoteTx
+	case *ClaimEscrowTx:
+		return GasClaimEscrowTx
+	case *MeteredSettlementTx:
+		return GasMeteredSettlementTx
+	case *DelegateTx:
+		return GasDelegateTx
+	case *UndelegateTx:
+		return GasUndelegateTx
+	case *SetCommissionTx:
+		return GasSetCommissionTx
+	case *KeyRotationTx:
+		return GasKeyRotationTx
+	case *HTLCCreateTx:
+		return GasHTLCCreateTx
+	case *HTLCClaimTx:
+		return GasHTLCClaimTx
+	case *HTLCRefundTx:
+		return GasHTLCRefundTx
+	case *ProposalTx:
+		return GasProposalTx
+	case *VoteTx:
+		return GasVoteTx
+	case *BridgeLockTx:
+		return GasBridgeLockTx
+	case *BridgeBurnTx:
+		return GasBridgeBurnTx
+	case *SubchainRegisterTx:
+		return GasSubchainRegisterTx
+	case *SubchainStakeTx:
+		return GasSubchainStakeTx
+	}
+	return 0
+}
+
+// sendTxGas returns the gas of a send transaction affecting the given number of accounts
+func sendTxGas(numAccountsAffected int) uint64 {
+	gas := GasSendTxPerAccount * uint64(numAccountsAffected)
+	if gas < 2*GasSendTxPerAccount {
+		gas = 2 * GasSendTxPerAccount // to prevent spamming with invalid transactions, e.g. empty inputs/outputs
+	}
+	return gas
+}
+
+// BlockBudget keeps track of the gas and the size of the regular transactions added to a block
+// against the block limits
+type BlockBudget struct {
+	GasLimit  uint64
+	SizeLimit uint64
+	GasUsed   uint64
+	SizeUsed  uint64
+}
+
+// NewBlockBudget creates an empty budget with the given limits
+func NewBlockBudget(gasLimit uint64, sizeLimit uint64) *BlockBudget {
+	return &BlockBudget{
+		GasLimit:  gasLimit,
+		SizeLimit: sizeLimit,
+	}
+}
+
+// Fits indicates whether a transaction of the given gas and size fits in the rest of the budget
+func (b *BlockBudget) Fits(gas uint64, size uint64) bool {
+	return gas <= b.GasLimit-b.GasUsed && size <= b.SizeLimit-b.SizeUsed
+}
+
+// Add adds the transaction to the budget, and returns an error if the transaction does not fit
+func (b *BlockBudget) Add(rawTx []byte, tx Tx) error {
+	gas := TxGas(tx)
+	size := uint64(len(rawTx))
+	if !b.Fits(gas, size) {
+		return fmt.Errorf("Transaction with gas %v and size %v exceeds the block budget, %v out of %v gas and %v out of %v bytes used",
+			gas, size, b.GasUsed, b.GasLimit, b.SizeUsed, b.SizeLimit)
+	}
+	b.GasUsed += gas
+	b.SizeUsed += size
+	return nil
+}
+
+// Utilization returns the usage of the budget and the usage at which the base fee stays unchanged,
+// i.e. 1/BaseFeeElasticityMultiplier of the limit. The gas and the size count in units of gas, so the
+// block is as full as the more utilized of the two.
+func (b *BlockBudget) Utilization() (used uint64, target uint64) {
+	used = b.GasUsed
+	if b.SizeLimit != 0 {
+		// sizeUsed * GasLimit / SizeLimit <= GasLimit
+		sizeUsed := new(big.Int).SetUint64(b.SizeUsed)
+		sizeUsed.Mul(sizeUsed, new(big.Int).SetUint64(b.GasLimit))
+		sizeUsed.Quo(sizeUsed, new(big.Int).SetUint64(b.SizeLimit))
+		if sizeUsed.Uint64() > used {
+			used = sizeUsed.Uint64()
+		}
+	}
+	return used, b.GasLimit / BaseFeeElasticityMultiplier
+}
+
+func (b *BlockBudget) String() string {
+	return fmt.Sprintf("BlockBudget{gas: %v/%v, size: %v/%v}", b.GasUsed, b.GasLimit, b.SizeUsed, b.SizeLimit)
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxGas(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(uint64(0), TxGas(&CoinbaseTx{}))
+	assert.Equal(2*GasSendTxPerAccount, TxGas(&SendTx{}))
+	assert.Equal(3*GasSendTxPerAccount, TxGas(&SendTx{Inputs: []TxInput{{}}, Outputs: []TxOutput{{}, {}}}))
+	assert.Equal(uint64(500000), TxGas(&SmartContractTx{GasLimit: 500000}))
+	assert.Equal(GasHTLCCreateTx, TxGas(&HTLCCreateTx{}))
+}
+
+func TestBlockBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	budget := NewBlockBudget(5*GasSendTxPerAccount, 100)
+	rawTx := make([]byte, 40)
+
+	assert.Nil(budget.Add(rawTx, &SendTx{}))
+	assert.Nil(budget.Add(rawTx, &SendTx{}))
+	assert.Equal(4*GasSendTxPerAccount, budget.GasUsed)
+	assert.Equal(uint64(80), budget.SizeUsed)
+
+	// Out of gas
+	assert.False(budget.Fits(2*GasSendTxPerAccount, 10))
+	assert.NotNil(budget.Add(make([]byte, 10), &SendTx{}))
+
+	// Out of space
+	assert.False(budget.Fits(0, 40))
+	assert.NotNil(budget.Add(rawTx, &SlashTx{}))
+
+	// A rejected transaction leaves the budget as is
+	assert.Equal(4*GasSendTxPerAccount, budget.GasUsed)
+	assert.Equal(uint64(80), budget.SizeUsed)
+	assert.True(budget.Fits(GasSendTxPerAccount, 20))
+}
+
+func TestBlockBudgetUtilization(t *testing.T) {
+	assert := assert.New(t)
+
+	budget := NewBlockBudget(1000, 100)
+	used, target := budget.Utilization()
+	assert.Equal(uint64(0), used)
+	assert.Equal(uint64(500), target)
+
+	// The more utilized of the gas and the size counts
+	budget.GasUsed, budget.SizeUsed = 300, 20
+	used, _ = budget.Utilization()
+	assert.Equal(uint64(300), used)
+	budget.SizeUsed = 80
+	used, _ = budget.Utilization()
+	assert.Equal(uint64(800), used)
+	budget.SizeUsed = 100
+	used, _ = budget.Utilization()
+	assert.Equal(uint64(1000), used)
+}
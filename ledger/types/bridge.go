@@ -0,0 +1,305 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// ParamBridgeAttestationThreshold is the governance parameter for the share of the stake of the bridge
+// validators (in basis points) which needs to attest a foreign event, see VerifyForeignProof
+const ParamBridgeAttestationThreshold = "BridgeAttestationThreshold"
+
+// The kinds of the outbound bridge messages
+const (
+	// BridgeMessageLock is the lock of native coins on Pando, to be minted as wrapped coins on the foreign chain
+	BridgeMessageLock uint64 = 1
+
+	// BridgeMessageBurn is the burn of a wrapped foreign asset on Pando, to be released on the foreign chain
+	BridgeMessageBurn uint64 = 2
+)
+
+// BridgeMessage is an outbound message of the bridge, recorded in the state by a BridgeLockTx or a
+// BridgeBurnTx. The relayers prove its inclusion in the state of a finalized block (see the
+// pando.GetBridgeMessageProof RPC) to the bridge contract of the foreign chain.
+type BridgeMessage struct {
+	Nonce     uint64         // sequence number of the message, starting at 1
+	Kind      uint64         // BridgeMessageLock or BridgeMessageBurn
+	Sender    common.Address // the sender on Pando
+	DestChain string         // the foreign chain
+	Recipient common.Address // the recipient on the foreign chain
+	Coins     Coins          // the locked coins, for the lock messages
+	Token     common.Address // the token of the foreign chain, for the burn messages
+	Amount    *big.Int       // the burned amount, for the burn messages
+	Height    uint64         // height of the block recording the message
+}
+
+type BridgeMessageJSON struct {
+	Nonce     common.JSONUint64 `json:"nonce"`
+	Kind      common.JSONUint64 `json:"kind"`
+	Sender    common.Address    `json:"sender"`
+	DestChain string            `json:"dest_chain"`
+	Recipient common.Address    `json:"recipient"`
+	Coins     Coins             `json:"coins"`
+	Token     common.Address    `json:"token"`
+	Amount    *common.JSONBig   `json:"amount"`
+	Height    common.JSONUint64 `json:"height"`
+}
+
+func NewBridgeMessageJSON(a BridgeMessage) BridgeMessageJSON {
+	return BridgeMessageJSON{
+		Nonce:     common.JSONUint64(a.Nonce),
+		Kind:      common.JSONUint64(a.Kind),
+		Sender:    a.Sender,
+		DestChain: a.DestChain,
+		Recipient: a.Recipient,
+		Coins:     a.Coins,
+		Token:     a.Token,
+		Amount:    (*common.JSONBig)(a.Amount),
+		Height:    common.JSONUint64(a.Height),
+	}
+}
+
+func (a BridgeMessageJSON) BridgeMessage() BridgeMessage {
+	return BridgeMessage{
+		Nonce:     uint64(a.Nonce),
+		Kind:      uint64(a.Kind),
+		Sender:    a.Sender,
+		DestChain: a.DestChain,
+		Recipient: a.Recipient,
+		Coins:     a.Coins,
+		Token:     a.Token,
+		Amount:    (*big.Int)(a.Amount),
+		Height:    uint64(a.Height),
+	}
+}
+
+func (a BridgeMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewBridgeMessageJSON(a))
+}
+
+func (a *BridgeMessage) UnmarshalJSON(data []byte) error {
+	var b BridgeMessageJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.BridgeMessage()
+	return nil
+}
+
+// Hash returns the Keccak256 hash of the RLP encoding of the message, i.e. of the value proven in the state
+func (m *BridgeMessage) Hash() common.Hash {
+	encoded, err := ToBytes(m)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the bridge message: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+func (m *BridgeMessage) String() string {
+	return fmt.Sprintf("BridgeMessage{nonce: %v, kind: %v, sender: %v, dest_chain: %v, recipient: %v, coins: %v, token: %v, amount: %v, height: %v}",
+		m.Nonce, m.Kind, m.Sender.Hex(), m.DestChain, m.Recipient.Hex(), m.Coins, m.Token.Hex(), m.Amount, m.Height)
+}
+
+// CheckBridgeChainID checks the ID of a foreign chain of the bridge
+func CheckBridgeChainID(chainID string) error {
+	if len(chainID) == 0 || len(chainID) > MaximumBridgeChainIDLength {
+		return fmt.Errorf("Invalid foreign chain ID %q, needs to be between 1 and %v bytes", chainID, MaximumBridgeChainIDLength)
+	}
+	return nil
+}
+
+// WrappedAssetID returns the ID of the wrapped asset minted on Pando for the token of the foreign chain.
+// The zero address designates the native coin of the foreign chain.
+func WrappedAssetID(chain string, token common.Address) common.Hash {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"wrapped", chain, token})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the wrapped asset ID: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// BridgeForeignEvent is an event of the bridge contract of a foreign chain, attested by the bridge
+// validators. It is either the lock of a foreign asset, minted as a wrapped asset on Pando, or the burn
+// of wrapped Pando coins on the foreign chain, releasing the coins locked on Pando.
+type BridgeForeignEvent struct {
+	SourceChain string         // the foreign chain
+	TxHash      common.Hash    // the transaction of the foreign chain emitting the event
+	LogIndex    uint64         // the index of the event in the logs of the transaction
+	Recipient   common.Address // the recipient on Pando
+	Coins       Coins          // the coins to release, for the burns of wrapped Pando coins
+	Token       common.Address // the locked token of the foreign chain, for the locks of foreign assets
+	Amount      *big.Int       // the locked amount, for the locks of foreign assets
+}
+
+type BridgeForeignEventJSON struct {
+	SourceChain string            `json:"source_chain"`
+	TxHash      common.Hash       `json:"tx_hash"`
+	LogIndex    common.JSONUint64 `json:"log_index"`
+	Recipient   common.Address    `json:"recipient"`
+	Coins       Coins             `json:"coins"`
+	Token       common.Address    `json:"token"`
+	Amount      *common.JSONBig   `json:"amount"`
+}
+
+func NewBridgeForeignEventJSON(a BridgeForeignEvent) BridgeForeignEventJSON {
+	return BridgeForeignEventJSON{
+		SourceChain: a.SourceChain,
+		TxHash:      a.TxHash,
+		LogIndex:    common.JSONUint64(a.LogIndex),
+		Recipient:   a.Recipient,
+		Coins:       a.Coins,
+		Token:       a.Token,
+		Amount:      (*common.JSONBig)(a.Amount),
+	}
+}
+
+func (a BridgeForeignEventJSON) BridgeForeignEvent() BridgeForeignEvent {
+	return BridgeForeignEvent{
+		SourceChain: a.SourceChain,
+		TxHash:      a.TxHash,
+		LogIndex:    uint64(a.LogIndex),
+		Recipient:   a.Recipient,
+		Coins:       a.Coins,
+		Token:       a.Token,
+		Amount:      (*big.Int)(a.Amount),
+	}
+}
+
+func (a BridgeForeignEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewBridgeForeignEventJSON(a))
+}
+
+func (a *BridgeForeignEvent) UnmarshalJSON(data []byte) error {
+	var b BridgeForeignEventJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.BridgeForeignEvent()
+	return nil
+}
+
+// ID returns the ID of the event, which can only be claimed once
+func (e *BridgeForeignEvent) ID() common.Hash {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"bridge", e.SourceChain, e.TxHash, e.LogIndex})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the foreign event ID: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// IsRelease returns whether the event releases coins locked on Pando, rather than minting a wrapped asset
+func (e *BridgeForeignEvent) IsRelease() bool {
+	return e.Amount == nil || e.Amount.Sign() == 0
+}
+
+// ValidateBasic checks the event, without checking its attestations
+func (e *BridgeForeignEvent) ValidateBasic() error {
+	if err := CheckBridgeChainID(e.SourceChain); err != nil {
+		return err
+	}
+	if e.Recipient == (common.Address{}) {
+		return errors.New("The recipient is not specified")
+	}
+	coins := e.Coins.NoNil()
+	if e.IsRelease() {
+		if !coins.IsValid() || !coins.IsPositive() {
+			return fmt.Errorf("Invalid coins to release: %v", coins)
+		}
+		return nil
+	}
+	if e.Amount.Sign() < 0 {
+		return fmt.Errorf("Invalid amount to mint: %v", e.Amount)
+	}
+	if !coins.IsZero() {
+		return errors.New("An event cannot both mint a wrapped asset and release coins")
+	}
+	return nil
+}
+
+// SignBytes returns the bytes the bridge validators sign to attest the event. The chain ID of Pando
+// is included so that the attestations cannot be replayed on another network.
+func (e *BridgeForeignEvent) SignBytes(chainID string) common.Bytes {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"pando-bridge", chainID, e})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the foreign event: %v", err))
+	}
+	return encoded
+}
+
+func (e *BridgeForeignEvent) String() string {
+	return fmt.Sprintf("BridgeForeignEvent{source_chain: %v, tx_hash: %v, log_index: %v, recipient: %v, coins: %v, token: %v, amount: %v}",
+		e.SourceChain, e.TxHash.Hex(), e.LogIndex, e.Recipient.Hex(), e.Coins, e.Token.Hex(), e.Amount)
+}
+
+// BridgeForeignProof is a foreign event with the attestations of the bridge validators, i.e. their
+// signatures over the sign bytes of the event.
+type BridgeForeignProof struct {
+	Event        BridgeForeignEvent  `json:"event"`
+	Attestations []*crypto.Signature `json:"attestations"`
+}
+
+// BridgeValidators returns the bridge validators, i.e. the top stake holders of the validator
+// candidate pool
+func BridgeValidators(vcp *core.ValidatorCandidatePool) []*core.StakeHolder {
+	return vcp.GetTopStakeHolders(BridgeValidatorCount)
+}
+
+// VerifyForeignProof checks the foreign event, and that the bridge validators attesting it hold at
+// least threshold basis points of the stake of the bridge validators. Every attestation needs to be
+// a valid signature of a distinct bridge validator.
+func VerifyForeignProof(chainID string, proof *BridgeForeignProof, validators []*core.StakeHolder, threshold uint64) error {
+	if err := proof.Event.ValidateBasic(); err != nil {
+		return err
+	}
+	if len(proof.Attestations) > len(validators) {
+		return fmt.Errorf("Too many attestations, got %v, at most %v", len(proof.Attestations), len(validators))
+	}
+
+	stakes := make(map[common.Address]*big.Int, len(validators))
+	totalStake := new(big.Int)
+	for _, validator := range validators {
+		stake := validator.TotalStake()
+		stakes[validator.Holder] = stake
+		totalStake.Add(totalStake, stake)
+	}
+	if totalStake.Sign() == 0 {
+		return errors.New("There is no bridge validator")
+	}
+
+	signBytes := proof.Event.SignBytes(chainID)
+	attested := make(map[common.Address]bool, len(proof.Attestations))
+	attestedStake := new(big.Int)
+	for _, sig := range proof.Attestations {
+		if sig == nil {
+			return errors.New("Empty attestation")
+		}
+		signer, err := sig.RecoverSignerAddress(signBytes)
+		if err != nil {
+			return fmt.Errorf("Invalid attestation: %v", err)
+		}
+		stake, ok := stakes[signer]
+		if !ok {
+			return fmt.Errorf("%v is not a bridge validator", signer.Hex())
+		}
+		if attested[signer] {
+			return fmt.Errorf("%v attested the event more than once", signer.Hex())
+		}
+		attested[signer] = true
+		attestedStake.Add(attestedStake, stake)
+	}
+
+	// attestedStake / totalStake >= threshold / 10000
+	if new(big.Int).Mul(attestedStake, big.NewInt(10000)).Cmp(
+		new(big.Int).Mul(totalStake, new(big.Int).SetUint64(threshold))) < 0 {
+		return fmt.Errorf("Insufficient attestations, %v of the stake of %v attested, %v basis points required",
+			attestedStake, totalStake, threshold)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+)
+
+func TestBridgeForeignEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	release := &BridgeForeignEvent{SourceChain: "ethereum", TxHash: common.HexToHash("0x1"), Recipient: recipient, Coins: NewCoins(0, 100)}
+	mint := &BridgeForeignEvent{SourceChain: "ethereum", TxHash: common.HexToHash("0x1"), LogIndex: 1, Recipient: recipient, Amount: big.NewInt(100)}
+
+	assert.True(release.IsRelease())
+	assert.False(mint.IsRelease())
+	assert.Nil(release.ValidateBasic())
+	assert.Nil(mint.ValidateBasic())
+	assert.NotEqual(release.ID(), mint.ID())
+	assert.NotEqual(release.SignBytes("pandonet"), release.SignBytes("testnet"))
+
+	// An event needs a recipient, and cannot both release coins and mint a wrapped asset
+	assert.NotNil((&BridgeForeignEvent{SourceChain: "ethereum", Coins: NewCoins(0, 100)}).ValidateBasic())
+	assert.NotNil((&BridgeForeignEvent{SourceChain: "ethereum", Recipient: recipient}).ValidateBasic())
+	assert.NotNil((&BridgeForeignEvent{SourceChain: "", Recipient: recipient, Coins: NewCoins(0, 100)}).ValidateBasic())
+	assert.NotNil((&BridgeForeignEvent{SourceChain: "ethereum", Recipient: recipient, Coins: NewCoins(0, 100), Amount: big.NewInt(1)}).ValidateBasic())
+
+	// The wrapped assets are distinct per chain and token
+	token := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	assert.NotEqual(WrappedAssetID("ethereum", common.Address{}), WrappedAssetID("ethereum", token))
+	assert.NotEqual(WrappedAssetID("ethereum", token), WrappedAssetID("bsc", token))
+}
+
+func TestVerifyForeignProof(t *testing.T) {
+	assert := assert.New(t)
+
+	val1, val2, val3, outsider := MakeAcc("val1"), MakeAcc("val2"), MakeAcc("val3"), MakeAcc("outsider")
+	vcp := &core.ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(val1.Address, val1.Address, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(5))))
+	assert.Nil(vcp.DepositStake(val2.Address, val2.Address, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(3))))
+	assert.Nil(vcp.DepositStake(val3.Address, val3.Address, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(2))))
+	validators := BridgeValidators(vcp)
+	assert.Equal(3, len(validators))
+
+	chainID := "pandonet"
+	event := BridgeForeignEvent{
+		SourceChain: "ethereum",
+		TxHash:      common.HexToHash("0x1"),
+		Recipient:   outsider.Address,
+		Amount:      big.NewInt(100),
+	}
+	attest := func(signers ...PrivAccount) *BridgeForeignProof {
+		proof := &BridgeForeignProof{Event: event}
+		for _, signer := range signers {
+			proof.Attestations = append(proof.Attestations, signer.Sign(event.SignBytes(chainID)))
+		}
+		return proof
+	}
+
+	// 80% of the stake attests the event
+	assert.Nil(VerifyForeignProof(chainID, attest(val1, val2), validators, DefaultBridgeAttestationThreshold))
+
+	// 50% or 70% of the stake is not enough for the default threshold of 2/3
+	assert.NotNil(VerifyForeignProof(chainID, attest(val1), validators, DefaultBridgeAttestationThreshold))
+	assert.NotNil(VerifyForeignProof(chainID, attest(val1, val3), validators, 7500))
+	assert.Nil(VerifyForeignProof(chainID, attest(val1, val3), validators, 7000))
+
+	// The attestations need to be of distinct bridge validators, for the same network
+	assert.NotNil(VerifyForeignProof(chainID, attest(val1, val1), validators, DefaultBridgeAttestationThreshold))
+	assert.NotNil(VerifyForeignProof(chainID, attest(val1, outsider), validators, 5000))
+	assert.NotNil(VerifyForeignProof("testnet", attest(val1, val2), validators, DefaultBridgeAttestationThreshold))
+
+	// The attestations cannot be reused for another event
+	proof := attest(val1, val2, val3)
+	proof.Event.Amount = big.NewInt(1000)
+	assert.NotNil(VerifyForeignProof(chainID, proof, validators, DefaultBridgeAttestationThreshold))
+
+	proof = attest(val1, val2)
+	proof.Attestations = append(proof.Attestations, &crypto.Signature{})
+	assert.NotNil(VerifyForeignProof(chainID, proof, validators, DefaultBridgeAttestationThreshold))
+	assert.NotNil(VerifyForeignProof(chainID, attest(), []*core.StakeHolder{}, DefaultBridgeAttestationThreshold))
+}
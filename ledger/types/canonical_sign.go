@@ -0,0 +1,90 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/pandotoken/pando/common"
+)
+
+/*
+Canonical JSON signing.
+
+Besides the RLP encoded SignBytes and the TypedSignBytes, a transaction can be signed over its
+CanonicalSignBytes, a deterministic JSON document which integrators can produce and audit
+without an RLP encoder, e.g.
+
+	{"chainId":"pandonet","tx":{"fee":{"pandoWei":"0","ptxWei":"1000000000000"},...},"txType":"SendTx","version":"1"}
+
+The document is encoded with the following rules:
+
+	- the object keys are sorted in byte order, and there is no insignificant whitespace
+	- the field names and the excluded signature fields are the same as for typed signing
+	- integers of any size, including the coin amounts, are decimal strings
+	- addresses are EIP-55 checksummed hex strings, hashes and bytes are 0x prefixed hex strings
+	- strings are escaped as by encoding/json, without escaping the HTML characters
+	- a nil struct is an empty object, and a nil slice is an empty array
+
+The version identifies the encoding rules. Only CanonicalSignVersion is accepted, so that the
+rules can be changed later without ambiguity about what a signature was over.
+*/
+
+// CanonicalSignVersion is the version of the canonical JSON encoding rules
+const CanonicalSignVersion = 1
+
+// CanonicalSignData is the canonical JSON signing payload of a transaction
+type CanonicalSignData struct {
+	ChainID string                 `json:"chainId"`
+	Tx      map[string]interface{} `json:"tx"`
+	TxType  string                 `json:"txType"`
+	Version string                 `json:"version"`
+}
+
+// NewCanonicalSignData returns the canonical JSON signing payload of the transaction
+func NewCanonicalSignData(chainID string, tx Tx) (*CanonicalSignData, error) {
+	if _, err := GetTxType(tx); err != nil {
+		return nil, err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(tx))
+	types := map[string][]TypedDataField{}
+	primaryType, err := collectTypedTypes(v.Type(), types)
+	if err != nil {
+		return nil, err
+	}
+	message, ok := typedMessageValue(v, primaryType, types).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unsupported tx type: %v", v.Type())
+	}
+
+	return &CanonicalSignData{
+		ChainID: chainID,
+		Tx:      message,
+		TxType:  primaryType,
+		Version: strconv.Itoa(CanonicalSignVersion),
+	}, nil
+}
+
+// Encode returns the canonical JSON encoding of the payload
+func (d *CanonicalSignData) Encode() (common.Bytes, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(d); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// CanonicalSignBytes returns the bytes to sign for the canonical JSON signing of the transaction,
+// i.e. the canonical JSON encoding of its signing payload
+func CanonicalSignBytes(chainID string, tx Tx) (common.Bytes, error) {
+	data, err := NewCanonicalSignData(chainID, tx)
+	if err != nil {
+		return nil, err
+	}
+	return data.Encode()
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalSignBytes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := "test_chain_id"
+	tx := &SendTx{
+		Fee: NewCoins(0, 1000000000000),
+		Inputs: []TxInput{{
+			Address:  common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			Coins:    Coins{PandoWei: new(big.Int).SetUint64(1e18), PTXWei: big.NewInt(1000000000000)},
+			Sequence: 3,
+		}},
+		Outputs: []TxOutput{{
+			Address: common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			Coins:   Coins{PandoWei: new(big.Int).SetUint64(1e18), PTXWei: nil},
+		}},
+		Data: common.Bytes("<memo>"),
+	}
+
+	signBytes, err := CanonicalSignBytes(chainID, tx)
+	require.Nil(err)
+	assert.Equal(`{"chainId":"test_chain_id","tx":{"data":"0x3c6d656d6f3e",`+
+		`"fee":{"pandoWei":"0","ptxWei":"1000000000000"},`+
+		`"inputs":[{"address":"0x1111111111111111111111111111111111111111","coins":{"pandoWei":"1000000000000000000","ptxWei":"1000000000000"},"sequence":"3"}],`+
+		`"outputs":[{"address":"0x2222222222222222222222222222222222222222","coins":{"pandoWei":"1000000000000000000","ptxWei":"0"}}],"validUntilBlock":"0"},`+
+		`"txType":"SendTx","version":"1"}`, string(signBytes))
+
+	// The payload is valid JSON, and the signatures are not part of it
+	var decoded map[string]interface{}
+	require.Nil(json.Unmarshal(signBytes, &decoded))
+	acc := MakeAcc("signer")
+	tx.SetSignature(tx.Inputs[0].Address, acc.Sign(signBytes))
+	signBytes2, err := CanonicalSignBytes(chainID, tx)
+	require.Nil(err)
+	assert.Equal(signBytes, signBytes2)
+
+	// The payload separates the chains, and differs from the RLP and typed sign bytes
+	signBytes3, err := CanonicalSignBytes("other_chain_id", tx)
+	require.Nil(err)
+	assert.NotEqual(signBytes, signBytes3)
+	typedSignBytes, err := TypedSignBytes(chainID, tx)
+	require.Nil(err)
+	assert.NotEqual(signBytes, typedSignBytes)
+	assert.NotEqual(signBytes, common.Bytes(tx.SignBytes(chainID)))
+}
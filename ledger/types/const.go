@@ -71,4 +71,174 @@ const (
 
 	// ReservedFundFreezePeriodDuration indicates the freeze duration (in terms of number of blocks) of the reserved fund
 	ReservedFundFreezePeriodDuration uint64 = 5
+
+	// ServicePaymentDisputeWindow indicates the duration (in terms of number of blocks) after the first settlement
+	// of a target, during which a service payment with a higher payment sequence overrides the settlement
+	ServicePaymentDisputeWindow uint64 = 100
+)
+
+const (
+
+	// MaximumSessionKeysPerAccount gives the maximum number of session keys an account can register
+	MaximumSessionKeysPerAccount int = 16
+
+	// MaximumSessionKeyDestinations gives the maximum number of destinations a session key can be restricted to
+	MaximumSessionKeyDestinations int = 64
+
+	// MaximumSessionKeyDuration indicates the maximum duration (in terms of number of blocks) of a session key
+	MaximumSessionKeyDuration uint64 = 30 * 14400 // approximately 30 days with 6 second block time
+)
+
+const (
+
+	// KeyRotationGracePeriod indicates the duration (in terms of number of blocks) after a key rotation, during
+	// which the previous key of the account is still accepted
+	KeyRotationGracePeriod uint64 = 7 * 14400 // approximately 7 days with 6 second block time
+)
+
+const (
+
+	// HTLCPreimageLength gives the length (in bytes) of the preimage revealed to claim an HTLC. The length is fixed
+	// so that a preimage accepted on Pando is also accepted by the HTLCs on the other chain of a swap
+	HTLCPreimageLength int = 32
+
+	// MaximumHTLCDuration indicates the maximum duration (in terms of number of blocks) between the creation of an
+	// HTLC and the expiry of its time lock
+	MaximumHTLCDuration uint64 = 30 * 14400 // approximately 30 days with 6 second block time
+)
+
+const (
+
+	// MaximumBridgeChainIDLength gives the maximum length (in bytes) of the ID of a foreign chain of the bridge
+	MaximumBridgeChainIDLength int = 32
+
+	// BridgeValidatorCount gives the number of top stake holders which attest the foreign bridge events. It
+	// matches the size of the validator set, so that the bridge validators are the validators.
+	BridgeValidatorCount int = 31
+
+	// DefaultBridgeAttestationThreshold specifies the default share of the stake of the bridge validators (in
+	// basis points) which needs to attest a foreign event before its assets are minted or released
+	DefaultBridgeAttestationThreshold uint64 = 6667
+)
+
+const (
+
+	// MaximumSubchainChainIDLength gives the maximum length (in bytes) of the chain ID of a subchain
+	MaximumSubchainChainIDLength int = 64
+
+	// MinimumSubchainDepositPTX specifies the minimum deposit (in PTX) locked by the registration of a subchain
+	MinimumSubchainDepositPTX uint64 = 1000
+
+	// MinimumSubchainStakePando specifies the minimum stake (in Pando) of a subchain validator
+	MinimumSubchainStakePando uint64 = 100
+
+	// MaximumSubchainCheckpointInterval gives the maximum number of subchain blocks between two checkpoints
+	MaximumSubchainCheckpointInterval uint64 = 100000
+
+	// MaximumSubchainStakers gives the maximum number of validators of a subchain
+	MaximumSubchainStakers int = 64
+
+	// SubchainFraudProofWindow indicates the number of blocks after a subchain checkpoint is anchored within
+	// which it can be challenged, and after which a withdrawn subchain stake is returned
+	SubchainFraudProofWindow uint64 = 14400 // approximately 1 day with 6 second block time
+
+	// MaximumPendingSubchainCheckpoints gives the maximum number of checkpoints of a subchain within their
+	// fraud proof window
+	MaximumPendingSubchainCheckpoints int = 32
+
+	// SubchainChallengeRewardPercentage specifies the percentage of the slashed stakes paid to the challenger,
+	// the rest is burnt
+	SubchainChallengeRewardPercentage int64 = 10
+)
+
+const (
+
+	// ParamChangeActivationDelay indicates the delay (in terms of number of blocks) between the announcement
+	// of a governance parameter change and its activation
+	ParamChangeActivationDelay uint64 = 28800 // approximately 2 days with 6 second block time
+)
+
+const (
+
+	// GovernanceVotingPeriod indicates the minimum number of blocks the stakers can vote on a governance
+	// proposal. The proposal is tallied at the first checkpoint after the voting period.
+	GovernanceVotingPeriod uint64 = 100800 // approximately 7 days with 6 second block time
+
+	// GovernanceQuorumPercent specifies the share of the stake (in percent) which needs to vote on a
+	// governance proposal for the vote to count
+	GovernanceQuorumPercent int64 = 33
+
+	// GovernanceThresholdPercent specifies the share of the voting stake (in percent) which needs to be
+	// exceeded by the approving stake for a governance proposal to pass
+	GovernanceThresholdPercent int64 = 66
+
+	// MinimumGovernanceDepositPTX specifies the minimum deposit locked by a governance proposal, in PTX
+	MinimumGovernanceDepositPTX uint64 = 1000
+
+	// MaxPendingGovernanceProposals bounds the number of governance proposals pending at the same time
+	MaxPendingGovernanceProposals int = 32
+)
+
+const (
+
+	// SlashAppealWindow indicates the number of blocks after a slash within which the slashed account
+	// can appeal, and the number of blocks after the appeal is filed within which the validators can approve it
+	SlashAppealWindow uint64 = 100800 // approximately 7 days with 6 second block time
+
+	// MinimumSlashAppealBondPTXWei specifies the minimum bond locked by a slash appeal
+	MinimumSlashAppealBondPTXWei uint64 = 1e19 // 10 PTX
+)
+
+const (
+
+	// DoubleSignEvidenceMaxAge indicates the number of blocks after the conflicting blocks within which
+	// the validator who double signed them can be slashed
+	DoubleSignEvidenceMaxAge uint64 = 100800 // approximately 7 days with 6 second block time
+
+	// DoubleSignSlashPercentage specifies the percentage of each stake of the validator burnt for double signing
+	DoubleSignSlashPercentage int64 = 5
+)
+
+const (
+
+	// MaximumMultiSigSigners gives the maximum number of signers of a multi-signature account
+	MaximumMultiSigSigners int = 16
+)
+
+const (
+
+	// MaximumBatchSendTxOutputs gives the maximum number of outputs of a BatchSendTx
+	MaximumBatchSendTxOutputs int = 1024
+
+	// MaximumBatchSendTxMemoLength gives the maximum length (in bytes) of the memo of a BatchSendTx output
+	MaximumBatchSendTxMemoLength int = 128
+
+	// MinimumBatchSendTxFeePerOutputPTXWei specifies the minimum fee per output of a BatchSendTx,
+	// charged on top of the minimum transaction fee
+	MinimumBatchSendTxFeePerOutputPTXWei uint64 = 1e11
+)
+
+const (
+
+	// MaximumSendTxDataLength gives the maximum length (in bytes) of the data attached to a SendTx
+	MaximumSendTxDataLength int = 1024
+
+	// MinimumSendTxFeePerDataBytePTXWei specifies the minimum fee per byte of the data attached to a SendTx,
+	// charged on top of the minimum transaction fee
+	MinimumSendTxFeePerDataBytePTXWei uint64 = 1e9
+)
+
+const (
+
+	// MaximumEscrowClauses gives the maximum number of clauses of an escrow address
+	MaximumEscrowClauses int = 8
+
+	// MaximumEscrowPreimageLength gives the maximum length (in bytes) of the preimage revealed to claim an escrow
+	MaximumEscrowPreimageLength int = 256
+)
+
+const (
+
+	// MaximumMeteringRecords gives the maximum number of metering records settled by a MeteredSettlementTx
+	MaximumMeteringRecords int = 256
 )
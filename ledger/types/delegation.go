@@ -0,0 +1,111 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// Delegation records the stake a token holder delegated to a validator with a DelegateTx, and the
+// block rewards attributed to the delegation so far
+type Delegation struct {
+	Validator common.Address
+	Amount    *big.Int // the delegated stake, in PandoWei
+	Rewards   *big.Int // the rewards attributed to the delegation, in PTXWei
+	Height    uint64   // height of the last DelegateTx to the validator
+}
+
+type DelegationJSON struct {
+	Validator common.Address    `json:"validator"`
+	Amount    *common.JSONBig   `json:"amount"`
+	Rewards   *common.JSONBig   `json:"rewards"`
+	Height    common.JSONUint64 `json:"height"`
+}
+
+func NewDelegationJSON(a Delegation) DelegationJSON {
+	return DelegationJSON{
+		Validator: a.Validator,
+		Amount:    (*common.JSONBig)(a.Amount),
+		Rewards:   (*common.JSONBig)(a.Rewards),
+		Height:    common.JSONUint64(a.Height),
+	}
+}
+
+func (a DelegationJSON) Delegation() Delegation {
+	return Delegation{
+		Validator: a.Validator,
+		Amount:    (*big.Int)(a.Amount),
+		Rewards:   (*big.Int)(a.Rewards),
+		Height:    uint64(a.Height),
+	}
+}
+
+func (a Delegation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewDelegationJSON(a))
+}
+
+func (a *Delegation) UnmarshalJSON(data []byte) error {
+	var b DelegationJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.Delegation()
+	return nil
+}
+
+func (d *Delegation) String() string {
+	if d == nil {
+		return "nil-Delegation"
+	}
+	return fmt.Sprintf("Delegation{validator: %v, amount: %v, rewards: %v, height: %v}",
+		d.Validator, d.Amount, d.Rewards, d.Height)
+}
+
+// DelegationSet is the set of the delegations of a delegator, at most one per validator
+type DelegationSet struct {
+	Delegations []*Delegation
+}
+
+// Len returns the number of delegations
+func (s *DelegationSet) Len() int {
+	return len(s.Delegations)
+}
+
+// Get returns the delegation to the given validator, nil if not found
+func (s *DelegationSet) Get(validator common.Address) *Delegation {
+	for _, d := range s.Delegations {
+		if d.Validator == validator {
+			return d
+		}
+	}
+	return nil
+}
+
+// Add adds the amount to the delegation to the given validator, creating the delegation if needed
+func (s *DelegationSet) Add(validator common.Address, amount *big.Int, height uint64) *Delegation {
+	d := s.Get(validator)
+	if d == nil {
+		d = &Delegation{
+			Validator: validator,
+			Amount:    big.NewInt(0),
+			Rewards:   big.NewInt(0),
+		}
+		s.Delegations = append(s.Delegations, d)
+	}
+	d.Amount = new(big.Int).Add(d.Amount, amount)
+	d.Height = height
+	return d
+}
+
+// Remove removes the delegation to the given validator, and returns it, nil if not found
+func (s *DelegationSet) Remove(validator common.Address) *Delegation {
+	for i, d := range s.Delegations {
+		if d.Validator == validator {
+			s.Delegations = append(s.Delegations[:i], s.Delegations[i+1:]...)
+			return d
+		}
+	}
+	return nil
+}
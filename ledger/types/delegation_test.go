@@ -0,0 +1,40 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/pandotoken/pando/common"
+)
+
+func TestDelegationSet(t *testing.T) {
+	assert := assert.New(t)
+
+	va1 := common.HexToAddress("0x111")
+	va2 := common.HexToAddress("0x222")
+
+	set := &DelegationSet{}
+	assert.Nil(set.Get(va1))
+
+	set.Add(va1, big.NewInt(100), 10)
+	set.Add(va2, big.NewInt(50), 11)
+	d := set.Add(va1, big.NewInt(20), 12)
+	assert.Equal(2, set.Len())
+	assert.Equal(big.NewInt(120), d.Amount)
+	assert.Equal(uint64(12), d.Height)
+	assert.Equal(0, d.Rewards.Sign())
+
+	raw, err := ToBytes(set)
+	assert.Nil(err)
+	decoded := &DelegationSet{}
+	assert.Nil(FromBytes(raw, decoded))
+	assert.Equal(2, decoded.Len())
+	assert.Equal(big.NewInt(50), decoded.Get(va2).Amount)
+
+	removed := set.Remove(va1)
+	assert.Equal(big.NewInt(120), removed.Amount)
+	assert.Nil(set.Get(va1))
+	assert.Nil(set.Remove(va1))
+	assert.Equal(1, set.Len())
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// DoubleSignSlashRecord records the slash of a validator for double signing the blocks at a
+// height, so that the same double signing is not slashed twice
+type DoubleSignSlashRecord struct {
+	Offender       common.Address
+	EvidenceHeight uint64         // height of the conflicting blocks
+	Amount         *big.Int       // the burnt stake, in PandoWei
+	Reporter       common.Address // the proposer which included the evidence
+	Height         uint64         // height of the block which included the slash
+}
+
+type DoubleSignSlashRecordJSON struct {
+	Offender       common.Address    `json:"offender"`
+	EvidenceHeight common.JSONUint64 `json:"evidence_height"`
+	Amount         *common.JSONBig   `json:"amount"`
+	Reporter       common.Address    `json:"reporter"`
+	Height         common.JSONUint64 `json:"height"`
+}
+
+func NewDoubleSignSlashRecordJSON(a DoubleSignSlashRecord) DoubleSignSlashRecordJSON {
+	return DoubleSignSlashRecordJSON{
+		Offender:       a.Offender,
+		EvidenceHeight: common.JSONUint64(a.EvidenceHeight),
+		Amount:         (*common.JSONBig)(a.Amount),
+		Reporter:       a.Reporter,
+		Height:         common.JSONUint64(a.Height),
+	}
+}
+
+func (a DoubleSignSlashRecordJSON) DoubleSignSlashRecord() DoubleSignSlashRecord {
+	return DoubleSignSlashRecord{
+		Offender:       a.Offender,
+		EvidenceHeight: uint64(a.EvidenceHeight),
+		Amount:         (*big.Int)(a.Amount),
+		Reporter:       a.Reporter,
+		Height:         uint64(a.Height),
+	}
+}
+
+func (a DoubleSignSlashRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewDoubleSignSlashRecordJSON(a))
+}
+
+func (a *DoubleSignSlashRecord) UnmarshalJSON(data []byte) error {
+	var b DoubleSignSlashRecordJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.DoubleSignSlashRecord()
+	return nil
+}
+
+func (r *DoubleSignSlashRecord) String() string {
+	if r == nil {
+		return "nil-DoubleSignSlashRecord"
+	}
+	return fmt.Sprintf("DoubleSignSlashRecord{offender: %v, evidence_height: %v, amount: %v, reporter: %v, height: %v}",
+		r.Offender, r.EvidenceHeight, r.Amount, r.Reporter, r.Height)
+}
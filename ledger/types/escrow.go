@@ -0,0 +1,120 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// EscrowClause is a condition under which the coins held by an escrow address can be claimed.
+// The coins can only be paid to the recipient of the clause, hence revealing the preimage of
+// a hash lock does not allow anyone else to redirect the claim. A clause needs at least one of
+// the signature, hash lock and time lock conditions.
+type EscrowClause struct {
+	Recipient common.Address   `json:"recipient"` // receives the claimed coins
+	Threshold uint64           `json:"threshold"` // minimal number of signatures of the signers, 0 if none required
+	Signers   []common.Address `json:"signers"`   // addresses of the signers in ascending order
+	HashLock  common.Hash      `json:"hash_lock"` // Keccak256 hash of the preimage to reveal, empty if none
+	TimeLock  uint64           `json:"time_lock"` // minimal block height of the claim, 0 if none
+	Expiry    uint64           `json:"expiry"`    // maximal block height of the claim, 0 if none
+}
+
+// EscrowAddress returns the address of the escrow with the given clauses. The escrow does not
+// have a private key of its own, its coins can only be spent with a ClaimEscrowTx satisfying one
+// of the clauses. The address depends on the order of the clauses.
+func EscrowAddress(clauses []EscrowClause) common.Address {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"escrow", clauses})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the escrow clauses: %v", err))
+	}
+	return common.BytesToAddress(crypto.Keccak256(encoded)[12:])
+}
+
+// HashLockOf returns the hash lock for the given preimage
+func HashLockOf(preimage common.Bytes) common.Hash {
+	return crypto.Keccak256Hash(preimage)
+}
+
+// ValidateEscrowClauses checks the number of clauses and each of the clauses
+func ValidateEscrowClauses(clauses []EscrowClause) error {
+	if len(clauses) == 0 || len(clauses) > MaximumEscrowClauses {
+		return fmt.Errorf("Invalid number of clauses %v, needs to be between 1 and %v", len(clauses), MaximumEscrowClauses)
+	}
+	for i, clause := range clauses {
+		if err := clause.ValidateBasic(); err != nil {
+			return fmt.Errorf("Invalid clause %v: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateBasic checks the conditions of the clause are well formed
+func (c *EscrowClause) ValidateBasic() error {
+	numSigners := len(c.Signers)
+	if numSigners > MaximumMultiSigSigners {
+		return fmt.Errorf("Too many signers %v, at most %v", numSigners, MaximumMultiSigSigners)
+	}
+	if c.Threshold > uint64(numSigners) || (c.Threshold == 0 && numSigners > 0) {
+		return fmt.Errorf("Invalid threshold %v, needs to be between 1 and %v", c.Threshold, numSigners)
+	}
+	for i := 1; i < numSigners; i++ {
+		if bytes.Compare(c.Signers[i-1][:], c.Signers[i][:]) >= 0 {
+			return errors.New("Signers need to be unique and in ascending order")
+		}
+	}
+	if c.Threshold == 0 && c.HashLock == (common.Hash{}) && c.TimeLock == 0 {
+		return errors.New("The clause needs at least one of the signature, hash lock and time lock conditions")
+	}
+	if c.Expiry != 0 && c.Expiry < c.TimeLock {
+		return fmt.Errorf("The expiry %v is before the time lock %v", c.Expiry, c.TimeLock)
+	}
+	return nil
+}
+
+// Verify checks that the claim at the given block height satisfies the clause, with the revealed
+// preimage and the signatures of the signers over one of the messages
+func (c *EscrowClause) Verify(blockHeight uint64, preimage common.Bytes, sigs []*crypto.Signature, msgs ...common.Bytes) error {
+	if blockHeight < c.TimeLock {
+		return fmt.Errorf("The clause cannot be claimed until height %v", c.TimeLock)
+	}
+	if c.Expiry != 0 && blockHeight > c.Expiry {
+		return fmt.Errorf("The clause expired at height %v", c.Expiry)
+	}
+	if c.HashLock != (common.Hash{}) && HashLockOf(preimage) != c.HashLock {
+		return errors.New("The preimage does not match the hash lock")
+	}
+	if c.HashLock == (common.Hash{}) && len(preimage) != 0 {
+		return errors.New("The clause has no hash lock, but a preimage is revealed")
+	}
+	if c.Threshold == 0 {
+		if len(sigs) != 0 {
+			return errors.New("The clause requires no signatures, but signatures are attached")
+		}
+		return nil
+	}
+	ms := &MultiSigSignature{
+		Threshold:  c.Threshold,
+		Signers:    c.Signers,
+		Signatures: sigs,
+	}
+	return ms.Verify(ms.Address(), msgs...)
+}
+
+// IsSigner indicates whether the address is one of the signers of the clause
+func (c *EscrowClause) IsSigner(addr common.Address) bool {
+	for _, signer := range c.Signers {
+		if signer == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (c EscrowClause) String() string {
+	return fmt.Sprintf("EscrowClause{recipient: %v, threshold: %v, signers: %v, hash_lock: %v, time_lock: %v, expiry: %v}",
+		c.Recipient.Hex(), c.Threshold, c.Signers, c.HashLock.Hex(), c.TimeLock, c.Expiry)
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscrowClauses(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := MakeAcc("alice")
+	bob := MakeAcc("bob")
+	preimage := common.Bytes("secret")
+	signers := sortSigners([]common.Address{alice.Address, bob.Address})
+
+	htlc := []EscrowClause{
+		EscrowClause{Recipient: bob.Address, HashLock: HashLockOf(preimage), Expiry: 200},
+		EscrowClause{Recipient: alice.Address, TimeLock: 201},
+	}
+	require.Nil(ValidateEscrowClauses(htlc))
+
+	// The address depends on every clause and on their order
+	addr := EscrowAddress(htlc)
+	assert.NotEqual(addr, EscrowAddress([]EscrowClause{htlc[1], htlc[0]}))
+	assert.NotEqual(addr, EscrowAddress(htlc[:1]))
+
+	// Malformed clauses
+	assert.NotNil(ValidateEscrowClauses(nil))
+	assert.NotNil((&EscrowClause{Recipient: bob.Address}).ValidateBasic())
+	assert.NotNil((&EscrowClause{Recipient: bob.Address, Threshold: 1}).ValidateBasic())
+	assert.NotNil((&EscrowClause{Recipient: bob.Address, Signers: signers}).ValidateBasic())
+	assert.NotNil((&EscrowClause{Recipient: bob.Address, Threshold: 1, Signers: []common.Address{signers[1], signers[0]}}).ValidateBasic())
+	assert.NotNil((&EscrowClause{Recipient: bob.Address, TimeLock: 100, Expiry: 99}).ValidateBasic())
+
+	// Hash and time locks
+	assert.Nil(htlc[0].Verify(200, preimage, nil))
+	assert.NotNil(htlc[0].Verify(201, preimage, nil))
+	assert.NotNil(htlc[0].Verify(100, common.Bytes("guess"), nil))
+	assert.NotNil(htlc[1].Verify(200, nil, nil))
+	assert.Nil(htlc[1].Verify(201, nil, nil))
+	assert.NotNil(htlc[1].Verify(201, preimage, nil))
+
+	// Signatures
+	msg := common.Bytes("claim")
+	multiSig := EscrowClause{Recipient: bob.Address, Threshold: 2, Signers: signers}
+	require.Nil(multiSig.ValidateBasic())
+	assert.NotNil(multiSig.Verify(1, nil, nil, msg))
+	assert.NotNil(multiSig.Verify(1, nil, []*crypto.Signature{alice.Sign(msg)}, msg))
+	assert.Nil(multiSig.Verify(1, nil, []*crypto.Signature{alice.Sign(msg), bob.Sign(msg)}, msg))
+	assert.NotNil(htlc[0].Verify(1, preimage, []*crypto.Signature{alice.Sign(msg)}, msg))
+}
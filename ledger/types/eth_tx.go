@@ -0,0 +1,182 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+/*
+Ethereum transaction compatibility.
+
+A SmartContractTx can also be signed as a legacy Ethereum transaction with EIP-155 replay
+protection, so that it can be produced by Ethereum wallets and tooling. The sign bytes are
+
+	rlp([nonce, gasPrice, gasLimit, to, value, data, ethChainID, 0, 0])
+
+where the nonce is the sequence of the transaction minus one (Ethereum nonces start from 0),
+the value is in PTXWei, the recipient is empty for contract creation, and ethChainID is the
+chain ID returned by MapChainID. Such a transaction is converted from and to the raw Ethereum
+transaction without loss, hence it can be identified by the Ethereum transaction hash too.
+*/
+
+var (
+	ErrEthTxMalformed        = errors.New("Malformed Ethereum transaction")
+	ErrEthTxInvalidChainID   = errors.New("Invalid chain ID of the Ethereum transaction")
+	ErrEthTxInvalidSignature = errors.New("Invalid signature of the Ethereum transaction")
+)
+
+// MapChainID returns the Ethereum chain ID of the chain, which is also the value of the
+// CHAINID opcode. To be compatible with Ethereum, it returns 1 for "mainnet".
+// Reference: https://github.com/ethereum/go-ethereum/blob/43cd31ea9f57e26f8f67aa8bd03bbb0a50814465/params/config.go#L55
+func MapChainID(chainIDStr string) *big.Int {
+	if chainIDStr == "mainnet" { // correspond to the Ethereum mainnet
+		return big.NewInt(1)
+	} else if chainIDStr == "testnet" {
+		return big.NewInt(7)
+	} else if chainIDStr == "pandonet" {
+		return big.NewInt(8)
+	}
+
+	chainIDBigInt := new(big.Int).Abs(crypto.Keccak256Hash(common.Bytes(chainIDStr)).Big()) // all other chainIDs
+	return chainIDBigInt
+}
+
+// ethTx is the RLP layout of a signed legacy Ethereum transaction
+type ethTx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	GasLimit uint64
+	To       common.Bytes // empty for contract creation
+	Value    *big.Int
+	Data     common.Bytes
+	V        *big.Int
+	R        *big.Int
+	S        *big.Int
+}
+
+// EthSignBytes returns the EIP-155 sign bytes of the transaction, or nil if the transaction
+// cannot be expressed as an Ethereum transaction
+func EthSignBytes(chainID string, tx *SmartContractTx) []byte {
+	if tx.From.Sequence == 0 || tx.GasPrice == nil {
+		return nil
+	}
+	coins := tx.From.Coins.NoNil()
+	if coins.PandoWei.Sign() != 0 || coins.PTXWei.Sign() < 0 {
+		return nil // Ethereum transactions only carry the EVM native currency
+	}
+	signBytes, err := rlp.EncodeToBytes([]interface{}{
+		tx.From.Sequence - 1,
+		tx.GasPrice,
+		tx.GasLimit,
+		ethRecipient(tx),
+		coins.PTXWei,
+		[]byte(tx.Data),
+		MapChainID(chainID),
+		uint(0),
+		uint(0),
+	})
+	if err != nil {
+		return nil
+	}
+	return signBytes
+}
+
+func ethRecipient(tx *SmartContractTx) common.Bytes {
+	if (tx.To.Address == common.Address{}) {
+		return common.Bytes{}
+	}
+	return tx.To.Address.Bytes()
+}
+
+// IsEthSigned indicates whether the transaction is signed over its Ethereum sign bytes
+func IsEthSigned(chainID string, tx *SmartContractTx) bool {
+	signBytes := EthSignBytes(chainID, tx)
+	return signBytes != nil && tx.From.Signature.Verify(signBytes, tx.From.Address)
+}
+
+// SmartContractTxFromEthTx converts a signed raw Ethereum transaction into a SmartContractTx
+func SmartContractTxFromEthTx(chainID string, raw common.Bytes) (*SmartContractTx, error) {
+	etx := &ethTx{}
+	if err := rlp.DecodeBytes(raw, etx); err != nil {
+		return nil, ErrEthTxMalformed
+	}
+	if len(etx.To) != 0 && len(etx.To) != common.AddressLength {
+		return nil, ErrEthTxMalformed
+	}
+
+	// Only the EIP-155 signatures are accepted, i.e. v = chainID * 2 + 35 + recid
+	ethChainID := MapChainID(chainID)
+	recid := new(big.Int).Sub(etx.V, new(big.Int).Add(new(big.Int).Lsh(ethChainID, 1), big.NewInt(35)))
+	if recid.Sign() < 0 || recid.Cmp(common.Big1) > 0 {
+		return nil, ErrEthTxInvalidChainID
+	}
+	if !crypto.ValidateSignatureValues(byte(recid.Uint64()), etx.R, etx.S, true) {
+		return nil, ErrEthTxInvalidSignature
+	}
+	sigBytes := make([]byte, 65)
+	copy(sigBytes[32-len(etx.R.Bytes()):32], etx.R.Bytes())
+	copy(sigBytes[64-len(etx.S.Bytes()):64], etx.S.Bytes())
+	sigBytes[64] = byte(recid.Uint64())
+	sig, err := crypto.SignatureFromBytes(sigBytes)
+	if err != nil {
+		return nil, ErrEthTxInvalidSignature
+	}
+
+	tx := &SmartContractTx{
+		From: TxInput{
+			Coins:     Coins{PandoWei: big.NewInt(0), PTXWei: etx.Value},
+			Sequence:  etx.Nonce + 1,
+			Signature: sig,
+		},
+		To:       TxOutput{Address: common.BytesToAddress(etx.To)},
+		GasLimit: etx.GasLimit,
+		GasPrice: etx.GasPrice,
+		Data:     etx.Data,
+	}
+	signBytes := EthSignBytes(chainID, tx)
+	if signBytes == nil {
+		return nil, ErrEthTxMalformed
+	}
+	tx.From.Address, err = sig.RecoverSignerAddress(signBytes)
+	if err != nil {
+		return nil, ErrEthTxInvalidSignature
+	}
+	return tx, nil
+}
+
+// EthTxFromSmartContractTx converts a transaction signed over its Ethereum sign bytes back
+// into the raw Ethereum transaction
+func EthTxFromSmartContractTx(chainID string, tx *SmartContractTx) (common.Bytes, error) {
+	if !IsEthSigned(chainID, tx) {
+		return nil, fmt.Errorf("The transaction is not signed as an Ethereum transaction")
+	}
+	sigBytes := tx.From.Signature.ToBytes()
+	v := new(big.Int).Lsh(MapChainID(chainID), 1)
+	v.Add(v, big.NewInt(35+int64(sigBytes[64])))
+	return rlp.EncodeToBytes(ethTx{
+		Nonce:    tx.From.Sequence - 1,
+		GasPrice: tx.GasPrice,
+		GasLimit: tx.GasLimit,
+		To:       ethRecipient(tx),
+		Value:    tx.From.Coins.NoNil().PTXWei,
+		Data:     tx.Data,
+		V:        v,
+		R:        new(big.Int).SetBytes(sigBytes[:32]),
+		S:        new(big.Int).SetBytes(sigBytes[32:64]),
+	})
+}
+
+// EthTxHash returns the Ethereum transaction hash of the transaction, if it is signed over
+// its Ethereum sign bytes
+func EthTxHash(chainID string, tx *SmartContractTx) (common.Hash, bool) {
+	raw, err := EthTxFromSmartContractTx(chainID, tx)
+	if err != nil {
+		return common.Hash{}, false
+	}
+	return crypto.Keccak256Hash(raw), true
+}
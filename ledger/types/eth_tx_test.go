@@ -0,0 +1,78 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmartContractTxFromEthTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// The example transaction of EIP-155, signed with the chain ID 1
+	raw := common.Hex2Bytes("f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83")
+
+	tx, err := SmartContractTxFromEthTx("mainnet", raw)
+	require.Nil(err)
+	assert.Equal(common.HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"), tx.From.Address)
+	assert.Equal(uint64(10), tx.From.Sequence)
+	assert.Equal(common.HexToAddress("0x3535353535353535353535353535353535353535"), tx.To.Address)
+	assert.Equal(uint64(21000), tx.GasLimit)
+	assert.Equal(big.NewInt(20000000000), tx.GasPrice)
+	assert.Equal(new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e9)), tx.From.Coins.PTXWei)
+	assert.True(IsEthSigned("mainnet", tx))
+
+	ethRaw, err := EthTxFromSmartContractTx("mainnet", tx)
+	require.Nil(err)
+	assert.Equal(raw, []byte(ethRaw))
+	hash, ok := EthTxHash("mainnet", tx)
+	assert.True(ok)
+	assert.Equal(crypto.Keccak256Hash(raw), hash)
+
+	// The signature binds the chain ID
+	_, err = SmartContractTxFromEthTx("testnet", raw)
+	assert.Equal(ErrEthTxInvalidChainID, err)
+	assert.False(IsEthSigned("testnet", tx))
+
+	_, err = SmartContractTxFromEthTx("mainnet", raw[:len(raw)-1])
+	assert.Equal(ErrEthTxMalformed, err)
+}
+
+func TestEthSignedSmartContractTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privAcc := PrivAccountFromSecret("eth_signer")
+	tx := &SmartContractTx{
+		From:     NewTxInput(privAcc.Address, NewCoins(0, 0), 1),
+		GasLimit: 100000,
+		GasPrice: big.NewInt(4000000000000),
+		Data:     common.Hex2Bytes("6080"),
+	}
+	assert.False(IsEthSigned(chainID, tx))
+
+	// Contract creation, signed by an Ethereum wallet
+	sig, err := privAcc.PrivKey.Sign(EthSignBytes(chainID, tx))
+	require.Nil(err)
+	tx.From.Signature = sig
+	assert.True(IsEthSigned(chainID, tx))
+	assert.False(tx.From.Signature.Verify(tx.SignBytes(chainID), privAcc.Address))
+
+	raw, err := EthTxFromSmartContractTx(chainID, tx)
+	require.Nil(err)
+	converted, err := SmartContractTxFromEthTx(chainID, raw)
+	require.Nil(err)
+	assert.Equal(tx.From.Address, converted.From.Address)
+	assert.Equal(common.Address{}, converted.To.Address)
+	assert.Equal(tx.SignBytes(chainID), converted.SignBytes(chainID))
+
+	// Transactions carrying Pando cannot be expressed as Ethereum transactions
+	tx.From.Coins = NewCoins(1, 0)
+	assert.Nil(EthSignBytes(chainID, tx))
+	assert.False(IsEthSigned(chainID, tx))
+}
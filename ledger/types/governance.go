@@ -0,0 +1,276 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// ParamMinimumTxFee is the governance parameter for the lowest base fee, i.e. the floor of the
+// minimum fee of a regular transaction. It can only raise the floor above MinimumTransactionFeePTXWei.
+const ParamMinimumTxFee = "MinimumTxFee"
+
+// ParamMinValidatorStakeDeposit is the governance parameter for the minimum stake of a validator
+// deposit. It can only raise the minimum above core.MinValidatorStakeDeposit.
+const ParamMinValidatorStakeDeposit = "MinValidatorStakeDeposit"
+
+// GovernableParam is a parameter the stakers can change with a governance proposal, within bounds
+// which keep the chain operable whatever the outcome of the vote
+type GovernableParam struct {
+	Name string
+	Min  *big.Int
+	Max  *big.Int
+}
+
+// GovernableParams lists the parameters which can be changed with a governance proposal
+func GovernableParams() []GovernableParam {
+	minimumFee := new(big.Int).SetUint64(MinimumTransactionFeePTXWei)
+	return []GovernableParam{
+		{ParamMinimumTxFee, minimumFee, new(big.Int).Mul(minimumFee, big.NewInt(1000))},
+		{ParamBlockGasLimit, new(big.Int).SetUint64(MaximumTxGasLimit), big.NewInt(1e9)},
+		{ParamBlockTxsSizeLimit, big.NewInt(1024 * 1024), new(big.Int).SetUint64(DefaultBlockTxsSizeLimit)},
+		{ParamMinValidatorStakeDeposit, core.MinValidatorStakeDeposit, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(100))},
+		{ParamStakeUnbondingPeriod, new(big.Int).SetUint64(ParamChangeActivationDelay), new(big.Int).SetUint64(2 * core.ReturnLockingPeriod)},
+		{ParamMaxCommissionRateChange, big.NewInt(0), new(big.Int).SetUint64(MaxCommissionRate)},
+		{ParamBridgeAttestationThreshold, big.NewInt(5001), big.NewInt(10000)},
+	}
+}
+
+// CheckGovernableParam checks whether the parameter can be set to the value by a governance proposal
+func CheckGovernableParam(name string, value *big.Int) error {
+	for _, param := range GovernableParams() {
+		if param.Name != name {
+			continue
+		}
+		if value == nil || value.Cmp(param.Min) < 0 || value.Cmp(param.Max) > 0 {
+			return fmt.Errorf("The value of %v needs to be between %v and %v", name, param.Min, param.Max)
+		}
+		return nil
+	}
+	return fmt.Errorf("%v cannot be changed by a governance proposal", name)
+}
+
+// MinimumGovernanceDeposit returns the minimum deposit locked by a governance proposal, in PTXWei
+func MinimumGovernanceDeposit() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(MinimumGovernanceDepositPTX), big.NewInt(1e18))
+}
+
+// GovernanceProposalID returns the ID of the proposal submitted by the proposer with the transaction
+// of the given sequence
+func GovernanceProposalID(proposer common.Address, sequence uint64) common.Hash {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"governance", proposer, sequence})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the governance proposal ID: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// GovernanceTallyHeight returns the height at which a proposal submitted at the given height is
+// tallied, i.e. the first checkpoint at least GovernanceVotingPeriod blocks later
+func GovernanceTallyHeight(submitHeight uint64) uint64 {
+	height := submitHeight + GovernanceVotingPeriod
+	interval := uint64(common.CheckpointInterval)
+	tallyHeight := height/interval*interval + 1
+	if tallyHeight < height {
+		tallyHeight += interval
+	}
+	return tallyHeight
+}
+
+// GovernanceVote is the vote of a staker on a proposal
+type GovernanceVote struct {
+	Voter   common.Address `json:"voter"`
+	Approve bool           `json:"approve"`
+}
+
+// GovernanceProposal is a pending proposal to change a governance parameter. The stakers of the
+// validator candidate pool vote on it until it is tallied at the tally height. The proposal passes
+// if the voters hold at least GovernanceQuorumPercent of the stake, and the approving voters more
+// than GovernanceThresholdPercent of the voting stake. The parameter change is then scheduled like any other
+// (see ParamChange).
+type GovernanceProposal struct {
+	ID           common.Hash
+	Proposer     common.Address
+	Name         string   // name of the governance parameter
+	Value        *big.Int // proposed value
+	Deposit      Coins    // returned to the proposer if the quorum is reached, burned otherwise
+	SubmitHeight uint64
+	TallyHeight  uint64
+	Votes        []GovernanceVote
+}
+
+type GovernanceProposalJSON struct {
+	ID           common.Hash       `json:"id"`
+	Proposer     common.Address    `json:"proposer"`
+	Name         string            `json:"name"`
+	Value        *common.JSONBig   `json:"value"`
+	Deposit      Coins             `json:"deposit"`
+	SubmitHeight common.JSONUint64 `json:"submit_height"`
+	TallyHeight  common.JSONUint64 `json:"tally_height"`
+	Votes        []GovernanceVote  `json:"votes"`
+}
+
+func NewGovernanceProposalJSON(a GovernanceProposal) GovernanceProposalJSON {
+	return GovernanceProposalJSON{
+		ID:           a.ID,
+		Proposer:     a.Proposer,
+		Name:         a.Name,
+		Value:        (*common.JSONBig)(a.Value),
+		Deposit:      a.Deposit,
+		SubmitHeight: common.JSONUint64(a.SubmitHeight),
+		TallyHeight:  common.JSONUint64(a.TallyHeight),
+		Votes:        a.Votes,
+	}
+}
+
+func (a GovernanceProposalJSON) GovernanceProposal() GovernanceProposal {
+	return GovernanceProposal{
+		ID:           a.ID,
+		Proposer:     a.Proposer,
+		Name:         a.Name,
+		Value:        (*big.Int)(a.Value),
+		Deposit:      a.Deposit,
+		SubmitHeight: uint64(a.SubmitHeight),
+		TallyHeight:  uint64(a.TallyHeight),
+		Votes:        a.Votes,
+	}
+}
+
+func (a GovernanceProposal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewGovernanceProposalJSON(a))
+}
+
+func (a *GovernanceProposal) UnmarshalJSON(data []byte) error {
+	var b GovernanceProposalJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.GovernanceProposal()
+	return nil
+}
+
+// Vote records the vote of the voter, replacing its previous vote if any
+func (gp *GovernanceProposal) Vote(voter common.Address, approve bool) {
+	for i := range gp.Votes {
+		if gp.Votes[i].Voter == voter {
+			gp.Votes[i].Approve = approve
+			return
+		}
+	}
+	gp.Votes = append(gp.Votes, GovernanceVote{Voter: voter, Approve: approve})
+}
+
+// Tally counts the votes with the stakes of the validator candidate pool. It returns whether the
+// quorum is reached and whether the proposal passes.
+func (gp *GovernanceProposal) Tally(vcp *core.ValidatorCandidatePool) (quorum bool, passed bool) {
+	stakes, totalStake := governanceStakes(vcp)
+	if totalStake.Sign() == 0 {
+		return false, false
+	}
+
+	votingStake := new(big.Int)
+	approvingStake := new(big.Int)
+	for _, vote := range gp.Votes {
+		stake, ok := stakes[vote.Voter]
+		if !ok {
+			continue // the voter withdrew its stakes since
+		}
+		votingStake.Add(votingStake, stake)
+		if vote.Approve {
+			approvingStake.Add(approvingStake, stake)
+		}
+	}
+
+	// votingStake / totalStake >= GovernanceQuorumPercent / 100
+	quorum = new(big.Int).Mul(votingStake, big.NewInt(100)).Cmp(
+		new(big.Int).Mul(totalStake, big.NewInt(GovernanceQuorumPercent))) >= 0
+	// approvingStake / votingStake > GovernanceThresholdPercent / 100
+	passed = quorum && new(big.Int).Mul(approvingStake, big.NewInt(100)).Cmp(
+		new(big.Int).Mul(votingStake, big.NewInt(GovernanceThresholdPercent))) > 0
+	return quorum, passed
+}
+
+// GovernanceVotingStake returns the stake the voter votes on the governance proposals with, i.e. the
+// stakes the voter deposited or delegated to the validator candidates and has not withdrawn
+func GovernanceVotingStake(vcp *core.ValidatorCandidatePool, voter common.Address) *big.Int {
+	stakes, _ := governanceStakes(vcp)
+	if stake, ok := stakes[voter]; ok {
+		return stake
+	}
+	return new(big.Int)
+}
+
+// governanceStakes returns the stakes of the validator candidate pool by source, and their total
+func governanceStakes(vcp *core.ValidatorCandidatePool) (map[common.Address]*big.Int, *big.Int) {
+	totalStake := new(big.Int)
+	stakes := make(map[common.Address]*big.Int)
+	if vcp == nil {
+		return stakes, totalStake
+	}
+	for _, candidate := range vcp.SortedCandidates {
+		for _, stake := range candidate.Stakes {
+			if stake.Withdrawn {
+				continue
+			}
+			totalStake.Add(totalStake, stake.Amount)
+			if stakes[stake.Source] == nil {
+				stakes[stake.Source] = new(big.Int)
+			}
+			stakes[stake.Source].Add(stakes[stake.Source], stake.Amount)
+		}
+	}
+	return stakes, totalStake
+}
+
+func (gp *GovernanceProposal) String() string {
+	if gp == nil {
+		return "nil-GovernanceProposal"
+	}
+	return fmt.Sprintf("GovernanceProposal{id: %v, proposer: %v, name: %v, value: %v, deposit: %v, submit_height: %v, tally_height: %v, votes: %v}",
+		gp.ID.Hex(), gp.Proposer, gp.Name, gp.Value, gp.Deposit, gp.SubmitHeight, gp.TallyHeight, len(gp.Votes))
+}
+
+// GovernanceProposalSet keeps the pending governance proposals in the order they are submitted
+type GovernanceProposalSet struct {
+	Proposals []*GovernanceProposal
+}
+
+// Get returns the pending proposal with the given ID, nil if not found
+func (s *GovernanceProposalSet) Get(id common.Hash) *GovernanceProposal {
+	for _, proposal := range s.Proposals {
+		if proposal.ID == id {
+			return proposal
+		}
+	}
+	return nil
+}
+
+// Add adds a pending proposal
+func (s *GovernanceProposalSet) Add(proposal *GovernanceProposal) {
+	s.Proposals = append(s.Proposals, proposal)
+}
+
+// PopTallied removes and returns the proposals to tally at or before the given height
+func (s *GovernanceProposalSet) PopTallied(height uint64) []*GovernanceProposal {
+	tallied := []*GovernanceProposal{}
+	pending := []*GovernanceProposal{}
+	for _, proposal := range s.Proposals {
+		if height >= proposal.TallyHeight {
+			tallied = append(tallied, proposal)
+		} else {
+			pending = append(pending, proposal)
+		}
+	}
+	s.Proposals = pending
+	return tallied
+}
+
+// Len returns the number of pending proposals
+func (s *GovernanceProposalSet) Len() int {
+	return len(s.Proposals)
+}
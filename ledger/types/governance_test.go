@@ -0,0 +1,80 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+)
+
+func TestCheckGovernableParam(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(CheckGovernableParam(ParamBlockGasLimit, big.NewInt(200e6)))
+	assert.Nil(CheckGovernableParam(ParamMaxCommissionRateChange, big.NewInt(0)))
+	assert.NotNil(CheckGovernableParam(ParamBlockGasLimit, big.NewInt(1)))
+	assert.NotNil(CheckGovernableParam(ParamBlockGasLimit, big.NewInt(2e9)))
+	assert.NotNil(CheckGovernableParam(ParamBlockGasLimit, nil))
+	assert.NotNil(CheckGovernableParam("ChainID", big.NewInt(1)))
+}
+
+func TestGovernanceTallyHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, submitHeight := range []uint64{1, 2, 100, 101, 12345} {
+		tallyHeight := GovernanceTallyHeight(submitHeight)
+		assert.True(common.IsCheckPointHeight(tallyHeight))
+		assert.True(tallyHeight >= submitHeight+GovernanceVotingPeriod)
+		assert.True(tallyHeight < submitHeight+GovernanceVotingPeriod+uint64(common.CheckpointInterval))
+	}
+}
+
+func TestGovernanceProposalTally(t *testing.T) {
+	assert := assert.New(t)
+
+	val1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	val2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	delegator := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	vcp := &core.ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(val1, val1, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(4))))
+	assert.Nil(vcp.DepositStake(val2, val2, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(3))))
+	assert.Nil(vcp.Delegate(delegator, val2, new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(3))))
+
+	assert.Equal(new(big.Int).Mul(core.MinValidatorStakeDeposit, big.NewInt(3)), GovernanceVotingStake(vcp, delegator))
+	assert.Equal(0, GovernanceVotingStake(vcp, common.Address{}).Sign())
+
+	proposal := &GovernanceProposal{}
+
+	// No quorum without votes
+	quorum, passed := proposal.Tally(vcp)
+	assert.False(quorum)
+	assert.False(passed)
+
+	// 30% of the stake does not reach the quorum
+	proposal.Vote(delegator, true)
+	quorum, passed = proposal.Tally(vcp)
+	assert.False(quorum)
+	assert.False(passed)
+
+	// 70% of the stake reaches the quorum, but 3/7 approving is not enough
+	proposal.Vote(val1, false)
+	quorum, passed = proposal.Tally(vcp)
+	assert.True(quorum)
+	assert.False(passed)
+
+	// A voter can change its vote
+	proposal.Vote(val1, true)
+	assert.Equal(2, len(proposal.Votes))
+	quorum, passed = proposal.Tally(vcp)
+	assert.True(quorum)
+	assert.True(passed)
+
+	// The votes of the accounts without stake do not count
+	proposal = &GovernanceProposal{}
+	proposal.Vote(common.Address{}, true)
+	quorum, _ = proposal.Tally(vcp)
+	assert.False(quorum)
+}
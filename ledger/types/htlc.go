@@ -0,0 +1,66 @@
+package types
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// HTLC is a hash time locked contract, which holds the coins locked by an HTLCCreateTx. Until the
+// time lock expires, anyone revealing the preimage of the hash lock can claim the coins, which are
+// always paid to the recipient. After the time lock has expired, the coins can only be refunded to
+// the sender. Unlike the escrow hash locks, the hash lock of an HTLC is the SHA-256 hash of the
+// preimage, so that the same secret can unlock the HTLCs of the other chain of an atomic swap.
+type HTLC struct {
+	ID            common.Hash    `json:"id"`
+	Sender        common.Address `json:"sender"`    // locked the coins, and gets them back on refund
+	Recipient     common.Address `json:"recipient"` // receives the coins on claim
+	Coins         Coins          `json:"coins"`
+	HashLock      common.Hash    `json:"hash_lock"`      // SHA-256 hash of the preimage to reveal
+	TimeLock      uint64         `json:"time_lock"`      // last block height to claim the coins
+	CreatedHeight uint64         `json:"created_height"` // height of the block creating the HTLC
+}
+
+// HTLCID returns the ID of the HTLC created by the sender with the transaction of the given sequence
+func HTLCID(sender common.Address, sequence uint64) common.Hash {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"htlc", sender, sequence})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the HTLC ID: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// HTLCHashLockOf returns the HTLC hash lock for the given preimage
+func HTLCHashLockOf(preimage common.Bytes) common.Hash {
+	return common.Hash(sha256.Sum256(preimage))
+}
+
+// CheckClaim checks whether the HTLC can be claimed at the given block height with the preimage
+func (h *HTLC) CheckClaim(blockHeight uint64, preimage common.Bytes) error {
+	if blockHeight > h.TimeLock {
+		return fmt.Errorf("The HTLC expired at height %v", h.TimeLock)
+	}
+	if len(preimage) != HTLCPreimageLength {
+		return fmt.Errorf("Invalid preimage length %v, needs to be %v bytes", len(preimage), HTLCPreimageLength)
+	}
+	if HTLCHashLockOf(preimage) != h.HashLock {
+		return fmt.Errorf("The preimage does not match the hash lock")
+	}
+	return nil
+}
+
+// CheckRefund checks whether the HTLC can be refunded at the given block height
+func (h *HTLC) CheckRefund(blockHeight uint64) error {
+	if blockHeight <= h.TimeLock {
+		return fmt.Errorf("The HTLC cannot be refunded until height %v", h.TimeLock+1)
+	}
+	return nil
+}
+
+func (h *HTLC) String() string {
+	return fmt.Sprintf("HTLC{id: %v, sender: %v, recipient: %v, coins: %v, hash_lock: %v, time_lock: %v, created_height: %v}",
+		h.ID.Hex(), h.Sender.Hex(), h.Recipient.Hex(), h.Coins, h.HashLock.Hex(), h.TimeLock, h.CreatedHeight)
+}
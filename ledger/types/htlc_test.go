@@ -0,0 +1,52 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTLC(t *testing.T) {
+	assert := assert.New(t)
+
+	alice := MakeAcc("alice")
+	bob := MakeAcc("bob")
+	preimage := common.Bytes(strings.Repeat("s", HTLCPreimageLength))
+
+	// The hash lock is the SHA-256 hash of the preimage, as used by the HTLCs on the other chains
+	assert.Equal(common.HexToHash("0x8fd6a6a78f5857d7ba1cfe9033bffeec86da2ba6a4bd60a6f833209d9d3e390d"), HTLCHashLockOf(preimage))
+
+	// The ID depends on both the sender and the sequence
+	id := HTLCID(alice.Address, 1)
+	assert.NotEqual(id, HTLCID(alice.Address, 2))
+	assert.NotEqual(id, HTLCID(bob.Address, 1))
+
+	htlc := &HTLC{
+		ID:        id,
+		Sender:    alice.Address,
+		Recipient: bob.Address,
+		Coins:     NewCoins(1, 2),
+		HashLock:  HTLCHashLockOf(preimage),
+		TimeLock:  100,
+	}
+
+	assert.Nil(htlc.CheckClaim(100, preimage))
+	assert.NotNil(htlc.CheckClaim(101, preimage))
+	assert.NotNil(htlc.CheckClaim(50, common.Bytes(strings.Repeat("g", HTLCPreimageLength))))
+	assert.NotNil(htlc.CheckClaim(50, append(preimage, 0)))
+
+	assert.NotNil(htlc.CheckRefund(100))
+	assert.Nil(htlc.CheckRefund(101))
+
+	// The HTLC survives the round trip through the state encoding
+	raw, err := ToBytes(htlc)
+	assert.Nil(err)
+	decoded := &HTLC{}
+	assert.Nil(FromBytes(raw, decoded))
+	assert.Equal(htlc.ID, decoded.ID)
+	assert.Equal(htlc.HashLock, decoded.HashLock)
+	assert.Equal(htlc.TimeLock, decoded.TimeLock)
+	assert.True(htlc.Coins.IsEqual(decoded.Coins))
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// AuthorizedKey is the key controlling an account whose key has been rotated. The signatures of the
+// authorized key are accepted for the account instead of the signatures of the key of the account
+// address, which keeps the address, and the funds and integrations bound to it, unchanged.
+type AuthorizedKey struct {
+	Address         common.Address `json:"address"`          // address of the controlling key
+	PreviousAddress common.Address `json:"previous_address"` // previous controlling key, still accepted during the grace period
+	GraceEndHeight  uint64         `json:"grace_end_height"` // the previous key is rejected after this height
+}
+
+func (ak *AuthorizedKey) String() string {
+	return fmt.Sprintf("AuthorizedKey{address: %v, previous_address: %v, grace_end_height: %v}",
+		ak.Address.Hex(), ak.PreviousAddress.Hex(), ak.GraceEndHeight)
+}
+
+// InGracePeriod indicates whether the previous key is still accepted
+func (ak *AuthorizedKey) InGracePeriod() bool {
+	return ak.PreviousAddress != common.Address{}
+}
+
+// ControllingAddress returns the address of the key controlling the account
+func (acc *Account) ControllingAddress() common.Address {
+	if acc.AuthorizedKey == nil {
+		return acc.Address
+	}
+	return acc.AuthorizedKey.Address
+}
+
+// SignerAddresses returns the addresses of the keys whose signatures are accepted for the account,
+// i.e. the controlling key, and the previous controlling key during the grace period of a rotation
+func (acc *Account) SignerAddresses() []common.Address {
+	if acc.AuthorizedKey == nil {
+		return []common.Address{acc.Address}
+	}
+	signers := []common.Address{acc.AuthorizedKey.Address}
+	if acc.AuthorizedKey.InGracePeriod() {
+		signers = append(signers, acc.AuthorizedKey.PreviousAddress)
+	}
+	return signers
+}
+
+// CheckRotateKey verifies that the account can rotate its controlling key to the new key
+func (acc *Account) CheckRotateKey(newKey common.Address) error {
+	if (newKey == common.Address{}) {
+		return errors.New("The new key is empty")
+	}
+	if acc.IsASmartContract() {
+		return errors.New("The key of a smart contract cannot be rotated")
+	}
+	if newKey == acc.ControllingAddress() {
+		return fmt.Errorf("%v already controls the account", newKey.Hex())
+	}
+	return nil
+}
+
+// RotateKey authorizes the new key to control the account. The previous controlling key is still
+// accepted until the grace period ends, so that a rotation can be undone before it takes effect:
+// rotating back to the previous key during the grace period cancels the rotation right away, and
+// rotating to another key restarts the grace period.
+func (acc *Account) RotateKey(newKey common.Address, blockHeight uint64) {
+	previous := acc.ControllingAddress()
+	if acc.AuthorizedKey != nil && acc.AuthorizedKey.InGracePeriod() {
+		previous = acc.AuthorizedKey.PreviousAddress
+	}
+
+	if newKey == previous {
+		acc.setAuthorizedKey(&AuthorizedKey{Address: previous})
+		return
+	}
+	acc.setAuthorizedKey(&AuthorizedKey{
+		Address:         newKey,
+		PreviousAddress: previous,
+		GraceEndHeight:  blockHeight + KeyRotationGracePeriod,
+	})
+}
+
+// ExpireKeyRotation rejects the previous key once the grace period of the rotation has ended. Like
+// for the reserved funds, the height is the one of the view the account is read from, i.e. the parent
+// of the block the account is updated in.
+func (acc *Account) ExpireKeyRotation(height uint64) {
+	ak := acc.AuthorizedKey
+	if ak == nil || !ak.InGracePeriod() || height < ak.GraceEndHeight {
+		return
+	}
+	acc.setAuthorizedKey(&AuthorizedKey{Address: ak.Address})
+}
+
+// setAuthorizedKey replaces the authorized key, which is shared with the copies of the account.
+// The key of the account address needs no authorization.
+func (acc *Account) setAuthorizedKey(ak *AuthorizedKey) {
+	if ak.Address == acc.Address && !ak.InGracePeriod() {
+		ak = nil
+	}
+	acc.AuthorizedKey = ak
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountKeyRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	acc := NewAccount(common.HexToAddress("0x1"))
+	key1 := common.HexToAddress("0x2")
+	key2 := common.HexToAddress("0x3")
+
+	// Accounts without an authorized key keep their encoding
+	raw, err := rlp.EncodeToBytes(acc)
+	assert.Nil(err)
+	type accountV1 struct {
+		Address                common.Address
+		Sequence               uint64
+		Balance                Coins
+		ReservedFunds          []ReservedFund
+		LastUpdatedBlockHeight uint64
+		Root                   common.Hash
+		CodeHash               common.Hash
+	}
+	rawV1, err := rlp.EncodeToBytes(&accountV1{Address: acc.Address, Balance: acc.Balance, CodeHash: acc.CodeHash})
+	assert.Nil(err)
+	assert.Equal(rawV1, raw)
+
+	assert.Equal([]common.Address{acc.Address}, acc.SignerAddresses())
+	assert.NotNil(acc.CheckRotateKey(acc.Address))
+	assert.NotNil(acc.CheckRotateKey(common.Address{}))
+	assert.Nil(acc.CheckRotateKey(key1))
+
+	acc.RotateKey(key1, 100)
+	assert.Equal(key1, acc.ControllingAddress())
+	assert.Equal([]common.Address{key1, acc.Address}, acc.SignerAddresses())
+	assert.Equal(100+KeyRotationGracePeriod, acc.AuthorizedKey.GraceEndHeight)
+
+	raw, err = rlp.EncodeToBytes(acc)
+	assert.Nil(err)
+	decoded := &Account{}
+	assert.Nil(rlp.DecodeBytes(raw, decoded))
+	assert.Equal(acc.AuthorizedKey, decoded.AuthorizedKey)
+
+	// Rotating again during the grace period keeps the original key as the previous key
+	acc.RotateKey(key2, 200)
+	assert.Equal([]common.Address{key2, acc.Address}, acc.SignerAddresses())
+	assert.Equal(200+KeyRotationGracePeriod, acc.AuthorizedKey.GraceEndHeight)
+
+	acc.ExpireKeyRotation(200 + KeyRotationGracePeriod - 1)
+	assert.Equal([]common.Address{key2, acc.Address}, acc.SignerAddresses())
+	acc.ExpireKeyRotation(200 + KeyRotationGracePeriod)
+	assert.Equal([]common.Address{key2}, acc.SignerAddresses())
+
+	// Rotating back to the previous key during the grace period cancels the rotation
+	acc.RotateKey(key1, 300)
+	assert.Equal([]common.Address{key1, key2}, acc.SignerAddresses())
+	acc.RotateKey(key2, 310)
+	assert.Equal([]common.Address{key2}, acc.SignerAddresses())
+
+	// Back to the key of the account address
+	acc.RotateKey(acc.Address, 400)
+	acc.ExpireKeyRotation(400 + KeyRotationGracePeriod)
+	assert.Nil(acc.AuthorizedKey)
+	assert.Equal([]common.Address{acc.Address}, acc.SignerAddresses())
+}
@@ -18,8 +18,10 @@ package types
 
 import (
 	"io"
+	"math/big"
 
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/rlp"
 )
 
@@ -57,3 +59,15 @@ func (l *Log) DecodeRLP(s *rlp.Stream) error {
 	}
 	return err
 }
+
+// LogsBloom creates the bloom filter of the addresses and topics of the logs
+func LogsBloom(logs []*Log) core.Bloom {
+	bloom := core.Bloom{}
+	for _, log := range logs {
+		bloom.Add(new(big.Int).SetBytes(log.Address.Bytes()))
+		for _, topic := range log.Topics {
+			bloom.Add(new(big.Int).SetBytes(topic.Bytes()))
+		}
+	}
+	return bloom
+}
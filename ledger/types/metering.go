@@ -0,0 +1,136 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// BytesPerGB is the number of bytes in a gigabyte, the unit of the metered bandwidth prices
+const BytesPerGB uint64 = 1e9
+
+// MeteringRecord attests the bandwidth a Rametron edge node delivered to a client over a period.
+// It is signed by both the client and the edge node, so neither can inflate or deny the delivery.
+// The records are settled on chain with a MeteredSettlementTx, and paid from the reserve fund of
+// the client.
+type MeteringRecord struct {
+	Client            common.Address    // the payer owning the reserve fund
+	EdgeNode          common.Address    // the payee which delivered the bandwidth
+	ReserveSequence   uint64            // locates the reserve fund of the client
+	ResourceID        string            // the resource the bandwidth was delivered for
+	Sequence          uint64            // increases with each period, a record is only settled once
+	Bytes             uint64            // the number of bytes delivered over the period
+	PricePerGB        Coins             // the price of a gigabyte, in the currency of the reserve fund
+	ClientSignature   *crypto.Signature // signature of the client
+	EdgeNodeSignature *crypto.Signature // signature of the edge node
+}
+
+type MeteringRecordJSON struct {
+	Client            common.Address    `json:"client"`
+	EdgeNode          common.Address    `json:"edge_node"`
+	ReserveSequence   common.JSONUint64 `json:"reserve_sequence"`
+	ResourceID        string            `json:"resource_id"`
+	Sequence          common.JSONUint64 `json:"sequence"`
+	Bytes             common.JSONUint64 `json:"bytes"`
+	PricePerGB        Coins             `json:"price_per_gb"`
+	ClientSignature   *crypto.Signature `json:"client_signature"`
+	EdgeNodeSignature *crypto.Signature `json:"edge_node_signature"`
+}
+
+func NewMeteringRecordJSON(a MeteringRecord) MeteringRecordJSON {
+	return MeteringRecordJSON{
+		Client:            a.Client,
+		EdgeNode:          a.EdgeNode,
+		ReserveSequence:   common.JSONUint64(a.ReserveSequence),
+		ResourceID:        a.ResourceID,
+		Sequence:          common.JSONUint64(a.Sequence),
+		Bytes:             common.JSONUint64(a.Bytes),
+		PricePerGB:        a.PricePerGB,
+		ClientSignature:   a.ClientSignature,
+		EdgeNodeSignature: a.EdgeNodeSignature,
+	}
+}
+
+func (a MeteringRecordJSON) MeteringRecord() MeteringRecord {
+	return MeteringRecord{
+		Client:            a.Client,
+		EdgeNode:          a.EdgeNode,
+		ReserveSequence:   uint64(a.ReserveSequence),
+		ResourceID:        a.ResourceID,
+		Sequence:          uint64(a.Sequence),
+		Bytes:             uint64(a.Bytes),
+		PricePerGB:        a.PricePerGB,
+		ClientSignature:   a.ClientSignature,
+		EdgeNodeSignature: a.EdgeNodeSignature,
+	}
+}
+
+func (a MeteringRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewMeteringRecordJSON(a))
+}
+
+func (a *MeteringRecord) UnmarshalJSON(data []byte) error {
+	var b MeteringRecordJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.MeteringRecord()
+	return nil
+}
+
+// SignBytes returns the bytes signed by both the client and the edge node
+func (r *MeteringRecord) SignBytes(chainID string) []byte {
+	clientSig := r.ClientSignature
+	edgeNodeSig := r.EdgeNodeSignature
+	r.ClientSignature = nil
+	r.EdgeNodeSignature = nil
+
+	recordBytes, err := rlp.EncodeToBytes([]interface{}{"metering", chainID, r})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the metering record: %v", err))
+	}
+	signBytes := addPrefixForSignBytes(recordBytes)
+
+	r.ClientSignature = clientSig
+	r.EdgeNodeSignature = edgeNodeSig
+	return signBytes
+}
+
+// Amount returns the payment for the delivered bandwidth, rounded down to the wei
+func (r *MeteringRecord) Amount() Coins {
+	price := r.PricePerGB.NoNil()
+	bytes := new(big.Int).SetUint64(r.Bytes)
+	perGB := new(big.Int).SetUint64(BytesPerGB)
+	return Coins{
+		PandoWei: new(big.Int).Div(new(big.Int).Mul(price.PandoWei, bytes), perGB),
+		PTXWei:   new(big.Int).Div(new(big.Int).Mul(price.PTXWei, bytes), perGB),
+	}
+}
+
+// Verify checks that the record is signed by both the client and the edge node
+func (r *MeteringRecord) Verify(chainID string) error {
+	if r.ClientSignature == nil || r.ClientSignature.IsEmpty() {
+		return errors.New("The record is not signed by the client")
+	}
+	if r.EdgeNodeSignature == nil || r.EdgeNodeSignature.IsEmpty() {
+		return errors.New("The record is not signed by the edge node")
+	}
+	signBytes := r.SignBytes(chainID)
+	if !r.ClientSignature.Verify(signBytes, r.Client) {
+		return fmt.Errorf("Invalid signature of the client %v", r.Client.Hex())
+	}
+	if !r.EdgeNodeSignature.Verify(signBytes, r.EdgeNode) {
+		return fmt.Errorf("Invalid signature of the edge node %v", r.EdgeNode.Hex())
+	}
+	return nil
+}
+
+func (r *MeteringRecord) String() string {
+	return fmt.Sprintf("MeteringRecord{client: %v, edge_node: %v, reserve_sequence: %v, resource_id: %v, sequence: %v, bytes: %v, price_per_gb: %v}",
+		r.Client, r.EdgeNode, r.ReserveSequence, r.ResourceID, r.Sequence, r.Bytes, r.PricePerGB)
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeteringRecord(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	client := MakeAcc("client")
+	edgeNode := MakeAcc("edge node")
+	record := MeteringRecord{
+		Client:          client.Address,
+		EdgeNode:        edgeNode.Address,
+		ReserveSequence: 1,
+		ResourceID:      "vid01",
+		Sequence:        1,
+		Bytes:           2500 * 1000 * 1000,
+		PricePerGB:      NewCoins(0, 1000),
+	}
+	assert.Equal(NewCoins(0, 2500), record.Amount())
+
+	// Both the client and the edge node need to sign
+	assert.NotNil(record.Verify("test_chain"))
+	record.ClientSignature = client.Sign(record.SignBytes("test_chain"))
+	assert.NotNil(record.Verify("test_chain"))
+	record.EdgeNodeSignature = edgeNode.Sign(record.SignBytes("test_chain"))
+	require.Nil(record.Verify("test_chain"))
+	assert.NotNil(record.Verify("other_chain"))
+
+	// The delivered bytes cannot be altered
+	record.Bytes++
+	assert.NotNil(record.Verify("test_chain"))
+	record.Bytes--
+
+	tx := &MeteredSettlementTx{
+		Fee:             NewCoins(0, 1),
+		EdgeNode:        TxInput{Address: edgeNode.Address, Sequence: 1},
+		Client:          client.Address,
+		ReserveSequence: 1,
+		ResourceID:      "vid01",
+		Records:         []MeteringRecord{record},
+	}
+	require.Nil(tx.ValidateRecords("test_chain"))
+	assert.Equal(uint64(1), tx.ServicePayment().PaymentSequence)
+	assert.Equal(NewCoins(0, 2500), tx.ServicePayment().Source.Coins)
+
+	// The records need to belong to the settlement
+	tx.ResourceID = "vid02"
+	assert.NotNil(tx.ValidateRecords("test_chain"))
+	tx.ResourceID = "vid01"
+
+	raw, err := TxToBytes(tx)
+	require.Nil(err)
+	decoded, err := TxFromBytes(raw)
+	require.Nil(err)
+	assert.Nil(decoded.(*MeteredSettlementTx).ValidateRecords("test_chain"))
+}
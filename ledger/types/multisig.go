@@ -0,0 +1,169 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// MultiSigSignature holds the signatures of an M-of-N multi-signature TxInput. The address of
+// a multi-signature account is derived from its threshold and signers, hence the account does
+// not have a private key of its own. The co-signers sign the same sign bytes of the transaction
+// independently, and their signatures are then aggregated with AddSignature.
+type MultiSigSignature struct {
+	Threshold  uint64              `json:"threshold"`  // minimal number of signatures required
+	Signers    []common.Address    `json:"signers"`    // addresses of the signers in ascending order
+	Signatures []*crypto.Signature `json:"signatures"` // signatures by distinct signers, in the order of the signers
+}
+
+// NewMultiSigSignature creates a MultiSigSignature without signatures for the given policy
+func NewMultiSigSignature(threshold uint64, signers []common.Address) *MultiSigSignature {
+	return &MultiSigSignature{
+		Threshold:  threshold,
+		Signers:    sortSigners(signers),
+		Signatures: []*crypto.Signature{},
+	}
+}
+
+// MultiSigAddress returns the address of the multi-signature account with the given threshold
+// and signers. The address does not depend on the order of the signers.
+func MultiSigAddress(threshold uint64, signers []common.Address) common.Address {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"multisig", threshold, sortSigners(signers)})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the multi-signature policy: %v", err))
+	}
+	return common.BytesToAddress(crypto.Keccak256(encoded)[12:])
+}
+
+func sortSigners(signers []common.Address) []common.Address {
+	sorted := make([]common.Address, len(signers))
+	copy(sorted, signers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+	return sorted
+}
+
+// Address returns the address of the multi-signature account
+func (ms *MultiSigSignature) Address() common.Address {
+	return MultiSigAddress(ms.Threshold, ms.Signers)
+}
+
+// ValidateBasic checks the policy and the number of signatures, without verifying the signatures
+func (ms *MultiSigSignature) ValidateBasic() error {
+	numSigners := len(ms.Signers)
+	if numSigners == 0 || numSigners > MaximumMultiSigSigners {
+		return fmt.Errorf("Invalid number of signers %v, needs to be between 1 and %v", numSigners, MaximumMultiSigSigners)
+	}
+	if ms.Threshold == 0 || ms.Threshold > uint64(numSigners) {
+		return fmt.Errorf("Invalid threshold %v, needs to be between 1 and %v", ms.Threshold, numSigners)
+	}
+	for i := 1; i < numSigners; i++ {
+		if bytes.Compare(ms.Signers[i-1][:], ms.Signers[i][:]) >= 0 {
+			return errors.New("Signers need to be unique and in ascending order")
+		}
+	}
+	if uint64(len(ms.Signatures)) < ms.Threshold {
+		return fmt.Errorf("Insufficient signatures, got %v, need %v", len(ms.Signatures), ms.Threshold)
+	}
+	if len(ms.Signatures) > numSigners {
+		return fmt.Errorf("Too many signatures, got %v, at most %v", len(ms.Signatures), numSigners)
+	}
+	return nil
+}
+
+// AddSignature aggregates the signature of a signer over the sign bytes. A previous signature
+// of the same signer is replaced.
+func (ms *MultiSigSignature) AddSignature(signBytes common.Bytes, sig *crypto.Signature) error {
+	signer, err := sig.RecoverSignerAddress(signBytes)
+	if err != nil {
+		return err
+	}
+	if ms.signerIndex(signer) < 0 {
+		return fmt.Errorf("%v is not a signer", signer.Hex())
+	}
+
+	signatures := []*crypto.Signature{sig}
+	for _, existing := range ms.Signatures {
+		if addr, err := existing.RecoverSignerAddress(signBytes); err == nil && addr == signer {
+			continue
+		}
+		signatures = append(signatures, existing)
+	}
+	sort.SliceStable(signatures, func(i, j int) bool {
+		return ms.recoveredIndex(signatures[i], signBytes) < ms.recoveredIndex(signatures[j], signBytes)
+	})
+	ms.Signatures = signatures
+	return nil
+}
+
+// Verify checks that the multi-signature policy matches the address, and that at least threshold
+// distinct signers signed one of the messages. Every signature needs to be valid.
+func (ms *MultiSigSignature) Verify(addr common.Address, msgs ...common.Bytes) error {
+	if err := ms.ValidateBasic(); err != nil {
+		return err
+	}
+	if ms.Address() != addr {
+		return fmt.Errorf("Multi-signature policy does not match the address %v", addr.Hex())
+	}
+
+	signed := make(map[common.Address]bool)
+	for _, sig := range ms.Signatures {
+		signer, ok := ms.recoverSigner(sig, msgs)
+		if !ok {
+			return errors.New("Invalid signature in the multi-signature")
+		}
+		if signed[signer] {
+			return fmt.Errorf("Duplicated signature by %v", signer.Hex())
+		}
+		signed[signer] = true
+	}
+	if uint64(len(signed)) < ms.Threshold {
+		return fmt.Errorf("Insufficient signatures, got %v, need %v", len(signed), ms.Threshold)
+	}
+	return nil
+}
+
+// recoverSigner returns the signer of the signature over any of the messages
+func (ms *MultiSigSignature) recoverSigner(sig *crypto.Signature, msgs []common.Bytes) (common.Address, bool) {
+	if sig == nil || sig.IsEmpty() {
+		return common.Address{}, false
+	}
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		signer, err := sig.RecoverSignerAddress(msg)
+		if err == nil && ms.signerIndex(signer) >= 0 {
+			return signer, true
+		}
+	}
+	return common.Address{}, false
+}
+
+func (ms *MultiSigSignature) recoveredIndex(sig *crypto.Signature, signBytes common.Bytes) int {
+	signer, err := sig.RecoverSignerAddress(signBytes)
+	if err != nil {
+		return len(ms.Signers)
+	}
+	return ms.signerIndex(signer)
+}
+
+func (ms *MultiSigSignature) signerIndex(addr common.Address) int {
+	for i, signer := range ms.Signers {
+		if signer == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+func (ms *MultiSigSignature) String() string {
+	return fmt.Sprintf("MultiSigSignature{threshold: %v, signers: %v, signatures: %v}",
+		ms.Threshold, ms.Signers, ms.Signatures)
+}
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiSigAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	signer1 := MakeAcc("signer1").Address
+	signer2 := MakeAcc("signer2").Address
+	signer3 := MakeAcc("signer3").Address
+
+	addr := MultiSigAddress(2, []common.Address{signer1, signer2, signer3})
+	assert.Equal(addr, MultiSigAddress(2, []common.Address{signer3, signer1, signer2}))
+	assert.NotEqual(addr, MultiSigAddress(1, []common.Address{signer1, signer2, signer3}))
+	assert.NotEqual(addr, MultiSigAddress(2, []common.Address{signer1, signer2}))
+
+	ms := NewMultiSigSignature(2, []common.Address{signer3, signer2, signer1})
+	assert.Equal(addr, ms.Address())
+	for i := 1; i < len(ms.Signers); i++ {
+		assert.True(bytes.Compare(ms.Signers[i-1][:], ms.Signers[i][:]) < 0)
+	}
+}
+
+func TestMultiSigSignatureVerify(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	signer1 := MakeAcc("signer1")
+	signer2 := MakeAcc("signer2")
+	signer3 := MakeAcc("signer3")
+	other := MakeAcc("other")
+
+	ms := NewMultiSigSignature(2, []common.Address{signer1.Address, signer2.Address, signer3.Address})
+	addr := ms.Address()
+	msg := common.Bytes("multisig message")
+
+	require.Nil(ms.AddSignature(msg, signer3.Sign(msg)))
+	assert.NotNil(ms.Verify(addr, msg)) // below the threshold
+
+	assert.NotNil(ms.AddSignature(msg, other.Sign(msg)))
+	require.Nil(ms.AddSignature(msg, signer1.Sign(msg)))
+	require.Nil(ms.AddSignature(msg, signer1.Sign(msg))) // replaces the previous signature
+	assert.Equal(2, len(ms.Signatures))
+	assert.Nil(ms.Verify(addr, msg))
+	assert.Nil(ms.Verify(addr, nil, msg))
+
+	assert.NotNil(ms.Verify(other.Address, msg))
+	assert.NotNil(ms.Verify(addr, common.Bytes("another message")))
+
+	// Signatures by the same signer are counted once
+	dup := NewMultiSigSignature(2, ms.Signers)
+	dup.Signatures = []*crypto.Signature{signer1.Sign(msg), signer1.Sign(msg)}
+	assert.NotNil(dup.Verify(addr, msg))
+
+	// Malformed policies
+	assert.NotNil((&MultiSigSignature{Threshold: 0, Signers: ms.Signers, Signatures: ms.Signatures}).ValidateBasic())
+	assert.NotNil((&MultiSigSignature{Threshold: 4, Signers: ms.Signers, Signatures: ms.Signatures}).ValidateBasic())
+	unsorted := []common.Address{ms.Signers[2], ms.Signers[0], ms.Signers[1]}
+	assert.NotNil((&MultiSigSignature{Threshold: 2, Signers: unsorted, Signatures: ms.Signatures}).ValidateBasic())
+}
+
+func TestMultiSigTxInput(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := "test_chain_id"
+	signer1 := MakeAcc("signer1")
+	signer2 := MakeAcc("signer2")
+	accOut := MakeAcc("out")
+
+	ms := NewMultiSigSignature(2, []common.Address{signer1.Address, signer2.Address})
+	accIn := MakeAcc("in")
+	accIn.Address = ms.Address()
+
+	tx := MakeSendTx(1, accOut, accIn)
+	plainBytes, err := rlp.EncodeToBytes(tx.Inputs[0])
+	require.Nil(err)
+	signBytes := tx.SignBytes(chainID)
+
+	require.Nil(ms.AddSignature(signBytes, signer1.Sign(signBytes)))
+	require.Nil(ms.AddSignature(signBytes, signer2.Sign(signBytes)))
+	tx.Inputs[0].MultiSig = ms
+
+	// The multi-signature is not part of the sign bytes
+	assert.Equal(signBytes, tx.SignBytes(chainID))
+	assert.True(tx.Inputs[0].ValidateBasic().IsOK())
+
+	raw, err := TxToBytes(tx)
+	require.Nil(err)
+	decoded, err := TxFromBytes(raw)
+	require.Nil(err)
+	decodedIn := decoded.(*SendTx).Inputs[0]
+	require.NotNil(decodedIn.MultiSig)
+	assert.Nil(decodedIn.MultiSig.Verify(accIn.Address, signBytes))
+
+	// Inputs without multi-signature are encoded as before
+	tx.Inputs[0].MultiSig = nil
+	encoded, err := rlp.EncodeToBytes(tx.Inputs[0])
+	require.Nil(err)
+	assert.Equal(plainBytes, encoded)
+
+	// The policy needs to match the address
+	tx.Inputs[0].MultiSig = NewMultiSigSignature(1, []common.Address{signer1.Address})
+	tx.Inputs[0].MultiSig.Signatures = ms.Signatures[:1]
+	assert.True(tx.Inputs[0].ValidateBasic().IsError())
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// ParamChange is a governance parameter change. It is announced when the change passes,
+// and only takes effect at the activation height, so that wallets, explorers and exchanges
+// have time to adapt.
+type ParamChange struct {
+	Name             string
+	Value            *big.Int
+	AnnouncedHeight  uint64
+	ActivationHeight uint64
+}
+
+type ParamChangeJSON struct {
+	Name             string            `json:"name"`
+	Value            *common.JSONBig   `json:"value"`
+	AnnouncedHeight  common.JSONUint64 `json:"announced_height"`
+	ActivationHeight common.JSONUint64 `json:"activation_height"`
+}
+
+func NewParamChangeJSON(a ParamChange) ParamChangeJSON {
+	return ParamChangeJSON{
+		Name:             a.Name,
+		Value:            (*common.JSONBig)(a.Value),
+		AnnouncedHeight:  common.JSONUint64(a.AnnouncedHeight),
+		ActivationHeight: common.JSONUint64(a.ActivationHeight),
+	}
+}
+
+func (a ParamChangeJSON) ParamChange() ParamChange {
+	return ParamChange{
+		Name:             a.Name,
+		Value:            (*big.Int)(a.Value),
+		AnnouncedHeight:  uint64(a.AnnouncedHeight),
+		ActivationHeight: uint64(a.ActivationHeight),
+	}
+}
+
+func (a ParamChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewParamChangeJSON(a))
+}
+
+func (a *ParamChange) UnmarshalJSON(data []byte) error {
+	var b ParamChangeJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.ParamChange()
+	return nil
+}
+
+func (pc *ParamChange) String() string {
+	return fmt.Sprintf("ParamChange{name: %v, value: %v, announced_height: %v, activation_height: %v}",
+		pc.Name, pc.Value, pc.AnnouncedHeight, pc.ActivationHeight)
+}
+
+// ParamChangeSchedule keeps the pending parameter changes sorted by activation height.
+type ParamChangeSchedule struct {
+	Changes []*ParamChange
+}
+
+// Add adds a parameter change to the schedule. Changes with the same activation height
+// are applied in the order they are added.
+func (s *ParamChangeSchedule) Add(change *ParamChange) {
+	s.Changes = append(s.Changes, change)
+	sort.SliceStable(s.Changes, func(i, j int) bool {
+		return s.Changes[i].ActivationHeight < s.Changes[j].ActivationHeight
+	})
+}
+
+// PopActivated removes and returns the changes that take effect at or before the given height.
+func (s *ParamChangeSchedule) PopActivated(height uint64) []*ParamChange {
+	idx := 0
+	for idx < len(s.Changes) && s.Changes[idx].ActivationHeight <= height {
+		idx++
+	}
+	activated := s.Changes[:idx]
+	s.Changes = s.Changes[idx:]
+	return activated
+}
+
+// Len returns the number of pending changes.
+func (s *ParamChangeSchedule) Len() int {
+	return len(s.Changes)
+}
@@ -0,0 +1,281 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// RametronTier is the service tier a Rametron node registers for with a RametronStakeTx
+type RametronTier uint8
+
+const (
+	RametronTierNone       RametronTier = iota // untiered stake, not eligible for the Rametron rewards
+	RametronTierMobile                         // mobile devices
+	RametronTierPro                            // desktops and small servers
+	RametronTierEnterprise                     // data center nodes
+)
+
+// RametronUptimeWindow is the number of recent reward epochs the uptime of a Rametron node is measured over
+const RametronUptimeWindow uint64 = 64
+
+// MaxRametronUptime is the uptime of a Rametron node active in every epoch, in basis points
+const MaxRametronUptime uint64 = 10000
+
+// MinRametronAttesters is the number of guardians which need to attest the heartbeat of a Rametron
+// node in an epoch for the node to be active in the epoch
+const MinRametronAttesters = 2
+
+// RametronTierSpec defines the requirements and the reward weight of a Rametron tier
+type RametronTierSpec struct {
+	Tier         RametronTier
+	Name         string
+	MinStake     *big.Int // the min PandoWei balance of the node
+	RewardWeight uint64   // the share of the Rametron rewards of a node, relative to the other nodes
+	MinUptime    uint64   // the min uptime over the uptime window to be rewarded, in basis points
+}
+
+var rametronTierSpecs = []*RametronTierSpec{
+	{
+		Tier:         RametronTierMobile,
+		Name:         "mobile",
+		MinStake:     new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)),
+		RewardWeight: 1,
+		MinUptime:    5000,
+	},
+	{
+		Tier:         RametronTierPro,
+		Name:         "pro",
+		MinStake:     new(big.Int).Mul(big.NewInt(10000), big.NewInt(1e18)),
+		RewardWeight: 5,
+		MinUptime:    8000,
+	},
+	{
+		Tier:         RametronTierEnterprise,
+		Name:         "enterprise",
+		MinStake:     new(big.Int).Mul(big.NewInt(100000), big.NewInt(1e18)),
+		RewardWeight: 25,
+		MinUptime:    9500,
+	},
+}
+
+// GetRametronTierSpec returns the spec of the given tier, false for RametronTierNone and unknown tiers
+func GetRametronTierSpec(tier RametronTier) (*RametronTierSpec, bool) {
+	for _, spec := range rametronTierSpecs {
+		if spec.Tier == tier {
+			return spec, true
+		}
+	}
+	return nil, false
+}
+
+// ParseRametronTier parses the name of a tier, e.g. "pro"
+func ParseRametronTier(name string) (RametronTier, error) {
+	if name == "" || strings.EqualFold(name, "none") {
+		return RametronTierNone, nil
+	}
+	for _, spec := range rametronTierSpecs {
+		if strings.EqualFold(spec.Name, name) {
+			return spec.Tier, nil
+		}
+	}
+	return RametronTierNone, fmt.Errorf("Unknown rametron tier: %v", name)
+}
+
+func (t RametronTier) String() string {
+	if spec, ok := GetRametronTierSpec(t); ok {
+		return spec.Name
+	}
+	if t == RametronTierNone {
+		return "none"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(t))
+}
+
+// RametronNode is a Rametron node registered for a tier. Its uptime is recorded per reward epoch,
+// i.e. per checkpoint interval, as a bitmap of the epochs in which it settled delivered bandwidth.
+type RametronNode struct {
+	Node            common.Address
+	Holder          common.Address // the account which registered the node, the only one allowed to change its tier
+	Tier            RametronTier
+	RegisteredEpoch uint64
+	LastActiveEpoch uint64
+	ActiveEpochs    uint64 // bit i is set if the node was active in epoch LastActiveEpoch-i
+
+	AttestedEpoch uint64           `rlp:"optional"` // the epoch of the attesters below
+	Attesters     []common.Address `rlp:"optional"` // the guardians which attested the heartbeat of the node in AttestedEpoch
+}
+
+type RametronNodeJSON struct {
+	Node            common.Address    `json:"node"`
+	Holder          common.Address    `json:"holder"`
+	Tier            string            `json:"tier"`
+	RegisteredEpoch common.JSONUint64 `json:"registered_epoch"`
+	LastActiveEpoch common.JSONUint64 `json:"last_active_epoch"`
+	ActiveEpochs    common.JSONUint64 `json:"active_epochs"`
+	AttestedEpoch   common.JSONUint64 `json:"attested_epoch"`
+	Attesters       []common.Address  `json:"attesters"`
+}
+
+func NewRametronNodeJSON(a RametronNode) RametronNodeJSON {
+	return RametronNodeJSON{
+		Node:            a.Node,
+		Holder:          a.Holder,
+		Tier:            a.Tier.String(),
+		RegisteredEpoch: common.JSONUint64(a.RegisteredEpoch),
+		LastActiveEpoch: common.JSONUint64(a.LastActiveEpoch),
+		ActiveEpochs:    common.JSONUint64(a.ActiveEpochs),
+		AttestedEpoch:   common.JSONUint64(a.AttestedEpoch),
+		Attesters:       a.Attesters,
+	}
+}
+
+func (a RametronNodeJSON) RametronNode() (RametronNode, error) {
+	tier, err := ParseRametronTier(a.Tier)
+	if err != nil {
+		return RametronNode{}, err
+	}
+	return RametronNode{
+		Node:            a.Node,
+		Holder:          a.Holder,
+		Tier:            tier,
+		RegisteredEpoch: uint64(a.RegisteredEpoch),
+		LastActiveEpoch: uint64(a.LastActiveEpoch),
+		ActiveEpochs:    uint64(a.ActiveEpochs),
+		AttestedEpoch:   uint64(a.AttestedEpoch),
+		Attesters:       a.Attesters,
+	}, nil
+}
+
+func (a RametronNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewRametronNodeJSON(a))
+}
+
+func (a *RametronNode) UnmarshalJSON(data []byte) error {
+	var b RametronNodeJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	node, err := b.RametronNode()
+	if err != nil {
+		return err
+	}
+	*a = node
+	return nil
+}
+
+func (n *RametronNode) String() string {
+	if n == nil {
+		return "nil-RametronNode"
+	}
+	return fmt.Sprintf("RametronNode{node: %v, holder: %v, tier: %v, registered_epoch: %v, last_active_epoch: %v, active_epochs: %b, attested_epoch: %v, attesters: %v}",
+		n.Node, n.Holder, n.Tier, n.RegisteredEpoch, n.LastActiveEpoch, n.ActiveEpochs, n.AttestedEpoch, len(n.Attesters))
+}
+
+// RametronEpoch returns the reward epoch of the given block height. An epoch starts at a checkpoint,
+// where the Rametron rewards for the previous epochs are granted.
+func RametronEpoch(height uint64) uint64 {
+	if height == 0 {
+		return 0
+	}
+	return (height - 1) / uint64(common.CheckpointInterval)
+}
+
+// MarkActive records that the node was active in the given epoch
+func (n *RametronNode) MarkActive(epoch uint64) {
+	if n.ActiveEpochs != 0 {
+		if epoch < n.LastActiveEpoch {
+			return
+		}
+		shift := epoch - n.LastActiveEpoch
+		if shift >= 64 {
+			n.ActiveEpochs = 0
+		} else {
+			n.ActiveEpochs <<= shift
+		}
+	}
+	n.ActiveEpochs |= 1
+	n.LastActiveEpoch = epoch
+}
+
+// Attest records that the guardian attested the heartbeat of the node in the given epoch. The node
+// is active in the epoch once MinRametronAttesters guardians attested it. It returns false if the
+// guardian already attested the epoch, or if the epoch is before the last attested epoch.
+func (n *RametronNode) Attest(epoch uint64, guardian common.Address) bool {
+	if epoch < n.AttestedEpoch {
+		return false
+	}
+	if epoch > n.AttestedEpoch {
+		n.AttestedEpoch = epoch
+		n.Attesters = nil
+	}
+	for _, attester := range n.Attesters {
+		if attester == guardian {
+			return false
+		}
+	}
+	n.Attesters = append(n.Attesters, guardian)
+	if len(n.Attesters) >= MinRametronAttesters {
+		n.MarkActive(epoch)
+	}
+	return true
+}
+
+// IsActive returns whether the node was active in the given epoch, as far as the bitmap reaches
+func (n *RametronNode) IsActive(epoch uint64) bool {
+	if n.ActiveEpochs == 0 || epoch > n.LastActiveEpoch {
+		return false
+	}
+	age := n.LastActiveEpoch - epoch
+	return age < 64 && n.ActiveEpochs&(1<<age) != 0
+}
+
+// Uptime returns the share of the epochs before the given epoch, within the uptime window and
+// since the node registered, in which the node was active, in basis points
+func (n *RametronNode) Uptime(epoch uint64) uint64 {
+	if epoch <= n.RegisteredEpoch {
+		return 0
+	}
+	windowStart := n.RegisteredEpoch
+	if epoch >= RametronUptimeWindow && epoch-RametronUptimeWindow > windowStart {
+		windowStart = epoch - RametronUptimeWindow
+	}
+	active := uint64(0)
+	for e := windowStart; e < epoch; e++ {
+		if n.IsActive(e) {
+			active++
+		}
+	}
+	return active * MaxRametronUptime / (epoch - windowStart)
+}
+
+// RametronHeartbeat is the availability proof a Rametron node signs in each epoch, and sends to the
+// guardians which attest it on chain with a RametronAttestationTx
+type RametronHeartbeat struct {
+	Node      common.Address    `json:"node"`
+	Epoch     uint64            `json:"epoch"`
+	Signature *crypto.Signature `json:"signature"`
+}
+
+// SignBytes returns the bytes the node signs
+func (h *RametronHeartbeat) SignBytes(chainID string) common.Bytes {
+	raw, err := rlp.EncodeToBytes([]interface{}{"rametron_heartbeat", chainID, h.Node, h.Epoch})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the rametron heartbeat: %v", err))
+	}
+	return raw
+}
+
+// Verify checks that the heartbeat is signed by the node
+func (h *RametronHeartbeat) Verify(chainID string) bool {
+	return h.Signature != nil && h.Signature.Verify(h.SignBytes(chainID), h.Node)
+}
+
+func (h *RametronHeartbeat) String() string {
+	return fmt.Sprintf("RametronHeartbeat{node: %v, epoch: %v}", h.Node, h.Epoch)
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/common"
+)
+
+func TestRametronNodeUptime(t *testing.T) {
+	assert := assert.New(t)
+
+	node := &RametronNode{Tier: RametronTierPro, RegisteredEpoch: 10}
+	assert.Equal(uint64(0), node.Uptime(10))
+	assert.Equal(uint64(0), node.Uptime(12))
+
+	node.MarkActive(10)
+	node.MarkActive(11)
+	node.MarkActive(11)
+	assert.True(node.IsActive(10))
+	assert.True(node.IsActive(11))
+	assert.False(node.IsActive(12))
+	assert.Equal(MaxRametronUptime, node.Uptime(12))
+	assert.Equal(MaxRametronUptime/2, node.Uptime(14))
+
+	// Activity older than the last one is ignored
+	node.MarkActive(5)
+	assert.Equal(uint64(11), node.LastActiveEpoch)
+
+	// Only the epochs within the uptime window count
+	node.MarkActive(10 + RametronUptimeWindow)
+	assert.False(node.IsActive(10))
+	assert.True(node.IsActive(11))
+	assert.Equal(2*MaxRametronUptime/RametronUptimeWindow, node.Uptime(11+RametronUptimeWindow))
+
+	node.MarkActive(200)
+	assert.False(node.IsActive(10 + RametronUptimeWindow))
+	assert.Equal(MaxRametronUptime/RametronUptimeWindow, node.Uptime(201))
+}
+
+func TestRametronStakeTxTierEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	tx := &RametronStakeTx{
+		Fee:     NewCoins(0, 1),
+		Inputs:  []TxInput{NewTxInput(PrivAccountFromSecret("in").Address, NewCoins(10, 1), 1)},
+		Outputs: []TxOutput{{Address: PrivAccountFromSecret("out").Address, Coins: NewCoins(10, 0)}},
+	}
+	untiered, err := TxToBytes(tx)
+	assert.Nil(err)
+
+	tx.Tier = RametronTierEnterprise
+	tiered, err := TxToBytes(tx)
+	assert.Nil(err)
+	assert.NotEqual(untiered, tiered)
+
+	decoded, err := TxFromBytes(tiered)
+	assert.Nil(err)
+	assert.Equal(RametronTierEnterprise, decoded.(*RametronStakeTx).Tier)
+
+	// The untiered encoding is unchanged
+	tx.Tier = RametronTierNone
+	reencoded, err := TxToBytes(tx)
+	assert.Nil(err)
+	assert.Equal(untiered, reencoded)
+
+	tier, err := ParseRametronTier("Pro")
+	assert.Nil(err)
+	assert.Equal(RametronTierPro, tier)
+	_, err = ParseRametronTier("gold")
+	assert.NotNil(err)
+}
+
+func TestRametronNodeAttest(t *testing.T) {
+	assert := assert.New(t)
+
+	guardian1 := PrivAccountFromSecret("guardian1").Address
+	guardian2 := PrivAccountFromSecret("guardian2").Address
+	node := &RametronNode{Tier: RametronTierMobile}
+
+	assert.True(node.Attest(3, guardian1))
+	assert.False(node.Attest(3, guardian1))
+	assert.False(node.IsActive(3))
+	assert.True(node.Attest(3, guardian2))
+	assert.True(node.IsActive(3))
+
+	// The attesters are counted per epoch
+	assert.True(node.Attest(4, guardian1))
+	assert.Equal(1, len(node.Attesters))
+	assert.False(node.IsActive(4))
+	assert.False(node.Attest(3, guardian2))
+
+	// The attestation state survives the encoding
+	raw, err := ToBytes(node)
+	assert.Nil(err)
+	decoded := &RametronNode{}
+	assert.Nil(FromBytes(raw, decoded))
+	assert.Equal(uint64(4), decoded.AttestedEpoch)
+	assert.Equal([]common.Address{guardian1}, decoded.Attesters)
+}
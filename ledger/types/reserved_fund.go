@@ -12,6 +12,62 @@ type TransferRecord struct {
 	ServicePayment ServicePaymentTx `json:"service_payment"`
 }
 
+// PaymentSettlement is the on-chain settlement of the cumulative payments of a reserved fund to a target
+type PaymentSettlement struct {
+	Target          common.Address
+	PaymentSequence uint64 // payment sequence of the latest settled payment
+	Amount          Coins  // cumulative amount settled
+	Height          uint64 // height of the first settlement, which opens the dispute window
+}
+
+type PaymentSettlementJSON struct {
+	Target          common.Address    `json:"target"`
+	PaymentSequence common.JSONUint64 `json:"payment_sequence"`
+	Amount          Coins             `json:"amount"`
+	Height          common.JSONUint64 `json:"height"`
+}
+
+func NewPaymentSettlementJSON(s PaymentSettlement) PaymentSettlementJSON {
+	return PaymentSettlementJSON{
+		Target:          s.Target,
+		PaymentSequence: common.JSONUint64(s.PaymentSequence),
+		Amount:          s.Amount,
+		Height:          common.JSONUint64(s.Height),
+	}
+}
+
+func (s PaymentSettlementJSON) PaymentSettlement() PaymentSettlement {
+	return PaymentSettlement{
+		Target:          s.Target,
+		PaymentSequence: uint64(s.PaymentSequence),
+		Amount:          s.Amount,
+		Height:          uint64(s.Height),
+	}
+}
+
+func (s PaymentSettlement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewPaymentSettlementJSON(s))
+}
+
+func (s *PaymentSettlement) UnmarshalJSON(data []byte) error {
+	var a PaymentSettlementJSON
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*s = a.PaymentSettlement()
+	return nil
+}
+
+// DisputeWindowEnd returns the last height at which the settlement can be overridden
+func (s *PaymentSettlement) DisputeWindowEnd() uint64 {
+	return s.Height + ServicePaymentDisputeWindow
+}
+
+// IsFinal returns true if the dispute window of the settlement has closed
+func (s *PaymentSettlement) IsFinal(currentBlockHeight uint64) bool {
+	return currentBlockHeight > s.DisputeWindowEnd()
+}
+
 type ReservedFund struct {
 	Collateral      Coins
 	InitialFund     Coins
@@ -20,6 +76,8 @@ type ReservedFund struct {
 	EndBlockHeight  uint64
 	ReserveSequence uint64           // sequence number of the corresponding ReserveFundTx transaction
 	TransferRecords []TransferRecord // signed ServerPaymentTransactions
+
+	Settlements []PaymentSettlement `rlp:"optional"` // Added in the PaymentDisputeWindow fork
 }
 
 type ReservedFundJSON struct {
@@ -30,6 +88,8 @@ type ReservedFundJSON struct {
 	EndBlockHeight  common.JSONUint64 `json:"end_block_height"`
 	ReserveSequence common.JSONUint64 `json:"reserve_sequence"` // sequence number of the corresponding ReserveFundTx transaction
 	TransferRecords []TransferRecord  `json:"transfer_records"` // signed ServerPaymentTransactions
+
+	Settlements []PaymentSettlement `json:"settlements,omitempty"`
 }
 
 func NewReservedFundJSON(resv ReservedFund) ReservedFundJSON {
@@ -41,6 +101,7 @@ func NewReservedFundJSON(resv ReservedFund) ReservedFundJSON {
 		EndBlockHeight:  common.JSONUint64(resv.EndBlockHeight),
 		ReserveSequence: common.JSONUint64(resv.ReserveSequence),
 		TransferRecords: resv.TransferRecords,
+		Settlements:     resv.Settlements,
 	}
 }
 
@@ -53,6 +114,7 @@ func (resv ReservedFundJSON) ReservedFund() ReservedFund {
 		EndBlockHeight:  uint64(resv.EndBlockHeight),
 		ReserveSequence: uint64(resv.ReserveSequence),
 		TransferRecords: resv.TransferRecords,
+		Settlements:     resv.Settlements,
 	}
 }
 
@@ -69,6 +131,34 @@ func (resv *ReservedFund) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// IsPandoFund returns true if the fund is reserved in PandoWei rather than PTXWei
+func (reservedFund *ReservedFund) IsPandoFund() bool {
+	initialFund := reservedFund.InitialFund.NoNil()
+	return initialFund.PandoWei.Cmp(Zero) > 0
+}
+
+// RemainingFund returns the part of the fund which has not been paid out yet
+func (reservedFund *ReservedFund) RemainingFund() Coins {
+	remainingFund := reservedFund.InitialFund.NoNil().Minus(reservedFund.UsedFund.NoNil())
+	if !remainingFund.IsNonnegative() {
+		return NewCoins(0, 0)
+	}
+	return remainingFund
+}
+
+// CheckPaymentCurrency verifies that a payment only contains the currency the fund was reserved in
+func (reservedFund *ReservedFund) CheckPaymentCurrency(amount Coins) error {
+	amount = amount.NoNil()
+	if reservedFund.IsPandoFund() {
+		if amount.PTXWei.Cmp(Zero) != 0 {
+			return errors.New("The reserved fund is in PandoWei, cannot pay in PTXWei")
+		}
+	} else if amount.PandoWei.Cmp(Zero) != 0 {
+		return errors.New("The reserved fund is in PTXWei, cannot pay in PandoWei")
+	}
+	return nil
+}
+
 // TODO: this implementation is not very efficient
 func (reservedFund *ReservedFund) VerifyPaymentSequence(targetAddress common.Address, paymentSequence uint64) error {
 	currentPaymentSequence := uint64(0)
@@ -103,3 +193,75 @@ func (reservedFund *ReservedFund) HasResourceID(resourceID string) bool {
 	}
 	return false
 }
+
+// GetSettlement returns the settlement of the payments to the target, or nil if the target has not
+// been settled yet
+func (reservedFund *ReservedFund) GetSettlement(targetAddress common.Address) *PaymentSettlement {
+	for idx := range reservedFund.Settlements {
+		if reservedFund.Settlements[idx].Target == targetAddress {
+			return &reservedFund.Settlements[idx]
+		}
+	}
+	return nil
+}
+
+// IsInDisputeWindow returns true if the target has been settled, and the settlement can still be overridden
+func (reservedFund *ReservedFund) IsInDisputeWindow(targetAddress common.Address, currentBlockHeight uint64) bool {
+	settlement := reservedFund.GetSettlement(targetAddress)
+	return settlement != nil && !settlement.IsFinal(currentBlockHeight)
+}
+
+// CheckSettlement verifies that a cumulative payment to the target can override its settlement. The
+// payment with the latest payment sequence wins, as long as the dispute window is open. The amount
+// already paid out cannot be taken back, so the cumulative amount cannot decrease.
+func (reservedFund *ReservedFund) CheckSettlement(targetAddress common.Address, paymentSequence uint64,
+	amount Coins, currentBlockHeight uint64) error {
+	settlement := reservedFund.GetSettlement(targetAddress)
+	if settlement == nil {
+		return nil
+	}
+	if settlement.IsFinal(currentBlockHeight) {
+		return errors.Errorf("The settlement for address %X is final since block height %d",
+			targetAddress, settlement.DisputeWindowEnd()+1)
+	}
+	if paymentSequence <= settlement.PaymentSequence {
+		return errors.Errorf("Stale payment sequence for address %X: %d, already settled %d",
+			targetAddress, paymentSequence, settlement.PaymentSequence)
+	}
+	if !amount.NoNil().IsGTE(settlement.Amount.NoNil()) {
+		return errors.Errorf("The cumulative amount %v for address %X is less than the settled amount %v",
+			amount, targetAddress, settlement.Amount)
+	}
+	return nil
+}
+
+// UnsettledAmount returns the part of the cumulative payment to the target which has not been paid out yet
+func (reservedFund *ReservedFund) UnsettledAmount(targetAddress common.Address, amount Coins) Coins {
+	amount = amount.NoNil()
+	settlement := reservedFund.GetSettlement(targetAddress)
+	if settlement == nil {
+		return amount
+	}
+	unsettled := amount.Minus(settlement.Amount.NoNil())
+	if !unsettled.IsNonnegative() {
+		return NewCoins(0, 0)
+	}
+	return unsettled
+}
+
+// Settle records the cumulative payment as the settlement of the target. The dispute window opens
+// with the first settlement of the target, and is not extended by the overrides.
+func (reservedFund *ReservedFund) Settle(targetAddress common.Address, paymentSequence uint64, amount Coins,
+	currentBlockHeight uint64) {
+	if settlement := reservedFund.GetSettlement(targetAddress); settlement != nil {
+		settlement.PaymentSequence = paymentSequence
+		settlement.Amount = amount.NoNil()
+		return
+	}
+	reservedFund.Settlements = append(reservedFund.Settlements, PaymentSettlement{
+		Target:          targetAddress,
+		PaymentSequence: paymentSequence,
+		Amount:          amount.NoNil(),
+		Height:          currentBlockHeight,
+	})
+}
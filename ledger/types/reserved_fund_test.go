@@ -7,6 +7,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/rlp"
 )
 
 func TestHasResourceID(t *testing.T) {
@@ -58,3 +61,58 @@ func TestReserveFundJSON(t *testing.T) {
 	require.Nil(err)
 	assert.Equal(uint64(math.MaxUint64), d.EndBlockHeight)
 }
+
+func TestCheckPaymentCurrency(t *testing.T) {
+	assert := assert.New(t)
+
+	ptxFund := ReservedFund{InitialFund: NewCoins(0, 1000)}
+	assert.False(ptxFund.IsPandoFund())
+	assert.Nil(ptxFund.CheckPaymentCurrency(NewCoins(0, 100)))
+	assert.NotNil(ptxFund.CheckPaymentCurrency(NewCoins(100, 0)))
+
+	pandoFund := ReservedFund{InitialFund: NewCoins(1000, 0)}
+	assert.True(pandoFund.IsPandoFund())
+	assert.Nil(pandoFund.CheckPaymentCurrency(NewCoins(100, 0)))
+	assert.NotNil(pandoFund.CheckPaymentCurrency(NewCoins(0, 100)))
+	assert.NotNil(pandoFund.CheckPaymentCurrency(NewCoins(100, 100)))
+}
+
+func TestPaymentSettlement(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	target := common.HexToAddress("0x2e833968e5bb786ae419c4d13189fb081cc43bab")
+	rf := ReservedFund{
+		InitialFund:    NewCoins(0, 1000),
+		UsedFund:       NewCoins(0, 0),
+		EndBlockHeight: 150,
+	}
+	assert.Equal(NewCoins(0, 50), rf.UnsettledAmount(target, NewCoins(0, 50)))
+	assert.Nil(rf.CheckSettlement(target, 1, NewCoins(0, 50), 100))
+
+	rf.Settle(target, 1, NewCoins(0, 50), 100)
+	assert.True(rf.IsInDisputeWindow(target, 100+ServicePaymentDisputeWindow))
+	assert.False(rf.IsInDisputeWindow(target, 101+ServicePaymentDisputeWindow))
+
+	// An override pays the difference with the settled amount
+	assert.Nil(rf.CheckSettlement(target, 2, NewCoins(0, 80), 120))
+	assert.Equal(NewCoins(0, 30), rf.UnsettledAmount(target, NewCoins(0, 80)))
+	assert.NotNil(rf.CheckSettlement(target, 1, NewCoins(0, 80), 120))
+	assert.NotNil(rf.CheckSettlement(target, 2, NewCoins(0, 40), 120))
+	assert.NotNil(rf.CheckSettlement(target, 2, NewCoins(0, 80), 101+ServicePaymentDisputeWindow))
+
+	// The dispute window is not extended by the overrides
+	rf.Settle(target, 2, NewCoins(0, 80), 120)
+	require.Equal(1, len(rf.Settlements))
+	assert.Equal(uint64(100), rf.Settlements[0].Height)
+	assert.Equal(uint64(2), rf.Settlements[0].PaymentSequence)
+
+	// The fund is released once the dispute window closes
+	assert.Equal(101+ServicePaymentDisputeWindow, calcMinimumReleaseBlockHeight(&rf))
+
+	raw, err := rlp.EncodeToBytes(rf)
+	require.Nil(err)
+	var decoded ReservedFund
+	require.Nil(rlp.DecodeBytes(raw, &decoded))
+	assert.Equal(rf.Settlements, decoded.Settlements)
+}
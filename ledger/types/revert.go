@@ -0,0 +1,43 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+)
+
+// revertSelector is the selector of Error(string), which the Solidity revert reasons are encoded with
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// UnpackRevertReason decodes the Error(string) revert reason of the EVM output, empty if the output is not one
+func UnpackRevertReason(output []byte) string {
+	if len(output) < 4+64 || !bytes.Equal(output[:4], revertSelector) {
+		return ""
+	}
+	reason, _ := UnpackABIString(output[4:])
+	return reason
+}
+
+// UnpackABIString decodes the ABI encoded string, e.g. the value returned by a contract method with a
+// single string output. It returns false if the data is not a valid encoding.
+func UnpackABIString(data []byte) (string, bool) {
+	if len(data) < 64 {
+		return "", false
+	}
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return "", false
+	}
+	start := offset.Uint64()
+	lengthBytes := data[start : start+32]
+	for _, b := range lengthBytes[:24] {
+		if b != 0 {
+			return "", false
+		}
+	}
+	length := binary.BigEndian.Uint64(lengthBytes[24:])
+	if length > uint64(len(data))-start-32 {
+		return "", false
+	}
+	return string(data[start+32 : start+32+length]), true
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnpackRevertReason(t *testing.T) {
+	assert := assert.New(t)
+
+	// revert("insufficient balance")
+	output, _ := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000014" +
+		"696e73756666696369656e742062616c616e6365000000000000000000000000")
+	assert.Equal("insufficient balance", UnpackRevertReason(output))
+
+	assert.Equal("", UnpackRevertReason(nil))
+	assert.Equal("", UnpackRevertReason(output[:40]))
+	output[4+31] = 0xff // invalid offset
+	assert.Equal("", UnpackRevertReason(output))
+}
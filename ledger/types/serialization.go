@@ -37,6 +37,29 @@ const (
 	TxDepositStake
 	TxWithdrawStake
 	TxDepositStakeV2
+	TxSessionKey
+	TxBatchSend
+	TxSlashAppeal
+	TxSlashAppealVote
+	TxClaimEscrow
+	TxMeteredSettlement
+	TxDelegate
+	TxUndelegate
+	TxSetCommission
+	TxRametronAttestation
+	TxKeyRotation
+	TxHTLCCreate
+	TxHTLCClaim
+	TxHTLCRefund
+	TxProposal
+	TxVote
+	TxBridgeLock
+	TxBridgeBurn
+	TxBridgeClaim
+	TxSubchainRegister
+	TxSubchainStake
+	TxSubchainCheckpoint
+	TxSubchainChallenge
 )
 
 func Fuzz(data []byte) int {
@@ -58,6 +81,7 @@ func Fuzz(data []byte) int {
 	return -1
 }
 
+// TxFromBytes decodes a transaction of any registered type
 func TxFromBytes(raw []byte) (Tx, error) {
 	var txType TxType
 	buff := bytes.NewBuffer(raw)
@@ -66,98 +90,46 @@ func TxFromBytes(raw []byte) (Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	if txType == TxCoinbase {
-		data := &CoinbaseTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxSlash {
-		data := &SlashTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxSend {
-		data := &SendTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxRametronStake {
-		data := &RametronStakeTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxReserveFund {
-		data := &ReserveFundTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxReleaseFund {
-		data := &ReleaseFundTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxServicePayment {
-		data := &ServicePaymentTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxSplitRule {
-		data := &SplitRuleTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxSmartContract {
-		data := &SmartContractTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxDepositStake {
-		data := &DepositStakeTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxWithdrawStake {
-		data := &WithdrawStakeTx{}
-		err = s.Decode(data)
-		return data, err
-	} else if txType == TxDepositStakeV2 {
-		data := &DepositStakeTxV2{}
-		err = s.Decode(data)
-		return data, err
-	} else {
+	spec, ok := LookupTxType(txType)
+	if !ok {
 		return nil, fmt.Errorf("Unknown TX type: %v", txType)
 	}
+	data := spec.New()
+	if spec.Decode != nil {
+		err = spec.Decode(s, data)
+	} else {
+		err = s.Decode(data)
+	}
+	return data, err
 }
 
+// GetTxType returns the type of the transaction
+func GetTxType(t Tx) (TxType, error) {
+	spec, ok := getTxTypeSpec(t)
+	if !ok {
+		return 0, errors.New("Unsupported message type")
+	}
+	return spec.Type, nil
+}
+
+// TxToBytes encodes the transaction, prefixed with its type
 func TxToBytes(t Tx) ([]byte, error) {
 	var buf bytes.Buffer
-	var txType TxType
-	switch t.(type) {
-	case *CoinbaseTx:
-		txType = TxCoinbase
-	case *SlashTx:
-		txType = TxSlash
-	case *SendTx:
-		txType = TxSend
-	case *RametronStakeTx:
-		txType = TxRametronStake
-	case *ReserveFundTx:
-		txType = TxReserveFund
-	case *ReleaseFundTx:
-		txType = TxReleaseFund
-	case *ServicePaymentTx:
-		txType = TxServicePayment
-	case *SplitRuleTx:
-		txType = TxSplitRule
-	case *SmartContractTx:
-		txType = TxSmartContract
-	case *DepositStakeTx:
-		txType = TxDepositStake
-	case *WithdrawStakeTx:
-		txType = TxWithdrawStake
-	case *DepositStakeTxV2:
-		txType = TxDepositStakeV2
-	default:
+	spec, ok := getTxTypeSpec(t)
+	if !ok {
 		return nil, errors.New("Unsupported message type")
 	}
-	err := rlp.Encode(&buf, txType)
+	err := rlp.Encode(&buf, spec.Type)
 	if err != nil {
 		return nil, err
 	}
-	err = rlp.Encode(&buf, t)
+	if spec.Encode != nil {
+		err = spec.Encode(&buf, t)
+	} else {
+		err = rlp.Encode(&buf, t)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
 }
-
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// SessionKeyTxTypes are the tx types a session key can be permitted to sign
+var SessionKeyTxTypes = []TxType{TxSend, TxSmartContract}
+
+// IsSessionKeyTxType indicates whether a session key can be permitted to sign the given tx type
+func IsSessionKeyTxType(txType TxType) bool {
+	for _, t := range SessionKeyTxTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionKey is a key which may sign transactions on behalf of an account, but only for
+// specific tx types, destinations, and cumulative amounts until an expiry height
+type SessionKey struct {
+	Address       common.Address   `json:"address"`        // address of the session key
+	TxTypes       []TxType         `json:"tx_types"`       // tx types the session key may sign
+	Destinations  []common.Address `json:"destinations"`   // destinations the session key may send to, empty means any destination
+	SpendingLimit Coins            `json:"spending_limit"` // cumulative amount the session key may spend, fee included
+	Spent         Coins            `json:"spent"`          // cumulative amount spent so far
+	ExpiryHeight  uint64           `json:"expiry_height"`  // the session key cannot sign after this height
+}
+
+func (sk *SessionKey) String() string {
+	return fmt.Sprintf("SessionKey{address: %v, tx_types: %v, destinations: %v, spending_limit: %v, spent: %v, expiry_height: %v}",
+		sk.Address, sk.TxTypes, sk.Destinations, sk.SpendingLimit, sk.Spent, sk.ExpiryHeight)
+}
+
+// IsExpired indicates whether the session key has expired at the given height
+func (sk *SessionKey) IsExpired(height uint64) bool {
+	return height > sk.ExpiryHeight
+}
+
+// Authorize checks whether the session key is permitted to sign a transaction of the given
+// type, sending the given amount to the given destinations at the given height
+func (sk *SessionKey) Authorize(txType TxType, destinations []common.Address, amount Coins, height uint64) error {
+	if sk.IsExpired(height) {
+		return fmt.Errorf("session key expired at height %v", sk.ExpiryHeight)
+	}
+
+	allowed := false
+	for _, t := range sk.TxTypes {
+		if t == txType {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("session key is not permitted to sign tx type %v", txType)
+	}
+
+	if len(sk.Destinations) > 0 {
+		for _, dest := range destinations {
+			if !sk.allowsDestination(dest) {
+				return fmt.Errorf("session key is not permitted to send to %v", dest.Hex())
+			}
+		}
+	}
+
+	spent := sk.Spent.Plus(amount)
+	if !sk.SpendingLimit.IsGTE(spent) {
+		return fmt.Errorf("session key spending limit %v exceeded, spent: %v, amount: %v", sk.SpendingLimit, sk.Spent, amount)
+	}
+	return nil
+}
+
+// Spend adds the amount to the cumulative spending of the session key
+func (sk *SessionKey) Spend(amount Coins) {
+	sk.Spent = sk.Spent.Plus(amount)
+}
+
+func (sk *SessionKey) allowsDestination(addr common.Address) bool {
+	for _, dest := range sk.Destinations {
+		if dest == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionKeySet is the set of the session keys of an account
+type SessionKeySet struct {
+	Keys []*SessionKey
+}
+
+// Len returns the number of session keys
+func (s *SessionKeySet) Len() int {
+	return len(s.Keys)
+}
+
+// Get returns the session key with the given address, nil if not found
+func (s *SessionKeySet) Get(addr common.Address) *SessionKey {
+	for _, sk := range s.Keys {
+		if sk.Address == addr {
+			return sk
+		}
+	}
+	return nil
+}
+
+// Set adds the session key, or replaces the session key with the same address
+func (s *SessionKeySet) Set(sessionKey *SessionKey) {
+	for i, sk := range s.Keys {
+		if sk.Address == sessionKey.Address {
+			s.Keys[i] = sessionKey
+			return
+		}
+	}
+	s.Keys = append(s.Keys, sessionKey)
+}
+
+// Remove removes the session key with the given address, and returns whether it was found
+func (s *SessionKeySet) Remove(addr common.Address) bool {
+	for i, sk := range s.Keys {
+		if sk.Address == addr {
+			s.Keys = append(s.Keys[:i], s.Keys[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveExpired removes the session keys that have expired at the given height
+func (s *SessionKeySet) RemoveExpired(height uint64) {
+	keys := []*SessionKey{}
+	for _, sk := range s.Keys {
+		if !sk.IsExpired(height) {
+			keys = append(keys, sk)
+		}
+	}
+	s.Keys = keys
+}
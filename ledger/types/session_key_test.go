@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionKeyAuthorize(t *testing.T) {
+	assert := assert.New(t)
+
+	dest1 := common.HexToAddress("0x0d2fD67d573c8ecB4161510fc00754d64B401F86")
+	dest2 := common.HexToAddress("0xdf1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E")
+
+	sk := &SessionKey{
+		Address:       common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"),
+		TxTypes:       []TxType{TxSend},
+		Destinations:  []common.Address{dest1},
+		SpendingLimit: NewCoins(100, 1000),
+		Spent:         NewCoins(0, 0),
+		ExpiryHeight:  200,
+	}
+
+	assert.Nil(sk.Authorize(TxSend, []common.Address{dest1}, NewCoins(100, 1000), 200))
+	assert.NotNil(sk.Authorize(TxSend, []common.Address{dest1}, NewCoins(1, 1), 201))
+	assert.NotNil(sk.Authorize(TxSmartContract, []common.Address{dest1}, NewCoins(1, 1), 100))
+	assert.NotNil(sk.Authorize(TxSend, []common.Address{dest1, dest2}, NewCoins(1, 1), 100))
+	assert.NotNil(sk.Authorize(TxSend, []common.Address{dest1}, NewCoins(101, 0), 100))
+
+	sk.Spend(NewCoins(50, 900))
+	assert.Nil(sk.Authorize(TxSend, []common.Address{dest1}, NewCoins(50, 100), 100))
+	assert.NotNil(sk.Authorize(TxSend, []common.Address{dest1}, NewCoins(0, 101), 100))
+
+	// Any destination is allowed if none is specified
+	sk.Destinations = []common.Address{}
+	assert.Nil(sk.Authorize(TxSend, []common.Address{dest2}, NewCoins(1, 1), 100))
+}
+
+func TestSessionKeySet(t *testing.T) {
+	assert := assert.New(t)
+
+	addr1 := common.HexToAddress("0x0d2fD67d573c8ecB4161510fc00754d64B401F86")
+	addr2 := common.HexToAddress("0xdf1f3D3eE9430dB3A44aE6B80Eb3E23352BB785E")
+	addr3 := common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+
+	set := &SessionKeySet{}
+	assert.Equal(0, set.Len())
+	assert.Nil(set.Get(addr1))
+
+	set.Set(&SessionKey{Address: addr1, ExpiryHeight: 100})
+	set.Set(&SessionKey{Address: addr2, ExpiryHeight: 200})
+	set.Set(&SessionKey{Address: addr3, ExpiryHeight: 300})
+	assert.Equal(3, set.Len())
+
+	set.Set(&SessionKey{Address: addr1, ExpiryHeight: 150})
+	assert.Equal(3, set.Len())
+	assert.Equal(uint64(150), set.Get(addr1).ExpiryHeight)
+
+	assert.True(set.Remove(addr2))
+	assert.False(set.Remove(addr2))
+	assert.Equal(2, set.Len())
+	assert.Nil(set.Get(addr2))
+
+	set.RemoveExpired(151)
+	assert.Equal(1, set.Len())
+	assert.Nil(set.Get(addr1))
+	assert.NotNil(set.Get(addr3))
+}
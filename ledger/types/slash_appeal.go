@@ -0,0 +1,194 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// SlashInsurancePoolAddress is the account of the insurance pool, which reimburses the slashes
+// reversed by approved appeals. It is funded by the forfeited appeal bonds, and anyone can
+// contribute to it by sending coins to this address.
+var SlashInsurancePoolAddress = common.HexToAddress("0x0000000000000000000000000000000000005349")
+
+// SlashRecord records a slash, so that the slashed account can appeal it
+type SlashRecord struct {
+	SlashedAddress  common.Address
+	ReserveSequence uint64
+	Amount          Coins          // the slashed amount
+	Beneficiary     common.Address // the account which received the slashed amount
+	Height          uint64         // height of the block which included the slash
+}
+
+type SlashRecordJSON struct {
+	SlashedAddress  common.Address    `json:"slashed_address"`
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+	Amount          Coins             `json:"amount"`
+	Beneficiary     common.Address    `json:"beneficiary"`
+	Height          common.JSONUint64 `json:"height"`
+}
+
+func NewSlashRecordJSON(a SlashRecord) SlashRecordJSON {
+	return SlashRecordJSON{
+		SlashedAddress:  a.SlashedAddress,
+		ReserveSequence: common.JSONUint64(a.ReserveSequence),
+		Amount:          a.Amount,
+		Beneficiary:     a.Beneficiary,
+		Height:          common.JSONUint64(a.Height),
+	}
+}
+
+func (a SlashRecordJSON) SlashRecord() SlashRecord {
+	return SlashRecord{
+		SlashedAddress:  a.SlashedAddress,
+		ReserveSequence: uint64(a.ReserveSequence),
+		Amount:          a.Amount,
+		Beneficiary:     a.Beneficiary,
+		Height:          uint64(a.Height),
+	}
+}
+
+func (a SlashRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewSlashRecordJSON(a))
+}
+
+func (a *SlashRecord) UnmarshalJSON(data []byte) error {
+	var b SlashRecordJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.SlashRecord()
+	return nil
+}
+
+func (sr *SlashRecord) String() string {
+	if sr == nil {
+		return "nil-SlashRecord"
+	}
+	return fmt.Sprintf("SlashRecord{slashed_address: %v, reserve_sequence: %v, amount: %v, beneficiary: %v, height: %v}",
+		sr.SlashedAddress, sr.ReserveSequence, sr.Amount, sr.Beneficiary, sr.Height)
+}
+
+// SlashAppeal is a pending appeal of a slash
+type SlashAppeal struct {
+	Appellant       common.Address
+	ReserveSequence uint64
+	Bond            Coins
+	FiledHeight     uint64
+	Approvals       []common.Address // the validators who approved the appeal
+}
+
+type SlashAppealJSON struct {
+	Appellant       common.Address    `json:"appellant"`
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+	Bond            Coins             `json:"bond"`
+	FiledHeight     common.JSONUint64 `json:"filed_height"`
+	Approvals       []common.Address  `json:"approvals"`
+}
+
+func NewSlashAppealJSON(a SlashAppeal) SlashAppealJSON {
+	return SlashAppealJSON{
+		Appellant:       a.Appellant,
+		ReserveSequence: common.JSONUint64(a.ReserveSequence),
+		Bond:            a.Bond,
+		FiledHeight:     common.JSONUint64(a.FiledHeight),
+		Approvals:       a.Approvals,
+	}
+}
+
+func (a SlashAppealJSON) SlashAppeal() SlashAppeal {
+	return SlashAppeal{
+		Appellant:       a.Appellant,
+		ReserveSequence: uint64(a.ReserveSequence),
+		Bond:            a.Bond,
+		FiledHeight:     uint64(a.FiledHeight),
+		Approvals:       a.Approvals,
+	}
+}
+
+func (a SlashAppeal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewSlashAppealJSON(a))
+}
+
+func (a *SlashAppeal) UnmarshalJSON(data []byte) error {
+	var b SlashAppealJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.SlashAppeal()
+	return nil
+}
+
+// HasApproved indicates whether the validator has approved the appeal
+func (sa *SlashAppeal) HasApproved(validator common.Address) bool {
+	for _, approval := range sa.Approvals {
+		if approval == validator {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpiryHeight returns the last height at which the appeal can be approved
+func (sa *SlashAppeal) ExpiryHeight() uint64 {
+	return sa.FiledHeight + SlashAppealWindow
+}
+
+func (sa *SlashAppeal) String() string {
+	if sa == nil {
+		return "nil-SlashAppeal"
+	}
+	return fmt.Sprintf("SlashAppeal{appellant: %v, reserve_sequence: %v, bond: %v, filed_height: %v, approvals: %v}",
+		sa.Appellant, sa.ReserveSequence, sa.Bond, sa.FiledHeight, sa.Approvals)
+}
+
+// SlashAppealSet keeps the pending slash appeals in the order they are filed
+type SlashAppealSet struct {
+	Appeals []*SlashAppeal
+}
+
+// Get returns the pending appeal of the given slash, nil if the slash is not being appealed
+func (s *SlashAppealSet) Get(appellant common.Address, reserveSequence uint64) *SlashAppeal {
+	for _, appeal := range s.Appeals {
+		if appeal.Appellant == appellant && appeal.ReserveSequence == reserveSequence {
+			return appeal
+		}
+	}
+	return nil
+}
+
+// Add adds a pending appeal
+func (s *SlashAppealSet) Add(appeal *SlashAppeal) {
+	s.Appeals = append(s.Appeals, appeal)
+}
+
+// Remove removes the pending appeal of the given slash
+func (s *SlashAppealSet) Remove(appellant common.Address, reserveSequence uint64) {
+	for idx, appeal := range s.Appeals {
+		if appeal.Appellant == appellant && appeal.ReserveSequence == reserveSequence {
+			s.Appeals = append(s.Appeals[:idx], s.Appeals[idx+1:]...)
+			return
+		}
+	}
+}
+
+// PopExpired removes and returns the appeals which can no longer be approved at the given height
+func (s *SlashAppealSet) PopExpired(height uint64) []*SlashAppeal {
+	expired := []*SlashAppeal{}
+	pending := []*SlashAppeal{}
+	for _, appeal := range s.Appeals {
+		if height > appeal.ExpiryHeight() {
+			expired = append(expired, appeal)
+		} else {
+			pending = append(pending, appeal)
+		}
+	}
+	s.Appeals = pending
+	return expired
+}
+
+// Len returns the number of pending appeals
+func (s *SlashAppealSet) Len() int {
+	return len(s.Appeals)
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlashAppealSet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	validator := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	appeals := &SlashAppealSet{}
+	appeals.Add(&SlashAppeal{Appellant: alice, ReserveSequence: 1, Bond: NewCoins(0, 10), FiledHeight: 100})
+	appeals.Add(&SlashAppeal{Appellant: alice, ReserveSequence: 2, Bond: NewCoins(0, 10), FiledHeight: 200})
+	appeals.Add(&SlashAppeal{Appellant: bob, ReserveSequence: 1, Bond: NewCoins(0, 20), FiledHeight: 150,
+		Approvals: []common.Address{validator}})
+
+	assert.Nil(appeals.Get(bob, 2))
+	assert.True(appeals.Get(bob, 1).HasApproved(validator))
+	assert.False(appeals.Get(alice, 1).HasApproved(validator))
+
+	raw, err := ToBytes(appeals)
+	require.Nil(err)
+	decoded := &SlashAppealSet{}
+	require.Nil(FromBytes(raw, decoded))
+	assert.Equal(3, decoded.Len())
+	assert.Equal([]common.Address{validator}, decoded.Get(bob, 1).Approvals)
+
+	// The appeals can be approved up to SlashAppealWindow blocks after they are filed
+	assert.Equal(0, len(appeals.PopExpired(100+SlashAppealWindow)))
+	expired := appeals.PopExpired(150 + SlashAppealWindow + 1)
+	require.Equal(2, len(expired))
+	assert.Equal(alice, expired[0].Appellant)
+	assert.Equal(bob, expired[1].Appellant)
+	assert.Equal(1, appeals.Len())
+
+	appeals.Remove(alice, 2)
+	assert.Equal(0, appeals.Len())
+}
@@ -78,3 +78,16 @@ func (sc *SplitRule) String() string {
 	return fmt.Sprintf("SplitRule{%v %v %v %v}",
 		sc.InitiatorAddress.Hex(), string(sc.ResourceID), sc.Splits, sc.EndBlockHeight)
 }
+
+// IsExpired returns true if the split rule no longer applies at the given block height
+func (sc *SplitRule) IsExpired(height uint64) bool {
+	return sc.EndBlockHeight < height
+}
+
+// RemainingDuration returns the number of blocks the split rule still applies for after the given block height
+func (sc *SplitRule) RemainingDuration(height uint64) uint64 {
+	if sc.IsExpired(height) {
+		return 0
+	}
+	return sc.EndBlockHeight - height
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// ParamStakeUnbondingPeriod is the governance parameter for the number of blocks a withdrawn stake
+// stays locked before it is returned to its source
+const ParamStakeUnbondingPeriod = "StakeUnbondingPeriod"
+
+// StakeUnbonding is a withdrawn stake waiting for the end of its unbonding period
+type StakeUnbonding struct {
+	Source         common.Address
+	Holder         common.Address
+	Purpose        uint8
+	Amount         *big.Int
+	WithdrawHeight uint64 // height at which the stake was withdrawn
+	ReleaseHeight  uint64 // height at which the stake is returned to the source
+}
+
+type StakeUnbondingJSON struct {
+	Source         common.Address    `json:"source"`
+	Holder         common.Address    `json:"holder"`
+	Purpose        uint8             `json:"purpose"`
+	Amount         *common.JSONBig   `json:"amount"`
+	WithdrawHeight common.JSONUint64 `json:"withdraw_height"`
+	ReleaseHeight  common.JSONUint64 `json:"release_height"`
+}
+
+func NewStakeUnbondingJSON(a StakeUnbonding) StakeUnbondingJSON {
+	return StakeUnbondingJSON{
+		Source:         a.Source,
+		Holder:         a.Holder,
+		Purpose:        a.Purpose,
+		Amount:         (*common.JSONBig)(a.Amount),
+		WithdrawHeight: common.JSONUint64(a.WithdrawHeight),
+		ReleaseHeight:  common.JSONUint64(a.ReleaseHeight),
+	}
+}
+
+func (a StakeUnbondingJSON) StakeUnbonding() StakeUnbonding {
+	return StakeUnbonding{
+		Source:         a.Source,
+		Holder:         a.Holder,
+		Purpose:        a.Purpose,
+		Amount:         (*big.Int)(a.Amount),
+		WithdrawHeight: uint64(a.WithdrawHeight),
+		ReleaseHeight:  uint64(a.ReleaseHeight),
+	}
+}
+
+func (a StakeUnbonding) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewStakeUnbondingJSON(a))
+}
+
+func (a *StakeUnbonding) UnmarshalJSON(data []byte) error {
+	var b StakeUnbondingJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.StakeUnbonding()
+	return nil
+}
+
+func (su *StakeUnbonding) String() string {
+	return fmt.Sprintf("StakeUnbonding{source: %v, holder: %v, purpose: %v, amount: %v, withdraw_height: %v, release_height: %v}",
+		su.Source, su.Holder, su.Purpose, su.Amount, su.WithdrawHeight, su.ReleaseHeight)
+}
+
+// StakeUnbondingQueue keeps the withdrawn stakes sorted by release height
+type StakeUnbondingQueue struct {
+	Entries []*StakeUnbonding
+}
+
+// Add adds a withdrawn stake to the queue. Stakes with the same release height are released
+// in the order they are added.
+func (q *StakeUnbondingQueue) Add(entry *StakeUnbonding) {
+	q.Entries = append(q.Entries, entry)
+	sort.SliceStable(q.Entries, func(i, j int) bool {
+		return q.Entries[i].ReleaseHeight < q.Entries[j].ReleaseHeight
+	})
+}
+
+// Get returns the unbonding stake of the source withdrawn from the holder, nil if there is none
+func (q *StakeUnbondingQueue) Get(source common.Address, holder common.Address, purpose uint8) *StakeUnbonding {
+	for _, entry := range q.Entries {
+		if entry.Source == source && entry.Holder == holder && entry.Purpose == purpose {
+			return entry
+		}
+	}
+	return nil
+}
+
+// PopReleased removes and returns the stakes released at or before the given height
+func (q *StakeUnbondingQueue) PopReleased(height uint64) []*StakeUnbonding {
+	idx := 0
+	for idx < len(q.Entries) && q.Entries[idx].ReleaseHeight <= height {
+		idx++
+	}
+	released := q.Entries[:idx]
+	q.Entries = q.Entries[idx:]
+	return released
+}
+
+// Len returns the number of unbonding stakes
+func (q *StakeUnbondingQueue) Len() int {
+	return len(q.Entries)
+}
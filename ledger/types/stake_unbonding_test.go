@@ -0,0 +1,44 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakeUnbondingQueue(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	validator := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	queue := &StakeUnbondingQueue{}
+	queue.Add(&StakeUnbonding{Source: alice, Holder: validator, Amount: big.NewInt(100), WithdrawHeight: 10, ReleaseHeight: 310})
+	queue.Add(&StakeUnbonding{Source: bob, Holder: validator, Amount: big.NewInt(200), WithdrawHeight: 20, ReleaseHeight: 120})
+	queue.Add(&StakeUnbonding{Source: alice, Holder: bob, Purpose: 1, Amount: big.NewInt(300), WithdrawHeight: 30, ReleaseHeight: 120})
+
+	assert.Nil(queue.Get(alice, validator, 1))
+	assert.Equal(uint64(310), queue.Get(alice, validator, 0).ReleaseHeight)
+
+	raw, err := ToBytes(queue)
+	require.Nil(err)
+	decoded := &StakeUnbondingQueue{}
+	require.Nil(FromBytes(raw, decoded))
+	assert.Equal(3, decoded.Len())
+	assert.Equal(big.NewInt(300), decoded.Get(alice, bob, 1).Amount)
+
+	// The stakes are released in the order of their release heights, then of their withdrawals
+	assert.Equal(0, len(queue.PopReleased(119)))
+	released := queue.PopReleased(120)
+	require.Equal(2, len(released))
+	assert.Equal(bob, released[0].Source)
+	assert.Equal(alice, released[1].Source)
+	assert.Equal(1, queue.Len())
+	assert.Equal(1, len(queue.PopReleased(1000)))
+	assert.Equal(0, queue.Len())
+}
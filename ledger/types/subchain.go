@@ -0,0 +1,547 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// Subchain is an app-specific chain registered on the main chain by a SubchainRegisterTx. The
+// subchain validators stake Pando on the main chain with SubchainStakeTxs, and periodically anchor
+// the subchain on the main chain with SubchainCheckpointTxs signed by 2/3 of their stake. A
+// checkpoint is only finalized after its fraud proof window, during which anyone can prove that
+// the validators signed a conflicting checkpoint with a SubchainChallengeTx, which slashes them.
+type Subchain struct {
+	ID                 common.Hash
+	ChainID            string
+	Owner              common.Address // registered the subchain
+	Deposit            Coins          // the registration deposit, locked for the lifetime of the subchain
+	CheckpointInterval uint64         // the number of subchain blocks between two checkpoints
+	RegisteredHeight   uint64
+	Stakes             []*SubchainStake
+	LastCheckpoint     SubchainCheckpoint    // the latest finalized checkpoint, empty if there is none yet
+	Pending            []*SubchainCheckpoint // the checkpoints within their fraud proof window, by height
+}
+
+type SubchainJSON struct {
+	ID                 common.Hash           `json:"id"`
+	ChainID            string                `json:"chain_id"`
+	Owner              common.Address        `json:"owner"`
+	Deposit            Coins                 `json:"deposit"`
+	CheckpointInterval common.JSONUint64     `json:"checkpoint_interval"`
+	RegisteredHeight   common.JSONUint64     `json:"registered_height"`
+	Stakes             []*SubchainStake      `json:"stakes"`
+	LastCheckpoint     *SubchainCheckpoint   `json:"last_checkpoint"`
+	Pending            []*SubchainCheckpoint `json:"pending"`
+}
+
+func NewSubchainJSON(a Subchain) SubchainJSON {
+	var lastCheckpoint *SubchainCheckpoint
+	if a.HasCheckpoint() {
+		lastCheckpoint = &a.LastCheckpoint
+	}
+	return SubchainJSON{
+		ID:                 a.ID,
+		ChainID:            a.ChainID,
+		Owner:              a.Owner,
+		Deposit:            a.Deposit,
+		CheckpointInterval: common.JSONUint64(a.CheckpointInterval),
+		RegisteredHeight:   common.JSONUint64(a.RegisteredHeight),
+		Stakes:             a.Stakes,
+		LastCheckpoint:     lastCheckpoint,
+		Pending:            a.Pending,
+	}
+}
+
+func (a SubchainJSON) Subchain() Subchain {
+	subchain := Subchain{
+		ID:                 a.ID,
+		ChainID:            a.ChainID,
+		Owner:              a.Owner,
+		Deposit:            a.Deposit,
+		CheckpointInterval: uint64(a.CheckpointInterval),
+		RegisteredHeight:   uint64(a.RegisteredHeight),
+		Stakes:             a.Stakes,
+		Pending:            a.Pending,
+	}
+	if a.LastCheckpoint != nil {
+		subchain.LastCheckpoint = *a.LastCheckpoint
+	}
+	return subchain
+}
+
+func (a Subchain) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewSubchainJSON(a))
+}
+
+func (a *Subchain) UnmarshalJSON(data []byte) error {
+	var b SubchainJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.Subchain()
+	return nil
+}
+
+func (s *Subchain) String() string {
+	return fmt.Sprintf("Subchain{id: %v, chain_id: %v, owner: %v, checkpoint_interval: %v, stakes: %v, last_checkpoint: %v, pending: %v}",
+		s.ID.Hex(), s.ChainID, s.Owner.Hex(), s.CheckpointInterval, len(s.Stakes), s.LastCheckpoint.Height, len(s.Pending))
+}
+
+// SubchainStake is the stake of a subchain validator
+type SubchainStake struct {
+	Staker       common.Address
+	Amount       *big.Int // in PandoWei
+	Withdrawn    bool
+	ReturnHeight uint64 // height at which the withdrawn stake is returned, once the pending checkpoints it signed are final
+}
+
+type SubchainStakeJSON struct {
+	Staker       common.Address    `json:"staker"`
+	Amount       *common.JSONBig   `json:"amount"`
+	Withdrawn    bool              `json:"withdrawn"`
+	ReturnHeight common.JSONUint64 `json:"return_height"`
+}
+
+func NewSubchainStakeJSON(a SubchainStake) SubchainStakeJSON {
+	return SubchainStakeJSON{
+		Staker:       a.Staker,
+		Amount:       (*common.JSONBig)(a.Amount),
+		Withdrawn:    a.Withdrawn,
+		ReturnHeight: common.JSONUint64(a.ReturnHeight),
+	}
+}
+
+func (a SubchainStakeJSON) SubchainStake() SubchainStake {
+	return SubchainStake{
+		Staker:       a.Staker,
+		Amount:       (*big.Int)(a.Amount),
+		Withdrawn:    a.Withdrawn,
+		ReturnHeight: uint64(a.ReturnHeight),
+	}
+}
+
+func (a SubchainStake) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewSubchainStakeJSON(a))
+}
+
+func (a *SubchainStake) UnmarshalJSON(data []byte) error {
+	var b SubchainStakeJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.SubchainStake()
+	return nil
+}
+
+// SubchainCheckpoint anchors a block of a subchain on the main chain
+type SubchainCheckpoint struct {
+	SubchainID     common.Hash
+	Height         uint64      // height of the subchain block
+	BlockHash      common.Hash // hash of the subchain block
+	StateRoot      common.Hash // state root of the subchain block
+	Submitter      common.Address
+	SubmitHeight   uint64           // height of the main chain block anchoring the checkpoint
+	FinalizeHeight uint64           // height of the main chain block at which the fraud proof window ends
+	Signers        []common.Address // the subchain validators who signed the checkpoint
+}
+
+type SubchainCheckpointJSON struct {
+	SubchainID     common.Hash       `json:"subchain_id"`
+	Height         common.JSONUint64 `json:"height"`
+	BlockHash      common.Hash       `json:"block_hash"`
+	StateRoot      common.Hash       `json:"state_root"`
+	Submitter      common.Address    `json:"submitter"`
+	SubmitHeight   common.JSONUint64 `json:"submit_height"`
+	FinalizeHeight common.JSONUint64 `json:"finalize_height"`
+	Signers        []common.Address  `json:"signers"`
+}
+
+func NewSubchainCheckpointJSON(a SubchainCheckpoint) SubchainCheckpointJSON {
+	return SubchainCheckpointJSON{
+		SubchainID:     a.SubchainID,
+		Height:         common.JSONUint64(a.Height),
+		BlockHash:      a.BlockHash,
+		StateRoot:      a.StateRoot,
+		Submitter:      a.Submitter,
+		SubmitHeight:   common.JSONUint64(a.SubmitHeight),
+		FinalizeHeight: common.JSONUint64(a.FinalizeHeight),
+		Signers:        a.Signers,
+	}
+}
+
+func (a SubchainCheckpointJSON) SubchainCheckpoint() SubchainCheckpoint {
+	return SubchainCheckpoint{
+		SubchainID:     a.SubchainID,
+		Height:         uint64(a.Height),
+		BlockHash:      a.BlockHash,
+		StateRoot:      a.StateRoot,
+		Submitter:      a.Submitter,
+		SubmitHeight:   uint64(a.SubmitHeight),
+		FinalizeHeight: uint64(a.FinalizeHeight),
+		Signers:        a.Signers,
+	}
+}
+
+func (a SubchainCheckpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewSubchainCheckpointJSON(a))
+}
+
+func (a *SubchainCheckpoint) UnmarshalJSON(data []byte) error {
+	var b SubchainCheckpointJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.SubchainCheckpoint()
+	return nil
+}
+
+// SignBytes returns the bytes the subchain validators sign for the checkpoint. Only the subchain
+// block is signed, along with the chain ID of Pando so that the signatures cannot be replayed on
+// another network.
+func (c *SubchainCheckpoint) SignBytes(chainID string) common.Bytes {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"pando-subchain-checkpoint", chainID, c.SubchainID, c.Height, c.BlockHash, c.StateRoot})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the subchain checkpoint: %v", err))
+	}
+	return encoded
+}
+
+// ConflictsWith returns whether the two checkpoints anchor different blocks at the same height
+func (c *SubchainCheckpoint) ConflictsWith(other *SubchainCheckpoint) bool {
+	return c.SubchainID == other.SubchainID && c.Height == other.Height &&
+		(c.BlockHash != other.BlockHash || c.StateRoot != other.StateRoot)
+}
+
+func (c *SubchainCheckpoint) String() string {
+	return fmt.Sprintf("SubchainCheckpoint{subchain_id: %v, height: %v, block_hash: %v, state_root: %v, submit_height: %v, finalize_height: %v, signers: %v}",
+		c.SubchainID.Hex(), c.Height, c.BlockHash.Hex(), c.StateRoot.Hex(), c.SubmitHeight, c.FinalizeHeight, len(c.Signers))
+}
+
+// SubchainID returns the ID of the subchain with the given chain ID
+func SubchainID(chainID string) common.Hash {
+	encoded, err := rlp.EncodeToBytes([]interface{}{"subchain", chainID})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the subchain ID: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// MinimumSubchainDeposit returns the minimum deposit locked by the registration of a subchain, in PTXWei
+func MinimumSubchainDeposit() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(MinimumSubchainDepositPTX), big.NewInt(1e18))
+}
+
+// MinimumSubchainStake returns the minimum stake of a subchain validator, in PandoWei
+func MinimumSubchainStake() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(MinimumSubchainStakePando), big.NewInt(1e18))
+}
+
+// CheckSubchainChainID checks the chain ID of a subchain
+func CheckSubchainChainID(chainID string) error {
+	if len(chainID) == 0 || len(chainID) > MaximumSubchainChainIDLength {
+		return fmt.Errorf("Invalid subchain ID %q, needs to be between 1 and %v bytes", chainID, MaximumSubchainChainIDLength)
+	}
+	return nil
+}
+
+// HasCheckpoint returns whether a checkpoint of the subchain has been finalized
+func (s *Subchain) HasCheckpoint() bool {
+	return s.LastCheckpoint.BlockHash != (common.Hash{})
+}
+
+// NextCheckpointHeight returns the subchain height of the next checkpoint, which extends the latest
+// pending or finalized checkpoint by the checkpoint interval
+func (s *Subchain) NextCheckpointHeight() uint64 {
+	if len(s.Pending) > 0 {
+		return s.Pending[len(s.Pending)-1].Height + s.CheckpointInterval
+	}
+	return s.LastCheckpoint.Height + s.CheckpointInterval
+}
+
+// GetPending returns the pending checkpoint at the given subchain height, nil if there is none
+func (s *Subchain) GetPending(height uint64) *SubchainCheckpoint {
+	for _, checkpoint := range s.Pending {
+		if checkpoint.Height == height {
+			return checkpoint
+		}
+	}
+	return nil
+}
+
+// GetStake returns the stake of the staker, nil if there is none
+func (s *Subchain) GetStake(staker common.Address) *SubchainStake {
+	for _, stake := range s.Stakes {
+		if stake.Staker == staker {
+			return stake
+		}
+	}
+	return nil
+}
+
+// DepositStake adds the amount to the stake of the staker
+func (s *Subchain) DepositStake(staker common.Address, amount *big.Int) error {
+	stake := s.GetStake(staker)
+	if stake == nil {
+		if len(s.Stakes) >= MaximumSubchainStakers {
+			return fmt.Errorf("The subchain already has the maximum number of %v stakers", MaximumSubchainStakers)
+		}
+		s.Stakes = append(s.Stakes, &SubchainStake{Staker: staker, Amount: new(big.Int).Set(amount)})
+		return nil
+	}
+	if stake.Withdrawn {
+		return errors.New("The stake is being withdrawn")
+	}
+	stake.Amount = new(big.Int).Add(stake.Amount, amount)
+	return nil
+}
+
+// WithdrawStake marks the stake of the staker as withdrawn. It stops signing checkpoints, but stays
+// slashable until it is returned at the return height.
+func (s *Subchain) WithdrawStake(staker common.Address, returnHeight uint64) error {
+	stake := s.GetStake(staker)
+	if stake == nil {
+		return fmt.Errorf("%v has no stake in the subchain", staker.Hex())
+	}
+	if stake.Withdrawn {
+		return errors.New("The stake is already being withdrawn")
+	}
+	stake.Withdrawn = true
+	stake.ReturnHeight = returnHeight
+	return nil
+}
+
+// ActiveStake returns the total stake of the subchain validators which did not withdraw their stake
+func (s *Subchain) ActiveStake() *big.Int {
+	total := new(big.Int)
+	for _, stake := range s.Stakes {
+		if !stake.Withdrawn {
+			total.Add(total, stake.Amount)
+		}
+	}
+	return total
+}
+
+// RecoverSigners returns the distinct stakers who signed the checkpoint, and their total stake. The
+// withdrawn stakes are included only if includeWithdrawn is set, since they can no longer sign new
+// checkpoints, but are still accountable for the checkpoints they signed.
+func (s *Subchain) RecoverSigners(chainID string, checkpoint *SubchainCheckpoint, sigs []*crypto.Signature,
+	includeWithdrawn bool) ([]common.Address, *big.Int, error) {
+	signBytes := checkpoint.SignBytes(chainID)
+	signers := []common.Address{}
+	signed := make(map[common.Address]bool, len(sigs))
+	signedStake := new(big.Int)
+	for _, sig := range sigs {
+		if sig == nil {
+			return nil, nil, errors.New("Empty signature")
+		}
+		signer, err := sig.RecoverSignerAddress(signBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid signature: %v", err)
+		}
+		stake := s.GetStake(signer)
+		if stake == nil || (stake.Withdrawn && !includeWithdrawn) {
+			return nil, nil, fmt.Errorf("%v is not a validator of the subchain", signer.Hex())
+		}
+		if signed[signer] {
+			return nil, nil, fmt.Errorf("%v signed the checkpoint more than once", signer.Hex())
+		}
+		signed[signer] = true
+		signers = append(signers, signer)
+		signedStake.Add(signedStake, stake.Amount)
+	}
+	return signers, signedStake, nil
+}
+
+// VerifyCheckpoint checks that the checkpoint extends the subchain, and is signed by more than 2/3 of
+// the active stake. It returns the signers.
+func (s *Subchain) VerifyCheckpoint(chainID string, checkpoint *SubchainCheckpoint, sigs []*crypto.Signature) ([]common.Address, error) {
+	if checkpoint.SubchainID != s.ID {
+		return nil, errors.New("The checkpoint is not for this subchain")
+	}
+	if expected := s.NextCheckpointHeight(); checkpoint.Height != expected {
+		return nil, fmt.Errorf("The next checkpoint needs to be at height %v, got %v", expected, checkpoint.Height)
+	}
+	if checkpoint.BlockHash == (common.Hash{}) {
+		return nil, errors.New("The block hash is not specified")
+	}
+
+	signers, signedStake, err := s.RecoverSigners(chainID, checkpoint, sigs, false)
+	if err != nil {
+		return nil, err
+	}
+	activeStake := s.ActiveStake()
+	if activeStake.Sign() == 0 {
+		return nil, errors.New("The subchain has no active stake")
+	}
+	// signedStake / activeStake > 2 / 3
+	if new(big.Int).Mul(signedStake, big.NewInt(3)).Cmp(new(big.Int).Mul(activeStake, big.NewInt(2))) <= 0 {
+		return nil, fmt.Errorf("Insufficient signatures, %v of the stake of %v signed, more than 2/3 required", signedStake, activeStake)
+	}
+	return signers, nil
+}
+
+// VerifyChallenge checks the conflicting checkpoint of a challenge against the pending checkpoint at
+// the same height, and returns the equivocators, i.e. the stakers who signed both. The challenge is
+// valid only if the equivocators hold more than 1/3 of the slashable stake, since two conflicting
+// checkpoints can only both be signed by 2/3 of the stake if more than 1/3 of it equivocates.
+func (s *Subchain) VerifyChallenge(chainID string, conflict *SubchainCheckpoint, sigs []*crypto.Signature) ([]common.Address, error) {
+	pending := s.GetPending(conflict.Height)
+	if pending == nil {
+		return nil, fmt.Errorf("There is no pending checkpoint at height %v", conflict.Height)
+	}
+	if !pending.ConflictsWith(conflict) {
+		return nil, errors.New("The checkpoint does not conflict with the pending checkpoint")
+	}
+
+	signers, _, err := s.RecoverSigners(chainID, conflict, sigs, true)
+	if err != nil {
+		return nil, err
+	}
+	signedPending := make(map[common.Address]bool, len(pending.Signers))
+	for _, signer := range pending.Signers {
+		signedPending[signer] = true
+	}
+	equivocators := []common.Address{}
+	equivocatedStake := new(big.Int)
+	for _, signer := range signers {
+		if signedPending[signer] {
+			equivocators = append(equivocators, signer)
+			equivocatedStake.Add(equivocatedStake, s.GetStake(signer).Amount)
+		}
+	}
+
+	// equivocatedStake / totalStake > 1 / 3, where the withdrawn stakes count, since they may have
+	// signed the pending checkpoint before withdrawing
+	totalStake := new(big.Int)
+	for _, stake := range s.Stakes {
+		totalStake.Add(totalStake, stake.Amount)
+	}
+	if new(big.Int).Mul(equivocatedStake, big.NewInt(3)).Cmp(totalStake) <= 0 {
+		return nil, fmt.Errorf("Insufficient equivocation, %v of the stake of %v signed both checkpoints, more than 1/3 required",
+			equivocatedStake, totalStake)
+	}
+	return equivocators, nil
+}
+
+// AddPending adds a verified checkpoint to the pending checkpoints
+func (s *Subchain) AddPending(checkpoint *SubchainCheckpoint) {
+	s.Pending = append(s.Pending, checkpoint)
+	sort.SliceStable(s.Pending, func(i, j int) bool {
+		return s.Pending[i].Height < s.Pending[j].Height
+	})
+}
+
+// RevertFrom removes the pending checkpoints from the given subchain height, which are disproven or
+// build on a disproven checkpoint
+func (s *Subchain) RevertFrom(height uint64) []*SubchainCheckpoint {
+	idx := 0
+	for idx < len(s.Pending) && s.Pending[idx].Height < height {
+		idx++
+	}
+	reverted := append([]*SubchainCheckpoint{}, s.Pending[idx:]...)
+	s.Pending = s.Pending[:idx]
+	return reverted
+}
+
+// Slash removes the stakes of the stakers, and returns the total slashed amount
+func (s *Subchain) Slash(stakers []common.Address) *big.Int {
+	slashed := new(big.Int)
+	remaining := []*SubchainStake{}
+	for _, stake := range s.Stakes {
+		isSlashed := false
+		for _, staker := range stakers {
+			if stake.Staker == staker {
+				isSlashed = true
+				break
+			}
+		}
+		if isSlashed {
+			slashed.Add(slashed, stake.Amount)
+		} else {
+			remaining = append(remaining, stake)
+		}
+	}
+	s.Stakes = remaining
+	return slashed
+}
+
+// PopFinalized finalizes the pending checkpoints whose fraud proof window ended at or before the
+// given height, and returns them
+func (s *Subchain) PopFinalized(height uint64) []*SubchainCheckpoint {
+	idx := 0
+	for idx < len(s.Pending) && s.Pending[idx].FinalizeHeight <= height {
+		idx++
+	}
+	finalized := s.Pending[:idx]
+	s.Pending = s.Pending[idx:]
+	if len(finalized) > 0 {
+		s.LastCheckpoint = *finalized[len(finalized)-1]
+	}
+	return finalized
+}
+
+// PopReturned removes and returns the withdrawn stakes to return at or before the given height
+func (s *Subchain) PopReturned(height uint64) []*SubchainStake {
+	returned := []*SubchainStake{}
+	remaining := []*SubchainStake{}
+	for _, stake := range s.Stakes {
+		if stake.Withdrawn && stake.ReturnHeight <= height {
+			returned = append(returned, stake)
+		} else {
+			remaining = append(remaining, stake)
+		}
+	}
+	s.Stakes = remaining
+	return returned
+}
+
+// SubchainUpdate schedules the processing of a subchain at a height, i.e. the finalization of its
+// pending checkpoints, and the return of its withdrawn stakes
+type SubchainUpdate struct {
+	SubchainID common.Hash
+	Height     uint64
+}
+
+// SubchainUpdateQueue keeps the scheduled subchain updates sorted by height
+type SubchainUpdateQueue struct {
+	Entries []*SubchainUpdate
+}
+
+// Add schedules an update of the subchain at the height
+func (q *SubchainUpdateQueue) Add(subchainID common.Hash, height uint64) {
+	q.Entries = append(q.Entries, &SubchainUpdate{SubchainID: subchainID, Height: height})
+	sort.SliceStable(q.Entries, func(i, j int) bool {
+		return q.Entries[i].Height < q.Entries[j].Height
+	})
+}
+
+// PopDue removes the updates scheduled at or before the given height, and returns the IDs of the
+// subchains to update, in the order they are first scheduled
+func (q *SubchainUpdateQueue) PopDue(height uint64) []common.Hash {
+	idx := 0
+	for idx < len(q.Entries) && q.Entries[idx].Height <= height {
+		idx++
+	}
+	ids := []common.Hash{}
+	seen := make(map[common.Hash]bool)
+	for _, entry := range q.Entries[:idx] {
+		if !seen[entry.SubchainID] {
+			seen[entry.SubchainID] = true
+			ids = append(ids, entry.SubchainID)
+		}
+	}
+	q.Entries = q.Entries[idx:]
+	return ids
+}
+
+// Len returns the number of scheduled updates
+func (q *SubchainUpdateQueue) Len() int {
+	return len(q.Entries)
+}
@@ -0,0 +1,180 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+)
+
+func TestSubchainCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	val1, val2, val3, outsider := MakeAcc("val1"), MakeAcc("val2"), MakeAcc("val3"), MakeAcc("outsider")
+	chainID := "pandonet"
+	subchain := &Subchain{ID: SubchainID("gamechain"), ChainID: "gamechain", CheckpointInterval: 100}
+	assert.Nil(subchain.DepositStake(val1.Address, big.NewInt(400)))
+	assert.Nil(subchain.DepositStake(val2.Address, big.NewInt(300)))
+	assert.Nil(subchain.DepositStake(val3.Address, big.NewInt(300)))
+	assert.Equal(big.NewInt(1000), subchain.ActiveStake())
+	assert.Equal(uint64(100), subchain.NextCheckpointHeight())
+
+	checkpoint := &SubchainCheckpoint{
+		SubchainID: subchain.ID,
+		Height:     100,
+		BlockHash:  common.HexToHash("0x1"),
+		StateRoot:  common.HexToHash("0x2"),
+	}
+	sign := func(cp *SubchainCheckpoint, signers ...PrivAccount) []*crypto.Signature {
+		sigs := []*crypto.Signature{}
+		for _, signer := range signers {
+			sigs = append(sigs, signer.Sign(cp.SignBytes(chainID)))
+		}
+		return sigs
+	}
+
+	// 70% of the stake signs the checkpoint
+	signers, err := subchain.VerifyCheckpoint(chainID, checkpoint, sign(checkpoint, val1, val2))
+	assert.Nil(err)
+	assert.Equal([]common.Address{val1.Address, val2.Address}, signers)
+
+	// 60% of the stake is not more than 2/3
+	_, err = subchain.VerifyCheckpoint(chainID, checkpoint, sign(checkpoint, val2, val3))
+	assert.NotNil(err)
+
+	// The signatures need to be of distinct subchain validators, for the same network
+	_, err = subchain.VerifyCheckpoint(chainID, checkpoint, sign(checkpoint, val1, val1, val2))
+	assert.NotNil(err)
+	_, err = subchain.VerifyCheckpoint(chainID, checkpoint, sign(checkpoint, val1, val2, outsider))
+	assert.NotNil(err)
+	_, err = subchain.VerifyCheckpoint("testnet", checkpoint, sign(checkpoint, val1, val2))
+	assert.NotNil(err)
+
+	// The checkpoints need to be sequential
+	skipped := &SubchainCheckpoint{SubchainID: subchain.ID, Height: 200, BlockHash: common.HexToHash("0x1")}
+	_, err = subchain.VerifyCheckpoint(chainID, skipped, sign(skipped, val1, val2, val3))
+	assert.NotNil(err)
+
+	// A withdrawn stake no longer signs new checkpoints
+	withdrawing := &Subchain{ID: subchain.ID, CheckpointInterval: 100}
+	assert.Nil(withdrawing.DepositStake(val1.Address, big.NewInt(400)))
+	assert.Nil(withdrawing.DepositStake(val2.Address, big.NewInt(300)))
+	assert.Nil(withdrawing.WithdrawStake(val2.Address, 1000))
+	assert.NotNil(withdrawing.WithdrawStake(val2.Address, 1000))
+	assert.NotNil(withdrawing.DepositStake(val2.Address, big.NewInt(100)))
+	assert.Equal(big.NewInt(400), withdrawing.ActiveStake())
+	_, err = withdrawing.VerifyCheckpoint(chainID, checkpoint, sign(checkpoint, val1, val2))
+	assert.NotNil(err)
+	_, err = withdrawing.VerifyCheckpoint(chainID, checkpoint, sign(checkpoint, val1))
+	assert.Nil(err)
+}
+
+func TestSubchainChallenge(t *testing.T) {
+	assert := assert.New(t)
+
+	val1, val2, val3 := MakeAcc("val1"), MakeAcc("val2"), MakeAcc("val3")
+	chainID := "pandonet"
+	subchain := &Subchain{ID: SubchainID("gamechain"), ChainID: "gamechain", CheckpointInterval: 100}
+	assert.Nil(subchain.DepositStake(val1.Address, big.NewInt(400)))
+	assert.Nil(subchain.DepositStake(val2.Address, big.NewInt(300)))
+	assert.Nil(subchain.DepositStake(val3.Address, big.NewInt(300)))
+
+	sign := func(cp *SubchainCheckpoint, signers ...PrivAccount) []*crypto.Signature {
+		sigs := []*crypto.Signature{}
+		for _, signer := range signers {
+			sigs = append(sigs, signer.Sign(cp.SignBytes(chainID)))
+		}
+		return sigs
+	}
+	for height := uint64(100); height <= 200; height += 100 {
+		checkpoint := &SubchainCheckpoint{SubchainID: subchain.ID, Height: height, BlockHash: common.BigToHash(big.NewInt(int64(height)))}
+		signers, err := subchain.VerifyCheckpoint(chainID, checkpoint, sign(checkpoint, val1, val2))
+		assert.Nil(err)
+		checkpoint.Signers = signers
+		checkpoint.FinalizeHeight = 1000 + height
+		subchain.AddPending(checkpoint)
+	}
+	assert.Equal(uint64(300), subchain.NextCheckpointHeight())
+
+	conflict := &SubchainCheckpoint{SubchainID: subchain.ID, Height: 100, BlockHash: common.HexToHash("0xbad")}
+
+	// The same block is not a conflict, and there needs to be a pending checkpoint at the height
+	_, err := subchain.VerifyChallenge(chainID, subchain.GetPending(100), sign(subchain.GetPending(100), val1, val2))
+	assert.NotNil(err)
+	missing := &SubchainCheckpoint{SubchainID: subchain.ID, Height: 300, BlockHash: common.HexToHash("0xbad")}
+	_, err = subchain.VerifyChallenge(chainID, missing, sign(missing, val1, val2))
+	assert.NotNil(err)
+
+	// Only the stake which signed both checkpoints equivocates: val3 alone is not enough, and 30% is not more than 1/3
+	_, err = subchain.VerifyChallenge(chainID, conflict, sign(conflict, val3))
+	assert.NotNil(err)
+	_, err = subchain.VerifyChallenge(chainID, conflict, sign(conflict, val2, val3))
+	assert.NotNil(err)
+
+	// val1 equivocates with 40% of the stake, and stays accountable after withdrawing
+	assert.Nil(subchain.WithdrawStake(val1.Address, 5000))
+	equivocators, err := subchain.VerifyChallenge(chainID, conflict, sign(conflict, val1, val3))
+	assert.Nil(err)
+	assert.Equal([]common.Address{val1.Address}, equivocators)
+
+	reverted := subchain.RevertFrom(conflict.Height)
+	assert.Equal(2, len(reverted))
+	assert.Equal(0, len(subchain.Pending))
+	assert.Equal(big.NewInt(400), subchain.Slash(equivocators))
+	assert.Nil(subchain.GetStake(val1.Address))
+	assert.Equal(uint64(100), subchain.NextCheckpointHeight())
+}
+
+func TestSubchainFinalization(t *testing.T) {
+	assert := assert.New(t)
+
+	val1, val2 := MakeAcc("val1"), MakeAcc("val2")
+	subchain := &Subchain{ID: SubchainID("gamechain"), ChainID: "gamechain", CheckpointInterval: 100}
+	assert.Nil(subchain.DepositStake(val1.Address, big.NewInt(400)))
+	assert.Nil(subchain.DepositStake(val2.Address, big.NewInt(300)))
+	assert.Nil(subchain.WithdrawStake(val2.Address, 50))
+
+	subchain.AddPending(&SubchainCheckpoint{SubchainID: subchain.ID, Height: 200, BlockHash: common.HexToHash("0x2"), FinalizeHeight: 60})
+	subchain.AddPending(&SubchainCheckpoint{SubchainID: subchain.ID, Height: 100, BlockHash: common.HexToHash("0x1"), FinalizeHeight: 40})
+	assert.False(subchain.HasCheckpoint())
+
+	assert.Equal(0, len(subchain.PopFinalized(39)))
+	assert.Equal(0, len(subchain.PopReturned(49)))
+
+	finalized := subchain.PopFinalized(50)
+	assert.Equal(1, len(finalized))
+	assert.True(subchain.HasCheckpoint())
+	assert.Equal(uint64(100), subchain.LastCheckpoint.Height)
+	assert.Equal(uint64(300), subchain.NextCheckpointHeight())
+
+	returned := subchain.PopReturned(50)
+	assert.Equal(1, len(returned))
+	assert.Equal(val2.Address, returned[0].Staker)
+	assert.Equal(1, len(subchain.Stakes))
+
+	assert.Equal(1, len(subchain.PopFinalized(60)))
+	assert.Equal(uint64(200), subchain.LastCheckpoint.Height)
+	assert.Equal(0, len(subchain.Pending))
+}
+
+func TestSubchainUpdateQueue(t *testing.T) {
+	assert := assert.New(t)
+
+	id1, id2 := SubchainID("chain1"), SubchainID("chain2")
+	assert.NotEqual(id1, id2)
+
+	queue := &SubchainUpdateQueue{}
+	queue.Add(id1, 30)
+	queue.Add(id2, 10)
+	queue.Add(id1, 20)
+	queue.Add(id2, 40)
+
+	assert.Equal(0, len(queue.PopDue(9)))
+	assert.Equal([]common.Hash{id2, id1}, queue.PopDue(30))
+	assert.Equal(1, queue.Len())
+	assert.Equal([]common.Hash{id2}, queue.PopDue(40))
+	assert.Equal(0, queue.Len())
+}
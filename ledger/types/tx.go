@@ -1,8 +1,10 @@
 package types
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
@@ -29,18 +31,72 @@ Transaction Types:
  - DepositStakeTx       Deposit stake to a target address (e.g. a validator)
  - WithdrawStakeTx      Withdraw stake from a target address (e.g. a validator)
  - SmartContractTx      Execute smart contract
+ - SessionKeyTx         Register or revoke a session key of an account
+ - BatchSendTx          Send coins to many addresses, each with an optional memo
+ - SlashAppealTx        Appeal a slash by locking a bond, reviewed by the validators
+ - SlashAppealVoteTx    Approve a pending slash appeal, submitted by a validator
+ - ClaimEscrowTx        Claim the coins of an escrow address by satisfying one of its clauses
+ - MeteredSettlementTx  Settle the metered bandwidth delivered by an edge node against the reserve fund of a client
+ - DelegateTx           Delegate stake to a validator
+ - UndelegateTx         Withdraw the stake delegated to a validator
+ - SetCommissionTx      Set the commission rate a validator keeps from the rewards of its delegators
+ - RametronAttestationTx Attest the heartbeats of the Rametron nodes, submitted by a guardian
+ - KeyRotationTx        Authorize a new key to control an account, after a grace period
+ - HTLCCreateTx         Lock coins under a hash lock and a time lock, e.g. for an atomic swap with another chain
+ - HTLCClaimTx          Claim the coins of an HTLC for its recipient by revealing the preimage of the hash lock
+ - HTLCRefundTx         Refund the coins of an expired HTLC to its sender
+ - ProposalTx           Propose a change of a governance parameter, voted on by the stakers
+ - VoteTx               Vote on a pending governance proposal, submitted by a staker
+ - BridgeLockTx         Lock coins in the bridge, to be minted as wrapped coins on a foreign chain
+ - BridgeBurnTx         Burn a wrapped foreign asset, to be released on its foreign chain
+ - BridgeClaimTx        Mint a wrapped asset or release locked coins, as attested by the bridge validators
+ - SubchainRegisterTx   Register a subchain, locking a deposit
+ - SubchainStakeTx      Deposit or withdraw the stake of a subchain validator
+ - SubchainCheckpointTx Anchor a subchain block signed by 2/3 of the stake of the subchain validators
+ - SubchainChallengeTx  Disprove a pending subchain checkpoint with a conflicting one, slashing the equivocators
 */
 
 // Gas of regular transactions
 const (
-	GasSendTxPerAccount   uint64 = 5000
-	GasReserveFundTx      uint64 = 10000
-	GasReleaseFundTx      uint64 = 10000
-	GasServicePaymentTx   uint64 = 10000
-	GasSplitRuleTx        uint64 = 10000
-	GasUpdateValidatorsTx uint64 = 10000
-	GasDepositStakeTx     uint64 = 10000
-	GasWidthdrawStakeTx   uint64 = 10000
+	GasSendTxPerAccount    uint64 = 5000
+	GasReserveFundTx       uint64 = 10000
+	GasReleaseFundTx       uint64 = 10000
+	GasServicePaymentTx    uint64 = 10000
+	GasSplitRuleTx         uint64 = 10000
+	GasUpdateValidatorsTx  uint64 = 10000
+	GasDepositStakeTx      uint64 = 10000
+	GasWidthdrawStakeTx    uint64 = 10000
+	GasSessionKeyTx        uint64 = 10000
+	GasSlashAppealTx       uint64 = 10000
+	GasSlashAppealVoteTx   uint64 = 10000
+	GasClaimEscrowTx       uint64 = 10000
+	GasMeteredSettlementTx uint64 = 10000
+	GasDelegateTx          uint64 = 10000
+	GasUndelegateTx        uint64 = 10000
+	GasSetCommissionTx     uint64 = 10000
+
+	GasRametronAttestationTx           uint64 = 10000
+	GasRametronAttestationPerHeartbeat uint64 = 1000
+
+	GasKeyRotationTx uint64 = 10000
+
+	GasHTLCCreateTx uint64 = 10000
+	GasHTLCClaimTx  uint64 = 10000
+	GasHTLCRefundTx uint64 = 10000
+
+	GasProposalTx uint64 = 10000
+	GasVoteTx     uint64 = 10000
+
+	GasBridgeLockTx              uint64 = 10000
+	GasBridgeBurnTx              uint64 = 10000
+	GasBridgeClaimTx             uint64 = 10000
+	GasBridgeClaimPerAttestation uint64 = 1000
+
+	GasSubchainRegisterTx   uint64 = 10000
+	GasSubchainStakeTx      uint64 = 10000
+	GasSubchainCheckpointTx uint64 = 10000
+	GasSubchainChallengeTx  uint64 = 10000
+	GasSubchainPerSignature uint64 = 1000
 )
 
 type Tx interface {
@@ -52,12 +108,10 @@ type Tx interface {
 
 func TxID(chainID string, tx Tx) common.Hash {
 	var signBytes []byte
-	switch tx.(type) {
-	default:
+	if spec, ok := getTxTypeSpec(tx); ok && spec.SignBytes != nil {
+		signBytes = spec.SignBytes(chainID, tx)
+	} else {
 		signBytes = tx.SignBytes(chainID)
-	case *ServicePaymentTx:
-		spTx := tx.(*ServicePaymentTx)
-		signBytes = spTx.TargetSignBytes(chainID)
 	}
 	return crypto.Keccak256Hash(signBytes)
 }
@@ -86,15 +140,17 @@ func encodeToBytes(str string) []byte {
 type TxInput struct {
 	Address   common.Address // Hash of the PubKey
 	Coins     Coins
-	Sequence  uint64            // Must be 1 greater than the last committed TxInput
-	Signature *crypto.Signature // Depends on the PubKey type and the whole Tx
+	Sequence  uint64             // Must be 1 greater than the last committed TxInput
+	Signature *crypto.Signature  // Depends on the PubKey type and the whole Tx
+	MultiSig  *MultiSigSignature `rlp:"optional"` // Signatures of a multi-signature account, in place of Signature
 }
 
 type TxInputJSON struct {
-	Address   common.Address    `json:"address"`   // Hash of the PubKey
-	Coins     Coins             `json:"coins"`     //
-	Sequence  common.JSONUint64 `json:"sequence"`  // Must be 1 greater than the last committed TxInput
-	Signature *crypto.Signature `json:"signature"` // Depends on the PubKey type and the whole Tx
+	Address   common.Address     `json:"address"`   // Hash of the PubKey
+	Coins     Coins              `json:"coins"`     //
+	Sequence  common.JSONUint64  `json:"sequence"`  // Must be 1 greater than the last committed TxInput
+	Signature *crypto.Signature  `json:"signature"` // Depends on the PubKey type and the whole Tx
+	MultiSig  *MultiSigSignature `json:"multisig,omitempty"`
 }
 
 func NewTxInputJSON(a TxInput) TxInputJSON {
@@ -103,6 +159,7 @@ func NewTxInputJSON(a TxInput) TxInputJSON {
 		Coins:     a.Coins,
 		Sequence:  common.JSONUint64(a.Sequence),
 		Signature: a.Signature,
+		MultiSig:  a.MultiSig,
 	}
 }
 
@@ -112,6 +169,7 @@ func (a TxInputJSON) TxInput() TxInput {
 		Coins:     a.Coins,
 		Sequence:  uint64(a.Sequence),
 		Signature: a.Signature,
+		MultiSig:  a.MultiSig,
 	}
 }
 
@@ -135,6 +193,14 @@ func (txIn TxInput) ValidateBasic() result.Result {
 	if !txIn.Coins.IsValid() {
 		return result.Error("Invalid coins: %v", txIn.Coins)
 	}
+	if txIn.MultiSig != nil {
+		if err := txIn.MultiSig.ValidateBasic(); err != nil {
+			return result.Error("Invalid multi-signature: %v", err)
+		}
+		if txIn.MultiSig.Address() != txIn.Address {
+			return result.Error("Multi-signature policy does not match the address %v", txIn.Address.Hex())
+		}
+	}
 	// if txIn.Coins.IsZero() {
 	// 	return result.Error("Coins cannot be zero")
 	// }
@@ -150,6 +216,9 @@ func (txIn TxInput) ValidateBasic() result.Result {
 }
 
 func (txIn TxInput) String() string {
+	if txIn.MultiSig != nil {
+		return fmt.Sprintf("TxInput{%v,%v,%v,%v}", txIn.Address.Hex(), txIn.Coins, txIn.Sequence, txIn.MultiSig)
+	}
 	return fmt.Sprintf("TxInput{%v,%v,%v,%v}", txIn.Address.Hex(), txIn.Coins, txIn.Sequence, txIn.Signature)
 }
 
@@ -263,13 +332,18 @@ type SlashTx struct {
 	SlashedAddress  common.Address
 	ReserveSequence uint64
 	SlashProof      common.Bytes
+
+	// DoubleSign is the evidence of the slashed validator double signing, in place of the
+	// overspending proof of a reserve fund
+	DoubleSign *core.DoubleSignEvidence `rlp:"optional"`
 }
 
 type SlashTxJSON struct {
-	Proposer        TxInput           `json:"proposer"`
-	SlashedAddress  common.Address    `json:"slashed_address"`
-	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
-	SlashProof      common.Bytes      `json:"slash_proof"`
+	Proposer        TxInput                  `json:"proposer"`
+	SlashedAddress  common.Address           `json:"slashed_address"`
+	ReserveSequence common.JSONUint64        `json:"reserve_sequence"`
+	SlashProof      common.Bytes             `json:"slash_proof"`
+	DoubleSign      *core.DoubleSignEvidence `json:"double_sign,omitempty"`
 }
 
 func NewSlashTxJSON(a SlashTx) SlashTxJSON {
@@ -278,6 +352,7 @@ func NewSlashTxJSON(a SlashTx) SlashTxJSON {
 		SlashedAddress:  a.SlashedAddress,
 		ReserveSequence: common.JSONUint64(a.ReserveSequence),
 		SlashProof:      a.SlashProof,
+		DoubleSign:      a.DoubleSign,
 	}
 }
 
@@ -287,6 +362,7 @@ func (a SlashTxJSON) SlashTx() SlashTx {
 		SlashedAddress:  a.SlashedAddress,
 		ReserveSequence: uint64(a.ReserveSequence),
 		SlashProof:      a.SlashProof,
+		DoubleSign:      a.DoubleSign,
 	}
 }
 
@@ -326,6 +402,10 @@ func (tx *SlashTx) SetSignature(addr common.Address, sig *crypto.Signature) bool
 }
 
 func (tx *SlashTx) String() string {
+	if tx.DoubleSign != nil {
+		return fmt.Sprintf("SlashTx{%v->%v, double_sign: %v}",
+			tx.SlashedAddress.Hex(), tx.Proposer.Address[:], tx.DoubleSign)
+	}
 	return fmt.Sprintf("SlashTx{%v->%v, reserve_sequence: %v, slash_proof: %v}",
 		tx.SlashedAddress.Hex(), tx.Proposer.Address[:],
 		tx.ReserveSequence, hex.EncodeToString(tx.SlashProof))
@@ -334,15 +414,21 @@ func (tx *SlashTx) String() string {
 //-----------------------------------------------------------------------------
 
 type SendTx struct {
-	Fee     Coins      `json:"fee"` // Fee
-	Inputs  []TxInput  `json:"inputs"`
-	Outputs []TxOutput `json:"outputs"`
+	Fee             Coins        `json:"fee"` // Fee
+	Inputs          []TxInput    `json:"inputs"`
+	Outputs         []TxOutput   `json:"outputs"`
+	Data            common.Bytes `json:"data" rlp:"optional"`              // Optional data, e.g. the deposit identifier required by an exchange
+	ValidUntilBlock uint64       `json:"valid_until_block" rlp:"optional"` // Optional, the tx is rejected after this block height
 }
 
 type RametronStakeTx struct {
 	Fee     Coins      `json:"fee"` // Fee
 	Inputs  []TxInput  `json:"inputs"`
 	Outputs []TxOutput `json:"outputs"`
+
+	// Tier registers the single output as a Rametron node of the tier. It is omitted from the
+	// encoding of the untiered stakes, which keeps their encoding and hash unchanged.
+	Tier RametronTier `json:"tier" rlp:"optional"`
 }
 
 func (_ *SendTx) AssertIsTx()          {}
@@ -351,9 +437,12 @@ func (_ *RametronStakeTx) AssertIsTx() {}
 func (tx *SendTx) SignBytes(chainID string) []byte {
 	signBytes := encodeToBytes(chainID)
 	sigz := make([]*crypto.Signature, len(tx.Inputs))
+	multiSigz := make([]*MultiSigSignature, len(tx.Inputs))
 	for i := range tx.Inputs {
 		sigz[i] = tx.Inputs[i].Signature
+		multiSigz[i] = tx.Inputs[i].MultiSig
 		tx.Inputs[i].Signature = nil
+		tx.Inputs[i].MultiSig = nil
 	}
 	txBytes, _ := TxToBytes(tx)
 	signBytes = append(signBytes, txBytes...)
@@ -361,6 +450,7 @@ func (tx *SendTx) SignBytes(chainID string) []byte {
 
 	for i := range tx.Inputs {
 		tx.Inputs[i].Signature = sigz[i]
+		tx.Inputs[i].MultiSig = multiSigz[i]
 	}
 	return signBytes
 }
@@ -368,9 +458,12 @@ func (tx *SendTx) SignBytes(chainID string) []byte {
 func (tx *RametronStakeTx) SignBytes(chainID string) []byte {
 	signBytes := encodeToBytes(chainID)
 	sigz := make([]*crypto.Signature, len(tx.Inputs))
+	multiSigz := make([]*MultiSigSignature, len(tx.Inputs))
 	for i := range tx.Inputs {
 		sigz[i] = tx.Inputs[i].Signature
+		multiSigz[i] = tx.Inputs[i].MultiSig
 		tx.Inputs[i].Signature = nil
+		tx.Inputs[i].MultiSig = nil
 	}
 	txBytes, _ := TxToBytes(tx)
 	signBytes = append(signBytes, txBytes...)
@@ -378,6 +471,7 @@ func (tx *RametronStakeTx) SignBytes(chainID string) []byte {
 
 	for i := range tx.Inputs {
 		tx.Inputs[i].Signature = sigz[i]
+		tx.Inputs[i].MultiSig = multiSigz[i]
 	}
 	return signBytes
 }
@@ -402,16 +496,137 @@ func (tx *RametronStakeTx) SetSignature(addr common.Address, sig *crypto.Signatu
 	return false
 }
 
+// MinimumFee returns the minimum fee of the transaction, which grows with the length of the data
+func (tx *SendTx) MinimumFee() *big.Int {
+	return tx.MinimumFeeAt(MinimumBaseFee())
+}
+
+// MinimumFeeAt returns the minimum fee of the transaction under the given base fee
+func (tx *SendTx) MinimumFeeAt(baseFee *big.Int) *big.Int {
+	fee := new(big.Int).SetUint64(MinimumSendTxFeePerDataBytePTXWei)
+	fee.Mul(fee, big.NewInt(int64(len(tx.Data))))
+	return fee.Add(fee, baseFee)
+}
+
 func (tx *SendTx) String() string {
-	return fmt.Sprintf("SendTx{fee: %v, %v->%v}", tx.Fee, tx.Inputs, tx.Outputs)
+	s := fmt.Sprintf("SendTx{fee: %v, %v->%v", tx.Fee, tx.Inputs, tx.Outputs)
+	if len(tx.Data) != 0 {
+		s += fmt.Sprintf(", data: %v", tx.Data)
+	}
+	if tx.ValidUntilBlock != 0 {
+		s += fmt.Sprintf(", valid_until_block: %v", tx.ValidUntilBlock)
+	}
+	return s + "}"
 }
 
 func (tx *RametronStakeTx) String() string {
+	if tx.Tier != RametronTierNone {
+		return fmt.Sprintf("RametronStakeTx{fee: %v, %v->%v, tier: %v}", tx.Fee, tx.Inputs, tx.Outputs, tx.Tier)
+	}
 	return fmt.Sprintf("RametronStakeTx{fee: %v, %v->%v}", tx.Fee, tx.Inputs, tx.Outputs)
 }
 
 //-----------------------------------------------------------------------------
 
+// BatchOutput is an output of a BatchSendTx, with an optional memo for the recipient
+type BatchOutput struct {
+	Address common.Address `json:"address"`
+	Coins   Coins          `json:"coins"`
+	Memo    string         `json:"memo"`
+}
+
+func (out BatchOutput) ValidateBasic() result.Result {
+	if res := out.TxOutput().ValidateBasic(); res.IsError() {
+		return res
+	}
+	if len(out.Memo) > MaximumBatchSendTxMemoLength {
+		return result.Error("Memo too long, at most %v bytes are allowed", MaximumBatchSendTxMemoLength)
+	}
+	return result.OK
+}
+
+// TxOutput returns the output without the memo
+func (out BatchOutput) TxOutput() TxOutput {
+	return TxOutput{Address: out.Address, Coins: out.Coins}
+}
+
+func (out BatchOutput) String() string {
+	return fmt.Sprintf("BatchOutput{%v,%v,%q}", out.Address.Hex(), out.Coins, out.Memo)
+}
+
+// BatchSendTx sends coins to many addresses in one transaction, e.g. for payrolls and airdrops.
+// Its minimum fee grows with the number of outputs.
+type BatchSendTx struct {
+	Fee             Coins         `json:"fee"` // Fee
+	Inputs          []TxInput     `json:"inputs"`
+	Outputs         []BatchOutput `json:"outputs"`
+	ValidUntilBlock uint64        `json:"valid_until_block" rlp:"optional"` // Optional, the tx is rejected after this block height
+}
+
+func (_ *BatchSendTx) AssertIsTx() {}
+
+func (tx *BatchSendTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sigz := make([]*crypto.Signature, len(tx.Inputs))
+	multiSigz := make([]*MultiSigSignature, len(tx.Inputs))
+	for i := range tx.Inputs {
+		sigz[i] = tx.Inputs[i].Signature
+		multiSigz[i] = tx.Inputs[i].MultiSig
+		tx.Inputs[i].Signature = nil
+		tx.Inputs[i].MultiSig = nil
+	}
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	for i := range tx.Inputs {
+		tx.Inputs[i].Signature = sigz[i]
+		tx.Inputs[i].MultiSig = multiSigz[i]
+	}
+	return signBytes
+}
+
+func (tx *BatchSendTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	for i, input := range tx.Inputs {
+		if input.Address == addr {
+			tx.Inputs[i].Signature = sig
+			return true
+		}
+	}
+	return false
+}
+
+// TxOutputs returns the outputs without the memos
+func (tx *BatchSendTx) TxOutputs() []TxOutput {
+	outputs := make([]TxOutput, len(tx.Outputs))
+	for i, out := range tx.Outputs {
+		outputs[i] = out.TxOutput()
+	}
+	return outputs
+}
+
+// MinimumFee returns the minimum fee of the transaction in PTXWei
+func (tx *BatchSendTx) MinimumFee() *big.Int {
+	return tx.MinimumFeeAt(MinimumBaseFee())
+}
+
+// MinimumFeeAt returns the minimum fee of the transaction in PTXWei under the given base fee
+func (tx *BatchSendTx) MinimumFeeAt(baseFee *big.Int) *big.Int {
+	fee := new(big.Int).SetUint64(MinimumBatchSendTxFeePerOutputPTXWei)
+	fee.Mul(fee, big.NewInt(int64(len(tx.Outputs))))
+	return fee.Add(fee, baseFee)
+}
+
+func (tx *BatchSendTx) String() string {
+	if tx.ValidUntilBlock != 0 {
+		return fmt.Sprintf("BatchSendTx{fee: %v, %v->%v, valid_until_block: %v}",
+			tx.Fee, tx.Inputs, tx.Outputs, tx.ValidUntilBlock)
+	}
+	return fmt.Sprintf("BatchSendTx{fee: %v, %v->%v}", tx.Fee, tx.Inputs, tx.Outputs)
+}
+
+//-----------------------------------------------------------------------------
+
 type ReserveFundTx struct {
 	Fee         Coins    // Fee
 	Source      TxInput  // Source account
@@ -957,32 +1172,1455 @@ func (tx *WithdrawStakeTx) String() string {
 		tx.Source.Address, tx.Holder.Address, tx.Source.Coins.PandoWei, tx.Purpose)
 }
 
-// --------------- Utils --------------- //
+//-----------------------------------------------------------------------------
 
-type EthereumTxWrapper struct {
-	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
-	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
-	GasLimit     uint64          `json:"gas"      gencodec:"required"`
-	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
-	Amount       *big.Int        `json:"value"    gencodec:"required"`
-	Payload      []byte          `json:"input"    gencodec:"required"`
+// SessionKeyTx registers a session key which may sign transactions on behalf of the account,
+// within the granted permissions. Registering an existing session key replaces its permissions
+// and resets its spending. An ExpiryHeight of 0 revokes the session key.
+type SessionKeyTx struct {
+	Fee           Coins            `json:"fee"`            // Fee
+	Account       TxInput          `json:"account"`        // account granting the session key
+	SessionKey    common.Address   `json:"session_key"`    // address of the session key
+	TxTypes       []TxType         `json:"tx_types"`       // tx types the session key may sign
+	Destinations  []common.Address `json:"destinations"`   // destinations the session key may send to, empty means any destination
+	SpendingLimit Coins            `json:"spending_limit"` // cumulative amount the session key may spend, fee included
+	ExpiryHeight  uint64           `json:"expiry_height"`  // the session key cannot sign after this height
 }
 
-// Need to add the following prefix to the tx signbytes to be compatible with
-// the Ethereum tx format
-func addPrefixForSignBytes(signBytes common.Bytes) common.Bytes {
-	ethTx := EthereumTxWrapper{
-		AccountNonce: uint64(0),
-		Price:        new(big.Int).SetUint64(0),
-		GasLimit:     uint64(0),
-		Recipient:    &common.Address{},
-		Amount:       new(big.Int).SetUint64(0),
-		Payload:      signBytes,
+func (_ *SessionKeyTx) AssertIsTx() {}
+
+func (tx *SessionKeyTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Account.Signature
+	tx.Account.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Account.Signature = sig
+	return signBytes
+}
+
+func (tx *SessionKeyTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Account.Address == addr {
+		tx.Account.Signature = sig
+		return true
 	}
-	signBytes, err := rlp.EncodeToBytes(ethTx)
-	if err != nil {
-		log.Panic(err)
+	return false
+}
+
+// IsRevocation indicates whether the transaction revokes the session key
+func (tx *SessionKeyTx) IsRevocation() bool {
+	return tx.ExpiryHeight == 0
+}
+
+func (tx *SessionKeyTx) String() string {
+	return fmt.Sprintf("SessionKeyTx{account: %v, session_key: %v, tx_types: %v, destinations: %v, spending_limit: %v, expiry_height: %v}",
+		tx.Account.Address, tx.SessionKey, tx.TxTypes, tx.Destinations, tx.SpendingLimit, tx.ExpiryHeight)
+}
+
+//-----------------------------------------------------------------------------
+
+// SlashAppealTx appeals the slash of the appellant's reserve fund with the given reserve
+// sequence. The bond is locked until the appeal is settled. It is returned together with
+// the slashed amount if the validators approve the appeal within SlashAppealWindow blocks,
+// otherwise it is forfeited to the insurance pool.
+type SlashAppealTx struct {
+	Fee             Coins   `json:"fee"`              // Fee
+	Appellant       TxInput `json:"appellant"`        // the slashed account
+	ReserveSequence uint64  `json:"reserve_sequence"` // reserve sequence of the slashed reserve fund
+	Bond            Coins   `json:"bond"`             // bond locked by the appeal
+}
+
+type SlashAppealTxJSON struct {
+	Fee             Coins             `json:"fee"`
+	Appellant       TxInput           `json:"appellant"`
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+	Bond            Coins             `json:"bond"`
+}
+
+func NewSlashAppealTxJSON(a SlashAppealTx) SlashAppealTxJSON {
+	return SlashAppealTxJSON{
+		Fee:             a.Fee,
+		Appellant:       a.Appellant,
+		ReserveSequence: common.JSONUint64(a.ReserveSequence),
+		Bond:            a.Bond,
+	}
+}
+
+func (a SlashAppealTxJSON) SlashAppealTx() SlashAppealTx {
+	return SlashAppealTx{
+		Fee:             a.Fee,
+		Appellant:       a.Appellant,
+		ReserveSequence: uint64(a.ReserveSequence),
+		Bond:            a.Bond,
+	}
+}
+
+func (a SlashAppealTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewSlashAppealTxJSON(a))
+}
+
+func (a *SlashAppealTx) UnmarshalJSON(data []byte) error {
+	var b SlashAppealTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.SlashAppealTx()
+	return nil
+}
+
+func (_ *SlashAppealTx) AssertIsTx() {}
+
+func (tx *SlashAppealTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Appellant.Signature
+	tx.Appellant.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Appellant.Signature = sig
+	return signBytes
+}
+
+func (tx *SlashAppealTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Appellant.Address == addr {
+		tx.Appellant.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *SlashAppealTx) String() string {
+	return fmt.Sprintf("SlashAppealTx{appellant: %v, reserve_sequence: %v, bond: %v}",
+		tx.Appellant.Address, tx.ReserveSequence, tx.Bond)
+}
+
+//-----------------------------------------------------------------------------
+
+// SlashAppealVoteTx approves the pending slash appeal of the appellant's reserve fund with
+// the given reserve sequence. Only the validators can vote, and the appeal is approved once
+// the validators holding more than 2/3 of the stake have voted for it.
+type SlashAppealVoteTx struct {
+	Fee             Coins          `json:"fee"`              // Fee
+	Voter           TxInput        `json:"voter"`            // the voting validator
+	Appellant       common.Address `json:"appellant"`        // the slashed account
+	ReserveSequence uint64         `json:"reserve_sequence"` // reserve sequence of the slashed reserve fund
+}
+
+type SlashAppealVoteTxJSON struct {
+	Fee             Coins             `json:"fee"`
+	Voter           TxInput           `json:"voter"`
+	Appellant       common.Address    `json:"appellant"`
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+}
+
+func NewSlashAppealVoteTxJSON(a SlashAppealVoteTx) SlashAppealVoteTxJSON {
+	return SlashAppealVoteTxJSON{
+		Fee:             a.Fee,
+		Voter:           a.Voter,
+		Appellant:       a.Appellant,
+		ReserveSequence: common.JSONUint64(a.ReserveSequence),
+	}
+}
+
+func (a SlashAppealVoteTxJSON) SlashAppealVoteTx() SlashAppealVoteTx {
+	return SlashAppealVoteTx{
+		Fee:             a.Fee,
+		Voter:           a.Voter,
+		Appellant:       a.Appellant,
+		ReserveSequence: uint64(a.ReserveSequence),
+	}
+}
+
+func (a SlashAppealVoteTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewSlashAppealVoteTxJSON(a))
+}
+
+func (a *SlashAppealVoteTx) UnmarshalJSON(data []byte) error {
+	var b SlashAppealVoteTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.SlashAppealVoteTx()
+	return nil
+}
+
+func (_ *SlashAppealVoteTx) AssertIsTx() {}
+
+func (tx *SlashAppealVoteTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Voter.Signature
+	tx.Voter.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Voter.Signature = sig
+	return signBytes
+}
+
+func (tx *SlashAppealVoteTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Voter.Address == addr {
+		tx.Voter.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *SlashAppealVoteTx) String() string {
+	return fmt.Sprintf("SlashAppealVoteTx{voter: %v, appellant: %v, reserve_sequence: %v}",
+		tx.Voter.Address, tx.Appellant, tx.ReserveSequence)
+}
+
+//-----------------------------------------------------------------------------
+
+// ClaimEscrowTx pays coins held by an escrow address to the recipient of one of its clauses.
+// The escrow address is derived from its clauses (see EscrowAddress), which the transaction
+// carries along with the preimage of the hash lock and the signatures required by the claimed
+// clause. The fee is charged to the escrow on top of the claimed coins.
+type ClaimEscrowTx struct {
+	Fee         Coins               `json:"fee"`          // Fee
+	Escrow      TxInput             `json:"escrow"`       // the escrow address, the claimed coins and the sequence, without signature
+	Clauses     []EscrowClause      `json:"clauses"`      // all the clauses of the escrow
+	ClauseIndex uint64              `json:"clause_index"` // index of the claimed clause
+	Preimage    common.Bytes        `json:"preimage"`     // preimage of the hash lock of the claimed clause
+	Signatures  []*crypto.Signature `json:"signatures"`   // signatures by the signers of the claimed clause
+}
+
+type ClaimEscrowTxJSON struct {
+	Fee         Coins               `json:"fee"`
+	Escrow      TxInput             `json:"escrow"`
+	Clauses     []EscrowClause      `json:"clauses"`
+	ClauseIndex common.JSONUint64   `json:"clause_index"`
+	Preimage    common.Bytes        `json:"preimage"`
+	Signatures  []*crypto.Signature `json:"signatures"`
+}
+
+func NewClaimEscrowTxJSON(a ClaimEscrowTx) ClaimEscrowTxJSON {
+	return ClaimEscrowTxJSON{
+		Fee:         a.Fee,
+		Escrow:      a.Escrow,
+		Clauses:     a.Clauses,
+		ClauseIndex: common.JSONUint64(a.ClauseIndex),
+		Preimage:    a.Preimage,
+		Signatures:  a.Signatures,
+	}
+}
+
+func (a ClaimEscrowTxJSON) ClaimEscrowTx() ClaimEscrowTx {
+	return ClaimEscrowTx{
+		Fee:         a.Fee,
+		Escrow:      a.Escrow,
+		Clauses:     a.Clauses,
+		ClauseIndex: uint64(a.ClauseIndex),
+		Preimage:    a.Preimage,
+		Signatures:  a.Signatures,
+	}
+}
+
+func (a ClaimEscrowTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewClaimEscrowTxJSON(a))
+}
+
+func (a *ClaimEscrowTx) UnmarshalJSON(data []byte) error {
+	var b ClaimEscrowTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.ClaimEscrowTx()
+	return nil
+}
+
+func (_ *ClaimEscrowTx) AssertIsTx() {}
+
+func (tx *ClaimEscrowTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Escrow.Signature
+	sigz := tx.Signatures
+	tx.Escrow.Signature = nil
+	tx.Signatures = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Escrow.Signature = sig
+	tx.Signatures = sigz
+	return signBytes
+}
+
+// SetSignature adds the signature of a signer of the claimed clause
+func (tx *ClaimEscrowTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	clause, ok := tx.Clause()
+	if !ok || !clause.IsSigner(addr) {
+		return false
+	}
+	tx.Signatures = append(tx.Signatures, sig)
+	return true
+}
+
+// Clause returns the claimed clause, false if the clause index is out of range
+func (tx *ClaimEscrowTx) Clause() (*EscrowClause, bool) {
+	if tx.ClauseIndex >= uint64(len(tx.Clauses)) {
+		return nil, false
+	}
+	return &tx.Clauses[tx.ClauseIndex], true
+}
+
+func (tx *ClaimEscrowTx) String() string {
+	return fmt.Sprintf("ClaimEscrowTx{escrow: %v, coins: %v, clause_index: %v, clauses: %v}",
+		tx.Escrow.Address, tx.Escrow.Coins, tx.ClauseIndex, tx.Clauses)
+}
+
+//-----------------------------------------------------------------------------
+
+// MeteredSettlementTx settles a batch of metering records of a Rametron edge node, paid from the
+// reserve fund of the client. Each record is signed by both the client and the edge node, which
+// signs and submits the transaction and pays its fee. The sequence of the last record is used as
+// the payment sequence of the settlement, hence the records settled before cannot be replayed.
+type MeteredSettlementTx struct {
+	Fee             Coins            // Fee
+	EdgeNode        TxInput          // the edge node submitting the settlement, without coins
+	Client          common.Address   // the payer owning the reserve fund
+	ReserveSequence uint64           // ReserveSequence to locate the ReservedFund
+	ResourceID      string           // The corresponding resourceID
+	Records         []MeteringRecord // in ascending order of their sequences
+}
+
+type MeteredSettlementTxJSON struct {
+	Fee             Coins             `json:"fee"`
+	EdgeNode        TxInput           `json:"edge_node"`
+	Client          common.Address    `json:"client"`
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+	ResourceID      string            `json:"resource_id"`
+	Records         []MeteringRecord  `json:"records"`
+}
+
+func NewMeteredSettlementTxJSON(a MeteredSettlementTx) MeteredSettlementTxJSON {
+	return MeteredSettlementTxJSON{
+		Fee:             a.Fee,
+		EdgeNode:        a.EdgeNode,
+		Client:          a.Client,
+		ReserveSequence: common.JSONUint64(a.ReserveSequence),
+		ResourceID:      a.ResourceID,
+		Records:         a.Records,
+	}
+}
+
+func (a MeteredSettlementTxJSON) MeteredSettlementTx() MeteredSettlementTx {
+	return MeteredSettlementTx{
+		Fee:             a.Fee,
+		EdgeNode:        a.EdgeNode,
+		Client:          a.Client,
+		ReserveSequence: uint64(a.ReserveSequence),
+		ResourceID:      a.ResourceID,
+		Records:         a.Records,
 	}
+}
+
+func (a MeteredSettlementTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewMeteredSettlementTxJSON(a))
+}
+
+func (a *MeteredSettlementTx) UnmarshalJSON(data []byte) error {
+	var b MeteredSettlementTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.MeteredSettlementTx()
+	return nil
+}
+
+func (_ *MeteredSettlementTx) AssertIsTx() {}
+
+func (tx *MeteredSettlementTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.EdgeNode.Signature
+	tx.EdgeNode.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.EdgeNode.Signature = sig
 	return signBytes
 }
 
+func (tx *MeteredSettlementTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.EdgeNode.Address == addr {
+		tx.EdgeNode.Signature = sig
+		return true
+	}
+	return false
+}
+
+// Amount returns the total payment for the bandwidth of the records
+func (tx *MeteredSettlementTx) Amount() Coins {
+	amount := NewCoins(0, 0)
+	for idx := range tx.Records {
+		amount = amount.Plus(tx.Records[idx].Amount())
+	}
+	return amount
+}
+
+// PaymentSequence returns the sequence of the last record, 0 if there is no record
+func (tx *MeteredSettlementTx) PaymentSequence() uint64 {
+	if len(tx.Records) == 0 {
+		return 0
+	}
+	return tx.Records[len(tx.Records)-1].Sequence
+}
+
+// ServicePayment returns the service payment the settlement is recorded as in the reserve fund of the
+// client, hence the service payments and the settlements of an edge node share the payment sequences
+func (tx *MeteredSettlementTx) ServicePayment() *ServicePaymentTx {
+	return &ServicePaymentTx{
+		Fee:             NewCoins(0, 0),
+		Source:          TxInput{Address: tx.Client, Coins: tx.Amount()},
+		Target:          TxInput{Address: tx.EdgeNode.Address},
+		PaymentSequence: tx.PaymentSequence(),
+		ReserveSequence: tx.ReserveSequence,
+		ResourceID:      tx.ResourceID,
+	}
+}
+
+// ValidateRecords checks that the records belong to the settlement, are in ascending order of
+// their sequences, and are signed by both the client and the edge node
+func (tx *MeteredSettlementTx) ValidateRecords(chainID string) error {
+	if len(tx.Records) == 0 || len(tx.Records) > MaximumMeteringRecords {
+		return fmt.Errorf("Invalid number of metering records %v, needs to be between 1 and %v",
+			len(tx.Records), MaximumMeteringRecords)
+	}
+	for idx := range tx.Records {
+		record := &tx.Records[idx]
+		if record.Client != tx.Client || record.EdgeNode != tx.EdgeNode.Address ||
+			record.ReserveSequence != tx.ReserveSequence || record.ResourceID != tx.ResourceID {
+			return fmt.Errorf("Metering record %v does not belong to the settlement", idx)
+		}
+		if idx > 0 && record.Sequence <= tx.Records[idx-1].Sequence {
+			return fmt.Errorf("Metering record %v is out of order, sequence %v after %v",
+				idx, record.Sequence, tx.Records[idx-1].Sequence)
+		}
+		if !record.PricePerGB.IsNonnegative() {
+			return fmt.Errorf("Metering record %v has a negative price", idx)
+		}
+		if err := record.Verify(chainID); err != nil {
+			return fmt.Errorf("Metering record %v: %v", idx, err)
+		}
+	}
+	return nil
+}
+
+func (tx *MeteredSettlementTx) String() string {
+	return fmt.Sprintf("MeteredSettlementTx{edge_node: %v, client: %v, reserve_sequence: %v, resource_id: %v, records: %v}",
+		tx.EdgeNode.Address, tx.Client, tx.ReserveSequence, tx.ResourceID, len(tx.Records))
+}
+
+//-----------------------------------------------------------------------------
+
+// DelegateTx delegates the PandoWei of the delegator to an existing validator. The delegated stake
+// adds to the voting power of the validator, and earns the delegator its share of the block
+// rewards until it is undelegated.
+type DelegateTx struct {
+	Fee       Coins          `json:"fee"`       // Fee
+	Delegator TxInput        `json:"delegator"` // the delegator account, with the delegated coins
+	Validator common.Address `json:"validator"` // the validator the stake is delegated to
+}
+
+func (_ *DelegateTx) AssertIsTx() {}
+
+func (tx *DelegateTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Delegator.Signature
+	tx.Delegator.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Delegator.Signature = sig
+	return signBytes
+}
+
+func (tx *DelegateTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Delegator.Address == addr {
+		tx.Delegator.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *DelegateTx) String() string {
+	return fmt.Sprintf("DelegateTx{%v -> %v, stake: %v}",
+		tx.Delegator.Address, tx.Validator, tx.Delegator.Coins.PandoWei)
+}
+
+//-----------------------------------------------------------------------------
+
+// UndelegateTx withdraws the whole stake the delegator delegated to the validator. The stake stops
+// earning rewards, and is returned to the delegator after the unbonding period.
+type UndelegateTx struct {
+	Fee       Coins          `json:"fee"`       // Fee
+	Delegator TxInput        `json:"delegator"` // the delegator account, without coins
+	Validator common.Address `json:"validator"` // the validator the stake was delegated to
+}
+
+func (_ *UndelegateTx) AssertIsTx() {}
+
+func (tx *UndelegateTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Delegator.Signature
+	tx.Delegator.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Delegator.Signature = sig
+	return signBytes
+}
+
+func (tx *UndelegateTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Delegator.Address == addr {
+		tx.Delegator.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *UndelegateTx) String() string {
+	return fmt.Sprintf("UndelegateTx{%v <- %v}", tx.Delegator.Address, tx.Validator)
+}
+
+//-----------------------------------------------------------------------------
+
+// SetCommissionTx sets the commission rate of a validator, i.e. the share of the block rewards of
+// its delegated stake the validator keeps before the rewards are distributed to the delegators.
+type SetCommissionTx struct {
+	Fee            Coins   `json:"fee"`             // Fee
+	Validator      TxInput `json:"validator"`       // the validator account, without coins
+	CommissionRate uint64  `json:"commission_rate"` // the new commission rate, in basis points
+}
+
+func (_ *SetCommissionTx) AssertIsTx() {}
+
+func (tx *SetCommissionTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Validator.Signature
+	tx.Validator.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Validator.Signature = sig
+	return signBytes
+}
+
+func (tx *SetCommissionTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Validator.Address == addr {
+		tx.Validator.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *SetCommissionTx) String() string {
+	return fmt.Sprintf("SetCommissionTx{validator: %v, commission_rate: %v}",
+		tx.Validator.Address, tx.CommissionRate)
+}
+
+//-----------------------------------------------------------------------------
+
+// MaxRametronHeartbeatsPerTx is the max number of heartbeats attested by one RametronAttestationTx
+const MaxRametronHeartbeatsPerTx = 1000
+
+// RametronAttestationTx attests the heartbeats the guardian received from the Rametron nodes in the
+// current reward epoch. Only the nodes attested by enough guardians in an epoch are active in the
+// epoch, and eligible for the Rametron rewards.
+type RametronAttestationTx struct {
+	Fee        Coins                `json:"fee"`      // Fee
+	Guardian   TxInput              `json:"guardian"` // the guardian account, without coins
+	Epoch      uint64               `json:"epoch"`
+	Heartbeats []*RametronHeartbeat `json:"heartbeats"`
+}
+
+func (_ *RametronAttestationTx) AssertIsTx() {}
+
+func (tx *RametronAttestationTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Guardian.Signature
+	tx.Guardian.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Guardian.Signature = sig
+	return signBytes
+}
+
+func (tx *RametronAttestationTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Guardian.Address == addr {
+		tx.Guardian.Signature = sig
+		return true
+	}
+	return false
+}
+
+// Gas returns the gas of the transaction, which grows with the number of heartbeats
+func (tx *RametronAttestationTx) Gas() uint64 {
+	return GasRametronAttestationTx + GasRametronAttestationPerHeartbeat*uint64(len(tx.Heartbeats))
+}
+
+func (tx *RametronAttestationTx) String() string {
+	return fmt.Sprintf("RametronAttestationTx{guardian: %v, epoch: %v, heartbeats: %v}",
+		tx.Guardian.Address, tx.Epoch, len(tx.Heartbeats))
+}
+
+//-----------------------------------------------------------------------------
+
+// KeyRotationTx authorizes a new key to control the account, signed by a key currently accepted for
+// the account. The previous key is still accepted during the grace period of KeyRotationGracePeriod
+// blocks, and rejected afterwards. Rotating back to the previous key during the grace period cancels
+// the rotation.
+type KeyRotationTx struct {
+	Fee     Coins          `json:"fee"`     // Fee
+	Account TxInput        `json:"account"` // the account whose key is rotated, without coins
+	NewKey  common.Address `json:"new_key"` // address of the new controlling key
+}
+
+func (_ *KeyRotationTx) AssertIsTx() {}
+
+func (tx *KeyRotationTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Account.Signature
+	tx.Account.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Account.Signature = sig
+	return signBytes
+}
+
+func (tx *KeyRotationTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Account.Address == addr {
+		tx.Account.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *KeyRotationTx) String() string {
+	return fmt.Sprintf("KeyRotationTx{account: %v, new_key: %v}", tx.Account.Address, tx.NewKey)
+}
+
+//-----------------------------------------------------------------------------
+
+// HTLCCreateTx locks the coins of the sender in a new HTLC (see HTLC). The ID of the HTLC is derived
+// from the sender and the sequence of the transaction (see HTLCID).
+type HTLCCreateTx struct {
+	Fee       Coins          `json:"fee"`       // Fee
+	Sender    TxInput        `json:"sender"`    // the sender, and the coins to lock
+	Recipient common.Address `json:"recipient"` // receives the coins on claim
+	HashLock  common.Hash    `json:"hash_lock"` // SHA-256 hash of the preimage to reveal
+	TimeLock  uint64         `json:"time_lock"` // last block height to claim the coins
+}
+
+type HTLCCreateTxJSON struct {
+	Fee       Coins             `json:"fee"`
+	Sender    TxInput           `json:"sender"`
+	Recipient common.Address    `json:"recipient"`
+	HashLock  common.Hash       `json:"hash_lock"`
+	TimeLock  common.JSONUint64 `json:"time_lock"`
+}
+
+func NewHTLCCreateTxJSON(a HTLCCreateTx) HTLCCreateTxJSON {
+	return HTLCCreateTxJSON{
+		Fee:       a.Fee,
+		Sender:    a.Sender,
+		Recipient: a.Recipient,
+		HashLock:  a.HashLock,
+		TimeLock:  common.JSONUint64(a.TimeLock),
+	}
+}
+
+func (a HTLCCreateTxJSON) HTLCCreateTx() HTLCCreateTx {
+	return HTLCCreateTx{
+		Fee:       a.Fee,
+		Sender:    a.Sender,
+		Recipient: a.Recipient,
+		HashLock:  a.HashLock,
+		TimeLock:  uint64(a.TimeLock),
+	}
+}
+
+func (a HTLCCreateTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewHTLCCreateTxJSON(a))
+}
+
+func (a *HTLCCreateTx) UnmarshalJSON(data []byte) error {
+	var b HTLCCreateTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.HTLCCreateTx()
+	return nil
+}
+
+func (_ *HTLCCreateTx) AssertIsTx() {}
+
+func (tx *HTLCCreateTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Sender.Signature
+	tx.Sender.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Sender.Signature = sig
+	return signBytes
+}
+
+func (tx *HTLCCreateTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Sender.Address == addr {
+		tx.Sender.Signature = sig
+		return true
+	}
+	return false
+}
+
+// HTLCID returns the ID of the HTLC created by the transaction
+func (tx *HTLCCreateTx) HTLCID() common.Hash {
+	return HTLCID(tx.Sender.Address, tx.Sender.Sequence)
+}
+
+func (tx *HTLCCreateTx) String() string {
+	return fmt.Sprintf("HTLCCreateTx{sender: %v, recipient: %v, coins: %v, hash_lock: %v, time_lock: %v}",
+		tx.Sender.Address, tx.Recipient, tx.Sender.Coins, tx.HashLock.Hex(), tx.TimeLock)
+}
+
+//-----------------------------------------------------------------------------
+
+// HTLCClaimTx claims the coins of an HTLC by revealing the preimage of its hash lock, before the
+// time lock expires. The coins are paid to the recipient of the HTLC, hence the transaction can be
+// signed and submitted by any account, which pays the fee.
+type HTLCClaimTx struct {
+	Fee      Coins        `json:"fee"`      // Fee
+	Claimer  TxInput      `json:"claimer"`  // the account submitting the claim, without coins
+	HTLCID   common.Hash  `json:"htlc_id"`  // ID of the claimed HTLC
+	Preimage common.Bytes `json:"preimage"` // preimage of the hash lock
+}
+
+func (_ *HTLCClaimTx) AssertIsTx() {}
+
+func (tx *HTLCClaimTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Claimer.Signature
+	tx.Claimer.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Claimer.Signature = sig
+	return signBytes
+}
+
+func (tx *HTLCClaimTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Claimer.Address == addr {
+		tx.Claimer.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *HTLCClaimTx) String() string {
+	return fmt.Sprintf("HTLCClaimTx{claimer: %v, htlc_id: %v, preimage: %v}",
+		tx.Claimer.Address, tx.HTLCID.Hex(), tx.Preimage)
+}
+
+//-----------------------------------------------------------------------------
+
+// HTLCRefundTx refunds the coins of an HTLC to its sender, once the time lock has expired. Like the
+// claims, the refund can be signed and submitted by any account, which pays the fee.
+type HTLCRefundTx struct {
+	Fee      Coins       `json:"fee"`      // Fee
+	Refunder TxInput     `json:"refunder"` // the account submitting the refund, without coins
+	HTLCID   common.Hash `json:"htlc_id"`  // ID of the refunded HTLC
+}
+
+func (_ *HTLCRefundTx) AssertIsTx() {}
+
+func (tx *HTLCRefundTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Refunder.Signature
+	tx.Refunder.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Refunder.Signature = sig
+	return signBytes
+}
+
+func (tx *HTLCRefundTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Refunder.Address == addr {
+		tx.Refunder.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *HTLCRefundTx) String() string {
+	return fmt.Sprintf("HTLCRefundTx{refunder: %v, htlc_id: %v}", tx.Refunder.Address, tx.HTLCID.Hex())
+}
+
+//-----------------------------------------------------------------------------
+
+// ProposalTx proposes to change a governance parameter (see GovernableParams), and locks the deposit
+// of the proposer until the proposal is tallied. The ID of the proposal is derived from the proposer
+// and the sequence of the transaction (see GovernanceProposalID).
+type ProposalTx struct {
+	Fee      Coins    `json:"fee"`      // Fee
+	Proposer TxInput  `json:"proposer"` // the proposer, and the deposit to lock
+	Name     string   `json:"name"`     // name of the governance parameter
+	Value    *big.Int `json:"value"`    // proposed value
+}
+
+type ProposalTxJSON struct {
+	Fee      Coins           `json:"fee"`
+	Proposer TxInput         `json:"proposer"`
+	Name     string          `json:"name"`
+	Value    *common.JSONBig `json:"value"`
+}
+
+func NewProposalTxJSON(a ProposalTx) ProposalTxJSON {
+	return ProposalTxJSON{
+		Fee:      a.Fee,
+		Proposer: a.Proposer,
+		Name:     a.Name,
+		Value:    (*common.JSONBig)(a.Value),
+	}
+}
+
+func (a ProposalTxJSON) ProposalTx() ProposalTx {
+	return ProposalTx{
+		Fee:      a.Fee,
+		Proposer: a.Proposer,
+		Name:     a.Name,
+		Value:    (*big.Int)(a.Value),
+	}
+}
+
+func (a ProposalTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewProposalTxJSON(a))
+}
+
+func (a *ProposalTx) UnmarshalJSON(data []byte) error {
+	var b ProposalTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.ProposalTx()
+	return nil
+}
+
+func (_ *ProposalTx) AssertIsTx() {}
+
+func (tx *ProposalTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Proposer.Signature
+	tx.Proposer.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Proposer.Signature = sig
+	return signBytes
+}
+
+func (tx *ProposalTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Proposer.Address == addr {
+		tx.Proposer.Signature = sig
+		return true
+	}
+	return false
+}
+
+// ProposalID returns the ID of the proposal submitted by the transaction
+func (tx *ProposalTx) ProposalID() common.Hash {
+	return GovernanceProposalID(tx.Proposer.Address, tx.Proposer.Sequence)
+}
+
+func (tx *ProposalTx) String() string {
+	return fmt.Sprintf("ProposalTx{proposer: %v, name: %v, value: %v, deposit: %v}",
+		tx.Proposer.Address, tx.Name, tx.Value, tx.Proposer.Coins)
+}
+
+//-----------------------------------------------------------------------------
+
+// VoteTx votes on a pending governance proposal. Only the accounts with stakes deposited or
+// delegated to the validator candidates can vote, and their votes count with their stakes at the
+// tally. A voter can change its vote until the proposal is tallied.
+type VoteTx struct {
+	Fee        Coins       `json:"fee"`         // Fee
+	Voter      TxInput     `json:"voter"`       // the voting staker
+	ProposalID common.Hash `json:"proposal_id"` // ID of the proposal
+	Approve    bool        `json:"approve"`     // whether the voter approves the proposal
+}
+
+func (_ *VoteTx) AssertIsTx() {}
+
+func (tx *VoteTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Voter.Signature
+	tx.Voter.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Voter.Signature = sig
+	return signBytes
+}
+
+func (tx *VoteTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Voter.Address == addr {
+		tx.Voter.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *VoteTx) String() string {
+	return fmt.Sprintf("VoteTx{voter: %v, proposal_id: %v, approve: %v}",
+		tx.Voter.Address, tx.ProposalID.Hex(), tx.Approve)
+}
+
+//-----------------------------------------------------------------------------
+
+// BridgeLockTx locks the coins of the sender in the bridge, to be minted as wrapped coins for the
+// recipient on the foreign chain. It records an outbound BridgeMessage for the relayers.
+type BridgeLockTx struct {
+	Fee       Coins          `json:"fee"`        // Fee
+	Sender    TxInput        `json:"sender"`     // the sender, and the coins to lock
+	DestChain string         `json:"dest_chain"` // the foreign chain
+	Recipient common.Address `json:"recipient"`  // the recipient on the foreign chain
+}
+
+func (_ *BridgeLockTx) AssertIsTx() {}
+
+func (tx *BridgeLockTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Sender.Signature
+	tx.Sender.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Sender.Signature = sig
+	return signBytes
+}
+
+func (tx *BridgeLockTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Sender.Address == addr {
+		tx.Sender.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *BridgeLockTx) String() string {
+	return fmt.Sprintf("BridgeLockTx{sender: %v, coins: %v, dest_chain: %v, recipient: %v}",
+		tx.Sender.Address, tx.Sender.Coins, tx.DestChain, tx.Recipient)
+}
+
+//-----------------------------------------------------------------------------
+
+// BridgeBurnTx burns a wrapped asset held by the sender, to be released to the recipient on the
+// foreign chain the asset comes from. It records an outbound BridgeMessage for the relayers.
+type BridgeBurnTx struct {
+	Fee         Coins          `json:"fee"`          // Fee
+	Sender      TxInput        `json:"sender"`       // the holder of the wrapped asset, without coins
+	SourceChain string         `json:"source_chain"` // the foreign chain of the wrapped asset
+	Token       common.Address `json:"token"`        // the token of the foreign chain, zero address for its native coin
+	Amount      *big.Int       `json:"amount"`       // the amount to burn
+	Recipient   common.Address `json:"recipient"`    // the recipient on the foreign chain
+}
+
+type BridgeBurnTxJSON struct {
+	Fee         Coins           `json:"fee"`
+	Sender      TxInput         `json:"sender"`
+	SourceChain string          `json:"source_chain"`
+	Token       common.Address  `json:"token"`
+	Amount      *common.JSONBig `json:"amount"`
+	Recipient   common.Address  `json:"recipient"`
+}
+
+func NewBridgeBurnTxJSON(a BridgeBurnTx) BridgeBurnTxJSON {
+	return BridgeBurnTxJSON{
+		Fee:         a.Fee,
+		Sender:      a.Sender,
+		SourceChain: a.SourceChain,
+		Token:       a.Token,
+		Amount:      (*common.JSONBig)(a.Amount),
+		Recipient:   a.Recipient,
+	}
+}
+
+func (a BridgeBurnTxJSON) BridgeBurnTx() BridgeBurnTx {
+	return BridgeBurnTx{
+		Fee:         a.Fee,
+		Sender:      a.Sender,
+		SourceChain: a.SourceChain,
+		Token:       a.Token,
+		Amount:      (*big.Int)(a.Amount),
+		Recipient:   a.Recipient,
+	}
+}
+
+func (a BridgeBurnTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewBridgeBurnTxJSON(a))
+}
+
+func (a *BridgeBurnTx) UnmarshalJSON(data []byte) error {
+	var b BridgeBurnTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.BridgeBurnTx()
+	return nil
+}
+
+func (_ *BridgeBurnTx) AssertIsTx() {}
+
+func (tx *BridgeBurnTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Sender.Signature
+	tx.Sender.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Sender.Signature = sig
+	return signBytes
+}
+
+func (tx *BridgeBurnTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Sender.Address == addr {
+		tx.Sender.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *BridgeBurnTx) String() string {
+	return fmt.Sprintf("BridgeBurnTx{sender: %v, source_chain: %v, token: %v, amount: %v, recipient: %v}",
+		tx.Sender.Address, tx.SourceChain, tx.Token, tx.Amount, tx.Recipient)
+}
+
+//-----------------------------------------------------------------------------
+
+// BridgeClaimTx submits a foreign event attested by the bridge validators (see VerifyForeignProof),
+// which mints a wrapped asset or releases locked coins for the recipient of the event. The assets
+// always go to the recipient, hence the transaction can be signed and submitted by any account, e.g.
+// a relayer, which pays the fee.
+type BridgeClaimTx struct {
+	Fee     Coins              `json:"fee"`     // Fee
+	Relayer TxInput            `json:"relayer"` // the account submitting the claim, without coins
+	Proof   BridgeForeignProof `json:"proof"`   // the attested foreign event
+}
+
+func (_ *BridgeClaimTx) AssertIsTx() {}
+
+func (tx *BridgeClaimTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Relayer.Signature
+	tx.Relayer.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Relayer.Signature = sig
+	return signBytes
+}
+
+func (tx *BridgeClaimTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Relayer.Address == addr {
+		tx.Relayer.Signature = sig
+		return true
+	}
+	return false
+}
+
+// Gas returns the gas of the transaction, which grows with the number of attestations to verify
+func (tx *BridgeClaimTx) Gas() uint64 {
+	return GasBridgeClaimTx + GasBridgeClaimPerAttestation*uint64(len(tx.Proof.Attestations))
+}
+
+func (tx *BridgeClaimTx) String() string {
+	return fmt.Sprintf("BridgeClaimTx{relayer: %v, event: %v, attestations: %v}",
+		tx.Relayer.Address, tx.Proof.Event.String(), len(tx.Proof.Attestations))
+}
+
+//-----------------------------------------------------------------------------
+
+// SubchainRegisterTx registers a subchain, identified by its chain ID. The coins of the owner are
+// locked as the registration deposit.
+type SubchainRegisterTx struct {
+	Fee                Coins   `json:"fee"`                 // Fee
+	Owner              TxInput `json:"owner"`               // the owner, and the registration deposit
+	ChainID            string  `json:"chain_id"`            // the chain ID of the subchain
+	CheckpointInterval uint64  `json:"checkpoint_interval"` // the number of subchain blocks between two checkpoints
+}
+
+func (_ *SubchainRegisterTx) AssertIsTx() {}
+
+func (tx *SubchainRegisterTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Owner.Signature
+	tx.Owner.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Owner.Signature = sig
+	return signBytes
+}
+
+func (tx *SubchainRegisterTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Owner.Address == addr {
+		tx.Owner.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *SubchainRegisterTx) String() string {
+	return fmt.Sprintf("SubchainRegisterTx{owner: %v, deposit: %v, chain_id: %v, checkpoint_interval: %v}",
+		tx.Owner.Address, tx.Owner.Coins, tx.ChainID, tx.CheckpointInterval)
+}
+
+//-----------------------------------------------------------------------------
+
+// SubchainStakeTx deposits the Pando of the staker as its stake in a subchain, or withdraws the
+// stake of the staker, which is returned after the fraud proof window.
+type SubchainStakeTx struct {
+	Fee        Coins       `json:"fee"`         // Fee
+	Staker     TxInput     `json:"staker"`      // the staker, and the Pando to stake, no coins when withdrawing
+	SubchainID common.Hash `json:"subchain_id"` // the subchain
+	Withdraw   bool        `json:"withdraw"`    // withdraws the whole stake of the staker
+}
+
+func (_ *SubchainStakeTx) AssertIsTx() {}
+
+func (tx *SubchainStakeTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Staker.Signature
+	tx.Staker.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Staker.Signature = sig
+	return signBytes
+}
+
+func (tx *SubchainStakeTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Staker.Address == addr {
+		tx.Staker.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *SubchainStakeTx) String() string {
+	return fmt.Sprintf("SubchainStakeTx{staker: %v, coins: %v, subchain_id: %v, withdraw: %v}",
+		tx.Staker.Address, tx.Staker.Coins, tx.SubchainID.Hex(), tx.Withdraw)
+}
+
+//-----------------------------------------------------------------------------
+
+// SubchainCheckpointTx anchors a block of a subchain on the main chain, along with the signatures
+// of the subchain validators holding more than 2/3 of the active stake. The checkpoint is final
+// after the fraud proof window. It can be submitted by any account, e.g. a relayer, which pays the fee.
+type SubchainCheckpointTx struct {
+	Fee        Coins               `json:"fee"`       // Fee
+	Submitter  TxInput             `json:"submitter"` // the account submitting the checkpoint, without coins
+	SubchainID common.Hash         `json:"subchain_id"`
+	Height     uint64              `json:"height"`     // height of the subchain block
+	BlockHash  common.Hash         `json:"block_hash"` // hash of the subchain block
+	StateRoot  common.Hash         `json:"state_root"` // state root of the subchain block
+	Signatures []*crypto.Signature `json:"signatures"` // the signatures of the subchain validators
+}
+
+func (_ *SubchainCheckpointTx) AssertIsTx() {}
+
+func (tx *SubchainCheckpointTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Submitter.Signature
+	tx.Submitter.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Submitter.Signature = sig
+	return signBytes
+}
+
+func (tx *SubchainCheckpointTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Submitter.Address == addr {
+		tx.Submitter.Signature = sig
+		return true
+	}
+	return false
+}
+
+// Checkpoint returns the checkpoint anchored by the transaction
+func (tx *SubchainCheckpointTx) Checkpoint() *SubchainCheckpoint {
+	return &SubchainCheckpoint{
+		SubchainID: tx.SubchainID,
+		Height:     tx.Height,
+		BlockHash:  tx.BlockHash,
+		StateRoot:  tx.StateRoot,
+		Submitter:  tx.Submitter.Address,
+	}
+}
+
+// Gas returns the gas of the transaction, which grows with the number of signatures to verify
+func (tx *SubchainCheckpointTx) Gas() uint64 {
+	return GasSubchainCheckpointTx + GasSubchainPerSignature*uint64(len(tx.Signatures))
+}
+
+func (tx *SubchainCheckpointTx) String() string {
+	return fmt.Sprintf("SubchainCheckpointTx{submitter: %v, subchain_id: %v, height: %v, block_hash: %v, state_root: %v, signatures: %v}",
+		tx.Submitter.Address, tx.SubchainID.Hex(), tx.Height, tx.BlockHash.Hex(), tx.StateRoot.Hex(), len(tx.Signatures))
+}
+
+//-----------------------------------------------------------------------------
+
+// SubchainChallengeTx proves that a pending subchain checkpoint is fraudulent, with a conflicting
+// checkpoint at the same height, signed by the subchain validators who also signed the pending one.
+// The pending checkpoints from that height are reverted, and the equivocators are slashed, part of
+// their stake rewarding the challenger.
+type SubchainChallengeTx struct {
+	Fee        Coins               `json:"fee"`        // Fee
+	Challenger TxInput             `json:"challenger"` // the challenger, without coins
+	SubchainID common.Hash         `json:"subchain_id"`
+	Height     uint64              `json:"height"`     // height of the subchain block of the challenged checkpoint
+	BlockHash  common.Hash         `json:"block_hash"` // hash of the conflicting subchain block
+	StateRoot  common.Hash         `json:"state_root"` // state root of the conflicting subchain block
+	Signatures []*crypto.Signature `json:"signatures"` // the signatures of the conflicting checkpoint
+}
+
+func (_ *SubchainChallengeTx) AssertIsTx() {}
+
+func (tx *SubchainChallengeTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Challenger.Signature
+	tx.Challenger.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Challenger.Signature = sig
+	return signBytes
+}
+
+func (tx *SubchainChallengeTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Challenger.Address == addr {
+		tx.Challenger.Signature = sig
+		return true
+	}
+	return false
+}
+
+// Conflict returns the conflicting checkpoint of the challenge
+func (tx *SubchainChallengeTx) Conflict() *SubchainCheckpoint {
+	return &SubchainCheckpoint{
+		SubchainID: tx.SubchainID,
+		Height:     tx.Height,
+		BlockHash:  tx.BlockHash,
+		StateRoot:  tx.StateRoot,
+	}
+}
+
+// Gas returns the gas of the transaction, which grows with the number of signatures to verify
+func (tx *SubchainChallengeTx) Gas() uint64 {
+	return GasSubchainChallengeTx + GasSubchainPerSignature*uint64(len(tx.Signatures))
+}
+
+func (tx *SubchainChallengeTx) String() string {
+	return fmt.Sprintf("SubchainChallengeTx{challenger: %v, subchain_id: %v, height: %v, block_hash: %v, state_root: %v, signatures: %v}",
+		tx.Challenger.Address, tx.SubchainID.Hex(), tx.Height, tx.BlockHash.Hex(), tx.StateRoot.Hex(), len(tx.Signatures))
+}
+
+// --------------- Tx Addresses --------------- //
+
+// GetTxAddresses returns the addresses sending the coins or signing the transaction, and the
+// addresses receiving the coins or being acted upon by the transaction. The address of a
+// contract created by a smart contract transaction is not known before the transaction executes.
+func GetTxAddresses(tx Tx) (senders []common.Address, receivers []common.Address) {
+	switch tx := tx.(type) {
+	case *CoinbaseTx:
+		senders = append(senders, tx.Proposer.Address)
+		for _, output := range tx.Outputs {
+			receivers = append(receivers, output.Address)
+		}
+	case *SlashTx:
+		senders = append(senders, tx.Proposer.Address)
+		receivers = append(receivers, tx.SlashedAddress)
+	case *SendTx:
+		for _, input := range tx.Inputs {
+			senders = append(senders, input.Address)
+		}
+		for _, output := range tx.Outputs {
+			receivers = append(receivers, output.Address)
+		}
+	case *RametronStakeTx:
+		for _, input := range tx.Inputs {
+			senders = append(senders, input.Address)
+		}
+		for _, output := range tx.Outputs {
+			receivers = append(receivers, output.Address)
+		}
+	case *BatchSendTx:
+		for _, input := range tx.Inputs {
+			senders = append(senders, input.Address)
+		}
+		for _, output := range tx.Outputs {
+			receivers = append(receivers, output.Address)
+		}
+	case *ReserveFundTx:
+		senders = append(senders, tx.Source.Address)
+	case *ReleaseFundTx:
+		senders = append(senders, tx.Source.Address)
+	case *ServicePaymentTx:
+		senders = append(senders, tx.Source.Address)
+		receivers = append(receivers, tx.Target.Address)
+	case *SplitRuleTx:
+		senders = append(senders, tx.Initiator.Address)
+		for _, split := range tx.Splits {
+			receivers = append(receivers, split.Address)
+		}
+	case *SmartContractTx:
+		senders = append(senders, tx.From.Address)
+		if tx.To.Address != (common.Address{}) {
+			receivers = append(receivers, tx.To.Address)
+		}
+	case *DepositStakeTx:
+		senders = append(senders, tx.Source.Address)
+		receivers = append(receivers, tx.Holder.Address)
+	case *DepositStakeTxV2:
+		senders = append(senders, tx.Source.Address)
+		receivers = append(receivers, tx.Holder.Address)
+	case *WithdrawStakeTx:
+		senders = append(senders, tx.Source.Address)
+		receivers = append(receivers, tx.Holder.Address)
+	case *SessionKeyTx:
+		senders = append(senders, tx.Account.Address)
+		receivers = append(receivers, tx.SessionKey)
+	case *SlashAppealTx:
+		senders = append(senders, tx.Appellant.Address)
+	case *SlashAppealVoteTx:
+		senders = append(senders, tx.Voter.Address)
+		receivers = append(receivers, tx.Appellant)
+	case *ClaimEscrowTx:
+		senders = append(senders, tx.Escrow.Address)
+		if clause, ok := tx.Clause(); ok {
+			receivers = append(receivers, clause.Recipient)
+		}
+	case *MeteredSettlementTx:
+		senders = append(senders, tx.Client)
+		receivers = append(receivers, tx.EdgeNode.Address)
+	case *DelegateTx:
+		senders = append(senders, tx.Delegator.Address)
+		receivers = append(receivers, tx.Validator)
+	case *UndelegateTx:
+		senders = append(senders, tx.Delegator.Address)
+		receivers = append(receivers, tx.Validator)
+	case *SetCommissionTx:
+		senders = append(senders, tx.Validator.Address)
+	case *RametronAttestationTx:
+		senders = append(senders, tx.Guardian.Address)
+		for _, heartbeat := range tx.Heartbeats {
+			receivers = append(receivers, heartbeat.Node)
+		}
+	case *KeyRotationTx:
+		senders = append(senders, tx.Account.Address)
+	case *HTLCCreateTx:
+		senders = append(senders, tx.Sender.Address)
+		receivers = append(receivers, tx.Recipient)
+	case *HTLCClaimTx:
+		senders = append(senders, tx.Claimer.Address)
+	case *HTLCRefundTx:
+		senders = append(senders, tx.Refunder.Address)
+	case *ProposalTx:
+		senders = append(senders, tx.Proposer.Address)
+	case *VoteTx:
+		senders = append(senders, tx.Voter.Address)
+	case *BridgeLockTx:
+		senders = append(senders, tx.Sender.Address)
+	case *BridgeBurnTx:
+		senders = append(senders, tx.Sender.Address)
+	case *BridgeClaimTx:
+		senders = append(senders, tx.Relayer.Address)
+		receivers = append(receivers, tx.Proof.Event.Recipient)
+	case *SubchainRegisterTx:
+		senders = append(senders, tx.Owner.Address)
+	case *SubchainStakeTx:
+		senders = append(senders, tx.Staker.Address)
+	case *SubchainCheckpointTx:
+		senders = append(senders, tx.Submitter.Address)
+	case *SubchainChallengeTx:
+		senders = append(senders, tx.Challenger.Address)
+	}
+	return senders, receivers
+}
+
+// --------------- Utils --------------- //
+
+type EthereumTxWrapper struct {
+	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
+	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
+	GasLimit     uint64          `json:"gas"      gencodec:"required"`
+	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
+	Amount       *big.Int        `json:"value"    gencodec:"required"`
+	Payload      []byte          `json:"input"    gencodec:"required"`
+}
+
+// Need to add the following prefix to the tx signbytes to be compatible with
+// the Ethereum tx format
+func addPrefixForSignBytes(signBytes common.Bytes) common.Bytes {
+	ethTx := EthereumTxWrapper{
+		AccountNonce: uint64(0),
+		Price:        new(big.Int).SetUint64(0),
+		GasLimit:     uint64(0),
+		Recipient:    &common.Address{},
+		Amount:       new(big.Int).SetUint64(0),
+		Payload:      signBytes,
+	}
+	signBytes, err := rlp.EncodeToBytes(ethTx)
+	if err != nil {
+		log.Panic(err)
+	}
+	return signBytes
+}
+
+// TxFromSignBytes recovers the chain ID and the transaction from the sign bytes of the
+// transaction, e.g. for a signer to review what it signs. The transaction is returned without
+// signatures, and fails to decode unless its SignBytes are the given bytes.
+func TxFromSignBytes(signBytes common.Bytes) (string, Tx, error) {
+	ethTx := &EthereumTxWrapper{}
+	if err := rlp.DecodeBytes(signBytes, ethTx); err != nil {
+		return "", nil, fmt.Errorf("Failed to decode the sign bytes: %v", err)
+	}
+	kind, chainID, txBytes, err := rlp.Split(ethTx.Payload)
+	if err != nil || kind != rlp.String {
+		return "", nil, fmt.Errorf("Failed to decode the chain ID: %v", err)
+	}
+	tx, err := TxFromBytes(txBytes)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to decode the transaction: %v", err)
+	}
+	if !bytes.Equal(tx.SignBytes(string(chainID)), signBytes) {
+		return "", nil, errors.New("The sign bytes are not of a transaction")
+	}
+	return string(chainID), tx, nil
+}
@@ -0,0 +1,31 @@
+package types
+
+// ExpiringTx is a transaction which can be signed to expire after a block height. Since the height is
+// covered by the signature, a tx which failed to be included in time can safely be signed again with
+// new parameters without the risk of both being executed.
+type ExpiringTx interface {
+	Tx
+	GetValidUntilBlock() uint64
+}
+
+func (tx *SendTx) GetValidUntilBlock() uint64 {
+	return tx.ValidUntilBlock
+}
+
+func (tx *BatchSendTx) GetValidUntilBlock() uint64 {
+	return tx.ValidUntilBlock
+}
+
+// GetValidUntilBlock returns the last block height the tx can be included in, or 0 if it does not expire
+func GetValidUntilBlock(tx Tx) uint64 {
+	if etx, ok := tx.(ExpiringTx); ok {
+		return etx.GetValidUntilBlock()
+	}
+	return 0
+}
+
+// IsTxExpired indicates whether the tx can no longer be included in the block of the given height
+func IsTxExpired(tx Tx, blockHeight uint64) bool {
+	validUntil := GetValidUntilBlock(tx)
+	return validUntil != 0 && blockHeight > validUntil
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTxExpired(t *testing.T) {
+	assert := assert.New(t)
+
+	// A tx without ValidUntilBlock never expires
+	sendTx := &SendTx{}
+	assert.False(IsTxExpired(sendTx, 1000000))
+
+	sendTx.ValidUntilBlock = 100
+	assert.False(IsTxExpired(sendTx, 99))
+	assert.False(IsTxExpired(sendTx, 100))
+	assert.True(IsTxExpired(sendTx, 101))
+
+	batchSendTx := &BatchSendTx{ValidUntilBlock: 100}
+	assert.Equal(uint64(100), GetValidUntilBlock(batchSendTx))
+	assert.True(IsTxExpired(batchSendTx, 101))
+
+	// The tx types without ValidUntilBlock do not expire
+	assert.Equal(uint64(0), GetValidUntilBlock(&ReserveFundTx{}))
+	assert.False(IsTxExpired(&ReserveFundTx{}, 1000000))
+}
+
+func TestSendTxValidUntilBlockEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	// The encoding of a tx without ValidUntilBlock is unchanged
+	sendTx := &SendTx{Fee: NewCoins(0, 1), Inputs: []TxInput{}, Outputs: []TxOutput{}}
+	raw, err := TxToBytes(sendTx)
+	assert.Nil(err)
+	expiring := &SendTx{Fee: NewCoins(0, 1), Inputs: []TxInput{}, Outputs: []TxOutput{}, ValidUntilBlock: 100}
+	expiringRaw, err := TxToBytes(expiring)
+	assert.Nil(err)
+	assert.True(len(expiringRaw) > len(raw))
+	assert.NotEqual(sendTx.SignBytes("pandonet"), expiring.SignBytes("pandonet"))
+
+	decoded, err := TxFromBytes(expiringRaw)
+	assert.Nil(err)
+	assert.Equal(uint64(100), decoded.(*SendTx).ValidUntilBlock)
+	decoded, err = TxFromBytes(raw)
+	assert.Nil(err)
+	assert.Equal(uint64(0), decoded.(*SendTx).ValidUntilBlock)
+}
@@ -0,0 +1,171 @@
+package types
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/pandotoken/pando/rlp"
+)
+
+// TxTypeSpec declares a transaction type. A new transaction type registers its spec once with
+// RegisterTxType, and the serialization, the tx hashing and the tooling pick it up from there.
+type TxTypeSpec struct {
+	Type TxType // the type byte prefixed to the encoded transaction
+	Name string // short name of the type, e.g. "send", used by the tooling
+	New  func() Tx
+
+	// Encode and Decode override the RLP codec of the transaction body. Nil uses the
+	// default RLP encoding of the struct.
+	Encode func(w io.Writer, tx Tx) error
+	Decode func(s *rlp.Stream, tx Tx) error
+
+	// SignBytes overrides the bytes the transaction hash is computed over. Nil uses
+	// tx.SignBytes.
+	SignBytes func(chainID string, tx Tx) []byte
+}
+
+var (
+	txTypeSpecsMtx  sync.RWMutex
+	txTypeSpecs     = make(map[TxType]*TxTypeSpec)
+	txTypesByGoType = make(map[reflect.Type]TxType)
+)
+
+// RegisterTxType registers a transaction type. It is meant to be called from init(), and panics
+// if the type byte, the name or the Go type is already taken.
+func RegisterTxType(spec *TxTypeSpec) {
+	if spec.New == nil || spec.Name == "" {
+		panic(fmt.Sprintf("Incomplete spec for tx type %v", spec.Type))
+	}
+
+	txTypeSpecsMtx.Lock()
+	defer txTypeSpecsMtx.Unlock()
+
+	if existing, ok := txTypeSpecs[spec.Type]; ok {
+		panic(fmt.Sprintf("Tx type %v is already registered as %v", spec.Type, existing.Name))
+	}
+	for _, existing := range txTypeSpecs {
+		if existing.Name == spec.Name {
+			panic(fmt.Sprintf("Tx type name %v is already registered", spec.Name))
+		}
+	}
+	goType := reflect.TypeOf(spec.New())
+	if _, ok := txTypesByGoType[goType]; ok {
+		panic(fmt.Sprintf("Go type %v is already registered", goType))
+	}
+
+	txTypeSpecs[spec.Type] = spec
+	txTypesByGoType[goType] = spec.Type
+}
+
+// LookupTxType returns the spec of the given transaction type
+func LookupTxType(txType TxType) (*TxTypeSpec, bool) {
+	txTypeSpecsMtx.RLock()
+	defer txTypeSpecsMtx.RUnlock()
+
+	spec, ok := txTypeSpecs[txType]
+	return spec, ok
+}
+
+// LookupTxTypeByName returns the spec of the transaction type with the given name
+func LookupTxTypeByName(name string) (*TxTypeSpec, bool) {
+	txTypeSpecsMtx.RLock()
+	defer txTypeSpecsMtx.RUnlock()
+
+	for _, spec := range txTypeSpecs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return nil, false
+}
+
+// RegisteredTxTypes returns all the registered transaction types in ascending order
+func RegisteredTxTypes() []TxType {
+	txTypeSpecsMtx.RLock()
+	defer txTypeSpecsMtx.RUnlock()
+
+	txTypes := make([]TxType, 0, len(txTypeSpecs))
+	for txType := range txTypeSpecs {
+		txTypes = append(txTypes, txType)
+	}
+	sort.Slice(txTypes, func(i, j int) bool { return txTypes[i] < txTypes[j] })
+	return txTypes
+}
+
+// NewTx allocates an empty transaction of the given type
+func NewTx(txType TxType) (Tx, error) {
+	spec, ok := LookupTxType(txType)
+	if !ok {
+		return nil, fmt.Errorf("Unknown TX type: %v", txType)
+	}
+	return spec.New(), nil
+}
+
+// TxTypeName returns the name of the given transaction type, "unknown" if not registered
+func TxTypeName(txType TxType) string {
+	spec, ok := LookupTxType(txType)
+	if !ok {
+		return "unknown"
+	}
+	return spec.Name
+}
+
+func getTxTypeSpec(t Tx) (*TxTypeSpec, bool) {
+	txTypeSpecsMtx.RLock()
+	defer txTypeSpecsMtx.RUnlock()
+
+	txType, ok := txTypesByGoType[reflect.TypeOf(t)]
+	if !ok {
+		return nil, false
+	}
+	return txTypeSpecs[txType], true
+}
+
+func init() {
+	RegisterTxType(&TxTypeSpec{Type: TxCoinbase, Name: "coinbase", New: func() Tx { return &CoinbaseTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSlash, Name: "slash", New: func() Tx { return &SlashTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSend, Name: "send", New: func() Tx { return &SendTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxRametronStake, Name: "rametron_stake", New: func() Tx { return &RametronStakeTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxReserveFund, Name: "reserve_fund", New: func() Tx { return &ReserveFundTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxReleaseFund, Name: "release_fund", New: func() Tx { return &ReleaseFundTx{} }})
+	RegisterTxType(&TxTypeSpec{
+		Type: TxServicePayment,
+		Name: "service_payment",
+		New:  func() Tx { return &ServicePaymentTx{} },
+		// The target signs last, over the complete transaction
+		SignBytes: func(chainID string, tx Tx) []byte {
+			return tx.(*ServicePaymentTx).TargetSignBytes(chainID)
+		},
+	})
+	RegisterTxType(&TxTypeSpec{Type: TxSplitRule, Name: "split_rule", New: func() Tx { return &SplitRuleTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSmartContract, Name: "smart_contract", New: func() Tx { return &SmartContractTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxDepositStake, Name: "deposit_stake", New: func() Tx { return &DepositStakeTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxWithdrawStake, Name: "withdraw_stake", New: func() Tx { return &WithdrawStakeTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxDepositStakeV2, Name: "deposit_stake_v2", New: func() Tx { return &DepositStakeTxV2{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSessionKey, Name: "session_key", New: func() Tx { return &SessionKeyTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxBatchSend, Name: "batch_send", New: func() Tx { return &BatchSendTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSlashAppeal, Name: "slash_appeal", New: func() Tx { return &SlashAppealTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSlashAppealVote, Name: "slash_appeal_vote", New: func() Tx { return &SlashAppealVoteTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxClaimEscrow, Name: "claim_escrow", New: func() Tx { return &ClaimEscrowTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxMeteredSettlement, Name: "metered_settlement", New: func() Tx { return &MeteredSettlementTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxDelegate, Name: "delegate", New: func() Tx { return &DelegateTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxUndelegate, Name: "undelegate", New: func() Tx { return &UndelegateTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSetCommission, Name: "set_commission", New: func() Tx { return &SetCommissionTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxRametronAttestation, Name: "rametron_attestation", New: func() Tx { return &RametronAttestationTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxKeyRotation, Name: "key_rotation", New: func() Tx { return &KeyRotationTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxHTLCCreate, Name: "htlc_create", New: func() Tx { return &HTLCCreateTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxHTLCClaim, Name: "htlc_claim", New: func() Tx { return &HTLCClaimTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxHTLCRefund, Name: "htlc_refund", New: func() Tx { return &HTLCRefundTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxProposal, Name: "proposal", New: func() Tx { return &ProposalTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxVote, Name: "vote", New: func() Tx { return &VoteTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxBridgeLock, Name: "bridge_lock", New: func() Tx { return &BridgeLockTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxBridgeBurn, Name: "bridge_burn", New: func() Tx { return &BridgeBurnTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxBridgeClaim, Name: "bridge_claim", New: func() Tx { return &BridgeClaimTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSubchainRegister, Name: "subchain_register", New: func() Tx { return &SubchainRegisterTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSubchainStake, Name: "subchain_stake", New: func() Tx { return &SubchainStakeTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSubchainCheckpoint, Name: "subchain_checkpoint", New: func() Tx { return &SubchainCheckpointTx{} }})
+	RegisterTxType(&TxTypeSpec{Type: TxSubchainChallenge, Name: "subchain_challenge", New: func() Tx { return &SubchainChallengeTx{} }})
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const txTest TxType = 0xff00
+
+type testTx struct {
+	From common.Address
+	Memo string
+}
+
+func (_ *testTx) AssertIsTx() {}
+
+func (tx *testTx) SignBytes(chainID string) []byte {
+	return append([]byte(chainID), []byte(tx.Memo)...)
+}
+
+func TestTxRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	RegisterTxType(&TxTypeSpec{Type: txTest, Name: "test", New: func() Tx { return &testTx{} }})
+
+	// The type byte, the name and the Go type can only be registered once
+	assert.Panics(func() {
+		RegisterTxType(&TxTypeSpec{Type: txTest, Name: "test2", New: func() Tx { return &SendTx{} }})
+	})
+	assert.Panics(func() {
+		RegisterTxType(&TxTypeSpec{Type: txTest + 1, Name: "send", New: func() Tx { return &testTx{} }})
+	})
+	assert.Panics(func() {
+		RegisterTxType(&TxTypeSpec{Type: txTest + 1, Name: "test2", New: func() Tx { return &SendTx{} }})
+	})
+
+	tx := &testTx{From: common.HexToAddress("0x111"), Memo: "hello"}
+	txType, err := GetTxType(tx)
+	assert.Nil(err)
+	assert.Equal(txTest, txType)
+	assert.Equal("test", TxTypeName(txType))
+
+	raw, err := TxToBytes(tx)
+	assert.Nil(err)
+	decoded, err := TxFromBytes(raw)
+	assert.Nil(err)
+	assert.Equal(tx, decoded)
+	assert.Equal(TxID("testnet", tx), TxID("testnet", decoded))
+
+	spec, ok := LookupTxTypeByName("send")
+	assert.True(ok)
+	assert.Equal(TxSend, spec.Type)
+	assert.Equal("unknown", TxTypeName(txTest+1))
+
+	_, err = TxFromBytes([]byte{0x82, 0xff, 0x01})
+	assert.NotNil(err)
+}
+
+func TestTxRegistryBuiltinTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	// Every built-in tx type survives the round trip through its registered codec
+	for _, txType := range RegisteredTxTypes() {
+		if txType == txTest {
+			continue
+		}
+		tx, err := NewTx(txType)
+		assert.Nil(err)
+		gotType, err := GetTxType(tx)
+		assert.Nil(err)
+		assert.Equal(txType, gotType)
+
+		raw, err := TxToBytes(tx)
+		assert.Nil(err, TxTypeName(txType))
+		decoded, err := TxFromBytes(raw)
+		assert.Nil(err, TxTypeName(txType))
+		gotType, _ = GetTxType(decoded)
+		assert.Equal(txType, gotType)
+	}
+}
@@ -388,6 +388,70 @@ func TestSendTxProto(t *testing.T) {
 	assert.False(tx2.Inputs[0].Signature.IsEmpty())
 }
 
+func TestSendTxData(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	chainID := "test_chain_id"
+	test1PrivAcc := PrivAccountFromSecret("sendtx1")
+	test2PrivAcc := PrivAccountFromSecret("sendtx2")
+
+	tx := &SendTx{
+		Fee: Coins{PandoWei: big.NewInt(0), PTXWei: big.NewInt(2)},
+		Inputs: []TxInput{
+			NewTxInput(test1PrivAcc.Address, Coins{PandoWei: big.NewInt(0), PTXWei: big.NewInt(10)}, 1),
+		},
+		Outputs: []TxOutput{
+			TxOutput{
+				Address: test2PrivAcc.Address,
+				Coins:   Coins{PandoWei: big.NewInt(0), PTXWei: big.NewInt(8)},
+			},
+		},
+	}
+	noDataBytes, err := TxToBytes(tx)
+	require.Nil(err)
+	noDataSignBytes := tx.SignBytes(chainID)
+	assert.Equal(new(big.Int).SetUint64(MinimumTransactionFeePTXWei), tx.MinimumFee())
+
+	// The data is covered by the sign bytes and survives the serialization
+	tx.Data = common.Bytes("deposit-id-12345")
+	signBytes := tx.SignBytes(chainID)
+	assert.NotEqual(noDataSignBytes, signBytes)
+	tx.SetSignature(test1PrivAcc.Address, test1PrivAcc.Sign(signBytes))
+
+	b, err := TxToBytes(tx)
+	require.Nil(err)
+	decoded, err := TxFromBytes(b)
+	require.Nil(err)
+	tx2 := decoded.(*SendTx)
+	assert.Equal(tx.Data, tx2.Data)
+	assert.Equal(signBytes, tx2.SignBytes(chainID))
+
+	expectedFee := new(big.Int).SetUint64(MinimumSendTxFeePerDataBytePTXWei * uint64(len(tx.Data)))
+	expectedFee.Add(expectedFee, new(big.Int).SetUint64(MinimumTransactionFeePTXWei))
+	assert.Equal(expectedFee, tx.MinimumFee())
+
+	// SendTx encoded before the data field was added can still be decoded
+	decoded, err = TxFromBytes(noDataBytes)
+	require.Nil(err)
+	assert.Empty(decoded.(*SendTx).Data)
+
+	// but the empty optional fields must be omitted, so that the tx has a single encoding
+	for _, fields := range [][]interface{}{
+		{tx.Fee, tx.Inputs, tx.Outputs, common.Bytes{}},
+		{tx.Fee, tx.Inputs, tx.Outputs, tx.Data, uint64(0)},
+	} {
+		raw, err := rlp.EncodeToBytes(fields)
+		require.Nil(err)
+		assert.NotNil(rlp.DecodeBytes(raw, &SendTx{}))
+	}
+	tx.Data = common.Bytes{}
+	raw, err := rlp.EncodeToBytes(tx)
+	require.Nil(err)
+	raw2, err := rlp.EncodeToBytes([]interface{}{tx.Fee, tx.Inputs, tx.Outputs})
+	require.Nil(err)
+	assert.Equal(raw2, raw)
+}
+
 //---------------------------RametronStake ----------------
 
 func TestRametronStakeTxSignable(t *testing.T) {
@@ -955,3 +1019,50 @@ func TestSmartContractTxJSON(t *testing.T) {
 	assert.Equal(0, gasPrice.Cmp(d.GasPrice))
 }
 
+
+func TestTxFromSignBytes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	acc := MakeAcc("signer")
+	sendTx := &SendTx{
+		Fee: NewCoins(0, 1000000000000),
+		Inputs: []TxInput{{
+			Address:  acc.Address,
+			Coins:    NewCoins(100, 1000000000000),
+			Sequence: 3,
+		}},
+		Outputs: []TxOutput{{
+			Address: getTestAddress("output1"),
+			Coins:   NewCoins(100, 0),
+		}},
+		Data: common.Bytes("memo"),
+	}
+	signBytes := sendTx.SignBytes(chainID)
+
+	decodedChainID, decoded, err := TxFromSignBytes(signBytes)
+	require.Nil(err)
+	assert.Equal(chainID, decodedChainID)
+	decodedSendTx, ok := decoded.(*SendTx)
+	require.True(ok)
+	assert.Equal(signBytes, decodedSendTx.SignBytes(chainID))
+
+	// The signature set on the decoded transaction is valid for the original one
+	sig := acc.Sign(signBytes)
+	require.True(decodedSendTx.SetSignature(acc.Address, sig))
+	raw, err := TxToBytes(decodedSendTx)
+	require.Nil(err)
+	sendTx.SetSignature(acc.Address, sig)
+	expected, err := TxToBytes(sendTx)
+	require.Nil(err)
+	assert.Equal(expected, raw)
+
+	// The sign bytes of the transaction with signatures are the same
+	_, _, err = TxFromSignBytes(sendTx.SignBytes(chainID))
+	assert.Nil(err)
+
+	_, _, err = TxFromSignBytes(common.Bytes("not a transaction"))
+	assert.NotNil(err)
+	_, _, err = TxFromSignBytes(addPrefixForSignBytes(common.Bytes("not a transaction")))
+	assert.NotNil(err)
+}
@@ -0,0 +1,418 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/math"
+	"github.com/pandotoken/pando/crypto"
+)
+
+/*
+Typed structured signing, in the style of EIP-712.
+
+Besides the RLP encoded SignBytes, a transaction can be signed over its TypedSignBytes
+
+	"\x19\x01" || domainSeparator || hashStruct(tx)
+
+so that wallets (e.g. eth_signTypedData_v4 in MetaMask, or hardware devices) can display
+the human-readable fields of the transaction before signing. The domain separator binds
+the signature to the chain ID and the tx type:
+
+	EIP712Domain(string name,string version,string chainId,uint16 txType)
+
+The struct types are derived from the Go tx structs. Field names are the lower camel case
+of the Go field names, and the fields are mapped to the following types:
+
+	common.Address            address
+	common.Hash               bytes32
+	*big.Int                  uint256
+	uintN, TxType             uintN (uint64 for uint)
+	string                    string
+	common.Bytes, BLS keys    bytes
+	struct                    the struct type, e.g. TxInput
+	slice                     array of the element type, e.g. TxOutput[]
+
+The signature fields are excluded, hence all the signers of a transaction sign the same
+typed data.
+*/
+
+const (
+	TypedSignDomainName    = "Pando"
+	TypedSignDomainVersion = "1"
+
+	typedSignDomainType = "EIP712Domain"
+)
+
+var typedSignPrefix = []byte{0x19, 0x01}
+
+var (
+	addressType   = reflect.TypeOf(common.Address{})
+	hashType      = reflect.TypeOf(common.Hash{})
+	bigIntType    = reflect.TypeOf(big.Int{})
+	signatureType = reflect.TypeOf(crypto.Signature{})
+	multiSigType  = reflect.TypeOf(MultiSigSignature{})
+	bytesType     = reflect.TypeOf(common.Bytes{})
+	toBytesType   = reflect.TypeOf((*interface{ ToBytes() common.Bytes })(nil)).Elem()
+)
+
+// TypedDataField is a field of a typed data struct type
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataDomain is the domain of the typed data, which separates the signatures for
+// different chains and tx types
+type TypedDataDomain struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	ChainID string `json:"chainId"`
+	TxType  TxType `json:"txType"`
+}
+
+// NewTypedDataDomain creates the domain of the given chain and tx type
+func NewTypedDataDomain(chainID string, txType TxType) TypedDataDomain {
+	return TypedDataDomain{
+		Name:    TypedSignDomainName,
+		Version: TypedSignDomainVersion,
+		ChainID: chainID,
+		TxType:  txType,
+	}
+}
+
+func typedDataDomainFields() []TypedDataField {
+	return []TypedDataField{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "string"},
+		{Name: "txType", Type: "uint16"},
+	}
+}
+
+// Separator returns the domain separator, i.e. the hashStruct of the domain
+func (d TypedDataDomain) Separator() common.Hash {
+	typeHash := crypto.Keccak256(encodeTypedType(typedSignDomainType,
+		map[string][]TypedDataField{typedSignDomainType: typedDataDomainFields()}))
+	return crypto.Keccak256Hash(
+		typeHash,
+		crypto.Keccak256([]byte(d.Name)),
+		crypto.Keccak256([]byte(d.Version)),
+		crypto.Keccak256([]byte(d.ChainID)),
+		math.PaddedBigBytes(new(big.Int).SetUint64(uint64(d.TxType)), 32),
+	)
+}
+
+// TypedData is the typed data of a transaction in the JSON format of eth_signTypedData_v4
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      TypedDataDomain             `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// TypedSignData returns the typed data of the transaction, which wallets can display and sign
+func TypedSignData(chainID string, tx Tx) (*TypedData, error) {
+	txType, err := GetTxType(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(tx))
+	types := map[string][]TypedDataField{typedSignDomainType: typedDataDomainFields()}
+	primaryType, err := collectTypedTypes(v.Type(), types)
+	if err != nil {
+		return nil, err
+	}
+	message, ok := typedMessageValue(v, primaryType, types).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unsupported tx type: %v", v.Type())
+	}
+
+	return &TypedData{
+		Types:       types,
+		PrimaryType: primaryType,
+		Domain:      NewTypedDataDomain(chainID, txType),
+		Message:     message,
+	}, nil
+}
+
+// TypedSignBytes returns the bytes to sign for the typed structured signing of the transaction
+func TypedSignBytes(chainID string, tx Tx) (common.Bytes, error) {
+	txType, err := GetTxType(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(tx))
+	types := map[string][]TypedDataField{}
+	primaryType, err := collectTypedTypes(v.Type(), types)
+	if err != nil {
+		return nil, err
+	}
+	domainSeparator := NewTypedDataDomain(chainID, txType).Separator()
+	structHash := hashTypedStruct(primaryType, v, types)
+
+	signBytes := append([]byte{}, typedSignPrefix...)
+	signBytes = append(signBytes, domainSeparator[:]...)
+	signBytes = append(signBytes, structHash[:]...)
+	return signBytes, nil
+}
+
+// collectTypedTypes returns the typed data type of the Go type, and adds the struct types
+// it references to types. It returns an empty type for the fields to be excluded.
+func collectTypedTypes(t reflect.Type, types map[string][]TypedDataField) (string, error) {
+	switch {
+	case t == addressType:
+		return "address", nil
+	case t == hashType:
+		return "bytes32", nil
+	case t == bigIntType || t == reflect.PtrTo(bigIntType):
+		return "uint256", nil
+	case t == signatureType || t == reflect.PtrTo(signatureType),
+		t == multiSigType || t == reflect.PtrTo(multiSigType):
+		return "", nil
+	case t == bytesType || t.Implements(toBytesType):
+		return "bytes", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return collectTypedTypes(t.Elem(), types)
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Uint:
+		return "uint64", nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("uint%d", t.Bits()), nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes", nil
+		}
+		elemType, err := collectTypedTypes(t.Elem(), types)
+		if err != nil || elemType == "" {
+			return elemType, err
+		}
+		return elemType + "[]", nil
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := types[name]; ok {
+			return name, nil
+		}
+		fields := []TypedDataField{}
+		types[name] = fields // placeholder to terminate recursive types
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			fieldType, err := collectTypedTypes(field.Type, types)
+			if err != nil {
+				return "", err
+			}
+			if fieldType == "" {
+				continue
+			}
+			fields = append(fields, TypedDataField{Name: lowerCamelCase(field.Name), Type: fieldType})
+		}
+		types[name] = fields
+		return name, nil
+	}
+	return "", fmt.Errorf("Unsupported type for typed signing: %v", t)
+}
+
+// encodeTypedType returns the encoding of the struct type, followed by the struct types it
+// references sorted by name
+func encodeTypedType(primaryType string, types map[string][]TypedDataField) []byte {
+	deps := map[string]bool{}
+	findTypedDependencies(primaryType, types, deps)
+	delete(deps, primaryType)
+
+	names := []string{primaryType}
+	sorted := []string{}
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+	names = append(names, sorted...)
+
+	var buf strings.Builder
+	for _, name := range names {
+		buf.WriteString(name)
+		buf.WriteString("(")
+		for i, field := range types[name] {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(field.Type)
+			buf.WriteString(" ")
+			buf.WriteString(field.Name)
+		}
+		buf.WriteString(")")
+	}
+	return []byte(buf.String())
+}
+
+func findTypedDependencies(typ string, types map[string][]TypedDataField, deps map[string]bool) {
+	typ = strings.TrimSuffix(typ, "[]")
+	fields, ok := types[typ]
+	if !ok || deps[typ] {
+		return
+	}
+	deps[typ] = true
+	for _, field := range fields {
+		findTypedDependencies(field.Type, types, deps)
+	}
+}
+
+func hashTypedStruct(typ string, v reflect.Value, types map[string][]TypedDataField) common.Hash {
+	data := [][]byte{crypto.Keccak256(encodeTypedType(typ, types))}
+	for _, field := range typedStructFields(v, types[typ]) {
+		data = append(data, encodeTypedValue(field.typ, field.value, types))
+	}
+	return crypto.Keccak256Hash(data...)
+}
+
+type typedFieldValue struct {
+	name  string
+	typ   string
+	value reflect.Value
+}
+
+// typedStructFields returns the values of the typed data fields of the struct value
+func typedStructFields(v reflect.Value, fields []TypedDataField) []typedFieldValue {
+	values := make([]typedFieldValue, 0, len(fields))
+	for _, field := range fields {
+		for i := 0; i < v.NumField(); i++ {
+			if lowerCamelCase(v.Type().Field(i).Name) == field.Name {
+				values = append(values, typedFieldValue{name: field.Name, typ: field.Type, value: v.Field(i)})
+				break
+			}
+		}
+	}
+	return values
+}
+
+// encodeTypedValue returns the 32 byte encoding of the value
+func encodeTypedValue(typ string, v reflect.Value, types map[string][]TypedDataField) []byte {
+	if strings.HasSuffix(typ, "[]") {
+		elemType := strings.TrimSuffix(typ, "[]")
+		data := [][]byte{}
+		for i := 0; i < v.Len(); i++ {
+			data = append(data, encodeTypedValue(elemType, v.Index(i), types))
+		}
+		return crypto.Keccak256(data...)
+	}
+
+	switch typ {
+	case "address":
+		addr := v.Interface().(common.Address)
+		return common.LeftPadBytes(addr[:], 32)
+	case "bytes32":
+		hash := v.Interface().(common.Hash)
+		return hash[:]
+	case "uint256":
+		return math.PaddedBigBytes(math.U256(typedBigInt(v)), 32)
+	case "bool":
+		if v.Bool() {
+			return math.PaddedBigBytes(big.NewInt(1), 32)
+		}
+		return make([]byte, 32)
+	case "string":
+		return crypto.Keccak256([]byte(v.String()))
+	case "bytes":
+		return crypto.Keccak256(typedBytes(v))
+	}
+	if strings.HasPrefix(typ, "uint") {
+		return math.PaddedBigBytes(new(big.Int).SetUint64(v.Uint()), 32)
+	}
+
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return make([]byte, 32)
+	}
+	structHash := hashTypedStruct(typ, v, types)
+	return structHash[:]
+}
+
+// typedMessageValue returns the JSON value of the typed data message
+func typedMessageValue(v reflect.Value, typ string, types map[string][]TypedDataField) interface{} {
+	if strings.HasSuffix(typ, "[]") {
+		elemType := strings.TrimSuffix(typ, "[]")
+		values := []interface{}{}
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, typedMessageValue(v.Index(i), elemType, types))
+		}
+		return values
+	}
+
+	switch typ {
+	case "address":
+		return v.Interface().(common.Address).Hex()
+	case "bytes32":
+		return v.Interface().(common.Hash).Hex()
+	case "uint256":
+		return typedBigInt(v).String()
+	case "bool":
+		return v.Bool()
+	case "string":
+		return v.String()
+	case "bytes":
+		return "0x" + common.Bytes2Hex(typedBytes(v))
+	}
+	if strings.HasPrefix(typ, "uint") {
+		return new(big.Int).SetUint64(v.Uint()).String()
+	}
+
+	message := map[string]interface{}{}
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return message
+	}
+	for _, field := range typedStructFields(v, types[typ]) {
+		message[field.name] = typedMessageValue(field.value, field.typ, types)
+	}
+	return message
+}
+
+func typedBigInt(v reflect.Value) *big.Int {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return new(big.Int)
+		}
+		return new(big.Int).Set(v.Interface().(*big.Int))
+	}
+	b := v.Interface().(big.Int)
+	return new(big.Int).Set(&b)
+}
+
+func typedBytes(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return []byte{}
+	}
+	return v.Interface().(interface{ ToBytes() common.Bytes }).ToBytes()
+}
+
+// lowerCamelCase converts a Go field name to lower camel case, e.g. PTXWei to ptxWei
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] < 'A' || runes[i] > 'Z' {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && runes[i+1] >= 'a' && runes[i+1] <= 'z' {
+			break
+		}
+		runes[i] = runes[i] + ('a' - 'A')
+	}
+	return string(runes)
+}
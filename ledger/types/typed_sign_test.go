@@ -0,0 +1,127 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPerson struct {
+	Name   string
+	Wallet common.Address
+}
+
+type testMail struct {
+	From     testPerson
+	To       testPerson
+	Contents string
+}
+
+func TestTypedStructHash(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// The example in the EIP-712 specification
+	mail := testMail{
+		From:     testPerson{Name: "Cow", Wallet: common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")},
+		To:       testPerson{Name: "Bob", Wallet: common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")},
+		Contents: "Hello, Bob!",
+	}
+	types := map[string][]TypedDataField{}
+	primaryType, err := collectTypedTypes(reflect.TypeOf(mail), types)
+	require.Nil(err)
+	assert.Equal("testMail", primaryType)
+	assert.Equal("testMail(testPerson from,testPerson to,string contents)testPerson(string name,address wallet)",
+		string(encodeTypedType(primaryType, types)))
+
+	// Rename the types to match the specification
+	types["Mail"] = []TypedDataField{{"from", "Person"}, {"to", "Person"}, {"contents", "string"}}
+	types["Person"] = types["testPerson"]
+	delete(types, "testMail")
+	delete(types, "testPerson")
+	assert.Equal("0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e",
+		hashTypedStruct("Mail", reflect.ValueOf(mail), types).Hex())
+}
+
+func TestTypedSignBytes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := "test_chain_id"
+	va1 := PrivAccountFromSecret("validator1")
+	va2 := PrivAccountFromSecret("validator2")
+	tx := MakeSendTx(1, va2, va1)
+
+	types := map[string][]TypedDataField{}
+	_, err := collectTypedTypes(reflect.TypeOf(*tx), types)
+	require.Nil(err)
+	assert.Equal("SendTx(Coins fee,TxInput[] inputs,TxOutput[] outputs,bytes data,uint64 validUntilBlock)Coins(uint256 pandoWei,uint256 ptxWei)"+
+		"TxInput(address address,Coins coins,uint64 sequence)TxOutput(address address,Coins coins)",
+		string(encodeTypedType("SendTx", types)))
+
+	signBytes, err := TypedSignBytes(chainID, tx)
+	require.Nil(err)
+	assert.Equal(66, len(signBytes))
+	assert.Equal([]byte{0x19, 0x01}, []byte(signBytes[:2]))
+
+	// The signatures are not part of the typed data
+	sig := va1.Sign(signBytes)
+	tx.SetSignature(va1.Address, sig)
+	signBytes2, err := TypedSignBytes(chainID, tx)
+	require.Nil(err)
+	assert.Equal(signBytes, signBytes2)
+	assert.True(sig.Verify(signBytes, va1.Address))
+	assert.False(sig.Verify(tx.SignBytes(chainID), va1.Address))
+
+	// The domain separates the chains and tx types
+	signBytes3, err := TypedSignBytes("other_chain_id", tx)
+	require.Nil(err)
+	assert.NotEqual(signBytes, signBytes3)
+	assert.NotEqual(NewTypedDataDomain(chainID, TxSend).Separator(), NewTypedDataDomain(chainID, TxRametronStake).Separator())
+
+	tx.Outputs[0].Coins = NewCoins(5, 0)
+	signBytes4, err := TypedSignBytes(chainID, tx)
+	require.Nil(err)
+	assert.NotEqual(signBytes, signBytes4)
+}
+
+func TestTypedSignData(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	va1 := PrivAccountFromSecret("validator1")
+	tx := &SmartContractTx{
+		From:     TxInput{Address: va1.Address, Coins: NewCoins(0, 100), Sequence: 3},
+		To:       TxOutput{Address: common.HexToAddress("0x0d2fD67d573c8ecB4161510fc00754d64B401F86")},
+		GasLimit: 50000,
+		Data:     common.Hex2Bytes("a9059cbb"),
+	}
+
+	typedData, err := TypedSignData("test_chain_id", tx)
+	require.Nil(err)
+	assert.Equal("SmartContractTx", typedData.PrimaryType)
+	assert.Equal(TxSmartContract, typedData.Domain.TxType)
+	assert.Equal("test_chain_id", typedData.Domain.ChainID)
+	assert.Equal([]TypedDataField{{"from", "TxInput"}, {"to", "TxOutput"}, {"gasLimit", "uint64"}, {"gasPrice", "uint256"}, {"data", "bytes"}},
+		typedData.Types["SmartContractTx"])
+
+	from := typedData.Message["from"].(map[string]interface{})
+	assert.Equal(va1.Address.Hex(), from["address"])
+	assert.Equal("3", from["sequence"])
+	assert.Equal("100", from["coins"].(map[string]interface{})["ptxWei"])
+	assert.Equal("50000", typedData.Message["gasLimit"])
+	assert.Equal("0", typedData.Message["gasPrice"])
+	assert.Equal("0xa9059cbb", typedData.Message["data"])
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("fee", lowerCamelCase("Fee"))
+	assert.Equal("ptxWei", lowerCamelCase("PTXWei"))
+	assert.Equal("resourceIDs", lowerCamelCase("ResourceIDs"))
+	assert.Equal("blsPubkey", lowerCamelCase("BlsPubkey"))
+	assert.Equal("id", lowerCamelCase("ID"))
+}
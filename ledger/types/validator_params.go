@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// MaxCommissionRate is the highest commission rate a validator may set, in basis points
+const MaxCommissionRate uint64 = 10000
+
+// DefaultMaxCommissionRateChange is the default of the largest change of the commission rate
+// allowed per reward epoch, in basis points
+const DefaultMaxCommissionRateChange uint64 = 100
+
+// ParamMaxCommissionRateChange is the governance parameter for the largest change of the
+// commission rate allowed per reward epoch, in basis points
+const ParamMaxCommissionRateChange = "MaxCommissionRateChange"
+
+// ValidatorParams are the parameters a validator registers on-chain
+type ValidatorParams struct {
+	Validator        common.Address
+	CommissionRate   uint64 // the share of the rewards of the delegated stake kept by the validator, in basis points
+	LastUpdateHeight uint64 // height of the last SetCommissionTx of the validator
+}
+
+type ValidatorParamsJSON struct {
+	Validator        common.Address    `json:"validator"`
+	CommissionRate   common.JSONUint64 `json:"commission_rate"`
+	LastUpdateHeight common.JSONUint64 `json:"last_update_height"`
+}
+
+func NewValidatorParamsJSON(a ValidatorParams) ValidatorParamsJSON {
+	return ValidatorParamsJSON{
+		Validator:        a.Validator,
+		CommissionRate:   common.JSONUint64(a.CommissionRate),
+		LastUpdateHeight: common.JSONUint64(a.LastUpdateHeight),
+	}
+}
+
+func (a ValidatorParamsJSON) ValidatorParams() ValidatorParams {
+	return ValidatorParams{
+		Validator:        a.Validator,
+		CommissionRate:   uint64(a.CommissionRate),
+		LastUpdateHeight: uint64(a.LastUpdateHeight),
+	}
+}
+
+func (a ValidatorParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewValidatorParamsJSON(a))
+}
+
+func (a *ValidatorParams) UnmarshalJSON(data []byte) error {
+	var b ValidatorParamsJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.ValidatorParams()
+	return nil
+}
+
+func (p *ValidatorParams) String() string {
+	if p == nil {
+		return "nil-ValidatorParams"
+	}
+	return fmt.Sprintf("ValidatorParams{validator: %v, commission_rate: %v, last_update_height: %v}",
+		p.Validator, p.CommissionRate, p.LastUpdateHeight)
+}
+
+// CanChangeCommissionRate checks whether the commission rate can be changed to the given rate at
+// the given height. The rate changes at most once per reward epoch, by at most maxChange.
+func (p *ValidatorParams) CanChangeCommissionRate(rate uint64, height uint64, maxChange uint64) error {
+	if rate > MaxCommissionRate {
+		return fmt.Errorf("Commission rate %v exceeds the maximum of %v", rate, MaxCommissionRate)
+	}
+	if p.LastUpdateHeight != 0 && height < p.LastUpdateHeight+uint64(common.CheckpointInterval) {
+		return fmt.Errorf("Commission rate was changed at height %v, it can be changed again at height %v",
+			p.LastUpdateHeight, p.LastUpdateHeight+uint64(common.CheckpointInterval))
+	}
+	diff := rate - p.CommissionRate
+	if rate < p.CommissionRate {
+		diff = p.CommissionRate - rate
+	}
+	if diff > maxChange {
+		return fmt.Errorf("Commission rate can change by at most %v per epoch, from %v to %v requested",
+			maxChange, p.CommissionRate, rate)
+	}
+	return nil
+}
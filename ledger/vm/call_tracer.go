@@ -0,0 +1,164 @@
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+// CallTracerConfig are the configuration options of the call tracer
+type CallTracerConfig struct {
+	OnlyTopCall bool `json:"onlyTopCall"` // do not trace the internal calls
+	WithStorage bool `json:"withStorage"` // record the storage reads and writes of each frame
+}
+
+// StorageAccess is a storage slot read (SLOAD) or written (SSTORE) by a call frame
+type StorageAccess struct {
+	Op    string      `json:"op"`
+	Slot  common.Hash `json:"slot"`
+	Value common.Hash `json:"value"` // the value read or written
+}
+
+// CallFrame is a call frame in the format of the Geth callTracer. The storage accesses are a
+// Pando extension, only recorded with CallTracerConfig.WithStorage.
+type CallFrame struct {
+	Type         string           `json:"type"`
+	From         common.Address   `json:"from"`
+	To           common.Address   `json:"to"`
+	Value        *hexutil.Big     `json:"value,omitempty"`
+	Gas          hexutil.Uint64   `json:"gas"`
+	GasUsed      hexutil.Uint64   `json:"gasUsed"`
+	Input        hexutil.Bytes    `json:"input"`
+	Output       hexutil.Bytes    `json:"output,omitempty"`
+	Error        string           `json:"error,omitempty"`
+	RevertReason string           `json:"revertReason,omitempty"`
+	Storage      []*StorageAccess `json:"storage,omitempty"`
+	Calls        []*CallFrame     `json:"calls,omitempty"`
+}
+
+// CallTracer is a Tracer recording the tree of the call frames of a transaction, including the
+// internal value transfers between contracts.
+type CallTracer struct {
+	cfg   CallTracerConfig
+	stack []*CallFrame // the frames being executed, the top-level one first
+	root  *CallFrame
+}
+
+// NewCallTracer returns a new call tracer
+func NewCallTracer(cfg *CallTracerConfig) *CallTracer {
+	tracer := &CallTracer{}
+	if cfg != nil {
+		tracer.cfg = *cfg
+	}
+	return tracer
+}
+
+func newCallFrame(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) *CallFrame {
+	frame := &CallFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	return frame
+}
+
+// finish records the outcome of the call frame
+func (frame *CallFrame) finish(output []byte, gasUsed uint64, err error) {
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	if err == nil {
+		frame.Output = common.CopyBytes(output)
+		return
+	}
+	frame.Error = err.Error()
+	if err == errExecutionReverted {
+		frame.Error = "execution reverted"
+		frame.Output = common.CopyBytes(output)
+		frame.RevertReason = types.UnpackRevertReason(output)
+	}
+	if frame.Type == CREATE.String() || frame.Type == CREATE2.String() {
+		frame.To = common.Address{}
+	}
+}
+
+// CaptureStart implements the Tracer interface to start the top-level call frame.
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := CALL
+	if create {
+		typ = CREATE
+	}
+	t.root = newCallFrame(typ, from, to, input, gas, value)
+	t.stack = []*CallFrame{t.root}
+	return nil
+}
+
+// CaptureState implements the Tracer interface to record the storage accesses.
+func (t *CallTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	if !t.cfg.WithStorage || len(t.stack) == 0 {
+		return nil
+	}
+	frame := t.stack[len(t.stack)-1]
+	switch {
+	case op == SLOAD && stack.len() >= 1:
+		slot := common.BigToHash(stack.data[stack.len()-1])
+		frame.Storage = append(frame.Storage, &StorageAccess{
+			Op:    op.String(),
+			Slot:  slot,
+			Value: env.StateDB.GetState(contract.Address(), slot),
+		})
+	case op == SSTORE && stack.len() >= 2:
+		frame.Storage = append(frame.Storage, &StorageAccess{
+			Op:    op.String(),
+			Slot:  common.BigToHash(stack.data[stack.len()-1]),
+			Value: common.BigToHash(stack.data[stack.len()-2]),
+		})
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface, the faults are reported with the end of the frame.
+func (t *CallTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnter implements the Tracer interface to start an internal call frame.
+func (t *CallTracer) CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.cfg.OnlyTopCall || len(t.stack) == 0 {
+		return
+	}
+	t.stack = append(t.stack, newCallFrame(typ, from, to, input, gas, value))
+}
+
+// CaptureExit implements the Tracer interface to finish an internal call frame.
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.cfg.OnlyTopCall || len(t.stack) <= 1 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	frame.finish(output, gasUsed, err)
+
+	parent := t.stack[len(t.stack)-1]
+	parent.Calls = append(parent.Calls, frame)
+}
+
+// CaptureEnd implements the Tracer interface to finish the top-level call frame.
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if t.root != nil {
+		t.root.finish(output, gasUsed, err)
+	}
+	t.stack = nil
+	return nil
+}
+
+// Result returns the top-level call frame, nil if the EVM did not start executing.
+func (t *CallTracer) Result() *CallFrame {
+	return t.root
+}
@@ -0,0 +1,88 @@
+package vm
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/vm/params"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallTracer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	storeView := state.NewStoreView(0, common.Hash{}, backend.NewMemDatabase())
+	caller := common.HexToAddress("0x100")
+	outer := common.HexToAddress("0x200")
+	inner := common.HexToAddress("0x300")
+	storeView.CreateAccount(caller)
+
+	// ASM:
+	// push 0x1, push 0x0, sstore
+	// push 0x0, push 0x0, revert
+	innerCode, _ := hex.DecodeString("600160005560006000fd")
+	storeView.CreateAccount(inner)
+	storeView.SetCode(inner, innerCode)
+
+	// ASM:
+	// push 0x0 (x5), push20 <inner>, gas, call, pop
+	// push 0x5, sload, pop, stop
+	outerCode, _ := hex.DecodeString("60006000600060006000" + "73" + hex.EncodeToString(inner.Bytes()) + "5af150" + "6005545000")
+	storeView.CreateAccount(outer)
+	storeView.SetCode(outer, outerCode)
+
+	tracer := NewCallTracer(&CallTracerConfig{WithStorage: true})
+	context := Context{
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
+		Origin:      caller,
+		GasPrice:    big.NewInt(1),
+		GasLimit:    100000,
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(0),
+		Difficulty:  big.NewInt(0),
+	}
+	evm := NewEVM(context, storeView, &params.ChainConfig{ChainID: big.NewInt(1)}, Config{Debug: true, Tracer: tracer})
+	_, leftOverGas, err := evm.Call(AccountRef(caller), outer, common.Bytes{0x12}, 100000, big.NewInt(0))
+	require.Nil(err)
+
+	root := tracer.Result()
+	require.NotNil(root)
+	assert.Equal("CALL", root.Type)
+	assert.Equal(caller, root.From)
+	assert.Equal(outer, root.To)
+	assert.Equal(uint64(100000), uint64(root.Gas))
+	assert.Equal(uint64(100000)-leftOverGas, uint64(root.GasUsed))
+	assert.Equal([]byte{0x12}, []byte(root.Input))
+	assert.Empty(root.Error)
+	require.Equal(1, len(root.Storage))
+	assert.Equal("SLOAD", root.Storage[0].Op)
+	assert.Equal(common.BigToHash(big.NewInt(5)), root.Storage[0].Slot)
+
+	require.Equal(1, len(root.Calls))
+	call := root.Calls[0]
+	assert.Equal("CALL", call.Type)
+	assert.Equal(outer, call.From)
+	assert.Equal(inner, call.To)
+	assert.Equal("execution reverted", call.Error)
+	assert.True(call.GasUsed > 0)
+	require.Equal(1, len(call.Storage))
+	assert.Equal("SSTORE", call.Storage[0].Op)
+	assert.Equal(common.BigToHash(big.NewInt(1)), call.Storage[0].Value)
+	// The write was reverted
+	assert.Equal(common.Hash{}, storeView.GetState(inner, common.Hash{}))
+
+	// Only the top-level call is traced
+	tracer = NewCallTracer(&CallTracerConfig{OnlyTopCall: true})
+	evm = NewEVM(context, storeView, &params.ChainConfig{ChainID: big.NewInt(1)}, Config{Debug: true, Tracer: tracer})
+	_, _, err = evm.Call(AccountRef(caller), outer, nil, 100000, big.NewInt(0))
+	require.Nil(err)
+	assert.Equal(0, len(tracer.Result().Calls))
+	assert.Equal(0, len(tracer.Result().Storage))
+}
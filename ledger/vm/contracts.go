@@ -25,6 +25,7 @@ import (
 	"github.com/pandotoken/pando/common/math"
 	"github.com/pandotoken/pando/crypto"
 	"github.com/pandotoken/pando/crypto/bn256"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/ledger/vm/params"
 	"golang.org/x/crypto/ripemd160"
 )
@@ -62,6 +63,24 @@ var PrecompiledContractsByzantium = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{202}): &pandoStake{},
 }
 
+// PrecompiledContractsRandomnessBeacon contains the pre-compiled contracts added
+// by the RandomnessBeacon fork.
+var PrecompiledContractsRandomnessBeacon = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{203}): &pandoRandomness{},
+}
+
+// precompile returns the pre-compiled contract at the given address, if any is
+// enabled at the height of the block being executed.
+func (evm *EVM) precompile(addr common.Address) PrecompiledContract {
+	if p := PrecompiledContractsByzantium[addr]; p != nil {
+		return p
+	}
+	if evm.BlockNumber != nil && features.IsEnabled(features.RandomnessBeacon, evm.BlockNumber.Uint64()) {
+		return PrecompiledContractsRandomnessBeacon[addr]
+	}
+	return nil
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(evm *EVM, p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
@@ -395,3 +414,19 @@ func (c *pandoStake) Run(evm *EVM, input []byte) ([]byte, error) {
 	pandoStakeBytes32 := common.LeftPadBytes(pandoStakeBytes[:], 32) // easier to convert bytes32 into uint256 in smart contracts
 	return pandoStakeBytes32, nil
 }
+
+// pandoRandomness retrieves the latest randomness beacon, followed by the height of the block at
+// which it advanced. Contracts can commit to a height, and use the first beacon advancing after it.
+type pandoRandomness struct {
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *pandoRandomness) RequiredGas(input []byte) uint64 {
+	return params.PandoRandomnessGas
+}
+
+func (c *pandoRandomness) Run(evm *EVM, input []byte) ([]byte, error) {
+	randomness, height := evm.StateDB.GetRandomness()
+	heightBytes32 := common.LeftPadBytes(new(big.Int).SetUint64(height).Bytes(), 32)
+	return append(randomness.Bytes(), heightBytes32...), nil
+}
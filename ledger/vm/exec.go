@@ -6,7 +6,7 @@ import (
 
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/core"
-	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 	"github.com/pandotoken/pando/ledger/vm/params"
@@ -14,6 +14,13 @@ import (
 
 // Execute executes the given smart contract
 func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *state.StoreView) (evmRet common.Bytes,
+	contractAddr common.Address, gasUsed uint64, evmErr error) {
+	return ExecuteWithConfig(parentBlock, tx, storeView, Config{})
+}
+
+// ExecuteWithConfig executes the given smart contract with the given EVM configuration, e.g. to trace
+// the execution
+func ExecuteWithConfig(parentBlock *core.Block, tx *types.SmartContractTx, storeView *state.StoreView, config Config) (evmRet common.Bytes,
 	contractAddr common.Address, gasUsed uint64, evmErr error) {
 	context := Context{
 		CanTransfer: CanTransfer,
@@ -25,11 +32,13 @@ func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *stat
 		Time:        parentBlock.Timestamp,
 		Difficulty:  new(big.Int).SetInt64(0),
 	}
-	chainIDBigInt := mapChainID(parentBlock.ChainID)
+	if features.IsEnabled(features.BlockHash, parentBlock.Height+1) {
+		context.GetHash = getHashFn(parentBlock, storeView)
+	}
+	chainIDBigInt := types.MapChainID(parentBlock.ChainID)
 	chainConfig := &params.ChainConfig{
 		ChainID: chainIDBigInt,
 	}
-	config := Config{}
 	evm := NewEVM(context, storeView, chainConfig, config)
 
 	value := tx.From.Coins.PTXWei
@@ -72,6 +81,19 @@ func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *stat
 	return evmRet, contractAddr, gasUsed, evmErr
 }
 
+// getHashFn returns the GetHashFunc of the block following the parent block. The hash of the parent
+// block is recorded in the state only once its child is processed, the earlier hashes are looked up
+// in the recent block hashes of the state, which follow the fork the parent block is on.
+func getHashFn(parentBlock *core.Block, storeView *state.StoreView) GetHashFunc {
+	parentHash := parentBlock.Hash()
+	return func(height uint64) common.Hash {
+		if height == parentBlock.Height {
+			return parentHash
+		}
+		return storeView.GetBlockHash(height)
+	}
+}
+
 // calculateIntrinsicGas computes the 'intrinsic gas' for a message with the given data.
 func calculateIntrinsicGas(data []byte, createContract bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
@@ -104,18 +126,3 @@ func calculateIntrinsicGas(data []byte, createContract bool) (uint64, error) {
 	}
 	return gas, nil
 }
-
-// To be compatible with Ethereum, mapChainID() returns 1 for "mainnet", 3 for "testnet_sapphire", and 4 for "testnet_amber"
-// Reference: https://github.com/ethereum/go-ethereum/blob/43cd31ea9f57e26f8f67aa8bd03bbb0a50814465/params/config.go#L55
-func mapChainID(chainIDStr string) *big.Int {
-	if chainIDStr == "mainnet" { // correspond to the Ethereum mainnet
-		return big.NewInt(1)
-	} else if chainIDStr == "testnet" {
-		return big.NewInt(7)
-	} else if chainIDStr == "pandonet" {
-		return big.NewInt(8)
-	}
-
-	chainIDBigInt := new(big.Int).Abs(crypto.Keccak256Hash(common.Bytes(chainIDStr)).Big()) // all other chainIDs
-	return chainIDBigInt
-}
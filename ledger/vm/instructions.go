@@ -530,27 +530,27 @@ func opGasprice(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	return nil, nil
 }
 
+// opBlockhash pushes the hash of one of the 256 most recent blocks, or zero for the other blocks
 func opBlockhash(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
-	return nil, fmt.Errorf("opBlockhash not supported")
+	if interpreter.evm.GetHash == nil {
+		return nil, fmt.Errorf("opBlockhash not supported")
+	}
+	num := stack.pop()
+
+	n := interpreter.intPool.get().Sub(interpreter.evm.BlockNumber, common.Big257)
+	if num.Cmp(n) > 0 && num.Cmp(interpreter.evm.BlockNumber) < 0 {
+		stack.push(interpreter.evm.GetHash(num.Uint64()).Big())
+	} else {
+		stack.push(interpreter.intPool.getZero())
+	}
+	interpreter.intPool.put(num, n)
+	return nil, nil
 }
 
 func opCoinbase(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	return nil, fmt.Errorf("opCoinbase not supported")
 }
 
-// func opBlockhash(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
-// 	num := stack.pop()
-
-// 	n := interpreter.intPool.get().Sub(interpreter.evm.BlockNumber, common.Big257)
-// 	if num.Cmp(n) > 0 && num.Cmp(interpreter.evm.BlockNumber) < 0 {
-// 		stack.push(interpreter.evm.GetHash(num.Uint64()).Big())
-// 	} else {
-// 		stack.push(interpreter.intPool.getZero())
-// 	}
-// 	interpreter.intPool.put(num, n)
-// 	return nil, nil
-// }
-
 // func opCoinbase(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 // 	stack.push(interpreter.evm.Coinbase.Big())
 // 	return nil, nil
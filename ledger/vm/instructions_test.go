@@ -208,6 +208,51 @@ func TestSLT(t *testing.T) {
 	testTwoOperandOp(t, tests, opSlt)
 }
 
+func TestBlockhash(t *testing.T) {
+	var (
+		env            = NewEVM(Context{BlockNumber: big.NewInt(300)}, nil, params.TestChainConfig, Config{})
+		stack          = newstack()
+		pc             = uint64(0)
+		evmInterpreter = NewEVMInterpreter(env, env.vmConfig)
+	)
+
+	env.interpreter = evmInterpreter
+	evmInterpreter.intPool = poolOfIntPools.get()
+	defer poolOfIntPools.put(evmInterpreter.intPool)
+
+	// BLOCKHASH is not supported without the block hashes
+	stack.push(big.NewInt(299))
+	if _, err := opBlockhash(&pc, evmInterpreter, nil, nil, stack); err == nil {
+		t.Errorf("Expected an error without GetHash")
+	}
+
+	blockHash := func(n uint64) common.Hash {
+		return crypto.Keccak256Hash(new(big.Int).SetUint64(n).Bytes())
+	}
+	env.GetHash = blockHash
+	tests := []struct {
+		num      uint64
+		expected common.Hash
+	}{
+		{299, blockHash(299)},
+		{44, blockHash(44)},
+		{43, common.Hash{}},
+		{0, common.Hash{}},
+		{300, common.Hash{}},
+		{301, common.Hash{}},
+	}
+	for i, test := range tests {
+		stack.push(new(big.Int).SetUint64(test.num))
+		if _, err := opBlockhash(&pc, evmInterpreter, nil, nil, stack); err != nil {
+			t.Fatalf("Testcase %d, unexpected error: %v", i, err)
+		}
+		actual := stack.pop()
+		if actual.Cmp(test.expected.Big()) != 0 {
+			t.Errorf("Testcase %d, expected %x, got %x", i, test.expected, actual)
+		}
+	}
+}
+
 func opBenchmark(bench *testing.B, op func(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error), args ...string) {
 	var (
 		env            = NewEVM(Context{}, nil, params.TestChainConfig, Config{})
@@ -33,6 +33,7 @@ type StateDB interface {
 
 	GetPandoBalance(common.Address) *big.Int // GetPandoBalance returns the PandoWei balance of the given address
 	GetPandoStake(common.Address) *big.Int   // GetPandoStake returns the total amount of PandoWei the address staked to validators and/or guardians
+	GetRandomness() (common.Hash, uint64)    // GetRandomness returns the latest randomness beacon and the height at which it advanced
 
 	GetNonce(common.Address) uint64
 	SetNonce(common.Address, uint64)
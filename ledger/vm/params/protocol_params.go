@@ -86,8 +86,9 @@ const (
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
 
-	PandoBalanceGas uint64 = 4   // Retrieve the Pando balance for an address
-	PandoStakeGas   uint64 = 200 // Retrieve the total amount of staked Pando for an address
+	PandoBalanceGas    uint64 = 4   // Retrieve the Pando balance for an address
+	PandoStakeGas      uint64 = 200 // Retrieve the total amount of staked Pando for an address
+	PandoRandomnessGas uint64 = 200 // Retrieve the latest randomness beacon
 )
 
 var (
@@ -0,0 +1,360 @@
+package lightclient
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/snapshot"
+	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/store/database"
+	"github.com/pandotoken/pando/store/kvstore"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "lightclient"})
+
+// Provider serves the headers and the proofs a light client syncs from, e.g. a full node.
+type Provider interface {
+	// GetGenesis returns the genesis block header and the VCP proof of its state.
+	GetGenesis() (*core.BlockHeader, *core.VCPProof, error)
+
+	// GetValidatorSetChanges returns the block trios proving the validator set changes after the given
+	// height, in ascending order of heights. It may only return the earliest of them.
+	GetValidatorSetChanges(start uint64) ([]*core.SnapshotBlockTrio, error)
+
+	// GetHeader returns the block trio proving the finalization of the block at the given height, or of
+	// the latest block the provider can prove if the height is 0.
+	GetHeader(height uint64) (*core.SnapshotBlockTrio, error)
+
+	// GetAccountProof returns the Merkle proof of the account in the state of the block at the given height.
+	GetAccountProof(addr common.Address, height uint64) ([][]byte, error)
+
+	// GetTxProof returns the Merkle proof of the inclusion of the transaction in its block.
+	GetTxProof(hash common.Hash) (*TxProof, error)
+}
+
+// TxProof is the Merkle proof of a transaction in the transaction trie of its block.
+type TxProof struct {
+	BlockHash   common.Hash
+	BlockHeight uint64
+	Index       uint64
+	Proof       [][]byte
+}
+
+// validatorSetChange records the proof of a validator set, which is the validator candidate pool in
+// the state of a finalized block.
+type validatorSetChange struct {
+	Height    uint64
+	StateHash common.Hash
+	Proof     core.VCPProof
+}
+
+func genesisKey() common.Bytes {
+	return common.Bytes("lc/genesis")
+}
+
+func validatorSetChangesKey() common.Bytes {
+	return common.Bytes("lc/vs")
+}
+
+func latestHeaderKey() common.Bytes {
+	return common.Bytes("lc/latest")
+}
+
+func headerKey(height uint64) common.Bytes {
+	return common.Bytes("lc/h/" + strconv.FormatUint(height, 10))
+}
+
+// LightClient syncs only the block headers and the validator set changes from a provider. It verifies
+// the votes finalizing the headers against the validator sets, which are proven in turn by the votes of
+// the previous validator sets back to the genesis block, and verifies the Merkle proofs of the accounts
+// and of the transactions against the verified headers.
+type LightClient struct {
+	mu *sync.Mutex
+
+	store    store.Store
+	provider Provider
+
+	genesis       *core.BlockHeader
+	changes       []validatorSetChange
+	validatorSets []*core.ValidatorSet // the validator set of the genesis block, followed by the one of each change
+	latest        *core.BlockHeader
+}
+
+// NewLightClient creates a light client, resuming from the headers and the validator sets synced into
+// the database.
+func NewLightClient(db database.Database, provider Provider) (*LightClient, error) {
+	lc := &LightClient{
+		mu:       &sync.Mutex{},
+		store:    kvstore.NewKVStore(db),
+		provider: provider,
+	}
+
+	genesis := &core.BlockHeader{}
+	if err := lc.store.Get(genesisKey(), genesis); err != nil {
+		if err == store.ErrKeyNotFound {
+			return lc, nil
+		}
+		return nil, err
+	}
+	changes := []validatorSetChange{}
+	if err := lc.store.Get(validatorSetChangesKey(), &changes); err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 || changes[0].Height != core.GenesisBlockHeight {
+		return nil, fmt.Errorf("The genesis validator set is missing")
+	}
+	for _, change := range changes {
+		valSet, err := snapshot.GetValidatorSetFromVCPProof(change.StateHash, &change.Proof)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load the validator set at height %v: %v", change.Height, err)
+		}
+		lc.validatorSets = append(lc.validatorSets, valSet)
+	}
+	lc.genesis = genesis
+	lc.changes = changes
+	lc.latest = genesis
+
+	latest := &core.BlockHeader{}
+	if err := lc.store.Get(latestHeaderKey(), latest); err == nil {
+		lc.latest = latest
+	}
+	logger.Infof("Resumed light client at height %v, validator set changes: %v", lc.latest.Height, len(changes)-1)
+	return lc, nil
+}
+
+// Sync syncs the validator set changes and the latest finalized header from the provider.
+func (lc *LightClient) Sync() error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.genesis == nil {
+		if err := lc.syncGenesis(); err != nil {
+			return err
+		}
+	}
+	if err := lc.syncValidatorSets(); err != nil {
+		return err
+	}
+
+	trio, err := lc.provider.GetHeader(0)
+	if err != nil {
+		return err
+	}
+	header, err := lc.verifyTrio(trio)
+	if err != nil {
+		return fmt.Errorf("Invalid header proof: %v", err)
+	}
+	if err := lc.store.Put(headerKey(header.Height), header); err != nil {
+		return err
+	}
+	if header.Height > lc.latest.Height {
+		if err := lc.store.Put(latestHeaderKey(), header); err != nil {
+			return err
+		}
+		lc.latest = header
+		logger.Debugf("Synced finalized header, height: %v, hash: %v", header.Height, header.Hash().Hex())
+	}
+	return nil
+}
+
+// syncGenesis verifies the genesis block header against the expected genesis block hash, and its
+// validator set against its state.
+func (lc *LightClient) syncGenesis() error {
+	genesis, proof, err := lc.provider.GetGenesis()
+	if err != nil {
+		return err
+	}
+	if genesis.Height != core.GenesisBlockHeight {
+		return fmt.Errorf("Invalid genesis block height: %v", genesis.Height)
+	}
+	if err := snapshot.VerifyGenesisBlockHash(genesis); err != nil {
+		return err
+	}
+	valSet, err := snapshot.GetValidatorSetFromVCPProof(genesis.StateHash, proof)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve the genesis validator set: %v", err)
+	}
+
+	changes := []validatorSetChange{{Height: genesis.Height, StateHash: genesis.StateHash, Proof: *proof}}
+	if err := lc.store.Put(validatorSetChangesKey(), changes); err != nil {
+		return err
+	}
+	if err := lc.store.Put(genesisKey(), genesis); err != nil {
+		return err
+	}
+	lc.genesis = genesis
+	lc.changes = changes
+	lc.validatorSets = []*core.ValidatorSet{valSet}
+	lc.latest = genesis
+	logger.Infof("Verified genesis block %v, validator set: %v", genesis.Hash().Hex(), valSet)
+	return nil
+}
+
+// syncValidatorSets verifies the validator set changes since the last one synced, each proven by the
+// votes of the previous validator set.
+func (lc *LightClient) syncValidatorSets() error {
+	for {
+		last := lc.changes[len(lc.changes)-1]
+		trios, err := lc.provider.GetValidatorSetChanges(last.Height)
+		if err != nil {
+			return err
+		}
+		if len(trios) == 0 {
+			return nil
+		}
+		for _, trio := range trios {
+			if trio.First.Header == nil || trio.First.Header.Height <= lc.changes[len(lc.changes)-1].Height {
+				return fmt.Errorf("Validator set changes are not in ascending order of heights")
+			}
+			first, err := lc.verifyTrio(trio)
+			if err != nil {
+				return fmt.Errorf("Invalid validator set change at height %v: %v", trio.First.Header.Height, err)
+			}
+			valSet, err := snapshot.GetValidatorSetFromVCPProof(first.StateHash, &trio.First.Proof)
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve the validator set at height %v: %v", first.Height, err)
+			}
+			lc.changes = append(lc.changes, validatorSetChange{Height: first.Height, StateHash: first.StateHash, Proof: trio.First.Proof})
+			lc.validatorSets = append(lc.validatorSets, valSet)
+			logger.Infof("Verified validator set change at height %v, validator set: %v", first.Height, valSet)
+		}
+		if err := lc.store.Put(validatorSetChangesKey(), lc.changes); err != nil {
+			return err
+		}
+	}
+}
+
+// verifyTrio checks that the second block of the trio is voted by the validators, and returns the first
+// block of the trio, finalized by the votes.
+func (lc *LightClient) verifyTrio(trio *core.SnapshotBlockTrio) (*core.BlockHeader, error) {
+	first := trio.First.Header
+	second := trio.Second.Header
+	third := trio.Third.Header
+	if first == nil || second == nil || third == nil {
+		return nil, fmt.Errorf("block trio is incomplete")
+	}
+	if first.ChainID != lc.genesis.ChainID {
+		return nil, fmt.Errorf("block trio is for chain %v, expected %v", first.ChainID, lc.genesis.ChainID)
+	}
+	if second.Parent != first.Hash() || third.Parent != second.Hash() {
+		return nil, fmt.Errorf("block trio has invalid Parent link")
+	}
+	if second.HCC.BlockHash != first.Hash() || third.HCC.BlockHash != second.Hash() {
+		return nil, fmt.Errorf("block trio has invalid HCC link")
+	}
+
+	// third.HCC contains the votes for the second block in the trio
+	if err := snapshot.ValidateCommitCertificate(lc.validatorSetFor(second.Height), second, third.HCC); err != nil {
+		return nil, fmt.Errorf("Failed to validate the votes: %v", err)
+	}
+	return first, nil
+}
+
+// validatorSetFor returns the validator set voting on the block at the given height. The validators of
+// a block are selected from the state of its grandparent, hence a validator set change at some height
+// takes effect two blocks later.
+func (lc *LightClient) validatorSetFor(height uint64) *core.ValidatorSet {
+	for i := len(lc.changes) - 1; i > 0; i-- {
+		if lc.changes[i].Height+2 <= height {
+			return lc.validatorSets[i]
+		}
+	}
+	return lc.validatorSets[0]
+}
+
+// LatestHeader returns the latest verified finalized header, nil if the light client has not synced yet.
+func (lc *LightClient) LatestHeader() *core.BlockHeader {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	return lc.latest
+}
+
+// GetHeader returns the verified finalized header at the given height, or the latest one if the height is 0.
+func (lc *LightClient) GetHeader(height uint64) (*core.BlockHeader, error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	return lc.getHeader(height)
+}
+
+func (lc *LightClient) getHeader(height uint64) (*core.BlockHeader, error) {
+	if lc.genesis == nil {
+		return nil, fmt.Errorf("The light client has not synced yet")
+	}
+	if height == 0 {
+		return lc.latest, nil
+	}
+	header := &core.BlockHeader{}
+	if err := lc.store.Get(headerKey(height), header); err == nil {
+		return header, nil
+	}
+
+	trio, err := lc.provider.GetHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	header, err = lc.verifyTrio(trio)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid header proof: %v", err)
+	}
+	if header.Height != height {
+		return nil, fmt.Errorf("Header proof is for height %v, expected %v", header.Height, height)
+	}
+	if err := lc.store.Put(headerKey(height), header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// GetAccount returns the account at the given height, or at the latest verified height if the height
+// is 0, verified against the state root of the block header. It returns nil if the account does not exist.
+func (lc *LightClient) GetAccount(addr common.Address, height uint64) (*types.Account, error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	header, err := lc.getHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := lc.provider.GetAccountProof(addr, header.Height)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyAccountProof(header.StateHash, addr, proof)
+}
+
+// GetTransaction returns the finalized transaction along with the header of its block, verified against
+// the TxHash of the block header.
+func (lc *LightClient) GetTransaction(hash common.Hash) (common.Bytes, *core.BlockHeader, error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	txProof, err := lc.provider.GetTxProof(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	header, err := lc.getHeader(txProof.BlockHeight)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Hash() != txProof.BlockHash {
+		return nil, nil, fmt.Errorf("Transaction is included in block %v, but the finalized block is %v",
+			txProof.BlockHash.Hex(), header.Hash().Hex())
+	}
+	raw, err := core.VerifyTxProof(header.TxHash, int(txProof.Index), newProofReader(txProof.Proof))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid transaction proof: %v", err)
+	}
+	if crypto.Keccak256Hash(raw) != hash {
+		return nil, nil, fmt.Errorf("The proven transaction does not match the hash %v", hash.Hex())
+	}
+	return raw, header, nil
+}
@@ -0,0 +1,291 @@
+package lightclient
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store/database/backend"
+)
+
+const testChainID = "lightclient_test"
+
+// testChain is a chain of blocks with their states, serving as the provider of the light client
+type testChain struct {
+	headers    []*core.BlockHeader
+	txs        [][]common.Bytes
+	states     []*state.StoreView
+	validators [][]*crypto.PrivateKey // the validators of the state of each block
+	changes    []uint64               // the heights of the validator set changes
+}
+
+func newValidators(n int) []*crypto.PrivateKey {
+	keys := []*crypto.PrivateKey{}
+	for i := 0; i < n; i++ {
+		key, _, _ := crypto.GenerateKeyPair()
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func newVCP(validators []*crypto.PrivateKey) *core.ValidatorCandidatePool {
+	vcp := &core.ValidatorCandidatePool{}
+	for _, key := range validators {
+		addr := key.PublicKey().Address()
+		vcp.DepositStake(addr, addr, core.MinValidatorStakeDeposit)
+	}
+	return vcp
+}
+
+// newTestChain creates a chain of the given length, whose validator set changes at the given heights
+func newTestChain(length int, changes ...uint64) *testChain {
+	db := backend.NewMemDatabase()
+	chain := &testChain{changes: changes}
+	validators := newValidators(4)
+	sv := state.NewStoreView(0, common.Hash{}, db)
+	sv.UpdateValidatorCandidatePool(newVCP(validators))
+
+	var parent *core.BlockHeader
+	for height := uint64(0); height < uint64(length); height++ {
+		for _, h := range changes {
+			if h == height {
+				validators = newValidators(4)
+				sv.UpdateValidatorCandidatePool(newVCP(validators))
+			}
+		}
+		acc := &types.Account{
+			Address: common.HexToAddress("0x100"),
+			Balance: types.NewCoins(int64(height), 0),
+		}
+		sv.SetAccount(acc.Address, acc)
+		stateHash := sv.Save()
+		sv = state.NewStoreView(height, stateHash, db)
+
+		txs := []common.Bytes{common.Bytes(fmt.Sprintf("tx%v-0", height)), common.Bytes(fmt.Sprintf("tx%v-1", height))}
+		header := &core.BlockHeader{
+			ChainID:   testChainID,
+			Epoch:     height,
+			Height:    height,
+			TxHash:    core.CalculateRootHash(txs),
+			StateHash: stateHash,
+			Timestamp: big.NewInt(int64(height)),
+		}
+		if parent != nil {
+			header.Parent = parent.Hash()
+			header.HCC = core.CommitCertificate{BlockHash: parent.Hash(), Votes: chain.vote(parent)}
+		}
+		chain.headers = append(chain.headers, header)
+		chain.txs = append(chain.txs, txs)
+		chain.states = append(chain.states, state.NewStoreView(height, stateHash, db))
+		chain.validators = append(chain.validators, validators)
+		parent = header
+	}
+	return chain
+}
+
+// vote signs the block with the validators selected from the state of its grandparent
+func (c *testChain) vote(header *core.BlockHeader) *core.VoteSet {
+	height := header.Height
+	if height >= 2 {
+		height -= 2
+	} else {
+		height = 0
+	}
+	votes := core.NewVoteSet()
+	for _, key := range c.validators[height] {
+		vote := core.Vote{Block: header.Hash(), Height: header.Height, Epoch: header.Epoch, ID: key.PublicKey().Address()}
+		vote.Sign(key)
+		votes.AddVote(vote)
+	}
+	return votes
+}
+
+func (c *testChain) trio(height uint64) *core.SnapshotBlockTrio {
+	vp := &core.VCPProof{}
+	c.states[height].ProveVCP(state.ValidatorCandidatePoolKey(), vp)
+	return &core.SnapshotBlockTrio{
+		First:  core.SnapshotFirstBlock{Header: c.headers[height], Proof: *vp},
+		Second: core.SnapshotSecondBlock{Header: c.headers[height+1]},
+		Third:  core.SnapshotThirdBlock{Header: c.headers[height+2]},
+	}
+}
+
+func (c *testChain) GetGenesis() (*core.BlockHeader, *core.VCPProof, error) {
+	vp := &core.VCPProof{}
+	err := c.states[0].ProveVCP(state.ValidatorCandidatePoolKey(), vp)
+	return c.headers[0], vp, err
+}
+
+func (c *testChain) GetValidatorSetChanges(start uint64) ([]*core.SnapshotBlockTrio, error) {
+	trios := []*core.SnapshotBlockTrio{}
+	for _, h := range c.changes {
+		if h > start {
+			trios = append(trios, c.trio(h))
+		}
+	}
+	return trios, nil
+}
+
+func (c *testChain) GetHeader(height uint64) (*core.SnapshotBlockTrio, error) {
+	if height == 0 {
+		height = uint64(len(c.headers) - 3)
+	}
+	return c.trio(height), nil
+}
+
+func (c *testChain) GetAccountProof(addr common.Address, height uint64) ([][]byte, error) {
+	return c.states[height].ProveAccount(addr)
+}
+
+func (c *testChain) GetTxProof(hash common.Hash) (*TxProof, error) {
+	for height, txs := range c.txs {
+		for idx, tx := range txs {
+			if crypto.Keccak256Hash(tx) == hash {
+				proof := proofCollector{}
+				err := core.ProveTx(txs, idx, &proof)
+				return &TxProof{
+					BlockHash:   c.headers[height].Hash(),
+					BlockHeight: uint64(height),
+					Index:       uint64(idx),
+					Proof:       proof,
+				}, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+type proofCollector [][]byte
+
+func (pc *proofCollector) Put(key []byte, value []byte) error {
+	*pc = append(*pc, common.CopyBytes(value))
+	return nil
+}
+
+func TestLightClientSync(t *testing.T) {
+	require := require.New(t)
+
+	chain := newTestChain(20, 5, 11)
+	viper.Set(common.CfgGenesisHash, chain.headers[0].Hash().Hex())
+	defer viper.Set(common.CfgGenesisHash, "")
+
+	db := backend.NewMemDatabase()
+	lc, err := NewLightClient(db, chain)
+	require.Nil(err)
+	require.Nil(lc.LatestHeader())
+
+	require.Nil(lc.Sync())
+	require.Equal(chain.headers[17].Hash(), lc.LatestHeader().Hash())
+	require.Equal(3, len(lc.changes))
+
+	header, err := lc.GetHeader(8)
+	require.Nil(err)
+	require.Equal(chain.headers[8].Hash(), header.Hash())
+
+	// Accounts and transactions are verified against the headers
+	account, err := lc.GetAccount(common.HexToAddress("0x100"), 0)
+	require.Nil(err)
+	require.Equal(int64(17), account.Balance.PandoWei.Int64())
+	account, err = lc.GetAccount(common.HexToAddress("0x200"), 0)
+	require.Nil(err)
+	require.Nil(account)
+
+	raw, header, err := lc.GetTransaction(crypto.Keccak256Hash(chain.txs[6][1]))
+	require.Nil(err)
+	require.Equal(chain.txs[6][1], raw)
+	require.Equal(uint64(6), header.Height)
+
+	// The light client resumes from the database
+	lc, err = NewLightClient(db, chain)
+	require.Nil(err)
+	require.Equal(uint64(17), lc.LatestHeader().Height)
+	require.Equal(3, len(lc.validatorSets))
+	require.True(lc.validatorSetFor(19).Equals(lc.validatorSets[2]))
+}
+
+func TestLightClientRejectsInvalidProofs(t *testing.T) {
+	require := require.New(t)
+
+	chain := newTestChain(12, 5)
+	viper.Set(common.CfgGenesisHash, chain.headers[0].Hash().Hex())
+	defer viper.Set(common.CfgGenesisHash, "")
+
+	// A validator set change not voted by the previous validators
+	forged := newTestChain(12, 3, 5)
+	forged.headers[0] = chain.headers[0]
+	forged.states[0] = chain.states[0]
+	lc, err := NewLightClient(backend.NewMemDatabase(), forged)
+	require.Nil(err)
+	require.NotNil(lc.Sync())
+
+	lc, err = NewLightClient(backend.NewMemDatabase(), chain)
+	require.Nil(err)
+	require.Nil(lc.Sync())
+
+	// A header voted by the validators of another chain
+	trio := forged.trio(8)
+	_, err = lc.verifyTrio(trio)
+	require.NotNil(err)
+
+	// Broken links
+	trio = chain.trio(8)
+	trio.Second.Header = chain.headers[4]
+	_, err = lc.verifyTrio(trio)
+	require.NotNil(err)
+
+	// An account proof against another state
+	proof, err := chain.GetAccountProof(common.HexToAddress("0x100"), 3)
+	require.Nil(err)
+	_, err = VerifyAccountProof(chain.headers[9].StateHash, common.HexToAddress("0x100"), proof)
+	require.NotNil(err)
+
+	// A genesis block other than the expected one
+	viper.Set(common.CfgGenesisHash, forged.headers[1].Hash().Hex())
+	lc, err = NewLightClient(backend.NewMemDatabase(), chain)
+	require.Nil(err)
+	require.NotNil(lc.Sync())
+}
+
+func TestVerifyBridgeMessageProof(t *testing.T) {
+	require := require.New(t)
+
+	sv := state.NewStoreView(0, common.Hash{}, backend.NewMemDatabase())
+	msg := &types.BridgeMessage{
+		Kind:      types.BridgeMessageLock,
+		Sender:    common.HexToAddress("0x100"),
+		DestChain: "ethereum",
+		Recipient: common.HexToAddress("0x200"),
+		Coins:     types.NewCoins(0, 100),
+		Amount:    big.NewInt(0),
+	}
+	sv.AddBridgeMessage(msg)
+	stateRoot := sv.Save()
+
+	proof, err := sv.ProveBridgeMessage(1)
+	require.Nil(err)
+	proven, err := VerifyBridgeMessageProof(stateRoot, 1, proof)
+	require.Nil(err)
+	require.NotNil(proven)
+	require.Equal(msg.Hash(), proven.Hash())
+
+	// The proof of an absent message
+	proof, err = sv.ProveBridgeMessage(2)
+	require.Nil(err)
+	proven, err = VerifyBridgeMessageProof(stateRoot, 2, proof)
+	require.Nil(err)
+	require.Nil(proven)
+
+	// A proof against another state
+	proof, err = sv.ProveBridgeMessage(1)
+	require.Nil(err)
+	_, err = VerifyBridgeMessageProof(common.HexToHash("0x1"), 1, proof)
+	require.NotNil(err)
+}
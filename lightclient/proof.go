@@ -0,0 +1,88 @@
+package lightclient
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/store/trie"
+)
+
+// VerifyAccountProof checks the Merkle proof of the account against the state root, and returns the
+// proven account, nil if the proof proves its absence.
+func VerifyAccountProof(stateRoot common.Hash, addr common.Address, proof [][]byte) (*types.Account, error) {
+	data, _, err := trie.VerifyProof(stateRoot, state.AccountKey(addr), newProofReader(proof))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	account := &types.Account{}
+	if err := types.FromBytes(data, account); err != nil {
+		return nil, fmt.Errorf("Failed to decode the account: %v", err)
+	}
+	return account, nil
+}
+
+// VerifyStorageProof checks the Merkle proof of the storage slot against the storage root of the
+// contract account, and returns the proven value, empty if the proof proves its absence.
+func VerifyStorageProof(storageRoot common.Hash, slot common.Hash, proof [][]byte) (common.Hash, error) {
+	enc, _, err := trie.VerifyProof(storageRoot, slot[:], newProofReader(proof))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(enc) == 0 {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("Failed to decode the storage value: %v", err)
+	}
+	return common.BytesToHash(content), nil
+}
+
+// VerifyBridgeMessageProof checks the Merkle proof of the outbound bridge message against the state root,
+// and returns the proven message, nil if the proof proves its absence. This is the check the bridge
+// contracts of the foreign chains perform before minting or releasing the assets of the message.
+func VerifyBridgeMessageProof(stateRoot common.Hash, nonce uint64, proof [][]byte) (*types.BridgeMessage, error) {
+	data, _, err := trie.VerifyProof(stateRoot, state.BridgeMessageKey(nonce), newProofReader(proof))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	msg := &types.BridgeMessage{}
+	if err := types.FromBytes(data, msg); err != nil {
+		return nil, fmt.Errorf("Failed to decode the bridge message: %v", err)
+	}
+	return msg, nil
+}
+
+// proofReader serves the trie nodes of a Merkle proof by their hashes
+type proofReader map[common.Hash][]byte
+
+func newProofReader(proof [][]byte) proofReader {
+	pr := proofReader{}
+	for _, node := range proof {
+		pr[crypto.Keccak256Hash(node)] = node
+	}
+	return pr
+}
+
+func (pr proofReader) Get(key []byte) ([]byte, error) {
+	node, ok := pr[common.BytesToHash(key)]
+	if !ok {
+		return nil, fmt.Errorf("proof node %x missing", key)
+	}
+	return node, nil
+}
+
+func (pr proofReader) Has(key []byte) (bool, error) {
+	_, ok := pr[common.BytesToHash(key)]
+	return ok, nil
+}
@@ -0,0 +1,126 @@
+package lightclient
+
+import (
+	"strconv"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/rpc"
+)
+
+var _ Provider = (*RPCProvider)(nil)
+
+// RPCProvider serves the headers and the proofs from the RPC endpoint of a full node.
+type RPCProvider struct {
+	client rpc.Client
+}
+
+// NewRPCProvider creates a provider querying the RPC endpoint at the url.
+func NewRPCProvider(url string) *RPCProvider {
+	return &RPCProvider{
+		client: rpc.NewClient(url),
+	}
+}
+
+// GetGenesis implements the Provider interface.
+func (p *RPCProvider) GetGenesis() (*core.BlockHeader, *core.VCPProof, error) {
+	result := &rpc.GetGenesisProofResult{}
+	if err := p.client.Call("pando.GetGenesisProof", []interface{}{rpc.GetGenesisProofArgs{}}, result); err != nil {
+		return nil, nil, err
+	}
+	header := &core.BlockHeader{}
+	if err := decodeRLP(result.Header, header); err != nil {
+		return nil, nil, err
+	}
+	proof := &core.VCPProof{}
+	if err := decodeRLP(result.Proof, proof); err != nil {
+		return nil, nil, err
+	}
+	return header, proof, nil
+}
+
+// GetValidatorSetChanges implements the Provider interface.
+func (p *RPCProvider) GetValidatorSetChanges(start uint64) ([]*core.SnapshotBlockTrio, error) {
+	args := rpc.GetValidatorSetProofsArgs{Start: common.JSONUint64(start)}
+	result := &rpc.GetValidatorSetProofsResult{}
+	if err := p.client.Call("pando.GetValidatorSetProofs", []interface{}{args}, result); err != nil {
+		return nil, err
+	}
+	trios := []*core.SnapshotBlockTrio{}
+	for _, encoded := range result.Trios {
+		trio := &core.SnapshotBlockTrio{}
+		if err := decodeRLP(encoded, trio); err != nil {
+			return nil, err
+		}
+		trios = append(trios, trio)
+	}
+	return trios, nil
+}
+
+// GetHeader implements the Provider interface.
+func (p *RPCProvider) GetHeader(height uint64) (*core.SnapshotBlockTrio, error) {
+	args := rpc.GetHeaderProofArgs{Height: common.JSONUint64(height)}
+	result := &rpc.GetHeaderProofResult{}
+	if err := p.client.Call("pando.GetHeaderProof", []interface{}{args}, result); err != nil {
+		return nil, err
+	}
+	trio := &core.SnapshotBlockTrio{}
+	if err := decodeRLP(result.Trio, trio); err != nil {
+		return nil, err
+	}
+	return trio, nil
+}
+
+// GetAccountProof implements the Provider interface.
+func (p *RPCProvider) GetAccountProof(addr common.Address, height uint64) ([][]byte, error) {
+	args := rpc.GetProofArgs{
+		Address: addr.Hex(),
+		Block:   rpc.BlockSpecifier(strconv.FormatUint(height, 10)),
+	}
+	result := &rpc.GetProofResult{}
+	if err := p.client.Call("pando.GetProof", []interface{}{args}, result); err != nil {
+		return nil, err
+	}
+	return decodeProof(result.AccountProof)
+}
+
+// GetTxProof implements the Provider interface.
+func (p *RPCProvider) GetTxProof(hash common.Hash) (*TxProof, error) {
+	args := rpc.GetTransactionProofArgs{Hash: hash.Hex()}
+	result := &rpc.GetTransactionProofResult{}
+	if err := p.client.Call("pando.GetTransactionProof", []interface{}{args}, result); err != nil {
+		return nil, err
+	}
+	proof, err := decodeProof(result.Proof)
+	if err != nil {
+		return nil, err
+	}
+	return &TxProof{
+		BlockHash:   result.BlockHash,
+		BlockHeight: uint64(result.BlockHeight),
+		Index:       uint64(result.Index),
+		Proof:       proof,
+	}, nil
+}
+
+func decodeRLP(encoded string, val interface{}) error {
+	raw, err := hexutil.Decode(encoded)
+	if err != nil {
+		return err
+	}
+	return rlp.DecodeBytes(raw, val)
+}
+
+func decodeProof(encoded []string) ([][]byte, error) {
+	proof := make([][]byte, len(encoded))
+	for i, node := range encoded {
+		raw, err := hexutil.Decode(node)
+		if err != nil {
+			return nil, err
+		}
+		proof[i] = raw
+	}
+	return proof, nil
+}
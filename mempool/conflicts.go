@@ -0,0 +1,214 @@
+package mempool
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+// TxConflictType is the type of a conflict among the pending transactions
+type TxConflictType string
+
+const (
+	// TxConflictSequence: transactions signed by the same account with the same sequence,
+	// at most one of them can be included
+	TxConflictSequence TxConflictType = "sequence"
+
+	// TxConflictReservedFund: service payments settling the same payment sequence of a
+	// reserved fund, or service payments racing against the release of the reserved fund
+	TxConflictReservedFund TxConflictType = "reserved_fund"
+)
+
+// TxConflict is a group of pending transactions which cannot all be included in the chain
+type TxConflict struct {
+	Type     TxConflictType    `json:"type"`
+	Address  common.Address    `json:"address"`  // the sender, or the source of the reserved fund
+	Sequence common.JSONUint64 `json:"sequence"` // the account sequence, or the reserve sequence
+	TxHashes []string          `json:"tx_hashes"`
+}
+
+// GetConflictingTransactions analyzes the pending transactions and reports those that conflict
+// with each other. The analysis only looks at the transactions themselves, it does not check
+// them against the ledger state.
+func (mp *Mempool) GetConflictingTransactions() []TxConflict {
+	mp.mutex.Lock()
+	rawTxs := []common.Bytes{}
+	txgElemList := mp.candidateTxs.ElementList()
+	for _, txgElem := range *txgElemList {
+		txg := txgElem.(*mempoolTransactionGroup)
+		txElemList := txg.txs.ElementList()
+		for _, txElem := range *txElemList {
+			rawTxs = append(rawTxs, txElem.(*mempoolTransaction).rawTransaction)
+		}
+	}
+	mp.mutex.Unlock()
+
+	return findConflicts(rawTxs)
+}
+
+type sequenceKey struct {
+	address  common.Address
+	sequence uint64
+}
+
+type reservedFundKey struct {
+	source          common.Address
+	reserveSequence uint64
+}
+
+type paymentKey struct {
+	reservedFundKey
+	target          common.Address
+	paymentSequence uint64
+}
+
+func findConflicts(rawTxs []common.Bytes) []TxConflict {
+	bySequence := make(map[sequenceKey][]string)
+	byPayment := make(map[paymentKey][]string)
+	paymentsByFund := make(map[reservedFundKey][]string)
+	releasesByFund := make(map[reservedFundKey][]string)
+
+	for _, rawTx := range rawTxs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			continue
+		}
+		txHash := "0x" + getTransactionHash(rawTx)
+
+		for _, input := range sequencedInputs(tx) {
+			key := sequenceKey{input.Address, input.Sequence}
+			bySequence[key] = append(bySequence[key], txHash)
+		}
+
+		switch tx := tx.(type) {
+		case *types.ServicePaymentTx:
+			fund := reservedFundKey{tx.Source.Address, tx.ReserveSequence}
+			payment := paymentKey{fund, tx.Target.Address, tx.PaymentSequence}
+			byPayment[payment] = append(byPayment[payment], txHash)
+			paymentsByFund[fund] = append(paymentsByFund[fund], txHash)
+		case *types.ReleaseFundTx:
+			fund := reservedFundKey{tx.Source.Address, tx.ReserveSequence}
+			releasesByFund[fund] = append(releasesByFund[fund], txHash)
+		}
+	}
+
+	conflicts := []TxConflict{}
+	for key, txHashes := range bySequence {
+		if len(txHashes) > 1 {
+			conflicts = append(conflicts, newTxConflict(TxConflictSequence, key.address, key.sequence, txHashes))
+		}
+	}
+	for key, txHashes := range byPayment {
+		if len(txHashes) > 1 {
+			conflicts = append(conflicts, newTxConflict(TxConflictReservedFund, key.source, key.reserveSequence, txHashes))
+		}
+	}
+	for key, releaseTxHashes := range releasesByFund {
+		paymentTxHashes := paymentsByFund[key]
+		if len(paymentTxHashes) == 0 {
+			continue
+		}
+		txHashes := append(append([]string{}, releaseTxHashes...), paymentTxHashes...)
+		conflicts = append(conflicts, newTxConflict(TxConflictReservedFund, key.source, key.reserveSequence, txHashes))
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Type != conflicts[j].Type {
+			return conflicts[i].Type < conflicts[j].Type
+		}
+		if cmp := bytes.Compare(conflicts[i].Address[:], conflicts[j].Address[:]); cmp != 0 {
+			return cmp < 0
+		}
+		if conflicts[i].Sequence != conflicts[j].Sequence {
+			return conflicts[i].Sequence < conflicts[j].Sequence
+		}
+		return conflicts[i].TxHashes[0] < conflicts[j].TxHashes[0]
+	})
+	return conflicts
+}
+
+func newTxConflict(conflictType TxConflictType, address common.Address, sequence uint64, txHashes []string) TxConflict {
+	sort.Strings(txHashes)
+	return TxConflict{
+		Type:     conflictType,
+		Address:  address,
+		Sequence: common.JSONUint64(sequence),
+		TxHashes: txHashes,
+	}
+}
+
+// sequencedInputs returns the inputs of the transaction whose account sequence is consumed
+// when the transaction is included
+func sequencedInputs(tx types.Tx) []types.TxInput {
+	switch tx := tx.(type) {
+	case *types.SendTx:
+		return tx.Inputs
+	case *types.RametronStakeTx:
+		return tx.Inputs
+	case *types.BatchSendTx:
+		return tx.Inputs
+	case *types.ReserveFundTx:
+		return []types.TxInput{tx.Source}
+	case *types.ReleaseFundTx:
+		return []types.TxInput{tx.Source}
+	case *types.ServicePaymentTx:
+		return []types.TxInput{tx.Target}
+	case *types.SplitRuleTx:
+		return []types.TxInput{tx.Initiator}
+	case *types.DepositStakeTx:
+		return []types.TxInput{tx.Source}
+	case *types.WithdrawStakeTx:
+		return []types.TxInput{tx.Source}
+	case *types.DepositStakeTxV2:
+		return []types.TxInput{tx.Source}
+	case *types.SessionKeyTx:
+		return []types.TxInput{tx.Account}
+	case *types.SlashAppealTx:
+		return []types.TxInput{tx.Appellant}
+	case *types.SlashAppealVoteTx:
+		return []types.TxInput{tx.Voter}
+	case *types.ClaimEscrowTx:
+		return []types.TxInput{tx.Escrow}
+	case *types.MeteredSettlementTx:
+		return []types.TxInput{tx.EdgeNode}
+	case *types.DelegateTx:
+		return []types.TxInput{tx.Delegator}
+	case *types.UndelegateTx:
+		return []types.TxInput{tx.Delegator}
+	case *types.SetCommissionTx:
+		return []types.TxInput{tx.Validator}
+	case *types.RametronAttestationTx:
+		return []types.TxInput{tx.Guardian}
+	case *types.KeyRotationTx:
+		return []types.TxInput{tx.Account}
+	case *types.HTLCCreateTx:
+		return []types.TxInput{tx.Sender}
+	case *types.HTLCClaimTx:
+		return []types.TxInput{tx.Claimer}
+	case *types.HTLCRefundTx:
+		return []types.TxInput{tx.Refunder}
+	case *types.ProposalTx:
+		return []types.TxInput{tx.Proposer}
+	case *types.VoteTx:
+		return []types.TxInput{tx.Voter}
+	case *types.BridgeLockTx:
+		return []types.TxInput{tx.Sender}
+	case *types.BridgeBurnTx:
+		return []types.TxInput{tx.Sender}
+	case *types.BridgeClaimTx:
+		return []types.TxInput{tx.Relayer}
+	case *types.SubchainRegisterTx:
+		return []types.TxInput{tx.Owner}
+	case *types.SubchainStakeTx:
+		return []types.TxInput{tx.Staker}
+	case *types.SubchainCheckpointTx:
+		return []types.TxInput{tx.Submitter}
+	case *types.SubchainChallengeTx:
+		return []types.TxInput{tx.Challenger}
+	case *types.SmartContractTx:
+		return []types.TxInput{tx.From}
+	}
+	return nil
+}
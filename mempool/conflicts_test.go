@@ -0,0 +1,90 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindConflicts(t *testing.T) {
+	assert := assert.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	carol := common.HexToAddress("0x3")
+
+	send1 := encodeTestTx(t, &types.SendTx{
+		Fee:     types.NewCoins(0, 1e12),
+		Inputs:  []types.TxInput{{Address: alice, Coins: types.NewCoins(0, 10), Sequence: 1}},
+		Outputs: []types.TxOutput{{Address: bob, Coins: types.NewCoins(0, 10)}},
+	})
+	send2 := encodeTestTx(t, &types.SendTx{
+		Fee:     types.NewCoins(0, 1e12),
+		Inputs:  []types.TxInput{{Address: alice, Coins: types.NewCoins(0, 20), Sequence: 1}},
+		Outputs: []types.TxOutput{{Address: carol, Coins: types.NewCoins(0, 20)}},
+	})
+	send3 := encodeTestTx(t, &types.SendTx{
+		Fee:     types.NewCoins(0, 1e12),
+		Inputs:  []types.TxInput{{Address: alice, Coins: types.NewCoins(0, 20), Sequence: 2}},
+		Outputs: []types.TxOutput{{Address: carol, Coins: types.NewCoins(0, 20)}},
+	})
+
+	payment := func(target common.Address, targetSeq, paymentSeq uint64) common.Bytes {
+		return encodeTestTx(t, &types.ServicePaymentTx{
+			Fee:             types.NewCoins(0, 1e12),
+			Source:          types.TxInput{Address: bob, Coins: types.NewCoins(0, 5)},
+			Target:          types.TxInput{Address: target, Sequence: targetSeq},
+			PaymentSequence: paymentSeq,
+			ReserveSequence: 7,
+			ResourceID:      "rid",
+		})
+	}
+	payment1 := payment(carol, 1, 1)
+	payment2 := payment(carol, 2, 1)
+	payment3 := payment(alice, 3, 1)
+
+	release := encodeTestTx(t, &types.ReleaseFundTx{
+		Fee:             types.NewCoins(0, 1e12),
+		Source:          types.TxInput{Address: bob, Sequence: 9},
+		ReserveSequence: 7,
+	})
+
+	assert.Empty(findConflicts([]common.Bytes{send1, send3, payment1, payment3}))
+
+	conflicts := findConflicts([]common.Bytes{send1, send2, send3, payment1, payment2, common.Bytes("malformed")})
+	if assert.Len(conflicts, 2) {
+		assert.Equal(TxConflictReservedFund, conflicts[0].Type)
+		assert.Equal(bob, conflicts[0].Address)
+		assert.Equal(common.JSONUint64(7), conflicts[0].Sequence)
+		assert.ElementsMatch(testTxHashes(payment1, payment2), conflicts[0].TxHashes)
+
+		assert.Equal(TxConflictSequence, conflicts[1].Type)
+		assert.Equal(alice, conflicts[1].Address)
+		assert.Equal(common.JSONUint64(1), conflicts[1].Sequence)
+		assert.ElementsMatch(testTxHashes(send1, send2), conflicts[1].TxHashes)
+	}
+
+	// Releasing the reserved fund conflicts with the pending payments against it
+	conflicts = findConflicts([]common.Bytes{payment1, payment3, release})
+	if assert.Len(conflicts, 1) {
+		assert.Equal(TxConflictReservedFund, conflicts[0].Type)
+		assert.ElementsMatch(testTxHashes(payment1, payment3, release), conflicts[0].TxHashes)
+	}
+}
+
+func encodeTestTx(t *testing.T, tx types.Tx) common.Bytes {
+	raw, err := types.TxToBytes(tx)
+	require.Nil(t, err)
+	return raw
+}
+
+func testTxHashes(rawTxs ...common.Bytes) []string {
+	txHashes := []string{}
+	for _, rawTx := range rawTxs {
+		txHashes = append(txHashes, "0x"+getTransactionHash(rawTx))
+	}
+	return txHashes
+}
@@ -0,0 +1,119 @@
+package mempool
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+)
+
+// maxNumFeeSimulationAudits is the number of the most recent inclusion audits the fee simulation
+// learns the proposer behavior from
+const maxNumFeeSimulationAudits = 100
+
+// FeeSimulation is the predicted inclusion of a candidate transaction, simulated against a
+// checkpoint of the mempool, i.e. a snapshot of the pending transactions, and the recent proposer
+// behavior recorded by the inclusion audits.
+//
+// The candidate is queued behind the pending transactions the proposers reap first, and reaches
+// the front of the queue after BlocksAhead blocks. From then on, each block includes it with the
+// BlockInclusionRate, which accounts for the fee floors of the recent full blocks and for the
+// eligible transactions the recent proposers skipped. The transactions arriving after the
+// checkpoint are not simulated, so the estimates are optimistic when the mempool is filling up.
+type FeeSimulation struct {
+	EffectiveGasPrice    *common.JSONBig   `json:"effective_gas_price"`
+	Accepted             bool              `json:"accepted"`       // whether the mempool would accept the transaction now
+	NumPending           common.JSONUint64 `json:"num_pending"`    // number of pending transactions at the checkpoint
+	QueuePosition        common.JSONUint64 `json:"queue_position"` // number of pending transactions reaped before the candidate
+	BlocksAhead          common.JSONUint64 `json:"blocks_ahead"`   // number of blocks until the candidate reaches the front of the queue
+	BlockInclusionRate   float64           `json:"block_inclusion_rate"`
+	Horizon              common.JSONUint64 `json:"horizon"`               // number of blocks the inclusion probability is estimated for
+	InclusionProbability float64           `json:"inclusion_probability"` // probability to be included within the horizon
+	ExpectedBlocks       *float64          `json:"expected_blocks"`       // expected number of blocks to inclusion, nil if never included
+	NumAuditedBlocks     int               `json:"num_audited_blocks"`    // number of inclusion audits the proposer behavior is learned from
+}
+
+// SimulateFee simulates the inclusion of a candidate transaction paying the given effective gas
+// price within the next horizon blocks, against the current pending transactions.
+func (mp *Mempool) SimulateFee(gasPrice *big.Int, horizon uint64) *FeeSimulation {
+	mp.mutex.Lock()
+	pending := mp.pendingTxsUnsafe()
+	full := mp.size >= mp.maxSize
+	audits := mp.inclusionAudits
+	if len(audits) > maxNumFeeSimulationAudits {
+		audits = audits[len(audits)-maxNumFeeSimulationAudits:]
+	}
+	audits = append([]*InclusionAudit{}, audits...)
+	mp.mutex.Unlock()
+
+	return simulateFee(pending, full, audits, gasPrice, horizon, core.MaxNumRegularTxsPerBlock)
+}
+
+// simulateFee simulates the inclusion of a candidate transaction paying the given effective gas price,
+// with blocks of the given capacity.
+func simulateFee(pending []pendingTx, full bool, audits []*InclusionAudit, gasPrice *big.Int,
+	horizon uint64, capacity int) *FeeSimulation {
+	sim := &FeeSimulation{
+		EffectiveGasPrice: (*common.JSONBig)(new(big.Int).Set(gasPrice)),
+		Accepted:          true,
+		NumPending:        common.JSONUint64(len(pending)),
+		Horizon:           common.JSONUint64(horizon),
+		NumAuditedBlocks:  len(audits),
+	}
+
+	// The candidate is reaped as soon as the best next transaction of the other senders is priced
+	// lower. It is inserted last, so it goes after the transactions priced the same.
+	ranked := rankTransactions(pending, 0)
+	position := len(ranked)
+	for i, tx := range ranked {
+		if gasPrice.Cmp(tx.EffectiveGasPrice.ToInt()) > 0 {
+			position = i
+			break
+		}
+	}
+	sim.QueuePosition = common.JSONUint64(position)
+	sim.BlocksAhead = common.JSONUint64(position / capacity)
+
+	// A full mempool only takes the candidate by evicting a lower priced transaction
+	if full && position == len(ranked) {
+		sim.Accepted = false
+		return sim
+	}
+
+	sim.BlockInclusionRate = blockInclusionRate(audits, gasPrice)
+	if sim.BlockInclusionRate > 0 {
+		expected := float64(sim.BlocksAhead) + 1/sim.BlockInclusionRate
+		sim.ExpectedBlocks = &expected
+	}
+	if uint64(sim.BlocksAhead) < horizon {
+		attempts := float64(horizon - uint64(sim.BlocksAhead))
+		sim.InclusionProbability = 1 - math.Pow(1-sim.BlockInclusionRate, attempts)
+	}
+	return sim
+}
+
+// blockInclusionRate estimates the probability that a block includes the candidate transaction once
+// it is at the front of the queue. A full block only includes the transactions priced at least its
+// fee floor, and the proposers are expected to skip the eligible transactions at the rate they
+// recently did. Without audits, the proposers are assumed to follow the fee order.
+func blockInclusionRate(audits []*InclusionAudit, gasPrice *big.Int) float64 {
+	if len(audits) == 0 {
+		return 1
+	}
+
+	numAccepting := 0
+	numIncluded, numSkipped := 0, 0
+	for _, audit := range audits {
+		if audit.FeeFloor == nil || gasPrice.Cmp(audit.FeeFloor.ToInt()) >= 0 {
+			numAccepting++
+		}
+		numIncluded += audit.NumAudited
+		numSkipped += len(audit.SkippedTxs)
+	}
+	rate := float64(numAccepting) / float64(len(audits))
+	if numIncluded+numSkipped > 0 {
+		rate *= float64(numIncluded) / float64(numIncluded+numSkipped)
+	}
+	return rate
+}
@@ -0,0 +1,77 @@
+package mempool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateFee(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	now := time.Now()
+	newPendingTx := func(sender common.Address, seq uint64, price int64) pendingTx {
+		return pendingTx{
+			rawTx:      common.Bytes(sender.Hex() + string(rune('0'+seq))),
+			sender:     sender,
+			sequence:   seq,
+			gasPrice:   big.NewInt(price),
+			insertedAt: now,
+		}
+	}
+	pending := []pendingTx{
+		newPendingTx(alice, 1, 10),
+		newPendingTx(alice, 2, 2),
+		newPendingTx(bob, 1, 8),
+		newPendingTx(bob, 2, 5),
+	}
+
+	// Without audits, the proposers are assumed to follow the fee order. The candidate goes after
+	// alice1, bob1 and bob2, and with blocks of two transactions, it is included in the second block.
+	sim := simulateFee(pending, false, nil, big.NewInt(6), 3, 2)
+	assert.True(sim.Accepted)
+	assert.Equal(common.JSONUint64(4), sim.NumPending)
+	assert.Equal(common.JSONUint64(2), sim.QueuePosition)
+	assert.Equal(common.JSONUint64(1), sim.BlocksAhead)
+	assert.Equal(1.0, sim.BlockInclusionRate)
+	assert.Equal(1.0, sim.InclusionProbability)
+	require.NotNil(sim.ExpectedBlocks)
+	assert.Equal(2.0, *sim.ExpectedBlocks)
+
+	// The candidate is not included if it does not reach the front of the queue within the horizon
+	sim = simulateFee(pending, false, nil, big.NewInt(1), 2, 1)
+	assert.Equal(common.JSONUint64(4), sim.QueuePosition)
+	assert.Equal(common.JSONUint64(4), sim.BlocksAhead)
+	assert.Equal(0.0, sim.InclusionProbability)
+
+	// A full mempool does not take the lowest priced candidate
+	sim = simulateFee(pending, true, nil, big.NewInt(1), 10, 2)
+	assert.False(sim.Accepted)
+	assert.Equal(0.0, sim.InclusionProbability)
+	assert.Nil(sim.ExpectedBlocks)
+
+	// Half of the recent blocks were full with a fee floor above the candidate price, and the
+	// proposers skipped one in five eligible transactions
+	audits := []*InclusionAudit{
+		&InclusionAudit{NumAudited: 4, FeeFloor: (*common.JSONBig)(big.NewInt(20)), SkippedTxs: []SkippedTx{}},
+		&InclusionAudit{NumAudited: 4, SkippedTxs: []SkippedTx{SkippedTx{}, SkippedTx{}}},
+	}
+	sim = simulateFee(pending, false, audits, big.NewInt(12), 2, 2)
+	assert.Equal(common.JSONUint64(0), sim.QueuePosition)
+	assert.Equal(2, sim.NumAuditedBlocks)
+	assert.InDelta(0.4, sim.BlockInclusionRate, 1e-9)
+	assert.InDelta(1-0.6*0.6, sim.InclusionProbability, 1e-9)
+	require.NotNil(sim.ExpectedBlocks)
+	assert.InDelta(2.5, *sim.ExpectedBlocks, 1e-9)
+
+	// A candidate priced above the fee floors is only subject to the skip rate
+	sim = simulateFee(pending, false, audits, big.NewInt(20), 2, 2)
+	assert.InDelta(0.8, sim.BlockInclusionRate, 1e-9)
+}
@@ -0,0 +1,178 @@
+package mempool
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/metrics"
+	"github.com/pandotoken/pando/core"
+)
+
+// maxNumInclusionAudits is the number of the most recent inclusion audits retained
+const maxNumInclusionAudits = 256
+
+var (
+	inclusionAuditedCounter    = metrics.NewRegisteredCounter("mempool/inclusion/audited", nil)
+	inclusionSkippedCounter    = metrics.NewRegisteredCounter("mempool/inclusion/skipped", nil)
+	inclusionOutOfOrderCounter = metrics.NewRegisteredCounter("mempool/inclusion/out_of_order", nil)
+)
+
+// SkippedTx is a pending transaction paying above the fee floor of a block which the
+// proposer left out although it was eligible for inclusion
+type SkippedTx struct {
+	Hash              string            `json:"hash"`
+	Sender            common.Address    `json:"sender"`
+	Sequence          common.JSONUint64 `json:"sequence"`
+	EffectiveGasPrice *common.JSONBig   `json:"effective_gas_price"`
+}
+
+// InclusionAudit compares the transactions included in a block with the snapshot of the
+// local mempool at the time the block was applied. The proposer is expected to include the
+// pending transactions in the order of the effective gas price. Since the mempools of the
+// nodes are never exactly the same, a skipped transaction alone is not a proof of censorship,
+// but a sender repeatedly skipped by a proposer is a strong signal.
+type InclusionAudit struct {
+	Height     common.JSONUint64 `json:"height"`
+	BlockHash  common.Hash       `json:"block_hash"`
+	Proposer   common.Address    `json:"proposer"`
+	NumTxs     int               `json:"num_txs"`     // number of transactions in the block
+	NumAudited int               `json:"num_audited"` // number of included transactions known to the local mempool
+	FeeFloor   *common.JSONBig   `json:"fee_floor"`   // lowest effective gas price included, nil if the block is not full
+	OutOfOrder int               `json:"out_of_order"`
+	SkippedTxs []SkippedTx       `json:"skipped_txs"`
+}
+
+// pendingTx is a transaction of the mempool snapshot taken for an inclusion audit
+type pendingTx struct {
+	rawTx      common.Bytes
+	sender     common.Address
+	sequence   uint64
+	gasPrice   *big.Int
+	insertedAt time.Time
+}
+
+// AuditInclusionUnsafe audits the transactions of the block against the pending transactions,
+// and records the audit. Transactions inserted into the mempool less than gracePeriod before
+// the block timestamp are not expected to be included. It must be called before the committed
+// transactions are removed from the mempool, with the mempool locked.
+func (mp *Mempool) AuditInclusionUnsafe(block *core.Block, gracePeriod time.Duration) *InclusionAudit {
+	pending := mp.pendingTxsUnsafe()
+
+	cutoff := time.Unix(block.Timestamp.Int64(), 0).Add(-gracePeriod)
+	audit := auditInclusion(pending, block.Txs, cutoff)
+	audit.Height = common.JSONUint64(block.Height)
+	audit.BlockHash = block.Hash()
+	audit.Proposer = block.Proposer
+
+	inclusionAuditedCounter.Inc(1)
+	inclusionSkippedCounter.Inc(int64(len(audit.SkippedTxs)))
+	inclusionOutOfOrderCounter.Inc(int64(audit.OutOfOrder))
+	if len(audit.SkippedTxs) > 0 || audit.OutOfOrder > 0 {
+		logger.Warnf("Proposer %v skipped %v eligible transactions and included %v out of fee order in block %v",
+			block.Proposer.Hex(), len(audit.SkippedTxs), audit.OutOfOrder, block.Hash().Hex())
+	}
+
+	mp.inclusionAudits = append(mp.inclusionAudits, audit)
+	if len(mp.inclusionAudits) > maxNumInclusionAudits {
+		mp.inclusionAudits = mp.inclusionAudits[len(mp.inclusionAudits)-maxNumInclusionAudits:]
+	}
+	return audit
+}
+
+// GetInclusionAudits returns the retained inclusion audits, the most recent first
+func (mp *Mempool) GetInclusionAudits() []*InclusionAudit {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	audits := make([]*InclusionAudit, 0, len(mp.inclusionAudits))
+	for i := len(mp.inclusionAudits) - 1; i >= 0; i-- {
+		audits = append(audits, mp.inclusionAudits[i])
+	}
+	return audits
+}
+
+// auditInclusion compares the block transactions with the pending transactions inserted
+// before the cutoff time.
+func auditInclusion(pending []pendingTx, blockTxs []common.Bytes, cutoff time.Time) *InclusionAudit {
+	audit := &InclusionAudit{
+		NumTxs:     len(blockTxs),
+		SkippedTxs: []SkippedTx{},
+	}
+
+	included := make(map[string]bool)
+	for _, rawTx := range blockTxs {
+		included[string(rawTx)] = true
+	}
+	pendingByRawTx := make(map[string]*pendingTx)
+	for i := range pending {
+		pendingByRawTx[string(pending[i].rawTx)] = &pending[i]
+	}
+
+	// Fee order of the included transactions. The proposer reaps the sender with the highest
+	// priced next transaction first, so the price can only go up between two consecutive
+	// transactions of the same sender.
+	var floor, prevPrice *big.Int
+	var prevSender common.Address
+	for _, rawTx := range blockTxs {
+		tx, ok := pendingByRawTx[string(rawTx)]
+		if !ok {
+			continue // special transactions, or transactions never received locally
+		}
+		audit.NumAudited++
+		if prevPrice != nil && tx.sender != prevSender && tx.gasPrice.Cmp(prevPrice) > 0 {
+			audit.OutOfOrder++
+		}
+		if floor == nil || tx.gasPrice.Cmp(floor) < 0 {
+			floor = tx.gasPrice
+		}
+		prevPrice, prevSender = tx.gasPrice, tx.sender
+	}
+	full := len(blockTxs) >= core.MaxNumRegularTxsPerBlock
+	if full {
+		if floor == nil {
+			return audit // nothing to compare the pending transactions against
+		}
+		audit.FeeFloor = (*common.JSONBig)(new(big.Int).Set(floor))
+	}
+
+	// Only the lowest sequence transaction of a sender not included in the block can be
+	// included next, the higher sequence ones depend on it.
+	bySender := make(map[common.Address][]*pendingTx)
+	senders := []common.Address{}
+	for i := range pending {
+		tx := &pending[i]
+		if _, ok := bySender[tx.sender]; !ok {
+			senders = append(senders, tx.sender)
+		}
+		bySender[tx.sender] = append(bySender[tx.sender], tx)
+	}
+	for _, sender := range senders {
+		txs := bySender[sender]
+		sort.Slice(txs, func(i, j int) bool { return txs[i].sequence < txs[j].sequence })
+		for _, tx := range txs {
+			if included[string(tx.rawTx)] {
+				continue
+			}
+			if tx.insertedAt.Before(cutoff) && (!full || tx.gasPrice.Cmp(floor) > 0) {
+				audit.SkippedTxs = append(audit.SkippedTxs, SkippedTx{
+					Hash:              "0x" + getTransactionHash(tx.rawTx),
+					Sender:            tx.sender,
+					Sequence:          common.JSONUint64(tx.sequence),
+					EffectiveGasPrice: (*common.JSONBig)(new(big.Int).Set(tx.gasPrice)),
+				})
+			}
+			break
+		}
+	}
+
+	sort.Slice(audit.SkippedTxs, func(i, j int) bool {
+		txi, txj := audit.SkippedTxs[i], audit.SkippedTxs[j]
+		if cmp := txi.EffectiveGasPrice.ToInt().Cmp(txj.EffectiveGasPrice.ToInt()); cmp != 0 {
+			return cmp > 0
+		}
+		return txi.Hash < txj.Hash
+	})
+	return audit
+}
@@ -0,0 +1,64 @@
+package mempool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditInclusion(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	early := now.Add(-time.Minute)
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	carol := common.HexToAddress("0x3")
+
+	newTx := func(raw string, sender common.Address, seq uint64, price int64, insertedAt time.Time) pendingTx {
+		return pendingTx{
+			rawTx:      common.Bytes(raw),
+			sender:     sender,
+			sequence:   seq,
+			gasPrice:   big.NewInt(price),
+			insertedAt: insertedAt,
+		}
+	}
+	alice1 := newTx("alice1", alice, 1, 30, early)
+	alice2 := newTx("alice2", alice, 2, 50, early)
+	bob1 := newTx("bob1", bob, 1, 40, early)
+	carol1 := newTx("carol1", carol, 1, 20, early)
+	carolLate := newTx("carol1", carol, 1, 20, now)
+	pending := []pendingTx{alice1, alice2, bob1, carol1}
+
+	// Fair block, the price only goes up between the transactions of the same sender
+	audit := auditInclusion(pending, []common.Bytes{common.Bytes("coinbase"), bob1.rawTx, alice1.rawTx, alice2.rawTx, carol1.rawTx}, now)
+	assert.Equal(5, audit.NumTxs)
+	assert.Equal(4, audit.NumAudited)
+	assert.Equal(0, audit.OutOfOrder)
+	assert.Nil(audit.FeeFloor)
+	assert.Empty(audit.SkippedTxs)
+
+	// Carol skipped in a block which is not full
+	audit = auditInclusion(pending, []common.Bytes{bob1.rawTx, alice1.rawTx, alice2.rawTx}, now)
+	assert.Equal(1, len(audit.SkippedTxs))
+	assert.Equal(carol, audit.SkippedTxs[0].Sender)
+	assert.Equal(common.JSONUint64(1), audit.SkippedTxs[0].Sequence)
+
+	// Carol's transaction arrived too late to be expected in the block
+	audit = auditInclusion([]pendingTx{alice1, alice2, bob1, carolLate}, []common.Bytes{bob1.rawTx, alice1.rawTx, alice2.rawTx}, now)
+	assert.Empty(audit.SkippedTxs)
+
+	// Only the next transaction of a sender is expected, and it is reported once
+	audit = auditInclusion(pending, []common.Bytes{bob1.rawTx, carol1.rawTx}, now)
+	assert.Equal(1, len(audit.SkippedTxs))
+	assert.Equal(alice, audit.SkippedTxs[0].Sender)
+	assert.Equal(common.JSONUint64(1), audit.SkippedTxs[0].Sequence)
+
+	// Lower priced transaction of another sender included first
+	audit = auditInclusion(pending, []common.Bytes{carol1.rawTx, bob1.rawTx, alice1.rawTx, alice2.rawTx}, now)
+	assert.Equal(1, audit.OutOfOrder)
+}
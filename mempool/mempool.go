@@ -3,25 +3,36 @@ package mempool
 import (
 	"context"
 	"encoding/hex"
-	"errors"
 	"math/big"
 	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/clist"
 	"github.com/pandotoken/pando/common/math"
 	"github.com/pandotoken/pando/common/pqueue"
 	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/common/util"
 	"github.com/pandotoken/pando/consensus"
 	"github.com/pandotoken/pando/core"
 	dp "github.com/pandotoken/pando/dispatcher"
+	"github.com/pandotoken/pando/ledger/sigverify"
+	"github.com/pandotoken/pando/ledger/types"
 )
 
 var logger *log.Entry = log.WithFields(log.Fields{"prefix": "mempool"})
 
+// traceTx records the mempool stage of the transaction if the transaction is traced
+func traceTx(rawTx common.Bytes, stage string, format string, args ...interface{}) {
+	if !util.IsTracing(util.TraceTx) {
+		return
+	}
+	util.Trace(util.TraceTx, getTransactionHash(rawTx), "mempool", stage, format, args...)
+}
+
 type MempoolError string
 
 func (m MempoolError) Error() string {
@@ -30,7 +41,20 @@ func (m MempoolError) Error() string {
 
 const DuplicateTxError = MempoolError("Transaction already seen")
 const FastsyncSkipTxError = MempoolError("Skip tx during fastsync")
+const MempoolFullError = MempoolError("Mempool is full, please submit your transaction again later")
 
+// TxScreeningError is returned when a transaction fails the screening against the ledger
+// state. The error code tells the clients why the transaction was rejected.
+type TxScreeningError struct {
+	Code    result.ErrorCode
+	Message string
+}
+
+func (e *TxScreeningError) Error() string {
+	return e.Message
+}
+
+// MaxMempoolTxCount is the default maximum number of transactions in the Mempool
 const MaxMempoolTxCount int = 25600
 
 //
@@ -40,6 +64,7 @@ type mempoolTransaction struct {
 	index          int
 	rawTransaction common.Bytes
 	txInfo         *core.TxInfo
+	insertedAt     time.Time
 }
 
 var _ pqueue.Element = (*mempoolTransaction)(nil)
@@ -61,6 +86,7 @@ func createMempoolTransaction(rawTransaction common.Bytes, txInfo *core.TxInfo)
 	return &mempoolTransaction{
 		rawTransaction: rawTransaction,
 		txInfo:         txInfo,
+		insertedAt:     time.Now(),
 	}
 }
 
@@ -105,6 +131,18 @@ func (mtg *mempoolTransactionGroup) IsEmpty() bool {
 	return mtg.txs.IsEmpty()
 }
 
+// LastTx returns the transaction with the highest sequence of the group, or nil if the group is empty
+func (mtg *mempoolTransactionGroup) LastTx() *mempoolTransaction {
+	var last *mempoolTransaction
+	for _, elem := range *mtg.txs.ElementList() {
+		mptx := elem.(*mempoolTransaction)
+		if last == nil || mptx.txInfo.Sequence > last.txInfo.Sequence {
+			last = mptx
+		}
+	}
+	return last
+}
+
 // RemoveTxs removes matching Txs from transaction group. Returns number of Txs removed.
 func (mtg *mempoolTransactionGroup) RemoveTxs(committedRawTxMap map[string]bool) (numRemoved int) {
 	elementList := mtg.txs.ElementList()
@@ -140,15 +178,18 @@ func createMempoolTransactionGroup(rawTx common.Bytes, txInfo *core.TxInfo) *mem
 type Mempool struct {
 	mutex *sync.Mutex
 
-	consensus  *consensus.ConsensusEngine
-	ledger     core.Ledger
-	dispatcher *dp.Dispatcher
+	consensus   *consensus.ConsensusEngine
+	ledger      core.Ledger
+	dispatcher  *dp.Dispatcher
+	sigVerifier *sigverify.Verifier
 
 	newTxs           *clist.CList          // new transactions, to be gossiped to other nodes
 	candidateTxs     *pqueue.PriorityQueue // candidate transactions for new block assembly, ordered by the transaction fee (high to low)
 	txBookeepper     transactionBookkeeper
 	addressToTxGroup map[common.Address]*mempoolTransactionGroup
 	size             int
+	maxSize          int
+	inclusionAudits  []*InclusionAudit // recent audits of the transactions included by the proposers
 
 	// Life cycle
 	wg      *sync.WaitGroup
@@ -160,6 +201,10 @@ type Mempool struct {
 
 // CreateMempool creates an instance of Mempool
 func CreateMempool(dispatcher *dp.Dispatcher, engine *consensus.ConsensusEngine) *Mempool {
+	maxSize := viper.GetInt(common.CfgMempoolMaxSize)
+	if maxSize <= 0 {
+		maxSize = MaxMempoolTxCount
+	}
 	return &Mempool{
 		mutex:            &sync.Mutex{},
 		consensus:        engine,
@@ -168,6 +213,7 @@ func CreateMempool(dispatcher *dp.Dispatcher, engine *consensus.ConsensusEngine)
 		candidateTxs:     pqueue.CreatePriorityQueue(),
 		addressToTxGroup: make(map[common.Address]*mempoolTransactionGroup),
 		txBookeepper:     createTransactionBookkeeper(defaultMaxNumTxs),
+		maxSize:          maxSize,
 		wg:               &sync.WaitGroup{},
 	}
 }
@@ -177,31 +223,56 @@ func (mp *Mempool) SetLedger(ledger core.Ledger) {
 	mp.ledger = ledger
 }
 
+// SetSignatureVerifier sets the verifier recovering the signers of the incoming transactions
+// before they are screened.
+func (mp *Mempool) SetSignatureVerifier(sigVerifier *sigverify.Verifier) {
+	mp.sigVerifier = sigVerifier
+}
+
 // InsertTransaction inserts the incoming transaction to mempool (submitted by the clients or relayed from peers)
 func (mp *Mempool) InsertTransaction(rawTx common.Bytes) error {
+	// The stateless checks do not need the lock
+	if err := precheckTransaction(rawTx); err != nil {
+		logger.Debugf("Transaction failed the pre-checks, hash: 0x%v, error: %v", getTransactionHash(rawTx), err)
+		traceTx(rawTx, "precheck_failed", "error: %v", err)
+		return err
+	}
+
+	// Recover the signers before taking the lock, so that the concurrent submissions recover them
+	// in parallel, and the screening under the lock hits the signature cache
+	if mp.consensus == nil || mp.consensus.HasSynced() {
+		mp.sigVerifier.Verify(rawTx)
+	}
+
 	mp.mutex.Lock()
 	defer mp.mutex.Unlock()
 
 	if mp.txBookeepper.hasSeen(rawTx) {
 		logger.Debugf("Transaction already seen: %v, hash: 0x%v",
 			hex.EncodeToString(rawTx), getTransactionHash(rawTx))
+		traceTx(rawTx, "duplicate", "the transaction has already been seen")
 		return DuplicateTxError
 	}
 
-	// if mp.size >= MaxMempoolTxCount {
-	// 	logger.Debugf("Mempool is full")
-	// 	return errors.New("mempool is full, please submit your transaction again later")
-	// }
-
 	var txInfo *core.TxInfo
 	var checkTxRes result.Result
 
-	// Delay tx verification when in fast sync
-	if mp.consensus.HasSynced() {
+	// Delay tx verification when in fast sync. A mempool without a consensus engine (e.g. one
+	// driven directly by the benchmarks) always screens the transactions right away.
+	if mp.consensus == nil || mp.consensus.HasSynced() {
 		txInfo, checkTxRes = mp.ledger.ScreenTx(rawTx)
 		if !checkTxRes.IsOK() {
 			logger.Debugf("Transaction screening failed, tx: %v, error: %v", hex.EncodeToString(rawTx), checkTxRes.Message)
-			return errors.New(checkTxRes.Message)
+			traceTx(rawTx, "screening_failed", "code: %v, error: %v", checkTxRes.Code, checkTxRes.Message)
+			return &TxScreeningError{Code: checkTxRes.Code, Message: checkTxRes.Message}
+		}
+
+		// When the mempool is full, the transaction can only replace a lower priced one
+		if mp.size >= mp.maxSize && !mp.evictUnsafe(txInfo) {
+			logger.Debugf("Mempool is full, reject tx: 0x%v, effective gas price: %v", getTransactionHash(rawTx), txInfo.EffectiveGasPrice)
+			mempoolRejectedFullCounter.Inc(1)
+			traceTx(rawTx, "rejected_full", "effective gas price: %v, mempool size: %v", txInfo.EffectiveGasPrice, mp.size)
+			return MempoolFullError
 		}
 
 		// only record the transactions that passed the screening. This is because that
@@ -210,26 +281,33 @@ func (mp *Mempool) InsertTransaction(rawTx common.Bytes) error {
 		// He then submit txB(seq = 6), and then txA(seq = 7) again. For the second submission, txA
 		// should not be rejected even though it has been submitted earlier.
 		mp.txBookeepper.record(rawTx)
-
-		txGroup, ok := mp.addressToTxGroup[txInfo.Address]
-		if ok {
-			txGroup.AddTx(rawTx, txInfo)
-			mp.candidateTxs.Remove(txGroup.index) // Need to re-insert txGroup into queue since its priority could change.
-		} else {
-			txGroup = createMempoolTransactionGroup(rawTx, txInfo)
-			mp.addressToTxGroup[txInfo.Address] = txGroup
-		}
-		mp.candidateTxs.Push(txGroup)
-		logger.Debugf("rawTx: %v, txInfo: %v", hex.EncodeToString(rawTx), txInfo)
-		//logger.Infof("Insert tx, tx.hash: 0x%v", getTransactionHash(rawTx))
-		mp.size++
+		mp.addTxUnsafe(rawTx, txInfo)
+		traceTx(rawTx, "inserted", "sender: %v, sequence: %v, effective gas price: %v, mempool size: %v",
+			txInfo.Address.Hex(), txInfo.Sequence, txInfo.EffectiveGasPrice, mp.size)
 
 		return nil
 	}
 
+	traceTx(rawTx, "fastsync_skipped", "the node has not synced yet")
 	return FastsyncSkipTxError
 }
 
+// addTxUnsafe adds the screened transaction to the candidate transactions. The caller needs to hold the lock.
+func (mp *Mempool) addTxUnsafe(rawTx common.Bytes, txInfo *core.TxInfo) {
+	txGroup, ok := mp.addressToTxGroup[txInfo.Address]
+	if ok {
+		txGroup.AddTx(rawTx, txInfo)
+		mp.candidateTxs.Remove(txGroup.index) // Need to re-insert txGroup into queue since its priority could change.
+	} else {
+		txGroup = createMempoolTransactionGroup(rawTx, txInfo)
+		mp.addressToTxGroup[txInfo.Address] = txGroup
+	}
+	mp.candidateTxs.Push(txGroup)
+	logger.Debugf("rawTx: %v, txInfo: %v", hex.EncodeToString(rawTx), txInfo)
+	//logger.Infof("Insert tx, tx.hash: 0x%v", getTransactionHash(rawTx))
+	mp.size++
+}
+
 // Start needs to be called when the Mempool starts
 func (mp *Mempool) Start(ctx context.Context) error {
 	c, cancel := context.WithCancel(ctx)
@@ -280,6 +358,15 @@ func (mp *Mempool) Reap(maxNumTxs int) []common.Bytes {
 
 // ReapUnsafe is the non-locking version of Reap.
 func (mp *Mempool) ReapUnsafe(maxNumTxs int) []common.Bytes {
+	return mp.ReapWithBudgetUnsafe(maxNumTxs, nil)
+}
+
+// ReapWithBudgetUnsafe reaps the transactions by fee priority like ReapUnsafe, and in addition only
+// reaps the transactions fitting in the rest of the block budget, which accounts for them. Once the
+// next transaction of an account does not fit, the later transactions of the account are skipped as
+// well, since they cannot be included without it. The skipped transactions stay in the mempool. A nil
+// budget reaps without a budget. It is the non-locking version.
+func (mp *Mempool) ReapWithBudgetUnsafe(maxNumTxs int, budget *types.BlockBudget) []common.Bytes {
 	if maxNumTxs == 0 {
 		return []common.Bytes{}
 	} else if maxNumTxs < 0 {
@@ -289,11 +376,25 @@ func (mp *Mempool) ReapUnsafe(maxNumTxs int) []common.Bytes {
 	}
 
 	txs := make([]common.Bytes, 0, maxNumTxs)
-	for i := 0; i < maxNumTxs; i++ {
+	skippedGroups := []*mempoolTransactionGroup{}
+	for len(txs) < maxNumTxs {
 		if mp.candidateTxs.IsEmpty() {
 			break
 		}
 		txGroup := mp.candidateTxs.Pop().(*mempoolTransactionGroup)
+
+		var tx types.Tx
+		if budget != nil {
+			next := txGroup.txs.Peek().(*mempoolTransaction)
+			decoded, err := types.TxFromBytes(next.rawTransaction)
+			if err != nil || !budget.Fits(types.TxGas(decoded), uint64(len(next.rawTransaction))) {
+				skippedGroups = append(skippedGroups, txGroup)
+				traceTx(next.rawTransaction, "skipped", "the transaction does not fit in the block budget: %v", budget)
+				continue
+			}
+			tx = decoded
+		}
+
 		rawTx, txInfo := txGroup.PopTx()
 
 		// Check for outdated txs
@@ -302,6 +403,12 @@ func (mp *Mempool) ReapUnsafe(maxNumTxs int) []common.Bytes {
 		if exists {
 			// Only add back Txs that has not been removed from bookkeeper due to timeout
 			txs = append(txs, rawTx)
+			if budget != nil {
+				budget.Add(rawTx, tx)
+			}
+			traceTx(rawTx, "reaped", "sequence: %v", txInfo.Sequence)
+		} else {
+			traceTx(rawTx, "expired", "the transaction timed out before it was reaped")
 		}
 
 		if txGroup.IsEmpty() {
@@ -313,6 +420,9 @@ func (mp *Mempool) ReapUnsafe(maxNumTxs int) []common.Bytes {
 		logger.Debugf("Reap tx: %v, txInfo: %v",
 			hex.EncodeToString(rawTx), txInfo)
 	}
+	for _, txGroup := range skippedGroups {
+		mp.candidateTxs.Push(txGroup)
+	}
 
 	mp.size -= len(txs)
 
@@ -332,6 +442,9 @@ func (mp *Mempool) Update(committedRawTxs []common.Bytes) {
 // calling this method.
 func (mp *Mempool) UpdateUnsafe(committedRawTxs []common.Bytes) {
 	start := time.Now()
+	for _, rawTx := range committedRawTxs {
+		traceTx(rawTx, "included", "the transaction is included in an applied block")
+	}
 	mp.removeTxs(committedRawTxs)
 	removeCommittedTxTime := time.Since(start)
 
@@ -354,6 +467,7 @@ func (mp *Mempool) UpdateUnsafe(committedRawTxs []common.Bytes) {
 			if !exists {
 				// Tx has been removed from bookkeeper due to timeout
 				invalidTxs = append(invalidTxs, mempoolTx.rawTransaction)
+				traceTx(mempoolTx.rawTransaction, "expired", "the transaction timed out")
 				continue
 			}
 
@@ -361,6 +475,7 @@ func (mp *Mempool) UpdateUnsafe(committedRawTxs []common.Bytes) {
 			if !checkTxRes.IsOK() {
 				invalidTxs = append(invalidTxs, mempoolTx.rawTransaction)
 				mp.txBookeepper.markAbandoned(mempoolTx.rawTransaction)
+				traceTx(mempoolTx.rawTransaction, "abandoned", "code: %v, error: %v", checkTxRes.Code, checkTxRes.Message)
 			}
 		}
 	}
@@ -453,5 +568,6 @@ func (mp *Mempool) BroadcastTxUnsafe(tx common.Bytes) {
 
 	peerIDs := []string{}
 	mp.dispatcher.SendData(peerIDs, data)
+	traceTx(tx, "broadcast", "gossiped the transaction to the peers")
 }
 
@@ -7,8 +7,10 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/util"
 	dp "github.com/pandotoken/pando/dispatcher"
 	"github.com/pandotoken/pando/p2p/types"
+	p2pcmn "github.com/pandotoken/pando/p2pl/common"
 	"github.com/pandotoken/pando/rlp"
 )
 
@@ -47,10 +49,19 @@ func Fuzz(data []byte) int {
 	return 0
 }
 
+// messageLimits bounds the gossiped transactions, oversized transactions
+// are rejected before they are copied out of the message
+var messageLimits = rlp.Limits{
+	MaxInputSize:  p2pcmn.MaxNormalMessageSize,
+	MaxStringSize: MaxTxSize,
+}
+
 // ParseMessage implements the p2p.MessageHandler interface
 func (mmh *MempoolMessageHandler) ParseMessage(peerID string, channelID common.ChannelIDEnum, rawMessageBytes common.Bytes) (types.Message, error) {
 	var dataResponse dp.DataResponse
-	rlp.DecodeBytes(rawMessageBytes, &dataResponse)
+	if err := rlp.DecodeBytesWithLimits(rawMessageBytes, &dataResponse, messageLimits); err != nil {
+		return types.Message{}, err
+	}
 
 	rawTx := dataResponse.Payload
 	message := types.Message{
@@ -68,6 +79,10 @@ func (mmh *MempoolMessageHandler) HandleMessage(message types.Message) error {
 	}
 	rawTx := message.Content.(common.Bytes)
 	logger.Debugf("Received gossiped transaction: %v", hex.EncodeToString(rawTx))
+	traceTx(rawTx, "received", "gossiped by peer %v", message.PeerID)
+	if util.IsTracing(util.TracePeer) {
+		util.Trace(util.TracePeer, message.PeerID, "mempool", "tx_received", "hash: 0x%v", getTransactionHash(rawTx))
+	}
 
 	err := mmh.mempool.InsertTransaction(rawTx)
 	if err == DuplicateTxError {
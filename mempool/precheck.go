@@ -0,0 +1,287 @@
+package mempool
+
+import (
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/metrics"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+// MaxTxSize is the size of the largest transaction accepted into the mempool.
+const MaxTxSize = 128 * 1024
+
+// signatureLength is the length of a secp256k1 signature in the [R || S || V] format.
+const signatureLength = 65
+
+const (
+	TxTooLargeError        = MempoolError("Transaction is too large")
+	TxMalformedError       = MempoolError("Transaction is malformed")
+	TxTypeNotAllowedError  = MempoolError("Transaction type cannot be submitted to the mempool")
+	TxFeeTooLowError       = MempoolError("Transaction fee is below the minimum")
+	TxBadSignatureError    = MempoolError("Transaction signature is malformed")
+	TxGasLimitTooHighError = MempoolError("Transaction gas limit is above the maximum")
+)
+
+var (
+	precheckPassedCounter    = metrics.NewRegisteredCounter("mempool/precheck/passed", nil)
+	precheckTooLargeCounter  = metrics.NewRegisteredCounter("mempool/precheck/rejected/size", nil)
+	precheckMalformedCounter = metrics.NewRegisteredCounter("mempool/precheck/rejected/malformed", nil)
+	precheckTypeCounter      = metrics.NewRegisteredCounter("mempool/precheck/rejected/type", nil)
+	precheckFeeCounter       = metrics.NewRegisteredCounter("mempool/precheck/rejected/fee", nil)
+	precheckSignatureCounter = metrics.NewRegisteredCounter("mempool/precheck/rejected/signature", nil)
+	precheckGasLimitCounter  = metrics.NewRegisteredCounter("mempool/precheck/rejected/gas_limit", nil)
+)
+
+// precheckTransaction performs the cheap stateless checks on an incoming transaction.
+// It runs before any state read or signature recovery, so that spam can be rejected
+// with minimal CPU cost. Passing the checks does not mean the transaction is valid.
+//
+// Note the chain ID is not part of the raw transaction, it only appears in the sign
+// bytes, so it is verified later during signature recovery.
+func precheckTransaction(rawTx common.Bytes) error {
+	err := doPrecheckTransaction(rawTx)
+	switch err {
+	case nil:
+		precheckPassedCounter.Inc(1)
+	case TxTooLargeError:
+		precheckTooLargeCounter.Inc(1)
+	case TxMalformedError:
+		precheckMalformedCounter.Inc(1)
+	case TxTypeNotAllowedError:
+		precheckTypeCounter.Inc(1)
+	case TxFeeTooLowError:
+		precheckFeeCounter.Inc(1)
+	case TxBadSignatureError:
+		precheckSignatureCounter.Inc(1)
+	case TxGasLimitTooHighError:
+		precheckGasLimitCounter.Inc(1)
+	}
+	return err
+}
+
+func doPrecheckTransaction(rawTx common.Bytes) error {
+	if len(rawTx) == 0 {
+		return TxMalformedError
+	}
+	if len(rawTx) > MaxTxSize {
+		return TxTooLargeError
+	}
+
+	tx, err := types.TxFromBytes(rawTx)
+	if err != nil {
+		return TxMalformedError
+	}
+
+	var fee types.Coins
+	var sigs []*crypto.Signature
+	switch tx := tx.(type) {
+	case *types.CoinbaseTx, *types.SlashTx:
+		// Only added by the block proposer
+		return TxTypeNotAllowedError
+	case *types.SendTx:
+		fee = tx.Fee
+		if len(tx.Data) > types.MaximumSendTxDataLength {
+			return TxMalformedError
+		}
+		if fee.NoNil().PTXWei.Cmp(tx.MinimumFee()) < 0 {
+			return TxFeeTooLowError
+		}
+		for _, input := range tx.Inputs {
+			sigs = append(sigs, inputSignatures(input)...)
+		}
+	case *types.RametronStakeTx:
+		fee = tx.Fee
+		for _, input := range tx.Inputs {
+			sigs = append(sigs, inputSignatures(input)...)
+		}
+	case *types.BatchSendTx:
+		fee = tx.Fee
+		if fee.NoNil().PTXWei.Cmp(tx.MinimumFee()) < 0 {
+			return TxFeeTooLowError
+		}
+		for _, input := range tx.Inputs {
+			sigs = append(sigs, inputSignatures(input)...)
+		}
+	case *types.ReserveFundTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Source.Signature)
+	case *types.ReleaseFundTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Source.Signature)
+	case *types.ServicePaymentTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Source.Signature, tx.Target.Signature)
+	case *types.SplitRuleTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Initiator.Signature)
+	case *types.DepositStakeTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Source.Signature)
+	case *types.WithdrawStakeTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Source.Signature)
+	case *types.DepositStakeTxV2:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Source.Signature)
+		if tx.HolderSig != nil {
+			sigs = append(sigs, tx.HolderSig)
+		}
+	case *types.SessionKeyTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Account.Signature)
+	case *types.KeyRotationTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Account.Signature)
+	case *types.HTLCCreateTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Sender.Signature)
+	case *types.HTLCClaimTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Claimer.Signature)
+		if len(tx.Preimage) != types.HTLCPreimageLength {
+			return TxMalformedError
+		}
+	case *types.HTLCRefundTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Refunder.Signature)
+	case *types.ProposalTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Proposer.Signature)
+		if tx.Value == nil {
+			return TxMalformedError
+		}
+	case *types.VoteTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Voter.Signature)
+	case *types.BridgeLockTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Sender.Signature)
+	case *types.BridgeBurnTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Sender.Signature)
+		if tx.Amount == nil {
+			return TxMalformedError
+		}
+	case *types.BridgeClaimTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Relayer.Signature)
+		if len(tx.Proof.Attestations) == 0 || len(tx.Proof.Attestations) > types.BridgeValidatorCount {
+			return TxMalformedError
+		}
+	case *types.SubchainRegisterTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Owner.Signature)
+	case *types.SubchainStakeTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Staker.Signature)
+	case *types.SubchainCheckpointTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Submitter.Signature)
+		if len(tx.Signatures) == 0 || len(tx.Signatures) > types.MaximumSubchainStakers {
+			return TxMalformedError
+		}
+	case *types.SubchainChallengeTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Challenger.Signature)
+		if len(tx.Signatures) == 0 || len(tx.Signatures) > types.MaximumSubchainStakers {
+			return TxMalformedError
+		}
+	case *types.SlashAppealTx:
+		fee = tx.Fee
+		sigs = append(sigs, inputSignatures(tx.Appellant)...)
+	case *types.SlashAppealVoteTx:
+		fee = tx.Fee
+		sigs = append(sigs, inputSignatures(tx.Voter)...)
+	case *types.ClaimEscrowTx:
+		fee = tx.Fee
+		if len(tx.Preimage) > types.MaximumEscrowPreimageLength {
+			return TxMalformedError
+		}
+		// A clause may be satisfied without any signature
+		sigs = append(sigs, tx.Signatures...)
+	case *types.MeteredSettlementTx:
+		fee = tx.Fee
+		if len(tx.Records) == 0 || len(tx.Records) > types.MaximumMeteringRecords {
+			return TxMalformedError
+		}
+		sigs = append(sigs, tx.EdgeNode.Signature)
+		for _, record := range tx.Records {
+			sigs = append(sigs, record.ClientSignature, record.EdgeNodeSignature)
+		}
+	case *types.DelegateTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Delegator.Signature)
+	case *types.UndelegateTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Delegator.Signature)
+	case *types.SetCommissionTx:
+		fee = tx.Fee
+		sigs = append(sigs, tx.Validator.Signature)
+	case *types.RametronAttestationTx:
+		fee = tx.Fee
+		if len(tx.Heartbeats) == 0 || len(tx.Heartbeats) > types.MaxRametronHeartbeatsPerTx {
+			return TxMalformedError
+		}
+		sigs = append(sigs, tx.Guardian.Signature)
+		for _, heartbeat := range tx.Heartbeats {
+			if heartbeat == nil {
+				return TxMalformedError
+			}
+			sigs = append(sigs, heartbeat.Signature)
+		}
+	case *types.SmartContractTx:
+		if tx.GasPrice == nil || tx.GasPrice.Cmp(new(big.Int).SetUint64(types.MinimumGasPrice)) < 0 {
+			return TxFeeTooLowError
+		}
+		if tx.GasLimit > types.MaximumTxGasLimit {
+			return TxGasLimitTooHighError
+		}
+		sigs = append(sigs, tx.From.Signature)
+	default:
+		return TxTypeNotAllowedError
+	}
+
+	if _, ok := tx.(*types.SmartContractTx); !ok && !isFeeAboveFloor(fee) {
+		return TxFeeTooLowError
+	}
+
+	for _, sig := range sigs {
+		if !isSignatureWellFormed(sig) {
+			return TxBadSignatureError
+		}
+	}
+
+	return nil
+}
+
+// inputSignatures returns the signatures of the input, which are the individual signatures
+// of the signers for a multi-signature input.
+func inputSignatures(input types.TxInput) []*crypto.Signature {
+	if input.MultiSig == nil {
+		return []*crypto.Signature{input.Signature}
+	}
+	if len(input.MultiSig.Signatures) == 0 {
+		return []*crypto.Signature{nil}
+	}
+	return input.MultiSig.Signatures
+}
+
+// isFeeAboveFloor checks the fee against the lowest base fee. The check is stateless, the base fee
+// of the next block is only checked by the tx executors when the transaction is screened against the
+// state, and it never falls below the lowest base fee.
+func isFeeAboveFloor(fee types.Coins) bool {
+	fee = fee.NoNil()
+	return fee.PandoWei.Sign() == 0 && fee.PTXWei.Cmp(types.MinimumBaseFee()) >= 0
+}
+
+// isSignatureWellFormed checks the signature has the format expected by the public key
+// recovery, without performing the recovery.
+func isSignatureWellFormed(sig *crypto.Signature) bool {
+	if sig == nil || sig.IsEmpty() {
+		return false
+	}
+	raw := sig.ToBytes()
+	return len(raw) == signatureLength && raw[signatureLength-1] < 4
+}
@@ -0,0 +1,86 @@
+package mempool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecheckTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(TxMalformedError, precheckTransaction(common.Bytes{}))
+	assert.Equal(TxMalformedError, precheckTransaction(common.Bytes("not a tx")))
+	assert.Equal(TxTooLargeError, precheckTransaction(make(common.Bytes, MaxTxSize+1)))
+
+	assert.Nil(precheckTransaction(createTestSendTx(t, types.MinimumTransactionFeePTXWei, true)))
+	assert.Equal(TxFeeTooLowError, precheckTransaction(createTestSendTx(t, types.MinimumTransactionFeePTXWei-1, true)))
+	assert.Equal(TxBadSignatureError, precheckTransaction(createTestSendTx(t, types.MinimumTransactionFeePTXWei, false)))
+
+	coinbaseTx := &types.CoinbaseTx{
+		Proposer: types.TxInput{Address: common.HexToAddress("0x1")},
+	}
+	raw, err := types.TxToBytes(coinbaseTx)
+	require.Nil(t, err)
+	assert.Equal(TxTypeNotAllowedError, precheckTransaction(raw))
+
+	assert.Nil(precheckTransaction(createTestSmartContractTx(t, types.MinimumGasPrice, types.MaximumTxGasLimit)))
+	assert.Equal(TxFeeTooLowError, precheckTransaction(createTestSmartContractTx(t, types.MinimumGasPrice-1, types.MaximumTxGasLimit)))
+	assert.Equal(TxGasLimitTooHighError, precheckTransaction(createTestSmartContractTx(t, types.MinimumGasPrice, types.MaximumTxGasLimit+1)))
+}
+
+func createTestSendTx(t *testing.T, fee uint64, signed bool) common.Bytes {
+	privKey, _, err := crypto.GenerateKeyPair()
+	require.Nil(t, err)
+
+	tx := &types.SendTx{
+		Fee: types.NewCoins(0, 0),
+		Inputs: []types.TxInput{{
+			Address:  privKey.PublicKey().Address(),
+			Coins:    types.NewCoins(0, 100),
+			Sequence: 1,
+		}},
+		Outputs: []types.TxOutput{{
+			Address: common.HexToAddress("0x2"),
+			Coins:   types.NewCoins(0, 100),
+		}},
+	}
+	tx.Fee.PTXWei = new(big.Int).SetUint64(fee)
+	if signed {
+		sig, err := privKey.Sign(tx.SignBytes("testnet"))
+		require.Nil(t, err)
+		tx.SetSignature(privKey.PublicKey().Address(), sig)
+	}
+
+	raw, err := types.TxToBytes(tx)
+	require.Nil(t, err)
+	return raw
+}
+
+func createTestSmartContractTx(t *testing.T, gasPrice uint64, gasLimit uint64) common.Bytes {
+	privKey, _, err := crypto.GenerateKeyPair()
+	require.Nil(t, err)
+
+	tx := &types.SmartContractTx{
+		From: types.TxInput{
+			Address:  privKey.PublicKey().Address(),
+			Coins:    types.NewCoins(0, 0),
+			Sequence: 1,
+		},
+		To:       types.TxOutput{Address: common.HexToAddress("0x2")},
+		GasLimit: gasLimit,
+		GasPrice: new(big.Int).SetUint64(gasPrice),
+	}
+	sig, err := privKey.Sign(tx.SignBytes("testnet"))
+	require.Nil(t, err)
+	tx.SetSignature(privKey.PublicKey().Address(), sig)
+
+	raw, err := types.TxToBytes(tx)
+	require.Nil(t, err)
+	return raw
+}
@@ -0,0 +1,202 @@
+package mempool
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/metrics"
+	"github.com/pandotoken/pando/core"
+)
+
+var (
+	mempoolEvictedCounter      = metrics.NewRegisteredCounter("mempool/evicted", nil)
+	mempoolRejectedFullCounter = metrics.NewRegisteredCounter("mempool/rejected/full", nil)
+)
+
+// RankedTx is a pending transaction with its rank in the order the transactions are reaped
+// for the next blocks
+type RankedTx struct {
+	Rank              int               `json:"rank"`
+	Hash              string            `json:"hash"`
+	Sender            common.Address    `json:"sender"`
+	Sequence          common.JSONUint64 `json:"sequence"`
+	EffectiveGasPrice *common.JSONBig   `json:"effective_gas_price"`
+	InsertedAt        time.Time         `json:"inserted_at"`
+}
+
+// MaxSize returns the maximum number of transactions in the Mempool
+func (mp *Mempool) MaxSize() int {
+	return mp.maxSize
+}
+
+// GetRankedTransactions returns the pending transactions ranked by priority, i.e. in the order the
+// proposer reaps them. The sender with the highest priced next transaction goes first, and the
+// transactions of a sender are always ranked by sequence. maxNumTxs <= 0 means uncapped.
+func (mp *Mempool) GetRankedTransactions(maxNumTxs int) []RankedTx {
+	mp.mutex.Lock()
+	pending := mp.pendingTxsUnsafe()
+	mp.mutex.Unlock()
+
+	return rankTransactions(pending, maxNumTxs)
+}
+
+// pendingTxsUnsafe takes a snapshot of the pending transactions. The caller needs to hold the lock.
+func (mp *Mempool) pendingTxsUnsafe() []pendingTx {
+	pending := []pendingTx{}
+	txgElemList := mp.candidateTxs.ElementList()
+	for _, txgElem := range *txgElemList {
+		txg := txgElem.(*mempoolTransactionGroup)
+		txElemList := txg.txs.ElementList()
+		for _, txElem := range *txElemList {
+			tx := txElem.(*mempoolTransaction)
+			pending = append(pending, pendingTx{
+				rawTx:      tx.rawTransaction,
+				sender:     tx.txInfo.Address,
+				sequence:   tx.txInfo.Sequence,
+				gasPrice:   tx.txInfo.EffectiveGasPrice,
+				insertedAt: tx.insertedAt,
+			})
+		}
+	}
+	return pending
+}
+
+// evictUnsafe makes room for the incoming transaction by evicting the pending transaction with the
+// lowest effective gas price, which needs to be strictly lower than the price of the incoming one.
+// Only the last transaction of a sender can be evicted, since evicting an earlier one would leave a
+// gap in its sequences, and the transactions of the incoming sender are never evicted. Among the
+// transactions with the same price, the most recently inserted one is evicted. The caller needs to
+// hold the lock.
+func (mp *Mempool) evictUnsafe(incoming *core.TxInfo) bool {
+	var victimGroup *mempoolTransactionGroup
+	var victim *mempoolTransaction
+	txgElemList := mp.candidateTxs.ElementList()
+	for _, txgElem := range *txgElemList {
+		txg := txgElem.(*mempoolTransactionGroup)
+		if txg.address == incoming.Address {
+			continue
+		}
+		last := txg.LastTx()
+		if last == nil {
+			continue
+		}
+		if victim == nil {
+			victimGroup, victim = txg, last
+			continue
+		}
+		cmp := last.txInfo.EffectiveGasPrice.Cmp(victim.txInfo.EffectiveGasPrice)
+		if cmp < 0 || (cmp == 0 && last.insertedAt.After(victim.insertedAt)) {
+			victimGroup, victim = txg, last
+		}
+	}
+
+	if victim == nil || incoming.EffectiveGasPrice.Cmp(victim.txInfo.EffectiveGasPrice) <= 0 {
+		return false
+	}
+
+	// The last transaction is never the head of a group with other transactions, so the priority
+	// of the group does not change unless it becomes empty
+	victimGroup.txs.Remove(victim.GetIndex())
+	if victimGroup.IsEmpty() {
+		delete(mp.addressToTxGroup, victimGroup.address)
+		mp.candidateTxs.Remove(victimGroup.GetIndex())
+	}
+	mp.size--
+	mp.txBookeepper.markAbandoned(victim.rawTransaction)
+	mempoolEvictedCounter.Inc(1)
+	traceTx(victim.rawTransaction, "evicted", "effective gas price: %v, replaced by a tx of %v with effective gas price %v",
+		victim.txInfo.EffectiveGasPrice, incoming.Address.Hex(), incoming.EffectiveGasPrice)
+
+	logger.Debugf("Evicted tx: %v, txInfo: %v, to make room for the tx of %v with effective gas price %v",
+		hex.EncodeToString(victim.rawTransaction), victim.txInfo, incoming.Address.Hex(), incoming.EffectiveGasPrice)
+	return true
+}
+
+// rankedSender is the cursor over the pending transactions of a sender, ordered by sequence
+type rankedSender struct {
+	txs  []*pendingTx
+	next int
+}
+
+func (rs *rankedSender) head() *pendingTx {
+	return rs.txs[rs.next]
+}
+
+// rankedSenderHeap orders the senders by the price of their next transaction. The ties go to the
+// transaction inserted first, and then to the lower sender address, to keep the ranking stable.
+type rankedSenderHeap []*rankedSender
+
+func (h rankedSenderHeap) Len() int { return len(h) }
+
+func (h rankedSenderHeap) Less(i, j int) bool {
+	txi, txj := h[i].head(), h[j].head()
+	if cmp := txi.gasPrice.Cmp(txj.gasPrice); cmp != 0 {
+		return cmp > 0
+	}
+	if !txi.insertedAt.Equal(txj.insertedAt) {
+		return txi.insertedAt.Before(txj.insertedAt)
+	}
+	return bytes.Compare(txi.sender[:], txj.sender[:]) < 0
+}
+
+func (h rankedSenderHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *rankedSenderHeap) Push(x interface{}) { *h = append(*h, x.(*rankedSender)) }
+
+func (h *rankedSenderHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	rs := old[n-1]
+	*h = old[:n-1]
+	return rs
+}
+
+// rankTransactions ranks the pending transactions in the order they are reaped
+func rankTransactions(pending []pendingTx, maxNumTxs int) []RankedTx {
+	bySender := make(map[common.Address]*rankedSender)
+	senders := &rankedSenderHeap{}
+	for i := range pending {
+		tx := &pending[i]
+		rs, ok := bySender[tx.sender]
+		if !ok {
+			rs = &rankedSender{}
+			bySender[tx.sender] = rs
+			*senders = append(*senders, rs)
+		}
+		rs.txs = append(rs.txs, tx)
+	}
+	for _, rs := range *senders {
+		txs := rs.txs
+		sort.Slice(txs, func(i, j int) bool { return txs[i].sequence < txs[j].sequence })
+	}
+	heap.Init(senders)
+
+	if maxNumTxs <= 0 || maxNumTxs > len(pending) {
+		maxNumTxs = len(pending)
+	}
+	ranked := make([]RankedTx, 0, maxNumTxs)
+	for len(ranked) < maxNumTxs {
+		rs := (*senders)[0]
+		tx := rs.head()
+		ranked = append(ranked, RankedTx{
+			Rank:              len(ranked) + 1,
+			Hash:              "0x" + getTransactionHash(tx.rawTx),
+			Sender:            tx.sender,
+			Sequence:          common.JSONUint64(tx.sequence),
+			EffectiveGasPrice: (*common.JSONBig)(new(big.Int).Set(tx.gasPrice)),
+			InsertedAt:        tx.insertedAt,
+		})
+		rs.next++
+		if rs.next < len(rs.txs) {
+			heap.Fix(senders, 0)
+		} else {
+			heap.Pop(senders)
+		}
+	}
+	return ranked
+}
@@ -0,0 +1,98 @@
+package mempool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMempoolEviction(t *testing.T) {
+	assert := assert.New(t)
+
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	carol := common.HexToAddress("0x3")
+	newTxInfo := func(sender common.Address, seq uint64, price int64) *core.TxInfo {
+		return &core.TxInfo{Address: sender, Sequence: seq, EffectiveGasPrice: big.NewInt(price)}
+	}
+
+	mp := CreateMempool(nil, nil)
+	mp.maxSize = 3
+	mp.addTxUnsafe(common.Bytes("alice1"), newTxInfo(alice, 1, 10))
+	mp.addTxUnsafe(common.Bytes("alice2"), newTxInfo(alice, 2, 5))
+	mp.addTxUnsafe(common.Bytes("bob1"), newTxInfo(bob, 1, 3))
+	assert.Equal(3, mp.Size())
+
+	// Only a higher priced transaction can take the place of the lowest priced one
+	assert.False(mp.evictUnsafe(newTxInfo(carol, 1, 3)))
+	assert.Equal(3, mp.Size())
+	assert.True(mp.evictUnsafe(newTxInfo(carol, 1, 4)))
+	assert.Equal(2, mp.Size())
+	_, ok := mp.addressToTxGroup[bob]
+	assert.False(ok)
+	mp.addTxUnsafe(common.Bytes("carol1"), newTxInfo(carol, 1, 4))
+
+	// The transactions of the incoming sender are never evicted, so alice2 makes room for carol3
+	// although carol1 is priced lower
+	mp.addTxUnsafe(common.Bytes("carol2"), newTxInfo(carol, 2, 50))
+	assert.True(mp.evictUnsafe(newTxInfo(carol, 3, 100)))
+	assert.Equal(3, mp.Size())
+	hashes := []string{}
+	for _, tx := range mp.GetRankedTransactions(0) {
+		hashes = append(hashes, tx.Hash)
+	}
+	assert.Equal(testTxHashes(common.Bytes("alice1"), common.Bytes("carol1"), common.Bytes("carol2")), hashes)
+
+	assert.False(mp.evictUnsafe(newTxInfo(alice, 2, 50)))
+}
+
+func TestRankTransactions(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	alice := common.HexToAddress("0x1")
+	bob := common.HexToAddress("0x2")
+	carol := common.HexToAddress("0x3")
+
+	newTx := func(raw string, sender common.Address, seq uint64, price int64, insertedAt time.Time) pendingTx {
+		return pendingTx{
+			rawTx:      common.Bytes(raw),
+			sender:     sender,
+			sequence:   seq,
+			gasPrice:   big.NewInt(price),
+			insertedAt: insertedAt,
+		}
+	}
+	pending := []pendingTx{
+		newTx("alice2", alice, 2, 90, now),
+		newTx("alice1", alice, 1, 30, now),
+		newTx("bob1", bob, 1, 40, now),
+		newTx("carol1", carol, 1, 40, now.Add(-time.Second)),
+		newTx("carol2", carol, 2, 10, now),
+	}
+
+	hashes := func(ranked []RankedTx) []string {
+		h := []string{}
+		for i, tx := range ranked {
+			assert.Equal(i+1, tx.Rank)
+			h = append(h, tx.Hash)
+		}
+		return h
+	}
+
+	// The transactions of a sender are ranked by sequence, and the ties go to the earlier transaction
+	ranked := rankTransactions(pending, 0)
+	assert.Equal(testTxHashes(common.Bytes("carol1"), common.Bytes("bob1"), common.Bytes("alice1"),
+		common.Bytes("alice2"), common.Bytes("carol2")), hashes(ranked))
+	assert.Equal(common.JSONUint64(1), ranked[0].Sequence)
+	assert.Equal(int64(40), ranked[0].EffectiveGasPrice.ToInt().Int64())
+
+	ranked = rankTransactions(pending, 2)
+	assert.Equal(testTxHashes(common.Bytes("carol1"), common.Bytes("bob1")), hashes(ranked))
+
+	assert.Equal(0, len(rankTransactions([]pendingTx{}, 10)))
+}
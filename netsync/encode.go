@@ -7,9 +7,36 @@ import (
 
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/dispatcher"
+	p2pcmn "github.com/pandotoken/pando/p2pl/common"
 	"github.com/pandotoken/pando/rlp"
 )
 
+// maxFieldSize caps the size of any single field of the small messages, e.g.
+// hashes, signatures and public keys
+const maxFieldSize = 64 * 1024 // 64 KBytes
+
+var (
+	// messageLimits bounds the sync messages, the payloads are capped again
+	// when they are decoded according to their channels
+	messageLimits = rlp.Limits{
+		MaxInputSize:  p2pcmn.MaxBlockMessageSize,
+		MaxStringSize: p2pcmn.MaxBlockMessageSize,
+	}
+
+	// blockLimits bounds the blocks and proposals, a single string of a block
+	// is at most a transaction
+	blockLimits = rlp.Limits{
+		MaxInputSize:  p2pcmn.MaxBlockMessageSize,
+		MaxStringSize: p2pcmn.MaxNormalMessageSize,
+	}
+
+	// normalLimits bounds the votes and headers
+	normalLimits = rlp.Limits{
+		MaxInputSize:  p2pcmn.MaxNormalMessageSize,
+		MaxStringSize: maxFieldSize,
+	}
+)
+
 // type MessageIDEnum uint8
 
 // const (
@@ -56,19 +83,19 @@ func decodeMessage(raw common.Bytes) (interface{}, error) {
 	}
 	if msgID == common.MessageIDInvRequest {
 		data := dispatcher.InventoryRequest{}
-		err = rlp.DecodeBytes(raw[1:], &data)
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, messageLimits)
 		return data, err
 	} else if msgID == common.MessageIDInvResponse {
 		data := dispatcher.InventoryResponse{}
-		err = rlp.DecodeBytes(raw[1:], &data)
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, messageLimits)
 		return data, err
 	} else if msgID == common.MessageIDDataRequest {
 		data := dispatcher.DataRequest{}
-		err = rlp.DecodeBytes(raw[1:], &data)
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, messageLimits)
 		return data, err
 	} else if msgID == common.MessageIDDataResponse {
 		data := dispatcher.DataResponse{}
-		err = rlp.DecodeBytes(raw[1:], &data)
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, messageLimits)
 		return data, err
 	} else {
 		return nil, fmt.Errorf("Unknown message ID: %v", msgID)
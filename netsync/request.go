@@ -604,6 +604,13 @@ func (rm *RequestManager) sendBlocksRequest(peerID string, entries []string) {
 		"peer":            peerID,
 	}).Debug("Sending data request from header")
 	rm.syncMgr.dispatcher.GetData([]string{peerID}, request)
+
+	util.Trace(util.TracePeer, peerID, "sync", "blocks_requested", "blocks: %v", entries)
+	if util.IsTracing(util.TraceBlock) {
+		for _, hash := range entries {
+			util.Trace(util.TraceBlock, hash, "sync", "requested", "from peer %v", peerID)
+		}
+	}
 }
 
 func (rm *RequestManager) removeEl(el *list.Element) {
@@ -0,0 +1,669 @@
+package netsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/p2p"
+	p2ptypes "github.com/pandotoken/pando/p2p/types"
+	"github.com/pandotoken/pando/p2pl"
+	p2pcmn "github.com/pandotoken/pando/p2pl/common"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/snapshot"
+)
+
+const (
+	// SnapshotChunkSize is the size of the chunks a snapshot is split into for download
+	SnapshotChunkSize = 512 * 1024 // 512 KBytes
+
+	// maxSnapshotChunks caps the number of chunks so that the manifest fits in a message
+	maxSnapshotChunks = (p2pcmn.MaxNormalMessageSize - maxFieldSize) / (common.HashLength + 1)
+
+	maxInflightSnapshotChunks = 8
+	maxSnapshotChunkAttempts  = 5
+	snapshotChunkTimeout      = 30 * time.Second
+
+	// snapshotRefreshInterval is how often the serving folder is checked for a newer snapshot
+	snapshotRefreshInterval = 1 * time.Minute
+	// snapshotSettleTime is how long a snapshot file needs to stay unmodified before it is
+	// served, so that the snapshots still being exported are skipped
+	snapshotSettleTime = 1 * time.Minute
+)
+
+var (
+	// snapshotManifestWait is how long the manifests are collected from the peers
+	snapshotManifestWait = 10 * time.Second
+	// snapshotRetryInterval is how long to wait before asking the peers for the manifests again
+	snapshotRetryInterval = 10 * time.Second
+)
+
+// ErrNoSnapshotPeers is returned when no peer serves a snapshot to download
+var ErrNoSnapshotPeers = errors.New("No snapshot served by enough peers")
+
+// snapshotLimits bounds the snapshot messages, a single string is at most a chunk
+var snapshotLimits = rlp.Limits{
+	MaxInputSize:  p2pcmn.MaxNormalMessageSize,
+	MaxStringSize: SnapshotChunkSize,
+}
+
+// SnapshotManifestRequest asks a peer for the manifest of the latest snapshot it serves. Peers
+// only reply if their snapshot is above the given height.
+type SnapshotManifestRequest struct {
+	Height uint64
+}
+
+// SnapshotManifest describes a snapshot served by a peer
+type SnapshotManifest struct {
+	Height      uint64
+	BlockHash   common.Hash // hash of the block the snapshot is taken at
+	Size        uint64
+	ChunkHashes []common.Hash
+}
+
+// ID returns the hash identifying the manifest
+func (m *SnapshotManifest) ID() common.Hash {
+	raw, _ := rlp.EncodeToBytes(m)
+	return crypto.Keccak256Hash(raw)
+}
+
+// Validate checks that the chunk hashes cover the snapshot
+func (m *SnapshotManifest) Validate() error {
+	if m.Size == 0 {
+		return errors.New("Empty snapshot")
+	}
+	numChunks := (m.Size + SnapshotChunkSize - 1) / SnapshotChunkSize
+	if numChunks > maxSnapshotChunks {
+		return fmt.Errorf("Snapshot has too many chunks: %v", numChunks)
+	}
+	if uint64(len(m.ChunkHashes)) != numChunks {
+		return fmt.Errorf("Expected %v chunk hashes, got %v", numChunks, len(m.ChunkHashes))
+	}
+	return nil
+}
+
+func (m *SnapshotManifest) String() string {
+	return fmt.Sprintf("SnapshotManifest{Height: %v, BlockHash: %v, Size: %v, Chunks: %v}",
+		m.Height, m.BlockHash.Hex(), m.Size, len(m.ChunkHashes))
+}
+
+// SnapshotChunkRequest asks a peer for a chunk of the snapshot it serves
+type SnapshotChunkRequest struct {
+	ManifestID common.Hash
+	Index      uint64
+}
+
+// SnapshotChunk is a chunk of a snapshot
+type SnapshotChunk struct {
+	ManifestID common.Hash
+	Index      uint64
+	Data       common.Bytes
+}
+
+type snapshotManifestMessage struct {
+	peerID   string
+	manifest *SnapshotManifest
+}
+
+type snapshotChunkMessage struct {
+	peerID string
+	chunk  *SnapshotChunk
+}
+
+// servedSnapshot is the snapshot file served to the peers
+type servedSnapshot struct {
+	filePath   string
+	modTime    time.Time
+	manifest   *SnapshotManifest
+	manifestID common.Hash
+}
+
+var _ p2p.MessageHandler = (*SnapshotManager)(nil)
+
+// SnapshotManager exchanges the snapshots with the peers. It serves the latest snapshot exported
+// to the serving folder, and allows a fresh node to download the latest snapshot from the peers
+// instead of syncing all the blocks since the genesis.
+type SnapshotManager struct {
+	networkOld p2p.Network
+	network    p2pl.Network
+	serveDir   string
+
+	mu     sync.Mutex
+	served *servedSnapshot
+
+	manifests chan snapshotManifestMessage
+	chunks    chan snapshotChunkMessage
+}
+
+// NewSnapshotManager creates an instance of SnapshotManager, snapshots are not served if serveDir is empty
+func NewSnapshotManager(networkOld p2p.Network, network p2pl.Network, serveDir string) *SnapshotManager {
+	sm := &SnapshotManager{
+		serveDir:  serveDir,
+		manifests: make(chan snapshotManifestMessage, 64),
+		chunks:    make(chan snapshotChunkMessage, 2*maxInflightSnapshotChunks),
+	}
+	if networkOld != nil && !reflect.ValueOf(networkOld).IsNil() {
+		sm.networkOld = networkOld
+		networkOld.RegisterMessageHandler(sm)
+	}
+	if network != nil && !reflect.ValueOf(network).IsNil() {
+		sm.network = network
+		network.RegisterMessageHandler(sm)
+	}
+	return sm
+}
+
+// Start starts refreshing the served snapshot
+func (sm *SnapshotManager) Start(ctx context.Context) {
+	if sm.serveDir == "" {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(snapshotRefreshInterval)
+		defer ticker.Stop()
+		for {
+			sm.refreshServedSnapshot()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// GetChannelIDs implements the p2p.MessageHandler interface
+func (sm *SnapshotManager) GetChannelIDs() []common.ChannelIDEnum {
+	return []common.ChannelIDEnum{
+		common.ChannelIDSnapshot,
+	}
+}
+
+// ParseMessage implements the p2p.MessageHandler interface
+func (sm *SnapshotManager) ParseMessage(peerID string, channelID common.ChannelIDEnum,
+	rawMessageBytes common.Bytes) (p2ptypes.Message, error) {
+	message := p2ptypes.Message{
+		PeerID:    peerID,
+		ChannelID: channelID,
+	}
+	data, err := decodeSnapshotMessage(rawMessageBytes)
+	message.Content = data
+	return message, err
+}
+
+// EncodeMessage implements the p2p.MessageHandler interface
+func (sm *SnapshotManager) EncodeMessage(message interface{}) (common.Bytes, error) {
+	return encodeSnapshotMessage(message)
+}
+
+// HandleMessage implements the p2p.MessageHandler interface
+func (sm *SnapshotManager) HandleMessage(msg p2ptypes.Message) error {
+	switch content := msg.Content.(type) {
+	case SnapshotManifestRequest:
+		sm.handleManifestRequest(msg.PeerID, &content)
+	case SnapshotChunkRequest:
+		sm.handleChunkRequest(msg.PeerID, &content)
+	case SnapshotManifest:
+		// Responses are dropped when nobody is syncing, or the syncing is falling behind
+		select {
+		case sm.manifests <- snapshotManifestMessage{peerID: msg.PeerID, manifest: &content}:
+		default:
+		}
+	case SnapshotChunk:
+		select {
+		case sm.chunks <- snapshotChunkMessage{peerID: msg.PeerID, chunk: &content}:
+		default:
+		}
+	default:
+		logger.Warnf("Received unknown snapshot message: %v", msg)
+	}
+	return nil
+}
+
+func (sm *SnapshotManager) handleManifestRequest(peerID string, req *SnapshotManifestRequest) {
+	served := sm.getServedSnapshot()
+	if served == nil || served.manifest.Height <= req.Height {
+		return
+	}
+	sm.send(peerID, *served.manifest)
+}
+
+func (sm *SnapshotManager) handleChunkRequest(peerID string, req *SnapshotChunkRequest) {
+	served := sm.getServedSnapshot()
+	if served == nil || served.manifestID != req.ManifestID ||
+		req.Index >= uint64(len(served.manifest.ChunkHashes)) {
+		return
+	}
+
+	data, err := readSnapshotChunk(served.filePath, served.manifest.Size, req.Index)
+	if err != nil {
+		logger.Warnf("Failed to read chunk %v of snapshot %v: %v", req.Index, served.filePath, err)
+		return
+	}
+	sm.send(peerID, SnapshotChunk{
+		ManifestID: req.ManifestID,
+		Index:      req.Index,
+		Data:       data,
+	})
+}
+
+func (sm *SnapshotManager) getServedSnapshot() *servedSnapshot {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.served
+}
+
+// refreshServedSnapshot serves the snapshot of the highest height in the serving folder
+func (sm *SnapshotManager) refreshServedSnapshot() {
+	files, err := ioutil.ReadDir(sm.serveDir)
+	if err != nil {
+		logger.Warnf("Failed to read snapshot folder %v: %v", sm.serveDir, err)
+		return
+	}
+
+	var latestPath string
+	var latestInfo os.FileInfo
+	var latestHeight uint64
+	for _, file := range files {
+		if file.IsDir() || time.Since(file.ModTime()) < snapshotSettleTime {
+			continue
+		}
+		filePath := path.Join(sm.serveDir, file.Name())
+		header := snapshot.LoadSnapshotCheckpointHeader(filePath)
+		if header == nil {
+			continue
+		}
+		if latestInfo == nil || header.Height > latestHeight {
+			latestPath, latestInfo, latestHeight = filePath, file, header.Height
+		}
+	}
+	if latestInfo == nil {
+		return
+	}
+
+	served := sm.getServedSnapshot()
+	if served != nil && served.filePath == latestPath && served.modTime.Equal(latestInfo.ModTime()) {
+		return
+	}
+
+	manifest, err := NewSnapshotManifest(latestPath)
+	if err != nil {
+		logger.Warnf("Failed to create the manifest of snapshot %v: %v", latestPath, err)
+		return
+	}
+
+	sm.mu.Lock()
+	sm.served = &servedSnapshot{
+		filePath:   latestPath,
+		modTime:    latestInfo.ModTime(),
+		manifest:   manifest,
+		manifestID: manifest.ID(),
+	}
+	sm.mu.Unlock()
+
+	logger.Infof("Serving snapshot %v, %v", latestPath, manifest)
+}
+
+// NewSnapshotManifest creates the manifest of the given snapshot file
+func NewSnapshotManifest(filePath string) (*SnapshotManifest, error) {
+	header := snapshot.LoadSnapshotCheckpointHeader(filePath)
+	if header == nil {
+		return nil, fmt.Errorf("Failed to load the snapshot header")
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	manifest := &SnapshotManifest{
+		Height:    header.Height,
+		BlockHash: header.Hash(),
+	}
+	buf := make([]byte, SnapshotChunkSize)
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 {
+			manifest.Size += uint64(n)
+			manifest.ChunkHashes = append(manifest.ChunkHashes, crypto.Keccak256Hash(buf[:n]))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func readSnapshotChunk(filePath string, size uint64, index uint64) (common.Bytes, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	offset := index * SnapshotChunkSize
+	length := size - offset
+	if length > SnapshotChunkSize {
+		length = SnapshotChunkSize
+	}
+	data := make([]byte, length)
+	if _, err := file.ReadAt(data, int64(offset)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Sync downloads the latest snapshot above the given height served by at least minPeers peers to the
+// given file. The downloaded snapshot is checked by verify before it is moved to the file, and the next
+// best snapshot is tried if it fails. Sync keeps retrying until the context is done.
+func (sm *SnapshotManager) Sync(ctx context.Context, height uint64, minPeers int, filePath string,
+	verify func(filePath string) error) (*SnapshotManifest, error) {
+	downloadPath := filePath + ".download"
+	defer os.Remove(downloadPath)
+
+	rejected := make(map[common.Hash]bool)
+	for {
+		candidates := sm.collectManifests(ctx, height)
+		manifest, peerIDs := selectSnapshotManifest(candidates, minPeers, rejected)
+		if manifest == nil {
+			logger.Infof("Waiting for a snapshot above height %v served by at least %v peers", height, minPeers)
+			select {
+			case <-ctx.Done():
+				return nil, ErrNoSnapshotPeers
+			case <-time.After(snapshotRetryInterval):
+			}
+			continue
+		}
+
+		logger.Infof("Downloading snapshot %v from %v peers", manifest, len(peerIDs))
+		err := sm.download(ctx, manifest, peerIDs, downloadPath)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			logger.Warnf("Failed to download snapshot %v: %v", manifest, err)
+			continue
+		}
+
+		if err := verify(downloadPath); err != nil {
+			logger.Warnf("Downloaded snapshot %v is invalid: %v", manifest, err)
+			rejected[manifest.ID()] = true
+			continue
+		}
+
+		if err := os.Rename(downloadPath, filePath); err != nil {
+			return nil, err
+		}
+		return manifest, nil
+	}
+}
+
+type snapshotCandidate struct {
+	manifest *SnapshotManifest
+	peerIDs  []string
+}
+
+// collectManifests asks all the peers for their snapshots, and groups the replies by manifest
+func (sm *SnapshotManager) collectManifests(ctx context.Context, height uint64) map[common.Hash]*snapshotCandidate {
+	for _, peerID := range sm.peers() {
+		sm.send(peerID, SnapshotManifestRequest{Height: height})
+	}
+
+	candidates := make(map[common.Hash]*snapshotCandidate)
+	timer := time.NewTimer(snapshotManifestWait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return candidates
+		case <-timer.C:
+			return candidates
+		case msg := <-sm.manifests:
+			if msg.manifest.Height <= height || msg.manifest.Validate() != nil {
+				continue
+			}
+			id := msg.manifest.ID()
+			candidate, ok := candidates[id]
+			if !ok {
+				candidate = &snapshotCandidate{manifest: msg.manifest}
+				candidates[id] = candidate
+			}
+			if !containsString(candidate.peerIDs, msg.peerID) {
+				candidate.peerIDs = append(candidate.peerIDs, msg.peerID)
+			}
+		}
+	}
+}
+
+// selectSnapshotManifest picks the snapshot of the highest height served by at least minPeers peers.
+// Among the snapshots of the same height, the one served by more peers is preferred.
+func selectSnapshotManifest(candidates map[common.Hash]*snapshotCandidate, minPeers int,
+	rejected map[common.Hash]bool) (*SnapshotManifest, []string) {
+	var best *snapshotCandidate
+	for id, candidate := range candidates {
+		if rejected[id] || len(candidate.peerIDs) < minPeers {
+			continue
+		}
+		if best == nil || candidate.manifest.Height > best.manifest.Height ||
+			(candidate.manifest.Height == best.manifest.Height && len(candidate.peerIDs) > len(best.peerIDs)) {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return best.manifest, best.peerIDs
+}
+
+type inflightSnapshotChunk struct {
+	peerID   string
+	deadline time.Time
+}
+
+// download fetches the chunks from the given peers in parallel. The chunks are checked against the
+// manifest, and the peers sending invalid chunks are no longer asked.
+func (sm *SnapshotManager) download(ctx context.Context, manifest *SnapshotManifest, peerIDs []string, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	manifestID := manifest.ID()
+	peerIDs = append([]string{}, peerIDs...)
+	queue := make([]uint64, len(manifest.ChunkHashes))
+	for i := range queue {
+		queue[i] = uint64(i)
+	}
+	attempts := make(map[uint64]int)
+	inflight := make(map[uint64]*inflightSnapshotChunk)
+	nextPeer := 0
+	done := 0
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for done < len(manifest.ChunkHashes) {
+		for len(inflight) < maxInflightSnapshotChunks && len(queue) > 0 {
+			if len(peerIDs) == 0 {
+				return errors.New("No peer left to download the snapshot from")
+			}
+			index := queue[0]
+			queue = queue[1:]
+			if attempts[index] >= maxSnapshotChunkAttempts {
+				return fmt.Errorf("Failed to download chunk %v after %v attempts", index, attempts[index])
+			}
+			attempts[index]++
+
+			peerID := peerIDs[nextPeer%len(peerIDs)]
+			nextPeer++
+			sm.send(peerID, SnapshotChunkRequest{ManifestID: manifestID, Index: index})
+			inflight[index] = &inflightSnapshotChunk{
+				peerID:   peerID,
+				deadline: time.Now().Add(snapshotChunkTimeout),
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-sm.chunks:
+			chunk := msg.chunk
+			req, ok := inflight[chunk.Index]
+			if !ok || chunk.ManifestID != manifestID || req.peerID != msg.peerID {
+				continue
+			}
+			delete(inflight, chunk.Index)
+			if crypto.Keccak256Hash(chunk.Data) != manifest.ChunkHashes[chunk.Index] {
+				logger.Warnf("Peer %v sent invalid chunk %v of snapshot %v", msg.peerID, chunk.Index, manifest)
+				peerIDs = removeString(peerIDs, msg.peerID)
+				queue = append(queue, chunk.Index)
+				continue
+			}
+			if _, err := file.WriteAt(chunk.Data, int64(chunk.Index*SnapshotChunkSize)); err != nil {
+				return err
+			}
+			done++
+			if done%100 == 0 {
+				logger.Infof("Downloaded %v/%v chunks of snapshot %v", done, len(manifest.ChunkHashes), manifest)
+			}
+		case now := <-ticker.C:
+			for index, req := range inflight {
+				if now.After(req.deadline) {
+					delete(inflight, index)
+					queue = append(queue, index)
+				}
+			}
+		}
+	}
+
+	return file.Sync()
+}
+
+// send sends the message to the peer through the network it is connected with
+func (sm *SnapshotManager) send(peerID string, content interface{}) bool {
+	message := p2ptypes.Message{
+		PeerID:    peerID,
+		ChannelID: common.ChannelIDSnapshot,
+		Content:   content,
+	}
+	if sm.networkOld != nil && sm.networkOld.PeerExists(peerID) {
+		return sm.networkOld.Send(peerID, message)
+	}
+	if sm.network != nil && sm.network.PeerExists(peerID) {
+		return sm.network.Send(peerID, message)
+	}
+	return false
+}
+
+// peers returns the peers of both networks
+func (sm *SnapshotManager) peers() []string {
+	peerIDs := []string{}
+	if sm.networkOld != nil {
+		peerIDs = append(peerIDs, sm.networkOld.Peers()...)
+	}
+	if sm.network != nil {
+		for _, peerID := range sm.network.Peers() {
+			if !containsString(peerIDs, peerID) {
+				peerIDs = append(peerIDs, peerID)
+			}
+		}
+	}
+	sort.Strings(peerIDs)
+	return peerIDs
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	result := []string{}
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func encodeSnapshotMessage(message interface{}) (common.Bytes, error) {
+	var buf bytes.Buffer
+	var msgID common.MessageIDEnum
+	switch message.(type) {
+	case SnapshotManifestRequest:
+		msgID = common.MessageIDSnapshotManifestRequest
+	case SnapshotManifest:
+		msgID = common.MessageIDSnapshotManifest
+	case SnapshotChunkRequest:
+		msgID = common.MessageIDSnapshotChunkRequest
+	case SnapshotChunk:
+		msgID = common.MessageIDSnapshotChunk
+	default:
+		return nil, errors.New("Unsupported message type")
+	}
+	err := rlp.Encode(&buf, msgID)
+	if err != nil {
+		return nil, err
+	}
+	err = rlp.Encode(&buf, message)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshotMessage(raw common.Bytes) (interface{}, error) {
+	if len(raw) <= 1 {
+		return nil, fmt.Errorf("Invalid message size")
+	}
+	var msgID common.MessageIDEnum
+	err := rlp.DecodeBytes(raw[:1], &msgID)
+	if err != nil {
+		return nil, err
+	}
+	switch msgID {
+	case common.MessageIDSnapshotManifestRequest:
+		data := SnapshotManifestRequest{}
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, snapshotLimits)
+		return data, err
+	case common.MessageIDSnapshotManifest:
+		data := SnapshotManifest{}
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, snapshotLimits)
+		return data, err
+	case common.MessageIDSnapshotChunkRequest:
+		data := SnapshotChunkRequest{}
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, snapshotLimits)
+		return data, err
+	case common.MessageIDSnapshotChunk:
+		data := SnapshotChunk{}
+		err = rlp.DecodeBytesWithLimits(raw[1:], &data, snapshotLimits)
+		return data, err
+	default:
+		return nil, fmt.Errorf("Unknown message ID: %v", msgID)
+	}
+}
@@ -0,0 +1,258 @@
+package netsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/p2p"
+	p2ptypes "github.com/pandotoken/pando/p2p/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotTestNetwork connects the snapshot managers of the test, the messages are encoded and
+// decoded as if they were sent over the wire
+type snapshotTestNetwork struct {
+	id       string
+	peers    map[string]*snapshotTestNetwork
+	handlers []p2p.MessageHandler
+}
+
+var _ p2p.Network = (*snapshotTestNetwork)(nil)
+
+func newSnapshotTestNetworks(ids ...string) map[string]*snapshotTestNetwork {
+	networks := make(map[string]*snapshotTestNetwork)
+	for _, id := range ids {
+		networks[id] = &snapshotTestNetwork{id: id, peers: networks}
+	}
+	return networks
+}
+
+func (n *snapshotTestNetwork) Start(ctx context.Context) error { return nil }
+func (n *snapshotTestNetwork) Wait()                           {}
+func (n *snapshotTestNetwork) Stop()                           {}
+func (n *snapshotTestNetwork) ID() string                      { return n.id }
+
+func (n *snapshotTestNetwork) Broadcast(message p2ptypes.Message) chan bool {
+	return n.BroadcastToNeighbors(message, len(n.peers))
+}
+
+func (n *snapshotTestNetwork) BroadcastToNeighbors(message p2ptypes.Message, maxNumPeersToBroadcast int) chan bool {
+	successes := make(chan bool, len(n.peers))
+	for _, peerID := range n.Peers() {
+		successes <- n.Send(peerID, message)
+	}
+	return successes
+}
+
+func (n *snapshotTestNetwork) Send(peerID string, message p2ptypes.Message) bool {
+	peer, ok := n.peers[peerID]
+	if !ok || peerID == n.id {
+		return false
+	}
+	raw, err := n.handlers[0].EncodeMessage(message.Content)
+	if err != nil {
+		return false
+	}
+	go func() {
+		for _, handler := range peer.handlers {
+			msg, err := handler.ParseMessage(n.id, message.ChannelID, raw)
+			if err == nil {
+				handler.HandleMessage(msg)
+			}
+		}
+	}()
+	return true
+}
+
+func (n *snapshotTestNetwork) Peers() []string {
+	peerIDs := []string{}
+	for id := range n.peers {
+		if id != n.id {
+			peerIDs = append(peerIDs, id)
+		}
+	}
+	return peerIDs
+}
+
+func (n *snapshotTestNetwork) PeerExists(peerID string) bool {
+	_, ok := n.peers[peerID]
+	return ok && peerID != n.id
+}
+
+func (n *snapshotTestNetwork) RegisterMessageHandler(messageHandler p2p.MessageHandler) {
+	n.handlers = append(n.handlers, messageHandler)
+}
+
+func newTestSnapshotManifest(data []byte, height uint64) *SnapshotManifest {
+	manifest := &SnapshotManifest{
+		Height: height,
+		Size:   uint64(len(data)),
+	}
+	for offset := 0; offset < len(data); offset += SnapshotChunkSize {
+		end := offset + SnapshotChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		manifest.ChunkHashes = append(manifest.ChunkHashes, crypto.Keccak256Hash(data[offset:end]))
+	}
+	return manifest
+}
+
+func serveTestSnapshot(t *testing.T, sm *SnapshotManager, filePath string, data []byte, manifest *SnapshotManifest) {
+	require.Nil(t, ioutil.WriteFile(filePath, data, 0600))
+	sm.served = &servedSnapshot{
+		filePath:   filePath,
+		manifest:   manifest,
+		manifestID: manifest.ID(),
+	}
+}
+
+func TestSnapshotMessageEncoding(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	manifest := newTestSnapshotManifest([]byte("snapshot"), 100)
+	for _, message := range []interface{}{
+		SnapshotManifestRequest{Height: 10},
+		*manifest,
+		SnapshotChunkRequest{ManifestID: manifest.ID(), Index: 3},
+		SnapshotChunk{ManifestID: manifest.ID(), Index: 3, Data: common.Bytes("chunk")},
+	} {
+		raw, err := encodeSnapshotMessage(message)
+		require.Nil(err)
+		decoded, err := decodeSnapshotMessage(raw)
+		require.Nil(err)
+		assert.Equal(message, decoded)
+	}
+
+	_, err := encodeSnapshotMessage("unsupported")
+	assert.NotNil(err)
+	_, err = decodeSnapshotMessage(common.Bytes{byte(common.MessageIDSnapshotChunk)})
+	assert.NotNil(err)
+
+	// Chunks larger than the chunk size are rejected
+	raw, err := encodeSnapshotMessage(SnapshotChunk{Data: make([]byte, SnapshotChunkSize+1)})
+	require.Nil(err)
+	_, err = decodeSnapshotMessage(raw)
+	assert.NotNil(err)
+}
+
+func TestSnapshotManifestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	manifest := newTestSnapshotManifest(make([]byte, 2*SnapshotChunkSize+1), 100)
+	assert.Equal(3, len(manifest.ChunkHashes))
+	assert.Nil(manifest.Validate())
+
+	manifest.ChunkHashes = manifest.ChunkHashes[:2]
+	assert.NotNil(manifest.Validate())
+	assert.NotNil((&SnapshotManifest{}).Validate())
+}
+
+func TestSelectSnapshotManifest(t *testing.T) {
+	assert := assert.New(t)
+
+	m1 := newTestSnapshotManifest([]byte("m1"), 100)
+	m2 := newTestSnapshotManifest([]byte("m2"), 200)
+	m3 := newTestSnapshotManifest([]byte("m3"), 200)
+	m4 := newTestSnapshotManifest([]byte("m4"), 300)
+	candidates := map[common.Hash]*snapshotCandidate{
+		m1.ID(): {manifest: m1, peerIDs: []string{"p1", "p2", "p3"}},
+		m2.ID(): {manifest: m2, peerIDs: []string{"p1", "p2"}},
+		m3.ID(): {manifest: m3, peerIDs: []string{"p3", "p4", "p5"}},
+		m4.ID(): {manifest: m4, peerIDs: []string{"p6"}},
+	}
+
+	// m4 is not served by enough peers, and m3 is served by more peers than m2
+	manifest, peerIDs := selectSnapshotManifest(candidates, 2, map[common.Hash]bool{})
+	assert.Equal(m3.ID(), manifest.ID())
+	assert.Equal([]string{"p3", "p4", "p5"}, peerIDs)
+
+	manifest, _ = selectSnapshotManifest(candidates, 2, map[common.Hash]bool{m3.ID(): true})
+	assert.Equal(m2.ID(), manifest.ID())
+
+	manifest, _ = selectSnapshotManifest(candidates, 1, map[common.Hash]bool{})
+	assert.Equal(m4.ID(), manifest.ID())
+
+	manifest, peerIDs = selectSnapshotManifest(candidates, 4, map[common.Hash]bool{})
+	assert.Nil(manifest)
+	assert.Nil(peerIDs)
+}
+
+func TestSnapshotManagerSync(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	snapshotManifestWait = 500 * time.Millisecond
+	snapshotRetryInterval = 500 * time.Millisecond
+
+	dir, err := ioutil.TempDir("", "snapshot_sync")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	data := make([]byte, 3*SnapshotChunkSize+100)
+	_, err = rand.Read(data)
+	require.Nil(err)
+	manifest := newTestSnapshotManifest(data, 1000)
+
+	networks := newSnapshotTestNetworks("client", "server1", "server2", "server3")
+	client := NewSnapshotManager(networks["client"], nil, "")
+	server1 := NewSnapshotManager(networks["server1"], nil, "")
+	server2 := NewSnapshotManager(networks["server2"], nil, "")
+	server3 := NewSnapshotManager(networks["server3"], nil, "")
+
+	// server3 claims to serve the same snapshot, but sends corrupted chunks
+	corrupted := append([]byte{}, data...)
+	for i := range corrupted {
+		corrupted[i] ^= 0xff
+	}
+	serveTestSnapshot(t, server1, path.Join(dir, "server1"), data, manifest)
+	serveTestSnapshot(t, server2, path.Join(dir, "server2"), data, manifest)
+	serveTestSnapshot(t, server3, path.Join(dir, "server3"), corrupted, manifest)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The first download is rejected by the verification, and the snapshot is not served by
+	// another group of peers
+	filePath := path.Join(dir, "snapshot")
+	verifyErr := errors.New("invalid snapshot")
+	_, err = client.Sync(ctx, 999, 3, filePath, func(string) error { return verifyErr })
+	assert.Equal(ErrNoSnapshotPeers, err)
+	_, err = os.Stat(filePath)
+	assert.True(os.IsNotExist(err))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	downloaded, err := client.Sync(ctx, 999, 3, filePath, func(downloadPath string) error {
+		raw, err := ioutil.ReadFile(downloadPath)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(data, raw) {
+			return verifyErr
+		}
+		return nil
+	})
+	require.Nil(err)
+	assert.Equal(manifest.ID(), downloaded.ID())
+	raw, err := ioutil.ReadFile(filePath)
+	require.Nil(err)
+	assert.True(bytes.Equal(data, raw))
+
+	// Snapshots not above the local height are ignored
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = client.Sync(ctx, 1000, 1, filePath, func(string) error { return nil })
+	assert.Equal(ErrNoSnapshotPeers, err)
+}
@@ -56,6 +56,8 @@ type SyncManager struct {
 
 	whitelist []string
 
+	wsCheckpoints *core.WeakSubjectivityCheckpoints
+
 	logger *log.Entry
 
 	voteCache *lru.Cache // Cache for votes
@@ -72,6 +74,8 @@ func NewSyncManager(chain *blockchain.Chain, cons core.ConsensusEngine, networkO
 		incoming:   make(chan p2ptypes.Message, viper.GetInt(common.CfgSyncMessageQueueSize)),
 
 		voteCache: voteCache,
+
+		wsCheckpoints: loadWeakSubjectivityCheckpoints(),
 	}
 	sm.requestMgr = NewRequestManager(sm, reporter)
 
@@ -100,6 +104,8 @@ func (sm *SyncManager) Start(ctx context.Context) {
 	sm.ctx = c
 	sm.cancel = cancel
 
+	sm.verifyWeakSubjectivity()
+
 	sm.requestMgr.Start(c)
 
 	sm.wg.Add(1)
@@ -177,6 +183,11 @@ func (sm *SyncManager) processMessage(message p2ptypes.Message) {
 		}
 	}
 
+	if util.IsTracing(util.TracePeer) {
+		util.Trace(util.TracePeer, message.PeerID, "sync", "message_received", "type: %T, channel: %v, allowed: %v",
+			message.Content, message.ChannelID, inboundAllowed)
+	}
+
 	switch content := message.Content.(type) {
 	case dispatcher.InventoryRequest:
 		sm.handleInvRequest(message.PeerID, &content)
@@ -531,11 +542,11 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 	case common.ChannelIDBlock:
 		maxReceivedHeight := uint64(0)
 		block := core.NewBlock()
-		err := rlp.DecodeBytes(data.Payload, block)
+		err := rlp.DecodeBytesWithLimits(data.Payload, block, blockLimits)
 		if err != nil {
 			//check if payload is blocks
 			blocks := &Blocks{}
-			err = rlp.DecodeBytes(data.Payload, blocks)
+			err = rlp.DecodeBytesWithLimits(data.Payload, blocks, blockLimits)
 			if err != nil {
 				m.logger.WithFields(log.Fields{
 					"channelID": data.ChannelID,
@@ -552,6 +563,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 					"block.Height": block.Height,
 					"peer":         peerID,
 				}).Debug("Received block")
+				traceReceivedBlock(peerID, block, "block")
 				m.handleBlock(block)
 				if block.Height > maxReceivedHeight {
 					maxReceivedHeight = block.Height
@@ -564,12 +576,13 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"block.Height": block.Height,
 				"peer":         peerID,
 			}).Debug("Received block")
+			traceReceivedBlock(peerID, block, "block")
 			m.handleBlock(block)
 			maxReceivedHeight = block.Height
 		}
 	case common.ChannelIDVote:
 		vote := core.Vote{}
-		err := rlp.DecodeBytes(data.Payload, &vote)
+		err := rlp.DecodeBytesWithLimits(data.Payload, &vote, normalLimits)
 		if err != nil {
 			m.logger.WithFields(log.Fields{
 				"channelID": data.ChannelID,
@@ -588,7 +601,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 		m.handleVote(vote)
 	case common.ChannelIDProposal:
 		proposal := &core.Proposal{}
-		err := rlp.DecodeBytes(data.Payload, proposal)
+		err := rlp.DecodeBytesWithLimits(data.Payload, proposal, blockLimits)
 		if err != nil {
 			m.logger.WithFields(log.Fields{
 				"channelID": data.ChannelID,
@@ -602,10 +615,11 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 			"proposal": proposal,
 			"peer":     peerID,
 		}).Debug("Received proposal")
+		traceReceivedBlock(peerID, proposal.Block, "proposal")
 		m.handleProposal(proposal)
 	case common.ChannelIDGuardian:
 		vote := &core.AggregatedVotes{}
-		err := rlp.DecodeBytes(data.Payload, vote)
+		err := rlp.DecodeBytesWithLimits(data.Payload, vote, normalLimits)
 		if err != nil {
 			m.logger.WithFields(log.Fields{
 				"channelID": data.ChannelID,
@@ -624,7 +638,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 		m.handleGuardianVote(vote)
 	case common.ChannelIDHeader:
 		headers := &Headers{}
-		err := rlp.DecodeBytes(data.Payload, headers)
+		err := rlp.DecodeBytesWithLimits(data.Payload, headers, normalLimits)
 		if err != nil {
 			m.logger.WithFields(log.Fields{
 				"channelID": data.ChannelID,
@@ -650,6 +664,15 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 	}
 }
 
+// traceReceivedBlock records the block received from the peer if the block or the peer is traced
+func traceReceivedBlock(peerID string, block *core.Block, source string) {
+	if block == nil {
+		return
+	}
+	util.Trace(util.TraceBlock, block.Hash().Hex(), "sync", "received", "%v from peer %v, height: %v", source, peerID, block.Height)
+	util.Trace(util.TracePeer, peerID, "sync", source+"_received", "block: %v, height: %v", block.Hash().Hex(), block.Height)
+}
+
 func (sm *SyncManager) handleProposal(p *core.Proposal) {
 	if p.Votes != nil {
 		for _, vote := range p.Votes.Votes() {
@@ -678,6 +701,15 @@ func (sm *SyncManager) handleHeader(header *core.BlockHeader, peerID []string) {
 		}
 	}
 
+	if err := sm.checkWeakSubjectivity(header.Height, header.Hash()); err != nil {
+		sm.logger.WithFields(log.Fields{
+			"block hash":   header.Hash().String(),
+			"block height": header.Height,
+			"peer":         peerID,
+		}).Warn(err.Error())
+		return
+	}
+
 	lfbHeight := sm.consensus.GetLastFinalizedBlock().Height
 	tipHeight := sm.consensus.GetTip(true).Height
 	if header.Height > lfbHeight && header.Height <= tipHeight+dispatcher.MaxInventorySize+1 {
@@ -710,6 +742,14 @@ func (sm *SyncManager) handleBlock(block *core.Block) {
 		return
 	}
 
+	if err := sm.checkWeakSubjectivity(block.Height, block.Hash()); err != nil {
+		sm.logger.WithFields(log.Fields{
+			"block hash":   block.Hash().String(),
+			"block height": block.Height,
+		}).Warn(err.Error())
+		return
+	}
+
 	sm.requestMgr.AddBlock(block)
 
 	p2pOpt := common.P2POptEnum(viper.GetInt(common.CfgP2POpt))
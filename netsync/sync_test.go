@@ -185,7 +185,7 @@ func NewMockConsensus(chain *blockchain.Chain, lfb *core.ExtendedBlock) *MockCon
 }
 
 // ID() string
-// PrivateKey() *crypto.PrivateKey
+// Signer() crypto.Signer
 // GetTip(includePendingBlockingLeaf bool) *ExtendedBlock
 // GetEpoch() uint64
 // GetLedger() Ledger
@@ -197,7 +197,7 @@ func (c *MockConsensus) ID() string {
 	return ""
 }
 
-func (c *MockConsensus) PrivateKey() *crypto.PrivateKey {
+func (c *MockConsensus) Signer() crypto.Signer {
 	return nil
 }
 
@@ -0,0 +1,86 @@
+package netsync
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// loadWeakSubjectivityCheckpoints loads the weak subjectivity checkpoints from the config
+func loadWeakSubjectivityCheckpoints() *core.WeakSubjectivityCheckpoints {
+	checkpoints, err := core.NewWeakSubjectivityCheckpoints(viper.GetStringSlice(common.CfgSyncWeakSubjectivityCheckpoints))
+	if err != nil {
+		logger.Fatalf("Failed to load the weak subjectivity checkpoints: %v", err)
+	}
+	return checkpoints
+}
+
+// checkWeakSubjectivity returns an error if the block conflicts with a weak subjectivity checkpoint
+func (sm *SyncManager) checkWeakSubjectivity(height uint64, hash common.Hash) error {
+	return sm.wsCheckpoints.Check(height, hash)
+}
+
+// verifyWeakSubjectivity verifies the local chain against the weak subjectivity checkpoints before
+// the sync starts. A node which has been offline for longer than the trust period can no longer tell
+// the canonical chain from a long-range attack, so it refuses to start until given a recent checkpoint.
+func (sm *SyncManager) verifyWeakSubjectivity() {
+	if sm.wsCheckpoints.IsEmpty() {
+		return
+	}
+
+	lfb := sm.consensus.GetLastFinalizedBlock()
+	if err := verifyFinalizedBlocks(sm.chain, sm.wsCheckpoints, lfb.Height); err != nil {
+		sm.logger.Fatalf("%v", err)
+	}
+
+	period := time.Duration(viper.GetInt64(common.CfgSyncWeakSubjectivityPeriodSecs)) * time.Second
+	if err := checkTrustPeriod(sm.wsCheckpoints, lfb, time.Now(), period); err != nil {
+		sm.logger.Fatalf("%v", err)
+	}
+
+	sm.logger.WithFields(log.Fields{
+		"checkpoints": len(sm.wsCheckpoints.List()),
+		"latest":      sm.wsCheckpoints.Latest(),
+	}).Info("Weak subjectivity checkpoints verified")
+}
+
+// verifyFinalizedBlocks returns an error if a finalized block in the local chain conflicts with a checkpoint
+func verifyFinalizedBlocks(chain *blockchain.Chain, checkpoints *core.WeakSubjectivityCheckpoints, lfbHeight uint64) error {
+	for _, checkpoint := range checkpoints.List() {
+		if checkpoint.Height > lfbHeight {
+			break
+		}
+		for _, block := range chain.FindBlocksByHeight(checkpoint.Height) {
+			if !block.Status.IsFinalized() {
+				continue
+			}
+			if err := checkpoints.Check(block.Height, block.Hash()); err != nil {
+				return fmt.Errorf("The local chain has finalized a conflicting block, resync from a trusted snapshot: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkTrustPeriod returns an error if the last finalized block is older than the trust period and no
+// checkpoint beyond it is configured
+func checkTrustPeriod(checkpoints *core.WeakSubjectivityCheckpoints, lfb *core.ExtendedBlock, now time.Time, period time.Duration) error {
+	if period <= 0 || checkpoints.IsEmpty() || lfb.Timestamp == nil {
+		return nil
+	}
+	lfbTime := time.Unix(lfb.Timestamp.Int64(), 0)
+	if now.Sub(lfbTime) <= period {
+		return nil
+	}
+	if latest := checkpoints.Latest(); latest.Height > lfb.Height {
+		return nil
+	}
+	return fmt.Errorf("The last finalized block %v at height %v is older than the weak subjectivity period of %v, "+
+		"configure a recent checkpoint (see pandocli checkpoint fetch) in %v",
+		lfb.Hash().Hex(), lfb.Height, period, common.CfgSyncWeakSubjectivityCheckpoints)
+}
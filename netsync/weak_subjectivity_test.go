@@ -0,0 +1,40 @@
+package netsync
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeakSubjectivityTrustPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1600000000, 0)
+	period := 14 * 24 * time.Hour
+	hash := common.BytesToHash([]byte("checkpoint"))
+
+	lfb := &core.ExtendedBlock{Block: &core.Block{BlockHeader: &core.BlockHeader{
+		Height:    1050,
+		Timestamp: big.NewInt(now.Add(-period - time.Hour).Unix()),
+	}}}
+
+	// No checkpoint, no enforcement
+	empty, _ := core.NewWeakSubjectivityCheckpoints([]string{})
+	assert.Nil(checkTrustPeriod(empty, lfb, now, period))
+
+	// The last finalized block is older than the trust period, a more recent checkpoint is required
+	stale, _ := core.NewWeakSubjectivityCheckpoints([]string{"1001:" + hash.Hex()})
+	assert.NotNil(checkTrustPeriod(stale, lfb, now, period))
+	assert.Nil(checkTrustPeriod(stale, lfb, now, 0))
+
+	recent, _ := core.NewWeakSubjectivityCheckpoints([]string{"1001:" + hash.Hex(), "2001:" + hash.Hex()})
+	assert.Nil(checkTrustPeriod(recent, lfb, now, period))
+
+	// The last finalized block is within the trust period
+	lfb.Timestamp = big.NewInt(now.Add(-time.Hour).Unix())
+	assert.Nil(checkTrustPeriod(stale, lfb, now, period))
+}
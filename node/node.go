@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"log"
+	"path"
 	"reflect"
 	"sync"
 
@@ -20,9 +21,11 @@ import (
 	"github.com/pandotoken/pando/p2pl"
 	rp "github.com/pandotoken/pando/report"
 	"github.com/pandotoken/pando/rpc"
+	"github.com/pandotoken/pando/scheduler"
 	"github.com/pandotoken/pando/snapshot"
 	"github.com/pandotoken/pando/store"
 	"github.com/pandotoken/pando/store/database"
+	"github.com/pandotoken/pando/store/freezer"
 	"github.com/pandotoken/pando/store/kvstore"
 )
 
@@ -32,10 +35,14 @@ type Node struct {
 	Consensus        *consensus.ConsensusEngine
 	ValidatorManager core.ValidatorManager
 	SyncManager      *netsync.SyncManager
+	SnapshotManager  *netsync.SnapshotManager
 	Dispatcher       *dp.Dispatcher
 	Ledger           core.Ledger
 	Mempool          *mp.Mempool
 	RPC              *rpc.PandoRPCServer
+	Scheduler        *scheduler.Scheduler
+	HeartbeatPool    *scheduler.HeartbeatPool // set if the node attests the heartbeats of the Rametron nodes
+	Generator        *snapshot.Generator      // set if the node generates snapshots in the background
 	reporter         *rp.Reporter
 
 	// Life cycle
@@ -48,7 +55,7 @@ type Node struct {
 
 type Params struct {
 	ChainID             string
-	PrivateKey          *crypto.PrivateKey
+	Signer              crypto.Signer
 	Root                *core.Block
 	NetworkOld          p2p.Network
 	Network             p2pl.Network
@@ -56,18 +63,31 @@ type Params struct {
 	SnapshotPath        string
 	ChainImportDirPath  string
 	ChainCorrectionPath string
+
+	// SnapshotManager is set if the snapshot manager has been created for snapshot sync
+	SnapshotManager *netsync.SnapshotManager
+
+	// Freezer is set if the old finalized blocks are moved out of the key-value store
+	Freezer *freezer.Freezer
 }
 
 func NewNode(params *Params) *Node {
 	store := kvstore.NewKVStore(params.DB)
 	chain := blockchain.NewChain(params.ChainID, store, params.Root)
+	if params.Freezer != nil {
+		chain.SetFreezer(params.Freezer)
+	}
 	validatorManager := consensus.NewRotatingValidatorManager()
 	dispatcher := dp.NewDispatcher(params.NetworkOld, params.Network)
-	consensus := consensus.NewConsensusEngine(params.PrivateKey, store, chain, dispatcher, validatorManager)
+	consensus := consensus.NewConsensusEngine(params.Signer, store, chain, dispatcher, validatorManager)
 	reporter := rp.NewReporter(dispatcher, consensus, chain)
 
 	// TODO: check if this is a guardian node
 	syncMgr := netsync.NewSyncManager(chain, consensus, params.NetworkOld, params.Network, dispatcher, consensus, reporter)
+	snapshotMgr := params.SnapshotManager
+	if snapshotMgr == nil {
+		snapshotMgr = netsync.NewSnapshotManager(params.NetworkOld, params.Network, viper.GetString(common.CfgSyncSnapshotServeDir))
+	}
 	mempool := mp.CreateMempool(dispatcher, consensus)
 	ledger := ld.NewLedger(params.ChainID, params.DB, chain, consensus, validatorManager, mempool)
 
@@ -81,6 +101,15 @@ func NewNode(params *Params) *Node {
 	}
 	if !reflect.ValueOf(params.NetworkOld).IsNil() {
 		params.NetworkOld.RegisterMessageHandler(txMsgHandler)
+
+		// Warn of the upcoming forks advertised by the peers which the binary does not support
+		if monitored, ok := params.NetworkOld.(interface {
+			SetHeightProvider(heightProvider func() uint64)
+		}); ok {
+			monitored.SetHeightProvider(func() uint64 {
+				return consensus.GetLastFinalizedBlock().Height
+			})
+		}
 	}
 
 	currentHeight := consensus.GetLastFinalizedBlock().Height
@@ -108,14 +137,38 @@ func NewNode(params *Params) *Node {
 		Consensus:        consensus,
 		ValidatorManager: validatorManager,
 		SyncManager:      syncMgr,
+		SnapshotManager:  snapshotMgr,
 		Dispatcher:       dispatcher,
 		Ledger:           ledger,
 		Mempool:          mempool,
 		reporter:         reporter,
 	}
 
+	if viper.GetBool(common.CfgSchedulerEnabled) {
+		if len(viper.GetString(common.CfgSchedulerRametronAttestationSchedule)) != 0 {
+			node.HeartbeatPool = scheduler.NewHeartbeatPool(params.ChainID)
+		}
+		sched, err := newScheduler(params, chain, consensus, ledger, mempool, node.HeartbeatPool)
+		if err != nil {
+			log.Fatalf("Failed to create the scheduler: %v", err)
+		}
+		node.Scheduler = sched
+	}
+
+	if interval := viper.GetInt64(common.CfgSnapshotGenerateInterval); interval > 0 {
+		snapshotDir := viper.GetString(common.CfgSnapshotGenerateDir)
+		if len(snapshotDir) == 0 {
+			snapshotDir = path.Join(viper.GetString(common.CfgConfigPath), "backup", "snapshot")
+		}
+		node.Generator = snapshot.NewGenerator(params.DB, consensus, chain, snapshotDir, uint64(interval),
+			viper.GetInt(common.CfgSnapshotGenerateRetain), viper.GetInt64(common.CfgSnapshotGenerateWriteLimit)*1024)
+	}
+
 	if viper.GetBool(common.CfgRPCEnabled) {
 		node.RPC = rpc.NewPandoRPCServer(mempool, ledger, dispatcher, chain, consensus)
+		node.RPC.SetScheduler(node.Scheduler)
+		node.RPC.SetHeartbeatPool(node.HeartbeatPool)
+		node.RPC.SetNetwork(params.NetworkOld)
 	}
 	return node
 }
@@ -128,10 +181,19 @@ func (n *Node) Start(ctx context.Context) {
 
 	n.Consensus.Start(n.ctx)
 	n.SyncManager.Start(n.ctx)
+	n.SnapshotManager.Start(n.ctx)
 	n.Dispatcher.Start(n.ctx)
 	n.Mempool.Start(n.ctx)
 	n.reporter.Start(n.ctx)
 
+	if n.Scheduler != nil {
+		n.Scheduler.Start(n.ctx)
+	}
+
+	if n.Generator != nil {
+		n.Generator.Start(n.ctx)
+	}
+
 	if viper.GetBool(common.CfgRPCEnabled) {
 		n.RPC.Start(n.ctx)
 	}
@@ -146,6 +208,12 @@ func (n *Node) Stop() {
 func (n *Node) Wait() {
 	n.Consensus.Wait()
 	n.SyncManager.Wait()
+	if n.Scheduler != nil {
+		n.Scheduler.Wait()
+	}
+	if n.Generator != nil {
+		n.Generator.Wait()
+	}
 	if n.RPC != nil {
 		n.RPC.Wait()
 	}
@@ -0,0 +1,122 @@
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path"
+	"strings"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/consensus"
+	"github.com/pandotoken/pando/core"
+	ld "github.com/pandotoken/pando/ledger"
+	"github.com/pandotoken/pando/ledger/types"
+	mp "github.com/pandotoken/pando/mempool"
+	"github.com/pandotoken/pando/scheduler"
+	"github.com/pandotoken/pando/wallet/softwallet"
+	"github.com/spf13/viper"
+)
+
+// newScheduler creates the scheduler with the periodic tasks enabled in the config
+func newScheduler(params *Params, chain *blockchain.Chain, consensus *consensus.ConsensusEngine,
+	ledger *ld.Ledger, mempool *mp.Mempool, heartbeatPool *scheduler.HeartbeatPool) (*scheduler.Scheduler, error) {
+	sched := scheduler.NewScheduler(viper.GetInt(common.CfgSchedulerHistorySize))
+	cfgPath := viper.GetString(common.CfgConfigPath)
+
+	if spec := viper.GetString(common.CfgSchedulerSnapshotSchedule); len(spec) != 0 {
+		snapshotDir := viper.GetString(common.CfgSchedulerSnapshotDir)
+		if len(snapshotDir) == 0 {
+			snapshotDir = path.Join(cfgPath, "backup", "snapshot")
+		}
+		task := scheduler.NewSnapshotExportTask(params.DB, consensus, chain, snapshotDir,
+			viper.GetInt(common.CfgSchedulerSnapshotRetain))
+		if err := sched.AddTask(scheduler.TaskSnapshotExport, spec, task); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec := viper.GetString(common.CfgSchedulerCompactionSchedule); len(spec) != 0 {
+		task := scheduler.NewDBCompactionTask(params.DB)
+		if err := sched.AddTask(scheduler.TaskDBCompaction, spec, task); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec := viper.GetString(common.CfgSchedulerStakeCompoundSchedule); len(spec) != 0 {
+		task, err := newStakeCompoundTask(params.ChainID, ledger, mempool)
+		if err != nil {
+			return nil, err
+		}
+		if err := sched.AddTask(scheduler.TaskStakeCompound, spec, task); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec := viper.GetString(common.CfgSchedulerRametronAttestationSchedule); len(spec) != 0 {
+		task := scheduler.NewRametronAttestationTask(params.ChainID, ledger, mempool, heartbeatPool, params.Signer)
+		if err := sched.AddTask(scheduler.TaskRametronAttestation, spec, task); err != nil {
+			return nil, err
+		}
+	}
+
+	return sched, nil
+}
+
+// newStakeCompoundTask unlocks the staker key for the lifetime of the node, since the
+// task runs unattended
+func newStakeCompoundTask(chainID string, ledger *ld.Ledger, mempool *mp.Mempool) (scheduler.TaskFunc, error) {
+	source := viper.GetString(common.CfgSchedulerStakeCompoundSource)
+	if !common.IsHexAddress(source) {
+		return nil, fmt.Errorf("Invalid stake compounding source address: %q", source)
+	}
+	holder := viper.GetString(common.CfgSchedulerStakeCompoundHolder)
+	if !common.IsHexAddress(holder) {
+		return nil, fmt.Errorf("Invalid stake compounding holder address: %q", holder)
+	}
+	purpose := uint8(viper.GetInt(common.CfgSchedulerStakeCompoundPurpose))
+	if purpose != core.StakeForValidator && purpose != core.StakeForGuardian {
+		return nil, fmt.Errorf("Invalid stake compounding purpose: %v", purpose)
+	}
+	reserve, ok := types.ParseCoinAmount(viper.GetString(common.CfgSchedulerStakeCompoundReserve))
+	if !ok {
+		return nil, fmt.Errorf("Failed to parse stake compounding reserve")
+	}
+
+	keysDir := viper.GetString(common.CfgSchedulerStakeCompoundKeysDir)
+	if len(keysDir) == 0 {
+		keyPath := viper.GetString(common.CfgKeyPath)
+		if len(keyPath) == 0 {
+			keyPath = viper.GetString(common.CfgConfigPath)
+		}
+		keysDir = path.Join(keyPath, "key")
+	}
+	passwordFile := viper.GetString(common.CfgSchedulerStakeCompoundPasswordFile)
+	if len(passwordFile) == 0 {
+		return nil, fmt.Errorf("The password file of the stake compounding key is not specified")
+	}
+	password, err := ioutil.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the password file of the stake compounding key: %v", err)
+	}
+
+	wallet, err := softwallet.NewSoftWallet(keysDir, softwallet.KeystoreTypeEncrypted)
+	if err != nil {
+		return nil, err
+	}
+	sourceAddress := common.HexToAddress(source)
+	if err := wallet.Unlock(sourceAddress, strings.TrimRight(string(password), "\r\n"), nil); err != nil {
+		return nil, fmt.Errorf("Failed to unlock the stake compounding key %v: %v", source, err)
+	}
+
+	config := scheduler.StakeCompoundConfig{
+		ChainID: chainID,
+		Source:  sourceAddress,
+		Holder:  common.HexToAddress(holder),
+		Purpose: purpose,
+		Reserve: reserve,
+		Fee:     new(big.Int).SetUint64(types.MinimumTransactionFeePTXWei),
+	}
+	return scheduler.NewStakeCompoundTask(ledger, mempool, wallet, config), nil
+}
@@ -79,6 +79,11 @@ const (
 // the allowed 24 bits (i.e. length >= 16MB).
 var errPlainMessageTooLarge = errors.New("message length >= 16MB")
 
+// errFrameTooLarge is returned if a received frame is larger than an encoded packet.
+// The check is done before the frame is read, so a peer cannot make us allocate
+// large buffers.
+var errFrameTooLarge = errors.New("frame is larger than the maximum packet size")
+
 // DoEncHandshake runs the protocol handshake using authenticated
 // messages. the protocol handshake is the first authenticated message
 // and also verifies whether the encryption handshake 'worked' and the
@@ -557,6 +562,9 @@ func (rw *rlpxFrameRW) ReadPacket() (*Packet, error) {
 	rw.dec.XORKeyStream(headbuf[:16], headbuf[:16]) // first half is now decrypted
 	fsize := readInt24(headbuf)
 	// ignore protocol type for now
+	if fsize > uint32(snappy.MaxEncodedLen(maxEncodedPacketSize)) {
+		return nil, errFrameTooLarge
+	}
 
 	// read the frame content
 	var rsize = fsize // frame size rounded up to 16 byte boundary
@@ -594,8 +602,8 @@ func (rw *rlpxFrameRW) ReadPacket() (*Packet, error) {
 		if err != nil {
 			return nil, err
 		}
-		if size > int(maxUint24) {
-			return nil, errPlainMessageTooLarge
+		if size > maxEncodedPacketSize {
+			return nil, errFrameTooLarge
 		}
 		payload, err = snappy.Decode(nil, payload)
 		if err != nil {
@@ -604,7 +612,9 @@ func (rw *rlpxFrameRW) ReadPacket() (*Packet, error) {
 		content = bytes.NewReader(payload)
 	}
 	packet := &Packet{}
-	if err := rlp.Decode(content, &packet); err != nil {
+	s := rlp.NewStream(content, 0)
+	s.SetStringLimit(maxPayloadSize)
+	if err := s.Decode(&packet); err != nil {
 		return nil, err
 	}
 	return packet, nil
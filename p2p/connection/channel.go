@@ -2,6 +2,7 @@ package connection
 
 import (
 	"github.com/pandotoken/pando/common"
+	p2pcmn "github.com/pandotoken/pando/p2pl/common"
 )
 
 //
@@ -28,6 +29,7 @@ func createDefaultChannel(channelID common.ChannelIDEnum) Channel {
 	chCfg := getDefaultChannelConfig()
 	sbCfg := getDefaultSendBufferConfig()
 	rbCfg := getDefaultRecvBufferConfig()
+	rbCfg.maxMessageSize = p2pcmn.MaxMessageSize(channelID)
 
 	channel := createChannel(channelID, chCfg, sbCfg, rbCfg)
 	return channel
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	p2ptypes "github.com/pandotoken/pando/p2p/types"
+	p2pcmn "github.com/pandotoken/pando/p2pl/common"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/pandotoken/pando/common"
@@ -190,12 +191,12 @@ func TestDefaultChannelRecvMultipleMsgs(t *testing.T) {
 
 func TestDefaultChannelRecvExtraLongMsg(t *testing.T) {
 	assert := assert.New(t)
-	ch := createDefaultChannel(common.ChannelIDTransaction)
+	ch := createDefaultChannel(common.ChannelIDBlock)
 
 	expectedMsgBytes := []byte{}
 	msgBytes := []byte("01234567890123450123456789012345012345678901234501234567890123450123456789012345012345678901234501234567890123450123456789012345") // 128 Bytes
 	packet := &Packet{
-		ChannelID: common.ChannelIDTransaction,
+		ChannelID: common.ChannelIDBlock,
 		Bytes:     msgBytes,
 		IsEOF:     byte(0x00),
 	}
@@ -213,7 +214,7 @@ func TestDefaultChannelRecvExtraLongMsg(t *testing.T) {
 	}
 
 	endPacket := &Packet{
-		ChannelID: common.ChannelIDTransaction,
+		ChannelID: common.ChannelIDBlock,
 		Bytes:     msgBytes,
 		IsEOF:     byte(0x01),
 		SeqID:     i,
@@ -231,3 +232,39 @@ func TestDefaultChannelRecvExtraLongMsg(t *testing.T) {
 	sameBytes := (bytes.Compare(expectedMsgBytes, aggregatedBytes) == 0)
 	assert.True(sameBytes)
 }
+
+func TestDefaultChannelRecvOversizedMsg(t *testing.T) {
+	assert := assert.New(t)
+	ch := createDefaultChannel(common.ChannelIDTransaction)
+
+	msgBytes := make([]byte, maxPayloadSize)
+	packet := &Packet{
+		ChannelID: common.ChannelIDTransaction,
+		Bytes:     msgBytes,
+		IsEOF:     byte(0x00),
+	}
+
+	// The message is dropped once it exceeds the limit of the channel
+	i := uint(0)
+	for ; i < p2pcmn.MaxNormalMessageSize/maxPayloadSize; i++ {
+		packet.SeqID = i
+		recvBytes, success := ch.receivePacket(packet)
+		assert.True(success)
+		assert.Nil(recvBytes)
+	}
+	packet.SeqID = i
+	recvBytes, success := ch.receivePacket(packet)
+	assert.False(success)
+	assert.Nil(recvBytes)
+
+	// The rest of the dropped message is ignored, and the next message is received
+	packet.SeqID = i + 1
+	packet.IsEOF = byte(0x01)
+	_, success = ch.receivePacket(packet)
+	assert.False(success)
+
+	packet.SeqID = 0
+	recvBytes, success = ch.receivePacket(packet)
+	assert.True(success)
+	assert.Equal(msgBytes, recvBytes)
+}
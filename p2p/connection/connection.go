@@ -110,6 +110,7 @@ func CreateConnection(netconn net.Conn, config ConnectionConfig) *Connection {
 	channelPing := createDefaultChannel(common.ChannelIDPing)
 	channelGuardian := createDefaultChannel(common.ChannelIDGuardian)
 	channelNATMapping := createDefaultChannel(common.ChannelIDNATMapping)
+	channelSnapshot := createDefaultChannel(common.ChannelIDSnapshot)
 	channels := []*Channel{
 		&channelCheckpoint,
 		&channelHeader,
@@ -121,6 +122,7 @@ func CreateConnection(netconn net.Conn, config ConnectionConfig) *Connection {
 		&channelPing,
 		&channelGuardian,
 		&channelNATMapping,
+		&channelSnapshot,
 	}
 
 	success, channelGroup := createChannelGroup(getDefaultChannelGroupConfig(), channels)
@@ -376,7 +378,8 @@ func (conn *Connection) readPacket() (*Packet, error) {
 	// Plaintext transport.
 	if conn.rw == nil {
 		packet := &Packet{}
-		s := rlp.NewStream(conn.bufReader, maxPayloadSize*1024)
+		s := rlp.NewStream(conn.bufReader, maxEncodedPacketSize)
+		s.SetStringLimit(maxPayloadSize)
 		err := s.Decode(packet)
 		return packet, err
 	}
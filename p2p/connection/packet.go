@@ -10,6 +10,7 @@ const (
 	maxPayloadSize        = 1024 // 1k bytes
 	maxAdditionalDataSize = 10
 	maxPacketTotalSize    = maxPayloadSize + maxAdditionalDataSize
+	maxEncodedPacketSize  = maxPacketTotalSize + 32 // with the RLP headers
 	packetTypePing        = byte(0x01)
 	packetTypePong        = byte(0x02)
 	packetTypeMsg         = byte(0x03)
@@ -1,5 +1,9 @@
 package connection
 
+import (
+	p2pcmn "github.com/pandotoken/pando/p2pl/common"
+)
+
 type RecvBuffer struct {
 	workspace []byte
 
@@ -9,6 +13,7 @@ type RecvBuffer struct {
 
 type RecvBufferConfig struct {
 	workspaceCapacity int
+	maxMessageSize    int // 0 for no limit
 }
 
 // createRecvBuffer creates a RecvBuffer instance for the given config
@@ -23,16 +28,20 @@ func createRecvBuffer(config RecvBufferConfig) RecvBuffer {
 func getDefaultRecvBufferConfig() RecvBufferConfig {
 	return RecvBufferConfig{
 		workspaceCapacity: 4 * 1024, // 4 KB
+		maxMessageSize:    p2pcmn.MaxNormalMessageSize,
 	}
 }
 
 // receivePacket handles incoming msgPackets. It returns a msg bytes if msg is
 // complete (i.e. ends with EOF). It is not goroutine safe
 func (rb *RecvBuffer) receivePacket(packet *Packet) ([]byte, bool) {
-	// Here we disable the check below to allow arbitrarily long message
-	// if len(rb.workspace)+len(packet.Bytes) > rb.config.workspaceCapacity {
-	// 	return nil, false
-	// }
+	// Drop the partially received message if it grows beyond the limit,
+	// before buffering more of it
+	if rb.config.maxMessageSize > 0 && len(rb.workspace)+len(packet.Bytes) > rb.config.maxMessageSize {
+		rb.workspace = rb.workspace[:0]
+		rb.chanSeq = 0
+		return nil, false
+	}
 
 	// Note: We do NOT need to worry about the order of the packets.
 	//       TCP guarantees that if bytes arrive, they will be in the
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/pandotoken/pando/common"
+	p2pcmn "github.com/pandotoken/pando/p2pl/common"
 )
 
 func TestDefaultRecvBuffer(t *testing.T) {
@@ -103,7 +104,9 @@ func TestRecvMultipleMessages(t *testing.T) {
 
 func TestRecvExtraLongMessage(t *testing.T) {
 	assert := assert.New(t)
-	drb := newTestDefaultRecvBuffer()
+	config := getDefaultRecvBufferConfig()
+	config.maxMessageSize = p2pcmn.MaxBlockMessageSize
+	drb := createRecvBuffer(config)
 
 	expectedMsgBytes := []byte{}
 	msgBytes := []byte("01234567890123450123456789012345012345678901234501234567890123450123456789012345012345678901234501234567890123450123456789012345") // 128 Bytes
@@ -145,6 +148,29 @@ func TestRecvExtraLongMessage(t *testing.T) {
 	assert.True(sameBytes)
 }
 
+func TestRecvOversizedMessage(t *testing.T) {
+	assert := assert.New(t)
+	drb := newTestDefaultRecvBuffer()
+
+	msgBytes := make([]byte, 64*1024)
+	var i uint
+	for ; i < uint(p2pcmn.MaxNormalMessageSize/len(msgBytes)); i++ {
+		recvBytes, success := drb.receivePacket(&Packet{ChannelID: common.ChannelIDTransaction, SeqID: i, Bytes: msgBytes})
+		assert.True(success)
+		assert.Nil(recvBytes)
+	}
+
+	// The message is dropped once it exceeds the limit
+	recvBytes, success := drb.receivePacket(&Packet{ChannelID: common.ChannelIDTransaction, SeqID: i, Bytes: msgBytes, IsEOF: byte(0x01)})
+	assert.False(success)
+	assert.Nil(recvBytes)
+
+	// The buffer is ready for the next message
+	recvBytes, success = drb.receivePacket(&Packet{ChannelID: common.ChannelIDTransaction, SeqID: 0, Bytes: []byte("hello"), IsEOF: byte(0x01)})
+	assert.True(success)
+	assert.Equal("hello", string(recvBytes))
+}
+
 // --------------- Test Utilities --------------- //
 
 func newTestDefaultRecvBuffer() RecvBuffer {
@@ -114,7 +114,7 @@ func (ipl *InboundPeerListener) SetInboundCallback(incb InboundCallback) {
 func (ipl *InboundPeerListener) listenRoutine() {
 	defer ipl.wg.Done()
 
-	seedPeerOnly := viper.GetBool(common.CfgP2PSeedPeerOnly)
+	seedPeerOnly := ipl.discMgr.seedPeerOnly
 	maxNumPeers := GetDefaultPeerDiscoveryManagerConfig().MaxNumPeers
 	logger.Infof("InboundPeerListener listen routine started, seedPeerOnly set to %v", seedPeerOnly)
 
@@ -136,7 +136,10 @@ func (ipl *InboundPeerListener) listenRoutine() {
 			}
 		} else {
 			numPeers := int(ipl.discMgr.peerTable.GetTotalNumPeers())
-			if numPeers >= maxNumPeers {
+			if numPeers >= maxNumPeers && ipl.discMgr.isPrivatePeerIP(remoteAddr.IP) {
+				// The validators behind the node are always accepted
+				logger.Infof("Accept inbound connection from private peer %v despite the max peers limit", remoteAddr.String())
+			} else if numPeers >= maxNumPeers {
 				if viper.GetBool(common.CfgP2PConnectionFIFO) {
 					purgedPeer := ipl.discMgr.peerTable.PurgeOldestPeer()
 					if purgedPeer != nil {
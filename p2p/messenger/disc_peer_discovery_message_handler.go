@@ -24,6 +24,9 @@ type PeerDiscoveryMessageType byte
 const (
 	peerAddressesRequestType PeerDiscoveryMessageType = 0x01
 	peerAddressesReplyType   PeerDiscoveryMessageType = 0x02
+	sentryRegisterType       PeerDiscoveryMessageType = 0x03 // a validator registers with its sentry
+	sentryStatusType         PeerDiscoveryMessageType = 0x04 // the sentry replies with its other peers
+	peerExchangeType         PeerDiscoveryMessageType = 0x05 // a peer periodically shares healthy peer addresses
 )
 
 const (
@@ -152,6 +155,16 @@ func (pdmh *PeerDiscoveryMessageHandler) HandleMessage(msg types.Message) error
 		pdmh.handlePeerAddressRequest(peer, discMsg)
 	case peerAddressesReplyType:
 		pdmh.handlePeerAddressReply(peer, discMsg)
+	case sentryRegisterType:
+		pdmh.handleSentryRegister(peer, discMsg)
+	case sentryStatusType:
+		if pdmh.discMgr.sentryMonitor != nil {
+			pdmh.discMgr.sentryMonitor.handleStatus(peer, discMsg.Addresses)
+		}
+	case peerExchangeType:
+		if pdmh.discMgr.peerExchange != nil {
+			pdmh.discMgr.peerExchange.handleExchange(peer, discMsg)
+		}
 	default:
 		errMsg := "Invalid PeerDiscoveryMessageType"
 		logger.Errorf(errMsg)
@@ -163,15 +176,33 @@ func (pdmh *PeerDiscoveryMessageHandler) HandleMessage(msg types.Message) error
 
 func (pdmh *PeerDiscoveryMessageHandler) handlePeerAddressRequest(peer *pr.Peer, message PeerDiscoveryMessage) {
 	peerIDAddrs := pdmh.discMgr.peerTable.GetSelection()
+	// The addresses of the validators behind the node are kept private
+	peerIDAddrs = pdmh.discMgr.filterPrivatePeers(peerIDAddrs, "")
 	pdmh.sendAddresses(peer, peerIDAddrs)
 }
 
+func (pdmh *PeerDiscoveryMessageHandler) handleSentryRegister(peer *pr.Peer, message PeerDiscoveryMessage) {
+	if !pdmh.discMgr.registerPrivatePeer(peer) {
+		return
+	}
+
+	peerIDAddrs := []pr.PeerIDAddress{}
+	for _, p := range *(pdmh.discMgr.peerTable.GetAllPeers()) {
+		peerIDAddrs = append(peerIDAddrs, pr.PeerIDAddress{ID: p.ID(), Addr: p.NetAddress()})
+	}
+	reply := PeerDiscoveryMessage{
+		Type:      sentryStatusType,
+		Addresses: pdmh.discMgr.filterPrivatePeers(peerIDAddrs, peer.ID()),
+	}
+	peer.Send(common.ChannelIDPeerDiscovery, reply)
+}
+
 func (pdmh *PeerDiscoveryMessageHandler) handlePeerAddressReply(peer *pr.Peer, message PeerDiscoveryMessage) {
 	logger.Infof("Received peer discovery reply from %v with %v peer addresses", peer.ID(), len(message.Addresses))
 	validAddressMap := make(map[*netutil.NetAddress]bool)
 	for _, idAddr := range message.Addresses {
 		isNotASeedPeer := !pdmh.discMgr.seedPeerConnector.isASeedPeer(idAddr.Addr)
-		if seedPeerOnlyOutbound() && isNotASeedPeer {
+		if (seedPeerOnlyOutbound() || pdmh.discMgr.seedPeerOnly) && isNotASeedPeer {
 			// Sometimes we want to run some nodes behind firewalls. We only allow these nodes to proactively
 			// connect to the seed peers (i.e. only the seed peers can be outbound peers). Such nodes can
 			// still accept inbound connections from non-seed peers based on the firewall rules.
@@ -291,7 +322,10 @@ func (pdmh *PeerDiscoveryMessageHandler) maintainSufficientConnectivity() {
 		} else {
 			logger.Infof("Already has sufficient number of peers, numPeers: %v, sufficientNumPeers: %v", numPeers, sufficientNumPeers)
 		}
-	} else { // no peer left in the peer table, try to reconnect to seed peers
+	} else { // no peer left in the peer table, try to reconnect to the exchanged peers and the seed peers
+		if pdmh.discMgr.peerExchange != nil {
+			pdmh.discMgr.peerExchange.bootstrap()
+		}
 		pdmh.discMgr.seedPeerConnector.connectToSeedPeers()
 	}
 }
@@ -0,0 +1,375 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/p2p/netutil"
+	pr "github.com/pandotoken/pando/p2p/peer"
+)
+
+const (
+	// maxPexAddresses caps the addresses of an exchange message, a message with more addresses is rejected as spam
+	maxPexAddresses = 32
+	// maxPexKnownAddresses caps the addresses learned through peer exchange, the least recently seen are evicted
+	maxPexKnownAddresses = 1000
+	// maxPexFailures is the number of failed dials after which a learned address is dropped
+	maxPexFailures = 3
+	// pexAddressExpiry is how long a learned address is shared with the other peers since it was last seen
+	pexAddressExpiry = 3 * time.Hour
+	// pexMaxViolations is the number of rejected exchange messages after which a peer is ignored
+	pexMaxViolations = 5
+)
+
+// pexAddress is a peer address learned through peer exchange
+type pexAddress struct {
+	id       string
+	addr     *netutil.NetAddress
+	lastSeen time.Time
+	failures int
+}
+
+// pexPeerState tracks the exchange messages received from a peer
+type pexPeerState struct {
+	lastReceived time.Time
+	violations   int
+}
+
+// PeerExchange implements the peer exchange (PEX) protocol. The node periodically shares a sample of
+// healthy peer addresses with its peers, i.e. the addresses of the outbound peers it is connected to,
+// and the learned addresses which were recently seen and could be dialed. The learned addresses are
+// persisted, so that the node can bootstrap from them without relying on the seeds.
+type PeerExchange struct {
+	discMgr  *PeerDiscoveryManager
+	interval time.Duration
+
+	mutex *sync.Mutex
+	known map[string]*pexAddress   // map: address |-> learned address
+	peers map[string]*pexPeerState // map: peerID |-> exchange state
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// createPeerExchange creates an instance of the PeerExchange
+func createPeerExchange(discMgr *PeerDiscoveryManager) *PeerExchange {
+	return &PeerExchange{
+		discMgr:  discMgr,
+		interval: time.Duration(viper.GetInt(common.CfgP2PPexIntervalSecs)) * time.Second,
+		mutex:    &sync.Mutex{},
+		known:    make(map[string]*pexAddress),
+		peers:    make(map[string]*pexPeerState),
+		wg:       &sync.WaitGroup{},
+	}
+}
+
+// Start is called when the PeerExchange starts
+func (pex *PeerExchange) Start(ctx context.Context) error {
+	c, cancel := context.WithCancel(ctx)
+	pex.ctx = c
+	pex.cancel = cancel
+
+	pex.restore()
+
+	pex.wg.Add(1)
+	go pex.mainLoop()
+	return nil
+}
+
+// Stop is called when the PeerExchange stops
+func (pex *PeerExchange) Stop() {
+	pex.cancel()
+}
+
+// Wait suspends the caller goroutine
+func (pex *PeerExchange) Wait() {
+	pex.wg.Wait()
+}
+
+func (pex *PeerExchange) mainLoop() {
+	defer pex.wg.Done()
+
+	ticker := time.NewTicker(pex.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pex.ctx.Done():
+			return
+		case <-ticker.C:
+			pex.share()
+			pex.prunePeerStates(time.Now())
+			pex.persist()
+		}
+	}
+}
+
+// restore loads the persisted learned addresses
+func (pex *PeerExchange) restore() {
+	addrs, err := pex.discMgr.peerTable.RetrieveExchangedAddresses()
+	if err != nil {
+		logger.Debugf("No peer exchange addresses restored: %v", err)
+		return
+	}
+
+	pex.mutex.Lock()
+	defer pex.mutex.Unlock()
+	now := time.Now()
+	for _, addr := range addrs {
+		pex.known[addr.String()] = &pexAddress{addr: addr, lastSeen: now}
+	}
+	logger.Infof("Restored %v peer exchange addresses", len(addrs))
+}
+
+func (pex *PeerExchange) persist() {
+	pex.mutex.Lock()
+	addrs := make([]*netutil.NetAddress, 0, len(pex.known))
+	for _, ka := range pex.known {
+		addrs = append(addrs, ka.addr)
+	}
+	pex.mutex.Unlock()
+
+	pex.discMgr.peerTable.PersistExchangedAddresses(addrs)
+}
+
+// share sends a sample of the healthy peer addresses to each connected peer
+func (pex *PeerExchange) share() {
+	sample := pex.healthySample(time.Now())
+	if len(sample) == 0 {
+		return
+	}
+	for _, peer := range *(pex.discMgr.peerTable.GetAllPeers()) {
+		// The validators behind the node are not told about their sentry peers, they only connect to the sentries
+		if pex.discMgr.isPrivatePeer(peer.ID()) {
+			continue
+		}
+		addresses := []pr.PeerIDAddress{}
+		for _, idAddr := range sample {
+			if idAddr.ID != peer.ID() {
+				addresses = append(addresses, idAddr)
+			}
+		}
+		if len(addresses) == 0 {
+			continue
+		}
+		peer.Send(common.ChannelIDPeerDiscovery, PeerDiscoveryMessage{
+			Type:      peerExchangeType,
+			Addresses: addresses,
+		})
+	}
+}
+
+// healthySample returns a random sample of the addresses of the connected outbound peers, whose
+// addresses could be dialed, and of the recently seen learned addresses which did not fail to dial.
+// The addresses of the inbound peers are not shared, since their ports are ephemeral.
+func (pex *PeerExchange) healthySample(now time.Time) []pr.PeerIDAddress {
+	candidates := []pr.PeerIDAddress{}
+	included := make(map[string]bool)
+	for _, peer := range *(pex.discMgr.peerTable.GetAllPeers()) {
+		if !peer.IsOutbound() || peer.NetAddress() == nil {
+			continue
+		}
+		candidates = append(candidates, pr.PeerIDAddress{ID: peer.ID(), Addr: peer.NetAddress()})
+		included[peer.NetAddress().String()] = true
+	}
+
+	pex.mutex.Lock()
+	for key, ka := range pex.known {
+		if included[key] || ka.failures > 0 || now.Sub(ka.lastSeen) > pexAddressExpiry {
+			continue
+		}
+		candidates = append(candidates, pr.PeerIDAddress{ID: ka.id, Addr: ka.addr})
+	}
+	pex.mutex.Unlock()
+
+	candidates = pex.discMgr.filterPrivatePeers(candidates, "")
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > maxPexAddresses {
+		candidates = candidates[:maxPexAddresses]
+	}
+	return candidates
+}
+
+// handleExchange validates the addresses shared by a peer, learns the valid ones, and dials some of
+// them if the node needs more peers
+func (pex *PeerExchange) handleExchange(peer *pr.Peer, message PeerDiscoveryMessage) {
+	addrs, err := pex.addAddresses(peer.ID(), message.Addresses, time.Now())
+	if err != nil {
+		logger.Warnf("Rejected peer exchange message from %v: %v", peer.ID(), err)
+		return
+	}
+	logger.Debugf("Learned %v out of %v peer addresses from %v", len(addrs), len(message.Addresses), peer.ID())
+
+	if len(addrs) > 0 && !seedPeerOnlyOutbound() && !pex.discMgr.seedPeerOnly {
+		pex.dial(addrs)
+	}
+}
+
+// addAddresses applies the anti-spam limits to an exchange message of a peer, and learns the valid
+// addresses it contains. It returns the addresses the node is not connected to yet.
+func (pex *PeerExchange) addAddresses(sourceID string, addresses []pr.PeerIDAddress, now time.Time) ([]*netutil.NetAddress, error) {
+	pex.mutex.Lock()
+	defer pex.mutex.Unlock()
+
+	state, ok := pex.peers[sourceID]
+	if !ok {
+		state = &pexPeerState{}
+		pex.peers[sourceID] = state
+	}
+	if state.violations >= pexMaxViolations {
+		return nil, fmt.Errorf("peer ignored after %v violations", state.violations)
+	}
+	// A peer shares its addresses once per interval, a message received within half an interval is spam
+	if !state.lastReceived.IsZero() && now.Sub(state.lastReceived) < pex.interval/2 {
+		state.violations++
+		return nil, fmt.Errorf("exchange messages too frequent, last one received at %v", state.lastReceived)
+	}
+	if len(addresses) > maxPexAddresses {
+		state.violations++
+		return nil, fmt.Errorf("too many addresses: %v, at most %v are allowed", len(addresses), maxPexAddresses)
+	}
+	state.lastReceived = now
+
+	var selfID string
+	if pex.discMgr.messenger != nil {
+		selfID = pex.discMgr.messenger.ID()
+	}
+	newAddrs := []*netutil.NetAddress{}
+	seen := make(map[string]bool)
+	for _, idAddr := range addresses {
+		if idAddr.Addr == nil || !idAddr.Addr.Valid() || idAddr.Addr.Port == 0 {
+			continue
+		}
+		key := idAddr.Addr.String()
+		if seen[key] || idAddr.ID == selfID || idAddr.ID == sourceID {
+			continue
+		}
+		seen[key] = true
+
+		if ka, ok := pex.known[key]; ok {
+			ka.lastSeen = now
+			if idAddr.ID != "" {
+				ka.id = idAddr.ID
+			}
+		} else {
+			pex.evictIfFull()
+			pex.known[key] = &pexAddress{id: idAddr.ID, addr: idAddr.Addr, lastSeen: now}
+		}
+
+		if !pex.discMgr.peerTable.PeerExists(idAddr.ID) && !pex.discMgr.peerTable.PeerAddrExists(idAddr.Addr) {
+			newAddrs = append(newAddrs, idAddr.Addr)
+		}
+	}
+	return newAddrs, nil
+}
+
+// prunePeerStates drops the exchange states of the disconnected peers which were idle for an interval
+func (pex *PeerExchange) prunePeerStates(now time.Time) {
+	pex.mutex.Lock()
+	defer pex.mutex.Unlock()
+
+	for peerID, state := range pex.peers {
+		if now.Sub(state.lastReceived) > pex.interval && !pex.discMgr.peerTable.PeerExists(peerID) {
+			delete(pex.peers, peerID)
+		}
+	}
+}
+
+// evictIfFull removes the least recently seen learned address if the capacity is reached
+func (pex *PeerExchange) evictIfFull() {
+	if len(pex.known) < maxPexKnownAddresses {
+		return
+	}
+	var oldestKey string
+	var oldest *pexAddress
+	for key, ka := range pex.known {
+		if oldest == nil || ka.lastSeen.Before(oldest.lastSeen) {
+			oldestKey, oldest = key, ka
+		}
+	}
+	delete(pex.known, oldestKey)
+}
+
+// markDialResult records the outcome of dialing a learned address. An address which repeatedly
+// fails to dial is dropped.
+func (pex *PeerExchange) markDialResult(addr *netutil.NetAddress, err error) {
+	pex.mutex.Lock()
+	defer pex.mutex.Unlock()
+
+	ka, ok := pex.known[addr.String()]
+	if !ok {
+		return
+	}
+	if err == nil {
+		ka.failures = 0
+		ka.lastSeen = time.Now()
+		return
+	}
+	ka.failures++
+	if ka.failures >= maxPexFailures {
+		delete(pex.known, addr.String())
+	}
+}
+
+// dial connects to some of the given addresses if the node has less than the sufficient number of peers
+func (pex *PeerExchange) dial(addrs []*netutil.NetAddress) {
+	numPeers := int(pex.discMgr.peerTable.GetTotalNumPeers())
+	numNeeded := int(GetDefaultPeerDiscoveryManagerConfig().SufficientNumPeers) - numPeers
+	if numNeeded <= 0 {
+		return
+	}
+	if numNeeded > len(addrs) {
+		numNeeded = len(addrs)
+	}
+
+	perm := rand.Perm(len(addrs))
+	for i := 0; i < numNeeded; i++ {
+		go func(addr *netutil.NetAddress) {
+			time.Sleep(time.Duration(rand.Int63n(discoverInterval)) * time.Millisecond)
+			_, err := pex.discMgr.connectToOutboundPeer(addr, true)
+			if err != nil {
+				logger.Debugf("Failed to connect to exchanged peer %v: %v", addr.String(), err)
+			} else {
+				logger.Infof("Successfully connected to exchanged peer %v", addr.String())
+			}
+			pex.markDialResult(addr, err)
+		}(addrs[perm[i]])
+	}
+}
+
+// bootstrap dials the learned addresses, most recently seen first. It is used when the node has no
+// peer, so that it can rejoin the network without the seeds.
+func (pex *PeerExchange) bootstrap() {
+	pex.mutex.Lock()
+	known := make([]*pexAddress, 0, len(pex.known))
+	for _, ka := range pex.known {
+		known = append(known, ka)
+	}
+	pex.mutex.Unlock()
+	if len(known) == 0 {
+		return
+	}
+
+	sort.Slice(known, func(i, j int) bool { return known[i].lastSeen.After(known[j].lastSeen) })
+	addrs := []*netutil.NetAddress{}
+	for _, ka := range known {
+		if !pex.discMgr.peerTable.PeerAddrExists(ka.addr) {
+			addrs = append(addrs, ka.addr)
+		}
+		if len(addrs) >= maxPexAddresses {
+			break
+		}
+	}
+	logger.Infof("Bootstrapping from %v peer exchange addresses", len(addrs))
+	pex.dial(addrs)
+}
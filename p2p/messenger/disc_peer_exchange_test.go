@@ -0,0 +1,89 @@
+package messenger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/p2p/netutil"
+	pr "github.com/pandotoken/pando/p2p/peer"
+)
+
+func TestPeerExchangeAddAddresses(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	peerTable := pr.CreatePeerTable()
+	discMgr := &PeerDiscoveryManager{
+		peerTable:    &peerTable,
+		mutex:        &sync.Mutex{},
+		privatePeers: make(map[string]*privatePeer),
+	}
+	pex := createPeerExchange(discMgr)
+	pex.interval = time.Minute
+
+	newIDAddr := func(id string, addr string) pr.PeerIDAddress {
+		netAddr, err := netutil.NewNetAddressString(addr)
+		require.Nil(err)
+		return pr.PeerIDAddress{ID: id, Addr: netAddr}
+	}
+
+	now := time.Now()
+	addresses := []pr.PeerIDAddress{
+		newIDAddr("peer1", "10.0.0.1:50001"),
+		newIDAddr("peer1", "10.0.0.1:50001"),  // duplicate
+		newIDAddr("peer2", "0.0.0.0:50001"),   // unspecified
+		newIDAddr("peer3", "10.0.0.3:0"),      // no port
+		newIDAddr("source", "10.0.0.4:50001"), // the source itself
+		{ID: "peer5"},                         // no address
+		newIDAddr("peer6", "10.0.0.6:50001"),
+	}
+	addrs, err := pex.addAddresses("source", addresses, now)
+	require.Nil(err)
+	require.Equal(2, len(addrs))
+	assert.Equal("10.0.0.1:50001", addrs[0].String())
+	assert.Equal("10.0.0.6:50001", addrs[1].String())
+	assert.Equal(2, len(pex.known))
+
+	// The learned addresses are shared as healthy until they fail to dial
+	sample := pex.healthySample(now)
+	assert.Equal(2, len(sample))
+	pex.markDialResult(addrs[0], fmt.Errorf("connection refused"))
+	sample = pex.healthySample(now)
+	require.Equal(1, len(sample))
+	assert.Equal("peer6", sample[0].ID)
+	for i := 1; i < maxPexFailures; i++ {
+		pex.markDialResult(addrs[0], fmt.Errorf("connection refused"))
+	}
+	assert.Equal(1, len(pex.known))
+
+	// A peer can share its addresses once per interval
+	_, err = pex.addAddresses("source", addresses, now.Add(time.Second))
+	assert.NotNil(err)
+	_, err = pex.addAddresses("source", addresses, now.Add(pex.interval))
+	assert.Nil(err)
+
+	// Too many addresses
+	tooMany := []pr.PeerIDAddress{}
+	for i := 0; i <= maxPexAddresses; i++ {
+		tooMany = append(tooMany, newIDAddr(fmt.Sprintf("peer%v", i), fmt.Sprintf("10.0.1.%v:50001", i)))
+	}
+	_, err = pex.addAddresses("spammer", tooMany, now)
+	assert.NotNil(err)
+	assert.Equal(1, pex.peers["spammer"].violations)
+
+	// A peer is ignored after repeated violations
+	for i := 1; i < pexMaxViolations; i++ {
+		pex.addAddresses("spammer", tooMany, now)
+	}
+	_, err = pex.addAddresses("spammer", tooMany[:1], now.Add(pex.interval))
+	assert.NotNil(err)
+
+	// The states of the disconnected idle peers are pruned
+	pex.prunePeerStates(now.Add(3 * pex.interval))
+	assert.Equal(0, len(pex.peers))
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/viper"
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/util"
 	cn "github.com/pandotoken/pando/p2p/connection"
 	"github.com/pandotoken/pando/p2p/netutil"
 	pr "github.com/pandotoken/pando/p2p/peer"
@@ -29,6 +30,13 @@ type PeerDiscoveryManager struct {
 
 	seedPeerOnly bool
 
+	// Validators behind sentries, which registered with the node as their sentry
+	privatePeers map[string]*privatePeer
+	// sentryMonitor is set if the node is a validator behind sentries
+	sentryMonitor *SentryMonitor
+	// peerExchange is set if the node exchanges peer addresses with its peers
+	peerExchange *PeerExchange
+
 	// Three mechanisms for peer discovery
 	seedPeerConnector   SeedPeerConnector           // pro-actively connect to seed peers
 	peerDiscMsgHandler  PeerDiscoveryMessageHandler // pro-actively connect to peer candidates obtained from connected peers
@@ -56,13 +64,23 @@ func CreatePeerDiscoveryManager(msgr *Messenger, nodeInfo *p2ptypes.NodeInfo, ad
 	networkProtocol string, localNetworkAddr string, externalPort int, skipUPNP bool, peerTable *pr.PeerTable,
 	config PeerDiscoveryManagerConfig) (*PeerDiscoveryManager, error) {
 
+	// In sentry mode, the validator only connects to its sentries, and only accepts their connections
+	sentryMode := viper.GetBool(common.CfgP2PSentryMode)
+	if sentryMode {
+		seedPeerNetAddresses = parseSentries()
+		if len(seedPeerNetAddresses) == 0 {
+			return nil, errors.New("No sentry configured for the sentry mode")
+		}
+	}
+
 	discMgr := &PeerDiscoveryManager{
 		messenger:    msgr,
 		nodeInfo:     nodeInfo,
 		peerTable:    peerTable,
 		seedPeers:    make(map[string]*pr.Peer),
 		mutex:        &sync.Mutex{},
-		seedPeerOnly: viper.GetBool(common.CfgP2PSeedPeerOnly),
+		seedPeerOnly: viper.GetBool(common.CfgP2PSeedPeerOnly) || sentryMode,
+		privatePeers: make(map[string]*privatePeer),
 		wg:           &sync.WaitGroup{},
 	}
 
@@ -79,6 +97,14 @@ func CreatePeerDiscoveryManager(msgr *Messenger, nodeInfo *p2ptypes.NodeInfo, ad
 		return discMgr, err
 	}
 
+	if sentryMode {
+		discMgr.sentryMonitor = createSentryMonitor(discMgr, discMgr.seedPeerConnector.seedPeerNetAddresses)
+	}
+
+	if viper.GetBool(common.CfgP2PPexEnabled) && !discMgr.seedPeerOnly {
+		discMgr.peerExchange = createPeerExchange(discMgr)
+	}
+
 	inlConfig := GetDefaultInboundPeerListenerConfig()
 	discMgr.inboundPeerListener, err = createInboundPeerListener(discMgr, networkProtocol, localNetworkAddr, externalPort, skipUPNP, inlConfig)
 	if err != nil {
@@ -125,6 +151,13 @@ func (discMgr *PeerDiscoveryManager) Start(ctx context.Context) error {
 		return err
 	}
 
+	if discMgr.sentryMonitor != nil {
+		err = discMgr.sentryMonitor.Start(c)
+		if err != nil {
+			return err
+		}
+	}
+
 	if discMgr.seedPeerOnly {
 		return nil // if seed peer only, we don't need to start the peer discovery manager
 	}
@@ -134,6 +167,13 @@ func (discMgr *PeerDiscoveryManager) Start(ctx context.Context) error {
 		return err
 	}
 
+	if discMgr.peerExchange != nil {
+		err = discMgr.peerExchange.Start(c)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -147,6 +187,12 @@ func (discMgr *PeerDiscoveryManager) Wait() {
 	discMgr.seedPeerConnector.wg.Wait()
 	discMgr.inboundPeerListener.wg.Wait()
 	discMgr.peerDiscMsgHandler.wg.Wait()
+	if discMgr.sentryMonitor != nil {
+		discMgr.sentryMonitor.Wait()
+	}
+	if discMgr.peerExchange != nil {
+		discMgr.peerExchange.Wait()
+	}
 	discMgr.wg.Wait()
 }
 
@@ -173,9 +219,10 @@ func (discMgr *PeerDiscoveryManager) HandlePeerWithErrors(peer *pr.Peer) {
 	}
 
 	discMgr.peerTable.DeletePeer(peer.ID())
+	util.Trace(util.TracePeer, peer.ID(), "p2p", "disconnected", "the peer is in the error state, remote address: %v", peerRemoteAddress)
 	peer.Stop() // TODO: may need to stop peer regardless of the remote address comparison
 
-	seedPeerOnly := viper.GetBool(common.CfgP2PSeedPeerOnly)
+	seedPeerOnly := discMgr.seedPeerOnly
 
 	//shouldRetry := seedPeerOnly && peer.IsPersistent()
 	shouldRetry := (seedPeerOnly && peer.IsSeed()) || (!seedPeerOnly && !peer.IsSeed()) // avoid bombarding the seed nodes
@@ -260,6 +307,7 @@ func (discMgr *PeerDiscoveryManager) handshakeAndAddPeer(peer *pr.Peer) error {
 		logger.Errorf(errMsg)
 		return errors.New(errMsg)
 	}
+	util.Trace(util.TracePeer, peer.ID(), "p2p", "connected", "addr: %v, outbound: %v", peer.NetAddress(), peer.IsOutbound())
 
 	//discMgr.addrBook.AddAddress(peer.NetAddress(), peer.NetAddress())
 	//discMgr.addrBook.Save()
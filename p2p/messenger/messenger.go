@@ -29,6 +29,7 @@ var _ p2p.Network = (*Messenger)(nil)
 type Messenger struct {
 	discMgr       *PeerDiscoveryManager
 	natMgr        *NATManager
+	upgradeMon    *UpgradeMonitor
 	msgHandlerMap map[common.ChannelIDEnum](p2p.MessageHandler)
 
 	peerTable pr.PeerTable
@@ -41,6 +42,7 @@ type Messenger struct {
 	quit    chan struct{}
 	ctx     context.Context
 	cancel  context.CancelFunc
+	started bool
 	stopped bool
 }
 
@@ -96,6 +98,8 @@ func CreateMessenger(privKey *crypto.PrivateKey, seedPeerNetAddresses []string,
 	messenger.SetNATManager(natMgr)
 	messenger.RegisterMessageHandler(natMgr)
 
+	messenger.upgradeMon = NewUpgradeMonitor(messenger)
+
 	return messenger, nil
 }
 
@@ -119,8 +123,14 @@ func (msgr *Messenger) SetNATManager(natMgr *NATManager) {
 	msgr.natMgr = natMgr
 }
 
-// Start is called when the Messenger starts
+// Start is called when the Messenger starts. The Messenger can be started ahead of the
+// node, e.g. for snapshot sync, in which case the later calls are no-ops.
 func (msgr *Messenger) Start(ctx context.Context) error {
+	if msgr.started {
+		return nil
+	}
+	msgr.started = true
+
 	c, cancel := context.WithCancel(ctx)
 	msgr.ctx = c
 	msgr.cancel = cancel
@@ -134,6 +144,10 @@ func (msgr *Messenger) Start(ctx context.Context) error {
 		err = msgr.natMgr.Start(c)
 	}
 
+	if msgr.upgradeMon != nil {
+		msgr.upgradeMon.Start(c)
+	}
+
 	return err
 }
 
@@ -148,6 +162,9 @@ func (msgr *Messenger) Wait() {
 	if msgr.natMgr != nil {
 		msgr.natMgr.Wait()
 	}
+	if msgr.upgradeMon != nil {
+		msgr.upgradeMon.Wait()
+	}
 	msgr.wg.Wait()
 }
 
@@ -236,6 +253,19 @@ func (msgr *Messenger) Peers() []string {
 	return peerIDs
 }
 
+// PeerURLs returns the net addresses the peers listen on, in the host:port format of the seeds
+func (msgr *Messenger) PeerURLs() []string {
+	allPeers := msgr.peerTable.GetAllPeers()
+	urls := []string{}
+	for _, peer := range *allPeers {
+		if peer.NetAddress() == nil {
+			continue
+		}
+		urls = append(urls, peer.NetAddress().String())
+	}
+	return urls
+}
+
 // PeerExists indicates if the given peerID is a neighboring peer
 func (msgr *Messenger) PeerExists(peerID string) bool {
 	return msgr.peerTable.PeerExists(peerID)
@@ -298,3 +328,25 @@ func (msgr *Messenger) AttachMessageHandlersToPeer(peer *pr.Peer) {
 func (msgrConfig *MessengerConfig) SetAddressBookFilePath(filePath string) {
 	msgrConfig.addrBookFilePath = filePath
 }
+
+// SentryTopology returns the sentry setup of the node
+func (msgr *Messenger) SentryTopology() *SentryTopology {
+	return msgr.discMgr.SentryTopology()
+}
+
+// NetworkVersions returns the binary versions and the forks advertised by the peers
+func (msgr *Messenger) NetworkVersions() *NetworkVersions {
+	peers := []versionedPeer{}
+	for _, peer := range *msgr.peerTable.GetAllPeers() {
+		peers = append(peers, peer)
+	}
+	return collectNetworkVersions(msgr.nodeInfo.Version, msgr.nodeInfo.Forks, peers)
+}
+
+// SetHeightProvider sets the function returning the current block height, to warn of the upcoming
+// forks the binary of the node does not support
+func (msgr *Messenger) SetHeightProvider(heightProvider func() uint64) {
+	if msgr.upgradeMon != nil {
+		msgr.upgradeMon.SetHeightProvider(heightProvider)
+	}
+}
@@ -0,0 +1,326 @@
+package messenger
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/p2p/netutil"
+	pr "github.com/pandotoken/pando/p2p/peer"
+)
+
+const (
+	// maxPrivatePeers caps the validators which can register with a sentry
+	maxPrivatePeers = 64
+	// privatePeerExpiry is how long a registration lasts, the validators re-register on every health check
+	privatePeerExpiry = 10 * time.Minute
+	// sentryReplyTimeoutChecks is the number of health checks a sentry can miss before it is unhealthy
+	sentryReplyTimeoutChecks = 2
+)
+
+// sentryInitialCheckDelay is the delay of the first health check, which gives the seed peer connector
+// the time to connect to the sentries
+var sentryInitialCheckDelay = 5 * time.Second
+
+//
+// SentryStatus is the health of a sentry node as seen by the validator
+//
+type SentryStatus struct {
+	Address   string    `json:"address"`
+	PeerID    string    `json:"peer_id"`
+	Connected bool      `json:"connected"`
+	NumPeers  int       `json:"num_peers"` // peers of the sentry other than the validators behind it
+	LastReply time.Time `json:"last_reply"`
+	Healthy   bool      `json:"healthy"`
+}
+
+//
+// SentryTopology describes the sentry setup of the node, either as a validator behind sentries, or
+// as a sentry of the registered validators
+//
+type SentryTopology struct {
+	SentryMode        bool           `json:"sentry_mode"`
+	InboundRestricted bool           `json:"inbound_restricted"` // only the seeds or sentries can connect to the node
+	Sentries          []SentryStatus `json:"sentries"`
+	NumHealthy        int            `json:"num_healthy"`
+	PrivatePeers      []string       `json:"private_peers"` // validators registered with the node as their sentry
+}
+
+// privatePeer is a validator registered with the node as its sentry
+type privatePeer struct {
+	ip           net.IP
+	registeredAt time.Time
+}
+
+//
+// SentryMonitor registers the validator with its sentries and monitors their health. A sentry is
+// healthy if the validator is connected to it, and it recently reported peers other than the validator,
+// i.e. the validator is connected to the network through it.
+//
+type SentryMonitor struct {
+	discMgr  *PeerDiscoveryManager
+	sentries []netutil.NetAddress
+	interval time.Duration
+
+	mutex    *sync.Mutex
+	statuses map[string]*SentryStatus // map: sentry address |-> status
+	verified bool
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// parseSentries parses the sentry addresses of the config
+func parseSentries() []string {
+	return strings.FieldsFunc(viper.GetString(common.CfgP2PSentries), func(c rune) bool {
+		return c == ','
+	})
+}
+
+// createSentryMonitor creates an instance of the SentryMonitor
+func createSentryMonitor(discMgr *PeerDiscoveryManager, sentries []netutil.NetAddress) *SentryMonitor {
+	sm := &SentryMonitor{
+		discMgr:  discMgr,
+		sentries: sentries,
+		interval: time.Duration(viper.GetInt(common.CfgP2PSentryCheckIntervalSecs)) * time.Second,
+		mutex:    &sync.Mutex{},
+		statuses: make(map[string]*SentryStatus),
+		wg:       &sync.WaitGroup{},
+	}
+	for _, sentry := range sentries {
+		sm.statuses[sentry.String()] = &SentryStatus{Address: sentry.String()}
+	}
+	return sm
+}
+
+// Start is called when the SentryMonitor starts
+func (sm *SentryMonitor) Start(ctx context.Context) error {
+	c, cancel := context.WithCancel(ctx)
+	sm.ctx = c
+	sm.cancel = cancel
+
+	logger.Infof("Sentry mode enabled, only connecting to the sentries: %v", sm.sentries)
+
+	sm.wg.Add(1)
+	go sm.mainLoop()
+	return nil
+}
+
+// Stop is called when the SentryMonitor stops
+func (sm *SentryMonitor) Stop() {
+	sm.cancel()
+}
+
+// Wait suspends the caller goroutine
+func (sm *SentryMonitor) Wait() {
+	sm.wg.Wait()
+}
+
+func (sm *SentryMonitor) mainLoop() {
+	defer sm.wg.Done()
+
+	timer := time.NewTimer(sentryInitialCheckDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-sm.ctx.Done():
+			return
+		case <-timer.C:
+			sm.check()
+			timer.Reset(sm.interval)
+		}
+	}
+}
+
+// check registers the validator with the connected sentries, which reply with their status, and
+// marks the sentries without a recent reply as unhealthy
+func (sm *SentryMonitor) check() {
+	now := time.Now()
+	for _, sentry := range sm.sentries {
+		sentry := sentry
+		peer := sm.discMgr.peerTable.GetPeerWithAddr(&sentry)
+
+		sm.mutex.Lock()
+		status := sm.statuses[sentry.String()]
+		status.Connected = peer != nil
+		if peer != nil {
+			status.PeerID = peer.ID()
+		}
+		stale := now.Sub(status.LastReply) > sentryReplyTimeoutChecks*sm.interval
+		sm.setHealthy(status, status.Healthy && status.Connected && !stale)
+		sm.mutex.Unlock()
+
+		if peer != nil {
+			peer.Send(common.ChannelIDPeerDiscovery, PeerDiscoveryMessage{
+				Type: sentryRegisterType,
+			})
+		} else {
+			logger.Warnf("Not connected to sentry %v", sentry.String())
+		}
+	}
+
+	if numHealthy := sm.numHealthy(); numHealthy == 0 {
+		logger.Errorf("None of the %v sentries is healthy, the validator is cut off from the network", len(sm.sentries))
+	} else if numHealthy < len(sm.sentries) {
+		logger.Warnf("Only %v of the %v sentries are healthy", numHealthy, len(sm.sentries))
+	}
+}
+
+// handleStatus handles the status reply of a sentry, the addresses are the other peers of the sentry
+func (sm *SentryMonitor) handleStatus(peer *pr.Peer, addresses []pr.PeerIDAddress) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	status, ok := sm.statuses[peer.NetAddress().String()]
+	if !ok {
+		logger.Debugf("Ignore sentry status from %v, which is not a sentry", peer.ID())
+		return
+	}
+	status.PeerID = peer.ID()
+	status.Connected = true
+	status.NumPeers = len(addresses)
+	status.LastReply = time.Now()
+	sm.setHealthy(status, status.NumPeers > 0)
+
+	if status.Healthy && !sm.verified {
+		sm.verified = true
+		logger.Infof("Verified the connectivity through sentry %v, which has %v peers", status.Address, status.NumPeers)
+	}
+}
+
+// setHealthy updates the health of the sentry, and logs the transitions. The caller needs to hold the lock.
+func (sm *SentryMonitor) setHealthy(status *SentryStatus, healthy bool) {
+	if status.Healthy == healthy {
+		return
+	}
+	status.Healthy = healthy
+	if healthy {
+		logger.Infof("Sentry %v is healthy, numPeers: %v", status.Address, status.NumPeers)
+	} else {
+		logger.Warnf("Sentry %v is unhealthy, connected: %v, numPeers: %v, lastReply: %v",
+			status.Address, status.Connected, status.NumPeers, status.LastReply)
+	}
+}
+
+func (sm *SentryMonitor) numHealthy() int {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	numHealthy := 0
+	for _, status := range sm.statuses {
+		if status.Healthy {
+			numHealthy++
+		}
+	}
+	return numHealthy
+}
+
+// Statuses returns the statuses of the sentries, in the order of the config
+func (sm *SentryMonitor) Statuses() []SentryStatus {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	statuses := []SentryStatus{}
+	for _, sentry := range sm.sentries {
+		statuses = append(statuses, *sm.statuses[sentry.String()])
+	}
+	return statuses
+}
+
+// registerPrivatePeer registers the validator behind the node as its sentry. The address of the validator
+// is no longer shared with the other peers, and its connections are accepted even if the node is full.
+func (discMgr *PeerDiscoveryManager) registerPrivatePeer(peer *pr.Peer) bool {
+	discMgr.mutex.Lock()
+	defer discMgr.mutex.Unlock()
+
+	now := time.Now()
+	for id, pp := range discMgr.privatePeers {
+		if now.Sub(pp.registeredAt) > privatePeerExpiry {
+			delete(discMgr.privatePeers, id)
+		}
+	}
+
+	_, registered := discMgr.privatePeers[peer.ID()]
+	if !registered && len(discMgr.privatePeers) >= maxPrivatePeers {
+		logger.Warnf("Too many private peers, ignore the registration of %v", peer.ID())
+		return false
+	}
+	var ip net.IP
+	if tcpAddr, ok := peer.GetRemoteAddress().(*net.TCPAddr); ok {
+		ip = tcpAddr.IP
+	}
+	discMgr.privatePeers[peer.ID()] = &privatePeer{
+		ip:           ip,
+		registeredAt: now,
+	}
+	if !registered {
+		logger.Infof("Registered private peer %v", peer.ID())
+	}
+	return true
+}
+
+func (discMgr *PeerDiscoveryManager) isPrivatePeer(peerID string) bool {
+	discMgr.mutex.Lock()
+	defer discMgr.mutex.Unlock()
+
+	_, ok := discMgr.privatePeers[peerID]
+	return ok
+}
+
+func (discMgr *PeerDiscoveryManager) isPrivatePeerIP(ip net.IP) bool {
+	discMgr.mutex.Lock()
+	defer discMgr.mutex.Unlock()
+
+	for _, pp := range discMgr.privatePeers {
+		if pp.ip.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterPrivatePeers removes the private peers, and the excluded peer, from the addresses
+func (discMgr *PeerDiscoveryManager) filterPrivatePeers(peerIDAddrs []pr.PeerIDAddress, excludedPeerID string) []pr.PeerIDAddress {
+	filtered := []pr.PeerIDAddress{}
+	for _, idAddr := range peerIDAddrs {
+		if idAddr.ID != excludedPeerID && !discMgr.isPrivatePeer(idAddr.ID) {
+			filtered = append(filtered, idAddr)
+		}
+	}
+	return filtered
+}
+
+// SentryTopology returns the sentry setup of the node
+func (discMgr *PeerDiscoveryManager) SentryTopology() *SentryTopology {
+	topology := &SentryTopology{
+		SentryMode:        discMgr.sentryMonitor != nil,
+		InboundRestricted: discMgr.seedPeerOnly,
+		Sentries:          []SentryStatus{},
+		PrivatePeers:      []string{},
+	}
+	if discMgr.sentryMonitor != nil {
+		topology.Sentries = discMgr.sentryMonitor.Statuses()
+		for _, status := range topology.Sentries {
+			if status.Healthy {
+				topology.NumHealthy++
+			}
+		}
+	}
+
+	discMgr.mutex.Lock()
+	for id := range discMgr.privatePeers {
+		topology.PrivatePeers = append(topology.PrivatePeers, id)
+	}
+	discMgr.mutex.Unlock()
+	sort.Strings(topology.PrivatePeers)
+
+	return topology
+}
@@ -0,0 +1,85 @@
+package messenger
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/p2p/netutil"
+)
+
+func TestSentryMode(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sentryPort := 24561
+	peerPort := 24562
+	validatorPort := 24563
+	// The sentry is addressed through a different loopback IP, so that the validator can tell the
+	// connections of the sentry and the other peer apart
+	sentryAddr := "127.0.0.2:" + strconv.Itoa(sentryPort)
+	validatorAddr := "127.0.0.1:" + strconv.Itoa(validatorPort)
+
+	sentry := newTestMessenger([]string{}, sentryPort)
+	require.Nil(sentry.Start(ctx))
+	peer := newTestMessenger([]string{sentryAddr}, peerPort)
+	require.Nil(peer.Start(ctx))
+
+	sentryInitialCheckDelay = 500 * time.Millisecond
+	viper.Set(common.CfgP2PSentryMode, true)
+	viper.Set(common.CfgP2PSentries, sentryAddr)
+	viper.Set(common.CfgP2PSentryCheckIntervalSecs, 1)
+	defer func() {
+		viper.Set(common.CfgP2PSentryMode, false)
+		viper.Set(common.CfgP2PSentries, "")
+		viper.Set(common.CfgP2PSentryCheckIntervalSecs, 30)
+	}()
+	// The seeds are replaced by the sentries
+	validator := newTestMessenger([]string{validatorAddr}, validatorPort)
+	require.NotNil(validator.discMgr.sentryMonitor)
+	require.Nil(validator.Start(ctx))
+
+	// The validator registers with the sentry, which is healthy as it has another peer
+	require.Eventually(func() bool {
+		return validator.SentryTopology().NumHealthy == 1
+	}, 10*time.Second, 100*time.Millisecond)
+	topology := validator.SentryTopology()
+	assert.True(topology.SentryMode)
+	assert.True(topology.InboundRestricted)
+	require.Equal(1, len(topology.Sentries))
+	assert.Equal(sentryAddr, topology.Sentries[0].Address)
+	assert.Equal(sentry.ID(), topology.Sentries[0].PeerID)
+	assert.Equal(1, topology.Sentries[0].NumPeers)
+
+	sentryTopology := sentry.SentryTopology()
+	assert.False(sentryTopology.SentryMode)
+	assert.Equal([]string{validator.ID()}, sentryTopology.PrivatePeers)
+
+	// The sentry does not share the address of the validator
+	for _, idAddr := range sentry.discMgr.filterPrivatePeers(sentry.discMgr.peerTable.GetSelection(), "") {
+		assert.NotEqual(validator.ID(), idAddr.ID)
+	}
+
+	// The validator refuses the direct connections of the other peers
+	addr, err := netutil.NewNetAddressString(validatorAddr)
+	require.Nil(err)
+	_, err = peer.discMgr.connectToOutboundPeer(addr, false)
+	assert.NotNil(err)
+	assert.False(validator.PeerExists(peer.ID()))
+
+	// The sentry turns unhealthy once it loses its other peer
+	peer.discMgr.peerTable.GetPeer(sentry.ID()).Stop()
+	sentry.discMgr.peerTable.DeletePeer(peer.ID())
+	require.Eventually(func() bool {
+		return validator.SentryTopology().NumHealthy == 0
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.True(validator.SentryTopology().Sentries[0].Connected)
+}
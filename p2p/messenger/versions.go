@@ -0,0 +1,230 @@
+package messenger
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/metrics"
+)
+
+const (
+	// UpgradeAlertWindow is the number of blocks ahead of a fork activation the operators are warned
+	// if their binary does not support the fork
+	UpgradeAlertWindow = uint64(100000)
+	// upgradeCheckInterval is the interval of the checks of the forks advertised by the peers
+	upgradeCheckInterval = 10 * time.Minute
+	// unknownVersion stands for the version of the peers running a binary which does not advertise it
+	unknownVersion = "unknown"
+)
+
+var (
+	unsupportedForksGauge     = metrics.NewRegisteredGauge("p2p/upgrade/unsupported_forks", nil)
+	mismatchedForksGauge      = metrics.NewRegisteredGauge("p2p/upgrade/mismatched_forks", nil)
+	peersUnsupportedForkGauge = metrics.NewRegisteredGauge("p2p/upgrade/peers_unsupported_fork", nil)
+)
+
+//
+// ForkSupport is a fork advertised by the peers
+//
+type ForkSupport struct {
+	Name        string `json:"name"`
+	Height      uint64 `json:"height"`       // the activation height advertised by most of the peers
+	LocalHeight uint64 `json:"local_height"` // the activation height in the local binary, 0 if the fork is not supported
+	NumPeers    int    `json:"num_peers"`
+	Supported   bool   `json:"supported"`
+}
+
+// Mismatched indicates that the local binary activates the fork at a different height than the peers
+func (fs *ForkSupport) Mismatched() bool {
+	return fs.Supported && fs.LocalHeight != fs.Height
+}
+
+//
+// NetworkVersions aggregates the binary versions and the forks advertised by the peers
+//
+type NetworkVersions struct {
+	LocalVersion string         `json:"local_version"`
+	NumPeers     int            `json:"num_peers"`
+	PeerVersions map[string]int `json:"peer_versions"` // map: version |-> number of peers
+	Forks        []*ForkSupport `json:"forks"`
+}
+
+// UpgradeAlerts returns the forks the local binary does not support or activates at a different height
+// than the peers, and which activate within the window from the current height, or have already
+// activated, in which case the node is likely to be on a different chain than its peers.
+func (nv *NetworkVersions) UpgradeAlerts(currentHeight uint64, window uint64) []*ForkSupport {
+	alerts := []*ForkSupport{}
+	for _, fork := range nv.Forks {
+		if fork.Supported && !fork.Mismatched() {
+			continue
+		}
+		if fork.Height > currentHeight && fork.Height-currentHeight > window {
+			continue
+		}
+		alerts = append(alerts, fork)
+	}
+	return alerts
+}
+
+// versionedPeer is a peer advertising the version and the forks of its binary
+type versionedPeer interface {
+	Version() string
+	Forks() []common.Fork
+}
+
+// collectNetworkVersions aggregates the versions and the forks of the peers
+func collectNetworkVersions(localVersion string, localForks []common.Fork, peers []versionedPeer) *NetworkVersions {
+	nv := &NetworkVersions{
+		LocalVersion: localVersion,
+		NumPeers:     len(peers),
+		PeerVersions: make(map[string]int),
+		Forks:        []*ForkSupport{},
+	}
+	localHeights := make(map[string]uint64)
+	for _, fork := range localForks {
+		localHeights[fork.Name] = fork.Height
+	}
+
+	heightVotes := make(map[string]map[uint64]int) // map: fork |-> height |-> number of peers
+	for _, peer := range peers {
+		version := peer.Version()
+		if len(version) == 0 {
+			version = unknownVersion
+		}
+		nv.PeerVersions[version]++
+		for _, fork := range peer.Forks() {
+			if heightVotes[fork.Name] == nil {
+				heightVotes[fork.Name] = make(map[uint64]int)
+			}
+			heightVotes[fork.Name][fork.Height]++
+		}
+	}
+
+	for name, votes := range heightVotes {
+		fs := &ForkSupport{Name: name}
+		for height, numPeers := range votes {
+			fs.NumPeers += numPeers
+			if numPeers > votes[fs.Height] || (numPeers == votes[fs.Height] && height < fs.Height) {
+				fs.Height = height
+			}
+		}
+		fs.LocalHeight, fs.Supported = localHeights[name]
+		nv.Forks = append(nv.Forks, fs)
+	}
+	sort.Slice(nv.Forks, func(i, j int) bool {
+		if nv.Forks[i].Height != nv.Forks[j].Height {
+			return nv.Forks[i].Height < nv.Forks[j].Height
+		}
+		return nv.Forks[i].Name < nv.Forks[j].Name
+	})
+	return nv
+}
+
+//
+// UpgradeMonitor periodically checks the forks advertised by the peers, and warns the operator of
+// the upcoming forks the binary of the node does not support
+//
+type UpgradeMonitor struct {
+	msgr     *Messenger
+	interval time.Duration
+
+	mutex          *sync.Mutex
+	heightProvider func() uint64
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewUpgradeMonitor creates an instance of UpgradeMonitor
+func NewUpgradeMonitor(msgr *Messenger) *UpgradeMonitor {
+	return &UpgradeMonitor{
+		msgr:     msgr,
+		interval: upgradeCheckInterval,
+		mutex:    &sync.Mutex{},
+		wg:       &sync.WaitGroup{},
+	}
+}
+
+// SetHeightProvider sets the function returning the current block height, the checks are skipped
+// until it is set
+func (um *UpgradeMonitor) SetHeightProvider(heightProvider func() uint64) {
+	um.mutex.Lock()
+	defer um.mutex.Unlock()
+	um.heightProvider = heightProvider
+}
+
+// Start is called when the monitor starts
+func (um *UpgradeMonitor) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	um.ctx = c
+	um.cancel = cancel
+
+	um.wg.Add(1)
+	go um.mainLoop()
+}
+
+// Stop is called when the monitor stops
+func (um *UpgradeMonitor) Stop() {
+	if um.cancel != nil {
+		um.cancel()
+	}
+}
+
+// Wait suspends the caller goroutine
+func (um *UpgradeMonitor) Wait() {
+	um.wg.Wait()
+}
+
+func (um *UpgradeMonitor) mainLoop() {
+	defer um.wg.Done()
+
+	ticker := time.NewTicker(um.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-um.ctx.Done():
+			return
+		case <-ticker.C:
+			um.check()
+		}
+	}
+}
+
+// check warns of the forks the local binary is not ready for, and updates the metrics
+func (um *UpgradeMonitor) check() []*ForkSupport {
+	um.mutex.Lock()
+	heightProvider := um.heightProvider
+	um.mutex.Unlock()
+	if heightProvider == nil {
+		return nil
+	}
+
+	nv := um.msgr.NetworkVersions()
+	currentHeight := heightProvider()
+	alerts := nv.UpgradeAlerts(currentHeight, UpgradeAlertWindow)
+
+	unsupported, mismatched, numPeers := 0, 0, 0
+	for _, fork := range alerts {
+		if fork.Mismatched() {
+			mismatched++
+			logger.Warnf("Fork %v activates at height %v for %v peers, but at height %v for the local binary %v, current height: %v. Please check the version of the binary.",
+				fork.Name, fork.Height, fork.NumPeers, fork.LocalHeight, nv.LocalVersion, currentHeight)
+		} else {
+			unsupported++
+			logger.Warnf("Fork %v activates at height %v according to %v peers, but is not supported by the local binary %v, current height: %v. Please upgrade the binary.",
+				fork.Name, fork.Height, fork.NumPeers, nv.LocalVersion, currentHeight)
+		}
+		if fork.NumPeers > numPeers {
+			numPeers = fork.NumPeers
+		}
+	}
+	unsupportedForksGauge.Update(int64(unsupported))
+	mismatchedForksGauge.Update(int64(mismatched))
+	peersUnsupportedForkGauge.Update(int64(numPeers))
+	return alerts
+}
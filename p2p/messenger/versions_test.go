@@ -0,0 +1,69 @@
+package messenger
+
+import (
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testVersionedPeer struct {
+	version string
+	forks   []common.Fork
+}
+
+func (p *testVersionedPeer) Version() string {
+	return p.version
+}
+
+func (p *testVersionedPeer) Forks() []common.Fork {
+	return p.forks
+}
+
+func TestNetworkVersions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	localForks := []common.Fork{{Name: "SmartContract", Height: 10}, {Name: "Escrow", Height: 5000}}
+	upgradedForks := []common.Fork{{Name: "SmartContract", Height: 10}, {Name: "Escrow", Height: 6000}, {Name: "Rametron", Height: 8000}}
+	peers := []versionedPeer{
+		&testVersionedPeer{version: "2.2.1", forks: localForks},
+		&testVersionedPeer{version: "2.3.0", forks: upgradedForks},
+		&testVersionedPeer{version: "2.3.0", forks: upgradedForks},
+		&testVersionedPeer{}, // older binary
+	}
+
+	nv := collectNetworkVersions("2.2.1", localForks, peers)
+	assert.Equal(4, nv.NumPeers)
+	assert.Equal(map[string]int{"2.2.1": 1, "2.3.0": 2, unknownVersion: 1}, nv.PeerVersions)
+	require.Equal(3, len(nv.Forks))
+
+	smartContract, escrow, rametron := nv.Forks[0], nv.Forks[1], nv.Forks[2]
+	assert.Equal("SmartContract", smartContract.Name)
+	assert.True(smartContract.Supported)
+	assert.False(smartContract.Mismatched())
+	assert.Equal(3, smartContract.NumPeers)
+
+	// Most of the peers activate the escrow at a later height
+	assert.Equal("Escrow", escrow.Name)
+	assert.Equal(uint64(6000), escrow.Height)
+	assert.Equal(uint64(5000), escrow.LocalHeight)
+	assert.True(escrow.Mismatched())
+
+	assert.Equal("Rametron", rametron.Name)
+	assert.False(rametron.Supported)
+	assert.Equal(2, rametron.NumPeers)
+
+	// Only the forks within the window are alerted
+	assert.Equal(0, len(nv.UpgradeAlerts(100, 1000)))
+	alerts := nv.UpgradeAlerts(5500, 1000)
+	require.Equal(1, len(alerts))
+	assert.Equal("Escrow", alerts[0].Name)
+	alerts = nv.UpgradeAlerts(7500, 1000)
+	assert.Equal(2, len(alerts), "the activated forks are still alerted")
+
+	// No alert if the binary is up to date
+	nv = collectNetworkVersions("2.3.0", upgradedForks, peers)
+	assert.Equal(0, len(nv.UpgradeAlerts(7500, 1000)))
+}
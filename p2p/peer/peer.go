@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +25,12 @@ var logger *log.Entry = log.WithFields(log.Fields{"prefix": "p2p"})
 
 const maxExtraHandshakeInfo = 4096
 
+// The prefixes of the extra handshake info, which the peers running an older binary ignore
+const (
+	handshakeVersionPrefix = "version:"
+	handshakeForksPrefix   = "forks:"
+)
+
 //
 // Peer models a peer node in a network
 //
@@ -129,7 +136,7 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 			sendError = rlp.Encode(peer.connection.GetBufNetconn(), sourceNodeInfo)
 		},
 		func() {
-			s = rlp.NewStream(peer.connection.GetBufReader(), 1024)
+			s = rlp.NewStream(peer.connection.GetBufReader(), maxExtraHandshakeInfo)
 			recvError = s.Decode(&targetPeerNodeInfo)
 		},
 	)
@@ -158,6 +165,14 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 			if sendError != nil {
 				return
 			}
+			sendError = rlp.Encode(peer.connection.GetBufNetconn(), handshakeVersionPrefix+sourceNodeInfo.Version)
+			if sendError != nil {
+				return
+			}
+			sendError = rlp.Encode(peer.connection.GetBufNetconn(), handshakeForksPrefix+p2ptypes.EncodeForks(sourceNodeInfo.Forks))
+			if sendError != nil {
+				return
+			}
 			sendError = rlp.Encode(peer.connection.GetBufNetconn(), "EOH")
 		},
 		func() {
@@ -182,6 +197,7 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 				if msg == "EOH" {
 					return
 				}
+				peer.handleExtraHandshakeInfo(msg)
 			}
 		},
 	)
@@ -220,6 +236,22 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 	return nil
 }
 
+// handleExtraHandshakeInfo records the version and the forks advertised by the peer, the
+// unknown info is skipped for forward compatibility
+func (peer *Peer) handleExtraHandshakeInfo(msg string) {
+	switch {
+	case strings.HasPrefix(msg, handshakeVersionPrefix):
+		peer.nodeInfo.Version = strings.TrimPrefix(msg, handshakeVersionPrefix)
+	case strings.HasPrefix(msg, handshakeForksPrefix):
+		forks, err := p2ptypes.DecodeForks(strings.TrimPrefix(msg, handshakeForksPrefix))
+		if err != nil {
+			logger.Warnf("Failed to decode the forks of the peer: %v", err)
+			return
+		}
+		peer.nodeInfo.Forks = forks
+	}
+}
+
 // Send sends the given message through the specified channel to the target peer
 func (peer *Peer) Send(channelID cmn.ChannelIDEnum, message interface{}) bool {
 	success := peer.connection.EnqueueMessage(channelID, message)
@@ -290,6 +322,16 @@ func (peer *Peer) SetPort(port uint16) {
 }
 
 // ID returns the unique idenitifier of the peer in the P2P network
+// Version returns the binary version of the peer, empty if the peer did not advertise it
+func (peer *Peer) Version() string {
+	return peer.nodeInfo.Version
+}
+
+// Forks returns the forks supported by the binary of the peer
+func (peer *Peer) Forks() []cmn.Fork {
+	return peer.nodeInfo.Forks
+}
+
 func (peer *Peer) ID() string {
 	peerID := peer.nodeInfo.PubKey.Address() // use the blockchain address as the peer ID
 	id := peerID.Hex()
@@ -29,7 +29,8 @@ const (
 	// max peers returned by GetSelection
 	maxGetSelection = 250
 
-	dbKey = "p2pPeer"
+	dbKey    = "p2pPeer"
+	pexDBKey = "p2pPex"
 )
 
 //
@@ -266,6 +267,32 @@ func (pt *PeerTable) RetrievePreviousPeers() ([]*nu.NetAddress, error) {
 	return nu.NewNetAddressStrings(addrs)
 }
 
+// PersistExchangedAddresses persists the peer addresses learned through peer exchange, so that the
+// node can bootstrap from them after a restart without relying on the seeds
+func (pt *PeerTable) PersistExchangedAddresses(addrs []*nu.NetAddress) {
+	addrStrs := make([]string, len(addrs))
+	for i, addr := range addrs {
+		addrStrs[i] = addr.String()
+	}
+	pt.writeToDB(pexDBKey, strings.Join(addrStrs, "|"))
+}
+
+// RetrieveExchangedAddresses returns the persisted peer addresses learned through peer exchange
+func (pt *PeerTable) RetrieveExchangedAddresses() ([]*nu.NetAddress, error) {
+	if pt.db == nil {
+		return []*nu.NetAddress{}, fmt.Errorf("peerTable DB not ready yet")
+	}
+
+	dat, err := pt.db.Get([]byte(pexDBKey), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(dat) == 0 {
+		return []*nu.NetAddress{}, nil
+	}
+	return nu.NewNetAddressStrings(strings.Split(string(dat), "|"))
+}
+
 func (pt *PeerTable) persistPeers() {
 	maxPeerPersistence := viper.GetInt(common.CfgMaxNumPersistentPeers)
 	numPeers := len(pt.peers)
@@ -95,6 +95,10 @@ func TestPeerHandshakeAndCommunication(t *testing.T) {
 	// ID checks
 	assert.Equal(receivedPeerAAddr, inboundPeer.ID())
 
+	// Version and forks checks
+	assert.Equal(peerBNodeInfo.Version, inboundPeer.Version())
+	assert.Equal(common.SupportedForks(), inboundPeer.Forks())
+
 	// Persistency checks
 	inboundPeer.SetPersistency(false)
 	assert.False(inboundPeer.IsPersistent())
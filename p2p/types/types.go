@@ -2,9 +2,13 @@ package types
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/version"
 )
 
 //
@@ -24,6 +28,10 @@ type NodeInfo struct {
 	PubKey      *crypto.PublicKey  `rlp:"-"`
 	PubKeyBytes common.Bytes       // needed for RLP serialization
 	Port        uint16
+
+	// Exchanged as extra handshake info, empty for the peers running an older binary
+	Version string        `rlp:"-"`
+	Forks   []common.Fork `rlp:"-"`
 }
 
 // CreateNodeInfo creates an instance of NodeInfo
@@ -44,10 +52,52 @@ func CreateLocalNodeInfo(privateKey *crypto.PrivateKey, port uint16) NodeInfo {
 		PubKey:      pubKey,
 		PubKeyBytes: pubKey.ToBytes(),
 		Port:        port,
+		Version:     version.Version,
+		Forks:       features.Forks(),
 	}
 	return nodeInfo
 }
 
+// MaxForksInfoSize caps the size of the encoded forks, for the handshake to fit in the
+// input limit of the peers
+const MaxForksInfoSize = 512
+
+// EncodeForks encodes the forks as name:height pairs separated by commas. If the encoding
+// exceeds MaxForksInfoSize, the most recently introduced forks are kept.
+func EncodeForks(forks []common.Fork) string {
+	encoded := []string{}
+	size := 0
+	for i := len(forks) - 1; i >= 0; i-- {
+		entry := forks[i].Name + ":" + strconv.FormatUint(forks[i].Height, 10)
+		if size+len(entry)+1 > MaxForksInfoSize {
+			break
+		}
+		size += len(entry) + 1
+		encoded = append([]string{entry}, encoded...)
+	}
+	return strings.Join(encoded, ",")
+}
+
+// DecodeForks decodes the forks encoded by EncodeForks
+func DecodeForks(s string) ([]common.Fork, error) {
+	forks := []common.Fork{}
+	if len(s) == 0 {
+		return forks, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		sep := strings.LastIndex(entry, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid fork: %v", entry)
+		}
+		height, err := strconv.ParseUint(entry[sep+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fork height: %v", entry)
+		}
+		forks = append(forks, common.Fork{Name: entry[:sep], Height: height})
+	}
+	return forks, nil
+}
+
 const (
 	// PingSignal represents a ping signal to a peer
 	PingSignal = byte(0x0)
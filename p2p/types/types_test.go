@@ -3,9 +3,11 @@ package types
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/crypto"
 	"github.com/pandotoken/pando/rlp"
 )
@@ -55,3 +57,34 @@ func TestNodeInfoRLPEncoding2(t *testing.T) {
 
 	assert.Equal(nodeInfo.PubKey.Address(), decodedNodeInfo.PubKey.Address())
 }
+
+func TestForksEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	forks := []common.Fork{{Name: "SmartContract", Height: 1}, {Name: "Escrow", Height: 1234567}}
+	encoded := EncodeForks(forks)
+	assert.Equal("SmartContract:1,Escrow:1234567", encoded)
+	decoded, err := DecodeForks(encoded)
+	assert.Nil(err)
+	assert.Equal(forks, decoded)
+
+	decoded, err = DecodeForks("")
+	assert.Nil(err)
+	assert.Equal(0, len(decoded))
+	_, err = DecodeForks("Escrow")
+	assert.NotNil(err)
+	_, err = DecodeForks("Escrow:abc")
+	assert.NotNil(err)
+
+	// The most recent forks are kept if the encoding is too large
+	forks = []common.Fork{}
+	for i := 0; i < 100; i++ {
+		forks = append(forks, common.Fork{Name: fmt.Sprintf("Fork%v", i), Height: uint64(i)})
+	}
+	encoded = EncodeForks(forks)
+	assert.True(len(encoded) <= MaxForksInfoSize)
+	decoded, err = DecodeForks(encoded)
+	assert.Nil(err)
+	assert.Equal(forks[len(forks)-1], decoded[len(decoded)-1])
+	assert.Equal(forks[len(forks)-len(decoded):], decoded)
+}
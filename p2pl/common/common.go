@@ -1,6 +1,10 @@
 package common
 
-import "io"
+import (
+	"io"
+
+	cmn "github.com/pandotoken/pando/common"
+)
 
 const (
 	MaxBlockMessageSize  = 12 * 1024 * 1024 // 12 MBytes
@@ -11,6 +15,14 @@ const (
 	MaxRecvRate = int64(128 * 1024 * 1024) // 128 Mbps
 )
 
+// MaxMessageSize returns the size limit of the messages received on the channel
+func MaxMessageSize(channelID cmn.ChannelIDEnum) int {
+	if channelID == cmn.ChannelIDBlock || channelID == cmn.ChannelIDProposal {
+		return MaxBlockMessageSize
+	}
+	return MaxNormalMessageSize
+}
+
 type ReadWriteCloser interface {
 	io.Reader
 	io.Writer
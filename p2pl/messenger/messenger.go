@@ -3,6 +3,7 @@ package messenger
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"strconv"
@@ -91,6 +92,7 @@ type Messenger struct {
 	quit    chan struct{}
 	ctx     context.Context
 	cancel  context.CancelFunc
+	started bool
 	stopped bool
 }
 
@@ -312,6 +314,7 @@ func (msgr *Messenger) processLoop(ctx context.Context) {
 			peer.Start(msgr.ctx)
 			peer.OpenStreams()
 			logger.Infof("Peer connected, id: %v, addrs: %v", pr.ID, pr.Addrs)
+			util.Trace(util.TracePeer, pid.String(), "p2p", "connected", "addrs: %v, outbound: %v", pr.Addrs, isOutbound)
 		case pid := <-msgr.newPeerError:
 			peer := msgr.peerTable.GetPeer(pid)
 			if peer == nil {
@@ -321,6 +324,7 @@ func (msgr *Messenger) processLoop(ctx context.Context) {
 			peer.Stop()
 			msgr.peerTable.DeletePeer(pid)
 			msgr.host.Network().ClosePeer(pid)
+			util.Trace(util.TracePeer, pid.String(), "p2p", "disconnected", "the peer failed")
 		case pid := <-msgr.peerDead:
 			peer := msgr.peerTable.GetPeer(pid)
 			if peer == nil {
@@ -337,6 +341,7 @@ func (msgr *Messenger) processLoop(ctx context.Context) {
 			peer.Stop()
 			msgr.peerTable.DeletePeer(pid)
 			logger.Infof("Peer disconnected, id: %v, addrs: %v", peer.ID(), peer.Addrs())
+			util.Trace(util.TracePeer, pid.String(), "p2p", "disconnected", "the peer is dead")
 		case <-ctx.Done():
 			log.Debug("messenger processloop shutting down")
 			return
@@ -450,8 +455,14 @@ func (msgr *Messenger) maintainSufficientConnections(ctx context.Context) {
 	}
 }
 
-// Start is called when the Messenger starts
+// Start is called when the Messenger starts. The Messenger can be started ahead of the
+// node, e.g. for snapshot sync, in which case the later calls are no-ops.
 func (msgr *Messenger) Start(ctx context.Context) error {
+	if msgr.started {
+		return nil
+	}
+	msgr.started = true
+
 	c, cancel := context.WithCancel(ctx)
 	msgr.ctx = c
 	msgr.cancel = cancel
@@ -773,6 +784,7 @@ func (msgr *Messenger) registerStreamHandler(channelID common.ChannelIDEnum) {
 			remotePeer.Start(msgr.ctx)
 
 			logger.Infof("Peer connected (via stream), id: %v, addrs: %v", remotePeer.ID, remotePeer.Addrs)
+			util.Trace(util.TracePeer, peerID.String(), "p2p", "connected", "via stream, addr: %v", strm.Conn().RemoteMultiaddr())
 		}
 
 		reuseStream := viper.GetBool(common.CfgP2PReuseStream)
@@ -786,11 +798,17 @@ func (msgr *Messenger) registerStreamHandler(channelID common.ChannelIDEnum) {
 			remotePeer.AcceptStream(channelID, stream)
 
 		} else {
-			rawPeerMsg, err := ioutil.ReadAll(strm)
+			maxMessageSize := p2pcmn.MaxMessageSize(channelID)
+			rawPeerMsg, err := ioutil.ReadAll(io.LimitReader(strm, int64(maxMessageSize)+1))
 			if err != nil {
 				logger.Warnf("Failed to read stream, %v. channel: %v, peer: %v", err, channelID, peerID)
 				return
 			}
+			if len(rawPeerMsg) > maxMessageSize {
+				logger.Errorf("Message ignored since it exceeds the peer message size limit, channel: %v, peer: %v", channelID, peerID)
+				strm.Reset()
+				return
+			}
 			msgHandler := msgr.msgHandlerMap[channelID]
 			message, err := msgHandler.ParseMessage(peerID.String(), channelID, rawPeerMsg)
 			if err != nil {
@@ -30,6 +30,7 @@ var Channels = []cmn.ChannelIDEnum{
 	cmn.ChannelIDPeerDiscovery,
 	cmn.ChannelIDPing,
 	cmn.ChannelIDGuardian,
+	cmn.ChannelIDSnapshot,
 }
 
 //
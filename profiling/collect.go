@@ -0,0 +1,93 @@
+package profiling
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// profileSpec describes a profile to be included in the bundle.
+type profileSpec struct {
+	fileName string
+	path     string
+	timed    bool // whether the profile is collected over the requested duration
+}
+
+var bundledProfiles = []profileSpec{
+	{fileName: "cpu.pprof", path: "pprof/profile", timed: true},
+	{fileName: "trace.out", path: "pprof/trace", timed: true},
+	{fileName: "heap.pprof", path: "pprof/heap"},
+	{fileName: "block.pprof", path: "pprof/block"},
+	{fileName: "mutex.pprof", path: "pprof/mutex"},
+	{fileName: "goroutine.txt", path: "pprof/goroutine?debug=2"},
+	{fileName: "sampling.json", path: "sampling"},
+}
+
+// Collect downloads the CPU profile and the execution trace over the given duration,
+// followed by the heap, block, mutex and goroutine profiles, and writes them into
+// a gzipped tarball.
+func Collect(address string, authToken string, duration time.Duration, w io.Writer) error {
+	seconds := int(duration / time.Second)
+	if seconds < 1 {
+		return fmt.Errorf("Profile duration needs to be at least 1 second")
+	}
+
+	client := &http.Client{Timeout: duration + 30*time.Second}
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, spec := range bundledProfiles {
+		url := "http://" + address + PathPrefix + spec.path
+		if spec.timed {
+			url = fmt.Sprintf("%s?seconds=%d", url, seconds)
+		}
+		data, err := fetch(client, url, authToken)
+		if err != nil {
+			return fmt.Errorf("Failed to collect %v: %v", spec.fileName, err)
+		}
+		hdr := &tar.Header{
+			Name:    spec.fileName,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func fetch(client *http.Client, url string, authToken string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+authToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %v: %s", resp.Status, data)
+	}
+	return data, nil
+}
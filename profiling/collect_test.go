@@ -0,0 +1,97 @@
+package profiling
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollect(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := NewServer("127.0.0.1:0", "secret")
+	ts := httptest.NewServer(s.server.Handler)
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	require.Nil(Collect(strings.TrimPrefix(ts.URL, "http://"), "secret", time.Second, &buf))
+
+	files := make(map[string][]byte)
+	gr, err := gzip.NewReader(&buf)
+	require.Nil(err)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(err)
+		files[hdr.Name], err = ioutil.ReadAll(tr)
+		require.Nil(err)
+	}
+	require.Equal(len(bundledProfiles), len(files))
+
+	// The named profiles are the gzipped pprof protobufs, not the HTML index
+	for _, name := range []string{"heap.pprof", "block.pprof", "mutex.pprof"} {
+		strs, err := parseProfileStrings(files[name])
+		require.Nil(err, name)
+		assert.NotEmpty(strs, name)
+	}
+	strs, err := parseProfileStrings(files["heap.pprof"])
+	require.Nil(err)
+	assert.Contains(strs, "inuse_space")
+	assert.Contains(string(files["goroutine.txt"]), "goroutine ")
+}
+
+// parseProfileStrings decodes the gzipped pprof profile, and returns its string table
+func parseProfileStrings(data []byte) ([]string, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ioutil.ReadAll(gr)
+	if err != nil {
+		return nil, err
+	}
+
+	const fieldStringTable = 6
+	var strs []string
+	for len(raw) > 0 {
+		key, n := proto.DecodeVarint(raw)
+		if n == 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		raw = raw[n:]
+		switch key & 7 {
+		case proto.WireVarint:
+			if _, n = proto.DecodeVarint(raw); n == 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			raw = raw[n:]
+		case proto.WireBytes:
+			size, n := proto.DecodeVarint(raw)
+			if n == 0 || size > uint64(len(raw)-n) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			if key>>3 == fieldStringTable {
+				strs = append(strs, string(raw[n:n+int(size)]))
+			}
+			raw = raw[n+int(size):]
+		default:
+			return nil, fmt.Errorf("unexpected wire type %v", key&7)
+		}
+	}
+	return strs, nil
+}
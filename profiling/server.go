@@ -0,0 +1,203 @@
+package profiling
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/util"
+)
+
+// PathPrefix is the admin namespace under which the profiling endpoints are served.
+const PathPrefix = "/admin/debug/"
+
+// TokenFileName is the name of the file under the config folder that stores the
+// auto-generated auth token.
+const TokenFileName = "prof_token"
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "profiling"})
+
+// Server serves the pprof and runtime/trace endpoints. All requests need to carry the
+// auth token as a bearer token in the Authorization header.
+type Server struct {
+	address   string
+	authToken string
+	server    *http.Server
+
+	wg *sync.WaitGroup
+}
+
+// NewServer creates a new instance of the profiling server.
+func NewServer(address string, authToken string) *Server {
+	s := &Server{
+		address:   address,
+		authToken: authToken,
+		wg:        &sync.WaitGroup{},
+	}
+	s.server = &http.Server{
+		Handler: s.authMiddleware(newHandler()),
+	}
+	logger = util.GetLoggerForModule("profiling")
+	return s
+}
+
+func newHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathPrefix+"pprof/", pprof.Index)
+	mux.HandleFunc(PathPrefix+"pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc(PathPrefix+"pprof/profile", pprof.Profile)
+	mux.HandleFunc(PathPrefix+"pprof/symbol", pprof.Symbol)
+	mux.HandleFunc(PathPrefix+"pprof/trace", pprof.Trace)
+	// pprof.Index only serves the named profiles under /debug/pprof/, so they are
+	// registered one by one under the admin namespace
+	for _, profile := range rpprof.Profiles() {
+		mux.Handle(PathPrefix+"pprof/"+profile.Name(), pprof.Handler(profile.Name()))
+	}
+	mux.HandleFunc(PathPrefix+"sampling", handleSampling)
+	return mux
+}
+
+// Start starts serving in the background.
+func (s *Server) Start(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	logger.WithFields(log.Fields{"address": s.address}).Info("Profiling server started")
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		logger.Info(s.server.Serve(l))
+	}()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		<-ctx.Done()
+		s.server.Shutdown(context.Background())
+	}()
+	return nil
+}
+
+// Wait blocks until the server stops.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Server) authMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.authToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// SamplingConfig contains the sampling rates of the block and mutex profiles.
+type SamplingConfig struct {
+	BlockProfileRate     int `json:"block_profile_rate"`
+	MutexProfileFraction int `json:"mutex_profile_fraction"`
+}
+
+var (
+	samplingMu     sync.Mutex
+	samplingConfig SamplingConfig
+)
+
+// SetSampling updates the sampling rates of the block and mutex profiles. A rate of
+// zero disables the corresponding profile.
+func SetSampling(config SamplingConfig) {
+	samplingMu.Lock()
+	defer samplingMu.Unlock()
+
+	runtime.SetBlockProfileRate(config.BlockProfileRate)
+	runtime.SetMutexProfileFraction(config.MutexProfileFraction)
+	samplingConfig = config
+}
+
+// GetSampling returns the current sampling rates.
+func GetSampling() SamplingConfig {
+	samplingMu.Lock()
+	defer samplingMu.Unlock()
+
+	return samplingConfig
+}
+
+// handleSampling returns the sampling rates on GET, and updates them with the
+// block_profile_rate and mutex_profile_fraction query parameters on POST.
+func handleSampling(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		config := GetSampling()
+		if v := r.URL.Query().Get("block_profile_rate"); v != "" {
+			rate, err := strconv.Atoi(v)
+			if err != nil || rate < 0 {
+				http.Error(w, "Invalid block_profile_rate", http.StatusBadRequest)
+				return
+			}
+			config.BlockProfileRate = rate
+		}
+		if v := r.URL.Query().Get("mutex_profile_fraction"); v != "" {
+			fraction, err := strconv.Atoi(v)
+			if err != nil || fraction < 0 {
+				http.Error(w, "Invalid mutex_profile_fraction", http.StatusBadRequest)
+				return
+			}
+			config.MutexProfileFraction = fraction
+		}
+		SetSampling(config)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GetSampling())
+}
+
+// LoadOrCreateAuthToken returns the configured auth token. If none is configured, it
+// reads the token from tokenFilePath, generating and saving a new one if the file does
+// not exist yet.
+func LoadOrCreateAuthToken(tokenFilePath string) (string, error) {
+	if token := viper.GetString(common.CfgProfAuthToken); token != "" {
+		return token, nil
+	}
+
+	raw, err := ioutil.ReadFile(tokenFilePath)
+	if err == nil {
+		return strings.TrimSpace(string(raw)), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	if err := common.WriteFileAtomic(tokenFilePath, []byte(token), 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
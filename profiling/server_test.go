@@ -0,0 +1,60 @@
+package profiling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer("127.0.0.1:0", "secret")
+
+	req := httptest.NewRequest(http.MethodGet, PathPrefix+"sampling", nil)
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, PathPrefix+"sampling", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, PathPrefix+"sampling", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(http.StatusOK, rec.Code)
+
+	// An empty token never grants access.
+	s = NewServer("127.0.0.1:0", "")
+	req = httptest.NewRequest(http.MethodGet, PathPrefix+"sampling", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(http.StatusUnauthorized, rec.Code)
+}
+
+func TestSampling(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer("127.0.0.1:0", "secret")
+	req := httptest.NewRequest(http.MethodPost, PathPrefix+"sampling?block_profile_rate=5&mutex_profile_fraction=3", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(SamplingConfig{BlockProfileRate: 5, MutexProfileFraction: 3}, GetSampling())
+
+	req = httptest.NewRequest(http.MethodPost, PathPrefix+"sampling?block_profile_rate=-1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+
+	SetSampling(SamplingConfig{})
+}
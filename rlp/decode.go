@@ -41,6 +41,8 @@ var (
 	ErrElemTooLarge     = errors.New("rlp: element is larger than containing list")
 	ErrValueTooLarge    = errors.New("rlp: value size exceeds available input length")
 	ErrMoreThanOneValue = errors.New("rlp: input contains more than one value")
+	ErrInputTooLarge    = errors.New("rlp: input size exceeds the limit")
+	ErrStringTooLarge   = errors.New("rlp: string size exceeds the limit")
 
 	// internal errors
 	errNotInList     = errors.New("rlp: call of ListEnd outside of any list")
@@ -145,6 +147,33 @@ func DecodeBytes(b []byte, val interface{}) error {
 	return nil
 }
 
+// Limits caps the sizes accepted when decoding untrusted input. The sizes
+// are checked against the size information of the values, so that crafted
+// input is rejected before any memory is allocated for it. Zero means no
+// limit.
+type Limits struct {
+	MaxInputSize  uint64 // size of the whole input
+	MaxStringSize uint64 // size of any single string (byte array) in the input
+}
+
+// DecodeBytesWithLimits is like DecodeBytes, but rejects input exceeding the
+// given limits.
+func DecodeBytesWithLimits(b []byte, val interface{}, limits Limits) error {
+	if limits.MaxInputSize > 0 && uint64(len(b)) > limits.MaxInputSize {
+		return ErrInputTooLarge
+	}
+	r := bytes.NewReader(b)
+	s := NewStream(r, uint64(len(b)))
+	s.SetStringLimit(limits.MaxStringSize)
+	if err := s.Decode(val); err != nil {
+		return err
+	}
+	if r.Len() > 0 {
+		return ErrMoreThanOneValue
+	}
+	return nil
+}
+
 type decodeError struct {
 	msg string
 	typ reflect.Type
@@ -438,19 +467,50 @@ func makeStructDecoder(typ reflect.Type) (decoder, error) {
 		if _, err := s.List(); err != nil {
 			return wrapStreamError(err, typ)
 		}
-		for _, f := range fields {
+		// The writer omits the trailing optional fields which are empty, so an
+		// input ending with an empty optional field is not canonical.
+		decoded, lastEmpty := 0, false
+		for i, f := range fields {
+			empty := false
+			if f.optional {
+				kind, size, _ := s.Kind()
+				empty = kind != Byte && size == 0
+			}
 			err := f.info.decoder(s, val.Field(f.index))
 			if err == EOL {
+				if f.optional {
+					// The field is optional, so reaching the end of the list before
+					// reaching the last field is acceptable. All remaining undecoded
+					// fields are zeroed.
+					zeroFields(val, fields[i:])
+					break
+				}
 				return &decodeError{msg: "too few elements", typ: typ}
 			} else if err != nil {
 				return addErrorContext(err, "."+typ.Field(f.index).Name)
 			}
+			decoded, lastEmpty = decoded+1, empty
+		}
+		if err := s.ListEnd(); err != nil {
+			return wrapStreamError(err, typ)
+		}
+		if decoded > 0 {
+			if last := fields[decoded-1]; last.optional && (lastEmpty || val.Field(last.index).IsZero()) {
+				return &decodeError{msg: "non-canonical empty trailing optional field", typ: typ}
+			}
 		}
-		return wrapStreamError(s.ListEnd(), typ)
+		return nil
 	}
 	return dec, nil
 }
 
+func zeroFields(structval reflect.Value, fields []field) {
+	for _, f := range fields {
+		fv := structval.Field(f.index)
+		fv.Set(reflect.Zero(fv.Type()))
+	}
+}
+
 // makePtrDecoder creates a decoder that decodes into
 // the pointer's element type.
 func makePtrDecoder(typ reflect.Type) (decoder, error) {
@@ -597,6 +657,9 @@ type Stream struct {
 	remaining uint64
 	limited   bool
 
+	// maximum size of a string, 0 if not limited
+	maxStringSize uint64
+
 	// auxiliary buffer for integer decoding
 	uintbuf []byte
 
@@ -632,6 +695,14 @@ func NewStream(r io.Reader, inputLimit uint64) *Stream {
 	return s
 }
 
+// SetStringLimit sets the maximum size of the strings read from the
+// stream. Operations that encounter a larger string return
+// ErrStringTooLarge. A limit of 0 disables the check. The limit is kept
+// across Reset.
+func (s *Stream) SetStringLimit(maxStringSize uint64) {
+	s.maxStringSize = maxStringSize
+}
+
 // NewListStream creates a new stream that pretends to be positioned
 // at an encoded list of the given length.
 func NewListStream(r io.Reader, len uint64) *Stream {
@@ -892,6 +963,9 @@ func (s *Stream) Kind() (kind Kind, size uint64, err error) {
 					s.kinderr = ErrElemTooLarge
 				}
 			}
+			if s.kinderr == nil && s.kind == String && s.maxStringSize > 0 && s.size > s.maxStringSize {
+				s.kinderr = ErrStringTooLarge
+			}
 		}
 	}
 	// Note: this might return a sticky error generated
@@ -347,6 +347,28 @@ type tailUint struct {
 	Tail []uint `rlp:"tail"`
 }
 
+type optionalFields struct {
+	A uint
+	B uint `rlp:"optional"`
+	C uint `rlp:"optional"`
+}
+
+type optionalPtrField struct {
+	A uint
+	B *[3]byte `rlp:"optional"`
+}
+
+type optionalEmptyFields struct {
+	A uint
+	B []byte `rlp:"optional"`
+	C []uint `rlp:"optional"`
+}
+
+type invalidOptional struct {
+	A uint `rlp:"optional"`
+	B uint
+}
+
 var (
 	veryBigInt = big.NewInt(0).Add(
 		big.NewInt(0).Lsh(big.NewInt(0xFFFFFFFFFFFFFF), 16),
@@ -511,6 +533,73 @@ var decodeTests = []decodeTest{
 		value: tailRaw{A: 1, Tail: []RawValue{}},
 	},
 
+	// struct tag "optional"
+	{
+		input: "C101",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 0, 0},
+	},
+	{
+		input: "C20102",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 2, 0},
+	},
+	{
+		input: "C3010203",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 2, 3},
+	},
+	{
+		input: "C401020304",
+		ptr:   new(optionalFields),
+		error: "rlp: input list has too many elements for rlp.optionalFields",
+	},
+	{
+		input: "C101",
+		ptr:   new(optionalPtrField),
+		value: optionalPtrField{A: 1},
+	},
+	{
+		input: "C50183010203",
+		ptr:   new(optionalPtrField),
+		value: optionalPtrField{A: 1, B: &[3]byte{1, 2, 3}},
+	},
+	{
+		input: "C3018003",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 0, 3},
+	},
+	{
+		input: "C20180",
+		ptr:   new(optionalFields),
+		error: "rlp: non-canonical empty trailing optional field for rlp.optionalFields",
+	},
+	{
+		input: "C3010280",
+		ptr:   new(optionalFields),
+		error: "rlp: non-canonical empty trailing optional field for rlp.optionalFields",
+	},
+	{
+		input: "C40180C101",
+		ptr:   new(optionalEmptyFields),
+		value: optionalEmptyFields{A: 1, B: []byte{}, C: []uint{1}},
+	},
+	{
+		input: "C20180",
+		ptr:   new(optionalEmptyFields),
+		error: "rlp: non-canonical empty trailing optional field for rlp.optionalEmptyFields",
+	},
+	{
+		input: "C50182AABBC0",
+		ptr:   new(optionalEmptyFields),
+		error: "rlp: non-canonical empty trailing optional field for rlp.optionalEmptyFields",
+	},
+	{
+		input: "C0",
+		ptr:   new(invalidOptional),
+		error: "rlp: struct field rlp.invalidOptional.B needs \"optional\" tag",
+	},
+
 	// struct tag "-"
 	{
 		input: "C20102",
@@ -624,6 +713,40 @@ func TestDecodeStreamReset(t *testing.T) {
 	})
 }
 
+func TestDecodeBytesWithLimits(t *testing.T) {
+	runTests(t, func(input []byte, into interface{}) error {
+		return DecodeBytesWithLimits(input, into, Limits{MaxInputSize: 1 << 20, MaxStringSize: 1 << 20})
+	})
+
+	type msg struct {
+		ID      uint
+		Payload []byte
+	}
+	input, _ := EncodeToBytes(msg{ID: 1, Payload: make([]byte, 100)})
+	tests := []struct {
+		limits Limits
+		err    error
+	}{
+		{Limits{}, nil},
+		{Limits{MaxInputSize: uint64(len(input)), MaxStringSize: 100}, nil},
+		{Limits{MaxInputSize: uint64(len(input)) - 1}, ErrInputTooLarge},
+		{Limits{MaxStringSize: 99}, ErrStringTooLarge},
+	}
+	for i, test := range tests {
+		if err := DecodeBytesWithLimits(input, new(msg), test.limits); err != test.err {
+			t.Errorf("test %d: error mismatch: got %v, want %v", i, err, test.err)
+		}
+	}
+
+	// The declared size is rejected before the string is read
+	huge := unhex("B9FFFF")
+	s := NewStream(newPlainReader(huge), 0)
+	s.SetStringLimit(1024)
+	if _, err := s.Bytes(); err != ErrStringTooLarge {
+		t.Errorf("error mismatch: got %v, want %v", err, ErrStringTooLarge)
+	}
+}
+
 type testDecoder struct{ called bool }
 
 func (t *testDecoder) DecodeRLP(s *Stream) error {
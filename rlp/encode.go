@@ -529,10 +529,33 @@ func makeStructWriter(typ reflect.Type) (writer, error) {
 	if err != nil {
 		return nil, err
 	}
+	firstOptional := firstOptionalField(fields)
+	if firstOptional == len(fields) {
+		writer := func(val reflect.Value, w *encbuf) error {
+			lh := w.list()
+			for _, f := range fields {
+				if err := f.info.writer(val.Field(f.index), w); err != nil {
+					return err
+				}
+			}
+			w.listEnd(lh)
+			return nil
+		}
+		return writer, nil
+	}
+
+	// If there are any "optional" fields, the writer needs to perform additional
+	// checks to determine the output list length.
 	writer := func(val reflect.Value, w *encbuf) error {
+		lastField := len(fields) - 1
+		for ; lastField >= firstOptional; lastField-- {
+			if !isEmptyField(fields[lastField], val.Field(fields[lastField].index)) {
+				break
+			}
+		}
 		lh := w.list()
-		for _, f := range fields {
-			if err := f.info.writer(val.Field(f.index), w); err != nil {
+		for i := 0; i <= lastField; i++ {
+			if err := fields[i].info.writer(val.Field(fields[i].index), w); err != nil {
 				return err
 			}
 		}
@@ -542,6 +565,23 @@ func makeStructWriter(typ reflect.Type) (writer, error) {
 	return writer, nil
 }
 
+// isEmptyField reports whether the field value is zero, or encodes to an empty
+// string or list. Such trailing optional fields are omitted, so that each value
+// has a single encoding.
+func isEmptyField(f field, val reflect.Value) bool {
+	if val.IsZero() {
+		return true
+	}
+	eb := encbufPool.Get().(*encbuf)
+	defer encbufPool.Put(eb)
+	eb.reset()
+	if err := f.info.writer(val, eb); err != nil {
+		return false
+	}
+	enc := eb.toBytes()
+	return len(enc) == 1 && (enc[0] == 0x80 || enc[0] == 0xC0)
+}
+
 func makePtrWriter(typ reflect.Type) (writer, error) {
 	etypeinfo, err := cachedTypeInfo1(typ.Elem(), tags{})
 	if err != nil {
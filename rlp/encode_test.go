@@ -219,6 +219,15 @@ var encTests = []encTest{
 	{val: &tailRaw{A: 1, Tail: []RawValue{}}, output: "C101"},
 	{val: &tailRaw{A: 1, Tail: nil}, output: "C101"},
 	{val: &hasIgnoredField{A: 1, B: 2, C: 3}, output: "C20103"},
+	{val: &optionalFields{A: 1}, output: "C101"},
+	{val: &optionalFields{A: 1, B: 2}, output: "C20102"},
+	{val: &optionalFields{A: 1, C: 3}, output: "C3018003"},
+	{val: &optionalPtrField{A: 1}, output: "C101"},
+	{val: &optionalPtrField{A: 1, B: &[3]byte{1, 2, 3}}, output: "C50183010203"},
+	{val: &optionalEmptyFields{A: 1, B: []byte{}}, output: "C101"},
+	{val: &optionalEmptyFields{A: 1, B: []byte{0xAA}, C: []uint{}}, output: "C30181AA"},
+	{val: &optionalEmptyFields{A: 1, B: []byte{}, C: []uint{1}}, output: "C40180C101"},
+	{val: &invalidOptional{}, error: "rlp: struct field rlp.invalidOptional.B needs \"optional\" tag"},
 
 	// nil
 	{val: (*uint)(nil), output: "80"},
@@ -0,0 +1,189 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/spf13/viper"
+)
+
+// ------------------------------- BlockSpecifier -----------------------------------
+
+// BlockSpecifier identifies a block in query RPCs. It can be a block height
+// (decimal or 0x-prefixed hex), a 32-byte block hash, or one of the tags
+// "latest", "finalized" and "pending". An empty specifier lets the RPC fall
+// back to its own default, which is "finalized" for most endpoints.
+//
+// The query RPCs reading the ledger state accept a specifier. The others do not:
+//   - GetBlockByHeight, GetBlocksByRange, GetLogs, GetHeaderProof and GetBridgeMessageProof
+//     address the blocks by height already, and GetBlock accepts a specifier.
+//   - GetTransaction and GetTransactionProof look the transaction up by hash, and report
+//     the block which includes it.
+//   - GetTransactionHistory, GetAccountActivity, GetTokenBalances, GetTokenTransfers,
+//     GetNFTsByOwner, GetNFTHistory, GetFeeStats and GetValidatorSetProofs read indexes which
+//     are accumulated over the finalized blocks, and not kept per block.
+//   - The mempool, network and node RPCs do not depend on a block.
+type BlockSpecifier string
+
+const (
+	BlockSpecifierLatest    BlockSpecifier = "latest"
+	BlockSpecifierFinalized BlockSpecifier = "finalized"
+	BlockSpecifierPending   BlockSpecifier = "pending"
+)
+
+// UnmarshalJSON implements json.Unmarshaler. Both JSON strings and JSON numbers are accepted.
+func (bs *BlockSpecifier) UnmarshalJSON(input []byte) error {
+	if hexutil.IsString(input) {
+		var s string
+		if err := json.Unmarshal(input, &s); err != nil {
+			return err
+		}
+		*bs = BlockSpecifier(strings.TrimSpace(s))
+	} else if string(input) == "null" {
+		*bs = ""
+	} else {
+		height, err := strconv.ParseUint(string(input), 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid block specifier: %s", string(input))
+		}
+		*bs = BlockSpecifier(strconv.FormatUint(height, 10))
+	}
+	_, _, err := bs.parse()
+	return err
+}
+
+// IsEmpty returns true if no block is specified.
+func (bs BlockSpecifier) IsEmpty() bool {
+	return bs == ""
+}
+
+// IsTag returns true if the specifier is one of latest, finalized and pending.
+func (bs BlockSpecifier) IsTag() bool {
+	switch BlockSpecifier(strings.ToLower(string(bs))) {
+	case BlockSpecifierLatest, BlockSpecifierFinalized, BlockSpecifierPending:
+		return true
+	}
+	return false
+}
+
+// Hash returns the block hash if the specifier is a block hash.
+func (bs BlockSpecifier) Hash() (common.Hash, bool) {
+	hash, isHash, err := bs.parse()
+	if err != nil || !isHash {
+		return common.Hash{}, false
+	}
+	return hash, true
+}
+
+// Height returns the block height if the specifier is a block height.
+func (bs BlockSpecifier) Height() (uint64, bool) {
+	if bs.IsEmpty() || bs.IsTag() {
+		return 0, false
+	}
+	_, isHash, err := bs.parse()
+	if err != nil || isHash {
+		return 0, false
+	}
+	height, err := parseBlockHeight(string(bs))
+	if err != nil {
+		return 0, false
+	}
+	return height, true
+}
+
+func (bs BlockSpecifier) parse() (hash common.Hash, isHash bool, err error) {
+	if bs.IsEmpty() || bs.IsTag() {
+		return
+	}
+	s := string(bs)
+	if hexutil.Has0xPrefix(s) && len(s) == 2+2*common.HashLength {
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return hash, false, fmt.Errorf("Invalid block hash: %v", s)
+		}
+		return common.BytesToHash(b), true, nil
+	}
+	if _, err = parseBlockHeight(s); err != nil {
+		return hash, false, fmt.Errorf("Invalid block specifier: %v", s)
+	}
+	return
+}
+
+func parseBlockHeight(s string) (uint64, error) {
+	if hexutil.Has0xPrefix(s) {
+		return strconv.ParseUint(s[2:], 16, 64)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// resolveBlock returns the block identified by the specifier. A height resolves to
+// the finalized block at that height, "latest" and "pending" resolve to the tip of the
+// chain, and "finalized" resolves to the last finalized block. defaultSpec is used
+// when bs is empty.
+func (t *PandoRPCService) resolveBlock(bs BlockSpecifier, defaultSpec BlockSpecifier) (*core.ExtendedBlock, error) {
+	if bs.IsEmpty() {
+		bs = defaultSpec
+	}
+
+	switch BlockSpecifier(strings.ToLower(string(bs))) {
+	case BlockSpecifierFinalized:
+		return t.consensus.GetLastFinalizedBlock(), nil
+	case BlockSpecifierLatest, BlockSpecifierPending:
+		return t.consensus.GetTipToVote(), nil
+	}
+
+	hash, isHash, err := bs.parse()
+	if err != nil {
+		return nil, err
+	}
+	if isHash {
+		return t.chain.FindBlock(hash)
+	}
+
+	height, _ := bs.Height()
+	for _, b := range t.chain.FindBlocksByHeight(height) {
+		if b.Status.IsFinalized() {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("Finalized block at height %v is not found", height)
+}
+
+// resolveStoreView returns a snapshot of the ledger state after the block identified
+// by the specifier. "pending" resolves to the screened view, which also includes the
+// effect of the transactions in the mempool. The state of a past block is opened
+// read-only, so that executing calls against it never alters the stored tries.
+func (t *PandoRPCService) resolveStoreView(bs BlockSpecifier, defaultSpec BlockSpecifier) (*state.StoreView, error) {
+	if bs.IsEmpty() {
+		bs = defaultSpec
+	}
+
+	switch BlockSpecifier(strings.ToLower(string(bs))) {
+	case BlockSpecifierFinalized:
+		return t.ledger.GetFinalizedSnapshot()
+	case BlockSpecifierPending:
+		return t.ledger.GetScreenedSnapshot()
+	}
+
+	block, err := t.resolveBlock(bs, defaultSpec)
+	if err != nil {
+		return nil, err
+	}
+	db := t.ledger.State().DB()
+	view := state.NewReadOnlyStoreView(block.Height, block.StateHash, db)
+	if view == nil { // might have been pruned
+		if viper.GetBool(common.CfgStorageArchive) {
+			return nil, fmt.Errorf("the state for block %v does not exists, the archive node retains the states since height %v",
+				block.Hash().Hex(), t.ledger.EarliestStateHeight())
+		}
+		return nil, fmt.Errorf("the state for block %v does not exists, it might have been pruned, the historical states "+
+			"are retained by the archive nodes, see --archive", block.Hash().Hex())
+	}
+	return view, nil
+}
@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/pandotoken/pando/common"
+)
+
+func TestBlockSpecifierUnmarshal(t *testing.T) {
+	assert := assert.New(t)
+
+	var args GetBlockArgs
+	err := json.Unmarshal([]byte(`{"block": 123}`), &args)
+	assert.Nil(err)
+	height, ok := args.Block.Height()
+	assert.True(ok)
+	assert.Equal(uint64(123), height)
+
+	err = json.Unmarshal([]byte(`{"block": "0x10"}`), &args)
+	assert.Nil(err)
+	height, ok = args.Block.Height()
+	assert.True(ok)
+	assert.Equal(uint64(16), height)
+
+	err = json.Unmarshal([]byte(`{"block": "finalized"}`), &args)
+	assert.Nil(err)
+	assert.True(args.Block.IsTag())
+	_, ok = args.Block.Height()
+	assert.False(ok)
+
+	hashStr := "0xc88485a473527c55c5ddb067b018324b7e390b188e76702bc1db74dfc2dc6d13"
+	err = json.Unmarshal([]byte(`{"block": "`+hashStr+`"}`), &args)
+	assert.Nil(err)
+	hash, ok := args.Block.Hash()
+	assert.True(ok)
+	assert.Equal(common.HexToHash(hashStr), hash)
+	_, ok = args.Block.Height()
+	assert.False(ok)
+
+	err = json.Unmarshal([]byte(`{"block": "earliest"}`), &args)
+	assert.NotNil(err)
+
+	err = json.Unmarshal([]byte(`{"block": -1}`), &args)
+	assert.NotNil(err)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/features"
 	"github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 	"github.com/pandotoken/pando/ledger/vm"
@@ -13,7 +15,8 @@ import (
 // ------------------------------- CallSmartContract -----------------------------------
 
 type CallSmartContractArgs struct {
-	SctxBytes string `json:"sctx_bytes"`
+	SctxBytes string         `json:"sctx_bytes"`
+	Block     BlockSpecifier `json:"block"` // the block on top of which the call is executed, defaults to the delivered state
 }
 
 type CallSmartContractResult struct {
@@ -21,21 +24,36 @@ type CallSmartContractResult struct {
 	ContractAddress common.Address    `json:"contract_address"`
 	GasUsed         common.JSONUint64 `json:"gas_used"`
 	VmError         string            `json:"vm_error"`
+	BlockHeight     common.JSONUint64 `json:"block_height"` // the height of the block the call was executed on top of
 }
 
 // CallSmartContract calls the smart contract. However, calling a smart contract does NOT modify
 // the globally consensus state. It can be used for dry run, or for retrieving info from smart contracts
-// without actually spending gas.
+// without actually spending gas. With args.Block, the call is executed against the state of a past
+// block, e.g. to chart the token balances over time, as long as the state has not been pruned.
+// Without args.Block, the call is executed against the delivered state, as it always has been. The
+// delivered state can trail "latest" while a validated block is not yet applied by the ledger.
 func (t *PandoRPCService) CallSmartContract(args *CallSmartContractArgs, result *CallSmartContractResult) (err error) {
 	var ledgerState *state.StoreView
-	ledgerState, err = t.ledger.GetDeliveredSnapshot()
+	var parentBlock *core.Block
+	if args.Block.IsEmpty() {
+		ledgerState, err = t.ledger.GetDeliveredSnapshot()
+		parentBlock = t.ledger.State().ParentBlock()
+	} else {
+		var block *core.ExtendedBlock
+		block, err = t.resolveBlock(args.Block, BlockSpecifierLatest)
+		if err == nil {
+			parentBlock = block.Block
+			ledgerState, err = t.resolveStoreView(args.Block, BlockSpecifierLatest)
+		}
+	}
 	if err != nil {
 		return err
 	}
 
 	blockHeight := ledgerState.Height() + 1 // the view points to the parent of the current block
-	if blockHeight < common.HeightEnableSmartContract {
-		return fmt.Errorf("Smart contract feature not enabled until block height %v.", common.HeightEnableSmartContract)
+	if !features.IsEnabled(features.SmartContract, blockHeight) {
+		return fmt.Errorf("Smart contract feature not enabled until block height %v.", features.Height(features.SmartContract))
 	}
 
 	sctxBytes, err := hex.DecodeString(args.SctxBytes)
@@ -52,16 +70,117 @@ func (t *PandoRPCService) CallSmartContract(args *CallSmartContractArgs, result
 		return fmt.Errorf("Failed to parse SmartContractTx: %v", args.SctxBytes)
 	}
 
-	parentBlock := t.ledger.State().ParentBlock()
-	vmRet, contractAddr, gasUsed, vmErr := vm.Execute(parentBlock, sctx, ledgerState)
+	vmRet, contractAddr, gasUsed, vmErr := t.callCache.execute(parentBlock, sctx, ledgerState)
 	ledgerState.Save()
 
 	result.VmReturn = hex.EncodeToString(vmRet)
 	result.ContractAddress = contractAddr
 	result.GasUsed = common.JSONUint64(gasUsed)
+	result.BlockHeight = common.JSONUint64(ledgerState.Height())
 	if vmErr != nil {
 		result.VmError = vmErr.Error()
 	}
 
 	return nil
 }
+
+// ------------------------------- EstimateGas -----------------------------------
+
+type EstimateGasArgs struct {
+	SctxBytes string         `json:"sctx_bytes"` // the gas limit of the transaction caps the estimate, if set
+	Block     BlockSpecifier `json:"block"`      // the block on top of which the transaction is executed, defaults to pending
+}
+
+type EstimateGasResult struct {
+	GasLimit     common.JSONUint64 `json:"gas_limit"`
+	VmReturn     string            `json:"vm_return"`
+	VmError      string            `json:"vm_error"`
+	RevertReason string            `json:"revert_reason,omitempty"` // the Error(string) reason of a reverted transaction
+}
+
+// EstimateGas searches for the lowest gas limit the smart contract transaction succeeds with. If the
+// transaction fails even with the highest gas limit, the VM error, return data and revert reason are
+// set instead.
+func (t *PandoRPCService) EstimateGas(args *EstimateGasArgs, result *EstimateGasResult) (err error) {
+	bs := args.Block
+	if bs.IsEmpty() {
+		bs = BlockSpecifierPending
+	}
+	parentBlock, view, err := t.ethCallContext(bs)
+	if err != nil {
+		return err
+	}
+
+	sctxBytes, err := hex.DecodeString(args.SctxBytes)
+	if err != nil {
+		return err
+	}
+	tx, err := types.TxFromBytes(sctxBytes)
+	if err != nil {
+		return fmt.Errorf("Failed to parse SmartContractTx, error: %v", err)
+	}
+	sctx, ok := tx.(*types.SmartContractTx)
+	if !ok {
+		return fmt.Errorf("Failed to parse SmartContractTx: %v", args.SctxBytes)
+	}
+
+	hi := types.MaximumTxGasLimit
+	if sctx.GasLimit != 0 && sctx.GasLimit < hi {
+		hi = sctx.GasLimit
+	}
+	build := func(gasLimit uint64) *types.SmartContractTx {
+		estimated := *sctx
+		estimated.GasLimit = gasLimit
+		return &estimated
+	}
+	gasLimit, vmRet, vmErr, err := estimateGas(parentBlock, view, build, hi)
+	if err != nil {
+		return err
+	}
+	if vmErr != nil {
+		result.VmReturn = hex.EncodeToString(vmRet)
+		result.VmError = vmErr.Error()
+		result.RevertReason = types.UnpackRevertReason(vmRet)
+		return nil
+	}
+	result.GasLimit = common.JSONUint64(gasLimit)
+	return nil
+}
+
+// estimateGas searches for the lowest gas limit up to hi the transaction built by build succeeds with.
+// The VM error and return data are returned if the transaction fails with the gas limit hi.
+func estimateGas(parentBlock *core.Block, view *state.StoreView, build func(gasLimit uint64) *types.SmartContractTx,
+	hi uint64) (gasLimit uint64, vmRet common.Bytes, vmErr error, err error) {
+	execute := func(gasLimit uint64) (uint64, common.Bytes, error, error) {
+		snapshot, err := view.Copy()
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		ret, _, gasUsed, vmErr := vm.Execute(parentBlock, build(gasLimit), snapshot)
+		return gasUsed, ret, vmErr, nil
+	}
+
+	gasUsed, vmRet, vmErr, err := execute(hi)
+	if err != nil || vmErr != nil {
+		return 0, vmRet, vmErr, err
+	}
+
+	// The gas used can be lower than the gas limit required, e.g. due to refunds
+	lo := uint64(0)
+	if gasUsed > 0 {
+		lo = gasUsed - 1
+	}
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		_, _, vmErr, err := execute(mid)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if vmErr != nil {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil, nil, nil
+}
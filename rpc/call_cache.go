@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/metrics"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/ledger/vm"
+)
+
+var (
+	callCacheHitCounter  = metrics.NewRegisteredCounter("rpc/call_cache/hit", nil)
+	callCacheMissCounter = metrics.NewRegisteredCounter("rpc/call_cache/miss", nil)
+)
+
+// callResult is the outcome of a read-only contract call
+type callResult struct {
+	vmRet     common.Bytes
+	gasUsed   uint64
+	vmErr     error
+	expiresAt time.Time
+}
+
+// callCache memoizes the results of the read-only contract calls, since the dApp frontends repeat
+// the same view calls. A result is keyed by the block the call executes on top of, the state root,
+// the code hash of the contract and the call parameters, so it never outlives the state it was
+// computed from. The TTL bounds how long the results of the stale states are kept.
+type callCache struct {
+	mutex *sync.Mutex
+	cache *lru.Cache // map: call key |-> *callResult
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// newCallCache creates a call cache holding up to size results, nil if size is 0
+func newCallCache(size int, ttl time.Duration) *callCache {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil
+	}
+	return &callCache{
+		mutex: &sync.Mutex{},
+		cache: cache,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// callCacheKey returns the key of the call, the zero hash if the call cannot be cached, i.e. it
+// deploys a contract
+func callCacheKey(parentBlock *core.Block, view *state.StoreView, tx *types.SmartContractTx) common.Hash {
+	to := tx.To.Address
+	if to == (common.Address{}) {
+		return common.Hash{}
+	}
+	codeHash := view.GetCodeHash(to)
+	value := tx.From.Coins.PTXWei
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	gasPrice := tx.GasPrice
+	if gasPrice == nil {
+		gasPrice = big.NewInt(0)
+	}
+	var gasLimit [8]byte
+	binary.BigEndian.PutUint64(gasLimit[:], tx.GasLimit)
+	parentHash := parentBlock.Hash()
+	stateRoot := view.Hash()
+	return crypto.Keccak256Hash(parentHash[:], stateRoot[:], to[:], codeHash[:], tx.From.Address[:],
+		common.BigToHash(value).Bytes(), common.BigToHash(gasPrice).Bytes(), gasLimit[:], tx.Data)
+}
+
+// execute returns the cached result of the call if any, or executes the call on the view and
+// caches its result. The view is modified by the execution, as with vm.Execute.
+func (cc *callCache) execute(parentBlock *core.Block, tx *types.SmartContractTx, view *state.StoreView) (
+	vmRet common.Bytes, contractAddr common.Address, gasUsed uint64, vmErr error) {
+	if cc == nil {
+		return vm.Execute(parentBlock, tx, view)
+	}
+	key := callCacheKey(parentBlock, view, tx)
+	if key == (common.Hash{}) {
+		return vm.Execute(parentBlock, tx, view)
+	}
+
+	if cached, ok := cc.get(key); ok {
+		callCacheHitCounter.Inc(1)
+		return common.CopyBytes(cached.vmRet), tx.To.Address, cached.gasUsed, cached.vmErr
+	}
+	callCacheMissCounter.Inc(1)
+
+	vmRet, contractAddr, gasUsed, vmErr = vm.Execute(parentBlock, tx, view)
+	cc.cache.Add(key, &callResult{
+		vmRet:     common.CopyBytes(vmRet),
+		gasUsed:   gasUsed,
+		vmErr:     vmErr,
+		expiresAt: cc.now().Add(cc.ttl),
+	})
+	return vmRet, contractAddr, gasUsed, vmErr
+}
+
+func (cc *callCache) get(key common.Hash) (*callResult, bool) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	value, ok := cc.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	result := value.(*callResult)
+	if cc.now().After(result.expiresAt) {
+		cc.cache.Remove(key)
+		return nil, false
+	}
+	return result, true
+}
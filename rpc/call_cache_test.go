@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallCache(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	view := state.NewStoreView(0, common.Hash{}, backend.NewMemDatabase())
+	caller := common.HexToAddress("0x100")
+	contract := common.HexToAddress("0x200")
+	view.CreateAccount(caller)
+	// ASM: push 0x0, sload, push 0x0, mstore, push 0x20, push 0x0, return
+	code, _ := hex.DecodeString("60005460005260206000f3")
+	view.CreateAccount(contract)
+	view.SetCode(contract, code)
+	view.SetState(contract, common.Hash{}, common.BigToHash(big.NewInt(7)))
+
+	parentBlock := &core.Block{BlockHeader: &core.BlockHeader{ChainID: "privatenet", Height: 1, Timestamp: big.NewInt(0)}}
+	tx := &types.SmartContractTx{
+		From:     types.TxInput{Address: caller, Coins: types.NewCoins(0, 0)},
+		To:       types.TxOutput{Address: contract},
+		GasLimit: 100000,
+		GasPrice: big.NewInt(1),
+	}
+
+	now := time.Now()
+	cc := newCallCache(16, time.Minute)
+	require.NotNil(cc)
+	cc.now = func() time.Time { return now }
+
+	execute := func(view *state.StoreView, tx *types.SmartContractTx) common.Bytes {
+		snapshot, err := view.Copy()
+		require.Nil(err)
+		vmRet, _, _, vmErr := cc.execute(parentBlock, tx, snapshot)
+		require.Nil(vmErr)
+		return vmRet
+	}
+
+	assert.Equal(common.BigToHash(big.NewInt(7)).Bytes(), []byte(execute(view, tx)))
+	assert.Equal(1, cc.cache.Len())
+	assert.Equal(common.BigToHash(big.NewInt(7)).Bytes(), []byte(execute(view, tx)))
+	assert.Equal(1, cc.cache.Len(), "the result is cached")
+
+	// The result is not reused once the state changes
+	view.SetState(contract, common.Hash{}, common.BigToHash(big.NewInt(8)))
+	assert.Equal(common.BigToHash(big.NewInt(8)).Bytes(), []byte(execute(view, tx)))
+	assert.Equal(2, cc.cache.Len())
+
+	// The call parameters are part of the key
+	other := *tx
+	other.Data = common.Bytes{0x1}
+	execute(view, &other)
+	assert.Equal(3, cc.cache.Len())
+
+	// The results expire
+	key := callCacheKey(parentBlock, view, tx)
+	_, ok := cc.get(key)
+	assert.True(ok)
+	now = now.Add(2 * time.Minute)
+	_, ok = cc.get(key)
+	assert.False(ok)
+	assert.Equal(2, cc.cache.Len())
+
+	// Deploying a contract is not cached
+	deploy := *tx
+	deploy.To = types.TxOutput{}
+	assert.Equal(common.Hash{}, callCacheKey(parentBlock, view, &deploy))
+
+	assert.Nil(newCallCache(0, time.Minute))
+}
@@ -0,0 +1,141 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/consensus"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/ledger"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/ledger/vm"
+	"github.com/pandotoken/pando/store/database/backend"
+	"github.com/pandotoken/pando/store/kvstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateGas(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	view := state.NewStoreView(0, common.Hash{}, backend.NewMemDatabase())
+	caller := common.HexToAddress("0x100")
+	storer := common.HexToAddress("0x200")
+	reverter := common.HexToAddress("0x300")
+	view.CreateAccount(caller)
+
+	// ASM: push 0x1, push 0x0, sstore, stop
+	code, _ := hex.DecodeString("600160005500")
+	view.CreateAccount(storer)
+	view.SetCode(storer, code)
+	// ASM: push 0x0, push 0x0, revert
+	code, _ = hex.DecodeString("60006000fd")
+	view.CreateAccount(reverter)
+	view.SetCode(reverter, code)
+
+	parentBlock := &core.Block{BlockHeader: &core.BlockHeader{ChainID: "privatenet", Height: 1, Timestamp: big.NewInt(0)}}
+	build := func(to common.Address) func(gasLimit uint64) *types.SmartContractTx {
+		return func(gasLimit uint64) *types.SmartContractTx {
+			return &types.SmartContractTx{
+				From:     types.TxInput{Address: caller, Coins: types.NewCoins(0, 0)},
+				To:       types.TxOutput{Address: to},
+				GasLimit: gasLimit,
+				GasPrice: big.NewInt(1),
+			}
+		}
+	}
+
+	gasLimit, _, vmErr, err := estimateGas(parentBlock, view, build(storer), types.MaximumTxGasLimit)
+	require.Nil(err)
+	require.Nil(vmErr)
+	// The speculative executions do not modify the state
+	assert.Equal(common.Hash{}, view.GetState(storer, common.Hash{}))
+
+	snapshot, _ := view.Copy()
+	_, _, _, vmErr = vm.Execute(parentBlock, build(storer)(gasLimit), snapshot)
+	assert.Nil(vmErr, "the estimate is enough")
+	snapshot, _ = view.Copy()
+	_, _, _, vmErr = vm.Execute(parentBlock, build(storer)(gasLimit-1), snapshot)
+	assert.NotNil(vmErr, "the estimate is the lowest gas limit")
+
+	_, _, vmErr, err = estimateGas(parentBlock, view, build(reverter), types.MaximumTxGasLimit)
+	require.Nil(err)
+	assert.Equal(vm.ErrExecutionReverted, vmErr)
+}
+
+func TestCallSmartContractDefaultBlock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	require.Nil(features.Configure(map[string]uint64{features.SmartContract: 0}))
+	defer features.Configure(nil)
+
+	// ASM: push 0x0, sload, push 0x0, mstore, push 0x20, push 0x0, return
+	code, _ := hex.DecodeString("60005460005260206000f3")
+	contract := common.HexToAddress("0x100")
+	db := backend.NewMemDatabase()
+	view := state.NewStoreView(0, common.Hash{}, db)
+	view.CreateAccount(contract)
+	view.SetCode(contract, code)
+	view.SetState(contract, common.Hash{}, common.BigToHash(big.NewInt(1)))
+	rootStateHash := view.Save()
+	view.IncrementHeight()
+	view.SetState(contract, common.Hash{}, common.BigToHash(big.NewInt(2)))
+	tipStateHash := view.Save()
+
+	root := core.NewBlock()
+	root.ChainID = "privatenet"
+	root.StateHash = rootStateHash
+	root.Timestamp = big.NewInt(0)
+	chain := blockchain.NewChain(root.ChainID, kvstore.NewKVStore(db), root)
+	privKey, _, _ := crypto.GenerateKeyPair()
+	ce := consensus.NewConsensusEngine(privKey, kvstore.NewKVStore(db), chain, nil, consensus.NewFixedValidatorManager())
+	ldgr := ledger.NewLedger(root.ChainID, db, chain, ce, nil, nil)
+	require.True(ldgr.ResetState(root).IsOK())
+
+	// The tip has been validated, but not yet delivered to the ledger
+	tip := core.NewBlock()
+	tip.ChainID = root.ChainID
+	tip.Height = 1
+	tip.Parent = root.Hash()
+	tip.HCC.BlockHash = root.Hash()
+	tip.StateHash = tipStateHash
+	tip.Timestamp = big.NewInt(1)
+	_, err := chain.AddBlock(tip)
+	require.Nil(err)
+	chain.MarkBlockValid(tip.Hash())
+
+	service := &PandoRPCService{ledger: ldgr, chain: chain, consensus: ce}
+	sctx := &types.SmartContractTx{
+		From:     types.TxInput{Address: common.HexToAddress("0x200"), Coins: types.NewCoins(0, 0)},
+		To:       types.TxOutput{Address: contract},
+		GasLimit: 100000,
+		GasPrice: big.NewInt(1),
+	}
+	raw, err := types.TxToBytes(sctx)
+	require.Nil(err)
+
+	call := func(block BlockSpecifier) *CallSmartContractResult {
+		result := &CallSmartContractResult{}
+		require.Nil(service.CallSmartContract(&CallSmartContractArgs{SctxBytes: hex.EncodeToString(raw), Block: block}, result))
+		require.Equal("", result.VmError)
+		return result
+	}
+	latest := call(BlockSpecifierLatest)
+	assert.Equal(common.JSONUint64(1), latest.BlockHeight)
+	assert.Equal(hex.EncodeToString(common.BigToHash(big.NewInt(2)).Bytes()), latest.VmReturn)
+
+	past := call("0")
+	assert.Equal(common.JSONUint64(0), past.BlockHeight)
+	assert.Equal(hex.EncodeToString(common.BigToHash(big.NewInt(1)).Bytes()), past.VmReturn)
+
+	// Without a block, the call keeps running against the delivered state, which trails the tip
+	assert.Equal(past, call(""), "the default is the delivered state")
+}
@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/state"
+	"golang.org/x/net/websocket"
+)
+
+// maxChangefeedBlocks is the maximum number of blocks returned by one GetStateChanges call
+const maxChangefeedBlocks = 100
+
+// changefeedPollInterval is how often the changefeed stream checks for newly finalized blocks
+const changefeedPollInterval = 1 * time.Second
+
+// BlockStateChanges holds the state mutations applied by a finalized block. Consumers pass
+// the ResumeToken of the last block they processed to resume the changefeed after it.
+type BlockStateChanges struct {
+	Height      common.JSONUint64   `json:"height"`
+	BlockHash   common.Hash         `json:"block_hash"`
+	StateRoot   common.Hash         `json:"state_root"`
+	Changes     []state.StateChange `json:"changes"`
+	ResumeToken string              `json:"resume_token"`
+}
+
+// changefeedToken returns the resume token of the block, in the format of <height>:<block hash>
+func changefeedToken(block *core.ExtendedBlock) string {
+	return fmt.Sprintf("%d:%s", block.Height, block.Hash().Hex())
+}
+
+// resolveChangefeedToken returns the block the token was issued for. The last finalized block
+// is returned if the token is empty.
+func (t *PandoRPCService) resolveChangefeedToken(token string) (*core.ExtendedBlock, error) {
+	if token == "" {
+		return t.consensus.GetLastFinalizedBlock(), nil
+	}
+	parts := strings.Split(token, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid resume token: %v", token)
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid resume token: %v", token)
+	}
+	block, err := t.getFinalizedBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash() != common.HexToHash(parts[1]) {
+		return nil, fmt.Errorf("Resume token %v does not match the finalized block %v", token, block.Hash().Hex())
+	}
+	return block, nil
+}
+
+func (t *PandoRPCService) getFinalizedBlockByHeight(height uint64) (*core.ExtendedBlock, error) {
+	for _, b := range t.chain.FindBlocksByHeight(height) {
+		if b.Status.IsFinalized() {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("Finalized block at height %v is not found", height)
+}
+
+// getStateChangesAfter returns the state changes of up to maxBlocks finalized blocks after
+// the given block in the order of the block heights, and the last of these blocks
+func (t *PandoRPCService) getStateChangesAfter(parent *core.ExtendedBlock, maxBlocks int) ([]BlockStateChanges, *core.ExtendedBlock, error) {
+	db := t.ledger.State().DB()
+	lastFinalized := t.consensus.GetLastFinalizedBlock()
+
+	blocks := []BlockStateChanges{}
+	for height := parent.Height + 1; height <= lastFinalized.Height && len(blocks) < maxBlocks; height++ {
+		block, err := t.getFinalizedBlockByHeight(height)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes, err := state.DiffStates(db, parent.StateHash, block.StateHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		blocks = append(blocks, BlockStateChanges{
+			Height:      common.JSONUint64(block.Height),
+			BlockHash:   block.Hash(),
+			StateRoot:   block.StateHash,
+			Changes:     changes,
+			ResumeToken: changefeedToken(block),
+		})
+		parent = block
+	}
+	return blocks, parent, nil
+}
+
+// ------------------------------ GetStateChanges -----------------------------------
+
+type GetStateChangesArgs struct {
+	ResumeToken string            `json:"resume_token"` // empty to start after the last finalized block
+	MaxBlocks   common.JSONUint64 `json:"max_blocks"`
+}
+
+type GetStateChangesResult struct {
+	Blocks      []BlockStateChanges `json:"blocks"`
+	ResumeToken string              `json:"resume_token"` // token to pass in the next call
+}
+
+func (t *PandoRPCService) GetStateChanges(args *GetStateChangesArgs, result *GetStateChangesResult) (err error) {
+	parent, err := t.resolveChangefeedToken(args.ResumeToken)
+	if err != nil {
+		return err
+	}
+
+	maxBlocks := int(args.MaxBlocks)
+	if maxBlocks <= 0 || maxBlocks > maxChangefeedBlocks {
+		maxBlocks = maxChangefeedBlocks
+	}
+	blocks, last, err := t.getStateChangesAfter(parent, maxBlocks)
+	if err != nil {
+		return err
+	}
+	result.Blocks = blocks
+	result.ResumeToken = changefeedToken(last)
+	return nil
+}
+
+// ------------------------------ Changefeed stream -----------------------------------
+
+// serveChangefeed streams the state changes of every finalized block to the websocket
+// subscriber, one BlockStateChanges message per block. The stream starts after the block
+// of the resume_token query parameter, or after the last finalized block if not specified.
+func (t *PandoRPCService) serveChangefeed(ws *websocket.Conn) {
+	defer ws.Close()
+
+	parent, err := t.resolveChangefeedToken(ws.Request().URL.Query().Get("resume_token"))
+	if err != nil {
+		websocket.JSON.Send(ws, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ticker := time.NewTicker(changefeedPollInterval)
+	defer ticker.Stop()
+
+	for {
+		blocks, last, err := t.getStateChangesAfter(parent, maxChangefeedBlocks)
+		if err != nil {
+			websocket.JSON.Send(ws, map[string]string{"error": err.Error()})
+			return
+		}
+		for _, block := range blocks {
+			if err := websocket.JSON.Send(ws, block); err != nil {
+				logger.Debugf("Changefeed subscriber disconnected: %v", err)
+				return
+			}
+		}
+		if len(blocks) > 0 {
+			parent = last
+			continue
+		}
+
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/ledger/vm"
+	"github.com/pandotoken/pando/rpc/lib/rpc-codec/jsonrpc2"
+)
+
+// ------------------------------- debug_trace* -----------------------------------
+
+// ethCallTracer is the name of the tracer emitting the call frames, the struct logs are emitted otherwise
+const ethCallTracer = "callTracer"
+
+// ethTraceConfig are the options of debug_traceTransaction and debug_traceBlockByHash, as in Geth
+type ethTraceConfig struct {
+	Tracer         string               `json:"tracer"`
+	TracerConfig   *vm.CallTracerConfig `json:"tracerConfig"`
+	DisableStorage bool                 `json:"disableStorage"`
+	DisableStack   bool                 `json:"disableStack"`
+	EnableMemory   bool                 `json:"enableMemory"`
+	Limit          int                  `json:"limit"`
+}
+
+// ethStructLog is a step of the EVM execution in the format of the Geth struct logger
+type ethStructLog struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"` // the slots read or written so far by the contract
+}
+
+// ethExecutionResult is the result of the struct logger
+type ethExecutionResult struct {
+	Gas         uint64          `json:"gas"`
+	Failed      bool            `json:"failed"`
+	ReturnValue string          `json:"returnValue"`
+	StructLogs  []*ethStructLog `json:"structLogs"`
+}
+
+// ethTxTraceResult is the trace of a transaction of a block
+type ethTxTraceResult struct {
+	TxHash common.Hash `json:"txHash"`
+	Result interface{} `json:"result"`
+}
+
+// ethTxTracer traces a smart contract transaction and formats its trace
+type ethTxTracer struct {
+	config       *ethTraceConfig
+	btx          *ethBlockTx
+	callTracer   *vm.CallTracer
+	structLogger *vm.StructLogger
+}
+
+func newEthTxTracer(btx *ethBlockTx, config *ethTraceConfig) (*ethTxTracer, error) {
+	tt := &ethTxTracer{config: config, btx: btx}
+	switch config.Tracer {
+	case ethCallTracer:
+		tt.callTracer = vm.NewCallTracer(config.TracerConfig)
+	case "":
+		tt.structLogger = vm.NewStructLogger(&vm.LogConfig{
+			DisableMemory:  !config.EnableMemory,
+			DisableStack:   config.DisableStack,
+			DisableStorage: config.DisableStorage,
+			Limit:          config.Limit,
+		})
+	default:
+		return nil, jsonrpc2.NewError(ethErrInvalidParams, fmt.Sprintf("Unsupported tracer: %v, only %v and the struct logger are available", config.Tracer, ethCallTracer))
+	}
+	return tt, nil
+}
+
+func (tt *ethTxTracer) tracer() vm.Tracer {
+	if tt.callTracer != nil {
+		return tt.callTracer
+	}
+	return tt.structLogger
+}
+
+// result formats the trace. The gas of the top-level call frame is reported as in the receipt,
+// i.e. including the intrinsic gas of the transaction, which the EVM does not see.
+func (tt *ethTxTracer) result(receipt *blockchain.TxReceiptEntry) interface{} {
+	tx := tt.btx.tx
+	if tt.structLogger != nil {
+		res := &ethExecutionResult{
+			Failed:      tt.structLogger.Error() != nil,
+			ReturnValue: hex.EncodeToString(tt.structLogger.Output()),
+			StructLogs:  formatEthStructLogs(tt.structLogger.StructLogs()),
+		}
+		if receipt != nil {
+			res.Gas = receipt.GasUsed
+		}
+		return res
+	}
+
+	frame := tt.callTracer.Result()
+	if frame == nil {
+		// The transaction failed before the EVM started, e.g. for insufficient balance
+		frame = &vm.CallFrame{
+			Type:  vm.CALL.String(),
+			From:  tx.From.Address,
+			To:    tx.To.Address,
+			Input: hexutil.Bytes(tx.Data),
+		}
+		if tx.To.Address == (common.Address{}) {
+			frame.Type = vm.CREATE.String()
+		}
+		if tx.From.Coins.PTXWei != nil {
+			frame.Value = (*hexutil.Big)(tx.From.Coins.PTXWei)
+		}
+		if receipt != nil {
+			frame.Error = receipt.EvmErr
+		}
+	}
+	frame.Gas = hexutil.Uint64(tx.GasLimit)
+	if receipt != nil {
+		frame.GasUsed = hexutil.Uint64(receipt.GasUsed)
+	}
+	return frame
+}
+
+func formatEthStructLogs(logs []vm.StructLog) []*ethStructLog {
+	formatted := make([]*ethStructLog, 0, len(logs))
+	for _, log := range logs {
+		entry := &ethStructLog{
+			Pc:      log.Pc,
+			Op:      log.Op.String(),
+			Gas:     log.Gas,
+			GasCost: log.GasCost,
+			Depth:   log.Depth,
+		}
+		if log.Err != nil {
+			entry.Error = log.Err.Error()
+		}
+		for _, item := range log.Stack {
+			entry.Stack = append(entry.Stack, hexutil.EncodeBig(item))
+		}
+		for i := 0; i+32 <= len(log.Memory); i += 32 {
+			entry.Memory = append(entry.Memory, hex.EncodeToString(log.Memory[i:i+32]))
+		}
+		if len(log.Storage) > 0 {
+			entry.Storage = make(map[string]string)
+			for slot, value := range log.Storage {
+				entry.Storage[hex.EncodeToString(slot.Bytes())] = hex.EncodeToString(value.Bytes())
+			}
+		}
+		formatted = append(formatted, entry)
+	}
+	return formatted
+}
+
+// debugTraceTransaction re-executes the block of the smart contract transaction with the given
+// Ethereum or Pando hash, and traces the transaction
+func (t *PandoRPCService) debugTraceTransaction(hash common.Hash, config *ethTraceConfig) (interface{}, error) {
+	block, btx := t.findEthTx(hash)
+	if btx == nil {
+		return nil, fmt.Errorf("Smart contract transaction %v is not found", hash.Hex())
+	}
+	tt, err := newEthTxTracer(btx, config)
+	if err != nil {
+		return nil, err
+	}
+	err = t.ledger.TraceBlockTxs(block.Block, int(btx.index), func(idx int, tx *types.SmartContractTx) vm.Tracer {
+		if uint64(idx) != btx.index {
+			return nil
+		}
+		return tt.tracer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	receipt, _ := t.chain.FindTxReceiptByHash(btx.pandoHash)
+	return tt.result(receipt), nil
+}
+
+// debugTraceBlockByHash re-executes the block with the given hash, and traces its smart contract
+// transactions
+func (t *PandoRPCService) debugTraceBlockByHash(hash common.Hash, config *ethTraceConfig) (interface{}, error) {
+	block, err := t.chain.FindBlock(hash)
+	if err != nil || block == nil {
+		return nil, fmt.Errorf("Block %v is not found", hash.Hex())
+	}
+	tracers := make(map[uint64]*ethTxTracer)
+	btxs := t.ethBlockTxs(block)
+	for _, btx := range btxs {
+		tt, err := newEthTxTracer(btx, config)
+		if err != nil {
+			return nil, err
+		}
+		tracers[btx.index] = tt
+	}
+	err = t.ledger.TraceBlockTxs(block.Block, len(block.Txs)-1, func(idx int, tx *types.SmartContractTx) vm.Tracer {
+		if tt, ok := tracers[uint64(idx)]; ok {
+			return tt.tracer()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*ethTxTraceResult{}
+	for _, btx := range btxs {
+		receipt, _ := t.chain.FindTxReceiptByHash(btx.pandoHash)
+		results = append(results, &ethTxTraceResult{
+			TxHash: btx.hash,
+			Result: tracers[btx.index].result(receipt),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,153 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/mempool"
+	"github.com/pandotoken/pando/rpc/lib/rpc-codec/jsonrpc2"
+	"github.com/ybbus/jsonrpc"
+)
+
+// ErrorCategory tells the clients whether and how a failed broadcast can be retried
+type ErrorCategory string
+
+const (
+	// ErrorCategoryRetryable means the same transaction can be submitted again later
+	ErrorCategoryRetryable ErrorCategory = "retryable"
+
+	// ErrorCategoryResign means the transaction needs to be re-signed with a fresh
+	// sequence number, e.g. another transaction of the account got in first
+	ErrorCategoryResign ErrorCategory = "resign"
+
+	// ErrorCategoryPending means the transaction has already been accepted, the clients
+	// should poll its status instead of submitting it again
+	ErrorCategoryPending ErrorCategory = "pending"
+
+	// ErrorCategoryPermanent means the transaction will never be accepted as is
+	ErrorCategoryPermanent ErrorCategory = "permanent"
+)
+
+// Retry hints of the retryable errors, in seconds
+const (
+	retryAfterMempoolFull   uint64 = 10
+	retryAfterFastsync      uint64 = 30
+	retryAfterInternalError uint64 = 5
+)
+
+const broadcastErrorCode = -32000 // the code net/rpc errors are reported with
+
+var (
+	errTxTimeout     = errors.New("Timed out waiting for transaction to be included")
+	errInternalError = errors.New("Internal server error")
+)
+
+// ErrorData is the machine-readable part of a broadcast error, sent in the data field of
+// the JSON-RPC error object
+type ErrorData struct {
+	Code       result.ErrorCode `json:"code"` // the ledger error code, if the tx failed the screening
+	Category   ErrorCategory    `json:"category"`
+	RetryAfter uint64           `json:"retry_after"` // seconds to wait before retrying, 0 if unknown
+}
+
+// BroadcastError is a failed broadcast as seen by the clients
+type BroadcastError struct {
+	ErrorData
+	Message string
+}
+
+func (e *BroadcastError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v (%v, retry after %vs)", e.Message, e.Category, e.RetryAfter)
+	}
+	return fmt.Sprintf("%v (%v)", e.Message, e.Category)
+}
+
+// Retryable indicates whether the same transaction can be broadcasted again
+func (e *BroadcastError) Retryable() bool {
+	return e.Category == ErrorCategoryRetryable
+}
+
+// classifyBroadcastError returns the retry hints of an error returned while
+// inserting a transaction into the mempool
+func classifyBroadcastError(err error) ErrorData {
+	switch err {
+	case mempool.DuplicateTxError, errTxTimeout:
+		return ErrorData{Category: ErrorCategoryPending}
+	case mempool.MempoolFullError:
+		return ErrorData{Category: ErrorCategoryRetryable, RetryAfter: retryAfterMempoolFull}
+	case mempool.FastsyncSkipTxError:
+		return ErrorData{Category: ErrorCategoryRetryable, RetryAfter: retryAfterFastsync}
+	case errInternalError:
+		return ErrorData{Category: ErrorCategoryRetryable, RetryAfter: retryAfterInternalError}
+	}
+
+	if screeningErr, ok := err.(*mempool.TxScreeningError); ok {
+		data := ErrorData{Code: screeningErr.Code, Category: ErrorCategoryPermanent}
+		if screeningErr.Code == result.CodeInvalidSequence {
+			data.Category = ErrorCategoryResign
+		}
+		return data
+	}
+
+	// Malformed transactions, pre-check failures, etc.
+	return ErrorData{Category: ErrorCategoryPermanent}
+}
+
+// newBroadcastError attaches the retry hints to an error returned by the broadcast
+// handlers. The code and message are unchanged so existing clients are not affected.
+func newBroadcastError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &jsonrpc2.Error{
+		Code:    broadcastErrorCode,
+		Message: err.Error(),
+		Data:    classifyBroadcastError(err),
+	}
+}
+
+// ParseBroadcastError converts the error returned by the broadcast RPC call into a
+// BroadcastError. Errors from nodes that do not send the retry hints are treated as
+// permanent, except transport errors which are retryable.
+func ParseBroadcastError(err error) *BroadcastError {
+	if err == nil {
+		return nil
+	}
+
+	var message string
+	var data interface{}
+	switch rpcErr := err.(type) {
+	case *BroadcastError:
+		return rpcErr
+	case *jsonrpc.RPCError:
+		message, data = rpcErr.Message, rpcErr.Data
+	case *jsonrpc2.Error:
+		message, data = rpcErr.Message, rpcErr.Data
+	default:
+		return &BroadcastError{
+			ErrorData: ErrorData{Category: ErrorCategoryRetryable},
+			Message:   err.Error(),
+		}
+	}
+
+	broadcastErr := &BroadcastError{
+		ErrorData: ErrorData{Category: ErrorCategoryPermanent},
+		Message:   message,
+	}
+	if data == nil {
+		return broadcastErr
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return broadcastErr
+	}
+	errData := ErrorData{}
+	if err := json.Unmarshal(raw, &errData); err != nil || len(errData.Category) == 0 {
+		return broadcastErr
+	}
+	broadcastErr.ErrorData = errData
+	return broadcastErr
+}
@@ -0,0 +1,823 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/common/util"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/ledger/vm"
+	"github.com/pandotoken/pando/mempool"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/rpc/lib/rpc-codec/jsonrpc2"
+	"github.com/pandotoken/pando/version"
+)
+
+/*
+Ethereum JSON-RPC compatibility.
+
+The /eth endpoint serves a subset of the Ethereum JSON-RPC API, i.e. the eth_* namespace, so
+that Ethereum tooling (web3.js, ethers, Hardhat, etc.) can target a Pando node directly. The
+Ethereum concepts are mapped onto the ledger as follows:
+
+	accounts      the Pando accounts, with the balance in PTXWei (the EVM native currency)
+	nonce         the sequence of the account
+	transactions  the smart contract transactions, other transactions are not listed
+	block number  the block height
+	"latest"      the last finalized block, "pending" is the tip including the mempool
+
+The debug_traceTransaction and debug_traceBlockByHash methods of the Geth debug namespace
+re-execute the block of the traced transactions on top of the state of its parent, so they
+require the state not to be pruned. They emit the Geth struct logs, or the call frames with the
+callTracer, which show the internal PTX transfers between contracts.
+
+Raw transactions are legacy Ethereum transactions signed with the EIP-155 chain ID returned by
+eth_chainId. They are converted into smart contract transactions signed over their Ethereum
+sign bytes (see ledger/types/eth_tx.go), and can be looked up by their Ethereum hash once they
+are included in a block.
+*/
+
+// Standard JSON-RPC error codes
+const (
+	ethErrParse          = -32700
+	ethErrInvalidRequest = -32600
+	ethErrMethodNotFound = -32601
+	ethErrInvalidParams  = -32602
+	ethErrServer         = -32000
+)
+
+const (
+	// maxEthRequestSize is the size limit of a request body
+	maxEthRequestSize = 5 * 1024 * 1024
+
+	// maxEthBatchSize is the maximum number of requests in a batch
+	maxEthBatchSize = 100
+
+	// maxEthLogsBlockRange is the maximum number of blocks eth_getLogs can scan
+	maxEthLogsBlockRange = 5000
+
+	// ethEmptyUncleHash is the hash of an empty uncle list
+	ethEmptyUncleHash = "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"
+)
+
+var ethNull = json.RawMessage("null")
+
+type ethRequest struct {
+	Version string            `json:"jsonrpc"`
+	ID      *json.RawMessage  `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type ethResponse struct {
+	Version string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *jsonrpc2.Error  `json:"error,omitempty"`
+}
+
+// serveEth handles the Ethereum JSON-RPC requests, including batches
+func (t *PandoRPCService) serveEth(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxEthRequestSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(body) > maxEthRequestSize {
+		json.NewEncoder(w).Encode(newEthErrorResponse(nil, jsonrpc2.NewError(ethErrInvalidRequest, "Request too large")))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []json.RawMessage
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			json.NewEncoder(w).Encode(newEthErrorResponse(nil, jsonrpc2.NewError(ethErrParse, err.Error())))
+			return
+		}
+		if len(reqs) == 0 || len(reqs) > maxEthBatchSize {
+			json.NewEncoder(w).Encode(newEthErrorResponse(nil, jsonrpc2.NewError(ethErrInvalidRequest,
+				fmt.Sprintf("The batch must contain 1 to %v requests", maxEthBatchSize))))
+			return
+		}
+		resps := make([]ethResponse, 0, len(reqs))
+		for _, req := range reqs {
+			resps = append(resps, t.handleEthRequest(req))
+		}
+		json.NewEncoder(w).Encode(resps)
+		return
+	}
+	json.NewEncoder(w).Encode(t.handleEthRequest(body))
+}
+
+func newEthErrorResponse(id *json.RawMessage, err *jsonrpc2.Error) ethResponse {
+	if id == nil {
+		id = &ethNull
+	}
+	return ethResponse{Version: "2.0", ID: id, Error: err}
+}
+
+func (t *PandoRPCService) handleEthRequest(raw json.RawMessage) ethResponse {
+	req := ethRequest{}
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return newEthErrorResponse(nil, jsonrpc2.NewError(ethErrParse, err.Error()))
+	}
+	if req.Version != "2.0" || len(req.Method) == 0 {
+		return newEthErrorResponse(req.ID, jsonrpc2.NewError(ethErrInvalidRequest, "Invalid request"))
+	}
+
+	result, err := t.callEth(req.Method, req.Params)
+	if err != nil {
+		rpcErr, ok := err.(*jsonrpc2.Error)
+		if !ok {
+			rpcErr = jsonrpc2.NewError(ethErrServer, err.Error())
+		}
+		return newEthErrorResponse(req.ID, rpcErr)
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return newEthErrorResponse(req.ID, jsonrpc2.NewError(ethErrServer, err.Error()))
+	}
+	return ethResponse{Version: "2.0", ID: req.ID, Result: encoded}
+}
+
+func (t *PandoRPCService) callEth(method string, params []json.RawMessage) (interface{}, error) {
+	switch method {
+	case "web3_clientVersion":
+		return fmt.Sprintf("Pando/v%v-%v", version.Version, version.GitHash), nil
+	case "net_version":
+		return t.ethChainID().String(), nil
+	case "net_listening":
+		return true, nil
+	case "eth_chainId":
+		return (*hexutil.Big)(t.ethChainID()), nil
+	case "eth_accounts":
+		return []common.Address{}, nil // the node does not manage any key for the clients
+	case "eth_syncing":
+		if t.consensus.HasSynced() {
+			return false, nil
+		}
+		return map[string]hexutil.Uint64{
+			"startingBlock": 0,
+			"currentBlock":  hexutil.Uint64(t.consensus.GetLastFinalizedBlock().Height),
+			"highestBlock":  hexutil.Uint64(t.consensus.GetTipToVote().Height),
+		}, nil
+	case "eth_blockNumber":
+		return hexutil.Uint64(t.consensus.GetLastFinalizedBlock().Height), nil
+	case "eth_gasPrice":
+		view, err := t.ledger.GetScreenedSnapshot()
+		if err != nil {
+			return nil, err
+		}
+		return (*hexutil.Big)(types.BaseGasPrice(types.MaxNextBaseFee(view.GetBaseFee()))), nil
+	case "eth_getBalance", "eth_getTransactionCount", "eth_getCode":
+		var address common.Address
+		var bs ethBlockSpecifier
+		if err := parseEthParams(params, 1, &address, &bs); err != nil {
+			return nil, err
+		}
+		view, err := t.resolveStoreView(bs.spec, BlockSpecifierFinalized)
+		if err != nil {
+			return nil, err
+		}
+		switch method {
+		case "eth_getBalance":
+			return (*hexutil.Big)(view.GetBalance(address)), nil
+		case "eth_getTransactionCount":
+			return hexutil.Uint64(view.GetNonce(address)), nil
+		default:
+			return hexutil.Bytes(view.GetCode(address)), nil
+		}
+	case "eth_getStorageAt":
+		var address common.Address
+		var key hexutil.Big
+		var bs ethBlockSpecifier
+		if err := parseEthParams(params, 2, &address, &key, &bs); err != nil {
+			return nil, err
+		}
+		view, err := t.resolveStoreView(bs.spec, BlockSpecifierFinalized)
+		if err != nil {
+			return nil, err
+		}
+		return view.GetState(address, common.BigToHash(key.ToInt())), nil
+	case "eth_call":
+		var call ethCallArgs
+		var bs ethBlockSpecifier
+		if err := parseEthParams(params, 1, &call, &bs); err != nil {
+			return nil, err
+		}
+		return t.ethCall(&call, bs.spec)
+	case "eth_estimateGas":
+		var call ethCallArgs
+		var bs ethBlockSpecifier
+		if err := parseEthParams(params, 1, &call, &bs); err != nil {
+			return nil, err
+		}
+		return t.ethEstimateGas(&call, bs.spec)
+	case "eth_sendRawTransaction":
+		var raw hexutil.Bytes
+		if err := parseEthParams(params, 1, &raw); err != nil {
+			return nil, err
+		}
+		return t.ethSendRawTransaction(raw)
+	case "eth_getTransactionByHash", "eth_getTransactionReceipt":
+		var hash common.Hash
+		if err := parseEthParams(params, 1, &hash); err != nil {
+			return nil, err
+		}
+		block, btx := t.findEthTx(hash)
+		if btx == nil {
+			return nil, nil
+		}
+		if method == "eth_getTransactionByHash" {
+			return t.newEthTransaction(block, btx), nil
+		}
+		for _, receipt := range t.ethBlockReceipts(block) {
+			if receipt.TransactionIndex == hexutil.Uint64(btx.index) {
+				return receipt, nil
+			}
+		}
+		return nil, nil
+	case "eth_getBlockByNumber", "eth_getBlockByHash":
+		var bs ethBlockSpecifier
+		var fullTxs bool
+		if err := parseEthParams(params, 1, &bs, &fullTxs); err != nil {
+			return nil, err
+		}
+		if method == "eth_getBlockByHash" {
+			if _, ok := bs.spec.Hash(); !ok {
+				return nil, jsonrpc2.NewError(ethErrInvalidParams, "Invalid block hash")
+			}
+		}
+		block, err := t.resolveBlock(bs.spec, BlockSpecifierFinalized)
+		if err != nil || block == nil {
+			return nil, nil
+		}
+		return t.newEthBlock(block, fullTxs), nil
+	case "eth_getLogs":
+		var query ethLogQuery
+		if err := parseEthParams(params, 1, &query); err != nil {
+			return nil, err
+		}
+		return t.ethGetLogs(&query)
+	case "debug_traceTransaction", "debug_traceBlockByHash":
+		var hash common.Hash
+		config := &ethTraceConfig{}
+		if err := parseEthParams(params, 1, &hash, config); err != nil {
+			return nil, err
+		}
+		if method == "debug_traceTransaction" {
+			return t.debugTraceTransaction(hash, config)
+		}
+		return t.debugTraceBlockByHash(hash, config)
+	}
+	return nil, jsonrpc2.NewError(ethErrMethodNotFound, fmt.Sprintf("Method not found: %v", method))
+}
+
+// parseEthParams decodes the positional params into the values, only the first required
+// params are mandatory
+func parseEthParams(params []json.RawMessage, required int, values ...interface{}) error {
+	if len(params) < required || len(params) > len(values) {
+		return jsonrpc2.NewError(ethErrInvalidParams, fmt.Sprintf("Expected %v to %v params, got %v", required, len(values), len(params)))
+	}
+	for i, param := range params {
+		if err := json.Unmarshal(param, values[i]); err != nil {
+			return jsonrpc2.NewError(ethErrInvalidParams, fmt.Sprintf("Invalid param %v: %v", i, err))
+		}
+	}
+	return nil
+}
+
+func (t *PandoRPCService) ethChainID() *big.Int {
+	return types.MapChainID(t.chain.ChainID)
+}
+
+// ethBlockSpecifier is the Ethereum block parameter. It is a block number or tag, or an
+// EIP-1898 object with either the blockNumber or the blockHash.
+type ethBlockSpecifier struct {
+	spec BlockSpecifier
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (bs *ethBlockSpecifier) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		var obj struct {
+			BlockNumber string `json:"blockNumber"`
+			BlockHash   string `json:"blockHash"`
+		}
+		if err := json.Unmarshal(input, &obj); err != nil {
+			return fmt.Errorf("Invalid block specifier: %s", string(input))
+		}
+		s = obj.BlockNumber
+		if len(obj.BlockHash) > 0 {
+			s = obj.BlockHash
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "latest", "safe", "finalized":
+		bs.spec = BlockSpecifierFinalized
+		return nil
+	case "pending":
+		bs.spec = BlockSpecifierPending
+		return nil
+	case "earliest":
+		bs.spec = "0"
+		return nil
+	}
+	bs.spec = BlockSpecifier(s)
+	if bs.spec.IsEmpty() {
+		return fmt.Errorf("Invalid block specifier: %s", string(input))
+	}
+	_, _, err := bs.spec.parse()
+	return err
+}
+
+// ------------------------------- Blocks and transactions -----------------------------------
+
+type ethBlock struct {
+	Number           hexutil.Uint64 `json:"number"`
+	Hash             common.Hash    `json:"hash"`
+	ParentHash       common.Hash    `json:"parentHash"`
+	Nonce            hexutil.Bytes  `json:"nonce"`
+	Sha3Uncles       string         `json:"sha3Uncles"`
+	LogsBloom        hexutil.Bytes  `json:"logsBloom"`
+	TransactionsRoot common.Hash    `json:"transactionsRoot"`
+	StateRoot        common.Hash    `json:"stateRoot"`
+	ReceiptsRoot     common.Hash    `json:"receiptsRoot"`
+	Miner            common.Address `json:"miner"`
+	Difficulty       hexutil.Uint64 `json:"difficulty"`
+	TotalDifficulty  hexutil.Uint64 `json:"totalDifficulty"`
+	ExtraData        hexutil.Bytes  `json:"extraData"`
+	Size             hexutil.Uint64 `json:"size"`
+	GasLimit         hexutil.Uint64 `json:"gasLimit"`
+	GasUsed          hexutil.Uint64 `json:"gasUsed"`
+	Timestamp        *hexutil.Big   `json:"timestamp"`
+	Transactions     []interface{}  `json:"transactions"` // hashes, or ethTransaction objects
+	Uncles           []common.Hash  `json:"uncles"`
+}
+
+type ethTransaction struct {
+	BlockHash        *common.Hash    `json:"blockHash"`
+	BlockNumber      *hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+	Hash             common.Hash     `json:"hash"`
+	From             common.Address  `json:"from"`
+	To               *common.Address `json:"to"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	Gas              hexutil.Uint64  `json:"gas"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Value            *hexutil.Big    `json:"value"`
+	Input            hexutil.Bytes   `json:"input"`
+	Type             hexutil.Uint64  `json:"type"`
+	ChainID          *hexutil.Big    `json:"chainId"`
+	V                *hexutil.Big    `json:"v"`
+	R                *hexutil.Big    `json:"r"`
+	S                *hexutil.Big    `json:"s"`
+}
+
+type ethReceipt struct {
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64  `json:"transactionIndex"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*ethLog       `json:"logs"`
+	LogsBloom         hexutil.Bytes   `json:"logsBloom"`
+	Status            hexutil.Uint64  `json:"status"`
+	Type              hexutil.Uint64  `json:"type"`
+	RevertReason      string          `json:"revertReason,omitempty"` // a Pando extension, as in some Ethereum clients
+}
+
+type ethLog struct {
+	Address          common.Address `json:"address"`
+	Topics           []common.Hash  `json:"topics"`
+	Data             hexutil.Bytes  `json:"data"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	LogIndex         hexutil.Uint64 `json:"logIndex"` // index of the log in the block
+	Removed          bool           `json:"removed"`
+}
+
+// ethBlockTx is a smart contract transaction of a block
+type ethBlockTx struct {
+	tx        *types.SmartContractTx
+	hash      common.Hash // the Ethereum hash if signed as an Ethereum transaction, the Pando hash otherwise
+	pandoHash common.Hash
+	index     uint64 // index of the transaction in the block
+}
+
+// ethBlockTxs returns the smart contract transactions of the block
+func (t *PandoRPCService) ethBlockTxs(block *core.ExtendedBlock) []*ethBlockTx {
+	btxs := []*ethBlockTx{}
+	for i, raw := range block.Txs {
+		tx, err := types.TxFromBytes(raw)
+		if err != nil {
+			continue
+		}
+		sctx, ok := tx.(*types.SmartContractTx)
+		if !ok {
+			continue
+		}
+		btx := &ethBlockTx{
+			tx:        sctx,
+			pandoHash: crypto.Keccak256Hash(raw),
+			index:     uint64(i),
+		}
+		btx.hash = btx.pandoHash
+		if ethHash, ok := types.EthTxHash(block.ChainID, sctx); ok {
+			btx.hash = ethHash
+		}
+		btxs = append(btxs, btx)
+	}
+	return btxs
+}
+
+// findEthTx looks up a smart contract transaction by its Ethereum or Pando hash
+func (t *PandoRPCService) findEthTx(hash common.Hash) (*core.ExtendedBlock, *ethBlockTx) {
+	raw, block, found := t.chain.FindTxByHash(hash)
+	if !found {
+		return nil, nil
+	}
+	pandoHash := crypto.Keccak256Hash(raw)
+	for _, btx := range t.ethBlockTxs(block) {
+		if btx.pandoHash == pandoHash {
+			return block, btx
+		}
+	}
+	return nil, nil
+}
+
+func (t *PandoRPCService) newEthTransaction(block *core.ExtendedBlock, btx *ethBlockTx) *ethTransaction {
+	tx := btx.tx
+	chainID := types.MapChainID(block.ChainID)
+	blockHash := block.Hash()
+	blockNumber := hexutil.Uint64(block.Height)
+	index := hexutil.Uint64(btx.index)
+	etx := &ethTransaction{
+		BlockHash:        &blockHash,
+		BlockNumber:      &blockNumber,
+		TransactionIndex: &index,
+		Hash:             btx.hash,
+		From:             tx.From.Address,
+		To:               ethRecipient(tx.To.Address),
+		Gas:              hexutil.Uint64(tx.GasLimit),
+		GasPrice:         (*hexutil.Big)(tx.GasPrice),
+		Value:            (*hexutil.Big)(tx.From.Coins.NoNil().PTXWei),
+		Input:            hexutil.Bytes(tx.Data),
+		ChainID:          (*hexutil.Big)(chainID),
+	}
+	if tx.From.Sequence > 0 {
+		etx.Nonce = hexutil.Uint64(tx.From.Sequence - 1)
+	}
+	if tx.From.Signature != nil && len(tx.From.Signature.ToBytes()) == 65 {
+		sig := tx.From.Signature.ToBytes()
+		v := new(big.Int).Lsh(chainID, 1)
+		v.Add(v, big.NewInt(35+int64(sig[64])))
+		etx.V = (*hexutil.Big)(v)
+		etx.R = (*hexutil.Big)(new(big.Int).SetBytes(sig[:32]))
+		etx.S = (*hexutil.Big)(new(big.Int).SetBytes(sig[32:64]))
+	}
+	return etx
+}
+
+func ethRecipient(address common.Address) *common.Address {
+	if (address == common.Address{}) {
+		return nil // contract creation
+	}
+	return &address
+}
+
+// ethBlockReceipts returns the receipts of the smart contract transactions of the block
+func (t *PandoRPCService) ethBlockReceipts(block *core.ExtendedBlock) []*ethReceipt {
+	receipts := []*ethReceipt{}
+	blockHash := block.Hash()
+	cumulativeGasUsed := uint64(0)
+	logIndex := uint64(0)
+	for _, btx := range t.ethBlockTxs(block) {
+		entry, found := t.chain.FindTxReceiptByHash(btx.pandoHash)
+		if !found {
+			continue
+		}
+		cumulativeGasUsed += entry.GasUsed
+		receipt := &ethReceipt{
+			TransactionHash:   btx.hash,
+			TransactionIndex:  hexutil.Uint64(btx.index),
+			BlockHash:         blockHash,
+			BlockNumber:       hexutil.Uint64(block.Height),
+			From:              btx.tx.From.Address,
+			To:                ethRecipient(btx.tx.To.Address),
+			CumulativeGasUsed: hexutil.Uint64(cumulativeGasUsed),
+			GasUsed:           hexutil.Uint64(entry.GasUsed),
+			EffectiveGasPrice: (*hexutil.Big)(btx.tx.GasPrice),
+			Logs:              []*ethLog{},
+		}
+		if receipt.To == nil && len(entry.EvmErr) == 0 {
+			receipt.ContractAddress = &entry.ContractAddress
+		}
+		if len(entry.EvmErr) == 0 {
+			receipt.Status = 1
+		}
+		receipt.RevertReason = entry.RevertReason
+		for _, log := range entry.Logs {
+			receipt.Logs = append(receipt.Logs, &ethLog{
+				Address:          log.Address,
+				Topics:           log.Topics,
+				Data:             hexutil.Bytes(log.Data),
+				BlockNumber:      receipt.BlockNumber,
+				BlockHash:        blockHash,
+				TransactionHash:  btx.hash,
+				TransactionIndex: receipt.TransactionIndex,
+				LogIndex:         hexutil.Uint64(logIndex),
+			})
+			logIndex++
+		}
+		receipt.LogsBloom = hexutil.Bytes(types.LogsBloom(entry.Logs).Bytes())
+		receipts = append(receipts, receipt)
+	}
+	return receipts
+}
+
+func (t *PandoRPCService) newEthBlock(block *core.ExtendedBlock, fullTxs bool) *ethBlock {
+	eb := &ethBlock{
+		Number:           hexutil.Uint64(block.Height),
+		Hash:             block.Hash(),
+		ParentHash:       block.Parent,
+		Nonce:            make(hexutil.Bytes, 8),
+		Sha3Uncles:       ethEmptyUncleHash,
+		LogsBloom:        hexutil.Bytes(block.Bloom.Bytes()),
+		TransactionsRoot: block.TxHash,
+		StateRoot:        block.StateHash,
+		ReceiptsRoot:     block.ReceiptHash,
+		Miner:            block.Proposer,
+		ExtraData:        hexutil.Bytes{},
+		GasLimit:         hexutil.Uint64(types.MaximumTxGasLimit),
+		Timestamp:        (*hexutil.Big)(block.Timestamp),
+		Transactions:     []interface{}{},
+		Uncles:           []common.Hash{},
+	}
+	if raw, err := rlp.EncodeToBytes(block.Block); err == nil {
+		eb.Size = hexutil.Uint64(len(raw))
+	}
+	if eb.Timestamp == nil {
+		eb.Timestamp = (*hexutil.Big)(big.NewInt(0))
+	}
+	if bloom, ok := t.chain.FindBlockBloom(block.Hash()); ok {
+		eb.LogsBloom = hexutil.Bytes(bloom.Bytes())
+	}
+	for _, btx := range t.ethBlockTxs(block) {
+		if fullTxs {
+			eb.Transactions = append(eb.Transactions, t.newEthTransaction(block, btx))
+		} else {
+			eb.Transactions = append(eb.Transactions, btx.hash)
+		}
+	}
+	for _, receipt := range t.ethBlockReceipts(block) {
+		eb.GasUsed += receipt.GasUsed
+	}
+	return eb
+}
+
+// ------------------------------- Logs -----------------------------------
+
+// ethLogQuery is the filter of eth_getLogs
+type ethLogQuery struct {
+	LogFilter
+	FromBlock *ethBlockSpecifier
+	ToBlock   *ethBlockSpecifier
+	BlockHash *common.Hash
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (q *ethLogQuery) UnmarshalJSON(data []byte) error {
+	if err := q.LogFilter.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var raw struct {
+		FromBlock *ethBlockSpecifier `json:"fromBlock"`
+		ToBlock   *ethBlockSpecifier `json:"toBlock"`
+		BlockHash *common.Hash       `json:"blockHash"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.BlockHash != nil && (raw.FromBlock != nil || raw.ToBlock != nil) {
+		return fmt.Errorf("blockHash cannot be combined with fromBlock or toBlock")
+	}
+	q.FromBlock, q.ToBlock, q.BlockHash = raw.FromBlock, raw.ToBlock, raw.BlockHash
+	return nil
+}
+
+func (t *PandoRPCService) ethGetLogs(query *ethLogQuery) ([]*ethLog, error) {
+	blocks := []*core.ExtendedBlock{}
+	if query.BlockHash != nil {
+		block, err := t.chain.FindBlock(*query.BlockHash)
+		if err != nil {
+			return nil, fmt.Errorf("Block %v is not found", query.BlockHash.Hex())
+		}
+		blocks = append(blocks, block)
+	} else {
+		from, err := t.resolveEthBlockHeight(query.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		to, err := t.resolveEthBlockHeight(query.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		if from > to {
+			return []*ethLog{}, nil
+		}
+		if to-from >= maxEthLogsBlockRange {
+			return nil, fmt.Errorf("The block range cannot exceed %v blocks", maxEthLogsBlockRange)
+		}
+		for height := from; height <= to; height++ {
+			for _, block := range t.chain.FindBlocksByHeight(height) {
+				if block.Status.IsFinalized() {
+					blocks = append(blocks, block)
+					break
+				}
+			}
+		}
+	}
+
+	logs := []*ethLog{}
+	for _, block := range blocks {
+		if bloom, ok := t.chain.FindBlockBloom(block.Hash()); ok && !query.MatchesBloom(bloom) {
+			continue
+		}
+		for _, receipt := range t.ethBlockReceipts(block) {
+			for _, log := range receipt.Logs {
+				if query.Matches(&types.Log{Address: log.Address, Topics: log.Topics}) {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+	return logs, nil
+}
+
+// resolveEthBlockHeight returns the height of the block, the last finalized block by default
+func (t *PandoRPCService) resolveEthBlockHeight(bs *ethBlockSpecifier) (uint64, error) {
+	if bs == nil {
+		return t.consensus.GetLastFinalizedBlock().Height, nil
+	}
+	if height, ok := bs.spec.Height(); ok {
+		return height, nil
+	}
+	block, err := t.resolveBlock(bs.spec, BlockSpecifierFinalized)
+	if err != nil {
+		return 0, err
+	}
+	return block.Height, nil
+}
+
+// ------------------------------- Calls and transactions -----------------------------------
+
+// ethCallArgs is the call object of eth_call and eth_estimateGas
+type ethCallArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     *hexutil.Bytes  `json:"data"`
+	Input    *hexutil.Bytes  `json:"input"`
+}
+
+func (args *ethCallArgs) toSmartContractTx(gasLimit uint64) *types.SmartContractTx {
+	tx := &types.SmartContractTx{
+		From: types.TxInput{
+			Address: args.From,
+			Coins:   types.NewCoins(0, 0),
+		},
+		GasLimit: gasLimit,
+		GasPrice: new(big.Int).SetUint64(types.MinimumGasPrice),
+	}
+	if args.To != nil {
+		tx.To.Address = *args.To
+	}
+	if args.GasPrice != nil {
+		tx.GasPrice = args.GasPrice.ToInt()
+	}
+	if args.Value != nil {
+		tx.From.Coins.PTXWei = args.Value.ToInt()
+	}
+	if args.Input != nil {
+		tx.Data = common.Bytes(*args.Input)
+	} else if args.Data != nil {
+		tx.Data = common.Bytes(*args.Data)
+	}
+	return tx
+}
+
+// ethCallContext resolves the parent block and the state the calls are executed on
+func (t *PandoRPCService) ethCallContext(bs BlockSpecifier) (*core.Block, *state.StoreView, error) {
+	block, err := t.resolveBlock(bs, BlockSpecifierFinalized)
+	if err != nil {
+		return nil, nil, err
+	}
+	view, err := t.resolveStoreView(bs, BlockSpecifierFinalized)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !features.IsEnabled(features.SmartContract, view.Height()+1) {
+		return nil, nil, fmt.Errorf("Smart contract feature not enabled until block height %v.", features.Height(features.SmartContract))
+	}
+	return block.Block, view, nil
+}
+
+func (t *PandoRPCService) ethCall(args *ethCallArgs, bs BlockSpecifier) (interface{}, error) {
+	parentBlock, view, err := t.ethCallContext(bs)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit := types.MaximumTxGasLimit
+	if args.Gas != nil {
+		gasLimit = uint64(*args.Gas)
+	}
+	vmRet, _, _, vmErr := t.callCache.execute(parentBlock, args.toSmartContractTx(gasLimit), view)
+	if vmErr != nil {
+		return nil, newEthExecutionError(vmErr, vmRet)
+	}
+	return hexutil.Bytes(vmRet), nil
+}
+
+// ethEstimateGas searches for the lowest gas limit the call succeeds with
+func (t *PandoRPCService) ethEstimateGas(args *ethCallArgs, bs BlockSpecifier) (interface{}, error) {
+	if bs.IsEmpty() {
+		bs = BlockSpecifierPending
+	}
+	parentBlock, view, err := t.ethCallContext(bs)
+	if err != nil {
+		return nil, err
+	}
+	hi := types.MaximumTxGasLimit
+	if args.Gas != nil && uint64(*args.Gas) < hi {
+		hi = uint64(*args.Gas)
+	}
+	gasLimit, vmRet, vmErr, err := estimateGas(parentBlock, view, args.toSmartContractTx, hi)
+	if err != nil {
+		return nil, err
+	}
+	if vmErr != nil {
+		return nil, newEthExecutionError(vmErr, vmRet)
+	}
+	return hexutil.Uint64(gasLimit), nil
+}
+
+// newEthExecutionError returns the error of a failed call, with the revert data if any
+func newEthExecutionError(vmErr error, vmRet common.Bytes) error {
+	if vmErr == vm.ErrExecutionReverted {
+		return &jsonrpc2.Error{Code: 3, Message: "execution reverted", Data: hexutil.Bytes(vmRet)}
+	}
+	return jsonrpc2.NewError(ethErrServer, vmErr.Error())
+}
+
+func (t *PandoRPCService) ethSendRawTransaction(raw hexutil.Bytes) (interface{}, error) {
+	tx, err := types.SmartContractTxFromEthTx(t.chain.ChainID, common.Bytes(raw))
+	if err != nil {
+		return nil, jsonrpc2.NewError(ethErrInvalidParams, err.Error())
+	}
+	txBytes, err := types.TxToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	hash := crypto.Keccak256Hash(raw)
+	pandoHash := crypto.Keccak256Hash(txBytes)
+
+	logger.Infof("Broadcast Ethereum transaction: %v, hash: %v, Pando hash: %v", tx, hash.Hex(), pandoHash.Hex())
+	util.Trace(util.TraceTx, pandoHash.Hex(), "rpc", "submitted", "eth_sendRawTransaction, Ethereum hash: %v", hash.Hex())
+
+	err = t.mempool.InsertTransaction(txBytes)
+	if err == mempool.DuplicateTxError {
+		return hash, nil
+	}
+	if err != nil {
+		return nil, newBroadcastError(err)
+	}
+	t.mempool.BroadcastTx(txBytes)
+
+	return hash, nil
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthBlockSpecifier(t *testing.T) {
+	assert := assert.New(t)
+
+	parse := func(input string) (BlockSpecifier, error) {
+		bs := ethBlockSpecifier{}
+		err := json.Unmarshal([]byte(input), &bs)
+		return bs.spec, err
+	}
+
+	for input, expected := range map[string]BlockSpecifier{
+		`"latest"`:               BlockSpecifierFinalized,
+		`"safe"`:                 BlockSpecifierFinalized,
+		`"pending"`:              BlockSpecifierPending,
+		`"earliest"`:             "0",
+		`"0x1b4"`:                "0x1b4",
+		`{"blockNumber":"0x10"}`: "0x10",
+		`{"blockHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}`: "0x0000000000000000000000000000000000000000000000000000000000000001",
+	} {
+		spec, err := parse(input)
+		assert.Nil(err, input)
+		assert.Equal(expected, spec, input)
+	}
+
+	height, ok := BlockSpecifier("0x1b4").Height()
+	assert.True(ok)
+	assert.Equal(uint64(436), height)
+
+	for _, input := range []string{`""`, `"0xzz"`, `"oldest"`, `[]`} {
+		_, err := parse(input)
+		assert.NotNil(err, input)
+	}
+
+	query := ethLogQuery{}
+	assert.Nil(json.Unmarshal([]byte(`{"fromBlock":"0x1","toBlock":"latest","address":"0x1111111111111111111111111111111111111111","topics":[null,"0xaa"]}`), &query))
+	assert.Equal(BlockSpecifier("0x1"), query.FromBlock.spec)
+	assert.Equal(BlockSpecifierFinalized, query.ToBlock.spec)
+	assert.Equal([]common.Address{common.HexToAddress("0x1111111111111111111111111111111111111111")}, query.Addresses)
+	assert.Equal(2, len(query.Topics))
+	assert.NotNil(json.Unmarshal([]byte(`{"fromBlock":"0x1","blockHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}`), &ethLogQuery{}))
+}
+
+type ethTestResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func TestServeEth(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	service := &PandoRPCService{chain: &blockchain.Chain{ChainID: "mainnet"}}
+	server := httptest.NewServer(http.HandlerFunc(service.serveEth))
+	defer server.Close()
+
+	post := func(body string) []byte {
+		resp, err := http.Post(server.URL, "application/json", bytes.NewBufferString(body))
+		require.Nil(err)
+		defer resp.Body.Close()
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(resp.Body)
+		return buf.Bytes()
+	}
+
+	res := ethTestResponse{}
+	require.Nil(json.Unmarshal(post(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`), &res))
+	assert.Nil(res.Error)
+	assert.Equal(`"0x1"`, string(res.Result))
+
+	batch := []ethTestResponse{}
+	require.Nil(json.Unmarshal(post(`[
+		{"jsonrpc":"2.0","id":1,"method":"net_version"},
+		{"jsonrpc":"2.0","id":2,"method":"eth_foo","params":[]},
+		{"jsonrpc":"2.0","id":3,"method":"eth_getBalance","params":[]},
+		{"jsonrpc":"2.0","id":4,"method":"eth_sendRawTransaction","params":["0x01"]},
+		{"jsonrpc":"2.0","id":5,"method":"eth_accounts"}
+	]`), &batch))
+	require.Equal(5, len(batch))
+	assert.Equal(`"1"`, string(batch[0].Result))
+	assert.Equal(ethErrMethodNotFound, batch[1].Error.Code)
+	assert.Equal(ethErrInvalidParams, batch[2].Error.Code)
+	assert.Equal(ethErrInvalidParams, batch[3].Error.Code)
+	assert.Equal(`[]`, string(batch[4].Result))
+	for i, r := range batch {
+		assert.Equal(i+1, r.ID)
+	}
+
+	res = ethTestResponse{}
+	require.Nil(json.Unmarshal(post(`{"jsonrpc":"2.0","id":1,`), &res))
+	assert.Equal(ethErrParse, res.Error.Code)
+	require.Nil(json.Unmarshal(post(`[]`), &res))
+	assert.Equal(ethErrInvalidRequest, res.Error.Code)
+}
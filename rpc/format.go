@@ -0,0 +1,216 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pandotoken/pando/common/hexutil"
+)
+
+/*
+RPC response formats.
+
+The /rpc endpoint answers in the native format by default, i.e. snake_case field names and the
+quantities as decimal strings. A request with the header
+
+	Pando-RPC-Format: eth
+
+gets the result in the Ethereum style instead, so that the infrastructure ported from Ethereum
+can parse it with its existing decoders:
+
+	field names  camelCase, e.g. "block_hash" becomes "blockHash" and "ID" becomes "id"
+	quantities   0x-prefixed hex strings without leading zeros, e.g. "1000" becomes "0x3e8"
+
+The conversion is applied to the result of each response, the JSON-RPC envelope and the errors
+are left as is. Quantities are recognized by their encoding: the non-negative integer numbers and
+the strings made only of decimal digits. The hex strings (hashes, addresses, data) and the map
+keys other than field names are not changed.
+*/
+
+const (
+	// RPCFormatHeader is the HTTP header selecting the format of the RPC responses
+	RPCFormatHeader = "Pando-RPC-Format"
+
+	// RPCFormatNative is the default format of the RPC responses
+	RPCFormatNative = "native"
+
+	// RPCFormatEth is the Ethereum style format of the RPC responses
+	RPCFormatEth = "eth"
+)
+
+// formatMiddleware converts the responses of the requests asking for the Ethereum style format
+func formatMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.ToLower(r.Header.Get(RPCFormatHeader)) {
+		case "", RPCFormatNative:
+			handler.ServeHTTP(w, r)
+		case RPCFormatEth:
+			bw := &bufferedResponseWriter{header: w.Header(), code: http.StatusOK}
+			handler.ServeHTTP(bw, r)
+
+			body := bw.body.Bytes()
+			if bw.code == http.StatusOK {
+				if converted, err := toEthFormatResponse(body); err == nil {
+					body = converted
+				} else {
+					logger.Warnf("Failed to convert the RPC response to the %v format: %v", RPCFormatEth, err)
+				}
+			}
+			w.Header().Del("Content-Length")
+			w.WriteHeader(bw.code)
+			w.Write(body)
+		default:
+			http.Error(w, "Unsupported "+RPCFormatHeader+": "+r.Header.Get(RPCFormatHeader), http.StatusBadRequest)
+		}
+	})
+}
+
+// bufferedResponseWriter holds the response of a handler so that it can be converted
+type bufferedResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+	code   int
+}
+
+func (bw *bufferedResponseWriter) Header() http.Header { return bw.header }
+
+func (bw *bufferedResponseWriter) Write(p []byte) (int, error) { return bw.body.Write(p) }
+
+func (bw *bufferedResponseWriter) WriteHeader(code int) { bw.code = code }
+
+// toEthFormatResponse converts the results of a JSON-RPC response, or of a batch of responses, to
+// the Ethereum style format
+func toEthFormatResponse(body []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body, nil // notification
+	}
+
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	switch resp := decoded.(type) {
+	case map[string]interface{}:
+		convertEthFormatResult(resp)
+	case []interface{}:
+		for _, r := range resp {
+			if r, ok := r.(map[string]interface{}); ok {
+				convertEthFormatResult(r)
+			}
+		}
+	}
+
+	converted, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+	return append(converted, '\n'), nil
+}
+
+func convertEthFormatResult(resp map[string]interface{}) {
+	if result, ok := resp["result"]; ok {
+		resp["result"] = toEthFormat(result)
+	}
+}
+
+// toEthFormat converts a decoded JSON value to the Ethereum style format
+func toEthFormat(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			converted[toCamelCase(key)] = toEthFormat(field)
+		}
+		return converted
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = toEthFormat(elem)
+		}
+		return v
+	case json.Number:
+		if quantity, ok := toEthQuantity(string(v)); ok {
+			return quantity
+		}
+		return v
+	case string:
+		if quantity, ok := toEthQuantity(v); ok {
+			return quantity
+		}
+		return v
+	}
+	return value
+}
+
+// toEthQuantity returns the hex encoding of a non-negative decimal integer
+func toEthQuantity(s string) (string, bool) {
+	if len(s) == 0 {
+		return "", false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	if len(s) < 20 { // fits in an uint64
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return hexutil.EncodeUint64(n), true
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return "", false
+	}
+	return hexutil.EncodeBig(n), true
+}
+
+// toCamelCase converts a snake_case or a PascalCase field name to camelCase. The other map keys,
+// e.g. the hex addresses, are not changed.
+func toCamelCase(key string) string {
+	if len(key) == 0 || !unicode.IsLetter(rune(key[0])) {
+		return key
+	}
+
+	var sb strings.Builder
+	for i, word := range strings.Split(key, "_") {
+		if len(word) == 0 {
+			continue
+		}
+		if i == 0 {
+			sb.WriteString(lowerInitialism(word))
+		} else {
+			sb.WriteString(strings.ToUpper(word[:1]))
+			sb.WriteString(word[1:])
+		}
+	}
+	return sb.String()
+}
+
+// lowerInitialism lowers the leading upper case letters of a word, keeping the first letter of
+// the next word upper case, e.g. "TxHash" becomes "txHash" and "HTTPServer" becomes "httpServer"
+func lowerInitialism(word string) string {
+	runes := []rune(word)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	if i > 1 && i < len(runes) && unicode.IsLower(runes[i]) {
+		i-- // the last upper case letter starts the next word
+	}
+	for j := 0; j < i; j++ {
+		runes[j] = unicode.ToLower(runes[j])
+	}
+	if i == 0 {
+		return word
+	}
+	return string(runes)
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToCamelCase(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("blockHash", toCamelCase("block_hash"))
+	assert.Equal("height", toCamelCase("height"))
+	assert.Equal("txHash", toCamelCase("TxHash"))
+	assert.Equal("id", toCamelCase("ID"))
+	assert.Equal("httpServer", toCamelCase("HTTPServer"))
+	assert.Equal("baseGasPrice", toCamelCase("base_gas_price"))
+	assert.Equal("0x2e833968e5bb786ae419c4d13189fb081cc43bab", toCamelCase("0x2e833968e5bb786ae419c4d13189fb081cc43bab"))
+}
+
+func TestFormatMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	response := `{"jsonrpc":"2.0","id":7,"result":{"height":"1000","block_hash":"0x12ab",` +
+		`"balance":{"PandoWei":"25000000000000000000000","PTXWei":"0"},"Status":2,"ratio":0.5,"txs":[{"gas_limit":"21000"}]}}`
+	handler := formatMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, response)
+	}))
+
+	serve := func(format string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/rpc", strings.NewReader(`{}`))
+		if format != "" {
+			req.Header.Set(RPCFormatHeader, format)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The native format by default
+	assert.Equal(response, serve("").Body.String())
+	assert.Equal(response, serve(RPCFormatNative).Body.String())
+
+	// The Ethereum style format on request, with the envelope left as is
+	rec := serve("ETH")
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(`{"jsonrpc":"2.0","id":7,"result":{"height":"0x3e8","blockHash":"0x12ab",`+
+		`"balance":{"pandoWei":"0x54b40b1f852bda00000","ptxWei":"0x0"},"status":"0x2","ratio":0.5,"txs":[{"gasLimit":"0x5208"}]}}`,
+		rec.Body.String())
+
+	// Errors and batches
+	body, err := toEthFormatResponse([]byte(`[{"id":"1","error":{"code":-32000,"message":"not_found"}},{"id":"2","result":"16"}]`))
+	assert.Nil(err)
+	assert.JSONEq(`[{"id":"1","error":{"code":-32000,"message":"not_found"}},{"id":"2","result":"0x10"}]`, string(body))
+
+	assert.Equal(http.StatusBadRequest, serve("xml").Code)
+}
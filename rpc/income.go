@@ -0,0 +1,231 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/ledger/execution"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+// maxIncomeReportBlocks is the max number of blocks covered by one income report
+const maxIncomeReportBlocks = 1000000
+
+// ------------------------------- GetValidatorIncomeReport -----------------------------------
+
+type GetValidatorIncomeReportArgs struct {
+	Address      string            `json:"address"`
+	StartHeight  common.JSONUint64 `json:"start_height"`
+	EndHeight    common.JSONUint64 `json:"end_height"`    // capped to the last finalized height, 0 for the last finalized height
+	PeriodLength common.JSONUint64 `json:"period_length"` // number of blocks per period, 0 for a single period
+}
+
+// ValidatorIncomePeriod is the income of the validator over the finalized blocks of a period, in
+// PTXWei. The commissions are included in the block rewards. Transaction fees are burned rather
+// than paid to the proposers, so the fees are those paid by the validator account.
+type ValidatorIncomePeriod struct {
+	StartHeight    common.JSONUint64 `json:"start_height"`
+	EndHeight      common.JSONUint64 `json:"end_height"`
+	ProposedBlocks common.JSONUint64 `json:"proposed_blocks"`
+	BlockRewards   *common.JSONBig   `json:"block_rewards"`
+	Commissions    *common.JSONBig   `json:"commissions"`
+	FeesPaid       *common.JSONBig   `json:"fees_paid"`
+}
+
+// ValidatorIncomeReport is the income of the validator per period
+type ValidatorIncomeReport struct {
+	ChainID     string                   `json:"chain_id"`
+	Address     common.Address           `json:"address"`
+	StartHeight common.JSONUint64        `json:"start_height"`
+	EndHeight   common.JSONUint64        `json:"end_height"`
+	Periods     []*ValidatorIncomePeriod `json:"periods"`
+	Total       *ValidatorIncomePeriod   `json:"total"`
+}
+
+// GetValidatorIncomeReportResult contains the report and the signature of the node over the JSON
+// encoding of the report, so that the report can be audited against the node that generated it
+type GetValidatorIncomeReportResult struct {
+	Report    *ValidatorIncomeReport `json:"report"`
+	Signer    common.Address         `json:"signer"`
+	Signature *crypto.Signature      `json:"signature"`
+}
+
+// GetValidatorIncomeReport computes the reward income of a validator per period over the finalized blocks
+func (t *PandoRPCService) GetValidatorIncomeReport(args *GetValidatorIncomeReportArgs, result *GetValidatorIncomeReportResult) (err error) {
+	if !common.IsHexAddress(args.Address) {
+		return fmt.Errorf("Invalid address: %v", args.Address)
+	}
+	address := common.HexToAddress(args.Address)
+
+	lastFinalized := t.consensus.GetLastFinalizedBlock()
+	if lastFinalized == nil {
+		return fmt.Errorf("No finalized block yet")
+	}
+	start := uint64(args.StartHeight)
+	end := uint64(args.EndHeight)
+	if end == 0 || end > lastFinalized.Height {
+		end = lastFinalized.Height
+	}
+	if start == 0 || start > end {
+		return fmt.Errorf("Invalid height range [%v, %v], the last finalized height is %v", start, end, lastFinalized.Height)
+	}
+	if end-start+1 > maxIncomeReportBlocks {
+		return fmt.Errorf("Height range too large, at most %v blocks per report", maxIncomeReportBlocks)
+	}
+	periodLength := uint64(args.PeriodLength)
+	if periodLength == 0 {
+		periodLength = end - start + 1
+	}
+
+	report := &ValidatorIncomeReport{
+		ChainID:     t.ledger.State().GetChainID(),
+		Address:     address,
+		StartHeight: common.JSONUint64(start),
+		EndHeight:   common.JSONUint64(end),
+		Periods:     []*ValidatorIncomePeriod{},
+		Total:       newValidatorIncomePeriod(start, end),
+	}
+	for periodStart := start; periodStart <= end; periodStart += periodLength {
+		periodEnd := periodStart + periodLength - 1
+		if periodEnd > end {
+			periodEnd = end
+		}
+		period := newValidatorIncomePeriod(periodStart, periodEnd)
+		for height := periodStart; height <= periodEnd; height++ {
+			block, err := t.resolveBlock(BlockSpecifier(fmt.Sprintf("%d", height)), BlockSpecifierFinalized)
+			if err != nil {
+				return err
+			}
+			if err := t.addBlockIncome(period, address, block); err != nil {
+				return err
+			}
+		}
+		report.Periods = append(report.Periods, period)
+		report.Total.add(period)
+	}
+
+	reportBytes, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	signer := t.consensus.Signer()
+	sig, err := signer.Sign(reportBytes)
+	if err != nil {
+		return fmt.Errorf("Failed to sign the report: %v", err)
+	}
+
+	result.Report = report
+	result.Signer = signer.PublicKey().Address()
+	result.Signature = sig
+	return nil
+}
+
+// addBlockIncome adds the income of the validator from the finalized block to the period
+func (t *PandoRPCService) addBlockIncome(period *ValidatorIncomePeriod, address common.Address, block *core.ExtendedBlock) error {
+	if block.Proposer == address {
+		period.ProposedBlocks++
+	}
+	for _, raw := range block.Txs {
+		tx, err := types.TxFromBytes(raw)
+		if err != nil {
+			return fmt.Errorf("Failed to parse a transaction of block %v: %v", block.Hash().Hex(), err)
+		}
+
+		if coinbaseTx, ok := tx.(*types.CoinbaseTx); ok {
+			reward := big.NewInt(0)
+			for _, output := range coinbaseTx.Outputs {
+				if output.Address == address {
+					reward.Add(reward, output.Coins.NoNil().PTXWei)
+				}
+			}
+			if reward.Sign() == 0 {
+				continue
+			}
+			(*big.Int)(period.BlockRewards).Add((*big.Int)(period.BlockRewards), reward)
+
+			commission, err := t.getCommission(address, block)
+			if err != nil {
+				return err
+			}
+			(*big.Int)(period.Commissions).Add((*big.Int)(period.Commissions), commission)
+			continue
+		}
+
+		senders, _ := types.GetTxAddresses(tx)
+		for _, sender := range senders {
+			if sender == address {
+				fee := t.chain.GetTxFee(tx, crypto.Keccak256Hash(raw))
+				(*big.Int)(period.FeesPaid).Add((*big.Int)(period.FeesPaid), fee.NoNil().PTXWei)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// getCommission returns the commission the validator earned in the coinbase transaction of the
+// block. The commission is not recorded separately, so the rewards are recalculated the same way
+// the coinbase transaction was validated.
+func (t *PandoRPCService) getCommission(address common.Address, block *core.ExtendedBlock) (*big.Int, error) {
+	if !features.IsEnabled(features.ValidatorCommission, block.Height) {
+		return big.NewInt(0), nil
+	}
+
+	db := t.ledger.State().DB()
+	parent, err := t.chain.FindBlock(block.Parent)
+	if err != nil {
+		return nil, err
+	}
+	view := state.NewReadOnlyStoreView(parent.Height, parent.StateHash, db)
+	if view == nil {
+		return nil, fmt.Errorf("The state of block %v does not exist, it might have been pruned", parent.Hash().Hex())
+	}
+	if params := view.GetValidatorParams(address); params == nil || params.CommissionRate == 0 {
+		return big.NewInt(0), nil
+	}
+
+	validatorSet := t.consensus.GetValidatorManager().GetNextValidatorSet(block.Parent)
+	var commissions map[common.Address]*big.Int
+	guardianVotes := block.GuardianVotes
+	if !features.IsEnabled(features.Pando2, block.Height) || guardianVotes == nil {
+		_, commissions = execution.CalculateRewardWithCommissions(nil, view, validatorSet, nil, nil)
+	} else {
+		voteBlock, err := t.chain.FindBlock(guardianVotes.Block)
+		if err != nil {
+			return nil, err
+		}
+		voteView := state.NewReadOnlyStoreView(voteBlock.Height, voteBlock.StateHash, db)
+		if voteView == nil {
+			return nil, fmt.Errorf("The state of block %v does not exist, it might have been pruned", voteBlock.Hash().Hex())
+		}
+		_, commissions = execution.CalculateRewardWithCommissions(nil, view, validatorSet, guardianVotes, voteView.GetGuardianCandidatePool())
+	}
+
+	if commission, ok := commissions[address]; ok {
+		return commission, nil
+	}
+	return big.NewInt(0), nil
+}
+
+func newValidatorIncomePeriod(start, end uint64) *ValidatorIncomePeriod {
+	return &ValidatorIncomePeriod{
+		StartHeight:  common.JSONUint64(start),
+		EndHeight:    common.JSONUint64(end),
+		BlockRewards: (*common.JSONBig)(big.NewInt(0)),
+		Commissions:  (*common.JSONBig)(big.NewInt(0)),
+		FeesPaid:     (*common.JSONBig)(big.NewInt(0)),
+	}
+}
+
+func (p *ValidatorIncomePeriod) add(other *ValidatorIncomePeriod) {
+	p.ProposedBlocks += other.ProposedBlocks
+	(*big.Int)(p.BlockRewards).Add((*big.Int)(p.BlockRewards), (*big.Int)(other.BlockRewards))
+	(*big.Int)(p.Commissions).Add((*big.Int)(p.Commissions), (*big.Int)(other.Commissions))
+	(*big.Int)(p.FeesPaid).Add((*big.Int)(p.FeesPaid), (*big.Int)(other.FeesPaid))
+}
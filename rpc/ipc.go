@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/netutil"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// DefaultIPCFileName is the file name of the unix domain socket in the config path
+const DefaultIPCFileName = "pando.ipc"
+
+// listenFDsStart is the first file descriptor passed by systemd with socket activation
+const listenFDsStart = 3
+
+// IPCPath returns the path of the unix domain socket the RPC is served on
+func IPCPath() string {
+	if ipcPath := viper.GetString(common.CfgRPCIPCPath); ipcPath != "" {
+		return ipcPath
+	}
+	return path.Join(viper.GetString(common.CfgConfigPath), DefaultIPCFileName)
+}
+
+// serveIPC serves the RPC over a unix domain socket, so that the local tools can reach the node
+// without a TCP port. The access is controlled with the file permissions of the socket. It
+// listens on the configured path unless a listener is passed by the service manager.
+func (t *PandoRPCServer) serveIPC(l net.Listener) {
+	if l == nil {
+		var err error
+		l, err = listenIPC(IPCPath(), viper.GetString(common.CfgRPCIPCMode))
+		if err != nil {
+			logger.WithFields(log.Fields{"error": err}).Fatal("Failed to create IPC listener")
+		}
+	}
+	logger.WithFields(log.Fields{"path": l.Addr().String()}).Info("RPC IPC server started")
+	defer l.Close()
+
+	ll := netutil.LimitListener(l, viper.GetInt(common.CfgRPCMaxConnections))
+	logger.Info(t.server.Serve(ll))
+}
+
+// listenIPC creates the unix domain socket with the given permissions. A socket left over by a
+// node which did not shut down cleanly is replaced, while a socket still served is not.
+func listenIPC(ipcPath string, mode string) (net.Listener, error) {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IPC socket mode %v: %v", mode, err)
+	}
+
+	if info, err := os.Lstat(ipcPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%v exists and is not a socket", ipcPath)
+		}
+		if conn, err := net.DialTimeout("unix", ipcPath, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%v is in use by another process", ipcPath)
+		}
+		if err := os.Remove(ipcPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(path.Dir(ipcPath), 0700); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", ipcPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(ipcPath, os.FileMode(perm)); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// activatedListeners returns the TCP and the unix domain socket listeners passed by systemd
+// with socket activation, nil if none is passed. See sd_listen_fds(3).
+func activatedListeners() (tcpListener net.Listener, ipcListener net.Listener) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	numFDs, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || numFDs <= 0 {
+		return nil, nil
+	}
+
+	for fd := listenFDsStart; fd < listenFDsStart+numFDs; fd++ {
+		file := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(file)
+		file.Close() // the listener holds a duplicate of the descriptor
+		if err != nil {
+			logger.Warnf("Ignoring the activated socket %v: %v", fd, err)
+			continue
+		}
+
+		switch l.Addr().Network() {
+		case "tcp", "tcp4", "tcp6":
+			if tcpListener == nil {
+				tcpListener = l
+				continue
+			}
+		case "unix":
+			if ipcListener == nil {
+				ipcListener = l
+				continue
+			}
+		}
+		logger.Warnf("Ignoring the activated socket %v on %v", fd, l.Addr())
+		l.Close()
+	}
+	return tcpListener, ipcListener
+}
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenIPC(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "ipc")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+	ipcPath := path.Join(dir, DefaultIPCFileName)
+
+	_, err = listenIPC(ipcPath, "rw")
+	assert.NotNil(err)
+
+	l, err := listenIPC(ipcPath, "0600")
+	require.Nil(err)
+	info, err := os.Stat(ipcPath)
+	require.Nil(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// A socket still served is not replaced
+	_, err = listenIPC(ipcPath, "0600")
+	assert.NotNil(err)
+
+	conn, err := net.Dial("unix", ipcPath)
+	require.Nil(err)
+	conn.Close()
+
+	// A socket left over by a node which did not shut down cleanly is replaced
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	_, err = os.Stat(ipcPath)
+	require.Nil(err)
+	l, err = listenIPC(ipcPath, "0660")
+	require.Nil(err)
+	info, err = os.Stat(ipcPath)
+	require.Nil(err)
+	assert.Equal(os.FileMode(0660), info.Mode().Perm())
+	l.Close()
+
+	// Other files are never removed
+	require.Nil(ioutil.WriteFile(ipcPath, []byte("data"), 0600))
+	_, err = listenIPC(ipcPath, "0600")
+	assert.NotNil(err)
+}
+
+func TestActivatedListenersNotActivated(t *testing.T) {
+	assert := assert.New(t)
+
+	os.Setenv("LISTEN_PID", "1")
+	os.Setenv("LISTEN_FDS", "2")
+	tcpListener, ipcListener := activatedListeners()
+	assert.Nil(tcpListener)
+	assert.Nil(ipcListener)
+	assert.Equal("", os.Getenv("LISTEN_FDS"))
+}
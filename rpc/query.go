@@ -1,22 +1,33 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/pandotoken/pando/blockchain"
 	"github.com/pandotoken/pando/crypto/bls"
 
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/hexutil"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/ledger/execution"
 	"github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
 	"github.com/pandotoken/pando/mempool"
+	"github.com/pandotoken/pando/p2p/messenger"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/snapshot"
 	"github.com/pandotoken/pando/version"
 )
 
@@ -41,9 +52,10 @@ func (t *PandoRPCService) GetVersion(args *GetVersionArgs, result *GetVersionRes
 // ------------------------------- GetAccount -----------------------------------
 
 type GetAccountArgs struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
-	Preview bool   `json:"preview"` // preview the account balance from the ScreenedView
+	Name    string         `json:"name"`
+	Address string         `json:"address"`
+	Preview bool           `json:"preview"` // preview the account balance from the ScreenedView
+	Block   BlockSpecifier `json:"block"`
 }
 
 type GetAccountResult struct {
@@ -58,12 +70,11 @@ func (t *PandoRPCService) GetAccount(args *GetAccountArgs, result *GetAccountRes
 	address := common.HexToAddress(args.Address)
 	result.Address = args.Address
 
-	var ledgerState *state.StoreView
+	defaultSpec := BlockSpecifierFinalized
 	if args.Preview {
-		ledgerState, err = t.ledger.GetScreenedSnapshot()
-	} else {
-		ledgerState, err = t.ledger.GetFinalizedSnapshot()
+		defaultSpec = BlockSpecifierPending
 	}
+	ledgerState, err := t.resolveStoreView(args.Block, defaultSpec)
 	if err != nil {
 		return err
 	}
@@ -81,7 +92,8 @@ func (t *PandoRPCService) GetAccount(args *GetAccountArgs, result *GetAccountRes
 // ------------------------------- GetSplitRule -----------------------------------
 
 type GetSplitRuleArgs struct {
-	ResourceID string `json:"resource_id"`
+	ResourceID string         `json:"resource_id"`
+	Block      BlockSpecifier `json:"block"`
 }
 
 type GetSplitRuleResult struct {
@@ -93,7 +105,7 @@ func (t *PandoRPCService) GetSplitRule(args *GetSplitRuleArgs, result *GetSplitR
 		return errors.New("ResourceID must be specified")
 	}
 	resourceID := args.ResourceID
-	ledgerState, err := t.ledger.GetDeliveredSnapshot()
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierLatest)
 	if err != nil {
 		return err
 	}
@@ -101,6 +113,263 @@ func (t *PandoRPCService) GetSplitRule(args *GetSplitRuleArgs, result *GetSplitR
 	return nil
 }
 
+// ------------------------------- GetSplitRules -----------------------------------
+
+type GetSplitRulesArgs struct {
+	ResourceID string         `json:"resource_id"` // optional
+	Initiator  string         `json:"initiator"`   // optional
+	Block      BlockSpecifier `json:"block"`
+}
+
+type SplitRuleStatus struct {
+	SplitRule         *types.SplitRule  `json:"split_rule"`
+	RemainingDuration common.JSONUint64 `json:"remaining_duration"` // number of blocks the rule still applies for
+}
+
+type GetSplitRulesResult struct {
+	Height     common.JSONUint64  `json:"height"`
+	SplitRules []*SplitRuleStatus `json:"split_rules"`
+}
+
+// GetSplitRules returns the active split rules, optionally selected by resource ID and initiator,
+// so that the content platforms can monitor their revenue splits and renew them before they expire.
+func (t *PandoRPCService) GetSplitRules(args *GetSplitRulesArgs, result *GetSplitRulesResult) (err error) {
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierLatest)
+	if err != nil {
+		return err
+	}
+	height := ledgerState.Height()
+
+	var splitRules []*types.SplitRule
+	if args.ResourceID != "" {
+		if splitRule := ledgerState.GetSplitRule(args.ResourceID); splitRule != nil {
+			splitRules = append(splitRules, splitRule)
+		}
+	} else if args.Initiator != "" {
+		splitRules = ledgerState.GetSplitRulesByInitiator(common.HexToAddress(args.Initiator))
+	} else {
+		splitRules = ledgerState.GetSplitRules()
+	}
+
+	result.Height = common.JSONUint64(height)
+	result.SplitRules = []*SplitRuleStatus{}
+	for _, splitRule := range splitRules {
+		if splitRule.IsExpired(height) {
+			continue // expired rules are deleted lazily
+		}
+		if args.Initiator != "" && splitRule.InitiatorAddress != common.HexToAddress(args.Initiator) {
+			continue
+		}
+		result.SplitRules = append(result.SplitRules, &SplitRuleStatus{
+			SplitRule:         splitRule,
+			RemainingDuration: common.JSONUint64(splitRule.RemainingDuration(height)),
+		})
+	}
+	return nil
+}
+
+// ------------------------------- GetReserveFund -----------------------------------
+
+// Status of the collateral of a reserve fund
+const (
+	CollateralLocked   = "locked"
+	CollateralReleased = "released"
+	CollateralSlashed  = "slashed"
+)
+
+type GetReserveFundArgs struct {
+	Address         string            `json:"address"`
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+	Block           BlockSpecifier    `json:"block"`
+}
+
+type ReserveFundUsage struct {
+	NumPayments common.JSONUint64 `json:"num_payments"`
+	TotalPaid   types.Coins       `json:"total_paid"`
+	LastTxHash  common.Hash       `json:"last_tx_hash"`
+	LastHeight  common.JSONUint64 `json:"last_height"`
+}
+
+type GetReserveFundResult struct {
+	Address          string             `json:"address"`
+	ReserveSequence  common.JSONUint64  `json:"reserve_sequence"`
+	Height           common.JSONUint64  `json:"height"`
+	Collateral       types.Coins        `json:"collateral"`
+	CollateralStatus string             `json:"collateral_status"` // locked, released or slashed
+	InitialFund      types.Coins        `json:"initial_fund"`
+	UsedFund         types.Coins        `json:"used_fund"`
+	RemainingFund    types.Coins        `json:"remaining_fund"`
+	ResourceIDs      []string           `json:"resource_ids"`
+	EndBlockHeight   common.JSONUint64  `json:"end_block_height"`
+	Expired          bool               `json:"expired"` // no more payments are accepted
+	Slash            *types.SlashRecord `json:"slash"`
+	Usage            *ReserveFundUsage  `json:"usage"` // settled payments of the finalized blocks, if indexed
+}
+
+// GetReserveFund returns the remaining balance and the collateral status of a reserve fund, so that
+// the payer can monitor the health of its payment channels. The released and slashed funds are gone
+// from the account; they are only reported with the usage recorded while storage.reserveFundIndex
+// was enabled, or with their slash record.
+func (t *PandoRPCService) GetReserveFund(args *GetReserveFundArgs, result *GetReserveFundResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address := common.HexToAddress(args.Address)
+	reserveSequence := uint64(args.ReserveSequence)
+	result.Address = args.Address
+	result.ReserveSequence = args.ReserveSequence
+
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	height := ledgerState.Height()
+	result.Height = common.JSONUint64(height)
+
+	if viper.GetBool(common.CfgStorageReserveFundIndex) {
+		if usage, ok := t.chain.GetReserveFundUsage(address, reserveSequence); ok {
+			result.Usage = &ReserveFundUsage{
+				NumPayments: common.JSONUint64(usage.NumPayments),
+				TotalPaid:   usage.TotalPaid,
+				LastTxHash:  usage.LastTxHash,
+				LastHeight:  common.JSONUint64(usage.LastHeight),
+			}
+		}
+	}
+	result.Slash = ledgerState.GetSlashRecord(address, reserveSequence)
+
+	var reservedFund *types.ReservedFund
+	if account := ledgerState.GetAccount(address); account != nil {
+		account.UpdateToHeight(height)
+		reservedFund = account.GetReservedFund(reserveSequence)
+	}
+	if reservedFund == nil {
+		switch {
+		case result.Slash != nil:
+			result.CollateralStatus = CollateralSlashed
+		case result.Usage != nil:
+			result.CollateralStatus = CollateralReleased
+		default:
+			return fmt.Errorf("Reserve fund %v of %v is not found, it may have been released", reserveSequence, address.Hex())
+		}
+		result.Expired = true
+		return nil
+	}
+
+	result.Collateral = reservedFund.Collateral
+	result.CollateralStatus = CollateralLocked
+	result.InitialFund = reservedFund.InitialFund
+	result.UsedFund = reservedFund.UsedFund
+	result.RemainingFund = reservedFund.RemainingFund()
+	result.ResourceIDs = reservedFund.ResourceIDs
+	result.EndBlockHeight = common.JSONUint64(reservedFund.EndBlockHeight)
+	result.Expired = reservedFund.EndBlockHeight < height
+	return nil
+}
+
+// ------------------------------- GetAccountBundle -----------------------------------
+
+type GetAccountBundleArgs struct {
+	Address string         `json:"address"`
+	Block   BlockSpecifier `json:"block"`
+}
+
+type AccountStake struct {
+	Holder       common.Address    `json:"holder"`
+	Purpose      uint8             `json:"purpose"`
+	Amount       *common.JSONBig   `json:"amount"`
+	Withdrawn    bool              `json:"withdrawn"`
+	ReturnHeight common.JSONUint64 `json:"return_height"`
+}
+
+type AccountReserve struct {
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+	Collateral      types.Coins       `json:"collateral"`
+	InitialFund     types.Coins       `json:"initial_fund"`
+	UsedFund        types.Coins       `json:"used_fund"`
+	RemainingFund   types.Coins       `json:"remaining_fund"`
+	ResourceIDs     []string          `json:"resource_ids"`
+	EndBlockHeight  common.JSONUint64 `json:"end_block_height"`
+	Expired         bool              `json:"expired"`
+}
+
+type GetAccountBundleResult struct {
+	Address     string             `json:"address"`
+	BlockHeight common.JSONUint64  `json:"block_height"`
+	StateRoot   common.Hash        `json:"state_root"`
+	Account     *types.Account     `json:"account"`     // null if the account does not exist
+	Stakes      []AccountStake     `json:"stakes"`      // the validator and guardian stakes deposited by the address
+	Reserves    []AccountReserve   `json:"reserves"`    // the reserve funds of the account
+	SplitRules  []*types.SplitRule `json:"split_rules"` // the split rules initiated by the address
+}
+
+// GetAccountBundle returns the balance, sequence, stakes, reserve funds and split rules of an address, all
+// read from the same state, so that they are consistent with each other even when a block is committed
+// in between the separate queries of GetAccount, GetVcpByHeight, GetReserveFund and GetSplitRule.
+func (t *PandoRPCService) GetAccountBundle(args *GetAccountBundleArgs, result *GetAccountBundleResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address := common.HexToAddress(args.Address)
+	result.Address = args.Address
+
+	// The store view is a copy of the state at a single height, it is not updated by the block commits
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	height := ledgerState.Height()
+	result.BlockHeight = common.JSONUint64(height)
+	result.StateRoot = ledgerState.Hash()
+
+	result.Reserves = []AccountReserve{}
+	if account := ledgerState.GetAccount(address); account != nil {
+		account.UpdateToHeight(height)
+		result.Account = account
+		for _, reservedFund := range account.ReservedFunds {
+			result.Reserves = append(result.Reserves, AccountReserve{
+				ReserveSequence: common.JSONUint64(reservedFund.ReserveSequence),
+				Collateral:      reservedFund.Collateral,
+				InitialFund:     reservedFund.InitialFund,
+				UsedFund:        reservedFund.UsedFund,
+				RemainingFund:   reservedFund.RemainingFund(),
+				ResourceIDs:     reservedFund.ResourceIDs,
+				EndBlockHeight:  common.JSONUint64(reservedFund.EndBlockHeight),
+				Expired:         reservedFund.EndBlockHeight < height,
+			})
+		}
+	}
+
+	result.Stakes = []AccountStake{}
+	addStakes := func(holder *core.StakeHolder, purpose uint8) {
+		for _, stake := range holder.Stakes {
+			if stake.Source != address {
+				continue
+			}
+			result.Stakes = append(result.Stakes, AccountStake{
+				Holder:       holder.Holder,
+				Purpose:      purpose,
+				Amount:       (*common.JSONBig)(stake.Amount),
+				Withdrawn:    stake.Withdrawn,
+				ReturnHeight: common.JSONUint64(stake.ReturnHeight),
+			})
+		}
+	}
+	if vcp := ledgerState.GetValidatorCandidatePool(); vcp != nil {
+		for _, candidate := range vcp.SortedCandidates {
+			addStakes(candidate, core.StakeForValidator)
+		}
+	}
+	if gcp := ledgerState.GetGuardianCandidatePool(); gcp != nil {
+		for _, g := range gcp.SortedGuardians {
+			addStakes(g.StakeHolder, core.StakeForGuardian)
+		}
+	}
+
+	result.SplitRules = ledgerState.GetSplitRulesByInitiator(address)
+	return nil
+}
+
 // ------------------------------ GetTransaction -----------------------------------
 
 type GetTransactionArgs struct {
@@ -172,503 +441,2438 @@ func (t *PandoRPCService) GetTransaction(args *GetTransactionArgs, result *GetTr
 	return nil
 }
 
-// ------------------------------ GetPendingTransactions -----------------------------------
+// ------------------------------ GetTransactionHistory -----------------------------------
 
-type GetPendingTransactionsArgs struct {
+const (
+	defaultTransactionHistoryLimit = 20
+	maxTransactionHistoryLimit     = 100
+)
+
+type GetTransactionHistoryArgs struct {
+	Address   string            `json:"address"`
+	Offset    common.JSONUint64 `json:"offset"`
+	Limit     common.JSONUint64 `json:"limit"`
+	Ascending bool              `json:"ascending"` // the oldest transactions first, instead of the newest
 }
 
-type GetPendingTransactionsResult struct {
-	TxHashes []string `json:"tx_hashes"`
+type GetTransactionHistoryResult struct {
+	Address      string                `json:"address"`
+	Total        common.JSONUint64     `json:"total"`
+	Transactions []*AccountTransaction `json:"transactions"`
 }
 
-func (t *PandoRPCService) GetPendingTransactions(args *GetPendingTransactionsArgs, result *GetPendingTransactionsResult) (err error) {
-	pendingTxHashes := t.mempool.GetCandidateTransactionHashes()
-	result.TxHashes = pendingTxHashes
+type AccountTransaction struct {
+	TxHash      common.Hash       `json:"hash"`
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	Index       common.JSONUint64 `json:"index"`
+	Sent        bool              `json:"sent"`
+	Received    bool              `json:"received"`
+	Type        byte              `json:"type"`
+	Tx          types.Tx          `json:"transaction"`
+}
+
+// GetTransactionHistory returns the finalized transactions sent or received by the address. It requires
+// the node to run with storage.accountHistoryIndex enabled, and only covers the blocks finalized since.
+func (t *PandoRPCService) GetTransactionHistory(args *GetTransactionHistoryArgs, result *GetTransactionHistoryResult) (err error) {
+	if !viper.GetBool(common.CfgStorageAccountHistoryIndex) {
+		return errors.New("The account history index is not enabled on this node, set " + common.CfgStorageAccountHistoryIndex + " to enable it")
+	}
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	limit := uint64(args.Limit)
+	if limit == 0 {
+		limit = defaultTransactionHistoryLimit
+	}
+	if limit > maxTransactionHistoryLimit {
+		return fmt.Errorf("The limit cannot exceed %v", maxTransactionHistoryLimit)
+	}
+
+	address := common.HexToAddress(args.Address)
+	result.Address = args.Address
+	result.Total = common.JSONUint64(t.chain.GetAccountTxCount(address))
+	result.Transactions = []*AccountTransaction{}
+
+	entries := t.chain.GetAccountTxs(address, uint64(args.Offset), limit, !args.Ascending)
+	for _, entry := range entries {
+		block, err := t.chain.FindBlock(entry.BlockHash)
+		if err != nil || entry.Index >= uint64(len(block.Txs)) {
+			return fmt.Errorf("Failed to find transaction %v in block %v", entry.TxHash.Hex(), entry.BlockHash.Hex())
+		}
+		tx, err := types.TxFromBytes(block.Txs[entry.Index])
+		if err != nil {
+			return err
+		}
+		result.Transactions = append(result.Transactions, &AccountTransaction{
+			TxHash:      entry.TxHash,
+			BlockHash:   entry.BlockHash,
+			BlockHeight: common.JSONUint64(entry.BlockHeight),
+			Index:       common.JSONUint64(entry.Index),
+			Sent:        entry.Sent,
+			Received:    entry.Received,
+			Type:        getTxType(tx),
+			Tx:          tx,
+		})
+	}
+
 	return nil
 }
 
-// ------------------------------ GetBlock -----------------------------------
+// ------------------------------ GetAccountActivity -----------------------------------
 
-type GetBlockArgs struct {
-	Hash common.Hash `json:"hash"`
+const defaultAccountActivityCounterparties = 10
+
+type GetAccountActivityArgs struct {
+	Address string            `json:"address"`
+	Limit   common.JSONUint64 `json:"limit"` // the number of top counterparties to return
 }
 
-type Tx struct {
-	types.Tx `json:"raw"`
-	Type     byte                       `json:"type"`
-	Hash     common.Hash                `json:"hash"`
-	Receipt  *blockchain.TxReceiptEntry `json:"receipt"`
+type GetAccountActivityResult struct {
+	Address           string                 `json:"address"`
+	FirstSeenHeight   common.JSONUint64      `json:"first_seen_height"`
+	FirstSeenTime     common.JSONUint64      `json:"first_seen_time"`
+	LastSeenHeight    common.JSONUint64      `json:"last_seen_height"`
+	LastSeenTime      common.JSONUint64      `json:"last_seen_time"`
+	TxCount           common.JSONUint64      `json:"tx_count"`
+	SentCount         common.JSONUint64      `json:"sent_count"`
+	ReceivedCount     common.JSONUint64      `json:"received_count"`
+	TopCounterparties []*AccountCounterparty `json:"top_counterparties"`
 }
 
-type GetBlockResult struct {
-	*GetBlockResultInner
+type AccountCounterparty struct {
+	Address         common.Address    `json:"address"`
+	TxCount         common.JSONUint64 `json:"tx_count"`
+	MaxOverestimate common.JSONUint64 `json:"max_overestimate"` // the count is exact if 0
+	LastSeenHeight  common.JSONUint64 `json:"last_seen_height"`
 }
 
-type GetBlocksResult []*GetBlockResultInner
+// GetAccountActivity returns the activity summary of the address: its first and last finalized transactions,
+// its transaction counts and its most frequent counterparties. It requires the node to run with
+// storage.accountActivityIndex enabled, and only covers the blocks finalized since.
+func (t *PandoRPCService) GetAccountActivity(args *GetAccountActivityArgs, result *GetAccountActivityResult) (err error) {
+	if !viper.GetBool(common.CfgStorageAccountActivityIndex) {
+		return errors.New("The account activity index is not enabled on this node, set " + common.CfgStorageAccountActivityIndex + " to enable it")
+	}
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	limit := uint64(args.Limit)
+	if limit == 0 {
+		limit = defaultAccountActivityCounterparties
+	}
+	if limit > blockchain.MaxTrackedCounterparties {
+		return fmt.Errorf("The limit cannot exceed %v", blockchain.MaxTrackedCounterparties)
+	}
 
-type GetBlockResultInner struct {
-	ChainID       string                 `json:"chain_id"`
-	Epoch         common.JSONUint64      `json:"epoch"`
-	Height        common.JSONUint64      `json:"height"`
-	Parent        common.Hash            `json:"parent"`
-	TxHash        common.Hash            `json:"transactions_hash"`
-	StateHash     common.Hash            `json:"state_hash"`
-	Timestamp     *common.JSONBig        `json:"timestamp"`
-	Proposer      common.Address         `json:"proposer"`
-	HCC           core.CommitCertificate `json:"hcc"`
-	GuardianVotes *core.AggregatedVotes  `json:"guardian_votes"`
+	address := common.HexToAddress(args.Address)
+	result.Address = args.Address
+	result.TopCounterparties = []*AccountCounterparty{}
+	activity, ok := t.chain.GetAccountActivity(address)
+	if !ok {
+		return nil
+	}
 
-	Children []common.Hash    `json:"children"`
-	Status   core.BlockStatus `json:"status"`
+	result.FirstSeenHeight = common.JSONUint64(activity.FirstHeight)
+	result.FirstSeenTime = common.JSONUint64(activity.FirstTimestamp)
+	result.LastSeenHeight = common.JSONUint64(activity.LastHeight)
+	result.LastSeenTime = common.JSONUint64(activity.LastTimestamp)
+	result.TxCount = common.JSONUint64(activity.TxCount)
+	result.SentCount = common.JSONUint64(activity.SentCount)
+	result.ReceivedCount = common.JSONUint64(activity.ReceivedCount)
+	for _, cp := range activity.TopCounterparties(int(limit)) {
+		result.TopCounterparties = append(result.TopCounterparties, &AccountCounterparty{
+			Address:         cp.Address,
+			TxCount:         common.JSONUint64(cp.TxCount),
+			MaxOverestimate: common.JSONUint64(cp.Error),
+			LastSeenHeight:  common.JSONUint64(cp.LastHeight),
+		})
+	}
+	return nil
+}
 
-	Hash common.Hash `json:"hash"`
-	Txs  []Tx        `json:"transactions"`
+// ------------------------------ GetTokenBalances -----------------------------------
+
+type GetTokenBalancesArgs struct {
+	Address string `json:"address"`
+	Token   string `json:"token"` // when specified, only the balance of this token is returned
 }
 
-type TxType byte
+type TokenBalanceInfo struct {
+	Token      common.Address    `json:"token"`
+	Balance    *common.JSONBig   `json:"balance"`
+	LastHeight common.JSONUint64 `json:"last_height"` // height of the block of the last transfer
+}
 
-const (
-	TxTypeCoinbase = byte(iota)
-	TxTypeSlash
-	TxTypeSend
-	TxTypeRametronStake
-	TxTypeReserveFund
-	TxTypeReleaseFund
-	TxTypeServicePayment
-	TxTypeSplitRule
-	TxTypeSmartContract
-	TxTypeDepositStake
-	TxTypeWithdrawStake
-	TxTypeDepositStakeTxV2
-)
+type GetTokenBalancesResult struct {
+	Address  string              `json:"address"`
+	Height   common.JSONUint64   `json:"height"` // height of the last block indexed
+	Balances []*TokenBalanceInfo `json:"balances"`
+}
 
-func (t *PandoRPCService) GetBlock(args *GetBlockArgs, result *GetBlockResult) (err error) {
-	if args.Hash.IsEmpty() {
-		return errors.New("Block hash must be specified")
+// GetTokenBalances returns the balances of the ERC-20 compatible tokens held by the address, computed from
+// the Transfer events of the token contracts. It requires the node to run with storage.tokenIndex enabled,
+// and only covers the blocks finalized since.
+func (t *PandoRPCService) GetTokenBalances(args *GetTokenBalancesArgs, result *GetTokenBalancesResult) (err error) {
+	if !viper.GetBool(common.CfgStorageTokenIndex) {
+		return errors.New("The token index is not enabled on this node, set " + common.CfgStorageTokenIndex + " to enable it")
+	}
+	if args.Address == "" {
+		return errors.New("Address must be specified")
 	}
 
-	block, err := t.chain.FindBlock(args.Hash)
-	if err != nil {
-		return err
+	address := common.HexToAddress(args.Address)
+	result.Address = args.Address
+	height, _ := t.chain.GetTokenIndexHeight()
+	result.Height = common.JSONUint64(height)
+	result.Balances = []*TokenBalanceInfo{}
+
+	var balances []*blockchain.TokenBalance
+	if args.Token != "" {
+		if balance, ok := t.chain.GetTokenBalance(address, common.HexToAddress(args.Token)); ok {
+			balances = append(balances, balance)
+		}
+	} else {
+		balances = t.chain.GetTokenBalances(address)
+	}
+	for _, balance := range balances {
+		result.Balances = append(result.Balances, &TokenBalanceInfo{
+			Token:      balance.Token,
+			Balance:    (*common.JSONBig)(balance.Balance),
+			LastHeight: common.JSONUint64(balance.LastHeight),
+		})
 	}
+	return nil
+}
 
-	result.GetBlockResultInner = &GetBlockResultInner{}
-	result.ChainID = block.ChainID
-	result.Epoch = common.JSONUint64(block.Epoch)
-	result.Height = common.JSONUint64(block.Height)
-	result.Parent = block.Parent
-	result.TxHash = block.TxHash
-	result.StateHash = block.StateHash
-	result.Timestamp = (*common.JSONBig)(block.Timestamp)
-	result.Proposer = block.Proposer
-	result.Children = block.Children
-	result.Status = block.Status
-	result.HCC = block.HCC
-	result.GuardianVotes = block.GuardianVotes
+// ------------------------------ GetTokenTransfers -----------------------------------
 
-	result.Hash = block.Hash()
+type GetTokenTransfersArgs struct {
+	Address   string            `json:"address"`
+	Offset    common.JSONUint64 `json:"offset"`
+	Limit     common.JSONUint64 `json:"limit"`
+	Ascending bool              `json:"ascending"` // the oldest transfers first, instead of the newest
+}
 
-	// Parse and fulfill Txs.
-	var tx types.Tx
-	for _, txBytes := range block.Txs {
-		tx, err = types.TxFromBytes(txBytes)
-		if err != nil {
-			return
-		}
-		hash := crypto.Keccak256Hash(txBytes)
+type TokenTransferInfo struct {
+	Token       common.Address    `json:"token"`
+	From        common.Address    `json:"from"`
+	To          common.Address    `json:"to"`
+	Value       *common.JSONBig   `json:"value"`
+	TxHash      common.Hash       `json:"hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	Index       common.JSONUint64 `json:"index"`
+	LogIndex    common.JSONUint64 `json:"log_index"`
+}
 
-		tp := getTxType(tx)
-		txw := Tx{
-			Tx:   tx,
-			Hash: hash,
-			Type: tp,
-		}
+type GetTokenTransfersResult struct {
+	Address   string               `json:"address"`
+	Total     common.JSONUint64    `json:"total"`
+	Transfers []*TokenTransferInfo `json:"transfers"`
+}
 
-		receipt, found := t.chain.FindTxReceiptByHash(hash)
-		if found {
-			txw.Receipt = receipt
-		}
+// GetTokenTransfers returns the transfers of the ERC-20 compatible tokens sent or received by the address.
+// It requires the node to run with storage.tokenIndex enabled, and only covers the blocks finalized since.
+func (t *PandoRPCService) GetTokenTransfers(args *GetTokenTransfersArgs, result *GetTokenTransfersResult) (err error) {
+	if !viper.GetBool(common.CfgStorageTokenIndex) {
+		return errors.New("The token index is not enabled on this node, set " + common.CfgStorageTokenIndex + " to enable it")
+	}
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	limit := uint64(args.Limit)
+	if limit == 0 {
+		limit = defaultTransactionHistoryLimit
+	}
+	if limit > maxTransactionHistoryLimit {
+		return fmt.Errorf("The limit cannot exceed %v", maxTransactionHistoryLimit)
+	}
 
-		result.Txs = append(result.Txs, txw)
+	address := common.HexToAddress(args.Address)
+	result.Address = args.Address
+	result.Total = common.JSONUint64(t.chain.GetTokenTransferCount(address))
+	result.Transfers = []*TokenTransferInfo{}
+	for _, transfer := range t.chain.GetTokenTransfers(address, uint64(args.Offset), limit, !args.Ascending) {
+		result.Transfers = append(result.Transfers, &TokenTransferInfo{
+			Token:       transfer.Token,
+			From:        transfer.From,
+			To:          transfer.To,
+			Value:       (*common.JSONBig)(transfer.Value),
+			TxHash:      transfer.TxHash,
+			BlockHeight: common.JSONUint64(transfer.BlockHeight),
+			Index:       common.JSONUint64(transfer.Index),
+			LogIndex:    common.JSONUint64(transfer.LogIndex),
+		})
 	}
-	return
+	return nil
 }
 
-// ------------------------------ GetBlockByHeight -----------------------------------
+// ------------------------------ GetNFTsByOwner -----------------------------------
 
-type GetBlockByHeightArgs struct {
-	Height common.JSONUint64 `json:"height"`
+var (
+	// nftTokenURISelector is the selector of the ERC-721 tokenURI(uint256) method
+	nftTokenURISelector = crypto.Keccak256([]byte("tokenURI(uint256)"))[:4]
+
+	// nftURISelector is the selector of the ERC-1155 uri(uint256) method
+	nftURISelector = crypto.Keccak256([]byte("uri(uint256)"))[:4]
+)
+
+type GetNFTsByOwnerArgs struct {
+	Address  string            `json:"address"`
+	Contract string            `json:"contract"` // when specified, only the tokens of this contract are returned
+	Offset   common.JSONUint64 `json:"offset"`
+	Limit    common.JSONUint64 `json:"limit"`
 }
 
-func (t *PandoRPCService) GetBlockByHeight(args *GetBlockByHeightArgs, result *GetBlockResult) (err error) {
-	if args.Height == 0 {
-		return errors.New("Block height must be specified")
-	}
+type NFTInfo struct {
+	Contract   common.Address    `json:"contract"`
+	TokenID    *common.JSONBig   `json:"token_id"`
+	Standard   string            `json:"standard"`
+	Amount     *common.JSONBig   `json:"amount"`
+	URI        string            `json:"uri"`
+	LastHeight common.JSONUint64 `json:"last_height"` // height of the block of the last transfer
+}
 
-	blocks := t.chain.FindBlocksByHeight(uint64(args.Height))
+type GetNFTsByOwnerResult struct {
+	Address string            `json:"address"`
+	Height  common.JSONUint64 `json:"height"` // height of the last block indexed
+	Total   common.JSONUint64 `json:"total"`
+	NFTs    []*NFTInfo        `json:"nfts"`
+}
 
-	var block *core.ExtendedBlock
-	for _, b := range blocks {
-		if b.Status.IsFinalized() {
-			block = b
-			break
-		}
+// GetNFTsByOwner returns the ERC-721 and ERC-1155 tokens currently owned by the address, with their URIs.
+// It requires the node to run with storage.nftIndex enabled, and only covers the blocks finalized since.
+func (t *PandoRPCService) GetNFTsByOwner(args *GetNFTsByOwnerArgs, result *GetNFTsByOwnerResult) (err error) {
+	if !viper.GetBool(common.CfgStorageNFTIndex) {
+		return errors.New("The NFT index is not enabled on this node, set " + common.CfgStorageNFTIndex + " to enable it")
 	}
-
-	if block == nil {
-		return
+	if args.Address == "" {
+		return errors.New("Address must be specified")
 	}
+	limit, err := nftQueryLimit(args.Limit)
+	if err != nil {
+		return err
+	}
+
+	owner := common.HexToAddress(args.Address)
+	var contract common.Address
+	if args.Contract != "" {
+		contract = common.HexToAddress(args.Contract)
+	}
+	result.Address = args.Address
+	height, _ := t.chain.GetNFTIndexHeight()
+	result.Height = common.JSONUint64(height)
+	total := 0
+	for _, ref := range t.chain.GetNFTsOwned(owner) {
+		if contract == (common.Address{}) || ref.Contract == contract {
+			total++
+		}
+	}
+	result.Total = common.JSONUint64(total)
+	result.NFTs = []*NFTInfo{}
+	for _, holding := range t.chain.GetNFTHoldings(owner, contract, uint64(args.Offset), limit) {
+		result.NFTs = append(result.NFTs, &NFTInfo{
+			Contract:   holding.Contract,
+			TokenID:    (*common.JSONBig)(holding.TokenID),
+			Standard:   holding.Standard,
+			Amount:     (*common.JSONBig)(holding.Amount),
+			URI:        t.nftURI(holding.Contract, holding.TokenID, holding.Standard),
+			LastHeight: common.JSONUint64(holding.LastHeight),
+		})
+	}
+	return nil
+}
+
+func nftQueryLimit(requested common.JSONUint64) (uint64, error) {
+	limit := uint64(requested)
+	if limit == 0 {
+		limit = defaultTransactionHistoryLimit
+	}
+	if limit > maxTransactionHistoryLimit {
+		return 0, fmt.Errorf("The limit cannot exceed %v", maxTransactionHistoryLimit)
+	}
+	return limit, nil
+}
+
+// nftURI returns the URI of the token, empty if it is unknown. The URI set by the last ERC-1155 URI
+// event takes precedence, otherwise it is read from the contract with a call on the finalized state.
+// The ERC-1155 URIs are returned as is, i.e. the clients substitute the {id} placeholder.
+func (t *PandoRPCService) nftURI(contract common.Address, tokenID *big.Int, standard string) string {
+	if uri, ok := t.chain.GetNFTURI(contract, tokenID); ok {
+		return uri
+	}
+	parentBlock, view, err := t.ethCallContext(BlockSpecifierFinalized)
+	if err != nil {
+		return ""
+	}
+	selector := nftTokenURISelector
+	if standard == blockchain.NFTStandardERC1155 {
+		selector = nftURISelector
+	}
+	tx := &types.SmartContractTx{
+		From:     types.TxInput{Coins: types.NewCoins(0, 0)},
+		To:       types.TxOutput{Address: contract},
+		GasLimit: types.MaximumTxGasLimit,
+		GasPrice: new(big.Int).SetUint64(types.MinimumGasPrice),
+		Data:     append(common.CopyBytes(selector), common.LeftPadBytes(tokenID.Bytes(), 32)...),
+	}
+	vmRet, _, _, vmErr := t.callCache.execute(parentBlock, tx, view)
+	if vmErr != nil {
+		return ""
+	}
+	uri, _ := types.UnpackABIString(vmRet)
+	return uri
+}
+
+// ------------------------------ GetNFTHistory -----------------------------------
+
+type GetNFTHistoryArgs struct {
+	Contract  string            `json:"contract"`
+	TokenID   *common.JSONBig   `json:"token_id"`
+	Offset    common.JSONUint64 `json:"offset"`
+	Limit     common.JSONUint64 `json:"limit"`
+	Ascending bool              `json:"ascending"` // the oldest transfers first, instead of the newest
+}
+
+type NFTTransferInfo struct {
+	Operator    common.Address    `json:"operator"`
+	From        common.Address    `json:"from"`
+	To          common.Address    `json:"to"`
+	Amount      *common.JSONBig   `json:"amount"`
+	TxHash      common.Hash       `json:"hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	Index       common.JSONUint64 `json:"index"`
+	LogIndex    common.JSONUint64 `json:"log_index"`
+}
+
+type GetNFTHistoryResult struct {
+	Contract  common.Address     `json:"contract"`
+	TokenID   *common.JSONBig    `json:"token_id"`
+	Standard  string             `json:"standard"`
+	URI       string             `json:"uri"`
+	Total     common.JSONUint64  `json:"total"`
+	Transfers []*NFTTransferInfo `json:"transfers"`
+}
+
+// GetNFTHistory returns the transfers of the ERC-721 or ERC-1155 token, i.e. its mint, its changes of
+// owners and its burn. It requires the node to run with storage.nftIndex enabled, and only covers the
+// blocks finalized since.
+func (t *PandoRPCService) GetNFTHistory(args *GetNFTHistoryArgs, result *GetNFTHistoryResult) (err error) {
+	if !viper.GetBool(common.CfgStorageNFTIndex) {
+		return errors.New("The NFT index is not enabled on this node, set " + common.CfgStorageNFTIndex + " to enable it")
+	}
+	if args.Contract == "" || args.TokenID == nil {
+		return errors.New("Contract and token_id must be specified")
+	}
+	limit, err := nftQueryLimit(args.Limit)
+	if err != nil {
+		return err
+	}
+
+	contract := common.HexToAddress(args.Contract)
+	tokenID := (*big.Int)(args.TokenID)
+	result.Contract = contract
+	result.TokenID = args.TokenID
+	result.Total = common.JSONUint64(t.chain.GetNFTTransferCount(contract, tokenID))
+	result.Transfers = []*NFTTransferInfo{}
+	if result.Total == 0 {
+		return nil
+	}
+
+	// The standard is the same for all the transfers of the token
+	if first := t.chain.GetNFTTransfers(contract, tokenID, 0, 1, false); len(first) > 0 {
+		result.Standard = first[0].Standard
+	}
+	result.URI = t.nftURI(contract, tokenID, result.Standard)
+	for _, transfer := range t.chain.GetNFTTransfers(contract, tokenID, uint64(args.Offset), limit, !args.Ascending) {
+		result.Transfers = append(result.Transfers, &NFTTransferInfo{
+			Operator:    transfer.Operator,
+			From:        transfer.From,
+			To:          transfer.To,
+			Amount:      (*common.JSONBig)(transfer.Amount),
+			TxHash:      transfer.TxHash,
+			BlockHeight: common.JSONUint64(transfer.BlockHeight),
+			Index:       common.JSONUint64(transfer.Index),
+			LogIndex:    common.JSONUint64(transfer.LogIndex),
+		})
+	}
+	return nil
+}
+
+// ------------------------------ GetFeeStats -----------------------------------
+
+const (
+	// defaultFeeStatsBuckets is the number of buckets GetFeeStats returns if the start is not specified
+	defaultFeeStatsBuckets = 24
+
+	// maxFeeStatsBuckets is the maximum number of buckets GetFeeStats returns
+	maxFeeStatsBuckets = 1000
+)
+
+type GetFeeStatsArgs struct {
+	Granularity string             `json:"granularity"` // block, hour (default) or day
+	Start       *common.JSONUint64 `json:"start"`       // the block height or the unix time, defaults to the 24 buckets before the end
+	End         *common.JSONUint64 `json:"end"`         // the block height or the unix time, defaults to the last finalized block or now
+}
+
+type GetFeeStatsResult struct {
+	Granularity string            `json:"granularity"`
+	Start       common.JSONUint64 `json:"start"`
+	End         common.JSONUint64 `json:"end"`
+	Total       *FeeStatsBucket   `json:"total"`
+	Buckets     []*FeeStatsBucket `json:"buckets"`
+}
+
+type FeeStatsBucket struct {
+	Start      common.JSONUint64       `json:"start"`
+	NumBlocks  common.JSONUint64       `json:"num_blocks"`
+	NumTxs     common.JSONUint64       `json:"num_txs"`
+	TotalFee   types.Coins             `json:"total_fee"`
+	Burned     types.Coins             `json:"burned"`
+	AverageFee *common.JSONBig         `json:"average_fee"` // in PTXWei
+	ByType     []*TxTypeFeeStatsBucket `json:"by_type"`
+}
+
+type TxTypeFeeStatsBucket struct {
+	Type       byte              `json:"type"`
+	NumTxs     common.JSONUint64 `json:"num_txs"`
+	TotalFee   types.Coins       `json:"total_fee"`
+	AverageFee *common.JSONBig   `json:"average_fee"` // in PTXWei
+}
+
+func averageFee(totalFee types.Coins, numTxs uint64) *common.JSONBig {
+	if numTxs == 0 {
+		return (*common.JSONBig)(big.NewInt(0))
+	}
+	return (*common.JSONBig)(new(big.Int).Div(totalFee.NoNil().PTXWei, new(big.Int).SetUint64(numTxs)))
+}
+
+func newFeeStatsBucket(stats *blockchain.FeeStats) *FeeStatsBucket {
+	bucket := &FeeStatsBucket{
+		Start:      common.JSONUint64(stats.Start),
+		NumBlocks:  common.JSONUint64(stats.NumBlocks),
+		NumTxs:     common.JSONUint64(stats.NumTxs),
+		TotalFee:   stats.TotalFee.NoNil(),
+		Burned:     stats.Burned.NoNil(),
+		AverageFee: averageFee(stats.TotalFee, stats.NumTxs),
+		ByType:     []*TxTypeFeeStatsBucket{},
+	}
+	for _, ts := range stats.ByType {
+		bucket.ByType = append(bucket.ByType, &TxTypeFeeStatsBucket{
+			Type:       byte(ts.Type),
+			NumTxs:     common.JSONUint64(ts.NumTxs),
+			TotalFee:   ts.TotalFee.NoNil(),
+			AverageFee: averageFee(ts.TotalFee, ts.NumTxs),
+		})
+	}
+	return bucket
+}
+
+// GetFeeStats returns the transaction fees, the burned amounts and the average fee per transaction
+// type of the finalized blocks, bucketed per block, hour or day. The hourly and daily buckets are
+// based on the block timestamps. All the buckets in the range are returned, including the empty
+// ones, along with their total. It requires the node to run with storage.feeStatsIndex enabled,
+// and only covers the blocks finalized since.
+func (t *PandoRPCService) GetFeeStats(args *GetFeeStatsArgs, result *GetFeeStatsResult) (err error) {
+	if !viper.GetBool(common.CfgStorageFeeStatsIndex) {
+		return errors.New("The fee statistics are not enabled on this node, set " + common.CfgStorageFeeStatsIndex + " to enable them")
+	}
+
+	var granularity blockchain.FeeStatsGranularity
+	switch args.Granularity {
+	case "block":
+		granularity = blockchain.FeeStatsBlock
+	case "", "hour":
+		args.Granularity = "hour"
+		granularity = blockchain.FeeStatsHour
+	case "day":
+		granularity = blockchain.FeeStatsDay
+	default:
+		return fmt.Errorf("Invalid granularity %v, expected block, hour or day", args.Granularity)
+	}
+	interval := granularity.Interval()
+
+	var end uint64
+	if args.End != nil {
+		end = uint64(*args.End)
+	} else if granularity == blockchain.FeeStatsBlock {
+		end = t.consensus.GetLastFinalizedBlock().Height
+	} else {
+		end = uint64(time.Now().Unix())
+	}
+	end = granularity.BucketStart(end)
+
+	var start uint64
+	if args.Start != nil {
+		start = granularity.BucketStart(uint64(*args.Start))
+	} else if end >= (defaultFeeStatsBuckets-1)*interval {
+		start = end - (defaultFeeStatsBuckets-1)*interval
+	}
+	if start > end {
+		return errors.New("The start cannot be after the end")
+	}
+	if (end-start)/interval >= maxFeeStatsBuckets {
+		return fmt.Errorf("The range cannot span more than %v buckets", maxFeeStatsBuckets)
+	}
+
+	result.Granularity = args.Granularity
+	result.Start = common.JSONUint64(start)
+	result.End = common.JSONUint64(end)
+	result.Buckets = []*FeeStatsBucket{}
+
+	total := blockchain.NewFeeStats(start)
+	for pos := start; pos <= end; pos += interval {
+		stats, ok := t.chain.GetFeeStats(granularity, pos)
+		if !ok {
+			stats = blockchain.NewFeeStats(pos)
+		}
+		total.Merge(stats)
+		result.Buckets = append(result.Buckets, newFeeStatsBucket(stats))
+	}
+	result.Total = newFeeStatsBucket(total)
+
+	return nil
+}
+
+// ------------------------------ GetPendingTransactions -----------------------------------
+
+type GetPendingTransactionsArgs struct {
+}
+
+type GetPendingTransactionsResult struct {
+	TxHashes []string `json:"tx_hashes"`
+}
+
+func (t *PandoRPCService) GetPendingTransactions(args *GetPendingTransactionsArgs, result *GetPendingTransactionsResult) (err error) {
+	pendingTxHashes := t.mempool.GetCandidateTransactionHashes()
+	result.TxHashes = pendingTxHashes
+	return nil
+}
+
+// ------------------------------ GetMempoolContents -----------------------------------
+
+type GetMempoolContentsArgs struct {
+	Count common.JSONUint64 `json:"count"` // number of the highest ranked transactions, all of them if zero
+}
+
+type GetMempoolContentsResult struct {
+	Size         common.JSONUint64  `json:"size"`
+	MaxSize      common.JSONUint64  `json:"max_size"`
+	Transactions []mempool.RankedTx `json:"transactions"` // ordered by priority, the next to be included first
+}
+
+func (t *PandoRPCService) GetMempoolContents(args *GetMempoolContentsArgs, result *GetMempoolContentsResult) (err error) {
+	result.Transactions = t.mempool.GetRankedTransactions(int(args.Count))
+	result.Size = common.JSONUint64(t.mempool.Size())
+	result.MaxSize = common.JSONUint64(t.mempool.MaxSize())
+	return nil
+}
+
+// ------------------------------ GetPendingTransactionConflicts -----------------------------------
+
+type GetPendingTransactionConflictsArgs struct {
+}
+
+type GetPendingTransactionConflictsResult struct {
+	Conflicts []mempool.TxConflict `json:"conflicts"`
+}
+
+func (t *PandoRPCService) GetPendingTransactionConflicts(args *GetPendingTransactionConflictsArgs, result *GetPendingTransactionConflictsResult) (err error) {
+	result.Conflicts = t.mempool.GetConflictingTransactions()
+	return nil
+}
+
+// ------------------------------ GetInclusionAudits -----------------------------------
+
+type GetInclusionAuditsArgs struct {
+	Proposer string            `json:"proposer"` // only the blocks of the proposer if specified
+	Count    common.JSONUint64 `json:"count"`    // number of most recent audits, all the retained ones if zero
+}
+
+type SkippedSender struct {
+	Address   common.Address    `json:"address"`
+	NumBlocks common.JSONUint64 `json:"num_blocks"` // number of audited blocks which skipped the sender
+}
+
+type GetInclusionAuditsResult struct {
+	Audits         []*mempool.InclusionAudit `json:"audits"`
+	SkippedSenders []SkippedSender           `json:"skipped_senders"` // ordered by the number of blocks
+}
+
+func (t *PandoRPCService) GetInclusionAudits(args *GetInclusionAuditsArgs, result *GetInclusionAuditsResult) (err error) {
+	if !viper.GetBool(common.CfgMempoolInclusionAudit) {
+		return fmt.Errorf("Inclusion audit is not enabled, set %v to enable it", common.CfgMempoolInclusionAudit)
+	}
+
+	result.Audits = []*mempool.InclusionAudit{}
+	for _, audit := range t.mempool.GetInclusionAudits() {
+		if args.Proposer != "" && audit.Proposer != common.HexToAddress(args.Proposer) {
+			continue
+		}
+		if args.Count != 0 && len(result.Audits) >= int(args.Count) {
+			break
+		}
+		result.Audits = append(result.Audits, audit)
+	}
+
+	numBlocks := make(map[common.Address]uint64)
+	for _, audit := range result.Audits {
+		seen := make(map[common.Address]bool)
+		for _, tx := range audit.SkippedTxs {
+			if !seen[tx.Sender] {
+				seen[tx.Sender] = true
+				numBlocks[tx.Sender]++
+			}
+		}
+	}
+	result.SkippedSenders = []SkippedSender{}
+	for addr, n := range numBlocks {
+		result.SkippedSenders = append(result.SkippedSenders, SkippedSender{Address: addr, NumBlocks: common.JSONUint64(n)})
+	}
+	sort.Slice(result.SkippedSenders, func(i, j int) bool {
+		si, sj := result.SkippedSenders[i], result.SkippedSenders[j]
+		if si.NumBlocks != sj.NumBlocks {
+			return si.NumBlocks > sj.NumBlocks
+		}
+		return bytes.Compare(si.Address[:], sj.Address[:]) < 0
+	})
+	return nil
+}
+
+// ------------------------------ SimulateFee -----------------------------------
+
+const (
+	defaultFeeSimulationHorizon = 10
+	maxFeeSimulationHorizon     = 1000
+)
+
+type SimulateFeeArgs struct {
+	Fee     *common.JSONBig   `json:"fee"`     // candidate fee in PTXWei
+	Size    common.JSONUint64 `json:"size"`    // gas of the candidate transaction, that of a simple send if zero
+	Horizon common.JSONUint64 `json:"horizon"` // number of blocks to estimate the inclusion probability for
+}
+
+type SimulateFeeResult struct {
+	*mempool.FeeSimulation
+	Height common.JSONUint64 `json:"height"` // height of the last finalized block at the checkpoint
+}
+
+func (t *PandoRPCService) SimulateFee(args *SimulateFeeArgs, result *SimulateFeeResult) (err error) {
+	if args.Fee == nil {
+		return fmt.Errorf("Fee is required")
+	}
+	fee := args.Fee.ToInt()
+	if fee.Cmp(new(big.Int).SetUint64(types.MinimumTransactionFeePTXWei)) < 0 {
+		return fmt.Errorf("Insufficient fee, the fee needs to be at least %v PTXWei", types.MinimumTransactionFeePTXWei)
+	}
+	size := uint64(args.Size)
+	if size == 0 {
+		size = 2 * types.GasSendTxPerAccount
+	}
+	horizon := uint64(args.Horizon)
+	if horizon == 0 {
+		horizon = defaultFeeSimulationHorizon
+	}
+	if horizon > maxFeeSimulationHorizon {
+		return fmt.Errorf("Horizon too large, at most %v blocks are allowed", maxFeeSimulationHorizon)
+	}
+
+	gasPrice := new(big.Int).Div(fee, new(big.Int).SetUint64(size))
+	result.FeeSimulation = t.mempool.SimulateFee(gasPrice, horizon)
+	result.Height = common.JSONUint64(t.consensus.GetLastFinalizedBlock().Height)
+	return nil
+}
+
+// ------------------------------ GetBaseFee -----------------------------------
+
+type GetBaseFeeArgs struct {
+	Block BlockSpecifier `json:"block"`
+}
+
+type GetBaseFeeResult struct {
+	Height            common.JSONUint64 `json:"height"`
+	BaseFee           *common.JSONBig   `json:"base_fee"`             // minimum fee of a regular transaction in the next block
+	BaseGasPrice      *common.JSONBig   `json:"base_gas_price"`       // minimum gas price of a smart contract transaction in the next block
+	SuggestedFee      *common.JSONBig   `json:"suggested_fee"`        // fee which still qualifies if the next block is full
+	SuggestedGasPrice *common.JSONBig   `json:"suggested_gas_price"`  // gas price which still qualifies if the next block is full
+	BlockGasLimit     common.JSONUint64 `json:"block_gas_limit"`      // total gas of the regular transactions in a block
+	BlockTxsSizeLimit common.JSONUint64 `json:"block_txs_size_limit"` // total size of the regular transactions in a block
+}
+
+// GetBaseFee returns the base fee the transactions of the block following the given block need to
+// pay. By default, this is the next block to be proposed.
+func (t *PandoRPCService) GetBaseFee(args *GetBaseFeeArgs, result *GetBaseFeeResult) (err error) {
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierPending)
+	if err != nil {
+		return err
+	}
+
+	baseFee := ledgerState.GetBaseFee()
+	suggestedFee := types.MaxNextBaseFee(baseFee)
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.BaseFee = (*common.JSONBig)(baseFee)
+	result.BaseGasPrice = (*common.JSONBig)(types.BaseGasPrice(baseFee))
+	result.SuggestedFee = (*common.JSONBig)(suggestedFee)
+	result.SuggestedGasPrice = (*common.JSONBig)(types.BaseGasPrice(suggestedFee))
+	result.BlockGasLimit = common.JSONUint64(ledgerState.BlockGasLimit())
+	result.BlockTxsSizeLimit = common.JSONUint64(ledgerState.BlockTxsSizeLimit())
+	return nil
+}
+
+// ------------------------------ GetBlock -----------------------------------
+
+type GetBlockArgs struct {
+	Hash  common.Hash    `json:"hash"`
+	Block BlockSpecifier `json:"block"` // used when hash is not specified
+}
+
+type Tx struct {
+	types.Tx `json:"raw"`
+	Type     byte                       `json:"type"`
+	Hash     common.Hash                `json:"hash"`
+	Receipt  *blockchain.TxReceiptEntry `json:"receipt"`
+}
+
+type GetBlockResult struct {
+	*GetBlockResultInner
+}
+
+type GetBlocksResult []*GetBlockResultInner
+
+type GetBlockResultInner struct {
+	ChainID       string                 `json:"chain_id"`
+	Epoch         common.JSONUint64      `json:"epoch"`
+	Height        common.JSONUint64      `json:"height"`
+	Parent        common.Hash            `json:"parent"`
+	TxHash        common.Hash            `json:"transactions_hash"`
+	StateHash     common.Hash            `json:"state_hash"`
+	Timestamp     *common.JSONBig        `json:"timestamp"`
+	Proposer      common.Address         `json:"proposer"`
+	HCC           core.CommitCertificate `json:"hcc"`
+	GuardianVotes *core.AggregatedVotes  `json:"guardian_votes"`
+	Randomness    common.Hash            `json:"randomness"`
+
+	Children []common.Hash    `json:"children"`
+	Status   core.BlockStatus `json:"status"`
+
+	Hash common.Hash `json:"hash"`
+	Txs  []Tx        `json:"transactions"`
+}
+
+// TxType is the type of a transaction in the RPC responses. The values match types.TxType.
+type TxType byte
+
+const (
+	TxTypeCoinbase = byte(iota)
+	TxTypeSlash
+	TxTypeSend
+	TxTypeRametronStake
+	TxTypeReserveFund
+	TxTypeReleaseFund
+	TxTypeServicePayment
+	TxTypeSplitRule
+	TxTypeSmartContract
+	TxTypeDepositStake
+	TxTypeWithdrawStake
+	TxTypeDepositStakeTxV2
+	TxTypeSessionKey
+	TxTypeBatchSend
+	TxTypeSlashAppeal
+	TxTypeSlashAppealVote
+	TxTypeClaimEscrow
+	TxTypeMeteredSettlement
+	TxTypeDelegate
+	TxTypeUndelegate
+	TxTypeSetCommission
+)
+
+func (t *PandoRPCService) GetBlock(args *GetBlockArgs, result *GetBlockResult) (err error) {
+	var block *core.ExtendedBlock
+	if !args.Hash.IsEmpty() {
+		block, err = t.chain.FindBlock(args.Hash)
+	} else if !args.Block.IsEmpty() {
+		block, err = t.resolveBlock(args.Block, BlockSpecifierFinalized)
+	} else {
+		return errors.New("Block hash or block specifier must be specified")
+	}
+	if err != nil {
+		return err
+	}
+
+	result.GetBlockResultInner, err = t.getBlockResultInner(block)
+	return
+}
+
+// ------------------------------ GetBlockByHeight -----------------------------------
+
+type GetBlockByHeightArgs struct {
+	Height common.JSONUint64 `json:"height"`
+}
+
+func (t *PandoRPCService) GetBlockByHeight(args *GetBlockByHeightArgs, result *GetBlockResult) (err error) {
+	if args.Height == 0 {
+		return errors.New("Block height must be specified")
+	}
+
+	blocks := t.chain.FindBlocksByHeight(uint64(args.Height))
+
+	var block *core.ExtendedBlock
+	for _, b := range blocks {
+		if b.Status.IsFinalized() {
+			block = b
+			break
+		}
+	}
+
+	if block == nil {
+		return
+	}
+
+	result.GetBlockResultInner, err = t.getBlockResultInner(block)
+	return
+}
+
+// ------------------------------ GetBlocksByRange -----------------------------------
+
+type GetBlocksByRangeArgs struct {
+	Start common.JSONUint64 `json:"start"`
+	End   common.JSONUint64 `json:"end"`
+}
+
+func (t *PandoRPCService) GetBlocksByRange(args *GetBlocksByRangeArgs, result *GetBlocksResult) (err error) {
+	if args.Start == 0 && args.End == 0 {
+		return errors.New("Starting block and ending block must be specified")
+	}
+
+	if args.Start > args.End {
+		return errors.New("Starting block must be less than ending block")
+	}
+
+	if args.End-args.Start > 100 {
+		return errors.New("Can't retrieve more than 100 blocks at a time")
+	}
+
+	blocks := t.chain.FindBlocksByHeight(uint64(args.End))
+
+	var block *core.ExtendedBlock
+	for _, b := range blocks {
+		if b.Status.IsFinalized() {
+			block = b
+			break
+		}
+	}
+
+	if block == nil {
+		return
+	}
+
+	for common.JSONUint64(block.Height) >= args.Start {
+		blkInner := &GetBlockResultInner{}
+		blkInner.ChainID = block.ChainID
+		blkInner.Epoch = common.JSONUint64(block.Epoch)
+		blkInner.Height = common.JSONUint64(block.Height)
+		blkInner.Parent = block.Parent
+		blkInner.TxHash = block.TxHash
+		blkInner.StateHash = block.StateHash
+		blkInner.Timestamp = (*common.JSONBig)(block.Timestamp)
+		blkInner.Proposer = block.Proposer
+		blkInner.Children = block.Children
+		blkInner.Status = block.Status
+		blkInner.HCC = block.HCC
+		blkInner.GuardianVotes = block.GuardianVotes
+		blkInner.Randomness = block.Randomness
+
+		blkInner.Hash = block.Hash()
+
+		// Parse and fulfill Txs.
+		var tx types.Tx
+		for _, txBytes := range block.Txs {
+			tx, err = types.TxFromBytes(txBytes)
+			if err != nil {
+				return
+			}
+			hash := crypto.Keccak256Hash(txBytes)
+
+			t := getTxType(tx)
+			txw := Tx{
+				Tx:   tx,
+				Hash: hash,
+				Type: t,
+			}
+			blkInner.Txs = append(blkInner.Txs, txw)
+		}
+
+		*result = append([]*GetBlockResultInner{blkInner}, *result...)
+
+		block, err = t.chain.FindBlock(block.Parent)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// ------------------------------ GetLogs -----------------------------------
+
+const (
+	// maxLogsBlockRange is the maximum number of blocks GetLogs can scan
+	maxLogsBlockRange = 50000
+
+	// maxLogsResults is the maximum number of logs GetLogs returns
+	maxLogsResults = 10000
+)
+
+type GetLogsArgs struct {
+	LogFilter
+	FromBlock *common.JSONUint64 // the ToBlock if not specified
+	ToBlock   *common.JSONUint64 // the last finalized block if not specified
+	BlockHash *common.Hash       // only the logs of the block if specified
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (args *GetLogsArgs) UnmarshalJSON(data []byte) error {
+	if err := args.LogFilter.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var raw struct {
+		FromBlock *common.JSONUint64 `json:"from_block"`
+		ToBlock   *common.JSONUint64 `json:"to_block"`
+		BlockHash *common.Hash       `json:"block_hash"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.BlockHash != nil && (raw.FromBlock != nil || raw.ToBlock != nil) {
+		return fmt.Errorf("block_hash cannot be combined with from_block or to_block")
+	}
+	args.FromBlock, args.ToBlock, args.BlockHash = raw.FromBlock, raw.ToBlock, raw.BlockHash
+	return nil
+}
+
+type GetLogsResult struct {
+	Logs []*SubscriptionLog `json:"logs"`
+}
+
+// GetLogs returns the logs emitted by the smart contract transactions of the finalized blocks in
+// the range, filtered by the contract addresses and the topics. The blocks whose bloom filter
+// rules out a match are skipped without loading their receipts.
+func (t *PandoRPCService) GetLogs(args *GetLogsArgs, result *GetLogsResult) (err error) {
+	result.Logs = []*SubscriptionLog{}
+
+	if args.BlockHash != nil {
+		block, err := t.chain.FindBlock(*args.BlockHash)
+		if err != nil {
+			return fmt.Errorf("Block %v is not found", args.BlockHash.Hex())
+		}
+		result.Logs = t.blockLogs(block.Block, &args.LogFilter)
+		return nil
+	}
+
+	to := t.consensus.GetLastFinalizedBlock().Height
+	if args.ToBlock != nil && uint64(*args.ToBlock) < to {
+		to = uint64(*args.ToBlock)
+	}
+	from := to
+	if args.FromBlock != nil {
+		from = uint64(*args.FromBlock)
+	}
+	if from > to {
+		return nil
+	}
+	if to-from >= maxLogsBlockRange {
+		return fmt.Errorf("The block range cannot exceed %v blocks", maxLogsBlockRange)
+	}
+
+	numSkipped := 0
+	for height := from; height <= to; height++ {
+		var block *core.ExtendedBlock
+		for _, b := range t.chain.FindBlocksByHeight(height) {
+			if b.Status.IsFinalized() {
+				block = b
+				break
+			}
+		}
+		if block == nil {
+			continue
+		}
+		if bloom, ok := t.chain.FindBlockBloom(block.Hash()); ok && !args.LogFilter.MatchesBloom(bloom) {
+			numSkipped++
+			continue
+		}
+		result.Logs = append(result.Logs, t.blockLogs(block.Block, &args.LogFilter)...)
+		if len(result.Logs) > maxLogsResults {
+			return fmt.Errorf("More than %v logs match the query, please narrow down the block range", maxLogsResults)
+		}
+	}
+	logger.Debugf("GetLogs: scanned blocks %v to %v, skipped %v blocks by the bloom filters", from, to, numSkipped)
+	return nil
+}
+
+// blockLogs returns the logs emitted by the transactions of the block which pass the filter
+func (t *PandoRPCService) blockLogs(block *core.Block, filter *LogFilter) []*SubscriptionLog {
+	hash := block.Hash()
+	logs := []*SubscriptionLog{}
+	for _, rawTx := range block.Txs {
+		txHash := crypto.Keccak256Hash(rawTx)
+		receipt, found := t.chain.FindTxReceiptByHash(txHash)
+		if !found {
+			continue
+		}
+		for i, log := range receipt.Logs {
+			if !filter.Matches(log) {
+				continue
+			}
+			logs = append(logs, &SubscriptionLog{
+				Log:         log,
+				TxHash:      txHash,
+				BlockHash:   hash,
+				BlockHeight: common.JSONUint64(block.Height),
+				LogIndex:    common.JSONUint64(i),
+			})
+		}
+	}
+	return logs
+}
+
+// ------------------------------ GetStatus -----------------------------------
+
+type GetStatusArgs struct{}
+
+type GetStatusResult struct {
+	Address                    string            `json:"address"`
+	ChainID                    string            `json:"chain_id"`
+	PeerID                     string            `json:"peer_id"`
+	LatestFinalizedBlockHash   common.Hash       `json:"latest_finalized_block_hash"`
+	LatestFinalizedBlockHeight common.JSONUint64 `json:"latest_finalized_block_height"`
+	LatestFinalizedBlockTime   *common.JSONBig   `json:"latest_finalized_block_time"`
+	LatestFinalizedBlockEpoch  common.JSONUint64 `json:"latest_finalized_block_epoch"`
+	CurrentEpoch               common.JSONUint64 `json:"current_epoch"`
+	CurrentHeight              common.JSONUint64 `json:"current_height"`
+	CurrentTime                *common.JSONBig   `json:"current_time"`
+	Syncing                    bool              `json:"syncing"`
+	Archive                    bool              `json:"archive"`
+	EarliestStateHeight        common.JSONUint64 `json:"earliest_state_height"`
+}
+
+func (t *PandoRPCService) GetStatus(args *GetStatusArgs, result *GetStatusResult) (err error) {
+	s := t.consensus.GetSummary()
+	result.Address = t.consensus.ID()
+	//result.PeerID = t.dispatcher.ID()
+	result.PeerID = t.dispatcher.LibP2PID() // TODO: use ID() instead after 1.3.0 upgrade
+	result.ChainID = t.consensus.Chain().ChainID
+	latestFinalizedHash := s.LastFinalizedBlock
+	var latestFinalizedBlock *core.ExtendedBlock
+	if !latestFinalizedHash.IsEmpty() {
+		result.LatestFinalizedBlockHash = latestFinalizedHash
+		latestFinalizedBlock, err = t.chain.FindBlock(latestFinalizedHash)
+		if err != nil {
+			return err
+		}
+		result.LatestFinalizedBlockEpoch = common.JSONUint64(latestFinalizedBlock.Epoch)
+		result.LatestFinalizedBlockHeight = common.JSONUint64(latestFinalizedBlock.Height)
+		result.LatestFinalizedBlockTime = (*common.JSONBig)(latestFinalizedBlock.Timestamp)
+	}
+	result.CurrentEpoch = common.JSONUint64(s.Epoch)
+	result.CurrentTime = (*common.JSONBig)(big.NewInt(time.Now().Unix()))
+
+	maxVoteHeight := uint64(0)
+	epochVotes, err := t.consensus.State().GetEpochVotes()
+	if err != nil {
+		return err
+	}
+	if epochVotes != nil {
+		for _, v := range epochVotes.Votes() {
+			if v.Height > maxVoteHeight {
+				maxVoteHeight = v.Height
+			}
+		}
+		result.CurrentHeight = common.JSONUint64(maxVoteHeight - 1) // current finalized height is at most maxVoteHeight-1
+	}
+
+	result.Syncing = !t.consensus.HasSynced()
+	result.Archive = viper.GetBool(common.CfgStorageArchive)
+	result.EarliestStateHeight = common.JSONUint64(t.ledger.EarliestStateHeight())
+
+	return
+}
+
+// ------------------------------ GetPeers -----------------------------------
+
+type GetPeersArgs struct{}
+
+type GetPeersResult struct {
+	Peers []string `json:"peers"`
+}
+
+func (t *PandoRPCService) GetPeers(args *GetPeersArgs, result *GetPeersResult) (err error) {
+	peers := t.dispatcher.Peers()
+	result.Peers = peers
+
+	return
+}
+
+// ------------------------------ GetPeerURLs -----------------------------------
+
+type GetPeerURLsArgs struct{}
+
+type GetPeerURLsResult struct {
+	PeerURLs []string `json:"peer_urls"`
+}
+
+// GetPeerURLs returns the net addresses of the peers, which a new node can use as its seeds
+func (t *PandoRPCService) GetPeerURLs(args *GetPeerURLsArgs, result *GetPeerURLsResult) (err error) {
+	provider, ok := t.network.(interface {
+		PeerURLs() []string
+	})
+	if !ok || reflect.ValueOf(provider).IsNil() {
+		return errors.New("The peer URLs are only available on the p2p network")
+	}
+	result.PeerURLs = provider.PeerURLs()
+	return
+}
+
+// ------------------------------ GetSentryTopology -----------------------------------
+
+type GetSentryTopologyArgs struct{}
+
+type GetSentryTopologyResult struct {
+	*messenger.SentryTopology
+}
+
+// GetSentryTopology returns the sentries of the validator and their health, or the validators
+// registered with the node if it is a sentry
+func (t *PandoRPCService) GetSentryTopology(args *GetSentryTopologyArgs, result *GetSentryTopologyResult) (err error) {
+	provider, ok := t.network.(interface {
+		SentryTopology() *messenger.SentryTopology
+	})
+	if !ok || reflect.ValueOf(provider).IsNil() {
+		return errors.New("The sentry topology is only available on the p2p network")
+	}
+	result.SentryTopology = provider.SentryTopology()
+	return
+}
+
+// ------------------------------ GetNetworkVersions -----------------------------------
+
+type GetNetworkVersionsArgs struct{}
+
+type GetNetworkVersionsResult struct {
+	*messenger.NetworkVersions
+	CurrentHeight   common.JSONUint64        `json:"current_height"`
+	UpgradeAlerts   []*messenger.ForkSupport `json:"upgrade_alerts"` // the upcoming forks the binary is not ready for
+	UpgradeRequired bool                     `json:"upgrade_required"`
+}
+
+// GetNetworkVersions returns the binary versions and the forks advertised by the peers, and the forks
+// activating within messenger.UpgradeAlertWindow blocks which the binary of the node does not support
+func (t *PandoRPCService) GetNetworkVersions(args *GetNetworkVersionsArgs, result *GetNetworkVersionsResult) (err error) {
+	provider, ok := t.network.(interface {
+		NetworkVersions() *messenger.NetworkVersions
+	})
+	if !ok || reflect.ValueOf(provider).IsNil() {
+		return errors.New("The network versions are only available on the p2p network")
+	}
+	currentHeight := t.consensus.GetLastFinalizedBlock().Height
+	result.NetworkVersions = provider.NetworkVersions()
+	result.CurrentHeight = common.JSONUint64(currentHeight)
+	result.UpgradeAlerts = result.NetworkVersions.UpgradeAlerts(currentHeight, messenger.UpgradeAlertWindow)
+	result.UpgradeRequired = len(result.UpgradeAlerts) > 0
+	return
+}
+
+// ------------------------------ GetForkSchedule -----------------------------------
+
+type GetForkScheduleArgs struct {
+	Block BlockSpecifier `json:"block"` // the forks are reported as enabled or not as of the block following it
+}
+
+type ForkInfo struct {
+	Name    string            `json:"name"`
+	Height  common.JSONUint64 `json:"height"`
+	Enabled bool              `json:"enabled"` // active as of the next block
+}
+
+type GetForkScheduleResult struct {
+	CurrentHeight common.JSONUint64 `json:"current_height"`
+	Forks         []*ForkInfo       `json:"forks"` // in the order the forks were introduced
+}
+
+// GetForkSchedule returns the activation heights of the forks in effect on the node, including the
+// heights overridden by the config
+func (t *PandoRPCService) GetForkSchedule(args *GetForkScheduleArgs, result *GetForkScheduleResult) (err error) {
+	block, err := t.resolveBlock(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	currentHeight := block.Height
+	result.CurrentHeight = common.JSONUint64(currentHeight)
+	result.Forks = []*ForkInfo{}
+	for _, fork := range features.Forks() {
+		result.Forks = append(result.Forks, &ForkInfo{
+			Name:    fork.Name,
+			Height:  common.JSONUint64(fork.Height),
+			Enabled: features.IsEnabled(fork.Name, currentHeight+1),
+		})
+	}
+	return
+}
+
+// ------------------------------ GetVcp -----------------------------------
+
+type GetVcpByHeightArgs struct {
+	Height common.JSONUint64 `json:"height"`
+	Block  BlockSpecifier    `json:"block"` // when specified, only the pool of this block is returned
+}
+
+type GetVcpResult struct {
+	BlockHashVcpPairs []BlockHashVcpPair
+}
+
+type BlockHashVcpPair struct {
+	BlockHash  common.Hash
+	Vcp        *core.ValidatorCandidatePool
+	HeightList *types.HeightList
+}
+
+func (t *PandoRPCService) GetVcpByHeight(args *GetVcpByHeightArgs, result *GetVcpResult) (err error) {
+	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return err
+	}
+
+	db := deliveredView.GetDB()
+	height := uint64(args.Height)
+
+	blockHashVcpPairs := []BlockHashVcpPair{}
+	var blocks []*core.ExtendedBlock
+	if args.Block.IsEmpty() {
+		blocks = t.chain.FindBlocksByHeight(height)
+	} else {
+		block, err := t.resolveBlock(args.Block, BlockSpecifierFinalized)
+		if err != nil {
+			return err
+		}
+		height = block.Height
+		blocks = []*core.ExtendedBlock{block}
+	}
+	for _, b := range blocks {
+		blockHash := b.Hash()
+		stateRoot := b.StateHash
+		blockStoreView := state.NewStoreView(height, stateRoot, db)
+		if blockStoreView == nil { // might have been pruned
+			return fmt.Errorf("the VCP for height %v does not exists, it might have been pruned", height)
+		}
+		vcp := blockStoreView.GetValidatorCandidatePool()
+		hl := blockStoreView.GetStakeTransactionHeightList()
+		blockHashVcpPairs = append(blockHashVcpPairs, BlockHashVcpPair{
+			BlockHash:  blockHash,
+			Vcp:        vcp,
+			HeightList: hl,
+		})
+	}
+
+	result.BlockHashVcpPairs = blockHashVcpPairs
+
+	return nil
+}
+
+// ------------------------------ GetGcp -----------------------------------
+
+type GetGcpByHeightArgs struct {
+	Height common.JSONUint64 `json:"height"`
+	Block  BlockSpecifier    `json:"block"` // when specified, only the pool of this block is returned
+}
+
+type GetGcpResult struct {
+	BlockHashGcpPairs []BlockHashGcpPair
+}
+
+type BlockHashGcpPair struct {
+	BlockHash common.Hash
+	Gcp       *core.GuardianCandidatePool
+}
+
+func (t *PandoRPCService) GetGcpByHeight(args *GetGcpByHeightArgs, result *GetGcpResult) (err error) {
+	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return err
+	}
+
+	db := deliveredView.GetDB()
+	height := uint64(args.Height)
+
+	blockHashGcpPairs := []BlockHashGcpPair{}
+	var blocks []*core.ExtendedBlock
+	if args.Block.IsEmpty() {
+		blocks = t.chain.FindBlocksByHeight(height)
+	} else {
+		block, err := t.resolveBlock(args.Block, BlockSpecifierFinalized)
+		if err != nil {
+			return err
+		}
+		height = block.Height
+		blocks = []*core.ExtendedBlock{block}
+	}
+	for _, b := range blocks {
+		blockHash := b.Hash()
+		stateRoot := b.StateHash
+		blockStoreView := state.NewStoreView(height, stateRoot, db)
+		if blockStoreView == nil { // might have been pruned
+			return fmt.Errorf("the GCP for height %v does not exists, it might have been pruned", height)
+		}
+		gcp := blockStoreView.GetGuardianCandidatePool()
+		blockHashGcpPairs = append(blockHashGcpPairs, BlockHashGcpPair{
+			BlockHash: blockHash,
+			Gcp:       gcp,
+		})
+	}
+
+	result.BlockHashGcpPairs = blockHashGcpPairs
+
+	return nil
+}
+
+// ------------------------------ GetStakeWithdrawalStatus -----------------------------------
+
+// blockIntervalSampleSize is the number of recent blocks used to estimate the average block interval
+const blockIntervalSampleSize = 100
+
+type GetStakeWithdrawalStatusArgs struct {
+	Address string         `json:"address"` // the source address of the stakes
+	Block   BlockSpecifier `json:"block"`
+}
+
+type StakeWithdrawal struct {
+	Holder           common.Address    `json:"holder"`
+	Purpose          uint8             `json:"purpose"`
+	Amount           *common.JSONBig   `json:"amount"`
+	InitiatingHeight common.JSONUint64 `json:"initiating_height"`
+	ReleaseHeight    common.JSONUint64 `json:"release_height"`
+	RemainingBlocks  common.JSONUint64 `json:"remaining_blocks"`
+	RemainingSeconds common.JSONUint64 `json:"remaining_seconds"` // estimated from the recent block interval
+}
+
+type GetStakeWithdrawalStatusResult struct {
+	Address     string            `json:"address"`
+	Height      common.JSONUint64 `json:"height"`
+	Withdrawals []StakeWithdrawal `json:"withdrawals"`
+}
+
+func (t *PandoRPCService) GetStakeWithdrawalStatus(args *GetStakeWithdrawalStatusArgs, result *GetStakeWithdrawalStatusResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	source := common.HexToAddress(args.Address)
+	result.Address = args.Address
+
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	currentHeight := ledgerState.Height()
+	result.Height = common.JSONUint64(currentHeight)
+	blockInterval := t.estimateBlockInterval()
+
+	result.Withdrawals = []StakeWithdrawal{}
+	unbonding := ledgerState.GetStakeUnbondingQueue()
+	addWithdrawals := func(holder *core.StakeHolder, purpose uint8) {
+		for _, stake := range holder.Stakes {
+			if stake.Source != source || !stake.Withdrawn {
+				continue
+			}
+			withdrawal := StakeWithdrawal{
+				Holder:        holder.Holder,
+				Purpose:       purpose,
+				Amount:        (*common.JSONBig)(stake.Amount),
+				ReleaseHeight: common.JSONUint64(stake.ReturnHeight),
+			}
+			if entry := unbonding.Get(source, holder.Holder, purpose); entry != nil {
+				withdrawal.InitiatingHeight = common.JSONUint64(entry.WithdrawHeight)
+			} else if stake.ReturnHeight >= core.ReturnLockingPeriod {
+				withdrawal.InitiatingHeight = common.JSONUint64(stake.ReturnHeight - core.ReturnLockingPeriod)
+			}
+			if stake.ReturnHeight > currentHeight {
+				remaining := stake.ReturnHeight - currentHeight
+				withdrawal.RemainingBlocks = common.JSONUint64(remaining)
+				withdrawal.RemainingSeconds = common.JSONUint64(remaining * blockInterval)
+			}
+			result.Withdrawals = append(result.Withdrawals, withdrawal)
+		}
+	}
+
+	if vcp := ledgerState.GetValidatorCandidatePool(); vcp != nil {
+		for _, candidate := range vcp.SortedCandidates {
+			addWithdrawals(candidate, core.StakeForValidator)
+		}
+	}
+	if gcp := ledgerState.GetGuardianCandidatePool(); gcp != nil {
+		for _, g := range gcp.SortedGuardians {
+			addWithdrawals(g.StakeHolder, core.StakeForGuardian)
+		}
+	}
+
+	return nil
+}
+
+// ------------------------------ GetParamChanges -----------------------------------
+
+type GetParamChangesArgs struct {
+	Block BlockSpecifier `json:"block"`
+}
+
+type GetParamChangesResult struct {
+	Height    common.JSONUint64    `json:"height"`
+	Pending   []*types.ParamChange `json:"pending"`   // announced but not yet activated, sorted by activation height
+	Announced []*types.ParamChange `json:"announced"` // announced by the finalized blocks up to the height, in the order of announcement
+}
+
+func (t *PandoRPCService) GetParamChanges(args *GetParamChangesArgs, result *GetParamChangesResult) (err error) {
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Pending = ledgerState.GetParamChangeSchedule().Changes
+	if result.Pending == nil {
+		result.Pending = []*types.ParamChange{}
+	}
+	result.Announced = []*types.ParamChange{}
+	for _, change := range t.chain.GetParamChangeHistory().Changes {
+		if change.AnnouncedHeight <= ledgerState.Height() {
+			result.Announced = append(result.Announced, change)
+		}
+	}
+	return nil
+}
+
+// ------------------------------ GetGovernanceProposals -----------------------------------
+
+type GetGovernanceProposalsArgs struct {
+	Block BlockSpecifier `json:"block"`
+}
+
+type GovernanceProposalInfo struct {
+	Proposal *types.GovernanceProposal `json:"proposal"`
+	Quorum   bool                      `json:"quorum"`  // the quorum would be reached if tallied with the current stakes
+	Passing  bool                      `json:"passing"` // the proposal would pass if tallied with the current stakes
+}
+
+type GetGovernanceProposalsResult struct {
+	Height    common.JSONUint64         `json:"height"`
+	Proposals []*GovernanceProposalInfo `json:"proposals"` // pending proposals, in the order they were submitted
+}
+
+func (t *PandoRPCService) GetGovernanceProposals(args *GetGovernanceProposalsArgs, result *GetGovernanceProposalsResult) (err error) {
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Proposals = []*GovernanceProposalInfo{}
+	vcp := ledgerState.GetValidatorCandidatePool()
+	for _, proposal := range ledgerState.GetGovernanceProposals().Proposals {
+		quorum, passing := proposal.Tally(vcp)
+		result.Proposals = append(result.Proposals, &GovernanceProposalInfo{
+			Proposal: proposal,
+			Quorum:   quorum,
+			Passing:  passing,
+		})
+	}
+	return nil
+}
+
+// ------------------------------ GetSessionKeys -----------------------------------
+
+type GetSessionKeysArgs struct {
+	Address string         `json:"address"`
+	Block   BlockSpecifier `json:"block"`
+}
+
+type GetSessionKeysResult struct {
+	Address     string              `json:"address"`
+	Height      common.JSONUint64   `json:"height"`
+	SessionKeys []*types.SessionKey `json:"session_keys"` // including the expired session keys not yet removed
+}
+
+func (t *PandoRPCService) GetSessionKeys(args *GetSessionKeysArgs, result *GetSessionKeysResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address := common.HexToAddress(args.Address)
+	result.Address = args.Address
 
-	result.GetBlockResultInner = &GetBlockResultInner{}
-	result.ChainID = block.ChainID
-	result.Epoch = common.JSONUint64(block.Epoch)
-	result.Height = common.JSONUint64(block.Height)
-	result.Parent = block.Parent
-	result.TxHash = block.TxHash
-	result.StateHash = block.StateHash
-	result.Timestamp = (*common.JSONBig)(block.Timestamp)
-	result.Proposer = block.Proposer
-	result.Children = block.Children
-	result.Status = block.Status
-	result.HCC = block.HCC
-	result.GuardianVotes = block.GuardianVotes
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.SessionKeys = ledgerState.GetSessionKeys(address).Keys
+	if result.SessionKeys == nil {
+		result.SessionKeys = []*types.SessionKey{}
+	}
+	return nil
+}
 
-	result.Hash = block.Hash()
+// ------------------------------ GetRametronNode -----------------------------------
 
-	// Parse and fulfill Txs.
-	var tx types.Tx
-	for _, txBytes := range block.Txs {
-		tx, err = types.TxFromBytes(txBytes)
-		if err != nil {
-			return
-		}
-		hash := crypto.Keccak256Hash(txBytes)
+type GetRametronNodeArgs struct {
+	Address string         `json:"address"`
+	Block   BlockSpecifier `json:"block"`
+}
 
-		tp := getTxType(tx)
-		txw := Tx{
-			Tx:   tx,
-			Hash: hash,
-			Type: tp,
-		}
+type GetRametronNodeResult struct {
+	Height       common.JSONUint64   `json:"height"`
+	Node         *types.RametronNode `json:"node"`
+	Stake        *common.JSONBig     `json:"stake"`         // the PandoWei balance of the node
+	Uptime       common.JSONUint64   `json:"uptime"`        // over the uptime window, in basis points
+	RewardWeight common.JSONUint64   `json:"reward_weight"` // 0 if the node is not eligible for the next Rametron rewards
+}
 
-		receipt, found := t.chain.FindTxReceiptByHash(hash)
-		if found {
-			txw.Receipt = receipt
+func (t *PandoRPCService) GetRametronNode(args *GetRametronNodeArgs, result *GetRametronNodeResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address := common.HexToAddress(args.Address)
+
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	node := ledgerState.GetRametronNode(address)
+	if node == nil {
+		return fmt.Errorf("Rametron node %v is not registered for a tier", address.Hex())
+	}
+	stake := big.NewInt(0)
+	if account := ledgerState.GetAccount(address); account != nil {
+		stake = account.Balance.NoNil().PandoWei
+	}
+	// The next Rametron rewards are granted at the checkpoint starting the next epoch
+	nextEpoch := types.RametronEpoch(ledgerState.Height()) + 1
+	uptime := node.Uptime(nextEpoch)
+
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Node = node
+	result.Stake = (*common.JSONBig)(stake)
+	result.Uptime = common.JSONUint64(uptime)
+	nextCheckpoint := nextEpoch*uint64(common.CheckpointInterval) + 1
+	result.RewardWeight = common.JSONUint64(execution.RametronRewardWeight(ledgerState, node, nextEpoch, nextCheckpoint))
+	return nil
+}
+
+// ------------------------------ GetBridgeMessages -----------------------------------
+
+const maxBridgeMessages = 100
+
+type GetBridgeMessagesArgs struct {
+	Start common.JSONUint64 `json:"start"` // the nonce of the first message, 1 if not specified
+	Limit common.JSONUint64 `json:"limit"`
+	Block BlockSpecifier    `json:"block"`
+}
+
+type BridgeMessageInfo struct {
+	Message *types.BridgeMessage `json:"message"`
+	Hash    common.Hash          `json:"hash"` // the hash of the RLP encoded message
+}
+
+type GetBridgeMessagesResult struct {
+	Height    common.JSONUint64    `json:"height"`
+	LastNonce common.JSONUint64    `json:"last_nonce"`
+	Messages  []*BridgeMessageInfo `json:"messages"`
+}
+
+// GetBridgeMessages returns the outbound bridge messages recorded by the bridge lock and burn
+// transactions, in the order of their nonces. The relayers poll it for the messages to relay.
+func (t *PandoRPCService) GetBridgeMessages(args *GetBridgeMessagesArgs, result *GetBridgeMessagesResult) (err error) {
+	limit := uint64(args.Limit)
+	if limit == 0 || limit > maxBridgeMessages {
+		limit = maxBridgeMessages
+	}
+	start := uint64(args.Start)
+	if start == 0 {
+		start = 1
+	}
+
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+
+	lastNonce := ledgerState.GetBridgeNonce()
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.LastNonce = common.JSONUint64(lastNonce)
+	result.Messages = []*BridgeMessageInfo{}
+	for nonce := start; nonce <= lastNonce && uint64(len(result.Messages)) < limit; nonce++ {
+		msg := ledgerState.GetBridgeMessage(nonce)
+		if msg == nil {
+			continue
 		}
+		result.Messages = append(result.Messages, &BridgeMessageInfo{Message: msg, Hash: msg.Hash()})
+	}
+	return nil
+}
 
-		result.Txs = append(result.Txs, txw)
+// ------------------------------ GetBridgeMessageProof -----------------------------------
+
+type GetBridgeMessageProofArgs struct {
+	Nonce  common.JSONUint64 `json:"nonce"`
+	Height common.JSONUint64 `json:"height"` // the latest provable header if not specified
+}
+
+type GetBridgeMessageProofResult struct {
+	Message   *types.BridgeMessage `json:"message"`
+	Hash      common.Hash          `json:"hash"`       // the hash of the RLP encoded message
+	Encoded   string               `json:"encoded"`    // the RLP encoded message, i.e. the value proven in the state
+	Height    common.JSONUint64    `json:"height"`     // the height of the block the message is proven at
+	BlockHash common.Hash          `json:"block_hash"` // the hash of the block the message is proven at
+	StateRoot common.Hash          `json:"state_root"` // the state root of the block
+	Proof     []string             `json:"proof"`      // the state trie nodes from the state root to the message
+	Trio      string               `json:"trio"`       // the RLP encoded block trio proving the finalization of the block
+}
+
+// GetBridgeMessageProof returns the proof of an outbound bridge message for the relayers: the Merkle
+// proof of the message against the state root of a finalized block, along with the proof of the
+// finalization of the block (see GetHeaderProof). Together, they let the bridge contract of the
+// foreign chain verify the message against the validator set it tracks.
+func (t *PandoRPCService) GetBridgeMessageProof(args *GetBridgeMessageProofArgs, result *GetBridgeMessageProofResult) (err error) {
+	if args.Nonce == 0 {
+		return errors.New("Nonce must be specified")
 	}
-	return
+
+	trio, err := t.getProvableBlockTrio(uint64(args.Height))
+	if err != nil {
+		return err
+	}
+	header := trio.First.Header
+	sv := state.NewReadOnlyStoreView(header.Height, header.StateHash, t.ledger.State().DB())
+	if sv == nil {
+		return fmt.Errorf("The state of block %v is not found, it might have been pruned", header.Height)
+	}
+
+	nonce := uint64(args.Nonce)
+	msg := sv.GetBridgeMessage(nonce)
+	if msg == nil {
+		return fmt.Errorf("Bridge message %v does not exist at height %v", nonce, header.Height)
+	}
+	proof, err := sv.ProveBridgeMessage(nonce)
+	if err != nil {
+		return fmt.Errorf("Failed to prove the bridge message: %v", err)
+	}
+
+	result.Message = msg
+	result.Hash = msg.Hash()
+	if result.Encoded, err = encodeRLP(msg); err != nil {
+		return err
+	}
+	result.Height = common.JSONUint64(header.Height)
+	result.BlockHash = header.Hash()
+	result.StateRoot = header.StateHash
+	result.Proof = encodeProof(proof)
+	result.Trio, err = encodeRLP(trio)
+	return err
 }
 
-// ------------------------------ GetBlocksByRange -----------------------------------
+// ------------------------------ GetBridgeValidators -----------------------------------
 
-type GetBlocksByRangeArgs struct {
-	Start common.JSONUint64 `json:"start"`
-	End   common.JSONUint64 `json:"end"`
+type GetBridgeValidatorsArgs struct {
+	Block BlockSpecifier `json:"block"`
 }
 
-func (t *PandoRPCService) GetBlocksByRange(args *GetBlocksByRangeArgs, result *GetBlocksResult) (err error) {
-	if args.Start == 0 && args.End == 0 {
-		return errors.New("Starting block and ending block must be specified")
+type BridgeValidatorInfo struct {
+	Address common.Address  `json:"address"`
+	Stake   *common.JSONBig `json:"stake"`
+}
+
+type GetBridgeValidatorsResult struct {
+	Height     common.JSONUint64      `json:"height"`
+	Threshold  common.JSONUint64      `json:"threshold"` // the share of the stake (in basis points) which needs to attest a foreign event
+	Validators []*BridgeValidatorInfo `json:"validators"`
+}
+
+// GetBridgeValidators returns the bridge validators, whose attestations of the foreign events are
+// verified by the BridgeClaimTx, and the attestation threshold.
+func (t *PandoRPCService) GetBridgeValidators(args *GetBridgeValidatorsArgs, result *GetBridgeValidatorsResult) (err error) {
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
 	}
 
-	if args.Start > args.End {
-		return errors.New("Starting block must be less than ending block")
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Threshold = common.JSONUint64(ledgerState.BridgeAttestationThreshold())
+	result.Validators = []*BridgeValidatorInfo{}
+	for _, validator := range types.BridgeValidators(ledgerState.GetValidatorCandidatePool()) {
+		result.Validators = append(result.Validators, &BridgeValidatorInfo{
+			Address: validator.Holder,
+			Stake:   (*common.JSONBig)(validator.TotalStake()),
+		})
 	}
+	return nil
+}
 
-	if args.End-args.Start > 100 {
-		return errors.New("Can't retrieve more than 100 blocks at a time")
+// ------------------------------ GetWrappedBalance -----------------------------------
+
+type GetWrappedBalanceArgs struct {
+	Address     string         `json:"address"`
+	SourceChain string         `json:"source_chain"`
+	Token       string         `json:"token"` // the token of the foreign chain, its native coin if not specified
+	Block       BlockSpecifier `json:"block"`
+}
+
+type GetWrappedBalanceResult struct {
+	Height  common.JSONUint64 `json:"height"`
+	Asset   common.Hash       `json:"asset"` // the ID of the wrapped asset
+	Balance *common.JSONBig   `json:"balance"`
+	Supply  *common.JSONBig   `json:"supply"`
+}
+
+// GetWrappedBalance returns the balance of the wrapped foreign asset held by the address, and the
+// supply of the asset.
+func (t *PandoRPCService) GetWrappedBalance(args *GetWrappedBalanceArgs, result *GetWrappedBalanceResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	if err := types.CheckBridgeChainID(args.SourceChain); err != nil {
+		return err
+	}
+	var token common.Address
+	if args.Token != "" {
+		token = common.HexToAddress(args.Token)
 	}
 
-	blocks := t.chain.FindBlocksByHeight(uint64(args.End))
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
 
-	var block *core.ExtendedBlock
-	for _, b := range blocks {
+	asset := types.WrappedAssetID(args.SourceChain, token)
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Asset = asset
+	result.Balance = (*common.JSONBig)(ledgerState.GetWrappedBalance(asset, common.HexToAddress(args.Address)))
+	result.Supply = (*common.JSONBig)(ledgerState.GetWrappedSupply(asset))
+	return nil
+}
+
+// ------------------------------ GetSubchain -----------------------------------
+
+type GetSubchainArgs struct {
+	ID      string         `json:"id"`       // the ID of the subchain, or
+	ChainID string         `json:"chain_id"` // the chain ID of the subchain
+	Block   BlockSpecifier `json:"block"`
+}
+
+type GetSubchainResult struct {
+	Height           common.JSONUint64 `json:"height"`
+	Subchain         *types.Subchain   `json:"subchain"`
+	ActiveStake      *common.JSONBig   `json:"active_stake"`       // the stake of the validators which can sign the checkpoints
+	NextCheckpoint   common.JSONUint64 `json:"next_checkpoint"`    // the subchain height of the next checkpoint
+	FraudProofWindow common.JSONUint64 `json:"fraud_proof_window"` // the number of blocks a checkpoint can be challenged
+}
+
+// GetSubchain returns the registration, the stakes and the checkpoints of a subchain
+func (t *PandoRPCService) GetSubchain(args *GetSubchainArgs, result *GetSubchainResult) (err error) {
+	var id common.Hash
+	if args.ID != "" {
+		id = common.HexToHash(args.ID)
+	} else if args.ChainID != "" {
+		id = types.SubchainID(args.ChainID)
+	} else {
+		return errors.New("Subchain ID or chain ID must be specified")
+	}
+
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+
+	subchain := ledgerState.GetSubchain(id)
+	if subchain == nil {
+		return fmt.Errorf("Subchain %v is not registered", id.Hex())
+	}
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Subchain = subchain
+	result.ActiveStake = (*common.JSONBig)(subchain.ActiveStake())
+	result.NextCheckpoint = common.JSONUint64(subchain.NextCheckpointHeight())
+	result.FraudProofWindow = common.JSONUint64(types.SubchainFraudProofWindow)
+	return nil
+}
+
+// ------------------------------ GetSubchains -----------------------------------
+
+type GetSubchainsArgs struct {
+	Block BlockSpecifier `json:"block"`
+}
+
+type GetSubchainsResult struct {
+	Height    common.JSONUint64 `json:"height"`
+	Subchains []*types.Subchain `json:"subchains"`
+}
+
+// GetSubchains returns all the registered subchains, ordered by ID
+func (t *PandoRPCService) GetSubchains(args *GetSubchainsArgs, result *GetSubchainsResult) (err error) {
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Subchains = ledgerState.GetSubchains()
+	return nil
+}
+
+// ------------------------------ GetHTLC -----------------------------------
+
+type GetHTLCArgs struct {
+	ID    string         `json:"id"`
+	Block BlockSpecifier `json:"block"`
+}
+
+type GetHTLCResult struct {
+	Height  common.JSONUint64 `json:"height"`
+	HTLC    *types.HTLC       `json:"htlc"`
+	Expired bool              `json:"expired"` // the HTLC can no longer be claimed, only refunded
+}
+
+func (t *PandoRPCService) GetHTLC(args *GetHTLCArgs, result *GetHTLCResult) (err error) {
+	if args.ID == "" {
+		return errors.New("ID must be specified")
+	}
+	id := common.HexToHash(args.ID)
+
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	htlc := ledgerState.GetHTLC(id)
+	if htlc == nil {
+		return fmt.Errorf("HTLC %v does not exist, or has already been claimed or refunded", id.Hex())
+	}
+
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.HTLC = htlc
+	result.Expired = htlc.CheckRefund(ledgerState.Height()+1) == nil
+	return nil
+}
+
+// ------------------------------ GetSlashAppeals -----------------------------------
+
+type GetSlashAppealsArgs struct {
+	Block BlockSpecifier `json:"block"`
+}
+
+type SlashAppealInfo struct {
+	Appeal       *types.SlashAppeal `json:"appeal"`
+	Slash        *types.SlashRecord `json:"slash"`
+	ExpiryHeight common.JSONUint64  `json:"expiry_height"` // the last height at which the appeal can be approved
+}
+
+type GetSlashAppealsResult struct {
+	Height        common.JSONUint64  `json:"height"`
+	Appeals       []*SlashAppealInfo `json:"appeals"` // pending appeals, in the order they were filed
+	InsurancePool types.Coins        `json:"insurance_pool"`
+}
+
+func (t *PandoRPCService) GetSlashAppeals(args *GetSlashAppealsArgs, result *GetSlashAppealsResult) (err error) {
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	result.Height = common.JSONUint64(ledgerState.Height())
+	result.Appeals = []*SlashAppealInfo{}
+	for _, appeal := range ledgerState.GetSlashAppeals().Appeals {
+		result.Appeals = append(result.Appeals, &SlashAppealInfo{
+			Appeal:       appeal,
+			Slash:        ledgerState.GetSlashRecord(appeal.Appellant, appeal.ReserveSequence),
+			ExpiryHeight: common.JSONUint64(appeal.ExpiryHeight()),
+		})
+	}
+	result.InsurancePool = types.NewCoins(0, 0)
+	if pool := ledgerState.GetAccount(types.SlashInsurancePoolAddress); pool != nil {
+		result.InsurancePool = pool.Balance
+	}
+	return nil
+}
+
+// estimateBlockInterval returns the average interval in seconds between the recent finalized blocks.
+func (t *PandoRPCService) estimateBlockInterval() uint64 {
+	defaultInterval := uint64(viper.GetInt(common.CfgConsensusMinProposalWait))
+
+	latest := t.consensus.GetLastFinalizedBlock()
+	if latest == nil || latest.Timestamp == nil || latest.Height <= blockIntervalSampleSize {
+		return defaultInterval
+	}
+	var earlier *core.ExtendedBlock
+	for _, b := range t.chain.FindBlocksByHeight(latest.Height - blockIntervalSampleSize) {
 		if b.Status.IsFinalized() {
-			block = b
+			earlier = b
 			break
 		}
 	}
+	if earlier == nil || earlier.Timestamp == nil || latest.Timestamp.Cmp(earlier.Timestamp) <= 0 {
+		return defaultInterval
+	}
+	elapsed := new(big.Int).Sub(latest.Timestamp, earlier.Timestamp).Uint64()
+	interval := elapsed / blockIntervalSampleSize
+	if interval == 0 {
+		return 1
+	}
+	return interval
+}
 
-	if block == nil {
-		return
+// ------------------------------ GetGuardianKey -----------------------------------
+
+type GetGuardianInfoArgs struct{}
+
+type GetGuardianInfoResult struct {
+	BLSPubkey string
+	BLSPop    string
+	Address   string
+	Signature string
+}
+
+func (t *PandoRPCService) GetGuardianInfo(args *GetGuardianInfoArgs, result *GetGuardianInfoResult) (err error) {
+	signer := t.consensus.Signer()
+	blsKey, err := bls.GenKey(strings.NewReader(common.Bytes2Hex(signer.PublicKey().ToBytes())))
+	if err != nil {
+		return fmt.Errorf("Failed to get BLS key: %v", err.Error())
 	}
 
-	for common.JSONUint64(block.Height) >= args.Start {
-		blkInner := &GetBlockResultInner{}
-		blkInner.ChainID = block.ChainID
-		blkInner.Epoch = common.JSONUint64(block.Epoch)
-		blkInner.Height = common.JSONUint64(block.Height)
-		blkInner.Parent = block.Parent
-		blkInner.TxHash = block.TxHash
-		blkInner.StateHash = block.StateHash
-		blkInner.Timestamp = (*common.JSONBig)(block.Timestamp)
-		blkInner.Proposer = block.Proposer
-		blkInner.Children = block.Children
-		blkInner.Status = block.Status
-		blkInner.HCC = block.HCC
-		blkInner.GuardianVotes = block.GuardianVotes
+	result.Address = signer.PublicKey().Address().Hex()
+	result.BLSPubkey = hex.EncodeToString(blsKey.PublicKey().ToBytes())
+	popBytes := blsKey.PopProve().ToBytes()
+	result.BLSPop = hex.EncodeToString(popBytes)
 
-		blkInner.Hash = block.Hash()
+	sig, err := signer.Sign(popBytes)
+	if err != nil {
+		return fmt.Errorf("Failed to generate signature: %v", err.Error())
+	}
+	result.Signature = hex.EncodeToString(sig.ToBytes())
 
-		// Parse and fulfill Txs.
-		var tx types.Tx
-		for _, txBytes := range block.Txs {
-			tx, err = types.TxFromBytes(txBytes)
-			if err != nil {
-				return
-			}
-			hash := crypto.Keccak256Hash(txBytes)
+	return nil
+}
 
-			t := getTxType(tx)
-			txw := Tx{
-				Tx:   tx,
-				Hash: hash,
-				Type: t,
-			}
-			blkInner.Txs = append(blkInner.Txs, txw)
+// ------------------------------ GetContractStorage -----------------------------------
+
+const (
+	defaultContractStorageLimit = 100
+	maxContractStorageLimit     = 1000
+)
+
+type GetContractStorageArgs struct {
+	Address string         `json:"address"`
+	Block   BlockSpecifier `json:"block"`
+	Start   string         `json:"start"` // the slot to start the enumeration from, inclusive
+	Limit   uint64         `json:"limit"`
+	Prove   bool           `json:"prove"` // include the Merkle proofs of the account and the slots
+}
+
+type ContractStorageSlot struct {
+	Slot  common.Hash `json:"slot"`
+	Value common.Hash `json:"value"`
+	Proof []string    `json:"proof,omitempty"` // the storage trie nodes from the storage root to the slot
+}
+
+type GetContractStorageResult struct {
+	Address      string                 `json:"address"`
+	BlockHeight  common.JSONUint64      `json:"block_height"`
+	StateRoot    common.Hash            `json:"state_root"`
+	StorageRoot  common.Hash            `json:"storage_root"`
+	AccountProof []string               `json:"account_proof,omitempty"` // the state trie nodes from the state root to the account
+	Slots        []*ContractStorageSlot `json:"slots"`
+	Next         *common.Hash           `json:"next"` // the start of the next page, null if all the slots were returned
+}
+
+// GetContractStorage enumerates the non-empty storage slots of a contract in the order of the slots,
+// one page at a time.
+func (t *PandoRPCService) GetContractStorage(args *GetContractStorageArgs, result *GetContractStorageResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address := common.HexToAddress(args.Address)
+	limit := args.Limit
+	if limit == 0 {
+		limit = defaultContractStorageLimit
+	}
+	if limit > maxContractStorageLimit {
+		return fmt.Errorf("Limit cannot exceed %v", maxContractStorageLimit)
+	}
+	var start common.Hash
+	if args.Start != "" {
+		startBytes, err := hexutil.Decode(args.Start)
+		if err != nil || len(startBytes) > common.HashLength {
+			return fmt.Errorf("Invalid start slot: %v", args.Start)
 		}
+		start = common.BytesToHash(startBytes)
+	}
 
-		*result = append([]*GetBlockResultInner{blkInner}, *result...)
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
+	if err != nil {
+		return err
+	}
+	account := ledgerState.GetAccount(address)
+	if account == nil {
+		return fmt.Errorf("Account with address %s is not found", address.Hex())
+	}
 
-		block, err = t.chain.FindBlock(block.Parent)
+	result.Address = args.Address
+	result.BlockHeight = common.JSONUint64(ledgerState.Height())
+	result.StateRoot = ledgerState.Hash()
+	result.StorageRoot = account.Root
+	result.Slots = []*ContractStorageSlot{}
+	if args.Prove {
+		proof, err := ledgerState.ProveAccount(address)
 		if err != nil {
-			return err
+			return fmt.Errorf("Failed to prove the account: %v", err)
 		}
+		result.AccountProof = encodeProof(proof)
 	}
-	return
+
+	// One more slot than the limit is visited to find the start of the next page
+	err = ledgerState.IterateStorage(address, start, args.Prove, func(slot, value common.Hash, proof [][]byte) bool {
+		if uint64(len(result.Slots)) == limit {
+			next := slot
+			result.Next = &next
+			return false
+		}
+		entry := &ContractStorageSlot{
+			Slot:  slot,
+			Value: value,
+		}
+		if args.Prove {
+			entry.Proof = encodeProof(proof)
+		}
+		result.Slots = append(result.Slots, entry)
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to iterate the contract storage: %v", err)
+	}
+	return nil
 }
 
-// ------------------------------ GetStatus -----------------------------------
+// ------------------------------ GetProof -----------------------------------
 
-type GetStatusArgs struct{}
+const maxProofSlots = 100
 
-type GetStatusResult struct {
-	Address                    string            `json:"address"`
-	ChainID                    string            `json:"chain_id"`
-	PeerID                     string            `json:"peer_id"`
-	LatestFinalizedBlockHash   common.Hash       `json:"latest_finalized_block_hash"`
-	LatestFinalizedBlockHeight common.JSONUint64 `json:"latest_finalized_block_height"`
-	LatestFinalizedBlockTime   *common.JSONBig   `json:"latest_finalized_block_time"`
-	LatestFinalizedBlockEpoch  common.JSONUint64 `json:"latest_finalized_block_epoch"`
-	CurrentEpoch               common.JSONUint64 `json:"current_epoch"`
-	CurrentHeight              common.JSONUint64 `json:"current_height"`
-	CurrentTime                *common.JSONBig   `json:"current_time"`
-	Syncing                    bool              `json:"syncing"`
+type GetProofArgs struct {
+	Address string         `json:"address"`
+	Slots   []string       `json:"slots"` // the storage slots to prove
+	Block   BlockSpecifier `json:"block"`
 }
 
-func (t *PandoRPCService) GetStatus(args *GetStatusArgs, result *GetStatusResult) (err error) {
-	s := t.consensus.GetSummary()
-	result.Address = t.consensus.ID()
-	//result.PeerID = t.dispatcher.ID()
-	result.PeerID = t.dispatcher.LibP2PID() // TODO: use ID() instead after 1.3.0 upgrade
-	result.ChainID = t.consensus.Chain().ChainID
-	latestFinalizedHash := s.LastFinalizedBlock
-	var latestFinalizedBlock *core.ExtendedBlock
-	if !latestFinalizedHash.IsEmpty() {
-		result.LatestFinalizedBlockHash = latestFinalizedHash
-		latestFinalizedBlock, err = t.chain.FindBlock(latestFinalizedHash)
-		if err != nil {
-			return err
+type GetProofResult struct {
+	Address       string                 `json:"address"`
+	BlockHeight   common.JSONUint64      `json:"block_height"`
+	StateRoot     common.Hash            `json:"state_root"`
+	Account       *types.Account         `json:"account"`       // null if the account does not exist
+	AccountProof  []string               `json:"account_proof"` // the state trie nodes from the state root to the account
+	StorageRoot   common.Hash            `json:"storage_root"`
+	StorageProofs []*ContractStorageSlot `json:"storage_proofs"`
+}
+
+// GetProof returns the Merkle proofs of the account and of its storage slots at the given block. The account
+// proof verifies against the state root of the block, and the storage proofs against the storage root of the
+// account. The proofs of an absent account or slot prove its absence.
+func (t *PandoRPCService) GetProof(args *GetProofArgs, result *GetProofResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	if len(args.Slots) > maxProofSlots {
+		return fmt.Errorf("Cannot prove more than %v slots", maxProofSlots)
+	}
+	address := common.HexToAddress(args.Address)
+	slots := []common.Hash{}
+	for _, s := range args.Slots {
+		slotBytes, err := hexutil.Decode(s)
+		if err != nil || len(slotBytes) > common.HashLength {
+			return fmt.Errorf("Invalid slot: %v", s)
 		}
-		result.LatestFinalizedBlockEpoch = common.JSONUint64(latestFinalizedBlock.Epoch)
-		result.LatestFinalizedBlockHeight = common.JSONUint64(latestFinalizedBlock.Height)
-		result.LatestFinalizedBlockTime = (*common.JSONBig)(latestFinalizedBlock.Timestamp)
+		slots = append(slots, common.BytesToHash(slotBytes))
 	}
-	result.CurrentEpoch = common.JSONUint64(s.Epoch)
-	result.CurrentTime = (*common.JSONBig)(big.NewInt(time.Now().Unix()))
 
-	maxVoteHeight := uint64(0)
-	epochVotes, err := t.consensus.State().GetEpochVotes()
+	ledgerState, err := t.resolveStoreView(args.Block, BlockSpecifierFinalized)
 	if err != nil {
 		return err
 	}
-	if epochVotes != nil {
-		for _, v := range epochVotes.Votes() {
-			if v.Height > maxVoteHeight {
-				maxVoteHeight = v.Height
-			}
-		}
-		result.CurrentHeight = common.JSONUint64(maxVoteHeight - 1) // current finalized height is at most maxVoteHeight-1
-	}
 
-	result.Syncing = !t.consensus.HasSynced()
+	result.Address = args.Address
+	result.BlockHeight = common.JSONUint64(ledgerState.Height())
+	result.StateRoot = ledgerState.Hash()
+	accountProof, err := ledgerState.ProveAccount(address)
+	if err != nil {
+		return fmt.Errorf("Failed to prove the account: %v", err)
+	}
+	result.AccountProof = encodeProof(accountProof)
+	result.StorageProofs = []*ContractStorageSlot{}
+	if account := ledgerState.GetAccount(address); account != nil {
+		result.Account = account
+		result.StorageRoot = account.Root
+	}
 
-	return
+	for _, slot := range slots {
+		proof, err := ledgerState.ProveStorage(address, slot)
+		if err != nil {
+			return fmt.Errorf("Failed to prove slot %v: %v", slot.Hex(), err)
+		}
+		result.StorageProofs = append(result.StorageProofs, &ContractStorageSlot{
+			Slot:  slot,
+			Value: ledgerState.GetState(address, slot),
+			Proof: encodeProof(proof),
+		})
+	}
+	return nil
 }
 
-// ------------------------------ GetPeers -----------------------------------
+// ------------------------------ Light Client -----------------------------------
 
-type GetPeersArgs struct{}
+const maxValidatorSetProofs = 100
 
-type GetPeersResult struct {
-	Peers []string `json:"peers"`
-}
+// headerProofSearchDepth is how far below the last finalized block the latest provable header is
+// searched for, since proving a block requires its finalized child and grandchild
+const headerProofSearchDepth = 16
 
-func (t *PandoRPCService) GetPeers(args *GetPeersArgs, result *GetPeersResult) (err error) {
-	peers := t.dispatcher.Peers()
-	result.Peers = peers
+type GetGenesisProofArgs struct {
+}
 
-	return
+type GetGenesisProofResult struct {
+	Header string `json:"header"` // the RLP encoded genesis block header
+	Proof  string `json:"proof"`  // the RLP encoded VCP proof against the state root of the genesis block
 }
 
-// ------------------------------ GetVcp -----------------------------------
+// GetGenesisProof returns the genesis block header and the proof of the genesis validator candidate pool,
+// the trust anchor of the light clients.
+func (t *PandoRPCService) GetGenesisProof(args *GetGenesisProofArgs, result *GetGenesisProofResult) (err error) {
+	blocks := t.chain.FindBlocksByHeight(core.GenesisBlockHeight)
+	if len(blocks) == 0 {
+		return errors.New("Genesis block not found")
+	}
+	genesis := blocks[0]
+	sv := state.NewReadOnlyStoreView(genesis.Height, genesis.StateHash, t.ledger.State().DB())
+	if sv == nil {
+		return errors.New("The state of the genesis block is not found")
+	}
+	vp := &core.VCPProof{}
+	if err := sv.ProveVCP(state.ValidatorCandidatePoolKey(), vp); err != nil {
+		return fmt.Errorf("Failed to prove the genesis validator candidate pool: %v", err)
+	}
 
-type GetVcpByHeightArgs struct {
-	Height common.JSONUint64 `json:"height"`
+	if result.Header, err = encodeRLP(genesis.BlockHeader); err != nil {
+		return err
+	}
+	result.Proof, err = encodeRLP(vp)
+	return err
 }
 
-type GetVcpResult struct {
-	BlockHashVcpPairs []BlockHashVcpPair
+type GetValidatorSetProofsArgs struct {
+	Start common.JSONUint64 `json:"start"` // the validator set changes after this height are returned
 }
 
-type BlockHashVcpPair struct {
-	BlockHash  common.Hash
-	Vcp        *core.ValidatorCandidatePool
-	HeightList *types.HeightList
+type GetValidatorSetProofsResult struct {
+	Trios []string `json:"trios"` // the RLP encoded block trios proving the validator set changes, in ascending order of heights
 }
 
-func (t *PandoRPCService) GetVcpByHeight(args *GetVcpByHeightArgs, result *GetVcpResult) (err error) {
-	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+// GetValidatorSetProofs returns the proofs of the validator set changes after the start height, up to
+// maxValidatorSetProofs of them. Each proof is the block trio of a finalized block with stake changes,
+// carrying the VCP proof of its state, and the votes of the previous validator set on its child.
+func (t *PandoRPCService) GetValidatorSetProofs(args *GetValidatorSetProofsArgs, result *GetValidatorSetProofsResult) (err error) {
+	sv, err := t.ledger.GetFinalizedSnapshot()
 	if err != nil {
 		return err
 	}
+	db := t.ledger.State().DB()
 
-	db := deliveredView.GetDB()
-	height := uint64(args.Height)
-
-	blockHashVcpPairs := []BlockHashVcpPair{}
-	blocks := t.chain.FindBlocksByHeight(height)
-	for _, b := range blocks {
-		blockHash := b.Hash()
-		stateRoot := b.StateHash
-		blockStoreView := state.NewStoreView(height, stateRoot, db)
-		if blockStoreView == nil { // might have been pruned
-			return fmt.Errorf("the VCP for height %v does not exists, it might have been pruned", height)
+	result.Trios = []string{}
+	for _, height := range sv.GetStakeTransactionHeightList().Heights {
+		if height <= uint64(args.Start) || height == core.GenesisBlockHeight {
+			continue
 		}
-		vcp := blockStoreView.GetValidatorCandidatePool()
-		hl := blockStoreView.GetStakeTransactionHeightList()
-		blockHashVcpPairs = append(blockHashVcpPairs, BlockHashVcpPair{
-			BlockHash:  blockHash,
-			Vcp:        vcp,
-			HeightList: hl,
-		})
+		if len(result.Trios) >= maxValidatorSetProofs {
+			break
+		}
+		trio, err := snapshot.GetProofTrio(t.chain, db, height)
+		if err != nil {
+			// The child or grandchild of the latest stake changes might not be finalized yet
+			logger.Debugf("Failed to prove the validator set change at height %v: %v", height, err)
+			break
+		}
+		encoded, err := encodeRLP(trio)
+		if err != nil {
+			return err
+		}
+		result.Trios = append(result.Trios, encoded)
 	}
-
-	result.BlockHashVcpPairs = blockHashVcpPairs
-
 	return nil
 }
 
-// ------------------------------ GetGcp -----------------------------------
-
-type GetGcpByHeightArgs struct {
-	Height common.JSONUint64 `json:"height"`
-}
-
-type GetGcpResult struct {
-	BlockHashGcpPairs []BlockHashGcpPair
+type GetHeaderProofArgs struct {
+	Height common.JSONUint64 `json:"height"` // the latest provable header if not specified
 }
 
-type BlockHashGcpPair struct {
-	BlockHash common.Hash
-	Gcp       *core.GuardianCandidatePool
+type GetHeaderProofResult struct {
+	Height common.JSONUint64 `json:"height"`
+	Hash   common.Hash       `json:"hash"`
+	Trio   string            `json:"trio"` // the RLP encoded block trio proving the finalization of the block
 }
 
-func (t *PandoRPCService) GetGcpByHeight(args *GetGcpByHeightArgs, result *GetGcpResult) (err error) {
-	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+// GetHeaderProof returns the proof of the finalization of the block at the given height, i.e. the block
+// header along with the headers of its finalized child and grandchild, the latter carrying the votes on
+// the child.
+func (t *PandoRPCService) GetHeaderProof(args *GetHeaderProofArgs, result *GetHeaderProofResult) (err error) {
+	trio, err := t.getProvableBlockTrio(uint64(args.Height))
 	if err != nil {
 		return err
 	}
 
-	db := deliveredView.GetDB()
-	height := uint64(args.Height)
+	result.Height = common.JSONUint64(trio.First.Header.Height)
+	result.Hash = trio.First.Header.Hash()
+	result.Trio, err = encodeRLP(trio)
+	return err
+}
 
-	blockHashGcpPairs := []BlockHashGcpPair{}
-	blocks := t.chain.FindBlocksByHeight(height)
-	for _, b := range blocks {
-		blockHash := b.Hash()
-		stateRoot := b.StateHash
-		blockStoreView := state.NewStoreView(height, stateRoot, db)
-		if blockStoreView == nil { // might have been pruned
-			return fmt.Errorf("the GCP for height %v does not exists, it might have been pruned", height)
+// getProvableBlockTrio returns the block trio proving the finalization of the block at the given
+// height, or of the latest provable block if the height is 0.
+func (t *PandoRPCService) getProvableBlockTrio(height uint64) (*core.SnapshotBlockTrio, error) {
+	db := t.ledger.State().DB()
+	if height != 0 {
+		return snapshot.GetFinalizedBlockTrio(t.chain, db, height)
+	}
+
+	lastFinalized := t.consensus.GetLastFinalizedBlock()
+	for depth := uint64(2); depth <= headerProofSearchDepth && depth <= lastFinalized.Height; depth++ {
+		trio, err := snapshot.GetFinalizedBlockTrio(t.chain, db, lastFinalized.Height-depth)
+		if err == nil {
+			return trio, nil
 		}
-		gcp := blockStoreView.GetGuardianCandidatePool()
-		blockHashGcpPairs = append(blockHashGcpPairs, BlockHashGcpPair{
-			BlockHash: blockHash,
-			Gcp:       gcp,
-		})
 	}
+	return nil, fmt.Errorf("No provable header below the last finalized block %v", lastFinalized.Height)
+}
 
-	result.BlockHashGcpPairs = blockHashGcpPairs
+type GetTransactionProofArgs struct {
+	Hash string `json:"hash"`
+}
 
-	return nil
+type GetTransactionProofResult struct {
+	TxHash      common.Hash       `json:"hash"`
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	Index       common.JSONUint64 `json:"index"`
+	Transaction string            `json:"transaction"` // the raw transaction
+	Proof       []string          `json:"proof"`       // the transaction trie nodes from the TxHash of the block to the transaction
 }
 
-// ------------------------------ GetGuardianKey -----------------------------------
+// GetTransactionProof returns the Merkle proof of the inclusion of the finalized transaction in its
+// block, which verifies against the TxHash of the block header.
+func (t *PandoRPCService) GetTransactionProof(args *GetTransactionProofArgs, result *GetTransactionProofResult) (err error) {
+	if args.Hash == "" {
+		return errors.New("Transanction hash must be specified")
+	}
+	hash := common.HexToHash(args.Hash)
 
-type GetGuardianInfoArgs struct{}
+	raw, block, found := t.chain.FindTxByHash(hash)
+	if !found {
+		return fmt.Errorf("Transaction %v not found", hash.Hex())
+	}
+	if !block.Status.IsFinalized() {
+		return fmt.Errorf("Transaction %v is not finalized yet", hash.Hex())
+	}
+	index := -1
+	for i, tx := range block.Txs {
+		if bytes.Equal(tx, raw) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("Transaction %v not found in block %v", hash.Hex(), block.Hash().Hex())
+	}
 
-type GetGuardianInfoResult struct {
-	BLSPubkey string
-	BLSPop    string
-	Address   string
-	Signature string
+	pc := &proofCollector{}
+	if err := core.ProveTx(block.Txs, index, pc); err != nil {
+		return fmt.Errorf("Failed to prove the transaction: %v", err)
+	}
+
+	result.TxHash = hash
+	result.BlockHash = block.Hash()
+	result.BlockHeight = common.JSONUint64(block.Height)
+	result.Index = common.JSONUint64(index)
+	result.Transaction = hexutil.Encode(raw)
+	result.Proof = encodeProof(pc.nodes)
+	return nil
 }
 
-func (t *PandoRPCService) GetGuardianInfo(args *GetGuardianInfoArgs, result *GetGuardianInfoResult) (err error) {
-	privKey := t.consensus.PrivateKey()
-	blsKey, err := bls.GenKey(strings.NewReader(common.Bytes2Hex(privKey.PublicKey().ToBytes())))
-	if err != nil {
-		return fmt.Errorf("Failed to get BLS key: %v", err.Error())
-	}
+// proofCollector collects the trie nodes of a Merkle proof
+type proofCollector struct {
+	nodes [][]byte
+}
 
-	result.Address = privKey.PublicKey().Address().Hex()
-	result.BLSPubkey = hex.EncodeToString(blsKey.PublicKey().ToBytes())
-	popBytes := blsKey.PopProve().ToBytes()
-	result.BLSPop = hex.EncodeToString(popBytes)
+func (pc *proofCollector) Put(key []byte, value []byte) error {
+	pc.nodes = append(pc.nodes, common.CopyBytes(value))
+	return nil
+}
 
-	sig, err := privKey.Sign(popBytes)
+func encodeRLP(val interface{}) (string, error) {
+	raw, err := rlp.EncodeToBytes(val)
 	if err != nil {
-		return fmt.Errorf("Failed to generate signature: %v", err.Error())
+		return "", err
 	}
-	result.Signature = hex.EncodeToString(sig.ToBytes())
+	return hexutil.Encode(raw), nil
+}
 
-	return nil
+func encodeProof(proof [][]byte) []string {
+	encoded := make([]string, len(proof))
+	for i, node := range proof {
+		encoded[i] = hexutil.Encode(node)
+	}
+	return encoded
 }
 
 // ------------------------------ Utils ------------------------------
 
-func getTxType(tx types.Tx) byte {
-	t := byte(0x0)
-	switch tx.(type) {
-	case *types.CoinbaseTx:
-		t = TxTypeCoinbase
-	case *types.SlashTx:
-		t = TxTypeSlash
-	case *types.SendTx:
-		t = TxTypeSend
-	case *types.RametronStakeTx:
-		t = TxTypeRametronStake
-	case *types.ReserveFundTx:
-		t = TxTypeReserveFund
-	case *types.ReleaseFundTx:
-		t = TxTypeReleaseFund
-	case *types.ServicePaymentTx:
-		t = TxTypeServicePayment
-	case *types.SplitRuleTx:
-		t = TxTypeSplitRule
-	case *types.SmartContractTx:
-		t = TxTypeSmartContract
-	case *types.DepositStakeTx:
-		t = TxTypeDepositStake
-	case *types.WithdrawStakeTx:
-		t = TxTypeWithdrawStake
-	case *types.DepositStakeTxV2:
-		t = TxTypeDepositStakeTxV2
-	}
-
-	return t
+func (t *PandoRPCService) getBlockResultInner(block *core.ExtendedBlock) (*GetBlockResultInner, error) {
+	result := &GetBlockResultInner{}
+	result.ChainID = block.ChainID
+	result.Epoch = common.JSONUint64(block.Epoch)
+	result.Height = common.JSONUint64(block.Height)
+	result.Parent = block.Parent
+	result.TxHash = block.TxHash
+	result.StateHash = block.StateHash
+	result.Timestamp = (*common.JSONBig)(block.Timestamp)
+	result.Proposer = block.Proposer
+	result.Children = block.Children
+	result.Status = block.Status
+	result.HCC = block.HCC
+	result.GuardianVotes = block.GuardianVotes
+	result.Randomness = block.Randomness
+
+	result.Hash = block.Hash()
+
+	// Parse and fulfill Txs.
+	for _, txBytes := range block.Txs {
+		tx, err := types.TxFromBytes(txBytes)
+		if err != nil {
+			return nil, err
+		}
+		hash := crypto.Keccak256Hash(txBytes)
+
+		tp := getTxType(tx)
+		txw := Tx{
+			Tx:   tx,
+			Hash: hash,
+			Type: tp,
+		}
+
+		receipt, found := t.chain.FindTxReceiptByHash(hash)
+		if found {
+			txw.Receipt = receipt
+		}
+
+		result.Txs = append(result.Txs, txw)
+	}
+	return result, nil
 }
 
+func getTxType(tx types.Tx) byte {
+	t, err := types.GetTxType(tx)
+	if err != nil {
+		return 0x0
+	}
+	return byte(t)
+}
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLogsByBlockHash(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	transfer := common.HexToHash("0xaa")
+	approval := common.HexToHash("0xbb")
+
+	sctx := &types.SmartContractTx{
+		From:     types.NewTxInput(common.HexToAddress("0x1"), types.NewCoins(0, 0), 1),
+		To:       types.TxOutput{Address: contract},
+		GasLimit: 21000,
+		GasPrice: big.NewInt(4000000000000),
+	}
+	raw, err := types.TxToBytes(sctx)
+	require.Nil(err)
+
+	chain := blockchain.CreateTestChain()
+	chain.AddTxReceipt(sctx, []*types.Log{
+		{Address: contract, Topics: []common.Hash{transfer}},
+		{Address: contract, Topics: []common.Hash{approval}},
+	}, common.Bytes{}, common.Address{}, 21000, nil)
+	block := core.CreateTestBlock("b1", "")
+	block.Height = 10
+	block.Txs = []common.Bytes{raw}
+	block.UpdateHash()
+	_, err = chain.AddBlock(block)
+	require.Nil(err)
+	service := &PandoRPCService{chain: chain}
+
+	args := &GetLogsArgs{}
+	require.Nil(json.Unmarshal([]byte(`{"block_hash":"`+block.Hash().Hex()+`","address":"`+contract.Hex()+`","topics":["0xbb"]}`), args))
+	result := &GetLogsResult{}
+	require.Nil(service.GetLogs(args, result))
+	require.Equal(1, len(result.Logs))
+	log := result.Logs[0]
+	assert.Equal([]common.Hash{approval}, log.Topics)
+	assert.Equal(crypto.Keccak256Hash(raw), log.TxHash)
+	assert.Equal(block.Hash(), log.BlockHash)
+	assert.Equal(common.JSONUint64(10), log.BlockHeight)
+	assert.Equal(common.JSONUint64(1), log.LogIndex)
+
+	// The block hash cannot be combined with a block range
+	assert.NotNil(json.Unmarshal([]byte(`{"block_hash":"`+block.Hash().Hex()+`","from_block":"1"}`), &GetLogsArgs{}))
+	assert.NotNil(service.GetLogs(&GetLogsArgs{BlockHash: &common.Hash{}}, &GetLogsResult{}))
+}
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/snapshot"
+)
+
+// ------------------------------- ProposeRestartManifest -----------------------------------
+
+type ProposeRestartManifestArgs struct {
+	Height         common.JSONUint64 `json:"height"` // 0 for the last finalized block
+	Reason         string            `json:"reason"`
+	ExportSnapshot bool              `json:"export_snapshot"` // export the snapshot at the block, and reference its hash
+	Config         string            `json:"config"`          // the snapshot is exported to the backup/snapshot dir of the config
+}
+
+type ProposeRestartManifestResult struct {
+	Manifest     *core.RestartManifest `json:"manifest"`
+	SnapshotFile string                `json:"snapshot_file"`
+}
+
+// ProposeRestartManifest creates an unsigned restart manifest at a finalized block of the node, to
+// be signed by the validators to restart the chain from the block after a halt
+func (t *PandoRPCService) ProposeRestartManifest(args *ProposeRestartManifestArgs, result *ProposeRestartManifestResult) error {
+	var block *core.ExtendedBlock
+	if args.Height == 0 {
+		block = t.consensus.GetLastFinalizedBlock()
+	} else {
+		for _, b := range t.chain.FindBlocksByHeight(uint64(args.Height)) {
+			if b.Status.IsFinalized() {
+				block = b
+				break
+			}
+		}
+		if block == nil {
+			return fmt.Errorf("No finalized block at height %v", args.Height)
+		}
+	}
+
+	manifest := &core.RestartManifest{
+		ChainID:   t.chain.ChainID,
+		Height:    block.Height,
+		BlockHash: block.Hash(),
+		StateHash: block.StateHash,
+		Reason:    args.Reason,
+	}
+
+	if args.ExportSnapshot {
+		if args.Config == "" {
+			return fmt.Errorf("The config dir to export the snapshot to is not specified")
+		}
+		snapshotDir := path.Join(args.Config, "backup", "snapshot")
+		if err := os.MkdirAll(snapshotDir, os.ModePerm); err != nil {
+			return err
+		}
+		snapshotFile, err := snapshot.ExportSnapshot(t.ledger.State().DB(), t.consensus, t.chain, snapshotDir, block.Height)
+		if err != nil {
+			return err
+		}
+		manifest.SnapshotHash, err = core.HashSnapshotFile(snapshotFile)
+		if err != nil {
+			return err
+		}
+		result.SnapshotFile = snapshotFile
+	}
+
+	result.Manifest = manifest
+	return nil
+}
+
+// ------------------------------- VerifyRestartManifest -----------------------------------
+
+type VerifyRestartManifestArgs struct {
+	Manifest *core.RestartManifest `json:"manifest"`
+}
+
+type VerifyRestartManifestResult struct {
+	ManifestHash      common.Hash      `json:"manifest_hash"`
+	SignedStake       *common.JSONBig  `json:"signed_stake"`
+	TotalStake        *common.JSONBig  `json:"total_stake"`
+	Quorum            bool             `json:"quorum"`
+	MissingValidators []common.Address `json:"missing_validators"` // the validators which have not signed yet
+	Applied           bool             `json:"applied"`            // whether the node restarted with the manifest
+}
+
+// VerifyRestartManifest checks the manifest against the chain of the node, and reports the stake of
+// the validators which signed it
+func (t *PandoRPCService) VerifyRestartManifest(args *VerifyRestartManifestArgs, result *VerifyRestartManifestResult) error {
+	m := args.Manifest
+	if m == nil {
+		return fmt.Errorf("The manifest is not specified")
+	}
+
+	_, validatorSet, err := t.consensus.GetRestartValidatorSet(m)
+	if err != nil {
+		return err
+	}
+	signedStake, err := m.SignedStake(validatorSet)
+	if err != nil {
+		return err
+	}
+
+	signed := make(map[common.Address]bool)
+	for _, s := range m.Signatures {
+		signed[s.Validator] = true
+	}
+	result.MissingValidators = []common.Address{}
+	for _, v := range validatorSet.Validators() {
+		if !signed[v.Address] {
+			result.MissingValidators = append(result.MissingValidators, v.Address)
+		}
+	}
+
+	result.ManifestHash = m.Hash()
+	result.SignedStake = (*common.JSONBig)(signedStake)
+	result.TotalStake = (*common.JSONBig)(new(big.Int).Set(validatorSet.TotalStake()))
+	result.Quorum = m.Verify(validatorSet) == nil
+	result.Applied = t.consensus.State().GetAppliedRestartManifest() == result.ManifestHash
+	return nil
+}
@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/pandotoken/pando/scheduler"
+)
+
+var errSchedulerDisabled = errors.New("Scheduler is not enabled")
+
+// ------------------------------- GetScheduledTasks -----------------------------------
+
+type GetScheduledTasksArgs struct {
+}
+
+type GetScheduledTasksResult struct {
+	Tasks []scheduler.TaskStatus `json:"tasks"`
+}
+
+// GetScheduledTasks returns the status of the periodic tasks run by the node
+func (t *PandoRPCService) GetScheduledTasks(args *GetScheduledTasksArgs, result *GetScheduledTasksResult) (err error) {
+	if t.scheduler == nil {
+		return errSchedulerDisabled
+	}
+	result.Tasks = t.scheduler.Tasks()
+	return nil
+}
+
+// ------------------------------- GetTaskRunHistory -----------------------------------
+
+type GetTaskRunHistoryArgs struct {
+	Task  string `json:"task"`  // empty for all tasks
+	Count int    `json:"count"` // 0 for all the retained runs
+}
+
+type GetTaskRunHistoryResult struct {
+	Runs []*scheduler.TaskRun `json:"runs"`
+}
+
+// GetTaskRunHistory returns the most recent runs of the periodic tasks, the most recent first
+func (t *PandoRPCService) GetTaskRunHistory(args *GetTaskRunHistoryArgs, result *GetTaskRunHistoryResult) (err error) {
+	if t.scheduler == nil {
+		return errSchedulerDisabled
+	}
+	result.Runs = t.scheduler.History(args.Task, args.Count)
+	return nil
+}
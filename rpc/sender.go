@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/ybbus/jsonrpc"
+)
+
+// RPCCaller is implemented by the JSON-RPC clients, e.g. *jsonrpc.RPCClient
+type RPCCaller interface {
+	Call(method string, params ...interface{}) (*jsonrpc.RPCResponse, error)
+}
+
+// RetryPolicy configures how TxSender retries failed broadcasts
+type RetryPolicy struct {
+	MaxAttempts    int           // including the first attempt
+	InitialBackoff time.Duration // doubled after each retry
+	MaxBackoff     time.Duration
+	Resign         bool // rebuild the transaction with a fresh sequence on sequence errors
+}
+
+// DefaultRetryPolicy is the retry policy used by pandocli
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 1 * time.Second,
+	MaxBackoff:     30 * time.Second,
+	Resign:         true,
+}
+
+// NoRetryPolicy broadcasts the transaction only once
+var NoRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// TxBuilder returns the signed raw transaction. It is called with resign set to true
+// after the previous attempt was rejected for its sequence, in which case the builder
+// should fetch the current sequence of the account and sign the transaction again.
+type TxBuilder func(resign bool) (common.Bytes, error)
+
+// TxSender broadcasts transactions, retrying according to its retry policy
+type TxSender struct {
+	client RPCCaller
+	policy RetryPolicy
+	sleep  func(time.Duration)
+}
+
+// NewTxSender creates a new instance of TxSender
+func NewTxSender(client RPCCaller, policy RetryPolicy) *TxSender {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	return &TxSender{
+		client: client,
+		policy: policy,
+		sleep:  time.Sleep,
+	}
+}
+
+// Send builds and broadcasts the transaction until it is accepted, or the error is
+// permanent, or the retry policy is exhausted. In the async mode, a transaction
+// already in the mempool of the node, e.g. accepted by an attempt whose response was
+// lost, is treated as successfully broadcasted. The returned error, if any, is a
+// *BroadcastError unless building the transaction failed.
+func (s *TxSender) Send(build TxBuilder, async bool) (*BroadcastRawTransactionResult, error) {
+	raw, err := build(false)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := s.policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := s.broadcast(raw, async)
+		if err == nil {
+			return result, nil
+		}
+		if async && err.Category == ErrorCategoryPending {
+			return &BroadcastRawTransactionResult{TxHash: crypto.Keccak256Hash(raw).Hex()}, nil
+		}
+		if attempt >= s.policy.MaxAttempts {
+			return nil, err
+		}
+
+		switch {
+		case err.Category == ErrorCategoryResign && s.policy.Resign:
+			rebuilt, buildErr := build(true)
+			if buildErr != nil {
+				return nil, buildErr
+			}
+			raw = rebuilt
+		case err.Retryable():
+			delay := backoff
+			if retryAfter := time.Duration(err.RetryAfter) * time.Second; retryAfter > delay {
+				delay = retryAfter
+			}
+			s.sleep(delay)
+			backoff *= 2
+			if s.policy.MaxBackoff > 0 && backoff > s.policy.MaxBackoff {
+				backoff = s.policy.MaxBackoff
+			}
+		default:
+			return nil, err
+		}
+	}
+}
+
+func (s *TxSender) broadcast(raw common.Bytes, async bool) (*BroadcastRawTransactionResult, *BroadcastError) {
+	method := "pando.BroadcastRawTransaction"
+	if async {
+		method = "pando.BroadcastRawTransactionAsync"
+	}
+	res, err := s.client.Call(method, BroadcastRawTransactionArgs{TxBytes: hex.EncodeToString(raw)})
+	if err != nil {
+		return nil, ParseBroadcastError(err)
+	}
+	if res.Error != nil {
+		return nil, ParseBroadcastError(res.Error)
+	}
+	result := &BroadcastRawTransactionResult{}
+	if err := res.GetObject(result); err != nil {
+		// The node has accepted the transaction
+		return nil, &BroadcastError{
+			ErrorData: ErrorData{Category: ErrorCategoryPending},
+			Message:   fmt.Sprintf("Failed to parse server response: %v", err),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/result"
+	"github.com/pandotoken/pando/mempool"
+	"github.com/pandotoken/pando/rpc/lib/rpc-codec/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ybbus/jsonrpc"
+)
+
+// mockCaller replies to the broadcast calls with the given errors in order, and
+// succeeds once they are used up
+type mockCaller struct {
+	errs  []error
+	calls []string
+}
+
+func (c *mockCaller) Call(method string, params ...interface{}) (*jsonrpc.RPCResponse, error) {
+	args := params[0].(BroadcastRawTransactionArgs)
+	c.calls = append(c.calls, args.TxBytes)
+
+	var err error
+	if len(c.errs) > 0 {
+		err, c.errs = c.errs[0], c.errs[1:]
+	}
+	if err == nil {
+		return &jsonrpc.RPCResponse{Result: map[string]interface{}{"hash": "0x01"}}, nil
+	}
+	rpcErr, ok := err.(*jsonrpc2.Error)
+	if !ok {
+		return nil, err // transport error
+	}
+
+	// Decode the error the way the clients do
+	res := &jsonrpc.RPCResponse{}
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":1,"error":`+rpcErr.Error()+`}`), res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func serverError(err error) error {
+	return newBroadcastError(err)
+}
+
+func TestParseBroadcastError(t *testing.T) {
+	assert := assert.New(t)
+
+	caller := &mockCaller{}
+	parse := func(err error) *BroadcastError {
+		caller.errs = []error{err}
+		_, broadcastErr := NewTxSender(caller, NoRetryPolicy).broadcast(common.Bytes{0x01}, true)
+		return broadcastErr
+	}
+
+	err := parse(serverError(mempool.MempoolFullError))
+	assert.True(err.Retryable())
+	assert.Equal(retryAfterMempoolFull, err.RetryAfter)
+	assert.Equal(mempool.MempoolFullError.Error(), err.Message)
+
+	err = parse(serverError(&mempool.TxScreeningError{Code: result.CodeInvalidSequence, Message: "bad sequence"}))
+	assert.Equal(ErrorCategoryResign, err.Category)
+	assert.Equal(result.CodeInvalidSequence, err.Code)
+
+	err = parse(serverError(&mempool.TxScreeningError{Code: result.CodeInsufficientFund, Message: "no funds"}))
+	assert.Equal(ErrorCategoryPermanent, err.Category)
+	assert.Equal(result.CodeInsufficientFund, err.Code)
+
+	err = parse(serverError(mempool.TxBadSignatureError))
+	assert.Equal(ErrorCategoryPermanent, err.Category)
+
+	err = parse(serverError(mempool.DuplicateTxError))
+	assert.Equal(ErrorCategoryPending, err.Category)
+
+	err = parse(serverError(errTxTimeout))
+	assert.Equal(ErrorCategoryPending, err.Category)
+
+	err = parse(errors.New("connection refused"))
+	assert.True(err.Retryable())
+
+	// Nodes without the retry hints
+	err = ParseBroadcastError(&jsonrpc.RPCError{Code: -32000, Message: "some error"})
+	assert.Equal(ErrorCategoryPermanent, err.Category)
+	assert.Equal("some error", err.Message)
+}
+
+func TestTxSenderRetry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	builds := []bool{}
+	build := func(resign bool) (common.Bytes, error) {
+		builds = append(builds, resign)
+		return common.Bytes{byte(len(builds))}, nil
+	}
+	newSender := func(caller RPCCaller, policy RetryPolicy) (*TxSender, *[]time.Duration) {
+		builds = []bool{}
+		sleeps := []time.Duration{}
+		sender := NewTxSender(caller, policy)
+		sender.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+		return sender, &sleeps
+	}
+	policy := RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, Resign: true}
+
+	// Retryable errors honour the retry hint and back off exponentially
+	caller := &mockCaller{errs: []error{
+		errors.New("connection reset"),
+		errors.New("connection reset"),
+		serverError(mempool.FastsyncSkipTxError),
+	}}
+	sender, sleeps := newSender(caller, policy)
+	res, err := sender.Send(build, false)
+	require.Nil(err)
+	assert.Equal("0x01", res.TxHash)
+	assert.Equal(4, len(caller.calls))
+	assert.Equal([]time.Duration{time.Second, 2 * time.Second, time.Duration(retryAfterFastsync) * time.Second}, *sleeps)
+	assert.Equal([]bool{false}, builds)
+
+	// Sequence errors trigger a rebuild
+	caller = &mockCaller{errs: []error{
+		serverError(&mempool.TxScreeningError{Code: result.CodeInvalidSequence, Message: "bad sequence"}),
+	}}
+	sender, sleeps = newSender(caller, policy)
+	_, err = sender.Send(build, false)
+	require.Nil(err)
+	assert.Equal([]bool{false, true}, builds)
+	assert.Equal([]string{"01", "02"}, caller.calls)
+	assert.Equal(0, len(*sleeps))
+
+	// Permanent errors are not retried
+	caller = &mockCaller{errs: []error{serverError(mempool.TxBadSignatureError)}}
+	sender, _ = newSender(caller, policy)
+	_, err = sender.Send(build, false)
+	require.NotNil(err)
+	assert.Equal(ErrorCategoryPermanent, err.(*BroadcastError).Category)
+	assert.Equal(1, len(caller.calls))
+
+	// The retries are bounded
+	caller = &mockCaller{errs: []error{
+		serverError(mempool.MempoolFullError),
+		serverError(mempool.MempoolFullError),
+		serverError(mempool.MempoolFullError),
+		serverError(mempool.MempoolFullError),
+	}}
+	sender, _ = newSender(caller, policy)
+	_, err = sender.Send(build, false)
+	require.NotNil(err)
+	assert.True(err.(*BroadcastError).Retryable())
+	assert.Equal(4, len(caller.calls))
+
+	// A duplicate after a lost response means the async broadcast went through
+	caller = &mockCaller{errs: []error{
+		errors.New("connection reset"),
+		serverError(mempool.DuplicateTxError),
+	}}
+	sender, _ = newSender(caller, policy)
+	res, err = sender.Send(build, true)
+	require.Nil(err)
+	assert.NotEmpty(res.TxHash)
+}
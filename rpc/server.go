@@ -24,7 +24,9 @@ import (
 	"github.com/pandotoken/pando/dispatcher"
 	"github.com/pandotoken/pando/ledger"
 	"github.com/pandotoken/pando/mempool"
+	"github.com/pandotoken/pando/p2p"
 	"github.com/pandotoken/pando/rpc/lib/rpc-codec/jsonrpc2"
+	"github.com/pandotoken/pando/scheduler"
 	"golang.org/x/net/netutil"
 	"golang.org/x/net/websocket"
 )
@@ -37,6 +39,13 @@ type PandoRPCService struct {
 	dispatcher *dispatcher.Dispatcher
 	chain      *blockchain.Chain
 	consensus  *consensus.ConsensusEngine
+	scheduler  *scheduler.Scheduler
+	network    p2p.Network
+
+	heartbeatPool *scheduler.HeartbeatPool // nil if the node does not attest the rametron heartbeats
+
+	subscriptions *subscriptionHub
+	callCache     *callCache // nil if disabled
 
 	// Life cycle
 	wg      *sync.WaitGroup
@@ -60,7 +69,10 @@ func NewPandoRPCServer(mempool *mempool.Mempool, ledger *ledger.Ledger, dispatch
 	chain *blockchain.Chain, consensus *consensus.ConsensusEngine) *PandoRPCServer {
 	t := &PandoRPCServer{
 		PandoRPCService: &PandoRPCService{
-			wg: &sync.WaitGroup{},
+			wg:            &sync.WaitGroup{},
+			subscriptions: newSubscriptionHub(viper.GetInt(common.CfgRPCMaxSubscriptionsPerConnection)),
+			callCache: newCallCache(viper.GetInt(common.CfgRPCCallCacheSize),
+				viper.GetDuration(common.CfgRPCCallCacheTTLSecs)*time.Second),
 		},
 	}
 
@@ -77,10 +89,13 @@ func NewPandoRPCServer(mempool *mempool.Mempool, ledger *ledger.Ledger, dispatch
 
 	t.router = mux.NewRouter()
 	t.router.Handle("/", &defaultHTTPHandler{})
-	t.router.Handle("/rpc", corsMiddleware(TimeoutHandler(jsonrpc2.HTTPHandler(s), viper.GetDuration(common.CfgRPCTimeoutSecs)*time.Second, "")))
+	t.router.Handle("/rpc", corsMiddleware(TimeoutHandler(formatMiddleware(jsonrpc2.HTTPHandler(s)), viper.GetDuration(common.CfgRPCTimeoutSecs)*time.Second, "")))
 	t.router.Handle("/ws", websocket.Handler(func(ws *websocket.Conn) {
 		s.ServeCodec(jsonrpc2.NewServerCodec(ws, s))
 	}))
+	t.router.Handle("/changefeed", websocket.Handler(t.serveChangefeed))
+	t.router.Handle("/subscribe", websocket.Handler(t.subscriptions.serve))
+	t.router.Handle("/eth", corsMiddleware(TimeoutHandler(http.HandlerFunc(t.serveEth), viper.GetDuration(common.CfgRPCTimeoutSecs)*time.Second, "")))
 
 	t.server = &http.Server{
 		Handler: t.router,
@@ -91,6 +106,23 @@ func NewPandoRPCServer(mempool *mempool.Mempool, ledger *ledger.Ledger, dispatch
 	return t
 }
 
+// SetScheduler sets the scheduler whose tasks are exposed through the RPC, nil if the
+// scheduler is disabled.
+func (t *PandoRPCServer) SetScheduler(scheduler *scheduler.Scheduler) {
+	t.scheduler = scheduler
+}
+
+// SetHeartbeatPool sets the pool the heartbeats of the Rametron nodes submitted through the RPC
+// are added to, nil if the node does not attest the heartbeats.
+func (t *PandoRPCServer) SetHeartbeatPool(pool *scheduler.HeartbeatPool) {
+	t.heartbeatPool = pool
+}
+
+// SetNetwork sets the p2p network whose sentry topology is exposed through the RPC.
+func (t *PandoRPCServer) SetNetwork(network p2p.Network) {
+	t.network = network
+}
+
 // Start creates the main goroutine.
 func (t *PandoRPCServer) Start(ctx context.Context) {
 	c, cancel := context.WithCancel(ctx)
@@ -102,27 +134,41 @@ func (t *PandoRPCServer) Start(ctx context.Context) {
 
 	t.wg.Add(1)
 	go t.txCallback()
+
+	t.wg.Add(1)
+	go t.pollSubscriptionEvents()
 }
 
 func (t *PandoRPCServer) mainLoop() {
 	defer t.wg.Done()
 
-	go t.serve()
+	// The listeners passed by the service manager take precedence over the configured ones
+	tcpListener, ipcListener := activatedListeners()
+	if tcpListener != nil || viper.GetBool(common.CfgRPCTCPEnabled) {
+		go t.serve(tcpListener)
+	}
+	if ipcListener != nil || viper.GetBool(common.CfgRPCIPCEnabled) {
+		go t.serveIPC(ipcListener)
+	}
 
 	<-t.ctx.Done()
 	t.stopped = true
 	t.server.Shutdown(t.ctx)
 }
 
-func (t *PandoRPCServer) serve() {
-	address := viper.GetString(common.CfgRPCAddress)
-	port := viper.GetString(common.CfgRPCPort)
-	l, err := net.Listen("tcp", address+":"+port)
-	if err != nil {
-		logger.WithFields(log.Fields{"error": err}).Fatal("Failed to create listener")
-	} else {
-		logger.WithFields(log.Fields{"address": address, "port": port}).Info("RPC server started")
+// serve serves the RPC over TCP. It listens on the configured address and port unless a
+// listener is passed by the service manager.
+func (t *PandoRPCServer) serve(l net.Listener) {
+	if l == nil {
+		address := viper.GetString(common.CfgRPCAddress)
+		port := viper.GetString(common.CfgRPCPort)
+		var err error
+		l, err = net.Listen("tcp", address+":"+port)
+		if err != nil {
+			logger.WithFields(log.Fields{"error": err}).Fatal("Failed to create listener")
+		}
 	}
+	logger.WithFields(log.Fields{"address": l.Addr().String()}).Info("RPC server started")
 	defer l.Close()
 
 	ll := netutil.LimitListener(l, viper.GetInt(common.CfgRPCMaxConnections))
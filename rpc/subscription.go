@@ -0,0 +1,672 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/state"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc/lib/rpc-codec/jsonrpc2"
+	"golang.org/x/net/websocket"
+)
+
+// Subscription kinds
+const (
+	SubscriptionNewHeads            = "newHeads"
+	SubscriptionFinalizedBlocks     = "finalizedBlocks"
+	SubscriptionFinalizedHeaders    = "finalizedHeaders"
+	SubscriptionLogs                = "logs"
+	SubscriptionPendingTransactions = "pendingTransactions"
+	SubscriptionReserveFundPayments = "reserveFundPayments"
+	SubscriptionSplitRuleExpiries   = "splitRuleExpiries"
+)
+
+// Methods of the subscription endpoint, and of the notifications it pushes
+const (
+	subscribeMethod    = "pando_subscribe"
+	unsubscribeMethod  = "pando_unsubscribe"
+	subscriptionMethod = "pando_subscription"
+)
+
+// subscriptionPollInterval is how often the new heads and pending transactions are checked
+const subscriptionPollInterval = 500 * time.Millisecond
+
+// subscriptionQueueSize is the number of messages buffered per connection. The connection
+// is closed if the subscriber cannot keep up.
+const subscriptionQueueSize = 256
+
+// SubscriptionBlockHeader is the payload of the newHeads and finalizedBlocks notifications
+type SubscriptionBlockHeader struct {
+	*core.BlockHeader
+	Hash common.Hash `json:"hash"`
+}
+
+// SubscriptionFinalizedHeader is the payload of the finalizedHeaders notifications. Commit is the
+// RLP encoded core.HeaderCommit of the block, i.e. its header and the commit certificate of the
+// validators, with the votes aggregated into a single BLS signature where the validators allow it.
+type SubscriptionFinalizedHeader struct {
+	Height common.JSONUint64 `json:"height"`
+	Hash   common.Hash       `json:"hash"`
+	Commit string            `json:"commit"`
+}
+
+// SubscriptionLog is the payload of the logs notifications, and the log returned by GetLogs
+type SubscriptionLog struct {
+	*types.Log
+	TxHash      common.Hash       `json:"transaction_hash"`
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	LogIndex    common.JSONUint64 `json:"log_index"` // index of the log in the transaction
+}
+
+// SubscriptionReserveFundPayment is the payload of the reserveFundPayments notifications, pushed for
+// each service payment of a finalized block settled against a reserve fund
+type SubscriptionReserveFundPayment struct {
+	TxHash          common.Hash       `json:"transaction_hash"`
+	BlockHash       common.Hash       `json:"block_hash"`
+	BlockHeight     common.JSONUint64 `json:"block_height"`
+	Source          common.Address    `json:"source"`
+	Target          common.Address    `json:"target"`
+	ReserveSequence common.JSONUint64 `json:"reserve_sequence"`
+	PaymentSequence common.JSONUint64 `json:"payment_sequence"`
+	ResourceID      string            `json:"resource_id"`
+	Amount          types.Coins       `json:"amount"`
+	UsedFund        types.Coins       `json:"used_fund"`      // cumulative spend of the fund after the payment
+	RemainingFund   types.Coins       `json:"remaining_fund"` // remaining balance of the fund after the payment
+}
+
+// SubscriptionSplitRuleExpiry is the payload of the splitRuleExpiries notifications, pushed for each
+// split rule which expires at a finalized block without having been renewed
+type SubscriptionSplitRuleExpiry struct {
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"` // first height the rule no longer applies at
+	SplitRule   *types.SplitRule  `json:"split_rule"`
+}
+
+// LogFilter selects the logs pushed to a logs subscription. A log matches if it is emitted
+// by one of the addresses, and each of its topics matches one of the hashes at the same
+// position in Topics. An empty address list or topic position matches anything.
+type LogFilter struct {
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+type logFilterJSON struct {
+	Address json.RawMessage   `json:"address"`
+	Topics  []json.RawMessage `json:"topics"`
+}
+
+// UnmarshalJSON accepts a single value or an array for the address and each topic
+// position, as in eth_subscribe
+func (f *LogFilter) UnmarshalJSON(data []byte) error {
+	var raw logFilterJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var addresses []string
+	if err := unmarshalOneOrMany(raw.Address, &addresses); err != nil {
+		return fmt.Errorf("Invalid address filter: %v", err)
+	}
+	f.Addresses = nil
+	for _, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("Invalid address filter: %v", address)
+		}
+		f.Addresses = append(f.Addresses, common.HexToAddress(address))
+	}
+	f.Topics = nil
+	for _, rawTopics := range raw.Topics {
+		var topics []string
+		if err := unmarshalOneOrMany(rawTopics, &topics); err != nil {
+			return fmt.Errorf("Invalid topic filter: %v", err)
+		}
+		hashes := []common.Hash{}
+		for _, topic := range topics {
+			hashes = append(hashes, common.HexToHash(topic))
+		}
+		f.Topics = append(f.Topics, hashes)
+	}
+	return nil
+}
+
+func unmarshalOneOrMany(data json.RawMessage, values *[]string) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*values = []string{value}
+		return nil
+	}
+	return json.Unmarshal(data, values)
+}
+
+// MatchesAddress indicates whether the address passes the address filter. The
+// reserveFundPayments subscriptions use it to select the payers.
+func (f *LogFilter) MatchesAddress(addr common.Address) bool {
+	if len(f.Addresses) == 0 {
+		return true
+	}
+	for _, address := range f.Addresses {
+		if address == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches indicates whether the log passes the filter
+func (f *LogFilter) Matches(log *types.Log) bool {
+	if !f.MatchesAddress(log.Address) {
+		return false
+	}
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range f.Topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			if topic == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesBloom indicates whether the block with the given bloom filter may contain logs passing
+// the filter. False positives are possible, false negatives are not.
+func (f *LogFilter) MatchesBloom(bloom core.Bloom) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, address := range f.Addresses {
+			if bloom.TestBytes(address.Bytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, alternatives := range f.Topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			if bloom.TestBytes(topic.Bytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type subscription struct {
+	id     string
+	kind   string
+	filter *LogFilter
+	conn   *subscriptionConn
+}
+
+type subscriptionConn struct {
+	ws        *websocket.Conn
+	queue     chan interface{}
+	closed    chan struct{}
+	closeOnce *sync.Once
+	numSubs   int
+}
+
+func (c *subscriptionConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.ws.Close()
+	})
+}
+
+// send queues the message without blocking. A subscriber which is too slow to drain its
+// queue is disconnected, rather than slowing down the other subscribers.
+func (c *subscriptionConn) send(msg interface{}) {
+	select {
+	case c.queue <- msg:
+	case <-c.closed:
+	default:
+		logger.Warnf("Subscriber %v is too slow, closing the connection", c.ws.Request().RemoteAddr)
+		c.close()
+	}
+}
+
+func (c *subscriptionConn) writeLoop() {
+	for {
+		select {
+		case <-c.closed:
+			return
+		case msg := <-c.queue:
+			if err := websocket.JSON.Send(c.ws, msg); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+type subscriptionRequest struct {
+	ID     *json.RawMessage  `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type subscriptionResponse struct {
+	Version string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *jsonrpc2.Error  `json:"error,omitempty"`
+}
+
+type subscriptionNotification struct {
+	Version string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  subscriptionResult `json:"params"`
+}
+
+type subscriptionResult struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+// subscriptionHub keeps track of the subscriptions and dispatches the events to them
+type subscriptionHub struct {
+	mu               *sync.Mutex
+	subs             map[string]*subscription
+	nextID           uint64
+	maxSubsPerClient int
+}
+
+func newSubscriptionHub(maxSubsPerClient int) *subscriptionHub {
+	return &subscriptionHub{
+		mu:               &sync.Mutex{},
+		subs:             make(map[string]*subscription),
+		maxSubsPerClient: maxSubsPerClient,
+	}
+}
+
+// serve handles a websocket connection of the subscription endpoint. The clients send
+// JSON-RPC 2.0 requests:
+//
+//	{"jsonrpc":"2.0","id":1,"method":"pando_subscribe","params":["logs",{"address":"0x...","topics":[["0x..."]]}]}
+//	{"jsonrpc":"2.0","id":2,"method":"pando_unsubscribe","params":["0x1"]}
+//
+// and receive the events as notifications:
+//
+//	{"jsonrpc":"2.0","method":"pando_subscription","params":{"subscription":"0x1","result":{...}}}
+func (h *subscriptionHub) serve(ws *websocket.Conn) {
+	conn := &subscriptionConn{
+		ws:        ws,
+		queue:     make(chan interface{}, subscriptionQueueSize),
+		closed:    make(chan struct{}),
+		closeOnce: &sync.Once{},
+	}
+	defer func() {
+		h.removeConn(conn)
+		conn.close()
+	}()
+	go conn.writeLoop()
+
+	for {
+		req := subscriptionRequest{}
+		if err := websocket.JSON.Receive(ws, &req); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				conn.send(subscriptionResponse{Version: "2.0", Error: jsonrpc2.NewError(-32700, err.Error())})
+				continue
+			}
+			logger.Debugf("Subscriber disconnected: %v", err)
+			return
+		}
+		result, err := h.handleRequest(conn, &req)
+		res := subscriptionResponse{Version: "2.0", ID: req.ID, Result: result}
+		if err != nil {
+			res.Result = nil
+			res.Error = err
+		}
+		conn.send(res)
+	}
+}
+
+func (h *subscriptionHub) handleRequest(conn *subscriptionConn, req *subscriptionRequest) (interface{}, *jsonrpc2.Error) {
+	switch req.Method {
+	case subscribeMethod:
+		if len(req.Params) == 0 {
+			return nil, jsonrpc2.NewError(-32602, "Subscription kind must be specified")
+		}
+		var kind string
+		if err := json.Unmarshal(req.Params[0], &kind); err != nil {
+			return nil, jsonrpc2.NewError(-32602, "Invalid subscription kind")
+		}
+		var filter *LogFilter
+		switch kind {
+		case SubscriptionNewHeads, SubscriptionFinalizedBlocks, SubscriptionFinalizedHeaders, SubscriptionPendingTransactions:
+		case SubscriptionLogs, SubscriptionReserveFundPayments, SubscriptionSplitRuleExpiries:
+			filter = &LogFilter{}
+			if len(req.Params) > 1 {
+				if err := json.Unmarshal(req.Params[1], filter); err != nil {
+					return nil, jsonrpc2.NewError(-32602, err.Error())
+				}
+			}
+		default:
+			return nil, jsonrpc2.NewError(-32602, fmt.Sprintf("Unsupported subscription kind: %v", kind))
+		}
+		id, err := h.subscribe(conn, kind, filter)
+		if err != nil {
+			return nil, jsonrpc2.NewError(-32000, err.Error())
+		}
+		return id, nil
+	case unsubscribeMethod:
+		var id string
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &id) != nil {
+			return nil, jsonrpc2.NewError(-32602, "Subscription ID must be specified")
+		}
+		return h.unsubscribe(conn, id), nil
+	default:
+		return nil, jsonrpc2.NewError(-32601, fmt.Sprintf("Method not found: %v", req.Method))
+	}
+}
+
+func (h *subscriptionHub) subscribe(conn *subscriptionConn, kind string, filter *LogFilter) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.maxSubsPerClient > 0 && conn.numSubs >= h.maxSubsPerClient {
+		return "", fmt.Errorf("Too many subscriptions, at most %v are allowed per connection", h.maxSubsPerClient)
+	}
+	h.nextID++
+	sub := &subscription{
+		id:     fmt.Sprintf("0x%x", h.nextID),
+		kind:   kind,
+		filter: filter,
+		conn:   conn,
+	}
+	h.subs[sub.id] = sub
+	conn.numSubs++
+	return sub.id, nil
+}
+
+func (h *subscriptionHub) unsubscribe(conn *subscriptionConn, id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub, ok := h.subs[id]
+	if !ok || sub.conn != conn {
+		return false
+	}
+	delete(h.subs, id)
+	conn.numSubs--
+	return true
+}
+
+func (h *subscriptionHub) removeConn(conn *subscriptionConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, sub := range h.subs {
+		if sub.conn == conn {
+			delete(h.subs, id)
+		}
+	}
+	conn.numSubs = 0
+}
+
+func (h *subscriptionHub) hasSubscribers(kind string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subs {
+		if sub.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// publish pushes the event to the subscriptions of the given kind
+func (h *subscriptionHub) publish(kind string, event interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subs {
+		if sub.kind == kind {
+			sub.conn.send(newSubscriptionNotification(sub.id, event))
+		}
+	}
+}
+
+// publishLog pushes the log to the logs subscriptions whose filter it matches
+func (h *subscriptionHub) publishLog(log *SubscriptionLog) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subs {
+		if sub.kind == SubscriptionLogs && sub.filter.Matches(log.Log) {
+			sub.conn.send(newSubscriptionNotification(sub.id, log))
+		}
+	}
+}
+
+// publishReserveFundPayment pushes the payment to the reserveFundPayments subscriptions
+// whose filter selects the payer
+func (h *subscriptionHub) publishReserveFundPayment(payment *SubscriptionReserveFundPayment) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subs {
+		if sub.kind == SubscriptionReserveFundPayments && sub.filter.MatchesAddress(payment.Source) {
+			sub.conn.send(newSubscriptionNotification(sub.id, payment))
+		}
+	}
+}
+
+// publishSplitRuleExpiry pushes the expiry to the splitRuleExpiries subscriptions whose filter
+// selects the initiator of the rule
+func (h *subscriptionHub) publishSplitRuleExpiry(expiry *SubscriptionSplitRuleExpiry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subs {
+		if sub.kind == SubscriptionSplitRuleExpiries && sub.filter.MatchesAddress(expiry.SplitRule.InitiatorAddress) {
+			sub.conn.send(newSubscriptionNotification(sub.id, expiry))
+		}
+	}
+}
+
+func newSubscriptionNotification(id string, event interface{}) subscriptionNotification {
+	return subscriptionNotification{
+		Version: "2.0",
+		Method:  subscriptionMethod,
+		Params: subscriptionResult{
+			Subscription: id,
+			Result:       event,
+		},
+	}
+}
+
+// ------------------------------ Event sources -----------------------------------
+
+// publishFinalizedBlock pushes the finalized block, its header commit, the logs emitted by
+// its transactions, its settled service payments and the split rules expiring at it to the
+// subscribers
+func (t *PandoRPCService) publishFinalizedBlock(block *core.Block) {
+	hash := block.Hash()
+	t.subscriptions.publish(SubscriptionFinalizedBlocks, &SubscriptionBlockHeader{BlockHeader: block.BlockHeader, Hash: hash})
+
+	if t.subscriptions.hasSubscribers(SubscriptionFinalizedHeaders) {
+		if header, err := t.finalizedHeader(block); err == nil {
+			t.subscriptions.publish(SubscriptionFinalizedHeaders, header)
+		} else {
+			logger.Warnf("Failed to build the header commit of block %v: %v", hash.Hex(), err)
+		}
+	}
+
+	if t.subscriptions.hasSubscribers(SubscriptionLogs) {
+		for _, log := range t.blockLogs(block, &LogFilter{}) {
+			t.subscriptions.publishLog(log)
+		}
+	}
+
+	if t.subscriptions.hasSubscribers(SubscriptionReserveFundPayments) {
+		for _, payment := range t.blockReserveFundPayments(block) {
+			t.subscriptions.publishReserveFundPayment(payment)
+		}
+	}
+
+	if t.subscriptions.hasSubscribers(SubscriptionSplitRuleExpiries) {
+		expiries, err := t.blockSplitRuleExpiries(block)
+		if err != nil {
+			logger.Warnf("Failed to get the split rules expiring at block %v: %v", hash.Hex(), err)
+		}
+		for _, expiry := range expiries {
+			t.subscriptions.publishSplitRuleExpiry(expiry)
+		}
+	}
+}
+
+// finalizedHeader returns the header of the block with the commit certificate of the validators
+// of the block on it
+func (t *PandoRPCService) finalizedHeader(block *core.Block) (*SubscriptionFinalizedHeader, error) {
+	hash := block.Hash()
+	validators := t.consensus.GetValidatorManager().GetValidatorSet(hash)
+	votes := t.chain.FindVotesByHash(hash).UniqueVoter().FilterByValidators(validators)
+	hc := &core.HeaderCommit{
+		Header: block.BlockHeader,
+		CC:     core.CommitCertificate{Votes: votes, BlockHash: hash},
+	}
+	hc.CC.Aggregate(validators)
+	if res := hc.Validate(validators); res.IsError() {
+		return nil, errors.New(res.Message)
+	}
+	commit, err := encodeRLP(hc)
+	if err != nil {
+		return nil, err
+	}
+	return &SubscriptionFinalizedHeader{
+		Height: common.JSONUint64(block.Height),
+		Hash:   hash,
+		Commit: commit,
+	}, nil
+}
+
+// blockReserveFundPayments returns the service payments of the block settled against a reserve fund
+func (t *PandoRPCService) blockReserveFundPayments(block *core.Block) []*SubscriptionReserveFundPayment {
+	hash := block.Hash()
+	payments := []*SubscriptionReserveFundPayment{}
+	for _, rawTx := range block.Txs {
+		txHash := crypto.Keccak256Hash(rawTx)
+		payment, found := t.chain.FindReserveFundPaymentByHash(txHash)
+		if !found || !payment.Settled {
+			continue
+		}
+		payments = append(payments, &SubscriptionReserveFundPayment{
+			TxHash:          txHash,
+			BlockHash:       hash,
+			BlockHeight:     common.JSONUint64(block.Height),
+			Source:          payment.Source,
+			Target:          payment.Target,
+			ReserveSequence: common.JSONUint64(payment.ReserveSequence),
+			PaymentSequence: common.JSONUint64(payment.PaymentSequence),
+			ResourceID:      payment.ResourceID,
+			Amount:          payment.Amount,
+			UsedFund:        payment.UsedFund,
+			RemainingFund:   payment.RemainingFund,
+		})
+	}
+	return payments
+}
+
+// blockSplitRuleExpiries returns the split rules which applied at the parent of the block, and no
+// longer apply at the block. The expired rules are only deleted from the state by the next split
+// rule transaction, hence they are looked up in the state of the parent block.
+func (t *PandoRPCService) blockSplitRuleExpiries(block *core.Block) ([]*SubscriptionSplitRuleExpiry, error) {
+	parent, err := t.chain.FindBlock(block.Parent)
+	if err != nil {
+		return nil, err
+	}
+	db := t.ledger.State().DB()
+	parentView := state.NewReadOnlyStoreView(parent.Height, parent.StateHash, db)
+	view := state.NewReadOnlyStoreView(block.Height, block.StateHash, db)
+	if parentView == nil || view == nil {
+		return nil, fmt.Errorf("the state might have been pruned")
+	}
+
+	hash := block.Hash()
+	expiries := []*SubscriptionSplitRuleExpiry{}
+	for _, splitRule := range parentView.GetSplitRules() {
+		if splitRule.IsExpired(parent.Height) || !splitRule.IsExpired(block.Height) {
+			continue
+		}
+		if renewed := view.GetSplitRule(splitRule.ResourceID); renewed != nil && !renewed.IsExpired(block.Height) {
+			continue
+		}
+		expiries = append(expiries, &SubscriptionSplitRuleExpiry{
+			BlockHash:   hash,
+			BlockHeight: common.JSONUint64(block.Height),
+			SplitRule:   splitRule,
+		})
+	}
+	return expiries, nil
+}
+
+// pollSubscriptionEvents checks for new heads and pending transactions, which the
+// consensus engine and the mempool do not notify
+func (t *PandoRPCService) pollSubscriptionEvents() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(subscriptionPollInterval)
+	defer ticker.Stop()
+
+	var lastHead common.Hash
+	pendingTxs := make(map[string]struct{})
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if t.subscriptions.hasSubscribers(SubscriptionNewHeads) {
+			head := t.consensus.GetTipToVote()
+			if head.Hash() != lastHead {
+				lastHead = head.Hash()
+				t.subscriptions.publish(SubscriptionNewHeads, &SubscriptionBlockHeader{BlockHeader: head.BlockHeader, Hash: lastHead})
+			}
+		}
+
+		if t.subscriptions.hasSubscribers(SubscriptionPendingTransactions) {
+			current := make(map[string]struct{})
+			for _, txHash := range t.mempool.GetCandidateTransactionHashes() {
+				current[txHash] = struct{}{}
+				if _, seen := pendingTxs[txHash]; !seen {
+					t.subscriptions.publish(SubscriptionPendingTransactions, txHash)
+				}
+			}
+			pendingTxs = current
+		} else if len(pendingTxs) > 0 {
+			pendingTxs = make(map[string]struct{})
+		}
+	}
+}
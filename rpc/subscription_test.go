@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/util"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestLogFilter(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	transfer := common.HexToHash("0xaa")
+	approval := common.HexToHash("0xbb")
+	holder := common.HexToHash("0xcc")
+
+	filter := &LogFilter{}
+	require.Nil(json.Unmarshal([]byte(`{"address":"`+contract.Hex()+`","topics":[["0xaa","0xbb"],null,"0xcc"]}`), filter))
+	assert.Equal([]common.Address{contract}, filter.Addresses)
+	require.Equal(3, len(filter.Topics))
+	assert.Equal([]common.Hash{transfer, approval}, filter.Topics[0])
+	assert.Equal(0, len(filter.Topics[1]))
+
+	assert.True(filter.Matches(&types.Log{Address: contract, Topics: []common.Hash{transfer, approval, holder}}))
+	assert.True(filter.Matches(&types.Log{Address: contract, Topics: []common.Hash{approval, transfer, holder, transfer}}))
+	assert.False(filter.Matches(&types.Log{Address: contract, Topics: []common.Hash{holder, transfer, holder}}))
+	assert.False(filter.Matches(&types.Log{Address: contract, Topics: []common.Hash{transfer, approval}}))
+	assert.False(filter.Matches(&types.Log{Address: common.Address{}, Topics: []common.Hash{transfer, approval, holder}}))
+
+	// An empty filter matches all logs
+	filter = &LogFilter{}
+	require.Nil(json.Unmarshal([]byte(`{}`), filter))
+	assert.True(filter.Matches(&types.Log{Address: contract}))
+
+	assert.NotNil(json.Unmarshal([]byte(`{"address":["0x123"]}`), &LogFilter{}))
+}
+
+func TestLogFilterMatchesBloom(t *testing.T) {
+	assert := assert.New(t)
+
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	transfer := common.HexToHash("0xaa")
+	approval := common.HexToHash("0xbb")
+	holder := common.HexToHash("0xcc")
+
+	bloom := types.LogsBloom([]*types.Log{{Address: contract, Topics: []common.Hash{transfer, holder}}})
+
+	assert.True((&LogFilter{}).MatchesBloom(bloom))
+	assert.True((&LogFilter{Addresses: []common.Address{other, contract}}).MatchesBloom(bloom))
+	assert.False((&LogFilter{Addresses: []common.Address{other}}).MatchesBloom(bloom))
+	assert.True((&LogFilter{Topics: [][]common.Hash{{approval, transfer}, {}, {holder}}}).MatchesBloom(bloom))
+	assert.False((&LogFilter{Topics: [][]common.Hash{{transfer}, {approval}}}).MatchesBloom(bloom))
+	assert.True((&LogFilter{}).MatchesBloom(core.Bloom{}))
+	assert.False((&LogFilter{Addresses: []common.Address{contract}}).MatchesBloom(core.Bloom{}))
+}
+
+type subscriptionMessage struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Method string `json:"method"`
+	Params struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+func TestSubscriptionHub(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger = util.GetLoggerForModule("rpc")
+
+	hub := newSubscriptionHub(2)
+	server := httptest.NewServer(websocket.Handler(hub.serve))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := websocket.Dial(url, "", server.URL)
+	require.Nil(err)
+	defer ws.Close()
+
+	call := func(id int, method string, params ...interface{}) subscriptionMessage {
+		require.Nil(websocket.JSON.Send(ws, map[string]interface{}{
+			"jsonrpc": "2.0", "id": id, "method": method, "params": params,
+		}))
+		return receive(t, ws)
+	}
+
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	res := call(1, subscribeMethod, SubscriptionLogs, map[string]interface{}{"address": contract.Hex()})
+	require.Nil(res.Error)
+	var logsSub string
+	require.Nil(json.Unmarshal(res.Result, &logsSub))
+
+	res = call(2, subscribeMethod, SubscriptionFinalizedBlocks)
+	require.Nil(res.Error)
+	var blocksSub string
+	require.Nil(json.Unmarshal(res.Result, &blocksSub))
+	assert.NotEqual(logsSub, blocksSub)
+
+	res = call(3, subscribeMethod, SubscriptionPendingTransactions)
+	assert.NotNil(res.Error, "the number of subscriptions is limited")
+	res = call(4, subscribeMethod, "syncing")
+	assert.NotNil(res.Error)
+	res = call(5, "pando_foo")
+	assert.NotNil(res.Error)
+
+	// Only the matching logs are pushed
+	hub.publishLog(&SubscriptionLog{Log: &types.Log{Address: common.Address{}}})
+	hub.publishLog(&SubscriptionLog{Log: &types.Log{Address: contract}, BlockHeight: 7})
+	hub.publish(SubscriptionNewHeads, "ignored")
+	hub.publish(SubscriptionFinalizedBlocks, "block")
+
+	msg := receive(t, ws)
+	assert.Equal(subscriptionMethod, msg.Method)
+	assert.Equal(logsSub, msg.Params.Subscription)
+	log := SubscriptionLog{}
+	require.Nil(json.Unmarshal(msg.Params.Result, &log))
+	assert.Equal(contract, log.Address)
+	assert.Equal(common.JSONUint64(7), log.BlockHeight)
+
+	msg = receive(t, ws)
+	assert.Equal(blocksSub, msg.Params.Subscription)
+	assert.Equal(`"block"`, string(msg.Params.Result))
+
+	// No more events after unsubscribing
+	res = call(6, unsubscribeMethod, logsSub)
+	assert.Equal("true", string(res.Result))
+	res = call(7, unsubscribeMethod, logsSub)
+	assert.Equal("false", string(res.Result))
+	assert.False(hub.hasSubscribers(SubscriptionLogs))
+	assert.True(hub.hasSubscribers(SubscriptionFinalizedBlocks))
+
+	// The subscriptions are removed when the client disconnects
+	ws.Close()
+	for i := 0; i < 100 && hub.hasSubscribers(SubscriptionFinalizedBlocks); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.False(hub.hasSubscribers(SubscriptionFinalizedBlocks))
+}
+
+func receive(t *testing.T, ws *websocket.Conn) subscriptionMessage {
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	msg := subscriptionMessage{}
+	require.Nil(t, websocket.JSON.Receive(ws, &msg))
+	return msg
+}
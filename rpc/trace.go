@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/common/util"
+)
+
+// ------------------------------- SetTraceTarget -----------------------------------
+
+type SetTraceTargetArgs struct {
+	Kind         string            `json:"kind"` // "peer", "tx" or "block"
+	ID           string            `json:"id"`   // peer ID, tx hash or block hash
+	DurationSecs common.JSONUint64 `json:"duration_secs"`
+	Remove       bool              `json:"remove"`
+}
+
+type SetTraceTargetResult struct {
+	Target  *util.TraceTarget `json:"target"`
+	Removed bool              `json:"removed"`
+}
+
+// SetTraceTarget starts, extends or stops tracing every pipeline stage touching a peer, a transaction or a block
+func (t *PandoRPCService) SetTraceTarget(args *SetTraceTargetArgs, result *SetTraceTargetResult) error {
+	kind := util.TraceKind(args.Kind)
+	if args.Remove {
+		result.Removed = util.RemoveTraceTarget(kind, args.ID)
+		return nil
+	}
+
+	duration := time.Duration(args.DurationSecs) * time.Second
+	target, err := util.AddTraceTarget(kind, args.ID, duration)
+	if err != nil {
+		return err
+	}
+	result.Target = &target
+	return nil
+}
+
+// ------------------------------- GetTraceTargets -----------------------------------
+
+type GetTraceTargetsArgs struct {
+}
+
+type GetTraceTargetsResult struct {
+	Targets []util.TraceTarget `json:"targets"`
+}
+
+func (t *PandoRPCService) GetTraceTargets(args *GetTraceTargetsArgs, result *GetTraceTargetsResult) error {
+	result.Targets = util.GetTraceTargets()
+	return nil
+}
+
+// ------------------------------- GetTraceEvents -----------------------------------
+
+type GetTraceEventsArgs struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+type GetTraceEventsResult struct {
+	Events []util.TraceEvent `json:"events"`
+}
+
+// GetTraceEvents returns the recent pipeline stages which touched the trace target, the oldest first
+func (t *PandoRPCService) GetTraceEvents(args *GetTraceEventsArgs, result *GetTraceEventsResult) error {
+	events, ok := util.GetTraceEvents(util.TraceKind(args.Kind), args.ID)
+	if !ok {
+		return fmt.Errorf("The %v %v is not traced", args.Kind, args.ID)
+	}
+	result.Events = events
+	return nil
+}
+
+// ------------------------------- SetLogLevel -----------------------------------
+
+type SetLogLevelArgs struct {
+	Module string `json:"module"` // "*" for the default level
+	Level  string `json:"level"`
+}
+
+type SetLogLevelResult struct {
+	Levels map[string]string `json:"levels"`
+}
+
+func (t *PandoRPCService) SetLogLevel(args *SetLogLevelArgs, result *SetLogLevelResult) error {
+	if args.Module == "" {
+		return fmt.Errorf("The module is not specified, use \"*\" for the default level")
+	}
+	if err := util.SetModuleLogLevel(args.Module, args.Level); err != nil {
+		return err
+	}
+	logger.Infof("Log level of module %v set to %v", args.Module, args.Level)
+	result.Levels = util.GetModuleLogLevels()
+	return nil
+}
+
+// ------------------------------- GetLogLevels -----------------------------------
+
+type GetLogLevelsArgs struct {
+}
+
+type GetLogLevelsResult struct {
+	Levels map[string]string `json:"levels"`
+}
+
+func (t *PandoRPCService) GetLogLevels(args *GetLogLevelsArgs, result *GetLogLevelsResult) error {
+	result.Levels = util.GetModuleLogLevels()
+	return nil
+}
@@ -3,13 +3,16 @@ package rpc
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/common/hexutil"
+	"github.com/pandotoken/pando/common/util"
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
 )
 
 const txTimeout = 60 * time.Second
@@ -107,6 +110,8 @@ func (t *PandoRPCService) txCallback() {
 				}
 			}
 
+			t.publishFinalizedBlock(block)
+
 			logger.Infof("Done processing finalized block, height=%v", block.Height)
 		case <-timer.C:
 			logger.Debugf("txCallbackManager.Trim()")
@@ -140,10 +145,11 @@ func (t *PandoRPCService) BroadcastRawTransaction(
 	result.TxHash = hash.Hex()
 
 	logger.Infof("Broadcast raw transaction (sync): %v, hash: %v", hex.EncodeToString(txBytes), hash.Hex())
+	util.Trace(util.TraceTx, hash.Hex(), "rpc", "submitted", "BroadcastRawTransaction (sync)")
 
 	err = t.mempool.InsertTransaction(txBytes)
 	if err != nil {
-		return err
+		return newBroadcastError(err)
 	}
 
 	t.mempool.BroadcastTx(txBytes)
@@ -163,12 +169,12 @@ func (t *PandoRPCService) BroadcastRawTransaction(
 	case block := <-finalized:
 		if block == nil {
 			logger.Infof("Tx callback returns nil, txHash=%v", result.TxHash)
-			return errors.New("Internal server error")
+			return newBroadcastError(errInternalError)
 		}
 		result.Block = block.BlockHeader
 		return nil
 	case <-timeout.C:
-		return errors.New("Timed out waiting for transaction to be included")
+		return newBroadcastError(errTxTimeout)
 	}
 }
 
@@ -193,10 +199,11 @@ func (t *PandoRPCService) BroadcastRawTransactionAsync(
 	result.TxHash = hash.Hex()
 
 	logger.Infof("Broadcast raw transaction (async): %v, hash: %v", hex.EncodeToString(txBytes), hash.Hex())
+	util.Trace(util.TraceTx, hash.Hex(), "rpc", "submitted", "BroadcastRawTransaction (async)")
 
 	err = t.mempool.InsertTransaction(txBytes)
 	if err != nil {
-		return err
+		return newBroadcastError(err)
 	}
 
 	t.mempool.BroadcastTx(txBytes)
@@ -204,6 +211,112 @@ func (t *PandoRPCService) BroadcastRawTransactionAsync(
 	return nil
 }
 
+// ------------------------------- GetTypedSignData -----------------------------------
+
+type GetTypedSignDataArgs struct {
+	TxBytes string `json:"tx_bytes"`
+}
+
+type GetTypedSignDataResult struct {
+	TypedData *types.TypedData `json:"typed_data"`
+	SignBytes string           `json:"sign_bytes"`
+}
+
+// GetTypedSignData returns the typed data of an unsigned transaction, which wallets can display
+// and sign (e.g. with eth_signTypedData_v4) instead of the RLP encoded sign bytes.
+func (t *PandoRPCService) GetTypedSignData(
+	args *GetTypedSignDataArgs, result *GetTypedSignDataResult) (err error) {
+	txBytes, err := decodeTxHexBytes(args.TxBytes)
+	if err != nil {
+		return err
+	}
+	tx, err := types.TxFromBytes(txBytes)
+	if err != nil {
+		return err
+	}
+
+	chainID := t.consensus.Chain().ChainID
+	result.TypedData, err = types.TypedSignData(chainID, tx)
+	if err != nil {
+		return err
+	}
+	signBytes, err := types.TypedSignBytes(chainID, tx)
+	if err != nil {
+		return err
+	}
+	result.SignBytes = hex.EncodeToString(signBytes)
+
+	return nil
+}
+
+// ------------------------------- GetCanonicalSignData -----------------------------------
+
+type GetCanonicalSignDataArgs struct {
+	TxBytes string `json:"tx_bytes"`
+}
+
+type GetCanonicalSignDataResult struct {
+	CanonicalData string `json:"canonical_data"`
+	SignBytes     string `json:"sign_bytes"`
+}
+
+// GetCanonicalSignData returns the canonical JSON signing payload of an unsigned transaction,
+// which integrators can sign instead of the RLP encoded sign bytes, and compare with their own
+// encoding of the transaction.
+func (t *PandoRPCService) GetCanonicalSignData(
+	args *GetCanonicalSignDataArgs, result *GetCanonicalSignDataResult) (err error) {
+	txBytes, err := decodeTxHexBytes(args.TxBytes)
+	if err != nil {
+		return err
+	}
+	tx, err := types.TxFromBytes(txBytes)
+	if err != nil {
+		return err
+	}
+
+	chainID := t.consensus.Chain().ChainID
+	signBytes, err := types.CanonicalSignBytes(chainID, tx)
+	if err != nil {
+		return err
+	}
+	result.CanonicalData = string(signBytes)
+	result.SignBytes = hex.EncodeToString(signBytes)
+
+	return nil
+}
+
+// ------------------------------- SubmitRametronHeartbeat -----------------------------------
+
+type SubmitRametronHeartbeatArgs struct {
+	Heartbeat *types.RametronHeartbeat `json:"heartbeat"`
+}
+
+type SubmitRametronHeartbeatResult struct {
+	Epoch common.JSONUint64 `json:"epoch"` // the current epoch of the guardian
+}
+
+// SubmitRametronHeartbeat adds the heartbeat of a Rametron node to the heartbeats the guardian
+// attests on chain in the current epoch
+func (t *PandoRPCService) SubmitRametronHeartbeat(args *SubmitRametronHeartbeatArgs, result *SubmitRametronHeartbeatResult) (err error) {
+	if t.heartbeatPool == nil {
+		return errors.New("The node does not attest rametron heartbeats")
+	}
+	if args.Heartbeat == nil {
+		return errors.New("Heartbeat must be specified")
+	}
+
+	view, err := t.ledger.GetScreenedSnapshot()
+	if err != nil {
+		return err
+	}
+	epoch := types.RametronEpoch(view.Height() + 1)
+	result.Epoch = common.JSONUint64(epoch)
+	if view.GetRametronNode(args.Heartbeat.Node) == nil {
+		return fmt.Errorf("%v is not a registered rametron node", args.Heartbeat.Node.Hex())
+	}
+	return t.heartbeatPool.Add(args.Heartbeat, epoch)
+}
+
 // -------------------------- Utilities -------------------------- //
 
 func decodeTxHexBytes(txBytes string) ([]byte, error) {
@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/ledger"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/mempool"
+)
+
+// TaskRametronAttestation is the name of the task attesting the heartbeats of the Rametron nodes
+const TaskRametronAttestation = "rametron_attestation"
+
+// HeartbeatPool collects the heartbeats the Rametron nodes send to the guardian in the current
+// epoch, until the guardian attests them on chain
+type HeartbeatPool struct {
+	mu         *sync.Mutex
+	chainID    string
+	epoch      uint64
+	heartbeats map[common.Address]*types.RametronHeartbeat
+}
+
+// NewHeartbeatPool creates an empty heartbeat pool
+func NewHeartbeatPool(chainID string) *HeartbeatPool {
+	return &HeartbeatPool{
+		mu:         &sync.Mutex{},
+		chainID:    chainID,
+		heartbeats: make(map[common.Address]*types.RametronHeartbeat),
+	}
+}
+
+// Add adds the heartbeat received in the given epoch. The heartbeats of the previous epochs are
+// dropped, since they can no longer be attested.
+func (p *HeartbeatPool) Add(heartbeat *types.RametronHeartbeat, currentEpoch uint64) error {
+	if heartbeat.Epoch != currentEpoch {
+		return fmt.Errorf("The heartbeat is for epoch %v, but the current epoch is %v", heartbeat.Epoch, currentEpoch)
+	}
+	if !heartbeat.Verify(p.chainID) {
+		return errors.New("Invalid heartbeat signature")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.setEpoch(currentEpoch)
+	p.heartbeats[heartbeat.Node] = heartbeat
+	return nil
+}
+
+// Pending returns the heartbeats of the epoch not attested yet, sorted by node address
+func (p *HeartbeatPool) Pending(epoch uint64) []*types.RametronHeartbeat {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.setEpoch(epoch)
+	heartbeats := make([]*types.RametronHeartbeat, 0, len(p.heartbeats))
+	for _, heartbeat := range p.heartbeats {
+		heartbeats = append(heartbeats, heartbeat)
+	}
+	sort.Slice(heartbeats, func(i, j int) bool {
+		return heartbeats[i].Node.Hex() < heartbeats[j].Node.Hex()
+	})
+	return heartbeats
+}
+
+// Remove removes the attested heartbeats
+func (p *HeartbeatPool) Remove(heartbeats []*types.RametronHeartbeat) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, heartbeat := range heartbeats {
+		if existing, ok := p.heartbeats[heartbeat.Node]; ok && existing.Epoch == heartbeat.Epoch {
+			delete(p.heartbeats, heartbeat.Node)
+		}
+	}
+}
+
+func (p *HeartbeatPool) setEpoch(epoch uint64) {
+	if epoch > p.epoch {
+		p.epoch = epoch
+		p.heartbeats = make(map[common.Address]*types.RametronHeartbeat)
+	}
+}
+
+// NewRametronAttestationTask returns a task which attests the pending heartbeats of the current
+// epoch with a RametronAttestationTx signed by the guardian. The transaction is submitted to the
+// local mempool and broadcasted to the peers.
+func NewRametronAttestationTask(chainID string, ledger *ledger.Ledger, mempool *mempool.Mempool, pool *HeartbeatPool,
+	signer crypto.Signer) TaskFunc {
+	guardian := signer.PublicKey().Address()
+	return func(ctx context.Context) (string, error) {
+		// The screened view accounts for the pending transactions of the guardian account
+		view, err := ledger.GetScreenedSnapshot()
+		if err != nil {
+			return "", err
+		}
+		blockHeight := view.Height() + 1
+		if !features.IsEnabled(features.RametronAttestation, blockHeight) {
+			return "Rametron attestation is not enabled yet", nil
+		}
+		if !view.GetGuardianCandidatePool().WithStake().Contains(guardian) {
+			return "", fmt.Errorf("%v is not a guardian", guardian.Hex())
+		}
+		account := view.GetAccount(guardian)
+		if account == nil {
+			return "", fmt.Errorf("Guardian account %v not found", guardian.Hex())
+		}
+
+		epoch := types.RametronEpoch(blockHeight)
+		heartbeats := []*types.RametronHeartbeat{}
+		for _, heartbeat := range pool.Pending(epoch) {
+			if view.GetRametronNode(heartbeat.Node) != nil {
+				heartbeats = append(heartbeats, heartbeat)
+			}
+		}
+		if len(heartbeats) == 0 {
+			return fmt.Sprintf("No heartbeat to attest in epoch %v", epoch), nil
+		}
+		if len(heartbeats) > types.MaxRametronHeartbeatsPerTx {
+			heartbeats = heartbeats[:types.MaxRametronHeartbeatsPerTx]
+		}
+
+		tx := &types.RametronAttestationTx{
+			Fee: types.Coins{PandoWei: big.NewInt(0), PTXWei: view.GetBaseFee()},
+			Guardian: types.TxInput{
+				Address:  guardian,
+				Sequence: account.Sequence + 1,
+			},
+			Epoch:      epoch,
+			Heartbeats: heartbeats,
+		}
+		sig, err := signer.Sign(tx.SignBytes(chainID))
+		if err != nil {
+			return "", err
+		}
+		tx.SetSignature(guardian, sig)
+
+		raw, err := types.TxToBytes(tx)
+		if err != nil {
+			return "", err
+		}
+		if err := mempool.InsertTransaction(raw); err != nil {
+			return "", err
+		}
+		mempool.BroadcastTx(raw)
+		pool.Remove(heartbeats)
+
+		return fmt.Sprintf("Attested %v heartbeats in epoch %v, tx hash: %v", len(heartbeats), epoch,
+			crypto.Keccak256Hash(raw).Hex()), nil
+	}
+}
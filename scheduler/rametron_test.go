@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+)
+
+func TestHeartbeatPool(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := NewHeartbeatPool("test")
+	heartbeat := func(epoch uint64) *types.RametronHeartbeat {
+		priv, _, _ := crypto.GenerateKeyPair()
+		h := &types.RametronHeartbeat{Node: priv.PublicKey().Address(), Epoch: epoch}
+		h.Signature, _ = priv.Sign(h.SignBytes("test"))
+		return h
+	}
+
+	h1, h2 := heartbeat(5), heartbeat(5)
+	assert.Nil(pool.Add(h1, 5))
+	assert.Nil(pool.Add(h2, 5))
+	assert.NotNil(pool.Add(heartbeat(4), 5))
+
+	forged := heartbeat(5)
+	forged.Epoch = 6
+	assert.NotNil(pool.Add(forged, 6))
+
+	assert.Equal(2, len(pool.Pending(5)))
+	pool.Remove([]*types.RametronHeartbeat{h1})
+	pending := pool.Pending(5)
+	assert.Equal(1, len(pending))
+	assert.Equal(h2.Node, pending[0].Node)
+
+	// The heartbeats of the previous epochs are dropped
+	assert.Equal(0, len(pool.Pending(6)))
+}
@@ -0,0 +1,306 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "scheduler"})
+
+// DefaultHistorySize is the default number of task runs retained by the scheduler
+const DefaultHistorySize = 100
+
+// TaskFunc runs a task. The returned message summarizes the outcome of the run.
+type TaskFunc func(ctx context.Context) (string, error)
+
+// TaskRun records a run of a task
+type TaskRun struct {
+	Task      string    `json:"task"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Message   string    `json:"message"`
+	Error     string    `json:"error"`
+}
+
+// Succeeded indicates whether the run finished without an error
+func (run *TaskRun) Succeeded() bool {
+	return len(run.Error) == 0
+}
+
+// TaskStatus is the current status of a scheduled task
+type TaskStatus struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	Running  bool      `json:"running"`
+	NextRun  time.Time `json:"next_run"`
+	LastRun  *TaskRun  `json:"last_run"`
+}
+
+type task struct {
+	name     string
+	spec     string
+	schedule Schedule
+	run      TaskFunc
+
+	running bool
+	nextRun time.Time
+	lastRun *TaskRun
+}
+
+// Scheduler runs the registered tasks on their schedules. A task is never run
+// concurrently with itself, an activation is skipped if the previous run is not
+// finished yet.
+type Scheduler struct {
+	mu          *sync.Mutex
+	tasks       []*task
+	history     []*TaskRun
+	historySize int
+	wakeup      chan struct{}
+	now         func() time.Time
+
+	// Life cycle
+	wg      *sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped bool
+}
+
+// NewScheduler creates a new instance of Scheduler retaining the given number of task runs
+func NewScheduler(historySize int) *Scheduler {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &Scheduler{
+		mu:          &sync.Mutex{},
+		historySize: historySize,
+		wakeup:      make(chan struct{}, 1),
+		now:         time.Now,
+		wg:          &sync.WaitGroup{},
+	}
+}
+
+// AddTask registers a task to run on the schedule given by spec (see ParseSchedule)
+func (s *Scheduler) AddTask(name string, spec string, run TaskFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.tasks {
+		if t.name == name {
+			return fmt.Errorf("Task %v already exists", name)
+		}
+	}
+	s.tasks = append(s.tasks, &task{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		run:      run,
+		nextRun:  schedule.Next(s.now()),
+	})
+	s.notify()
+
+	logger.Infof("Scheduled task %v, schedule: %v", name, spec)
+	return nil
+}
+
+// Start creates the main goroutine.
+func (s *Scheduler) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	s.ctx = c
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go s.mainLoop()
+}
+
+// Stop notifies all goroutines to stop without blocking.
+func (s *Scheduler) Stop() {
+	s.cancel()
+}
+
+// Wait blocks until all goroutines stop, including the running tasks.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) mainLoop() {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.stopped = true
+			return
+		case <-timer.C:
+		case <-s.wakeup:
+		}
+
+		s.runDueTasks()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(s.untilNextRun())
+	}
+}
+
+// runDueTasks starts the tasks whose activation time has passed
+func (s *Scheduler) runDueTasks() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, t := range s.tasks {
+		if t.nextRun.IsZero() || t.nextRun.After(now) {
+			continue
+		}
+		t.nextRun = t.schedule.Next(now)
+		if t.running {
+			logger.Warnf("Skipped task %v, the previous run is not finished yet", t.name)
+			continue
+		}
+		s.startTaskUnsafe(t)
+	}
+}
+
+// untilNextRun returns the time until the earliest activation of the tasks
+func (s *Scheduler) untilNextRun() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := time.Hour // re-check periodically in case of clock adjustments
+	now := s.now()
+	for _, t := range s.tasks {
+		if t.nextRun.IsZero() {
+			continue
+		}
+		if d := t.nextRun.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+func (s *Scheduler) startTaskUnsafe(t *task) {
+	t.running = true
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		run := &TaskRun{
+			Task:      t.name,
+			StartTime: s.now(),
+		}
+		logger.Infof("Running task %v", t.name)
+		message, err := s.runTask(t)
+		run.EndTime = s.now()
+		run.Message = message
+		if err != nil {
+			run.Error = err.Error()
+			logger.Errorf("Task %v failed after %v: %v", t.name, run.EndTime.Sub(run.StartTime), err)
+		} else {
+			logger.Infof("Task %v finished in %v: %v", t.name, run.EndTime.Sub(run.StartTime), message)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		t.running = false
+		t.lastRun = run
+		s.history = append(s.history, run)
+		if len(s.history) > s.historySize {
+			s.history = s.history[len(s.history)-s.historySize:]
+		}
+	}()
+}
+
+// runTask runs the task, and recovers from a panic so that a faulty task does not
+// bring down the node
+func (s *Scheduler) runTask(t *task) (message string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return t.run(s.ctx)
+}
+
+// RunNow starts the given task immediately, regardless of its schedule
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx == nil || s.ctx.Err() != nil {
+		return fmt.Errorf("Scheduler is not running")
+	}
+	for _, t := range s.tasks {
+		if t.name != name {
+			continue
+		}
+		if t.running {
+			return fmt.Errorf("Task %v is already running", name)
+		}
+		s.startTaskUnsafe(t)
+		return nil
+	}
+	return fmt.Errorf("Task %v not found", name)
+}
+
+// Tasks returns the status of the registered tasks
+func (s *Scheduler) Tasks() []TaskStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]TaskStatus, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		statuses = append(statuses, TaskStatus{
+			Name:     t.name,
+			Schedule: t.spec,
+			Running:  t.running,
+			NextRun:  t.nextRun,
+			LastRun:  t.lastRun,
+		})
+	}
+	return statuses
+}
+
+// History returns up to count most recent runs of the given task, the most recent
+// first. The runs of all tasks are returned if name is empty, and all the retained
+// runs if count is 0.
+func (s *Scheduler) History(name string, count int) []*TaskRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := []*TaskRun{}
+	for i := len(s.history) - 1; i >= 0; i-- {
+		if count > 0 && len(runs) >= count {
+			break
+		}
+		if len(name) == 0 || s.history[i].Task == name {
+			runs = append(runs, s.history[i])
+		}
+	}
+	return runs
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, spec := range []string{"* * * * *", "*/15 0-6,22 1 */3 1-5", "30 3 * * 7", "@daily", "@every 6h"} {
+		_, err := ParseSchedule(spec)
+		assert.Nil(err, spec)
+	}
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@every 1ms", "@monthly"} {
+		_, err := ParseSchedule(spec)
+		assert.NotNil(err, spec)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	assert := assert.New(t)
+
+	// Friday
+	now := time.Date(2021, time.January, 1, 10, 17, 30, 0, time.UTC)
+	next := func(spec string) time.Time {
+		schedule, err := ParseSchedule(spec)
+		require.Nil(t, err)
+		return schedule.Next(now)
+	}
+
+	assert.Equal(time.Date(2021, time.January, 1, 10, 18, 0, 0, time.UTC), next("* * * * *"))
+	assert.Equal(time.Date(2021, time.January, 1, 10, 30, 0, 0, time.UTC), next("*/15 * * * *"))
+	assert.Equal(time.Date(2021, time.January, 2, 3, 30, 0, 0, time.UTC), next("30 3 * * *"))
+	assert.Equal(time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC), next("@weekly"))
+	assert.Equal(time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC), next("0 0 * * 7"))
+	assert.Equal(time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC), next("0 0 1 3 *"))
+	assert.Equal(now.Add(6*time.Hour), next("@every 6h"))
+
+	// Either the day of month or the day of week matches when both are restricted
+	assert.Equal(time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC), next("0 0 15 * 1"))
+
+	// Never matches
+	assert.True(next("0 0 30 2 *").IsZero())
+}
+
+func TestSchedulerRunHistory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sched := NewScheduler(3)
+	done := make(chan struct{}, 10)
+	require.Nil(sched.AddTask("ok", "@daily", func(ctx context.Context) (string, error) {
+		defer func() { done <- struct{}{} }()
+		return "all good", nil
+	}))
+	require.Nil(sched.AddTask("fail", "@daily", func(ctx context.Context) (string, error) {
+		defer func() { done <- struct{}{} }()
+		return "", errors.New("disk full")
+	}))
+	require.Nil(sched.AddTask("panic", "@daily", func(ctx context.Context) (string, error) {
+		defer func() { done <- struct{}{} }()
+		panic("oops")
+	}))
+	assert.NotNil(sched.AddTask("ok", "@hourly", nil))
+	assert.NotNil(sched.RunNow("ok"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sched.Start(ctx)
+	defer func() {
+		cancel()
+		sched.Wait()
+	}()
+
+	run := func(name string) {
+		require.Nil(sched.RunNow(name))
+		<-done
+		// wait for the run to be recorded
+		for i := 0; i < 100 && sched.isRunning(name); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	run("ok")
+	run("fail")
+	run("panic")
+	assert.NotNil(sched.RunNow("unknown"))
+
+	runs := sched.History("", 0)
+	require.Equal(3, len(runs))
+	assert.Equal("panic", runs[0].Task)
+	assert.Equal("panic: oops", runs[0].Error)
+	assert.Equal("disk full", runs[1].Error)
+	assert.True(runs[2].Succeeded())
+	assert.Equal("all good", runs[2].Message)
+
+	// The history is bounded
+	run("ok")
+	runs = sched.History("", 0)
+	require.Equal(3, len(runs))
+	assert.Equal("ok", runs[0].Task)
+	assert.Equal("fail", runs[2].Task)
+
+	runs = sched.History("ok", 1)
+	require.Equal(1, len(runs))
+	assert.Equal("ok", runs[0].Task)
+
+	for _, status := range sched.Tasks() {
+		assert.False(status.NextRun.IsZero())
+		assert.NotNil(status.LastRun, status.Name)
+	}
+}
+
+func (s *Scheduler) isRunning(name string) bool {
+	for _, status := range s.Tasks() {
+		if status.Name == name {
+			return status.Running
+		}
+	}
+	return false
+}
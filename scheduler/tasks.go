@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/pandotoken/pando/blockchain"
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/consensus"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/features"
+	"github.com/pandotoken/pando/ledger"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/mempool"
+	"github.com/pandotoken/pando/snapshot"
+	"github.com/pandotoken/pando/store/database"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+)
+
+// Names of the built-in tasks
+const (
+	TaskSnapshotExport = "snapshot_export"
+	TaskDBCompaction   = "db_compaction"
+	TaskStakeCompound  = "stake_compound"
+)
+
+// NewSnapshotExportTask returns a task which exports a snapshot of the last finalized block
+// into snapshotDir, keeping at most the given number of snapshots (0 to keep all)
+func NewSnapshotExportTask(db database.Database, consensus *consensus.ConsensusEngine, chain *blockchain.Chain,
+	snapshotDir string, retain int) TaskFunc {
+	return func(ctx context.Context) (string, error) {
+		if err := os.MkdirAll(snapshotDir, os.ModePerm); err != nil {
+			return "", err
+		}
+		snapshotFile, err := snapshot.ExportSnapshot(db, consensus, chain, snapshotDir, 0)
+		if err != nil {
+			return "", err
+		}
+
+		removed, err := snapshot.PruneSnapshots(snapshotDir, retain)
+		if err != nil {
+			return "", fmt.Errorf("Exported snapshot %v, but failed to remove old snapshots: %v", snapshotFile, err)
+		}
+		return fmt.Sprintf("Exported snapshot %v, removed %v old snapshots", snapshotFile, removed), nil
+	}
+}
+
+// compacter is implemented by the database backends supporting manual compaction
+type compacter interface {
+	Compact() error
+}
+
+// NewDBCompactionTask returns a task which compacts the database
+func NewDBCompactionTask(db database.Database) TaskFunc {
+	return func(ctx context.Context) (string, error) {
+		c, ok := db.(compacter)
+		if !ok {
+			return "", fmt.Errorf("The database backend does not support compaction")
+		}
+		start := time.Now()
+		if err := c.Compact(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Compacted the database in %v", time.Since(start)), nil
+	}
+}
+
+// StakeCompoundConfig configures the stake compounding task
+type StakeCompoundConfig struct {
+	ChainID string
+	Source  common.Address // the staker account, needs to be unlocked in the wallet
+	Holder  common.Address // the validator or guardian node to deposit the stake to
+	Purpose uint8
+	Reserve *big.Int // PTXWei to keep in the source account
+	Fee     *big.Int // PTXWei
+}
+
+// NewStakeCompoundTask returns a task which deposits the PTX balance of the source account
+// above the reserve, e.g. the accumulated staking rewards, as stake to the holder. The
+// deposit transaction is submitted to the local mempool and broadcasted to the peers.
+func NewStakeCompoundTask(ledger *ledger.Ledger, mempool *mempool.Mempool, wallet wtypes.Wallet,
+	config StakeCompoundConfig) TaskFunc {
+	return func(ctx context.Context) (string, error) {
+		// The screened view accounts for the pending transactions of the source account
+		view, err := ledger.GetScreenedSnapshot()
+		if err != nil {
+			return "", err
+		}
+		account := view.GetAccount(config.Source)
+		if account == nil {
+			return "", fmt.Errorf("Source account %v not found", config.Source.Hex())
+		}
+
+		blockHeight := view.Height() + 1
+		minStake := core.MinValidatorStakeDeposit
+		if config.Purpose == core.StakeForGuardian {
+			minStake = core.MinGuardianStakeDeposit
+			if features.IsEnabled(features.LowerGNStakeThresholdTo1000, blockHeight) {
+				minStake = core.MinGuardianStakeDeposit1000
+			}
+		}
+
+		balance := account.Balance.NoNil().PTXWei
+		amount := new(big.Int).Sub(balance, config.Reserve)
+		amount.Sub(amount, config.Fee)
+		if amount.Cmp(minStake) < 0 {
+			return fmt.Sprintf("Nothing to compound, balance %v PTXWei is below the minimum deposit %v PTXWei plus the reserve and fee",
+				balance, minStake), nil
+		}
+
+		fee := types.Coins{PandoWei: big.NewInt(0), PTXWei: config.Fee}
+		source := types.TxInput{
+			Address:  config.Source,
+			Coins:    types.Coins{PandoWei: big.NewInt(0), PTXWei: amount},
+			Sequence: account.Sequence + 1,
+		}
+		holder := types.TxOutput{
+			Address: config.Holder,
+		}
+		var tx types.Tx
+		if features.IsEnabled(features.Pando2, blockHeight) {
+			tx = &types.DepositStakeTxV2{Fee: fee, Source: source, Holder: holder, Purpose: config.Purpose}
+		} else {
+			tx = &types.DepositStakeTx{Fee: fee, Source: source, Holder: holder, Purpose: config.Purpose}
+		}
+		sig, err := wallet.Sign(config.Source, tx.SignBytes(config.ChainID))
+		if err != nil {
+			return "", err
+		}
+		switch depositTx := tx.(type) {
+		case *types.DepositStakeTxV2:
+			depositTx.SetSignature(config.Source, sig)
+		case *types.DepositStakeTx:
+			depositTx.SetSignature(config.Source, sig)
+		}
+
+		raw, err := types.TxToBytes(tx)
+		if err != nil {
+			return "", err
+		}
+		if err := mempool.InsertTransaction(raw); err != nil {
+			return "", err
+		}
+		mempool.BroadcastTx(raw)
+
+		return fmt.Sprintf("Deposited %v PTXWei stake from %v to %v, tx hash: %v", amount,
+			config.Source.Hex(), config.Holder.Hex(), crypto.Keccak256Hash(raw).Hex()), nil
+	}
+}
@@ -0,0 +1,171 @@
+package signer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// callTimeout is the max time to wait for the signer to respond.
+const callTimeout = 5 * time.Second
+
+// RemoteSigner implements crypto.Signer by forwarding the signing requests to a
+// pandosigner process, so that the private key is never loaded into the node process.
+type RemoteSigner struct {
+	dial   func() (net.Conn, error)
+	pubKey *crypto.PublicKey
+
+	mu     *sync.Mutex
+	client *rpc.Client
+}
+
+var _ crypto.Signer = (*RemoteSigner)(nil)
+
+// NewRemoteSigner connects to the signer listening on the given socket.
+func NewRemoteSigner(socketPath string) (*RemoteSigner, error) {
+	return newRemoteSigner(func() (net.Conn, error) {
+		return net.DialTimeout("unix", socketPath, callTimeout)
+	})
+}
+
+// NewRemoteSignerTLS connects to the signer listening on the given TCP address, e.g. a signer
+// shared by an active and a standby validator node. The connection is authenticated both ways
+// with the certificates of the TLS config.
+func NewRemoteSignerTLS(address string, config *tls.Config) (*RemoteSigner, error) {
+	return newRemoteSigner(func() (net.Conn, error) {
+		return tls.DialWithDialer(&net.Dialer{Timeout: callTimeout}, "tcp", address, config)
+	})
+}
+
+func newRemoteSigner(dial func() (net.Conn, error)) (*RemoteSigner, error) {
+	s := &RemoteSigner{
+		dial: dial,
+		mu:   &sync.Mutex{},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &PublicKeyResult{}
+	if err := s.call("PublicKey", &PublicKeyArgs{}, result); err != nil {
+		return nil, fmt.Errorf("Failed to get public key from signer: %v", err)
+	}
+	pubKey, err := crypto.PublicKeyFromBytes(result.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Signer returned invalid public key: %v", err)
+	}
+	s.pubKey = pubKey
+
+	return s, nil
+}
+
+// PublicKey returns the public key of the signer.
+func (s *RemoteSigner) PublicKey() *crypto.PublicKey {
+	return s.pubKey
+}
+
+// Sign requests the signer to sign the message. The returned signature is verified
+// against the public key before it is used.
+func (s *RemoteSigner) Sign(msg common.Bytes) (*crypto.Signature, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &SignResult{}
+	if err := s.call("Sign", &SignArgs{Message: msg}, result); err != nil {
+		return nil, err
+	}
+	return s.verify(msg, result.Signature)
+}
+
+// SignVote requests the signer to sign the vote on the block of the header. The signer refuses to
+// sign a vote conflicting with a vote it has signed before.
+func (s *RemoteSigner) SignVote(vote core.Vote, header *core.BlockHeader) (*crypto.Signature, error) {
+	if vote.Block != header.Hash() || vote.Height != header.Height {
+		return nil, errors.New("The vote is not on the block of the header")
+	}
+	raw, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &SignVoteResult{}
+	if err := s.call("SignVote", &SignVoteArgs{Header: raw, Epoch: vote.Epoch}, result); err != nil {
+		return nil, err
+	}
+	return s.verify(vote.SignBytes(), result.Signature)
+}
+
+// SignProposal requests the signer to sign the header of the proposed block. The signer refuses
+// to sign a proposal conflicting with a proposal it has signed before.
+func (s *RemoteSigner) SignProposal(header *core.BlockHeader) (*crypto.Signature, error) {
+	raw, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &SignProposalResult{}
+	if err := s.call("SignProposal", &SignProposalArgs{Header: raw}, result); err != nil {
+		return nil, err
+	}
+	return s.verify(header.SignBytes(), result.Signature)
+}
+
+// verify checks the signature returned by the signer is valid for the message.
+func (s *RemoteSigner) verify(msg common.Bytes, raw common.Bytes) (*crypto.Signature, error) {
+	sig, err := crypto.SignatureFromBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !s.pubKey.VerifySignature(msg, sig) {
+		return nil, errors.New("Signer returned invalid signature")
+	}
+	return sig, nil
+}
+
+// call invokes the method, reconnecting once if the connection is broken. Should be
+// called with the lock held.
+func (s *RemoteSigner) call(method string, args interface{}, result interface{}) (err error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.client == nil {
+			conn, err := s.dial()
+			if err != nil {
+				return err
+			}
+			s.client = jsonrpc.NewClient(conn)
+		}
+
+		call := s.client.Go(ServiceName+"."+method, args, result, nil)
+		select {
+		case <-call.Done:
+			err = call.Error
+		case <-time.After(callTimeout):
+			err = errors.New("Signer timed out")
+		}
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(rpc.ServerError); ok {
+			// Rejected by the signer, no need to retry
+			return err
+		}
+		s.client.Close()
+		s.client = nil
+	}
+	return err
+}
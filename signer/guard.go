@@ -0,0 +1,137 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// DefaultStateName is the name of the double signing protection state created under the config
+// folder by default.
+const DefaultStateName = "signer_state.json"
+
+// SignState is the last vote and the last proposal signed by the signer.
+type SignState struct {
+	VoteHeight uint64      `json:"vote_height"`
+	VoteEpoch  uint64      `json:"vote_epoch"`
+	VoteBlock  common.Hash `json:"vote_block"`
+
+	ProposalHeight uint64      `json:"proposal_height"`
+	ProposalEpoch  uint64      `json:"proposal_epoch"`
+	ProposalBlock  common.Hash `json:"proposal_block"` // hash of the sign bytes of the proposed header
+}
+
+// Guard keeps the signer from double signing, i.e. voting for two different blocks at the same
+// height or proposing two different blocks in the same epoch, which gets the validator slashed.
+// It only allows the votes and the proposals at or above the last ones signed, and persists the
+// last ones before they are signed, so that the nodes sharing the signer, e.g. an active and a
+// standby validator node, cannot equivocate even across the restarts of the signer.
+//
+// The guard has no view of the chain, it trusts the nodes with the blocks, not with the heights:
+// the height of a vote or a proposal comes from the header signed or voted on, which the block
+// hash commits to. A node can still make the signer refuse the later votes, e.g. with the header
+// of a fake block far above the tip, but not make it sign two blocks at the same height.
+type Guard struct {
+	mu    *sync.Mutex
+	path  string
+	state SignState
+}
+
+// LoadGuard loads the state of the guard from the file, which is created if not present.
+func LoadGuard(statePath string) (*Guard, error) {
+	g := &Guard{
+		mu:   &sync.Mutex{},
+		path: statePath,
+	}
+	raw, err := ioutil.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return g, g.save(g.state)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &g.state); err != nil {
+		return nil, fmt.Errorf("Failed to parse the signer state %v: %v", statePath, err)
+	}
+	return g, nil
+}
+
+// State returns the last vote and proposal signed.
+func (g *Guard) State() SignState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// AllowVote checks the vote does not conflict with the last vote signed, and records it as the
+// last vote. A vote on the last voted block can be signed again, e.g. in a later epoch.
+func (g *Guard) AllowVote(height uint64, epoch uint64, block common.Hash) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if height < g.state.VoteHeight {
+		return fmt.Errorf("Refused to vote at height %v, already voted at height %v", height, g.state.VoteHeight)
+	}
+	if height == g.state.VoteHeight && block != g.state.VoteBlock {
+		return fmt.Errorf("Refused to vote for block %v at height %v, already voted for block %v",
+			block.Hex(), height, g.state.VoteBlock.Hex())
+	}
+
+	state := g.state
+	state.VoteHeight, state.VoteEpoch, state.VoteBlock = height, epoch, block
+	return g.save(state)
+}
+
+// AllowProposal checks the proposal does not conflict with the last proposal signed, and records
+// it as the last proposal.
+func (g *Guard) AllowProposal(height uint64, epoch uint64, block common.Hash) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if epoch < g.state.ProposalEpoch {
+		return fmt.Errorf("Refused to propose in epoch %v, already proposed in epoch %v", epoch, g.state.ProposalEpoch)
+	}
+	if epoch == g.state.ProposalEpoch && block != g.state.ProposalBlock {
+		return fmt.Errorf("Refused to propose block %v in epoch %v, already proposed block %v",
+			block.Hex(), epoch, g.state.ProposalBlock.Hex())
+	}
+
+	state := g.state
+	state.ProposalHeight, state.ProposalEpoch, state.ProposalBlock = height, epoch, block
+	return g.save(state)
+}
+
+// save writes the state to disk before it is applied, so that nothing is signed unless recorded.
+// Should be called with the lock held.
+func (g *Guard) save(state SignState) error {
+	raw, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path.Join(path.Dir(g.path), "."+path.Base(g.path)+".tmp")
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(raw); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, g.path); err != nil {
+		return err
+	}
+	g.state = state
+	return nil
+}
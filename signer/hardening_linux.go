@@ -0,0 +1,145 @@
+//go:build linux
+// +build linux
+
+package signer
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Constants of the seccomp(2) interface not exported by golang.org/x/sys/unix.
+const (
+	seccompSetModeFilter   = 1
+	seccompFilterFlagTsync = 1
+	seccompRetErrno        = 0x00050000
+	seccompRetAllow        = 0x7fff0000
+
+	seccompDataNrOffset   = 0
+	seccompDataArchOffset = 4
+)
+
+// deniedSyscalls are the syscalls the signer never needs once the key is loaded and the
+// socket is listening. Denying them limits what an attacker can do with a compromised
+// signer, e.g. spawning processes, opening connections or inspecting other processes.
+var deniedSyscalls = []uintptr{
+	unix.SYS_EXECVE,
+	unix.SYS_EXECVEAT,
+	unix.SYS_PTRACE,
+	unix.SYS_PROCESS_VM_READV,
+	unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_SOCKET,
+	unix.SYS_CONNECT,
+	unix.SYS_MOUNT,
+	unix.SYS_UMOUNT2,
+	unix.SYS_PIVOT_ROOT,
+	unix.SYS_CHROOT,
+	unix.SYS_SETNS,
+	unix.SYS_UNSHARE,
+	unix.SYS_KEXEC_LOAD,
+	unix.SYS_INIT_MODULE,
+	unix.SYS_FINIT_MODULE,
+	unix.SYS_BPF,
+	unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_USERFAULTFD,
+	unix.SYS_KEYCTL,
+}
+
+// Harden minimizes the privileges of the signer process. It prevents core dumps and
+// ptrace attachment, locks the memory so the key is never swapped out, and installs a
+// seccomp filter denying the syscalls the signer does not need. It should be called
+// after the key is loaded and the socket is listening.
+func Harden() error {
+	if err := unix.Prctl(unix.PR_SET_DUMPABLE, 0, 0, 0, 0); err != nil {
+		return fmt.Errorf("Failed to disable core dumps: %v", err)
+	}
+	if err := unix.Mlockall(unix.MCL_CURRENT | unix.MCL_FUTURE); err != nil {
+		// Requires CAP_IPC_LOCK or a sufficient RLIMIT_MEMLOCK
+		logger.Warnf("Failed to lock memory, the key might be swapped to disk: %v", err)
+	}
+	if !seccompSupported {
+		logger.Warnf("Seccomp filter is not supported on %v", runtime.GOARCH)
+		return nil
+	}
+	return installSeccompFilter()
+}
+
+func installSeccompFilter() error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// Required to install a filter without CAP_SYS_ADMIN. Propagated to the other
+	// threads by the TSYNC flag below.
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("Failed to set no_new_privs: %v", err)
+	}
+
+	filter := []unix.SockFilter{
+		// Deny everything if the syscall is made with an unexpected ABI
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArchOffset),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, seccompAuditArch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetErrno|uint32(unix.EPERM)),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNrOffset),
+	}
+	for _, nr := range deniedSyscalls {
+		filter = append(filter,
+			bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, uint32(nr), 0, 1),
+			bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetErrno|uint32(unix.EPERM)),
+		)
+	}
+	filter = append(filter, bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetAllow))
+
+	prog := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, seccompSetModeFilter, seccompFilterFlagTsync, uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return fmt.Errorf("Failed to install seccomp filter: %v", errno)
+	}
+	return nil
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
+
+// checkPeerCredentials verifies the process on the other end of the socket is run by
+// one of the allowed users.
+func checkPeerCredentials(conn net.Conn, allowedUIDs []uint32) error {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return fmt.Errorf("Not a unix socket connection")
+	}
+	raw, err := unixConn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return err
+	}
+	if credErr != nil {
+		return credErr
+	}
+
+	for _, uid := range allowedUIDs {
+		if cred.Uid == uid {
+			return nil
+		}
+	}
+	return fmt.Errorf("User %v is not allowed to connect, pid: %v", cred.Uid, cred.Pid)
+}
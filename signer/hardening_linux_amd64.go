@@ -0,0 +1,9 @@
+//go:build linux && amd64
+// +build linux,amd64
+
+package signer
+
+const (
+	seccompSupported = true
+	seccompAuditArch = 0xc000003e // AUDIT_ARCH_X86_64
+)
@@ -0,0 +1,9 @@
+//go:build linux && arm64
+// +build linux,arm64
+
+package signer
+
+const (
+	seccompSupported = true
+	seccompAuditArch = 0xc00000b7 // AUDIT_ARCH_AARCH64
+)
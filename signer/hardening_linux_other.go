@@ -0,0 +1,9 @@
+//go:build linux && !amd64 && !arm64
+// +build linux,!amd64,!arm64
+
+package signer
+
+const (
+	seccompSupported = false
+	seccompAuditArch = 0
+)
@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package signer
+
+import (
+	"net"
+	"runtime"
+)
+
+// Harden is only supported on Linux.
+func Harden() error {
+	logger.Warnf("Process hardening is not supported on %v", runtime.GOOS)
+	return nil
+}
+
+// checkPeerCredentials is only supported on Linux, where the file permission of the
+// socket is the only access control.
+func checkPeerCredentials(conn net.Conn, allowedUIDs []uint32) error {
+	return nil
+}
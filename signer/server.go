@@ -0,0 +1,167 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pandotoken/pando/common/util"
+	"github.com/pandotoken/pando/crypto"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "signer"})
+
+// DefaultSocketName is the name of the socket created under the config folder by default.
+const DefaultSocketName = "signer.sock"
+
+// handshakeTimeout is the max time for a TLS client to complete the handshake.
+const handshakeTimeout = 10 * time.Second
+
+// Server serves the signing service over a unix domain socket, where only processes running
+// as one of the allowed users can connect, or over TCP with mutual TLS, where only the clients
+// with a certificate signed by the configured CA can connect.
+type Server struct {
+	socketPath  string
+	allowedUIDs []uint32
+	address     string
+	tlsConfig   *tls.Config
+	service     *SignerService
+	handler     *rpc.Server
+	listener    net.Listener
+
+	wg *sync.WaitGroup
+}
+
+// NewServer creates a new instance of the signer server. If allowedUIDs is empty, only
+// the user running the signer is allowed to connect.
+func NewServer(privKey *crypto.PrivateKey, socketPath string, allowedUIDs []uint32) *Server {
+	logger = util.GetLoggerForModule("signer")
+
+	if len(allowedUIDs) == 0 {
+		allowedUIDs = []uint32{uint32(os.Getuid())}
+	}
+
+	s := newServer(privKey)
+	s.socketPath = socketPath
+	s.allowedUIDs = allowedUIDs
+	return s
+}
+
+// NewTLSServer creates a new instance of the signer server listening on the TCP address. The
+// TLS config needs to require and verify the client certificates, see LoadTLSConfig.
+func NewTLSServer(privKey *crypto.PrivateKey, address string, tlsConfig *tls.Config) *Server {
+	logger = util.GetLoggerForModule("signer")
+
+	s := newServer(privKey)
+	s.address = address
+	s.tlsConfig = tlsConfig
+	return s
+}
+
+func newServer(privKey *crypto.PrivateKey) *Server {
+	service := &SignerService{privKey: privKey}
+	handler := rpc.NewServer()
+	handler.RegisterName(ServiceName, service)
+
+	return &Server{
+		service: service,
+		handler: handler,
+		wg:      &sync.WaitGroup{},
+	}
+}
+
+// SetGuard protects the signer from double signing with the guard. Should be called before Start.
+func (s *Server) SetGuard(guard *Guard) {
+	s.service.guard = guard
+}
+
+// Listen creates the socket. It is separated from Start so that the socket can be
+// created before the process drops its privileges.
+func (s *Server) Listen() error {
+	if s.tlsConfig != nil {
+		l, err := tls.Listen("tcp", s.address, s.tlsConfig)
+		if err != nil {
+			return err
+		}
+		s.listener = l
+		return nil
+	}
+
+	if _, err := os.Stat(s.socketPath); err == nil {
+		// Remove the socket left behind by a previous run
+		if err := os.Remove(s.socketPath); err != nil {
+			return err
+		}
+	}
+
+	l, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return err
+	}
+	// Owner and group only, the peer credentials are checked on each connection.
+	if err := os.Chmod(s.socketPath, 0660); err != nil {
+		l.Close()
+		return err
+	}
+	s.listener = l
+	return nil
+}
+
+// Start serves the connections in the background.
+func (s *Server) Start(ctx context.Context) {
+	logger.WithFields(log.Fields{"address": s.listener.Addr().String()}).Info("Signer started")
+
+	s.wg.Add(1)
+	go s.acceptRoutine()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		<-ctx.Done()
+		s.listener.Close()
+	}()
+}
+
+// Wait blocks until the server stops.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Server) acceptRoutine() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			logger.WithFields(log.Fields{"err": err}).Info("Signer stopped accepting connections")
+			return
+		}
+		if err := s.authenticate(conn); err != nil {
+			logger.WithFields(log.Fields{"err": err}).Warn("Rejected connection")
+			conn.Close()
+			continue
+		}
+		go s.handler.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// authenticate checks the peer is allowed to use the signer, by the user running it for a unix
+// socket, or by its certificate for TLS.
+func (s *Server) authenticate(conn net.Conn) error {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return checkPeerCredentials(conn, s.allowedUIDs)
+	}
+	tlsConn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	return tlsConn.SetDeadline(time.Time{})
+}
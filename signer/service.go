@@ -0,0 +1,185 @@
+package signer
+
+import (
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// ServiceName is the name under which the signing service is registered.
+const ServiceName = "signer"
+
+// MaxMessageSize is the largest message the signer agrees to sign. Votes, block headers
+// and the txs signed by the node are all well below this limit.
+const MaxMessageSize = 64 * 1024
+
+// SignerService exposes a narrow protocol: fetching the public key, signing the votes and the
+// proposals of the consensus, and signing the other messages. The private key never leaves the
+// signer process.
+//
+// With a guard, the votes and the proposals are checked against the double signing, and can
+// only be signed through SignVote and SignProposal.
+type SignerService struct {
+	privKey *crypto.PrivateKey
+	guard   *Guard
+}
+
+// ------------------------------- PublicKey -----------------------------------
+
+type PublicKeyArgs struct{}
+
+type PublicKeyResult struct {
+	PublicKey common.Bytes `json:"public_key"`
+}
+
+func (s *SignerService) PublicKey(args *PublicKeyArgs, result *PublicKeyResult) error {
+	result.PublicKey = s.privKey.PublicKey().ToBytes()
+	return nil
+}
+
+// ------------------------------- Sign -----------------------------------
+
+type SignArgs struct {
+	Message common.Bytes `json:"message"`
+}
+
+type SignResult struct {
+	Signature common.Bytes `json:"signature"`
+}
+
+func (s *SignerService) Sign(args *SignArgs, result *SignResult) error {
+	if len(args.Message) == 0 {
+		return errors.New("Message is empty")
+	}
+	if len(args.Message) > MaxMessageSize {
+		return errors.New("Message is too large")
+	}
+	if s.guard != nil && isConsensusMessage(args.Message) {
+		return errors.New("Votes and proposals need to be signed with SignVote and SignProposal")
+	}
+
+	sig, err := s.privKey.Sign(args.Message)
+	if err != nil {
+		return err
+	}
+	result.Signature = sig.ToBytes()
+
+	logger.WithFields(log.Fields{
+		"msgHash": crypto.Keccak256Hash(args.Message).Hex(),
+	}).Debug("Signed message")
+
+	return nil
+}
+
+// isConsensusMessage returns whether the message is the sign bytes of a vote or a block header.
+func isConsensusMessage(msg common.Bytes) bool {
+	if err := rlp.DecodeBytes(msg, &core.Vote{}); err == nil {
+		return true
+	}
+	if err := rlp.DecodeBytes(msg, &core.BlockHeader{}); err == nil {
+		return true
+	}
+	return false
+}
+
+// ------------------------------- SignVote -----------------------------------
+
+type SignVoteArgs struct {
+	Header common.Bytes `json:"header"` // RLP encoded header of the voted block
+	Epoch  uint64       `json:"epoch"`
+}
+
+type SignVoteResult struct {
+	Signature common.Bytes `json:"signature"`
+}
+
+// SignVote signs the vote of the signer on the block of the header. The height is not part of the
+// signed vote, so it is taken from the header, which the block hash commits to, rather than from
+// the node.
+func (s *SignerService) SignVote(args *SignVoteArgs, result *SignVoteResult) error {
+	if len(args.Header) > MaxMessageSize {
+		return errors.New("Header is too large")
+	}
+	header := &core.BlockHeader{}
+	if err := rlp.DecodeBytes(args.Header, header); err != nil {
+		return errors.New("Failed to decode the block header")
+	}
+	vote := core.Vote{
+		Block:  header.Hash(),
+		Height: header.Height,
+		Epoch:  args.Epoch,
+		ID:     s.privKey.PublicKey().Address(),
+	}
+	if s.guard != nil {
+		if err := s.guard.AllowVote(vote.Height, vote.Epoch, vote.Block); err != nil {
+			logger.WithFields(log.Fields{"err": err}).Warn("Refused to double sign")
+			return err
+		}
+	}
+
+	sig, err := s.privKey.Sign(vote.SignBytes())
+	if err != nil {
+		return err
+	}
+	result.Signature = sig.ToBytes()
+
+	logger.WithFields(log.Fields{
+		"block":  vote.Block.Hex(),
+		"height": vote.Height,
+		"epoch":  vote.Epoch,
+	}).Debug("Signed vote")
+
+	return nil
+}
+
+// ------------------------------- SignProposal -----------------------------------
+
+type SignProposalArgs struct {
+	Header common.Bytes `json:"header"` // RLP encoded block header
+}
+
+type SignProposalResult struct {
+	Signature common.Bytes `json:"signature"`
+}
+
+// SignProposal signs the header of the block proposed by the signer.
+func (s *SignerService) SignProposal(args *SignProposalArgs, result *SignProposalResult) error {
+	if len(args.Header) > MaxMessageSize {
+		return errors.New("Header is too large")
+	}
+	header := &core.BlockHeader{}
+	if err := rlp.DecodeBytes(args.Header, header); err != nil {
+		return errors.New("Failed to decode the block header")
+	}
+	if header.Proposer != s.privKey.PublicKey().Address() {
+		return errors.New("The block is not proposed by the signer")
+	}
+	// The block hash covers the signature, the proposal is identified by its sign bytes instead
+	signBytes := header.SignBytes()
+	proposal := crypto.Keccak256Hash(signBytes)
+	if s.guard != nil {
+		if err := s.guard.AllowProposal(header.Height, header.Epoch, proposal); err != nil {
+			logger.WithFields(log.Fields{"err": err}).Warn("Refused to double sign")
+			return err
+		}
+	}
+
+	sig, err := s.privKey.Sign(signBytes)
+	if err != nil {
+		return err
+	}
+	result.Signature = sig.ToBytes()
+
+	logger.WithFields(log.Fields{
+		"proposal": proposal.Hex(),
+		"height":   header.Height,
+		"epoch":    header.Epoch,
+	}).Debug("Signed proposal")
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rlp"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	privKey, pubKey, err := crypto.GenerateKeyPair()
+	require.Nil(err)
+
+	socketPath := path.Join(dir, "signer.sock")
+	server := NewServer(privKey, socketPath, nil)
+	require.Nil(server.Listen())
+	ctx, cancel := context.WithCancel(context.Background())
+	server.Start(ctx)
+	defer func() {
+		cancel()
+		server.Wait()
+	}()
+
+	signer, err := NewRemoteSigner(socketPath)
+	require.Nil(err)
+	assert.Equal(pubKey.Address(), signer.PublicKey().Address())
+
+	msg := []byte("hello pando")
+	sig, err := signer.Sign(msg)
+	require.Nil(err)
+	assert.True(pubKey.VerifySignature(msg, sig))
+
+	_, err = signer.Sign(make([]byte, MaxMessageSize+1))
+	assert.NotNil(err)
+
+	// The connection is still usable after a rejected request
+	sig, err = signer.Sign(msg)
+	require.Nil(err)
+	assert.True(pubKey.VerifySignature(msg, sig))
+}
+
+func TestRemoteSignerRejectsUnknownUser(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	privKey, _, err := crypto.GenerateKeyPair()
+	require.Nil(err)
+
+	socketPath := path.Join(dir, "signer.sock")
+	server := NewServer(privKey, socketPath, []uint32{uint32(os.Getuid()) + 1})
+	require.Nil(server.Listen())
+	ctx, cancel := context.WithCancel(context.Background())
+	server.Start(ctx)
+	defer func() {
+		cancel()
+		server.Wait()
+	}()
+
+	_, err = NewRemoteSigner(socketPath)
+	require.NotNil(err)
+}
+
+func TestGuard(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	statePath := path.Join(dir, DefaultStateName)
+	guard, err := LoadGuard(statePath)
+	require.Nil(err)
+
+	blockA := common.BytesToHash([]byte("a"))
+	blockB := common.BytesToHash([]byte("b"))
+	assert.Nil(guard.AllowVote(10, 5, blockA))
+	assert.Nil(guard.AllowVote(10, 6, blockA), "repeating the vote in a later epoch is allowed")
+	assert.NotNil(guard.AllowVote(10, 6, blockB), "voting for another block at the same height")
+	assert.NotNil(guard.AllowVote(9, 7, blockB), "voting below the last vote")
+	assert.Nil(guard.AllowVote(11, 7, blockB))
+
+	assert.Nil(guard.AllowProposal(12, 8, blockA))
+	assert.Nil(guard.AllowProposal(12, 8, blockA))
+	assert.NotNil(guard.AllowProposal(12, 8, blockB), "proposing another block in the same epoch")
+	assert.NotNil(guard.AllowProposal(13, 7, blockB), "proposing in an earlier epoch")
+
+	// The state survives the restarts of the signer
+	guard, err = LoadGuard(statePath)
+	require.Nil(err)
+	assert.Equal(uint64(11), guard.State().VoteHeight)
+	assert.Equal(blockB, guard.State().VoteBlock)
+	assert.Equal(uint64(8), guard.State().ProposalEpoch)
+	assert.NotNil(guard.AllowVote(11, 8, blockA))
+}
+
+func TestSharedRemoteSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	privKey, pubKey, err := crypto.GenerateKeyPair()
+	require.Nil(err)
+	guard, err := LoadGuard(path.Join(dir, DefaultStateName))
+	require.Nil(err)
+
+	socketPath := path.Join(dir, "signer.sock")
+	server := NewServer(privKey, socketPath, nil)
+	server.SetGuard(guard)
+	require.Nil(server.Listen())
+	ctx, cancel := context.WithCancel(context.Background())
+	server.Start(ctx)
+	defer func() {
+		cancel()
+		server.Wait()
+	}()
+
+	// The active and the standby node share the signer
+	active, err := NewRemoteSigner(socketPath)
+	require.Nil(err)
+	standby, err := NewRemoteSigner(socketPath)
+	require.Nil(err)
+
+	voted := &core.BlockHeader{ChainID: "testchain", Height: 10, Epoch: 5, Timestamp: big.NewInt(1)}
+	vote := core.Vote{Block: voted.Hash(), Height: 10, Epoch: 5, ID: pubKey.Address()}
+	sig, err := active.SignVote(vote, voted)
+	require.Nil(err)
+	vote.SetSignature(sig)
+	assert.True(vote.Validate().IsOK())
+	assert.Equal(uint64(10), guard.State().VoteHeight)
+
+	conflictingBlock := &core.BlockHeader{ChainID: "testchain", Height: 10, Epoch: 5, Timestamp: big.NewInt(2)}
+	conflicting := core.Vote{Block: conflictingBlock.Hash(), Height: 10, Epoch: 5, ID: pubKey.Address()}
+	_, err = standby.SignVote(conflicting, conflictingBlock)
+	assert.NotNil(err)
+
+	// The height of the vote is the height of the voted block, whatever the node reports
+	lying := conflicting
+	lying.Height = 11
+	_, err = standby.SignVote(lying, conflictingBlock)
+	assert.NotNil(err)
+	raw, err := rlp.EncodeToBytes(conflictingBlock)
+	require.Nil(err)
+	service := &SignerService{privKey: privKey, guard: guard}
+	assert.NotNil(service.SignVote(&SignVoteArgs{Header: raw, Epoch: 6}, &SignVoteResult{}))
+	assert.Equal(voted.Hash(), guard.State().VoteBlock)
+
+	// The votes and the proposals cannot bypass the guard as plain messages
+	_, err = standby.Sign(conflicting.SignBytes())
+	assert.NotNil(err)
+
+	header := &core.BlockHeader{ChainID: "testchain", Height: 11, Epoch: 6, Proposer: pubKey.Address()}
+	sig, err = active.SignProposal(header)
+	require.Nil(err)
+	assert.True(pubKey.VerifySignature(header.SignBytes(), sig))
+	_, err = standby.Sign(header.SignBytes())
+	assert.NotNil(err)
+
+	conflictingHeader := &core.BlockHeader{ChainID: "testchain", Height: 11, Epoch: 6, Proposer: pubKey.Address(), Timestamp: big.NewInt(1)}
+	_, err = standby.SignProposal(conflictingHeader)
+	assert.NotNil(err)
+
+	// The other messages, e.g. the txs, are still signed
+	tx := &types.SendTx{
+		Fee:     types.NewCoins(0, 1),
+		Inputs:  []types.TxInput{{Address: pubKey.Address(), Coins: types.NewCoins(0, 2), Sequence: 1}},
+		Outputs: []types.TxOutput{{Address: pubKey.Address(), Coins: types.NewCoins(0, 1)}},
+	}
+	_, err = standby.Sign(tx.SignBytes("testchain"))
+	assert.Nil(err)
+}
+
+func TestRemoteSignerTLS(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	ca, caKey := writeTestCert(t, dir, "ca", nil, nil)
+	writeTestCert(t, dir, "signer", ca, caKey)
+	writeTestCert(t, dir, "node", ca, caKey)
+	writeTestCert(t, dir, "rogue", nil, nil)
+
+	serverConfig, err := LoadTLSConfig(path.Join(dir, "signer.crt"), path.Join(dir, "signer.key"), path.Join(dir, "ca.crt"))
+	require.Nil(err)
+	privKey, pubKey, err := crypto.GenerateKeyPair()
+	require.Nil(err)
+	server := NewTLSServer(privKey, "127.0.0.1:0", serverConfig)
+	require.Nil(server.Listen())
+	ctx, cancel := context.WithCancel(context.Background())
+	server.Start(ctx)
+	defer func() {
+		cancel()
+		server.Wait()
+	}()
+	address := server.listener.Addr().String()
+
+	clientConfig, err := LoadTLSConfig(path.Join(dir, "node.crt"), path.Join(dir, "node.key"), path.Join(dir, "ca.crt"))
+	require.Nil(err)
+	signer, err := NewRemoteSignerTLS(address, clientConfig)
+	require.Nil(err)
+	assert.Equal(pubKey.Address(), signer.PublicKey().Address())
+
+	// A client certificate not signed by the CA is rejected
+	rogueConfig, err := LoadTLSConfig(path.Join(dir, "rogue.crt"), path.Join(dir, "rogue.key"), path.Join(dir, "ca.crt"))
+	require.Nil(err)
+	_, err = NewRemoteSignerTLS(address, rogueConfig)
+	assert.NotNil(err)
+}
+
+// writeTestCert writes the certificate and the key of the given name into the directory, signed by
+// the parent, or self-signed as a CA if the parent is nil.
+func writeTestCert(t *testing.T, dir string, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.Nil(t, err)
+	rawKey, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	require.Nil(t, ioutil.WriteFile(path.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}), 0600))
+	require.Nil(t, ioutil.WriteFile(path.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey}), 0600))
+	return cert, key
+}
@@ -0,0 +1,185 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/sha3"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// BundleManifestFile is the name of the manifest file of a snapshot bundle
+const BundleManifestFile = "manifest.json"
+
+// bundleChunkSize is the size of the chunks a snapshot bundle is split into
+var bundleChunkSize uint64 = 64 * 1024 * 1024 // 64 MBytes
+
+// BundleManifest describes a snapshot bundle, i.e. a snapshot file split into chunks, so that it can
+// be copied around in parts. The manifest records the checksum of each chunk and of the whole file,
+// and is signed by the node which exported the snapshot, so that an operator can check where a
+// snapshot comes from and that it is intact before importing it.
+type BundleManifest struct {
+	ChainID      string        `json:"chain_id"`
+	Height       uint64        `json:"height"`
+	BlockHash    common.Hash   `json:"block_hash"`
+	StateHash    common.Hash   `json:"state_hash"`
+	SnapshotHash common.Hash   `json:"snapshot_hash"` // hash of the whole snapshot file
+	Size         uint64        `json:"size"`
+	ChunkSize    uint64        `json:"chunk_size"`
+	ChunkHashes  []common.Hash `json:"chunk_hashes"`
+
+	Signer    common.Address    `json:"signer"`
+	Signature *crypto.Signature `json:"signature"`
+}
+
+// SignBytes returns the bytes the exporting node signs, which cover everything but the signature
+func (m *BundleManifest) SignBytes() common.Bytes {
+	raw, err := rlp.EncodeToBytes([]interface{}{
+		"snapshot_bundle", m.ChainID, m.Height, m.BlockHash, m.StateHash, m.SnapshotHash,
+		m.Size, m.ChunkSize, m.ChunkHashes, m.Signer,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode the snapshot bundle manifest: %v", err))
+	}
+	return raw
+}
+
+// Validate checks that the chunk hashes cover the snapshot, and that the manifest is signed by
+// its signer.
+func (m *BundleManifest) Validate() error {
+	if m.Size == 0 || m.ChunkSize == 0 {
+		return errors.New("Empty snapshot bundle")
+	}
+	numChunks := (m.Size + m.ChunkSize - 1) / m.ChunkSize
+	if uint64(len(m.ChunkHashes)) != numChunks {
+		return fmt.Errorf("Expected %v chunk hashes, got %v", numChunks, len(m.ChunkHashes))
+	}
+	if m.Signature == nil || !m.Signature.Verify(m.SignBytes(), m.Signer) {
+		return fmt.Errorf("Invalid signature of the snapshot bundle, expected signer %v", m.Signer.Hex())
+	}
+	return nil
+}
+
+func (m *BundleManifest) String() string {
+	return fmt.Sprintf("BundleManifest{ChainID: %v, Height: %v, BlockHash: %v, StateHash: %v, Size: %v, Chunks: %v, Signer: %v}",
+		m.ChainID, m.Height, m.BlockHash.Hex(), m.StateHash.Hex(), m.Size, len(m.ChunkHashes), m.Signer.Hex())
+}
+
+func bundleChunkFile(dir string, index int) string {
+	return path.Join(dir, fmt.Sprintf("chunk-%06d", index))
+}
+
+// WriteBundle splits the snapshot file taken at the given block into chunks under the directory,
+// and writes the manifest of the chunks signed by the signer.
+func WriteBundle(snapshotFilePath string, header *core.BlockHeader, dir string, signer crypto.Signer) (*BundleManifest, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(snapshotFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	manifest := &BundleManifest{
+		ChainID:   header.ChainID,
+		Height:    header.Height,
+		BlockHash: header.Hash(),
+		StateHash: header.StateHash,
+		ChunkSize: bundleChunkSize,
+		Signer:    signer.PublicKey().Address(),
+	}
+	fileHasher := sha3.NewKeccak256()
+	buf := make([]byte, bundleChunkSize)
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 {
+			chunk := buf[:n]
+			if err := ioutil.WriteFile(bundleChunkFile(dir, len(manifest.ChunkHashes)), chunk, 0600); err != nil {
+				return nil, err
+			}
+			fileHasher.Write(chunk)
+			manifest.Size += uint64(n)
+			manifest.ChunkHashes = append(manifest.ChunkHashes, crypto.Keccak256Hash(chunk))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	manifest.SnapshotHash = common.BytesToHash(fileHasher.Sum(nil))
+
+	manifest.Signature, err = signer.Sign(manifest.SignBytes())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to sign the snapshot bundle: %v", err)
+	}
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path.Join(dir, BundleManifestFile), raw, 0600); err != nil {
+		return nil, err
+	}
+	logger.Infof("Exported snapshot bundle %v: %v", dir, manifest)
+	return manifest, nil
+}
+
+// LoadBundleManifest reads the manifest of the snapshot bundle under the directory, and validates it.
+func LoadBundleManifest(dir string) (*BundleManifest, error) {
+	raw, err := ioutil.ReadFile(path.Join(dir, BundleManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BundleManifest{}
+	if err := json.Unmarshal(raw, manifest); err != nil {
+		return nil, fmt.Errorf("Failed to parse the snapshot bundle manifest: %v", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// AssembleBundle checks the chunks of the snapshot bundle under the directory against the manifest,
+// and concatenates them into the snapshot file.
+func AssembleBundle(dir string, manifest *BundleManifest, snapshotFilePath string) error {
+	file, err := os.OpenFile(snapshotFilePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileHasher := sha3.NewKeccak256()
+	size := uint64(0)
+	for i, expected := range manifest.ChunkHashes {
+		chunk, err := ioutil.ReadFile(bundleChunkFile(dir, i))
+		if err != nil {
+			return err
+		}
+		if hash := crypto.Keccak256Hash(chunk); hash != expected {
+			return fmt.Errorf("Checksum mismatch of chunk %v: expected %v, got %v", i, expected.Hex(), hash.Hex())
+		}
+		if _, err := file.Write(chunk); err != nil {
+			return err
+		}
+		fileHasher.Write(chunk)
+		size += uint64(len(chunk))
+	}
+	if size != manifest.Size {
+		return fmt.Errorf("Snapshot size mismatch: expected %v, got %v", manifest.Size, size)
+	}
+	if hash := common.BytesToHash(fileHasher.Sum(nil)); hash != manifest.SnapshotHash {
+		return fmt.Errorf("Snapshot checksum mismatch: expected %v, got %v", manifest.SnapshotHash.Hex(), hash.Hex())
+	}
+	return file.Sync()
+}
@@ -0,0 +1,76 @@
+package snapshot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+)
+
+func TestSnapshotBundle(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "snapshot_bundle_test_")
+	if err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(size uint64) { bundleChunkSize = size }(bundleChunkSize)
+	bundleChunkSize = 1000
+
+	content := make([]byte, 4500)
+	rand.Read(content)
+	snapshotFile := path.Join(dir, "snapshot")
+	if err := ioutil.WriteFile(snapshotFile, content, 0600); err != nil {
+		t.Fatalf("failed to write the snapshot: %v", err)
+	}
+
+	privKey, _, _ := crypto.GenerateKeyPair()
+	header := &core.BlockHeader{ChainID: "testchain", Height: 100, StateHash: common.HexToHash("0x1234")}
+	bundleDir := path.Join(dir, "bundle")
+	written, err := WriteBundle(snapshotFile, header, bundleDir, privKey)
+	if err != nil {
+		t.Fatalf("failed to write the bundle: %v", err)
+	}
+	if len(written.ChunkHashes) != 5 {
+		t.Fatalf("expected 5 chunks, got %v", len(written.ChunkHashes))
+	}
+
+	manifest, err := LoadBundleManifest(bundleDir)
+	if err != nil {
+		t.Fatalf("failed to load the manifest: %v", err)
+	}
+	if manifest.Signer != privKey.PublicKey().Address() || manifest.Height != 100 || manifest.BlockHash != header.Hash() {
+		t.Fatalf("unexpected manifest: %v", manifest)
+	}
+
+	assembled := path.Join(dir, "assembled")
+	if err := AssembleBundle(bundleDir, manifest, assembled); err != nil {
+		t.Fatalf("failed to assemble the bundle: %v", err)
+	}
+	raw, _ := ioutil.ReadFile(assembled)
+	if !bytes.Equal(raw, content) {
+		t.Fatalf("assembled snapshot differs from the exported one")
+	}
+
+	// A tampered manifest fails the signature check
+	manifest.Height = 101
+	if err := manifest.Validate(); err == nil {
+		t.Fatalf("tampered manifest passed the validation")
+	}
+	manifest.Height = 100
+
+	// A tampered chunk fails the checksum
+	chunk := bundleChunkFile(bundleDir, 2)
+	raw, _ = ioutil.ReadFile(chunk)
+	raw[0] ^= 0xff
+	ioutil.WriteFile(chunk, raw, 0600)
+	if err := AssembleBundle(bundleDir, manifest, assembled); err == nil {
+		t.Fatalf("tampered chunk passed the checksum")
+	}
+}
@@ -33,6 +33,12 @@ func ExcludeTxs(txs []common.Bytes, exclusionTxMap map[string]bool, chain *block
 		if _, ok := t.(*types.WithdrawStakeTx); ok {
 			continue
 		}
+		if _, ok := t.(*types.DelegateTx); ok {
+			continue
+		}
+		if _, ok := t.(*types.UndelegateTx); ok {
+			continue
+		}
 
 		hash := crypto.Keccak256Hash(tx).Hex()
 		if _, ok := exclusionTxMap[hash]; !ok {
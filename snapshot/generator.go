@@ -0,0 +1,182 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pandotoken/pando/blockchain"
+	cns "github.com/pandotoken/pando/consensus"
+	"github.com/pandotoken/pando/store/database"
+)
+
+// generatorPollInterval is how often the generator checks the last finalized block
+var generatorPollInterval = 5 * time.Second
+
+// generatingDir is the sub-directory the snapshots are written into, and moved out of once
+// complete, so that a partial snapshot is never picked up, e.g. by the snapshot sync
+const generatingDir = ".generating"
+
+// Generator exports a snapshot every given number of finalized blocks in the background, with
+// its writes throttled, and keeps the most recent snapshots in its directory.
+type Generator struct {
+	db         database.Database
+	consensus  *cns.ConsensusEngine
+	chain      *blockchain.Chain
+	dir        string
+	interval   uint64
+	retain     int
+	writeLimit int64
+
+	lastHeight uint64 // height of the last finalized block when the last snapshot was due
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewGenerator creates a generator writing snapshots into dir every interval finalized blocks,
+// at most writeLimit bytes per second (unlimited if 0), and keeping the retain most recent
+// snapshots (all if 0)
+func NewGenerator(db database.Database, consensus *cns.ConsensusEngine, chain *blockchain.Chain,
+	dir string, interval uint64, retain int, writeLimit int64) *Generator {
+	return &Generator{
+		db:         db,
+		consensus:  consensus,
+		chain:      chain,
+		dir:        dir,
+		interval:   interval,
+		retain:     retain,
+		writeLimit: writeLimit,
+		wg:         &sync.WaitGroup{},
+	}
+}
+
+// Start starts the background goroutine.
+func (g *Generator) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	g.ctx = c
+	g.cancel = cancel
+
+	// The first snapshot is generated once the next multiple of the interval is finalized
+	g.lastHeight = g.consensus.GetLastFinalizedBlock().Height
+
+	g.wg.Add(1)
+	go g.mainLoop()
+}
+
+// Stop notifies the background goroutine to stop without blocking.
+func (g *Generator) Stop() {
+	g.cancel()
+}
+
+// Wait blocks until the background goroutine stops. A snapshot being generated is completed first.
+func (g *Generator) Wait() {
+	g.wg.Wait()
+}
+
+func (g *Generator) mainLoop() {
+	defer g.wg.Done()
+
+	ticker := time.NewTicker(generatorPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		height := g.consensus.GetLastFinalizedBlock().Height
+		if !g.due(height) {
+			continue
+		}
+		start := time.Now()
+		filename, err := g.generate()
+		if err != nil {
+			logger.Errorf("Failed to generate the snapshot at height %v: %v", height, err)
+			continue
+		}
+		logger.Infof("Generated snapshot %v in %v", filename, time.Since(start))
+
+		removed, err := PruneSnapshots(g.dir, g.retain)
+		if err != nil {
+			logger.Errorf("Failed to remove the old snapshots: %v", err)
+		} else if removed > 0 {
+			logger.Infof("Removed %v old snapshots", removed)
+		}
+	}
+}
+
+// due returns whether a multiple of the interval has been finalized since the last snapshot
+func (g *Generator) due(height uint64) bool {
+	if height/g.interval <= g.lastHeight/g.interval {
+		return false
+	}
+	g.lastHeight = height
+	return true
+}
+
+// generate exports the snapshot of the last finalized block. The export panics on the write
+// errors, which should not bring down the node.
+func (g *Generator) generate() (filename string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	tmpDir := path.Join(g.dir, generatingDir)
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	filename, err = ExportThrottledSnapshot(g.db, g.consensus, g.chain, tmpDir, 0, g.writeLimit)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(path.Join(tmpDir, filename), path.Join(g.dir, filename)); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// PruneSnapshots removes all but the most recent retain snapshots in the directory
+func PruneSnapshots(snapshotDir string, retain int) (int, error) {
+	if retain <= 0 {
+		return 0, nil
+	}
+	files, err := ioutil.ReadDir(snapshotDir)
+	if err != nil {
+		return 0, err
+	}
+	snapshots := []os.FileInfo{}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasPrefix(file.Name(), SnapshotFilePrefix) {
+			snapshots = append(snapshots, file)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ModTime().After(snapshots[j].ModTime())
+	})
+
+	removed := 0
+	for i := retain; i < len(snapshots); i++ {
+		if err := os.Remove(path.Join(snapshotDir, snapshots[i].Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
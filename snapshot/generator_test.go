@@ -0,0 +1,60 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestGeneratorDue(t *testing.T) {
+	g := &Generator{interval: 100, lastHeight: 150}
+	for _, c := range []struct {
+		height uint64
+		due    bool
+	}{
+		{150, false},
+		{199, false},
+		{203, true}, // crossed 200
+		{250, false},
+		{450, true}, // crossed 300 and 400, a single snapshot is due
+		{499, false},
+		{500, true},
+	} {
+		if due := g.due(c.height); due != c.due {
+			t.Fatalf("height %v: expected due %v, got %v", c.height, c.due, due)
+		}
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "snapshot_prune_test_")
+	if err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	for i, name := range []string{"pando_snapshot-1", "pando_snapshot-2", "pando_snapshot-3", "other"} {
+		file := path.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(name), 0600); err != nil {
+			t.Fatalf("failed to write %v: %v", name, err)
+		}
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(file, modTime, modTime)
+	}
+
+	removed, err := PruneSnapshots(dir, 2)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected 1 snapshot removed, got %v, err: %v", removed, err)
+	}
+	for _, name := range []string{"pando_snapshot-2", "pando_snapshot-3", "other"} {
+		if _, err := os.Stat(path.Join(dir, name)); err != nil {
+			t.Fatalf("%v should be kept: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(path.Join(dir, "pando_snapshot-1")); !os.IsNotExist(err) {
+		t.Fatalf("the oldest snapshot should be removed")
+	}
+}
@@ -0,0 +1,96 @@
+package snapshot
+
+import (
+	"bufio"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/core"
+	"github.com/pandotoken/pando/crypto"
+)
+
+func writeGenesisSnapshot(t *testing.T, filePath string, stateHash common.Hash) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatalf("failed to create the snapshot: %v", err)
+	}
+	defer file.Close()
+
+	genesis := &core.BlockHeader{
+		ChainID:   "testchain",
+		Height:    core.GenesisBlockHeight,
+		StateHash: stateHash,
+		Timestamp: big.NewInt(0),
+	}
+	metadata := &core.SnapshotMetadata{}
+	metadata.TailTrio.Second.Header = genesis
+	if err := core.WriteMetadata(bufio.NewWriter(file), metadata); err != nil {
+		t.Fatalf("failed to write the snapshot: %v", err)
+	}
+}
+
+func TestVerifyGenesisSignatures(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "genesis_signature_test_")
+	if err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer viper.Set(common.CfgGenesisSigners, []string{})
+
+	snapshotFile := path.Join(dir, "snapshot")
+	sigFile := path.Join(dir, "genesis.sig")
+	writeGenesisSnapshot(t, snapshotFile, common.HexToHash("0x1001"))
+	genesis, err := LoadSnapshotGenesisHeader(snapshotFile)
+	if err != nil {
+		t.Fatalf("failed to load the genesis block: %v", err)
+	}
+
+	// Not checked without trusted signers
+	if err := VerifyGenesisSignatures(genesis, sigFile); err != nil {
+		t.Fatalf("unexpected error without trusted signers: %v", err)
+	}
+
+	privKey, _, _ := crypto.GenerateKeyPair()
+	viper.Set(common.CfgGenesisSigners, []string{privKey.PublicKey().Address().Hex()})
+	if err := VerifyGenesisSignatures(genesis, sigFile); err == nil {
+		t.Fatalf("unsigned genesis block passed the verification")
+	}
+
+	sigs := &core.GenesisSignatures{ChainID: genesis.ChainID, GenesisHash: genesis.Hash()}
+	sig, err := privKey.Sign(sigs.SignBytes())
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if err := sigs.AddSignature(privKey.PublicKey().Address(), sig); err != nil {
+		t.Fatalf("failed to add the signature: %v", err)
+	}
+	if err := core.WriteGenesisSignatures(sigFile, sigs); err != nil {
+		t.Fatalf("failed to write the signatures: %v", err)
+	}
+	if err := VerifyGenesisSignatures(genesis, sigFile); err != nil {
+		t.Fatalf("signed genesis block failed the verification: %v", err)
+	}
+
+	// A genesis file with tampered allocations fails the verification
+	writeGenesisSnapshot(t, snapshotFile, common.HexToHash("0x1002"))
+	tampered, err := LoadSnapshotGenesisHeader(snapshotFile)
+	if err != nil {
+		t.Fatalf("failed to load the genesis block: %v", err)
+	}
+	if err := VerifyGenesisSignatures(tampered, sigFile); err == nil {
+		t.Fatalf("tampered genesis block passed the verification")
+	}
+
+	// The signature of another key does not count
+	other, _, _ := crypto.GenerateKeyPair()
+	viper.Set(common.CfgGenesisSigners, []string{other.PublicKey().Address().Hex()})
+	if err := VerifyGenesisSignatures(genesis, sigFile); err == nil {
+		t.Fatalf("genesis block signed by an untrusted key passed the verification")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"strconv"
@@ -15,12 +16,22 @@ import (
 	"github.com/pandotoken/pando/core"
 	"github.com/pandotoken/pando/ledger/state"
 	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/p2p/connection/flowrate"
 	"github.com/pandotoken/pando/store/database"
 	"github.com/pandotoken/pando/store/kvstore"
 	"github.com/pandotoken/pando/store/treestore"
 )
 
+// SnapshotFilePrefix is the file name prefix of the exported snapshots
+const SnapshotFilePrefix = "pando_snapshot-"
+
 func ExportSnapshot(db database.Database, consensus *cns.ConsensusEngine, chain *blockchain.Chain, snapshotDir string, height uint64) (string, error) {
+	return ExportThrottledSnapshot(db, consensus, chain, snapshotDir, height, 0)
+}
+
+// ExportThrottledSnapshot exports the snapshot like ExportSnapshot, writing at most writeLimit bytes
+// per second (unlimited if 0), so that an export in the background leaves the IO to the node.
+func ExportThrottledSnapshot(db database.Database, consensus *cns.ConsensusEngine, chain *blockchain.Chain, snapshotDir string, height uint64, writeLimit int64) (string, error) {
 	var lastFinalizedBlock *core.ExtendedBlock
 	if height != 0 {
 		blocks := chain.FindBlocksByHeight(height)
@@ -45,14 +56,18 @@ func ExportSnapshot(db database.Database, consensus *cns.ConsensusEngine, chain
 	sv := state.NewStoreView(lastFinalizedBlock.Height, lastFinalizedBlock.BlockHeader.StateHash, db)
 
 	currentTime := time.Now().UTC()
-	filename := "pando_snapshot-" + strconv.FormatUint(sv.Height(), 10) + "-" + sv.Hash().String() + "-" + currentTime.Format("2006-01-02")
+	filename := SnapshotFilePrefix + strconv.FormatUint(sv.Height(), 10) + "-" + sv.Hash().String() + "-" + currentTime.Format("2006-01-02")
 	snapshotPath := path.Join(snapshotDir, filename)
 	file, err := os.Create(snapshotPath)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
-	writer := bufio.NewWriter(file)
+	var output io.Writer = file
+	if writeLimit > 0 {
+		output = flowrate.NewWriter(file, writeLimit)
+	}
+	writer := bufio.NewWriter(output)
 
 	// --------------- Export the Header Section --------------- //
 
@@ -96,89 +111,15 @@ func ExportSnapshot(db database.Database, consensus *cns.ConsensusEngine, chain
 
 	metadata := &core.SnapshotMetadata{}
 	var genesisBlockHeader *core.BlockHeader
-	kvStore := kvstore.NewKVStore(db)
 	hl := sv.GetStakeTransactionHeightList().Heights
 	for _, height := range hl {
-		// check kvstore first
-		blockTrio := &core.SnapshotBlockTrio{}
-		blockTrioKey := []byte(core.BlockTrioStoreKeyPrefix + strconv.FormatUint(height, 10))
-		err := kvStore.Get(blockTrioKey, blockTrio)
-		if err == nil {
-			metadata.ProofTrios = append(metadata.ProofTrios, *blockTrio)
-			if height == core.GenesisBlockHeight {
-				genesisBlockHeader = blockTrio.Second.Header
-			}
-			continue
+		blockTrio, err := GetProofTrio(chain, db, height)
+		if err != nil {
+			return "", err
 		}
-
+		metadata.ProofTrios = append(metadata.ProofTrios, *blockTrio)
 		if height == core.GenesisBlockHeight {
-			blocks := chain.FindBlocksByHeight(core.GenesisBlockHeight)
-			genesisBlock := blocks[0]
-			genesisBlockHeader = genesisBlock.BlockHeader
-			metadata.ProofTrios = append(metadata.ProofTrios,
-				core.SnapshotBlockTrio{
-					First:  core.SnapshotFirstBlock{},
-					Second: core.SnapshotSecondBlock{Header: genesisBlock.BlockHeader},
-					Third:  core.SnapshotThirdBlock{},
-				})
-		} else {
-			blocks := chain.FindBlocksByHeight(height)
-			foundDirectlyFinalizedBlock := false
-			for _, block := range blocks {
-				if block.Status.IsDirectlyFinalized() {
-					var child, grandChild core.BlockHeader
-					b, err := getFinalizedChild(block, chain)
-					if err != nil {
-						return "", err
-					}
-					if b != nil {
-						child = *b.BlockHeader
-						b, err = getFinalizedChild(b, chain)
-						if err != nil {
-							return "", err
-						}
-						if b != nil {
-							grandChild = *b.BlockHeader
-						} else {
-							return "", fmt.Errorf("Can't find finalized grandchild block. " +
-								"Likely the last finalized block also contains stake change transactions. " +
-								"Please try again in 30 seconds.")
-						}
-					} else {
-						return "", fmt.Errorf("Can't find finalized child block. " +
-							"Likely the last finalized block also contains stake change transactions. " +
-							"Please try again in 30 seconds.")
-					}
-
-					if child.HCC.BlockHash != block.Hash() || grandChild.HCC.BlockHash != child.Hash() {
-						return "", fmt.Errorf("Invalid block HCC link for validator set changes")
-					}
-					if grandChild.HCC.Votes.IsEmpty() {
-						return "", fmt.Errorf("Missing block HCC votes for validator set changes")
-					}
-					for _, vote := range grandChild.HCC.Votes.Votes() {
-						if vote.Block != child.Hash() {
-							return "", fmt.Errorf("Invalid block HCC votes for validator set changes")
-						}
-					}
-
-					vcpProof, err := proveVCP(block, db)
-					if err != nil {
-						return "", fmt.Errorf("Failed to get VCP Proof")
-					}
-					metadata.ProofTrios = append(metadata.ProofTrios,
-						core.SnapshotBlockTrio{
-							First:  core.SnapshotFirstBlock{Header: block.BlockHeader, Proof: *vcpProof},
-							Second: core.SnapshotSecondBlock{Header: &child},
-							Third:  core.SnapshotThirdBlock{Header: &grandChild},
-						})
-					foundDirectlyFinalizedBlock = true
-					break
-				}
-			}
-			if !foundDirectlyFinalizedBlock {
-				return "", fmt.Errorf("Finalized block not found for height %v", height)
-			}
+			genesisBlockHeader = blockTrio.Second.Header
 		}
 	}
 
@@ -244,6 +185,104 @@ func proveVCP(block *core.ExtendedBlock, db database.Database) (*core.VCPProof,
 	return vp, err
 }
 
+// GetProofTrio returns the block trio proving the validator set change at the given height of the
+// stake transaction height list, i.e. the directly finalized block at the height with the VCP proof
+// of its state, along with its finalized child and grandchild. The trio of the genesis block only
+// carries the genesis block header.
+func GetProofTrio(chain *blockchain.Chain, db database.Database, height uint64) (*core.SnapshotBlockTrio, error) {
+	// check kvstore first
+	blockTrio := &core.SnapshotBlockTrio{}
+	blockTrioKey := []byte(core.BlockTrioStoreKeyPrefix + strconv.FormatUint(height, 10))
+	err := kvstore.NewKVStore(db).Get(blockTrioKey, blockTrio)
+	if err == nil {
+		return blockTrio, nil
+	}
+
+	if height == core.GenesisBlockHeight {
+		blocks := chain.FindBlocksByHeight(core.GenesisBlockHeight)
+		if len(blocks) == 0 {
+			return nil, fmt.Errorf("Genesis block not found")
+		}
+		genesisBlock := blocks[0]
+		return &core.SnapshotBlockTrio{
+			First:  core.SnapshotFirstBlock{},
+			Second: core.SnapshotSecondBlock{Header: genesisBlock.BlockHeader},
+			Third:  core.SnapshotThirdBlock{},
+		}, nil
+	}
+	for _, block := range chain.FindBlocksByHeight(height) {
+		if block.Status.IsDirectlyFinalized() {
+			return getBlockTrio(block, chain, db, true)
+		}
+	}
+	return nil, fmt.Errorf("Finalized block not found for height %v", height)
+}
+
+// GetFinalizedBlockTrio returns the finalized block at the given height along with its finalized
+// child and grandchild, whose HCC links and votes prove the finalization of the block.
+func GetFinalizedBlockTrio(chain *blockchain.Chain, db database.Database, height uint64) (*core.SnapshotBlockTrio, error) {
+	for _, block := range chain.FindBlocksByHeight(height) {
+		if block.Status.IsFinalized() {
+			return getBlockTrio(block, chain, db, false)
+		}
+	}
+	return nil, fmt.Errorf("Finalized block not found for height %v", height)
+}
+
+// getBlockTrio returns the trio of the finalized block, with the VCP proof of the state of the
+// block if withVCPProof is set.
+func getBlockTrio(block *core.ExtendedBlock, chain *blockchain.Chain, db database.Database, withVCPProof bool) (*core.SnapshotBlockTrio, error) {
+	var child, grandChild core.BlockHeader
+	b, err := getFinalizedChild(block, chain)
+	if err != nil {
+		return nil, err
+	}
+	if b != nil {
+		child = *b.BlockHeader
+		b, err = getFinalizedChild(b, chain)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			grandChild = *b.BlockHeader
+		} else {
+			return nil, fmt.Errorf("Can't find finalized grandchild block. " +
+				"Likely the last finalized block also contains stake change transactions. " +
+				"Please try again in 30 seconds.")
+		}
+	} else {
+		return nil, fmt.Errorf("Can't find finalized child block. " +
+			"Likely the last finalized block also contains stake change transactions. " +
+			"Please try again in 30 seconds.")
+	}
+
+	if child.HCC.BlockHash != block.Hash() || grandChild.HCC.BlockHash != child.Hash() {
+		return nil, fmt.Errorf("Invalid block HCC link for validator set changes")
+	}
+	if grandChild.HCC.Votes.IsEmpty() && grandChild.HCC.Aggregated == nil {
+		return nil, fmt.Errorf("Missing block HCC votes for validator set changes")
+	}
+	for _, vote := range grandChild.HCC.Votes.Votes() {
+		if vote.Block != child.Hash() {
+			return nil, fmt.Errorf("Invalid block HCC votes for validator set changes")
+		}
+	}
+
+	first := core.SnapshotFirstBlock{Header: block.BlockHeader}
+	if withVCPProof {
+		vcpProof, err := proveVCP(block, db)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get VCP Proof")
+		}
+		first.Proof = *vcpProof
+	}
+	return &core.SnapshotBlockTrio{
+		First:  first,
+		Second: core.SnapshotSecondBlock{Header: &child},
+		Third:  core.SnapshotThirdBlock{Header: &grandChild},
+	}, nil
+}
+
 func getFinalizedChild(block *core.ExtendedBlock, chain *blockchain.Chain) (*core.ExtendedBlock, error) {
 	for _, h := range block.Children {
 		b, err := chain.FindBlock(h)
@@ -170,6 +170,89 @@ func LoadSnapshotCheckpointHeader(snapshotFilePath string) *core.BlockHeader {
 	return metadata.TailTrio.Second.Header
 }
 
+// LoadSnapshotGenesisHeader returns the header of the genesis block included in the snapshot.
+func LoadSnapshotGenesisHeader(snapshotFilePath string) (*core.BlockHeader, error) {
+	snapshotFile, err := os.Open(snapshotFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshotFile.Close()
+
+	snapshotHeader := &core.SnapshotHeader{}
+	_, err = core.ReadRecord(snapshotFile, snapshotHeader)
+	if err != nil || snapshotHeader.Magic != core.SnapshotHeaderMagic { // older version, reset snapshotFile
+		snapshotFile.Seek(0, 0)
+	} else if snapshotHeader.Version >= 2 {
+		lastCheckpoint := core.LastCheckpoint{}
+		_, err = core.ReadRecord(snapshotFile, &lastCheckpoint)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load snapshot last checkpoint, %v", err)
+		}
+	}
+
+	metadata := core.SnapshotMetadata{}
+	_, err = core.ReadRecord(snapshotFile, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load snapshot metadata, %v", err)
+	}
+
+	if len(metadata.ProofTrios) > 0 {
+		return metadata.ProofTrios[0].Second.Header, nil
+	}
+	if metadata.TailTrio.Second.Header != nil && metadata.TailTrio.Second.Header.Height == core.GenesisBlockHeight {
+		return metadata.TailTrio.Second.Header, nil
+	}
+	return nil, fmt.Errorf("No genesis block found in snapshot %v", snapshotFilePath)
+}
+
+// VerifyGenesisBlockHash checks the hash of the genesis block against the hash embedded in
+// the binary for released networks, or against the configured genesis hash otherwise.
+func VerifyGenesisBlockHash(block *core.BlockHeader) error {
+	expectedGenesisHash, ok := core.KnownGenesisBlockHash(block.ChainID)
+	if !ok {
+		expectedGenesisHash = viper.GetString(common.CfgGenesisHash)
+	}
+	if expectedGenesisHash == "" {
+		return fmt.Errorf("Expected genesis block hash for chain %v is not configured, please set %v",
+			block.ChainID, common.CfgGenesisHash)
+	}
+
+	if block.Hash() != common.HexToHash(expectedGenesisHash) {
+		return fmt.Errorf("Genesis block hash mismatch, expected: %v, calculated: %v",
+			expectedGenesisHash, block.Hash().Hex())
+	}
+	return nil
+}
+
+// VerifyGenesisSignatures checks the genesis block is signed by the release keys configured with
+// genesis.signers. The check is skipped if no signer is configured. Unlike the hash check, it cannot
+// be bypassed with --skip_genesis_check.
+func VerifyGenesisSignatures(block *core.BlockHeader, sigFilePath string) error {
+	signers := []common.Address{}
+	for _, signer := range viper.GetStringSlice(common.CfgGenesisSigners) {
+		if !common.IsHexAddress(signer) {
+			return fmt.Errorf("Invalid genesis signer address: %v", signer)
+		}
+		signers = append(signers, common.HexToAddress(signer))
+	}
+	if len(signers) == 0 {
+		return nil
+	}
+
+	threshold := viper.GetInt(common.CfgGenesisSignatureThreshold)
+	if threshold < 1 || threshold > len(signers) {
+		return fmt.Errorf("Invalid %v: %v, expected 1 to %v", common.CfgGenesisSignatureThreshold, threshold, len(signers))
+	}
+	sigs, err := core.ReadGenesisSignatures(sigFilePath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("The genesis block is not signed, %v not found", sigFilePath)
+	}
+	if err != nil {
+		return err
+	}
+	return sigs.Verify(block, signers, threshold)
+}
+
 func loadSnapshot(snapshotFilePath string, db database.Database, logStr string) (*core.BlockHeader, *core.SnapshotMetadata, error) {
 	snapshotFile, err := os.Open(snapshotFilePath)
 	if err != nil {
@@ -495,7 +578,7 @@ func loadChainSegment(filePath string, start, end uint64, prevBlock *core.Extend
 				if proofTrio.First.Header.Height == core.GenesisBlockHeight {
 					provenValSet, err = checkGenesisBlock(proofTrio.Second.Header, db)
 				} else {
-					provenValSet, err = getValidatorSetFromVCPProof(proofTrio.First.Header.StateHash, &proofTrio.First.Proof)
+					provenValSet, err = GetValidatorSetFromVCPProof(proofTrio.First.Header.StateHash, &proofTrio.First.Proof)
 				}
 				if err != nil {
 					return nil, fmt.Errorf("Failed to retrieve validator set from VCP proof: %v", err)
@@ -754,10 +837,10 @@ func checkProofTrios(proofTrios []core.SnapshotBlockTrio, db database.Database)
 			}
 
 			// third.Header.HCC.Votes contains the votes for the second block in the trio
-			if err := validateVotes(provenValSet, second.Header, third.Header.HCC.Votes); err != nil {
+			if err := ValidateCommitCertificate(provenValSet, second.Header, third.Header.HCC); err != nil {
 				return nil, fmt.Errorf("Failed to validate voteSet, %v", err)
 			}
-			provenValSet, err = getValidatorSetFromVCPProof(first.Header.StateHash, &first.Proof)
+			provenValSet, err = GetValidatorSetFromVCPProof(first.Header.StateHash, &first.Proof)
 			if err != nil {
 				return nil, fmt.Errorf("Failed to retrieve validator set from VCP proof: %v", err)
 			}
@@ -794,19 +877,11 @@ func checkGenesisBlock(block *core.BlockHeader, db database.Database) (*core.Val
 		return nil, fmt.Errorf("Invalid genesis block height: %v", block.Height)
 	}
 
-	var expectedGenesisHash string
-	if block.ChainID == core.MainnetChainID {
-		expectedGenesisHash = core.MainnetGenesisBlockHash
-	} else {
-		expectedGenesisHash = viper.GetString(common.CfgGenesisHash)
-	}
-
-	// logger.Infof("Expected genesis hash: %v", expectedGenesisHash)
-	// logger.Infof("Acutal   genesis hash: %v", block.Hash().Hex())
-
-	if block.Hash() != common.HexToHash(expectedGenesisHash) {
-		return nil, fmt.Errorf("Genesis block hash mismatch, expected: %v, calculated: %v",
-			expectedGenesisHash, block.Hash().Hex())
+	if err := VerifyGenesisBlockHash(block); err != nil {
+		if !viper.GetBool(common.CfgGenesisSkipHashCheck) {
+			return nil, err
+		}
+		logger.Warnf("Ignoring genesis block hash check failure: %v", err)
 	}
 
 	// now that the block hash matches with the expected genesis block hash,
@@ -819,7 +894,9 @@ func checkGenesisBlock(block *core.BlockHeader, db database.Database) (*core.Val
 	return genesisValidatorSet, nil
 }
 
-func getValidatorSetFromVCPProof(stateHash common.Hash, recoverredVp *core.VCPProof) (*core.ValidatorSet, error) {
+// GetValidatorSetFromVCPProof verifies the VCP proof against the state root, and returns the
+// validator set selected from the proven validator candidate pool.
+func GetValidatorSetFromVCPProof(stateHash common.Hash, recoverredVp *core.VCPProof) (*core.ValidatorSet, error) {
 	serializedVCP, _, err := trie.VerifyProof(stateHash, state.ValidatorCandidatePoolKey(), recoverredVp)
 	if err != nil {
 		return nil, err
@@ -838,12 +915,27 @@ func getValidatorSetFromSV(sv *state.StoreView) *core.ValidatorSet {
 	return consensus.SelectTopStakeHoldersAsValidators(vcp)
 }
 
+// ValidateCommitCertificate checks the commit certificate for the block, whose votes may be
+// aggregated into a BLS signature.
+func ValidateCommitCertificate(validatorSet *core.ValidatorSet, block *core.BlockHeader, cc core.CommitCertificate) error {
+	if cc.Aggregated == nil {
+		return validateVotes(validatorSet, block, cc.Votes)
+	}
+	if cc.BlockHash != block.Hash() {
+		return fmt.Errorf("commit certificate is not for corresponding block")
+	}
+	if !cc.IsValid(validatorSet) {
+		return fmt.Errorf("commit certificate is not valid")
+	}
+	return nil
+}
+
 func validateVotes(validatorSet *core.ValidatorSet, block *core.BlockHeader, voteSet *core.VoteSet) error {
 	if !validatorSet.HasMajority(voteSet) {
 		return fmt.Errorf("block doesn't have majority votes")
 	}
 	for _, vote := range voteSet.Votes() {
-		res := vote.Validate()
+		res := vote.ValidateWithValidators(validatorSet)
 		if !res.IsOK() {
 			return fmt.Errorf("vote is not valid, %v", res)
 		}
@@ -0,0 +1,152 @@
+package backend
+
+import (
+	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/store/database"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// ArchiveDatabase is the database of an archive node. The recent data is kept in the hot
+// database, which the block processing works on. The state trie nodes pruned from the hot
+// database are frozen into the ancient database instead of being deleted, so the states of
+// all the historical blocks remain readable, while the hot keyspace stays as compact as the
+// one of a pruning node.
+type ArchiveDatabase struct {
+	NodeDatabase
+
+	ancientFile string
+	ancient     *leveldb.DB
+}
+
+// NewArchiveDatabase opens the ancient database under the given path, and combines it with
+// the hot database.
+func NewArchiveDatabase(hot NodeDatabase, ancientFile string, cache int, handles int) (*ArchiveDatabase, error) {
+	if cache < 16 {
+		cache = 16
+	}
+	if handles < 16 {
+		handles = 16
+	}
+
+	// The ancient data is written once and rarely read, so it is stored in larger blocks and
+	// tables, which compact less often, with a smaller cache
+	ancient, err := leveldb.OpenFile(ancientFile, &opt.Options{
+		OpenFilesCacheCapacity: handles,
+		BlockCacheCapacity:     cache / 4 * opt.MiB,
+		WriteBuffer:            cache / 4 * opt.MiB,
+		BlockSize:              32 * opt.KiB,
+		CompactionTableSize:    8 * opt.MiB,
+		Filter:                 filter.NewBloomFilter(10),
+	})
+	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
+		ancient, err = leveldb.RecoverFile(ancientFile, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArchiveDatabase{
+		NodeDatabase: hot,
+		ancientFile:  ancientFile,
+		ancient:      ancient,
+	}, nil
+}
+
+// Get returns the value of the key from the hot database, or from the ancient database
+// if it has been frozen.
+func (db *ArchiveDatabase) Get(key []byte) ([]byte, error) {
+	dat, err := db.NodeDatabase.Get(key)
+	if err != store.ErrKeyNotFound {
+		return dat, err
+	}
+	dat, err = db.ancient.Get(key, nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, store.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return dat, nil
+}
+
+// Has returns whether the key is in either the hot or the ancient database.
+func (db *ArchiveDatabase) Has(key []byte) (bool, error) {
+	has, err := db.NodeDatabase.Has(key)
+	if err != nil || has {
+		return has, err
+	}
+	return db.ancient.Has(key, nil)
+}
+
+// Delete deletes the key from both the hot and the ancient database, so that the deleted
+// data does not reappear from the ancient database.
+func (db *ArchiveDatabase) Delete(key []byte) error {
+	if err := db.ancient.Delete(key, nil); err != nil {
+		return err
+	}
+	return db.NodeDatabase.Delete(key)
+}
+
+// Freeze moves the key from the hot database to the ancient database.
+func (db *ArchiveDatabase) Freeze(key []byte) error {
+	dat, err := db.NodeDatabase.Get(key)
+	if err == store.ErrKeyNotFound {
+		// Already frozen, only the reference count is left in the hot database
+		db.NodeDatabase.Delete(key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := db.ancient.Put(key, dat, nil); err != nil {
+		return err
+	}
+	err = db.NodeDatabase.Delete(key)
+	if err == store.ErrKeyNotFound {
+		return nil
+	}
+	return err
+}
+
+// Freezer returns a view of the database whose deletions freeze the keys instead, for pruning
+// the hot database without losing the historical states.
+func (db *ArchiveDatabase) Freezer() database.Database {
+	return &freezer{db}
+}
+
+// AncientPath returns the path to the ancient database directory.
+func (db *ArchiveDatabase) AncientPath() string {
+	return db.ancientFile
+}
+
+// Compact compacts the hot and the ancient databases.
+func (db *ArchiveDatabase) Compact() error {
+	if err := db.NodeDatabase.Compact(); err != nil {
+		return err
+	}
+	return db.ancient.CompactRange(util.Range{})
+}
+
+// Close closes the ancient and the hot databases.
+func (db *ArchiveDatabase) Close() {
+	if err := db.ancient.Close(); err != nil {
+		logger.Errorf("Failed to close ancient database, err: %v", err)
+	}
+	db.NodeDatabase.Close()
+}
+
+type freezer struct {
+	*ArchiveDatabase
+}
+
+func (f *freezer) Delete(key []byte) error {
+	return f.Freeze(key)
+}
+
+func (f *freezer) Close() {
+	// Do nothing; don't close the underlying DB.
+}
@@ -0,0 +1,74 @@
+package backend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pandotoken/pando/store"
+)
+
+func newTestArchiveDB() (*ArchiveDatabase, func()) {
+	hot, removeHot := newTestLDB()
+	dirname, err := ioutil.TempDir(os.TempDir(), "ethdb_ancient_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := NewArchiveDatabase(hot, dirname, 0, 0)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+	return db, func() {
+		db.ancient.Close()
+		removeHot()
+		os.RemoveAll(dirname)
+	}
+}
+
+func TestArchiveDB_Freeze(t *testing.T) {
+	db, remove := newTestArchiveDB()
+	defer remove()
+
+	key, value := []byte("node"), []byte("value")
+	if err := db.Put(key, value); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if err := db.Reference(key); err != nil {
+		t.Fatalf("reference failed: %v", err)
+	}
+
+	// Pruning through the freezer moves the node to the ancient database
+	if err := db.Freezer().Delete(key); err != nil {
+		t.Fatalf("freeze failed: %v", err)
+	}
+	if has, _ := db.NodeDatabase.Has(key); has {
+		t.Fatalf("frozen key still in the hot database")
+	}
+	if _, err := db.CountReference(key); err != store.ErrKeyNotFound {
+		t.Fatalf("reference count of the frozen key not removed, err: %v", err)
+	}
+	dat, err := db.Get(key)
+	if err != nil || !bytes.Equal(dat, value) {
+		t.Fatalf("get of frozen key returned wrong result, got %q expected %q, err: %v", dat, value, err)
+	}
+	if has, _ := db.Has(key); !has {
+		t.Fatalf("frozen key not found")
+	}
+
+	// Freezing again is a no-op
+	if err := db.Freezer().Delete(key); err != nil {
+		t.Fatalf("freeze of frozen key failed: %v", err)
+	}
+	if _, err := db.Get(key); err != nil {
+		t.Fatalf("get of frozen key failed: %v", err)
+	}
+
+	// A deletion removes the key from the ancient database as well
+	if err := db.Delete(key); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := db.Get(key); err != store.ErrKeyNotFound {
+		t.Fatalf("deleted key found, err: %v", err)
+	}
+}
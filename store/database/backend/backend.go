@@ -0,0 +1,112 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pandotoken/pando/store/database"
+)
+
+// The backends the database of a node can be stored in, see OpenDatabase. The backends
+// implemented on top of a C library, or of a module the default build does not depend on, are
+// compiled in with the build tag of the same name, e.g. go build -tags rocksdb.
+const (
+	BackendLevelDB = "leveldb"
+	BackendRocksDB = "rocksdb"
+	BackendPebble  = "pebble"
+)
+
+// NodeDatabase is the database of a node. It keeps the data and the reference counts of the keys
+// in two key-value stores of the same backend.
+type NodeDatabase interface {
+	database.Database
+
+	// Path returns the path to the database directory.
+	Path() string
+	// Compact compacts the whole key space of the database and the reference database.
+	Compact() error
+	// Backup copies a consistent snapshot of the database and the reference database into new
+	// databases of the same backend under the given paths.
+	Backup(file string, reffile string) error
+	// Stores returns the key-value stores of the data and of the reference counts.
+	Stores() (data KVStore, refs KVStore)
+}
+
+// KVStore is a plain key-value store of a backend, without reference counting.
+type KVStore interface {
+	// Get returns store.ErrKeyNotFound if the key is not present.
+	Get(key []byte) ([]byte, error)
+	Has(key []byte) (bool, error)
+	Put(key []byte, value []byte) error
+	Delete(key []byte) error
+	NewWriteBatch() KVBatch
+	// Iterate calls fn on the entries in the key order, and stops at the first error returned by fn.
+	Iterate(fn func(key []byte, value []byte) error) error
+	Compact() error
+	Close() error
+}
+
+// KVBatch collects the writes to a KVStore, which are applied atomically by Write.
+type KVBatch interface {
+	Put(key []byte, value []byte)
+	Delete(key []byte)
+	Len() int
+	Write() error
+	Reset()
+}
+
+// Opener opens the database of a backend, with a cache of the given size in MB and the given
+// number of file handles.
+type Opener func(file string, reffile string, cache int, handles int) (NodeDatabase, error)
+
+var (
+	openersMtx sync.RWMutex
+	openers    = make(map[string]Opener)
+)
+
+func init() {
+	RegisterBackend(BackendLevelDB, func(file string, reffile string, cache int, handles int) (NodeDatabase, error) {
+		db, err := NewLDBDatabase(file, reffile, cache, handles)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	})
+}
+
+// RegisterBackend registers the opener of a backend. It is meant to be called from init(), and
+// panics if the name is already taken.
+func RegisterBackend(name string, opener Opener) {
+	openersMtx.Lock()
+	defer openersMtx.Unlock()
+
+	if _, ok := openers[name]; ok {
+		panic(fmt.Sprintf("Database backend %v is already registered", name))
+	}
+	openers[name] = opener
+}
+
+// Backends returns the names of the backends compiled in, in alphabetical order.
+func Backends() []string {
+	openersMtx.RLock()
+	defer openersMtx.RUnlock()
+
+	names := make([]string, 0, len(openers))
+	for name := range openers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenDatabase opens the database of the node with the named backend.
+func OpenDatabase(name string, file string, reffile string, cache int, handles int) (NodeDatabase, error) {
+	openersMtx.RLock()
+	opener, ok := openers[name]
+	openersMtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Database backend %v is not available, the backends compiled in are %v", name, Backends())
+	}
+	return opener(file, reffile, cache, handles)
+}
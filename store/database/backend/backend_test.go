@@ -0,0 +1,114 @@
+package backend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// newTestKVDB creates a KVDatabase on top of the LevelDB stores, to test the reference counting
+// shared by the backends without their C libraries
+func newTestKVDB() (*KVDatabase, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "kvdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := NewKVDatabase(dirname+"/main", dirname+"/ref", openTestLDBStore)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
+func openTestLDBStore(file string) (KVStore, error) {
+	db, err := leveldb.OpenFile(file, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ldbStore{db}, nil
+}
+
+func TestOpenDatabase(t *testing.T) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "backend_test_")
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	defer os.RemoveAll(dirname)
+
+	db, err := OpenDatabase(BackendLevelDB, dirname+"/main", dirname+"/ref", 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open the leveldb backend: %v", err)
+	}
+	db.Close()
+
+	if _, err := OpenDatabase("unknown", dirname+"/main", dirname+"/ref", 0, 0); err == nil {
+		t.Fatalf("opened an unknown backend")
+	}
+	found := false
+	for _, name := range Backends() {
+		found = found || name == BackendLevelDB
+	}
+	if !found {
+		t.Fatalf("leveldb backend not registered, got %v", Backends())
+	}
+}
+
+func TestKVDB_PutGet(t *testing.T) {
+	db, remove := newTestKVDB()
+	defer remove()
+	testPutGet(db, db.NewBatch(), t)
+}
+
+func TestKVDB_ParallelPutGet(t *testing.T) {
+	db, remove := newTestKVDB()
+	defer remove()
+	testParallelPutGet(db, t)
+}
+
+func TestConvert(t *testing.T) {
+	src, removeSrc := newTestLDB()
+	defer removeSrc()
+	for _, v := range testValues {
+		if err := src.Put([]byte(v), []byte(v)); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if err := src.Reference([]byte("a")); err != nil {
+			t.Fatalf("reference failed: %v", err)
+		}
+	}
+
+	dst, removeDst := newTestKVDB()
+	defer removeDst()
+	n, err := Convert(src, dst)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if n != len(testValues)+1 {
+		t.Fatalf("wrong number of converted entries, got %v", n)
+	}
+	for _, v := range testValues {
+		data, err := dst.Get([]byte(v))
+		if err != nil || !bytes.Equal(data, []byte(v)) {
+			t.Fatalf("get returned wrong result, got %q expected %q", string(data), v)
+		}
+	}
+	if ref, err := dst.CountReference([]byte("a")); err != nil || ref != 3 {
+		t.Fatalf("wrong reference count, got %v, err: %v", ref, err)
+	}
+
+	// The converted database keeps counting the references
+	if err := dst.Dereference([]byte("a")); err != nil {
+		t.Fatalf("dereference failed: %v", err)
+	}
+	if ref, _ := dst.CountReference([]byte("a")); ref != 2 {
+		t.Fatalf("wrong reference count after dereference, got %v", ref)
+	}
+}
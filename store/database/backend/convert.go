@@ -0,0 +1,53 @@
+package backend
+
+import (
+	"github.com/pandotoken/pando/store/database"
+)
+
+// convertLogInterval is the number of entries between the progress logs of a conversion
+const convertLogInterval = 1000000
+
+// Convert copies the data and the reference counts of the source database into the destination
+// database, which is typically of another backend, e.g. to move the LevelDB data directory of a
+// node to RocksDB or Pebble without resyncing. The destination is expected to be empty. It returns
+// the number of entries copied.
+func Convert(src NodeDatabase, dst NodeDatabase) (int, error) {
+	srcData, srcRefs := src.Stores()
+	dstData, dstRefs := dst.Stores()
+
+	numData, err := copyStore(srcData, dstData)
+	if err != nil {
+		return numData, err
+	}
+	numRefs, err := copyStore(srcRefs, dstRefs)
+	return numData + numRefs, err
+}
+
+// copyStore copies the entries of the source store into the destination store, in batches of
+// about database.IdealBatchSize bytes.
+func copyStore(src KVStore, dst KVStore) (int, error) {
+	batch := dst.NewWriteBatch()
+	size, copied, logged := 0, 0, 0
+	err := src.Iterate(func(key []byte, value []byte) error {
+		batch.Put(key, value)
+		size += len(key) + len(value)
+		copied++
+		if size < database.IdealBatchSize {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		size = 0
+		if copied-logged >= convertLogInterval {
+			logger.Infof("Converted %v entries", copied)
+			logged = copied
+		}
+		return nil
+	})
+	if err != nil {
+		return copied, err
+	}
+	return copied, batch.Write()
+}
@@ -0,0 +1,218 @@
+package backend
+
+import (
+	"strconv"
+
+	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/store/database"
+)
+
+// KVDatabase implements the NodeDatabase on top of the key-value stores of a backend, with the
+// same reference counting as the LDBDatabase, so that the backends only need to provide the
+// plain key-value operations.
+type KVDatabase struct {
+	fn   string
+	data KVStore
+	refs KVStore
+	open func(file string) (KVStore, error) // opens a store of the same backend, for the backups
+}
+
+var _ NodeDatabase = (*KVDatabase)(nil)
+
+// NewKVDatabase opens the data and the reference stores with the given function, and combines
+// them into a database.
+func NewKVDatabase(file string, reffile string, open func(file string) (KVStore, error)) (*KVDatabase, error) {
+	data, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := open(reffile)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+	return &KVDatabase{
+		fn:   file,
+		data: data,
+		refs: refs,
+		open: open,
+	}, nil
+}
+
+// Path returns the path to the database directory.
+func (db *KVDatabase) Path() string {
+	return db.fn
+}
+
+// Stores returns the key-value stores of the data and of the reference counts.
+func (db *KVDatabase) Stores() (KVStore, KVStore) {
+	return db.data, db.refs
+}
+
+func (db *KVDatabase) Put(key []byte, value []byte) error {
+	return db.data.Put(key, value)
+}
+
+func (db *KVDatabase) Has(key []byte) (bool, error) {
+	return db.data.Has(key)
+}
+
+func (db *KVDatabase) Get(key []byte) ([]byte, error) {
+	return db.data.Get(key)
+}
+
+func (db *KVDatabase) Delete(key []byte) error {
+	db.refs.Delete(key)
+	return db.data.Delete(key)
+}
+
+func (db *KVDatabase) Reference(key []byte) error {
+	if _, err := db.data.Get(key); err != nil {
+		return err
+	}
+	ref, err := db.CountReference(key)
+	if err != nil && err != store.ErrKeyNotFound {
+		return err
+	}
+	return db.refs.Put(key, []byte(strconv.Itoa(ref+1)))
+}
+
+func (db *KVDatabase) Dereference(key []byte) error {
+	if _, err := db.data.Get(key); err != nil {
+		return err
+	}
+	ref, err := db.CountReference(key)
+	if err == store.ErrKeyNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if ref > 0 {
+		return db.refs.Put(key, []byte(strconv.Itoa(ref-1)))
+	}
+	return nil
+}
+
+func (db *KVDatabase) CountReference(key []byte) (int, error) {
+	dat, err := db.refs.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(dat) == 0 {
+		return 0, nil
+	}
+	return strconv.Atoi(string(dat))
+}
+
+// Compact compacts the whole key space of the database and the reference database.
+func (db *KVDatabase) Compact() error {
+	if err := db.data.Compact(); err != nil {
+		return err
+	}
+	return db.refs.Compact()
+}
+
+// Backup copies the database and the reference database into new databases under the given paths.
+func (db *KVDatabase) Backup(file string, reffile string) error {
+	backup, err := NewKVDatabase(file, reffile, db.open)
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+	_, err = Convert(db, backup)
+	return err
+}
+
+func (db *KVDatabase) Close() {
+	err := db.data.Close()
+	if refErr := db.refs.Close(); refErr != nil {
+		err = refErr
+	}
+	if err == nil {
+		logger.Infof("Database closed")
+	} else {
+		logger.Errorf("Failed to close database, err: %v", err)
+	}
+}
+
+func (db *KVDatabase) NewBatch() database.Batch {
+	return &kvBatch{db: db, b: db.data.NewWriteBatch(), references: make(map[string]int)}
+}
+
+type kvBatch struct {
+	db         *KVDatabase
+	b          KVBatch
+	references map[string]int
+	size       int
+}
+
+func (b *kvBatch) Put(key, value []byte) error {
+	b.b.Put(key, value)
+	b.size += len(value)
+	return nil
+}
+
+func (b *kvBatch) Delete(key []byte) error {
+	b.db.refs.Delete(key)
+	b.b.Delete(key)
+	b.size++
+	return nil
+}
+
+func (b *kvBatch) Reference(key []byte) error {
+	b.references[string(key)]++
+	b.size++
+	return nil
+}
+
+func (b *kvBatch) Dereference(key []byte) error {
+	b.references[string(key)]--
+	b.size++
+	return nil
+}
+
+func (b *kvBatch) Write() error {
+	if err := b.b.Write(); err != nil {
+		return err
+	}
+
+	refBatch := b.db.refs.NewWriteBatch()
+	for k, v := range b.references {
+		if v == 0 {
+			// refs and derefs canceled out
+			continue
+		}
+		ref, err := b.db.CountReference([]byte(k))
+		if err != nil && err != store.ErrKeyNotFound {
+			return err
+		}
+		if err == store.ErrKeyNotFound && v < 0 {
+			continue
+		}
+		if ref <= 0 && v < 0 {
+			continue
+		}
+		ref += v
+		if ref < 0 {
+			ref = 0
+		}
+		refBatch.Put([]byte(k), []byte(strconv.Itoa(ref)))
+	}
+	if err := refBatch.Write(); err != nil {
+		return err
+	}
+
+	b.Reset()
+	return nil
+}
+
+func (b *kvBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *kvBatch) Reset() {
+	b.b.Reset()
+	b.references = make(map[string]int)
+	b.size = 0
+}
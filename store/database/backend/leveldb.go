@@ -267,10 +267,143 @@ func (db *LDBDatabase) Close() {
 	}
 }
 
+// Compact compacts the whole key space of the database and the reference database.
+func (db *LDBDatabase) Compact() error {
+	if err := db.db.CompactRange(util.Range{}); err != nil {
+		return err
+	}
+	return db.refdb.CompactRange(util.Range{})
+}
+
+// Backup copies a consistent snapshot of the database and the reference database into new
+// databases under the given paths.
+func (db *LDBDatabase) Backup(file string, reffile string) error {
+	if err := copyLDB(db.db, file); err != nil {
+		return err
+	}
+	return copyLDB(db.refdb, reffile)
+}
+
+func copyLDB(src *leveldb.DB, file string) error {
+	snapshot, err := src.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.Release()
+
+	dst, err := leveldb.OpenFile(file, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	it := snapshot.NewIterator(nil, nil)
+	defer it.Release()
+	batch := new(leveldb.Batch)
+	for it.Next() {
+		batch.Put(it.Key(), it.Value())
+		if batch.Len() >= 1024 {
+			if err := dst.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return dst.Write(batch, nil)
+}
+
 func (db *LDBDatabase) LDB() *leveldb.DB {
 	return db.db
 }
 
+// Stores returns the key-value stores of the data and of the reference counts.
+func (db *LDBDatabase) Stores() (KVStore, KVStore) {
+	return &ldbStore{db.db}, &ldbStore{db.refdb}
+}
+
+// ldbStore exposes a LevelDB instance as a KVStore
+type ldbStore struct {
+	db *leveldb.DB
+}
+
+func (s *ldbStore) Get(key []byte) ([]byte, error) {
+	dat, err := s.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, store.ErrKeyNotFound
+	}
+	return dat, err
+}
+
+func (s *ldbStore) Has(key []byte) (bool, error) {
+	return s.db.Has(key, nil)
+}
+
+func (s *ldbStore) Put(key []byte, value []byte) error {
+	return s.db.Put(key, value, nil)
+}
+
+func (s *ldbStore) Delete(key []byte) error {
+	return s.db.Delete(key, nil)
+}
+
+func (s *ldbStore) NewWriteBatch() KVBatch {
+	return &ldbWriteBatch{db: s.db, b: new(leveldb.Batch)}
+}
+
+// Iterate iterates over a snapshot of the store
+func (s *ldbStore) Iterate(fn func(key []byte, value []byte) error) error {
+	snapshot, err := s.db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.Release()
+
+	it := snapshot.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+func (s *ldbStore) Compact() error {
+	return s.db.CompactRange(util.Range{})
+}
+
+func (s *ldbStore) Close() error {
+	return s.db.Close()
+}
+
+type ldbWriteBatch struct {
+	db *leveldb.DB
+	b  *leveldb.Batch
+}
+
+func (b *ldbWriteBatch) Put(key []byte, value []byte) {
+	b.b.Put(key, value)
+}
+
+func (b *ldbWriteBatch) Delete(key []byte) {
+	b.b.Delete(key)
+}
+
+func (b *ldbWriteBatch) Len() int {
+	return b.b.Len()
+}
+
+func (b *ldbWriteBatch) Write() error {
+	return b.db.Write(b.b, nil)
+}
+
+func (b *ldbWriteBatch) Reset() {
+	b.b.Reset()
+}
+
 // Meter configures the database metrics collectors and
 func (db *LDBDatabase) Meter(prefix string) {
 	if metrics.Enabled {
@@ -62,6 +62,48 @@ func TestLDB_PutGet(t *testing.T) {
 	testPutGet(db, batch, t)
 }
 
+func TestLDB_Backup(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	for _, v := range testValues {
+		if err := db.Put([]byte(v), []byte(v)); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	if err := db.Reference([]byte("a")); err != nil {
+		t.Fatalf("reference failed: %v", err)
+	}
+
+	dirname, err := ioutil.TempDir(os.TempDir(), "ethdb_backup_test_")
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	defer os.RemoveAll(dirname)
+	if err := db.Backup(dirname+"/main", dirname+"/ref"); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	// Writes after the backup are not copied
+	db.Put([]byte("later"), []byte("later"))
+
+	backup, err := NewLDBDatabase(dirname+"/main", dirname+"/ref", 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+	defer backup.Close()
+	for _, v := range testValues {
+		data, err := backup.Get([]byte(v))
+		if err != nil || !bytes.Equal(data, []byte(v)) {
+			t.Fatalf("get returned wrong result, got %q expected %q", string(data), v)
+		}
+	}
+	if ref, err := backup.CountReference([]byte("a")); err != nil || ref != 1 {
+		t.Fatalf("wrong reference count, got %v, err: %v", ref, err)
+	}
+	if has, _ := backup.Has([]byte("later")); has {
+		t.Fatalf("write after backup is copied")
+	}
+}
+
 func TestMemoryDB_PutGet(t *testing.T) {
 	memDB := NewMemDatabase()
 	testPutGet(memDB, memDB.NewBatch(), t)
@@ -0,0 +1,165 @@
+//go:build pebble
+// +build pebble
+
+package backend
+
+import (
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+
+	"github.com/pandotoken/pando/store"
+)
+
+// The Pebble backend is compiled in with go build -tags pebble. Pebble is a LevelDB/RocksDB
+// inspired store in pure Go, which compacts concurrently and paces the writes against the
+// compaction debt instead of stalling them.
+
+func init() {
+	RegisterBackend(BackendPebble, func(file string, reffile string, cache int, handles int) (NodeDatabase, error) {
+		db, err := NewPebbleDatabase(file, reffile, cache, handles)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	})
+}
+
+// NewPebbleDatabase opens the database and the reference database with Pebble.
+func NewPebbleDatabase(file string, reffile string, cache int, handles int) (*KVDatabase, error) {
+	if cache < 16 {
+		cache = 16
+	}
+	if handles < 16 {
+		handles = 16
+	}
+	logger.Infof("Allocated cache and file handles for Pebble, cache: %v, handles: %v", cache, handles)
+
+	return NewKVDatabase(file, reffile, func(file string) (KVStore, error) {
+		return openPebbleStore(file, cache, handles)
+	})
+}
+
+type pebbleStore struct {
+	db *pebble.DB
+}
+
+func openPebbleStore(file string, cache int, handles int) (*pebbleStore, error) {
+	blockCache := pebble.NewCache(int64(cache / 2 * 1024 * 1024))
+	defer blockCache.Unref()
+
+	opts := &pebble.Options{
+		Cache:        blockCache,
+		MaxOpenFiles: handles,
+		MemTableSize: uint64(cache / 4 * 1024 * 1024),
+		Levels: []pebble.LevelOptions{
+			{FilterPolicy: bloom.FilterPolicy(10)},
+		},
+	}
+	db, err := pebble.Open(file, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pebbleStore{db: db}, nil
+}
+
+func (s *pebbleStore) Get(key []byte) ([]byte, error) {
+	dat, closer, err := s.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return nil, store.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return append([]byte{}, dat...), nil
+}
+
+func (s *pebbleStore) Has(key []byte) (bool, error) {
+	_, closer, err := s.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+// Put writes without syncing, as the LevelDB backend does
+func (s *pebbleStore) Put(key []byte, value []byte) error {
+	return s.db.Set(key, value, pebble.NoSync)
+}
+
+func (s *pebbleStore) Delete(key []byte) error {
+	return s.db.Delete(key, pebble.NoSync)
+}
+
+func (s *pebbleStore) NewWriteBatch() KVBatch {
+	return &pebbleWriteBatch{b: s.db.NewBatch()}
+}
+
+// Iterate iterates over the implicit snapshot taken by the iterator
+func (s *pebbleStore) Iterate(fn func(key []byte, value []byte) error) error {
+	it, err := s.db.NewIter(nil)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.First(); it.Valid(); it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// Compact compacts the range from the first to the last key
+func (s *pebbleStore) Compact() error {
+	it, err := s.db.NewIter(nil)
+	if err != nil {
+		return err
+	}
+	var first, last []byte
+	if it.First() {
+		first = append([]byte{}, it.Key()...)
+	}
+	if it.Last() {
+		last = append([]byte{}, it.Key()...)
+	}
+	if err := it.Close(); err != nil {
+		return err
+	}
+	if first == nil {
+		return nil
+	}
+	return s.db.Compact(first, append(last, 0), true)
+}
+
+func (s *pebbleStore) Close() error {
+	return s.db.Close()
+}
+
+type pebbleWriteBatch struct {
+	b *pebble.Batch
+}
+
+func (b *pebbleWriteBatch) Put(key []byte, value []byte) {
+	b.b.Set(key, value, nil)
+}
+
+func (b *pebbleWriteBatch) Delete(key []byte) {
+	b.b.Delete(key, nil)
+}
+
+func (b *pebbleWriteBatch) Len() int {
+	return int(b.b.Count())
+}
+
+func (b *pebbleWriteBatch) Write() error {
+	return b.b.Commit(pebble.NoSync)
+}
+
+func (b *pebbleWriteBatch) Reset() {
+	b.b.Reset()
+}
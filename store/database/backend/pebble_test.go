@@ -0,0 +1,46 @@
+//go:build pebble
+// +build pebble
+
+package backend
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func newTestPebbleDB() (*KVDatabase, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "pebble_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := NewPebbleDatabase(dirname+"/main", dirname+"/ref", 0, 0)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
+func TestPebbleDB_PutGet(t *testing.T) {
+	db, remove := newTestPebbleDB()
+	defer remove()
+	testPutGet(db, db.NewBatch(), t)
+}
+
+func TestPebbleDB_ParallelPutGet(t *testing.T) {
+	db, remove := newTestPebbleDB()
+	defer remove()
+	testParallelPutGet(db, t)
+}
+
+func TestPebbleDB_Registered(t *testing.T) {
+	for _, name := range Backends() {
+		if name == BackendPebble {
+			return
+		}
+	}
+	t.Fatalf("pebble backend not registered, got %v", Backends())
+}
@@ -0,0 +1,158 @@
+//go:build rocksdb
+// +build rocksdb
+
+package backend
+
+import (
+	"runtime"
+
+	"github.com/tecbot/gorocksdb"
+
+	"github.com/pandotoken/pando/store"
+)
+
+// The RocksDB backend links against the RocksDB C library, it is compiled in with
+// go build -tags rocksdb. RocksDB compacts in background threads and throttles the writes
+// gradually, instead of stalling them once the level 0 tables pile up.
+
+func init() {
+	RegisterBackend(BackendRocksDB, func(file string, reffile string, cache int, handles int) (NodeDatabase, error) {
+		db, err := NewRocksDBDatabase(file, reffile, cache, handles)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	})
+}
+
+// NewRocksDBDatabase opens the database and the reference database with RocksDB.
+func NewRocksDBDatabase(file string, reffile string, cache int, handles int) (*KVDatabase, error) {
+	if cache < 16 {
+		cache = 16
+	}
+	if handles < 16 {
+		handles = 16
+	}
+	logger.Infof("Allocated cache and file handles for RocksDB, cache: %v, handles: %v", cache, handles)
+
+	// The cache is shared by the data and the reference stores
+	blockCache := gorocksdb.NewLRUCache(uint64(cache / 2 * 1024 * 1024))
+	return NewKVDatabase(file, reffile, func(file string) (KVStore, error) {
+		return openRocksDBStore(file, blockCache, cache/4, handles)
+	})
+}
+
+type rocksDBStore struct {
+	db *gorocksdb.DB
+	ro *gorocksdb.ReadOptions
+	wo *gorocksdb.WriteOptions
+}
+
+func openRocksDBStore(file string, blockCache *gorocksdb.Cache, writeBuffer int, handles int) (*rocksDBStore, error) {
+	bbto := gorocksdb.NewDefaultBlockBasedTableOptions()
+	bbto.SetBlockCache(blockCache)
+	bbto.SetFilterPolicy(gorocksdb.NewBloomFilter(10))
+
+	opts := gorocksdb.NewDefaultOptions()
+	opts.SetBlockBasedTableFactory(bbto)
+	opts.SetCreateIfMissing(true)
+	opts.SetMaxOpenFiles(handles)
+	opts.IncreaseParallelism(runtime.NumCPU())
+	opts.OptimizeLevelStyleCompaction(uint64(writeBuffer * 1024 * 1024))
+
+	db, err := gorocksdb.OpenDb(opts, file)
+	if err != nil {
+		return nil, err
+	}
+	return &rocksDBStore{
+		db: db,
+		ro: gorocksdb.NewDefaultReadOptions(),
+		wo: gorocksdb.NewDefaultWriteOptions(),
+	}, nil
+}
+
+func (s *rocksDBStore) Get(key []byte) ([]byte, error) {
+	slice, err := s.db.Get(s.ro, key)
+	if err != nil {
+		return nil, err
+	}
+	defer slice.Free()
+	if !slice.Exists() {
+		return nil, store.ErrKeyNotFound
+	}
+	return append([]byte{}, slice.Data()...), nil
+}
+
+func (s *rocksDBStore) Has(key []byte) (bool, error) {
+	slice, err := s.db.Get(s.ro, key)
+	if err != nil {
+		return false, err
+	}
+	defer slice.Free()
+	return slice.Exists(), nil
+}
+
+func (s *rocksDBStore) Put(key []byte, value []byte) error {
+	return s.db.Put(s.wo, key, value)
+}
+
+func (s *rocksDBStore) Delete(key []byte) error {
+	return s.db.Delete(s.wo, key)
+}
+
+func (s *rocksDBStore) NewWriteBatch() KVBatch {
+	return &rocksDBWriteBatch{store: s, b: gorocksdb.NewWriteBatch()}
+}
+
+// Iterate iterates over the implicit snapshot taken by the iterator
+func (s *rocksDBStore) Iterate(fn func(key []byte, value []byte) error) error {
+	it := s.db.NewIterator(s.ro)
+	defer it.Close()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		key, value := it.Key(), it.Value()
+		err := fn(key.Data(), value.Data())
+		key.Free()
+		value.Free()
+		if err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func (s *rocksDBStore) Compact() error {
+	s.db.CompactRange(gorocksdb.Range{})
+	return nil
+}
+
+func (s *rocksDBStore) Close() error {
+	s.db.Close()
+	s.ro.Destroy()
+	s.wo.Destroy()
+	return nil
+}
+
+type rocksDBWriteBatch struct {
+	store *rocksDBStore
+	b     *gorocksdb.WriteBatch
+}
+
+func (b *rocksDBWriteBatch) Put(key []byte, value []byte) {
+	b.b.Put(key, value)
+}
+
+func (b *rocksDBWriteBatch) Delete(key []byte) {
+	b.b.Delete(key)
+}
+
+func (b *rocksDBWriteBatch) Len() int {
+	return b.b.Count()
+}
+
+func (b *rocksDBWriteBatch) Write() error {
+	return b.store.db.Write(b.store.wo, b.b)
+}
+
+func (b *rocksDBWriteBatch) Reset() {
+	b.b.Clear()
+}
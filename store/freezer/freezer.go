@@ -0,0 +1,324 @@
+package freezer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "freezer"})
+
+var (
+	// ErrOutOfBounds is returned when retrieving an item which has not been frozen
+	ErrOutOfBounds = errors.New("The item is not in the freezer")
+	// ErrOutOfOrder is returned when appending an item which does not follow the last frozen item
+	ErrOutOfOrder = errors.New("The items need to be appended in order")
+)
+
+const (
+	tailFileName  = "TAIL"
+	indexItemSize = 8
+)
+
+// Freezer is an append-only store of immutable items, numbered consecutively from the tail
+// number given to the first append. Each item has an entry in every table. A table keeps the
+// items back to back in a data file, and the end offset of each item in an index file, so an
+// item is retrieved with a single read, without the compaction of a key-value store.
+type Freezer struct {
+	dir    string
+	tables map[string]*table
+	tail   uint64 // number of the first item
+	items  uint64 // number of items
+
+	mu sync.RWMutex
+}
+
+// Open opens the freezer under the directory with the given tables, and repairs the tables
+// left inconsistent by a crash during an append.
+func Open(dir string, tableNames []string) (*Freezer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	f := &Freezer{
+		dir:    dir,
+		tables: make(map[string]*table, len(tableNames)),
+	}
+
+	tail, err := ioutil.ReadFile(filepath.Join(dir, tailFileName))
+	if err == nil && len(tail) == 8 {
+		f.tail = binary.BigEndian.Uint64(tail)
+	} else if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	items := uint64(0)
+	for i, name := range tableNames {
+		t, err := openTable(dir, name)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		f.tables[name] = t
+		if i == 0 || t.items < items {
+			items = t.items
+		}
+	}
+	// An interrupted append may have reached some of the tables only
+	for name, t := range f.tables {
+		if t.items > items {
+			logger.Warnf("Truncating freezer table %v from %v to %v items", name, t.items, items)
+			if err := t.truncate(items); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+	}
+	f.items = items
+	return f, nil
+}
+
+// Tail returns the number of the first item.
+func (f *Freezer) Tail() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.tail
+}
+
+// Items returns the number of items.
+func (f *Freezer) Items() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.items
+}
+
+// Head returns the number of the next item to append, and whether the freezer has any item.
+func (f *Freezer) Head() (uint64, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.tail + f.items, f.items > 0
+}
+
+// Has returns whether the item has been frozen.
+func (f *Freezer) Has(number uint64) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.items > 0 && number >= f.tail && number < f.tail+f.items
+}
+
+// Append appends the item with the given number, which needs to follow the last item. The first
+// append sets the tail number. The data of the item is given for each table.
+func (f *Freezer) Append(number uint64, data map[string][]byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.items == 0 {
+		if err := f.setTail(number); err != nil {
+			return err
+		}
+	} else if number != f.tail+f.items {
+		return fmt.Errorf("%v: expected item %v, got %v", ErrOutOfOrder, f.tail+f.items, number)
+	}
+	for name := range data {
+		if _, ok := f.tables[name]; !ok {
+			return fmt.Errorf("Unknown freezer table %v", name)
+		}
+	}
+	for name, t := range f.tables {
+		if err := t.append(data[name]); err != nil {
+			// Roll back the tables which already have the item
+			for _, other := range f.tables {
+				other.truncate(f.items)
+			}
+			return err
+		}
+	}
+	f.items++
+	return nil
+}
+
+// Retrieve returns the data of the item in the table.
+func (f *Freezer) Retrieve(tableName string, number uint64) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	t, ok := f.tables[tableName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown freezer table %v", tableName)
+	}
+	if f.items == 0 || number < f.tail || number >= f.tail+f.items {
+		return nil, ErrOutOfBounds
+	}
+	return t.retrieve(number - f.tail)
+}
+
+// Sync flushes the tables to the disk.
+func (f *Freezer) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, t := range f.tables {
+		if err := t.sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the tables.
+func (f *Freezer) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var lastErr error
+	for _, t := range f.tables {
+		if err := t.close(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (f *Freezer) setTail(tail uint64) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, tail)
+	if err := ioutil.WriteFile(filepath.Join(f.dir, tailFileName), buf, 0600); err != nil {
+		return err
+	}
+	f.tail = tail
+	return nil
+}
+
+// table is an append-only data file, with an index file of the end offsets of the items
+type table struct {
+	data  *os.File
+	index *os.File
+	items uint64
+	size  uint64 // size of the data file
+}
+
+func openTable(dir string, name string) (*table, error) {
+	data, err := os.OpenFile(filepath.Join(dir, name+".dat"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, name+".idx"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+	t := &table{data: data, index: index}
+	if err := t.repair(); err != nil {
+		t.close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// repair drops the partially written index entry and the data not covered by the index
+func (t *table) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	items := uint64(stat.Size()) / indexItemSize
+	stat, err = t.data.Stat()
+	if err != nil {
+		return err
+	}
+	dataSize := uint64(stat.Size())
+
+	// Drop the index entries of the data which did not make it to the disk
+	for items > 0 {
+		end, err := t.offset(items)
+		if err != nil {
+			return err
+		}
+		if end <= dataSize {
+			break
+		}
+		items--
+	}
+	return t.truncate(items)
+}
+
+// offset returns the end offset of the given item, where item 0 ends at offset 0
+func (t *table) offset(item uint64) (uint64, error) {
+	if item == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, indexItemSize)
+	if _, err := t.index.ReadAt(buf, int64((item-1)*indexItemSize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+func (t *table) truncate(items uint64) error {
+	end, err := t.offset(items)
+	if err != nil {
+		return err
+	}
+	if err := t.index.Truncate(int64(items * indexItemSize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(end)); err != nil {
+		return err
+	}
+	t.items = items
+	t.size = end
+	return nil
+}
+
+func (t *table) append(item []byte) error {
+	if _, err := t.data.WriteAt(item, int64(t.size)); err != nil {
+		return err
+	}
+	buf := make([]byte, indexItemSize)
+	binary.BigEndian.PutUint64(buf, t.size+uint64(len(item)))
+	if _, err := t.index.WriteAt(buf, int64(t.items*indexItemSize)); err != nil {
+		return err
+	}
+	t.items++
+	t.size += uint64(len(item))
+	return nil
+}
+
+func (t *table) retrieve(item uint64) ([]byte, error) {
+	start, err := t.offset(item)
+	if err != nil {
+		return nil, err
+	}
+	end, err := t.offset(item + 1)
+	if err != nil {
+		return nil, err
+	}
+	if end < start {
+		return nil, fmt.Errorf("Corrupted freezer index at item %v", item)
+	}
+	buf := make([]byte, end-start)
+	if _, err := t.data.ReadAt(buf, int64(start)); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (t *table) sync() error {
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+func (t *table) close() error {
+	err := t.data.Close()
+	if indexErr := t.index.Close(); indexErr != nil {
+		err = indexErr
+	}
+	return err
+}
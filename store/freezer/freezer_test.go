@@ -0,0 +1,105 @@
+package freezer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTables = []string{"bodies", "receipts"}
+
+func TestFreezerAppendRetrieve(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "freezer_test")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	f, err := Open(dir, testTables)
+	require.Nil(err)
+	_, ok := f.Head()
+	assert.False(ok)
+
+	// The first append sets the tail
+	require.Nil(f.Append(100, map[string][]byte{"bodies": []byte("body100"), "receipts": []byte("r100")}))
+	require.Nil(f.Append(101, map[string][]byte{"bodies": []byte("body101")}))
+	assert.NotNil(f.Append(103, map[string][]byte{"bodies": []byte("body103")}))
+	assert.NotNil(f.Append(102, map[string][]byte{"unknown": []byte("x")}))
+	require.Nil(f.Append(102, map[string][]byte{"bodies": []byte("body102"), "receipts": []byte("r102")}))
+
+	head, ok := f.Head()
+	assert.True(ok)
+	assert.Equal(uint64(103), head)
+	assert.Equal(uint64(100), f.Tail())
+	assert.True(f.Has(101))
+	assert.False(f.Has(99))
+	assert.False(f.Has(103))
+
+	data, err := f.Retrieve("bodies", 101)
+	assert.Nil(err)
+	assert.Equal([]byte("body101"), data)
+	data, err = f.Retrieve("receipts", 101)
+	assert.Nil(err)
+	assert.Equal(0, len(data))
+	data, err = f.Retrieve("receipts", 102)
+	assert.Nil(err)
+	assert.Equal([]byte("r102"), data)
+	_, err = f.Retrieve("bodies", 103)
+	assert.Equal(ErrOutOfBounds, err)
+	require.Nil(f.Sync())
+	require.Nil(f.Close())
+
+	// The items survive a restart
+	f, err = Open(dir, testTables)
+	require.Nil(err)
+	defer f.Close()
+	assert.Equal(uint64(100), f.Tail())
+	assert.Equal(uint64(3), f.Items())
+	data, err = f.Retrieve("bodies", 100)
+	assert.Nil(err)
+	assert.Equal([]byte("body100"), data)
+}
+
+func TestFreezerRepair(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "freezer_test")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	f, err := Open(dir, testTables)
+	require.Nil(err)
+	for i := uint64(0); i < 3; i++ {
+		require.Nil(f.Append(i, map[string][]byte{"bodies": []byte("body"), "receipts": []byte("receipts")}))
+	}
+	require.Nil(f.Close())
+
+	// Simulate a crash in the middle of an append: the last item of the receipts is only
+	// partially written, and a fourth body has been appended
+	require.Nil(os.Truncate(filepath.Join(dir, "receipts.dat"), int64(len("receipts")*3-1)))
+	body, err := os.OpenFile(filepath.Join(dir, "bodies.dat"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.Nil(err)
+	_, err = body.Write([]byte("body"))
+	require.Nil(err)
+	require.Nil(body.Close())
+
+	f, err = Open(dir, testTables)
+	require.Nil(err)
+	defer f.Close()
+	assert.Equal(uint64(2), f.Items())
+	head, _ := f.Head()
+	assert.Equal(uint64(2), head)
+	require.Nil(f.Append(2, map[string][]byte{"bodies": []byte("body2"), "receipts": []byte("receipts2")}))
+	data, err := f.Retrieve("bodies", 2)
+	assert.Nil(err)
+	assert.Equal([]byte("body2"), data)
+	data, err = f.Retrieve("receipts", 2)
+	assert.Nil(err)
+	assert.Equal([]byte("receipts2"), data)
+}
@@ -0,0 +1,273 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pandotoken/pando/rlp"
+	"github.com/pandotoken/pando/store"
+	"github.com/pandotoken/pando/store/database"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "migration"})
+
+// SchemaVersionKey is the key of the version of the on-disk format of the database. A database
+// without the record predates the versioning and is at version 0.
+const SchemaVersionKey = "/schema_version"
+
+// Migration upgrades the database from the previous schema version to Version, e.g. by adding an
+// index or re-encoding the keys, so that the node does not need to resync from scratch.
+type Migration struct {
+	Version     uint64
+	Description string
+	Migrate     func(db database.Database) error
+}
+
+// BackupHook is called before the migrations from one schema version to another are applied. The
+// migrations are aborted if the hook returns an error.
+type BackupHook func(from uint64, to uint64) error
+
+var migrations []*Migration
+
+// Register adds a migration to the ones applied at startup. It panics if the version of the
+// migration is already registered.
+func Register(m *Migration) {
+	for _, existing := range migrations {
+		if existing.Version == m.Version {
+			logger.Panicf("Migration to schema version %v is already registered", m.Version)
+		}
+	}
+	migrations = append(migrations, m)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+}
+
+// LatestVersion returns the schema version of the databases created by this build.
+func LatestVersion() uint64 {
+	return latestVersion(migrations)
+}
+
+func latestVersion(ms []*Migration) uint64 {
+	if len(ms) == 0 {
+		return 0
+	}
+	return ms[len(ms)-1].Version
+}
+
+// GetSchemaVersion returns the schema version recorded in the database.
+func GetSchemaVersion(db database.Database) (uint64, error) {
+	raw, err := db.Get([]byte(SchemaVersionKey))
+	if err == store.ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var version uint64
+	if err := rlp.DecodeBytes(raw, &version); err != nil {
+		return 0, fmt.Errorf("Failed to decode schema version: %v", err)
+	}
+	return version, nil
+}
+
+// SetSchemaVersion records the schema version in the database.
+func SetSchemaVersion(db database.Database, version uint64) error {
+	raw, err := rlp.EncodeToBytes(version)
+	if err != nil {
+		return err
+	}
+	return db.Put([]byte(SchemaVersionKey), raw)
+}
+
+// Initialize records the latest schema version in a newly created database, which needs no
+// migration.
+func Initialize(db database.Database) error {
+	return SetSchemaVersion(db, LatestVersion())
+}
+
+// Runner applies the pending migrations to a database.
+type Runner struct {
+	db         database.Database
+	migrations []*Migration
+	backup     BackupHook
+	dryRun     bool
+}
+
+// NewRunner creates a runner applying the registered migrations to the database.
+func NewRunner(db database.Database) *Runner {
+	return newRunner(db, migrations)
+}
+
+func newRunner(db database.Database, ms []*Migration) *Runner {
+	return &Runner{
+		db:         db,
+		migrations: ms,
+	}
+}
+
+// SetBackupHook sets the hook called before the pending migrations are applied.
+func (r *Runner) SetBackupHook(hook BackupHook) {
+	r.backup = hook
+}
+
+// SetDryRun sets whether the migrations are only simulated. In the dry-run mode the migrations run
+// against the database with all the writes discarded, so that their errors and the number of keys
+// they would write are reported without touching the data.
+func (r *Runner) SetDryRun(dryRun bool) {
+	r.dryRun = dryRun
+}
+
+// Pending returns the migrations not applied to the database yet.
+func (r *Runner) Pending() ([]*Migration, error) {
+	version, err := GetSchemaVersion(r.db)
+	if err != nil {
+		return nil, err
+	}
+	if latest := latestVersion(r.migrations); version > latest {
+		return nil, fmt.Errorf("Database schema version %v is newer than the latest supported version %v, please upgrade the node", version, latest)
+	}
+
+	pending := []*Migration{}
+	for _, m := range r.migrations {
+		if m.Version > version {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies the pending migrations in the order of their versions. The schema version is
+// recorded after each migration, so that an interrupted run resumes from the failed migration.
+func (r *Runner) Run() error {
+	from, err := GetSchemaVersion(r.db)
+	if err != nil {
+		return err
+	}
+	pending, err := r.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		logger.Infof("Database schema is up to date, version: %v", from)
+		return nil
+	}
+	to := pending[len(pending)-1].Version
+
+	if r.dryRun {
+		for _, m := range pending {
+			db := newDryRunDatabase(r.db)
+			if err := m.Migrate(db); err != nil {
+				return fmt.Errorf("Dry run of migration to schema version %v failed: %v", m.Version, err)
+			}
+			logger.Infof("Dry run of migration to schema version %v (%v): %v writes, %v deletes",
+				m.Version, m.Description, db.writes, db.deletes)
+		}
+		return nil
+	}
+
+	if r.backup != nil {
+		if err := r.backup(from, to); err != nil {
+			return fmt.Errorf("Failed to back up the database before migration: %v", err)
+		}
+	}
+
+	for _, m := range pending {
+		logger.Infof("Migrating database schema to version %v: %v", m.Version, m.Description)
+		if err := m.Migrate(r.db); err != nil {
+			return fmt.Errorf("Migration to schema version %v failed: %v", m.Version, err)
+		}
+		if err := SetSchemaVersion(r.db, m.Version); err != nil {
+			return err
+		}
+	}
+	logger.Infof("Migrated database schema from version %v to %v", from, to)
+	return nil
+}
+
+// dryRunDatabase reads from the underlying database and counts the writes instead of applying
+// them.
+type dryRunDatabase struct {
+	database.Database
+
+	writes  int
+	deletes int
+}
+
+var _ database.Database = (*dryRunDatabase)(nil)
+
+func newDryRunDatabase(db database.Database) *dryRunDatabase {
+	return &dryRunDatabase{Database: db}
+}
+
+func (db *dryRunDatabase) Put(key []byte, value []byte) error {
+	db.writes++
+	return nil
+}
+
+func (db *dryRunDatabase) Delete(key []byte) error {
+	db.deletes++
+	return nil
+}
+
+func (db *dryRunDatabase) Reference(key []byte) error {
+	db.writes++
+	return nil
+}
+
+func (db *dryRunDatabase) Dereference(key []byte) error {
+	db.writes++
+	return nil
+}
+
+func (db *dryRunDatabase) NewBatch() database.Batch {
+	return &dryRunBatch{db: db}
+}
+
+type dryRunBatch struct {
+	db      *dryRunDatabase
+	writes  int
+	deletes int
+	size    int
+}
+
+func (b *dryRunBatch) Put(key []byte, value []byte) error {
+	b.writes++
+	b.size += len(value)
+	return nil
+}
+
+func (b *dryRunBatch) Delete(key []byte) error {
+	b.deletes++
+	b.size++
+	return nil
+}
+
+func (b *dryRunBatch) Reference(key []byte) error {
+	b.writes++
+	b.size++
+	return nil
+}
+
+func (b *dryRunBatch) Dereference(key []byte) error {
+	b.writes++
+	b.size++
+	return nil
+}
+
+func (b *dryRunBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *dryRunBatch) Write() error {
+	b.db.writes += b.writes
+	b.db.deletes += b.deletes
+	b.Reset()
+	return nil
+}
+
+func (b *dryRunBatch) Reset() {
+	b.writes = 0
+	b.deletes = 0
+	b.size = 0
+}
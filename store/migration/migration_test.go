@@ -0,0 +1,124 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pandotoken/pando/store/database"
+	"github.com/pandotoken/pando/store/database/backend"
+)
+
+func testMigrations(applied *[]uint64) []*Migration {
+	return []*Migration{
+		{Version: 1, Description: "add key a", Migrate: func(db database.Database) error {
+			*applied = append(*applied, 1)
+			return db.Put([]byte("a"), []byte("1"))
+		}},
+		{Version: 2, Description: "move key a to b", Migrate: func(db database.Database) error {
+			*applied = append(*applied, 2)
+			value, err := db.Get([]byte("a"))
+			if err != nil {
+				return err
+			}
+			batch := db.NewBatch()
+			batch.Put([]byte("b"), value)
+			batch.Delete([]byte("a"))
+			return batch.Write()
+		}},
+	}
+}
+
+func TestMigrationRun(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	applied := []uint64{}
+	runner := newRunner(db, testMigrations(&applied))
+
+	version, err := GetSchemaVersion(db)
+	assert.Nil(err)
+	assert.Equal(uint64(0), version)
+
+	pending, err := runner.Pending()
+	assert.Nil(err)
+	assert.Equal(2, len(pending))
+
+	backups := [][2]uint64{}
+	runner.SetBackupHook(func(from uint64, to uint64) error {
+		backups = append(backups, [2]uint64{from, to})
+		return nil
+	})
+	assert.Nil(runner.Run())
+	assert.Equal([]uint64{1, 2}, applied)
+	assert.Equal([][2]uint64{{0, 2}}, backups)
+
+	version, err = GetSchemaVersion(db)
+	assert.Nil(err)
+	assert.Equal(uint64(2), version)
+	value, err := db.Get([]byte("b"))
+	assert.Nil(err)
+	assert.Equal([]byte("1"), value)
+
+	// Nothing left to apply
+	assert.Nil(runner.Run())
+	assert.Equal([]uint64{1, 2}, applied)
+	assert.Equal(1, len(backups))
+
+	// A database from a newer node is rejected
+	assert.Nil(SetSchemaVersion(db, 3))
+	assert.NotNil(runner.Run())
+}
+
+func TestMigrationDryRun(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	db.Put([]byte("a"), []byte("1"))
+	assert.Nil(SetSchemaVersion(db, 1))
+
+	applied := []uint64{}
+	runner := newRunner(db, testMigrations(&applied))
+	runner.SetDryRun(true)
+	runner.SetBackupHook(func(from uint64, to uint64) error {
+		return errors.New("should not back up in dry run")
+	})
+	assert.Nil(runner.Run())
+	assert.Equal([]uint64{2}, applied)
+
+	// Nothing is written
+	version, err := GetSchemaVersion(db)
+	assert.Nil(err)
+	assert.Equal(uint64(1), version)
+	has, _ := db.Has([]byte("b"))
+	assert.False(has)
+	has, _ = db.Has([]byte("a"))
+	assert.True(has)
+}
+
+func TestMigrationFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	applied := []uint64{}
+	ms := testMigrations(&applied)
+	ms = append(ms, &Migration{Version: 3, Migrate: func(db database.Database) error {
+		return errors.New("failed")
+	}})
+	runner := newRunner(db, ms)
+
+	// The migrations are not applied if the backup fails
+	runner.SetBackupHook(func(from uint64, to uint64) error {
+		return errors.New("disk full")
+	})
+	assert.NotNil(runner.Run())
+	assert.Equal(0, len(applied))
+
+	// The version of the last successful migration is recorded
+	runner.SetBackupHook(nil)
+	assert.NotNil(runner.Run())
+	version, err := GetSchemaVersion(db)
+	assert.Nil(err)
+	assert.Equal(uint64(2), version)
+}
@@ -115,3 +115,60 @@ func (store *TreeStore) Delete(key common.Bytes) (deleted bool) {
 func (store *TreeStore) Prune(cb func(n []byte) bool) error {
 	return store.Trie.Prune(cb)
 }
+
+// Diff calls cb on every key whose value differs between the store and the other store,
+// in ascending key order. The old value is nil for the keys added in the other store, and
+// the new value is nil for the keys deleted from it. Iteration stops if cb returns false.
+func (store *TreeStore) Diff(other *TreeStore, cb func(k, oldValue, newValue common.Bytes) bool) error {
+	added, err := diffLeaves(store.Trie, other.Trie)
+	if err != nil {
+		return err
+	}
+	removed, err := diffLeaves(other.Trie, store.Trie)
+	if err != nil {
+		return err
+	}
+
+	i, j := 0, 0
+	for i < len(added) || j < len(removed) {
+		var k, oldValue, newValue common.Bytes
+		switch {
+		case j == len(removed) || (i < len(added) && bytes.Compare(added[i].key, removed[j].key) < 0):
+			k, newValue = added[i].key, added[i].value
+			i++
+		case i == len(added) || bytes.Compare(added[i].key, removed[j].key) > 0:
+			k, oldValue = removed[j].key, removed[j].value
+			j++
+		default:
+			k, oldValue, newValue = added[i].key, removed[j].value, added[i].value
+			i++
+			j++
+			if bytes.Equal(oldValue, newValue) {
+				continue // the leaf only moved within the trie
+			}
+		}
+		if !cb(k, oldValue, newValue) {
+			break
+		}
+	}
+	return nil
+}
+
+type leaf struct {
+	key   common.Bytes
+	value common.Bytes
+}
+
+// diffLeaves returns the key/value pairs of b which are not in a, in ascending key order
+func diffLeaves(a, b *trie.Trie) ([]leaf, error) {
+	diffIt, _ := trie.NewDifferenceIterator(a.NodeIterator(nil), b.NodeIterator(nil))
+	it := trie.NewIterator(diffIt)
+	leaves := []leaf{}
+	for it.Next() {
+		leaves = append(leaves, leaf{
+			key:   common.CopyBytes(it.Key),
+			value: common.CopyBytes(it.Value),
+		})
+	}
+	return leaves, it.Err
+}
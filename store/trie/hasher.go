@@ -19,6 +19,7 @@ package trie
 
 import (
 	"hash"
+	"runtime"
 	"sync"
 
 	"github.com/pandotoken/pando/common"
@@ -32,8 +33,18 @@ type hasher struct {
 	cachegen   uint16
 	cachelimit uint16
 	onleaf     LeafCallback
+	parallel   bool // Whether to hash the children of the top full node in parallel
 }
 
+// parallelHashWorkers is the number of workers hashing the sub-tries of a full
+// node in parallel. A full node has at most 16 children.
+var parallelHashWorkers = func() int {
+	if runtime.NumCPU() < 16 {
+		return runtime.NumCPU()
+	}
+	return 16
+}()
+
 // keccakState wraps sha3.state. In addition to the usual hash methods, it also supports
 // Read to get a variable amount of data from the hash state. Read is faster than Sum
 // because it doesn't copy the internal state, but also modifies the internal state.
@@ -63,9 +74,12 @@ var hasherPool = sync.Pool{
 	},
 }
 
-func newHasher(cachegen, cachelimit uint16, onleaf LeafCallback) *hasher {
+// newHasher returns a hasher from the pool. If parallel is set, the sub-tries under
+// the top full node are hashed concurrently, hence onleaf needs to be safe for
+// concurrent use.
+func newHasher(cachegen, cachelimit uint16, onleaf LeafCallback, parallel bool) *hasher {
 	h := hasherPool.Get().(*hasher)
-	h.cachegen, h.cachelimit, h.onleaf = cachegen, cachelimit, onleaf
+	h.cachegen, h.cachelimit, h.onleaf, h.parallel = cachegen, cachelimit, onleaf, parallel
 	return h
 }
 
@@ -144,6 +158,15 @@ func (h *hasher) hashChildren(original node, db *Database) (node, node, error) {
 		// Hash the full node's children, caching the newly hashed subtrees
 		collapsed, cached := n.copy(), n.copy()
 
+		if h.parallel && parallelHashWorkers > 1 {
+			err = h.hashFullNodeChildrenParallel(n, collapsed, cached, db)
+			if err != nil {
+				return original, original, err
+			}
+			cached.Children[16] = n.Children[16]
+			return collapsed, cached, nil
+		}
+
 		for i := 0; i < 16; i++ {
 			if n.Children[i] != nil {
 				collapsed.Children[i], cached.Children[i], err = h.hash(n.Children[i], db, false)
@@ -161,6 +184,41 @@ func (h *hasher) hashChildren(original node, db *Database) (node, node, error) {
 	}
 }
 
+// hashFullNodeChildrenParallel hashes the children of the full node with a pool of
+// workers. The sub-tries are disjoint, and each worker uses its own hasher, so the
+// only shared state is the database, which is guarded by its lock.
+func (h *hasher) hashFullNodeChildrenParallel(n, collapsed, cached *fullNode, db *Database) error {
+	indices := make(chan int, 16)
+	for i := 0; i < 16; i++ {
+		if n.Children[i] != nil {
+			indices <- i
+		}
+	}
+	close(indices)
+
+	errs := make([]error, 16)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelHashWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := newHasher(h.cachegen, h.cachelimit, h.onleaf, false)
+			defer returnHasherToPool(worker)
+			for i := range indices {
+				collapsed.Children[i], cached.Children[i], errs[i] = worker.hash(n.Children[i], db, false)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // store hashes the node n and if we have a storage layer specified, it writes
 // the key/value pair to it and tracks any node->child references as well as any
 // node->external trie references.
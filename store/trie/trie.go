@@ -79,9 +79,18 @@ type Trie struct {
 	// when their generation is older than than cachegen-cachelimit.
 	cachegen, cachelimit uint16
 
+	// unhashed counts the updates since the last hash operation. The sub-tries are
+	// only hashed in parallel when enough of them are likely to be dirty.
+	unhashed int
+
 	mu *sync.RWMutex // Lock for commit & prune.
 }
 
+// parallelHashThreshold is the minimal number of updates since the last hash
+// operation to hash the sub-tries in parallel, below which spawning the workers
+// costs more than it saves.
+const parallelHashThreshold = 100
+
 // GetDB for testing purpose only
 func (t *Trie) GetDB() *Database {
 	return t.db
@@ -219,6 +228,7 @@ func (t *Trie) Update(key, value []byte) {
 //
 // If a node was not found in the database, a MissingNodeError is returned.
 func (t *Trie) TryUpdate(key, value []byte) error {
+	t.unhashed++
 	k := keybytesToHex(key)
 	if len(value) != 0 {
 		_, n, err := t.insert(t.root, nil, k, valueNode(value))
@@ -315,6 +325,7 @@ func (t *Trie) Delete(key []byte) {
 // TryDelete removes any existing value for key from the trie.
 // If a node was not found in the database, a MissingNodeError is returned.
 func (t *Trie) TryDelete(key []byte) error {
+	t.unhashed++
 	k := keybytesToHex(key)
 	_, n, err := t.delete(t.root, nil, k)
 	if err != nil {
@@ -495,8 +506,9 @@ func (t *Trie) hashRoot(db *Database, onleaf LeafCallback) (node, node, error) {
 	if t.root == nil {
 		return hashNode(emptyRoot.Bytes()), nil, nil
 	}
-	h := newHasher(t.cachegen, t.cachelimit, onleaf)
+	h := newHasher(t.cachegen, t.cachelimit, onleaf, t.unhashed >= parallelHashThreshold)
 	defer returnHasherToPool(h)
+	t.unhashed = 0
 	return h.hash(t.root, db, true)
 }
 
@@ -692,6 +692,51 @@ func BenchmarkHash(b *testing.B) {
 	trie.Hash()
 }
 
+func TestParallelCommit(t *testing.T) {
+	defer func(workers int) { parallelHashWorkers = workers }(parallelHashWorkers)
+	parallelHashWorkers = 4
+
+	random := rand.New(rand.NewSource(0))
+	keys := make([][]byte, 1000)
+	for i := range keys {
+		keys[i] = make([]byte, 32)
+		random.Read(keys[i])
+	}
+
+	parallel, sequential := newEmpty(), newEmpty()
+	for i, key := range keys {
+		value := []byte(fmt.Sprintf("value%d", i))
+		parallel.Update(key, value)
+		sequential.Update(key, value)
+	}
+	sequential.unhashed = 0 // below the threshold, hash sequentially
+
+	parallelRoot, err := parallel.Commit(nil)
+	if err != nil {
+		t.Fatalf("parallel commit error: %v", err)
+	}
+	sequentialRoot, err := sequential.Commit(nil)
+	if err != nil {
+		t.Fatalf("sequential commit error: %v", err)
+	}
+	if parallelRoot != sequentialRoot {
+		t.Errorf("root mismatch: parallel %x, sequential %x", parallelRoot, sequentialRoot)
+	}
+	if len(parallel.db.nodes) != len(sequential.db.nodes) {
+		t.Errorf("node count mismatch: parallel %d, sequential %d", len(parallel.db.nodes), len(sequential.db.nodes))
+	}
+	for hash := range sequential.db.nodes {
+		if _, ok := parallel.db.nodes[hash]; !ok {
+			t.Errorf("node %x missing after the parallel commit", hash)
+		}
+	}
+	for i, key := range keys {
+		if value := parallel.Get(key); !bytes.Equal(value, []byte(fmt.Sprintf("value%d", i))) {
+			t.Fatalf("wrong value for key %x: %q", key, value)
+		}
+	}
+}
+
 func tempDB() (string, *Database) {
 	dir, err := ioutil.TempDir("", "trie-bench")
 	if err != nil {
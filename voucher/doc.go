@@ -0,0 +1,57 @@
+/*
+Package voucher defines the canonical format of the off-chain payment vouchers
+exchanged between a payer and an edge server before the payment is settled on-chain
+with a ServicePaymentTx.
+
+A voucher carries the fields of the ServicePaymentTx signed by the source: the
+chain ID, the source and target addresses, the cumulative amount, the reserve
+sequence, the payment sequence and the resource ID. The voucher signature is the
+source signature of the ServicePaymentTx, so the target can settle the voucher by
+countersigning the transaction returned by Voucher.ServicePaymentTx().
+
+All the encodings below use RLP (https://github.com/ethereum/wiki/wiki/RLP).
+Integers are encoded as big endian with no leading zero bytes, addresses are 20
+bytes strings, and an absent signature or amount is encoded as the empty string 0x80.
+
+# Sign bytes
+
+The signature is a 65 bytes secp256k1 signature [R || S || V], V in {0, 1}, over
+the Keccak256 hash of the sign bytes:
+
+	payload    = RLP(chainID) || RLP(6) || RLP(tx)
+	tx         = [fee, source, target, paymentSequence, reserveSequence, resourceID]
+	fee        = [0, 0]
+	source     = [sourceAddress, [amountPandoWei, amountPTXWei], 0, ""]
+	target     = [targetAddress, ["", ""], 0, ""]
+	signBytes  = RLP([0, 0, 0, 20 zero bytes, 0, payload])
+
+where 6 is the type of the ServicePaymentTx, and the outer list wraps the payload
+into an Ethereum transaction so that the voucher can be signed by Ethereum wallets.
+
+# Wire format
+
+A signed voucher is transmitted as:
+
+	RLP([version, chainID, sourceAddress, targetAddress, [amountPandoWei, amountPTXWei],
+		reserveSequence, paymentSequence, resourceID, signature])
+
+where version is currently 1.
+
+# Verification
+
+Verify() only performs the stateless checks: the format of the fields and that the
+signature is from the source. The edge server still needs to check against its own
+records that the payment sequence and the cumulative amount increase, and against the
+chain that the reserved fund covers the amount.
+
+# Settlement
+
+The ServicePaymentTx of a voucher settles the cumulative amount paid to the target,
+and only the part not settled yet is transferred. The first settlement of a target
+opens a dispute window of types.ServicePaymentDisputeWindow blocks. During the window,
+a voucher with a higher payment sequence overrides the settlement, even after the
+reserved fund expires. The target can thus settle its latest voucher, if a stale
+voucher was settled first. The reserved fund is not released before all its dispute
+windows close. Once the window closes, the settlement is final.
+*/
+package voucher
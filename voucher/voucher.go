@@ -0,0 +1,171 @@
+package voucher
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rlp"
+)
+
+// Version is the version of the voucher wire format.
+const Version uint8 = 1
+
+// signatureLength is the length of a secp256k1 signature in the [R || S || V] format.
+const signatureLength = 65
+
+// Voucher is a payment promise signed by the source, redeemable by the target with a ServicePaymentTx.
+type Voucher struct {
+	ChainID         string
+	Source          common.Address
+	Target          common.Address
+	Amount          types.Coins // cumulative amount paid from the reserved fund
+	ReserveSequence uint64      // ReserveSequence to locate the ReservedFund
+	PaymentSequence uint64      // increased by 1 for each on-chain settlement
+	ResourceID      string
+	Signature       *crypto.Signature
+}
+
+// voucherRLP defines the wire format of the voucher.
+type voucherRLP struct {
+	Version         uint8
+	ChainID         string
+	Source          common.Address
+	Target          common.Address
+	Amount          types.Coins
+	ReserveSequence uint64
+	PaymentSequence uint64
+	ResourceID      string
+	Signature       *crypto.Signature
+}
+
+// NewVoucher creates an unsigned voucher.
+func NewVoucher(chainID string, source, target common.Address, amount types.Coins,
+	reserveSequence, paymentSequence uint64, resourceID string) *Voucher {
+	return &Voucher{
+		ChainID:         chainID,
+		Source:          source,
+		Target:          target,
+		Amount:          amount.NoNil(),
+		ReserveSequence: reserveSequence,
+		PaymentSequence: paymentSequence,
+		ResourceID:      resourceID,
+	}
+}
+
+// FromServicePaymentTx extracts the voucher signed by the source of the given transaction.
+func FromServicePaymentTx(chainID string, tx *types.ServicePaymentTx) *Voucher {
+	v := NewVoucher(chainID, tx.Source.Address, tx.Target.Address, tx.Source.Coins,
+		tx.ReserveSequence, tx.PaymentSequence, tx.ResourceID)
+	v.Signature = tx.Source.Signature
+	return v
+}
+
+// ServicePaymentTx returns the transaction settling the voucher on-chain. The target
+// still needs to set the fee and its own signature.
+func (v *Voucher) ServicePaymentTx() *types.ServicePaymentTx {
+	return &types.ServicePaymentTx{
+		Fee: types.NewCoins(0, 0),
+		Source: types.TxInput{
+			Address:   v.Source,
+			Coins:     v.Amount.NoNil(),
+			Signature: v.Signature,
+		},
+		Target: types.TxInput{
+			Address: v.Target,
+		},
+		PaymentSequence: v.PaymentSequence,
+		ReserveSequence: v.ReserveSequence,
+		ResourceID:      v.ResourceID,
+	}
+}
+
+// SignBytes returns the bytes signed by the source, which are identical to the source
+// sign bytes of the corresponding ServicePaymentTx.
+func (v *Voucher) SignBytes() common.Bytes {
+	return v.ServicePaymentTx().SourceSignBytes(v.ChainID)
+}
+
+// Sign signs the voucher with the given signer, which should hold the key of the source.
+func (v *Voucher) Sign(signer crypto.Signer) error {
+	if signer.PublicKey().Address() != v.Source {
+		return fmt.Errorf("Signer address %v does not match the source %v", signer.PublicKey().Address().Hex(), v.Source.Hex())
+	}
+	sig, err := signer.Sign(v.SignBytes())
+	if err != nil {
+		return err
+	}
+	v.Signature = sig
+	return nil
+}
+
+// Verify performs the stateless checks on the voucher, and verifies it is signed by the source.
+func (v *Voucher) Verify() error {
+	if v.ChainID == "" {
+		return errors.New("Chain ID is empty")
+	}
+	if v.ResourceID == "" {
+		return errors.New("Resource ID is empty")
+	}
+	if (v.Source == common.Address{}) || (v.Target == common.Address{}) {
+		return errors.New("Source or target address is empty")
+	}
+
+	amount := v.Amount.NoNil()
+	if !amount.IsPositive() {
+		return fmt.Errorf("Invalid amount: %v", amount)
+	}
+	if amount.PandoWei.Sign() != 0 && amount.PTXWei.Sign() != 0 {
+		return errors.New("Amount should be in either PandoWei or PTXWei, but not both")
+	}
+
+	if v.Signature == nil || v.Signature.IsEmpty() {
+		return errors.New("Voucher is not signed")
+	}
+	sig := v.Signature.ToBytes()
+	if len(sig) != signatureLength || sig[signatureLength-1] > 1 {
+		return errors.New("Malformed signature")
+	}
+	if !v.Signature.Verify(v.SignBytes(), v.Source) {
+		return fmt.Errorf("Voucher is not signed by the source %v", v.Source.Hex())
+	}
+	return nil
+}
+
+// Encode returns the wire format of the voucher.
+func (v *Voucher) Encode() (common.Bytes, error) {
+	return rlp.EncodeToBytes(voucherRLP{
+		Version:         Version,
+		ChainID:         v.ChainID,
+		Source:          v.Source,
+		Target:          v.Target,
+		Amount:          v.Amount.NoNil(),
+		ReserveSequence: v.ReserveSequence,
+		PaymentSequence: v.PaymentSequence,
+		ResourceID:      v.ResourceID,
+		Signature:       v.Signature,
+	})
+}
+
+// Decode parses a voucher in the wire format. It does not verify the voucher.
+func Decode(raw common.Bytes) (*Voucher, error) {
+	decoded := voucherRLP{}
+	if err := rlp.DecodeBytes(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("Failed to decode voucher: %v", err)
+	}
+	if decoded.Version != Version {
+		return nil, fmt.Errorf("Unsupported voucher version: %v", decoded.Version)
+	}
+	return &Voucher{
+		ChainID:         decoded.ChainID,
+		Source:          decoded.Source,
+		Target:          decoded.Target,
+		Amount:          decoded.Amount.NoNil(),
+		ReserveSequence: decoded.ReserveSequence,
+		PaymentSequence: decoded.PaymentSequence,
+		ResourceID:      decoded.ResourceID,
+		Signature:       decoded.Signature,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package voucher
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoucherSignAndVerify(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, v := createTestVoucher(t)
+	assert.NotNil(v.Verify())
+
+	require.Nil(v.Sign(privKey))
+	assert.Nil(v.Verify())
+
+	otherKey, _, err := crypto.GenerateKeyPair()
+	require.Nil(err)
+	assert.NotNil(v.Sign(otherKey))
+
+	tampered := *v
+	tampered.Amount = types.NewCoins(0, 2000)
+	assert.NotNil(tampered.Verify())
+
+	tampered = *v
+	tampered.ChainID = "othernet"
+	assert.NotNil(tampered.Verify())
+
+	tampered = *v
+	tampered.Amount = types.NewCoins(1, 1000)
+	assert.NotNil(tampered.Verify())
+}
+
+func TestVoucherEncoding(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, v := createTestVoucher(t)
+	require.Nil(v.Sign(privKey))
+
+	raw, err := v.Encode()
+	require.Nil(err)
+	decoded, err := Decode(raw)
+	require.Nil(err)
+	assert.Nil(decoded.Verify())
+	assert.Equal(v.ChainID, decoded.ChainID)
+	assert.Equal(v.Source, decoded.Source)
+	assert.Equal(v.Target, decoded.Target)
+	assert.True(v.Amount.IsEqual(decoded.Amount))
+	assert.Equal(v.ReserveSequence, decoded.ReserveSequence)
+	assert.Equal(v.PaymentSequence, decoded.PaymentSequence)
+	assert.Equal(v.ResourceID, decoded.ResourceID)
+	assert.Equal(v.Signature.ToBytes(), decoded.Signature.ToBytes())
+
+	_, err = Decode(raw[:len(raw)-1])
+	assert.NotNil(err)
+}
+
+// TestVoucherSignBytesLayout checks the sign bytes follow the layout documented for
+// the implementations in other languages.
+func TestVoucherSignBytesLayout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, v := createTestVoucher(t)
+
+	type coins struct{ PandoWei, PTXWei *big.Int }
+	type input struct {
+		Address   common.Address
+		Coins     coins
+		Sequence  uint64
+		Signature []byte
+	}
+	tx := []interface{}{
+		coins{big.NewInt(0), big.NewInt(0)},
+		input{Address: v.Source, Coins: coins{v.Amount.PandoWei, v.Amount.PTXWei}},
+		input{Address: v.Target, Coins: coins{big.NewInt(0), big.NewInt(0)}},
+		v.PaymentSequence,
+		v.ReserveSequence,
+		v.ResourceID,
+	}
+	payload := []byte{}
+	for _, item := range []interface{}{v.ChainID, uint16(6), tx} {
+		encoded, err := rlp.EncodeToBytes(item)
+		require.Nil(err)
+		payload = append(payload, encoded...)
+	}
+	signBytes, err := rlp.EncodeToBytes([]interface{}{
+		uint64(0), big.NewInt(0), uint64(0), common.Address{}, big.NewInt(0), payload,
+	})
+	require.Nil(err)
+
+	assert.Equal(hex.EncodeToString(signBytes), hex.EncodeToString(v.SignBytes()))
+}
+
+func TestVoucherServicePaymentTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, v := createTestVoucher(t)
+	require.Nil(v.Sign(privKey))
+
+	// The voucher signature is accepted as the source signature of the ServicePaymentTx
+	tx := v.ServicePaymentTx()
+	tx.Fee = types.NewCoins(0, 1000000000000)
+	tx.Target.Sequence = 3
+	assert.True(tx.Source.Signature.Verify(tx.SourceSignBytes(v.ChainID), v.Source))
+
+	extracted := FromServicePaymentTx(v.ChainID, tx)
+	assert.Nil(extracted.Verify())
+}
+
+func createTestVoucher(t *testing.T) (*crypto.PrivateKey, *Voucher) {
+	privKey, _, err := crypto.GenerateKeyPair()
+	require.Nil(t, err)
+	v := NewVoucher("testnet", privKey.PublicKey().Address(), common.HexToAddress("0x2"),
+		types.NewCoins(0, 1000), 1, 2, "rid1000001")
+	return privKey, v
+}
@@ -2,6 +2,7 @@ package coldwallet
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 
@@ -22,12 +23,13 @@ var _ types.Wallet = (*ColdWallet)(nil)
 type ColdWallet struct {
 	id string
 
-	hub    *Hub // USB hub scanning
+	hub    *Hub // USB hub scanning, nil for the wallets which are not USB devices
 	driver ks.Driver
 
 	addressPathMap map[common.Address]types.DerivationPath // Known derivation paths for signing operations
 	info           hid.DeviceInfo                          // Known USB device infos about the wallet
-	device         *hid.Device                             // USB device advertising itself as a hardware wallet
+	openDevice     func() (io.ReadWriteCloser, error)      // Opens the device the driver communicates through
+	device         io.ReadWriteCloser                      // USB device advertising itself as a hardware wallet
 
 	stateLock *sync.RWMutex // Protects read and write access to the wallet struct fields
 }
@@ -48,6 +50,14 @@ func NewColdWallet(hub *Hub, deviceInfo hid.DeviceInfo) (*ColdWallet, error) {
 		return nil, err
 	}
 
+	openDevice := func() (io.ReadWriteCloser, error) {
+		device, err := deviceInfo.Open()
+		if err != nil {
+			return nil, err
+		}
+		return device, nil
+	}
+
 	path := deviceInfo.Path
 	walletID := assembleColdWalletID(scheme, path)
 	wallet := &ColdWallet{
@@ -56,6 +66,7 @@ func NewColdWallet(hub *Hub, deviceInfo hid.DeviceInfo) (*ColdWallet, error) {
 		driver:         driver,
 		addressPathMap: nil, // need to set to nil initially
 		info:           deviceInfo,
+		openDevice:     openDevice,
 		device:         nil,
 		stateLock:      &sync.RWMutex{},
 	}
@@ -99,7 +110,7 @@ func (w *ColdWallet) Unlock(address common.Address, password string, derivationP
 		return fmt.Errorf("Wallet already unlocked")
 	}
 	if w.device == nil {
-		device, err := w.info.Open()
+		device, err := w.openDevice()
 		if err != nil {
 			return err
 		}
@@ -141,6 +152,18 @@ func (w *ColdWallet) Derive(path types.DerivationPath, pin bool) (common.Address
 	return address, err
 }
 
+// ConfirmAddress displays the address located on the derivation path on the device, and
+// returns it after the user confirms it
+func (w *ColdWallet) ConfirmAddress(path types.DerivationPath) (common.Address, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	if w.device == nil {
+		return common.Address{}, fmt.Errorf("wallet closed")
+	}
+	return w.driver.ConfirmAddress(path)
+}
+
 func (w *ColdWallet) GetPublicKey(address common.Address) (*crypto.PublicKey, error) {
 	return nil, fmt.Errorf("Not supported for cold wallet")
 }
@@ -157,15 +180,7 @@ func (w *ColdWallet) Sign(address common.Address, txrlp common.Bytes) (*crypto.S
 		return nil, fmt.Errorf("unknown account")
 	}
 
-	w.hub.commsLock.Lock()
-	w.hub.commsPend++
-	w.hub.commsLock.Unlock()
-
-	defer func() {
-		w.hub.commsLock.Lock()
-		w.hub.commsPend--
-		w.hub.commsLock.Unlock()
-	}()
+	defer w.pendComms()()
 
 	// Sign the transaction and verify the sender to avoid hardware fault surprises
 	senderAddr, signed, err := w.driver.SignTx(path, txrlp)
@@ -178,6 +193,23 @@ func (w *ColdWallet) Sign(address common.Address, txrlp common.Bytes) (*crypto.S
 	return signed, nil
 }
 
+// pendComms blocks the hub from enumerating the USB devices while communicating with the device,
+// the returned function releases it
+func (w *ColdWallet) pendComms() func() {
+	if w.hub == nil {
+		return func() {}
+	}
+	w.hub.commsLock.Lock()
+	w.hub.commsPend++
+	w.hub.commsLock.Unlock()
+
+	return func() {
+		w.hub.commsLock.Lock()
+		w.hub.commsPend--
+		w.hub.commsLock.Unlock()
+	}
+}
+
 func (w *ColdWallet) setDriver(driver ks.Driver) {
 	w.driver = driver
 }
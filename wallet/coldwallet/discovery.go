@@ -0,0 +1,95 @@
+package coldwallet
+
+import (
+	"fmt"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet/types"
+)
+
+// DefaultDiscoveryGapLimit is the number of consecutive unused accounts after which the account
+// discovery stops, as recommended by BIP-44
+const DefaultDiscoveryGapLimit = 20
+
+// maxDiscoveredAccounts caps the number of derivation paths scanned by the account discovery
+const maxDiscoveredAccounts = 1000
+
+// ActivityChecker tells whether the account has been used on chain
+type ActivityChecker func(address common.Address) (bool, error)
+
+// DiscoveredAccount is an account found by the account discovery
+type DiscoveredAccount struct {
+	Address common.Address
+	Path    types.DerivationPath
+	Used    bool
+}
+
+// DiscoverAccounts derives the accounts on the sequential derivation paths starting from the base
+// path, i.e. incrementing its last component, and checks each of them for activity on chain. As in
+// BIP-44, the scan stops after gapLimit consecutive unused accounts. The used accounts are returned,
+// and registered in the wallet so that they can sign. The wallet needs to be unlocked.
+func (w *ColdWallet) DiscoverAccounts(base types.DerivationPath, gapLimit int, isUsed ActivityChecker) ([]*DiscoveredAccount, error) {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	if w.device == nil || w.addressPathMap == nil {
+		return nil, fmt.Errorf("wallet locked")
+	}
+
+	defer w.pendComms()()
+
+	accounts, err := discoverAccounts(w.driver.Derive, base, gapLimit, isUsed)
+	for _, account := range accounts {
+		if account.Used {
+			w.addressPathMap[account.Address] = account.Path
+		}
+	}
+	return usedAccounts(accounts), err
+}
+
+// discoverAccounts scans the derivation paths starting from the base path until gapLimit consecutive
+// accounts are unused, and returns all the scanned accounts, including the ones scanned before a failure.
+func discoverAccounts(derive func(path types.DerivationPath) (common.Address, error), base types.DerivationPath,
+	gapLimit int, isUsed ActivityChecker) ([]*DiscoveredAccount, error) {
+	if len(base) == 0 {
+		return nil, fmt.Errorf("empty base derivation path")
+	}
+	if gapLimit <= 0 {
+		gapLimit = DefaultDiscoveryGapLimit
+	}
+
+	accounts := []*DiscoveredAccount{}
+	gap := 0
+	for i := 0; i < maxDiscoveredAccounts && gap < gapLimit; i++ {
+		path := make(types.DerivationPath, len(base))
+		copy(path, base)
+		path[len(path)-1] += uint32(i)
+
+		address, err := derive(path)
+		if err != nil {
+			return accounts, fmt.Errorf("failed to derive the account on path %v: %v", path, err)
+		}
+		used, err := isUsed(address)
+		if err != nil {
+			return accounts, fmt.Errorf("failed to check the activity of %v: %v", address.Hex(), err)
+		}
+
+		accounts = append(accounts, &DiscoveredAccount{Address: address, Path: path, Used: used})
+		if used {
+			gap = 0
+		} else {
+			gap++
+		}
+	}
+	return accounts, nil
+}
+
+func usedAccounts(accounts []*DiscoveredAccount) []*DiscoveredAccount {
+	used := []*DiscoveredAccount{}
+	for _, account := range accounts {
+		if account.Used {
+			used = append(used, account)
+		}
+	}
+	return used
+}
@@ -0,0 +1,57 @@
+package coldwallet
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverAccounts(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	base := types.DefaultBaseDerivationPath
+	// The address of an account is its index
+	derive := func(path types.DerivationPath) (common.Address, error) {
+		return common.BigToAddress(new(big.Int).SetUint64(uint64(path[len(path)-1]))), nil
+	}
+	usedIndexes := map[uint64]bool{0: true, 1: true, 4: true, 7: true}
+	isUsed := func(address common.Address) (bool, error) {
+		return usedIndexes[address.Big().Uint64()], nil
+	}
+
+	accounts, err := discoverAccounts(derive, base, 2, isUsed)
+	require.Nil(err)
+	// The gap limit is reached on accounts 2 and 3, before account 4
+	assert.Equal(4, len(accounts))
+	assert.Equal(2, len(usedAccounts(accounts)))
+
+	// A larger gap limit finds accounts 4 and 7, and stops after accounts 8 to 10
+	accounts, err = discoverAccounts(derive, base, 3, isUsed)
+	require.Nil(err)
+	assert.Equal(11, len(accounts))
+	used := usedAccounts(accounts)
+	require.Equal(4, len(used))
+	assert.Equal(common.BigToAddress(new(big.Int).SetUint64(7)), used[3].Address)
+	assert.Equal("m/44'/60'/0'/0/7", used[3].Path.String())
+	assert.Equal("m/44'/60'/0'/0/0", base.String(), "the base path is not modified")
+
+	// The accounts scanned before a failure are returned
+	failing := func(address common.Address) (bool, error) {
+		if address.Big().Uint64() == 2 {
+			return false, errors.New("connection refused")
+		}
+		return isUsed(address)
+	}
+	accounts, err = discoverAccounts(derive, base, 3, failing)
+	assert.NotNil(err)
+	assert.Equal(2, len(accounts))
+
+	_, err = discoverAccounts(derive, types.DerivationPath{}, 3, isUsed)
+	assert.NotNil(err)
+}
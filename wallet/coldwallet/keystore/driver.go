@@ -15,5 +15,6 @@ type Driver interface {
 	Close() error
 	Heartbeat() error
 	Derive(path types.DerivationPath) (common.Address, error)
+	ConfirmAddress(path types.DerivationPath) (common.Address, error)
 	SignTx(path types.DerivationPath, txrlp common.Bytes) (common.Address, *crypto.Signature, error)
 }
@@ -55,6 +55,7 @@ const (
 	ledgerOpGetConfiguration ledgerOpcode = 0x06 // Returns specific wallet application configuration
 
 	ledgerP1DirectlyFetchAddress    ledgerParam1 = 0x00 // Return address directly from the wallet
+	ledgerP1ConfirmFetchAddress     ledgerParam1 = 0x01 // Display the address on the device and return it after the user confirms
 	ledgerP1InitTransactionData     ledgerParam1 = 0x00 // First transaction data block for signing
 	ledgerP1ContTransactionData     ledgerParam1 = 0x80 // Subsequent transaction data block for signing
 	ledgerP2DiscardAddressChainCode ledgerParam2 = 0x00 // Do not return the chain code along with the address
@@ -69,6 +70,27 @@ var errLedgerReplyInvalidHeader = errors.New("ledger: invalid reply header")
 // when a response does arrive, but it does not contain the expected data.
 var errLedgerInvalidVersionReply = errors.New("ledger: invalid version reply")
 
+// ledgerStatusOK is the status word the Ledger appends to the reply of a successful command.
+const ledgerStatusOK uint16 = 0x9000
+
+// ledgerStatusError is the error returned by a Ledger data exchange if the device replies
+// with a status word other than ledgerStatusOK.
+type ledgerStatusError uint16
+
+func (e ledgerStatusError) Error() string {
+	switch uint16(e) {
+	case 0x6985:
+		return "ledger: request denied by the user"
+	case 0x6a80:
+		return "ledger: invalid data, please make sure to set \"Contract Data\" to Yes"
+	case 0x6b00, 0x6d00, 0x6e00:
+		return "ledger: the Ethereum app is not open on the device"
+	case 0x6982:
+		return "ledger: the device is locked"
+	}
+	return fmt.Sprintf("ledger: unexpected status word 0x%04x", uint16(e))
+}
+
 // ledgerDriver implements the communication with a Ledger hardware wallet.
 type ledgerDriver struct {
 	device  io.ReadWriter // USB device connection to communicate through
@@ -145,7 +167,18 @@ func (w *ledgerDriver) Heartbeat() error {
 // Derive implements keystore.Driver, sending a derivation request to the Ledger
 // and returning the Ethereum address located on that derivation path.
 func (w *ledgerDriver) Derive(path types.DerivationPath) (common.Address, error) {
-	return w.ledgerDerive(path)
+	return w.ledgerDerive(path, ledgerP1DirectlyFetchAddress)
+}
+
+// ConfirmAddress implements keystore.Driver, displaying the address located on the
+// derivation path on the Ledger, and returning it once the user confirms it on the
+// device.
+func (w *ledgerDriver) ConfirmAddress(path types.DerivationPath) (common.Address, error) {
+	// If the Ethereum app doesn't run, abort
+	if w.offline() {
+		return common.Address{}, errors.New("wallet closed")
+	}
+	return w.ledgerDerive(path, ledgerP1ConfirmFetchAddress)
 }
 
 // SignTx implements keystore.Driver, sending the transaction to the Ledger and
@@ -183,6 +216,9 @@ func (w *ledgerDriver) SignTx(path types.DerivationPath, txrlp common.Bytes) (co
 func (w *ledgerDriver) ledgerVersion() ([3]byte, error) {
 	// Send the request and wait for the response
 	reply, err := w.ledgerExchange(ledgerOpGetConfiguration, 0, 0, nil)
+	if _, ok := err.(ledgerStatusError); ok {
+		return [3]byte{}, errLedgerInvalidVersionReply // Command not supported prior to v1.0.2
+	}
 	if err != nil {
 		return [3]byte{}, err
 	}
@@ -226,7 +262,7 @@ func (w *ledgerDriver) ledgerVersion() ([3]byte, error) {
 //   Ethereum address length | 1 byte
 //   Ethereum address        | 40 bytes hex ascii
 //   Chain code if requested | 32 bytes
-func (w *ledgerDriver) ledgerDerive(derivationPath []uint32) (common.Address, error) {
+func (w *ledgerDriver) ledgerDerive(derivationPath []uint32, p1 ledgerParam1) (common.Address, error) {
 	// Flatten the derivation path into the Ledger request
 	path := make([]byte, 1+4*len(derivationPath))
 	path[0] = byte(len(derivationPath))
@@ -234,7 +270,7 @@ func (w *ledgerDriver) ledgerDerive(derivationPath []uint32) (common.Address, er
 		binary.BigEndian.PutUint32(path[1+4*i:], component)
 	}
 	// Send the request and wait for the response
-	reply, err := w.ledgerExchange(ledgerOpRetrieveAddress, ledgerP1DirectlyFetchAddress, ledgerP2DiscardAddressChainCode, path)
+	reply, err := w.ledgerExchange(ledgerOpRetrieveAddress, p1, ledgerP2DiscardAddressChainCode, path)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -252,7 +288,9 @@ func (w *ledgerDriver) ledgerDerive(derivationPath []uint32) (common.Address, er
 
 	// Decode the hex sting into an Ethereum address and return
 	var address common.Address
-	hex.Decode(address[:], hexstr)
+	if _, err := hex.Decode(address[:], hexstr); err != nil {
+		return common.Address{}, fmt.Errorf("reply contains invalid address: %v", err)
+	}
 	return address, nil
 }
 
@@ -438,8 +476,12 @@ func (w *ledgerDriver) ledgerExchange(opcode ledgerOpcode, p1 ledgerParam1, p2 l
 			break
 		}
 	}
-	if len(reply) == 0 {
+	if len(reply) < 2 {
 		return nil, fmt.Errorf("Reply is empty")
 	}
+	// The reply ends with the status word of the command
+	if status := binary.BigEndian.Uint16(reply[len(reply)-2:]); status != ledgerStatusOK {
+		return nil, ledgerStatusError(status)
+	}
 	return reply[:len(reply)-2], nil
 }
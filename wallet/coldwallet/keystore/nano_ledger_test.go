@@ -0,0 +1,73 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLedgerDevice records the APDU commands written to it, and replies to each of them
+// with the next canned reply, framed with the Ledger HID transport header
+type fakeLedgerDevice struct {
+	commands [][]byte
+	replies  [][]byte
+	pending  bytes.Buffer
+}
+
+func (d *fakeLedgerDevice) Write(chunk []byte) (int, error) {
+	// Only single chunk commands are used in the tests
+	length := binary.BigEndian.Uint16(chunk[5:7])
+	d.commands = append(d.commands, append([]byte{}, chunk[7:7+length]...))
+
+	reply := d.replies[0]
+	d.replies = d.replies[1:]
+	frame := make([]byte, 64)
+	copy(frame, []byte{0x01, 0x01, 0x05, 0x00, 0x00})
+	binary.BigEndian.PutUint16(frame[5:], uint16(len(reply)))
+	copy(frame[7:], reply)
+	d.pending.Write(frame)
+	return len(chunk), nil
+}
+
+func (d *fakeLedgerDevice) Read(p []byte) (int, error) {
+	return d.pending.Read(p)
+}
+
+func TestLedgerConfirmAddress(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	address := common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+	addressHex := []byte(hex.EncodeToString(address[:]))
+	addressReply := append([]byte{1, 0x04, byte(len(addressHex))}, addressHex...)
+	addressReply = append(addressReply, 0x90, 0x00)
+
+	device := &fakeLedgerDevice{
+		replies: [][]byte{
+			addressReply,                         // derivation on open
+			{0x01, 0x01, 0x04, 0x05, 0x90, 0x00}, // version
+			addressReply,                         // confirmed address
+			{0x69, 0x85},                         // address rejected by the user
+		},
+	}
+	driver := NewLedgerDriver()
+	require.Nil(driver.Open(device, ""))
+	status, err := driver.Status()
+	require.Nil(err)
+	assert.Equal("Ethereum app v1.4.5 online", status)
+
+	confirmed, err := driver.ConfirmAddress(types.DefaultRootDerivationPath)
+	require.Nil(err)
+	assert.Equal(address, confirmed)
+	assert.Equal(byte(ledgerOpRetrieveAddress), device.commands[2][1])
+	assert.Equal(byte(ledgerP1ConfirmFetchAddress), device.commands[2][2])
+
+	_, err = driver.ConfirmAddress(types.DefaultRootDerivationPath)
+	assert.Equal(ledgerStatusError(0x6985), err)
+}
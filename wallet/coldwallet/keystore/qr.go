@@ -0,0 +1,539 @@
+package keystore
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/secp256k1"
+	"github.com/pandotoken/pando/wallet/coldwallet/keystore/ur"
+	"github.com/pandotoken/pando/wallet/types"
+)
+
+// The air-gapped wallets, e.g. Keystone and AirGap Vault, exchange the accounts, the transactions
+// and the signatures as QR codes, in the URs registered by ERC-4527.
+const (
+	urTypeHDKey          = "crypto-hdkey"
+	urTypeEthSignRequest = "eth-sign-request"
+	urTypeEthSignature   = "eth-signature"
+
+	cborTagUUID    = 37
+	cborTagHDKey   = 303
+	cborTagKeypath = 304
+
+	// The sign bytes are the RLP of a legacy Ethereum transaction
+	ethDataTypeTransaction = 1
+
+	qrSignOrigin      = "Pando"
+	qrFrameInterval   = 250 * time.Millisecond
+	qrFrameImageSize  = 512
+	qrMaxInputLineLen = 1 << 20
+)
+
+const hardenedKeyStart = 0x80000000
+
+// zbarimg, the QR code scanner of the zbar tools, prefixes each decoded QR code with its type
+const zbarQRCodePrefix = "QR-Code:"
+
+// qrDriver implements the communication with an air-gapped wallet through QR codes. The device is
+// the terminal, on which the QR codes are shown, and the URs of the QR codes scanned from the wallet
+// are entered, either as text or as the path of a file with a UR part on each line.
+type qrDriver struct {
+	frameDir string // Directory the QR codes are written to as PNG files, instead of the terminal
+
+	device io.ReadWriter // Terminal to interact with the user
+	lines  chan string   // Lines entered on the terminal
+	quit   chan struct{} // Quit channel of the terminal reader
+	hdKey  *qrHDKey      // Extended public key of the account, scanned from the wallet
+}
+
+// qrHDKey is the extended public key of an account of the wallet, which the addresses are derived
+// from without the wallet
+type qrHDKey struct {
+	pubKey            []byte               // Compressed public key
+	chainCode         []byte               // BIP-32 chain code
+	origin            types.DerivationPath // Derivation path of the key
+	sourceFingerprint uint32               // Fingerprint of the master key, which the wallet signs with
+}
+
+// NewQRDriver creates a driver for the air-gapped wallets signing through QR codes. The QR codes are
+// shown on the terminal, or written as PNG files to frameDir if it is not empty.
+func NewQRDriver(frameDir string) Driver {
+	return &qrDriver{frameDir: frameDir}
+}
+
+// Status implements keystore.Driver, telling whether the account is scanned from the wallet.
+func (w *qrDriver) Status() (string, error) {
+	if w.hdKey == nil {
+		return "Account not scanned yet", nil
+	}
+	return fmt.Sprintf("Account %v scanned", w.hdKey.origin), nil
+}
+
+// Open implements keystore.Driver, starting to read the terminal. There is no password, so that
+// parameter is silently discarded.
+func (w *qrDriver) Open(device io.ReadWriter, password string) error {
+	w.device, w.hdKey = device, nil
+	w.lines, w.quit = make(chan string), make(chan struct{})
+	go readLines(device, w.lines, w.quit)
+	return nil
+}
+
+// Close implements keystore.Driver, forgetting the scanned account.
+func (w *qrDriver) Close() error {
+	if w.quit != nil {
+		close(w.quit)
+	}
+	w.device, w.lines, w.quit, w.hdKey = nil, nil, nil, nil
+	return nil
+}
+
+// Heartbeat implements keystore.Driver. The wallet is not connected, so there is nothing to check.
+func (w *qrDriver) Heartbeat() error {
+	return nil
+}
+
+// Derive implements keystore.Driver, deriving the address on the path from the extended public key
+// of the account. The account is scanned from the wallet the first time.
+func (w *qrDriver) Derive(path types.DerivationPath) (common.Address, error) {
+	if w.device == nil {
+		return common.Address{}, errors.New("wallet closed")
+	}
+	if w.hdKey == nil {
+		fmt.Fprintf(w.device, "Please show the account QR code (ur:%v) on the wallet, and enter the UR scanned from it:\n", urTypeHDKey)
+		account, err := w.readUR(urTypeHDKey, func() {})
+		if err != nil {
+			return common.Address{}, err
+		}
+		if w.hdKey, err = parseHDKey(account); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return w.hdKey.deriveAddress(path)
+}
+
+// ConfirmAddress implements keystore.Driver. The address can only be compared with the one shown on
+// the wallet by the user.
+func (w *qrDriver) ConfirmAddress(path types.DerivationPath) (common.Address, error) {
+	address, err := w.Derive(path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	fmt.Fprintf(w.device, "Please check the wallet shows the address %v on the path %v\n", address.Hex(), path)
+	return address, nil
+}
+
+// SignTx implements keystore.Driver, showing the transaction as an eth-sign-request to be scanned by
+// the wallet, and reading the eth-signature scanned from the wallet once the user signs.
+func (w *qrDriver) SignTx(path types.DerivationPath, txrlp common.Bytes) (common.Address, *crypto.Signature, error) {
+	if w.device == nil || w.hdKey == nil {
+		return common.Address{}, nil, errors.New("wallet closed")
+	}
+	address, err := w.hdKey.deriveAddress(path)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	requestID, err := newRequestID()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	request, err := newEthSignRequest(requestID, txrlp, path, w.hdKey.sourceFingerprint, address)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	fmt.Fprintf(w.device, "Please scan the QR code of the transaction with the wallet and sign it, then enter the signature (ur:%v) scanned from the wallet:\n", urTypeEthSignature)
+	stop, err := w.showUR(request)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	response, err := w.readUR(urTypeEthSignature, stop)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	signature, err := parseEthSignature(response, requestID)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	sender, err := signature.RecoverSignerAddress(txrlp)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return sender, signature, nil
+}
+
+// showUR shows the UR as a QR code on the terminal, animated if the UR has multiple parts, or writes
+// the frames of the QR code as PNG files. The returned function stops the animation.
+func (w *qrDriver) showUR(u *ur.UR) (func(), error) {
+	parts := u.Encode(ur.DefaultMaxFragmentLen)
+	codes := make([]*qrcode.QRCode, len(parts))
+	for i, part := range parts {
+		// The upper case URs are encoded in the denser alphanumeric mode
+		code, err := qrcode.New(strings.ToUpper(part), qrcode.Low)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+
+	if len(w.frameDir) != 0 {
+		return func() {}, w.writeFrames(u.Type, parts, codes)
+	}
+	if len(codes) == 1 {
+		fmt.Fprintln(w.device, codes[0].ToSmallString(false))
+		fmt.Fprintln(w.device, parts[0])
+		return func() {}, nil
+	}
+
+	quit, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(qrFrameInterval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			frame := fmt.Sprintf("Frame %v/%v\n%v", i%len(codes)+1, len(codes), codes[i%len(codes)].ToSmallString(false))
+			fmt.Fprint(w.device, frame)
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+			}
+			// Move the cursor back up to draw the next frame over this one
+			fmt.Fprintf(w.device, "\x1b[%vA", strings.Count(frame, "\n"))
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(quit)
+			<-done
+		})
+	}, nil
+}
+
+// writeFrames writes the frames of the QR code as PNG files, replacing the ones of the previous UR of
+// the type, to be shown in a loop, e.g. as a slideshow. The parts of the UR are written as text too.
+func (w *qrDriver) writeFrames(urType string, parts []string, codes []*qrcode.QRCode) error {
+	if err := os.MkdirAll(w.frameDir, 0700); err != nil {
+		return err
+	}
+	stale, err := filepath.Glob(filepath.Join(w.frameDir, urType+"-*.png"))
+	if err != nil {
+		return err
+	}
+	for _, file := range stale {
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+	for i, code := range codes {
+		file := filepath.Join(w.frameDir, fmt.Sprintf("%v-%03d.png", urType, i+1))
+		if err := code.WriteFile(qrFrameImageSize, file); err != nil {
+			return err
+		}
+	}
+	partsFile := filepath.Join(w.frameDir, urType+".txt")
+	if err := ioutil.WriteFile(partsFile, []byte(strings.Join(parts, "\n")+"\n"), 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.device, "The %v frame(s) of the QR code are written to %v, and the UR to %v\n", len(codes), filepath.Join(w.frameDir, urType+"-*.png"), partsFile)
+	return nil
+}
+
+// readUR reads the parts of a UR of the type entered on the terminal, until the UR is complete. The
+// stop function is called once the first line is entered.
+func (w *qrDriver) readUR(urType string, stop func()) (*ur.UR, error) {
+	decoder := ur.NewDecoder()
+	for {
+		line, ok := <-w.lines
+		stop()
+		if !ok {
+			return nil, fmt.Errorf("no ur:%v was entered", urType)
+		}
+		parts, err := expandURInput(line)
+		if err != nil {
+			fmt.Fprintf(w.device, "%v, please try again:\n", err)
+			continue
+		}
+		for _, part := range parts {
+			if err := decoder.Receive(part); err != nil {
+				fmt.Fprintf(w.device, "Invalid UR part: %v\n", err)
+			}
+		}
+		if !decoder.IsComplete() {
+			if received, total := decoder.Progress(); total > 0 {
+				fmt.Fprintf(w.device, "Received %v/%v parts, please enter the next one:\n", received, total)
+			}
+			continue
+		}
+		if result := decoder.Result(); result.Type != urType {
+			fmt.Fprintf(w.device, "Expected ur:%v, got ur:%v, please try again:\n", urType, result.Type)
+			decoder = ur.NewDecoder()
+			continue
+		}
+		return decoder.Result(), nil
+	}
+}
+
+// expandURInput returns the UR parts of a line entered on the terminal, which is either a part, or
+// the path of a file with a part on each line, e.g. the output of "zbarimg --raw" on the QR codes.
+func expandURInput(line string) ([]string, error) {
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), zbarQRCodePrefix))
+	if len(line) == 0 {
+		return nil, nil
+	}
+	if strings.HasPrefix(strings.ToLower(line), "ur:") {
+		return []string{line}, nil
+	}
+	content, err := ioutil.ReadFile(line)
+	if err != nil {
+		return nil, errors.New("Neither a UR nor a readable file")
+	}
+	var parts []string
+	for _, part := range strings.Split(string(content), "\n") {
+		part = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), zbarQRCodePrefix))
+		if len(part) != 0 {
+			parts = append(parts, part)
+		}
+	}
+	return parts, nil
+}
+
+// readLines sends the lines read from the terminal to the channel, until the end of the input or
+// the quit channel is closed
+func readLines(device io.Reader, lines chan<- string, quit <-chan struct{}) {
+	defer close(lines)
+	scanner := bufio.NewScanner(device)
+	scanner.Buffer(make([]byte, 4096), qrMaxInputLineLen)
+	for scanner.Scan() {
+		select {
+		case lines <- scanner.Text():
+		case <-quit:
+			return
+		}
+	}
+}
+
+// newRequestID generates a random (version 4) UUID identifying the sign request
+func newRequestID() ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return id, nil
+}
+
+// newEthSignRequest creates the eth-sign-request UR of the transaction
+func newEthSignRequest(requestID []byte, txrlp common.Bytes, path types.DerivationPath, sourceFingerprint uint32, address common.Address) (*ur.UR, error) {
+	return ur.New(urTypeEthSignRequest, ur.Map{
+		1: ur.Tag{Number: cborTagUUID, Content: requestID},
+		2: []byte(txrlp),
+		3: uint64(ethDataTypeTransaction),
+		5: ur.Tag{Number: cborTagKeypath, Content: encodeKeypath(path, sourceFingerprint)},
+		6: address.Bytes(),
+		7: qrSignOrigin,
+	})
+}
+
+// parseEthSignature parses the signature of the eth-signature UR, which answers the sign request of
+// the ID. The recovery ID is normalized, since the wallets encode it as in Ethereum.
+func parseEthSignature(u *ur.UR, requestID []byte) (*crypto.Signature, error) {
+	v, err := u.Value()
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(ur.Map)
+	if !ok {
+		return nil, errors.New("invalid eth-signature")
+	}
+	if id, ok := m[1]; ok {
+		tag, ok := id.(ur.Tag)
+		if !ok || tag.Number != cborTagUUID {
+			return nil, errors.New("invalid eth-signature request ID")
+		}
+		if content, ok := tag.Content.([]byte); !ok || !bytes.Equal(content, requestID) {
+			return nil, errors.New("the signature answers another sign request")
+		}
+	}
+	sigBytes, ok := m[2].([]byte)
+	if !ok || len(sigBytes) < 65 {
+		return nil, errors.New("invalid eth-signature signature")
+	}
+
+	// The recovery ID is 27 + {0, 1}, or 35 + 2 * chain ID + {0, 1} as in EIP-155
+	recovery := new(big.Int).SetBytes(sigBytes[64:])
+	if recovery.Cmp(big.NewInt(35)) >= 0 {
+		recovery.Sub(recovery, big.NewInt(35)).Mod(recovery, big.NewInt(2))
+	} else if recovery.Cmp(big.NewInt(27)) >= 0 {
+		recovery.Sub(recovery, big.NewInt(27))
+	}
+	if recovery.Cmp(big.NewInt(1)) > 0 {
+		return nil, fmt.Errorf("invalid signature recovery ID %v", new(big.Int).SetBytes(sigBytes[64:]))
+	}
+	signature := append(append([]byte{}, sigBytes[:64]...), byte(recovery.Uint64()))
+	return crypto.SignatureFromBytes(signature)
+}
+
+// parseHDKey parses the extended public key of the crypto-hdkey UR
+func parseHDKey(u *ur.UR) (*qrHDKey, error) {
+	v, err := u.Value()
+	if err != nil {
+		return nil, err
+	}
+	if tag, ok := v.(ur.Tag); ok && tag.Number == cborTagHDKey {
+		v = tag.Content
+	}
+	m, ok := v.(ur.Map)
+	if !ok {
+		return nil, errors.New("invalid crypto-hdkey")
+	}
+	if isPrivate, _ := m[2].(bool); isPrivate {
+		return nil, errors.New("the crypto-hdkey is a private key, please show the public key of the account")
+	}
+	pubKey, ok := m[3].([]byte)
+	if !ok || len(pubKey) != 33 {
+		return nil, errors.New("invalid crypto-hdkey public key")
+	}
+	if x, _ := secp256k1.DecompressPubkey(pubKey); x == nil {
+		return nil, errors.New("invalid crypto-hdkey public key")
+	}
+	chainCode, ok := m[4].([]byte)
+	if !ok || len(chainCode) != 32 {
+		return nil, errors.New("the crypto-hdkey has no chain code to derive the addresses")
+	}
+	originTag, ok := m[6].(ur.Tag)
+	if !ok || originTag.Number != cborTagKeypath {
+		return nil, errors.New("the crypto-hdkey has no origin derivation path")
+	}
+	origin, sourceFingerprint, err := decodeKeypath(originTag.Content)
+	if err != nil {
+		return nil, err
+	}
+	return &qrHDKey{
+		pubKey:            pubKey,
+		chainCode:         chainCode,
+		origin:            origin,
+		sourceFingerprint: sourceFingerprint,
+	}, nil
+}
+
+// encodeKeypath encodes the derivation path as a crypto-keypath
+func encodeKeypath(path types.DerivationPath, sourceFingerprint uint32) ur.Map {
+	components := make([]interface{}, 0, 2*len(path))
+	for _, index := range path {
+		components = append(components, uint64(index&^hardenedKeyStart), index >= hardenedKeyStart)
+	}
+	keypath := ur.Map{1: components}
+	if sourceFingerprint != 0 {
+		keypath[2] = uint64(sourceFingerprint)
+	}
+	return keypath
+}
+
+// decodeKeypath decodes the derivation path and the source fingerprint of a crypto-keypath
+func decodeKeypath(v interface{}) (types.DerivationPath, uint32, error) {
+	m, ok := v.(ur.Map)
+	if !ok {
+		return nil, 0, errors.New("invalid crypto-keypath")
+	}
+	components, ok := m[1].([]interface{})
+	if !ok || len(components)%2 != 0 {
+		return nil, 0, errors.New("invalid crypto-keypath components")
+	}
+	path := types.DerivationPath{}
+	for i := 0; i < len(components); i += 2 {
+		index, ok := components[i].(uint64)
+		hardened, isBool := components[i+1].(bool)
+		if !ok || !isBool || index >= hardenedKeyStart {
+			return nil, 0, errors.New("unsupported crypto-keypath component")
+		}
+		if hardened {
+			index += hardenedKeyStart
+		}
+		path = append(path, uint32(index))
+	}
+	var sourceFingerprint uint64
+	if fingerprint, ok := m[2]; ok {
+		if sourceFingerprint, ok = fingerprint.(uint64); !ok || sourceFingerprint > 0xffffffff {
+			return nil, 0, errors.New("invalid crypto-keypath source fingerprint")
+		}
+	}
+	return path, uint32(sourceFingerprint), nil
+}
+
+// deriveAddress derives the address on the path, which must extend the origin of the key with
+// non-hardened indexes, since only those can be derived from a public key
+func (k *qrHDKey) deriveAddress(path types.DerivationPath) (common.Address, error) {
+	if len(path) < len(k.origin) {
+		return common.Address{}, fmt.Errorf("the path %v is not derived from the account %v", path, k.origin)
+	}
+	for i, index := range k.origin {
+		if path[i] != index {
+			return common.Address{}, fmt.Errorf("the path %v is not derived from the account %v", path, k.origin)
+		}
+	}
+
+	pubKey, chainCode := k.pubKey, k.chainCode
+	for _, index := range path[len(k.origin):] {
+		var err error
+		if pubKey, chainCode, err = deriveChildPublicKey(pubKey, chainCode, index); err != nil {
+			return common.Address{}, fmt.Errorf("Failed to derive %v: %v", path, err)
+		}
+	}
+
+	x, y := secp256k1.DecompressPubkey(pubKey)
+	publicKey, err := crypto.PublicKeyFromBytes(secp256k1.S256().Marshal(x, y))
+	if err != nil {
+		return common.Address{}, err
+	}
+	return publicKey.Address(), nil
+}
+
+// deriveChildPublicKey derives the non-hardened child public key of the index as specified by BIP-32
+func deriveChildPublicKey(pubKey, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	if index >= hardenedKeyStart {
+		return nil, nil, errors.New("hardened indexes cannot be derived from a public key")
+	}
+	data := make([]byte, len(pubKey)+4)
+	copy(data, pubKey)
+	binary.BigEndian.PutUint32(data[len(pubKey):], index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curve := secp256k1.S256()
+	if new(big.Int).SetBytes(sum[:32]).Cmp(curve.Params().N) >= 0 {
+		return nil, nil, errors.New("invalid child key, use the next index")
+	}
+	x, y := secp256k1.DecompressPubkey(pubKey)
+	if x == nil {
+		return nil, nil, errors.New("invalid public key")
+	}
+	tweakX, tweakY := curve.ScalarBaseMult(sum[:32])
+	childX, childY := curve.Add(x, y, tweakX, tweakY)
+	if childX.Sign() == 0 && childY.Sign() == 0 {
+		return nil, nil, errors.New("invalid child key, use the next index")
+	}
+	return secp256k1.CompressPubkey(childX, childY), sum[32:], nil
+}
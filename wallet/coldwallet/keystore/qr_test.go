@@ -0,0 +1,158 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/secp256k1"
+	"github.com/pandotoken/pando/wallet/coldwallet/keystore/ur"
+	"github.com/pandotoken/pando/wallet/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQRTerminal plays the user of an air-gapped wallet: the lines entered on the terminal are read
+// from a pipe, and each sign request shown on the terminal is answered by the wallet function
+type fakeQRTerminal struct {
+	io.Reader
+	input  *io.PipeWriter
+	wallet func(request string) string
+
+	mu     sync.Mutex
+	output bytes.Buffer
+}
+
+func newFakeQRTerminal(wallet func(request string) string) *fakeQRTerminal {
+	reader, writer := io.Pipe()
+	return &fakeQRTerminal{Reader: reader, input: writer, wallet: wallet}
+}
+
+func (t *fakeQRTerminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, line := range strings.Split(string(p), "\n") {
+		if strings.HasPrefix(line, "ur:"+urTypeEthSignRequest+"/") {
+			go t.enter(t.wallet(line))
+		}
+	}
+	return t.output.Write(p)
+}
+
+func (t *fakeQRTerminal) enter(line string) {
+	t.input.Write([]byte(line + "\n"))
+}
+
+func TestQRDeriveChildPublicKey(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// BIP-32 test vector 1, from m/0' to m/0'/1
+	pubKey, _ := hex.DecodeString("035a784662a4a20a65bf6aab9ae98a6c068a81c52e4b032c0fb5400c706cfccc56")
+	chainCode, _ := hex.DecodeString("47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141")
+	childPubKey, childChainCode, err := deriveChildPublicKey(pubKey, chainCode, 1)
+	require.Nil(err)
+	assert.Equal("03501e454bf00751f24b1b489aa925215d66af2234e3891c3b21a52bedb3cd711c", hex.EncodeToString(childPubKey))
+	assert.Equal("2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19", hex.EncodeToString(childChainCode))
+
+	_, _, err = deriveChildPublicKey(pubKey, chainCode, hardenedKeyStart+2)
+	assert.NotNil(err)
+
+	key := &qrHDKey{pubKey: pubKey, chainCode: chainCode, origin: types.DerivationPath{hardenedKeyStart}}
+	x, y := secp256k1.DecompressPubkey(childPubKey)
+	childKey, err := crypto.PublicKeyFromBytes(secp256k1.S256().Marshal(x, y))
+	require.Nil(err)
+	address, err := key.deriveAddress(types.DerivationPath{hardenedKeyStart, 1})
+	require.Nil(err)
+	assert.Equal(childKey.Address(), address)
+
+	// The path must extend the origin of the key
+	_, err = key.deriveAddress(types.DerivationPath{hardenedKeyStart + 1, 1})
+	assert.NotNil(err)
+	_, err = key.deriveAddress(types.DerivationPath{hardenedKeyStart, hardenedKeyStart + 1})
+	assert.NotNil(err)
+}
+
+func TestQRDriverSignTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, pubKey, err := crypto.GenerateKeyPair()
+	require.Nil(err)
+	ecdsaPubKey := crypto.PubKeyToECDSA(pubKey)
+	path := types.DefaultBaseDerivationPath
+
+	// The account is exported at the path of the key itself, so no derivation is needed
+	account, err := ur.New(urTypeHDKey, ur.Map{
+		3: secp256k1.CompressPubkey(ecdsaPubKey.X, ecdsaPubKey.Y),
+		4: bytes.Repeat([]byte{0x01}, 32),
+		6: ur.Tag{Number: cborTagKeypath, Content: encodeKeypath(path, 0x12345678)},
+	})
+	require.Nil(err)
+	// The account is scanned with zbarimg to a file
+	dir, err := ioutil.TempDir("", "qr")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+	accountFile := filepath.Join(dir, "account.txt")
+	require.Nil(ioutil.WriteFile(accountFile, []byte(zbarQRCodePrefix+strings.ToUpper(account.Encode(ur.DefaultMaxFragmentLen)[0])+"\n"), 0600))
+
+	txrlp := common.Bytes("the sign bytes of a transaction")
+	var request ur.Map
+	terminal := newFakeQRTerminal(func(part string) string {
+		u, err := ur.Decode(part)
+		require.Nil(err)
+		v, err := u.Value()
+		require.Nil(err)
+		request = v.(ur.Map)
+
+		signature, err := privKey.Sign(common.Bytes(request[2].([]byte)))
+		require.Nil(err)
+		sigBytes := signature.ToBytes()
+		sigBytes[64] += 27
+		response, err := ur.New(urTypeEthSignature, ur.Map{1: request[1], 2: []byte(sigBytes)})
+		require.Nil(err)
+		return response.Encode(ur.DefaultMaxFragmentLen)[0]
+	})
+	go terminal.enter(accountFile)
+
+	driver := NewQRDriver("")
+	require.Nil(driver.Open(terminal, ""))
+	defer driver.Close()
+
+	address, err := driver.Derive(path)
+	require.Nil(err)
+	assert.Equal(pubKey.Address(), address)
+	status, err := driver.Status()
+	require.Nil(err)
+	assert.Equal("Account m/44'/60'/0'/0/0 scanned", status)
+
+	sender, signature, err := driver.SignTx(path, txrlp)
+	require.Nil(err)
+	assert.Equal(address, sender)
+	assert.True(signature.Verify(txrlp, address))
+
+	assert.Equal([]byte(txrlp), request[2])
+	assert.Equal(uint64(ethDataTypeTransaction), request[3])
+	assert.Equal(ur.Tag{Number: cborTagKeypath, Content: ur.Map{
+		1: []interface{}{uint64(44), true, uint64(60), true, uint64(0), true, uint64(0), false, uint64(0), false},
+		2: uint64(0x12345678),
+	}}, request[5])
+	assert.Equal(address.Bytes(), request[6])
+
+	// The signature of another request is rejected
+	response, err := ur.New(urTypeEthSignature, ur.Map{
+		1: ur.Tag{Number: cborTagUUID, Content: make([]byte, 16)},
+		2: []byte(signature.ToBytes()),
+	})
+	require.Nil(err)
+	_, err = parseEthSignature(response, request[1].(ur.Tag).Content.([]byte))
+	assert.NotNil(err)
+}
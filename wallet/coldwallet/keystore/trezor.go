@@ -41,15 +41,19 @@ import (
 
 const MAX_PASSPHRASE_LENGTH = 50
 
+// errTrezorCancelled is returned when the user cancels the action on the Trezor device
+var errTrezorCancelled = errors.New("trezor: action cancelled by the user")
+
 // trezorDriver implements the communication with a Trezor hardware wallet.
 type trezorDriver struct {
-	bridge  trezor.BridgeTransport
-	ui      *trezor.TrezorUI
-	device  io.ReadWriter // USB device connection to communicate through
-	version [3]uint32     // Current version of the Trezor firmware
-	label   string        // Current textual label of the Trezor device
-	pinwait bool          // Flags whether the device is waiting for PIN entry
-	failure error         // Any failure that would make the device unusable
+	bridge     trezor.BridgeTransport
+	ui         *trezor.TrezorUI
+	device     io.ReadWriter // USB device connection to communicate through
+	version    [3]uint32     // Current version of the Trezor firmware
+	label      string        // Current textual label of the Trezor device
+	pinwait    bool          // Flags whether the device is waiting for PIN entry
+	passphrase string        // Passphrase provided on open, prompted for if empty
+	failure    error         // Any failure that would make the device unusable
 }
 
 // newTrezorDriver creates a new instance of a Trezor USB protocol driver.
@@ -95,6 +99,7 @@ func (w *trezorDriver) Open(device io.ReadWriter, passphrase string) error {
 	w.version = [3]uint32{w.bridge.Features.GetMajorVersion(), w.bridge.Features.GetMinorVersion(), w.bridge.Features.GetPatchVersion()}
 	w.label = w.bridge.Features.GetLabel()
 	w.device, w.failure = device, nil
+	w.passphrase = passphrase
 	return w.bridge.CheckFirmwareVersion(w.version, false)
 }
 
@@ -102,7 +107,7 @@ func (w *trezorDriver) Open(device io.ReadWriter, passphrase string) error {
 // the Trezor driver.
 func (w *trezorDriver) Close() error {
 	w.bridge.EndSession()
-	w.version, w.label, w.pinwait = [3]uint32{}, "", false
+	w.version, w.label, w.pinwait, w.passphrase = [3]uint32{}, "", false, ""
 	return nil
 }
 
@@ -117,7 +122,16 @@ func (w *trezorDriver) Heartbeat() error {
 // Derive implements keystore.Driver, sending a derivation request to the Trezor
 // and returning the Pando address located on that derivation path.
 func (w *trezorDriver) Derive(path types.DerivationPath) (common.Address, error) {
-	return w.trezorDerive(path)
+	return w.trezorDerive(path, false)
+}
+
+// ConfirmAddress implements keystore.Driver, displaying the address located on the
+// derivation path on the Trezor for the user to confirm.
+func (w *trezorDriver) ConfirmAddress(path types.DerivationPath) (common.Address, error) {
+	if w.device == nil {
+		return common.Address{}, errors.New("wallet closed")
+	}
+	return w.trezorDerive(path, true)
 }
 
 // SignTx implements keystore.Driver, sending the transaction to the Trezor and
@@ -132,7 +146,7 @@ func (w *trezorDriver) SignTx(path types.DerivationPath, txrlp common.Bytes) (co
 
 // trezorDerive sends a derivation request to the Trezor device and returns the
 // Pando address located on that path.
-func (w *trezorDriver) trezorDerive(derivationPath []uint32) (common.Address, error) {
+func (w *trezorDriver) trezorDerive(derivationPath []uint32, showDisplay bool) (common.Address, error) {
 	err := w.bridge.BeginSession()
 	if err != nil {
 		return common.Address{}, err
@@ -141,7 +155,7 @@ func (w *trezorDriver) trezorDerive(derivationPath []uint32) (common.Address, er
 
 	request := &trezor.PandoGetAddress{
 		AddressN:    derivationPath,
-		ShowDisplay: false,
+		ShowDisplay: showDisplay,
 	}
 	res, msgType, err := w.trezorExchange(request)
 	if err != nil {
@@ -150,9 +164,12 @@ func (w *trezorDriver) trezorDerive(derivationPath []uint32) (common.Address, er
 
 	res, err = w.handleResponse(res, msgType, err)
 	if err != nil {
-		return common.Address{}, nil
+		return common.Address{}, err
+	}
+	resp, ok := res.(*trezor.PandoAddress)
+	if !ok {
+		return common.Address{}, fmt.Errorf("Unexpected reply %v", getMessageName(res))
 	}
-	resp := res.(*trezor.PandoAddress)
 	addr := common.Address{}
 
 	copy(addr[:], common.Hex2Bytes(string(resp.Address)[2:]))
@@ -179,7 +196,10 @@ func (w *trezorDriver) trezorSignMsg(derivationPath []uint32, txrlp common.Bytes
 	if err != nil {
 		return common.Address{}, nil, err
 	}
-	response := res.(*trezor.PandoMessageSignature)
+	response, ok := res.(*trezor.PandoMessageSignature)
+	if !ok {
+		return common.Address{}, nil, fmt.Errorf("Unexpected reply %v", getMessageName(res))
+	}
 	responseSig := response.Signature
 	if len(responseSig) != 65 {
 		return common.Address{}, nil, errors.New("Signature should be 65 bytes long")
@@ -211,7 +231,9 @@ func (w *trezorDriver) trezorSign(derivationPath []uint32, txrlp common.Bytes) (
 	defer w.bridge.EndSession()
 
 	tx := &tp.EthereumTxWrapper{}
-	err = rlp.DecodeBytes(txrlp, tx)
+	if err = rlp.DecodeBytes(txrlp, tx); err != nil {
+		return common.Address{}, nil, err
+	}
 
 	// Create the transaction initiation message
 	data := tx.Payload
@@ -243,18 +265,27 @@ func (w *trezorDriver) trezorSign(derivationPath []uint32, txrlp common.Bytes) (
 	if err != nil {
 		return common.Address{}, nil, err
 	}
-	response := res.(*trezor.PandoTxRequest)
+	response, ok := res.(*trezor.PandoTxRequest)
+	if !ok {
+		return common.Address{}, nil, fmt.Errorf("Unexpected reply %v", getMessageName(res))
+	}
 
 	for response.DataLength != 0 && int(response.DataLength) <= len(data) {
 		chunk := data[:response.DataLength]
 		data = data[response.DataLength:]
 
 		request := &trezor.PandoTxAck{DataChunk: chunk}
-		res, _, err := w.trezorExchange(request)
+		res, msgType, err := w.trezorExchange(request)
 		if err != nil {
 			return common.Address{}, nil, err
 		}
-		response = res.(*trezor.PandoTxRequest)
+		res, err = w.handleResponse(res, msgType, err)
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+		if response, ok = res.(*trezor.PandoTxRequest); !ok {
+			return common.Address{}, nil, fmt.Errorf("Unexpected reply %v", getMessageName(res))
+		}
 	}
 
 	// Extract the Pando signature and do a sanity validation
@@ -306,9 +337,9 @@ func (w *trezorDriver) handleResponse(res interface{}, msgType trezor.MessageTyp
 		} else if msgType == trezor.MessageType_MessageType_Failure {
 			response := res.(*trezor.Failure)
 			if response.Code == trezor.FailureType_Failure_ActionCancelled {
-				return nil, nil //TODO
+				return nil, errTrezorCancelled
 			}
-			return nil, fmt.Errorf("Failed to sign tx, %v", response.Message)
+			return nil, fmt.Errorf("Trezor request failed, %v", response.Message)
 		} else {
 			break
 		}
@@ -326,37 +357,43 @@ func (w *trezorDriver) callbackButton(msg *trezor.ButtonRequest) (interface{}, t
 	return w.trezorRead()
 }
 
+// callbackPin prompts for the PIN in the scrambled matrix layout shown on the device. The
+// digits entered are the positions on the matrix, not the PIN digits themselves.
 func (w *trezorDriver) callbackPin(msg *trezor.PinMatrixRequest) (interface{}, trezor.MessageType, error) {
-	pin := w.ui.GetPin(msg.Type)
-	// except exceptions.Cancelled:
-	//     self.call_raw(messages.Cancel())
+	w.pinwait = true
+	defer func() { w.pinwait = false }()
+
+	pin, ok := w.ui.GetPin(msg.Type)
+	if !ok {
+		w.trezorWrite(&trezor.Cancel{})
+		return nil, 0, errTrezorCancelled
+	}
 
-	request := &trezor.PinMatrixAck{Pin: string(pin)}
+	request := &trezor.PinMatrixAck{Pin: pin}
 	res, msgType, err := w.trezorExchange(request)
 	if err != nil {
 		return nil, 0, err
 	}
 	if msgType == trezor.MessageType_MessageType_Failure {
 		response := res.(*trezor.Failure)
-		//and resp.code in (
-		// messages.FailureType.PinInvalid,
-		// messages.FailureType.PinCancelled,
-		// messages.FailureType.PinExpected,
+		switch response.Code {
+		case trezor.FailureType_Failure_PinInvalid:
+			return nil, 0, errors.New("trezor: invalid PIN, the device delays further attempts")
+		case trezor.FailureType_Failure_PinCancelled, trezor.FailureType_Failure_ActionCancelled:
+			return nil, 0, errTrezorCancelled
+		}
 		return nil, 0, fmt.Errorf("Pin failed (%v), %v", response.Code, response.Message)
 	}
 	return res, msgType, nil
 }
 
+// callbackPassphrase answers the passphrase request with the passphrase provided on open,
+// or prompts for it if none was provided.
 func (w *trezorDriver) callbackPassphrase(msg *trezor.PassphraseRequest) (interface{}, trezor.MessageType, error) {
-	// if msg.on_device:
-	//     passphrase = None
-	// else:
-
-	passphrase := w.ui.GetPassphrase()
-	// except exceptions.Cancelled:
-	//     self.call_raw(messages.Cancel())
-
-	// passphrase = Mnemonic.normalize_string(passphrase)
+	passphrase := w.passphrase
+	if passphrase == "" {
+		passphrase = w.ui.GetPassphrase()
+	}
 
 	if len(passphrase) > MAX_PASSPHRASE_LENGTH {
 		w.trezorWrite(&trezor.Cancel{})
@@ -364,12 +401,6 @@ func (w *trezorDriver) callbackPassphrase(msg *trezor.PassphraseRequest) (interf
 	}
 
 	return w.trezorExchange(&trezor.PassphraseAck{Passphrase: passphrase})
-
-	// if isinstance(resp, messages.PassphraseStateRequest):
-	//     self.state = resp.state
-	//     return self.call_raw(messages.PassphraseStateAck())
-	// else:
-	//     return resp
 }
 
 func getMessageName(v interface{}) string {
@@ -4440,215 +4440,213 @@ func init() {
 func init() { proto.RegisterFile("messages.proto", fileDescriptor_4dc296cbfe5ffcd5) }
 
 var fileDescriptor_4dc296cbfe5ffcd5 = []byte{
-	// 3354 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0xcd, 0x73, 0xdc, 0xc6,
-	0x72, 0xcf, 0x7e, 0x70, 0xb9, 0xdb, 0xfb, 0xc1, 0x21, 0x44, 0x49, 0x2b, 0x4a, 0x94, 0x28, 0x50,
-	0x12, 0x29, 0xc9, 0x96, 0x65, 0xda, 0x71, 0x1c, 0xdb, 0xb1, 0x23, 0x91, 0xa2, 0xa4, 0x58, 0x1f,
-	0xcc, 0x92, 0x96, 0x6f, 0x41, 0x81, 0xc0, 0x70, 0x17, 0xe1, 0x2e, 0x00, 0x63, 0x00, 0x9a, 0xab,
-	0x43, 0xce, 0xa9, 0x5c, 0x92, 0xaa, 0x24, 0x15, 0x57, 0xe5, 0x10, 0x1f, 0x72, 0x48, 0x6e, 0xb9,
-	0xe4, 0xd5, 0x3b, 0xbc, 0x83, 0xef, 0xef, 0xaf, 0x78, 0xc7, 0xf7, 0x3f, 0xbc, 0xc3, 0xab, 0x99,
-	0xe9, 0x01, 0x06, 0x20, 0x96, 0x92, 0xec, 0xaa, 0x77, 0xc3, 0xf4, 0xfc, 0xb6, 0xa7, 0xbb, 0xa7,
-	0xa7, 0xa7, 0xbb, 0x67, 0xa1, 0x37, 0xa1, 0x8c, 0xd9, 0x43, 0xca, 0xee, 0x85, 0x51, 0x10, 0x07,
-	0x46, 0x23, 0x8e, 0xe8, 0xeb, 0x20, 0x5a, 0x6e, 0xc7, 0xd3, 0x50, 0x11, 0xcd, 0x0e, 0xc0, 0x53,
-	0xdf, 0x8b, 0x3d, 0x7b, 0xec, 0xbd, 0xa6, 0x66, 0x17, 0xda, 0x8f, 0x69, 0xbc, 0x43, 0xed, 0x38,
-	0x89, 0x28, 0x33, 0x7f, 0x9a, 0x83, 0xa6, 0x1a, 0x18, 0x17, 0xa0, 0x71, 0x4c, 0x7d, 0x37, 0x88,
-	0xfa, 0x95, 0xd5, 0xca, 0x46, 0x6b, 0x80, 0x23, 0x63, 0x0d, 0xba, 0x13, 0xfb, 0xef, 0x83, 0xc8,
-	0x3a, 0xa6, 0x11, 0xf3, 0x02, 0xbf, 0x5f, 0x5d, 0xad, 0x6c, 0x74, 0x07, 0x1d, 0x41, 0x7c, 0x25,
-	0x69, 0x02, 0xe4, 0xf9, 0x1a, 0xa8, 0x86, 0x20, 0x4e, 0xd4, 0x40, 0xa1, 0x1d, 0x3b, 0xa3, 0x14,
-	0x54, 0x97, 0x20, 0x41, 0x54, 0xa0, 0x75, 0x58, 0x38, 0x08, 0x82, 0x78, 0x1c, 0xd8, 0x2e, 0x8d,
-	0xac, 0x49, 0xe0, 0xd2, 0xfe, 0xdc, 0x6a, 0x65, 0xa3, 0x39, 0xe8, 0x65, 0xe4, 0xe7, 0x81, 0x4b,
-	0x8d, 0xcb, 0xd0, 0x72, 0xe9, 0xb1, 0xe7, 0x50, 0xcb, 0x73, 0xfb, 0x0d, 0x21, 0x72, 0x53, 0x12,
-	0x9e, 0xba, 0xc6, 0x4d, 0xe8, 0x85, 0x9e, 0x6f, 0x71, 0x1b, 0x50, 0x27, 0xe6, 0x6b, 0xcd, 0x0b,
-	0x26, 0xdd, 0xd0, 0xf3, 0x77, 0x53, 0xa2, 0xf1, 0x11, 0x9c, 0x0f, 0x6d, 0xc6, 0xc2, 0x51, 0x64,
-	0x33, 0xaa, 0xa3, 0x9b, 0x02, 0xbd, 0x94, 0x4d, 0x6a, 0x3f, 0x5a, 0x86, 0xe6, 0xd8, 0xf6, 0x87,
-	0x89, 0x3d, 0xa4, 0xfd, 0x96, 0x5c, 0x57, 0x8d, 0x8d, 0x25, 0x98, 0x1b, 0xdb, 0x07, 0x74, 0xdc,
-	0x07, 0x31, 0x21, 0x07, 0xc6, 0x2d, 0x98, 0x73, 0x02, 0xcf, 0x67, 0xfd, 0xf6, 0x6a, 0x6d, 0xa3,
-	0xbd, 0x49, 0xee, 0xc9, 0x9d, 0xba, 0xb7, 0x15, 0x78, 0xfe, 0xfe, 0x34, 0xa4, 0x03, 0x39, 0x6d,
-	0xac, 0x42, 0xdb, 0x4b, 0x37, 0xcb, 0xed, 0x77, 0x84, 0x10, 0x3a, 0x89, 0xaf, 0x1d, 0xd1, 0x63,
-	0x4f, 0x58, 0xaf, 0xbb, 0x5a, 0xd9, 0xe8, 0x0c, 0xd2, 0x71, 0xc1, 0x72, 0x23, 0x9b, 0x8d, 0xfa,
-	0x3d, 0x01, 0xd1, 0x2c, 0xf7, 0xc4, 0x66, 0x23, 0xce, 0xc4, 0x9b, 0x84, 0x41, 0x14, 0x53, 0xb7,
-	0xbf, 0x20, 0xd6, 0x48, 0xc7, 0xc6, 0x0a, 0x00, 0x37, 0x9c, 0x63, 0x3b, 0x23, 0xea, 0xf6, 0x89,
-	0x98, 0x6d, 0x85, 0x9e, 0xbf, 0x25, 0x08, 0xc6, 0x5d, 0x58, 0xd4, 0x0c, 0x86, 0xa8, 0x45, 0x81,
-	0x22, 0xd9, 0x04, 0x82, 0x6f, 0x03, 0x39, 0xf4, 0xa2, 0xc9, 0xf7, 0x76, 0xc4, 0x6d, 0x4b, 0x19,
-	0xf5, 0xe3, 0xbe, 0x21, 0xb0, 0x0b, 0x8a, 0xbe, 0x2b, 0xc9, 0xc6, 0x75, 0xe8, 0xf8, 0x94, 0xba,
-	0xcc, 0x3a, 0xb0, 0x9d, 0xa3, 0x24, 0xec, 0x9f, 0x93, 0xaa, 0x0b, 0xda, 0x43, 0x41, 0xe2, 0xa6,
-	0x3d, 0x1c, 0xdb, 0x43, 0xd6, 0x5f, 0x12, 0x5e, 0x23, 0x07, 0x66, 0x0f, 0x3a, 0x5b, 0x63, 0x6a,
-	0x47, 0x7b, 0x94, 0x71, 0x23, 0x98, 0xff, 0x58, 0x81, 0xee, 0x83, 0x30, 0x1c, 0x4f, 0xf7, 0x68,
-	0x1c, 0x7b, 0xfe, 0x90, 0xe5, 0xb6, 0xab, 0x32, 0x6b, 0xbb, 0xaa, 0xfa, 0x76, 0xdd, 0x84, 0x5e,
-	0xc2, 0xdd, 0x21, 0xd5, 0x47, 0x78, 0x73, 0x73, 0xd0, 0x4d, 0x18, 0xdd, 0x4d, 0x89, 0xc6, 0x55,
-	0x80, 0x51, 0x30, 0xa1, 0xcc, 0x89, 0x28, 0x95, 0xbe, 0xdc, 0x19, 0x68, 0x14, 0xd3, 0x04, 0x10,
-	0x92, 0xec, 0x70, 0x41, 0x33, 0xf1, 0x2b, 0xba, 0xf8, 0x6b, 0xd0, 0xda, 0x1a, 0xd9, 0xfe, 0x90,
-	0xee, 0x7a, 0x3e, 0x3f, 0x81, 0x11, 0x9d, 0x04, 0xc7, 0x52, 0xce, 0xe6, 0x00, 0x47, 0xe6, 0xff,
-	0x56, 0xa0, 0xbe, 0xeb, 0xf9, 0x43, 0xa3, 0x0f, 0xf3, 0x78, 0xe6, 0x51, 0x13, 0x35, 0xe4, 0xfb,
-	0x72, 0x90, 0xc4, 0x71, 0x90, 0x73, 0xf9, 0xaa, 0xdc, 0x17, 0x39, 0xa1, 0x39, 0xf0, 0xe9, 0xc3,
-	0x51, 0x7b, 0xa7, 0xc3, 0x51, 0x9f, 0x7d, 0x38, 0xcc, 0x35, 0x98, 0xdf, 0x4b, 0x1c, 0x87, 0x32,
-	0x36, 0x5b, 0x5a, 0xf3, 0x19, 0xcc, 0xef, 0xd8, 0xde, 0x38, 0x89, 0xa8, 0xb1, 0x0e, 0x75, 0x87,
-	0x9f, 0x71, 0x8e, 0xe8, 0x6d, 0x9e, 0x53, 0x27, 0x03, 0xa7, 0xc5, 0xe1, 0x10, 0x00, 0x9d, 0x5b,
-	0x35, 0xcf, 0x6d, 0x00, 0xdd, 0x87, 0x42, 0xc5, 0x01, 0xfd, 0x2e, 0xa1, 0x2c, 0x36, 0xde, 0xcf,
-	0xf1, 0xbc, 0xa4, 0x78, 0xe6, 0x40, 0x1a, 0x67, 0x03, 0xea, 0xae, 0x1d, 0xdb, 0xc8, 0x56, 0x7c,
-	0x9b, 0x6d, 0x68, 0x49, 0xf8, 0x03, 0xe7, 0xc8, 0xdc, 0x06, 0xb2, 0xeb, 0xf9, 0xcf, 0xed, 0x38,
-	0xf2, 0x4e, 0xd4, 0x1a, 0xf7, 0xa1, 0xce, 0xc3, 0x2c, 0xae, 0x71, 0x45, 0xad, 0x51, 0xc4, 0xc9,
-	0x65, 0x38, 0xd2, 0x5c, 0x85, 0x4e, 0x3a, 0xfb, 0xc0, 0x39, 0x32, 0x08, 0xd4, 0x42, 0xcf, 0x47,
-	0xd3, 0xf0, 0x4f, 0xb3, 0x09, 0x8d, 0x2d, 0xdb, 0x77, 0xe8, 0xd8, 0x3c, 0x07, 0x8b, 0x99, 0xa3,
-	0x21, 0x2b, 0xf3, 0x03, 0xe8, 0x66, 0x44, 0xce, 0xe1, 0x2a, 0x80, 0xe6, 0xa3, 0x92, 0x91, 0x46,
-	0x31, 0x57, 0x01, 0x1e, 0xd3, 0xf8, 0x91, 0x1f, 0x47, 0x41, 0x38, 0xe5, 0x6a, 0x32, 0xef, 0x35,
-	0x45, 0xff, 0x13, 0xdf, 0x7c, 0xb7, 0xd4, 0x74, 0x1f, 0xe6, 0xa9, 0xfc, 0x14, 0x88, 0xce, 0x40,
-	0x0d, 0xcd, 0x7f, 0xaf, 0x40, 0xe7, 0x31, 0x8d, 0x77, 0x93, 0x83, 0xb1, 0xe7, 0x7c, 0x4d, 0xa7,
-	0x3c, 0xf2, 0xda, 0xae, 0x1b, 0x51, 0xc6, 0x2c, 0x2e, 0x7f, 0x6d, 0xa3, 0x3b, 0x68, 0x22, 0xe1,
-	0x85, 0xb1, 0x01, 0x84, 0x3a, 0x2e, 0xb3, 0x2d, 0x27, 0x89, 0x8e, 0xa9, 0xe5, 0xdb, 0x13, 0xb5,
-	0x61, 0x3d, 0x41, 0xdf, 0xe2, 0xe4, 0x17, 0xf6, 0x84, 0xf2, 0x33, 0xcf, 0x46, 0xc1, 0xf7, 0x96,
-	0xeb, 0xb1, 0x70, 0x6c, 0x4f, 0xd1, 0x09, 0xdb, 0x9c, 0xb6, 0x2d, 0x49, 0x7c, 0x25, 0x1e, 0x19,
-	0x25, 0x97, 0xba, 0x3c, 0xbc, 0x9c, 0xc0, 0x7f, 0x6f, 0x3e, 0x86, 0x56, 0x26, 0xd3, 0x2d, 0xa8,
-	0xfb, 0x6a, 0xcf, 0xdb, 0x9b, 0x86, 0xda, 0x8f, 0x27, 0xdb, 0x2f, 0x02, 0x17, 0xdd, 0xc8, 0xc7,
-	0xcd, 0x3e, 0x09, 0x93, 0x03, 0xb5, 0xd9, 0xfc, 0xdb, 0xfc, 0x5d, 0x45, 0x18, 0xea, 0x81, 0x54,
-	0xe1, 0x6c, 0xf5, 0x72, 0x12, 0x55, 0xf3, 0x12, 0xbd, 0x8d, 0x46, 0x5f, 0x40, 0x73, 0x92, 0x8c,
-	0x63, 0x8f, 0x79, 0x43, 0xa1, 0x50, 0x7b, 0x73, 0x55, 0xc9, 0xfa, 0x1c, 0xe9, 0x03, 0xea, 0x52,
-	0x3a, 0xd9, 0x73, 0x22, 0x2f, 0x94, 0xfe, 0x93, 0xfe, 0xc2, 0xf8, 0x14, 0xda, 0x4c, 0xd0, 0x2d,
-	0xe1, 0x7c, 0x73, 0xc2, 0xf9, 0x2e, 0x2a, 0x06, 0x4f, 0xfd, 0x30, 0x89, 0xb5, 0xdf, 0x01, 0x4b,
-	0xbf, 0xcd, 0xbf, 0x85, 0x85, 0xfd, 0x11, 0x8d, 0xed, 0xb7, 0xd5, 0xb3, 0xa8, 0x4a, 0xf5, 0x94,
-	0x2a, 0xdc, 0x79, 0x14, 0xab, 0x3e, 0xcc, 0xe3, 0x2f, 0xd5, 0x51, 0xc7, 0xa1, 0xb9, 0x01, 0x1d,
-	0xb1, 0xee, 0x0c, 0x64, 0x27, 0x43, 0x76, 0x00, 0xbe, 0xf5, 0x42, 0xba, 0x2d, 0xae, 0x70, 0xf3,
-	0x9f, 0xab, 0x00, 0xcf, 0x02, 0xdb, 0x95, 0x43, 0x1e, 0xc4, 0x27, 0x3e, 0x9d, 0x04, 0xbe, 0xe7,
-	0xa8, 0x20, 0xae, 0xc6, 0xe9, 0xd6, 0x57, 0xdf, 0xb0, 0xf5, 0x78, 0xe0, 0x6a, 0xe9, 0x81, 0xfb,
-	0x59, 0x11, 0x2e, 0x77, 0x9f, 0xcc, 0xcd, 0xba, 0x4f, 0x1a, 0xfa, 0x7d, 0xb2, 0x06, 0x5d, 0x76,
-	0xe4, 0x85, 0x96, 0x33, 0xa2, 0xce, 0x11, 0x4b, 0x26, 0x98, 0x8b, 0x74, 0x38, 0x71, 0x0b, 0x69,
-	0xc6, 0x35, 0x68, 0x27, 0x9b, 0x87, 0x96, 0x13, 0x24, 0x7e, 0x4c, 0x23, 0x91, 0x80, 0x74, 0x07,
-	0x90, 0x6c, 0x1e, 0x6e, 0x49, 0x8a, 0xf9, 0x63, 0x15, 0xda, 0x03, 0xca, 0x68, 0x8c, 0x26, 0xb9,
-	0x09, 0x3d, 0xdc, 0x1c, 0x2b, 0xb2, 0x7d, 0x37, 0x98, 0xe0, 0xad, 0xd1, 0x45, 0xea, 0x40, 0x10,
-	0xb9, 0xb8, 0x2c, 0x8e, 0xa8, 0x3f, 0x8c, 0x47, 0x98, 0xb9, 0xa5, 0xe3, 0xd9, 0xfa, 0xd7, 0xce,
-	0xd0, 0xff, 0xf4, 0xed, 0x51, 0x2f, 0xbb, 0x3d, 0xde, 0xdd, 0x4c, 0x05, 0x0b, 0xcc, 0x17, 0x2d,
-	0xc0, 0x01, 0xc2, 0x8e, 0x98, 0x23, 0xc8, 0x1c, 0x0d, 0x38, 0x49, 0xa6, 0x08, 0x3c, 0x19, 0x90,
-	0x5f, 0xe8, 0x44, 0x04, 0x7a, 0x18, 0xde, 0x54, 0x0c, 0xbd, 0x05, 0x80, 0x14, 0x1e, 0x40, 0x67,
-	0xc7, 0xbc, 0x5f, 0x55, 0xa1, 0x37, 0xa0, 0x4e, 0x70, 0x4c, 0xa3, 0x29, 0xda, 0x7b, 0x05, 0xe0,
-	0xfb, 0x20, 0x72, 0xa5, 0x7c, 0x18, 0x45, 0x5b, 0x9c, 0x22, 0xc4, 0x9b, 0x6d, 0xcb, 0xea, 0x3b,
-	0xd9, 0xb2, 0xf6, 0x26, 0x5b, 0xd6, 0x67, 0xd9, 0x72, 0x4e, 0xb7, 0xe5, 0x6d, 0x20, 0xd4, 0x3f,
-	0x0c, 0x22, 0x87, 0x5a, 0x5c, 0xc4, 0xb1, 0xc7, 0x62, 0x61, 0xec, 0xe6, 0x60, 0x01, 0xe9, 0xdf,
-	0x22, 0x99, 0x47, 0x44, 0x11, 0x4c, 0xa4, 0xc7, 0x89, 0xef, 0xe2, 0x56, 0xb4, 0x4e, 0x6d, 0xc5,
-	0x45, 0x98, 0x77, 0xa3, 0xa9, 0x15, 0x25, 0xbe, 0xc8, 0x74, 0x9b, 0x83, 0x86, 0x1b, 0x4d, 0x07,
-	0x89, 0x6f, 0x7e, 0x06, 0x6d, 0xce, 0x59, 0x5d, 0x93, 0x77, 0x73, 0xd7, 0x64, 0x1a, 0xa9, 0x34,
-	0x88, 0x76, 0x43, 0xae, 0xc0, 0x3c, 0x9f, 0xe0, 0x3b, 0x63, 0x40, 0x9d, 0xcb, 0x8d, 0x67, 0x5d,
-	0x7c, 0x9b, 0xff, 0x59, 0x81, 0xf6, 0x9e, 0x37, 0xf4, 0x9f, 0x63, 0xce, 0x73, 0x66, 0xfc, 0x2a,
-	0xa4, 0x0b, 0x9d, 0x2c, 0x55, 0xca, 0x45, 0xf0, 0x5a, 0x21, 0x82, 0x17, 0x02, 0x6c, 0xfd, 0xed,
-	0x03, 0xec, 0x3f, 0x40, 0xf7, 0x15, 0x8d, 0xbc, 0xc3, 0xa9, 0x12, 0x6f, 0x66, 0x4c, 0x34, 0xae,
-	0x40, 0x8b, 0x79, 0x43, 0x5f, 0xd4, 0x5d, 0x28, 0x5d, 0x46, 0xd0, 0x25, 0xaf, 0x9d, 0x21, 0x79,
-	0xf1, 0x36, 0xfc, 0x1b, 0x20, 0xb8, 0xf2, 0x9e, 0xce, 0xea, 0xe7, 0x88, 0x60, 0xfe, 0x57, 0x85,
-	0x1f, 0x1c, 0x27, 0x9a, 0x86, 0xb1, 0xd2, 0xe6, 0x02, 0x34, 0xc2, 0xe4, 0xe0, 0x88, 0xaa, 0x93,
-	0x82, 0xa3, 0x33, 0xec, 0x7c, 0x1d, 0x3a, 0x2a, 0x3e, 0x05, 0xfe, 0x38, 0xbd, 0x0c, 0x91, 0xf6,
-	0xd2, 0x1f, 0x17, 0x12, 0x89, 0xfa, 0x59, 0x37, 0xed, 0x5c, 0x41, 0xdb, 0x57, 0x40, 0x50, 0x40,
-	0xea, 0x2a, 0x11, 0x97, 0x60, 0xce, 0x0f, 0x7c, 0x87, 0xa2, 0x84, 0x72, 0x70, 0x86, 0x80, 0x06,
-	0xd4, 0x47, 0x13, 0xdb, 0x41, 0x2b, 0x8b, 0x6f, 0xf3, 0x3b, 0xe8, 0x6d, 0xd3, 0x9c, 0xe2, 0x67,
-	0x7a, 0x59, 0xba, 0x64, 0x75, 0xc6, 0x92, 0xb5, 0xf2, 0x25, 0xeb, 0xda, 0x92, 0x3b, 0x40, 0x70,
-	0xc9, 0x4c, 0x95, 0x42, 0xea, 0xac, 0x71, 0xd0, 0xb6, 0xb4, 0x9a, 0xbf, 0x69, 0x7f, 0x5b, 0x81,
-	0xde, 0x96, 0x17, 0x8e, 0x68, 0xf4, 0x35, 0x9d, 0xbe, 0xb2, 0xc7, 0xc9, 0x1b, 0x64, 0x27, 0x50,
-	0xe3, 0xdb, 0x29, 0xb9, 0xf0, 0x4f, 0xae, 0xcd, 0x31, 0xff, 0x1d, 0x4a, 0x2d, 0x07, 0x32, 0x48,
-	0x0a, 0xf9, 0x30, 0xd0, 0xab, 0xa1, 0x71, 0x03, 0x7a, 0x36, 0x3b, 0xb2, 0x02, 0xdf, 0x52, 0x00,
-	0x59, 0xa9, 0x77, 0x6c, 0x76, 0xf4, 0xd2, 0x7f, 0x74, 0x0a, 0xe5, 0x4a, 0x35, 0x31, 0x10, 0x49,
-	0x14, 0xaa, 0x6e, 0xf4, 0xa0, 0xea, 0x1d, 0x8b, 0x98, 0xdf, 0x19, 0x54, 0xbd, 0x63, 0x73, 0x03,
-	0x88, 0x54, 0x86, 0xba, 0xa9, 0x3a, 0xa9, 0x7c, 0x15, 0x4d, 0x3e, 0x33, 0x81, 0xde, 0x23, 0x16,
-	0x7b, 0x13, 0x3b, 0xa6, 0xfb, 0x27, 0x7b, 0xde, 0x6b, 0xca, 0xef, 0xdb, 0x20, 0x89, 0xc3, 0x24,
-	0x66, 0xb9, 0x60, 0xdd, 0x41, 0xa2, 0x8c, 0xd7, 0xd7, 0xa1, 0xe3, 0xf9, 0x1a, 0x46, 0xde, 0x8d,
-	0x6d, 0x49, 0x93, 0x90, 0xb3, 0x22, 0x85, 0x79, 0x1d, 0x1a, 0xb8, 0xdc, 0x45, 0x98, 0x8f, 0x4f,
-	0x2c, 0x2d, 0xb7, 0x6e, 0xc4, 0x62, 0xc2, 0xfc, 0xef, 0x0a, 0x34, 0xf8, 0x61, 0xdc, 0x3f, 0xf9,
-	0x93, 0x88, 0xc4, 0x77, 0x2a, 0xdf, 0x59, 0x51, 0x43, 0xfe, 0xb3, 0x71, 0xe0, 0x1c, 0x59, 0xb1,
-	0x87, 0x67, 0xa9, 0x3b, 0x68, 0x72, 0xc2, 0xbe, 0x37, 0xa1, 0xe6, 0x1f, 0x2a, 0xd0, 0xd9, 0xf3,
-	0x26, 0xe1, 0x98, 0xa2, 0xb0, 0x77, 0xa1, 0x21, 0xd7, 0x14, 0x3e, 0xd3, 0xce, 0xaa, 0xb2, 0xfd,
-	0x13, 0x11, 0x01, 0x45, 0xec, 0x43, 0x88, 0x71, 0x0f, 0xe6, 0x51, 0x89, 0x7e, 0x55, 0xa0, 0x97,
-	0x32, 0xf4, 0x4b, 0x31, 0x21, 0xe0, 0x0a, 0x64, 0x7c, 0x0e, 0x9d, 0x38, 0xb2, 0x7d, 0x66, 0x8b,
-	0xab, 0x8d, 0xf5, 0x6b, 0xe2, 0x47, 0x69, 0x88, 0xdd, 0xcf, 0xe6, 0xc4, 0xef, 0x72, 0xe0, 0x33,
-	0x23, 0xa0, 0xae, 0xfe, 0xdc, 0x19, 0xea, 0x37, 0x0a, 0xea, 0xff, 0xa6, 0x02, 0xad, 0xfd, 0xb4,
-	0xae, 0xfb, 0x04, 0x3a, 0x91, 0xfc, 0xb4, 0xb4, 0x8b, 0x2b, 0xb5, 0x80, 0x7e, 0x69, 0xb5, 0xa3,
-	0x6c, 0x60, 0x7c, 0x02, 0xf3, 0x2e, 0x8d, 0x6d, 0x6f, 0xcc, 0x30, 0x0f, 0xbd, 0x92, 0x99, 0x01,
-	0x7f, 0xb4, 0x2d, 0xe7, 0xa5, 0x39, 0x10, 0x6c, 0x7c, 0x05, 0xc0, 0x68, 0xa4, 0x3a, 0x3e, 0x35,
-	0xf1, 0xd3, 0x6b, 0xa7, 0x7e, 0xba, 0x97, 0x42, 0xf0, 0xde, 0x49, 0xc7, 0xe6, 0x7d, 0x98, 0xdb,
-	0x17, 0xf5, 0xe4, 0x3a, 0x54, 0xe3, 0x13, 0xac, 0x7f, 0x66, 0x9a, 0xb3, 0x1a, 0x9f, 0x98, 0xff,
-	0x54, 0x85, 0xb6, 0xc8, 0xc9, 0x71, 0xbb, 0x7f, 0x46, 0x84, 0xbb, 0x0c, 0xad, 0xa1, 0xcd, 0xac,
-	0x30, 0xf2, 0x1c, 0x15, 0x2d, 0x9a, 0x43, 0x9b, 0xed, 0xf2, 0xb1, 0x9a, 0x1c, 0x7b, 0x13, 0x2f,
-	0xc6, 0x48, 0xc7, 0x27, 0x9f, 0xf1, 0x31, 0x3f, 0xe7, 0x71, 0x20, 0xf6, 0xa7, 0x33, 0xa8, 0xc6,
-	0x41, 0x76, 0xa6, 0x1b, 0x7a, 0xcc, 0x79, 0x0f, 0x0c, 0x5e, 0x86, 0x5b, 0xd8, 0xfa, 0xb2, 0x9c,
-	0x51, 0xe2, 0x1f, 0x61, 0x74, 0x20, 0x7c, 0x06, 0x7b, 0x9a, 0x5b, 0x9c, 0xce, 0xb3, 0x15, 0x81,
-	0x1e, 0xcb, 0x2c, 0x17, 0x53, 0x67, 0x4e, 0x7a, 0x26, 0xf3, 0xdc, 0x4b, 0xd0, 0x74, 0x46, 0xb6,
-	0xe7, 0x5b, 0x9e, 0x8b, 0xb9, 0xcc, 0xbc, 0x18, 0x3f, 0x75, 0xcd, 0x7f, 0xab, 0x40, 0x4f, 0x18,
-	0x23, 0x73, 0x81, 0x02, 0xbb, 0xca, 0x29, 0x76, 0x3c, 0x0f, 0x55, 0x77, 0xa5, 0x75, 0x8c, 0xc7,
-	0x14, 0x52, 0xd2, 0xab, 0x3c, 0x20, 0x42, 0x03, 0x65, 0x80, 0x41, 0x1e, 0xc0, 0x54, 0xef, 0x28,
-	0x25, 0xed, 0x99, 0x77, 0x01, 0x50, 0x2a, 0xbe, 0xb5, 0x2b, 0x20, 0x96, 0x47, 0x33, 0xc8, 0xe8,
-	0xd7, 0xe2, 0x14, 0xa1, 0xbf, 0xf9, 0x14, 0x48, 0xba, 0x9f, 0xbf, 0x2c, 0x39, 0x32, 0x0f, 0xc1,
-	0x10, 0xac, 0xce, 0x4c, 0x65, 0x3a, 0xbf, 0x38, 0x95, 0x31, 0x5f, 0xc2, 0x79, 0xb1, 0xce, 0x9b,
-	0x52, 0x96, 0xb7, 0x5d, 0xca, 0xfc, 0x49, 0x04, 0xb1, 0xa1, 0xff, 0xd4, 0xa5, 0x7e, 0xec, 0xc5,
-	0x53, 0xe3, 0x3e, 0x34, 0x3d, 0xfc, 0xc6, 0x43, 0x91, 0x06, 0x26, 0x85, 0x91, 0xc5, 0xb5, 0x42,
-	0xf1, 0x9c, 0xd9, 0x19, 0xd9, 0x63, 0xbe, 0xed, 0xd4, 0x1a, 0x79, 0xae, 0x4b, 0x7d, 0x5c, 0x67,
-	0x21, 0xa5, 0x3f, 0x11, 0xe4, 0x3c, 0xf4, 0xd8, 0x63, 0x89, 0x3d, 0xc6, 0x68, 0x9c, 0x41, 0x5f,
-	0x09, 0x72, 0x69, 0x3f, 0xa4, 0x5e, 0xd6, 0x0f, 0x31, 0x87, 0xd0, 0xe3, 0x1a, 0x50, 0x37, 0xd5,
-	0x61, 0x76, 0xfe, 0xb6, 0x02, 0x10, 0x8a, 0xde, 0x87, 0xa5, 0xee, 0xf0, 0xce, 0xa0, 0x15, 0xa6,
-	0xdd, 0x90, 0x9c, 0xad, 0x6a, 0x45, 0x5b, 0xfd, 0x47, 0x05, 0x16, 0x1f, 0xd3, 0xf8, 0xd1, 0xd6,
-	0xf6, 0x13, 0x6c, 0x9b, 0xf2, 0xdf, 0xbc, 0xbb, 0xc1, 0x6e, 0xc1, 0x42, 0x48, 0x69, 0x64, 0x9d,
-	0x92, 0xa4, 0xcb, 0xc9, 0x59, 0x6f, 0xa6, 0xcc, 0x04, 0xb5, 0x52, 0x13, 0x7c, 0x08, 0xbd, 0x82,
-	0x54, 0xfc, 0xa4, 0xc8, 0x91, 0x95, 0x25, 0x9f, 0xc0, 0x52, 0x80, 0x79, 0x1f, 0xba, 0x7b, 0x34,
-	0xfe, 0x66, 0x73, 0x47, 0xab, 0x12, 0xf5, 0xda, 0xa5, 0x72, 0xaa, 0x90, 0x5e, 0x87, 0xee, 0x0e,
-	0xb6, 0x9f, 0x1f, 0x89, 0x46, 0xee, 0x05, 0x68, 0xe4, 0xce, 0x3a, 0x8e, 0xcc, 0x07, 0xb0, 0xa0,
-	0x80, 0x2a, 0x36, 0x5c, 0x80, 0x46, 0x70, 0x78, 0xc8, 0xa8, 0xba, 0xc0, 0x71, 0xa4, 0xb1, 0xa8,
-	0xe6, 0x58, 0x7c, 0x09, 0x3d, 0xc5, 0xe2, 0x9b, 0x70, 0x1c, 0xd8, 0x2e, 0xdf, 0xd3, 0xd0, 0x9e,
-	0xf2, 0x4f, 0xe5, 0xe0, 0x38, 0x14, 0xc9, 0xa1, 0xcd, 0x46, 0x68, 0x43, 0xf1, 0x6d, 0xde, 0x80,
-	0xe6, 0x1e, 0x1d, 0x1f, 0xee, 0xf3, 0xb5, 0x67, 0xfe, 0xd2, 0xbc, 0x03, 0x8b, 0xdb, 0xf4, 0x20,
-	0x19, 0x3e, 0xf3, 0xfc, 0xa3, 0x6d, 0xea, 0xc8, 0xe7, 0x80, 0xf3, 0xd0, 0x98, 0x52, 0x66, 0xf9,
-	0x01, 0xf6, 0x05, 0xe6, 0xa6, 0x94, 0xbd, 0x08, 0xcc, 0x73, 0x1a, 0xf6, 0x31, 0x8d, 0xf7, 0x62,
-	0x3b, 0xa6, 0xe6, 0xef, 0xab, 0x3c, 0xef, 0x45, 0xaa, 0x20, 0x09, 0x8d, 0xec, 0x69, 0x90, 0xc4,
-	0x2a, 0xe1, 0x97, 0x23, 0xd5, 0x45, 0xa9, 0x66, 0x5d, 0x94, 0x0b, 0xd0, 0x98, 0x88, 0xae, 0x26,
-	0x6e, 0x2a, 0x8e, 0x72, 0x3d, 0x9b, 0xfa, 0x8c, 0x9e, 0xcd, 0xdc, 0x1b, 0x7a, 0x36, 0x33, 0xab,
-	0xea, 0xc6, 0x19, 0x55, 0xf5, 0x0a, 0x40, 0x44, 0x19, 0x8d, 0x45, 0xe9, 0x2b, 0x6e, 0x8d, 0xd6,
-	0xa0, 0x25, 0x28, 0xbc, 0xbc, 0xe4, 0xb9, 0x98, 0x9c, 0x56, 0xb5, 0x7f, 0x53, 0x28, 0xd8, 0x11,
-	0x44, 0xd5, 0x0e, 0x7d, 0x0f, 0x8c, 0x08, 0xeb, 0x7f, 0xeb, 0xd0, 0x3e, 0x92, 0x65, 0x34, 0x3e,
-	0xf7, 0x10, 0x35, 0xb3, 0x63, 0x1f, 0x89, 0x3a, 0xda, 0xb8, 0x03, 0x8b, 0x29, 0x5a, 0x34, 0x09,
-	0xc2, 0x80, 0x89, 0xc2, 0xb8, 0x3b, 0x58, 0x50, 0x13, 0x1c, 0xb8, 0x1b, 0x30, 0x73, 0x01, 0xba,
-	0x9a, 0xa9, 0x83, 0xd0, 0xdc, 0x85, 0x4e, 0x4a, 0x78, 0x16, 0x0c, 0x45, 0x45, 0x4f, 0x8f, 0xe9,
-	0x58, 0xbd, 0x14, 0x88, 0x01, 0xb7, 0xf2, 0x41, 0xe2, 0x1c, 0xd1, 0x18, 0x4d, 0x8f, 0x23, 0x51,
-	0xbe, 0xd3, 0x93, 0x18, 0x6d, 0x2f, 0xbe, 0xcd, 0xc7, 0x70, 0x2e, 0xe5, 0xf8, 0x9c, 0x4e, 0x82,
-	0x68, 0x3a, 0xa0, 0xd2, 0xf5, 0xf4, 0x70, 0xd2, 0xcd, 0xc2, 0xc9, 0x2c, 0xf7, 0xbd, 0x0d, 0x0b,
-	0x05, 0x46, 0x62, 0xb7, 0xc5, 0x97, 0xf2, 0x0b, 0x39, 0x32, 0xff, 0x0e, 0x96, 0x0a, 0xd0, 0x6f,
-	0x23, 0x2f, 0xa6, 0x67, 0x2f, 0x8a, 0x9c, 0xaa, 0x3a, 0x27, 0x7c, 0x29, 0x61, 0x23, 0xac, 0x18,
-	0xe5, 0xc0, 0x7c, 0x5f, 0xd3, 0x69, 0x87, 0x53, 0xd2, 0xb3, 0xcb, 0xa8, 0x13, 0x07, 0xea, 0xa0,
-	0xe3, 0xe8, 0xce, 0xbf, 0x9e, 0x87, 0x36, 0x5e, 0x2e, 0x22, 0x3f, 0x5b, 0x85, 0x0b, 0xda, 0xd0,
-	0xca, 0xde, 0x44, 0xc9, 0x9f, 0x2d, 0xd7, 0xff, 0xe5, 0xff, 0xfb, 0x15, 0x63, 0x39, 0x2d, 0xa0,
-	0x05, 0x62, 0xd7, 0xf3, 0x87, 0xa4, 0x82, 0x73, 0x2b, 0x70, 0x4e, 0x9f, 0xc3, 0x17, 0x0e, 0x52,
-	0x5d, 0xae, 0xff, 0x50, 0x32, 0x8d, 0x8f, 0x17, 0xa4, 0x86, 0xd3, 0xd7, 0xe0, 0xbc, 0x3e, 0x9d,
-	0x3e, 0xf8, 0x90, 0x3a, 0xb2, 0x2f, 0x08, 0x97, 0xb5, 0x41, 0xc9, 0x1c, 0x22, 0xd6, 0xe1, 0x52,
-	0x6e, 0x05, 0x3d, 0x7e, 0x91, 0xc6, 0x72, 0x93, 0x83, 0x7e, 0xcd, 0x81, 0x1b, 0xb0, 0x5c, 0x06,
-	0x94, 0xc1, 0x87, 0xcc, 0x6b, 0xc8, 0xdb, 0x70, 0xb9, 0x0c, 0x89, 0x91, 0x8e, 0x34, 0x97, 0x9b,
-	0x3f, 0x28, 0x68, 0x41, 0xbe, 0xec, 0x51, 0x81, 0xb4, 0xca, 0x0d, 0xa4, 0xa6, 0x01, 0x2d, 0x60,
-	0x42, 0xbf, 0xc0, 0x20, 0xbd, 0x1d, 0x48, 0x1b, 0x59, 0x14, 0xac, 0x94, 0x01, 0x3a, 0xc8, 0xa4,
-	0x20, 0x45, 0xd6, 0x1d, 0x26, 0x5d, 0x64, 0x71, 0x1d, 0x2e, 0xea, 0x08, 0xad, 0x5b, 0x4a, 0x7a,
-	0x08, 0xb9, 0x02, 0x46, 0x6e, 0x27, 0x45, 0x0a, 0x4c, 0x16, 0x70, 0xf6, 0x46, 0x5e, 0x4e, 0xbd,
-	0x2a, 0x22, 0x64, 0xb9, 0xc1, 0x31, 0xcd, 0x8a, 0x71, 0x15, 0x96, 0x72, 0x96, 0xc3, 0x17, 0x74,
-	0xb2, 0x88, 0x82, 0xde, 0x82, 0x2b, 0x05, 0x4f, 0xca, 0xbd, 0x09, 0x11, 0x23, 0xc5, 0xf5, 0x4b,
-	0x71, 0x0f, 0x9c, 0x23, 0x72, 0x4e, 0xee, 0xd4, 0xff, 0x95, 0xc8, 0x2c, 0xdf, 0x88, 0xc8, 0x52,
-	0xb9, 0xdd, 0xd2, 0x3c, 0x96, 0x9c, 0xc7, 0x65, 0x2e, 0xc3, 0x62, 0x1e, 0xc0, 0xf9, 0x5f, 0x48,
-	0x35, 0xce, 0xf9, 0x4b, 0xbe, 0x81, 0x40, 0x2e, 0x22, 0xaa, 0xb0, 0x7f, 0xfa, 0x8b, 0x2b, 0xe9,
-	0x23, 0x66, 0x2d, 0xef, 0xa2, 0xb9, 0x47, 0x58, 0x72, 0xa9, 0x1c, 0x94, 0x7b, 0x92, 0x23, 0xcb,
-	0x28, 0xf0, 0x5a, 0x5e, 0xa3, 0xf4, 0x21, 0x8e, 0x5c, 0xd6, 0x8c, 0x52, 0xf0, 0x86, 0xec, 0xa5,
-	0x95, 0x5c, 0x29, 0x3f, 0x55, 0xd9, 0xcb, 0x07, 0x59, 0x29, 0xf7, 0x5a, 0x35, 0x7d, 0x35, 0xf5,
-	0xda, 0xdc, 0x3e, 0xab, 0x8b, 0x98, 0xac, 0x6a, 0xa7, 0xa8, 0x60, 0x19, 0xbd, 0xfd, 0x4c, 0xcc,
-	0x72, 0x1b, 0xe7, 0x5b, 0xd2, 0x64, 0xad, 0xdc, 0xbd, 0xb3, 0x36, 0x35, 0xb9, 0x51, 0xee, 0xde,
-	0x5a, 0xba, 0x4f, 0x6e, 0x95, 0xdb, 0x37, 0x97, 0xc6, 0x93, 0x75, 0x04, 0x15, 0xfc, 0xb3, 0x98,
-	0x83, 0x93, 0x0d, 0x94, 0x68, 0x1d, 0x56, 0x72, 0xfe, 0x59, 0x7c, 0x91, 0x24, 0xb7, 0x53, 0xe0,
-	0xa5, 0x72, 0x20, 0x97, 0xfe, 0x8e, 0xb6, 0x69, 0xb7, 0x0a, 0x96, 0xc8, 0xf5, 0x6d, 0xc8, 0x5d,
-	0xed, 0x84, 0x19, 0x79, 0x97, 0x15, 0xf3, 0xef, 0x2d, 0x37, 0x7e, 0x90, 0xf3, 0x05, 0x8b, 0xe6,
-	0x3b, 0xf5, 0xe4, 0xfd, 0x72, 0x7b, 0x69, 0x4d, 0x67, 0x72, 0xaf, 0x3c, 0x72, 0x63, 0xfb, 0x99,
-	0x7c, 0x50, 0x6e, 0xa9, 0x62, 0x47, 0x8a, 0xdc, 0x4f, 0x4f, 0x72, 0x61, 0x87, 0xf5, 0x16, 0x22,
-	0xf9, 0x30, 0xd5, 0x6b, 0x23, 0xcf, 0xaf, 0xd8, 0xc2, 0x24, 0x9b, 0xa9, 0x86, 0x05, 0x8e, 0xf9,
-	0xa6, 0x24, 0xf9, 0x68, 0x16, 0xc7, 0x62, 0x27, 0x91, 0x7c, 0x9c, 0x72, 0x34, 0x8b, 0xb1, 0x2d,
-	0x2b, 0x96, 0xc8, 0x9f, 0x97, 0x7b, 0x6a, 0xbe, 0x1c, 0x21, 0x9f, 0xa0, 0xb6, 0x05, 0xbb, 0x6a,
-	0xff, 0x28, 0x22, 0x7f, 0x81, 0x8c, 0x6e, 0xe6, 0x2f, 0x97, 0xc2, 0xd3, 0x23, 0xf9, 0xb4, 0x3c,
-	0xae, 0xe8, 0x2f, 0x85, 0xe4, 0x2f, 0xcb, 0x57, 0xd3, 0x3a, 0x17, 0xe4, 0xb3, 0x72, 0xb1, 0xf3,
-	0xf5, 0x3c, 0xf9, 0xbc, 0xfc, 0x80, 0x65, 0xf5, 0x35, 0xf9, 0x22, 0xbd, 0x65, 0x57, 0x8a, 0xf7,
-	0x5c, 0xae, 0x1a, 0x21, 0x7f, 0x35, 0xe3, 0x44, 0xe7, 0x51, 0x5f, 0xa6, 0x71, 0xec, 0x52, 0x3e,
-	0x7e, 0x68, 0x65, 0x0a, 0xf9, 0xaa, 0xdc, 0xc5, 0x8a, 0x85, 0x3c, 0xf9, 0x6b, 0xc4, 0x6d, 0xc0,
-	0xd5, 0x53, 0xb8, 0xfc, 0xf1, 0x7e, 0x80, 0xc8, 0xbb, 0x70, 0xfd, 0x14, 0xf2, 0xd4, 0x19, 0x7f,
-	0x88, 0x32, 0xde, 0xcd, 0xab, 0x7c, 0xaa, 0x8c, 0x20, 0xee, 0x72, 0xf3, 0x47, 0x75, 0x7c, 0xd7,
-	0x67, 0x80, 0x55, 0x1d, 0x41, 0xe8, 0x72, 0xfd, 0xc7, 0x12, 0xfb, 0xe4, 0x4b, 0x0b, 0x72, 0xb8,
-	0x5c, 0xff, 0x9f, 0x12, 0xfb, 0xe4, 0xb2, 0x62, 0x32, 0x44, 0x56, 0x05, 0x17, 0xd1, 0x33, 0x65,
-	0x32, 0x42, 0x46, 0xb7, 0xe1, 0x5a, 0x29, 0x26, 0xcb, 0x7d, 0x89, 0x8f, 0xec, 0x0a, 0x8e, 0x59,
-	0x80, 0x92, 0x00, 0x39, 0xde, 0x81, 0xd5, 0x33, 0x60, 0x22, 0xb3, 0x25, 0x21, 0xb2, 0x9c, 0xb5,
-	0x7a, 0x96, 0xa5, 0x92, 0xef, 0x24, 0xf4, 0xe1, 0xc7, 0xb0, 0xe6, 0x04, 0x93, 0x7b, 0xcc, 0x8e,
-	0x03, 0x36, 0xf2, 0xc6, 0xf6, 0x01, 0x53, 0x95, 0xcf, 0xd8, 0x3b, 0x90, 0xff, 0xd8, 0x3b, 0x48,
-	0x0e, 0x1f, 0x76, 0xf7, 0x05, 0x11, 0xb9, 0x1e, 0x34, 0xc4, 0xc4, 0x47, 0x7f, 0x0c, 0x00, 0x00,
-	0xff, 0xff, 0xae, 0x03, 0x03, 0xb2, 0xf1, 0x27, 0x00, 0x00,
+	// 3322 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbd, 0x5a, 0x4b, 0x73, 0x1b, 0xc7,
+	0x11, 0x0e, 0x1e, 0x04, 0x81, 0xc6, 0x83, 0xc3, 0x15, 0x65, 0x41, 0xd4, 0x8b, 0x5a, 0xc9, 0x22,
+	0x25, 0xd9, 0xb2, 0x2c, 0x3b, 0x8e, 0x63, 0x3b, 0x76, 0x24, 0xd2, 0xb4, 0x94, 0xe8, 0xc1, 0x80,
+	0xb4, 0x7c, 0x0b, 0x6a, 0x09, 0x0c, 0x89, 0x0d, 0x81, 0x5d, 0x78, 0x77, 0x41, 0x93, 0x3e, 0xe4,
+	0x9c, 0xca, 0x25, 0xa9, 0x4a, 0x52, 0x71, 0x55, 0x0e, 0xd1, 0x21, 0x87, 0xe4, 0x96, 0x4b, 0x52,
+	0x39, 0xe4, 0xe0, 0x7b, 0x7e, 0x45, 0x8e, 0xf9, 0x0f, 0x39, 0xa4, 0x67, 0xa6, 0x67, 0x77, 0x76,
+	0xb9, 0x80, 0x24, 0xbb, 0x2a, 0xb7, 0x9d, 0x9e, 0x0f, 0x3d, 0x3d, 0xfd, 0x9a, 0x9e, 0x1e, 0x40,
+	0x6b, 0xc4, 0xc3, 0xd0, 0xd9, 0xe7, 0xe1, 0xad, 0x71, 0xe0, 0x47, 0xbe, 0x55, 0x89, 0x02, 0xfe,
+	0xa5, 0x1f, 0x2c, 0xd7, 0xa3, 0xe3, 0xb1, 0x26, 0xda, 0x0d, 0x80, 0x07, 0x9e, 0x1b, 0xb9, 0xce,
+	0xd0, 0xfd, 0x92, 0xdb, 0x4d, 0xa8, 0x7f, 0xc2, 0xa3, 0x4d, 0xee, 0x44, 0x93, 0x80, 0x87, 0xf6,
+	0xd7, 0x73, 0x50, 0xd5, 0x03, 0xeb, 0x15, 0xa8, 0x1c, 0x72, 0xaf, 0xef, 0x07, 0xed, 0xc2, 0x4a,
+	0x61, 0xad, 0xd6, 0xa1, 0x91, 0x75, 0x05, 0x9a, 0x23, 0xe7, 0x67, 0x7e, 0xd0, 0x3d, 0xe4, 0x41,
+	0xe8, 0xfa, 0x5e, 0xbb, 0x88, 0xd3, 0xcd, 0x4e, 0x43, 0x12, 0x9f, 0x2a, 0x9a, 0x04, 0xb9, 0x9e,
+	0x01, 0x2a, 0x11, 0x48, 0x10, 0x0d, 0xd0, 0xd8, 0x89, 0x7a, 0x83, 0x18, 0x54, 0x56, 0x20, 0x49,
+	0xd4, 0xa0, 0x55, 0x58, 0xd8, 0xf5, 0xfd, 0x68, 0xe8, 0x3b, 0x7d, 0x1e, 0x74, 0x47, 0x7e, 0x9f,
+	0xb7, 0xe7, 0x10, 0x56, 0xed, 0xb4, 0x12, 0xf2, 0x23, 0xa4, 0x5a, 0xe7, 0xa0, 0xd6, 0xe7, 0x87,
+	0x6e, 0x8f, 0x77, 0xdd, 0x7e, 0xbb, 0x22, 0x45, 0xae, 0x2a, 0xc2, 0x83, 0xbe, 0xf5, 0x2a, 0xb4,
+	0xc6, 0xae, 0xd7, 0x15, 0x3a, 0xe0, 0xbd, 0x48, 0xac, 0x35, 0x2f, 0x99, 0x34, 0x91, 0xba, 0x15,
+	0x13, 0xad, 0xb7, 0xe0, 0xf4, 0xd8, 0x09, 0xc3, 0xf1, 0x20, 0x70, 0x42, 0x6e, 0xa2, 0xab, 0x12,
+	0xbd, 0x94, 0x4c, 0x1a, 0x3f, 0x5a, 0x86, 0xea, 0xd0, 0xf1, 0xf6, 0x27, 0xa8, 0xfa, 0x76, 0x4d,
+	0xad, 0xab, 0xc7, 0xd6, 0x12, 0xcc, 0x0d, 0x9d, 0x5d, 0x3e, 0x6c, 0x83, 0x9c, 0x50, 0x03, 0xeb,
+	0x1a, 0xcc, 0xf5, 0x7c, 0xd7, 0x0b, 0xdb, 0xf5, 0x95, 0xd2, 0x5a, 0xfd, 0x0e, 0xbb, 0xa5, 0x2c,
+	0x75, 0x6b, 0x1d, 0x89, 0x3b, 0x68, 0xac, 0x8e, 0x9a, 0xb6, 0x56, 0xa0, 0xee, 0xc6, 0xc6, 0xea,
+	0xb7, 0x1b, 0x52, 0x08, 0x93, 0x24, 0xd6, 0x0e, 0x70, 0x8f, 0x52, 0x7b, 0x4d, 0x9c, 0x6e, 0x74,
+	0xe2, 0x71, 0x46, 0x73, 0x03, 0x27, 0x1c, 0xb4, 0x5b, 0x12, 0x62, 0x68, 0xee, 0x3e, 0x52, 0x05,
+	0x13, 0x77, 0x34, 0xf6, 0x83, 0x08, 0xd7, 0x58, 0x90, 0x6b, 0xc4, 0x63, 0xeb, 0x02, 0x80, 0x50,
+	0x5c, 0xcf, 0xe9, 0x0d, 0x70, 0x96, 0xc9, 0xd9, 0x1a, 0x52, 0xd6, 0x25, 0xc1, 0xba, 0x09, 0x8b,
+	0x86, 0xc2, 0x08, 0xb5, 0x28, 0x51, 0x2c, 0x99, 0x20, 0xf0, 0x75, 0x60, 0x7b, 0x6e, 0x30, 0xfa,
+	0xc2, 0x09, 0x84, 0x6e, 0x79, 0xc8, 0xbd, 0xa8, 0x6d, 0x49, 0xec, 0x82, 0xa6, 0x6f, 0x29, 0xb2,
+	0x75, 0x19, 0x1a, 0x1e, 0xe7, 0xfd, 0xb0, 0xbb, 0xeb, 0xf4, 0x0e, 0x26, 0xe3, 0xf6, 0x29, 0xb5,
+	0x75, 0x49, 0xbb, 0x27, 0x49, 0x42, 0xb5, 0x7b, 0x43, 0x67, 0x3f, 0x6c, 0x2f, 0x49, 0xaf, 0x51,
+	0x03, 0xbb, 0x05, 0x8d, 0xf5, 0x21, 0x77, 0x82, 0x6d, 0x8c, 0x05, 0x54, 0x82, 0xfd, 0x8b, 0x02,
+	0x34, 0xef, 0x8e, 0xc7, 0xc3, 0xe3, 0x6d, 0x1e, 0x45, 0xae, 0xb7, 0x1f, 0xa6, 0xcc, 0x55, 0x98,
+	0x66, 0xae, 0xa2, 0x69, 0x2e, 0x74, 0x9e, 0x89, 0x70, 0x87, 0x78, 0x3f, 0xd2, 0x9b, 0xd1, 0x79,
+	0x90, 0xba, 0x15, 0x13, 0xad, 0x8b, 0x00, 0x03, 0x1f, 0x63, 0xb0, 0x17, 0x70, 0xae, 0x7c, 0xb9,
+	0xd1, 0x31, 0x28, 0xb6, 0x0d, 0x20, 0x25, 0xd9, 0x14, 0x82, 0x26, 0xe2, 0x17, 0x4c, 0xf1, 0xaf,
+	0x40, 0x6d, 0x7d, 0x80, 0xd2, 0xf0, 0x2d, 0xd7, 0x13, 0x11, 0x18, 0xf0, 0x91, 0x7f, 0xa8, 0xe4,
+	0xac, 0x76, 0x68, 0x64, 0xff, 0xa5, 0x00, 0x65, 0x9c, 0xdf, 0xb7, 0xda, 0x30, 0x4f, 0x31, 0x4f,
+	0x3b, 0xd1, 0x43, 0x61, 0x97, 0xdd, 0x49, 0x14, 0xf9, 0x29, 0x97, 0x2f, 0x2a, 0xbb, 0xa8, 0x09,
+	0xc3, 0x81, 0x4f, 0x06, 0x47, 0xe9, 0xa5, 0x82, 0xa3, 0x3c, 0x3d, 0x38, 0x70, 0x43, 0xf3, 0xdb,
+	0x93, 0x5e, 0x0f, 0xc5, 0x9a, 0x2e, 0xad, 0xfd, 0x10, 0xe6, 0x37, 0x1d, 0x77, 0x88, 0x69, 0x07,
+	0x9d, 0xb6, 0xdc, 0x13, 0x31, 0x2e, 0x10, 0xad, 0x3b, 0xa7, 0x74, 0x64, 0xd0, 0xb4, 0x0c, 0x0e,
+	0x09, 0x30, 0xb9, 0x15, 0xd3, 0xdc, 0x3a, 0xd0, 0xbc, 0x27, 0xb7, 0xd8, 0xe1, 0x9f, 0x4f, 0x78,
+	0x18, 0x59, 0xaf, 0xa7, 0x78, 0x9e, 0xd5, 0x3c, 0x53, 0x20, 0x83, 0xb3, 0x05, 0xe5, 0xbe, 0x13,
+	0x39, 0xc4, 0x56, 0x7e, 0xdb, 0x75, 0xa8, 0x29, 0xf8, 0xdd, 0xde, 0x81, 0xbd, 0x01, 0x0c, 0xd5,
+	0xff, 0xc8, 0x89, 0x02, 0xf7, 0x48, 0xaf, 0x71, 0x1b, 0xca, 0x22, 0xcd, 0xd2, 0x1a, 0xe7, 0xf5,
+	0x1a, 0x59, 0x9c, 0x5a, 0x46, 0x20, 0xed, 0x15, 0x68, 0xc4, 0xb3, 0xc8, 0xd5, 0x62, 0x50, 0x42,
+	0x7d, 0x93, 0x6a, 0xc4, 0xa7, 0x5d, 0x85, 0xca, 0xba, 0xe3, 0xf5, 0xf8, 0xd0, 0x3e, 0x05, 0x8b,
+	0x89, 0xa3, 0x11, 0x2b, 0xfb, 0x0d, 0x68, 0x26, 0x44, 0xc1, 0x01, 0x1d, 0xd0, 0xf0, 0x51, 0xc5,
+	0xc8, 0xa0, 0xe0, 0x8a, 0x80, 0xd9, 0xfe, 0x63, 0x2f, 0x0a, 0xfc, 0xf1, 0xb1, 0xd8, 0x66, 0x88,
+	0x39, 0x84, 0xfc, 0x4f, 0x7e, 0x0b, 0x6b, 0xe9, 0x69, 0xd4, 0x2f, 0x57, 0x9f, 0x12, 0xd1, 0xe8,
+	0xe8, 0xa1, 0xfd, 0xbb, 0x02, 0x34, 0x90, 0xcf, 0xd6, 0x64, 0x77, 0xe8, 0xf6, 0x7e, 0xcc, 0x8f,
+	0x45, 0xe6, 0x75, 0xfa, 0x7d, 0x8c, 0xdc, 0xb0, 0x2b, 0xe4, 0x2f, 0x21, 0xbb, 0x2a, 0x11, 0x1e,
+	0x5b, 0x6b, 0xc0, 0x78, 0xaf, 0x1f, 0x3a, 0xdd, 0xde, 0x24, 0x38, 0xe4, 0x5d, 0xcf, 0x19, 0x69,
+	0x83, 0xb5, 0x24, 0x7d, 0x5d, 0x90, 0x1f, 0x23, 0x55, 0xc4, 0x7c, 0x38, 0xf0, 0xbf, 0xe8, 0xf6,
+	0xdd, 0x70, 0x3c, 0x74, 0x8e, 0xc9, 0x09, 0xeb, 0x82, 0xb6, 0xa1, 0x48, 0x62, 0x25, 0x91, 0x19,
+	0x15, 0x97, 0xb2, 0x0a, 0x5e, 0x41, 0x10, 0xbf, 0xb7, 0x3f, 0x81, 0x5a, 0x22, 0xd3, 0x35, 0x28,
+	0x7b, 0xda, 0xe6, 0xf5, 0x3b, 0x96, 0xb6, 0xc7, 0xfd, 0x8d, 0xc7, 0x48, 0x55, 0x56, 0xf0, 0xc8,
+	0xd8, 0x47, 0xe3, 0xc9, 0xae, 0x36, 0xb6, 0xf8, 0xb6, 0xff, 0x5d, 0x90, 0x8a, 0xba, 0xab, 0xb6,
+	0x30, 0x7b, 0x7b, 0x29, 0x89, 0x8a, 0x69, 0x89, 0x5e, 0x64, 0x47, 0x1f, 0x40, 0x75, 0x34, 0x19,
+	0x46, 0x98, 0xb1, 0xf7, 0xe5, 0x86, 0xea, 0x77, 0x56, 0xb4, 0xac, 0x8f, 0x88, 0xde, 0xe1, 0x7d,
+	0xce, 0x47, 0xdb, 0xbd, 0xc0, 0x1d, 0x2b, 0xff, 0x89, 0x7f, 0x61, 0xbd, 0x0b, 0xf5, 0x50, 0xd2,
+	0xbb, 0xd2, 0xf9, 0xe6, 0xa4, 0xf3, 0x9d, 0xd1, 0x0c, 0x1e, 0x78, 0xe3, 0x49, 0x64, 0xfc, 0x0e,
+	0xc2, 0xf8, 0xdb, 0xfe, 0x09, 0x2c, 0x6c, 0x39, 0x78, 0x9e, 0xbf, 0xe8, 0x3e, 0xb3, 0x5b, 0x29,
+	0x9e, 0xd8, 0x8a, 0x70, 0x1e, 0xcd, 0x0a, 0x9d, 0x87, 0x7e, 0xa9, 0x43, 0x9d, 0x86, 0xf6, 0x1a,
+	0x7a, 0xbd, 0x58, 0x77, 0x0a, 0xb2, 0x91, 0x20, 0xb1, 0x52, 0xf9, 0xcc, 0x1d, 0xf3, 0x0d, 0x79,
+	0x84, 0xdb, 0xbf, 0x2a, 0x02, 0x3c, 0xc4, 0x23, 0x4b, 0x0d, 0x45, 0x12, 0x1f, 0x79, 0x98, 0x0d,
+	0x3d, 0xb7, 0xa7, 0x93, 0xb8, 0x1e, 0xc7, 0xa6, 0x2f, 0x3e, 0xc7, 0xf4, 0x14, 0x70, 0xa5, 0x38,
+	0xe0, 0xbe, 0x51, 0x86, 0x4b, 0x9d, 0x27, 0x73, 0xd3, 0xce, 0x93, 0x8a, 0x79, 0x9e, 0x60, 0xdd,
+	0x13, 0x1e, 0xb8, 0xe3, 0x2e, 0x1e, 0x8a, 0xbd, 0x83, 0x70, 0x32, 0xa2, 0x5a, 0xa4, 0x21, 0x88,
+	0xeb, 0x44, 0xb3, 0x2e, 0x41, 0x7d, 0x72, 0x67, 0xaf, 0xdb, 0xf3, 0x27, 0x5e, 0xc4, 0x03, 0x59,
+	0x80, 0x34, 0x3b, 0x80, 0xa4, 0x75, 0x45, 0xb1, 0x9f, 0x15, 0xa1, 0xde, 0xc1, 0xd3, 0x32, 0x22,
+	0x95, 0x60, 0x16, 0x27, 0xe3, 0x74, 0x03, 0xa1, 0xe1, 0x11, 0x9d, 0x1a, 0x4d, 0xa2, 0x76, 0x24,
+	0x51, 0x88, 0x1b, 0xa2, 0x46, 0xbc, 0xfd, 0x68, 0x40, 0x95, 0x5b, 0x3c, 0x9e, 0xbe, 0xff, 0xd2,
+	0x8c, 0xfd, 0x9f, 0x3c, 0x3d, 0xca, 0x79, 0xa7, 0xc7, 0xcb, 0xab, 0x29, 0xa3, 0x81, 0xf9, 0xac,
+	0x06, 0x04, 0x40, 0xea, 0x91, 0x6a, 0x04, 0x55, 0xa3, 0x81, 0x20, 0xa9, 0x12, 0x41, 0x14, 0x03,
+	0xea, 0x8b, 0x9c, 0x88, 0x41, 0x8b, 0xd2, 0x9b, 0xce, 0xa1, 0xd7, 0x00, 0x88, 0x22, 0x12, 0xe8,
+	0xf4, 0x9c, 0xf7, 0xf7, 0x22, 0xb4, 0x3a, 0xbc, 0x87, 0xa7, 0x6f, 0x70, 0x4c, 0xfa, 0xc6, 0xca,
+	0xe8, 0x0b, 0x3f, 0xe8, 0x2b, 0xf9, 0x28, 0x8b, 0xd6, 0x04, 0x45, 0x8a, 0x37, 0x5d, 0x97, 0xc5,
+	0x97, 0xd2, 0x65, 0xe9, 0x79, 0xba, 0x2c, 0x4f, 0xd3, 0xe5, 0x9c, 0xa9, 0x4b, 0x2c, 0xbd, 0xb8,
+	0xb7, 0xe7, 0x07, 0x58, 0x1d, 0x0b, 0x11, 0x87, 0x6e, 0x18, 0x49, 0x65, 0x63, 0xe9, 0x45, 0xf4,
+	0xcf, 0x88, 0x2c, 0x32, 0xa2, 0x4c, 0x26, 0xca, 0xe3, 0xe4, 0x77, 0xd6, 0x14, 0xb5, 0x13, 0xa6,
+	0x38, 0x03, 0xf3, 0xfd, 0x00, 0x1d, 0x6f, 0xe2, 0xc9, 0x4a, 0x17, 0x6b, 0x15, 0x1c, 0x76, 0x26,
+	0x9e, 0xfd, 0x1e, 0xd4, 0x05, 0x67, 0x7d, 0x4c, 0xde, 0x4c, 0x1d, 0x93, 0x71, 0xa6, 0x32, 0x20,
+	0xc6, 0x09, 0x79, 0x01, 0xe6, 0xc5, 0x84, 0xb0, 0x0c, 0x0a, 0x25, 0xe4, 0xa6, 0x58, 0x97, 0xdf,
+	0xf6, 0x1f, 0x0a, 0x50, 0xdf, 0x76, 0xf7, 0xbd, 0x47, 0x54, 0xf3, 0xcc, 0xcc, 0x5f, 0x99, 0x72,
+	0xa1, 0x91, 0x94, 0x4a, 0xa9, 0x0c, 0x5e, 0xca, 0x64, 0xf0, 0x4c, 0x82, 0x2d, 0xbf, 0x78, 0x82,
+	0xfd, 0x39, 0x34, 0xf1, 0x0a, 0xe3, 0xee, 0x1d, 0x6b, 0xf1, 0xa6, 0xe6, 0x44, 0xeb, 0x3c, 0xd4,
+	0x30, 0x99, 0x7b, 0xf2, 0xde, 0x45, 0xd2, 0x25, 0x04, 0x53, 0xf2, 0xd2, 0x0c, 0xc9, 0xb3, 0xa7,
+	0xe1, 0x8f, 0x80, 0xd1, 0xca, 0xdb, 0x26, 0xab, 0x6f, 0x22, 0x82, 0xfd, 0xc7, 0x82, 0x08, 0x9c,
+	0x5e, 0x70, 0x3c, 0x8e, 0xf4, 0x6e, 0xb0, 0x36, 0xc5, 0xa3, 0xf2, 0x80, 0xeb, 0x48, 0xa1, 0xd1,
+	0x0c, 0x3d, 0xe3, 0x09, 0xa2, 0xf3, 0x93, 0xef, 0x0d, 0xe3, 0xc3, 0x90, 0x68, 0x4f, 0x90, 0x94,
+	0xb6, 0x60, 0x79, 0xd6, 0x49, 0x3b, 0x97, 0xd9, 0xed, 0x53, 0x60, 0x24, 0x20, 0xef, 0x6b, 0x11,
+	0x31, 0x12, 0x3c, 0x1f, 0xcb, 0x27, 0x92, 0x50, 0x0d, 0x66, 0x08, 0x88, 0x3e, 0x36, 0x18, 0x39,
+	0x3d, 0xd2, 0xb2, 0xfc, 0xb6, 0x3f, 0x87, 0xd6, 0x06, 0x4f, 0x6d, 0x7c, 0xa6, 0x97, 0xc5, 0x4b,
+	0x16, 0xa7, 0x2c, 0x59, 0xca, 0x5f, 0xb2, 0x6c, 0x2c, 0xb9, 0x09, 0x8c, 0x96, 0x4c, 0xb6, 0x92,
+	0x29, 0x9d, 0x0d, 0x0e, 0x86, 0x49, 0x8b, 0xe9, 0x93, 0xf6, 0x5f, 0x68, 0xb4, 0x75, 0x77, 0x3c,
+	0xe0, 0x01, 0xd6, 0x43, 0x4f, 0x9d, 0xe1, 0xe4, 0x39, 0xb2, 0xe3, 0x71, 0x28, 0xcc, 0xa9, 0xb8,
+	0x88, 0x4f, 0xb1, 0x9b, 0x43, 0xf1, 0x3b, 0x92, 0x5a, 0x0d, 0x54, 0x92, 0x94, 0xf2, 0x51, 0xa2,
+	0xd7, 0x43, 0xeb, 0x2a, 0xb4, 0x9c, 0xf0, 0x00, 0xad, 0xdb, 0xd5, 0x00, 0x75, 0x53, 0x6f, 0x20,
+	0xf5, 0x89, 0xf7, 0xf1, 0x09, 0x54, 0x5f, 0x6d, 0x93, 0x12, 0x91, 0x42, 0xd1, 0xd6, 0xad, 0x16,
+	0x14, 0xdd, 0x43, 0x99, 0xf3, 0x1b, 0x1d, 0xfc, 0xc2, 0xba, 0x81, 0xa9, 0xcd, 0xf0, 0x7e, 0xbc,
+	0x9d, 0x58, 0xbe, 0x82, 0x21, 0x9f, 0x3d, 0x41, 0x5f, 0x0d, 0x23, 0x77, 0xe4, 0x44, 0x7c, 0xe7,
+	0x68, 0x1b, 0xab, 0x5a, 0x71, 0xde, 0xfa, 0x93, 0x08, 0x43, 0x35, 0x4c, 0x25, 0xeb, 0x06, 0x11,
+	0x55, 0xbe, 0x46, 0xf7, 0x74, 0x3d, 0x03, 0xa3, 0xce, 0xc6, 0xba, 0xa2, 0x29, 0xc8, 0xac, 0x4c,
+	0x61, 0x5f, 0x86, 0x0a, 0x2d, 0x87, 0xb9, 0x30, 0x3a, 0xea, 0x1a, 0xb5, 0x75, 0x25, 0x92, 0x13,
+	0xf6, 0x9f, 0x0a, 0x50, 0x11, 0xc1, 0xb8, 0x73, 0xf4, 0x7f, 0x11, 0x49, 0x58, 0x2a, 0xdd, 0x59,
+	0xd1, 0x43, 0xf1, 0xb3, 0xa1, 0xdf, 0x3b, 0xe8, 0xa2, 0x9a, 0x54, 0x2c, 0xa1, 0x23, 0x08, 0xc2,
+	0x0e, 0x8e, 0xed, 0xff, 0x62, 0x7d, 0xbf, 0xed, 0x8e, 0xc6, 0x43, 0x4e, 0xc2, 0xde, 0x84, 0x8a,
+	0x5a, 0x53, 0xfa, 0x4c, 0x3d, 0xb9, 0x95, 0xed, 0x1c, 0xc9, 0x0c, 0x28, 0x73, 0x1f, 0x41, 0xac,
+	0x5b, 0x30, 0x4f, 0x9b, 0x40, 0x79, 0x05, 0x7a, 0x29, 0x41, 0x3f, 0x91, 0x13, 0x12, 0xae, 0x41,
+	0xd6, 0xfb, 0xd0, 0x88, 0xb0, 0x5e, 0x09, 0x1d, 0x79, 0xb4, 0x85, 0xb8, 0x09, 0xf1, 0xa3, 0x38,
+	0xc5, 0xee, 0x24, 0x73, 0xf2, 0x77, 0x29, 0xf0, 0xcc, 0x0c, 0x68, 0x6e, 0x7f, 0x6e, 0xc6, 0xf6,
+	0x2b, 0x99, 0xed, 0xff, 0xb3, 0x00, 0xb5, 0x9d, 0xf8, 0x5e, 0xf7, 0x0e, 0x34, 0x02, 0xf5, 0xd9,
+	0x35, 0x0e, 0xae, 0x58, 0x03, 0xe6, 0xa1, 0x55, 0x0f, 0x92, 0x01, 0xfe, 0x6e, 0xbe, 0xcf, 0x23,
+	0xbc, 0xb5, 0x86, 0x54, 0x87, 0x9e, 0x4f, 0xd4, 0x40, 0x3f, 0xda, 0x50, 0xf3, 0x4a, 0x1d, 0x04,
+	0xb6, 0x3e, 0x02, 0x08, 0xf1, 0xd8, 0xa0, 0x8e, 0x4f, 0x49, 0xfe, 0xf4, 0xd2, 0x89, 0x9f, 0x6e,
+	0xc7, 0x10, 0x3a, 0x77, 0xe2, 0xb1, 0x7d, 0x1b, 0xe6, 0x76, 0xe4, 0x7d, 0x72, 0x15, 0x8a, 0xd1,
+	0x11, 0xdd, 0x7f, 0xa6, 0xaa, 0x13, 0x21, 0xf6, 0x2f, 0xb1, 0x90, 0x94, 0x35, 0x39, 0x99, 0xfb,
+	0x1b, 0x64, 0x38, 0xfc, 0xc9, 0xbe, 0x13, 0x62, 0xdd, 0x82, 0x85, 0x11, 0x65, 0x8b, 0x2a, 0x12,
+	0xb6, 0xc4, 0x58, 0x4f, 0x0e, 0xdd, 0x91, 0x1b, 0x51, 0xa6, 0x13, 0x93, 0x0f, 0xc5, 0x58, 0xc4,
+	0x79, 0xe4, 0x4b, 0xfb, 0x60, 0x9c, 0x47, 0x7e, 0x12, 0xd3, 0x15, 0x33, 0xe7, 0xbc, 0x06, 0x96,
+	0xb8, 0x86, 0x77, 0xa9, 0xf5, 0x85, 0x95, 0xf3, 0xc4, 0x3b, 0xa0, 0xec, 0xc0, 0xc4, 0x0c, 0xf5,
+	0x34, 0xd7, 0x05, 0x5d, 0x54, 0x2b, 0x12, 0x3d, 0x54, 0x55, 0x2e, 0x95, 0xce, 0x82, 0xf4, 0x50,
+	0xd5, 0xb9, 0x67, 0xa1, 0xda, 0x1b, 0x38, 0xe8, 0x37, 0x6e, 0x9f, 0x6a, 0x99, 0x79, 0x39, 0x7e,
+	0xd0, 0xb7, 0x7f, 0x8b, 0x59, 0x53, 0x2a, 0x23, 0x71, 0x81, 0x0c, 0xbb, 0xc2, 0x09, 0x76, 0xa2,
+	0x0e, 0xd5, 0x67, 0x65, 0xf7, 0x90, 0xc2, 0x14, 0x62, 0xd2, 0xd3, 0x34, 0x20, 0x20, 0x05, 0x25,
+	0x80, 0x4e, 0x1a, 0x10, 0xea, 0xde, 0x51, 0x4c, 0xda, 0xb6, 0x6f, 0x02, 0x90, 0x54, 0xc2, 0xb4,
+	0x58, 0x7a, 0x4a, 0x89, 0x94, 0x1a, 0x54, 0xf6, 0xab, 0x09, 0x8a, 0xdc, 0xbf, 0xfd, 0x00, 0x58,
+	0x6c, 0xcf, 0x6f, 0x57, 0x1c, 0xd9, 0x7b, 0x60, 0x49, 0x56, 0x33, 0x4b, 0x99, 0xc6, 0xb7, 0x2e,
+	0x65, 0xec, 0x27, 0x70, 0x5a, 0xae, 0xf3, 0xbc, 0x92, 0xe5, 0x45, 0x97, 0xb2, 0xbf, 0x96, 0x49,
+	0x6c, 0x1f, 0x4d, 0x8a, 0x25, 0xbc, 0x1b, 0x1d, 0x5b, 0xb7, 0xa1, 0xea, 0xd2, 0x37, 0x05, 0x45,
+	0x9c, 0x98, 0x34, 0x46, 0x5d, 0xae, 0x35, 0x4a, 0xd4, 0xcc, 0xe8, 0x15, 0x43, 0x61, 0x76, 0xde,
+	0x1d, 0xb8, 0x7d, 0xa4, 0xd3, 0x3a, 0x0b, 0x31, 0xfd, 0xbe, 0x24, 0xa7, 0xa1, 0x87, 0x6e, 0x38,
+	0x71, 0x86, 0x94, 0x8d, 0x13, 0xe8, 0x53, 0x49, 0xce, 0xed, 0x87, 0x94, 0xf3, 0xfa, 0x21, 0xf6,
+	0x3e, 0xb4, 0xc4, 0x0e, 0x78, 0x3f, 0xde, 0xc3, 0xf4, 0xfa, 0x4d, 0xb4, 0x69, 0x65, 0xef, 0xa3,
+	0xab, 0xcf, 0x70, 0xd4, 0xc6, 0x38, 0xee, 0x86, 0xa4, 0x74, 0x55, 0xca, 0xea, 0xea, 0xf7, 0x05,
+	0x58, 0x14, 0x8d, 0xa1, 0xf5, 0x8d, 0xfb, 0xd4, 0x36, 0x15, 0xbf, 0x79, 0x79, 0x85, 0x5d, 0x83,
+	0x85, 0x31, 0xe7, 0x41, 0xf7, 0x84, 0x24, 0x4d, 0x41, 0x4e, 0x7a, 0x33, 0x79, 0x2a, 0x28, 0xe5,
+	0xaa, 0xe0, 0x4d, 0x3c, 0xcb, 0xd3, 0x52, 0x89, 0x48, 0x51, 0xa3, 0x6e, 0x52, 0x7c, 0x42, 0x18,
+	0x03, 0x30, 0xff, 0x35, 0xb7, 0x79, 0xf4, 0xe9, 0x9d, 0x4d, 0xe3, 0x96, 0x68, 0xde, 0x5d, 0x0a,
+	0x27, 0x2e, 0xd2, 0xab, 0xd0, 0xdc, 0xa4, 0xf6, 0xf3, 0xc7, 0xb2, 0x91, 0x8b, 0xb5, 0x6d, 0x2a,
+	0xd6, 0x69, 0x64, 0xdf, 0x85, 0x05, 0x0d, 0xd4, 0xb9, 0x01, 0xa1, 0xfe, 0xde, 0x1e, 0x5e, 0xc2,
+	0x35, 0x54, 0x8d, 0x0c, 0x16, 0xc5, 0x14, 0x8b, 0x0f, 0xa1, 0xa5, 0x59, 0x7c, 0x3a, 0x16, 0x2d,
+	0x78, 0x61, 0xd3, 0xb1, 0x73, 0x2c, 0x3e, 0xb5, 0x83, 0xd3, 0x50, 0x16, 0x87, 0xa2, 0x69, 0x5f,
+	0xa4, 0xe2, 0x10, 0xbf, 0xed, 0xab, 0x50, 0xdd, 0xe6, 0xc3, 0xbd, 0x1d, 0xb1, 0xf6, 0xd4, 0x5f,
+	0xda, 0x37, 0x60, 0x71, 0x83, 0xef, 0x4e, 0xf6, 0x1f, 0xba, 0xde, 0x01, 0x16, 0x54, 0xea, 0x39,
+	0xe0, 0x34, 0x54, 0x8e, 0x39, 0x46, 0xbf, 0x4f, 0x7d, 0x81, 0x39, 0x1c, 0x3d, 0xf6, 0x45, 0x6b,
+	0x31, 0xc6, 0xa2, 0x13, 0x6c, 0x47, 0x58, 0x37, 0xd9, 0xff, 0x29, 0x8a, 0xba, 0x97, 0xa8, 0x92,
+	0x24, 0x77, 0xe4, 0x1c, 0xe3, 0xa9, 0xad, 0x0b, 0x7e, 0x35, 0xd2, 0x5d, 0x94, 0x62, 0xd2, 0x45,
+	0x41, 0xe4, 0x48, 0x76, 0x35, 0xc9, 0xa8, 0x34, 0x4a, 0xf5, 0x6c, 0xca, 0x53, 0x7a, 0x36, 0x73,
+	0xcf, 0xe9, 0xd9, 0x4c, 0xbd, 0x55, 0x57, 0x66, 0xdc, 0xaa, 0x31, 0x38, 0xc4, 0xb3, 0x42, 0x24,
+	0xaf, 0xbe, 0xf2, 0xd4, 0xa8, 0x75, 0x6a, 0x92, 0x22, 0xae, 0x97, 0xa2, 0x16, 0x53, 0xd3, 0xfa,
+	0xee, 0x5f, 0x95, 0x1b, 0x6c, 0x48, 0xa2, 0x6e, 0x87, 0xe2, 0x09, 0x14, 0xd0, 0xfd, 0xbf, 0xbb,
+	0xe7, 0x1c, 0xa8, 0x6b, 0x34, 0x3d, 0xf7, 0x30, 0x3d, 0xb3, 0x89, 0x13, 0x92, 0x25, 0x1a, 0x20,
+	0x46, 0xcb, 0x26, 0xc1, 0xd8, 0x0f, 0xe5, 0xc5, 0xb8, 0xd9, 0x59, 0xd0, 0x13, 0x02, 0xb8, 0xe5,
+	0x87, 0xf6, 0x02, 0x34, 0x0d, 0x55, 0xfb, 0x63, 0x7b, 0x0b, 0x1a, 0x31, 0xe1, 0xa1, 0xbf, 0x2f,
+	0x6f, 0xf4, 0xfc, 0x10, 0x6f, 0xf4, 0xf4, 0x52, 0x20, 0x07, 0x42, 0xcb, 0xbb, 0x93, 0xde, 0x01,
+	0x8f, 0x48, 0xf5, 0x34, 0x92, 0xd7, 0x77, 0x7e, 0x14, 0x91, 0xee, 0xe5, 0xb7, 0xfd, 0x09, 0x9c,
+	0x8a, 0x39, 0x3e, 0x42, 0x8d, 0xe3, 0xd5, 0x9c, 0x2b, 0xd7, 0x33, 0xd3, 0x49, 0x33, 0x49, 0x27,
+	0xd3, 0xdc, 0xf7, 0x3a, 0x2c, 0x64, 0x18, 0x49, 0x6b, 0xcb, 0x2f, 0xed, 0x17, 0x6a, 0x64, 0xff,
+	0x14, 0x96, 0x32, 0xd0, 0xcf, 0x02, 0x37, 0xe2, 0xb3, 0x17, 0x25, 0x4e, 0x45, 0x93, 0x13, 0xbd,
+	0x94, 0x60, 0x20, 0xa8, 0x1b, 0xa3, 0x1a, 0xd8, 0xaf, 0x1b, 0x7b, 0xda, 0x14, 0x94, 0x38, 0x76,
+	0x43, 0x34, 0xbb, 0xaf, 0x03, 0x9d, 0x46, 0x37, 0x7e, 0x73, 0x1a, 0xea, 0x74, 0xb8, 0xc8, 0xfa,
+	0x6c, 0x05, 0x5e, 0x31, 0x86, 0xdd, 0xe4, 0x4d, 0x94, 0x7d, 0x67, 0xb9, 0xfc, 0xeb, 0xbf, 0xb5,
+	0x0b, 0xe8, 0xae, 0xcc, 0x44, 0x88, 0x07, 0x17, 0x56, 0xa0, 0xb9, 0x0b, 0x70, 0xca, 0x9c, 0xa3,
+	0x17, 0x0e, 0x56, 0x5c, 0x2e, 0x7f, 0x95, 0x33, 0x4d, 0x8f, 0x17, 0xac, 0x44, 0xd3, 0x97, 0xe0,
+	0xb4, 0x39, 0x1d, 0x3f, 0xf8, 0xb0, 0x32, 0xb1, 0xcf, 0x08, 0x97, 0xb4, 0x41, 0xd9, 0x1c, 0x21,
+	0x56, 0xe1, 0x6c, 0x6a, 0x05, 0x33, 0x7f, 0xb1, 0xca, 0x72, 0x55, 0x80, 0xfe, 0x21, 0x80, 0x6b,
+	0xb0, 0x9c, 0x07, 0x54, 0xc9, 0x87, 0xcd, 0x1b, 0xc8, 0xeb, 0x70, 0x2e, 0x0f, 0x49, 0x99, 0x8e,
+	0x55, 0x97, 0xab, 0x5f, 0x69, 0x68, 0x46, 0xbe, 0xe4, 0x51, 0x81, 0xd5, 0xf2, 0x15, 0xa4, 0xa7,
+	0x81, 0x34, 0x60, 0x43, 0x3b, 0xc3, 0x20, 0x3e, 0x1d, 0x58, 0x9d, 0x58, 0x64, 0xb4, 0x94, 0x00,
+	0x1a, 0xc4, 0x24, 0x23, 0x45, 0xd2, 0x1d, 0x66, 0x4d, 0x62, 0x71, 0x19, 0xce, 0x98, 0x08, 0xa3,
+	0x5b, 0xca, 0x5a, 0x04, 0x39, 0x0f, 0x56, 0xca, 0x92, 0xb2, 0x04, 0x66, 0x0b, 0x34, 0x7b, 0x35,
+	0x2d, 0xa7, 0x79, 0x2b, 0x62, 0x6c, 0xb9, 0x22, 0x30, 0xd5, 0x82, 0x75, 0x11, 0x96, 0x52, 0x9a,
+	0xa3, 0x17, 0x74, 0xb6, 0x48, 0x82, 0x5e, 0x83, 0xf3, 0x19, 0x4f, 0x4a, 0xbd, 0x09, 0x31, 0x2b,
+	0xc6, 0xb5, 0x73, 0x71, 0x58, 0xf2, 0xb1, 0x53, 0xca, 0x52, 0x7f, 0xcd, 0x91, 0x59, 0xbd, 0x11,
+	0xb1, 0xa5, 0x7c, 0xbd, 0xc5, 0x75, 0x2c, 0x3b, 0x4d, 0xcb, 0x9c, 0x83, 0xc5, 0x34, 0x40, 0xf0,
+	0x7f, 0x25, 0xde, 0x71, 0xca, 0x5f, 0xd2, 0x0d, 0x04, 0x76, 0x86, 0x50, 0x19, 0xfb, 0x99, 0x2f,
+	0xae, 0xac, 0x4d, 0x98, 0x2b, 0x69, 0x17, 0x4d, 0x3d, 0xc2, 0xb2, 0xb3, 0xf9, 0xa0, 0xd4, 0x93,
+	0x1c, 0x5b, 0x26, 0x81, 0xaf, 0xa4, 0x77, 0x14, 0x3f, 0xc4, 0xb1, 0x73, 0x86, 0x52, 0x32, 0xde,
+	0x90, 0xbc, 0xb4, 0xb2, 0xf3, 0xf9, 0x51, 0x95, 0xbc, 0x7c, 0xb0, 0x0b, 0xf9, 0x5e, 0xab, 0xa7,
+	0x2f, 0xc6, 0x5e, 0x9b, 0xb2, 0xb3, 0x3e, 0x88, 0xd9, 0x8a, 0x11, 0x45, 0x19, 0xcd, 0x98, 0xed,
+	0x67, 0x66, 0xe7, 0xeb, 0x38, 0xdd, 0x92, 0x66, 0x57, 0xf2, 0xdd, 0x3b, 0x69, 0x53, 0xb3, 0xab,
+	0xf9, 0xee, 0x6d, 0x94, 0xfb, 0xec, 0x5a, 0xbe, 0x7e, 0x53, 0x65, 0x3c, 0x5b, 0x25, 0x50, 0xc6,
+	0x3f, 0xb3, 0x35, 0x38, 0x5b, 0x23, 0x89, 0x56, 0xe1, 0x42, 0xca, 0x3f, 0xb3, 0x2f, 0x92, 0xec,
+	0x7a, 0x0c, 0x3c, 0x9b, 0x0f, 0x14, 0xd2, 0xdf, 0x30, 0x8c, 0x76, 0x2d, 0xa3, 0x89, 0x54, 0xdf,
+	0x86, 0xdd, 0x34, 0x22, 0xcc, 0x4a, 0xbb, 0xac, 0x9c, 0x7f, 0x6d, 0xb9, 0xf2, 0x95, 0x9a, 0xcf,
+	0x68, 0x34, 0xdd, 0xa9, 0x67, 0xaf, 0xe7, 0xeb, 0xcb, 0x68, 0x3a, 0xb3, 0x5b, 0xf9, 0x99, 0x9b,
+	0xda, 0xcf, 0xec, 0x8d, 0x7c, 0x4d, 0x65, 0x3b, 0x52, 0xec, 0x76, 0x1c, 0xc9, 0x19, 0x0b, 0x9b,
+	0x2d, 0x44, 0xf6, 0x66, 0xbc, 0xaf, 0xb5, 0x34, 0xbf, 0x6c, 0x0b, 0x93, 0xdd, 0x89, 0x77, 0x98,
+	0xe1, 0x98, 0x6e, 0x4a, 0xb2, 0xb7, 0xa6, 0x71, 0xcc, 0x76, 0x12, 0xd9, 0xdb, 0x31, 0x47, 0x3b,
+	0x9b, 0xdb, 0x92, 0xcb, 0x12, 0xfb, 0x6e, 0xbe, 0xa7, 0xa6, 0xaf, 0x23, 0xec, 0x1d, 0xda, 0x6d,
+	0x46, 0xaf, 0xc6, 0x3f, 0x8a, 0xd8, 0xf7, 0x88, 0xd1, 0xab, 0xe9, 0xc3, 0x25, 0xf3, 0xf4, 0xc8,
+	0xde, 0xcd, 0xcf, 0x2b, 0xe6, 0x4b, 0x21, 0xfb, 0x7e, 0xfe, 0x6a, 0x46, 0xe7, 0x82, 0xbd, 0x97,
+	0x2f, 0x76, 0xfa, 0x3e, 0xcf, 0xde, 0xcf, 0x0f, 0xb0, 0xe4, 0x7e, 0xcd, 0x3e, 0x88, 0x4f, 0xd9,
+	0x0b, 0xd9, 0x73, 0x2e, 0x75, 0x1b, 0x61, 0x3f, 0x98, 0x12, 0xd1, 0x69, 0xd4, 0x87, 0x71, 0x1e,
+	0x3b, 0x9b, 0xce, 0x1f, 0xc6, 0x35, 0x85, 0x7d, 0x94, 0xef, 0x62, 0xd9, 0x8b, 0x3c, 0xfb, 0x21,
+	0xe1, 0xd6, 0xe0, 0xe2, 0x09, 0x5c, 0x3a, 0xbc, 0xef, 0x12, 0xf2, 0x26, 0x5c, 0x3e, 0x81, 0x3c,
+	0x11, 0xe3, 0xf7, 0x48, 0xc6, 0x9b, 0xe9, 0x2d, 0x9f, 0xb8, 0x46, 0xb0, 0xfe, 0x72, 0xf5, 0x99,
+	0x0e, 0xdf, 0xd5, 0x29, 0x60, 0x7d, 0x8f, 0x60, 0x7c, 0xb9, 0xfc, 0x2c, 0x47, 0x3f, 0xe9, 0xab,
+	0x05, 0xdb, 0x5b, 0x2e, 0xff, 0x39, 0x47, 0x3f, 0xa9, 0xaa, 0x98, 0xed, 0x13, 0xab, 0x8c, 0x8b,
+	0x98, 0x95, 0x32, 0x1b, 0x10, 0xa3, 0xeb, 0x70, 0x29, 0x17, 0x93, 0xd4, 0xbe, 0xcc, 0x23, 0x76,
+	0x19, 0xc7, 0xcc, 0x40, 0x99, 0x4f, 0x1c, 0x6f, 0xc0, 0xca, 0x0c, 0x98, 0xac, 0x6c, 0xd9, 0x98,
+	0x58, 0x4e, 0x5b, 0x3d, 0xa9, 0x52, 0xd9, 0xe7, 0x0a, 0x7a, 0xef, 0x6d, 0xb8, 0xd2, 0xf3, 0x47,
+	0xb7, 0x42, 0x27, 0xf2, 0xc3, 0x81, 0x3b, 0x74, 0x76, 0x43, 0x7d, 0xf3, 0x19, 0xba, 0xbb, 0xea,
+	0x1f, 0x7b, 0xbb, 0x93, 0xbd, 0x7b, 0xcd, 0x1d, 0x49, 0x24, 0xae, 0xbb, 0x15, 0x39, 0xf1, 0xd6,
+	0xff, 0x00, 0x7d, 0x12, 0x61, 0x80, 0xf1, 0x27, 0x00, 0x00,
 }
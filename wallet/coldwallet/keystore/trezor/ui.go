@@ -3,7 +3,6 @@ package trezor
 import (
 	"fmt"
 	"os"
-	"strconv"
 
 	"golang.org/x/crypto/ssh/terminal"
 )
@@ -19,6 +18,9 @@ const PIN_MATRIX_DESCRIPTION = `Use the numeric keypad to describe number positi
     4 5 6
     1 2 3`
 
+// MAX_PIN_LENGTH is the maximum number of digits of a Trezor PIN
+const MAX_PIN_LENGTH = 9
+
 type TrezorUI struct {
 	pinmatrixShown bool
 	promptShown    bool
@@ -39,7 +41,9 @@ func (ui *TrezorUI) ButtonRequest() {
 	}
 }
 
-func (ui *TrezorUI) GetPin(code PinMatrixRequestType) string {
+// GetPin prompts for the PIN positions on the matrix shown on the device. It returns false
+// if the user entered an empty PIN to cancel the request.
+func (ui *TrezorUI) GetPin(code PinMatrixRequestType) (string, bool) {
 	var desc string
 	if code == PinMatrixRequestType_Current {
 		desc = "current PIN"
@@ -57,16 +61,30 @@ func (ui *TrezorUI) GetPin(code PinMatrixRequestType) string {
 		}
 	}
 	for {
-		pin := prompt(fmt.Sprintf("Please enter %v: ", desc))
-		// except click.Abort:
-		//     raise Cancelled from None
+		pin := prompt(fmt.Sprintf("Please enter %v (empty to cancel): ", desc))
 		fmt.Println()
-		if _, err := strconv.Atoi(pin); err != nil {
-			fmt.Println("Non-numerical PIN provided, please try again")
+		if pin == "" {
+			return "", false
+		}
+		if err := ValidatePinMatrixInput(pin); err != nil {
+			fmt.Printf("%v, please try again\n", err)
 		} else {
-			return pin
+			return pin, true
+		}
+	}
+}
+
+// ValidatePinMatrixInput checks that the input only consists of matrix positions 1 to 9
+func ValidatePinMatrixInput(pin string) error {
+	if len(pin) > MAX_PIN_LENGTH {
+		return fmt.Errorf("PIN longer than %v digits provided", MAX_PIN_LENGTH)
+	}
+	for _, c := range pin {
+		if c < '1' || c > '9' {
+			return fmt.Errorf("Invalid PIN provided, only the positions 1 to 9 are allowed")
 		}
 	}
+	return nil
 }
 
 func (ui *TrezorUI) GetPassphrase() string {
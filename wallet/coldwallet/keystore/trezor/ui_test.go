@@ -0,0 +1,18 @@
+package trezor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePinMatrixInput(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(ValidatePinMatrixInput("1"))
+	assert.Nil(ValidatePinMatrixInput("987654321"))
+
+	assert.NotNil(ValidatePinMatrixInput("1234567891"))
+	assert.NotNil(ValidatePinMatrixInput("1230"))
+	assert.NotNil(ValidatePinMatrixInput("12a4"))
+}
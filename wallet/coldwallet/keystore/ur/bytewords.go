@@ -0,0 +1,76 @@
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+// bytewords are the words of the Bytewords encoding (BCR-2020-012), one for each byte value. The
+// minimal encoding used by the URs spells each byte with the first and the last letter of its word.
+var bytewords = [256]string{
+	"able", "acid", "also", "apex", "aqua", "arch", "atom", "aunt", "away", "axis", "back", "bald", "barn", "belt", "beta", "bias",
+	"blue", "body", "brag", "brew", "bulb", "buzz", "calm", "cash", "cats", "chef", "city", "claw", "code", "cola", "cook", "cost",
+	"crux", "curl", "cusp", "cyan", "dark", "data", "days", "deli", "dice", "diet", "door", "down", "draw", "drop", "drum", "dull",
+	"duty", "each", "easy", "echo", "edge", "epic", "even", "exam", "exit", "eyes", "fact", "fair", "fern", "figs", "film", "fish",
+	"fizz", "flap", "flew", "flux", "foxy", "free", "frog", "fuel", "fund", "gala", "game", "gear", "gems", "gift", "girl", "glow",
+	"good", "gray", "grim", "guru", "gush", "gyro", "half", "hang", "hard", "hawk", "heat", "help", "high", "hill", "holy", "hope",
+	"horn", "huts", "iced", "idea", "idle", "inch", "inky", "into", "iris", "iron", "item", "jade", "jazz", "join", "jolt", "jowl",
+	"judo", "jugs", "jump", "junk", "jury", "keep", "keno", "kept", "keys", "kick", "kiln", "king", "kite", "kiwi", "knob", "lamb",
+	"lava", "lazy", "leaf", "legs", "liar", "limp", "lion", "list", "logo", "loud", "love", "luau", "luck", "lung", "main", "many",
+	"math", "maze", "memo", "menu", "meow", "mild", "mint", "miss", "monk", "nail", "navy", "need", "news", "next", "noon", "note",
+	"numb", "obey", "oboe", "omit", "onyx", "open", "oval", "owls", "paid", "part", "peck", "play", "plus", "poem", "pool", "pose",
+	"puff", "puma", "purr", "quad", "quiz", "race", "ramp", "real", "redo", "rich", "road", "rock", "roof", "ruby", "ruin", "runs",
+	"rust", "safe", "saga", "scar", "sets", "silk", "skew", "slot", "soap", "solo", "song", "stub", "surf", "swan", "taco", "task",
+	"taxi", "tent", "tied", "time", "tiny", "toil", "tomb", "toys", "trip", "tuna", "twin", "ugly", "undo", "unit", "urge", "user",
+	"vast", "very", "veto", "vial", "vibe", "view", "visa", "void", "vows", "wall", "wand", "warm", "wasp", "wave", "waxy", "webs",
+	"what", "when", "whiz", "wolf", "work", "yank", "yawn", "yell", "yoga", "yurt", "zaps", "zero", "zest", "zinc", "zone", "zoom",
+}
+
+var minimalBytewords = make(map[string]byte, len(bytewords))
+
+func init() {
+	for i, word := range bytewords {
+		minimalBytewords[word[:1]+word[3:]] = byte(i)
+	}
+}
+
+// encodeMinimalBytewords encodes the data, followed by its CRC32 checksum, in minimal Bytewords
+func encodeMinimalBytewords(data []byte) string {
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(data))
+
+	var sb strings.Builder
+	for _, b := range append(append([]byte{}, data...), checksum...) {
+		word := bytewords[b]
+		sb.WriteByte(word[0])
+		sb.WriteByte(word[3])
+	}
+	return sb.String()
+}
+
+// decodeMinimalBytewords decodes the minimal Bytewords, and verifies the checksum of the data
+func decodeMinimalBytewords(encoded string) ([]byte, error) {
+	encoded = strings.ToLower(encoded)
+	if len(encoded)%2 != 0 {
+		return nil, errors.New("invalid bytewords length")
+	}
+	decoded := make([]byte, 0, len(encoded)/2)
+	for i := 0; i < len(encoded); i += 2 {
+		b, ok := minimalBytewords[encoded[i:i+2]]
+		if !ok {
+			return nil, fmt.Errorf("invalid byteword %q", encoded[i:i+2])
+		}
+		decoded = append(decoded, b)
+	}
+	if len(decoded) < 4 {
+		return nil, errors.New("bytewords too short")
+	}
+	data, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	if binary.BigEndian.Uint32(checksum) != crc32.ChecksumIEEE(data) {
+		return nil, errors.New("invalid bytewords checksum")
+	}
+	return data, nil
+}
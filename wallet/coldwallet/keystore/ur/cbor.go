@@ -0,0 +1,268 @@
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// The subset of CBOR (RFC 7049) used by the registered UR types: integers, byte and text strings,
+// arrays, maps with integer keys, tags, booleans and null. Floats and indefinite lengths are not
+// supported.
+
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+
+	cborFalse = 20
+	cborTrue  = 21
+	cborNull  = 22
+
+	maxCBORDepth = 32
+)
+
+// Tag is a tagged CBOR data item, e.g. a registered type embedded in another one
+type Tag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// Map is a CBOR map with integer keys, as used by the registered UR types
+type Map map[uint64]interface{}
+
+// MarshalCBOR encodes the value in canonical CBOR. The integers, bool, []byte, string,
+// []interface{}, Map, Tag and nil are supported.
+func MarshalCBOR(v interface{}) ([]byte, error) {
+	return appendCBOR(nil, v)
+}
+
+func appendCBOR(buf []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		buf = append(buf, cborSimple<<5|cborNull)
+	case bool:
+		if v {
+			buf = append(buf, cborSimple<<5|cborTrue)
+		} else {
+			buf = append(buf, cborSimple<<5|cborFalse)
+		}
+	case uint64:
+		buf = appendCBORHead(buf, cborUint, v)
+	case uint32:
+		buf = appendCBORHead(buf, cborUint, uint64(v))
+	case uint:
+		buf = appendCBORHead(buf, cborUint, uint64(v))
+	case int64:
+		buf = appendCBORInt(buf, v)
+	case int:
+		buf = appendCBORInt(buf, int64(v))
+	case []byte:
+		buf = appendCBORHead(buf, cborBytes, uint64(len(v)))
+		buf = append(buf, v...)
+	case string:
+		buf = appendCBORHead(buf, cborText, uint64(len(v)))
+		buf = append(buf, v...)
+	case []interface{}:
+		buf = appendCBORHead(buf, cborArray, uint64(len(v)))
+		for _, item := range v {
+			if buf, err = appendCBOR(buf, item); err != nil {
+				return nil, err
+			}
+		}
+	case Map:
+		keys := make([]uint64, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		buf = appendCBORHead(buf, cborMap, uint64(len(v)))
+		for _, key := range keys {
+			buf = appendCBORHead(buf, cborUint, key)
+			if buf, err = appendCBOR(buf, v[key]); err != nil {
+				return nil, err
+			}
+		}
+	case Tag:
+		buf = appendCBORHead(buf, cborTag, v.Number)
+		if buf, err = appendCBOR(buf, v.Content); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported CBOR value type %T", v)
+	}
+	return buf, nil
+}
+
+func appendCBORInt(buf []byte, v int64) []byte {
+	if v < 0 {
+		return appendCBORHead(buf, cborNegInt, uint64(-(v + 1)))
+	}
+	return appendCBORHead(buf, cborUint, uint64(v))
+}
+
+// appendCBORHead appends the major type and the argument in its shortest form
+func appendCBORHead(buf []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(buf, major<<5|byte(arg))
+	case arg <= 0xff:
+		return append(buf, major<<5|24, byte(arg))
+	case arg <= 0xffff:
+		buf = append(buf, major<<5|25, 0, 0)
+		binary.BigEndian.PutUint16(buf[len(buf)-2:], uint16(arg))
+		return buf
+	case arg <= 0xffffffff:
+		buf = append(buf, major<<5|26, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(buf[len(buf)-4:], uint32(arg))
+		return buf
+	default:
+		buf = append(buf, major<<5|27, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(buf[len(buf)-8:], arg)
+		return buf
+	}
+}
+
+// UnmarshalCBOR decodes a single CBOR data item. The unsigned integers are decoded as uint64, the
+// negative ones as int64, and the other values as the types supported by MarshalCBOR.
+func UnmarshalCBOR(data []byte) (interface{}, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, errors.New("trailing bytes after the CBOR data item")
+	}
+	return v, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("CBOR data nested too deeply")
+	}
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return arg, nil
+	case cborNegInt:
+		if arg > 1<<63-1 {
+			return nil, errors.New("CBOR negative integer overflow")
+		}
+		return -1 - int64(arg), nil
+	case cborBytes, cborText:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errors.New("CBOR string exceeds the data")
+		}
+		raw := d.data[d.pos : d.pos+int(arg)]
+		d.pos += int(arg)
+		if major == cborText {
+			return string(raw), nil
+		}
+		return append([]byte{}, raw...), nil
+	case cborArray:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errors.New("CBOR array exceeds the data")
+		}
+		items := make([]interface{}, 0, int(arg))
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case cborMap:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errors.New("CBOR map exceeds the data")
+		}
+		m := make(Map, int(arg))
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			intKey, ok := key.(uint64)
+			if !ok {
+				return nil, fmt.Errorf("unsupported CBOR map key %v", key)
+			}
+			if _, exists := m[intKey]; exists {
+				return nil, fmt.Errorf("duplicate CBOR map key %v", intKey)
+			}
+			if m[intKey], err = d.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborTag:
+		content, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Tag{Number: arg, Content: content}, nil
+	default:
+		switch arg {
+		case cborFalse:
+			return false, nil
+		case cborTrue:
+			return true, nil
+		case cborNull:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unsupported CBOR simple value %v", arg)
+	}
+}
+
+// head reads the major type and the argument of the next data item
+func (d *cborDecoder) head() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, errors.New("unexpected end of the CBOR data")
+	}
+	initial := d.data[d.pos]
+	d.pos++
+	major, info := initial>>5, initial&0x1f
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+	if major == cborSimple && info > 24 {
+		return 0, 0, errors.New("CBOR floats are not supported")
+	}
+	var size int
+	switch info {
+	case 24:
+		size = 1
+	case 25:
+		size = 2
+	case 26:
+		size = 4
+	case 27:
+		size = 8
+	default:
+		return 0, 0, errors.New("CBOR indefinite lengths are not supported")
+	}
+	if len(d.data)-d.pos < size {
+		return 0, 0, errors.New("unexpected end of the CBOR data")
+	}
+	var arg uint64
+	for _, b := range d.data[d.pos : d.pos+size] {
+		arg = arg<<8 | uint64(b)
+	}
+	d.pos += size
+	return major, arg, nil
+}
@@ -0,0 +1,156 @@
+package ur
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// The fountain code of the multi-part URs (BCR-2020-005). The parts up to the sequence length carry
+// the fragments of the message in order, the following ones carry the XOR of the fragments chosen
+// by a pseudorandom generator seeded with the sequence number and the checksum of the message, so
+// the encoder and the decoder agree on the fragments of each part.
+
+// xoshiro256 is the xoshiro256** generator, seeded with the SHA-256 digest of the seed
+type xoshiro256 struct {
+	s [4]uint64
+}
+
+func newXoshiro256(seed []byte) *xoshiro256 {
+	digest := sha256.Sum256(seed)
+	rng := &xoshiro256{}
+	for i := range rng.s {
+		rng.s[i] = binary.BigEndian.Uint64(digest[8*i : 8*i+8])
+	}
+	return rng
+}
+
+func (r *xoshiro256) next() uint64 {
+	result := bits.RotateLeft64(r.s[1]*5, 7) * 9
+	t := r.s[1] << 17
+	r.s[2] ^= r.s[0]
+	r.s[3] ^= r.s[1]
+	r.s[1] ^= r.s[2]
+	r.s[0] ^= r.s[3]
+	r.s[2] ^= t
+	r.s[3] = bits.RotateLeft64(r.s[3], 45)
+	return result
+}
+
+// nextDouble returns a number in [0, 1)
+func (r *xoshiro256) nextDouble() float64 {
+	return float64(r.next()) / (float64(math.MaxUint64) + 1)
+}
+
+// nextInt returns a number in [low, high]
+func (r *xoshiro256) nextInt(low, high int) int {
+	return int(r.nextDouble()*float64(high-low+1)) + low
+}
+
+// randomSampler samples the indexes of the probabilities with Vose's alias method
+type randomSampler struct {
+	probs   []float64
+	aliases []int
+}
+
+func newRandomSampler(probs []float64) *randomSampler {
+	sum := 0.0
+	for _, p := range probs {
+		sum += p
+	}
+	n := len(probs)
+	scaled := make([]float64, n)
+	for i, p := range probs {
+		scaled[i] = p * float64(n) / sum
+	}
+
+	// The indexes are pushed in reverse order, as the reference implementation does
+	var small, large []int
+	for i := n - 1; i >= 0; i-- {
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	sampler := &randomSampler{probs: make([]float64, n), aliases: make([]int, n)}
+	for len(small) > 0 && len(large) > 0 {
+		a := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+		sampler.probs[a] = scaled[a]
+		sampler.aliases[a] = g
+		scaled[g] += scaled[a] - 1
+		if scaled[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	for _, i := range large {
+		sampler.probs[i] = 1
+	}
+	for _, i := range small { // only through numeric instability
+		sampler.probs[i] = 1
+	}
+	return sampler
+}
+
+func (s *randomSampler) next(rng *xoshiro256) int {
+	r1 := rng.nextDouble()
+	r2 := rng.nextDouble()
+	i := int(float64(len(s.probs)) * r1)
+	if r2 < s.probs[i] {
+		return i
+	}
+	return s.aliases[i]
+}
+
+// chooseFragments returns the indexes of the fragments mixed into the part
+func chooseFragments(seqNum uint32, seqLen int, checksum uint32) []int {
+	if int(seqNum) <= seqLen {
+		return []int{int(seqNum) - 1}
+	}
+
+	seed := make([]byte, 8)
+	binary.BigEndian.PutUint32(seed[:4], seqNum)
+	binary.BigEndian.PutUint32(seed[4:], checksum)
+	rng := newXoshiro256(seed)
+
+	// The degree, i.e. the number of fragments mixed, is d with a probability proportional to 1/d
+	probs := make([]float64, seqLen)
+	for i := range probs {
+		probs[i] = 1 / float64(i+1)
+	}
+	degree := newRandomSampler(probs).next(rng) + 1
+
+	remaining := make([]int, seqLen)
+	for i := range remaining {
+		remaining[i] = i
+	}
+	indexes := make([]int, 0, degree)
+	for len(indexes) < degree {
+		i := rng.nextInt(0, len(remaining)-1)
+		indexes = append(indexes, remaining[i])
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+	return indexes
+}
+
+// mixFragments returns the XOR of the fragments
+func mixFragments(fragments [][]byte, indexes []int) []byte {
+	mixed := make([]byte, len(fragments[0]))
+	for _, i := range indexes {
+		xorInto(mixed, fragments[i])
+	}
+	return mixed
+}
+
+func xorInto(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
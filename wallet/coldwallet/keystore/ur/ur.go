@@ -0,0 +1,416 @@
+// Package ur implements the Uniform Resources (BCR-2020-005), the format the air-gapped wallets,
+// e.g. Keystone and AirGap Vault, exchange data in through QR codes. A UR too large for a single QR
+// code is split into the parts of an animated QR code.
+package ur
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxFragmentLen keeps each part of an animated QR code small enough to be scanned reliably
+// from a screen
+const DefaultMaxFragmentLen = 200
+
+const minFragmentLen = 10
+
+// maxMessageLen is far above the size of the data exchanged through the QR codes
+const maxMessageLen = 1 << 20
+
+// UR is a Uniform Resource, i.e. the CBOR encoding of a registered type, e.g. eth-sign-request
+type UR struct {
+	Type string
+	CBOR []byte
+}
+
+// New creates the UR of the type from the CBOR encoding of the value
+func New(urType string, v interface{}) (*UR, error) {
+	if !isValidType(urType) {
+		return nil, fmt.Errorf("invalid UR type %q", urType)
+	}
+	cbor, err := MarshalCBOR(v)
+	if err != nil {
+		return nil, err
+	}
+	return &UR{Type: urType, CBOR: cbor}, nil
+}
+
+// Value decodes the CBOR encoding of the UR
+func (u *UR) Value() (interface{}, error) {
+	return UnmarshalCBOR(u.CBOR)
+}
+
+// Encode returns the parts of the UR, each to be shown as a frame of an animated QR code. A UR which
+// fits in maxFragmentLen bytes is returned as a single part. Otherwise the CBOR is split into the
+// fragments of a multi-part UR. Only the pure fragments of the fountain code are returned, to be
+// shown in a loop, so a part missed by the scanner comes again in the next loop.
+func (u *UR) Encode(maxFragmentLen int) []string {
+	encoder := NewEncoder(u, maxFragmentLen)
+	parts := make([]string, 0, encoder.SeqLen())
+	for i := 0; i < encoder.SeqLen(); i++ {
+		parts = append(parts, encoder.NextPart())
+	}
+	return parts
+}
+
+// Encoder generates the parts of a UR. The first parts of a multi-part UR carry its fragments in
+// order, the following ones the mixes of fragments chosen by the fountain code, without end.
+type Encoder struct {
+	u         *UR
+	fragments [][]byte // nil for a single-part UR
+	checksum  uint32
+	seqNum    uint32
+}
+
+// NewEncoder creates an encoder splitting the UR into fragments of up to maxFragmentLen bytes
+func NewEncoder(u *UR, maxFragmentLen int) *Encoder {
+	e := &Encoder{u: u}
+	if len(u.CBOR) <= maxFragmentLen {
+		return e
+	}
+	if maxFragmentLen < minFragmentLen {
+		maxFragmentLen = minFragmentLen
+	}
+	count := (len(u.CBOR) + maxFragmentLen - 1) / maxFragmentLen
+	fragmentLen := (len(u.CBOR) + count - 1) / count
+	message := make([]byte, fragmentLen*count)
+	copy(message, u.CBOR)
+	for i := 0; i < count; i++ {
+		e.fragments = append(e.fragments, message[i*fragmentLen:(i+1)*fragmentLen])
+	}
+	e.checksum = crc32.ChecksumIEEE(u.CBOR)
+	return e
+}
+
+// SeqLen returns the number of fragments, i.e. the number of the pure parts
+func (e *Encoder) SeqLen() int {
+	if e.fragments == nil {
+		return 1
+	}
+	return len(e.fragments)
+}
+
+// NextPart returns the next part of the UR
+func (e *Encoder) NextPart() string {
+	if e.fragments == nil {
+		return "ur:" + e.u.Type + "/" + encodeMinimalBytewords(e.u.CBOR)
+	}
+	e.seqNum++
+	part, err := MarshalCBOR([]interface{}{
+		uint64(e.seqNum),
+		uint64(len(e.fragments)),
+		uint64(len(e.u.CBOR)),
+		uint64(e.checksum),
+		mixFragments(e.fragments, chooseFragments(e.seqNum, len(e.fragments), e.checksum)),
+	})
+	if err != nil {
+		panic(err) // the values are all supported
+	}
+	return fmt.Sprintf("ur:%v/%v-%v/%v", e.u.Type, e.seqNum, len(e.fragments), encodeMinimalBytewords(part))
+}
+
+// Decode decodes a single-part UR
+func Decode(part string) (*UR, error) {
+	decoder := NewDecoder()
+	if err := decoder.Receive(part); err != nil {
+		return nil, err
+	}
+	if !decoder.IsComplete() {
+		return nil, errors.New("the UR is a part of a multi-part UR")
+	}
+	return decoder.Result(), nil
+}
+
+// Decoder reassembles a UR from its parts, which may come in any order and more than once. The
+// mixed parts are reduced by the fragments and the other mixes received, until they yield a
+// fragment, so any sufficient subset of the parts reassembles the UR.
+type Decoder struct {
+	urType      string
+	seqLen      uint64
+	messageLen  uint64
+	checksum    uint64
+	fragmentLen int
+
+	fragments map[int][]byte // the fragments received or recovered, by index
+	mixed     []*mixedPart   // the mixes not reduced to a single fragment yet
+
+	result *UR
+}
+
+// mixedPart is the XOR of the fragments at the indexes
+type mixedPart struct {
+	indexes []int
+	data    []byte
+}
+
+// NewDecoder creates a decoder for a single or multi-part UR
+func NewDecoder() *Decoder {
+	return &Decoder{fragments: make(map[int][]byte)}
+}
+
+// Receive processes a part of the UR
+func (d *Decoder) Receive(part string) error {
+	if d.result != nil {
+		return nil
+	}
+	urType, seq, body, err := splitPart(part)
+	if err != nil {
+		return err
+	}
+	if len(d.urType) != 0 && urType != d.urType {
+		return fmt.Errorf("expected a part of ur:%v, got ur:%v", d.urType, urType)
+	}
+	data, err := decodeMinimalBytewords(body)
+	if err != nil {
+		return err
+	}
+	if len(seq) == 0 {
+		d.urType = urType
+		d.result = &UR{Type: urType, CBOR: data}
+		return nil
+	}
+
+	seqNum, seqLen, messageLen, checksum, fragment, err := decodePart(data)
+	if err != nil {
+		return err
+	}
+	pathSeqNum, pathSeqLen, err := parseSeq(seq)
+	if err != nil {
+		return err
+	}
+	if pathSeqNum != seqNum || pathSeqLen != seqLen {
+		return fmt.Errorf("the sequence %v does not match the part %v-%v", seq, seqNum, seqLen)
+	}
+	if len(d.urType) == 0 {
+		d.urType, d.seqLen, d.messageLen, d.checksum, d.fragmentLen = urType, seqLen, messageLen, checksum, len(fragment)
+	} else if seqLen != d.seqLen || messageLen != d.messageLen || checksum != d.checksum || len(fragment) != d.fragmentLen {
+		return errors.New("the part belongs to a different UR")
+	}
+
+	indexes := chooseFragments(uint32(seqNum), int(seqLen), uint32(checksum))
+	return d.process(&mixedPart{indexes: indexes, data: fragment})
+}
+
+// process adds the part, and the fragments it yields once reduced, until the UR is reassembled
+func (d *Decoder) process(part *mixedPart) error {
+	queue := []*mixedPart{part}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		if len(p.indexes) == 1 {
+			index := p.indexes[0]
+			if _, ok := d.fragments[index]; ok {
+				continue
+			}
+			d.fragments[index] = p.data
+			if uint64(len(d.fragments)) == d.seqLen {
+				return d.reassemble()
+			}
+			queue = append(queue, d.reduceMixedBy(p)...)
+			continue
+		}
+
+		for _, m := range d.mixed {
+			if equalIndexes(m.indexes, p.indexes) {
+				p = nil
+				break
+			}
+		}
+		if p == nil {
+			continue
+		}
+		for index, fragment := range d.fragments {
+			p = reducePart(p, &mixedPart{indexes: []int{index}, data: fragment})
+		}
+		for _, m := range d.mixed {
+			p = reducePart(p, m)
+		}
+		if len(p.indexes) == 1 {
+			queue = append(queue, p)
+			continue
+		}
+		queue = append(queue, d.reduceMixedBy(p)...)
+		d.mixed = append(d.mixed, p)
+	}
+	return nil
+}
+
+// reduceMixedBy reduces the mixed parts by the part, and returns the ones reduced to a fragment
+func (d *Decoder) reduceMixedBy(part *mixedPart) []*mixedPart {
+	var yielded []*mixedPart
+	mixed := d.mixed[:0]
+	for _, m := range d.mixed {
+		m = reducePart(m, part)
+		if len(m.indexes) == 1 {
+			yielded = append(yielded, m)
+		} else {
+			mixed = append(mixed, m)
+		}
+	}
+	d.mixed = mixed
+	return yielded
+}
+
+// reducePart removes the fragments of b from a, if they are a strict subset of the fragments of a
+func reducePart(a, b *mixedPart) *mixedPart {
+	if len(b.indexes) >= len(a.indexes) {
+		return a
+	}
+	inB := make(map[int]bool, len(b.indexes))
+	for _, i := range b.indexes {
+		inB[i] = true
+	}
+	indexes := make([]int, 0, len(a.indexes)-len(b.indexes))
+	for _, i := range a.indexes {
+		if !inB[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) != len(a.indexes)-len(b.indexes) {
+		return a // not a subset
+	}
+	data := append([]byte{}, a.data...)
+	xorInto(data, b.data)
+	return &mixedPart{indexes: indexes, data: data}
+}
+
+func equalIndexes(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	in := make(map[int]bool, len(a))
+	for _, i := range a {
+		in[i] = true
+	}
+	for _, i := range b {
+		if !in[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsComplete tells whether the UR is reassembled
+func (d *Decoder) IsComplete() bool {
+	return d.result != nil
+}
+
+// Progress returns the number of fragments received or recovered, and the number of fragments of the UR
+func (d *Decoder) Progress() (int, int) {
+	if d.result != nil && d.seqLen == 0 {
+		return 1, 1
+	}
+	return len(d.fragments), int(d.seqLen)
+}
+
+// Result returns the reassembled UR, or nil if it is not complete yet
+func (d *Decoder) Result() *UR {
+	return d.result
+}
+
+func (d *Decoder) reassemble() error {
+	message := make([]byte, 0, int(d.seqLen)*d.fragmentLen)
+	for i := 0; i < int(d.seqLen); i++ {
+		message = append(message, d.fragments[i]...)
+	}
+	message = message[:d.messageLen]
+	if uint64(crc32.ChecksumIEEE(message)) != d.checksum {
+		return errors.New("invalid UR checksum")
+	}
+	d.result = &UR{Type: d.urType, CBOR: message}
+	return nil
+}
+
+// splitPart splits a part into its type, its sequence (empty for a single-part UR), and its body
+func splitPart(part string) (string, string, string, error) {
+	part = strings.ToLower(strings.TrimSpace(part))
+	if !strings.HasPrefix(part, "ur:") {
+		return "", "", "", errors.New("not a UR, expected the ur: prefix")
+	}
+	components := strings.Split(part[len("ur:"):], "/")
+	if !isValidType(components[0]) {
+		return "", "", "", fmt.Errorf("invalid UR type %q", components[0])
+	}
+	switch len(components) {
+	case 2:
+		return components[0], "", components[1], nil
+	case 3:
+		return components[0], components[1], components[2], nil
+	default:
+		return "", "", "", errors.New("invalid UR path")
+	}
+}
+
+// decodePart decodes the CBOR of a part of a multi-part UR
+func decodePart(data []byte) (seqNum, seqLen, messageLen, checksum uint64, fragment []byte, err error) {
+	v, err := UnmarshalCBOR(data)
+	if err != nil {
+		return
+	}
+	items, ok := v.([]interface{})
+	if !ok || len(items) != 5 {
+		err = errors.New("invalid UR part")
+		return
+	}
+	numbers := make([]uint64, 4)
+	for i := range numbers {
+		if numbers[i], ok = items[i].(uint64); !ok {
+			err = errors.New("invalid UR part header")
+			return
+		}
+	}
+	if fragment, ok = items[4].([]byte); !ok || len(fragment) == 0 {
+		err = errors.New("invalid UR part fragment")
+		return
+	}
+	seqNum, seqLen, messageLen, checksum = numbers[0], numbers[1], numbers[2], numbers[3]
+	if seqNum == 0 || seqNum > math.MaxUint32 || seqLen == 0 || checksum > math.MaxUint32 {
+		err = errors.New("invalid UR part header")
+		return
+	}
+	// The message is split into the fewest fragments of the same length, which bounds the work of
+	// the fountain code by the length of the message
+	if messageLen == 0 || messageLen > maxMessageLen {
+		err = fmt.Errorf("invalid UR message length %v", messageLen)
+		return
+	}
+	if seqLen != (messageLen+uint64(len(fragment))-1)/uint64(len(fragment)) {
+		err = errors.New("the UR part does not match the message length")
+	}
+	return
+}
+
+// isValidType checks the type consists of lower case letters, digits and hyphens
+func isValidType(urType string) bool {
+	if len(urType) == 0 {
+		return false
+	}
+	for _, c := range urType {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSeq parses the sequence number and length of a part, e.g. 2-5
+func parseSeq(seq string) (uint64, uint64, error) {
+	components := strings.Split(seq, "-")
+	if len(components) != 2 {
+		return 0, 0, fmt.Errorf("invalid UR sequence %q", seq)
+	}
+	seqNum, err := strconv.ParseUint(components[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid UR sequence %q", seq)
+	}
+	seqLen, err := strconv.ParseUint(components[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid UR sequence %q", seq)
+	}
+	return seqNum, seqLen, nil
+}
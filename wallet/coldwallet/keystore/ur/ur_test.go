@@ -0,0 +1,204 @@
+package ur
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytewords(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Test vector of BCR-2020-012
+	data := []byte{0x00, 0x01, 0x02, 0x80, 0xff}
+	assert.Equal("aeadaolazmjendeoti", encodeMinimalBytewords(data))
+
+	decoded, err := decodeMinimalBytewords("AEADAOLAZMJENDEOTI")
+	require.Nil(err)
+	assert.Equal(data, decoded)
+
+	_, err = decodeMinimalBytewords("aeadaolazmjendeota")
+	assert.NotNil(err) // Invalid byteword
+	_, err = decodeMinimalBytewords("aeadaolazmjendeoto")
+	assert.NotNil(err) // Invalid checksum
+}
+
+func TestCBOR(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	encoded, err := MarshalCBOR(Map{
+		2: []byte{0xde, 0xad},
+		1: Tag{Number: 37, Content: []byte{0x01}},
+		3: uint64(1000),
+		4: []interface{}{uint64(44), true, int64(-2), "pando", nil},
+	})
+	require.Nil(err)
+	assert.Equal("a401d82541010242dead031903e80485182cf5216570616e646ff6", hex.EncodeToString(encoded))
+
+	decoded, err := UnmarshalCBOR(encoded)
+	require.Nil(err)
+	assert.Equal(Map{
+		1: Tag{Number: 37, Content: []byte{0x01}},
+		2: []byte{0xde, 0xad},
+		3: uint64(1000),
+		4: []interface{}{uint64(44), true, int64(-2), "pando", nil},
+	}, decoded)
+
+	_, err = UnmarshalCBOR(encoded[:len(encoded)-1])
+	assert.NotNil(err)
+	_, err = UnmarshalCBOR(append(encoded, 0x00))
+	assert.NotNil(err)
+	_, err = UnmarshalCBOR([]byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	assert.NotNil(err) // Array length exceeds the data
+}
+
+func TestSinglePartUR(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	u, err := New("bytes", []byte{0x01, 0x02, 0x03})
+	require.Nil(err)
+	parts := u.Encode(DefaultMaxFragmentLen)
+	require.Equal(1, len(parts))
+	assert.True(strings.HasPrefix(parts[0], "ur:bytes/"))
+
+	decoded, err := Decode(strings.ToUpper(parts[0]))
+	require.Nil(err)
+	assert.Equal(u, decoded)
+	v, err := decoded.Value()
+	require.Nil(err)
+	assert.Equal([]byte{0x01, 0x02, 0x03}, v)
+
+	_, err = Decode("ur:Bytes/" + parts[0][len("ur:bytes/"):])
+	assert.Nil(err) // URs are case insensitive
+	_, err = Decode("bytes/" + parts[0][len("ur:bytes/"):])
+	assert.NotNil(err)
+}
+
+func TestMultiPartUR(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	u, err := New("bytes", bytes.Repeat([]byte("pando"), 100))
+	require.Nil(err)
+	parts := u.Encode(100)
+	require.Equal(6, len(parts))
+	assert.True(strings.HasPrefix(parts[0], "ur:bytes/1-6/"))
+
+	_, err = Decode(parts[0])
+	assert.NotNil(err)
+
+	// The parts may be scanned in any order and more than once
+	decoder := NewDecoder()
+	for _, i := range []int{3, 0, 3, 5, 1, 2} {
+		require.Nil(decoder.Receive(parts[i]))
+		assert.False(decoder.IsComplete())
+	}
+	received, total := decoder.Progress()
+	assert.Equal(5, received)
+	assert.Equal(6, total)
+	require.Nil(decoder.Receive(parts[4]))
+	require.True(decoder.IsComplete())
+	assert.Equal(u, decoder.Result())
+
+	// A part of another UR is rejected
+	other, err := New("bytes", bytes.Repeat([]byte("theta"), 100))
+	require.Nil(err)
+	decoder = NewDecoder()
+	require.Nil(decoder.Receive(parts[0]))
+	assert.NotNil(decoder.Receive(other.Encode(100)[1]))
+
+	// A mixed part of another UR is rejected as well
+	encoder := NewEncoder(other, 100)
+	for i := 0; i < 6; i++ {
+		encoder.NextPart()
+	}
+	assert.NotNil(decoder.Receive(encoder.NextPart()))
+
+	// The sequence in the path must match the part
+	assert.NotNil(NewDecoder().Receive("ur:bytes/2-6/" + parts[0][len("ur:bytes/1-6/"):]))
+}
+
+func TestFountainCode(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Test vector of the reference implementation, bc-ur
+	message := make([]byte, 256)
+	rng := newXoshiro256([]byte("Wolf"))
+	for i := range message {
+		message[i] = byte(rng.nextInt(0, 255))
+	}
+	u, err := New("bytes", message)
+	require.Nil(err)
+	expected := []string{
+		"ur:bytes/1-9/lpadascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtdkgslpgh",
+		"ur:bytes/2-9/lpaoascfadaxcywenbpljkhdcagwdpfnsboxgwlbaawzuefywkdplrsrjynbvygabwjldapfcsgmghhkhstlrdcxaefz",
+		"ur:bytes/3-9/lpaxascfadaxcywenbpljkhdcahelbknlkuejnbadmssfhfrdpsbiegecpasvssovlgeykssjykklronvsjksopdzmol",
+		"ur:bytes/4-9/lpaaascfadaxcywenbpljkhdcasotkhemthydawydtaxneurlkosgwcekonertkbrlwmplssjtammdplolsbrdzcrtas",
+		"ur:bytes/5-9/lpahascfadaxcywenbpljkhdcatbbdfmssrkzmcwnezelennjpfzbgmuktrhtejscktelgfpdlrkfyfwdajldejokbwf",
+		"ur:bytes/6-9/lpamascfadaxcywenbpljkhdcackjlhkhybssklbwefectpfnbbectrljectpavyrolkzczcpkmwidmwoxkilghdsowp",
+		"ur:bytes/7-9/lpatascfadaxcywenbpljkhdcavszmwnjkwtclrtvaynhpahrtoxmwvwatmedibkaegdosftvandiodagdhthtrlnnhy",
+		"ur:bytes/8-9/lpayascfadaxcywenbpljkhdcadmsponkkbbhgsoltjntegepmttmoonftnbuoiyrehfrtsabzsttorodklubbuyaetk",
+		"ur:bytes/9-9/lpasascfadaxcywenbpljkhdcajskecpmdckihdyhphfotjojtfmlnwmadspaxrkytbztpbauotbgtgtaeaevtgavtny",
+		"ur:bytes/10-9/lpbkascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtwdkiplzs",
+		"ur:bytes/11-9/lpbdascfadaxcywenbpljkhdcahelbknlkuejnbadmssfhfrdpsbiegecpasvssovlgeykssjykklronvsjkvetiiapk",
+		"ur:bytes/12-9/lpbnascfadaxcywenbpljkhdcarllaluzmdmgstospeyiefmwejlwtpedamktksrvlcygmzemovovllarodtmtbnptrs",
+		"ur:bytes/13-9/lpbtascfadaxcywenbpljkhdcamtkgtpknghchchyketwsvwgwfdhpgmgtylctotzopdrpayoschcmhplffziachrfgd",
+		"ur:bytes/14-9/lpbaascfadaxcywenbpljkhdcapazewnvonnvdnsbyleynwtnsjkjndeoldydkbkdslgjkbbkortbelomueekgvstegt",
+		"ur:bytes/15-9/lpbsascfadaxcywenbpljkhdcaynmhpddpzmversbdqdfyrehnqzlugmjzmnmtwmrouohtstgsbsahpawkditkckynwt",
+		"ur:bytes/16-9/lpbeascfadaxcywenbpljkhdcawygekobamwtlihsnpalnsghenskkiynthdzotsimtojetprsttmukirlrsbtamjtpd",
+		"ur:bytes/17-9/lpbyascfadaxcywenbpljkhdcamklgftaxykpewyrtqzhydntpnytyisincxmhtbceaykolduortotiaiaiafhiaoyce",
+		"ur:bytes/18-9/lpbgascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtntwkbkwy",
+		"ur:bytes/19-9/lpbwascfadaxcywenbpljkhdcadekicpaajootjzpsdrbalpeywllbdsnbinaerkurspbncxgslgftvtsrjtksplcpeo",
+		"ur:bytes/20-9/lpbbascfadaxcywenbpljkhdcayapmrleeleaxpasfrtrdkncffwjyjzgyetdmlewtkpktgllepfrltataztksmhkbot",
+	}
+	encoder := NewEncoder(u, 30)
+	for _, part := range expected {
+		assert.Equal(part, encoder.NextPart())
+	}
+	assert.Equal(expected[:9], u.Encode(30))
+
+	// The mixed parts make up for the pure parts missed
+	decoder := NewDecoder()
+	for _, i := range []int{2, 4, 5, 7, 9, 10, 12, 13, 15, 16} {
+		require.False(decoder.IsComplete())
+		require.Nil(decoder.Receive(expected[i]))
+	}
+	require.True(decoder.IsComplete())
+	assert.Equal(u, decoder.Result())
+
+	// The UR is reassembled from the mixed parts only
+	decoder = NewDecoder()
+	for i := 9; !decoder.IsComplete(); i++ {
+		require.True(i < 100)
+		require.Nil(decoder.Receive(encoder.NextPart()))
+	}
+	assert.Equal(u, decoder.Result())
+
+	// A corrupted part fails the checksum rather than yielding another UR
+	data := mustDecodeBody(t, expected[10])
+	data[len(data)-1] ^= 0x01
+	decoder = NewDecoder()
+	err = decoder.Receive("ur:bytes/11-9/" + encodeMinimalBytewords(data))
+	for _, i := range []int{0, 1, 2, 3, 4, 5, 6, 7, 8} {
+		if err == nil && !decoder.IsComplete() {
+			err = decoder.Receive(expected[i])
+		}
+	}
+	assert.NotNil(err)
+}
+
+func mustDecodeBody(t *testing.T, part string) []byte {
+	_, _, body, err := splitPart(part)
+	require.Nil(t, err)
+	data, err := decodeMinimalBytewords(body)
+	require.Nil(t, err)
+	return data
+}
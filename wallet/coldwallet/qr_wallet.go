@@ -0,0 +1,48 @@
+package coldwallet
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	ks "github.com/pandotoken/pando/wallet/coldwallet/keystore"
+)
+
+// QRScheme is the scheme of the air-gapped wallets signing through QR codes
+const QRScheme = "qr"
+
+// Stdio is the terminal of the process, i.e. its standard input and output
+var Stdio io.ReadWriter = stdio{}
+
+type stdio struct{}
+
+func (stdio) Read(p []byte) (int, error)  { return os.Stdin.Read(p) }
+func (stdio) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+
+// terminal is the device of the QR wallet, which is not closed with the wallet
+type terminal struct {
+	io.ReadWriter
+}
+
+func (terminal) Close() error { return nil }
+
+// NewQRWallet creates a cold wallet for an air-gapped wallet, e.g. Keystone or AirGap Vault, which
+// scans the transactions and shows the signatures as QR codes. The user interacts through the
+// terminal, on which the QR codes are shown, unless frameDir is set to write them as PNG files.
+func NewQRWallet(term io.ReadWriter, frameDir string) *ColdWallet {
+	path := "terminal"
+	if len(frameDir) != 0 {
+		path = frameDir
+	}
+	return &ColdWallet{
+		id:             assembleColdWalletID(QRScheme, path),
+		hub:            nil,
+		driver:         ks.NewQRDriver(frameDir),
+		addressPathMap: nil,
+		openDevice: func() (io.ReadWriteCloser, error) {
+			return terminal{term}, nil
+		},
+		device:    nil,
+		stateLock: &sync.RWMutex{},
+	}
+}
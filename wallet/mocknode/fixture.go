@@ -0,0 +1,72 @@
+package mocknode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// RPCError is the error object of a JSON-RPC 2.0 response
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// Fixture is a recorded RPC call and the response of the node. A fixture without
+// params matches the calls of the method with any params.
+type Fixture struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// Matches returns whether the fixture is recorded for the given call
+func (f *Fixture) Matches(method string, params json.RawMessage) bool {
+	if f.Method != method {
+		return false
+	}
+	if len(f.Params) == 0 {
+		return true
+	}
+	return jsonEqual(f.Params, params)
+}
+
+// LoadFixtures reads the fixtures from a JSON file
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := []Fixture{}
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("Failed to parse fixtures %v: %v", path, err)
+	}
+	return fixtures, nil
+}
+
+// SaveFixtures writes the fixtures to a JSON file
+func SaveFixtures(path string, fixtures []Fixture) error {
+	data, err := json.MarshalIndent(fixtures, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// jsonEqual compares two JSON documents regardless of the key order and whitespaces
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
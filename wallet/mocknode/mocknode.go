@@ -0,0 +1,155 @@
+// Package mocknode provides a mock Pando RPC node replaying recorded responses, so that
+// the wallet signing and broadcast flows can be tested end to end without a running chain.
+// The fixtures are recorded from a real node by pointing the client to a Recorder.
+package mocknode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeServerError    = -32000
+)
+
+// HandlerFunc computes the result of an RPC call dynamically. Returning an *RPCError
+// sets the code of the error response.
+type HandlerFunc func(params json.RawMessage) (interface{}, error)
+
+// Call is an RPC call received by the mock node
+type Call struct {
+	Method string
+	Params json.RawMessage
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// MockNode is a JSON-RPC 2.0 server answering the calls with the recorded fixtures, or
+// with the registered handlers for the calls not covered by the fixtures.
+type MockNode struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	fixtures []Fixture
+	handlers map[string]HandlerFunc
+	calls    []Call
+}
+
+// NewMockNode starts a mock node serving the given fixtures
+func NewMockNode(fixtures ...Fixture) *MockNode {
+	node := &MockNode{
+		fixtures: fixtures,
+		handlers: make(map[string]HandlerFunc),
+	}
+	node.server = httptest.NewServer(node)
+	return node
+}
+
+// URL returns the RPC endpoint of the mock node
+func (node *MockNode) URL() string {
+	return node.server.URL
+}
+
+// Close shuts down the mock node
+func (node *MockNode) Close() {
+	node.server.Close()
+}
+
+// AddFixtures adds fixtures to be replayed. Fixtures added earlier take precedence.
+func (node *MockNode) AddFixtures(fixtures ...Fixture) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.fixtures = append(node.fixtures, fixtures...)
+}
+
+// HandleFunc registers the handler of the method for the calls not matching any fixture
+func (node *MockNode) HandleFunc(method string, handler HandlerFunc) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.handlers[method] = handler
+}
+
+// Calls returns the RPC calls received so far
+func (node *MockNode) Calls() []Call {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	return append([]Call{}, node.calls...)
+}
+
+// ServeHTTP implements http.Handler
+func (node *MockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := rpcRequest{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeResponse(w, rpcResponse{Error: &RPCError{Code: codeParseError, Message: err.Error()}})
+		return
+	}
+	res := node.handle(req.Method, req.Params)
+	res.ID = req.ID
+	writeResponse(w, res)
+}
+
+func (node *MockNode) handle(method string, params json.RawMessage) rpcResponse {
+	node.mu.Lock()
+	node.calls = append(node.calls, Call{Method: method, Params: params})
+	var fixture *Fixture
+	for i := range node.fixtures {
+		if node.fixtures[i].Matches(method, params) {
+			fixture = &node.fixtures[i]
+			break
+		}
+	}
+	handler := node.handlers[method]
+	node.mu.Unlock()
+
+	if fixture != nil {
+		return rpcResponse{Result: fixture.Result, Error: fixture.Error}
+	}
+	if handler == nil {
+		return rpcResponse{Error: &RPCError{
+			Code:    codeMethodNotFound,
+			Message: fmt.Sprintf("No fixture for %v with params %s", method, params),
+		}}
+	}
+
+	result, err := handler(params)
+	if err != nil {
+		if rpcErr, ok := err.(*RPCError); ok {
+			return rpcResponse{Error: rpcErr}
+		}
+		return rpcResponse{Error: &RPCError{Code: codeServerError, Message: err.Error()}}
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return rpcResponse{Error: &RPCError{Code: codeServerError, Message: err.Error()}}
+	}
+	return rpcResponse{Result: raw}
+}
+
+func writeResponse(w http.ResponseWriter, res rpcResponse) {
+	res.JSONRPC = "2.0"
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,227 @@
+package mocknode
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/ledger/types"
+	"github.com/pandotoken/pando/rpc"
+	"github.com/pandotoken/pando/wallet/softwallet"
+	"github.com/pandotoken/pando/wallet/softwallet/keystore"
+	wtypes "github.com/pandotoken/pando/wallet/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+const testChainID = "privatenet"
+
+var (
+	testPrivKey  = "8e4d79ac2a1e6dc66bb4e1e9bb5d3e05f0b3d5fb6c8a2e7cf6d6b4e2a3f3c9d1"
+	testToAddr   = common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+	testFixtures = filepath.Join("testdata", "send_tx.json")
+)
+
+func TestSoftWalletSendTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fixtures, err := LoadFixtures(testFixtures)
+	require.Nil(err)
+	node := NewMockNode(fixtures...)
+	defer node.Close()
+
+	tmpdir := createTempDir(t)
+	defer os.RemoveAll(tmpdir)
+	wallet, from := newTestSoftWallet(t, tmpdir)
+	res, raw, err := sendTokens(wallet, from, node.URL())
+	require.Nil(err)
+
+	// The recorded broadcast only matches if the signed tx bytes are unchanged
+	assert.Equal(crypto.Keccak256Hash(raw).Hex(), res.TxHash)
+	calls := node.Calls()
+	require.Equal(2, len(calls))
+	assert.Equal("pando.GetAccount", calls[0].Method)
+	assert.Equal("pando.BroadcastRawTransaction", calls[1].Method)
+}
+
+func TestMockNodeHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tmpdir := createTempDir(t)
+	defer os.RemoveAll(tmpdir)
+	wallet, from := newTestSoftWallet(t, tmpdir)
+	node := newTestChain(from)
+	defer node.Close()
+
+	_, _, err := sendTokens(wallet, from, node.URL())
+	require.Nil(err)
+
+	// No handler and no fixture for the method
+	client := rpcc.NewRPCClient(node.URL())
+	res, err := client.Call("pando.GetStatus", rpc.GetStatusArgs{})
+	require.Nil(err)
+	require.NotNil(res.Error)
+	assert.Equal(codeMethodNotFound, res.Error.Code)
+
+	// Errors of the handler are returned to the client
+	node.HandleFunc("pando.GetStatus", func(params json.RawMessage) (interface{}, error) {
+		return nil, errors.New("not synced")
+	})
+	res, err = client.Call("pando.GetStatus", rpc.GetStatusArgs{})
+	require.Nil(err)
+	require.NotNil(res.Error)
+	assert.Equal("not synced", res.Error.Message)
+}
+
+func TestRecorder(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tmpdir := createTempDir(t)
+	defer os.RemoveAll(tmpdir)
+	wallet, from := newTestSoftWallet(t, tmpdir)
+	node := newTestChain(from)
+	defer node.Close()
+
+	recorder := NewRecorder(node.URL())
+	proxy := httptest.NewServer(recorder)
+	defer proxy.Close()
+
+	recorded, _, err := sendTokens(wallet, from, proxy.URL)
+	require.Nil(err)
+	require.Equal(2, len(recorder.Fixtures()))
+
+	path := filepath.Join(tmpdir, "fixtures.json")
+	require.Nil(recorder.Save(path))
+
+	// Replaying the recorded fixtures gives the same responses
+	fixtures, err := LoadFixtures(path)
+	require.Nil(err)
+	replay := NewMockNode(fixtures...)
+	defer replay.Close()
+	replayed, _, err := sendTokens(wallet, from, replay.URL())
+	require.Nil(err)
+	assert.Equal(recorded.TxHash, replayed.TxHash)
+}
+
+// ---------------- Test Utilities ---------------- //
+
+// newTestSoftWallet creates an unlocked soft wallet holding the deterministic test key
+func newTestSoftWallet(t *testing.T, tmpdir string) (wtypes.Wallet, common.Address) {
+	privKey, err := crypto.PrivateKeyFromBytes(common.Hex2Bytes(testPrivKey))
+	require.Nil(t, err)
+	ks, err := keystore.NewKeystorePlain(tmpdir)
+	require.Nil(t, err)
+	require.Nil(t, ks.StoreKey(keystore.NewKey(privKey), ""))
+
+	wallet, err := softwallet.NewSoftWallet(tmpdir, softwallet.KeystoreTypePlain)
+	require.Nil(t, err)
+	from := privKey.PublicKey().Address()
+	require.Nil(t, wallet.Unlock(from, "", nil))
+	return wallet, from
+}
+
+func createTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "mocknode")
+	require.Nil(t, err)
+	return dir
+}
+
+// newTestChain starts a mock node which computes the responses like a real node would
+func newTestChain(from common.Address) *MockNode {
+	node := NewMockNode()
+	node.HandleFunc("pando.GetAccount", func(params json.RawMessage) (interface{}, error) {
+		account := types.NewAccount(from)
+		account.Sequence = 1
+		account.Balance = types.NewCoins(1000, 1000)
+		return rpc.GetAccountResult{Account: account, Address: from.Hex()}, nil
+	})
+	node.HandleFunc("pando.BroadcastRawTransaction", func(params json.RawMessage) (interface{}, error) {
+		args := []rpc.BroadcastRawTransactionArgs{}
+		if err := json.Unmarshal(params, &args); err != nil || len(args) != 1 {
+			return nil, errors.New("Invalid params")
+		}
+		raw, err := hex.DecodeString(args[0].TxBytes)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := types.TxFromBytes(raw)
+		if err != nil {
+			return nil, err
+		}
+		sendTx, ok := tx.(*types.SendTx)
+		if !ok {
+			return nil, errors.New("Unexpected tx type")
+		}
+		input := sendTx.Inputs[0]
+		if !input.Signature.Verify(sendTx.SignBytes(testChainID), input.Address) {
+			return nil, errors.New("Invalid signature")
+		}
+		return rpc.BroadcastRawTransactionResult{TxHash: crypto.Keccak256Hash(raw).Hex()}, nil
+	})
+	return node
+}
+
+// sendTokens runs the same flow as "pandocli tx send" against the RPC endpoint
+func sendTokens(wallet wtypes.Wallet, from common.Address, endpoint string) (*rpc.BroadcastRawTransactionResult, common.Bytes, error) {
+	client := rpcc.NewRPCClient(endpoint)
+
+	res, err := client.Call("pando.GetAccount", rpc.GetAccountArgs{Address: from.Hex()})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	account := rpc.GetAccountResult{Account: &types.Account{}}
+	if err := res.GetObject(&account); err != nil {
+		return nil, nil, err
+	}
+
+	fee := new(big.Int).SetUint64(types.MinimumTransactionFeePTXWei)
+	sendTx := &types.SendTx{
+		Fee: types.Coins{PandoWei: big.NewInt(0), PTXWei: fee},
+		Inputs: []types.TxInput{{
+			Address:  from,
+			Coins:    types.Coins{PandoWei: big.NewInt(10), PTXWei: new(big.Int).Add(big.NewInt(20), fee)},
+			Sequence: account.Sequence + 1,
+		}},
+		Outputs: []types.TxOutput{{
+			Address: testToAddr,
+			Coins:   types.Coins{PandoWei: big.NewInt(10), PTXWei: big.NewInt(20)},
+		}},
+	}
+	sig, err := wallet.Sign(from, sendTx.SignBytes(testChainID))
+	if err != nil {
+		return nil, nil, err
+	}
+	sendTx.SetSignature(from, sig)
+	raw, err := types.TxToBytes(sendTx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err = client.Call("pando.BroadcastRawTransaction", rpc.BroadcastRawTransactionArgs{TxBytes: hex.EncodeToString(raw)})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	result := &rpc.BroadcastRawTransactionResult{}
+	if err := res.GetObject(result); err != nil {
+		return nil, nil, err
+	}
+	return result, raw, nil
+}
@@ -0,0 +1,77 @@
+package mocknode
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Recorder is an HTTP proxy in front of a real RPC node recording every call and its
+// response as a fixture. Point the client, e.g. pandocli --endpoint, to the recorder
+// and save the fixtures afterwards to replay them with the MockNode.
+type Recorder struct {
+	remote string
+	client *http.Client
+
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// NewRecorder creates a recorder proxying the calls to the remote RPC endpoint
+func NewRecorder(remote string) *Recorder {
+	return &Recorder{
+		remote: remote,
+		client: &http.Client{},
+	}
+}
+
+// Fixtures returns the fixtures recorded so far
+func (rec *Recorder) Fixtures() []Fixture {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Fixture{}, rec.fixtures...)
+}
+
+// Save writes the recorded fixtures to a JSON file
+func (rec *Recorder) Save(path string) error {
+	return SaveFixtures(path, rec.Fixtures())
+}
+
+// ServeHTTP implements http.Handler
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := rec.client.Post(rec.remote, "application/json", bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	req := rpcRequest{}
+	res := rpcResponse{}
+	if json.Unmarshal(body, &req) == nil && json.Unmarshal(respBody, &res) == nil {
+		rec.mu.Lock()
+		rec.fixtures = append(rec.fixtures, Fixture{
+			Method: req.Method,
+			Params: req.Params,
+			Result: res.Result,
+			Error:  res.Error,
+		})
+		rec.mu.Unlock()
+	}
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	w.Write(respBody)
+}
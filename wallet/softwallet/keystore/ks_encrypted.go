@@ -56,7 +56,9 @@ import (
 const (
 	version = 3
 
-	keyHeaderKDF = "scrypt"
+	keyHeaderKDF       = "scrypt"
+	keyHeaderKDFPBKDF2 = "pbkdf2"
+	pbkdf2PRF          = "hmac-sha256"
 
 	// StandardScryptN is the N parameter of Scrypt encryption algorithm, using 256MB
 	// memory and taking approximately 1s CPU time on a modern processor.
@@ -121,19 +123,33 @@ func (ks KeystoreEncrypted) ListKeyAddresses() ([]common.Address, error) {
 
 	addresses := []common.Address{}
 	for _, filename := range filenames {
-		addrStr := filepath.Base(filename)
-		address := common.HexToAddress(addrStr)
+		address, ok := keyFileAddress(filepath.Base(filename))
+		if !ok {
+			continue
+		}
 		addresses = append(addresses, address)
 	}
 
 	return addresses, nil
 }
 
+// keyFileAddress returns the address of the key in the file, which is named by the address for
+// the keys stored by the keystore, or by the creation time and the address for the key files of
+// geth, e.g. UTC--2016-03-22T12-57-55.920751759Z--7ef5a6135f1fd6a02593eedc869c6d41d934aef8
+func keyFileAddress(filename string) (common.Address, bool) {
+	if strings.HasPrefix(filename, "UTC--") {
+		filename = filename[strings.LastIndex(filename, "--")+2:]
+	}
+	if !common.IsHexAddress(filename) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(filename), true
+}
+
 func (ks KeystoreEncrypted) GetKey(address common.Address, auth string) (*Key, error) {
 	var keyjson []byte
 	var err error
-	for af := allLowerCase; af <= allUpperCase; af++ { // try all formats
-		filePath := ks.getFilePath(address, af)
+	for _, filePath := range ks.getFilePaths(address) { // try all formats
 		keyjson, err = ioutil.ReadFile(filePath)
 		if err == nil {
 			break
@@ -170,14 +186,24 @@ func (ks KeystoreEncrypted) DeleteKey(address common.Address, auth string) error
 		return err
 	}
 
-	for af := allLowerCase; af <= allUpperCase; af++ { // try all formats
-		filePath := ks.getFilePath(address, af)
+	for _, filePath := range ks.getFilePaths(address) { // try all formats
 		deleteKeyFile(filePath)
 	}
 
 	return nil
 }
 
+// getFilePaths returns the paths the key of the address may be stored under, including the
+// key files copied from geth.
+func (ks KeystoreEncrypted) getFilePaths(address common.Address) []string {
+	filePaths := []string{}
+	for af := allLowerCase; af <= allUpperCase; af++ {
+		filePaths = append(filePaths, ks.getFilePath(address, af))
+	}
+	gethFilePaths, _ := filepath.Glob(path.Join(ks.keysDirPath, "UTC--*--"+strings.ToLower(address.Hex()[2:])))
+	return append(filePaths, gethFilePaths...)
+}
+
 func (ks KeystoreEncrypted) getFilePath(address common.Address, addrFormat AddressFormat) string {
 	var filePath string
 	addrStr := address.Hex()[2:]
@@ -194,16 +220,46 @@ func (ks KeystoreEncrypted) getFilePath(address common.Address, addrFormat Addre
 // encryptKey encrypts a key using the specified scrypt parameters into a json
 // blob that can be decrypted later on.
 func encryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
-	authArray := []byte(auth)
+	salt := newSalt()
+	derivedKey, err := scrypt.Key([]byte(auth), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	scryptParamsJSON := make(map[string]interface{}, 5)
+	scryptParamsJSON["n"] = scryptN
+	scryptParamsJSON["r"] = scryptR
+	scryptParamsJSON["p"] = scryptP
+	scryptParamsJSON["dklen"] = scryptDKLen
+	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
+
+	return encryptKeyWithDerivedKey(key, derivedKey, keyHeaderKDF, scryptParamsJSON)
+}
+
+// encryptKeyPBKDF2 encrypts a key using PBKDF2 with the given number of iterations, for the
+// wallets not supporting scrypt.
+func encryptKeyPBKDF2(key *Key, auth string, iterations int) ([]byte, error) {
+	salt := newSalt()
+	derivedKey := pbkdf2.Key([]byte(auth), salt, iterations, scryptDKLen, sha256.New)
+
+	pbkdf2ParamsJSON := make(map[string]interface{}, 4)
+	pbkdf2ParamsJSON["c"] = iterations
+	pbkdf2ParamsJSON["prf"] = pbkdf2PRF
+	pbkdf2ParamsJSON["dklen"] = scryptDKLen
+	pbkdf2ParamsJSON["salt"] = hex.EncodeToString(salt)
 
+	return encryptKeyWithDerivedKey(key, derivedKey, keyHeaderKDFPBKDF2, pbkdf2ParamsJSON)
+}
+
+func newSalt() []byte {
 	salt := make([]byte, 32)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		panic("reading from crypto/rand failed: " + err.Error())
 	}
-	derivedKey, err := scrypt.Key(authArray, salt, scryptN, scryptR, scryptP, scryptDKLen)
-	if err != nil {
-		return nil, err
-	}
+	return salt
+}
+
+func encryptKeyWithDerivedKey(key *Key, derivedKey []byte, kdf string, kdfParams map[string]interface{}) ([]byte, error) {
 	encryptKey := derivedKey[:16]
 	keyBytes := math.PaddedBigBytes(key.PrivateKey.D(), 32)
 
@@ -217,13 +273,6 @@ func encryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
 	}
 	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
 
-	scryptParamsJSON := make(map[string]interface{}, 5)
-	scryptParamsJSON["n"] = scryptN
-	scryptParamsJSON["r"] = scryptR
-	scryptParamsJSON["p"] = scryptP
-	scryptParamsJSON["dklen"] = scryptDKLen
-	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
-
 	cipherParamsJSON := cipherparamsJSON{
 		IV: hex.EncodeToString(iv),
 	}
@@ -232,8 +281,8 @@ func encryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
 		Cipher:       "aes-128-ctr",
 		CipherText:   hex.EncodeToString(cipherText),
 		CipherParams: cipherParamsJSON,
-		KDF:          keyHeaderKDF,
-		KDFParams:    scryptParamsJSON,
+		KDF:          kdf,
+		KDFParams:    kdfParams,
 		MAC:          hex.EncodeToString(mac),
 	}
 
@@ -308,22 +357,42 @@ func decryptKey(keyjson []byte, auth string) (*Key, error) {
 
 func getKDFKey(cryptoJSON cryptoJSON, auth string) ([]byte, error) {
 	authArray := []byte(auth)
-	salt, err := hex.DecodeString(cryptoJSON.KDFParams["salt"].(string))
+	saltStr, _ := cryptoJSON.KDFParams["salt"].(string)
+	salt, err := hex.DecodeString(saltStr)
+	if err != nil {
+		return nil, err
+	}
+	dkLen, err := kdfParamInt(cryptoJSON.KDFParams, "dklen")
 	if err != nil {
 		return nil, err
 	}
-	dkLen := ensureInt(cryptoJSON.KDFParams["dklen"])
+	// The first half of the derived key is the AES key, the second half the MAC key
+	if dkLen < 32 {
+		return nil, fmt.Errorf("Derived key length %v is too short", dkLen)
+	}
 
 	if cryptoJSON.KDF == keyHeaderKDF {
-		n := ensureInt(cryptoJSON.KDFParams["n"])
-		r := ensureInt(cryptoJSON.KDFParams["r"])
-		p := ensureInt(cryptoJSON.KDFParams["p"])
+		n, err := kdfParamInt(cryptoJSON.KDFParams, "n")
+		if err != nil {
+			return nil, err
+		}
+		r, err := kdfParamInt(cryptoJSON.KDFParams, "r")
+		if err != nil {
+			return nil, err
+		}
+		p, err := kdfParamInt(cryptoJSON.KDFParams, "p")
+		if err != nil {
+			return nil, err
+		}
 		return scrypt.Key(authArray, salt, n, r, p, dkLen)
 
-	} else if cryptoJSON.KDF == "pbkdf2" {
-		c := ensureInt(cryptoJSON.KDFParams["c"])
-		prf := cryptoJSON.KDFParams["prf"].(string)
-		if prf != "hmac-sha256" {
+	} else if cryptoJSON.KDF == keyHeaderKDFPBKDF2 {
+		c, err := kdfParamInt(cryptoJSON.KDFParams, "c")
+		if err != nil {
+			return nil, err
+		}
+		prf, _ := cryptoJSON.KDFParams["prf"].(string)
+		if prf != pbkdf2PRF {
 			return nil, fmt.Errorf("Unsupported PBKDF2 PRF: %s", prf)
 		}
 		key := pbkdf2.Key(authArray, salt, c, dkLen, sha256.New)
@@ -345,15 +414,17 @@ func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
 	return outText, err
 }
 
-// TODO: can we do without this when unmarshalling dynamic JSON?
-// why do integers in KDF params end up as float64 and not int after
-// unmarshal?
-func ensureInt(x interface{}) int {
-	res, ok := x.(int)
-	if !ok {
-		res = int(x.(float64))
+// kdfParamInt returns the integer KDF parameter, which is unmarshalled as a float64, or is an int
+// for the params of a key encrypted in the process.
+func kdfParamInt(params map[string]interface{}, name string) (int, error) {
+	switch x := params[name].(type) {
+	case int:
+		return x, nil
+	case float64:
+		return int(x), nil
+	default:
+		return 0, fmt.Errorf("Invalid or missing KDF parameter %v", name)
 	}
-	return res
 }
 
 type encryptedKeyJSON struct {
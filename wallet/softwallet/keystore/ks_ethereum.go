@@ -0,0 +1,70 @@
+package keystore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// The KDFs of the Ethereum keystore V3 files, see ExportEthereumKey
+const (
+	KDFScrypt = keyHeaderKDF
+	KDFPBKDF2 = keyHeaderKDFPBKDF2
+)
+
+// StandardPBKDF2C is the number of PBKDF2 iterations of the key files exported by geth
+const StandardPBKDF2C = 262144
+
+// ImportEthereumKey decrypts a keystore V3 file exported by geth, MetaMask or the other Ethereum
+// wallets. The addresses of Pando and Ethereum are derived the same way, so the key controls the
+// same address on both.
+func ImportEthereumKey(keyjson []byte, auth string) (*Key, error) {
+	header := struct {
+		Address string `json:"address"`
+	}{}
+	if err := json.Unmarshal(keyjson, &header); err != nil {
+		return nil, fmt.Errorf("Not a keystore file: %v", err)
+	}
+
+	key, err := decryptKey(keyjson, auth)
+	if err != nil {
+		return nil, err
+	}
+	// The address is optional, but needs to match the key if present
+	if header.Address != "" && common.HexToAddress(header.Address) != key.Address {
+		return nil, fmt.Errorf("key content mismatch: have account %x, want %v", key.Address, header.Address)
+	}
+	if key.Id == nil {
+		key.Id = uuid.NewRandom()
+	}
+	return key, nil
+}
+
+// ExportEthereumKey encrypts the key into a keystore V3 file the Ethereum wallets can import, with
+// the given KDF, KDFScrypt or KDFPBKDF2.
+func ExportEthereumKey(key *Key, auth string, kdf string) ([]byte, error) {
+	switch kdf {
+	case KDFScrypt:
+		return encryptKey(key, auth, StandardScryptN, StandardScryptP)
+	case KDFPBKDF2:
+		return encryptKeyPBKDF2(key, auth, StandardPBKDF2C)
+	default:
+		return nil, fmt.Errorf("Unsupported KDF: %s", kdf)
+	}
+}
+
+// EthereumKeyFileName returns the name geth gives to the key file of the address.
+func EthereumKeyFileName(address common.Address) string {
+	ts := time.Now().UTC()
+	return fmt.Sprintf("UTC--%s--%s", toISO8601(ts), strings.ToLower(address.Hex()[2:]))
+}
+
+func toISO8601(t time.Time) string {
+	return fmt.Sprintf("%04d-%02d-%02dT%02d-%02d-%02d.%09dZ",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+}
@@ -0,0 +1,121 @@
+package keystore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/crypto"
+)
+
+const gethKeyFile = "UTC--2016-03-22T12-57-55.920751759Z--7ef5a6135f1fd6a02593eedc869c6d41d934aef8"
+
+func TestImportEthereumKey(t *testing.T) {
+	tests := []struct {
+		file    string
+		address common.Address
+	}{
+		{gethKeyFile, common.HexToAddress("7ef5a6135f1fd6a02593eedc869c6d41d934aef8")},
+		{"no-address", common.HexToAddress("f466859ead1932d743d622cb74fc058882e8648a")},
+	}
+	for _, test := range tests {
+		keyjson, err := ioutil.ReadFile(path.Join("testdata/keystore", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ImportEthereumKey(keyjson, "foobaz"); err != ErrDecrypt {
+			t.Errorf("%v: wrong error for invalid password: %v", test.file, err)
+		}
+		key, err := ImportEthereumKey(keyjson, "foobar")
+		if err != nil {
+			t.Fatalf("%v: failed to import: %v", test.file, err)
+		}
+		if key.Address != test.address {
+			t.Errorf("%v: address mismatch: have %v, want %v", test.file, key.Address.Hex(), test.address.Hex())
+		}
+		if key.Id == nil {
+			t.Errorf("%v: missing key id", test.file)
+		}
+	}
+
+	// The address field needs to match the key
+	keyjson, err := ioutil.ReadFile("testdata/keystore/aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := strings.Replace(string(keyjson), "f466859ead1932d743d622cb74fc058882e8648a", "7ef5a6135f1fd6a02593eedc869c6d41d934aef8", 1)
+	if _, err := ImportEthereumKey([]byte(forged), "foobar"); err == nil {
+		t.Error("imported a key file with a mismatching address")
+	}
+}
+
+func TestExportEthereumKey(t *testing.T) {
+	privKey, _, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := NewKey(privKey)
+
+	keyjson, err := encryptKeyPBKDF2(key, "foo", 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(keyjson), `"kdf":"pbkdf2"`) {
+		t.Errorf("unexpected kdf: %s", keyjson)
+	}
+	imported, err := ImportEthereumKey(keyjson, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Address != key.Address || !bytes.Equal(imported.PrivateKey.ToBytes(), key.PrivateKey.ToBytes()) {
+		t.Error("pbkdf2 key mismatch after round trip")
+	}
+
+	keyjson, err = ExportEthereumKey(key, "foo", KDFPBKDF2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported, err = ImportEthereumKey(keyjson, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if imported.Address != key.Address {
+		t.Error("address mismatch after round trip")
+	}
+
+	if _, err := ExportEthereumKey(key, "foo", "argon2"); err == nil {
+		t.Error("exported a key with an unsupported kdf")
+	}
+}
+
+func TestKeystoreEncryptedGethKeyFile(t *testing.T) {
+	dir, ks := tmpKeyStoreIface(t, true)
+	defer os.RemoveAll(dir)
+
+	keyjson, err := ioutil.ReadFile(path.Join("testdata/keystore", gethKeyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "encrypted", gethKeyFile), keyjson, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	address := common.HexToAddress("7ef5a6135f1fd6a02593eedc869c6d41d934aef8")
+	addresses, err := ks.ListKeyAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addresses) != 1 || addresses[0] != address {
+		t.Fatalf("unexpected addresses: %v", addresses)
+	}
+	key, err := ks.GetKey(address, "foobar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Address != address {
+		t.Errorf("address mismatch: have %v, want %v", key.Address.Hex(), address.Hex())
+	}
+}
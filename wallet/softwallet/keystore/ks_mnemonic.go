@@ -0,0 +1,127 @@
+package keystore
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bip39 "github.com/tyler-smith/go-bip39"
+
+	"github.com/pandotoken/pando/common/math"
+	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/secp256k1"
+	"github.com/pandotoken/pando/wallet/types"
+)
+
+// MnemonicEntropyBits is the entropy of the generated mnemonics, which have 24 words
+const MnemonicEntropyBits = 256
+
+const hardenedKeyStart = 0x80000000
+
+var errInvalidChildKey = errors.New("Invalid child key, use the next index")
+
+// NewMnemonic generates a BIP-39 mnemonic, i.e. a seed phrase the keys can be recovered from.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(MnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks the words and the checksum of the BIP-39 mnemonic.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := bip39.EntropyFromMnemonic(NormalizeMnemonic(mnemonic))
+	if err == bip39.ErrInvalidMnemonic {
+		return errors.New("Invalid mnemonic, expected 12, 15, 18, 21 or 24 words")
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid mnemonic: %v", err)
+	}
+	return nil
+}
+
+// NormalizeMnemonic lower cases the words of the mnemonic and separates them with single spaces.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// DeriveKeyFromMnemonic derives the key at the BIP-32 path, e.g. m/44'/60'/0'/0/0, from the seed of
+// the BIP-39 mnemonic and passphrase. The keys are derived the same way as in MetaMask, geth and the
+// hardware wallets, so the same mnemonic gives the same accounts.
+func DeriveKeyFromMnemonic(mnemonic string, passphrase string, path types.DerivationPath) (*Key, error) {
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return deriveKeyFromSeed(bip39.NewSeed(mnemonic, passphrase), path)
+}
+
+// deriveKeyFromSeed derives the key at the BIP-32 path from the seed.
+func deriveKeyFromSeed(seed []byte, path types.DerivationPath) (*Key, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	privKey, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	curveN := secp256k1.S256().Params().N
+	if privKey.Sign() == 0 || privKey.Cmp(curveN) >= 0 {
+		return nil, errors.New("Invalid master key")
+	}
+
+	var err error
+	for _, index := range path {
+		privKey, chainCode, err = deriveChildKey(privKey, chainCode, index)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive %v: %v", path, err)
+		}
+	}
+
+	key, err := crypto.PrivateKeyFromBytes(math.PaddedBigBytes(privKey, 32))
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(key), nil
+}
+
+// deriveChildKey derives the child private key of the index as specified by BIP-32.
+func deriveChildKey(privKey *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	curve := secp256k1.S256()
+	var data []byte
+	if index >= hardenedKeyStart {
+		data = append([]byte{0x0}, math.PaddedBigBytes(privKey, 32)...)
+	} else {
+		data = compressPoint(curve.ScalarBaseMult(math.PaddedBigBytes(privKey, 32)))
+	}
+	indexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(indexBytes, index)
+	data = append(data, indexBytes...)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curveN := curve.Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(curveN) >= 0 {
+		return nil, nil, errInvalidChildKey
+	}
+	childKey := tweak.Add(tweak, privKey)
+	childKey.Mod(childKey, curveN)
+	if childKey.Sign() == 0 {
+		return nil, nil, errInvalidChildKey
+	}
+	return childKey, sum[32:], nil
+}
+
+// compressPoint serializes the public key point in the 33 bytes compressed form.
+func compressPoint(x, y *big.Int) []byte {
+	prefix := byte(0x2)
+	if y.Bit(0) == 1 {
+		prefix = 0x3
+	}
+	return append([]byte{prefix}, math.PaddedBigBytes(x, 32)...)
+}
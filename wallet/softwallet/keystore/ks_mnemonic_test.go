@@ -0,0 +1,98 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet/types"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveKeyFromMnemonic(t *testing.T) {
+	tests := []struct {
+		index   uint32
+		address common.Address
+	}{
+		{0, common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")},
+		{1, common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")},
+	}
+	for _, test := range tests {
+		path := append(types.DerivationPath{}, types.DefaultBaseDerivationPath...)
+		path[len(path)-1] += test.index
+		key, err := DeriveKeyFromMnemonic(testMnemonic, "", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.Address != test.address {
+			t.Errorf("%v: address mismatch: have %v, want %v", path, key.Address.Hex(), test.address.Hex())
+		}
+	}
+
+	// The whitespaces and the case of the words do not matter
+	key, err := DeriveKeyFromMnemonic("  TEST test test test test test test test test test test\tjunk\n", "", types.DefaultBaseDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Address != tests[0].address {
+		t.Errorf("address mismatch: have %v, want %v", key.Address.Hex(), tests[0].address.Hex())
+	}
+
+	// The passphrase is part of the seed
+	key, err = DeriveKeyFromMnemonic(testMnemonic, "pando", types.DefaultBaseDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Address == tests[0].address {
+		t.Error("passphrase not used")
+	}
+
+	invalid := []string{
+		"",
+		"test test test test test test test test test test test test",   // wrong checksum
+		"test test test test test test test test test test test pando",  // unknown word
+		"test test test test test test test test test test test junk x", // wrong length
+	}
+	for _, mnemonic := range invalid {
+		if _, err := DeriveKeyFromMnemonic(mnemonic, "", types.DefaultBaseDerivationPath); err == nil {
+			t.Errorf("derived a key from invalid mnemonic %q", mnemonic)
+		}
+	}
+}
+
+// Test vector 1 of BIP-32
+func TestDeriveKeyFromSeed(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path types.DerivationPath
+		key  string
+	}{
+		{types.DerivationPath{}, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{types.DerivationPath{hardenedKeyStart}, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{types.DerivationPath{hardenedKeyStart, 1}, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+	}
+	for _, test := range tests {
+		key, err := deriveKeyFromSeed(seed, test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if privKey := hex.EncodeToString(key.PrivateKey.ToBytes()); privKey != test.key {
+			t.Errorf("%v: key mismatch: have %v, want %v", test.path, privKey, test.key)
+		}
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := strings.Fields(mnemonic); len(words) != 24 {
+		t.Errorf("expected 24 words, got %v", len(words))
+	}
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		t.Error(err)
+	}
+}
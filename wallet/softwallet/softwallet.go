@@ -2,10 +2,12 @@ package softwallet
 
 import (
 	"fmt"
+	"path"
 	"sync"
 
 	"github.com/pandotoken/pando/common"
 	"github.com/pandotoken/pando/crypto"
+	"github.com/pandotoken/pando/crypto/bls"
 	ks "github.com/pandotoken/pando/wallet/softwallet/keystore"
 	"github.com/pandotoken/pando/wallet/types"
 )
@@ -27,6 +29,7 @@ type SoftWallet struct {
 	mu             *sync.RWMutex
 	keystore       ks.Keystore
 	unlockedKeyMap map[common.Address]*UnlockedKey // Currently unlocked keys (decrypted private keys)
+	watchOnlyPath  string                          // File listing the watch-only accounts
 }
 
 type UnlockedKey struct {
@@ -50,6 +53,7 @@ func NewSoftWallet(keysDirPath string, kstype KeystoreType) (*SoftWallet, error)
 		mu:             &sync.RWMutex{},
 		keystore:       keystore,
 		unlockedKeyMap: make(map[common.Address]*UnlockedKey),
+		watchOnlyPath:  path.Join(keysDirPath, WatchOnlyFileName),
 	}
 
 	return wallet, nil
@@ -103,6 +107,10 @@ func (w *SoftWallet) Unlock(address common.Address, password string, derivationP
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if watchOnly, err := w.isWatchOnly(address); err == nil && watchOnly {
+		return fmt.Errorf("%v is a watch-only account, its transactions need to be signed offline", address.Hex())
+	}
+
 	key, err := w.keystore.GetKey(address, password)
 	if err != nil {
 		return err
@@ -207,6 +215,84 @@ func (w *SoftWallet) Sign(address common.Address, txrlp common.Bytes) (*crypto.S
 	return signature, err
 }
 
+// GetValidatorBlsKey returns the BLS key the validator signs its votes with, if the address has been
+// unlocked. The key is derived from the private key, the same way the node derives it.
+func (w *SoftWallet) GetValidatorBlsKey(address common.Address) (*bls.SecretKey, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	unlockedKey, found := w.unlockedKeyMap[address]
+	if !found {
+		return nil, fmt.Errorf("Key not unlocked yet for address: %v", address)
+	}
+
+	return bls.DeriveValidatorKey(unlockedKey.PrivateKey)
+}
+
+// ImportEthereumKey stores the key of an Ethereum keystore V3 file, decrypted with the password of
+// the file, under the given password
+func (w *SoftWallet) ImportEthereumKey(keyjson []byte, keyjsonPassword string, password string) (common.Address, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key, err := ks.ImportEthereumKey(keyjson, keyjsonPassword)
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer w.zeroKey(&UnlockedKey{Key: key})
+
+	return w.storeImportedKey(key, password)
+}
+
+// ImportMnemonicKey stores the key derived at the path from the BIP-39 mnemonic and passphrase
+// under the given password
+func (w *SoftWallet) ImportMnemonicKey(mnemonic string, passphrase string, path types.DerivationPath, password string) (common.Address, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key, err := ks.DeriveKeyFromMnemonic(mnemonic, passphrase, path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer w.zeroKey(&UnlockedKey{Key: key})
+
+	return w.storeImportedKey(key, password)
+}
+
+// storeImportedKey stores the key unless the keystore already has it. Should be called with the
+// lock held.
+func (w *SoftWallet) storeImportedKey(key *ks.Key, password string) (common.Address, error) {
+	addresses, err := w.keystore.ListKeyAddresses()
+	if err != nil {
+		return common.Address{}, err
+	}
+	for _, address := range addresses {
+		if address == key.Address {
+			return common.Address{}, fmt.Errorf("Key %v already exists", address.Hex())
+		}
+	}
+
+	if err := w.keystore.StoreKey(key, password); err != nil {
+		return common.Address{}, err
+	}
+	return key.Address, nil
+}
+
+// ExportEthereumKey encrypts the key of the address into an Ethereum keystore V3 file under the
+// given password, with the given KDF (keystore.KDFScrypt or keystore.KDFPBKDF2)
+func (w *SoftWallet) ExportEthereumKey(address common.Address, password string, keyjsonPassword string, kdf string) ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key, err := w.keystore.GetKey(address, password)
+	if err != nil {
+		return nil, err
+	}
+	defer w.zeroKey(&UnlockedKey{Key: key})
+
+	return ks.ExportEthereumKey(key, keyjsonPassword, kdf)
+}
+
 // zeroKey zeroes a private key in memory
 func (w *SoftWallet) zeroKey(unlockedKey *UnlockedKey) {
 	if unlockedKey == nil {
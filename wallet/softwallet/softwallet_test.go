@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/pandotoken/pando/common"
+	"github.com/pandotoken/pando/wallet/types"
 )
 
 func TestPlainSoftWalletBasics(t *testing.T) {
@@ -26,6 +27,95 @@ func TestEncryptedSoftWalletMultipleKeys(t *testing.T) {
 	testSoftWalletMultipleKeys(t, KeystoreTypeEncrypted)
 }
 
+func TestSoftWalletValidatorBlsKey(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir := createTempDir()
+	defer os.RemoveAll(tmpdir)
+
+	wallet, err := NewSoftWallet(tmpdir, KeystoreTypePlain)
+	assert.Nil(err)
+	addr, err := wallet.NewKey("abcd")
+	assert.Nil(err)
+
+	blsKey, err := wallet.GetValidatorBlsKey(addr)
+	assert.Nil(err)
+	assert.NotNil(blsKey)
+
+	// The key is derived deterministically
+	err = wallet.Lock(addr)
+	assert.Nil(err)
+	_, err = wallet.GetValidatorBlsKey(addr)
+	assert.NotNil(err)
+	err = wallet.Unlock(addr, "abcd", nil)
+	assert.Nil(err)
+	blsKey2, err := wallet.GetValidatorBlsKey(addr)
+	assert.Nil(err)
+	assert.True(blsKey.Equals(blsKey2))
+}
+
+func TestSoftWalletImportMnemonicKey(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir := createTempDir()
+	defer os.RemoveAll(tmpdir)
+
+	wallet, err := NewSoftWallet(tmpdir, KeystoreTypePlain)
+	assert.Nil(err)
+
+	mnemonic := "test test test test test test test test test test test junk"
+	addr, err := wallet.ImportMnemonicKey(mnemonic, "", types.DefaultBaseDerivationPath, "abcd")
+	assert.Nil(err)
+	assert.Equal(common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), addr)
+
+	// The key can only be imported once
+	_, err = wallet.ImportMnemonicKey(mnemonic, "", types.DefaultBaseDerivationPath, "abcd")
+	assert.NotNil(err)
+
+	err = wallet.Unlock(addr, "abcd", nil)
+	assert.Nil(err)
+	pubKey, err := wallet.GetPublicKey(addr)
+	assert.Nil(err)
+	assert.Equal(addr, pubKey.Address())
+}
+
+func TestSoftWalletWatchOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir := createTempDir()
+	defer os.RemoveAll(tmpdir)
+
+	wallet, err := NewSoftWallet(tmpdir, KeystoreTypePlain)
+	assert.Nil(err)
+	keyAddr, err := wallet.NewKey("abcd")
+	assert.Nil(err)
+
+	addr := common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+	assert.Nil(wallet.AddWatchOnly(addr, "cold storage"))
+	assert.NotNil(wallet.AddWatchOnly(addr, "cold storage"))
+	assert.NotNil(wallet.AddWatchOnly(keyAddr, "")) // the wallet has the key
+
+	// The watch-only accounts persist, and cannot be unlocked
+	wallet, err = NewSoftWallet(tmpdir, KeystoreTypePlain)
+	assert.Nil(err)
+	accounts, err := wallet.ListWatchOnly()
+	assert.Nil(err)
+	assert.Equal([]WatchOnlyAccount{{Address: addr, Label: "cold storage"}}, accounts)
+	watchOnly, err := wallet.IsWatchOnly(addr)
+	assert.Nil(err)
+	assert.True(watchOnly)
+	assert.NotNil(wallet.Unlock(addr, "abcd", nil))
+	addrs, err := wallet.List()
+	assert.Nil(err)
+	assert.Equal([]common.Address{keyAddr}, addrs)
+
+	assert.Nil(wallet.RemoveWatchOnly(addr))
+	assert.NotNil(wallet.RemoveWatchOnly(addr))
+	accounts, err = wallet.ListWatchOnly()
+	assert.Nil(err)
+	assert.Equal(0, len(accounts))
+}
+
 // ---------------- Test Utilities ---------------- //
 
 func testSoftWalletBasics(t *testing.T, ksType KeystoreType) {
@@ -0,0 +1,122 @@
+package softwallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pandotoken/pando/common"
+)
+
+// WatchOnlyFileName is the name of the file under the keys folder which lists the watch-only accounts
+const WatchOnlyFileName = "watch_only.json"
+
+// WatchOnlyAccount is an address the wallet keeps track of without its private key, e.g. the
+// address of a key kept on an air-gapped machine. Its transactions are built by the wallet, and
+// signed offline.
+type WatchOnlyAccount struct {
+	Address common.Address `json:"address"`
+	Label   string         `json:"label,omitempty"`
+}
+
+// ListWatchOnly returns the watch-only accounts
+func (w *SoftWallet) ListWatchOnly() ([]WatchOnlyAccount, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.loadWatchOnly()
+}
+
+// IsWatchOnly indicates whether the address is a watch-only account
+func (w *SoftWallet) IsWatchOnly(address common.Address) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.isWatchOnly(address)
+}
+
+// AddWatchOnly adds a watch-only account, unless the wallet already has the address
+func (w *SoftWallet) AddWatchOnly(address common.Address, label string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	addresses, err := w.keystore.ListKeyAddresses()
+	if err != nil {
+		return err
+	}
+	for _, keyAddress := range addresses {
+		if keyAddress == address {
+			return fmt.Errorf("The key of %v is in the wallet already", address.Hex())
+		}
+	}
+
+	accounts, err := w.loadWatchOnly()
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if account.Address == address {
+			return fmt.Errorf("%v is a watch-only account already", address.Hex())
+		}
+	}
+	accounts = append(accounts, WatchOnlyAccount{Address: address, Label: label})
+	return w.saveWatchOnly(accounts)
+}
+
+// RemoveWatchOnly removes a watch-only account
+func (w *SoftWallet) RemoveWatchOnly(address common.Address) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	accounts, err := w.loadWatchOnly()
+	if err != nil {
+		return err
+	}
+	for i, account := range accounts {
+		if account.Address == address {
+			accounts = append(accounts[:i], accounts[i+1:]...)
+			return w.saveWatchOnly(accounts)
+		}
+	}
+	return fmt.Errorf("%v is not a watch-only account", address.Hex())
+}
+
+// isWatchOnly should be called with the lock held
+func (w *SoftWallet) isWatchOnly(address common.Address) (bool, error) {
+	accounts, err := w.loadWatchOnly()
+	if err != nil {
+		return false, err
+	}
+	for _, account := range accounts {
+		if account.Address == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadWatchOnly should be called with the lock held
+func (w *SoftWallet) loadWatchOnly() ([]WatchOnlyAccount, error) {
+	raw, err := ioutil.ReadFile(w.watchOnlyPath)
+	if os.IsNotExist(err) {
+		return []WatchOnlyAccount{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	accounts := []WatchOnlyAccount{}
+	if err := json.Unmarshal(raw, &accounts); err != nil {
+		return nil, fmt.Errorf("Failed to parse %v: %v", w.watchOnlyPath, err)
+	}
+	return accounts, nil
+}
+
+// saveWatchOnly should be called with the lock held
+func (w *SoftWallet) saveWatchOnly(accounts []WatchOnlyAccount) error {
+	raw, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(w.watchOnlyPath, raw, 0600)
+}
@@ -11,6 +11,7 @@ const (
 	WalletTypeSoft WalletType = iota
 	WalletTypeColdNano
 	WalletTypeColdTrezor
+	WalletTypeColdQR
 )
 
 type Wallet interface {
@@ -28,6 +28,9 @@ func OpenWallet(cfgPath string, walletType types.WalletType, encrypted bool) (ty
 		if err != nil {
 			return nil, err
 		}
+	} else if walletType == types.WalletTypeColdQR {
+		// The air-gapped wallets are not connected, the QR codes are shown on the terminal
+		wallet = cw.NewQRWallet(cw.Stdio, "")
 	} else {
 		var hub *coldwallet.Hub
 		var err error